@@ -4,12 +4,29 @@ import (
 	"context"
 	"log/slog"
 	"strings"
+	"time"
+	// The runtime image ships without zoneinfo; clinic timezones must resolve anyway.
+	_ "time/tzdata"
 
+	"capim-test/internal/attachments"
+	"capim-test/internal/auditexport"
+	"capim-test/internal/bankverification"
+	"capim-test/internal/brasilapi"
+	"capim-test/internal/clamav"
+	"capim-test/internal/cnab"
 	"capim-test/internal/config"
 	"capim-test/internal/db"
+	"capim-test/internal/esignature"
 	httpapi "capim-test/internal/http"
+	"capim-test/internal/jobs"
+	"capim-test/internal/keywrap"
+	"capim-test/internal/notify"
+	"capim-test/internal/screening"
 	"capim-test/internal/service"
 	"capim-test/internal/telemetry"
+	"capim-test/internal/viacep"
+	"capim-test/internal/warehouse"
+	"capim-test/internal/webhook"
 )
 
 func main() {
@@ -34,17 +51,232 @@ func main() {
 		}
 	}()
 
-	database, err := db.OpenPostgres(ctx, cfg.DatabaseURL)
+	poolConfig := db.PoolConfig{
+		MaxConns:               cfg.DatabaseMaxConns,
+		StatementCacheCapacity: cfg.DatabaseStatementCache,
+		StatementTimeout:       cfg.DatabaseStatementTimeout,
+	}
+	database, err := db.OpenPostgres(ctx, cfg.DatabaseURL, poolConfig)
 	if err != nil {
 		slog.Error("open database", "error", err)
 		return
 	}
 	defer database.Close()
 
-	svc := service.New(
-		database,
+	validationPolicy, err := service.ParseValidationPolicy(cfg.ValidationRules)
+	if err != nil {
+		slog.Error("parse validation rules", "error", err)
+		return
+	}
+
+	attachmentStore, err := attachments.NewLocal(cfg.AttachmentsDir)
+	if err != nil {
+		slog.Error("open attachment storage", "error", err)
+		return
+	}
+
+	cacheInvalidations := database.Channel("reference_cache_invalidation")
+	serviceOptions := []service.Option{
 		service.WithAuthConfig(cfg.JWTSecret, cfg.JWTIssuer, cfg.JWTAccessTokenTTL),
-	)
+		service.WithValidationPolicy(validationPolicy),
+		service.WithTaxIDBlocklist(cfg.TaxIDBlocklist),
+		service.WithAttachmentStore(attachmentStore, cfg.PublicBaseURL),
+		service.WithDocumentNoticeDays(cfg.DocumentNoticeDays),
+		service.WithRetentionDays(cfg.RetentionDays),
+		service.WithDeleteConfirmationThreshold(cfg.DeleteConfirmationLimit),
+		service.WithDeletionGracePeriod(cfg.DeletionGracePeriod),
+		service.WithOffboardingPurgeDelay(cfg.OffboardingPurgeDelay),
+		service.WithReferenceCache(cfg.ReferenceCacheSize, cfg.ReferenceCacheTTL, cacheInvalidations),
+	}
+	adminSecret := strings.TrimSpace(cfg.AdminJWTSecret)
+	if adminSecret != "" {
+		if adminSecret == strings.TrimSpace(cfg.JWTSecret) {
+			slog.Error("ADMIN_JWT_SECRET must differ from JWT_SECRET")
+			return
+		}
+		serviceOptions = append(serviceOptions, service.WithAdminAuthConfig(adminSecret))
+	}
+	if cfg.TenantSchemasEnabled {
+		tenantSchemas := db.NewTenantSchemas(database.Pool, cfg.DatabaseURL, poolConfig)
+		defer tenantSchemas.Close()
+		serviceOptions = append(serviceOptions, service.WithTenantSchemas(tenantSchemas))
+	}
+	if masterKey := strings.TrimSpace(cfg.FieldEncryptionKey); masterKey != "" {
+		wrapper, err := keywrap.NewLocal(masterKey)
+		if err != nil {
+			slog.Error("configure field encryption", "error", err)
+			return
+		}
+		serviceOptions = append(serviceOptions, service.WithDataKeyWrapper(wrapper))
+	}
+	if cfg.ViaCEPEnabled {
+		serviceOptions = append(serviceOptions, service.WithAddressLookup(viacep.New(cfg.ViaCEPBaseURL, cfg.ViaCEPTimeout)))
+	}
+	if cfg.CompanyRegistryEnabled {
+		serviceOptions = append(serviceOptions, service.WithCompanyRegistry(brasilapi.New(cfg.CompanyRegistryBaseURL, cfg.CompanyRegistryTimeout)))
+	}
+	if screeningURL := strings.TrimSpace(cfg.ScreeningURL); screeningURL != "" {
+		serviceOptions = append(serviceOptions, service.WithTaxIDScreener(screening.New(screeningURL, cfg.ScreeningTimeout)))
+	}
+	if verificationURL := strings.TrimSpace(cfg.BankVerificationURL); verificationURL != "" {
+		callbackURL := ""
+		if baseURL := strings.TrimRight(strings.TrimSpace(cfg.PublicBaseURL), "/"); baseURL != "" {
+			callbackURL = baseURL + "/api/v1/bank-account-verifications/callback"
+		}
+		verifier := bankverification.New(cfg.BankVerificationProvider, verificationURL, callbackURL, cfg.BankVerificationTimeout)
+		serviceOptions = append(serviceOptions, service.WithBankAccountVerifier(verifier, cfg.BankVerificationSecret))
+	}
+	if clamAVAddr := strings.TrimSpace(cfg.ClamAVAddr); clamAVAddr != "" {
+		serviceOptions = append(serviceOptions, service.WithAttachmentScanner(clamav.New(clamAVAddr, cfg.ClamAVTimeout)))
+	}
+	if signatureURL := strings.TrimSpace(cfg.ESignatureURL); signatureURL != "" {
+		callbackURL := ""
+		if baseURL := strings.TrimRight(strings.TrimSpace(cfg.PublicBaseURL), "/"); baseURL != "" {
+			callbackURL = baseURL + "/api/v1/signature-requests/callback"
+		}
+		provider := esignature.New(cfg.ESignatureProvider, signatureURL, callbackURL, cfg.ESignatureTimeout)
+		serviceOptions = append(serviceOptions, service.WithSignatureProvider(provider, cfg.ESignatureSecret))
+	}
+	if payerTaxID := strings.TrimSpace(cfg.PayoutPayerTaxID); payerTaxID != "" {
+		serviceOptions = append(serviceOptions, service.WithPayoutPayer(cnab.Payer{
+			Name:        cfg.PayoutPayerName,
+			TaxID:       payerTaxID,
+			BankName:    cfg.PayoutPayerBankName,
+			AgreementID: cfg.PayoutPayerAgreement,
+			Account: cnab.Account{
+				BankCode:      cfg.PayoutPayerBankCode,
+				BranchNumber:  cfg.PayoutPayerBranch,
+				AccountNumber: cfg.PayoutPayerAccount,
+			},
+		}))
+	}
+	webhookURL := strings.TrimSpace(cfg.WebhookURL)
+	if webhookURL != "" {
+		serviceOptions = append(serviceOptions, service.WithEventPublisher(webhook.New(webhookURL, cfg.WebhookSecret, cfg.WebhookTimeout)))
+	}
+	auditExportRegion := cfg.AuditExportRegion
+	switch strings.ToLower(strings.TrimSpace(cfg.AuditExportSink)) {
+	case "":
+	case "webhook":
+		serviceOptions = append(serviceOptions, service.WithAuditExportSink(auditexport.NewWebhook(cfg.AuditExportWebhookURL, cfg.AuditExportWebhookSecret, cfg.AuditExportTimeout)))
+	case "s3":
+		sink, err := auditexport.NewS3(auditexport.S3Config{
+			Bucket:          cfg.AuditExportS3Bucket,
+			Region:          cfg.AuditExportS3Region,
+			Endpoint:        cfg.AuditExportS3Endpoint,
+			Prefix:          cfg.AuditExportS3Prefix,
+			AccessKeyID:     cfg.AuditExportS3AccessKey,
+			SecretAccessKey: cfg.AuditExportS3SecretKey,
+			SessionToken:    cfg.AuditExportS3Session,
+			Timeout:         cfg.AuditExportTimeout,
+		})
+		if err != nil {
+			slog.Error("configure audit export", "error", err)
+			return
+		}
+		serviceOptions = append(serviceOptions, service.WithAuditExportSink(sink))
+		if strings.TrimSpace(auditExportRegion) == "" {
+			auditExportRegion = cfg.AuditExportS3Region
+		}
+	default:
+		slog.Error("unsupported AUDIT_EXPORT_SINK", "sink", cfg.AuditExportSink)
+		return
+	}
+	warehouseExportRegion := cfg.WarehouseExportRegion
+	warehouseConfig := warehouse.Config{
+		Bucket:          cfg.WarehouseExportBucket,
+		Region:          cfg.WarehouseExportBucketRegion,
+		Endpoint:        cfg.WarehouseExportEndpoint,
+		Prefix:          cfg.WarehouseExportPrefix,
+		Format:          cfg.WarehouseExportFormat,
+		AccessKeyID:     cfg.WarehouseExportAccessKey,
+		SecretAccessKey: cfg.WarehouseExportSecretKey,
+		SessionToken:    cfg.WarehouseExportSession,
+		Timeout:         cfg.WarehouseExportTimeout,
+	}
+	switch strings.ToLower(strings.TrimSpace(cfg.WarehouseExportSink)) {
+	case "":
+	case "s3":
+		sink, err := warehouse.NewS3(warehouseConfig)
+		if err != nil {
+			slog.Error("configure warehouse export", "error", err)
+			return
+		}
+		serviceOptions = append(serviceOptions, service.WithWarehouseExportSink(sink))
+		if strings.TrimSpace(warehouseExportRegion) == "" {
+			warehouseExportRegion = cfg.WarehouseExportBucketRegion
+		}
+	case "gcs":
+		sink, err := warehouse.NewGCS(warehouseConfig)
+		if err != nil {
+			slog.Error("configure warehouse export", "error", err)
+			return
+		}
+		serviceOptions = append(serviceOptions, service.WithWarehouseExportSink(sink))
+	default:
+		slog.Error("unsupported WAREHOUSE_EXPORT_SINK", "sink", cfg.WarehouseExportSink)
+		return
+	}
+
+	notificationsEnabled := strings.TrimSpace(cfg.NotificationEmailProvider) != "" || strings.TrimSpace(cfg.NotificationSMSURL) != "" || strings.TrimSpace(cfg.NotificationWhatsAppURL) != ""
+	switch strings.ToLower(strings.TrimSpace(cfg.NotificationEmailProvider)) {
+	case "":
+	case "smtp":
+		sender, err := notify.NewSMTP(notify.SMTPConfig{
+			Addr:     cfg.NotificationSMTPAddr,
+			Username: cfg.NotificationSMTPUsername,
+			Password: cfg.NotificationSMTPPassword,
+			From:     cfg.NotificationEmailFrom,
+			Timeout:  cfg.NotificationTimeout,
+		})
+		if err != nil {
+			slog.Error("configure smtp notifications", "error", err)
+			return
+		}
+		serviceOptions = append(serviceOptions, service.WithNotificationSender(service.NotificationChannelEmail, sender))
+	case "ses":
+		sender, err := notify.NewSES(notify.SESConfig{
+			Region:          cfg.NotificationSESRegion,
+			Endpoint:        cfg.NotificationSESEndpoint,
+			From:            cfg.NotificationEmailFrom,
+			AccessKeyID:     cfg.NotificationSESAccessKey,
+			SecretAccessKey: cfg.NotificationSESSecretKey,
+			SessionToken:    cfg.NotificationSESSession,
+			Timeout:         cfg.NotificationTimeout,
+		})
+		if err != nil {
+			slog.Error("configure ses notifications", "error", err)
+			return
+		}
+		serviceOptions = append(serviceOptions, service.WithNotificationSender(service.NotificationChannelEmail, sender))
+	default:
+		slog.Error("unsupported NOTIFICATION_EMAIL_PROVIDER", "provider", cfg.NotificationEmailProvider)
+		return
+	}
+	if smsURL := strings.TrimSpace(cfg.NotificationSMSURL); smsURL != "" {
+		serviceOptions = append(serviceOptions, service.WithNotificationSender(service.NotificationChannelSMS, notify.NewProvider(smsURL, cfg.NotificationSMSToken, cfg.NotificationTimeout)))
+	}
+	if whatsAppURL := strings.TrimSpace(cfg.NotificationWhatsAppURL); whatsAppURL != "" {
+		serviceOptions = append(serviceOptions, service.WithNotificationSender(service.NotificationChannelWhatsApp, notify.NewProvider(whatsAppURL, cfg.NotificationWhatsAppToken, cfg.NotificationTimeout)))
+	}
+	serviceOptions = append(serviceOptions, service.WithNotificationCallbackSecret(cfg.NotificationCallbackSecret))
+
+	serviceOptions = append(serviceOptions, service.WithDestinationRegions(service.DestinationRegions{
+		Attachments: cfg.AttachmentsRegion,
+		Webhooks:    cfg.WebhookRegion,
+		AuditExport: auditExportRegion,
+		Warehouse:   warehouseExportRegion,
+	}))
+
+	svc := service.New(database.Pool, serviceOptions...)
+	if err := svc.MigrateTenantSchemas(ctx); err != nil {
+		slog.Error("migrate tenant schemas", "error", err)
+		return
+	}
+	if err := svc.CheckDataResidency(ctx); err != nil {
+		slog.Error("destination configuration violates data residency", "error", err)
+		return
+	}
 	bootstrapEmail := strings.TrimSpace(cfg.BootstrapUserEmail)
 	bootstrapPassword := strings.TrimSpace(cfg.BootstrapUserPassword)
 	if bootstrapEmail != "" || bootstrapPassword != "" {
@@ -58,8 +290,229 @@ func main() {
 		}
 		slog.Info("bootstrap user ensured", "email", bootstrapEmail)
 	}
+	operatorEmail := strings.TrimSpace(cfg.AdminBootstrapEmail)
+	operatorPassword := strings.TrimSpace(cfg.AdminBootstrapPassword)
+	if operatorEmail != "" || operatorPassword != "" {
+		if operatorEmail == "" || operatorPassword == "" {
+			slog.Error("bootstrap operator requires both ADMIN_BOOTSTRAP_EMAIL and ADMIN_BOOTSTRAP_PASSWORD")
+			return
+		}
+		if err := svc.EnsurePlatformOperator(ctx, operatorEmail, operatorPassword); err != nil {
+			slog.Error("ensure bootstrap operator", "error", err)
+			return
+		}
+		slog.Info("bootstrap operator ensured", "email", operatorEmail)
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(ctx)
+	defer stopJobs()
+	go jobs.Every(jobsCtx, "temporary-assignment-expiry", cfg.AssignmentsInterval, func(ctx context.Context) error {
+		return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+			ended, err := svc.EndDueTemporaryAssignments(ctx)
+			if ended > 0 {
+				slog.InfoContext(ctx, "temporary assignments ended", "count", ended)
+			}
+			return err
+		})
+	})
+	go jobs.Every(jobsCtx, "task-automation", cfg.TaskAutomationInterval, func(ctx context.Context) error {
+		return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+			created, err := svc.GenerateAutomatedTasks(ctx)
+			if created > 0 {
+				slog.InfoContext(ctx, "automated tasks created", "count", created)
+			}
+			return err
+		})
+	})
+	if webhookURL != "" {
+		go jobs.Every(jobsCtx, "document-expiry-notifications", cfg.DocumentCheckInterval, func(ctx context.Context) error {
+			return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+				sent, err := svc.NotifyExpiringDocuments(ctx)
+				if sent > 0 {
+					slog.InfoContext(ctx, "document expiry notifications sent", "count", sent)
+				}
+				return err
+			})
+		})
+	}
+
+	if cfg.RetentionDays > 0 {
+		go jobs.Every(jobsCtx, "retention-purge", cfg.RetentionPurgeInterval, func(ctx context.Context) error {
+			return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+				purged, err := svc.PurgeExpiredDeletedRecords(ctx)
+				if purged > 0 {
+					slog.InfoContext(ctx, "expired deleted records purged", "count", purged)
+				}
+				return err
+			})
+		})
+	}
+
+	go jobs.Every(jobsCtx, "document-retention", cfg.RetentionPurgeInterval, func(ctx context.Context) error {
+		return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+			purged, err := svc.PurgeExpiredGeneratedDocuments(ctx)
+			if purged > 0 {
+				slog.InfoContext(ctx, "expired generated documents purged", "count", purged)
+			}
+			return err
+		})
+	})
+
+	go jobs.Every(jobsCtx, "photo-thumbnails", cfg.PhotoThumbnailInterval, func(ctx context.Context) error {
+		return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+			generated, err := svc.GenerateDentistPhotoThumbnails(ctx)
+			if generated > 0 {
+				slog.InfoContext(ctx, "dentist photo thumbnails generated", "count", generated)
+			}
+			return err
+		})
+	})
+
+	go jobs.Every(jobsCtx, "reports", cfg.ReportInterval, func(ctx context.Context) error {
+		return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+			generated, err := svc.GenerateDueReports(ctx)
+			if generated > 0 {
+				slog.InfoContext(ctx, "reports generated", "count", generated)
+			}
+			return err
+		})
+	})
+
+	go jobs.Every(jobsCtx, "report-definitions", cfg.ReportDefinitionInterval, func(ctx context.Context) error {
+		return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+			sent, err := svc.SendScheduledReports(ctx)
+			if sent > 0 {
+				slog.InfoContext(ctx, "scheduled reports sent", "count", sent)
+			}
+			return err
+		})
+	})
+
+	if strings.TrimSpace(cfg.ClamAVAddr) != "" {
+		go jobs.Every(jobsCtx, "attachment-scan", cfg.AttachmentScanInterval, func(ctx context.Context) error {
+			return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+				scanned, err := svc.ScanQuarantinedAttachments(ctx)
+				if scanned > 0 {
+					slog.InfoContext(ctx, "quarantined attachments scanned", "count", scanned)
+				}
+				return err
+			})
+		})
+	}
+
+	if cfg.DeletionGracePeriod > 0 {
+		go jobs.Every(jobsCtx, "pending-deletions", cfg.DeletionWorkerInterval, func(ctx context.Context) error {
+			return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+				completed, err := svc.CompleteDuePendingDeletions(ctx)
+				if completed > 0 {
+					slog.InfoContext(ctx, "pending deletions completed", "count", completed)
+				}
+				return err
+			})
+		})
+	}
+
+	go jobs.Every(jobsCtx, "organization-purge", cfg.OrganizationPurgeInterval, func(ctx context.Context) error {
+		purged, err := svc.PurgeDueOrganizations(ctx)
+		if purged > 0 {
+			slog.InfoContext(ctx, "offboarded organizations purged", "count", purged)
+		}
+		return err
+	})
+
+	if cfg.ReferenceCacheSize > 0 && cfg.ReferenceCacheTTL > 0 {
+		// Listen returns only when the connection drops; the next run subscribes again and starts from an empty cache.
+		go jobs.Every(jobsCtx, "reference-cache-invalidation", 5*time.Second, func(ctx context.Context) error {
+			return cacheInvalidations.Listen(ctx, func() { svc.InvalidateReferenceCache("") }, svc.InvalidateReferenceCache)
+		})
+	}
 
-	router := httpapi.NewRouter(svc, cfg.OTelServiceName)
+	go jobs.Every(jobsCtx, "usage-metering", cfg.UsageMeteringInterval, svc.FlushUsageMetering)
+
+	go jobs.Every(jobsCtx, "audit-chain-seal", cfg.AuditChainSealInterval, func(ctx context.Context) error {
+		return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+			sealed, err := svc.SealAuditLogs(ctx)
+			if sealed > 0 {
+				slog.DebugContext(ctx, "audit logs sealed", "count", sealed)
+			}
+			return err
+		})
+	})
+
+	go jobs.Every(jobsCtx, "integrity-check", cfg.IntegrityCheckInterval, func(ctx context.Context) error {
+		return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+			report, err := svc.RunIntegrityChecks(ctx, cfg.IntegrityAutoRepair)
+			for _, check := range report.Checks {
+				if remaining := check.Count - len(check.RepairedIDs); remaining > 0 {
+					slog.WarnContext(ctx, "integrity violations found", "check", check.Check, "count", remaining, "truncated", check.Truncated)
+				}
+			}
+			return err
+		})
+	})
+
+	if notificationsEnabled {
+		go jobs.Every(jobsCtx, "notification-delivery", cfg.NotificationDeliveryInterval, func(ctx context.Context) error {
+			return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+				sent, err := svc.DeliverDueNotifications(ctx)
+				if sent > 0 {
+					slog.InfoContext(ctx, "notifications delivered", "count", sent)
+				}
+				return err
+			})
+		})
+		go jobs.Every(jobsCtx, "equipment-maintenance-alerts", cfg.MaintenanceCheckInterval, func(ctx context.Context) error {
+			return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+				alerted, err := svc.NotifyOverdueMaintenance(ctx)
+				if alerted > 0 {
+					slog.InfoContext(ctx, "overdue maintenance alerts queued", "count", alerted)
+				}
+				return err
+			})
+		})
+	}
+
+	if strings.TrimSpace(cfg.AuditExportSink) != "" {
+		go jobs.Every(jobsCtx, "audit-log-export", cfg.AuditExportInterval, func(ctx context.Context) error {
+			return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+				exported, err := svc.ExportPendingAuditLogs(ctx)
+				if exported > 0 {
+					slog.InfoContext(ctx, "audit logs exported", "count", exported)
+				}
+				return err
+			})
+		})
+	}
+
+	if strings.TrimSpace(cfg.WarehouseExportSink) != "" {
+		go jobs.Every(jobsCtx, "warehouse-export", cfg.WarehouseExportInterval, func(ctx context.Context) error {
+			return svc.ForEachOrganization(ctx, func(ctx context.Context) error {
+				exported, err := svc.ExportWarehouseChanges(ctx)
+				if exported > 0 {
+					slog.InfoContext(ctx, "warehouse rows exported", "count", exported)
+				}
+				return err
+			})
+		})
+	}
+
+	router := httpapi.NewRouter(
+		svc,
+		cfg.OTelServiceName,
+		httpapi.WithPublicRateLimit(cfg.PublicRateLimit, cfg.PublicRateLimitWindow),
+		httpapi.WithPlatformAPIKey(cfg.PlatformAPIKey),
+		httpapi.WithTenantBaseDomain(cfg.TenantBaseDomain),
+		httpapi.WithAdminConsole(adminSecret != ""),
+		httpapi.WithLoadShedding(httpapi.LoadSheddingConfig{
+			MaxConcurrency: cfg.LoadSheddingMaxInFlight,
+			MinConcurrency: cfg.LoadSheddingMinInFlight,
+			TargetP99:      cfg.LoadSheddingTargetP99,
+		}),
+		httpapi.WithQueryTimeouts(httpapi.QueryTimeoutConfig{
+			Default: cfg.QueryTimeout,
+			Routes:  cfg.QueryTimeoutRoutes,
+		}),
+	)
 
 	slog.Info("api listening", "port", cfg.Port)
 	if err := router.Run(":" + cfg.Port); err != nil {
@@ -0,0 +1,50 @@
+-- name: UpsertAddress :one
+INSERT INTO addresses (
+    organization_id,
+    person_id,
+    street,
+    number,
+    complement,
+    city,
+    state,
+    cep
+) VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(person_id)::uuid,
+    sqlc.arg(street),
+    sqlc.narg(number),
+    sqlc.narg(complement),
+    sqlc.arg(city),
+    sqlc.arg(state),
+    sqlc.arg(cep)
+)
+ON CONFLICT (person_id) DO UPDATE
+SET street = EXCLUDED.street,
+    number = EXCLUDED.number,
+    complement = EXCLUDED.complement,
+    city = EXCLUDED.city,
+    state = EXCLUDED.state,
+    cep = EXCLUDED.cep,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetAddressByPersonID :one
+SELECT *
+FROM addresses
+WHERE person_id = sqlc.arg(person_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: ListAddressesByPersonIDs :many
+SELECT *
+FROM addresses
+WHERE person_id = ANY(sqlc.arg(person_ids)::uuid[])
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CopyAddress :execrows
+INSERT INTO addresses (organization_id, person_id, street, number, complement, city, state, cep)
+SELECT a.organization_id, sqlc.arg(target_person_id)::uuid, a.street, a.number, a.complement, a.city, a.state, a.cep
+FROM addresses a
+WHERE a.person_id = sqlc.arg(person_id)::uuid
+  AND a.organization_id = sqlc.arg(organization_id)::uuid
+ON CONFLICT (person_id) DO NOTHING;
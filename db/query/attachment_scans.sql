@@ -0,0 +1,69 @@
+-- name: CreateAttachmentScan :one
+INSERT INTO attachment_scans (
+    id,
+    organization_id,
+    resource_type,
+    resource_id,
+    quarantine_key,
+    content_type,
+    size_bytes,
+    uploaded_by_user_id
+)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(resource_type),
+    sqlc.arg(resource_id)::uuid,
+    sqlc.arg(quarantine_key),
+    sqlc.arg(content_type),
+    sqlc.arg(size_bytes),
+    sqlc.arg(uploaded_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: GetLatestAttachmentScan :one
+SELECT *
+FROM attachment_scans
+WHERE resource_type = sqlc.arg(resource_type)
+  AND resource_id = sqlc.arg(resource_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY created_at DESC, id DESC
+LIMIT 1;
+
+-- name: ClaimDueAttachmentScans :many
+UPDATE attachment_scans
+SET attempts = attempts + 1,
+    next_attempt_at = sqlc.arg(lease_until)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+    SELECT due.id
+    FROM attachment_scans due
+    WHERE due.organization_id = sqlc.arg(organization_id)::uuid
+      AND due.status = 'PENDING'
+      AND due.next_attempt_at <= sqlc.arg(due_before)::timestamptz
+    ORDER BY due.next_attempt_at
+    LIMIT sqlc.arg(page_limit)
+    FOR UPDATE SKIP LOCKED
+)
+  AND organization_id = sqlc.arg(organization_id)::uuid
+RETURNING *;
+
+-- name: RescheduleAttachmentScan :exec
+UPDATE attachment_scans
+SET next_attempt_at = sqlc.arg(next_attempt_at)::timestamptz,
+    failure_reason = sqlc.arg(failure_reason),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'PENDING';
+
+-- name: CompleteAttachmentScan :execrows
+UPDATE attachment_scans
+SET status = sqlc.arg(status),
+    signature = sqlc.narg(signature),
+    failure_reason = sqlc.narg(failure_reason),
+    scanned_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'PENDING';
@@ -0,0 +1,48 @@
+-- name: GetAuditChainHeadForUpdate :one
+SELECT *
+FROM audit_chain_head
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+FOR UPDATE;
+
+-- name: UpdateAuditChainHead :exec
+UPDATE audit_chain_head
+SET last_sequence = sqlc.arg(last_sequence)::bigint,
+    last_hash = sqlc.arg(last_hash),
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListUnsealedAuditLogs :many
+SELECT *
+FROM audit_logs
+WHERE chain_sequence IS NULL
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: SealAuditLog :execrows
+UPDATE audit_logs
+SET chain_sequence = sqlc.arg(chain_sequence)::bigint,
+    previous_hash = sqlc.arg(previous_hash)::text,
+    entry_hash = sqlc.arg(entry_hash)::text
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND chain_sequence IS NULL;
+
+-- name: ListSealedAuditLogs :many
+SELECT *
+FROM audit_logs
+WHERE chain_sequence > sqlc.arg(after_sequence)::bigint
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY chain_sequence
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetAuditChainHead :one
+SELECT *
+FROM audit_chain_head
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CountUnsealedAuditLogs :one
+SELECT COUNT(*)::bigint
+FROM audit_logs
+WHERE chain_sequence IS NULL
+  AND organization_id = sqlc.arg(organization_id)::uuid;
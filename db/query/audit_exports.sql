@@ -0,0 +1,38 @@
+-- name: EnsureAuditExportCheckpoint :exec
+INSERT INTO audit_export_checkpoints (organization_id, sink, last_audit_id)
+VALUES (sqlc.arg(organization_id)::uuid, sqlc.arg(sink), '00000000-0000-0000-0000-000000000000'::uuid)
+ON CONFLICT (organization_id, sink) DO NOTHING;
+
+-- name: GetAuditExportCheckpointForUpdate :one
+SELECT *
+FROM audit_export_checkpoints
+WHERE sink = sqlc.arg(sink)
+  AND organization_id = sqlc.arg(organization_id)::uuid
+FOR UPDATE;
+
+-- name: AdvanceAuditExportCheckpoint :exec
+UPDATE audit_export_checkpoints
+SET last_audit_id = sqlc.arg(last_audit_id)::uuid,
+    exported_count = exported_count + sqlc.arg(exported)::bigint,
+    updated_at = CURRENT_TIMESTAMP
+WHERE sink = sqlc.arg(sink)
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListAuditLogsForExport :many
+SELECT *
+FROM audit_logs
+WHERE id > sqlc.arg(after_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND created_at <= sqlc.arg(settled_before)::timestamptz
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListAuditLogsCreatedBetween :many
+SELECT *
+FROM audit_logs
+WHERE created_at >= sqlc.arg(created_from)::timestamptz
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND created_at < sqlc.arg(created_to)::timestamptz
+  AND (sqlc.narg(after_id)::uuid IS NULL OR id > sqlc.narg(after_id)::uuid)
+ORDER BY id
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,74 @@
+-- name: CreateAuditLog :exec
+INSERT INTO audit_logs (
+    id,
+    organization_id,
+    clinic_id,
+    actor_user_id,
+    action,
+    entity_type,
+    entity_id,
+    metadata
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.narg(clinic_id)::uuid,
+    sqlc.narg(actor_user_id)::uuid,
+    sqlc.arg(action),
+    sqlc.arg(entity_type),
+    sqlc.arg(entity_id)::uuid,
+    sqlc.arg(metadata)::jsonb
+);
+
+-- name: CreateAuditLogs :exec
+INSERT INTO audit_logs (
+    id,
+    organization_id,
+    clinic_id,
+    actor_user_id,
+    action,
+    entity_type,
+    entity_id,
+    metadata
+)
+SELECT
+    unnest(sqlc.arg(ids)::uuid[]),
+    sqlc.arg(organization_id)::uuid,
+    sqlc.narg(clinic_id)::uuid,
+    sqlc.narg(actor_user_id)::uuid,
+    unnest(sqlc.arg(actions)::text[]),
+    unnest(sqlc.arg(entity_types)::text[]),
+    unnest(sqlc.arg(entity_ids)::uuid[]),
+    unnest(sqlc.arg(metadata)::text[])::jsonb;
+
+-- name: ListAuditLogsByEntity :many
+SELECT *
+FROM audit_logs
+WHERE entity_type = sqlc.arg(entity_type)
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND entity_id = sqlc.arg(entity_id)::uuid
+ORDER BY created_at, id;
+
+-- name: ListClinicBankAccountHistoryCursor :many
+SELECT
+    a.id,
+    a.entity_id AS bank_account_id,
+    b.bank_code,
+    b.branch_number,
+    b.account_number,
+    a.action,
+    a.actor_user_id,
+    u.email AS actor_email,
+    a.metadata,
+    a.created_at
+FROM audit_logs a
+JOIN bank_accounts b ON b.id = a.entity_id
+LEFT JOIN users u ON u.id = a.actor_user_id
+WHERE a.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND a.organization_id = sqlc.arg(organization_id)::uuid
+  AND a.entity_type = sqlc.arg(entity_type)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (a.created_at, a.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY a.created_at, a.id
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,67 @@
+-- name: CreateBankAccountChange :one
+INSERT INTO bank_account_changes (
+    id,
+    organization_id,
+    clinic_id,
+    change_type,
+    bank_account_id,
+    bank_code,
+    branch_number,
+    account_number,
+    account_type,
+    holder_name,
+    holder_tax_id,
+    reason,
+    requested_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(change_type),
+    sqlc.narg(bank_account_id)::uuid,
+    sqlc.narg(bank_code),
+    sqlc.narg(branch_number),
+    sqlc.narg(account_number),
+    sqlc.narg(account_type),
+    sqlc.narg(holder_name),
+    sqlc.narg(holder_tax_id),
+    sqlc.narg(reason),
+    sqlc.arg(requested_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: GetBankAccountChange :one
+SELECT *
+FROM bank_account_changes
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+LIMIT 1;
+
+-- name: ListBankAccountChanges :many
+SELECT *
+FROM bank_account_changes
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+ORDER BY created_at DESC, id DESC;
+
+-- name: CountPendingBankAccountRemovals :one
+SELECT COUNT(*)::int
+FROM bank_account_changes
+WHERE bank_account_id = sqlc.arg(bank_account_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND change_type = 'REMOVE'
+  AND status = 'PENDING';
+
+-- name: ReviewBankAccountChange :execrows
+UPDATE bank_account_changes
+SET status = sqlc.arg(status),
+    bank_account_id = COALESCE(sqlc.narg(bank_account_id)::uuid, bank_account_id),
+    reviewed_by_user_id = sqlc.arg(reviewed_by_user_id)::uuid,
+    reviewed_at = CURRENT_TIMESTAMP,
+    review_notes = sqlc.narg(review_notes)
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND status = 'PENDING';
@@ -1,30 +1,79 @@
 -- name: CreateBankAccount :one
 INSERT INTO bank_accounts (
     id,
+    organization_id,
     clinic_id,
     bank_code,
     branch_number,
-    account_number
+    account_number,
+    account_type,
+    holder_name,
+    holder_tax_id,
+    holder_review_required,
+    is_primary
 ) VALUES (
     sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
     sqlc.arg(clinic_id)::uuid,
     sqlc.arg(bank_code),
     sqlc.arg(branch_number),
-    sqlc.arg(account_number)
+    sqlc.arg(account_number),
+    sqlc.arg(account_type),
+    sqlc.narg(holder_name),
+    sqlc.narg(holder_tax_id),
+    sqlc.arg(holder_review_required),
+    sqlc.arg(is_primary)
 )
 RETURNING *;
 
+-- name: CreateBankAccounts :exec
+INSERT INTO bank_accounts (
+    id,
+    organization_id,
+    clinic_id,
+    bank_code,
+    branch_number,
+    account_number,
+    account_type,
+    holder_name,
+    holder_tax_id,
+    holder_review_required,
+    is_primary
+)
+SELECT
+    unnest(sqlc.arg(ids)::uuid[]),
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    unnest(sqlc.arg(bank_codes)::text[]),
+    unnest(sqlc.arg(branch_numbers)::text[]),
+    unnest(sqlc.arg(account_numbers)::text[]),
+    unnest(sqlc.arg(account_types)::text[]),
+    unnest(sqlc.arg(holder_names)::text[]),
+    unnest(sqlc.arg(holder_tax_ids)::text[]),
+    unnest(sqlc.arg(holder_review_required)::boolean[]),
+    unnest(sqlc.arg(is_primary)::boolean[]);
+
 -- name: ListBankAccountsByClinicID :many
 SELECT *
 FROM bank_accounts
 WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL
-ORDER BY created_at DESC;
+ORDER BY is_primary DESC, created_at DESC;
+
+-- name: ListBankAccountsByClinicIDDeletedAt :many
+SELECT *
+FROM bank_accounts
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND (deleted_at = sqlc.arg(deleted_at)::timestamptz OR deleted_at IS NULL)
+ORDER BY is_primary DESC, created_at DESC;
 
 -- name: GetBankAccountByIDAndClinicID :one
 SELECT *
 FROM bank_accounts
 WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND clinic_id = sqlc.arg(clinic_id)::uuid
   AND deleted_at IS NULL
 LIMIT 1;
@@ -32,8 +81,10 @@ LIMIT 1;
 -- name: DeleteBankAccountByIDAndClinicID :execrows
 UPDATE bank_accounts
 SET deleted_at = CURRENT_TIMESTAMP,
+    is_primary = FALSE,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND clinic_id = sqlc.arg(clinic_id)::uuid
   AND deleted_at IS NULL;
 
@@ -42,4 +93,114 @@ UPDATE bank_accounts
 SET deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: ClearPrimaryBankAccount :exec
+UPDATE bank_accounts
+SET is_primary = FALSE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND is_primary
+  AND deleted_at IS NULL;
+
+-- name: SetPrimaryBankAccount :execrows
+UPDATE bank_accounts
+SET is_primary = TRUE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: UpdateBankAccount :one
+UPDATE bank_accounts
+SET bank_code = COALESCE(sqlc.narg(bank_code), bank_code),
+    branch_number = COALESCE(sqlc.narg(branch_number), branch_number),
+    account_number = COALESCE(sqlc.narg(account_number), account_number),
+    account_type = COALESCE(sqlc.narg(account_type), account_type),
+    holder_name = COALESCE(sqlc.narg(holder_name), holder_name),
+    holder_tax_id = COALESCE(sqlc.narg(holder_tax_id), holder_tax_id),
+    holder_review_required = sqlc.arg(holder_review_required),
+    verification_status = 'UNVERIFIED',
+    verification_provider = NULL,
+    verification_reference = NULL,
+    verification_requested_at = NULL,
+    verified_at = NULL,
+    verification_failure_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: StartBankAccountVerification :execrows
+UPDATE bank_accounts
+SET verification_status = 'PENDING',
+    verification_provider = sqlc.arg(provider)::text,
+    verification_reference = sqlc.arg(reference)::text,
+    verification_requested_at = CURRENT_TIMESTAMP,
+    verified_at = NULL,
+    verification_failure_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND verification_status <> 'VERIFIED'
+  AND deleted_at IS NULL;
+
+-- name: GetBankAccountByVerificationReference :one
+SELECT *
+FROM bank_accounts
+WHERE verification_reference = sqlc.arg(reference)::text
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: MarkBankAccountVerified :execrows
+UPDATE bank_accounts
+SET verification_status = 'VERIFIED',
+    verified_at = CURRENT_TIMESTAMP,
+    verification_failure_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE verification_reference = sqlc.arg(reference)::text
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND verification_status = 'PENDING'
+  AND deleted_at IS NULL;
+
+-- name: MarkBankAccountVerificationFailed :execrows
+UPDATE bank_accounts
+SET verification_status = 'UNVERIFIED',
+    verification_failure_reason = sqlc.narg(reason),
+    updated_at = CURRENT_TIMESTAMP
+WHERE verification_reference = sqlc.arg(reference)::text
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND verification_status = 'PENDING'
+  AND deleted_at IS NULL;
+
+-- name: ApproveBankAccountHolder :execrows
+UPDATE bank_accounts
+SET holder_review_required = FALSE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND holder_review_required
+  AND deleted_at IS NULL;
+
+-- name: RestoreBankAccountsDeletedAt :execrows
+UPDATE bank_accounts
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at = sqlc.arg(deleted_at)::timestamptz;
+
+-- name: DeleteBankAccountsByClinicIDAt :execrows
+UPDATE bank_accounts
+SET deleted_at = sqlc.arg(deleted_at)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL;
@@ -0,0 +1,153 @@
+-- name: CreateClinicAnnouncement :one
+INSERT INTO clinic_announcements (id, organization_id, clinic_id, author_user_id, title, body)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(author_user_id)::uuid,
+    sqlc.arg(title),
+    sqlc.arg(body)
+)
+RETURNING *;
+
+-- name: ListClinicAnnouncementAudience :many
+SELECT DISTINCT ON (cd.dentist_id)
+    cd.dentist_id,
+    p.legal_name,
+    p.email,
+    p.phone,
+    np.channels AS preferred_channels
+FROM clinic_dentists cd
+JOIN dentists d ON d.id = cd.dentist_id
+JOIN people p ON p.id = d.person_id
+LEFT JOIN dentist_notification_preferences np ON np.dentist_id = cd.dentist_id
+WHERE cd.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND cd.organization_id = sqlc.arg(organization_id)::uuid
+  AND cd.ended_at IS NULL
+  AND d.deleted_at IS NULL
+ORDER BY cd.dentist_id;
+
+-- name: CreateClinicAnnouncementRecipients :exec
+INSERT INTO clinic_announcement_recipients (organization_id, announcement_id, dentist_id)
+SELECT sqlc.arg(organization_id)::uuid, sqlc.arg(announcement_id)::uuid, dentist_id
+FROM unnest(sqlc.arg(dentist_ids)::uuid[]) AS dentist_id
+ON CONFLICT DO NOTHING;
+
+-- name: GetClinicAnnouncement :one
+SELECT
+    a.*,
+    COUNT(r.dentist_id)::bigint AS recipient_count,
+    COUNT(r.read_at)::bigint AS read_count
+FROM clinic_announcements a
+LEFT JOIN clinic_announcement_recipients r ON r.announcement_id = a.id
+WHERE a.id = sqlc.arg(id)::uuid
+  AND a.organization_id = sqlc.arg(organization_id)::uuid
+  AND a.clinic_id = sqlc.arg(clinic_id)::uuid
+GROUP BY a.id;
+
+-- name: ListClinicAnnouncementsCursor :many
+SELECT
+    a.*,
+    COUNT(r.dentist_id)::bigint AS recipient_count,
+    COUNT(r.read_at)::bigint AS read_count
+FROM clinic_announcements a
+LEFT JOIN clinic_announcement_recipients r ON r.announcement_id = a.id
+WHERE a.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND a.organization_id = sqlc.arg(organization_id)::uuid
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (a.created_at, a.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+GROUP BY a.id
+ORDER BY a.created_at, a.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListClinicAnnouncementReceipts :many
+SELECT
+    r.dentist_id,
+    p.legal_name,
+    r.read_at
+FROM clinic_announcement_recipients r
+JOIN dentists d ON d.id = r.dentist_id
+JOIN people p ON p.id = d.person_id
+WHERE r.announcement_id = sqlc.arg(announcement_id)::uuid
+  AND r.organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY p.legal_name, r.dentist_id;
+
+-- name: ListDentistAnnouncementsCursor :many
+SELECT
+    a.id,
+    a.clinic_id,
+    a.title,
+    a.body,
+    a.created_at,
+    r.read_at
+FROM clinic_announcement_recipients r
+JOIN clinic_announcements a ON a.id = r.announcement_id
+WHERE r.dentist_id = sqlc.arg(dentist_id)::uuid
+  AND r.organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(unread)::boolean IS NULL OR (r.read_at IS NULL) = sqlc.narg(unread)::boolean)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (a.created_at, a.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY a.created_at DESC, a.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: MarkClinicAnnouncementRead :one
+UPDATE clinic_announcement_recipients
+SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP)
+WHERE announcement_id = sqlc.arg(announcement_id)::uuid
+  AND dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+RETURNING read_at;
+
+-- name: GetDentistNotificationPreferences :one
+SELECT *
+FROM dentist_notification_preferences
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: UpsertDentistNotificationPreferences :one
+INSERT INTO dentist_notification_preferences (organization_id, dentist_id, channels)
+VALUES (sqlc.arg(organization_id)::uuid, sqlc.arg(dentist_id)::uuid, sqlc.arg(channels)::text[])
+ON CONFLICT (dentist_id) DO UPDATE
+SET channels = EXCLUDED.channels,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetClinicAnnouncementByID :one
+SELECT *
+FROM clinic_announcements
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CopyDentistAnnouncementRecipients :execrows
+INSERT INTO clinic_announcement_recipients (organization_id, announcement_id, dentist_id, read_at)
+SELECT r.organization_id, r.announcement_id, sqlc.arg(target_dentist_id)::uuid, r.read_at
+FROM clinic_announcement_recipients r
+WHERE r.dentist_id = sqlc.arg(dentist_id)::uuid
+  AND r.organization_id = sqlc.arg(organization_id)::uuid
+ON CONFLICT (announcement_id, dentist_id) DO NOTHING;
+
+-- name: DeleteDentistAnnouncementRecipients :execrows
+DELETE FROM clinic_announcement_recipients
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: MoveDentistNotificationPreferences :execrows
+UPDATE dentist_notification_preferences np
+SET dentist_id = sqlc.arg(target_dentist_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE np.dentist_id = sqlc.arg(dentist_id)::uuid
+  AND np.organization_id = sqlc.arg(organization_id)::uuid
+  AND NOT EXISTS (
+      SELECT 1
+      FROM dentist_notification_preferences existing
+      WHERE existing.dentist_id = sqlc.arg(target_dentist_id)::uuid
+  );
+
+-- name: DeleteDentistNotificationPreferences :execrows
+DELETE FROM dentist_notification_preferences
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
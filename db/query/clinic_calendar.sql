@@ -0,0 +1,50 @@
+-- name: ListClinicOperatingHours :many
+SELECT *
+FROM clinic_operating_hours
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY weekday, opens_minute;
+
+-- name: DeleteClinicOperatingHours :execrows
+DELETE FROM clinic_operating_hours
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CreateClinicOperatingHours :exec
+INSERT INTO clinic_operating_hours (organization_id, clinic_id, weekday, opens_minute, closes_minute)
+VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(weekday),
+    sqlc.arg(opens_minute),
+    sqlc.arg(closes_minute)
+);
+
+-- name: CreateClinicHoliday :one
+INSERT INTO clinic_holidays (id, organization_id, clinic_id, holiday_date, name)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(holiday_date),
+    sqlc.arg(name)
+)
+RETURNING *;
+
+-- name: ListClinicHolidays :many
+SELECT *
+FROM clinic_holidays
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND holiday_date BETWEEN sqlc.arg(from_date)::date AND sqlc.arg(to_date)::date
+ORDER BY holiday_date, id;
+
+-- name: DeleteClinicHoliday :execrows
+UPDATE clinic_holidays
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND deleted_at IS NULL;
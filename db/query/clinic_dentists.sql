@@ -1,16 +1,22 @@
 -- name: CreateClinicDentist :one
 INSERT INTO clinic_dentists (
+    organization_id,
     clinic_id,
     dentist_id,
     is_admin,
     is_legal_representative,
-    started_at
+    started_at,
+    planned_end_at,
+    substitute_for_dentist_id
 ) VALUES (
+    sqlc.arg(organization_id)::uuid,
     sqlc.arg(clinic_id)::uuid,
     sqlc.arg(dentist_id)::uuid,
     sqlc.arg(is_admin),
     sqlc.arg(is_legal_representative),
-    sqlc.arg(started_at)
+    sqlc.arg(started_at),
+    sqlc.narg(planned_end_at),
+    sqlc.narg(substitute_for_dentist_id)::uuid
 )
 RETURNING *;
 
@@ -18,6 +24,7 @@ RETURNING *;
 SELECT *
 FROM clinic_dentists
 WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND dentist_id = sqlc.arg(dentist_id)::uuid
   AND ended_at IS NULL
 ORDER BY started_at DESC
@@ -30,15 +37,49 @@ SET
     is_legal_representative = COALESCE(sqlc.narg(is_legal_representative), is_legal_representative),
     updated_at = CURRENT_TIMESTAMP
 WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND dentist_id = sqlc.arg(dentist_id)::uuid
   AND ended_at IS NULL
 RETURNING *;
 
+-- name: UpdateClinicDentistAssignment :one
+UPDATE clinic_dentists
+SET planned_end_at = sqlc.narg(planned_end_at),
+    substitute_for_dentist_id = sqlc.narg(substitute_for_dentist_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND dentist_id = sqlc.arg(dentist_id)::uuid
+  AND ended_at IS NULL
+RETURNING *;
+
+-- name: ListDueTemporaryClinicDentists :many
+SELECT *
+FROM clinic_dentists
+WHERE ended_at IS NULL
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND planned_end_at IS NOT NULL
+  AND planned_end_at <= sqlc.arg(now)::timestamptz
+ORDER BY planned_end_at, clinic_id, dentist_id
+LIMIT sqlc.arg(batch_size);
+
+-- name: EndDueTemporaryClinicDentist :execrows
+UPDATE clinic_dentists
+SET ended_at = planned_end_at,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND dentist_id = sqlc.arg(dentist_id)::uuid
+  AND ended_at IS NULL
+  AND planned_end_at IS NOT NULL
+  AND planned_end_at <= sqlc.arg(now)::timestamptz;
+
 -- name: EndClinicDentist :execrows
 UPDATE clinic_dentists
 SET ended_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND dentist_id = sqlc.arg(dentist_id)::uuid
   AND ended_at IS NULL;
 
@@ -47,6 +88,7 @@ UPDATE clinic_dentists
 SET ended_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND ended_at IS NULL;
 
 -- name: EndClinicDentistsByClinic :execrows
@@ -54,10 +96,96 @@ UPDATE clinic_dentists
 SET ended_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND ended_at IS NULL;
 
 -- name: CountActiveClinicLinksByDentist :one
 SELECT COUNT(*)::bigint
 FROM clinic_dentists
 WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND ended_at IS NULL;
+
+-- name: ListClinicDentistHistory :many
+SELECT *
+FROM clinic_dentists
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND dentist_id = sqlc.arg(dentist_id)::uuid
+ORDER BY started_at DESC;
+
+-- name: ListDentistEmploymentHistory :many
+SELECT
+    cd.clinic_id,
+    cd.dentist_id,
+    cd.is_admin,
+    cd.is_legal_representative,
+    cd.started_at,
+    cd.ended_at,
+    p.legal_name AS clinic_legal_name,
+    p.trade_name AS clinic_trade_name
+FROM clinic_dentists cd
+JOIN clinics c ON c.id = cd.clinic_id
+JOIN people p ON p.id = c.person_id
+WHERE cd.dentist_id = sqlc.arg(dentist_id)::uuid
+  AND cd.organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY cd.started_at DESC, cd.clinic_id;
+
+-- name: CountClinicRoleHolders :one
+SELECT
+    COUNT(*)::bigint AS active_dentists,
+    COUNT(*) FILTER (WHERE cd.is_admin)::bigint AS admins,
+    COUNT(*) FILTER (WHERE cd.is_legal_representative)::bigint AS legal_representatives
+FROM clinic_dentists cd
+JOIN dentists d ON d.id = cd.dentist_id
+WHERE cd.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND cd.organization_id = sqlc.arg(organization_id)::uuid
+  AND cd.ended_at IS NULL
+  AND d.deleted_at IS NULL;
+
+-- name: ListActiveClinicIDsByDentist :many
+SELECT clinic_id
+FROM clinic_dentists
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND ended_at IS NULL
+ORDER BY clinic_id;
+
+-- name: ListClinicDentistRowsByDentist :many
+SELECT *
+FROM clinic_dentists
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY clinic_id, started_at;
+
+-- name: ReassignClinicDentistRow :execrows
+UPDATE clinic_dentists
+SET dentist_id = sqlc.arg(target_dentist_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND dentist_id = sqlc.arg(dentist_id)::uuid
+  AND started_at = sqlc.arg(started_at);
+
+-- name: ReassignSubstituteFor :execrows
+UPDATE clinic_dentists
+SET substitute_for_dentist_id = CASE
+        WHEN dentist_id = sqlc.arg(target_dentist_id)::uuid THEN NULL
+        ELSE sqlc.arg(target_dentist_id)::uuid
+    END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE substitute_for_dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: IsClinicLegalRepresentativeTaxID :one
+SELECT EXISTS (
+    SELECT 1
+    FROM clinic_dentists cd
+    JOIN dentists d ON d.id = cd.dentist_id AND d.deleted_at IS NULL
+    JOIN people p ON p.id = d.person_id AND p.deleted_at IS NULL
+    WHERE cd.clinic_id = sqlc.arg(clinic_id)::uuid
+      AND cd.organization_id = sqlc.arg(organization_id)::uuid
+      AND cd.ended_at IS NULL
+      AND cd.is_legal_representative
+      AND p.tax_id_number = sqlc.arg(tax_id_number)::text
+)::boolean;
@@ -0,0 +1,82 @@
+-- name: GetClinicDirectoryListing :one
+SELECT *
+FROM clinic_directory_listings
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: UpsertClinicDirectoryListing :one
+INSERT INTO clinic_directory_listings (
+    organization_id,
+    clinic_id,
+    listed,
+    public_phone
+) VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(listed),
+    sqlc.narg(public_phone)
+)
+ON CONFLICT (clinic_id) DO UPDATE
+SET listed = EXCLUDED.listed,
+    public_phone = EXCLUDED.public_phone,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: ListPublicClinicDirectoryCursor :many
+SELECT
+    c.id,
+    COALESCE(p.trade_name, p.legal_name)::text AS display_name,
+    a.city,
+    a.state,
+    l.public_phone,
+    c.created_at
+FROM clinic_directory_listings l
+JOIN clinics c ON c.id = l.clinic_id
+JOIN people p ON p.id = c.person_id
+JOIN addresses a ON a.person_id = c.person_id
+WHERE l.listed
+  AND l.organization_id = sqlc.arg(organization_id)::uuid
+  AND c.deleted_at IS NULL
+  AND c.deactivated_at IS NULL
+  AND c.onboarding_status = 'ACTIVE'
+  AND p.deleted_at IS NULL
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (c.created_at, c.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+  AND (sqlc.narg(city)::text IS NULL OR lower(a.city) = lower(sqlc.narg(city)::text))
+  AND (sqlc.narg(state)::text IS NULL OR a.state = sqlc.narg(state)::text)
+  AND (
+      sqlc.narg(specialty)::text IS NULL
+      OR EXISTS (
+          SELECT 1
+          FROM clinic_dentists cd
+          JOIN dentists d ON d.id = cd.dentist_id
+          JOIN dentist_specialties ds ON ds.dentist_id = d.id
+          JOIN specialties s ON s.id = ds.specialty_id
+          WHERE cd.clinic_id = c.id
+            AND cd.ended_at IS NULL
+            AND d.deleted_at IS NULL
+            AND s.deleted_at IS NULL
+            AND s.code = sqlc.narg(specialty)::text
+      )
+  )
+ORDER BY c.created_at, c.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListPublicClinicSpecialties :many
+SELECT DISTINCT
+    cd.clinic_id,
+    s.code,
+    s.name
+FROM clinic_dentists cd
+JOIN dentists d ON d.id = cd.dentist_id
+JOIN dentist_specialties ds ON ds.dentist_id = d.id
+JOIN specialties s ON s.id = ds.specialty_id
+WHERE cd.clinic_id = ANY(sqlc.arg(clinic_ids)::uuid[])
+  AND cd.organization_id = sqlc.arg(organization_id)::uuid
+  AND cd.ended_at IS NULL
+  AND d.deleted_at IS NULL
+  AND s.deleted_at IS NULL
+ORDER BY cd.clinic_id, s.name;
@@ -0,0 +1,83 @@
+-- name: CreateClinicFinancialHold :exec
+INSERT INTO clinic_financial_holds (
+    id,
+    organization_id,
+    clinic_id,
+    reason_code,
+    notes,
+    placed_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(reason_code),
+    sqlc.narg(notes),
+    sqlc.arg(placed_by_user_id)::uuid
+);
+
+-- name: GetClinicFinancialHold :one
+SELECT
+    h.id,
+    h.clinic_id,
+    h.reason_code,
+    h.notes,
+    h.placed_by_user_id,
+    placer.email AS placed_by_email,
+    h.placed_at,
+    h.lifted_by_user_id,
+    lifter.email AS lifted_by_email,
+    h.lifted_at,
+    h.lift_notes
+FROM clinic_financial_holds h
+JOIN users placer ON placer.id = h.placed_by_user_id
+LEFT JOIN users lifter ON lifter.id = h.lifted_by_user_id
+WHERE h.id = sqlc.arg(id)::uuid
+  AND h.organization_id = sqlc.arg(organization_id)::uuid
+  AND h.clinic_id = sqlc.arg(clinic_id)::uuid;
+
+-- name: ListClinicFinancialHolds :many
+SELECT
+    h.id,
+    h.clinic_id,
+    h.reason_code,
+    h.notes,
+    h.placed_by_user_id,
+    placer.email AS placed_by_email,
+    h.placed_at,
+    h.lifted_by_user_id,
+    lifter.email AS lifted_by_email,
+    h.lifted_at,
+    h.lift_notes
+FROM clinic_financial_holds h
+JOIN users placer ON placer.id = h.placed_by_user_id
+LEFT JOIN users lifter ON lifter.id = h.lifted_by_user_id
+WHERE h.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND h.organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(active)::boolean IS NULL OR (h.lifted_at IS NULL) = sqlc.narg(active)::boolean)
+ORDER BY h.placed_at DESC, h.id DESC;
+
+-- name: LiftClinicFinancialHold :execrows
+UPDATE clinic_financial_holds
+SET lifted_by_user_id = sqlc.arg(lifted_by_user_id)::uuid,
+    lifted_at = CURRENT_TIMESTAMP,
+    lift_notes = sqlc.narg(lift_notes)
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND lifted_at IS NULL;
+
+-- name: HasActiveClinicFinancialHold :one
+SELECT EXISTS (
+    SELECT 1
+    FROM clinic_financial_holds
+    WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+      AND organization_id = sqlc.arg(organization_id)::uuid
+      AND lifted_at IS NULL
+);
+
+-- name: CountPayoutBatchItemsOnFinancialHold :one
+SELECT COUNT(DISTINCT i.clinic_id)::int
+FROM payout_batch_items i
+JOIN clinic_financial_holds h ON h.clinic_id = i.clinic_id AND h.lifted_at IS NULL
+WHERE i.batch_id = sqlc.arg(batch_id)::uuid
+  AND i.organization_id = sqlc.arg(organization_id)::uuid;
@@ -0,0 +1,102 @@
+-- name: CreateClinicNote :one
+INSERT INTO clinic_notes (id, organization_id, clinic_id, author_user_id, body, pinned)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(author_user_id)::uuid,
+    sqlc.arg(body),
+    sqlc.arg(pinned)
+)
+RETURNING *;
+
+-- name: CreateClinicNoteMention :exec
+INSERT INTO clinic_note_mentions (organization_id, note_id, entity_type, entity_id)
+VALUES (sqlc.arg(organization_id)::uuid, sqlc.arg(note_id)::uuid, sqlc.arg(entity_type), sqlc.arg(entity_id)::uuid)
+ON CONFLICT DO NOTHING;
+
+-- name: GetClinicNoteDetails :one
+SELECT
+    n.id,
+    n.clinic_id,
+    n.author_user_id,
+    u.email AS author_email,
+    n.body,
+    n.pinned,
+    n.created_at,
+    n.updated_at
+FROM clinic_notes n
+JOIN users u ON u.id = n.author_user_id
+WHERE n.id = sqlc.arg(id)::uuid
+  AND n.organization_id = sqlc.arg(organization_id)::uuid
+  AND n.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND n.deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListClinicNotesCursor :many
+SELECT
+    n.id,
+    n.clinic_id,
+    n.author_user_id,
+    u.email AS author_email,
+    n.body,
+    n.pinned,
+    n.created_at,
+    n.updated_at
+FROM clinic_notes n
+JOIN users u ON u.id = n.author_user_id
+WHERE n.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND n.organization_id = sqlc.arg(organization_id)::uuid
+  AND n.deleted_at IS NULL
+  AND (sqlc.narg(pinned)::boolean IS NULL OR n.pinned = sqlc.narg(pinned)::boolean)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (n.created_at, n.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY n.created_at, n.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListClinicNoteMentionsByNoteIDs :many
+SELECT *
+FROM clinic_note_mentions
+WHERE note_id = ANY(sqlc.arg(note_ids)::uuid[])
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY note_id, entity_type, entity_id;
+
+-- name: MoveDentistNoteMentions :execrows
+UPDATE clinic_note_mentions m
+SET entity_id = sqlc.arg(target_dentist_id)::uuid
+WHERE m.entity_type = 'DENTIST'
+  AND m.entity_id = sqlc.arg(dentist_id)::uuid
+  AND m.organization_id = sqlc.arg(organization_id)::uuid
+  AND NOT EXISTS (
+      SELECT 1
+      FROM clinic_note_mentions existing
+      WHERE existing.note_id = m.note_id
+        AND existing.entity_type = 'DENTIST'
+        AND existing.entity_id = sqlc.arg(target_dentist_id)::uuid
+  );
+
+-- name: DeleteDentistNoteMentions :execrows
+DELETE FROM clinic_note_mentions
+WHERE entity_type = 'DENTIST'
+  AND entity_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: UpdateClinicNotePinned :execrows
+UPDATE clinic_notes
+SET pinned = sqlc.arg(pinned),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: DeleteClinicNote :execrows
+UPDATE clinic_notes
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND deleted_at IS NULL;
@@ -0,0 +1,30 @@
+-- name: CreateClinicOnboardingTransition :one
+INSERT INTO clinic_onboarding_transitions (
+    id,
+    organization_id,
+    clinic_id,
+    from_status,
+    to_status,
+    evidence_reference,
+    bank_account_id,
+    notes,
+    performed_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(from_status),
+    sqlc.arg(to_status),
+    sqlc.arg(evidence_reference),
+    sqlc.narg(bank_account_id)::uuid,
+    sqlc.narg(notes),
+    sqlc.narg(performed_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: ListClinicOnboardingTransitions :many
+SELECT *
+FROM clinic_onboarding_transitions
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY created_at, id;
@@ -0,0 +1,42 @@
+-- name: UpsertClinicRegistryRecord :one
+INSERT INTO clinic_registry_records (
+    organization_id,
+    clinic_id,
+    legal_name,
+    trade_name,
+    cnae_code,
+    cnae_description,
+    registration_status,
+    source
+) VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(legal_name),
+    sqlc.narg(trade_name),
+    sqlc.narg(cnae_code),
+    sqlc.narg(cnae_description),
+    sqlc.arg(registration_status),
+    sqlc.arg(source)
+)
+ON CONFLICT (clinic_id) DO UPDATE
+SET legal_name = EXCLUDED.legal_name,
+    trade_name = EXCLUDED.trade_name,
+    cnae_code = EXCLUDED.cnae_code,
+    cnae_description = EXCLUDED.cnae_description,
+    registration_status = EXCLUDED.registration_status,
+    source = EXCLUDED.source,
+    fetched_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetClinicRegistryRecordByClinicID :one
+SELECT *
+FROM clinic_registry_records
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: ListClinicRegistryRecordsByClinicIDs :many
+SELECT *
+FROM clinic_registry_records
+WHERE clinic_id = ANY(sqlc.arg(clinic_ids)::uuid[])
+  AND organization_id = sqlc.arg(organization_id)::uuid;
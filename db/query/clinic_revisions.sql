@@ -0,0 +1,75 @@
+-- name: CreateClinicRevision :exec
+INSERT INTO clinic_revisions (
+    id,
+    organization_id,
+    clinic_id,
+    entity_type,
+    entity_id,
+    operation,
+    before_state,
+    after_state,
+    changed_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(entity_type),
+    sqlc.arg(entity_id)::uuid,
+    sqlc.arg(operation),
+    sqlc.arg(before_state)::jsonb,
+    sqlc.arg(after_state)::jsonb,
+    sqlc.narg(changed_by_user_id)::uuid
+);
+
+-- name: ListClinicRevisionsCursor :many
+SELECT
+    r.id,
+    r.entity_type,
+    r.entity_id,
+    r.operation,
+    r.before_state,
+    r.after_state,
+    r.changed_by_user_id,
+    u.email AS changed_by_email,
+    r.changed_at
+FROM clinic_revisions r
+LEFT JOIN users u ON u.id = r.changed_by_user_id
+WHERE r.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND r.organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(entity_type)::text IS NULL OR r.entity_type = sqlc.narg(entity_type)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (r.changed_at, r.id) > (sqlc.narg(cursor_changed_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY r.changed_at, r.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetClinicForRevision :one
+SELECT *
+FROM clinics
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: ListBankAccountsForRevision :many
+SELECT *
+FROM bank_accounts
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY id;
+
+-- name: ListClinicRevisionsByClinicID :many
+SELECT *
+FROM clinic_revisions
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY id;
+
+-- name: ListClinicDentistIDsAsOf :many
+SELECT DISTINCT dentist_id
+FROM clinic_dentists
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND started_at <= sqlc.arg(as_of)::timestamptz
+  AND (ended_at IS NULL OR ended_at > sqlc.arg(as_of)::timestamptz)
+ORDER BY dentist_id;
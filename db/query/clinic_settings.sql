@@ -0,0 +1,33 @@
+-- name: GetClinicSettings :one
+SELECT *
+FROM clinic_settings
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: UpsertClinicSettings :one
+INSERT INTO clinic_settings (
+    organization_id,
+    clinic_id,
+    default_appointment_duration_minutes,
+    reminder_lead_minutes,
+    invoice_number_prefix,
+    locale,
+    currency
+) VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(default_appointment_duration_minutes),
+    sqlc.arg(reminder_lead_minutes)::int[],
+    sqlc.arg(invoice_number_prefix),
+    sqlc.arg(locale),
+    sqlc.arg(currency)
+)
+ON CONFLICT (clinic_id) DO UPDATE
+SET default_appointment_duration_minutes = EXCLUDED.default_appointment_duration_minutes,
+    reminder_lead_minutes = EXCLUDED.reminder_lead_minutes,
+    invoice_number_prefix = EXCLUDED.invoice_number_prefix,
+    locale = EXCLUDED.locale,
+    currency = EXCLUDED.currency,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
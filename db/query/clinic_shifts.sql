@@ -0,0 +1,111 @@
+-- name: CreateClinicShift :one
+INSERT INTO clinic_shifts (id, organization_id, clinic_id, dentist_id, starts_at, ends_at, notes, created_by_user_id)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(dentist_id)::uuid,
+    sqlc.arg(starts_at)::timestamptz,
+    sqlc.arg(ends_at)::timestamptz,
+    sqlc.narg(notes),
+    sqlc.narg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: GetClinicShift :one
+SELECT *
+FROM clinic_shifts
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: UpdateClinicShift :one
+UPDATE clinic_shifts
+SET starts_at = sqlc.arg(starts_at)::timestamptz,
+    ends_at = sqlc.arg(ends_at)::timestamptz,
+    notes = sqlc.narg(notes),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteClinicShift :execrows
+UPDATE clinic_shifts
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- Serializes shift writes per dentist, since an overlap can come from a shift at any clinic of the organization.
+-- name: LockDentistForShift :one
+SELECT id
+FROM dentists
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: GetOverlappingDentistShift :one
+SELECT *
+FROM clinic_shifts
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND starts_at < sqlc.arg(ends_at)::timestamptz
+  AND ends_at > sqlc.arg(starts_at)::timestamptz
+  AND (sqlc.narg(exclude_id)::uuid IS NULL OR id <> sqlc.narg(exclude_id)::uuid)
+ORDER BY starts_at, id
+LIMIT 1;
+
+-- Shifts outside the dentist's link with the clinic drop off the board, so unlinking needs no cleanup here.
+-- name: ListClinicShiftsBetween :many
+SELECT
+    s.*,
+    p.legal_name AS dentist_name
+FROM clinic_shifts s
+JOIN dentists d ON d.id = s.dentist_id
+JOIN people p ON p.id = d.person_id
+WHERE s.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND s.organization_id = sqlc.arg(organization_id)::uuid
+  AND s.deleted_at IS NULL
+  AND d.deleted_at IS NULL
+  AND s.starts_at >= sqlc.arg(from_at)::timestamptz
+  AND s.starts_at < sqlc.arg(to_at)::timestamptz
+  AND EXISTS (
+      SELECT 1
+      FROM clinic_dentists cd
+      WHERE cd.clinic_id = s.clinic_id
+        AND cd.dentist_id = s.dentist_id
+        AND cd.started_at <= s.starts_at
+        AND (cd.ended_at IS NULL OR cd.ended_at > s.starts_at)
+  )
+ORDER BY s.starts_at, s.id;
+
+-- name: GetMergedDentistShiftOverlap :one
+SELECT
+    s.starts_at,
+    s.ends_at,
+    t.clinic_id
+FROM clinic_shifts s
+JOIN clinic_shifts t
+  ON t.dentist_id = sqlc.arg(target_dentist_id)::uuid
+ AND t.organization_id = s.organization_id
+ AND t.deleted_at IS NULL
+ AND t.starts_at < s.ends_at
+ AND t.ends_at > s.starts_at
+WHERE s.dentist_id = sqlc.arg(dentist_id)::uuid
+  AND s.organization_id = sqlc.arg(organization_id)::uuid
+  AND s.deleted_at IS NULL
+ORDER BY s.starts_at, s.id
+LIMIT 1;
+
+-- name: MoveDentistShifts :execrows
+UPDATE clinic_shifts
+SET dentist_id = sqlc.arg(target_dentist_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
@@ -0,0 +1,227 @@
+-- name: CreateClinicTask :one
+INSERT INTO clinic_tasks (
+    id,
+    organization_id,
+    clinic_id,
+    title,
+    description,
+    due_date,
+    assignee_user_id,
+    entity_type,
+    entity_id,
+    created_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(title),
+    sqlc.narg(description),
+    sqlc.narg(due_date)::date,
+    sqlc.narg(assignee_user_id)::uuid,
+    sqlc.narg(entity_type),
+    sqlc.narg(entity_id)::uuid,
+    sqlc.arg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: CreateAutomatedClinicTask :execrows
+INSERT INTO clinic_tasks (
+    id,
+    organization_id,
+    clinic_id,
+    title,
+    due_date,
+    assignee_user_id,
+    entity_type,
+    entity_id,
+    automation_trigger,
+    automation_key
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(title),
+    sqlc.arg(due_date)::date,
+    sqlc.narg(assignee_user_id)::uuid,
+    sqlc.arg(entity_type)::text,
+    sqlc.arg(entity_id)::uuid,
+    sqlc.arg(automation_trigger)::text,
+    sqlc.arg(automation_key)::text
+)
+ON CONFLICT (organization_id, automation_key) WHERE automation_key IS NOT NULL DO NOTHING;
+
+-- name: GetClinicTaskForUpdate :one
+SELECT *
+FROM clinic_tasks
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND deleted_at IS NULL
+LIMIT 1
+FOR UPDATE;
+
+-- name: GetAssignedTaskForUpdate :one
+SELECT *
+FROM clinic_tasks
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND assignee_user_id = sqlc.arg(assignee_user_id)::uuid
+  AND deleted_at IS NULL
+LIMIT 1
+FOR UPDATE;
+
+-- name: UpdateClinicTask :one
+UPDATE clinic_tasks
+SET title = sqlc.arg(title),
+    description = sqlc.narg(description),
+    status = sqlc.arg(status),
+    due_date = sqlc.narg(due_date)::date,
+    assignee_user_id = sqlc.narg(assignee_user_id)::uuid,
+    completed_at = sqlc.narg(completed_at)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteClinicTask :execrows
+UPDATE clinic_tasks
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: MoveDentistClinicTasks :execrows
+UPDATE clinic_tasks
+SET entity_id = sqlc.arg(target_dentist_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE entity_type = 'DENTIST'
+  AND entity_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListClinicTasksCursor :many
+SELECT t.*
+FROM clinic_tasks t
+WHERE t.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND t.organization_id = sqlc.arg(organization_id)::uuid
+  AND t.deleted_at IS NULL
+  AND (cardinality(sqlc.arg(statuses)::text[]) = 0 OR t.status = ANY(sqlc.arg(statuses)::text[]))
+  AND (sqlc.narg(assignee_user_id)::uuid IS NULL OR t.assignee_user_id = sqlc.narg(assignee_user_id)::uuid)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (COALESCE(t.due_date, 'infinity'::date), t.id) > (COALESCE(sqlc.narg(cursor_due_date)::date, 'infinity'::date), sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY COALESCE(t.due_date, 'infinity'::date), t.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListAssignedTasksCursor :many
+SELECT t.*
+FROM clinic_tasks t
+JOIN clinics c ON c.id = t.clinic_id
+WHERE t.assignee_user_id = sqlc.arg(assignee_user_id)::uuid
+  AND t.organization_id = sqlc.arg(organization_id)::uuid
+  AND t.deleted_at IS NULL
+  AND c.deleted_at IS NULL
+  AND t.status = ANY(sqlc.arg(statuses)::text[])
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (COALESCE(t.due_date, 'infinity'::date), t.id) > (COALESCE(sqlc.narg(cursor_due_date)::date, 'infinity'::date), sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY COALESCE(t.due_date, 'infinity'::date), t.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListClinicTaskRules :many
+SELECT *
+FROM clinic_task_rules
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY trigger_type;
+
+-- name: DeleteClinicTaskRules :execrows
+DELETE FROM clinic_task_rules
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CreateClinicTaskRule :one
+INSERT INTO clinic_task_rules (organization_id, clinic_id, trigger_type, assignee_user_id, due_in_days, lead_days)
+VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(trigger_type),
+    sqlc.narg(assignee_user_id)::uuid,
+    sqlc.arg(due_in_days),
+    sqlc.arg(lead_days)
+)
+RETURNING *;
+
+-- name: ListMaintenanceOverdueTaskCandidates :many
+SELECT
+    r.clinic_id,
+    r.assignee_user_id,
+    r.due_in_days,
+    e.id AS entity_id,
+    e.name AS equipment_name,
+    e.next_maintenance_due::date AS next_maintenance_due
+FROM clinic_task_rules r
+JOIN clinics c ON c.id = r.clinic_id
+JOIN equipment e ON e.clinic_id = r.clinic_id
+WHERE r.organization_id = sqlc.arg(organization_id)::uuid
+  AND r.trigger_type = 'EQUIPMENT_MAINTENANCE_OVERDUE'
+  AND c.deleted_at IS NULL
+  AND c.deactivated_at IS NULL
+  AND e.deleted_at IS NULL
+  AND e.next_maintenance_due < sqlc.arg(today)::date
+ORDER BY e.next_maintenance_due, e.id;
+
+-- name: ListDocumentExpiringTaskCandidates :many
+SELECT
+    r.clinic_id,
+    r.assignee_user_id,
+    r.due_in_days,
+    cd.dentist_id,
+    dd.id AS document_id,
+    dd.document_type,
+    dd.expires_at
+FROM clinic_task_rules r
+JOIN clinics c ON c.id = r.clinic_id
+JOIN clinic_dentists cd ON cd.clinic_id = r.clinic_id
+JOIN dentists d ON d.id = cd.dentist_id
+JOIN dentist_documents dd ON dd.dentist_id = d.id
+WHERE r.organization_id = sqlc.arg(organization_id)::uuid
+  AND r.trigger_type = 'DENTIST_DOCUMENT_EXPIRING'
+  AND c.deleted_at IS NULL
+  AND c.deactivated_at IS NULL
+  AND cd.ended_at IS NULL
+  AND d.deleted_at IS NULL
+  AND dd.deleted_at IS NULL
+  AND dd.expires_at <= sqlc.arg(today)::date + r.lead_days
+ORDER BY dd.expires_at, dd.id, r.clinic_id;
+
+-- name: ListPendingBankAccountChangeTaskCandidates :many
+SELECT
+    r.clinic_id,
+    r.assignee_user_id,
+    r.due_in_days,
+    b.id AS entity_id,
+    b.change_type,
+    b.created_at
+FROM clinic_task_rules r
+JOIN clinics c ON c.id = r.clinic_id
+JOIN bank_account_changes b ON b.clinic_id = r.clinic_id
+WHERE r.organization_id = sqlc.arg(organization_id)::uuid
+  AND r.trigger_type = 'BANK_ACCOUNT_CHANGE_PENDING'
+  AND c.deleted_at IS NULL
+  AND b.status = 'PENDING'
+ORDER BY b.created_at, b.id;
+
+-- name: ListClinicTasksDueOn :many
+SELECT *
+FROM clinic_tasks
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND status = ANY(sqlc.arg(statuses)::text[])
+  AND due_date = sqlc.arg(due_date)::date
+ORDER BY created_at, id;
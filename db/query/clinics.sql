@@ -1,19 +1,59 @@
 -- name: CreateClinic :one
-INSERT INTO clinics (id, person_id)
-VALUES (sqlc.arg(id)::uuid, sqlc.arg(person_id)::uuid)
+INSERT INTO clinics (id, organization_id, person_id, parent_clinic_id, timezone)
+VALUES (sqlc.arg(id)::uuid, sqlc.arg(organization_id)::uuid, sqlc.arg(person_id)::uuid, sqlc.narg(parent_clinic_id)::uuid, sqlc.arg(timezone))
 RETURNING *;
 
+-- name: UpdateClinicTimezone :execrows
+UPDATE clinics
+SET timezone = sqlc.arg(timezone),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
 -- name: GetClinicByID :one
 SELECT *
 FROM clinics
 WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL
 LIMIT 1;
 
+-- name: UpdateClinicOnboardingStatus :execrows
+UPDATE clinics
+SET onboarding_status = sqlc.arg(to_status),
+    onboarding_status_changed_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND onboarding_status = sqlc.arg(from_status)
+  AND deleted_at IS NULL;
+
+-- name: DeactivateClinic :execrows
+UPDATE clinics
+SET deactivated_at = CURRENT_TIMESTAMP,
+    deactivation_reason = sqlc.narg(reason),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deactivated_at IS NULL
+  AND deleted_at IS NULL;
+
+-- name: ReactivateClinic :execrows
+UPDATE clinics
+SET deactivated_at = NULL,
+    deactivation_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deactivated_at IS NOT NULL
+  AND deleted_at IS NULL;
+
 -- name: LockClinicForUpdate :one
 SELECT id
 FROM clinics
 WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL
 FOR UPDATE;
 
@@ -22,12 +62,19 @@ UPDATE clinics
 SET deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL;
 
 -- name: GetClinicDetails :one
 SELECT
     c.id AS clinic_id,
     c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
     p.legal_name,
     p.trade_name,
     p.tax_id_number,
@@ -36,23 +83,258 @@ SELECT
 FROM clinics c
 JOIN people p ON p.id = c.person_id
 WHERE c.id = sqlc.arg(id)::uuid
+  AND c.organization_id = sqlc.arg(organization_id)::uuid
   AND c.deleted_at IS NULL
   AND p.deleted_at IS NULL
 LIMIT 1;
 
--- name: ListClinicDetailsCursor :many
+-- name: GetClinicAggregate :one
 SELECT
     c.id AS clinic_id,
     c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
     p.legal_name,
     p.trade_name,
     p.tax_id_number,
     p.email,
-    p.phone
+    p.phone,
+    a.street AS address_street,
+    a.number AS address_number,
+    a.complement AS address_complement,
+    a.city AS address_city,
+    a.state AS address_state,
+    a.cep AS address_cep,
+    r.legal_name AS registry_legal_name,
+    r.trade_name AS registry_trade_name,
+    r.cnae_code AS registry_cnae_code,
+    r.cnae_description AS registry_cnae_description,
+    r.registration_status AS registry_registration_status,
+    r.source AS registry_source,
+    r.fetched_at AS registry_fetched_at,
+    COALESCE((
+        SELECT jsonb_agg(cd.dentist_id ORDER BY cd.dentist_id)
+        FROM clinic_dentists cd
+        JOIN dentists d ON d.id = cd.dentist_id
+        JOIN people dp ON dp.id = d.person_id
+        WHERE cd.clinic_id = c.id
+          AND cd.organization_id = c.organization_id
+          AND cd.ended_at IS NULL
+          AND d.deleted_at IS NULL
+          AND dp.deleted_at IS NULL
+    ), '[]'::jsonb)::jsonb AS dentist_ids,
+    COALESCE((
+        SELECT jsonb_agg(to_jsonb(b) ORDER BY b.is_primary DESC, b.created_at DESC)
+        FROM bank_accounts b
+        WHERE sqlc.arg(include_bank_accounts)::boolean
+          AND b.clinic_id = c.id
+          AND b.organization_id = c.organization_id
+          AND b.deleted_at IS NULL
+    ), '[]'::jsonb)::jsonb AS bank_accounts
 FROM clinics c
 JOIN people p ON p.id = c.person_id
-WHERE c.deleted_at IS NULL
+LEFT JOIN addresses a ON a.person_id = c.person_id AND a.organization_id = c.organization_id
+LEFT JOIN clinic_registry_records r ON r.clinic_id = c.id AND r.organization_id = c.organization_id
+WHERE c.id = sqlc.arg(id)::uuid
+  AND c.organization_id = sqlc.arg(organization_id)::uuid
+  AND c.deleted_at IS NULL
   AND p.deleted_at IS NULL
-  AND (sqlc.narg(after_id)::uuid IS NULL OR c.id > sqlc.narg(after_id)::uuid)
-ORDER BY c.id
+LIMIT 1;
+
+-- name: GetClinicDetailsIncludingDeleted :one
+SELECT
+    c.id AS clinic_id,
+    c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
+    p.legal_name,
+    p.trade_name,
+    p.tax_id_number,
+    p.email,
+    p.phone,
+    c.deleted_at
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+WHERE c.id = sqlc.arg(id)::uuid
+  AND c.organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: ListClinicDetailsCursor :many
+SELECT
+    c.id AS clinic_id,
+    c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
+    p.legal_name,
+    p.trade_name,
+    p.tax_id_number,
+    p.email,
+    p.phone,
+    c.deleted_at,
+    c.created_at
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+WHERE c.organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.arg(include_deleted)::boolean OR (c.deleted_at IS NULL AND p.deleted_at IS NULL))
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (c.created_at, c.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+  AND (sqlc.narg(onboarding_status)::text IS NULL OR c.onboarding_status = sqlc.narg(onboarding_status)::text)
+  AND (sqlc.narg(status_changed_before)::timestamptz IS NULL OR c.onboarding_status_changed_at < sqlc.narg(status_changed_before)::timestamptz)
+ORDER BY c.created_at, c.id
 LIMIT sqlc.arg(page_limit);
+
+-- name: ListBranchClinicDetails :many
+SELECT
+    c.id AS clinic_id,
+    c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
+    p.legal_name,
+    p.trade_name,
+    p.tax_id_number,
+    p.email,
+    p.phone,
+    c.deleted_at,
+    c.created_at
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+WHERE c.parent_clinic_id = sqlc.arg(parent_clinic_id)::uuid
+  AND c.organization_id = sqlc.arg(organization_id)::uuid
+  AND c.deleted_at IS NULL
+  AND p.deleted_at IS NULL
+ORDER BY c.id;
+
+-- name: CountActiveBranches :one
+SELECT COUNT(*)::int
+FROM clinics
+WHERE parent_clinic_id = sqlc.arg(parent_clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: ListClinicGroupReport :many
+SELECT
+    c.id AS clinic_id,
+    c.parent_clinic_id,
+    p.legal_name,
+    p.trade_name,
+    p.tax_id_number,
+    COUNT(cd.dentist_id)::int AS active_dentists,
+    COUNT(cd.dentist_id) FILTER (WHERE cd.is_admin)::int AS admins,
+    COUNT(cd.dentist_id) FILTER (WHERE cd.is_legal_representative)::int AS legal_representatives,
+    COUNT(cd.dentist_id) FILTER (WHERE cd.planned_end_at IS NOT NULL)::int AS temporary_dentists
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+LEFT JOIN clinic_dentists cd ON cd.clinic_id = c.id AND cd.ended_at IS NULL
+WHERE c.organization_id = sqlc.arg(organization_id)::uuid
+  AND (c.id = sqlc.arg(group_clinic_id)::uuid OR c.parent_clinic_id = sqlc.arg(group_clinic_id)::uuid)
+  AND c.deleted_at IS NULL
+  AND p.deleted_at IS NULL
+GROUP BY c.id, c.parent_clinic_id, p.legal_name, p.trade_name, p.tax_id_number
+ORDER BY c.parent_clinic_id NULLS FIRST, c.id;
+
+-- name: CountDistinctActiveDentistsInClinicGroup :one
+SELECT COUNT(DISTINCT cd.dentist_id)::int
+FROM clinic_dentists cd
+JOIN clinics c ON c.id = cd.clinic_id
+WHERE cd.organization_id = sqlc.arg(organization_id)::uuid
+  AND (c.id = sqlc.arg(group_clinic_id)::uuid OR c.parent_clinic_id = sqlc.arg(group_clinic_id)::uuid)
+  AND c.deleted_at IS NULL
+  AND cd.ended_at IS NULL;
+
+-- name: ListClinicDuplicateCandidates :many
+SELECT
+    c.id AS clinic_id,
+    p.legal_name,
+    p.tax_id_number,
+    c.deleted_at,
+    similarity(p.legal_name, sqlc.arg(legal_name)::text)::float8 AS similarity
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+WHERE c.organization_id = sqlc.arg(organization_id)::uuid
+  AND (
+    (c.deleted_at IS NOT NULL AND p.tax_id_number = sqlc.arg(tax_id_number)::text)
+    OR (
+        c.deleted_at IS NULL
+        AND p.deleted_at IS NULL
+        AND p.legal_name % sqlc.arg(legal_name)::text
+        AND similarity(p.legal_name, sqlc.arg(legal_name)::text) >= sqlc.arg(min_similarity)::float8
+        AND (
+            sqlc.narg(group_clinic_id)::uuid IS NULL
+            OR (c.id <> sqlc.narg(group_clinic_id)::uuid AND c.parent_clinic_id IS DISTINCT FROM sqlc.narg(group_clinic_id)::uuid)
+        )
+    )
+  )
+ORDER BY similarity DESC, c.id
+LIMIT sqlc.arg(max_candidates)::int;
+
+-- name: LockDeletedClinicForUpdate :one
+SELECT *
+FROM clinics
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NOT NULL
+FOR UPDATE;
+
+-- name: RestoreClinic :execrows
+UPDATE clinics
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NOT NULL;
+
+-- name: GetClinicDeletePreviewCounts :one
+SELECT
+    (
+        SELECT COUNT(*)
+        FROM clinic_dentists cd
+        WHERE cd.clinic_id = sqlc.arg(clinic_id)::uuid
+          AND cd.organization_id = sqlc.arg(organization_id)::uuid
+          AND cd.ended_at IS NULL
+    )::bigint AS dentist_links,
+    (
+        SELECT COUNT(*)
+        FROM bank_accounts ba
+        WHERE ba.clinic_id = sqlc.arg(clinic_id)::uuid
+          AND ba.organization_id = sqlc.arg(organization_id)::uuid
+          AND ba.deleted_at IS NULL
+    )::bigint AS bank_accounts,
+    (
+        SELECT COUNT(*)
+        FROM clinics b
+        WHERE b.parent_clinic_id = sqlc.arg(clinic_id)::uuid
+          AND b.organization_id = sqlc.arg(organization_id)::uuid
+          AND b.deleted_at IS NULL
+    )::bigint AS active_branches,
+    (
+        SELECT COUNT(*)
+        FROM ledger_transactions lt
+        WHERE lt.clinic_id = sqlc.arg(clinic_id)::uuid
+          AND lt.organization_id = sqlc.arg(organization_id)::uuid
+          AND lt.kind = 'INVOICE'
+    )::bigint AS invoices,
+    (
+        SELECT COUNT(*)
+        FROM clinic_payables cp
+        WHERE cp.clinic_id = sqlc.arg(clinic_id)::uuid
+          AND cp.organization_id = sqlc.arg(organization_id)::uuid
+          AND cp.payout_batch_id IS NULL
+    )::bigint AS open_payables;
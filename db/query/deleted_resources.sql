@@ -0,0 +1,40 @@
+-- name: ListDeletedResourcesCursor :many
+WITH deleted AS (
+    SELECT
+        'CLINIC'::text AS resource_type,
+        c.id,
+        p.legal_name,
+        p.tax_id_number,
+        c.deleted_at
+    FROM clinics c
+    JOIN people p ON p.id = c.person_id
+    WHERE c.deleted_at IS NOT NULL
+      AND c.organization_id = sqlc.arg(organization_id)::uuid
+      AND p.anonymized_at IS NULL
+    UNION ALL
+    SELECT
+        'DENTIST'::text AS resource_type,
+        d.id,
+        p.legal_name,
+        p.tax_id_number,
+        d.deleted_at
+    FROM dentists d
+    JOIN people p ON p.id = d.person_id
+    WHERE d.deleted_at IS NOT NULL
+      AND d.organization_id = sqlc.arg(organization_id)::uuid
+      AND p.anonymized_at IS NULL
+)
+SELECT
+    resource_type::text AS resource_type,
+    id::uuid AS id,
+    legal_name::text AS legal_name,
+    tax_id_number::text AS tax_id_number,
+    deleted_at::timestamptz AS deleted_at
+FROM deleted
+WHERE (sqlc.narg(resource_type)::text IS NULL OR resource_type = sqlc.narg(resource_type)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (deleted_at, id) < (sqlc.narg(cursor_deleted_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY deleted_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
@@ -0,0 +1,146 @@
+-- name: CreateDentistDocument :one
+INSERT INTO dentist_documents (
+    id,
+    organization_id,
+    dentist_id,
+    document_type,
+    document_number,
+    issued_at,
+    expires_at,
+    notes
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(dentist_id)::uuid,
+    sqlc.arg(document_type),
+    sqlc.narg(document_number),
+    sqlc.narg(issued_at),
+    sqlc.arg(expires_at),
+    sqlc.narg(notes)
+)
+RETURNING *;
+
+-- name: GetDentistDocument :one
+SELECT *
+FROM dentist_documents
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND dentist_id = sqlc.arg(dentist_id)::uuid
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListDentistDocuments :many
+SELECT *
+FROM dentist_documents
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+ORDER BY expires_at, id;
+
+-- name: UpdateDentistDocument :one
+UPDATE dentist_documents
+SET document_number = COALESCE(sqlc.narg(document_number), document_number),
+    issued_at = COALESCE(sqlc.narg(issued_at), issued_at),
+    expires_at = COALESCE(sqlc.narg(expires_at), expires_at),
+    notes = COALESCE(sqlc.narg(notes), notes),
+    last_notified_threshold_days = CASE
+        WHEN sqlc.narg(expires_at)::date IS NOT NULL AND sqlc.narg(expires_at)::date <> expires_at THEN NULL
+        ELSE last_notified_threshold_days
+    END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND dentist_id = sqlc.arg(dentist_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteDentistDocument :execrows
+UPDATE dentist_documents
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND dentist_id = sqlc.arg(dentist_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: DeleteDentistDocumentsByDentist :execrows
+UPDATE dentist_documents
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: ListExpiringDocumentsByClinic :many
+SELECT
+    dd.id,
+    dd.dentist_id,
+    dd.document_type,
+    dd.document_number,
+    dd.issued_at,
+    dd.expires_at,
+    dd.notes,
+    p.legal_name AS dentist_legal_name
+FROM dentist_documents dd
+INNER JOIN dentists d ON d.id = dd.dentist_id
+INNER JOIN people p ON p.id = d.person_id
+INNER JOIN clinic_dentists cd ON cd.dentist_id = d.id
+WHERE cd.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND dd.organization_id = sqlc.arg(organization_id)::uuid
+  AND cd.ended_at IS NULL
+  AND dd.deleted_at IS NULL
+  AND d.deleted_at IS NULL
+  AND p.deleted_at IS NULL
+  AND dd.expires_at <= sqlc.arg(cutoff)::date
+ORDER BY dd.expires_at, dd.id;
+
+-- name: ListDocumentsDueForNotification :many
+SELECT *
+FROM dentist_documents
+WHERE deleted_at IS NULL
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND expires_at <= sqlc.arg(cutoff)::date
+  AND (
+      last_notified_threshold_days IS NULL
+      OR last_notified_threshold_days > 0
+  )
+ORDER BY expires_at, id
+FOR UPDATE SKIP LOCKED;
+
+-- name: MarkDentistDocumentNotified :exec
+UPDATE dentist_documents
+SET last_notified_threshold_days = sqlc.arg(threshold_days)::int,
+    last_notified_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: MoveDentistDocuments :execrows
+UPDATE dentist_documents dd
+SET dentist_id = sqlc.arg(target_dentist_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dd.dentist_id = sqlc.arg(dentist_id)::uuid
+  AND dd.organization_id = sqlc.arg(organization_id)::uuid
+  AND dd.deleted_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1
+      FROM dentist_documents existing
+      WHERE existing.dentist_id = sqlc.arg(target_dentist_id)::uuid
+        AND existing.document_type = dd.document_type
+        AND existing.deleted_at IS NULL
+  );
+
+-- name: RestoreDentistDocumentsDeletedAt :execrows
+UPDATE dentist_documents
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at = sqlc.arg(deleted_at)::timestamptz;
+
+-- name: DeleteDentistDocumentsByDentistAt :execrows
+UPDATE dentist_documents
+SET deleted_at = sqlc.arg(deleted_at)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
@@ -1,11 +1,65 @@
 -- name: CreateDentist :one
-INSERT INTO dentists (id, person_id)
-VALUES (sqlc.arg(id)::uuid, sqlc.arg(person_id)::uuid)
+INSERT INTO dentists (id, organization_id, person_id, cro_number, cro_state)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(person_id)::uuid,
+    sqlc.narg(cro_number),
+    sqlc.narg(cro_state)
+)
 RETURNING *;
 
+-- name: UpdateDentistCRO :one
+UPDATE dentists
+SET cro_number = sqlc.arg(cro_number),
+    cro_state = sqlc.arg(cro_state),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: UpdateDentistPhoto :one
+UPDATE dentists
+SET photo_key = sqlc.arg(photo_key),
+    photo_size_bytes = sqlc.arg(photo_size_bytes),
+    photo_updated_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: ListDentistsNeedingPhotoThumbnail :many
+SELECT id, photo_key::text AS photo_key, photo_updated_at::timestamptz AS photo_updated_at
+FROM dentists
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND photo_key IS NOT NULL
+  AND photo_updated_at IS NOT NULL
+  AND (photo_thumbnail_updated_at IS NULL OR photo_thumbnail_updated_at < photo_updated_at)
+ORDER BY photo_updated_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateDentistPhotoThumbnail :execrows
+UPDATE dentists
+SET photo_thumbnail_key = sqlc.arg(photo_thumbnail_key),
+    photo_thumbnail_updated_at = sqlc.arg(photo_updated_at)::timestamptz
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND photo_updated_at = sqlc.arg(photo_updated_at)::timestamptz;
+
 -- name: GetDentistByID :one
 SELECT *
 FROM dentists
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetDentistOrganizationID :one
+SELECT organization_id
+FROM dentists
 WHERE id = sqlc.arg(id)::uuid
   AND deleted_at IS NULL
 LIMIT 1;
@@ -14,6 +68,7 @@ LIMIT 1;
 SELECT *
 FROM dentists
 WHERE person_id = sqlc.arg(person_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL
 LIMIT 1;
 
@@ -22,12 +77,16 @@ UPDATE dentists
 SET deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL;
 
 -- name: GetDentistDetailsByID :one
 SELECT
     d.id AS dentist_id,
     d.person_id,
+    d.cro_number,
+    d.cro_state,
+    d.photo_updated_at,
     p.legal_name,
     p.tax_id_number,
     p.email,
@@ -35,14 +94,35 @@ SELECT
 FROM dentists d
 JOIN people p ON p.id = d.person_id
 WHERE d.id = sqlc.arg(id)::uuid
+  AND d.organization_id = sqlc.arg(organization_id)::uuid
   AND d.deleted_at IS NULL
   AND p.deleted_at IS NULL
 LIMIT 1;
 
+-- name: GetDentistDetailsIncludingDeleted :one
+SELECT
+    d.id AS dentist_id,
+    d.person_id,
+    d.cro_number,
+    d.cro_state,
+    d.photo_updated_at,
+    p.legal_name,
+    p.tax_id_number,
+    p.email,
+    p.phone,
+    d.deleted_at
+FROM dentists d
+JOIN people p ON p.id = d.person_id
+WHERE d.id = sqlc.arg(id)::uuid
+  AND d.organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
 -- name: ListDentistsByClinicID :many
 SELECT
     d.id AS dentist_id,
     d.person_id,
+    d.cro_number,
+    d.cro_state,
     p.legal_name,
     p.tax_id_number,
     p.email,
@@ -50,12 +130,15 @@ SELECT
     cd.is_admin,
     cd.is_legal_representative,
     cd.started_at,
-    cd.ended_at
+    cd.ended_at,
+    cd.planned_end_at,
+    cd.substitute_for_dentist_id
 FROM clinic_dentists cd
 JOIN dentists d ON d.id = cd.dentist_id
 JOIN people p ON p.id = d.person_id
 JOIN clinics c ON c.id = cd.clinic_id
 WHERE cd.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND cd.organization_id = sqlc.arg(organization_id)::uuid
   AND cd.ended_at IS NULL
   AND d.deleted_at IS NULL
   AND p.deleted_at IS NULL
@@ -66,6 +149,9 @@ ORDER BY d.id;
 SELECT
     d.id AS dentist_id,
     d.person_id,
+    d.cro_number,
+    d.cro_state,
+    d.photo_updated_at,
     p.legal_name,
     p.tax_id_number,
     p.email,
@@ -73,18 +159,38 @@ SELECT
     cd.is_admin,
     cd.is_legal_representative,
     cd.started_at,
-    cd.ended_at
+    cd.ended_at,
+    cd.planned_end_at,
+    cd.substitute_for_dentist_id,
+    d.deleted_at,
+    d.created_at
 FROM clinic_dentists cd
 JOIN dentists d ON d.id = cd.dentist_id
 JOIN people p ON p.id = d.person_id
 JOIN clinics c ON c.id = cd.clinic_id
 WHERE cd.clinic_id = sqlc.arg(clinic_id)::uuid
-  AND cd.ended_at IS NULL
-  AND d.deleted_at IS NULL
-  AND p.deleted_at IS NULL
+  AND cd.organization_id = sqlc.arg(organization_id)::uuid
   AND c.deleted_at IS NULL
-  AND (sqlc.narg(after_dentist_id)::uuid IS NULL OR d.id > sqlc.narg(after_dentist_id)::uuid)
-ORDER BY d.id
+  AND (
+      (cd.ended_at IS NULL AND d.deleted_at IS NULL AND p.deleted_at IS NULL)
+      OR (sqlc.arg(include_deleted)::boolean AND d.deleted_at IS NOT NULL AND cd.ended_at = d.deleted_at)
+  )
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (d.created_at, d.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+  AND (
+      sqlc.narg(specialty)::text IS NULL
+      OR EXISTS (
+          SELECT 1
+          FROM dentist_specialties ds
+          JOIN specialties s ON s.id = ds.specialty_id
+          WHERE ds.dentist_id = d.id
+            AND s.deleted_at IS NULL
+            AND (s.code = sqlc.narg(specialty)::text OR s.id::text = sqlc.narg(specialty)::text)
+      )
+  )
+ORDER BY d.created_at, d.id
 LIMIT sqlc.arg(page_limit);
 
 -- name: ListDentistsByClinicIDs :many
@@ -92,6 +198,8 @@ SELECT
     cd.clinic_id,
     d.id AS dentist_id,
     d.person_id,
+    d.cro_number,
+    d.cro_state,
     p.legal_name,
     p.tax_id_number,
     p.email,
@@ -99,14 +207,63 @@ SELECT
     cd.is_admin,
     cd.is_legal_representative,
     cd.started_at,
-    cd.ended_at
+    cd.ended_at,
+    cd.planned_end_at,
+    cd.substitute_for_dentist_id
 FROM clinic_dentists cd
 JOIN dentists d ON d.id = cd.dentist_id
 JOIN people p ON p.id = d.person_id
 JOIN clinics c ON c.id = cd.clinic_id
 WHERE cd.clinic_id = ANY(sqlc.arg(clinic_ids)::uuid[])
+  AND cd.organization_id = sqlc.arg(organization_id)::uuid
   AND cd.ended_at IS NULL
   AND d.deleted_at IS NULL
   AND p.deleted_at IS NULL
   AND c.deleted_at IS NULL
 ORDER BY cd.clinic_id, d.id;
+
+-- name: UpdateDentistPerson :one
+UPDATE dentists
+SET person_id = sqlc.arg(person_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: LockDeletedDentistForUpdate :one
+SELECT *
+FROM dentists
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NOT NULL
+FOR UPDATE;
+
+-- name: ExistsOtherActiveDentistByCRO :one
+SELECT EXISTS (
+    SELECT 1
+    FROM dentists
+    WHERE cro_state = sqlc.arg(cro_state)
+      AND organization_id = sqlc.arg(organization_id)::uuid
+      AND cro_number = sqlc.arg(cro_number)
+      AND id <> sqlc.arg(dentist_id)::uuid
+      AND deleted_at IS NULL
+);
+
+-- name: ExistsOtherActiveDentistByPersonID :one
+SELECT EXISTS (
+    SELECT 1
+    FROM dentists
+    WHERE person_id = sqlc.arg(person_id)::uuid
+      AND organization_id = sqlc.arg(organization_id)::uuid
+      AND id <> sqlc.arg(dentist_id)::uuid
+      AND deleted_at IS NULL
+);
+
+-- name: RestoreDentist :execrows
+UPDATE dentists
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NOT NULL;
@@ -0,0 +1,178 @@
+-- name: CreateDocumentTemplate :one
+INSERT INTO document_templates (id, organization_id, name, kind, title, body)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(name),
+    sqlc.arg(kind),
+    sqlc.arg(title),
+    sqlc.arg(body)
+)
+RETURNING *;
+
+-- name: GetDocumentTemplate :one
+SELECT *
+FROM document_templates
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: ListDocumentTemplates :many
+SELECT *
+FROM document_templates
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND (sqlc.narg(kind)::text IS NULL OR kind = sqlc.narg(kind)::text)
+ORDER BY lower(name), id;
+
+-- name: UpdateDocumentTemplate :one
+UPDATE document_templates
+SET name = sqlc.arg(name),
+    kind = sqlc.arg(kind),
+    title = sqlc.arg(title),
+    body = sqlc.arg(body),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteDocumentTemplate :execrows
+UPDATE document_templates
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: CreateGeneratedDocument :one
+INSERT INTO generated_documents (
+    id,
+    organization_id,
+    clinic_id,
+    template_id,
+    dentist_id,
+    kind,
+    title,
+    attachment_key,
+    content_type,
+    size_bytes,
+    checksum_sha256,
+    placeholders,
+    created_by_user_id
+)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(template_id)::uuid,
+    sqlc.narg(dentist_id)::uuid,
+    sqlc.arg(kind),
+    sqlc.arg(title),
+    sqlc.arg(attachment_key),
+    sqlc.arg(content_type),
+    sqlc.arg(size_bytes),
+    sqlc.arg(checksum_sha256),
+    sqlc.arg(placeholders)::text[],
+    sqlc.arg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: GetGeneratedDocument :one
+SELECT *
+FROM generated_documents
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListClinicGeneratedDocumentsCursor :many
+SELECT *
+FROM generated_documents gd
+WHERE gd.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND gd.organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(template_id)::uuid IS NULL OR gd.template_id = sqlc.narg(template_id)::uuid)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (gd.created_at, gd.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY gd.created_at, gd.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: MoveDentistGeneratedDocuments :execrows
+UPDATE generated_documents
+SET dentist_id = sqlc.arg(target_dentist_id)::uuid
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CreateDocumentSignatureRequest :one
+INSERT INTO document_signature_requests (
+    id,
+    organization_id,
+    clinic_id,
+    document_id,
+    provider,
+    reference,
+    signers,
+    created_by_user_id
+)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(document_id)::uuid,
+    sqlc.arg(provider),
+    sqlc.arg(reference),
+    sqlc.arg(signers)::jsonb,
+    sqlc.arg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: ExistsPendingDocumentSignatureRequest :one
+SELECT EXISTS (
+    SELECT 1
+    FROM document_signature_requests
+    WHERE document_id = sqlc.arg(document_id)::uuid
+      AND organization_id = sqlc.arg(organization_id)::uuid
+      AND status = 'PENDING'
+)::bool;
+
+-- name: ListDocumentSignatureRequests :many
+SELECT *
+FROM document_signature_requests
+WHERE document_id = sqlc.arg(document_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY created_at, id;
+
+-- name: GetDocumentSignatureRequest :one
+SELECT *
+FROM document_signature_requests
+WHERE id = sqlc.arg(id)::uuid
+  AND document_id = sqlc.arg(document_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: GetDocumentSignatureRequestByReference :one
+SELECT *
+FROM document_signature_requests
+WHERE reference = sqlc.arg(reference)::text
+LIMIT 1;
+
+-- name: MarkDocumentSignatureSigned :execrows
+UPDATE document_signature_requests
+SET status = 'SIGNED',
+    signed_attachment_key = sqlc.arg(signed_attachment_key),
+    signed_size_bytes = sqlc.arg(signed_size_bytes),
+    signed_checksum_sha256 = sqlc.arg(signed_checksum_sha256),
+    completed_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'PENDING';
+
+-- name: CloseDocumentSignatureRequest :execrows
+UPDATE document_signature_requests
+SET status = sqlc.arg(status),
+    failure_reason = sqlc.narg(failure_reason),
+    completed_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'PENDING';
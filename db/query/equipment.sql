@@ -0,0 +1,179 @@
+-- name: CreateEquipment :one
+INSERT INTO equipment (
+    id,
+    organization_id,
+    clinic_id,
+    name,
+    kind,
+    manufacturer,
+    model,
+    serial_number,
+    maintenance_interval_days,
+    last_maintained_at,
+    next_maintenance_due,
+    notes
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(name),
+    sqlc.arg(kind),
+    sqlc.narg(manufacturer),
+    sqlc.narg(model),
+    sqlc.narg(serial_number),
+    sqlc.narg(maintenance_interval_days),
+    sqlc.narg(last_maintained_at),
+    sqlc.narg(next_maintenance_due),
+    sqlc.narg(notes)
+)
+RETURNING *;
+
+-- name: GetEquipment :one
+SELECT *
+FROM equipment
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: LockEquipmentForUpdate :one
+SELECT *
+FROM equipment
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: ListEquipmentCursor :many
+SELECT *
+FROM equipment
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND (sqlc.narg(kind)::text IS NULL OR kind = sqlc.narg(kind)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateEquipment :one
+UPDATE equipment
+SET name = COALESCE(sqlc.narg(name), name),
+    kind = COALESCE(sqlc.narg(kind), kind),
+    manufacturer = NULLIF(COALESCE(sqlc.narg(manufacturer), manufacturer), ''),
+    model = NULLIF(COALESCE(sqlc.narg(model), model), ''),
+    serial_number = NULLIF(COALESCE(sqlc.narg(serial_number), serial_number), ''),
+    notes = NULLIF(COALESCE(sqlc.narg(notes), notes), ''),
+    maintenance_interval_days = CASE
+        WHEN sqlc.arg(clear_maintenance_interval)::boolean THEN NULL
+        ELSE COALESCE(sqlc.narg(maintenance_interval_days)::int, maintenance_interval_days)
+    END,
+    next_maintenance_due = COALESCE(sqlc.narg(next_maintenance_due)::date, next_maintenance_due),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteEquipment :execrows
+UPDATE equipment
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: SetEquipmentMaintenanceSchedule :one
+UPDATE equipment
+SET last_maintained_at = sqlc.narg(last_maintained_at),
+    next_maintenance_due = sqlc.narg(next_maintenance_due),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+RETURNING *;
+
+-- name: CreateEquipmentMaintenanceRecord :one
+INSERT INTO equipment_maintenance_records (
+    id,
+    organization_id,
+    clinic_id,
+    equipment_id,
+    kind,
+    performed_at,
+    performed_by,
+    cost_cents,
+    notes,
+    next_maintenance_due,
+    created_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(equipment_id)::uuid,
+    sqlc.arg(kind),
+    sqlc.arg(performed_at),
+    sqlc.narg(performed_by),
+    sqlc.narg(cost_cents),
+    sqlc.narg(notes),
+    sqlc.narg(next_maintenance_due),
+    sqlc.narg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: ListEquipmentMaintenanceRecordsCursor :many
+SELECT *
+FROM equipment_maintenance_records
+WHERE equipment_id = sqlc.arg(equipment_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListClinicEquipmentSchedule :many
+SELECT *
+FROM equipment
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+ORDER BY next_maintenance_due NULLS LAST, name, id;
+
+-- name: ListClinicOverdueEquipment :many
+SELECT *
+FROM equipment
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND next_maintenance_due < sqlc.arg(today)::date
+ORDER BY next_maintenance_due, name, id;
+
+-- name: ListOverdueEquipmentForNotification :many
+SELECT
+    e.*,
+    p.legal_name AS clinic_legal_name,
+    p.trade_name AS clinic_trade_name,
+    p.email AS clinic_email,
+    p.phone AS clinic_phone
+FROM equipment e
+JOIN clinics c ON c.id = e.clinic_id
+JOIN people p ON p.id = c.person_id
+WHERE e.organization_id = sqlc.arg(organization_id)::uuid
+  AND e.deleted_at IS NULL
+  AND e.next_maintenance_due < sqlc.arg(today)::date
+  AND e.overdue_notified_for IS DISTINCT FROM e.next_maintenance_due
+  AND c.deleted_at IS NULL
+  AND c.deactivated_at IS NULL
+ORDER BY e.next_maintenance_due, e.id;
+
+-- name: MarkEquipmentOverdueNotified :exec
+UPDATE equipment
+SET overdue_notified_for = sqlc.arg(due)::date
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
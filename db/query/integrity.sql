@@ -0,0 +1,94 @@
+-- name: ListClinicsWithoutActiveBankAccount :many
+SELECT c.id
+FROM clinics c
+WHERE c.deleted_at IS NULL
+  AND c.organization_id = sqlc.arg(organization_id)::uuid
+  AND NOT EXISTS (
+      SELECT 1
+      FROM bank_accounts b
+      WHERE b.clinic_id = c.id
+        AND b.deleted_at IS NULL
+  )
+ORDER BY c.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListClinicsWithoutPrimaryBankAccount :many
+SELECT c.id
+FROM clinics c
+WHERE c.deleted_at IS NULL
+  AND c.organization_id = sqlc.arg(organization_id)::uuid
+  AND EXISTS (
+      SELECT 1
+      FROM bank_accounts b
+      WHERE b.clinic_id = c.id
+        AND b.deleted_at IS NULL
+  )
+  AND NOT EXISTS (
+      SELECT 1
+      FROM bank_accounts b
+      WHERE b.clinic_id = c.id
+        AND b.deleted_at IS NULL
+        AND b.is_primary
+  )
+ORDER BY c.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListDentistsWithoutActiveClinic :many
+SELECT d.id
+FROM dentists d
+WHERE d.deleted_at IS NULL
+  AND d.organization_id = sqlc.arg(organization_id)::uuid
+  AND NOT EXISTS (
+      SELECT 1
+      FROM clinic_dentists cd
+      JOIN clinics c ON c.id = cd.clinic_id
+      WHERE cd.dentist_id = d.id
+        AND cd.ended_at IS NULL
+        AND c.deleted_at IS NULL
+  )
+ORDER BY d.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListOrphanedPeople :many
+SELECT p.id
+FROM people p
+WHERE p.deleted_at IS NULL
+  AND p.organization_id = sqlc.arg(organization_id)::uuid
+  AND NOT EXISTS (SELECT 1 FROM clinics c WHERE c.person_id = p.id)
+  AND NOT EXISTS (SELECT 1 FROM dentists d WHERE d.person_id = p.id)
+  AND NOT EXISTS (SELECT 1 FROM suppliers s WHERE s.person_id = p.id)
+ORDER BY p.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: PromoteOldestBankAccountToPrimary :one
+UPDATE bank_accounts
+SET is_primary = TRUE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND id = (
+    SELECT b.id
+    FROM bank_accounts b
+    WHERE b.clinic_id = sqlc.arg(clinic_id)::uuid
+      AND b.deleted_at IS NULL
+    ORDER BY b.created_at, b.id
+    LIMIT 1
+)
+  AND NOT EXISTS (
+      SELECT 1
+      FROM bank_accounts b
+      WHERE b.clinic_id = sqlc.arg(clinic_id)::uuid
+        AND b.deleted_at IS NULL
+        AND b.is_primary
+  )
+RETURNING id;
+
+-- name: DeleteOrphanedPerson :execrows
+UPDATE people p
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE p.id = sqlc.arg(id)::uuid
+  AND p.organization_id = sqlc.arg(organization_id)::uuid
+  AND p.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM clinics c WHERE c.person_id = p.id)
+  AND NOT EXISTS (SELECT 1 FROM dentists d WHERE d.person_id = p.id)
+  AND NOT EXISTS (SELECT 1 FROM suppliers s WHERE s.person_id = p.id);
@@ -0,0 +1,128 @@
+-- name: CreateInventoryItem :one
+INSERT INTO inventory_items (id, organization_id, clinic_id, name, sku, unit, minimum_quantity)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(name),
+    sqlc.narg(sku),
+    sqlc.arg(unit),
+    sqlc.arg(minimum_quantity)
+)
+RETURNING *;
+
+-- name: GetInventoryItem :one
+SELECT *
+FROM inventory_items
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: LockInventoryItemForUpdate :one
+SELECT *
+FROM inventory_items
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+FOR UPDATE;
+
+-- name: ListInventoryItemsCursor :many
+SELECT *
+FROM inventory_items
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateInventoryItem :one
+UPDATE inventory_items
+SET name = COALESCE(sqlc.narg(name), name),
+    sku = NULLIF(COALESCE(sqlc.narg(sku), sku), ''),
+    unit = COALESCE(sqlc.narg(unit), unit),
+    minimum_quantity = COALESCE(sqlc.narg(minimum_quantity)::bigint, minimum_quantity),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteInventoryItem :execrows
+UPDATE inventory_items
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: SetInventoryItemQuantity :exec
+UPDATE inventory_items
+SET quantity = sqlc.arg(quantity),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CreateInventoryMovement :one
+INSERT INTO inventory_movements (
+    id,
+    organization_id,
+    clinic_id,
+    item_id,
+    kind,
+    quantity_delta,
+    quantity_after,
+    unit_cost_cents,
+    notes,
+    created_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(item_id)::uuid,
+    sqlc.arg(kind),
+    sqlc.arg(quantity_delta),
+    sqlc.arg(quantity_after),
+    sqlc.narg(unit_cost_cents),
+    sqlc.narg(notes),
+    sqlc.narg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: ListInventoryMovementsCursor :many
+SELECT *
+FROM inventory_movements
+WHERE item_id = sqlc.arg(item_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(kind)::text IS NULL OR kind = sqlc.narg(kind)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListLowStockInventoryItems :many
+SELECT
+    i.*,
+    COALESCE((
+        SELECT -SUM(m.quantity_delta)
+        FROM inventory_movements m
+        WHERE m.item_id = i.id
+          AND m.organization_id = i.organization_id
+          AND m.kind = 'CONSUMPTION'
+          AND m.created_at >= sqlc.arg(usage_since)::timestamptz
+    ), 0)::bigint AS recent_consumption
+FROM inventory_items i
+WHERE i.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND i.organization_id = sqlc.arg(organization_id)::uuid
+  AND i.deleted_at IS NULL
+  AND i.quantity <= i.minimum_quantity
+ORDER BY i.quantity - i.minimum_quantity, i.name, i.id;
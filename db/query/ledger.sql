@@ -0,0 +1,64 @@
+-- name: CreateLedgerTransaction :exec
+INSERT INTO ledger_transactions (
+    id,
+    organization_id,
+    clinic_id,
+    kind,
+    description,
+    reference_type,
+    reference_id,
+    occurred_on,
+    created_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(kind),
+    sqlc.arg(description),
+    sqlc.narg(reference_type),
+    sqlc.narg(reference_id)::uuid,
+    sqlc.arg(occurred_on),
+    sqlc.narg(created_by_user_id)::uuid
+);
+
+-- name: CreateLedgerEntry :exec
+INSERT INTO ledger_entries (
+    organization_id,
+    transaction_id,
+    line,
+    account,
+    direction,
+    amount_cents
+) VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(transaction_id)::uuid,
+    sqlc.arg(line),
+    sqlc.arg(account),
+    sqlc.arg(direction),
+    sqlc.arg(amount_cents)
+);
+
+-- name: ListLedgerTransactions :many
+SELECT *
+FROM ledger_transactions
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND occurred_on >= sqlc.arg(from_date)::date
+  AND occurred_on <= sqlc.arg(to_date)::date
+ORDER BY occurred_on, id;
+
+-- name: ListLedgerEntriesByTransactionIDs :many
+SELECT *
+FROM ledger_entries
+WHERE transaction_id = ANY(sqlc.arg(transaction_ids)::uuid[])
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY transaction_id, line;
+
+-- name: GetClinicLedgerBalance :one
+SELECT COALESCE(SUM(CASE WHEN e.direction = 'CREDIT' THEN e.amount_cents ELSE -e.amount_cents END), 0)::bigint
+FROM ledger_entries e
+JOIN ledger_transactions t ON t.id = e.transaction_id
+WHERE t.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND e.organization_id = sqlc.arg(organization_id)::uuid
+  AND e.account = 'CLINIC_BALANCE'
+  AND t.occurred_on < sqlc.arg(before_date)::date;
@@ -0,0 +1,107 @@
+-- name: CreateLegalHold :exec
+INSERT INTO legal_holds (
+    id,
+    organization_id,
+    resource_type,
+    resource_id,
+    reason,
+    case_reference,
+    placed_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(resource_type),
+    sqlc.arg(resource_id)::uuid,
+    sqlc.arg(reason),
+    sqlc.narg(case_reference),
+    sqlc.arg(placed_by_user_id)::uuid
+);
+
+-- name: GetLegalHold :one
+SELECT
+    h.id,
+    h.resource_type,
+    h.resource_id,
+    h.reason,
+    h.case_reference,
+    h.placed_by_user_id,
+    placer.email AS placed_by_email,
+    h.placed_at,
+    h.released_by_user_id,
+    releaser.email AS released_by_email,
+    h.released_at,
+    h.release_notes
+FROM legal_holds h
+JOIN users placer ON placer.id = h.placed_by_user_id
+LEFT JOIN users releaser ON releaser.id = h.released_by_user_id
+WHERE h.id = sqlc.arg(id)::uuid
+  AND h.organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListLegalHolds :many
+SELECT
+    h.id,
+    h.resource_type,
+    h.resource_id,
+    h.reason,
+    h.case_reference,
+    h.placed_by_user_id,
+    placer.email AS placed_by_email,
+    h.placed_at,
+    h.released_by_user_id,
+    releaser.email AS released_by_email,
+    h.released_at,
+    h.release_notes
+FROM legal_holds h
+JOIN users placer ON placer.id = h.placed_by_user_id
+LEFT JOIN users releaser ON releaser.id = h.released_by_user_id
+WHERE h.organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(resource_type)::text IS NULL OR h.resource_type = sqlc.narg(resource_type)::text)
+  AND (sqlc.narg(resource_id)::uuid IS NULL OR h.resource_id = sqlc.narg(resource_id)::uuid)
+  AND (sqlc.narg(active)::boolean IS NULL OR (h.released_at IS NULL) = sqlc.narg(active)::boolean)
+ORDER BY h.placed_at DESC, h.id DESC;
+
+-- name: ReleaseLegalHold :execrows
+UPDATE legal_holds
+SET released_by_user_id = sqlc.arg(released_by_user_id)::uuid,
+    released_at = CURRENT_TIMESTAMP,
+    release_notes = sqlc.narg(release_notes)
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND released_at IS NULL;
+
+-- name: HasActiveLegalHold :one
+SELECT EXISTS (
+    SELECT 1
+    FROM legal_holds
+    WHERE resource_type = sqlc.arg(resource_type)::text
+      AND resource_id = sqlc.arg(resource_id)::uuid
+      AND organization_id = sqlc.arg(organization_id)::uuid
+      AND released_at IS NULL
+);
+
+-- name: HasActiveOrganizationLegalHold :one
+SELECT EXISTS (
+    SELECT 1
+    FROM legal_holds
+    WHERE organization_id = sqlc.arg(organization_id)::uuid
+      AND released_at IS NULL
+);
+
+-- name: GetLegalHoldResourceClinic :one
+-- Deleted clinics and dentists can be held too, since the hold exists to stop their purge.
+SELECT r.clinic_id::text AS clinic_id
+FROM (
+    SELECT 'CLINIC'::text AS resource_type, c.id, c.id::text AS clinic_id
+    FROM clinics c
+    WHERE c.organization_id = sqlc.arg(organization_id)::uuid
+    UNION ALL
+    SELECT 'DENTIST'::text, d.id, ''::text
+    FROM dentists d
+    WHERE d.organization_id = sqlc.arg(organization_id)::uuid
+    UNION ALL
+    SELECT 'GENERATED_DOCUMENT'::text, gd.id, gd.clinic_id::text
+    FROM generated_documents gd
+    WHERE gd.organization_id = sqlc.arg(organization_id)::uuid
+) r
+WHERE r.resource_type = sqlc.arg(resource_type)::text
+  AND r.id = sqlc.arg(resource_id)::uuid;
@@ -0,0 +1,160 @@
+-- name: CreateNotification :exec
+INSERT INTO notifications (id, organization_id, clinic_id, channel, template, recipient, subject, body, dedupe_key)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.narg(clinic_id)::uuid,
+    sqlc.arg(channel),
+    sqlc.arg(template),
+    sqlc.arg(recipient),
+    sqlc.arg(subject),
+    sqlc.arg(body),
+    sqlc.arg(dedupe_key)
+)
+ON CONFLICT (organization_id, dedupe_key) DO NOTHING;
+
+-- name: ClaimDueNotifications :many
+UPDATE notifications
+SET attempts = attempts + 1,
+    next_attempt_at = sqlc.arg(lease_until)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+    SELECT due.id
+    FROM notifications due
+    WHERE due.organization_id = sqlc.arg(organization_id)::uuid
+      AND due.status = 'PENDING'
+      AND due.next_attempt_at <= sqlc.arg(due_before)::timestamptz
+    ORDER BY due.next_attempt_at
+    LIMIT sqlc.arg(page_limit)
+    FOR UPDATE SKIP LOCKED
+)
+  AND organization_id = sqlc.arg(organization_id)::uuid
+RETURNING *;
+
+-- name: MarkNotificationSent :exec
+UPDATE notifications
+SET status = 'SENT',
+    sent_at = CURRENT_TIMESTAMP,
+    last_error = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: MarkNotificationAttemptFailed :exec
+UPDATE notifications
+SET status = sqlc.arg(status),
+    next_attempt_at = sqlc.arg(next_attempt_at)::timestamptz,
+    last_error = sqlc.arg(last_error),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: RetryFailedNotification :execrows
+UPDATE notifications
+SET status = 'PENDING',
+    attempts = 0,
+    next_attempt_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'FAILED';
+
+-- name: GetNotification :one
+SELECT *
+FROM notifications
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListNotificationsCursor :many
+SELECT *
+FROM notifications
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: GetNotificationForDeliveryCallback :one
+SELECT *
+FROM notifications
+WHERE id = sqlc.arg(id)::uuid;
+
+-- name: RecordNotificationDelivery :execrows
+UPDATE notifications
+SET delivery_status = sqlc.arg(delivery_status)::text,
+    bounce_type = sqlc.narg(bounce_type),
+    delivery_detail = sqlc.narg(delivery_detail),
+    delivery_updated_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND (delivery_status IS NULL OR delivery_status = 'DELIVERED' OR bounce_type = 'SOFT');
+
+-- name: CreateNotificationSuppression :exec
+INSERT INTO notification_suppressions (id, organization_id, channel, recipient, reason, notification_id)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(channel),
+    sqlc.arg(recipient),
+    sqlc.arg(reason),
+    sqlc.narg(notification_id)::uuid
+)
+ON CONFLICT (organization_id, channel, recipient) DO NOTHING;
+
+-- name: IsNotificationRecipientSuppressed :one
+SELECT EXISTS (
+    SELECT 1
+    FROM notification_suppressions
+    WHERE organization_id = sqlc.arg(organization_id)::uuid
+      AND channel = sqlc.arg(channel)
+      AND recipient = sqlc.arg(recipient)
+);
+
+-- name: FailPendingNotificationsForRecipient :execrows
+UPDATE notifications
+SET status = 'FAILED',
+    last_error = sqlc.arg(last_error),
+    updated_at = CURRENT_TIMESTAMP
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND channel = sqlc.arg(channel)
+  AND lower(recipient) = sqlc.arg(recipient)
+  AND status = 'PENDING';
+
+-- name: ListNotificationSuppressionsCursor :many
+SELECT *
+FROM notification_suppressions
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(channel)::text IS NULL OR channel = sqlc.narg(channel)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: DeleteNotificationSuppression :execrows
+DELETE FROM notification_suppressions
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: GetClinicNotificationDeliverability :many
+SELECT
+    channel,
+    COUNT(*)::bigint AS total,
+    COUNT(*) FILTER (WHERE status = 'PENDING')::bigint AS pending,
+    COUNT(*) FILTER (WHERE status = 'SENT')::bigint AS sent,
+    COUNT(*) FILTER (WHERE delivery_status = 'DELIVERED')::bigint AS delivered,
+    COUNT(*) FILTER (WHERE delivery_status = 'BOUNCED' AND bounce_type = 'HARD')::bigint AS hard_bounced,
+    COUNT(*) FILTER (WHERE delivery_status = 'BOUNCED' AND bounce_type = 'SOFT')::bigint AS soft_bounced,
+    COUNT(*) FILTER (WHERE status = 'FAILED' OR delivery_status = 'FAILED')::bigint AS failed
+FROM notifications
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND created_at >= sqlc.arg(from_time)::timestamptz
+  AND created_at < sqlc.arg(to_time)::timestamptz
+GROUP BY channel
+ORDER BY channel;
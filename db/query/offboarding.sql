@@ -0,0 +1,334 @@
+-- Password hashes stay out of the archive; everything else the organization owns goes in.
+-- name: ExportOrganizationData :one
+SELECT (jsonb_build_object(
+    'people', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM people t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'addresses', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM addresses t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinics', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinics t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_registry_records', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_registry_records t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'dentists', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM dentists t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_dentists', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_dentists t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_operating_hours', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_operating_hours t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_holidays', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_holidays t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_settings', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_settings t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_directory_listings', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_directory_listings t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'specialties', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM specialties t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'dentist_specialties', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM dentist_specialties t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'dentist_documents', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM dentist_documents t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'bank_accounts', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM bank_accounts t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'users', (SELECT COALESCE(jsonb_agg(to_jsonb(t) - 'password_hash'), '[]'::jsonb) FROM users t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_onboarding_transitions', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_onboarding_transitions t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_notes', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_notes t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_note_mentions', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_note_mentions t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'bank_account_changes', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM bank_account_changes t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_financial_holds', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_financial_holds t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'payout_batches', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM payout_batches t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'payout_batch_items', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM payout_batch_items t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_payables', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_payables t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'ledger_transactions', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM ledger_transactions t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'ledger_entries', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM ledger_entries t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_revisions', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_revisions t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'pending_deletions', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM pending_deletions t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'audit_logs', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM audit_logs t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'usage_daily_rollups', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM usage_daily_rollups t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'notifications', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM notifications t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'notification_suppressions', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM notification_suppressions t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_announcements', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_announcements t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_announcement_recipients', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_announcement_recipients t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'dentist_notification_preferences', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM dentist_notification_preferences t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'inventory_items', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM inventory_items t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'inventory_movements', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM inventory_movements t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'suppliers', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM suppliers t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'purchase_orders', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM purchase_orders t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'purchase_order_items', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM purchase_order_items t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'purchase_order_receipts', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM purchase_order_receipts t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'equipment', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM equipment t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'equipment_maintenance_records', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM equipment_maintenance_records t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_shifts', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_shifts t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_time_clock_settings', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_time_clock_settings t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'time_clock_entries', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM time_clock_entries t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_task_rules', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_task_rules t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'clinic_tasks', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM clinic_tasks t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'document_templates', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM document_templates t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'generated_documents', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM generated_documents t WHERE t.organization_id = sqlc.arg(organization_id)::uuid)
+-- jsonb_build_object takes at most 100 arguments, so later tables go in another object.
+) || jsonb_build_object(
+    'document_signature_requests', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM document_signature_requests t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'legal_holds', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM legal_holds t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'document_retention_rules', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM document_retention_rules t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'attachment_scans', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM attachment_scans t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'reports', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM reports t WHERE t.organization_id = sqlc.arg(organization_id)::uuid),
+    'report_definitions', (SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb) FROM report_definitions t WHERE t.organization_id = sqlc.arg(organization_id)::uuid)
+))::jsonb AS data;
+
+-- name: ListOrganizationAttachmentKeys :many
+SELECT photo_key::text AS attachment_key
+FROM dentists
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND photo_key IS NOT NULL
+UNION ALL
+SELECT photo_thumbnail_key::text
+FROM dentists
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND photo_thumbnail_key IS NOT NULL
+UNION ALL
+SELECT attachment_key
+FROM generated_documents
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+UNION ALL
+SELECT signed_attachment_key::text
+FROM document_signature_requests
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND signed_attachment_key IS NOT NULL;
+
+-- name: ListOrganizationQuarantinedAttachmentKeys :many
+SELECT quarantine_key
+FROM attachment_scans
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'PENDING';
+
+-- name: ListOrganizationReportKeys :many
+SELECT attachment_key::text AS attachment_key
+FROM reports
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND attachment_key IS NOT NULL;
+
+-- name: PurgeOrganizationClinicNoteMentions :execrows
+DELETE FROM clinic_note_mentions
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationReports :execrows
+DELETE FROM reports
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationReportDefinitions :execrows
+DELETE FROM report_definitions
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationAttachmentScans :execrows
+DELETE FROM attachment_scans
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationLegalHolds :execrows
+DELETE FROM legal_holds
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationDocumentRetentionRules :execrows
+DELETE FROM document_retention_rules
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationDocumentSignatureRequests :execrows
+DELETE FROM document_signature_requests
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationGeneratedDocuments :execrows
+DELETE FROM generated_documents
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationDocumentTemplates :execrows
+DELETE FROM document_templates
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicTasks :execrows
+DELETE FROM clinic_tasks
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicTaskRules :execrows
+DELETE FROM clinic_task_rules
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationTimeClockEntries :execrows
+DELETE FROM time_clock_entries
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationTimeClockSettings :execrows
+DELETE FROM clinic_time_clock_settings
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicShifts :execrows
+DELETE FROM clinic_shifts
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationEquipmentMaintenanceRecords :execrows
+DELETE FROM equipment_maintenance_records
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationEquipment :execrows
+DELETE FROM equipment
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationPurchaseOrderReceipts :execrows
+DELETE FROM purchase_order_receipts
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationPurchaseOrderItems :execrows
+DELETE FROM purchase_order_items
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationPurchaseOrders :execrows
+DELETE FROM purchase_orders
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationSuppliers :execrows
+DELETE FROM suppliers
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationInventoryMovements :execrows
+DELETE FROM inventory_movements
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationInventoryItems :execrows
+DELETE FROM inventory_items
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicAnnouncementRecipients :execrows
+DELETE FROM clinic_announcement_recipients
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicAnnouncements :execrows
+DELETE FROM clinic_announcements
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationDentistNotificationPreferences :execrows
+DELETE FROM dentist_notification_preferences
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicNotes :execrows
+DELETE FROM clinic_notes
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicOnboardingTransitions :execrows
+DELETE FROM clinic_onboarding_transitions
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationBankAccountChanges :execrows
+DELETE FROM bank_account_changes
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicFinancialHolds :execrows
+DELETE FROM clinic_financial_holds
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationLedgerEntries :execrows
+DELETE FROM ledger_entries
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationLedgerTransactions :execrows
+DELETE FROM ledger_transactions
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicPayables :execrows
+DELETE FROM clinic_payables
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationPayoutBatchItems :execrows
+DELETE FROM payout_batch_items
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationPayoutBatches :execrows
+DELETE FROM payout_batches
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicRevisions :execrows
+DELETE FROM clinic_revisions
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationPendingDeletions :execrows
+DELETE FROM pending_deletions
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationAuditExportCheckpoints :execrows
+DELETE FROM audit_export_checkpoints
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationWarehouseExportWatermarks :execrows
+DELETE FROM warehouse_export_watermarks
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationAuditChainHead :execrows
+DELETE FROM audit_chain_head
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationAuditLogs :execrows
+DELETE FROM audit_logs
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationUsers :execrows
+DELETE FROM users
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationBankAccounts :execrows
+DELETE FROM bank_accounts
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicDentists :execrows
+DELETE FROM clinic_dentists
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationDentistSpecialties :execrows
+DELETE FROM dentist_specialties
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationDentistDocuments :execrows
+DELETE FROM dentist_documents
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationSpecialties :execrows
+DELETE FROM specialties
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationDentists :execrows
+DELETE FROM dentists
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicOperatingHours :execrows
+DELETE FROM clinic_operating_hours
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicHolidays :execrows
+DELETE FROM clinic_holidays
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicSettings :execrows
+DELETE FROM clinic_settings
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicDirectoryListings :execrows
+DELETE FROM clinic_directory_listings
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicRegistryRecords :execrows
+DELETE FROM clinic_registry_records
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationClinicBranches :execrows
+DELETE FROM clinics
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND parent_clinic_id IS NOT NULL;
+
+-- name: PurgeOrganizationClinics :execrows
+DELETE FROM clinics
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationAddresses :execrows
+DELETE FROM addresses
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationUsageRecords :execrows
+DELETE FROM usage_records
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationUsageDailyRollups :execrows
+DELETE FROM usage_daily_rollups
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationNotifications :execrows
+DELETE FROM notifications
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationNotificationSuppressions :execrows
+DELETE FROM notification_suppressions
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeOrganizationPeople :execrows
+DELETE FROM people
+WHERE organization_id = sqlc.arg(organization_id)::uuid;
@@ -0,0 +1,17 @@
+-- name: GetOrganizationKey :one
+SELECT *
+FROM organization_keys
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: CreateOrganizationKey :exec
+INSERT INTO organization_keys (organization_id, wrapped_key)
+VALUES (sqlc.arg(organization_id)::uuid, sqlc.arg(wrapped_key))
+ON CONFLICT (organization_id) DO NOTHING;
+
+-- name: ShredOrganizationKey :exec
+INSERT INTO organization_keys (organization_id, wrapped_key, shredded_at)
+VALUES (sqlc.arg(organization_id)::uuid, NULL, CURRENT_TIMESTAMP)
+ON CONFLICT (organization_id) DO UPDATE
+SET wrapped_key = NULL,
+    shredded_at = COALESCE(organization_keys.shredded_at, CURRENT_TIMESTAMP);
@@ -0,0 +1,129 @@
+-- name: CreateOrganization :one
+INSERT INTO organizations (id, slug, name, status, trial_ends_at, isolation, schema_name, plan, data_region)
+VALUES (sqlc.arg(id)::uuid, sqlc.arg(slug), sqlc.arg(name), sqlc.arg(status), sqlc.narg(trial_ends_at), sqlc.arg(isolation), sqlc.narg(schema_name), sqlc.arg(plan), sqlc.narg(data_region))
+RETURNING *;
+
+-- name: GetOrganizationByID :one
+SELECT *
+FROM organizations
+WHERE id = sqlc.arg(id)::uuid
+LIMIT 1;
+
+-- name: GetOrganizationBySlug :one
+SELECT *
+FROM organizations
+WHERE slug = sqlc.arg(slug)
+LIMIT 1;
+
+-- name: ListOrganizations :many
+SELECT *
+FROM organizations
+ORDER BY slug;
+
+-- name: ListOrganizationIDs :many
+SELECT id
+FROM organizations
+WHERE status <> 'CLOSED'
+ORDER BY id;
+
+-- name: ListSchemaOrganizations :many
+SELECT *
+FROM organizations
+WHERE isolation = 'SCHEMA'
+  AND purged_at IS NULL
+ORDER BY id;
+
+-- name: DeleteOrganization :exec
+DELETE FROM organizations
+WHERE id = sqlc.arg(id)::uuid;
+
+-- name: CreateAuditChainHead :exec
+INSERT INTO audit_chain_head (organization_id, last_sequence, last_hash)
+VALUES (sqlc.arg(organization_id)::uuid, 0, sqlc.arg(last_hash))
+ON CONFLICT DO NOTHING;
+
+-- name: LockOrganizationForUpdate :one
+SELECT *
+FROM organizations
+WHERE id = sqlc.arg(organization_id)::uuid
+FOR UPDATE;
+
+-- name: UpdateOrganizationQuotas :one
+UPDATE organizations
+SET max_clinics = sqlc.narg(max_clinics),
+    max_dentists = sqlc.narg(max_dentists),
+    max_requests_per_minute = sqlc.narg(max_requests_per_minute),
+    max_storage_mb = sqlc.narg(max_storage_mb),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+RETURNING *;
+
+-- name: GetOrganizationUsage :one
+SELECT
+    (SELECT COUNT(*) FROM clinics c WHERE c.organization_id = sqlc.arg(organization_id)::uuid AND c.deleted_at IS NULL)::int AS clinics,
+    (SELECT COUNT(*) FROM dentists d WHERE d.organization_id = sqlc.arg(organization_id)::uuid AND d.deleted_at IS NULL)::int AS dentists,
+    ((SELECT COALESCE(SUM(d.photo_size_bytes), 0) FROM dentists d WHERE d.organization_id = sqlc.arg(organization_id)::uuid AND d.photo_key IS NOT NULL)
+        + (SELECT COALESCE(SUM(gd.size_bytes), 0) FROM generated_documents gd WHERE gd.organization_id = sqlc.arg(organization_id)::uuid)
+        + (SELECT COALESCE(SUM(dsr.signed_size_bytes), 0) FROM document_signature_requests dsr WHERE dsr.organization_id = sqlc.arg(organization_id)::uuid)
+        + (SELECT COALESCE(SUM(ats.size_bytes), 0) FROM attachment_scans ats WHERE ats.organization_id = sqlc.arg(organization_id)::uuid AND ats.status = 'PENDING')
+        + (SELECT COALESCE(SUM(r.size_bytes), 0) FROM reports r WHERE r.organization_id = sqlc.arg(organization_id)::uuid))::bigint AS storage_bytes;
+
+-- name: UpdateOrganizationStatus :one
+UPDATE organizations
+SET status = sqlc.arg(status),
+    trial_ends_at = sqlc.narg(trial_ends_at),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND status <> 'CLOSED'
+RETURNING *;
+
+-- name: UpdateOrganizationPlan :one
+UPDATE organizations
+SET plan = sqlc.arg(plan),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND status <> 'CLOSED'
+RETURNING *;
+
+-- name: UpdateOrganizationDataRegion :one
+UPDATE organizations
+SET data_region = sqlc.narg(data_region),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND status <> 'CLOSED'
+RETURNING *;
+
+-- name: CloseOrganization :one
+UPDATE organizations
+SET status = 'CLOSED',
+    trial_ends_at = NULL,
+    closed_at = COALESCE(closed_at, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND purged_at IS NULL
+RETURNING *;
+
+-- name: SetOrganizationExport :one
+UPDATE organizations
+SET export_key = sqlc.arg(export_key),
+    purge_after = sqlc.arg(purge_after)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND status = 'CLOSED'
+RETURNING *;
+
+-- name: ListOrganizationsDueForPurge :many
+SELECT *
+FROM organizations
+WHERE status = 'CLOSED'
+  AND purged_at IS NULL
+  AND purge_after <= sqlc.arg(cutoff)::timestamptz
+ORDER BY purge_after
+LIMIT sqlc.arg(page_limit);
+
+-- name: MarkOrganizationPurged :exec
+UPDATE organizations
+SET purged_at = CURRENT_TIMESTAMP,
+    export_key = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid;
@@ -0,0 +1,208 @@
+-- name: CreateClinicPayable :one
+INSERT INTO clinic_payables (
+    id,
+    organization_id,
+    clinic_id,
+    amount_cents,
+    description,
+    external_reference,
+    occurred_on,
+    created_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(amount_cents),
+    sqlc.arg(description),
+    sqlc.narg(external_reference),
+    sqlc.arg(occurred_on)::date,
+    sqlc.narg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: ListClinicPayables :many
+SELECT *
+FROM clinic_payables
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(open)::boolean IS NULL OR (payout_batch_id IS NULL) = sqlc.narg(open)::boolean)
+ORDER BY occurred_on DESC, id DESC;
+
+-- name: ListClinicsWithOpenPayables :many
+SELECT DISTINCT clinic_id
+FROM clinic_payables
+WHERE payout_batch_id IS NULL
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND occurred_on BETWEEN sqlc.arg(period_start)::date AND sqlc.arg(period_end)::date
+ORDER BY clinic_id;
+
+-- name: AssignClinicPayablesToBatch :many
+UPDATE clinic_payables
+SET payout_batch_id = sqlc.arg(batch_id)::uuid
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND payout_batch_id IS NULL
+  AND occurred_on BETWEEN sqlc.arg(period_start)::date AND sqlc.arg(period_end)::date
+RETURNING amount_cents;
+
+-- name: ReleasePayoutBatchPayables :execrows
+UPDATE clinic_payables
+SET payout_batch_id = NULL
+WHERE payout_batch_id = sqlc.arg(batch_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CreatePayoutBatch :one
+INSERT INTO payout_batches (
+    id,
+    organization_id,
+    format,
+    period_start,
+    period_end,
+    payment_date,
+    created_by_user_id
+) VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(format),
+    sqlc.arg(period_start)::date,
+    sqlc.arg(period_end)::date,
+    sqlc.arg(payment_date)::date,
+    sqlc.narg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: CompletePayoutBatch :exec
+UPDATE payout_batches
+SET total_cents = sqlc.arg(total_cents),
+    item_count = sqlc.arg(item_count),
+    skipped = sqlc.arg(skipped)::jsonb,
+    file_name = sqlc.arg(file_name),
+    file_content = sqlc.arg(file_content)
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: CreatePayoutBatchItem :exec
+INSERT INTO payout_batch_items (
+    organization_id,
+    batch_id,
+    clinic_id,
+    bank_account_id,
+    amount_cents,
+    payable_count,
+    bank_code,
+    branch_number,
+    account_number,
+    account_type,
+    holder_name,
+    holder_tax_id
+) VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(batch_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(bank_account_id)::uuid,
+    sqlc.arg(amount_cents),
+    sqlc.arg(payable_count),
+    sqlc.arg(bank_code),
+    sqlc.arg(branch_number),
+    sqlc.arg(account_number),
+    sqlc.arg(account_type),
+    sqlc.arg(holder_name),
+    sqlc.arg(holder_tax_id)
+);
+
+-- name: GetPayoutBatch :one
+SELECT
+    id,
+    file_sequence,
+    format,
+    status,
+    period_start,
+    period_end,
+    payment_date,
+    total_cents,
+    item_count,
+    skipped,
+    file_name,
+    failure_reason,
+    created_by_user_id,
+    status_changed_at,
+    created_at
+FROM payout_batches
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: GetPayoutBatchFile :one
+SELECT file_name, file_content, format
+FROM payout_batches
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: ListPayoutBatchesCursor :many
+SELECT
+    id,
+    file_sequence,
+    format,
+    status,
+    period_start,
+    period_end,
+    payment_date,
+    total_cents,
+    item_count,
+    skipped,
+    file_name,
+    failure_reason,
+    created_by_user_id,
+    status_changed_at,
+    created_at
+FROM payout_batches
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at DESC, id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListPayoutBatchItems :many
+SELECT *
+FROM payout_batch_items
+WHERE batch_id = sqlc.arg(batch_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY clinic_id;
+
+-- name: ListPayoutReceiptRecipients :many
+SELECT
+    i.clinic_id,
+    i.amount_cents,
+    i.payable_count,
+    b.file_sequence,
+    b.payment_date,
+    p.legal_name,
+    p.trade_name,
+    p.email,
+    p.phone
+FROM payout_batch_items i
+JOIN payout_batches b ON b.id = i.batch_id
+JOIN clinics c ON c.id = i.clinic_id
+JOIN people p ON p.id = c.person_id
+WHERE i.batch_id = sqlc.arg(batch_id)::uuid
+  AND i.organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY i.clinic_id;
+
+-- name: LockPayoutBatchForUpdate :one
+SELECT status
+FROM payout_batches
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+FOR UPDATE;
+
+-- name: UpdatePayoutBatchStatus :exec
+UPDATE payout_batches
+SET status = sqlc.arg(status),
+    failure_reason = sqlc.narg(failure_reason),
+    status_changed_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
@@ -0,0 +1,77 @@
+-- name: CreatePendingDeletion :exec
+INSERT INTO pending_deletions (
+    organization_id,
+    resource_type,
+    resource_id,
+    requested_by_user_id,
+    deleted_at,
+    cascade_after
+) VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(resource_type),
+    sqlc.arg(resource_id)::uuid,
+    sqlc.narg(requested_by_user_id)::uuid,
+    CURRENT_TIMESTAMP,
+    CURRENT_TIMESTAMP + make_interval(secs => sqlc.arg(grace_seconds)::double precision)
+);
+
+-- name: DeletePendingDeletion :execrows
+DELETE FROM pending_deletions
+WHERE resource_type = sqlc.arg(resource_type)
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND resource_id = sqlc.arg(resource_id)::uuid;
+
+-- name: ListDuePendingDeletions :many
+SELECT *
+FROM pending_deletions
+WHERE cascade_after <= sqlc.arg(now)::timestamptz
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY cascade_after, resource_id
+LIMIT sqlc.arg(batch_size);
+
+-- name: ListTrashCursor :many
+WITH trash AS (
+    SELECT
+        pd.resource_type,
+        pd.resource_id AS id,
+        p.legal_name,
+        p.tax_id_number,
+        pd.deleted_at,
+        pd.cascade_after,
+        pd.requested_by_user_id
+    FROM pending_deletions pd
+    JOIN clinics c ON pd.resource_type = 'CLINIC' AND c.id = pd.resource_id
+    JOIN people p ON p.id = c.person_id
+    WHERE pd.organization_id = sqlc.arg(organization_id)::uuid
+    UNION ALL
+    SELECT
+        pd.resource_type,
+        pd.resource_id AS id,
+        p.legal_name,
+        p.tax_id_number,
+        pd.deleted_at,
+        pd.cascade_after,
+        pd.requested_by_user_id
+    FROM pending_deletions pd
+    JOIN dentists d ON pd.resource_type = 'DENTIST' AND d.id = pd.resource_id
+    JOIN people p ON p.id = d.person_id
+    WHERE pd.organization_id = sqlc.arg(organization_id)::uuid
+)
+SELECT
+    t.resource_type::text AS resource_type,
+    t.id::uuid AS id,
+    t.legal_name::text AS legal_name,
+    t.tax_id_number::text AS tax_id_number,
+    t.deleted_at::timestamptz AS deleted_at,
+    t.cascade_after::timestamptz AS cascade_after,
+    t.requested_by_user_id,
+    u.email AS requested_by_email
+FROM trash t
+LEFT JOIN users u ON u.id = t.requested_by_user_id
+WHERE (sqlc.narg(resource_type)::text IS NULL OR t.resource_type = sqlc.narg(resource_type)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (t.cascade_after, t.id) > (sqlc.narg(cursor_cascade_after)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY t.cascade_after, t.id
+LIMIT sqlc.arg(page_limit);
@@ -1,6 +1,7 @@
 -- name: CreatePerson :one
 INSERT INTO people (
     id,
+    organization_id,
     person_type,
     tax_id_type,
     tax_id_number,
@@ -10,6 +11,7 @@ INSERT INTO people (
     phone
 ) VALUES (
     sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
     sqlc.arg(person_type),
     sqlc.arg(tax_id_type),
     sqlc.arg(tax_id_number),
@@ -24,6 +26,7 @@ RETURNING *;
 SELECT *
 FROM people
 WHERE tax_id_number = sqlc.arg(tax_id_number)
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL
 LIMIT 1;
 
@@ -36,6 +39,7 @@ SET
     phone = COALESCE(sqlc.narg(phone), phone),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL
 RETURNING *;
 
@@ -44,4 +48,41 @@ UPDATE people
 SET deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
   AND deleted_at IS NULL;
+
+-- name: GetPersonIncludingDeleted :one
+SELECT *
+FROM people
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+LIMIT 1;
+
+-- name: ExistsOtherActivePersonByTaxID :one
+SELECT EXISTS (
+    SELECT 1
+    FROM people
+    WHERE tax_id_number = sqlc.arg(tax_id_number)
+      AND organization_id = sqlc.arg(organization_id)::uuid
+      AND id <> sqlc.arg(person_id)::uuid
+      AND deleted_at IS NULL
+);
+
+-- name: RestorePerson :execrows
+UPDATE people
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NOT NULL;
+
+-- name: DeleteUnusedPersonAt :execrows
+UPDATE people p
+SET deleted_at = sqlc.arg(deleted_at)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE p.id = sqlc.arg(id)::uuid
+  AND p.organization_id = sqlc.arg(organization_id)::uuid
+  AND p.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM clinics c WHERE c.person_id = p.id AND c.deleted_at IS NULL)
+  AND NOT EXISTS (SELECT 1 FROM dentists d WHERE d.person_id = p.id AND d.deleted_at IS NULL)
+  AND NOT EXISTS (SELECT 1 FROM suppliers s WHERE s.person_id = p.id AND s.deleted_at IS NULL);
@@ -0,0 +1,10 @@
+-- name: CreatePlatformOperator :one
+INSERT INTO platform_operators (id, email, password_hash)
+VALUES (sqlc.arg(id)::uuid, sqlc.arg(email), sqlc.arg(password_hash))
+RETURNING *;
+
+-- name: GetPlatformOperatorByEmail :one
+SELECT *
+FROM platform_operators
+WHERE lower(email) = lower(sqlc.arg(email))
+LIMIT 1;
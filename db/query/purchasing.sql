@@ -0,0 +1,181 @@
+-- name: CreateSupplier :one
+INSERT INTO suppliers (id, organization_id, person_id, notes)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(person_id)::uuid,
+    sqlc.narg(notes)
+)
+RETURNING *;
+
+-- name: GetSupplier :one
+SELECT
+    s.*,
+    p.tax_id_number,
+    p.legal_name,
+    p.trade_name,
+    p.email,
+    p.phone
+FROM suppliers s
+JOIN people p ON p.id = s.person_id
+WHERE s.id = sqlc.arg(id)::uuid
+  AND s.organization_id = sqlc.arg(organization_id)::uuid
+  AND s.deleted_at IS NULL;
+
+-- name: ListSuppliersCursor :many
+SELECT
+    s.*,
+    p.tax_id_number,
+    p.legal_name,
+    p.trade_name,
+    p.email,
+    p.phone
+FROM suppliers s
+JOIN people p ON p.id = s.person_id
+WHERE s.organization_id = sqlc.arg(organization_id)::uuid
+  AND s.deleted_at IS NULL
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (s.created_at, s.id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY s.created_at, s.id
+LIMIT sqlc.arg(page_limit);
+
+-- name: UpdateSupplierNotes :exec
+UPDATE suppliers
+SET notes = NULLIF(sqlc.arg(notes)::text, ''),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: HasOpenSupplierPurchaseOrders :one
+SELECT EXISTS (
+    SELECT 1
+    FROM purchase_orders
+    WHERE supplier_id = sqlc.arg(supplier_id)::uuid
+      AND organization_id = sqlc.arg(organization_id)::uuid
+      AND status IN ('DRAFT', 'SUBMITTED', 'PARTIALLY_RECEIVED')
+);
+
+-- name: DeleteSupplier :execrows
+UPDATE suppliers
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: CreatePurchaseOrder :one
+INSERT INTO purchase_orders (id, organization_id, clinic_id, supplier_id, notes, created_by_user_id)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(supplier_id)::uuid,
+    sqlc.narg(notes),
+    sqlc.narg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: CreatePurchaseOrderItem :one
+INSERT INTO purchase_order_items (id, organization_id, purchase_order_id, item_id, quantity_ordered, unit_cost_cents)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(purchase_order_id)::uuid,
+    sqlc.arg(item_id)::uuid,
+    sqlc.arg(quantity_ordered),
+    sqlc.arg(unit_cost_cents)
+)
+RETURNING *;
+
+-- name: GetPurchaseOrder :one
+SELECT *
+FROM purchase_orders
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: LockPurchaseOrderForUpdate :one
+SELECT *
+FROM purchase_orders
+WHERE id = sqlc.arg(id)::uuid
+  AND clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+FOR UPDATE;
+
+-- name: ListPurchaseOrdersCursor :many
+SELECT *
+FROM purchase_orders
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(status)::text IS NULL OR status = sqlc.narg(status)::text)
+  AND (sqlc.narg(supplier_id)::uuid IS NULL OR supplier_id = sqlc.narg(supplier_id)::uuid)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY created_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: ListPurchaseOrderItems :many
+SELECT
+    poi.*,
+    i.name AS item_name,
+    i.unit AS item_unit
+FROM purchase_order_items poi
+JOIN inventory_items i ON i.id = poi.item_id
+WHERE poi.purchase_order_id = ANY(sqlc.arg(purchase_order_ids)::uuid[])
+  AND poi.organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY poi.purchase_order_id, poi.created_at, poi.id;
+
+-- name: SetPurchaseOrderStatus :one
+UPDATE purchase_orders
+SET status = sqlc.arg(status)::text,
+    submitted_at = CASE WHEN sqlc.arg(status)::text = 'SUBMITTED' THEN CURRENT_TIMESTAMP ELSE submitted_at END,
+    received_at = CASE WHEN sqlc.arg(status)::text = 'RECEIVED' THEN CURRENT_TIMESTAMP ELSE received_at END,
+    cancelled_at = CASE WHEN sqlc.arg(status)::text = 'CANCELLED' THEN CURRENT_TIMESTAMP ELSE cancelled_at END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+RETURNING *;
+
+-- name: ReceivePurchaseOrderItem :one
+UPDATE purchase_order_items
+SET quantity_received = quantity_received + sqlc.arg(quantity)
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+RETURNING *;
+
+-- name: CreatePurchaseOrderReceipt :one
+INSERT INTO purchase_order_receipts (id, organization_id, purchase_order_id, purchase_order_item_id, movement_id, quantity)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(purchase_order_id)::uuid,
+    sqlc.arg(purchase_order_item_id)::uuid,
+    sqlc.arg(movement_id)::uuid,
+    sqlc.arg(quantity)
+)
+RETURNING *;
+
+-- name: ListSupplierSpend :many
+SELECT
+    s.id AS supplier_id,
+    p.legal_name,
+    p.tax_id_number,
+    COUNT(DISTINCT r.purchase_order_id)::bigint AS order_count,
+    SUM(r.quantity)::bigint AS units_received,
+    SUM(r.quantity * poi.unit_cost_cents)::bigint AS spend_cents
+FROM purchase_order_receipts r
+JOIN purchase_order_items poi ON poi.id = r.purchase_order_item_id
+JOIN purchase_orders po ON po.id = r.purchase_order_id
+JOIN suppliers s ON s.id = po.supplier_id
+JOIN people p ON p.id = s.person_id
+WHERE r.organization_id = sqlc.arg(organization_id)::uuid
+  AND r.created_at >= sqlc.arg(received_from)::timestamptz
+  AND r.created_at < sqlc.arg(received_to)::timestamptz
+  AND (sqlc.narg(clinic_id)::uuid IS NULL OR po.clinic_id = sqlc.narg(clinic_id)::uuid)
+GROUP BY s.id, p.legal_name, p.tax_id_number
+ORDER BY spend_cents DESC, s.id;
@@ -0,0 +1,91 @@
+-- name: CreateReportDefinition :one
+INSERT INTO report_definitions (
+    id,
+    organization_id,
+    clinic_id,
+    name,
+    definition,
+    schedule,
+    recipients,
+    next_run_at,
+    created_by_user_id
+)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(name),
+    sqlc.arg(definition)::jsonb,
+    sqlc.narg(schedule),
+    sqlc.arg(recipients)::text[],
+    sqlc.narg(next_run_at),
+    sqlc.arg(created_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: GetReportDefinition :one
+SELECT *
+FROM report_definitions
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: ListClinicReportDefinitions :many
+SELECT *
+FROM report_definitions
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+ORDER BY lower(name), id;
+
+-- name: UpdateReportDefinition :one
+UPDATE report_definitions
+SET name = sqlc.arg(name),
+    definition = sqlc.arg(definition)::jsonb,
+    schedule = sqlc.narg(schedule),
+    recipients = sqlc.arg(recipients)::text[],
+    next_run_at = sqlc.narg(next_run_at),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteReportDefinition :execrows
+UPDATE report_definitions
+SET schedule = NULL,
+    next_run_at = NULL,
+    deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: ClaimDueReportDefinitions :many
+UPDATE report_definitions
+SET next_run_at = sqlc.arg(lease_until)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+    SELECT due.id
+    FROM report_definitions due
+    JOIN clinics c ON c.id = due.clinic_id
+    WHERE due.organization_id = sqlc.arg(organization_id)::uuid
+      AND due.deleted_at IS NULL
+      AND due.next_run_at <= sqlc.arg(due_before)::timestamptz
+      AND c.deleted_at IS NULL
+    ORDER BY due.next_run_at
+    LIMIT sqlc.arg(page_limit)
+    FOR UPDATE OF due SKIP LOCKED
+)
+  AND organization_id = sqlc.arg(organization_id)::uuid
+RETURNING *;
+
+-- name: CompleteReportDefinitionRun :exec
+UPDATE report_definitions
+SET last_run_at = sqlc.arg(ran_at)::timestamptz,
+    next_run_at = sqlc.arg(next_run_at)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND schedule IS NOT NULL;
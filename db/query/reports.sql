@@ -0,0 +1,120 @@
+-- name: CreateReport :one
+INSERT INTO reports (
+    id,
+    organization_id,
+    clinic_id,
+    report_type,
+    format,
+    parameters,
+    requested_by_user_id
+)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(report_type),
+    sqlc.arg(format),
+    sqlc.arg(parameters)::jsonb,
+    sqlc.arg(requested_by_user_id)::uuid
+)
+RETURNING *;
+
+-- name: GetReport :one
+SELECT *
+FROM reports
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListReportsCursor :many
+SELECT *
+FROM reports r
+WHERE r.organization_id = sqlc.arg(organization_id)::uuid
+  AND (sqlc.narg(clinic_id)::uuid IS NULL OR r.clinic_id = sqlc.narg(clinic_id)::uuid)
+  AND (sqlc.narg(status)::text IS NULL OR r.status = sqlc.narg(status)::text)
+  AND (
+      sqlc.narg(cursor_id)::uuid IS NULL
+      OR (r.created_at, r.id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)::uuid)
+  )
+ORDER BY r.created_at DESC, r.id DESC
+LIMIT sqlc.arg(page_limit);
+
+-- name: ClaimDueReports :many
+UPDATE reports
+SET status = 'RUNNING',
+    progress = 0,
+    attempts = attempts + 1,
+    next_attempt_at = sqlc.arg(lease_until)::timestamptz,
+    started_at = COALESCE(started_at, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id IN (
+    SELECT due.id
+    FROM reports due
+    WHERE due.organization_id = sqlc.arg(organization_id)::uuid
+      AND due.status IN ('PENDING', 'RUNNING')
+      AND due.next_attempt_at <= sqlc.arg(due_before)::timestamptz
+    ORDER BY due.next_attempt_at
+    LIMIT sqlc.arg(page_limit)
+    FOR UPDATE SKIP LOCKED
+)
+  AND organization_id = sqlc.arg(organization_id)::uuid
+RETURNING *;
+
+-- name: UpdateReportProgress :exec
+UPDATE reports
+SET progress = sqlc.arg(progress),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'RUNNING';
+
+-- name: RescheduleReport :exec
+UPDATE reports
+SET status = 'PENDING',
+    progress = 0,
+    next_attempt_at = sqlc.arg(next_attempt_at)::timestamptz,
+    failure_reason = sqlc.arg(failure_reason),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'RUNNING';
+
+-- name: CompleteReport :execrows
+UPDATE reports
+SET status = 'READY',
+    progress = 100,
+    row_count = sqlc.arg(row_count),
+    attachment_key = sqlc.arg(attachment_key),
+    file_name = sqlc.arg(file_name),
+    content_type = sqlc.arg(content_type),
+    size_bytes = sqlc.arg(size_bytes),
+    failure_reason = NULL,
+    completed_at = CURRENT_TIMESTAMP,
+    expires_at = sqlc.arg(expires_at)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'RUNNING';
+
+-- name: FailReport :execrows
+UPDATE reports
+SET status = 'FAILED',
+    failure_reason = sqlc.arg(failure_reason),
+    completed_at = CURRENT_TIMESTAMP,
+    expires_at = sqlc.arg(expires_at)::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status = 'RUNNING';
+
+-- name: ListExpiredReports :many
+SELECT *
+FROM reports
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND expires_at <= sqlc.arg(expired_before)::timestamptz
+ORDER BY expires_at, id
+LIMIT sqlc.arg(page_limit);
+
+-- name: DeleteReport :exec
+DELETE FROM reports
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
@@ -0,0 +1,427 @@
+-- name: ListRetentionCandidates :many
+WITH candidates AS (
+    SELECT
+        'CLINIC'::text AS resource_type,
+        c.id,
+        c.person_id,
+        p.legal_name,
+        c.deleted_at,
+        CASE
+            WHEN EXISTS (SELECT 1 FROM ledger_transactions lt WHERE lt.clinic_id = c.id)
+                OR EXISTS (SELECT 1 FROM clinic_payables cp WHERE cp.clinic_id = c.id)
+                OR EXISTS (SELECT 1 FROM payout_batch_items pbi WHERE pbi.clinic_id = c.id)
+                THEN 'FINANCIAL_RECORDS'
+            WHEN EXISTS (SELECT 1 FROM clinics b WHERE b.parent_clinic_id = c.id)
+                THEN 'BRANCHES'
+            WHEN EXISTS (SELECT 1 FROM generated_documents gd WHERE gd.clinic_id = c.id)
+                THEN 'GENERATED_DOCUMENTS'
+        END AS retention_reason,
+        NULL::text AS photo_key,
+        NULL::text AS photo_thumbnail_key,
+        EXISTS (SELECT 1 FROM legal_holds lh WHERE lh.resource_type = 'CLINIC' AND lh.resource_id = c.id AND lh.released_at IS NULL) AS on_legal_hold,
+        c.parent_clinic_id IS NOT NULL AS is_branch
+    FROM clinics c
+    JOIN people p ON p.id = c.person_id
+    WHERE c.deleted_at < sqlc.arg(cutoff)::timestamptz
+      AND c.organization_id = sqlc.arg(organization_id)::uuid
+      AND p.anonymized_at IS NULL
+      AND NOT EXISTS (SELECT 1 FROM pending_deletions pd WHERE pd.resource_type = 'CLINIC' AND pd.resource_id = c.id)
+      AND NOT EXISTS (SELECT 1 FROM reports r WHERE r.clinic_id = c.id)
+      AND NOT EXISTS (
+          SELECT 1
+          FROM clinics b
+          WHERE b.parent_clinic_id = c.id
+            AND (b.deleted_at IS NULL OR b.deleted_at >= sqlc.arg(cutoff)::timestamptz)
+      )
+    UNION ALL
+    SELECT
+        'DENTIST'::text AS resource_type,
+        d.id,
+        d.person_id,
+        p.legal_name,
+        d.deleted_at,
+        CASE
+            WHEN EXISTS (
+                SELECT 1
+                FROM users u
+                WHERE u.dentist_id = d.id
+                  AND (
+                      EXISTS (SELECT 1 FROM audit_logs al WHERE al.actor_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM clinic_notes cn WHERE cn.author_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM clinic_onboarding_transitions cot WHERE cot.performed_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM bank_account_changes bac WHERE bac.requested_by_user_id = u.id OR bac.reviewed_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM clinic_financial_holds cfh WHERE cfh.placed_by_user_id = u.id OR cfh.lifted_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM payout_batches pb WHERE pb.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM clinic_payables cp WHERE cp.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM ledger_transactions lt WHERE lt.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM clinic_revisions cr WHERE cr.changed_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM clinic_announcements ca WHERE ca.author_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM inventory_movements im WHERE im.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM purchase_orders po WHERE po.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM equipment_maintenance_records emr WHERE emr.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM clinic_shifts cs WHERE cs.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM clinic_tasks ct WHERE ct.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM generated_documents gd WHERE gd.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM document_signature_requests dsr WHERE dsr.created_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM legal_holds lh WHERE lh.placed_by_user_id = u.id OR lh.released_by_user_id = u.id)
+                      OR EXISTS (SELECT 1 FROM document_retention_rules drr WHERE drr.updated_by_user_id = u.id)
+                      OR EXISTS (
+                          SELECT 1
+                          FROM attachment_scans ats
+                          WHERE ats.uploaded_by_user_id = u.id
+                            AND NOT (ats.resource_type = 'DENTIST_PHOTO' AND ats.resource_id = d.id)
+                      )
+                  )
+            ) THEN 'USER_ACTIVITY'
+            WHEN EXISTS (SELECT 1 FROM clinic_dentists cd WHERE cd.substitute_for_dentist_id = d.id)
+                THEN 'ASSIGNMENT_HISTORY'
+            WHEN EXISTS (SELECT 1 FROM generated_documents gd WHERE gd.dentist_id = d.id)
+                THEN 'GENERATED_DOCUMENTS'
+        END AS retention_reason,
+        d.photo_key,
+        d.photo_thumbnail_key,
+        EXISTS (SELECT 1 FROM legal_holds lh WHERE lh.resource_type = 'DENTIST' AND lh.resource_id = d.id AND lh.released_at IS NULL) AS on_legal_hold,
+        FALSE AS is_branch
+    FROM dentists d
+    JOIN people p ON p.id = d.person_id
+    WHERE d.deleted_at < sqlc.arg(cutoff)::timestamptz
+      AND d.organization_id = sqlc.arg(organization_id)::uuid
+      AND p.anonymized_at IS NULL
+      AND NOT EXISTS (SELECT 1 FROM pending_deletions pd WHERE pd.resource_type = 'DENTIST' AND pd.resource_id = d.id)
+)
+SELECT
+    resource_type::text AS resource_type,
+    id::uuid AS id,
+    person_id::uuid AS person_id,
+    legal_name::text AS legal_name,
+    deleted_at::timestamptz AS deleted_at,
+    COALESCE(retention_reason, '')::text AS retention_reason,
+    COALESCE(photo_key, '')::text AS photo_key,
+    COALESCE(photo_thumbnail_key, '')::text AS photo_thumbnail_key,
+    on_legal_hold::bool AS on_legal_hold
+FROM candidates
+-- Held records go last so they cannot crowd the batch.
+ORDER BY on_legal_hold, is_branch DESC, deleted_at, id
+LIMIT sqlc.arg(batch_size);
+
+-- name: DeleteClinicChildRecords :exec
+WITH deleted_notes AS (
+    DELETE FROM clinic_notes WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_transitions AS (
+    DELETE FROM clinic_onboarding_transitions WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_changes AS (
+    DELETE FROM bank_account_changes WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_holds AS (
+    DELETE FROM clinic_financial_holds WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_registry AS (
+    DELETE FROM clinic_registry_records WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_hours AS (
+    DELETE FROM clinic_operating_hours WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_holidays AS (
+    DELETE FROM clinic_holidays WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_settings AS (
+    DELETE FROM clinic_settings WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_listings AS (
+    DELETE FROM clinic_directory_listings WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_links AS (
+    DELETE FROM clinic_dentists WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_revisions AS (
+    DELETE FROM clinic_revisions WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_announcements AS (
+    DELETE FROM clinic_announcements WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_receipts AS (
+    DELETE FROM purchase_order_receipts r
+    USING purchase_orders po
+    WHERE po.id = r.purchase_order_id
+      AND po.clinic_id = sqlc.arg(clinic_id)::uuid
+      AND r.organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_maintenance AS (
+    DELETE FROM equipment_maintenance_records WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_shifts AS (
+    DELETE FROM clinic_shifts WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_time_clock_entries AS (
+    DELETE FROM time_clock_entries WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_time_clock_settings AS (
+    DELETE FROM clinic_time_clock_settings WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_tasks AS (
+    DELETE FROM clinic_tasks WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_task_rules AS (
+    DELETE FROM clinic_task_rules WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+)
+UPDATE audit_logs
+SET clinic_id = NULL
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- Stock, purchasing and equipment rows reference each other, so they go in two steps after DeleteClinicChildRecords.
+-- name: PurgeClinicOperationalRecords :exec
+WITH deleted_order_items AS (
+    DELETE FROM purchase_order_items poi
+    USING purchase_orders po
+    WHERE po.id = poi.purchase_order_id
+      AND po.clinic_id = sqlc.arg(clinic_id)::uuid
+      AND poi.organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_movements AS (
+    DELETE FROM inventory_movements WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+)
+DELETE FROM equipment
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeClinicInventory :exec
+WITH deleted_orders AS (
+    DELETE FROM purchase_orders WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+)
+DELETE FROM inventory_items
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeClinicBankAccounts :exec
+DELETE FROM bank_accounts
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeClinicReportDefinitions :exec
+DELETE FROM report_definitions
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeClinic :execrows
+DELETE FROM clinics
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: PurgeDentistAttachmentScans :exec
+DELETE FROM attachment_scans
+WHERE resource_type = 'DENTIST_PHOTO'
+  AND resource_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: DeleteDentistChildRecords :exec
+WITH deleted_specialties AS (
+    DELETE FROM dentist_specialties WHERE dentist_id = sqlc.arg(dentist_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_documents AS (
+    DELETE FROM dentist_documents WHERE dentist_id = sqlc.arg(dentist_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_links AS (
+    DELETE FROM clinic_dentists WHERE dentist_id = sqlc.arg(dentist_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_announcement_receipts AS (
+    DELETE FROM clinic_announcement_recipients WHERE dentist_id = sqlc.arg(dentist_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_notification_preferences AS (
+    DELETE FROM dentist_notification_preferences WHERE dentist_id = sqlc.arg(dentist_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_shifts AS (
+    DELETE FROM clinic_shifts WHERE dentist_id = sqlc.arg(dentist_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_time_clock_entries AS (
+    DELETE FROM time_clock_entries WHERE dentist_id = sqlc.arg(dentist_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+)
+DELETE FROM users
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: PurgeDentist :execrows
+DELETE FROM dentists
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at < sqlc.arg(cutoff)::timestamptz;
+
+-- name: PurgeOrphanAddress :exec
+DELETE FROM addresses a
+WHERE a.person_id = sqlc.arg(person_id)::uuid
+  AND a.organization_id = sqlc.arg(organization_id)::uuid
+  AND NOT EXISTS (SELECT 1 FROM clinics c WHERE c.person_id = a.person_id)
+  AND NOT EXISTS (SELECT 1 FROM dentists d WHERE d.person_id = a.person_id)
+  AND NOT EXISTS (SELECT 1 FROM suppliers s WHERE s.person_id = a.person_id);
+
+-- name: PurgeOrphanPerson :execrows
+DELETE FROM people p
+WHERE p.id = sqlc.arg(id)::uuid
+  AND p.organization_id = sqlc.arg(organization_id)::uuid
+  AND p.deleted_at IS NOT NULL
+  AND NOT EXISTS (SELECT 1 FROM clinics c WHERE c.person_id = p.id)
+  AND NOT EXISTS (SELECT 1 FROM dentists d WHERE d.person_id = p.id)
+  AND NOT EXISTS (SELECT 1 FROM suppliers s WHERE s.person_id = p.id);
+
+-- name: AnonymizePerson :execrows
+UPDATE people
+SET legal_name = 'ANONYMIZED',
+    trade_name = NULL,
+    tax_id_number = 'ANONYMIZED',
+    email = NULL,
+    phone = NULL,
+    anonymized_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NOT NULL
+  AND anonymized_at IS NULL;
+
+-- name: DeleteAddressByPersonID :exec
+DELETE FROM addresses
+WHERE person_id = sqlc.arg(person_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: AnonymizeClinicRecords :exec
+WITH deleted_notes AS (
+    DELETE FROM clinic_notes WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_registry AS (
+    DELETE FROM clinic_registry_records WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_listings AS (
+    DELETE FROM clinic_directory_listings WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+),
+deleted_revisions AS (
+    DELETE FROM clinic_revisions WHERE clinic_id = sqlc.arg(clinic_id)::uuid AND organization_id = sqlc.arg(organization_id)::uuid
+)
+UPDATE clinics
+SET deactivation_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: AnonymizeDentistRecords :exec
+WITH scrubbed_documents AS (
+    UPDATE dentist_documents
+    SET document_number = NULL,
+        notes = NULL,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+      AND organization_id = sqlc.arg(organization_id)::uuid
+),
+scrubbed_users AS (
+    UPDATE users
+    SET email = 'anonymized+' || users.id::text || '@invalid',
+        password_hash = '',
+        updated_at = CURRENT_TIMESTAMP
+    WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+      AND organization_id = sqlc.arg(organization_id)::uuid
+)
+UPDATE dentists
+SET cro_number = NULL,
+    cro_state = NULL,
+    photo_key = NULL,
+    photo_updated_at = NULL,
+    photo_thumbnail_key = NULL,
+    photo_thumbnail_updated_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListDocumentRetentionRules :many
+SELECT *
+FROM document_retention_rules
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY document_kind;
+
+-- name: UpsertDocumentRetentionRule :one
+INSERT INTO document_retention_rules (organization_id, document_kind, retention_days, updated_by_user_id)
+VALUES (
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(document_kind),
+    sqlc.arg(retention_days),
+    sqlc.arg(updated_by_user_id)::uuid
+)
+ON CONFLICT (organization_id, document_kind) DO UPDATE
+SET retention_days = EXCLUDED.retention_days,
+    updated_by_user_id = EXCLUDED.updated_by_user_id,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: DeleteDocumentRetentionRule :execrows
+DELETE FROM document_retention_rules
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND document_kind = sqlc.arg(document_kind);
+
+-- name: ListExpiredGeneratedDocuments :many
+SELECT
+    gd.id,
+    gd.clinic_id,
+    gd.kind,
+    gd.attachment_key,
+    gd.created_at,
+    r.retention_days
+FROM generated_documents gd
+JOIN document_retention_rules r ON r.organization_id = gd.organization_id AND r.document_kind = gd.kind
+WHERE gd.organization_id = sqlc.arg(organization_id)::uuid
+  AND gd.created_at < sqlc.arg(now)::timestamptz - make_interval(days => r.retention_days)
+  AND NOT EXISTS (
+      SELECT 1
+      FROM legal_holds lh
+      WHERE lh.released_at IS NULL
+        AND (
+            (lh.resource_type = 'GENERATED_DOCUMENT' AND lh.resource_id = gd.id)
+            OR (lh.resource_type = 'CLINIC' AND lh.resource_id = gd.clinic_id)
+            OR (lh.resource_type = 'DENTIST' AND lh.resource_id = gd.dentist_id)
+        )
+  )
+  AND NOT EXISTS (
+      SELECT 1
+      FROM document_signature_requests dsr
+      WHERE dsr.document_id = gd.id
+        AND dsr.status = 'PENDING'
+  )
+ORDER BY gd.created_at, gd.id
+LIMIT sqlc.arg(batch_size);
+
+-- name: ListGeneratedDocumentSignedKeys :many
+SELECT signed_attachment_key::text
+FROM document_signature_requests
+WHERE document_id = sqlc.arg(document_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND signed_attachment_key IS NOT NULL;
+
+-- name: PurgeGeneratedDocumentSignatureRequests :exec
+DELETE FROM document_signature_requests
+WHERE document_id = sqlc.arg(document_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND status <> 'PENDING';
+
+-- name: PurgeExpiredGeneratedDocument :execrows
+-- The hold and rule checks are repeated here so a hold placed after the listing still wins.
+DELETE FROM generated_documents gd
+USING document_retention_rules r
+WHERE gd.id = sqlc.arg(id)::uuid
+  AND gd.organization_id = sqlc.arg(organization_id)::uuid
+  AND r.organization_id = gd.organization_id
+  AND r.document_kind = gd.kind
+  AND gd.created_at < sqlc.arg(now)::timestamptz - make_interval(days => r.retention_days)
+  AND NOT EXISTS (
+      SELECT 1
+      FROM legal_holds lh
+      WHERE lh.released_at IS NULL
+        AND (
+            (lh.resource_type = 'GENERATED_DOCUMENT' AND lh.resource_id = gd.id)
+            OR (lh.resource_type = 'CLINIC' AND lh.resource_id = gd.clinic_id)
+            OR (lh.resource_type = 'DENTIST' AND lh.resource_id = gd.dentist_id)
+        )
+  )
+  AND NOT EXISTS (
+      SELECT 1
+      FROM document_signature_requests dsr
+      WHERE dsr.document_id = gd.id
+  );
@@ -0,0 +1,87 @@
+-- name: CreateSpecialty :one
+INSERT INTO specialties (id, organization_id, code, name, description)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(code),
+    sqlc.arg(name),
+    sqlc.narg(description)
+)
+RETURNING *;
+
+-- name: GetSpecialtyByID :one
+SELECT *
+FROM specialties
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: ListSpecialties :many
+SELECT *
+FROM specialties
+WHERE deleted_at IS NULL
+  AND organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY name, id;
+
+-- name: UpdateSpecialty :one
+UPDATE specialties
+SET name = COALESCE(sqlc.narg(name), name),
+    description = COALESCE(sqlc.narg(description), description),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+RETURNING *;
+
+-- name: DeleteSpecialty :execrows
+UPDATE specialties
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: CountActiveSpecialtiesByIDs :one
+SELECT COUNT(*)
+FROM specialties
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: AddDentistSpecialty :exec
+INSERT INTO dentist_specialties (organization_id, dentist_id, specialty_id)
+VALUES (sqlc.arg(organization_id)::uuid, sqlc.arg(dentist_id)::uuid, sqlc.arg(specialty_id)::uuid)
+ON CONFLICT (dentist_id, specialty_id) DO NOTHING;
+
+-- name: DeleteDentistSpecialtiesByDentist :execrows
+DELETE FROM dentist_specialties
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: DeleteDentistSpecialtiesBySpecialty :execrows
+DELETE FROM dentist_specialties
+WHERE specialty_id = sqlc.arg(specialty_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListSpecialtiesByDentistIDs :many
+SELECT
+    ds.dentist_id,
+    s.id,
+    s.code,
+    s.name,
+    s.description
+FROM dentist_specialties ds
+JOIN specialties s ON s.id = ds.specialty_id
+WHERE ds.dentist_id = ANY(sqlc.arg(dentist_ids)::uuid[])
+  AND ds.organization_id = sqlc.arg(organization_id)::uuid
+  AND s.deleted_at IS NULL
+ORDER BY ds.dentist_id, s.name;
+
+-- name: CopyDentistSpecialties :execrows
+INSERT INTO dentist_specialties (organization_id, dentist_id, specialty_id)
+SELECT ds.organization_id, sqlc.arg(target_dentist_id)::uuid, ds.specialty_id
+FROM dentist_specialties ds
+WHERE ds.dentist_id = sqlc.arg(dentist_id)::uuid
+  AND ds.organization_id = sqlc.arg(organization_id)::uuid
+ON CONFLICT (dentist_id, specialty_id) DO NOTHING;
@@ -0,0 +1,110 @@
+-- name: GetClinicTimeClockSettings :one
+SELECT *
+FROM clinic_time_clock_settings
+WHERE clinic_id = sqlc.arg(clinic_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: UpsertClinicTimeClockSettings :one
+INSERT INTO clinic_time_clock_settings (clinic_id, organization_id, latitude, longitude, radius_meters, allowed_networks, enforce)
+VALUES (
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.narg(latitude),
+    sqlc.narg(longitude),
+    sqlc.narg(radius_meters),
+    sqlc.arg(allowed_networks)::text[],
+    sqlc.arg(enforce)
+)
+ON CONFLICT (clinic_id) DO UPDATE
+SET latitude = EXCLUDED.latitude,
+    longitude = EXCLUDED.longitude,
+    radius_meters = EXCLUDED.radius_meters,
+    allowed_networks = EXCLUDED.allowed_networks,
+    enforce = EXCLUDED.enforce,
+    updated_at = CURRENT_TIMESTAMP
+RETURNING *;
+
+-- name: GetOpenTimeClockEntry :one
+SELECT *
+FROM time_clock_entries
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clock_out_at IS NULL
+FOR UPDATE;
+
+-- name: CreateTimeClockEntry :one
+INSERT INTO time_clock_entries (
+    id,
+    organization_id,
+    clinic_id,
+    dentist_id,
+    clock_in_at,
+    clock_in_ip,
+    clock_in_latitude,
+    clock_in_longitude,
+    clock_in_accuracy_meters,
+    clock_in_distance_meters,
+    clock_in_verified
+)
+VALUES (
+    sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
+    sqlc.arg(clinic_id)::uuid,
+    sqlc.arg(dentist_id)::uuid,
+    sqlc.arg(clock_in_at)::timestamptz,
+    sqlc.narg(ip),
+    sqlc.narg(latitude),
+    sqlc.narg(longitude),
+    sqlc.narg(accuracy_meters),
+    sqlc.narg(distance_meters),
+    sqlc.narg(verified)
+)
+RETURNING *;
+
+-- name: CloseTimeClockEntry :one
+UPDATE time_clock_entries
+SET clock_out_at = sqlc.arg(clock_out_at)::timestamptz,
+    clock_out_ip = sqlc.narg(ip),
+    clock_out_latitude = sqlc.narg(latitude),
+    clock_out_longitude = sqlc.narg(longitude),
+    clock_out_accuracy_meters = sqlc.narg(accuracy_meters),
+    clock_out_distance_meters = sqlc.narg(distance_meters),
+    clock_out_verified = sqlc.narg(verified),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND clock_out_at IS NULL
+RETURNING *;
+
+-- name: ListClinicTimeClockEntries :many
+SELECT
+    e.*,
+    p.legal_name AS dentist_name,
+    p.tax_id_number AS dentist_tax_id_number
+FROM time_clock_entries e
+JOIN dentists d ON d.id = e.dentist_id
+JOIN people p ON p.id = d.person_id
+WHERE e.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND e.organization_id = sqlc.arg(organization_id)::uuid
+  AND e.clock_in_at >= sqlc.arg(from_at)::timestamptz
+  AND e.clock_in_at < sqlc.arg(to_at)::timestamptz
+ORDER BY p.legal_name, e.dentist_id, e.clock_in_at, e.id;
+
+-- name: MoveDentistTimeClockEntries :execrows
+UPDATE time_clock_entries
+SET dentist_id = sqlc.arg(target_dentist_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListOpenClinicTimeClockEntries :many
+SELECT
+    e.*,
+    p.legal_name AS dentist_name
+FROM time_clock_entries e
+JOIN dentists d ON d.id = e.dentist_id
+JOIN people p ON p.id = d.person_id
+WHERE e.clinic_id = sqlc.arg(clinic_id)::uuid
+  AND e.organization_id = sqlc.arg(organization_id)::uuid
+  AND e.clock_out_at IS NULL
+ORDER BY e.clock_in_at, e.id;
@@ -0,0 +1,21 @@
+-- name: CreateUsageRecord :exec
+INSERT INTO usage_records (id, organization_id, metric, quantity, recorded_at)
+VALUES (sqlc.arg(id)::uuid, sqlc.arg(organization_id)::uuid, sqlc.arg(metric), sqlc.arg(quantity), sqlc.arg(recorded_at)::timestamptz);
+
+-- Counters add up over the day; gauges keep the day's peak.
+-- name: UpsertUsageDailyRollup :exec
+INSERT INTO usage_daily_rollups (organization_id, metric, day, quantity)
+VALUES (sqlc.arg(organization_id)::uuid, sqlc.arg(metric), sqlc.arg(day)::date, sqlc.arg(quantity))
+ON CONFLICT (organization_id, metric, day) DO UPDATE
+SET quantity = CASE
+        WHEN usage_daily_rollups.metric IN ('api_calls', 'sms_sent') THEN usage_daily_rollups.quantity + EXCLUDED.quantity
+        ELSE GREATEST(usage_daily_rollups.quantity, EXCLUDED.quantity)
+    END,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: ListUsageDailyRollups :many
+SELECT *
+FROM usage_daily_rollups
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND day BETWEEN sqlc.arg(from_day)::date AND sqlc.arg(to_day)::date
+ORDER BY day, metric;
@@ -1,12 +1,18 @@
 -- name: CreateUser :one
 INSERT INTO users (
     id,
+    organization_id,
     email,
-    password_hash
+    password_hash,
+    role,
+    dentist_id
 ) VALUES (
     sqlc.arg(id)::uuid,
+    sqlc.arg(organization_id)::uuid,
     sqlc.arg(email),
-    sqlc.arg(password_hash)
+    sqlc.arg(password_hash),
+    sqlc.arg(role),
+    sqlc.narg(dentist_id)::uuid
 )
 RETURNING *;
 
@@ -16,3 +22,62 @@ FROM users
 WHERE lower(email) = lower(sqlc.arg(email))
   AND deleted_at IS NULL
 LIMIT 1;
+
+-- name: GetUserByID :one
+SELECT *
+FROM users
+WHERE id = sqlc.arg(id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+LIMIT 1;
+
+-- name: GetOldestAdminUser :one
+SELECT *
+FROM users
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+  AND role = 'ADMIN'
+  AND deleted_at IS NULL
+ORDER BY created_at, id
+LIMIT 1;
+
+-- name: DeleteUsersByDentistID :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL;
+
+-- name: MoveDentistUser :execrows
+UPDATE users
+SET dentist_id = sqlc.arg(target_dentist_id)::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1
+      FROM users existing
+      WHERE existing.dentist_id = sqlc.arg(target_dentist_id)::uuid
+        AND existing.deleted_at IS NULL
+  );
+
+-- name: ExistsUserEmailConflictForDentist :one
+SELECT EXISTS (
+    SELECT 1
+    FROM users u
+    JOIN users other ON lower(other.email) = lower(u.email)
+        AND other.id <> u.id
+        AND other.deleted_at IS NULL
+    WHERE u.dentist_id = sqlc.arg(dentist_id)::uuid
+      AND u.organization_id = sqlc.arg(organization_id)::uuid
+      AND u.deleted_at = sqlc.arg(deleted_at)::timestamptz
+);
+
+-- name: RestoreUsersByDentistIDDeletedAt :execrows
+UPDATE users
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = sqlc.arg(dentist_id)::uuid
+  AND organization_id = sqlc.arg(organization_id)::uuid
+  AND deleted_at = sqlc.arg(deleted_at)::timestamptz;
@@ -0,0 +1,28 @@
+-- name: EnsureWarehouseExportWatermark :exec
+INSERT INTO warehouse_export_watermarks (organization_id, sink, table_name, last_changed_at, last_id)
+VALUES (sqlc.arg(organization_id)::uuid, sqlc.arg(sink), sqlc.arg(table_name), 'epoch'::timestamptz, '00000000-0000-0000-0000-000000000000'::uuid)
+ON CONFLICT (organization_id, sink, table_name) DO NOTHING;
+
+-- name: GetWarehouseExportWatermarkForUpdate :one
+SELECT *
+FROM warehouse_export_watermarks
+WHERE sink = sqlc.arg(sink)
+  AND table_name = sqlc.arg(table_name)
+  AND organization_id = sqlc.arg(organization_id)::uuid
+FOR UPDATE;
+
+-- name: AdvanceWarehouseExportWatermark :exec
+UPDATE warehouse_export_watermarks
+SET last_changed_at = sqlc.arg(last_changed_at)::timestamptz,
+    last_id = sqlc.arg(last_id)::uuid,
+    exported_count = exported_count + sqlc.arg(exported)::bigint,
+    updated_at = CURRENT_TIMESTAMP
+WHERE sink = sqlc.arg(sink)
+  AND table_name = sqlc.arg(table_name)
+  AND organization_id = sqlc.arg(organization_id)::uuid;
+
+-- name: ListWarehouseExportWatermarks :many
+SELECT *
+FROM warehouse_export_watermarks
+WHERE organization_id = sqlc.arg(organization_id)::uuid
+ORDER BY sink, table_name;
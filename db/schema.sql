@@ -1,5 +1,40 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY,
+    slug TEXT NOT NULL,
+    name TEXT NOT NULL,
+    max_clinics INTEGER CHECK (max_clinics > 0),
+    max_dentists INTEGER CHECK (max_dentists > 0),
+    max_requests_per_minute INTEGER CHECK (max_requests_per_minute > 0),
+    max_storage_mb INTEGER CHECK (max_storage_mb > 0),
+    status TEXT NOT NULL DEFAULT 'ACTIVE',
+    trial_ends_at TIMESTAMPTZ,
+    closed_at TIMESTAMPTZ,
+    export_key TEXT,
+    purge_after TIMESTAMPTZ,
+    purged_at TIMESTAMPTZ,
+    isolation TEXT NOT NULL DEFAULT 'SHARED',
+    schema_name TEXT,
+    plan TEXT NOT NULL DEFAULT 'ENTERPRISE',
+    data_region TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CHECK (slug ~ '^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$'),
+    CHECK (status IN ('TRIAL', 'ACTIVE', 'SUSPENDED', 'CLOSED')),
+    CONSTRAINT organizations_trial_check CHECK ((status = 'TRIAL') = (trial_ends_at IS NOT NULL)),
+    CONSTRAINT organizations_closed_check CHECK ((status = 'CLOSED') = (closed_at IS NOT NULL)),
+    CONSTRAINT organizations_purge_check CHECK (purge_after IS NULL OR closed_at IS NOT NULL),
+    CHECK (isolation IN ('SHARED', 'SCHEMA')),
+    CONSTRAINT organizations_isolation_schema_check CHECK ((isolation = 'SCHEMA') = (schema_name IS NOT NULL)),
+    CHECK (schema_name IS NULL OR schema_name ~ '^tenant_[0-9a-f]{32}$'),
+    CHECK (plan IN ('BASIC', 'PRO', 'ENTERPRISE')),
+    CHECK (data_region IS NULL OR data_region ~ '^[a-z]{2}(-[a-z0-9]+)*$')
+);
+
 CREATE TABLE IF NOT EXISTS people (
     id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
     person_type TEXT NOT NULL CHECK (person_type IN ('INDIVIDUAL', 'COMPANY')),
     tax_id_type TEXT NOT NULL CHECK (tax_id_type IN ('CPF', 'CNPJ')),
     tax_id_number TEXT NOT NULL,
@@ -10,89 +45,1470 @@ CREATE TABLE IF NOT EXISTS people (
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMPTZ,
+    anonymized_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
     CHECK (
         (person_type = 'INDIVIDUAL' AND tax_id_type = 'CPF') OR
         (person_type = 'COMPANY' AND tax_id_type = 'CNPJ')
     )
 );
 
+CREATE TABLE IF NOT EXISTS addresses (
+    person_id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    street TEXT NOT NULL,
+    number TEXT,
+    complement TEXT,
+    city TEXT NOT NULL,
+    state TEXT NOT NULL,
+    cep TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE RESTRICT
+);
+
 CREATE TABLE IF NOT EXISTS clinics (
     id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
     person_id UUID NOT NULL,
+    parent_clinic_id UUID,
+    timezone TEXT NOT NULL DEFAULT 'America/Sao_Paulo',
+    onboarding_status TEXT NOT NULL DEFAULT 'DRAFT' CHECK (onboarding_status IN ('DRAFT', 'DOCUMENTS_PENDING', 'BANK_VERIFIED', 'ACTIVE')),
+    onboarding_status_changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deactivated_at TIMESTAMPTZ,
+    deactivation_reason TEXT,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMPTZ,
-    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE RESTRICT
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE RESTRICT,
+    FOREIGN KEY (parent_clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    CONSTRAINT clinics_parent_self_check CHECK (parent_clinic_id IS NULL OR parent_clinic_id <> id)
+);
+
+CREATE TABLE IF NOT EXISTS clinic_registry_records (
+    clinic_id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    legal_name TEXT NOT NULL,
+    trade_name TEXT,
+    cnae_code TEXT,
+    cnae_description TEXT,
+    registration_status TEXT NOT NULL,
+    source TEXT NOT NULL,
+    fetched_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT
 );
 
 CREATE TABLE IF NOT EXISTS dentists (
     id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
     person_id UUID NOT NULL,
+    cro_number TEXT,
+    cro_state TEXT,
+    photo_key TEXT,
+    photo_size_bytes BIGINT NOT NULL DEFAULT 0,
+    photo_updated_at TIMESTAMPTZ,
+    photo_thumbnail_key TEXT,
+    photo_thumbnail_updated_at TIMESTAMPTZ,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMPTZ,
-    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE RESTRICT
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE RESTRICT,
+    CONSTRAINT dentists_cro_pair_check CHECK ((cro_number IS NULL) = (cro_state IS NULL))
 );
 
 CREATE TABLE IF NOT EXISTS clinic_dentists (
+    organization_id UUID NOT NULL,
     clinic_id UUID NOT NULL,
     dentist_id UUID NOT NULL,
     is_admin BOOLEAN NOT NULL DEFAULT FALSE,
     is_legal_representative BOOLEAN NOT NULL DEFAULT FALSE,
     started_at TIMESTAMPTZ NOT NULL,
     ended_at TIMESTAMPTZ,
+    planned_end_at TIMESTAMPTZ,
+    substitute_for_dentist_id UUID,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     PRIMARY KEY (clinic_id, dentist_id, started_at),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
     FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
-    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT,
+    FOREIGN KEY (substitute_for_dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT,
+    CONSTRAINT clinic_dentists_planned_end_check CHECK (planned_end_at IS NULL OR planned_end_at > started_at),
+    CONSTRAINT clinic_dentists_substitute_planned_end_check CHECK (substitute_for_dentist_id IS NULL OR planned_end_at IS NOT NULL),
+    CONSTRAINT clinic_dentists_substitute_self_check CHECK (substitute_for_dentist_id IS NULL OR substitute_for_dentist_id <> dentist_id)
+);
+
+CREATE TABLE IF NOT EXISTS clinic_operating_hours (
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    weekday SMALLINT NOT NULL,
+    opens_minute SMALLINT NOT NULL,
+    closes_minute SMALLINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (clinic_id, weekday, opens_minute),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    CHECK (weekday BETWEEN 0 AND 6),
+    CHECK (opens_minute >= 0 AND closes_minute <= 1440 AND opens_minute < closes_minute)
+);
+
+CREATE TABLE IF NOT EXISTS clinic_holidays (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    holiday_date DATE NOT NULL,
+    name TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS clinic_settings (
+    clinic_id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    default_appointment_duration_minutes SMALLINT NOT NULL,
+    reminder_lead_minutes INTEGER[] NOT NULL,
+    invoice_number_prefix TEXT NOT NULL,
+    locale TEXT NOT NULL,
+    currency TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    CHECK (default_appointment_duration_minutes BETWEEN 5 AND 480)
+);
+
+CREATE TABLE IF NOT EXISTS clinic_directory_listings (
+    clinic_id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    listed BOOLEAN NOT NULL DEFAULT FALSE,
+    public_phone TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS specialties (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    code TEXT NOT NULL,
+    name TEXT NOT NULL,
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS dentist_specialties (
+    organization_id UUID NOT NULL,
+    dentist_id UUID NOT NULL,
+    specialty_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (dentist_id, specialty_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT,
+    FOREIGN KEY (specialty_id) REFERENCES specialties(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS dentist_documents (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    dentist_id UUID NOT NULL,
+    document_type TEXT NOT NULL,
+    document_number TEXT,
+    issued_at DATE,
+    expires_at DATE NOT NULL,
+    notes TEXT,
+    last_notified_threshold_days INTEGER,
+    last_notified_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT,
+    CHECK (document_type IN ('CRO_RENEWAL', 'LIABILITY_INSURANCE', 'VACCINATION_CARD')),
+    CHECK (issued_at IS NULL OR issued_at <= expires_at)
 );
 
 CREATE TABLE IF NOT EXISTS bank_accounts (
     id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
     clinic_id UUID NOT NULL,
     bank_code TEXT NOT NULL,
     branch_number TEXT NOT NULL,
     account_number TEXT NOT NULL,
+    account_type TEXT NOT NULL DEFAULT 'CHECKING' CHECK (account_type IN ('CHECKING', 'SAVINGS', 'PAYMENT')),
+    holder_name TEXT,
+    holder_tax_id TEXT,
+    holder_review_required BOOLEAN NOT NULL DEFAULT FALSE,
+    is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+    verification_status TEXT NOT NULL DEFAULT 'UNVERIFIED' CHECK (verification_status IN ('UNVERIFIED', 'PENDING', 'VERIFIED')),
+    verification_provider TEXT,
+    verification_reference TEXT,
+    verification_requested_at TIMESTAMPTZ,
+    verified_at TIMESTAMPTZ,
+    verification_failure_reason TEXT,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
     FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT
 );
 
 CREATE TABLE IF NOT EXISTS users (
     id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
     email TEXT NOT NULL,
     password_hash TEXT NOT NULL,
+    role TEXT NOT NULL DEFAULT 'ADMIN',
+    dentist_id UUID,
+    can_reveal_bank_details BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    CHECK (role IN ('ADMIN', 'DENTIST')),
+    CONSTRAINT users_role_dentist_check CHECK ((role = 'DENTIST') = (dentist_id IS NOT NULL)),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS clinic_onboarding_transitions (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    evidence_reference TEXT NOT NULL,
+    bank_account_id UUID,
+    notes TEXT,
+    performed_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (bank_account_id) REFERENCES bank_accounts(id) ON DELETE RESTRICT,
+    FOREIGN KEY (performed_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS clinic_notes (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    author_user_id UUID NOT NULL,
+    body TEXT NOT NULL,
+    pinned BOOLEAN NOT NULL DEFAULT FALSE,
     created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
     updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
-    deleted_at TIMESTAMPTZ
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (author_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS clinic_note_mentions (
+    organization_id UUID NOT NULL,
+    note_id UUID NOT NULL,
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('CLINIC', 'DENTIST', 'BANK_ACCOUNT')),
+    entity_id UUID NOT NULL,
+    PRIMARY KEY (note_id, entity_type, entity_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (note_id) REFERENCES clinic_notes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS clinic_announcements (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    author_user_id UUID NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (author_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS clinic_announcement_recipients (
+    organization_id UUID NOT NULL,
+    announcement_id UUID NOT NULL,
+    dentist_id UUID NOT NULL,
+    read_at TIMESTAMPTZ,
+    PRIMARY KEY (announcement_id, dentist_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (announcement_id) REFERENCES clinic_announcements(id) ON DELETE CASCADE,
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS dentist_notification_preferences (
+    organization_id UUID NOT NULL,
+    dentist_id UUID PRIMARY KEY,
+    channels TEXT[] NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT,
+    CHECK (channels <@ ARRAY['EMAIL', 'SMS', 'WHATSAPP']::TEXT[])
+);
+
+CREATE TABLE IF NOT EXISTS inventory_items (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    sku TEXT,
+    unit TEXT NOT NULL,
+    quantity BIGINT NOT NULL DEFAULT 0 CHECK (quantity >= 0),
+    minimum_quantity BIGINT NOT NULL DEFAULT 0 CHECK (minimum_quantity >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS inventory_movements (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    item_id UUID NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('PURCHASE', 'CONSUMPTION', 'ADJUSTMENT')),
+    quantity_delta BIGINT NOT NULL,
+    quantity_after BIGINT NOT NULL CHECK (quantity_after >= 0),
+    unit_cost_cents BIGINT CHECK (unit_cost_cents >= 0),
+    notes TEXT,
+    created_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (item_id) REFERENCES inventory_items(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK (unit_cost_cents IS NULL OR kind = 'PURCHASE')
+);
+
+CREATE TABLE IF NOT EXISTS suppliers (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    person_id UUID NOT NULL,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (person_id) REFERENCES people(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS purchase_orders (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    supplier_id UUID NOT NULL,
+    status TEXT NOT NULL DEFAULT 'DRAFT' CHECK (status IN ('DRAFT', 'SUBMITTED', 'PARTIALLY_RECEIVED', 'RECEIVED', 'CANCELLED')),
+    notes TEXT,
+    created_by_user_id UUID,
+    submitted_at TIMESTAMPTZ,
+    received_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (supplier_id) REFERENCES suppliers(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_items (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    purchase_order_id UUID NOT NULL,
+    item_id UUID NOT NULL,
+    quantity_ordered BIGINT NOT NULL CHECK (quantity_ordered > 0),
+    quantity_received BIGINT NOT NULL DEFAULT 0 CHECK (quantity_received >= 0),
+    unit_cost_cents BIGINT NOT NULL CHECK (unit_cost_cents >= 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE RESTRICT,
+    FOREIGN KEY (item_id) REFERENCES inventory_items(id) ON DELETE RESTRICT,
+    CHECK (quantity_received <= quantity_ordered)
+);
+
+CREATE TABLE IF NOT EXISTS purchase_order_receipts (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    purchase_order_id UUID NOT NULL,
+    purchase_order_item_id UUID NOT NULL,
+    movement_id UUID NOT NULL,
+    quantity BIGINT NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (purchase_order_id) REFERENCES purchase_orders(id) ON DELETE RESTRICT,
+    FOREIGN KEY (purchase_order_item_id) REFERENCES purchase_order_items(id) ON DELETE RESTRICT,
+    FOREIGN KEY (movement_id) REFERENCES inventory_movements(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS equipment (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('AUTOCLAVE', 'COMPRESSOR', 'XRAY', 'DENTAL_CHAIR', 'OTHER')),
+    manufacturer TEXT,
+    model TEXT,
+    serial_number TEXT,
+    maintenance_interval_days INT CHECK (maintenance_interval_days > 0),
+    last_maintained_at DATE,
+    next_maintenance_due DATE,
+    overdue_notified_for DATE,
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS equipment_maintenance_records (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    equipment_id UUID NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('PREVENTIVE', 'CORRECTIVE', 'CALIBRATION', 'INSPECTION')),
+    performed_at DATE NOT NULL,
+    performed_by TEXT,
+    cost_cents BIGINT CHECK (cost_cents >= 0),
+    notes TEXT,
+    next_maintenance_due DATE,
+    created_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (equipment_id) REFERENCES equipment(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS clinic_shifts (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    dentist_id UUID NOT NULL,
+    starts_at TIMESTAMPTZ NOT NULL,
+    ends_at TIMESTAMPTZ NOT NULL,
+    notes TEXT,
+    created_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK (ends_at > starts_at)
+);
+
+CREATE TABLE IF NOT EXISTS clinic_time_clock_settings (
+    clinic_id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    radius_meters INT,
+    allowed_networks TEXT[] NOT NULL DEFAULT '{}',
+    enforce BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    CHECK ((latitude IS NULL) = (longitude IS NULL) AND (latitude IS NULL) = (radius_meters IS NULL)),
+    CHECK (latitude BETWEEN -90 AND 90 AND longitude BETWEEN -180 AND 180 AND radius_meters > 0)
+);
+
+CREATE TABLE IF NOT EXISTS time_clock_entries (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    dentist_id UUID NOT NULL,
+    clock_in_at TIMESTAMPTZ NOT NULL,
+    clock_in_ip TEXT,
+    clock_in_latitude DOUBLE PRECISION,
+    clock_in_longitude DOUBLE PRECISION,
+    clock_in_accuracy_meters DOUBLE PRECISION,
+    clock_in_distance_meters DOUBLE PRECISION,
+    clock_in_verified BOOLEAN,
+    clock_out_at TIMESTAMPTZ,
+    clock_out_ip TEXT,
+    clock_out_latitude DOUBLE PRECISION,
+    clock_out_longitude DOUBLE PRECISION,
+    clock_out_accuracy_meters DOUBLE PRECISION,
+    clock_out_distance_meters DOUBLE PRECISION,
+    clock_out_verified BOOLEAN,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT,
+    CHECK (clock_out_at IS NULL OR clock_out_at >= clock_in_at)
+);
+
+CREATE TABLE IF NOT EXISTS clinic_task_rules (
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    trigger_type TEXT NOT NULL CHECK (trigger_type IN ('EQUIPMENT_MAINTENANCE_OVERDUE', 'DENTIST_DOCUMENT_EXPIRING', 'BANK_ACCOUNT_CHANGE_PENDING')),
+    assignee_user_id UUID,
+    due_in_days INT NOT NULL DEFAULT 0 CHECK (due_in_days BETWEEN 0 AND 90),
+    lead_days INT NOT NULL DEFAULT 0 CHECK (lead_days BETWEEN 0 AND 365),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (clinic_id, trigger_type),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (assignee_user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS clinic_tasks (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT,
+    status TEXT NOT NULL DEFAULT 'OPEN' CHECK (status IN ('OPEN', 'IN_PROGRESS', 'DONE', 'CANCELED')),
+    due_date DATE,
+    assignee_user_id UUID,
+    entity_type TEXT CHECK (entity_type IN ('CLINIC', 'DENTIST', 'BANK_ACCOUNT', 'BANK_ACCOUNT_CHANGE', 'EQUIPMENT', 'PURCHASE_ORDER')),
+    entity_id UUID,
+    automation_trigger TEXT,
+    automation_key TEXT,
+    created_by_user_id UUID,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (assignee_user_id) REFERENCES users(id) ON DELETE SET NULL,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK ((entity_type IS NULL) = (entity_id IS NULL)),
+    CHECK ((automation_trigger IS NULL) = (automation_key IS NULL))
 );
 
+CREATE TABLE IF NOT EXISTS document_templates (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('CONTRACT', 'BUDGET', 'OTHER')),
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS generated_documents (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    template_id UUID NOT NULL,
+    dentist_id UUID,
+    kind TEXT NOT NULL,
+    title TEXT NOT NULL,
+    attachment_key TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    checksum_sha256 TEXT NOT NULL,
+    placeholders TEXT[] NOT NULL DEFAULT '{}',
+    created_by_user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (template_id) REFERENCES document_templates(id) ON DELETE RESTRICT,
+    FOREIGN KEY (dentist_id) REFERENCES dentists(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS document_signature_requests (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    document_id UUID NOT NULL,
+    provider TEXT NOT NULL,
+    reference TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SIGNED', 'DECLINED', 'EXPIRED')),
+    signers JSONB NOT NULL,
+    failure_reason TEXT,
+    signed_attachment_key TEXT,
+    signed_size_bytes BIGINT NOT NULL DEFAULT 0,
+    signed_checksum_sha256 TEXT,
+    created_by_user_id UUID NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (document_id) REFERENCES generated_documents(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS legal_holds (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    resource_type TEXT NOT NULL CHECK (resource_type IN ('CLINIC', 'DENTIST', 'GENERATED_DOCUMENT')),
+    resource_id UUID NOT NULL,
+    reason TEXT NOT NULL,
+    case_reference TEXT,
+    placed_by_user_id UUID NOT NULL,
+    placed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    released_by_user_id UUID,
+    released_at TIMESTAMPTZ,
+    release_notes TEXT,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (placed_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    FOREIGN KEY (released_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK ((released_at IS NULL) = (released_by_user_id IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS document_retention_rules (
+    organization_id UUID NOT NULL,
+    document_kind TEXT NOT NULL CHECK (document_kind IN ('CONTRACT', 'BUDGET', 'OTHER')),
+    retention_days INTEGER NOT NULL CHECK (retention_days > 0),
+    updated_by_user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, document_kind),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (updated_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS attachment_scans (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    resource_type TEXT NOT NULL CHECK (resource_type IN ('DENTIST_PHOTO')),
+    resource_id UUID NOT NULL,
+    quarantine_key TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'CLEAN', 'INFECTED', 'REJECTED')),
+    signature TEXT,
+    failure_reason TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    uploaded_by_user_id UUID NOT NULL,
+    scanned_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (uploaded_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS reports (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    report_type TEXT NOT NULL CHECK (report_type IN ('TIMESHEET', 'LEDGER', 'LOW_STOCK')),
+    format TEXT NOT NULL CHECK (format IN ('CSV', 'XLSX', 'PDF')),
+    parameters JSONB NOT NULL DEFAULT '{}'::jsonb,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'RUNNING', 'READY', 'FAILED')),
+    progress INTEGER NOT NULL DEFAULT 0 CHECK (progress BETWEEN 0 AND 100),
+    row_count INTEGER,
+    attachment_key TEXT,
+    file_name TEXT,
+    content_type TEXT,
+    size_bytes BIGINT NOT NULL DEFAULT 0,
+    failure_reason TEXT,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    requested_by_user_id UUID NOT NULL,
+    started_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (requested_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS report_definitions (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    name TEXT NOT NULL,
+    definition JSONB NOT NULL,
+    schedule TEXT CHECK (schedule IN ('DAILY', 'WEEKLY', 'MONTHLY')),
+    recipients TEXT[] NOT NULL DEFAULT '{}',
+    next_run_at TIMESTAMPTZ,
+    last_run_at TIMESTAMPTZ,
+    created_by_user_id UUID NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK ((schedule IS NULL) = (next_run_at IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS bank_account_changes (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    change_type TEXT NOT NULL CHECK (change_type IN ('ADD', 'REMOVE')),
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'APPROVED', 'REJECTED')),
+    bank_account_id UUID,
+    bank_code TEXT,
+    branch_number TEXT,
+    account_number TEXT,
+    account_type TEXT,
+    holder_name TEXT,
+    holder_tax_id TEXT,
+    reason TEXT,
+    requested_by_user_id UUID NOT NULL,
+    reviewed_by_user_id UUID,
+    reviewed_at TIMESTAMPTZ,
+    review_notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (bank_account_id) REFERENCES bank_accounts(id) ON DELETE RESTRICT,
+    FOREIGN KEY (requested_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    FOREIGN KEY (reviewed_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK (
+        (change_type = 'ADD' AND bank_code IS NOT NULL AND branch_number IS NOT NULL AND account_number IS NOT NULL)
+        OR (change_type = 'REMOVE' AND bank_account_id IS NOT NULL)
+    )
+);
+
+CREATE TABLE IF NOT EXISTS clinic_financial_holds (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    reason_code TEXT NOT NULL,
+    notes TEXT,
+    placed_by_user_id UUID NOT NULL,
+    placed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    lifted_by_user_id UUID,
+    lifted_at TIMESTAMPTZ,
+    lift_notes TEXT,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (placed_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    FOREIGN KEY (lifted_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK (reason_code IN ('FRAUD_SUSPICION', 'CHARGEBACK_DISPUTE', 'COMPLIANCE_REVIEW', 'OTHER')),
+    CHECK ((lifted_at IS NULL) = (lifted_by_user_id IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    file_sequence BIGSERIAL NOT NULL,
+    format TEXT NOT NULL CHECK (format IN ('CNAB240', 'PIX')),
+    status TEXT NOT NULL DEFAULT 'GENERATED' CHECK (status IN ('GENERATED', 'SUBMITTED', 'SETTLED', 'FAILED')),
+    period_start DATE NOT NULL,
+    period_end DATE NOT NULL,
+    payment_date DATE NOT NULL,
+    total_cents BIGINT NOT NULL DEFAULT 0,
+    item_count INTEGER NOT NULL DEFAULT 0,
+    skipped JSONB NOT NULL DEFAULT '[]'::jsonb,
+    file_name TEXT NOT NULL DEFAULT '',
+    file_content BYTEA NOT NULL DEFAULT ''::bytea,
+    failure_reason TEXT,
+    created_by_user_id UUID,
+    status_changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK (period_start <= period_end)
+);
+
+CREATE TABLE IF NOT EXISTS payout_batch_items (
+    organization_id UUID NOT NULL,
+    batch_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    bank_account_id UUID NOT NULL,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    payable_count INTEGER NOT NULL,
+    bank_code TEXT NOT NULL,
+    branch_number TEXT NOT NULL,
+    account_number TEXT NOT NULL,
+    account_type TEXT NOT NULL,
+    holder_name TEXT NOT NULL,
+    holder_tax_id TEXT NOT NULL,
+    PRIMARY KEY (batch_id, clinic_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (batch_id) REFERENCES payout_batches(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (bank_account_id) REFERENCES bank_accounts(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS clinic_payables (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    description TEXT NOT NULL,
+    external_reference TEXT,
+    occurred_on DATE NOT NULL,
+    payout_batch_id UUID,
+    created_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (payout_batch_id) REFERENCES payout_batches(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS ledger_transactions (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    kind TEXT NOT NULL,
+    description TEXT NOT NULL,
+    reference_type TEXT,
+    reference_id UUID,
+    occurred_on DATE NOT NULL,
+    created_by_user_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (created_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK (kind IN ('INVOICE', 'PAYMENT', 'FEE', 'PAYOUT', 'ADJUSTMENT')),
+    CHECK ((reference_type IS NULL) = (reference_id IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    organization_id UUID NOT NULL,
+    transaction_id UUID NOT NULL,
+    line SMALLINT NOT NULL,
+    account TEXT NOT NULL,
+    direction TEXT NOT NULL,
+    amount_cents BIGINT NOT NULL CHECK (amount_cents > 0),
+    PRIMARY KEY (transaction_id, line),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (transaction_id) REFERENCES ledger_transactions(id) ON DELETE RESTRICT,
+    CHECK (account IN ('CLINIC_BALANCE', 'PLATFORM_CASH', 'PLATFORM_REVENUE', 'PLATFORM_ADJUSTMENTS')),
+    CHECK (direction IN ('DEBIT', 'CREDIT'))
+);
+
+CREATE TABLE IF NOT EXISTS clinic_revisions (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID NOT NULL,
+    entity_type TEXT NOT NULL CHECK (entity_type IN ('PERSON', 'CLINIC', 'BANK_ACCOUNT')),
+    entity_id UUID NOT NULL,
+    operation TEXT NOT NULL CHECK (operation IN ('CREATE', 'UPDATE', 'DELETE')),
+    before_state JSONB NOT NULL,
+    after_state JSONB NOT NULL,
+    changed_by_user_id UUID,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (changed_by_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CHECK (before_state <> 'null'::jsonb OR after_state <> 'null'::jsonb)
+);
+
+CREATE TABLE IF NOT EXISTS pending_deletions (
+    organization_id UUID NOT NULL,
+    resource_type TEXT NOT NULL CHECK (resource_type IN ('CLINIC', 'DENTIST')),
+    resource_id UUID NOT NULL,
+    requested_by_user_id UUID,
+    deleted_at TIMESTAMPTZ NOT NULL,
+    cascade_after TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (resource_type, resource_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (requested_by_user_id) REFERENCES users(id) ON DELETE SET NULL,
+    CHECK (cascade_after >= deleted_at)
+);
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID,
+    actor_user_id UUID,
+    action TEXT NOT NULL,
+    entity_type TEXT NOT NULL,
+    entity_id UUID NOT NULL,
+    metadata JSONB NOT NULL DEFAULT '{}'::jsonb,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    chain_sequence BIGINT,
+    previous_hash TEXT,
+    entry_hash TEXT,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    FOREIGN KEY (clinic_id) REFERENCES clinics(id) ON DELETE RESTRICT,
+    FOREIGN KEY (actor_user_id) REFERENCES users(id) ON DELETE RESTRICT,
+    CONSTRAINT audit_logs_chain_check CHECK ((chain_sequence IS NULL) = (entry_hash IS NULL) AND (chain_sequence IS NULL) = (previous_hash IS NULL))
+);
+
+CREATE TABLE IF NOT EXISTS audit_chain_head (
+    organization_id UUID PRIMARY KEY,
+    last_sequence BIGINT NOT NULL,
+    last_hash TEXT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS platform_operators (
+    id UUID PRIMARY KEY,
+    email TEXT NOT NULL,
+    password_hash TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS organization_keys (
+    organization_id UUID PRIMARY KEY,
+    wrapped_key BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    shredded_at TIMESTAMPTZ,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    CHECK ((wrapped_key IS NULL) = (shredded_at IS NOT NULL))
+);
+
+CREATE TABLE IF NOT EXISTS audit_export_checkpoints (
+    organization_id UUID NOT NULL,
+    sink TEXT NOT NULL,
+    last_audit_id UUID NOT NULL,
+    exported_count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, sink),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS warehouse_export_watermarks (
+    organization_id UUID NOT NULL,
+    sink TEXT NOT NULL,
+    table_name TEXT NOT NULL,
+    last_changed_at TIMESTAMPTZ NOT NULL,
+    last_id UUID NOT NULL,
+    exported_count BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, sink, table_name),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS usage_records (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    metric TEXT NOT NULL,
+    quantity BIGINT NOT NULL,
+    recorded_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    CHECK (metric IN ('api_calls', 'active_clinics', 'storage_bytes', 'sms_sent')),
+    CHECK (quantity >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS usage_daily_rollups (
+    organization_id UUID NOT NULL,
+    metric TEXT NOT NULL,
+    day DATE NOT NULL,
+    quantity BIGINT NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (organization_id, metric, day),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT,
+    CHECK (metric IN ('api_calls', 'active_clinics', 'storage_bytes', 'sms_sent')),
+    CHECK (quantity >= 0)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    clinic_id UUID,
+    channel TEXT NOT NULL CHECK (channel IN ('EMAIL', 'SMS', 'WHATSAPP')),
+    template TEXT NOT NULL,
+    recipient TEXT NOT NULL,
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    dedupe_key TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'PENDING' CHECK (status IN ('PENDING', 'SENT', 'FAILED')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    delivery_status TEXT CHECK (delivery_status IN ('DELIVERED', 'BOUNCED', 'FAILED')),
+    bounce_type TEXT CHECK (bounce_type IN ('HARD', 'SOFT')),
+    delivery_detail TEXT,
+    delivery_updated_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT
+);
+
+CREATE TABLE IF NOT EXISTS notification_suppressions (
+    id UUID PRIMARY KEY,
+    organization_id UUID NOT NULL,
+    channel TEXT NOT NULL CHECK (channel IN ('EMAIL', 'SMS', 'WHATSAPP')),
+    recipient TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    notification_id UUID,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT
+);
+
+-- Databases created by an earlier version of this file keep their tables,
+-- so columns added since then are brought in here before any index uses them.
+ALTER TABLE dentists
+    ADD COLUMN IF NOT EXISTS cro_number TEXT,
+    ADD COLUMN IF NOT EXISTS cro_state TEXT;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'dentists'::regclass AND conname = 'dentists_cro_pair_check') THEN
+        ALTER TABLE dentists ADD CONSTRAINT dentists_cro_pair_check CHECK ((cro_number IS NULL) = (cro_state IS NULL));
+    END IF;
+END $$;
+
+ALTER TABLE dentists
+    ADD COLUMN IF NOT EXISTS photo_key TEXT,
+    ADD COLUMN IF NOT EXISTS photo_updated_at TIMESTAMPTZ;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS role TEXT NOT NULL DEFAULT 'ADMIN' CHECK (role IN ('ADMIN', 'DENTIST')),
+    ADD COLUMN IF NOT EXISTS dentist_id UUID REFERENCES dentists(id) ON DELETE RESTRICT;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'users'::regclass AND conname = 'users_role_dentist_check') THEN
+        ALTER TABLE users ADD CONSTRAINT users_role_dentist_check CHECK ((role = 'DENTIST') = (dentist_id IS NOT NULL));
+    END IF;
+END $$;
+
+ALTER TABLE clinic_dentists
+    ADD COLUMN IF NOT EXISTS planned_end_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS substitute_for_dentist_id UUID REFERENCES dentists(id) ON DELETE RESTRICT;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'clinic_dentists'::regclass AND conname = 'clinic_dentists_planned_end_check') THEN
+        ALTER TABLE clinic_dentists ADD CONSTRAINT clinic_dentists_planned_end_check CHECK (planned_end_at IS NULL OR planned_end_at > started_at);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'clinic_dentists'::regclass AND conname = 'clinic_dentists_substitute_planned_end_check') THEN
+        ALTER TABLE clinic_dentists ADD CONSTRAINT clinic_dentists_substitute_planned_end_check CHECK (substitute_for_dentist_id IS NULL OR planned_end_at IS NOT NULL);
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'clinic_dentists'::regclass AND conname = 'clinic_dentists_substitute_self_check') THEN
+        ALTER TABLE clinic_dentists ADD CONSTRAINT clinic_dentists_substitute_self_check CHECK (substitute_for_dentist_id IS NULL OR substitute_for_dentist_id <> dentist_id);
+    END IF;
+END $$;
+
+ALTER TABLE clinics
+    ADD COLUMN IF NOT EXISTS timezone TEXT NOT NULL DEFAULT 'America/Sao_Paulo';
+
+ALTER TABLE clinics
+    ADD COLUMN IF NOT EXISTS parent_clinic_id UUID REFERENCES clinics(id) ON DELETE RESTRICT;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'clinics'::regclass AND conname = 'clinics_parent_self_check') THEN
+        ALTER TABLE clinics ADD CONSTRAINT clinics_parent_self_check CHECK (parent_clinic_id IS NULL OR parent_clinic_id <> id);
+    END IF;
+END $$;
+
+ALTER TABLE clinics
+    ADD COLUMN IF NOT EXISTS onboarding_status TEXT NOT NULL DEFAULT 'DRAFT' CHECK (onboarding_status IN ('DRAFT', 'DOCUMENTS_PENDING', 'BANK_VERIFIED', 'ACTIVE')),
+    ADD COLUMN IF NOT EXISTS onboarding_status_changed_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+ALTER TABLE clinics
+    ADD COLUMN IF NOT EXISTS deactivated_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS deactivation_reason TEXT;
+
+ALTER TABLE bank_accounts
+    ADD COLUMN IF NOT EXISTS is_primary BOOLEAN NOT NULL DEFAULT FALSE;
+-- Clinics that predate the flag get their oldest active account as primary.
+UPDATE bank_accounts
+SET is_primary = TRUE
+WHERE id IN (
+    SELECT DISTINCT ON (clinic_id) id
+    FROM bank_accounts
+    WHERE deleted_at IS NULL
+    ORDER BY clinic_id, created_at, id
+)
+AND NOT EXISTS (
+    SELECT 1
+    FROM bank_accounts primary_account
+    WHERE primary_account.clinic_id = bank_accounts.clinic_id
+      AND primary_account.is_primary
+      AND primary_account.deleted_at IS NULL
+);
+
+ALTER TABLE bank_accounts
+    ADD COLUMN IF NOT EXISTS verification_status TEXT NOT NULL DEFAULT 'UNVERIFIED' CHECK (verification_status IN ('UNVERIFIED', 'PENDING', 'VERIFIED')),
+    ADD COLUMN IF NOT EXISTS verification_provider TEXT,
+    ADD COLUMN IF NOT EXISTS verification_reference TEXT,
+    ADD COLUMN IF NOT EXISTS verification_requested_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS verified_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS verification_failure_reason TEXT;
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS can_reveal_bank_details BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE bank_accounts
+    ADD COLUMN IF NOT EXISTS account_type TEXT NOT NULL DEFAULT 'CHECKING' CHECK (account_type IN ('CHECKING', 'SAVINGS', 'PAYMENT')),
+    ADD COLUMN IF NOT EXISTS holder_name TEXT,
+    ADD COLUMN IF NOT EXISTS holder_tax_id TEXT,
+    ADD COLUMN IF NOT EXISTS holder_review_required BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE bank_account_changes
+    ADD COLUMN IF NOT EXISTS account_type TEXT,
+    ADD COLUMN IF NOT EXISTS holder_name TEXT,
+    ADD COLUMN IF NOT EXISTS holder_tax_id TEXT;
+
+ALTER TABLE people
+    ADD COLUMN IF NOT EXISTS anonymized_at TIMESTAMPTZ;
+
+ALTER TABLE audit_logs
+    ADD COLUMN IF NOT EXISTS chain_sequence BIGINT,
+    ADD COLUMN IF NOT EXISTS previous_hash TEXT,
+    ADD COLUMN IF NOT EXISTS entry_hash TEXT;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'audit_logs'::regclass AND conname = 'audit_logs_chain_check') THEN
+        ALTER TABLE audit_logs ADD CONSTRAINT audit_logs_chain_check CHECK ((chain_sequence IS NULL) = (entry_hash IS NULL) AND (chain_sequence IS NULL) = (previous_hash IS NULL));
+    END IF;
+END $$;
+
+-- Rows written before organizations existed belong to the default organization.
+INSERT INTO organizations (id, slug, name)
+VALUES ('01a13a20-4e6a-7000-8000-000000000001', 'default', 'Default')
+ON CONFLICT DO NOTHING;
+DO $$
+DECLARE
+    scoped_table TEXT;
+BEGIN
+    FOREACH scoped_table IN ARRAY ARRAY[
+        'people', 'addresses', 'clinics', 'clinic_registry_records', 'dentists', 'clinic_dentists',
+        'clinic_operating_hours', 'clinic_holidays', 'clinic_settings', 'clinic_directory_listings',
+        'specialties', 'dentist_specialties', 'dentist_documents', 'bank_accounts', 'users',
+        'clinic_onboarding_transitions', 'clinic_notes', 'clinic_note_mentions', 'bank_account_changes',
+        'clinic_financial_holds', 'payout_batches', 'payout_batch_items', 'clinic_payables',
+        'ledger_transactions', 'ledger_entries', 'clinic_revisions', 'pending_deletions',
+        'audit_logs', 'audit_chain_head', 'audit_export_checkpoints'
+    ] LOOP
+        EXECUTE format('ALTER TABLE %I ADD COLUMN IF NOT EXISTS organization_id UUID', scoped_table);
+        EXECUTE format('UPDATE %I SET organization_id = %L WHERE organization_id IS NULL', scoped_table, '01a13a20-4e6a-7000-8000-000000000001');
+        EXECUTE format('ALTER TABLE %I ALTER COLUMN organization_id SET NOT NULL', scoped_table);
+        IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = scoped_table::regclass AND conname = scoped_table || '_organization_id_fkey') THEN
+            EXECUTE format(
+                'ALTER TABLE %I ADD CONSTRAINT %I FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE RESTRICT',
+                scoped_table, scoped_table || '_organization_id_fkey'
+            );
+        END IF;
+    END LOOP;
+
+    -- The chain head was a singleton row and checkpoints were keyed by sink alone.
+    IF EXISTS (SELECT 1 FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = 'audit_chain_head' AND column_name = 'singleton') THEN
+        ALTER TABLE audit_chain_head DROP COLUMN singleton;
+        ALTER TABLE audit_chain_head ADD PRIMARY KEY (organization_id);
+    END IF;
+    IF EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'audit_export_checkpoints'::regclass AND contype = 'p' AND array_length(conkey, 1) = 1) THEN
+        ALTER TABLE audit_export_checkpoints DROP CONSTRAINT audit_export_checkpoints_pkey;
+        ALTER TABLE audit_export_checkpoints ADD PRIMARY KEY (organization_id, sink);
+    END IF;
+END $$;
+-- These indexes kept their names when they became organization-scoped, so the global versions are dropped and recreated below.
+DO $$
+DECLARE
+    scoped_index TEXT;
+BEGIN
+    FOREACH scoped_index IN ARRAY ARRAY[
+        'idx_people_tax_id_number_active_unique', 'idx_dentists_cro_active_unique', 'idx_specialties_code_active_unique',
+        'idx_audit_logs_chain_sequence', 'idx_audit_logs_created_at', 'idx_audit_logs_unsealed'
+    ] LOOP
+        IF EXISTS (
+            SELECT 1 FROM pg_indexes
+            WHERE schemaname = current_schema() AND indexname = scoped_index AND indexdef NOT LIKE '%organization_id%'
+        ) THEN
+            EXECUTE format('DROP INDEX %I', scoped_index);
+        END IF;
+    END LOOP;
+END $$;
+
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS max_clinics INTEGER CHECK (max_clinics > 0),
+    ADD COLUMN IF NOT EXISTS max_dentists INTEGER CHECK (max_dentists > 0),
+    ADD COLUMN IF NOT EXISTS max_requests_per_minute INTEGER CHECK (max_requests_per_minute > 0),
+    ADD COLUMN IF NOT EXISTS max_storage_mb INTEGER CHECK (max_storage_mb > 0);
+ALTER TABLE dentists
+    ADD COLUMN IF NOT EXISTS photo_size_bytes BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'ACTIVE' CHECK (status IN ('TRIAL', 'ACTIVE', 'SUSPENDED', 'CLOSED')),
+    ADD COLUMN IF NOT EXISTS trial_ends_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS closed_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS export_key TEXT,
+    ADD COLUMN IF NOT EXISTS purge_after TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS purged_at TIMESTAMPTZ;
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'organizations'::regclass AND conname = 'organizations_trial_check') THEN
+        ALTER TABLE organizations ADD CONSTRAINT organizations_trial_check CHECK ((status = 'TRIAL') = (trial_ends_at IS NOT NULL));
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'organizations'::regclass AND conname = 'organizations_closed_check') THEN
+        ALTER TABLE organizations ADD CONSTRAINT organizations_closed_check CHECK ((status = 'CLOSED') = (closed_at IS NOT NULL));
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'organizations'::regclass AND conname = 'organizations_purge_check') THEN
+        ALTER TABLE organizations ADD CONSTRAINT organizations_purge_check CHECK (purge_after IS NULL OR closed_at IS NOT NULL);
+    END IF;
+END $$;
+
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS isolation TEXT NOT NULL DEFAULT 'SHARED' CHECK (isolation IN ('SHARED', 'SCHEMA')),
+    ADD COLUMN IF NOT EXISTS schema_name TEXT CHECK (schema_name IS NULL OR schema_name ~ '^tenant_[0-9a-f]{32}$');
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conrelid = 'organizations'::regclass AND conname = 'organizations_isolation_schema_check') THEN
+        ALTER TABLE organizations ADD CONSTRAINT organizations_isolation_schema_check CHECK ((isolation = 'SCHEMA') = (schema_name IS NOT NULL));
+    END IF;
+END $$;
+
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS plan TEXT NOT NULL DEFAULT 'ENTERPRISE' CHECK (plan IN ('BASIC', 'PRO', 'ENTERPRISE'));
+
+ALTER TABLE organizations
+    ADD COLUMN IF NOT EXISTS data_region TEXT CHECK (data_region IS NULL OR data_region ~ '^[a-z]{2}(-[a-z0-9]+)*$');
+
+ALTER TABLE notifications
+    ADD COLUMN IF NOT EXISTS clinic_id UUID,
+    ADD COLUMN IF NOT EXISTS delivery_status TEXT CHECK (delivery_status IN ('DELIVERED', 'BOUNCED', 'FAILED')),
+    ADD COLUMN IF NOT EXISTS bounce_type TEXT CHECK (bounce_type IN ('HARD', 'SOFT')),
+    ADD COLUMN IF NOT EXISTS delivery_detail TEXT,
+    ADD COLUMN IF NOT EXISTS delivery_updated_at TIMESTAMPTZ;
+
+ALTER TABLE dentists
+    ADD COLUMN IF NOT EXISTS photo_thumbnail_key TEXT,
+    ADD COLUMN IF NOT EXISTS photo_thumbnail_updated_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_slug_unique ON organizations(slug);
+CREATE INDEX IF NOT EXISTS idx_usage_records_organization_recorded_at ON usage_records(organization_id, recorded_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notifications_dedupe_key_unique ON notifications(organization_id, dedupe_key);
+CREATE INDEX IF NOT EXISTS idx_notifications_pending ON notifications(organization_id, next_attempt_at) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_notifications_organization_created_at ON notifications(organization_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_notifications_clinic_created_at ON notifications(organization_id, clinic_id, created_at) WHERE clinic_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_notification_suppressions_recipient_unique ON notification_suppressions(organization_id, channel, recipient);
+CREATE INDEX IF NOT EXISTS idx_notification_suppressions_organization_created_at ON notification_suppressions(organization_id, created_at, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_organizations_schema_name_unique ON organizations(schema_name);
+CREATE INDEX IF NOT EXISTS idx_clinics_organization_id ON clinics(organization_id, id);
+CREATE INDEX IF NOT EXISTS idx_clinics_organization_created_at ON clinics(organization_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_payout_batches_organization_created_at ON payout_batches(organization_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_dentists_organization_id ON dentists(organization_id, id);
+CREATE INDEX IF NOT EXISTS idx_users_organization_id ON users(organization_id);
+
 CREATE UNIQUE INDEX IF NOT EXISTS idx_clinic_dentists_active_unique
 ON clinic_dentists(clinic_id, dentist_id)
 WHERE ended_at IS NULL;
 
 CREATE INDEX IF NOT EXISTS idx_people_tax_id_number ON people(tax_id_number);
 CREATE UNIQUE INDEX IF NOT EXISTS idx_people_tax_id_number_active_unique
-ON people(tax_id_number)
+ON people(organization_id, tax_id_number)
 WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_people_deleted_at ON people(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_people_legal_name_trgm ON people USING GIN (legal_name gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS idx_addresses_cep ON addresses(cep);
 CREATE INDEX IF NOT EXISTS idx_dentists_person_id ON dentists(person_id);
 CREATE UNIQUE INDEX IF NOT EXISTS idx_dentists_person_id_active_unique
 ON dentists(person_id)
 WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_dentists_deleted_at ON dentists(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dentists_cro_active_unique
+ON dentists(organization_id, cro_state, cro_number)
+WHERE deleted_at IS NULL AND cro_number IS NOT NULL;
 CREATE INDEX IF NOT EXISTS idx_clinics_person_id ON clinics(person_id);
 CREATE UNIQUE INDEX IF NOT EXISTS idx_clinics_person_id_active_unique
 ON clinics(person_id)
 WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_clinics_deleted_at ON clinics(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_clinics_onboarding_status
+ON clinics(onboarding_status, onboarding_status_changed_at)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_clinic_onboarding_transitions_clinic_id
+ON clinic_onboarding_transitions(clinic_id, created_at);
+DROP INDEX IF EXISTS idx_clinic_notes_clinic_id;
+CREATE INDEX IF NOT EXISTS idx_clinic_notes_clinic_created_at
+ON clinic_notes(clinic_id, created_at, id)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_clinic_note_mentions_entity
+ON clinic_note_mentions(entity_type, entity_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_inventory_items_clinic_name_unique
+ON inventory_items(clinic_id, lower(name))
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_items_clinic_created_at
+ON inventory_items(clinic_id, created_at, id)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_item_created_at
+ON inventory_movements(item_id, created_at, id);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_suppliers_person_active_unique
+ON suppliers(person_id)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_suppliers_organization_created_at
+ON suppliers(organization_id, created_at, id)
+WHERE deleted_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_clinic_created_at
+ON purchase_orders(clinic_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_purchase_orders_supplier
+ON purchase_orders(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_items_order
+ON purchase_order_items(purchase_order_id);
+CREATE INDEX IF NOT EXISTS idx_purchase_order_receipts_order_created_at
+ON purchase_order_receipts(purchase_order_id, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_equipment_clinic_created_at
+ON equipment(clinic_id, created_at, id)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_equipment_next_maintenance_due
+ON equipment(organization_id, next_maintenance_due)
+WHERE deleted_at IS NULL AND next_maintenance_due IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_equipment_maintenance_records_equipment
+ON equipment_maintenance_records(equipment_id, performed_at, id);
+CREATE INDEX IF NOT EXISTS idx_clinic_shifts_clinic_starts_at
+ON clinic_shifts(clinic_id, starts_at)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_clinic_shifts_dentist_starts_at
+ON clinic_shifts(dentist_id, starts_at)
+WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_time_clock_entries_open_unique
+ON time_clock_entries(dentist_id)
+WHERE clock_out_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_time_clock_entries_clinic_clock_in
+ON time_clock_entries(clinic_id, clock_in_at);
+CREATE INDEX IF NOT EXISTS idx_clinic_tasks_clinic_due_date
+ON clinic_tasks(clinic_id, due_date)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_clinic_tasks_assignee_due_date
+ON clinic_tasks(assignee_user_id, due_date)
+WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clinic_tasks_automation_key
+ON clinic_tasks(organization_id, automation_key)
+WHERE automation_key IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_document_templates_name_active_unique
+ON document_templates(organization_id, lower(name))
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_generated_documents_clinic_created_at
+ON generated_documents(clinic_id, created_at, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_legal_holds_active_unique
+ON legal_holds(resource_type, resource_id)
+WHERE released_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_document_signature_requests_reference
+ON document_signature_requests(reference);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_document_signature_requests_pending_unique
+ON document_signature_requests(document_id)
+WHERE status = 'PENDING';
+CREATE UNIQUE INDEX IF NOT EXISTS idx_attachment_scans_pending_unique
+ON attachment_scans(resource_type, resource_id)
+WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_attachment_scans_due
+ON attachment_scans(organization_id, next_attempt_at)
+WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_attachment_scans_resource_created_at
+ON attachment_scans(resource_type, resource_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_reports_due
+ON reports(organization_id, next_attempt_at)
+WHERE status IN ('PENDING', 'RUNNING');
+CREATE INDEX IF NOT EXISTS idx_reports_expires_at
+ON reports(organization_id, expires_at)
+WHERE expires_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_reports_created_at
+ON reports(organization_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_reports_clinic_id
+ON reports(clinic_id);
+CREATE INDEX IF NOT EXISTS idx_report_definitions_clinic_id
+ON report_definitions(clinic_id, lower(name), id)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_report_definitions_due
+ON report_definitions(organization_id, next_run_at)
+WHERE deleted_at IS NULL AND next_run_at IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_clinic_announcements_clinic_created_at
+ON clinic_announcements(clinic_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_clinic_announcement_recipients_dentist
+ON clinic_announcement_recipients(dentist_id, announcement_id);
+CREATE INDEX IF NOT EXISTS idx_bank_account_changes_clinic_id
+ON bank_account_changes(clinic_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_transactions_clinic_occurred_on
+ON ledger_transactions(clinic_id, occurred_on, id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ledger_transactions_reference_unique
+ON ledger_transactions(clinic_id, kind, reference_type, reference_id)
+WHERE reference_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clinic_financial_holds_active_unique
+ON clinic_financial_holds(clinic_id, reason_code)
+WHERE lifted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_clinic_payables_open
+ON clinic_payables(occurred_on, clinic_id)
+WHERE payout_batch_id IS NULL;
+CREATE INDEX IF NOT EXISTS idx_clinic_payables_clinic_id
+ON clinic_payables(clinic_id, occurred_on);
+CREATE INDEX IF NOT EXISTS idx_clinic_payables_payout_batch_id
+ON clinic_payables(payout_batch_id)
+WHERE payout_batch_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clinic_payables_external_reference_unique
+ON clinic_payables(clinic_id, external_reference)
+WHERE external_reference IS NOT NULL;
+DROP INDEX IF EXISTS idx_clinic_revisions_clinic_id;
+CREATE INDEX IF NOT EXISTS idx_clinic_revisions_clinic_changed_at
+ON clinic_revisions(clinic_id, changed_at, id);
+CREATE INDEX IF NOT EXISTS idx_pending_deletions_cascade_after
+ON pending_deletions(cascade_after);
+DROP INDEX IF EXISTS idx_audit_logs_clinic_id;
+CREATE INDEX IF NOT EXISTS idx_audit_logs_clinic_created_at
+ON audit_logs(clinic_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_entity
+ON audit_logs(entity_type, entity_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at
+ON audit_logs(organization_id, created_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_chain_sequence
+ON audit_logs(organization_id, chain_sequence)
+WHERE chain_sequence IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_audit_logs_unsealed
+ON audit_logs(organization_id, created_at, id)
+WHERE chain_sequence IS NULL;
+CREATE INDEX IF NOT EXISTS idx_clinics_parent_clinic_id
+ON clinics(parent_clinic_id)
+WHERE deleted_at IS NULL AND parent_clinic_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_clinic_directory_listings_listed
+ON clinic_directory_listings(clinic_id)
+WHERE listed;
 CREATE INDEX IF NOT EXISTS idx_clinic_dentists_dentist_id ON clinic_dentists(dentist_id);
 CREATE INDEX IF NOT EXISTS idx_clinic_dentists_active ON clinic_dentists(clinic_id, dentist_id, ended_at);
+CREATE INDEX IF NOT EXISTS idx_clinic_dentists_planned_end_at
+ON clinic_dentists(planned_end_at)
+WHERE ended_at IS NULL AND planned_end_at IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_clinic_holidays_date_active_unique
+ON clinic_holidays(clinic_id, holiday_date)
+WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_specialties_code_active_unique
+ON specialties(organization_id, code)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_dentist_specialties_specialty_id ON dentist_specialties(specialty_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_dentist_documents_type_active_unique
+ON dentist_documents(dentist_id, document_type)
+WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_dentist_documents_expires_at
+ON dentist_documents(expires_at)
+WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_bank_accounts_clinic_id ON bank_accounts(clinic_id);
 CREATE INDEX IF NOT EXISTS idx_bank_accounts_deleted_at ON bank_accounts(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_accounts_primary_unique
+ON bank_accounts(clinic_id)
+WHERE is_primary AND deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_accounts_verification_reference_unique
+ON bank_accounts(verification_reference)
+WHERE verification_reference IS NOT NULL;
 CREATE UNIQUE INDEX IF NOT EXISTS idx_users_email_active_unique
 ON users(lower(email))
 WHERE deleted_at IS NULL;
 CREATE INDEX IF NOT EXISTS idx_users_deleted_at ON users(deleted_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_users_dentist_id_active_unique
+ON users(dentist_id)
+WHERE deleted_at IS NULL AND dentist_id IS NOT NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_platform_operators_email_unique
+ON platform_operators(lower(email));
+
+INSERT INTO audit_chain_head (organization_id, last_sequence, last_hash)
+VALUES ('01a13a20-4e6a-7000-8000-000000000001', 0, '0000000000000000000000000000000000000000000000000000000000000000')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO specialties (id, organization_id, code, name) VALUES
+    ('01a13a20-4e6a-73a8-8c3c-ccc8af595704', '01a13a20-4e6a-7000-8000-000000000001', 'general_dentistry', 'Clínica Geral'),
+    ('01a13a20-4e6a-73f8-92bc-f782ae06fadc', '01a13a20-4e6a-7000-8000-000000000001', 'orthodontics', 'Ortodontia'),
+    ('01a13a20-4e6a-73fe-9727-0a4545dd92fa', '01a13a20-4e6a-7000-8000-000000000001', 'endodontics', 'Endodontia'),
+    ('01a13a20-4e6a-7402-bb26-2cf919adf766', '01a13a20-4e6a-7000-8000-000000000001', 'oral_surgery', 'Cirurgia e Traumatologia Bucomaxilofacial'),
+    ('01a13a20-4e6a-7406-9fd8-0de03ccc18c6', '01a13a20-4e6a-7000-8000-000000000001', 'periodontics', 'Periodontia'),
+    ('01a13a20-4e6a-740a-b76b-fe5fb06a7050', '01a13a20-4e6a-7000-8000-000000000001', 'implantology', 'Implantodontia'),
+    ('01a13a20-4e6a-740f-af44-fdb6bba2fdc1', '01a13a20-4e6a-7000-8000-000000000001', 'prosthodontics', 'Prótese Dentária'),
+    ('01a13a20-4e6a-7413-a11c-3800ad5f8dd8', '01a13a20-4e6a-7000-8000-000000000001', 'pediatric_dentistry', 'Odontopediatria')
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,105 @@
+package attachments
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"capim-test/internal/service"
+)
+
+type LocalStore struct {
+	root string
+}
+
+func NewLocal(root string) (*LocalStore, error) {
+	root = strings.TrimSpace(root)
+	if root == "" {
+		return nil, errors.New("attachments root directory is required")
+	}
+	absoluteRoot, err := filepath.Abs(root)
+	if err != nil {
+		return nil, fmt.Errorf("resolve attachments root: %w", err)
+	}
+	if err := os.MkdirAll(absoluteRoot, 0o750); err != nil {
+		return nil, fmt.Errorf("create attachments root: %w", err)
+	}
+	return &LocalStore{root: absoluteRoot}, nil
+}
+
+func (s *LocalStore) PutAttachment(_ context.Context, key string, _ string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("create attachment directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("create attachment temp file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	if _, err := temp.Write(data); err != nil {
+		temp.Close()
+		return fmt.Errorf("write attachment: %w", err)
+	}
+	if err := temp.Close(); err != nil {
+		return fmt.Errorf("close attachment: %w", err)
+	}
+	if err := os.Rename(temp.Name(), path); err != nil {
+		return fmt.Errorf("move attachment into place: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) GetAttachment(_ context.Context, key string) (service.Attachment, bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return service.Attachment{}, false, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return service.Attachment{}, false, nil
+		}
+		return service.Attachment{}, false, fmt.Errorf("stat attachment: %w", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return service.Attachment{}, false, fmt.Errorf("read attachment: %w", err)
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(path))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return service.Attachment{ContentType: contentType, Data: data, UpdatedAt: info.ModTime().UTC()}, true, nil
+}
+
+func (s *LocalStore) DeleteAttachment(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("delete attachment: %w", err)
+	}
+	return nil
+}
+
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned := filepath.Clean(filepath.FromSlash(strings.TrimSpace(key)))
+	if cleaned == "." || filepath.IsAbs(cleaned) || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) || cleaned == ".." {
+		return "", fmt.Errorf("invalid attachment key %q", key)
+	}
+	return filepath.Join(s.root, cleaned), nil
+}
@@ -0,0 +1,89 @@
+package auditexport
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const (
+	headerBatchID        = "X-Audit-Batch-ID"
+	headerOrganizationID = "X-Audit-Organization-ID"
+	headerBatchSize      = "X-Audit-Batch-Size"
+	headerSignature      = "X-Audit-Signature"
+	ndjsonContentType    = "application/x-ndjson"
+)
+
+type WebhookSink struct {
+	endpoint   string
+	secret     []byte
+	httpClient *http.Client
+}
+
+func NewWebhook(endpoint string, secret string, timeout time.Duration) *WebhookSink {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &WebhookSink{
+		endpoint:   strings.TrimSpace(endpoint),
+		secret:     []byte(strings.TrimSpace(secret)),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (w *WebhookSink) Name() string {
+	return "webhook"
+}
+
+func (w *WebhookSink) ExportAuditLogs(ctx context.Context, batch service.AuditExportBatch) error {
+	body, err := encodeNDJSON(batch.Records)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build audit export request: %w", err)
+	}
+	req.Header.Set("Content-Type", ndjsonContentType)
+	req.Header.Set(headerBatchID, batch.ID)
+	req.Header.Set(headerOrganizationID, batch.OrganizationID)
+	req.Header.Set(headerBatchSize, strconv.Itoa(len(batch.Records)))
+	if len(w.secret) > 0 {
+		mac := hmac.New(sha256.New, w.secret)
+		mac.Write(body)
+		req.Header.Set(headerSignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("call audit export webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("audit export webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func encodeNDJSON(records []service.AuditLogRecord) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, record := range records {
+		if err := encoder.Encode(record); err != nil {
+			return nil, fmt.Errorf("encode audit log %s: %w", record.ID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+package bankverification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+type Client struct {
+	provider    string
+	endpoint    string
+	callbackURL string
+	httpClient  *http.Client
+}
+
+type request struct {
+	BankAccountID string `json:"bank_account_id"`
+	ClinicID      string `json:"clinic_id"`
+	HolderTaxID   string `json:"holder_tax_id"`
+	BankCode      string `json:"bank_code"`
+	BranchNumber  string `json:"branch_number"`
+	AccountNumber string `json:"account_number"`
+	CallbackURL   string `json:"callback_url,omitempty"`
+}
+
+type response struct {
+	Reference string `json:"reference"`
+}
+
+func New(provider string, endpoint string, callbackURL string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Client{
+		provider:    strings.TrimSpace(provider),
+		endpoint:    strings.TrimSpace(endpoint),
+		callbackURL: strings.TrimSpace(callbackURL),
+		httpClient:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) StartVerification(ctx context.Context, verification service.BankAccountVerificationRequest) (service.BankAccountVerificationStart, error) {
+	body, err := json.Marshal(request{
+		BankAccountID: verification.BankAccountID,
+		ClinicID:      verification.ClinicID,
+		HolderTaxID:   verification.HolderTaxID,
+		BankCode:      verification.BankCode,
+		BranchNumber:  verification.BranchNumber,
+		AccountNumber: verification.AccountNumber,
+		CallbackURL:   c.callbackURL,
+	})
+	if err != nil {
+		return service.BankAccountVerificationStart{}, fmt.Errorf("encode verification request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return service.BankAccountVerificationStart{}, fmt.Errorf("build verification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return service.BankAccountVerificationStart{}, fmt.Errorf("call verification provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return service.BankAccountVerificationStart{}, fmt.Errorf("verification provider returned status %d", resp.StatusCode)
+	}
+
+	var payload response
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return service.BankAccountVerificationStart{}, fmt.Errorf("decode verification response: %w", err)
+	}
+	return service.BankAccountVerificationStart{Provider: c.provider, Reference: payload.Reference}, nil
+}
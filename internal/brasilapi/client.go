@@ -0,0 +1,89 @@
+package brasilapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const (
+	defaultBaseURL = "https://brasilapi.com.br/api/cnpj/v1"
+	sourceName     = "brasilapi"
+)
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type response struct {
+	RazaoSocial                string      `json:"razao_social"`
+	NomeFantasia               string      `json:"nome_fantasia"`
+	CNAEFiscal                 json.Number `json:"cnae_fiscal"`
+	CNAEFiscalDescricao        string      `json:"cnae_fiscal_descricao"`
+	DescricaoSituacaoCadastral string      `json:"descricao_situacao_cadastral"`
+}
+
+func New(baseURL string, timeout time.Duration) *Client {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = defaultBaseURL
+	}
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) LookupCNPJ(ctx context.Context, cnpj string) (service.CompanyRegistryResult, bool, error) {
+	endpoint := fmt.Sprintf("%s/%s", c.baseURL, url.PathEscape(cnpj))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return service.CompanyRegistryResult{}, false, fmt.Errorf("build brasilapi request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return service.CompanyRegistryResult{}, false, fmt.Errorf("call brasilapi: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return service.CompanyRegistryResult{}, false, nil
+	case resp.StatusCode != http.StatusOK:
+		return service.CompanyRegistryResult{}, false, fmt.Errorf("brasilapi returned status %d", resp.StatusCode)
+	}
+
+	var payload response
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return service.CompanyRegistryResult{}, false, fmt.Errorf("decode brasilapi response: %w", err)
+	}
+
+	return service.CompanyRegistryResult{
+		LegalName:          payload.RazaoSocial,
+		TradeName:          payload.NomeFantasia,
+		CNAECode:           formatCNAE(payload.CNAEFiscal.String()),
+		CNAEDescription:    payload.CNAEFiscalDescricao,
+		RegistrationStatus: payload.DescricaoSituacaoCadastral,
+		Source:             sourceName,
+	}, true, nil
+}
+
+func formatCNAE(code string) string {
+	if _, err := strconv.Atoi(code); err != nil || len(code) > 7 {
+		return code
+	}
+	code = strings.Repeat("0", 7-len(code)) + code
+	return fmt.Sprintf("%s-%s/%s", code[:4], code[4:5], code[5:])
+}
@@ -0,0 +1,86 @@
+package clamav
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const chunkSize = 64 << 10
+
+// Client talks to clamd over its INSTREAM command, so the daemon never needs access to the attachment storage.
+type Client struct {
+	address string
+	timeout time.Duration
+	dialer  net.Dialer
+}
+
+func New(address string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &Client{address: strings.TrimSpace(address), timeout: timeout}
+}
+
+func (c *Client) ScanAttachment(ctx context.Context, data []byte) (service.AttachmentScanResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	conn, err := c.dialer.DialContext(ctx, "tcp", c.address)
+	if err != nil {
+		return service.AttachmentScanResult{}, fmt.Errorf("connect to clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return service.AttachmentScanResult{}, fmt.Errorf("set clamd deadline: %w", err)
+		}
+	}
+
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return service.AttachmentScanResult{}, fmt.Errorf("send clamd command: %w", err)
+	}
+	var size [4]byte
+	for offset := 0; offset < len(data); offset += chunkSize {
+		chunk := data[offset:min(offset+chunkSize, len(data))]
+		binary.BigEndian.PutUint32(size[:], uint32(len(chunk)))
+		if _, err := conn.Write(size[:]); err != nil {
+			return service.AttachmentScanResult{}, fmt.Errorf("stream to clamd: %w", err)
+		}
+		if _, err := conn.Write(chunk); err != nil {
+			return service.AttachmentScanResult{}, fmt.Errorf("stream to clamd: %w", err)
+		}
+	}
+	binary.BigEndian.PutUint32(size[:], 0)
+	if _, err := conn.Write(size[:]); err != nil {
+		return service.AttachmentScanResult{}, fmt.Errorf("stream to clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil {
+		return service.AttachmentScanResult{}, fmt.Errorf("read clamd reply: %w", err)
+	}
+	return parseReply(strings.TrimRight(reply, "\x00"))
+}
+
+// parseReply reads "stream: OK" or "stream: <signature> FOUND"; anything else is an error from clamd.
+func parseReply(reply string) (service.AttachmentScanResult, error) {
+	result, ok := strings.CutPrefix(reply, "stream: ")
+	if !ok {
+		return service.AttachmentScanResult{}, fmt.Errorf("unexpected clamd reply %q", reply)
+	}
+	switch {
+	case result == "OK":
+		return service.AttachmentScanResult{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return service.AttachmentScanResult{Infected: true, Signature: strings.TrimSuffix(result, " FOUND")}, nil
+	default:
+		return service.AttachmentScanResult{}, fmt.Errorf("clamd returned %q", result)
+	}
+}
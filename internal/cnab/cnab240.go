@@ -0,0 +1,279 @@
+package cnab
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"capim-test/internal/validation"
+)
+
+const (
+	lineWidth     = 240
+	lineSeparator = "\r\n"
+
+	layoutVersion      = "089"
+	batchLayoutVersion = "045"
+	recordDensity      = "01600"
+	serviceSuppliers   = "20"
+	methodTED          = "03"
+	clearingTED        = "018"
+	purposeTED         = "00005"
+)
+
+type Account struct {
+	BankCode      string
+	BranchNumber  string
+	AccountNumber string
+}
+
+type Payer struct {
+	Name        string
+	TaxID       string
+	BankName    string
+	AgreementID string
+	Account     Account
+}
+
+type Payment struct {
+	Reference   string
+	HolderName  string
+	HolderTaxID string
+	Account     Account
+	AmountCents int64
+}
+
+type File struct {
+	Sequence    int64
+	GeneratedAt time.Time
+	PaymentDate time.Time
+	Payer       Payer
+	Payments    []Payment
+}
+
+// Encode240 writes a FEBRABAN CNAB240 remittance with a single batch of credit transfers (segments A and B).
+func Encode240(file File) ([]byte, error) {
+	if len(file.Payments) == 0 {
+		return nil, fmt.Errorf("cnab240: at least one payment is required")
+	}
+
+	var lines []string
+	lines = append(lines, fileHeader(file))
+	lines = append(lines, batchHeader(file.Payer))
+	var total int64
+	for idx, payment := range file.Payments {
+		if payment.AmountCents <= 0 {
+			return nil, fmt.Errorf("cnab240: payment %d must have a positive amount", idx)
+		}
+		total += payment.AmountCents
+		lines = append(lines, segmentA(file, payment, 2*idx+1))
+		lines = append(lines, segmentB(file.Payer, payment, 2*idx+2))
+	}
+	lines = append(lines, batchTrailer(file.Payer, 2*len(file.Payments)+2, total))
+	lines = append(lines, fileTrailer(file.Payer, len(lines)+1))
+
+	var buf bytes.Buffer
+	for idx, line := range lines {
+		if len(line) != lineWidth {
+			return nil, fmt.Errorf("cnab240: record %d has %d characters, expected %d", idx+1, len(line), lineWidth)
+		}
+		buf.WriteString(line)
+		buf.WriteString(lineSeparator)
+	}
+	return buf.Bytes(), nil
+}
+
+func fileHeader(file File) string {
+	payer := file.Payer
+	branch, branchDigit := splitCheckDigit(payer.Account.BranchNumber)
+	account, accountDigit := splitCheckDigit(payer.Account.AccountNumber)
+	var r record
+	r.num(payer.Account.BankCode, 3)
+	r.num("0000", 4)
+	r.num("0", 1)
+	r.blank(9)
+	r.num("2", 1)
+	r.num(payer.TaxID, 14)
+	r.alpha(payer.AgreementID, 20)
+	r.num(branch, 5)
+	r.alpha(branchDigit, 1)
+	r.num(account, 12)
+	r.alpha(accountDigit, 1)
+	r.blank(1)
+	r.alpha(payer.Name, 30)
+	r.alpha(payer.BankName, 30)
+	r.blank(10)
+	r.num("1", 1)
+	r.num(file.GeneratedAt.Format("02012006"), 8)
+	r.num(file.GeneratedAt.Format("150405"), 6)
+	r.num(strconv.FormatInt(file.Sequence, 10), 6)
+	r.num(layoutVersion, 3)
+	r.num(recordDensity, 5)
+	r.blank(69)
+	return r.String()
+}
+
+func batchHeader(payer Payer) string {
+	branch, branchDigit := splitCheckDigit(payer.Account.BranchNumber)
+	account, accountDigit := splitCheckDigit(payer.Account.AccountNumber)
+	var r record
+	r.num(payer.Account.BankCode, 3)
+	r.num("1", 4)
+	r.num("1", 1)
+	r.alpha("C", 1)
+	r.num(serviceSuppliers, 2)
+	r.num(methodTED, 2)
+	r.num(batchLayoutVersion, 3)
+	r.blank(1)
+	r.num("2", 1)
+	r.num(payer.TaxID, 14)
+	r.alpha(payer.AgreementID, 20)
+	r.num(branch, 5)
+	r.alpha(branchDigit, 1)
+	r.num(account, 12)
+	r.alpha(accountDigit, 1)
+	r.blank(1)
+	r.alpha(payer.Name, 30)
+	// Message and payer address are optional for credit transfers.
+	r.blank(40 + 30 + 5 + 15 + 20 + 5 + 3 + 2 + 8)
+	r.blank(10)
+	return r.String()
+}
+
+func segmentA(file File, payment Payment, sequence int) string {
+	branch, branchDigit := splitCheckDigit(payment.Account.BranchNumber)
+	account, accountDigit := splitCheckDigit(payment.Account.AccountNumber)
+	var r record
+	r.num(file.Payer.Account.BankCode, 3)
+	r.num("1", 4)
+	r.num("3", 1)
+	r.num(strconv.Itoa(sequence), 5)
+	r.alpha("A", 1)
+	r.num("0", 1)
+	r.num("00", 2)
+	r.num(clearingTED, 3)
+	r.num(payment.Account.BankCode, 3)
+	r.num(branch, 5)
+	r.alpha(branchDigit, 1)
+	r.num(account, 12)
+	r.alpha(accountDigit, 1)
+	r.blank(1)
+	r.alpha(payment.HolderName, 30)
+	r.alpha(payment.Reference, 20)
+	r.num(file.PaymentDate.Format("02012006"), 8)
+	r.alpha("BRL", 3)
+	r.num("0", 15)
+	r.num(strconv.FormatInt(payment.AmountCents, 10), 15)
+	r.blank(20)
+	r.num("0", 8)
+	r.num("0", 15)
+	r.blank(40)
+	r.blank(2)
+	r.num(purposeTED, 5)
+	r.blank(2)
+	r.blank(3)
+	r.num("0", 1)
+	r.blank(10)
+	return r.String()
+}
+
+func segmentB(payer Payer, payment Payment, sequence int) string {
+	taxID := validation.NormalizeCNPJ(payment.HolderTaxID)
+	registrationType := "2"
+	if len(taxID) == 11 {
+		registrationType = "1"
+	}
+	var r record
+	r.num(payer.Account.BankCode, 3)
+	r.num("1", 4)
+	r.num("3", 1)
+	r.num(strconv.Itoa(sequence), 5)
+	r.alpha("B", 1)
+	r.blank(3)
+	r.num(registrationType, 1)
+	r.alpha(leftPad(taxID, 14), 14)
+	// Payee address and document fields are not required for TED credits.
+	r.blank(208)
+	return r.String()
+}
+
+func batchTrailer(payer Payer, records int, totalCents int64) string {
+	var r record
+	r.num(payer.Account.BankCode, 3)
+	r.num("1", 4)
+	r.num("5", 1)
+	r.blank(9)
+	r.num(strconv.Itoa(records), 6)
+	r.num(strconv.FormatInt(totalCents, 10), 18)
+	r.num("0", 18)
+	r.num("0", 6)
+	r.blank(165)
+	r.blank(10)
+	return r.String()
+}
+
+func fileTrailer(payer Payer, records int) string {
+	var r record
+	r.num(payer.Account.BankCode, 3)
+	r.num("9999", 4)
+	r.num("9", 1)
+	r.blank(9)
+	r.num("1", 6)
+	r.num(strconv.Itoa(records), 6)
+	r.num("0", 6)
+	r.blank(205)
+	return r.String()
+}
+
+// Branch and account numbers are stored as typed by the clinic, usually with the check digit after a dash.
+func splitCheckDigit(value string) (string, string) {
+	value = strings.TrimSpace(value)
+	if idx := strings.LastIndex(value, "-"); idx >= 0 {
+		return digitsOnly(value[:idx]), strings.ToUpper(strings.TrimSpace(value[idx+1:]))
+	}
+	return digitsOnly(value), ""
+}
+
+func digitsOnly(value string) string {
+	return validation.NormalizeCPF(value)
+}
+
+func leftPad(value string, width int) string {
+	if len(value) >= width {
+		return value
+	}
+	return strings.Repeat("0", width-len(value)) + value
+}
+
+type record struct {
+	strings.Builder
+}
+
+func (r *record) num(value string, width int) {
+	value = digitsOnly(value)
+	if len(value) > width {
+		value = value[len(value)-width:]
+	}
+	r.WriteString(leftPad(value, width))
+}
+
+func (r *record) alpha(value string, width int) {
+	value = strings.Map(func(char rune) rune {
+		if char > unicode.MaxASCII {
+			return -1
+		}
+		return char
+	}, validation.NormalizeCompanyName(value))
+	if len(value) > width {
+		value = value[:width]
+	}
+	r.WriteString(value)
+	r.WriteString(strings.Repeat(" ", width-len(value)))
+}
+
+func (r *record) blank(width int) {
+	r.WriteString(strings.Repeat(" ", width))
+}
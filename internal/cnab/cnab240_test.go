@@ -0,0 +1,59 @@
+package cnab
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode240ProducesFixedWidthRecords(t *testing.T) {
+	file := File{
+		Sequence:    7,
+		GeneratedAt: time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC),
+		PaymentDate: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC),
+		Payer: Payer{
+			Name:        "Plataforma Pagadora LTDA",
+			TaxID:       "11.222.333/0001-81",
+			BankName:    "Banco do Brasil",
+			AgreementID: "123456",
+			Account:     Account{BankCode: "001", BranchNumber: "1234-5", AccountNumber: "998877-1"},
+		},
+		Payments: []Payment{
+			{Reference: "batch-1", HolderName: "Clínica Sorriso", HolderTaxID: "11222333000181", Account: Account{BankCode: "341", BranchNumber: "4321", AccountNumber: "12345-6"}, AmountCents: 150075},
+			{Reference: "batch-2", HolderName: "Maria Souza", HolderTaxID: "529.982.247-25", Account: Account{BankCode: "237", BranchNumber: "0001", AccountNumber: "777"}, AmountCents: 25},
+		},
+	}
+
+	content, err := Encode240(file)
+	if err != nil {
+		t.Fatalf("Encode240: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\r\n"), "\r\n")
+	if len(lines) != 8 {
+		t.Fatalf("expected 8 records, got %d", len(lines))
+	}
+	for idx, line := range lines {
+		if len(line) != 240 {
+			t.Fatalf("record %d has %d characters", idx+1, len(line))
+		}
+	}
+	if got := lines[2][119:134]; got != "000000000150075" {
+		t.Fatalf("unexpected segment A amount %q", got)
+	}
+	if got := lines[2][43:73]; got != "CLINICA SORRISO               " {
+		t.Fatalf("unexpected payee name %q", got)
+	}
+	if got := lines[5][17:32]; got != "100052998224725" {
+		t.Fatalf("unexpected payee registration %q", got)
+	}
+	if got := lines[6][23:41]; got != "000000000000150100" {
+		t.Fatalf("unexpected batch total %q", got)
+	}
+	if got := lines[7][23:29]; got != "000008" {
+		t.Fatalf("unexpected file record count %q", got)
+	}
+
+	if _, err := Encode240(File{Payer: file.Payer}); err == nil {
+		t.Fatal("expected an empty batch to be rejected")
+	}
+}
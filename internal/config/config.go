@@ -7,15 +7,130 @@ import (
 )
 
 type Config struct {
-	Port                  string        `env:"PORT" envDefault:"8080"`
-	DatabaseURL           string        `env:"DATABASE_URL,required"`
-	OTelEnabled           bool          `env:"OTEL_ENABLED" envDefault:"true"`
-	OTelServiceName       string        `env:"OTEL_SERVICE_NAME" envDefault:"capim-test-api"`
-	JWTSecret             string        `env:"JWT_SECRET,required"`
-	JWTIssuer             string        `env:"JWT_ISSUER" envDefault:"capim-test-api"`
-	JWTAccessTokenTTL     time.Duration `env:"JWT_ACCESS_TOKEN_TTL" envDefault:"15m"`
-	BootstrapUserEmail    string        `env:"AUTH_BOOTSTRAP_EMAIL"`
-	BootstrapUserPassword string        `env:"AUTH_BOOTSTRAP_PASSWORD"`
+	Port                         string                   `env:"PORT" envDefault:"8080"`
+	DatabaseURL                  string                   `env:"DATABASE_URL,required"`
+	DatabaseMaxConns             int32                    `env:"DATABASE_MAX_CONNS" envDefault:"20"`
+	DatabaseStatementCache       int                      `env:"DATABASE_STATEMENT_CACHE_CAPACITY" envDefault:"512"`
+	DatabaseStatementTimeout     time.Duration            `env:"DATABASE_STATEMENT_TIMEOUT" envDefault:"60s"`
+	QueryTimeout                 time.Duration            `env:"QUERY_TIMEOUT" envDefault:"10s"`
+	QueryTimeoutRoutes           map[string]time.Duration `env:"QUERY_TIMEOUT_ROUTES" envKeyValSeparator:"=" envDefault:"GET /api/v1/audit-logs/export=10m,GET /api/v1/platform/organizations/:id/export=2m"`
+	ReferenceCacheSize           int                      `env:"REFERENCE_CACHE_SIZE" envDefault:"1000"`
+	ReferenceCacheTTL            time.Duration            `env:"REFERENCE_CACHE_TTL" envDefault:"5m"`
+	OTelEnabled                  bool                     `env:"OTEL_ENABLED" envDefault:"true"`
+	OTelServiceName              string                   `env:"OTEL_SERVICE_NAME" envDefault:"capim-test-api"`
+	JWTSecret                    string                   `env:"JWT_SECRET,required"`
+	JWTIssuer                    string                   `env:"JWT_ISSUER" envDefault:"capim-test-api"`
+	JWTAccessTokenTTL            time.Duration            `env:"JWT_ACCESS_TOKEN_TTL" envDefault:"15m"`
+	BootstrapUserEmail           string                   `env:"AUTH_BOOTSTRAP_EMAIL"`
+	BootstrapUserPassword        string                   `env:"AUTH_BOOTSTRAP_PASSWORD"`
+	ViaCEPEnabled                bool                     `env:"VIACEP_ENABLED" envDefault:"false"`
+	ViaCEPBaseURL                string                   `env:"VIACEP_BASE_URL" envDefault:"https://viacep.com.br/ws"`
+	ViaCEPTimeout                time.Duration            `env:"VIACEP_TIMEOUT" envDefault:"3s"`
+	CompanyRegistryEnabled       bool                     `env:"COMPANY_REGISTRY_ENABLED" envDefault:"false"`
+	CompanyRegistryBaseURL       string                   `env:"COMPANY_REGISTRY_BASE_URL" envDefault:"https://brasilapi.com.br/api/cnpj/v1"`
+	CompanyRegistryTimeout       time.Duration            `env:"COMPANY_REGISTRY_TIMEOUT" envDefault:"5s"`
+	AttachmentsDir               string                   `env:"ATTACHMENTS_DIR" envDefault:"data/attachments"`
+	AttachmentsRegion            string                   `env:"ATTACHMENTS_REGION"`
+	ClamAVAddr                   string                   `env:"CLAMAV_ADDR"`
+	ClamAVTimeout                time.Duration            `env:"CLAMAV_TIMEOUT" envDefault:"30s"`
+	AttachmentScanInterval       time.Duration            `env:"ATTACHMENT_SCAN_INTERVAL" envDefault:"15s"`
+	PhotoThumbnailInterval       time.Duration            `env:"PHOTO_THUMBNAIL_INTERVAL" envDefault:"1m"`
+	ReportInterval               time.Duration            `env:"REPORT_INTERVAL" envDefault:"15s"`
+	ReportDefinitionInterval     time.Duration            `env:"REPORT_DEFINITION_INTERVAL" envDefault:"5m"`
+	PublicBaseURL                string                   `env:"PUBLIC_BASE_URL"`
+	ValidationRules              map[string]string        `env:"VALIDATION_RULES" envKeyValSeparator:"="`
+	TaxIDBlocklist               []string                 `env:"TAX_ID_BLOCKLIST" envSeparator:","`
+	ScreeningURL                 string                   `env:"SCREENING_URL"`
+	ScreeningTimeout             time.Duration            `env:"SCREENING_TIMEOUT" envDefault:"3s"`
+	WebhookURL                   string                   `env:"WEBHOOK_URL"`
+	WebhookSecret                string                   `env:"WEBHOOK_SECRET"`
+	WebhookTimeout               time.Duration            `env:"WEBHOOK_TIMEOUT" envDefault:"5s"`
+	WebhookRegion                string                   `env:"WEBHOOK_REGION"`
+	DocumentNoticeDays           []int                    `env:"DOCUMENT_EXPIRY_NOTICE_DAYS" envSeparator:"," envDefault:"30,7"`
+	DocumentCheckInterval        time.Duration            `env:"DOCUMENT_EXPIRY_CHECK_INTERVAL" envDefault:"1h"`
+	AssignmentsInterval          time.Duration            `env:"TEMPORARY_ASSIGNMENTS_CHECK_INTERVAL" envDefault:"5m"`
+	BankVerificationURL          string                   `env:"BANK_VERIFICATION_URL"`
+	BankVerificationProvider     string                   `env:"BANK_VERIFICATION_PROVIDER" envDefault:"micro-deposit"`
+	BankVerificationSecret       string                   `env:"BANK_VERIFICATION_CALLBACK_SECRET"`
+	BankVerificationTimeout      time.Duration            `env:"BANK_VERIFICATION_TIMEOUT" envDefault:"5s"`
+	ESignatureURL                string                   `env:"ESIGNATURE_URL"`
+	ESignatureProvider           string                   `env:"ESIGNATURE_PROVIDER" envDefault:"clicksign"`
+	ESignatureSecret             string                   `env:"ESIGNATURE_CALLBACK_SECRET"`
+	ESignatureTimeout            time.Duration            `env:"ESIGNATURE_TIMEOUT" envDefault:"10s"`
+	PayoutPayerName              string                   `env:"PAYOUT_PAYER_NAME"`
+	PayoutPayerTaxID             string                   `env:"PAYOUT_PAYER_TAX_ID"`
+	PayoutPayerBankCode          string                   `env:"PAYOUT_PAYER_BANK_CODE"`
+	PayoutPayerBankName          string                   `env:"PAYOUT_PAYER_BANK_NAME"`
+	PayoutPayerBranch            string                   `env:"PAYOUT_PAYER_BRANCH"`
+	PayoutPayerAccount           string                   `env:"PAYOUT_PAYER_ACCOUNT"`
+	PayoutPayerAgreement         string                   `env:"PAYOUT_PAYER_AGREEMENT"`
+	RetentionDays                int                      `env:"RETENTION_DAYS" envDefault:"0"`
+	RetentionPurgeInterval       time.Duration            `env:"RETENTION_PURGE_INTERVAL" envDefault:"24h"`
+	DeleteConfirmationLimit      int                      `env:"DELETE_CONFIRMATION_THRESHOLD" envDefault:"20"`
+	DeletionGracePeriod          time.Duration            `env:"DELETION_GRACE_PERIOD" envDefault:"72h"`
+	DeletionWorkerInterval       time.Duration            `env:"DELETION_WORKER_INTERVAL" envDefault:"15m"`
+	IntegrityCheckInterval       time.Duration            `env:"INTEGRITY_CHECK_INTERVAL" envDefault:"24h"`
+	IntegrityAutoRepair          bool                     `env:"INTEGRITY_AUTO_REPAIR" envDefault:"false"`
+	AuditChainSealInterval       time.Duration            `env:"AUDIT_CHAIN_SEAL_INTERVAL" envDefault:"1m"`
+	AuditExportSink              string                   `env:"AUDIT_EXPORT_SINK"`
+	AuditExportInterval          time.Duration            `env:"AUDIT_EXPORT_INTERVAL" envDefault:"1m"`
+	AuditExportTimeout           time.Duration            `env:"AUDIT_EXPORT_TIMEOUT" envDefault:"10s"`
+	AuditExportWebhookURL        string                   `env:"AUDIT_EXPORT_WEBHOOK_URL"`
+	AuditExportWebhookSecret     string                   `env:"AUDIT_EXPORT_WEBHOOK_SECRET"`
+	AuditExportS3Bucket          string                   `env:"AUDIT_EXPORT_S3_BUCKET"`
+	AuditExportS3Region          string                   `env:"AUDIT_EXPORT_S3_REGION"`
+	AuditExportS3Endpoint        string                   `env:"AUDIT_EXPORT_S3_ENDPOINT"`
+	AuditExportS3Prefix          string                   `env:"AUDIT_EXPORT_S3_PREFIX" envDefault:"audit-logs"`
+	AuditExportS3AccessKey       string                   `env:"AUDIT_EXPORT_S3_ACCESS_KEY_ID"`
+	AuditExportS3SecretKey       string                   `env:"AUDIT_EXPORT_S3_SECRET_ACCESS_KEY"`
+	AuditExportS3Session         string                   `env:"AUDIT_EXPORT_S3_SESSION_TOKEN"`
+	AuditExportRegion            string                   `env:"AUDIT_EXPORT_REGION"`
+	WarehouseExportSink          string                   `env:"WAREHOUSE_EXPORT_SINK"`
+	WarehouseExportFormat        string                   `env:"WAREHOUSE_EXPORT_FORMAT" envDefault:"ndjson"`
+	WarehouseExportInterval      time.Duration            `env:"WAREHOUSE_EXPORT_INTERVAL" envDefault:"15m"`
+	WarehouseExportTimeout       time.Duration            `env:"WAREHOUSE_EXPORT_TIMEOUT" envDefault:"30s"`
+	WarehouseExportBucket        string                   `env:"WAREHOUSE_EXPORT_BUCKET"`
+	WarehouseExportBucketRegion  string                   `env:"WAREHOUSE_EXPORT_BUCKET_REGION"`
+	WarehouseExportEndpoint      string                   `env:"WAREHOUSE_EXPORT_ENDPOINT"`
+	WarehouseExportPrefix        string                   `env:"WAREHOUSE_EXPORT_PREFIX" envDefault:"warehouse"`
+	WarehouseExportAccessKey     string                   `env:"WAREHOUSE_EXPORT_ACCESS_KEY_ID"`
+	WarehouseExportSecretKey     string                   `env:"WAREHOUSE_EXPORT_SECRET_ACCESS_KEY"`
+	WarehouseExportSession       string                   `env:"WAREHOUSE_EXPORT_SESSION_TOKEN"`
+	WarehouseExportRegion        string                   `env:"WAREHOUSE_EXPORT_REGION"`
+	NotificationEmailProvider    string                   `env:"NOTIFICATION_EMAIL_PROVIDER"`
+	NotificationEmailFrom        string                   `env:"NOTIFICATION_EMAIL_FROM"`
+	NotificationSMTPAddr         string                   `env:"NOTIFICATION_SMTP_ADDR"`
+	NotificationSMTPUsername     string                   `env:"NOTIFICATION_SMTP_USERNAME"`
+	NotificationSMTPPassword     string                   `env:"NOTIFICATION_SMTP_PASSWORD"`
+	NotificationSESRegion        string                   `env:"NOTIFICATION_SES_REGION"`
+	NotificationSESEndpoint      string                   `env:"NOTIFICATION_SES_ENDPOINT"`
+	NotificationSESAccessKey     string                   `env:"NOTIFICATION_SES_ACCESS_KEY_ID"`
+	NotificationSESSecretKey     string                   `env:"NOTIFICATION_SES_SECRET_ACCESS_KEY"`
+	NotificationSESSession       string                   `env:"NOTIFICATION_SES_SESSION_TOKEN"`
+	NotificationSMSURL           string                   `env:"NOTIFICATION_SMS_URL"`
+	NotificationSMSToken         string                   `env:"NOTIFICATION_SMS_TOKEN"`
+	NotificationWhatsAppURL      string                   `env:"NOTIFICATION_WHATSAPP_URL"`
+	NotificationWhatsAppToken    string                   `env:"NOTIFICATION_WHATSAPP_TOKEN"`
+	NotificationTimeout          time.Duration            `env:"NOTIFICATION_TIMEOUT" envDefault:"10s"`
+	NotificationDeliveryInterval time.Duration            `env:"NOTIFICATION_DELIVERY_INTERVAL" envDefault:"30s"`
+	MaintenanceCheckInterval     time.Duration            `env:"EQUIPMENT_MAINTENANCE_CHECK_INTERVAL" envDefault:"1h"`
+	TaskAutomationInterval       time.Duration            `env:"TASK_AUTOMATION_INTERVAL" envDefault:"15m"`
+	NotificationCallbackSecret   string                   `env:"NOTIFICATION_CALLBACK_SECRET"`
+	PublicRateLimit              int                      `env:"PUBLIC_RATE_LIMIT" envDefault:"60"`
+	PublicRateLimitWindow        time.Duration            `env:"PUBLIC_RATE_LIMIT_WINDOW" envDefault:"1m"`
+	LoadSheddingMaxInFlight      int                      `env:"LOAD_SHEDDING_MAX_CONCURRENCY" envDefault:"200"`
+	LoadSheddingMinInFlight      int                      `env:"LOAD_SHEDDING_MIN_CONCURRENCY" envDefault:"10"`
+	LoadSheddingTargetP99        time.Duration            `env:"LOAD_SHEDDING_TARGET_P99" envDefault:"500ms"`
+	PlatformAPIKey               string                   `env:"PLATFORM_API_KEY"`
+	TenantBaseDomain             string                   `env:"TENANT_BASE_DOMAIN"`
+	FieldEncryptionKey           string                   `env:"FIELD_ENCRYPTION_MASTER_KEY"`
+	OffboardingPurgeDelay        time.Duration            `env:"OFFBOARDING_PURGE_DELAY" envDefault:"720h"`
+	OrganizationPurgeInterval    time.Duration            `env:"ORGANIZATION_PURGE_INTERVAL" envDefault:"1h"`
+	UsageMeteringInterval        time.Duration            `env:"USAGE_METERING_INTERVAL" envDefault:"5m"`
+	AdminJWTSecret               string                   `env:"ADMIN_JWT_SECRET"`
+	AdminBootstrapEmail          string                   `env:"ADMIN_BOOTSTRAP_EMAIL"`
+	AdminBootstrapPassword       string                   `env:"ADMIN_BOOTSTRAP_PASSWORD"`
+	TenantSchemasEnabled         bool                     `env:"TENANT_SCHEMAS_ENABLED" envDefault:"false"`
 }
 
 func Load() (Config, error) {
@@ -2,28 +2,75 @@ package db
 
 import (
 	"context"
-	"database/sql"
 	"fmt"
+	"strconv"
+	"time"
 
-	"github.com/XSAM/otelsql"
-	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel/attribute"
 )
 
-func OpenPostgres(ctx context.Context, databaseURL string) (*sql.DB, error) {
-	db, err := otelsql.Open(
-		"pgx",
-		databaseURL,
-		otelsql.WithAttributes(attribute.String("db.system", "postgresql")),
-	)
+const databaseTracerName = "capim-test/internal/db"
+
+type PoolConfig struct {
+	// MaxConns caps the pgxpool; zero keeps the pgx default.
+	MaxConns int32
+	// StatementCacheCapacity is how many prepared statements each connection keeps. Zero turns caching off,
+	// which is what a transaction-mode PgBouncer in front of the database needs.
+	StatementCacheCapacity int
+	// StatementTimeout is set as statement_timeout on every connection, so the server stops a statement even when the client
+	// never cancels it. Zero keeps the server default.
+	StatementTimeout time.Duration
+}
+
+// Postgres is the pgxpool the generated queries run on, traced by otelpgx.
+type Postgres struct {
+	*pgxpool.Pool
+}
+
+func OpenPostgres(ctx context.Context, databaseURL string, config PoolConfig) (*Postgres, error) {
+	poolConfig, err := newPoolConfig(databaseURL, config)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return nil, fmt.Errorf("open postgres: %w", err)
 	}
-
-	if err := db.PingContext(ctx); err != nil {
-		db.Close()
+	if err := pool.Ping(ctx); err != nil {
+		pool.Close()
 		return nil, fmt.Errorf("ping postgres: %w", err)
 	}
+	return &Postgres{Pool: pool}, nil
+}
 
-	return db, nil
+func newPoolConfig(databaseURL string, config PoolConfig) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("parse database url: %w", err)
+	}
+	if config.MaxConns > 0 {
+		poolConfig.MaxConns = config.MaxConns
+	}
+	poolConfig.ConnConfig.StatementCacheCapacity = config.StatementCacheCapacity
+	poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeCacheStatement
+	if config.StatementCacheCapacity <= 0 {
+		poolConfig.ConnConfig.StatementCacheCapacity = 0
+		poolConfig.ConnConfig.DefaultQueryExecMode = pgx.QueryExecModeExec
+	}
+	if config.StatementTimeout > 0 {
+		poolConfig.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(config.StatementTimeout.Milliseconds(), 10)
+	}
+	poolConfig.ConnConfig.Tracer = multitracer.New(
+		otelpgx.NewTracer(otelpgx.WithTracerAttributes(attribute.String("db.system", "postgresql"))),
+		newInterruptionTracer(),
+	)
+	poolConfig.AfterConnect = func(_ context.Context, conn *pgx.Conn) error {
+		registerStringUUID(conn.TypeMap())
+		return nil
+	}
+	return poolConfig, nil
 }
@@ -0,0 +1,60 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const queryCanceledCode = "57014"
+
+// interruptionTracer counts statements that never finished: the caller went away, its deadline passed, or the server's
+// statement_timeout stopped them.
+type interruptionTracer struct {
+	interrupted metric.Int64Counter
+}
+
+func newInterruptionTracer() *interruptionTracer {
+	counter, err := otel.Meter(databaseTracerName).Int64Counter(
+		"capim.db.client.query.interrupted.count",
+		metric.WithDescription("Total de queries interrompidas por cancelamento ou timeout"),
+	)
+	if err != nil {
+		slog.Error("create interrupted query counter", "error", err)
+	}
+	return &interruptionTracer{interrupted: counter}
+}
+
+func (t *interruptionTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceQueryStartData) context.Context {
+	return ctx
+}
+
+func (t *interruptionTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	if data.Err == nil || t.interrupted == nil {
+		return
+	}
+	reason := interruptionReason(ctx, data.Err)
+	if reason == "" {
+		return
+	}
+	t.interrupted.Add(ctx, 1, metric.WithAttributes(attribute.String("reason", reason)))
+}
+
+func interruptionReason(ctx context.Context, err error) string {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded):
+		return "deadline"
+	case errors.Is(ctx.Err(), context.Canceled) || errors.Is(err, context.Canceled):
+		return "canceled"
+	}
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok && pgErr.Code == queryCanceledCode {
+		return "statement_timeout"
+	}
+	return ""
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Channel is a Postgres LISTEN/NOTIFY channel. Notifications reach every API instance connected to the database, but only while
+// it is listening: nothing is queued for a listener that is away.
+type Channel struct {
+	db   *Postgres
+	name string
+}
+
+func (p *Postgres) Channel(name string) *Channel {
+	return &Channel{db: p, name: name}
+}
+
+func (c *Channel) Publish(ctx context.Context, payload string) error {
+	if _, err := c.db.Pool.Exec(ctx, "SELECT pg_notify($1, $2)", c.name, payload); err != nil {
+		return fmt.Errorf("notify %s: %w", c.name, err)
+	}
+	return nil
+}
+
+// Listen holds a connection and passes each payload to handle until ctx ends or the connection fails. subscribed runs once the
+// LISTEN is in place, which is the point from which no notification can be missed.
+func (c *Channel) Listen(ctx context.Context, subscribed func(), handle func(payload string)) error {
+	pooled, err := c.db.Pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("acquire listener connection: %w", err)
+	}
+	// A subscribed connection must not go back to the pool, so it leaves the pool for good.
+	conn := pooled.Hijack()
+	defer conn.Close(context.Background())
+
+	if _, err := conn.Exec(ctx, "LISTEN "+pgx.Identifier{c.name}.Sanitize()); err != nil {
+		return fmt.Errorf("listen %s: %w", c.name, err)
+	}
+	subscribed()
+	for {
+		notification, err := conn.WaitForNotification(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return fmt.Errorf("wait for notification on %s: %w", c.name, err)
+		}
+		handle(notification.Payload)
+	}
+}
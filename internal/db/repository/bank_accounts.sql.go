@@ -7,48 +7,131 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
+const approveBankAccountHolder = `-- name: ApproveBankAccountHolder :execrows
+UPDATE bank_accounts
+SET holder_review_required = FALSE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND clinic_id = $3::uuid
+  AND holder_review_required
+  AND deleted_at IS NULL
+`
+
+type ApproveBankAccountHolderParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+	ClinicID       string `json:"clinic_id"`
+}
+
+func (q *Queries) ApproveBankAccountHolder(ctx context.Context, arg ApproveBankAccountHolderParams) (int64, error) {
+	result, err := q.db.Exec(ctx, approveBankAccountHolder, arg.ID, arg.OrganizationID, arg.ClinicID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const clearPrimaryBankAccount = `-- name: ClearPrimaryBankAccount :exec
+UPDATE bank_accounts
+SET is_primary = FALSE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND is_primary
+  AND deleted_at IS NULL
+`
+
+type ClearPrimaryBankAccountParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) ClearPrimaryBankAccount(ctx context.Context, arg ClearPrimaryBankAccountParams) error {
+	_, err := q.db.Exec(ctx, clearPrimaryBankAccount, arg.ClinicID, arg.OrganizationID)
+	return err
+}
+
 const createBankAccount = `-- name: CreateBankAccount :one
 INSERT INTO bank_accounts (
     id,
+    organization_id,
     clinic_id,
     bank_code,
     branch_number,
-    account_number
+    account_number,
+    account_type,
+    holder_name,
+    holder_tax_id,
+    holder_review_required,
+    is_primary
 ) VALUES (
     $1::uuid,
     $2::uuid,
-    $3,
+    $3::uuid,
     $4,
-    $5
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11
 )
-RETURNING id, clinic_id, bank_code, branch_number, account_number, created_at, updated_at, deleted_at
+RETURNING id, organization_id, clinic_id, bank_code, branch_number, account_number, account_type, holder_name, holder_tax_id, holder_review_required, is_primary, verification_status, verification_provider, verification_reference, verification_requested_at, verified_at, verification_failure_reason, created_at, updated_at, deleted_at
 `
 
 type CreateBankAccountParams struct {
-	ID            string `json:"id"`
-	ClinicID      string `json:"clinic_id"`
-	BankCode      string `json:"bank_code"`
-	BranchNumber  string `json:"branch_number"`
-	AccountNumber string `json:"account_number"`
+	ID                   string         `json:"id"`
+	OrganizationID       string         `json:"organization_id"`
+	ClinicID             string         `json:"clinic_id"`
+	BankCode             string         `json:"bank_code"`
+	BranchNumber         string         `json:"branch_number"`
+	AccountNumber        string         `json:"account_number"`
+	AccountType          string         `json:"account_type"`
+	HolderName           sql.NullString `json:"holder_name"`
+	HolderTaxID          sql.NullString `json:"holder_tax_id"`
+	HolderReviewRequired bool           `json:"holder_review_required"`
+	IsPrimary            bool           `json:"is_primary"`
 }
 
 func (q *Queries) CreateBankAccount(ctx context.Context, arg CreateBankAccountParams) (BankAccount, error) {
-	row := q.db.QueryRowContext(ctx, createBankAccount,
+	row := q.db.QueryRow(ctx, createBankAccount,
 		arg.ID,
+		arg.OrganizationID,
 		arg.ClinicID,
 		arg.BankCode,
 		arg.BranchNumber,
 		arg.AccountNumber,
+		arg.AccountType,
+		arg.HolderName,
+		arg.HolderTaxID,
+		arg.HolderReviewRequired,
+		arg.IsPrimary,
 	)
 	var i BankAccount
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.ClinicID,
 		&i.BankCode,
 		&i.BranchNumber,
 		&i.AccountNumber,
+		&i.AccountType,
+		&i.HolderName,
+		&i.HolderTaxID,
+		&i.HolderReviewRequired,
+		&i.IsPrimary,
+		&i.VerificationStatus,
+		&i.VerificationProvider,
+		&i.VerificationReference,
+		&i.VerificationRequestedAt,
+		&i.VerifiedAt,
+		&i.VerificationFailureReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -56,26 +139,88 @@ func (q *Queries) CreateBankAccount(ctx context.Context, arg CreateBankAccountPa
 	return i, err
 }
 
+const createBankAccounts = `-- name: CreateBankAccounts :exec
+INSERT INTO bank_accounts (
+    id,
+    organization_id,
+    clinic_id,
+    bank_code,
+    branch_number,
+    account_number,
+    account_type,
+    holder_name,
+    holder_tax_id,
+    holder_review_required,
+    is_primary
+)
+SELECT
+    unnest($1::uuid[]),
+    $2::uuid,
+    $3::uuid,
+    unnest($4::text[]),
+    unnest($5::text[]),
+    unnest($6::text[]),
+    unnest($7::text[]),
+    unnest($8::text[]),
+    unnest($9::text[]),
+    unnest($10::boolean[]),
+    unnest($11::boolean[])
+`
+
+type CreateBankAccountsParams struct {
+	Ids                  []string `json:"ids"`
+	OrganizationID       string   `json:"organization_id"`
+	ClinicID             string   `json:"clinic_id"`
+	BankCodes            []string `json:"bank_codes"`
+	BranchNumbers        []string `json:"branch_numbers"`
+	AccountNumbers       []string `json:"account_numbers"`
+	AccountTypes         []string `json:"account_types"`
+	HolderNames          []string `json:"holder_names"`
+	HolderTaxIds         []string `json:"holder_tax_ids"`
+	HolderReviewRequired []bool   `json:"holder_review_required"`
+	IsPrimary            []bool   `json:"is_primary"`
+}
+
+func (q *Queries) CreateBankAccounts(ctx context.Context, arg CreateBankAccountsParams) error {
+	_, err := q.db.Exec(ctx, createBankAccounts,
+		arg.Ids,
+		arg.OrganizationID,
+		arg.ClinicID,
+		arg.BankCodes,
+		arg.BranchNumbers,
+		arg.AccountNumbers,
+		arg.AccountTypes,
+		arg.HolderNames,
+		arg.HolderTaxIds,
+		arg.HolderReviewRequired,
+		arg.IsPrimary,
+	)
+	return err
+}
+
 const deleteBankAccountByIDAndClinicID = `-- name: DeleteBankAccountByIDAndClinicID :execrows
 UPDATE bank_accounts
 SET deleted_at = CURRENT_TIMESTAMP,
+    is_primary = FALSE,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1::uuid
-  AND clinic_id = $2::uuid
+  AND organization_id = $2::uuid
+  AND clinic_id = $3::uuid
   AND deleted_at IS NULL
 `
 
 type DeleteBankAccountByIDAndClinicIDParams struct {
-	ID       string `json:"id"`
-	ClinicID string `json:"clinic_id"`
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+	ClinicID       string `json:"clinic_id"`
 }
 
 func (q *Queries) DeleteBankAccountByIDAndClinicID(ctx context.Context, arg DeleteBankAccountByIDAndClinicIDParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteBankAccountByIDAndClinicID, arg.ID, arg.ClinicID)
+	result, err := q.db.Exec(ctx, deleteBankAccountByIDAndClinicID, arg.ID, arg.OrganizationID, arg.ClinicID)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return result.RowsAffected(), nil
 }
 
 const deleteBankAccountsByClinicID = `-- name: DeleteBankAccountsByClinicID :execrows
@@ -83,40 +228,119 @@ UPDATE bank_accounts
 SET deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE clinic_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NULL
+`
+
+type DeleteBankAccountsByClinicIDParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) DeleteBankAccountsByClinicID(ctx context.Context, arg DeleteBankAccountsByClinicIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBankAccountsByClinicID, arg.ClinicID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const deleteBankAccountsByClinicIDAt = `-- name: DeleteBankAccountsByClinicIDAt :execrows
+UPDATE bank_accounts
+SET deleted_at = $1::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = $2::uuid
+  AND organization_id = $3::uuid
   AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteBankAccountsByClinicID(ctx context.Context, clinicID string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteBankAccountsByClinicID, clinicID)
+type DeleteBankAccountsByClinicIDAtParams struct {
+	DeletedAt      time.Time `json:"deleted_at"`
+	ClinicID       string    `json:"clinic_id"`
+	OrganizationID string    `json:"organization_id"`
+}
+
+func (q *Queries) DeleteBankAccountsByClinicIDAt(ctx context.Context, arg DeleteBankAccountsByClinicIDAtParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteBankAccountsByClinicIDAt, arg.DeletedAt, arg.ClinicID, arg.OrganizationID)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return result.RowsAffected(), nil
 }
 
 const getBankAccountByIDAndClinicID = `-- name: GetBankAccountByIDAndClinicID :one
-SELECT id, clinic_id, bank_code, branch_number, account_number, created_at, updated_at, deleted_at
+SELECT id, organization_id, clinic_id, bank_code, branch_number, account_number, account_type, holder_name, holder_tax_id, holder_review_required, is_primary, verification_status, verification_provider, verification_reference, verification_requested_at, verified_at, verification_failure_reason, created_at, updated_at, deleted_at
 FROM bank_accounts
 WHERE id = $1::uuid
-  AND clinic_id = $2::uuid
+  AND organization_id = $2::uuid
+  AND clinic_id = $3::uuid
   AND deleted_at IS NULL
 LIMIT 1
 `
 
 type GetBankAccountByIDAndClinicIDParams struct {
-	ID       string `json:"id"`
-	ClinicID string `json:"clinic_id"`
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+	ClinicID       string `json:"clinic_id"`
 }
 
 func (q *Queries) GetBankAccountByIDAndClinicID(ctx context.Context, arg GetBankAccountByIDAndClinicIDParams) (BankAccount, error) {
-	row := q.db.QueryRowContext(ctx, getBankAccountByIDAndClinicID, arg.ID, arg.ClinicID)
+	row := q.db.QueryRow(ctx, getBankAccountByIDAndClinicID, arg.ID, arg.OrganizationID, arg.ClinicID)
 	var i BankAccount
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.ClinicID,
 		&i.BankCode,
 		&i.BranchNumber,
 		&i.AccountNumber,
+		&i.AccountType,
+		&i.HolderName,
+		&i.HolderTaxID,
+		&i.HolderReviewRequired,
+		&i.IsPrimary,
+		&i.VerificationStatus,
+		&i.VerificationProvider,
+		&i.VerificationReference,
+		&i.VerificationRequestedAt,
+		&i.VerifiedAt,
+		&i.VerificationFailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const getBankAccountByVerificationReference = `-- name: GetBankAccountByVerificationReference :one
+SELECT id, organization_id, clinic_id, bank_code, branch_number, account_number, account_type, holder_name, holder_tax_id, holder_review_required, is_primary, verification_status, verification_provider, verification_reference, verification_requested_at, verified_at, verification_failure_reason, created_at, updated_at, deleted_at
+FROM bank_accounts
+WHERE verification_reference = $1::text
+  AND deleted_at IS NULL
+LIMIT 1
+`
+
+func (q *Queries) GetBankAccountByVerificationReference(ctx context.Context, reference string) (BankAccount, error) {
+	row := q.db.QueryRow(ctx, getBankAccountByVerificationReference, reference)
+	var i BankAccount
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.ClinicID,
+		&i.BankCode,
+		&i.BranchNumber,
+		&i.AccountNumber,
+		&i.AccountType,
+		&i.HolderName,
+		&i.HolderTaxID,
+		&i.HolderReviewRequired,
+		&i.IsPrimary,
+		&i.VerificationStatus,
+		&i.VerificationProvider,
+		&i.VerificationReference,
+		&i.VerificationRequestedAt,
+		&i.VerifiedAt,
+		&i.VerificationFailureReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -125,15 +349,21 @@ func (q *Queries) GetBankAccountByIDAndClinicID(ctx context.Context, arg GetBank
 }
 
 const listBankAccountsByClinicID = `-- name: ListBankAccountsByClinicID :many
-SELECT id, clinic_id, bank_code, branch_number, account_number, created_at, updated_at, deleted_at
+SELECT id, organization_id, clinic_id, bank_code, branch_number, account_number, account_type, holder_name, holder_tax_id, holder_review_required, is_primary, verification_status, verification_provider, verification_reference, verification_requested_at, verified_at, verification_failure_reason, created_at, updated_at, deleted_at
 FROM bank_accounts
 WHERE clinic_id = $1::uuid
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
-ORDER BY created_at DESC
+ORDER BY is_primary DESC, created_at DESC
 `
 
-func (q *Queries) ListBankAccountsByClinicID(ctx context.Context, clinicID string) ([]BankAccount, error) {
-	rows, err := q.db.QueryContext(ctx, listBankAccountsByClinicID, clinicID)
+type ListBankAccountsByClinicIDParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) ListBankAccountsByClinicID(ctx context.Context, arg ListBankAccountsByClinicIDParams) ([]BankAccount, error) {
+	rows, err := q.db.Query(ctx, listBankAccountsByClinicID, arg.ClinicID, arg.OrganizationID)
 	if err != nil {
 		return nil, err
 	}
@@ -143,10 +373,22 @@ func (q *Queries) ListBankAccountsByClinicID(ctx context.Context, clinicID strin
 		var i BankAccount
 		if err := rows.Scan(
 			&i.ID,
+			&i.OrganizationID,
 			&i.ClinicID,
 			&i.BankCode,
 			&i.BranchNumber,
 			&i.AccountNumber,
+			&i.AccountType,
+			&i.HolderName,
+			&i.HolderTaxID,
+			&i.HolderReviewRequired,
+			&i.IsPrimary,
+			&i.VerificationStatus,
+			&i.VerificationProvider,
+			&i.VerificationReference,
+			&i.VerificationRequestedAt,
+			&i.VerifiedAt,
+			&i.VerificationFailureReason,
 			&i.CreatedAt,
 			&i.UpdatedAt,
 			&i.DeletedAt,
@@ -155,11 +397,274 @@ func (q *Queries) ListBankAccountsByClinicID(ctx context.Context, clinicID strin
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
 		return nil, err
 	}
+	return items, nil
+}
+
+const listBankAccountsByClinicIDDeletedAt = `-- name: ListBankAccountsByClinicIDDeletedAt :many
+SELECT id, organization_id, clinic_id, bank_code, branch_number, account_number, account_type, holder_name, holder_tax_id, holder_review_required, is_primary, verification_status, verification_provider, verification_reference, verification_requested_at, verified_at, verification_failure_reason, created_at, updated_at, deleted_at
+FROM bank_accounts
+WHERE clinic_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND (deleted_at = $3::timestamptz OR deleted_at IS NULL)
+ORDER BY is_primary DESC, created_at DESC
+`
+
+type ListBankAccountsByClinicIDDeletedAtParams struct {
+	ClinicID       string    `json:"clinic_id"`
+	OrganizationID string    `json:"organization_id"`
+	DeletedAt      time.Time `json:"deleted_at"`
+}
+
+func (q *Queries) ListBankAccountsByClinicIDDeletedAt(ctx context.Context, arg ListBankAccountsByClinicIDDeletedAtParams) ([]BankAccount, error) {
+	rows, err := q.db.Query(ctx, listBankAccountsByClinicIDDeletedAt, arg.ClinicID, arg.OrganizationID, arg.DeletedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []BankAccount{}
+	for rows.Next() {
+		var i BankAccount
+		if err := rows.Scan(
+			&i.ID,
+			&i.OrganizationID,
+			&i.ClinicID,
+			&i.BankCode,
+			&i.BranchNumber,
+			&i.AccountNumber,
+			&i.AccountType,
+			&i.HolderName,
+			&i.HolderTaxID,
+			&i.HolderReviewRequired,
+			&i.IsPrimary,
+			&i.VerificationStatus,
+			&i.VerificationProvider,
+			&i.VerificationReference,
+			&i.VerificationRequestedAt,
+			&i.VerifiedAt,
+			&i.VerificationFailureReason,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.DeletedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
+
+const markBankAccountVerificationFailed = `-- name: MarkBankAccountVerificationFailed :execrows
+UPDATE bank_accounts
+SET verification_status = 'UNVERIFIED',
+    verification_failure_reason = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE verification_reference = $2::text
+  AND organization_id = $3::uuid
+  AND verification_status = 'PENDING'
+  AND deleted_at IS NULL
+`
+
+type MarkBankAccountVerificationFailedParams struct {
+	Reason         sql.NullString `json:"reason"`
+	Reference      string         `json:"reference"`
+	OrganizationID string         `json:"organization_id"`
+}
+
+func (q *Queries) MarkBankAccountVerificationFailed(ctx context.Context, arg MarkBankAccountVerificationFailedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markBankAccountVerificationFailed, arg.Reason, arg.Reference, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markBankAccountVerified = `-- name: MarkBankAccountVerified :execrows
+UPDATE bank_accounts
+SET verification_status = 'VERIFIED',
+    verified_at = CURRENT_TIMESTAMP,
+    verification_failure_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE verification_reference = $1::text
+  AND organization_id = $2::uuid
+  AND verification_status = 'PENDING'
+  AND deleted_at IS NULL
+`
+
+type MarkBankAccountVerifiedParams struct {
+	Reference      string `json:"reference"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) MarkBankAccountVerified(ctx context.Context, arg MarkBankAccountVerifiedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markBankAccountVerified, arg.Reference, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreBankAccountsDeletedAt = `-- name: RestoreBankAccountsDeletedAt :execrows
+UPDATE bank_accounts
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at = $3::timestamptz
+`
+
+type RestoreBankAccountsDeletedAtParams struct {
+	ClinicID       string    `json:"clinic_id"`
+	OrganizationID string    `json:"organization_id"`
+	DeletedAt      time.Time `json:"deleted_at"`
+}
+
+func (q *Queries) RestoreBankAccountsDeletedAt(ctx context.Context, arg RestoreBankAccountsDeletedAtParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreBankAccountsDeletedAt, arg.ClinicID, arg.OrganizationID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setPrimaryBankAccount = `-- name: SetPrimaryBankAccount :execrows
+UPDATE bank_accounts
+SET is_primary = TRUE,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND clinic_id = $3::uuid
+  AND deleted_at IS NULL
+`
+
+type SetPrimaryBankAccountParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+	ClinicID       string `json:"clinic_id"`
+}
+
+func (q *Queries) SetPrimaryBankAccount(ctx context.Context, arg SetPrimaryBankAccountParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setPrimaryBankAccount, arg.ID, arg.OrganizationID, arg.ClinicID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const startBankAccountVerification = `-- name: StartBankAccountVerification :execrows
+UPDATE bank_accounts
+SET verification_status = 'PENDING',
+    verification_provider = $1::text,
+    verification_reference = $2::text,
+    verification_requested_at = CURRENT_TIMESTAMP,
+    verified_at = NULL,
+    verification_failure_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $3::uuid
+  AND organization_id = $4::uuid
+  AND clinic_id = $5::uuid
+  AND verification_status <> 'VERIFIED'
+  AND deleted_at IS NULL
+`
+
+type StartBankAccountVerificationParams struct {
+	Provider       string `json:"provider"`
+	Reference      string `json:"reference"`
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+	ClinicID       string `json:"clinic_id"`
+}
+
+func (q *Queries) StartBankAccountVerification(ctx context.Context, arg StartBankAccountVerificationParams) (int64, error) {
+	result, err := q.db.Exec(ctx, startBankAccountVerification,
+		arg.Provider,
+		arg.Reference,
+		arg.ID,
+		arg.OrganizationID,
+		arg.ClinicID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateBankAccount = `-- name: UpdateBankAccount :one
+UPDATE bank_accounts
+SET bank_code = COALESCE($1, bank_code),
+    branch_number = COALESCE($2, branch_number),
+    account_number = COALESCE($3, account_number),
+    account_type = COALESCE($4, account_type),
+    holder_name = COALESCE($5, holder_name),
+    holder_tax_id = COALESCE($6, holder_tax_id),
+    holder_review_required = $7,
+    verification_status = 'UNVERIFIED',
+    verification_provider = NULL,
+    verification_reference = NULL,
+    verification_requested_at = NULL,
+    verified_at = NULL,
+    verification_failure_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $8::uuid
+  AND organization_id = $9::uuid
+  AND clinic_id = $10::uuid
+  AND deleted_at IS NULL
+RETURNING id, organization_id, clinic_id, bank_code, branch_number, account_number, account_type, holder_name, holder_tax_id, holder_review_required, is_primary, verification_status, verification_provider, verification_reference, verification_requested_at, verified_at, verification_failure_reason, created_at, updated_at, deleted_at
+`
+
+type UpdateBankAccountParams struct {
+	BankCode             sql.NullString `json:"bank_code"`
+	BranchNumber         sql.NullString `json:"branch_number"`
+	AccountNumber        sql.NullString `json:"account_number"`
+	AccountType          sql.NullString `json:"account_type"`
+	HolderName           sql.NullString `json:"holder_name"`
+	HolderTaxID          sql.NullString `json:"holder_tax_id"`
+	HolderReviewRequired bool           `json:"holder_review_required"`
+	ID                   string         `json:"id"`
+	OrganizationID       string         `json:"organization_id"`
+	ClinicID             string         `json:"clinic_id"`
+}
+
+func (q *Queries) UpdateBankAccount(ctx context.Context, arg UpdateBankAccountParams) (BankAccount, error) {
+	row := q.db.QueryRow(ctx, updateBankAccount,
+		arg.BankCode,
+		arg.BranchNumber,
+		arg.AccountNumber,
+		arg.AccountType,
+		arg.HolderName,
+		arg.HolderTaxID,
+		arg.HolderReviewRequired,
+		arg.ID,
+		arg.OrganizationID,
+		arg.ClinicID,
+	)
+	var i BankAccount
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.ClinicID,
+		&i.BankCode,
+		&i.BranchNumber,
+		&i.AccountNumber,
+		&i.AccountType,
+		&i.HolderName,
+		&i.HolderTaxID,
+		&i.HolderReviewRequired,
+		&i.IsPrimary,
+		&i.VerificationStatus,
+		&i.VerificationProvider,
+		&i.VerificationReference,
+		&i.VerificationRequestedAt,
+		&i.VerifiedAt,
+		&i.VerificationFailureReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
@@ -9,63 +9,117 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 const countActiveClinicLinksByDentist = `-- name: CountActiveClinicLinksByDentist :one
 SELECT COUNT(*)::bigint
 FROM clinic_dentists
 WHERE dentist_id = $1::uuid
+  AND organization_id = $2::uuid
   AND ended_at IS NULL
 `
 
-func (q *Queries) CountActiveClinicLinksByDentist(ctx context.Context, dentistID string) (int64, error) {
-	row := q.db.QueryRowContext(ctx, countActiveClinicLinksByDentist, dentistID)
+type CountActiveClinicLinksByDentistParams struct {
+	DentistID      string `json:"dentist_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) CountActiveClinicLinksByDentist(ctx context.Context, arg CountActiveClinicLinksByDentistParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countActiveClinicLinksByDentist, arg.DentistID, arg.OrganizationID)
 	var column_1 int64
 	err := row.Scan(&column_1)
 	return column_1, err
 }
 
+const countClinicRoleHolders = `-- name: CountClinicRoleHolders :one
+SELECT
+    COUNT(*)::bigint AS active_dentists,
+    COUNT(*) FILTER (WHERE cd.is_admin)::bigint AS admins,
+    COUNT(*) FILTER (WHERE cd.is_legal_representative)::bigint AS legal_representatives
+FROM clinic_dentists cd
+JOIN dentists d ON d.id = cd.dentist_id
+WHERE cd.clinic_id = $1::uuid
+  AND cd.organization_id = $2::uuid
+  AND cd.ended_at IS NULL
+  AND d.deleted_at IS NULL
+`
+
+type CountClinicRoleHoldersParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+type CountClinicRoleHoldersRow struct {
+	ActiveDentists       int64 `json:"active_dentists"`
+	Admins               int64 `json:"admins"`
+	LegalRepresentatives int64 `json:"legal_representatives"`
+}
+
+func (q *Queries) CountClinicRoleHolders(ctx context.Context, arg CountClinicRoleHoldersParams) (CountClinicRoleHoldersRow, error) {
+	row := q.db.QueryRow(ctx, countClinicRoleHolders, arg.ClinicID, arg.OrganizationID)
+	var i CountClinicRoleHoldersRow
+	err := row.Scan(&i.ActiveDentists, &i.Admins, &i.LegalRepresentatives)
+	return i, err
+}
+
 const createClinicDentist = `-- name: CreateClinicDentist :one
 INSERT INTO clinic_dentists (
+    organization_id,
     clinic_id,
     dentist_id,
     is_admin,
     is_legal_representative,
-    started_at
+    started_at,
+    planned_end_at,
+    substitute_for_dentist_id
 ) VALUES (
     $1::uuid,
     $2::uuid,
-    $3,
+    $3::uuid,
     $4,
-    $5
+    $5,
+    $6,
+    $7,
+    $8::uuid
 )
-RETURNING clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, created_at, updated_at
+RETURNING organization_id, clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, planned_end_at, substitute_for_dentist_id, created_at, updated_at
 `
 
 type CreateClinicDentistParams struct {
-	ClinicID              string    `json:"clinic_id"`
-	DentistID             string    `json:"dentist_id"`
-	IsAdmin               bool      `json:"is_admin"`
-	IsLegalRepresentative bool      `json:"is_legal_representative"`
-	StartedAt             time.Time `json:"started_at"`
+	OrganizationID         string        `json:"organization_id"`
+	ClinicID               string        `json:"clinic_id"`
+	DentistID              string        `json:"dentist_id"`
+	IsAdmin                bool          `json:"is_admin"`
+	IsLegalRepresentative  bool          `json:"is_legal_representative"`
+	StartedAt              time.Time     `json:"started_at"`
+	PlannedEndAt           sql.NullTime  `json:"planned_end_at"`
+	SubstituteForDentistID uuid.NullUUID `json:"substitute_for_dentist_id"`
 }
 
 func (q *Queries) CreateClinicDentist(ctx context.Context, arg CreateClinicDentistParams) (ClinicDentist, error) {
-	row := q.db.QueryRowContext(ctx, createClinicDentist,
+	row := q.db.QueryRow(ctx, createClinicDentist,
+		arg.OrganizationID,
 		arg.ClinicID,
 		arg.DentistID,
 		arg.IsAdmin,
 		arg.IsLegalRepresentative,
 		arg.StartedAt,
+		arg.PlannedEndAt,
+		arg.SubstituteForDentistID,
 	)
 	var i ClinicDentist
 	err := row.Scan(
+		&i.OrganizationID,
 		&i.ClinicID,
 		&i.DentistID,
 		&i.IsAdmin,
 		&i.IsLegalRepresentative,
 		&i.StartedAt,
 		&i.EndedAt,
+		&i.PlannedEndAt,
+		&i.SubstituteForDentistID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -77,21 +131,23 @@ UPDATE clinic_dentists
 SET ended_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE clinic_id = $1::uuid
-  AND dentist_id = $2::uuid
+  AND organization_id = $2::uuid
+  AND dentist_id = $3::uuid
   AND ended_at IS NULL
 `
 
 type EndClinicDentistParams struct {
-	ClinicID  string `json:"clinic_id"`
-	DentistID string `json:"dentist_id"`
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+	DentistID      string `json:"dentist_id"`
 }
 
 func (q *Queries) EndClinicDentist(ctx context.Context, arg EndClinicDentistParams) (int64, error) {
-	result, err := q.db.ExecContext(ctx, endClinicDentist, arg.ClinicID, arg.DentistID)
+	result, err := q.db.Exec(ctx, endClinicDentist, arg.ClinicID, arg.OrganizationID, arg.DentistID)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return result.RowsAffected(), nil
 }
 
 const endClinicDentistsByClinic = `-- name: EndClinicDentistsByClinic :execrows
@@ -99,15 +155,21 @@ UPDATE clinic_dentists
 SET ended_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE clinic_id = $1::uuid
+  AND organization_id = $2::uuid
   AND ended_at IS NULL
 `
 
-func (q *Queries) EndClinicDentistsByClinic(ctx context.Context, clinicID string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, endClinicDentistsByClinic, clinicID)
+type EndClinicDentistsByClinicParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) EndClinicDentistsByClinic(ctx context.Context, arg EndClinicDentistsByClinicParams) (int64, error) {
+	result, err := q.db.Exec(ctx, endClinicDentistsByClinic, arg.ClinicID, arg.OrganizationID)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return result.RowsAffected(), nil
 }
 
 const endClinicDentistsByDentist = `-- name: EndClinicDentistsByDentist :execrows
@@ -115,42 +177,452 @@ UPDATE clinic_dentists
 SET ended_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE dentist_id = $1::uuid
+  AND organization_id = $2::uuid
   AND ended_at IS NULL
 `
 
-func (q *Queries) EndClinicDentistsByDentist(ctx context.Context, dentistID string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, endClinicDentistsByDentist, dentistID)
+type EndClinicDentistsByDentistParams struct {
+	DentistID      string `json:"dentist_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) EndClinicDentistsByDentist(ctx context.Context, arg EndClinicDentistsByDentistParams) (int64, error) {
+	result, err := q.db.Exec(ctx, endClinicDentistsByDentist, arg.DentistID, arg.OrganizationID)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return result.RowsAffected(), nil
+}
+
+const endDueTemporaryClinicDentist = `-- name: EndDueTemporaryClinicDentist :execrows
+UPDATE clinic_dentists
+SET ended_at = planned_end_at,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND dentist_id = $3::uuid
+  AND ended_at IS NULL
+  AND planned_end_at IS NOT NULL
+  AND planned_end_at <= $4::timestamptz
+`
+
+type EndDueTemporaryClinicDentistParams struct {
+	ClinicID       string    `json:"clinic_id"`
+	OrganizationID string    `json:"organization_id"`
+	DentistID      string    `json:"dentist_id"`
+	Now            time.Time `json:"now"`
+}
+
+func (q *Queries) EndDueTemporaryClinicDentist(ctx context.Context, arg EndDueTemporaryClinicDentistParams) (int64, error) {
+	result, err := q.db.Exec(ctx, endDueTemporaryClinicDentist,
+		arg.ClinicID,
+		arg.OrganizationID,
+		arg.DentistID,
+		arg.Now,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getActiveClinicDentist = `-- name: GetActiveClinicDentist :one
-SELECT clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, created_at, updated_at
+SELECT organization_id, clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, planned_end_at, substitute_for_dentist_id, created_at, updated_at
 FROM clinic_dentists
 WHERE clinic_id = $1::uuid
-  AND dentist_id = $2::uuid
+  AND organization_id = $2::uuid
+  AND dentist_id = $3::uuid
   AND ended_at IS NULL
 ORDER BY started_at DESC
 LIMIT 1
 `
 
 type GetActiveClinicDentistParams struct {
-	ClinicID  string `json:"clinic_id"`
-	DentistID string `json:"dentist_id"`
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+	DentistID      string `json:"dentist_id"`
 }
 
 func (q *Queries) GetActiveClinicDentist(ctx context.Context, arg GetActiveClinicDentistParams) (ClinicDentist, error) {
-	row := q.db.QueryRowContext(ctx, getActiveClinicDentist, arg.ClinicID, arg.DentistID)
+	row := q.db.QueryRow(ctx, getActiveClinicDentist, arg.ClinicID, arg.OrganizationID, arg.DentistID)
+	var i ClinicDentist
+	err := row.Scan(
+		&i.OrganizationID,
+		&i.ClinicID,
+		&i.DentistID,
+		&i.IsAdmin,
+		&i.IsLegalRepresentative,
+		&i.StartedAt,
+		&i.EndedAt,
+		&i.PlannedEndAt,
+		&i.SubstituteForDentistID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const isClinicLegalRepresentativeTaxID = `-- name: IsClinicLegalRepresentativeTaxID :one
+SELECT EXISTS (
+    SELECT 1
+    FROM clinic_dentists cd
+    JOIN dentists d ON d.id = cd.dentist_id AND d.deleted_at IS NULL
+    JOIN people p ON p.id = d.person_id AND p.deleted_at IS NULL
+    WHERE cd.clinic_id = $1::uuid
+      AND cd.organization_id = $2::uuid
+      AND cd.ended_at IS NULL
+      AND cd.is_legal_representative
+      AND p.tax_id_number = $3::text
+)::boolean
+`
+
+type IsClinicLegalRepresentativeTaxIDParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+	TaxIDNumber    string `json:"tax_id_number"`
+}
+
+func (q *Queries) IsClinicLegalRepresentativeTaxID(ctx context.Context, arg IsClinicLegalRepresentativeTaxIDParams) (bool, error) {
+	row := q.db.QueryRow(ctx, isClinicLegalRepresentativeTaxID, arg.ClinicID, arg.OrganizationID, arg.TaxIDNumber)
+	var column_1 bool
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const listActiveClinicIDsByDentist = `-- name: ListActiveClinicIDsByDentist :many
+SELECT clinic_id
+FROM clinic_dentists
+WHERE dentist_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND ended_at IS NULL
+ORDER BY clinic_id
+`
+
+type ListActiveClinicIDsByDentistParams struct {
+	DentistID      string `json:"dentist_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) ListActiveClinicIDsByDentist(ctx context.Context, arg ListActiveClinicIDsByDentistParams) ([]string, error) {
+	rows, err := q.db.Query(ctx, listActiveClinicIDsByDentist, arg.DentistID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var clinic_id string
+		if err := rows.Scan(&clinic_id); err != nil {
+			return nil, err
+		}
+		items = append(items, clinic_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClinicDentistHistory = `-- name: ListClinicDentistHistory :many
+SELECT organization_id, clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, planned_end_at, substitute_for_dentist_id, created_at, updated_at
+FROM clinic_dentists
+WHERE clinic_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND dentist_id = $3::uuid
+ORDER BY started_at DESC
+`
+
+type ListClinicDentistHistoryParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+	DentistID      string `json:"dentist_id"`
+}
+
+func (q *Queries) ListClinicDentistHistory(ctx context.Context, arg ListClinicDentistHistoryParams) ([]ClinicDentist, error) {
+	rows, err := q.db.Query(ctx, listClinicDentistHistory, arg.ClinicID, arg.OrganizationID, arg.DentistID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ClinicDentist{}
+	for rows.Next() {
+		var i ClinicDentist
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.ClinicID,
+			&i.DentistID,
+			&i.IsAdmin,
+			&i.IsLegalRepresentative,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.PlannedEndAt,
+			&i.SubstituteForDentistID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClinicDentistRowsByDentist = `-- name: ListClinicDentistRowsByDentist :many
+SELECT organization_id, clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, planned_end_at, substitute_for_dentist_id, created_at, updated_at
+FROM clinic_dentists
+WHERE dentist_id = $1::uuid
+  AND organization_id = $2::uuid
+ORDER BY clinic_id, started_at
+`
+
+type ListClinicDentistRowsByDentistParams struct {
+	DentistID      string `json:"dentist_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) ListClinicDentistRowsByDentist(ctx context.Context, arg ListClinicDentistRowsByDentistParams) ([]ClinicDentist, error) {
+	rows, err := q.db.Query(ctx, listClinicDentistRowsByDentist, arg.DentistID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ClinicDentist{}
+	for rows.Next() {
+		var i ClinicDentist
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.ClinicID,
+			&i.DentistID,
+			&i.IsAdmin,
+			&i.IsLegalRepresentative,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.PlannedEndAt,
+			&i.SubstituteForDentistID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDentistEmploymentHistory = `-- name: ListDentistEmploymentHistory :many
+SELECT
+    cd.clinic_id,
+    cd.dentist_id,
+    cd.is_admin,
+    cd.is_legal_representative,
+    cd.started_at,
+    cd.ended_at,
+    p.legal_name AS clinic_legal_name,
+    p.trade_name AS clinic_trade_name
+FROM clinic_dentists cd
+JOIN clinics c ON c.id = cd.clinic_id
+JOIN people p ON p.id = c.person_id
+WHERE cd.dentist_id = $1::uuid
+  AND cd.organization_id = $2::uuid
+ORDER BY cd.started_at DESC, cd.clinic_id
+`
+
+type ListDentistEmploymentHistoryParams struct {
+	DentistID      string `json:"dentist_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+type ListDentistEmploymentHistoryRow struct {
+	ClinicID              string         `json:"clinic_id"`
+	DentistID             string         `json:"dentist_id"`
+	IsAdmin               bool           `json:"is_admin"`
+	IsLegalRepresentative bool           `json:"is_legal_representative"`
+	StartedAt             time.Time      `json:"started_at"`
+	EndedAt               sql.NullTime   `json:"ended_at"`
+	ClinicLegalName       string         `json:"clinic_legal_name"`
+	ClinicTradeName       sql.NullString `json:"clinic_trade_name"`
+}
+
+func (q *Queries) ListDentistEmploymentHistory(ctx context.Context, arg ListDentistEmploymentHistoryParams) ([]ListDentistEmploymentHistoryRow, error) {
+	rows, err := q.db.Query(ctx, listDentistEmploymentHistory, arg.DentistID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListDentistEmploymentHistoryRow{}
+	for rows.Next() {
+		var i ListDentistEmploymentHistoryRow
+		if err := rows.Scan(
+			&i.ClinicID,
+			&i.DentistID,
+			&i.IsAdmin,
+			&i.IsLegalRepresentative,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.ClinicLegalName,
+			&i.ClinicTradeName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDueTemporaryClinicDentists = `-- name: ListDueTemporaryClinicDentists :many
+SELECT organization_id, clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, planned_end_at, substitute_for_dentist_id, created_at, updated_at
+FROM clinic_dentists
+WHERE ended_at IS NULL
+  AND organization_id = $1::uuid
+  AND planned_end_at IS NOT NULL
+  AND planned_end_at <= $2::timestamptz
+ORDER BY planned_end_at, clinic_id, dentist_id
+LIMIT $3
+`
+
+type ListDueTemporaryClinicDentistsParams struct {
+	OrganizationID string    `json:"organization_id"`
+	Now            time.Time `json:"now"`
+	BatchSize      int32     `json:"batch_size"`
+}
+
+func (q *Queries) ListDueTemporaryClinicDentists(ctx context.Context, arg ListDueTemporaryClinicDentistsParams) ([]ClinicDentist, error) {
+	rows, err := q.db.Query(ctx, listDueTemporaryClinicDentists, arg.OrganizationID, arg.Now, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ClinicDentist{}
+	for rows.Next() {
+		var i ClinicDentist
+		if err := rows.Scan(
+			&i.OrganizationID,
+			&i.ClinicID,
+			&i.DentistID,
+			&i.IsAdmin,
+			&i.IsLegalRepresentative,
+			&i.StartedAt,
+			&i.EndedAt,
+			&i.PlannedEndAt,
+			&i.SubstituteForDentistID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const reassignClinicDentistRow = `-- name: ReassignClinicDentistRow :execrows
+UPDATE clinic_dentists
+SET dentist_id = $1::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = $2::uuid
+  AND organization_id = $3::uuid
+  AND dentist_id = $4::uuid
+  AND started_at = $5
+`
+
+type ReassignClinicDentistRowParams struct {
+	TargetDentistID string    `json:"target_dentist_id"`
+	ClinicID        string    `json:"clinic_id"`
+	OrganizationID  string    `json:"organization_id"`
+	DentistID       string    `json:"dentist_id"`
+	StartedAt       time.Time `json:"started_at"`
+}
+
+func (q *Queries) ReassignClinicDentistRow(ctx context.Context, arg ReassignClinicDentistRowParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reassignClinicDentistRow,
+		arg.TargetDentistID,
+		arg.ClinicID,
+		arg.OrganizationID,
+		arg.DentistID,
+		arg.StartedAt,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const reassignSubstituteFor = `-- name: ReassignSubstituteFor :execrows
+UPDATE clinic_dentists
+SET substitute_for_dentist_id = CASE
+        WHEN dentist_id = $1::uuid THEN NULL
+        ELSE $1::uuid
+    END,
+    updated_at = CURRENT_TIMESTAMP
+WHERE substitute_for_dentist_id = $2::uuid
+  AND organization_id = $3::uuid
+`
+
+type ReassignSubstituteForParams struct {
+	TargetDentistID string `json:"target_dentist_id"`
+	DentistID       string `json:"dentist_id"`
+	OrganizationID  string `json:"organization_id"`
+}
+
+func (q *Queries) ReassignSubstituteFor(ctx context.Context, arg ReassignSubstituteForParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reassignSubstituteFor, arg.TargetDentistID, arg.DentistID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateClinicDentistAssignment = `-- name: UpdateClinicDentistAssignment :one
+UPDATE clinic_dentists
+SET planned_end_at = $1,
+    substitute_for_dentist_id = $2::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE clinic_id = $3::uuid
+  AND organization_id = $4::uuid
+  AND dentist_id = $5::uuid
+  AND ended_at IS NULL
+RETURNING organization_id, clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, planned_end_at, substitute_for_dentist_id, created_at, updated_at
+`
+
+type UpdateClinicDentistAssignmentParams struct {
+	PlannedEndAt           sql.NullTime  `json:"planned_end_at"`
+	SubstituteForDentistID uuid.NullUUID `json:"substitute_for_dentist_id"`
+	ClinicID               string        `json:"clinic_id"`
+	OrganizationID         string        `json:"organization_id"`
+	DentistID              string        `json:"dentist_id"`
+}
+
+func (q *Queries) UpdateClinicDentistAssignment(ctx context.Context, arg UpdateClinicDentistAssignmentParams) (ClinicDentist, error) {
+	row := q.db.QueryRow(ctx, updateClinicDentistAssignment,
+		arg.PlannedEndAt,
+		arg.SubstituteForDentistID,
+		arg.ClinicID,
+		arg.OrganizationID,
+		arg.DentistID,
+	)
 	var i ClinicDentist
 	err := row.Scan(
+		&i.OrganizationID,
 		&i.ClinicID,
 		&i.DentistID,
 		&i.IsAdmin,
 		&i.IsLegalRepresentative,
 		&i.StartedAt,
 		&i.EndedAt,
+		&i.PlannedEndAt,
+		&i.SubstituteForDentistID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -164,33 +636,39 @@ SET
     is_legal_representative = COALESCE($2, is_legal_representative),
     updated_at = CURRENT_TIMESTAMP
 WHERE clinic_id = $3::uuid
-  AND dentist_id = $4::uuid
+  AND organization_id = $4::uuid
+  AND dentist_id = $5::uuid
   AND ended_at IS NULL
-RETURNING clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, created_at, updated_at
+RETURNING organization_id, clinic_id, dentist_id, is_admin, is_legal_representative, started_at, ended_at, planned_end_at, substitute_for_dentist_id, created_at, updated_at
 `
 
 type UpdateClinicDentistRoleParams struct {
 	IsAdmin               sql.NullBool `json:"is_admin"`
 	IsLegalRepresentative sql.NullBool `json:"is_legal_representative"`
 	ClinicID              string       `json:"clinic_id"`
+	OrganizationID        string       `json:"organization_id"`
 	DentistID             string       `json:"dentist_id"`
 }
 
 func (q *Queries) UpdateClinicDentistRole(ctx context.Context, arg UpdateClinicDentistRoleParams) (ClinicDentist, error) {
-	row := q.db.QueryRowContext(ctx, updateClinicDentistRole,
+	row := q.db.QueryRow(ctx, updateClinicDentistRole,
 		arg.IsAdmin,
 		arg.IsLegalRepresentative,
 		arg.ClinicID,
+		arg.OrganizationID,
 		arg.DentistID,
 	)
 	var i ClinicDentist
 	err := row.Scan(
+		&i.OrganizationID,
 		&i.ClinicID,
 		&i.DentistID,
 		&i.IsAdmin,
 		&i.IsLegalRepresentative,
 		&i.StartedAt,
 		&i.EndedAt,
+		&i.PlannedEndAt,
+		&i.SubstituteForDentistID,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 	)
@@ -8,27 +8,87 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+const countActiveBranches = `-- name: CountActiveBranches :one
+SELECT COUNT(*)::int
+FROM clinics
+WHERE parent_clinic_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NULL
+`
+
+type CountActiveBranchesParams struct {
+	ParentClinicID string `json:"parent_clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) CountActiveBranches(ctx context.Context, arg CountActiveBranchesParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countActiveBranches, arg.ParentClinicID, arg.OrganizationID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
+const countDistinctActiveDentistsInClinicGroup = `-- name: CountDistinctActiveDentistsInClinicGroup :one
+SELECT COUNT(DISTINCT cd.dentist_id)::int
+FROM clinic_dentists cd
+JOIN clinics c ON c.id = cd.clinic_id
+WHERE cd.organization_id = $1::uuid
+  AND (c.id = $2::uuid OR c.parent_clinic_id = $2::uuid)
+  AND c.deleted_at IS NULL
+  AND cd.ended_at IS NULL
+`
+
+type CountDistinctActiveDentistsInClinicGroupParams struct {
+	OrganizationID string `json:"organization_id"`
+	GroupClinicID  string `json:"group_clinic_id"`
+}
+
+func (q *Queries) CountDistinctActiveDentistsInClinicGroup(ctx context.Context, arg CountDistinctActiveDentistsInClinicGroupParams) (int32, error) {
+	row := q.db.QueryRow(ctx, countDistinctActiveDentistsInClinicGroup, arg.OrganizationID, arg.GroupClinicID)
+	var column_1 int32
+	err := row.Scan(&column_1)
+	return column_1, err
+}
+
 const createClinic = `-- name: CreateClinic :one
-INSERT INTO clinics (id, person_id)
-VALUES ($1::uuid, $2::uuid)
-RETURNING id, person_id, created_at, updated_at, deleted_at
+INSERT INTO clinics (id, organization_id, person_id, parent_clinic_id, timezone)
+VALUES ($1::uuid, $2::uuid, $3::uuid, $4::uuid, $5)
+RETURNING id, organization_id, person_id, parent_clinic_id, timezone, onboarding_status, onboarding_status_changed_at, deactivated_at, deactivation_reason, created_at, updated_at, deleted_at
 `
 
 type CreateClinicParams struct {
-	ID       string `json:"id"`
-	PersonID string `json:"person_id"`
+	ID             string        `json:"id"`
+	OrganizationID string        `json:"organization_id"`
+	PersonID       string        `json:"person_id"`
+	ParentClinicID uuid.NullUUID `json:"parent_clinic_id"`
+	Timezone       string        `json:"timezone"`
 }
 
 func (q *Queries) CreateClinic(ctx context.Context, arg CreateClinicParams) (Clinic, error) {
-	row := q.db.QueryRowContext(ctx, createClinic, arg.ID, arg.PersonID)
+	row := q.db.QueryRow(ctx, createClinic,
+		arg.ID,
+		arg.OrganizationID,
+		arg.PersonID,
+		arg.ParentClinicID,
+		arg.Timezone,
+	)
 	var i Clinic
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.PersonID,
+		&i.ParentClinicID,
+		&i.Timezone,
+		&i.OnboardingStatus,
+		&i.OnboardingStatusChangedAt,
+		&i.DeactivatedAt,
+		&i.DeactivationReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -36,36 +96,211 @@ func (q *Queries) CreateClinic(ctx context.Context, arg CreateClinicParams) (Cli
 	return i, err
 }
 
+const deactivateClinic = `-- name: DeactivateClinic :execrows
+UPDATE clinics
+SET deactivated_at = CURRENT_TIMESTAMP,
+    deactivation_reason = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2::uuid
+  AND organization_id = $3::uuid
+  AND deactivated_at IS NULL
+  AND deleted_at IS NULL
+`
+
+type DeactivateClinicParams struct {
+	Reason         sql.NullString `json:"reason"`
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+}
+
+func (q *Queries) DeactivateClinic(ctx context.Context, arg DeactivateClinicParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deactivateClinic, arg.Reason, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteClinic = `-- name: DeleteClinic :execrows
 UPDATE clinics
 SET deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1::uuid
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteClinic(ctx context.Context, id string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteClinic, id)
+type DeleteClinicParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) DeleteClinic(ctx context.Context, arg DeleteClinicParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteClinic, arg.ID, arg.OrganizationID)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return result.RowsAffected(), nil
+}
+
+const getClinicAggregate = `-- name: GetClinicAggregate :one
+SELECT
+    c.id AS clinic_id,
+    c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
+    p.legal_name,
+    p.trade_name,
+    p.tax_id_number,
+    p.email,
+    p.phone,
+    a.street AS address_street,
+    a.number AS address_number,
+    a.complement AS address_complement,
+    a.city AS address_city,
+    a.state AS address_state,
+    a.cep AS address_cep,
+    r.legal_name AS registry_legal_name,
+    r.trade_name AS registry_trade_name,
+    r.cnae_code AS registry_cnae_code,
+    r.cnae_description AS registry_cnae_description,
+    r.registration_status AS registry_registration_status,
+    r.source AS registry_source,
+    r.fetched_at AS registry_fetched_at,
+    COALESCE((
+        SELECT jsonb_agg(cd.dentist_id ORDER BY cd.dentist_id)
+        FROM clinic_dentists cd
+        JOIN dentists d ON d.id = cd.dentist_id
+        JOIN people dp ON dp.id = d.person_id
+        WHERE cd.clinic_id = c.id
+          AND cd.organization_id = c.organization_id
+          AND cd.ended_at IS NULL
+          AND d.deleted_at IS NULL
+          AND dp.deleted_at IS NULL
+    ), '[]'::jsonb)::jsonb AS dentist_ids,
+    COALESCE((
+        SELECT jsonb_agg(to_jsonb(b) ORDER BY b.is_primary DESC, b.created_at DESC)
+        FROM bank_accounts b
+        WHERE $1::boolean
+          AND b.clinic_id = c.id
+          AND b.organization_id = c.organization_id
+          AND b.deleted_at IS NULL
+    ), '[]'::jsonb)::jsonb AS bank_accounts
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+LEFT JOIN addresses a ON a.person_id = c.person_id AND a.organization_id = c.organization_id
+LEFT JOIN clinic_registry_records r ON r.clinic_id = c.id AND r.organization_id = c.organization_id
+WHERE c.id = $2::uuid
+  AND c.organization_id = $3::uuid
+  AND c.deleted_at IS NULL
+  AND p.deleted_at IS NULL
+LIMIT 1
+`
+
+type GetClinicAggregateParams struct {
+	IncludeBankAccounts bool   `json:"include_bank_accounts"`
+	ID                  string `json:"id"`
+	OrganizationID      string `json:"organization_id"`
+}
+
+type GetClinicAggregateRow struct {
+	ClinicID                   string          `json:"clinic_id"`
+	PersonID                   string          `json:"person_id"`
+	ParentClinicID             uuid.NullUUID   `json:"parent_clinic_id"`
+	Timezone                   string          `json:"timezone"`
+	OnboardingStatus           string          `json:"onboarding_status"`
+	OnboardingStatusChangedAt  time.Time       `json:"onboarding_status_changed_at"`
+	DeactivatedAt              sql.NullTime    `json:"deactivated_at"`
+	DeactivationReason         sql.NullString  `json:"deactivation_reason"`
+	LegalName                  string          `json:"legal_name"`
+	TradeName                  sql.NullString  `json:"trade_name"`
+	TaxIDNumber                string          `json:"tax_id_number"`
+	Email                      sql.NullString  `json:"email"`
+	Phone                      sql.NullString  `json:"phone"`
+	AddressStreet              sql.NullString  `json:"address_street"`
+	AddressNumber              sql.NullString  `json:"address_number"`
+	AddressComplement          sql.NullString  `json:"address_complement"`
+	AddressCity                sql.NullString  `json:"address_city"`
+	AddressState               sql.NullString  `json:"address_state"`
+	AddressCep                 sql.NullString  `json:"address_cep"`
+	RegistryLegalName          sql.NullString  `json:"registry_legal_name"`
+	RegistryTradeName          sql.NullString  `json:"registry_trade_name"`
+	RegistryCnaeCode           sql.NullString  `json:"registry_cnae_code"`
+	RegistryCnaeDescription    sql.NullString  `json:"registry_cnae_description"`
+	RegistryRegistrationStatus sql.NullString  `json:"registry_registration_status"`
+	RegistrySource             sql.NullString  `json:"registry_source"`
+	RegistryFetchedAt          sql.NullTime    `json:"registry_fetched_at"`
+	DentistIds                 json.RawMessage `json:"dentist_ids"`
+	BankAccounts               json.RawMessage `json:"bank_accounts"`
+}
+
+func (q *Queries) GetClinicAggregate(ctx context.Context, arg GetClinicAggregateParams) (GetClinicAggregateRow, error) {
+	row := q.db.QueryRow(ctx, getClinicAggregate, arg.IncludeBankAccounts, arg.ID, arg.OrganizationID)
+	var i GetClinicAggregateRow
+	err := row.Scan(
+		&i.ClinicID,
+		&i.PersonID,
+		&i.ParentClinicID,
+		&i.Timezone,
+		&i.OnboardingStatus,
+		&i.OnboardingStatusChangedAt,
+		&i.DeactivatedAt,
+		&i.DeactivationReason,
+		&i.LegalName,
+		&i.TradeName,
+		&i.TaxIDNumber,
+		&i.Email,
+		&i.Phone,
+		&i.AddressStreet,
+		&i.AddressNumber,
+		&i.AddressComplement,
+		&i.AddressCity,
+		&i.AddressState,
+		&i.AddressCep,
+		&i.RegistryLegalName,
+		&i.RegistryTradeName,
+		&i.RegistryCnaeCode,
+		&i.RegistryCnaeDescription,
+		&i.RegistryRegistrationStatus,
+		&i.RegistrySource,
+		&i.RegistryFetchedAt,
+		&i.DentistIds,
+		&i.BankAccounts,
+	)
+	return i, err
 }
 
 const getClinicByID = `-- name: GetClinicByID :one
-SELECT id, person_id, created_at, updated_at, deleted_at
+SELECT id, organization_id, person_id, parent_clinic_id, timezone, onboarding_status, onboarding_status_changed_at, deactivated_at, deactivation_reason, created_at, updated_at, deleted_at
 FROM clinics
 WHERE id = $1::uuid
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
 LIMIT 1
 `
 
-func (q *Queries) GetClinicByID(ctx context.Context, id string) (Clinic, error) {
-	row := q.db.QueryRowContext(ctx, getClinicByID, id)
+type GetClinicByIDParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) GetClinicByID(ctx context.Context, arg GetClinicByIDParams) (Clinic, error) {
+	row := q.db.QueryRow(ctx, getClinicByID, arg.ID, arg.OrganizationID)
 	var i Clinic
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.PersonID,
+		&i.ParentClinicID,
+		&i.Timezone,
+		&i.OnboardingStatus,
+		&i.OnboardingStatusChangedAt,
+		&i.DeactivatedAt,
+		&i.DeactivationReason,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -73,10 +308,81 @@ func (q *Queries) GetClinicByID(ctx context.Context, id string) (Clinic, error)
 	return i, err
 }
 
+const getClinicDeletePreviewCounts = `-- name: GetClinicDeletePreviewCounts :one
+SELECT
+    (
+        SELECT COUNT(*)
+        FROM clinic_dentists cd
+        WHERE cd.clinic_id = $1::uuid
+          AND cd.organization_id = $2::uuid
+          AND cd.ended_at IS NULL
+    )::bigint AS dentist_links,
+    (
+        SELECT COUNT(*)
+        FROM bank_accounts ba
+        WHERE ba.clinic_id = $1::uuid
+          AND ba.organization_id = $2::uuid
+          AND ba.deleted_at IS NULL
+    )::bigint AS bank_accounts,
+    (
+        SELECT COUNT(*)
+        FROM clinics b
+        WHERE b.parent_clinic_id = $1::uuid
+          AND b.organization_id = $2::uuid
+          AND b.deleted_at IS NULL
+    )::bigint AS active_branches,
+    (
+        SELECT COUNT(*)
+        FROM ledger_transactions lt
+        WHERE lt.clinic_id = $1::uuid
+          AND lt.organization_id = $2::uuid
+          AND lt.kind = 'INVOICE'
+    )::bigint AS invoices,
+    (
+        SELECT COUNT(*)
+        FROM clinic_payables cp
+        WHERE cp.clinic_id = $1::uuid
+          AND cp.organization_id = $2::uuid
+          AND cp.payout_batch_id IS NULL
+    )::bigint AS open_payables
+`
+
+type GetClinicDeletePreviewCountsParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+type GetClinicDeletePreviewCountsRow struct {
+	DentistLinks   int64 `json:"dentist_links"`
+	BankAccounts   int64 `json:"bank_accounts"`
+	ActiveBranches int64 `json:"active_branches"`
+	Invoices       int64 `json:"invoices"`
+	OpenPayables   int64 `json:"open_payables"`
+}
+
+func (q *Queries) GetClinicDeletePreviewCounts(ctx context.Context, arg GetClinicDeletePreviewCountsParams) (GetClinicDeletePreviewCountsRow, error) {
+	row := q.db.QueryRow(ctx, getClinicDeletePreviewCounts, arg.ClinicID, arg.OrganizationID)
+	var i GetClinicDeletePreviewCountsRow
+	err := row.Scan(
+		&i.DentistLinks,
+		&i.BankAccounts,
+		&i.ActiveBranches,
+		&i.Invoices,
+		&i.OpenPayables,
+	)
+	return i, err
+}
+
 const getClinicDetails = `-- name: GetClinicDetails :one
 SELECT
     c.id AS clinic_id,
     c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
     p.legal_name,
     p.trade_name,
     p.tax_id_number,
@@ -85,27 +391,45 @@ SELECT
 FROM clinics c
 JOIN people p ON p.id = c.person_id
 WHERE c.id = $1::uuid
+  AND c.organization_id = $2::uuid
   AND c.deleted_at IS NULL
   AND p.deleted_at IS NULL
 LIMIT 1
 `
 
+type GetClinicDetailsParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
 type GetClinicDetailsRow struct {
-	ClinicID    string         `json:"clinic_id"`
-	PersonID    string         `json:"person_id"`
-	LegalName   string         `json:"legal_name"`
-	TradeName   sql.NullString `json:"trade_name"`
-	TaxIDNumber string         `json:"tax_id_number"`
-	Email       sql.NullString `json:"email"`
-	Phone       sql.NullString `json:"phone"`
+	ClinicID                  string         `json:"clinic_id"`
+	PersonID                  string         `json:"person_id"`
+	ParentClinicID            uuid.NullUUID  `json:"parent_clinic_id"`
+	Timezone                  string         `json:"timezone"`
+	OnboardingStatus          string         `json:"onboarding_status"`
+	OnboardingStatusChangedAt time.Time      `json:"onboarding_status_changed_at"`
+	DeactivatedAt             sql.NullTime   `json:"deactivated_at"`
+	DeactivationReason        sql.NullString `json:"deactivation_reason"`
+	LegalName                 string         `json:"legal_name"`
+	TradeName                 sql.NullString `json:"trade_name"`
+	TaxIDNumber               string         `json:"tax_id_number"`
+	Email                     sql.NullString `json:"email"`
+	Phone                     sql.NullString `json:"phone"`
 }
 
-func (q *Queries) GetClinicDetails(ctx context.Context, id string) (GetClinicDetailsRow, error) {
-	row := q.db.QueryRowContext(ctx, getClinicDetails, id)
+func (q *Queries) GetClinicDetails(ctx context.Context, arg GetClinicDetailsParams) (GetClinicDetailsRow, error) {
+	row := q.db.QueryRow(ctx, getClinicDetails, arg.ID, arg.OrganizationID)
 	var i GetClinicDetailsRow
 	err := row.Scan(
 		&i.ClinicID,
 		&i.PersonID,
+		&i.ParentClinicID,
+		&i.Timezone,
+		&i.OnboardingStatus,
+		&i.OnboardingStatusChangedAt,
+		&i.DeactivatedAt,
+		&i.DeactivationReason,
 		&i.LegalName,
 		&i.TradeName,
 		&i.TaxIDNumber,
@@ -115,41 +439,227 @@ func (q *Queries) GetClinicDetails(ctx context.Context, id string) (GetClinicDet
 	return i, err
 }
 
-const listClinicDetailsCursor = `-- name: ListClinicDetailsCursor :many
+const getClinicDetailsIncludingDeleted = `-- name: GetClinicDetailsIncludingDeleted :one
 SELECT
     c.id AS clinic_id,
     c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
     p.legal_name,
     p.trade_name,
     p.tax_id_number,
     p.email,
-    p.phone
+    p.phone,
+    c.deleted_at
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+WHERE c.id = $1::uuid
+  AND c.organization_id = $2::uuid
+LIMIT 1
+`
+
+type GetClinicDetailsIncludingDeletedParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+type GetClinicDetailsIncludingDeletedRow struct {
+	ClinicID                  string         `json:"clinic_id"`
+	PersonID                  string         `json:"person_id"`
+	ParentClinicID            uuid.NullUUID  `json:"parent_clinic_id"`
+	Timezone                  string         `json:"timezone"`
+	OnboardingStatus          string         `json:"onboarding_status"`
+	OnboardingStatusChangedAt time.Time      `json:"onboarding_status_changed_at"`
+	DeactivatedAt             sql.NullTime   `json:"deactivated_at"`
+	DeactivationReason        sql.NullString `json:"deactivation_reason"`
+	LegalName                 string         `json:"legal_name"`
+	TradeName                 sql.NullString `json:"trade_name"`
+	TaxIDNumber               string         `json:"tax_id_number"`
+	Email                     sql.NullString `json:"email"`
+	Phone                     sql.NullString `json:"phone"`
+	DeletedAt                 sql.NullTime   `json:"deleted_at"`
+}
+
+func (q *Queries) GetClinicDetailsIncludingDeleted(ctx context.Context, arg GetClinicDetailsIncludingDeletedParams) (GetClinicDetailsIncludingDeletedRow, error) {
+	row := q.db.QueryRow(ctx, getClinicDetailsIncludingDeleted, arg.ID, arg.OrganizationID)
+	var i GetClinicDetailsIncludingDeletedRow
+	err := row.Scan(
+		&i.ClinicID,
+		&i.PersonID,
+		&i.ParentClinicID,
+		&i.Timezone,
+		&i.OnboardingStatus,
+		&i.OnboardingStatusChangedAt,
+		&i.DeactivatedAt,
+		&i.DeactivationReason,
+		&i.LegalName,
+		&i.TradeName,
+		&i.TaxIDNumber,
+		&i.Email,
+		&i.Phone,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const listBranchClinicDetails = `-- name: ListBranchClinicDetails :many
+SELECT
+    c.id AS clinic_id,
+    c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
+    p.legal_name,
+    p.trade_name,
+    p.tax_id_number,
+    p.email,
+    p.phone,
+    c.deleted_at,
+    c.created_at
 FROM clinics c
 JOIN people p ON p.id = c.person_id
-WHERE c.deleted_at IS NULL
+WHERE c.parent_clinic_id = $1::uuid
+  AND c.organization_id = $2::uuid
+  AND c.deleted_at IS NULL
   AND p.deleted_at IS NULL
-  AND ($1::uuid IS NULL OR c.id > $1::uuid)
 ORDER BY c.id
-LIMIT $2
+`
+
+type ListBranchClinicDetailsParams struct {
+	ParentClinicID string `json:"parent_clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+type ListBranchClinicDetailsRow struct {
+	ClinicID                  string         `json:"clinic_id"`
+	PersonID                  string         `json:"person_id"`
+	ParentClinicID            uuid.NullUUID  `json:"parent_clinic_id"`
+	Timezone                  string         `json:"timezone"`
+	OnboardingStatus          string         `json:"onboarding_status"`
+	OnboardingStatusChangedAt time.Time      `json:"onboarding_status_changed_at"`
+	DeactivatedAt             sql.NullTime   `json:"deactivated_at"`
+	DeactivationReason        sql.NullString `json:"deactivation_reason"`
+	LegalName                 string         `json:"legal_name"`
+	TradeName                 sql.NullString `json:"trade_name"`
+	TaxIDNumber               string         `json:"tax_id_number"`
+	Email                     sql.NullString `json:"email"`
+	Phone                     sql.NullString `json:"phone"`
+	DeletedAt                 sql.NullTime   `json:"deleted_at"`
+	CreatedAt                 time.Time      `json:"created_at"`
+}
+
+func (q *Queries) ListBranchClinicDetails(ctx context.Context, arg ListBranchClinicDetailsParams) ([]ListBranchClinicDetailsRow, error) {
+	rows, err := q.db.Query(ctx, listBranchClinicDetails, arg.ParentClinicID, arg.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListBranchClinicDetailsRow{}
+	for rows.Next() {
+		var i ListBranchClinicDetailsRow
+		if err := rows.Scan(
+			&i.ClinicID,
+			&i.PersonID,
+			&i.ParentClinicID,
+			&i.Timezone,
+			&i.OnboardingStatus,
+			&i.OnboardingStatusChangedAt,
+			&i.DeactivatedAt,
+			&i.DeactivationReason,
+			&i.LegalName,
+			&i.TradeName,
+			&i.TaxIDNumber,
+			&i.Email,
+			&i.Phone,
+			&i.DeletedAt,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClinicDetailsCursor = `-- name: ListClinicDetailsCursor :many
+SELECT
+    c.id AS clinic_id,
+    c.person_id,
+    c.parent_clinic_id,
+    c.timezone,
+    c.onboarding_status,
+    c.onboarding_status_changed_at,
+    c.deactivated_at,
+    c.deactivation_reason,
+    p.legal_name,
+    p.trade_name,
+    p.tax_id_number,
+    p.email,
+    p.phone,
+    c.deleted_at,
+    c.created_at
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+WHERE c.organization_id = $1::uuid
+  AND ($2::boolean OR (c.deleted_at IS NULL AND p.deleted_at IS NULL))
+  AND (
+      $3::uuid IS NULL
+      OR (c.created_at, c.id) > ($4::timestamptz, $3::uuid)
+  )
+  AND ($5::text IS NULL OR c.onboarding_status = $5::text)
+  AND ($6::timestamptz IS NULL OR c.onboarding_status_changed_at < $6::timestamptz)
+ORDER BY c.created_at, c.id
+LIMIT $7
 `
 
 type ListClinicDetailsCursorParams struct {
-	AfterID   uuid.NullUUID `json:"after_id"`
-	PageLimit int32         `json:"page_limit"`
+	OrganizationID      string         `json:"organization_id"`
+	IncludeDeleted      bool           `json:"include_deleted"`
+	CursorID            uuid.NullUUID  `json:"cursor_id"`
+	CursorCreatedAt     sql.NullTime   `json:"cursor_created_at"`
+	OnboardingStatus    sql.NullString `json:"onboarding_status"`
+	StatusChangedBefore sql.NullTime   `json:"status_changed_before"`
+	PageLimit           int32          `json:"page_limit"`
 }
 
 type ListClinicDetailsCursorRow struct {
-	ClinicID    string         `json:"clinic_id"`
-	PersonID    string         `json:"person_id"`
-	LegalName   string         `json:"legal_name"`
-	TradeName   sql.NullString `json:"trade_name"`
-	TaxIDNumber string         `json:"tax_id_number"`
-	Email       sql.NullString `json:"email"`
-	Phone       sql.NullString `json:"phone"`
+	ClinicID                  string         `json:"clinic_id"`
+	PersonID                  string         `json:"person_id"`
+	ParentClinicID            uuid.NullUUID  `json:"parent_clinic_id"`
+	Timezone                  string         `json:"timezone"`
+	OnboardingStatus          string         `json:"onboarding_status"`
+	OnboardingStatusChangedAt time.Time      `json:"onboarding_status_changed_at"`
+	DeactivatedAt             sql.NullTime   `json:"deactivated_at"`
+	DeactivationReason        sql.NullString `json:"deactivation_reason"`
+	LegalName                 string         `json:"legal_name"`
+	TradeName                 sql.NullString `json:"trade_name"`
+	TaxIDNumber               string         `json:"tax_id_number"`
+	Email                     sql.NullString `json:"email"`
+	Phone                     sql.NullString `json:"phone"`
+	DeletedAt                 sql.NullTime   `json:"deleted_at"`
+	CreatedAt                 time.Time      `json:"created_at"`
 }
 
 func (q *Queries) ListClinicDetailsCursor(ctx context.Context, arg ListClinicDetailsCursorParams) ([]ListClinicDetailsCursorRow, error) {
-	rows, err := q.db.QueryContext(ctx, listClinicDetailsCursor, arg.AfterID, arg.PageLimit)
+	rows, err := q.db.Query(ctx, listClinicDetailsCursor,
+		arg.OrganizationID,
+		arg.IncludeDeleted,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.OnboardingStatus,
+		arg.StatusChangedBefore,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -160,19 +670,170 @@ func (q *Queries) ListClinicDetailsCursor(ctx context.Context, arg ListClinicDet
 		if err := rows.Scan(
 			&i.ClinicID,
 			&i.PersonID,
+			&i.ParentClinicID,
+			&i.Timezone,
+			&i.OnboardingStatus,
+			&i.OnboardingStatusChangedAt,
+			&i.DeactivatedAt,
+			&i.DeactivationReason,
 			&i.LegalName,
 			&i.TradeName,
 			&i.TaxIDNumber,
 			&i.Email,
 			&i.Phone,
+			&i.DeletedAt,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClinicDuplicateCandidates = `-- name: ListClinicDuplicateCandidates :many
+SELECT
+    c.id AS clinic_id,
+    p.legal_name,
+    p.tax_id_number,
+    c.deleted_at,
+    similarity(p.legal_name, $1::text)::float8 AS similarity
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+WHERE c.organization_id = $2::uuid
+  AND (
+    (c.deleted_at IS NOT NULL AND p.tax_id_number = $3::text)
+    OR (
+        c.deleted_at IS NULL
+        AND p.deleted_at IS NULL
+        AND p.legal_name % $1::text
+        AND similarity(p.legal_name, $1::text) >= $4::float8
+        AND (
+            $5::uuid IS NULL
+            OR (c.id <> $5::uuid AND c.parent_clinic_id IS DISTINCT FROM $5::uuid)
+        )
+    )
+  )
+ORDER BY similarity DESC, c.id
+LIMIT $6::int
+`
+
+type ListClinicDuplicateCandidatesParams struct {
+	LegalName      string        `json:"legal_name"`
+	OrganizationID string        `json:"organization_id"`
+	TaxIDNumber    string        `json:"tax_id_number"`
+	MinSimilarity  float64       `json:"min_similarity"`
+	GroupClinicID  uuid.NullUUID `json:"group_clinic_id"`
+	MaxCandidates  int32         `json:"max_candidates"`
+}
+
+type ListClinicDuplicateCandidatesRow struct {
+	ClinicID    string       `json:"clinic_id"`
+	LegalName   string       `json:"legal_name"`
+	TaxIDNumber string       `json:"tax_id_number"`
+	DeletedAt   sql.NullTime `json:"deleted_at"`
+	Similarity  float64      `json:"similarity"`
+}
+
+func (q *Queries) ListClinicDuplicateCandidates(ctx context.Context, arg ListClinicDuplicateCandidatesParams) ([]ListClinicDuplicateCandidatesRow, error) {
+	rows, err := q.db.Query(ctx, listClinicDuplicateCandidates,
+		arg.LegalName,
+		arg.OrganizationID,
+		arg.TaxIDNumber,
+		arg.MinSimilarity,
+		arg.GroupClinicID,
+		arg.MaxCandidates,
+	)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+	items := []ListClinicDuplicateCandidatesRow{}
+	for rows.Next() {
+		var i ListClinicDuplicateCandidatesRow
+		if err := rows.Scan(
+			&i.ClinicID,
+			&i.LegalName,
+			&i.TaxIDNumber,
+			&i.DeletedAt,
+			&i.Similarity,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listClinicGroupReport = `-- name: ListClinicGroupReport :many
+SELECT
+    c.id AS clinic_id,
+    c.parent_clinic_id,
+    p.legal_name,
+    p.trade_name,
+    p.tax_id_number,
+    COUNT(cd.dentist_id)::int AS active_dentists,
+    COUNT(cd.dentist_id) FILTER (WHERE cd.is_admin)::int AS admins,
+    COUNT(cd.dentist_id) FILTER (WHERE cd.is_legal_representative)::int AS legal_representatives,
+    COUNT(cd.dentist_id) FILTER (WHERE cd.planned_end_at IS NOT NULL)::int AS temporary_dentists
+FROM clinics c
+JOIN people p ON p.id = c.person_id
+LEFT JOIN clinic_dentists cd ON cd.clinic_id = c.id AND cd.ended_at IS NULL
+WHERE c.organization_id = $1::uuid
+  AND (c.id = $2::uuid OR c.parent_clinic_id = $2::uuid)
+  AND c.deleted_at IS NULL
+  AND p.deleted_at IS NULL
+GROUP BY c.id, c.parent_clinic_id, p.legal_name, p.trade_name, p.tax_id_number
+ORDER BY c.parent_clinic_id NULLS FIRST, c.id
+`
+
+type ListClinicGroupReportParams struct {
+	OrganizationID string `json:"organization_id"`
+	GroupClinicID  string `json:"group_clinic_id"`
+}
+
+type ListClinicGroupReportRow struct {
+	ClinicID             string         `json:"clinic_id"`
+	ParentClinicID       uuid.NullUUID  `json:"parent_clinic_id"`
+	LegalName            string         `json:"legal_name"`
+	TradeName            sql.NullString `json:"trade_name"`
+	TaxIDNumber          string         `json:"tax_id_number"`
+	ActiveDentists       int32          `json:"active_dentists"`
+	Admins               int32          `json:"admins"`
+	LegalRepresentatives int32          `json:"legal_representatives"`
+	TemporaryDentists    int32          `json:"temporary_dentists"`
+}
+
+func (q *Queries) ListClinicGroupReport(ctx context.Context, arg ListClinicGroupReportParams) ([]ListClinicGroupReportRow, error) {
+	rows, err := q.db.Query(ctx, listClinicGroupReport, arg.OrganizationID, arg.GroupClinicID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListClinicGroupReportRow{}
+	for rows.Next() {
+		var i ListClinicGroupReportRow
+		if err := rows.Scan(
+			&i.ClinicID,
+			&i.ParentClinicID,
+			&i.LegalName,
+			&i.TradeName,
+			&i.TaxIDNumber,
+			&i.ActiveDentists,
+			&i.Admins,
+			&i.LegalRepresentatives,
+			&i.TemporaryDentists,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -183,12 +844,153 @@ const lockClinicForUpdate = `-- name: LockClinicForUpdate :one
 SELECT id
 FROM clinics
 WHERE id = $1::uuid
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
 FOR UPDATE
 `
 
-func (q *Queries) LockClinicForUpdate(ctx context.Context, id string) (string, error) {
-	row := q.db.QueryRowContext(ctx, lockClinicForUpdate, id)
+type LockClinicForUpdateParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) LockClinicForUpdate(ctx context.Context, arg LockClinicForUpdateParams) (string, error) {
+	row := q.db.QueryRow(ctx, lockClinicForUpdate, arg.ID, arg.OrganizationID)
+	var id string
 	err := row.Scan(&id)
 	return id, err
 }
+
+const lockDeletedClinicForUpdate = `-- name: LockDeletedClinicForUpdate :one
+SELECT id, organization_id, person_id, parent_clinic_id, timezone, onboarding_status, onboarding_status_changed_at, deactivated_at, deactivation_reason, created_at, updated_at, deleted_at
+FROM clinics
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NOT NULL
+FOR UPDATE
+`
+
+type LockDeletedClinicForUpdateParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) LockDeletedClinicForUpdate(ctx context.Context, arg LockDeletedClinicForUpdateParams) (Clinic, error) {
+	row := q.db.QueryRow(ctx, lockDeletedClinicForUpdate, arg.ID, arg.OrganizationID)
+	var i Clinic
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.PersonID,
+		&i.ParentClinicID,
+		&i.Timezone,
+		&i.OnboardingStatus,
+		&i.OnboardingStatusChangedAt,
+		&i.DeactivatedAt,
+		&i.DeactivationReason,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const reactivateClinic = `-- name: ReactivateClinic :execrows
+UPDATE clinics
+SET deactivated_at = NULL,
+    deactivation_reason = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deactivated_at IS NOT NULL
+  AND deleted_at IS NULL
+`
+
+type ReactivateClinicParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) ReactivateClinic(ctx context.Context, arg ReactivateClinicParams) (int64, error) {
+	result, err := q.db.Exec(ctx, reactivateClinic, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreClinic = `-- name: RestoreClinic :execrows
+UPDATE clinics
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NOT NULL
+`
+
+type RestoreClinicParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) RestoreClinic(ctx context.Context, arg RestoreClinicParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreClinic, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateClinicOnboardingStatus = `-- name: UpdateClinicOnboardingStatus :execrows
+UPDATE clinics
+SET onboarding_status = $1,
+    onboarding_status_changed_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2::uuid
+  AND organization_id = $3::uuid
+  AND onboarding_status = $4
+  AND deleted_at IS NULL
+`
+
+type UpdateClinicOnboardingStatusParams struct {
+	ToStatus       string `json:"to_status"`
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+	FromStatus     string `json:"from_status"`
+}
+
+func (q *Queries) UpdateClinicOnboardingStatus(ctx context.Context, arg UpdateClinicOnboardingStatusParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateClinicOnboardingStatus,
+		arg.ToStatus,
+		arg.ID,
+		arg.OrganizationID,
+		arg.FromStatus,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateClinicTimezone = `-- name: UpdateClinicTimezone :execrows
+UPDATE clinics
+SET timezone = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2::uuid
+  AND organization_id = $3::uuid
+  AND deleted_at IS NULL
+`
+
+type UpdateClinicTimezoneParams struct {
+	Timezone       string `json:"timezone"`
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) UpdateClinicTimezone(ctx context.Context, arg UpdateClinicTimezoneParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateClinicTimezone, arg.Timezone, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -11,26 +11,48 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/lib/pq"
 )
 
 const createDentist = `-- name: CreateDentist :one
-INSERT INTO dentists (id, person_id)
-VALUES ($1::uuid, $2::uuid)
-RETURNING id, person_id, created_at, updated_at, deleted_at
+INSERT INTO dentists (id, organization_id, person_id, cro_number, cro_state)
+VALUES (
+    $1::uuid,
+    $2::uuid,
+    $3::uuid,
+    $4,
+    $5
+)
+RETURNING id, organization_id, person_id, cro_number, cro_state, photo_key, photo_size_bytes, photo_updated_at, photo_thumbnail_key, photo_thumbnail_updated_at, created_at, updated_at, deleted_at
 `
 
 type CreateDentistParams struct {
-	ID       string `json:"id"`
-	PersonID string `json:"person_id"`
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+	PersonID       string         `json:"person_id"`
+	CroNumber      sql.NullString `json:"cro_number"`
+	CroState       sql.NullString `json:"cro_state"`
 }
 
 func (q *Queries) CreateDentist(ctx context.Context, arg CreateDentistParams) (Dentist, error) {
-	row := q.db.QueryRowContext(ctx, createDentist, arg.ID, arg.PersonID)
+	row := q.db.QueryRow(ctx, createDentist,
+		arg.ID,
+		arg.OrganizationID,
+		arg.PersonID,
+		arg.CroNumber,
+		arg.CroState,
+	)
 	var i Dentist
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoKey,
+		&i.PhotoSizeBytes,
+		&i.PhotoUpdatedAt,
+		&i.PhotoThumbnailKey,
+		&i.PhotoThumbnailUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -43,31 +65,106 @@ UPDATE dentists
 SET deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1::uuid
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
 `
 
-func (q *Queries) DeleteDentist(ctx context.Context, id string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deleteDentist, id)
+type DeleteDentistParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) DeleteDentist(ctx context.Context, arg DeleteDentistParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDentist, arg.ID, arg.OrganizationID)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return result.RowsAffected(), nil
+}
+
+const existsOtherActiveDentistByCRO = `-- name: ExistsOtherActiveDentistByCRO :one
+SELECT EXISTS (
+    SELECT 1
+    FROM dentists
+    WHERE cro_state = $1
+      AND organization_id = $2::uuid
+      AND cro_number = $3
+      AND id <> $4::uuid
+      AND deleted_at IS NULL
+)
+`
+
+type ExistsOtherActiveDentistByCROParams struct {
+	CroState       sql.NullString `json:"cro_state"`
+	OrganizationID string         `json:"organization_id"`
+	CroNumber      sql.NullString `json:"cro_number"`
+	DentistID      string         `json:"dentist_id"`
+}
+
+func (q *Queries) ExistsOtherActiveDentistByCRO(ctx context.Context, arg ExistsOtherActiveDentistByCROParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsOtherActiveDentistByCRO,
+		arg.CroState,
+		arg.OrganizationID,
+		arg.CroNumber,
+		arg.DentistID,
+	)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const existsOtherActiveDentistByPersonID = `-- name: ExistsOtherActiveDentistByPersonID :one
+SELECT EXISTS (
+    SELECT 1
+    FROM dentists
+    WHERE person_id = $1::uuid
+      AND organization_id = $2::uuid
+      AND id <> $3::uuid
+      AND deleted_at IS NULL
+)
+`
+
+type ExistsOtherActiveDentistByPersonIDParams struct {
+	PersonID       string `json:"person_id"`
+	OrganizationID string `json:"organization_id"`
+	DentistID      string `json:"dentist_id"`
+}
+
+func (q *Queries) ExistsOtherActiveDentistByPersonID(ctx context.Context, arg ExistsOtherActiveDentistByPersonIDParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsOtherActiveDentistByPersonID, arg.PersonID, arg.OrganizationID, arg.DentistID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
 }
 
 const getDentistByID = `-- name: GetDentistByID :one
-SELECT id, person_id, created_at, updated_at, deleted_at
+SELECT id, organization_id, person_id, cro_number, cro_state, photo_key, photo_size_bytes, photo_updated_at, photo_thumbnail_key, photo_thumbnail_updated_at, created_at, updated_at, deleted_at
 FROM dentists
 WHERE id = $1::uuid
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
 LIMIT 1
 `
 
-func (q *Queries) GetDentistByID(ctx context.Context, id string) (Dentist, error) {
-	row := q.db.QueryRowContext(ctx, getDentistByID, id)
+type GetDentistByIDParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) GetDentistByID(ctx context.Context, arg GetDentistByIDParams) (Dentist, error) {
+	row := q.db.QueryRow(ctx, getDentistByID, arg.ID, arg.OrganizationID)
 	var i Dentist
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoKey,
+		&i.PhotoSizeBytes,
+		&i.PhotoUpdatedAt,
+		&i.PhotoThumbnailKey,
+		&i.PhotoThumbnailUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -76,19 +173,33 @@ func (q *Queries) GetDentistByID(ctx context.Context, id string) (Dentist, error
 }
 
 const getDentistByPersonID = `-- name: GetDentistByPersonID :one
-SELECT id, person_id, created_at, updated_at, deleted_at
+SELECT id, organization_id, person_id, cro_number, cro_state, photo_key, photo_size_bytes, photo_updated_at, photo_thumbnail_key, photo_thumbnail_updated_at, created_at, updated_at, deleted_at
 FROM dentists
 WHERE person_id = $1::uuid
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
 LIMIT 1
 `
 
-func (q *Queries) GetDentistByPersonID(ctx context.Context, personID string) (Dentist, error) {
-	row := q.db.QueryRowContext(ctx, getDentistByPersonID, personID)
+type GetDentistByPersonIDParams struct {
+	PersonID       string `json:"person_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) GetDentistByPersonID(ctx context.Context, arg GetDentistByPersonIDParams) (Dentist, error) {
+	row := q.db.QueryRow(ctx, getDentistByPersonID, arg.PersonID, arg.OrganizationID)
 	var i Dentist
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoKey,
+		&i.PhotoSizeBytes,
+		&i.PhotoUpdatedAt,
+		&i.PhotoThumbnailKey,
+		&i.PhotoThumbnailUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -100,6 +211,9 @@ const getDentistDetailsByID = `-- name: GetDentistDetailsByID :one
 SELECT
     d.id AS dentist_id,
     d.person_id,
+    d.cro_number,
+    d.cro_state,
+    d.photo_updated_at,
     p.legal_name,
     p.tax_id_number,
     p.email,
@@ -107,38 +221,122 @@ SELECT
 FROM dentists d
 JOIN people p ON p.id = d.person_id
 WHERE d.id = $1::uuid
+  AND d.organization_id = $2::uuid
   AND d.deleted_at IS NULL
   AND p.deleted_at IS NULL
 LIMIT 1
 `
 
+type GetDentistDetailsByIDParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
 type GetDentistDetailsByIDRow struct {
-	DentistID   string         `json:"dentist_id"`
-	PersonID    string         `json:"person_id"`
-	LegalName   string         `json:"legal_name"`
-	TaxIDNumber string         `json:"tax_id_number"`
-	Email       sql.NullString `json:"email"`
-	Phone       sql.NullString `json:"phone"`
+	DentistID      string         `json:"dentist_id"`
+	PersonID       string         `json:"person_id"`
+	CroNumber      sql.NullString `json:"cro_number"`
+	CroState       sql.NullString `json:"cro_state"`
+	PhotoUpdatedAt sql.NullTime   `json:"photo_updated_at"`
+	LegalName      string         `json:"legal_name"`
+	TaxIDNumber    string         `json:"tax_id_number"`
+	Email          sql.NullString `json:"email"`
+	Phone          sql.NullString `json:"phone"`
 }
 
-func (q *Queries) GetDentistDetailsByID(ctx context.Context, id string) (GetDentistDetailsByIDRow, error) {
-	row := q.db.QueryRowContext(ctx, getDentistDetailsByID, id)
+func (q *Queries) GetDentistDetailsByID(ctx context.Context, arg GetDentistDetailsByIDParams) (GetDentistDetailsByIDRow, error) {
+	row := q.db.QueryRow(ctx, getDentistDetailsByID, arg.ID, arg.OrganizationID)
 	var i GetDentistDetailsByIDRow
 	err := row.Scan(
 		&i.DentistID,
 		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoUpdatedAt,
+		&i.LegalName,
+		&i.TaxIDNumber,
+		&i.Email,
+		&i.Phone,
+	)
+	return i, err
+}
+
+const getDentistDetailsIncludingDeleted = `-- name: GetDentistDetailsIncludingDeleted :one
+SELECT
+    d.id AS dentist_id,
+    d.person_id,
+    d.cro_number,
+    d.cro_state,
+    d.photo_updated_at,
+    p.legal_name,
+    p.tax_id_number,
+    p.email,
+    p.phone,
+    d.deleted_at
+FROM dentists d
+JOIN people p ON p.id = d.person_id
+WHERE d.id = $1::uuid
+  AND d.organization_id = $2::uuid
+LIMIT 1
+`
+
+type GetDentistDetailsIncludingDeletedParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+type GetDentistDetailsIncludingDeletedRow struct {
+	DentistID      string         `json:"dentist_id"`
+	PersonID       string         `json:"person_id"`
+	CroNumber      sql.NullString `json:"cro_number"`
+	CroState       sql.NullString `json:"cro_state"`
+	PhotoUpdatedAt sql.NullTime   `json:"photo_updated_at"`
+	LegalName      string         `json:"legal_name"`
+	TaxIDNumber    string         `json:"tax_id_number"`
+	Email          sql.NullString `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	DeletedAt      sql.NullTime   `json:"deleted_at"`
+}
+
+func (q *Queries) GetDentistDetailsIncludingDeleted(ctx context.Context, arg GetDentistDetailsIncludingDeletedParams) (GetDentistDetailsIncludingDeletedRow, error) {
+	row := q.db.QueryRow(ctx, getDentistDetailsIncludingDeleted, arg.ID, arg.OrganizationID)
+	var i GetDentistDetailsIncludingDeletedRow
+	err := row.Scan(
+		&i.DentistID,
+		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoUpdatedAt,
 		&i.LegalName,
 		&i.TaxIDNumber,
 		&i.Email,
 		&i.Phone,
+		&i.DeletedAt,
 	)
 	return i, err
 }
 
+const getDentistOrganizationID = `-- name: GetDentistOrganizationID :one
+SELECT organization_id
+FROM dentists
+WHERE id = $1::uuid
+  AND deleted_at IS NULL
+LIMIT 1
+`
+
+func (q *Queries) GetDentistOrganizationID(ctx context.Context, id string) (string, error) {
+	row := q.db.QueryRow(ctx, getDentistOrganizationID, id)
+	var organization_id string
+	err := row.Scan(&organization_id)
+	return organization_id, err
+}
+
 const listDentistsByClinicID = `-- name: ListDentistsByClinicID :many
 SELECT
     d.id AS dentist_id,
     d.person_id,
+    d.cro_number,
+    d.cro_state,
     p.legal_name,
     p.tax_id_number,
     p.email,
@@ -146,12 +344,15 @@ SELECT
     cd.is_admin,
     cd.is_legal_representative,
     cd.started_at,
-    cd.ended_at
+    cd.ended_at,
+    cd.planned_end_at,
+    cd.substitute_for_dentist_id
 FROM clinic_dentists cd
 JOIN dentists d ON d.id = cd.dentist_id
 JOIN people p ON p.id = d.person_id
 JOIN clinics c ON c.id = cd.clinic_id
 WHERE cd.clinic_id = $1::uuid
+  AND cd.organization_id = $2::uuid
   AND cd.ended_at IS NULL
   AND d.deleted_at IS NULL
   AND p.deleted_at IS NULL
@@ -159,21 +360,30 @@ WHERE cd.clinic_id = $1::uuid
 ORDER BY d.id
 `
 
+type ListDentistsByClinicIDParams struct {
+	ClinicID       string `json:"clinic_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
 type ListDentistsByClinicIDRow struct {
-	DentistID             string         `json:"dentist_id"`
-	PersonID              string         `json:"person_id"`
-	LegalName             string         `json:"legal_name"`
-	TaxIDNumber           string         `json:"tax_id_number"`
-	Email                 sql.NullString `json:"email"`
-	Phone                 sql.NullString `json:"phone"`
-	IsAdmin               bool           `json:"is_admin"`
-	IsLegalRepresentative bool           `json:"is_legal_representative"`
-	StartedAt             time.Time      `json:"started_at"`
-	EndedAt               sql.NullTime   `json:"ended_at"`
-}
-
-func (q *Queries) ListDentistsByClinicID(ctx context.Context, clinicID string) ([]ListDentistsByClinicIDRow, error) {
-	rows, err := q.db.QueryContext(ctx, listDentistsByClinicID, clinicID)
+	DentistID              string         `json:"dentist_id"`
+	PersonID               string         `json:"person_id"`
+	CroNumber              sql.NullString `json:"cro_number"`
+	CroState               sql.NullString `json:"cro_state"`
+	LegalName              string         `json:"legal_name"`
+	TaxIDNumber            string         `json:"tax_id_number"`
+	Email                  sql.NullString `json:"email"`
+	Phone                  sql.NullString `json:"phone"`
+	IsAdmin                bool           `json:"is_admin"`
+	IsLegalRepresentative  bool           `json:"is_legal_representative"`
+	StartedAt              time.Time      `json:"started_at"`
+	EndedAt                sql.NullTime   `json:"ended_at"`
+	PlannedEndAt           sql.NullTime   `json:"planned_end_at"`
+	SubstituteForDentistID uuid.NullUUID  `json:"substitute_for_dentist_id"`
+}
+
+func (q *Queries) ListDentistsByClinicID(ctx context.Context, arg ListDentistsByClinicIDParams) ([]ListDentistsByClinicIDRow, error) {
+	rows, err := q.db.Query(ctx, listDentistsByClinicID, arg.ClinicID, arg.OrganizationID)
 	if err != nil {
 		return nil, err
 	}
@@ -184,6 +394,8 @@ func (q *Queries) ListDentistsByClinicID(ctx context.Context, clinicID string) (
 		if err := rows.Scan(
 			&i.DentistID,
 			&i.PersonID,
+			&i.CroNumber,
+			&i.CroState,
 			&i.LegalName,
 			&i.TaxIDNumber,
 			&i.Email,
@@ -192,14 +404,13 @@ func (q *Queries) ListDentistsByClinicID(ctx context.Context, clinicID string) (
 			&i.IsLegalRepresentative,
 			&i.StartedAt,
 			&i.EndedAt,
+			&i.PlannedEndAt,
+			&i.SubstituteForDentistID,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -210,6 +421,9 @@ const listDentistsByClinicIDCursor = `-- name: ListDentistsByClinicIDCursor :man
 SELECT
     d.id AS dentist_id,
     d.person_id,
+    d.cro_number,
+    d.cro_state,
+    d.photo_updated_at,
     p.legal_name,
     p.tax_id_number,
     p.email,
@@ -217,42 +431,81 @@ SELECT
     cd.is_admin,
     cd.is_legal_representative,
     cd.started_at,
-    cd.ended_at
+    cd.ended_at,
+    cd.planned_end_at,
+    cd.substitute_for_dentist_id,
+    d.deleted_at,
+    d.created_at
 FROM clinic_dentists cd
 JOIN dentists d ON d.id = cd.dentist_id
 JOIN people p ON p.id = d.person_id
 JOIN clinics c ON c.id = cd.clinic_id
 WHERE cd.clinic_id = $1::uuid
-  AND cd.ended_at IS NULL
-  AND d.deleted_at IS NULL
-  AND p.deleted_at IS NULL
+  AND cd.organization_id = $2::uuid
   AND c.deleted_at IS NULL
-  AND ($2::uuid IS NULL OR d.id > $2::uuid)
-ORDER BY d.id
-LIMIT $3
+  AND (
+      (cd.ended_at IS NULL AND d.deleted_at IS NULL AND p.deleted_at IS NULL)
+      OR ($3::boolean AND d.deleted_at IS NOT NULL AND cd.ended_at = d.deleted_at)
+  )
+  AND (
+      $4::uuid IS NULL
+      OR (d.created_at, d.id) > ($5::timestamptz, $4::uuid)
+  )
+  AND (
+      $6::text IS NULL
+      OR EXISTS (
+          SELECT 1
+          FROM dentist_specialties ds
+          JOIN specialties s ON s.id = ds.specialty_id
+          WHERE ds.dentist_id = d.id
+            AND s.deleted_at IS NULL
+            AND (s.code = $6::text OR s.id::text = $6::text)
+      )
+  )
+ORDER BY d.created_at, d.id
+LIMIT $7
 `
 
 type ListDentistsByClinicIDCursorParams struct {
-	ClinicID       string        `json:"clinic_id"`
-	AfterDentistID uuid.NullUUID `json:"after_dentist_id"`
-	PageLimit      int32         `json:"page_limit"`
+	ClinicID        string         `json:"clinic_id"`
+	OrganizationID  string         `json:"organization_id"`
+	IncludeDeleted  bool           `json:"include_deleted"`
+	CursorID        uuid.NullUUID  `json:"cursor_id"`
+	CursorCreatedAt sql.NullTime   `json:"cursor_created_at"`
+	Specialty       sql.NullString `json:"specialty"`
+	PageLimit       int32          `json:"page_limit"`
 }
 
 type ListDentistsByClinicIDCursorRow struct {
-	DentistID             string         `json:"dentist_id"`
-	PersonID              string         `json:"person_id"`
-	LegalName             string         `json:"legal_name"`
-	TaxIDNumber           string         `json:"tax_id_number"`
-	Email                 sql.NullString `json:"email"`
-	Phone                 sql.NullString `json:"phone"`
-	IsAdmin               bool           `json:"is_admin"`
-	IsLegalRepresentative bool           `json:"is_legal_representative"`
-	StartedAt             time.Time      `json:"started_at"`
-	EndedAt               sql.NullTime   `json:"ended_at"`
+	DentistID              string         `json:"dentist_id"`
+	PersonID               string         `json:"person_id"`
+	CroNumber              sql.NullString `json:"cro_number"`
+	CroState               sql.NullString `json:"cro_state"`
+	PhotoUpdatedAt         sql.NullTime   `json:"photo_updated_at"`
+	LegalName              string         `json:"legal_name"`
+	TaxIDNumber            string         `json:"tax_id_number"`
+	Email                  sql.NullString `json:"email"`
+	Phone                  sql.NullString `json:"phone"`
+	IsAdmin                bool           `json:"is_admin"`
+	IsLegalRepresentative  bool           `json:"is_legal_representative"`
+	StartedAt              time.Time      `json:"started_at"`
+	EndedAt                sql.NullTime   `json:"ended_at"`
+	PlannedEndAt           sql.NullTime   `json:"planned_end_at"`
+	SubstituteForDentistID uuid.NullUUID  `json:"substitute_for_dentist_id"`
+	DeletedAt              sql.NullTime   `json:"deleted_at"`
+	CreatedAt              time.Time      `json:"created_at"`
 }
 
 func (q *Queries) ListDentistsByClinicIDCursor(ctx context.Context, arg ListDentistsByClinicIDCursorParams) ([]ListDentistsByClinicIDCursorRow, error) {
-	rows, err := q.db.QueryContext(ctx, listDentistsByClinicIDCursor, arg.ClinicID, arg.AfterDentistID, arg.PageLimit)
+	rows, err := q.db.Query(ctx, listDentistsByClinicIDCursor,
+		arg.ClinicID,
+		arg.OrganizationID,
+		arg.IncludeDeleted,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Specialty,
+		arg.PageLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -263,6 +516,9 @@ func (q *Queries) ListDentistsByClinicIDCursor(ctx context.Context, arg ListDent
 		if err := rows.Scan(
 			&i.DentistID,
 			&i.PersonID,
+			&i.CroNumber,
+			&i.CroState,
+			&i.PhotoUpdatedAt,
 			&i.LegalName,
 			&i.TaxIDNumber,
 			&i.Email,
@@ -271,14 +527,15 @@ func (q *Queries) ListDentistsByClinicIDCursor(ctx context.Context, arg ListDent
 			&i.IsLegalRepresentative,
 			&i.StartedAt,
 			&i.EndedAt,
+			&i.PlannedEndAt,
+			&i.SubstituteForDentistID,
+			&i.DeletedAt,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
-		return nil, err
-	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
@@ -290,6 +547,8 @@ SELECT
     cd.clinic_id,
     d.id AS dentist_id,
     d.person_id,
+    d.cro_number,
+    d.cro_state,
     p.legal_name,
     p.tax_id_number,
     p.email,
@@ -297,12 +556,15 @@ SELECT
     cd.is_admin,
     cd.is_legal_representative,
     cd.started_at,
-    cd.ended_at
+    cd.ended_at,
+    cd.planned_end_at,
+    cd.substitute_for_dentist_id
 FROM clinic_dentists cd
 JOIN dentists d ON d.id = cd.dentist_id
 JOIN people p ON p.id = d.person_id
 JOIN clinics c ON c.id = cd.clinic_id
 WHERE cd.clinic_id = ANY($1::uuid[])
+  AND cd.organization_id = $2::uuid
   AND cd.ended_at IS NULL
   AND d.deleted_at IS NULL
   AND p.deleted_at IS NULL
@@ -310,22 +572,31 @@ WHERE cd.clinic_id = ANY($1::uuid[])
 ORDER BY cd.clinic_id, d.id
 `
 
+type ListDentistsByClinicIDsParams struct {
+	ClinicIds      []string `json:"clinic_ids"`
+	OrganizationID string   `json:"organization_id"`
+}
+
 type ListDentistsByClinicIDsRow struct {
-	ClinicID              string         `json:"clinic_id"`
-	DentistID             string         `json:"dentist_id"`
-	PersonID              string         `json:"person_id"`
-	LegalName             string         `json:"legal_name"`
-	TaxIDNumber           string         `json:"tax_id_number"`
-	Email                 sql.NullString `json:"email"`
-	Phone                 sql.NullString `json:"phone"`
-	IsAdmin               bool           `json:"is_admin"`
-	IsLegalRepresentative bool           `json:"is_legal_representative"`
-	StartedAt             time.Time      `json:"started_at"`
-	EndedAt               sql.NullTime   `json:"ended_at"`
-}
-
-func (q *Queries) ListDentistsByClinicIDs(ctx context.Context, clinicIds []string) ([]ListDentistsByClinicIDsRow, error) {
-	rows, err := q.db.QueryContext(ctx, listDentistsByClinicIDs, pq.Array(clinicIds))
+	ClinicID               string         `json:"clinic_id"`
+	DentistID              string         `json:"dentist_id"`
+	PersonID               string         `json:"person_id"`
+	CroNumber              sql.NullString `json:"cro_number"`
+	CroState               sql.NullString `json:"cro_state"`
+	LegalName              string         `json:"legal_name"`
+	TaxIDNumber            string         `json:"tax_id_number"`
+	Email                  sql.NullString `json:"email"`
+	Phone                  sql.NullString `json:"phone"`
+	IsAdmin                bool           `json:"is_admin"`
+	IsLegalRepresentative  bool           `json:"is_legal_representative"`
+	StartedAt              time.Time      `json:"started_at"`
+	EndedAt                sql.NullTime   `json:"ended_at"`
+	PlannedEndAt           sql.NullTime   `json:"planned_end_at"`
+	SubstituteForDentistID uuid.NullUUID  `json:"substitute_for_dentist_id"`
+}
+
+func (q *Queries) ListDentistsByClinicIDs(ctx context.Context, arg ListDentistsByClinicIDsParams) ([]ListDentistsByClinicIDsRow, error) {
+	rows, err := q.db.Query(ctx, listDentistsByClinicIDs, arg.ClinicIds, arg.OrganizationID)
 	if err != nil {
 		return nil, err
 	}
@@ -337,6 +608,8 @@ func (q *Queries) ListDentistsByClinicIDs(ctx context.Context, clinicIds []strin
 			&i.ClinicID,
 			&i.DentistID,
 			&i.PersonID,
+			&i.CroNumber,
+			&i.CroState,
 			&i.LegalName,
 			&i.TaxIDNumber,
 			&i.Email,
@@ -345,16 +618,270 @@ func (q *Queries) ListDentistsByClinicIDs(ctx context.Context, clinicIds []strin
 			&i.IsLegalRepresentative,
 			&i.StartedAt,
 			&i.EndedAt,
+			&i.PlannedEndAt,
+			&i.SubstituteForDentistID,
 		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
 	}
-	if err := rows.Close(); err != nil {
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDentistsNeedingPhotoThumbnail = `-- name: ListDentistsNeedingPhotoThumbnail :many
+SELECT id, photo_key::text AS photo_key, photo_updated_at::timestamptz AS photo_updated_at
+FROM dentists
+WHERE organization_id = $1::uuid
+  AND deleted_at IS NULL
+  AND photo_key IS NOT NULL
+  AND photo_updated_at IS NOT NULL
+  AND (photo_thumbnail_updated_at IS NULL OR photo_thumbnail_updated_at < photo_updated_at)
+ORDER BY photo_updated_at, id
+LIMIT $2
+`
+
+type ListDentistsNeedingPhotoThumbnailParams struct {
+	OrganizationID string `json:"organization_id"`
+	PageLimit      int32  `json:"page_limit"`
+}
+
+type ListDentistsNeedingPhotoThumbnailRow struct {
+	ID             string    `json:"id"`
+	PhotoKey       string    `json:"photo_key"`
+	PhotoUpdatedAt time.Time `json:"photo_updated_at"`
+}
+
+func (q *Queries) ListDentistsNeedingPhotoThumbnail(ctx context.Context, arg ListDentistsNeedingPhotoThumbnailParams) ([]ListDentistsNeedingPhotoThumbnailRow, error) {
+	rows, err := q.db.Query(ctx, listDentistsNeedingPhotoThumbnail, arg.OrganizationID, arg.PageLimit)
+	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
+	items := []ListDentistsNeedingPhotoThumbnailRow{}
+	for rows.Next() {
+		var i ListDentistsNeedingPhotoThumbnailRow
+		if err := rows.Scan(&i.ID, &i.PhotoKey, &i.PhotoUpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
 	if err := rows.Err(); err != nil {
 		return nil, err
 	}
 	return items, nil
 }
+
+const lockDeletedDentistForUpdate = `-- name: LockDeletedDentistForUpdate :one
+SELECT id, organization_id, person_id, cro_number, cro_state, photo_key, photo_size_bytes, photo_updated_at, photo_thumbnail_key, photo_thumbnail_updated_at, created_at, updated_at, deleted_at
+FROM dentists
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NOT NULL
+FOR UPDATE
+`
+
+type LockDeletedDentistForUpdateParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) LockDeletedDentistForUpdate(ctx context.Context, arg LockDeletedDentistForUpdateParams) (Dentist, error) {
+	row := q.db.QueryRow(ctx, lockDeletedDentistForUpdate, arg.ID, arg.OrganizationID)
+	var i Dentist
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoKey,
+		&i.PhotoSizeBytes,
+		&i.PhotoUpdatedAt,
+		&i.PhotoThumbnailKey,
+		&i.PhotoThumbnailUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const restoreDentist = `-- name: RestoreDentist :execrows
+UPDATE dentists
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NOT NULL
+`
+
+type RestoreDentistParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) RestoreDentist(ctx context.Context, arg RestoreDentistParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreDentist, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateDentistCRO = `-- name: UpdateDentistCRO :one
+UPDATE dentists
+SET cro_number = $1,
+    cro_state = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $3::uuid
+  AND organization_id = $4::uuid
+  AND deleted_at IS NULL
+RETURNING id, organization_id, person_id, cro_number, cro_state, photo_key, photo_size_bytes, photo_updated_at, photo_thumbnail_key, photo_thumbnail_updated_at, created_at, updated_at, deleted_at
+`
+
+type UpdateDentistCROParams struct {
+	CroNumber      sql.NullString `json:"cro_number"`
+	CroState       sql.NullString `json:"cro_state"`
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+}
+
+func (q *Queries) UpdateDentistCRO(ctx context.Context, arg UpdateDentistCROParams) (Dentist, error) {
+	row := q.db.QueryRow(ctx, updateDentistCRO,
+		arg.CroNumber,
+		arg.CroState,
+		arg.ID,
+		arg.OrganizationID,
+	)
+	var i Dentist
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoKey,
+		&i.PhotoSizeBytes,
+		&i.PhotoUpdatedAt,
+		&i.PhotoThumbnailKey,
+		&i.PhotoThumbnailUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateDentistPerson = `-- name: UpdateDentistPerson :one
+UPDATE dentists
+SET person_id = $1::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2::uuid
+  AND organization_id = $3::uuid
+  AND deleted_at IS NULL
+RETURNING id, organization_id, person_id, cro_number, cro_state, photo_key, photo_size_bytes, photo_updated_at, photo_thumbnail_key, photo_thumbnail_updated_at, created_at, updated_at, deleted_at
+`
+
+type UpdateDentistPersonParams struct {
+	PersonID       string `json:"person_id"`
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) UpdateDentistPerson(ctx context.Context, arg UpdateDentistPersonParams) (Dentist, error) {
+	row := q.db.QueryRow(ctx, updateDentistPerson, arg.PersonID, arg.ID, arg.OrganizationID)
+	var i Dentist
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoKey,
+		&i.PhotoSizeBytes,
+		&i.PhotoUpdatedAt,
+		&i.PhotoThumbnailKey,
+		&i.PhotoThumbnailUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateDentistPhoto = `-- name: UpdateDentistPhoto :one
+UPDATE dentists
+SET photo_key = $1,
+    photo_size_bytes = $2,
+    photo_updated_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $3::uuid
+  AND organization_id = $4::uuid
+  AND deleted_at IS NULL
+RETURNING id, organization_id, person_id, cro_number, cro_state, photo_key, photo_size_bytes, photo_updated_at, photo_thumbnail_key, photo_thumbnail_updated_at, created_at, updated_at, deleted_at
+`
+
+type UpdateDentistPhotoParams struct {
+	PhotoKey       sql.NullString `json:"photo_key"`
+	PhotoSizeBytes int64          `json:"photo_size_bytes"`
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+}
+
+func (q *Queries) UpdateDentistPhoto(ctx context.Context, arg UpdateDentistPhotoParams) (Dentist, error) {
+	row := q.db.QueryRow(ctx, updateDentistPhoto,
+		arg.PhotoKey,
+		arg.PhotoSizeBytes,
+		arg.ID,
+		arg.OrganizationID,
+	)
+	var i Dentist
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.PersonID,
+		&i.CroNumber,
+		&i.CroState,
+		&i.PhotoKey,
+		&i.PhotoSizeBytes,
+		&i.PhotoUpdatedAt,
+		&i.PhotoThumbnailKey,
+		&i.PhotoThumbnailUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const updateDentistPhotoThumbnail = `-- name: UpdateDentistPhotoThumbnail :execrows
+UPDATE dentists
+SET photo_thumbnail_key = $1,
+    photo_thumbnail_updated_at = $2::timestamptz
+WHERE id = $3::uuid
+  AND organization_id = $4::uuid
+  AND photo_updated_at = $2::timestamptz
+`
+
+type UpdateDentistPhotoThumbnailParams struct {
+	PhotoThumbnailKey sql.NullString `json:"photo_thumbnail_key"`
+	PhotoUpdatedAt    time.Time      `json:"photo_updated_at"`
+	ID                string         `json:"id"`
+	OrganizationID    string         `json:"organization_id"`
+}
+
+func (q *Queries) UpdateDentistPhotoThumbnail(ctx context.Context, arg UpdateDentistPhotoThumbnailParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateDentistPhotoThumbnail,
+		arg.PhotoThumbnailKey,
+		arg.PhotoUpdatedAt,
+		arg.ID,
+		arg.OrganizationID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -6,66 +6,832 @@ package repository
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
+
+	"github.com/google/uuid"
 )
 
+type Address struct {
+	PersonID       string         `json:"person_id"`
+	OrganizationID string         `json:"organization_id"`
+	Street         string         `json:"street"`
+	Number         sql.NullString `json:"number"`
+	Complement     sql.NullString `json:"complement"`
+	City           string         `json:"city"`
+	State          string         `json:"state"`
+	Cep            string         `json:"cep"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+type AttachmentScan struct {
+	ID               string         `json:"id"`
+	OrganizationID   string         `json:"organization_id"`
+	ResourceType     string         `json:"resource_type"`
+	ResourceID       string         `json:"resource_id"`
+	QuarantineKey    string         `json:"quarantine_key"`
+	ContentType      string         `json:"content_type"`
+	SizeBytes        int64          `json:"size_bytes"`
+	Status           string         `json:"status"`
+	Signature        sql.NullString `json:"signature"`
+	FailureReason    sql.NullString `json:"failure_reason"`
+	Attempts         int32          `json:"attempts"`
+	NextAttemptAt    time.Time      `json:"next_attempt_at"`
+	UploadedByUserID string         `json:"uploaded_by_user_id"`
+	ScannedAt        sql.NullTime   `json:"scanned_at"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+type AuditChainHead struct {
+	OrganizationID string    `json:"organization_id"`
+	LastSequence   int64     `json:"last_sequence"`
+	LastHash       string    `json:"last_hash"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type AuditExportCheckpoint struct {
+	OrganizationID string    `json:"organization_id"`
+	Sink           string    `json:"sink"`
+	LastAuditID    string    `json:"last_audit_id"`
+	ExportedCount  int64     `json:"exported_count"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type AuditLog struct {
+	ID             string          `json:"id"`
+	OrganizationID string          `json:"organization_id"`
+	ClinicID       uuid.NullUUID   `json:"clinic_id"`
+	ActorUserID    uuid.NullUUID   `json:"actor_user_id"`
+	Action         string          `json:"action"`
+	EntityType     string          `json:"entity_type"`
+	EntityID       string          `json:"entity_id"`
+	Metadata       json.RawMessage `json:"metadata"`
+	CreatedAt      time.Time       `json:"created_at"`
+	ChainSequence  sql.NullInt64   `json:"chain_sequence"`
+	PreviousHash   sql.NullString  `json:"previous_hash"`
+	EntryHash      sql.NullString  `json:"entry_hash"`
+}
+
 type BankAccount struct {
-	ID            string       `json:"id"`
-	ClinicID      string       `json:"clinic_id"`
-	BankCode      string       `json:"bank_code"`
-	BranchNumber  string       `json:"branch_number"`
-	AccountNumber string       `json:"account_number"`
-	CreatedAt     time.Time    `json:"created_at"`
-	UpdatedAt     time.Time    `json:"updated_at"`
-	DeletedAt     sql.NullTime `json:"deleted_at"`
+	ID                        string         `json:"id"`
+	OrganizationID            string         `json:"organization_id"`
+	ClinicID                  string         `json:"clinic_id"`
+	BankCode                  string         `json:"bank_code"`
+	BranchNumber              string         `json:"branch_number"`
+	AccountNumber             string         `json:"account_number"`
+	AccountType               string         `json:"account_type"`
+	HolderName                sql.NullString `json:"holder_name"`
+	HolderTaxID               sql.NullString `json:"holder_tax_id"`
+	HolderReviewRequired      bool           `json:"holder_review_required"`
+	IsPrimary                 bool           `json:"is_primary"`
+	VerificationStatus        string         `json:"verification_status"`
+	VerificationProvider      sql.NullString `json:"verification_provider"`
+	VerificationReference     sql.NullString `json:"verification_reference"`
+	VerificationRequestedAt   sql.NullTime   `json:"verification_requested_at"`
+	VerifiedAt                sql.NullTime   `json:"verified_at"`
+	VerificationFailureReason sql.NullString `json:"verification_failure_reason"`
+	CreatedAt                 time.Time      `json:"created_at"`
+	UpdatedAt                 time.Time      `json:"updated_at"`
+	DeletedAt                 sql.NullTime   `json:"deleted_at"`
+}
+
+type BankAccountChange struct {
+	ID                string         `json:"id"`
+	OrganizationID    string         `json:"organization_id"`
+	ClinicID          string         `json:"clinic_id"`
+	ChangeType        string         `json:"change_type"`
+	Status            string         `json:"status"`
+	BankAccountID     uuid.NullUUID  `json:"bank_account_id"`
+	BankCode          sql.NullString `json:"bank_code"`
+	BranchNumber      sql.NullString `json:"branch_number"`
+	AccountNumber     sql.NullString `json:"account_number"`
+	AccountType       sql.NullString `json:"account_type"`
+	HolderName        sql.NullString `json:"holder_name"`
+	HolderTaxID       sql.NullString `json:"holder_tax_id"`
+	Reason            sql.NullString `json:"reason"`
+	RequestedByUserID string         `json:"requested_by_user_id"`
+	ReviewedByUserID  uuid.NullUUID  `json:"reviewed_by_user_id"`
+	ReviewedAt        sql.NullTime   `json:"reviewed_at"`
+	ReviewNotes       sql.NullString `json:"review_notes"`
+	CreatedAt         time.Time      `json:"created_at"`
 }
 
 type Clinic struct {
-	ID        string       `json:"id"`
-	PersonID  string       `json:"person_id"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
-	DeletedAt sql.NullTime `json:"deleted_at"`
+	ID                        string         `json:"id"`
+	OrganizationID            string         `json:"organization_id"`
+	PersonID                  string         `json:"person_id"`
+	ParentClinicID            uuid.NullUUID  `json:"parent_clinic_id"`
+	Timezone                  string         `json:"timezone"`
+	OnboardingStatus          string         `json:"onboarding_status"`
+	OnboardingStatusChangedAt time.Time      `json:"onboarding_status_changed_at"`
+	DeactivatedAt             sql.NullTime   `json:"deactivated_at"`
+	DeactivationReason        sql.NullString `json:"deactivation_reason"`
+	CreatedAt                 time.Time      `json:"created_at"`
+	UpdatedAt                 time.Time      `json:"updated_at"`
+	DeletedAt                 sql.NullTime   `json:"deleted_at"`
+}
+
+type ClinicAnnouncement struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	ClinicID       string    `json:"clinic_id"`
+	AuthorUserID   string    `json:"author_user_id"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ClinicAnnouncementRecipient struct {
+	OrganizationID string       `json:"organization_id"`
+	AnnouncementID string       `json:"announcement_id"`
+	DentistID      string       `json:"dentist_id"`
+	ReadAt         sql.NullTime `json:"read_at"`
 }
 
 type ClinicDentist struct {
-	ClinicID              string       `json:"clinic_id"`
-	DentistID             string       `json:"dentist_id"`
-	IsAdmin               bool         `json:"is_admin"`
-	IsLegalRepresentative bool         `json:"is_legal_representative"`
-	StartedAt             time.Time    `json:"started_at"`
-	EndedAt               sql.NullTime `json:"ended_at"`
-	CreatedAt             time.Time    `json:"created_at"`
-	UpdatedAt             time.Time    `json:"updated_at"`
+	OrganizationID         string        `json:"organization_id"`
+	ClinicID               string        `json:"clinic_id"`
+	DentistID              string        `json:"dentist_id"`
+	IsAdmin                bool          `json:"is_admin"`
+	IsLegalRepresentative  bool          `json:"is_legal_representative"`
+	StartedAt              time.Time     `json:"started_at"`
+	EndedAt                sql.NullTime  `json:"ended_at"`
+	PlannedEndAt           sql.NullTime  `json:"planned_end_at"`
+	SubstituteForDentistID uuid.NullUUID `json:"substitute_for_dentist_id"`
+	CreatedAt              time.Time     `json:"created_at"`
+	UpdatedAt              time.Time     `json:"updated_at"`
+}
+
+type ClinicDirectoryListing struct {
+	ClinicID       string         `json:"clinic_id"`
+	OrganizationID string         `json:"organization_id"`
+	Listed         bool           `json:"listed"`
+	PublicPhone    sql.NullString `json:"public_phone"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+}
+
+type ClinicFinancialHold struct {
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+	ClinicID       string         `json:"clinic_id"`
+	ReasonCode     string         `json:"reason_code"`
+	Notes          sql.NullString `json:"notes"`
+	PlacedByUserID string         `json:"placed_by_user_id"`
+	PlacedAt       time.Time      `json:"placed_at"`
+	LiftedByUserID uuid.NullUUID  `json:"lifted_by_user_id"`
+	LiftedAt       sql.NullTime   `json:"lifted_at"`
+	LiftNotes      sql.NullString `json:"lift_notes"`
+}
+
+type ClinicHoliday struct {
+	ID             string       `json:"id"`
+	OrganizationID string       `json:"organization_id"`
+	ClinicID       string       `json:"clinic_id"`
+	HolidayDate    time.Time    `json:"holiday_date"`
+	Name           string       `json:"name"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	DeletedAt      sql.NullTime `json:"deleted_at"`
+}
+
+type ClinicNote struct {
+	ID             string       `json:"id"`
+	OrganizationID string       `json:"organization_id"`
+	ClinicID       string       `json:"clinic_id"`
+	AuthorUserID   string       `json:"author_user_id"`
+	Body           string       `json:"body"`
+	Pinned         bool         `json:"pinned"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	DeletedAt      sql.NullTime `json:"deleted_at"`
+}
+
+type ClinicNoteMention struct {
+	OrganizationID string `json:"organization_id"`
+	NoteID         string `json:"note_id"`
+	EntityType     string `json:"entity_type"`
+	EntityID       string `json:"entity_id"`
+}
+
+type ClinicOnboardingTransition struct {
+	ID                string         `json:"id"`
+	OrganizationID    string         `json:"organization_id"`
+	ClinicID          string         `json:"clinic_id"`
+	FromStatus        string         `json:"from_status"`
+	ToStatus          string         `json:"to_status"`
+	EvidenceReference string         `json:"evidence_reference"`
+	BankAccountID     uuid.NullUUID  `json:"bank_account_id"`
+	Notes             sql.NullString `json:"notes"`
+	PerformedByUserID uuid.NullUUID  `json:"performed_by_user_id"`
+	CreatedAt         time.Time      `json:"created_at"`
+}
+
+type ClinicOperatingHour struct {
+	OrganizationID string    `json:"organization_id"`
+	ClinicID       string    `json:"clinic_id"`
+	Weekday        int16     `json:"weekday"`
+	OpensMinute    int16     `json:"opens_minute"`
+	ClosesMinute   int16     `json:"closes_minute"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ClinicPayable struct {
+	ID                string         `json:"id"`
+	OrganizationID    string         `json:"organization_id"`
+	ClinicID          string         `json:"clinic_id"`
+	AmountCents       int64          `json:"amount_cents"`
+	Description       string         `json:"description"`
+	ExternalReference sql.NullString `json:"external_reference"`
+	OccurredOn        time.Time      `json:"occurred_on"`
+	PayoutBatchID     uuid.NullUUID  `json:"payout_batch_id"`
+	CreatedByUserID   uuid.NullUUID  `json:"created_by_user_id"`
+	CreatedAt         time.Time      `json:"created_at"`
+}
+
+type ClinicRegistryRecord struct {
+	ClinicID           string         `json:"clinic_id"`
+	OrganizationID     string         `json:"organization_id"`
+	LegalName          string         `json:"legal_name"`
+	TradeName          sql.NullString `json:"trade_name"`
+	CnaeCode           sql.NullString `json:"cnae_code"`
+	CnaeDescription    sql.NullString `json:"cnae_description"`
+	RegistrationStatus string         `json:"registration_status"`
+	Source             string         `json:"source"`
+	FetchedAt          time.Time      `json:"fetched_at"`
+}
+
+type ClinicRevision struct {
+	ID              string          `json:"id"`
+	OrganizationID  string          `json:"organization_id"`
+	ClinicID        string          `json:"clinic_id"`
+	EntityType      string          `json:"entity_type"`
+	EntityID        string          `json:"entity_id"`
+	Operation       string          `json:"operation"`
+	BeforeState     json.RawMessage `json:"before_state"`
+	AfterState      json.RawMessage `json:"after_state"`
+	ChangedByUserID uuid.NullUUID   `json:"changed_by_user_id"`
+	ChangedAt       time.Time       `json:"changed_at"`
+}
+
+type ClinicSetting struct {
+	ClinicID                          string    `json:"clinic_id"`
+	OrganizationID                    string    `json:"organization_id"`
+	DefaultAppointmentDurationMinutes int16     `json:"default_appointment_duration_minutes"`
+	ReminderLeadMinutes               []int32   `json:"reminder_lead_minutes"`
+	InvoiceNumberPrefix               string    `json:"invoice_number_prefix"`
+	Locale                            string    `json:"locale"`
+	Currency                          string    `json:"currency"`
+	CreatedAt                         time.Time `json:"created_at"`
+	UpdatedAt                         time.Time `json:"updated_at"`
+}
+
+type ClinicShift struct {
+	ID              string         `json:"id"`
+	OrganizationID  string         `json:"organization_id"`
+	ClinicID        string         `json:"clinic_id"`
+	DentistID       string         `json:"dentist_id"`
+	StartsAt        time.Time      `json:"starts_at"`
+	EndsAt          time.Time      `json:"ends_at"`
+	Notes           sql.NullString `json:"notes"`
+	CreatedByUserID uuid.NullUUID  `json:"created_by_user_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       sql.NullTime   `json:"deleted_at"`
+}
+
+type ClinicTask struct {
+	ID                string         `json:"id"`
+	OrganizationID    string         `json:"organization_id"`
+	ClinicID          string         `json:"clinic_id"`
+	Title             string         `json:"title"`
+	Description       sql.NullString `json:"description"`
+	Status            string         `json:"status"`
+	DueDate           sql.NullTime   `json:"due_date"`
+	AssigneeUserID    uuid.NullUUID  `json:"assignee_user_id"`
+	EntityType        sql.NullString `json:"entity_type"`
+	EntityID          uuid.NullUUID  `json:"entity_id"`
+	AutomationTrigger sql.NullString `json:"automation_trigger"`
+	AutomationKey     sql.NullString `json:"automation_key"`
+	CreatedByUserID   uuid.NullUUID  `json:"created_by_user_id"`
+	CompletedAt       sql.NullTime   `json:"completed_at"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         sql.NullTime   `json:"deleted_at"`
+}
+
+type ClinicTaskRule struct {
+	OrganizationID string        `json:"organization_id"`
+	ClinicID       string        `json:"clinic_id"`
+	TriggerType    string        `json:"trigger_type"`
+	AssigneeUserID uuid.NullUUID `json:"assignee_user_id"`
+	DueInDays      int32         `json:"due_in_days"`
+	LeadDays       int32         `json:"lead_days"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+type ClinicTimeClockSetting struct {
+	ClinicID        string          `json:"clinic_id"`
+	OrganizationID  string          `json:"organization_id"`
+	Latitude        sql.NullFloat64 `json:"latitude"`
+	Longitude       sql.NullFloat64 `json:"longitude"`
+	RadiusMeters    sql.NullInt32   `json:"radius_meters"`
+	AllowedNetworks []string        `json:"allowed_networks"`
+	Enforce         bool            `json:"enforce"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
 }
 
 type Dentist struct {
-	ID        string       `json:"id"`
-	PersonID  string       `json:"person_id"`
-	CreatedAt time.Time    `json:"created_at"`
-	UpdatedAt time.Time    `json:"updated_at"`
-	DeletedAt sql.NullTime `json:"deleted_at"`
+	ID                      string         `json:"id"`
+	OrganizationID          string         `json:"organization_id"`
+	PersonID                string         `json:"person_id"`
+	CroNumber               sql.NullString `json:"cro_number"`
+	CroState                sql.NullString `json:"cro_state"`
+	PhotoKey                sql.NullString `json:"photo_key"`
+	PhotoSizeBytes          int64          `json:"photo_size_bytes"`
+	PhotoUpdatedAt          sql.NullTime   `json:"photo_updated_at"`
+	PhotoThumbnailKey       sql.NullString `json:"photo_thumbnail_key"`
+	PhotoThumbnailUpdatedAt sql.NullTime   `json:"photo_thumbnail_updated_at"`
+	CreatedAt               time.Time      `json:"created_at"`
+	UpdatedAt               time.Time      `json:"updated_at"`
+	DeletedAt               sql.NullTime   `json:"deleted_at"`
+}
+
+type DentistDocument struct {
+	ID                        string         `json:"id"`
+	OrganizationID            string         `json:"organization_id"`
+	DentistID                 string         `json:"dentist_id"`
+	DocumentType              string         `json:"document_type"`
+	DocumentNumber            sql.NullString `json:"document_number"`
+	IssuedAt                  sql.NullTime   `json:"issued_at"`
+	ExpiresAt                 time.Time      `json:"expires_at"`
+	Notes                     sql.NullString `json:"notes"`
+	LastNotifiedThresholdDays sql.NullInt32  `json:"last_notified_threshold_days"`
+	LastNotifiedAt            sql.NullTime   `json:"last_notified_at"`
+	CreatedAt                 time.Time      `json:"created_at"`
+	UpdatedAt                 time.Time      `json:"updated_at"`
+	DeletedAt                 sql.NullTime   `json:"deleted_at"`
+}
+
+type DentistNotificationPreference struct {
+	OrganizationID string    `json:"organization_id"`
+	DentistID      string    `json:"dentist_id"`
+	Channels       []string  `json:"channels"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type DentistSpecialty struct {
+	OrganizationID string    `json:"organization_id"`
+	DentistID      string    `json:"dentist_id"`
+	SpecialtyID    string    `json:"specialty_id"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type DocumentRetentionRule struct {
+	OrganizationID  string    `json:"organization_id"`
+	DocumentKind    string    `json:"document_kind"`
+	RetentionDays   int32     `json:"retention_days"`
+	UpdatedByUserID string    `json:"updated_by_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type DocumentSignatureRequest struct {
+	ID                   string          `json:"id"`
+	OrganizationID       string          `json:"organization_id"`
+	ClinicID             string          `json:"clinic_id"`
+	DocumentID           string          `json:"document_id"`
+	Provider             string          `json:"provider"`
+	Reference            string          `json:"reference"`
+	Status               string          `json:"status"`
+	Signers              json.RawMessage `json:"signers"`
+	FailureReason        sql.NullString  `json:"failure_reason"`
+	SignedAttachmentKey  sql.NullString  `json:"signed_attachment_key"`
+	SignedSizeBytes      int64           `json:"signed_size_bytes"`
+	SignedChecksumSha256 sql.NullString  `json:"signed_checksum_sha256"`
+	CreatedByUserID      string          `json:"created_by_user_id"`
+	CompletedAt          sql.NullTime    `json:"completed_at"`
+	CreatedAt            time.Time       `json:"created_at"`
+	UpdatedAt            time.Time       `json:"updated_at"`
+}
+
+type DocumentTemplate struct {
+	ID             string       `json:"id"`
+	OrganizationID string       `json:"organization_id"`
+	Name           string       `json:"name"`
+	Kind           string       `json:"kind"`
+	Title          string       `json:"title"`
+	Body           string       `json:"body"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+	DeletedAt      sql.NullTime `json:"deleted_at"`
+}
+
+type Equipment struct {
+	ID                      string         `json:"id"`
+	OrganizationID          string         `json:"organization_id"`
+	ClinicID                string         `json:"clinic_id"`
+	Name                    string         `json:"name"`
+	Kind                    string         `json:"kind"`
+	Manufacturer            sql.NullString `json:"manufacturer"`
+	Model                   sql.NullString `json:"model"`
+	SerialNumber            sql.NullString `json:"serial_number"`
+	MaintenanceIntervalDays sql.NullInt32  `json:"maintenance_interval_days"`
+	LastMaintainedAt        sql.NullTime   `json:"last_maintained_at"`
+	NextMaintenanceDue      sql.NullTime   `json:"next_maintenance_due"`
+	OverdueNotifiedFor      sql.NullTime   `json:"overdue_notified_for"`
+	Notes                   sql.NullString `json:"notes"`
+	CreatedAt               time.Time      `json:"created_at"`
+	UpdatedAt               time.Time      `json:"updated_at"`
+	DeletedAt               sql.NullTime   `json:"deleted_at"`
+}
+
+type EquipmentMaintenanceRecord struct {
+	ID                 string         `json:"id"`
+	OrganizationID     string         `json:"organization_id"`
+	ClinicID           string         `json:"clinic_id"`
+	EquipmentID        string         `json:"equipment_id"`
+	Kind               string         `json:"kind"`
+	PerformedAt        time.Time      `json:"performed_at"`
+	PerformedBy        sql.NullString `json:"performed_by"`
+	CostCents          sql.NullInt64  `json:"cost_cents"`
+	Notes              sql.NullString `json:"notes"`
+	NextMaintenanceDue sql.NullTime   `json:"next_maintenance_due"`
+	CreatedByUserID    uuid.NullUUID  `json:"created_by_user_id"`
+	CreatedAt          time.Time      `json:"created_at"`
+}
+
+type GeneratedDocument struct {
+	ID              string        `json:"id"`
+	OrganizationID  string        `json:"organization_id"`
+	ClinicID        string        `json:"clinic_id"`
+	TemplateID      string        `json:"template_id"`
+	DentistID       uuid.NullUUID `json:"dentist_id"`
+	Kind            string        `json:"kind"`
+	Title           string        `json:"title"`
+	AttachmentKey   string        `json:"attachment_key"`
+	ContentType     string        `json:"content_type"`
+	SizeBytes       int64         `json:"size_bytes"`
+	ChecksumSha256  string        `json:"checksum_sha256"`
+	Placeholders    []string      `json:"placeholders"`
+	CreatedByUserID string        `json:"created_by_user_id"`
+	CreatedAt       time.Time     `json:"created_at"`
+}
+
+type InventoryItem struct {
+	ID              string         `json:"id"`
+	OrganizationID  string         `json:"organization_id"`
+	ClinicID        string         `json:"clinic_id"`
+	Name            string         `json:"name"`
+	Sku             sql.NullString `json:"sku"`
+	Unit            string         `json:"unit"`
+	Quantity        int64          `json:"quantity"`
+	MinimumQuantity int64          `json:"minimum_quantity"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       sql.NullTime   `json:"deleted_at"`
+}
+
+type InventoryMovement struct {
+	ID              string         `json:"id"`
+	OrganizationID  string         `json:"organization_id"`
+	ClinicID        string         `json:"clinic_id"`
+	ItemID          string         `json:"item_id"`
+	Kind            string         `json:"kind"`
+	QuantityDelta   int64          `json:"quantity_delta"`
+	QuantityAfter   int64          `json:"quantity_after"`
+	UnitCostCents   sql.NullInt64  `json:"unit_cost_cents"`
+	Notes           sql.NullString `json:"notes"`
+	CreatedByUserID uuid.NullUUID  `json:"created_by_user_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+type LedgerEntry struct {
+	OrganizationID string `json:"organization_id"`
+	TransactionID  string `json:"transaction_id"`
+	Line           int16  `json:"line"`
+	Account        string `json:"account"`
+	Direction      string `json:"direction"`
+	AmountCents    int64  `json:"amount_cents"`
+}
+
+type LedgerTransaction struct {
+	ID              string         `json:"id"`
+	OrganizationID  string         `json:"organization_id"`
+	ClinicID        string         `json:"clinic_id"`
+	Kind            string         `json:"kind"`
+	Description     string         `json:"description"`
+	ReferenceType   sql.NullString `json:"reference_type"`
+	ReferenceID     uuid.NullUUID  `json:"reference_id"`
+	OccurredOn      time.Time      `json:"occurred_on"`
+	CreatedByUserID uuid.NullUUID  `json:"created_by_user_id"`
+	CreatedAt       time.Time      `json:"created_at"`
+}
+
+type LegalHold struct {
+	ID               string         `json:"id"`
+	OrganizationID   string         `json:"organization_id"`
+	ResourceType     string         `json:"resource_type"`
+	ResourceID       string         `json:"resource_id"`
+	Reason           string         `json:"reason"`
+	CaseReference    sql.NullString `json:"case_reference"`
+	PlacedByUserID   string         `json:"placed_by_user_id"`
+	PlacedAt         time.Time      `json:"placed_at"`
+	ReleasedByUserID uuid.NullUUID  `json:"released_by_user_id"`
+	ReleasedAt       sql.NullTime   `json:"released_at"`
+	ReleaseNotes     sql.NullString `json:"release_notes"`
+}
+
+type Notification struct {
+	ID                string         `json:"id"`
+	OrganizationID    string         `json:"organization_id"`
+	ClinicID          uuid.NullUUID  `json:"clinic_id"`
+	Channel           string         `json:"channel"`
+	Template          string         `json:"template"`
+	Recipient         string         `json:"recipient"`
+	Subject           string         `json:"subject"`
+	Body              string         `json:"body"`
+	DedupeKey         string         `json:"dedupe_key"`
+	Status            string         `json:"status"`
+	Attempts          int32          `json:"attempts"`
+	NextAttemptAt     time.Time      `json:"next_attempt_at"`
+	LastError         sql.NullString `json:"last_error"`
+	SentAt            sql.NullTime   `json:"sent_at"`
+	DeliveryStatus    sql.NullString `json:"delivery_status"`
+	BounceType        sql.NullString `json:"bounce_type"`
+	DeliveryDetail    sql.NullString `json:"delivery_detail"`
+	DeliveryUpdatedAt sql.NullTime   `json:"delivery_updated_at"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+}
+
+type NotificationSuppression struct {
+	ID             string        `json:"id"`
+	OrganizationID string        `json:"organization_id"`
+	Channel        string        `json:"channel"`
+	Recipient      string        `json:"recipient"`
+	Reason         string        `json:"reason"`
+	NotificationID uuid.NullUUID `json:"notification_id"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+type Organization struct {
+	ID                   string         `json:"id"`
+	Slug                 string         `json:"slug"`
+	Name                 string         `json:"name"`
+	MaxClinics           sql.NullInt32  `json:"max_clinics"`
+	MaxDentists          sql.NullInt32  `json:"max_dentists"`
+	MaxRequestsPerMinute sql.NullInt32  `json:"max_requests_per_minute"`
+	MaxStorageMb         sql.NullInt32  `json:"max_storage_mb"`
+	Status               string         `json:"status"`
+	TrialEndsAt          sql.NullTime   `json:"trial_ends_at"`
+	ClosedAt             sql.NullTime   `json:"closed_at"`
+	ExportKey            sql.NullString `json:"export_key"`
+	PurgeAfter           sql.NullTime   `json:"purge_after"`
+	PurgedAt             sql.NullTime   `json:"purged_at"`
+	Isolation            string         `json:"isolation"`
+	SchemaName           sql.NullString `json:"schema_name"`
+	Plan                 string         `json:"plan"`
+	DataRegion           sql.NullString `json:"data_region"`
+	CreatedAt            time.Time      `json:"created_at"`
+	UpdatedAt            time.Time      `json:"updated_at"`
+}
+
+type OrganizationKey struct {
+	OrganizationID string       `json:"organization_id"`
+	WrappedKey     []byte       `json:"wrapped_key"`
+	CreatedAt      time.Time    `json:"created_at"`
+	ShreddedAt     sql.NullTime `json:"shredded_at"`
+}
+
+type PayoutBatch struct {
+	ID              string          `json:"id"`
+	OrganizationID  string          `json:"organization_id"`
+	FileSequence    int64           `json:"file_sequence"`
+	Format          string          `json:"format"`
+	Status          string          `json:"status"`
+	PeriodStart     time.Time       `json:"period_start"`
+	PeriodEnd       time.Time       `json:"period_end"`
+	PaymentDate     time.Time       `json:"payment_date"`
+	TotalCents      int64           `json:"total_cents"`
+	ItemCount       int32           `json:"item_count"`
+	Skipped         json.RawMessage `json:"skipped"`
+	FileName        string          `json:"file_name"`
+	FileContent     []byte          `json:"file_content"`
+	FailureReason   sql.NullString  `json:"failure_reason"`
+	CreatedByUserID uuid.NullUUID   `json:"created_by_user_id"`
+	StatusChangedAt time.Time       `json:"status_changed_at"`
+	CreatedAt       time.Time       `json:"created_at"`
+}
+
+type PayoutBatchItem struct {
+	OrganizationID string `json:"organization_id"`
+	BatchID        string `json:"batch_id"`
+	ClinicID       string `json:"clinic_id"`
+	BankAccountID  string `json:"bank_account_id"`
+	AmountCents    int64  `json:"amount_cents"`
+	PayableCount   int32  `json:"payable_count"`
+	BankCode       string `json:"bank_code"`
+	BranchNumber   string `json:"branch_number"`
+	AccountNumber  string `json:"account_number"`
+	AccountType    string `json:"account_type"`
+	HolderName     string `json:"holder_name"`
+	HolderTaxID    string `json:"holder_tax_id"`
+}
+
+type PendingDeletion struct {
+	OrganizationID    string        `json:"organization_id"`
+	ResourceType      string        `json:"resource_type"`
+	ResourceID        string        `json:"resource_id"`
+	RequestedByUserID uuid.NullUUID `json:"requested_by_user_id"`
+	DeletedAt         time.Time     `json:"deleted_at"`
+	CascadeAfter      time.Time     `json:"cascade_after"`
 }
 
 type Person struct {
-	ID          string         `json:"id"`
-	PersonType  string         `json:"person_type"`
-	TaxIDType   string         `json:"tax_id_type"`
-	TaxIDNumber string         `json:"tax_id_number"`
-	LegalName   string         `json:"legal_name"`
-	TradeName   sql.NullString `json:"trade_name"`
-	Email       sql.NullString `json:"email"`
-	Phone       sql.NullString `json:"phone"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   sql.NullTime   `json:"deleted_at"`
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+	PersonType     string         `json:"person_type"`
+	TaxIDType      string         `json:"tax_id_type"`
+	TaxIDNumber    string         `json:"tax_id_number"`
+	LegalName      string         `json:"legal_name"`
+	TradeName      sql.NullString `json:"trade_name"`
+	Email          sql.NullString `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      sql.NullTime   `json:"deleted_at"`
+	AnonymizedAt   sql.NullTime   `json:"anonymized_at"`
+}
+
+type PlatformOperator struct {
+	ID           string    `json:"id"`
+	Email        string    `json:"email"`
+	PasswordHash string    `json:"password_hash"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type PurchaseOrder struct {
+	ID              string         `json:"id"`
+	OrganizationID  string         `json:"organization_id"`
+	ClinicID        string         `json:"clinic_id"`
+	SupplierID      string         `json:"supplier_id"`
+	Status          string         `json:"status"`
+	Notes           sql.NullString `json:"notes"`
+	CreatedByUserID uuid.NullUUID  `json:"created_by_user_id"`
+	SubmittedAt     sql.NullTime   `json:"submitted_at"`
+	ReceivedAt      sql.NullTime   `json:"received_at"`
+	CancelledAt     sql.NullTime   `json:"cancelled_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+}
+
+type PurchaseOrderItem struct {
+	ID               string    `json:"id"`
+	OrganizationID   string    `json:"organization_id"`
+	PurchaseOrderID  string    `json:"purchase_order_id"`
+	ItemID           string    `json:"item_id"`
+	QuantityOrdered  int64     `json:"quantity_ordered"`
+	QuantityReceived int64     `json:"quantity_received"`
+	UnitCostCents    int64     `json:"unit_cost_cents"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+type PurchaseOrderReceipt struct {
+	ID                  string    `json:"id"`
+	OrganizationID      string    `json:"organization_id"`
+	PurchaseOrderID     string    `json:"purchase_order_id"`
+	PurchaseOrderItemID string    `json:"purchase_order_item_id"`
+	MovementID          string    `json:"movement_id"`
+	Quantity            int64     `json:"quantity"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+type Report struct {
+	ID                string          `json:"id"`
+	OrganizationID    string          `json:"organization_id"`
+	ClinicID          string          `json:"clinic_id"`
+	ReportType        string          `json:"report_type"`
+	Format            string          `json:"format"`
+	Parameters        json.RawMessage `json:"parameters"`
+	Status            string          `json:"status"`
+	Progress          int32           `json:"progress"`
+	RowCount          sql.NullInt32   `json:"row_count"`
+	AttachmentKey     sql.NullString  `json:"attachment_key"`
+	FileName          sql.NullString  `json:"file_name"`
+	ContentType       sql.NullString  `json:"content_type"`
+	SizeBytes         int64           `json:"size_bytes"`
+	FailureReason     sql.NullString  `json:"failure_reason"`
+	Attempts          int32           `json:"attempts"`
+	NextAttemptAt     time.Time       `json:"next_attempt_at"`
+	RequestedByUserID string          `json:"requested_by_user_id"`
+	StartedAt         sql.NullTime    `json:"started_at"`
+	CompletedAt       sql.NullTime    `json:"completed_at"`
+	ExpiresAt         sql.NullTime    `json:"expires_at"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+type ReportDefinition struct {
+	ID              string          `json:"id"`
+	OrganizationID  string          `json:"organization_id"`
+	ClinicID        string          `json:"clinic_id"`
+	Name            string          `json:"name"`
+	Definition      json.RawMessage `json:"definition"`
+	Schedule        sql.NullString  `json:"schedule"`
+	Recipients      []string        `json:"recipients"`
+	NextRunAt       sql.NullTime    `json:"next_run_at"`
+	LastRunAt       sql.NullTime    `json:"last_run_at"`
+	CreatedByUserID string          `json:"created_by_user_id"`
+	CreatedAt       time.Time       `json:"created_at"`
+	UpdatedAt       time.Time       `json:"updated_at"`
+	DeletedAt       sql.NullTime    `json:"deleted_at"`
+}
+
+type Specialty struct {
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+	Code           string         `json:"code"`
+	Name           string         `json:"name"`
+	Description    sql.NullString `json:"description"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      sql.NullTime   `json:"deleted_at"`
+}
+
+type Supplier struct {
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+	PersonID       string         `json:"person_id"`
+	Notes          sql.NullString `json:"notes"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      sql.NullTime   `json:"deleted_at"`
+}
+
+type TimeClockEntry struct {
+	ID                     string          `json:"id"`
+	OrganizationID         string          `json:"organization_id"`
+	ClinicID               string          `json:"clinic_id"`
+	DentistID              string          `json:"dentist_id"`
+	ClockInAt              time.Time       `json:"clock_in_at"`
+	ClockInIp              sql.NullString  `json:"clock_in_ip"`
+	ClockInLatitude        sql.NullFloat64 `json:"clock_in_latitude"`
+	ClockInLongitude       sql.NullFloat64 `json:"clock_in_longitude"`
+	ClockInAccuracyMeters  sql.NullFloat64 `json:"clock_in_accuracy_meters"`
+	ClockInDistanceMeters  sql.NullFloat64 `json:"clock_in_distance_meters"`
+	ClockInVerified        sql.NullBool    `json:"clock_in_verified"`
+	ClockOutAt             sql.NullTime    `json:"clock_out_at"`
+	ClockOutIp             sql.NullString  `json:"clock_out_ip"`
+	ClockOutLatitude       sql.NullFloat64 `json:"clock_out_latitude"`
+	ClockOutLongitude      sql.NullFloat64 `json:"clock_out_longitude"`
+	ClockOutAccuracyMeters sql.NullFloat64 `json:"clock_out_accuracy_meters"`
+	ClockOutDistanceMeters sql.NullFloat64 `json:"clock_out_distance_meters"`
+	ClockOutVerified       sql.NullBool    `json:"clock_out_verified"`
+	CreatedAt              time.Time       `json:"created_at"`
+	UpdatedAt              time.Time       `json:"updated_at"`
+}
+
+type UsageDailyRollup struct {
+	OrganizationID string    `json:"organization_id"`
+	Metric         string    `json:"metric"`
+	Day            time.Time `json:"day"`
+	Quantity       int64     `json:"quantity"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type UsageRecord struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	Metric         string    `json:"metric"`
+	Quantity       int64     `json:"quantity"`
+	RecordedAt     time.Time `json:"recorded_at"`
 }
 
 type User struct {
-	ID           string       `json:"id"`
-	Email        string       `json:"email"`
-	PasswordHash string       `json:"password_hash"`
-	CreatedAt    time.Time    `json:"created_at"`
-	UpdatedAt    time.Time    `json:"updated_at"`
-	DeletedAt    sql.NullTime `json:"deleted_at"`
+	ID                   string        `json:"id"`
+	OrganizationID       string        `json:"organization_id"`
+	Email                string        `json:"email"`
+	PasswordHash         string        `json:"password_hash"`
+	Role                 string        `json:"role"`
+	DentistID            uuid.NullUUID `json:"dentist_id"`
+	CanRevealBankDetails bool          `json:"can_reveal_bank_details"`
+	CreatedAt            time.Time     `json:"created_at"`
+	UpdatedAt            time.Time     `json:"updated_at"`
+	DeletedAt            sql.NullTime  `json:"deleted_at"`
+}
+
+type WarehouseExportWatermark struct {
+	OrganizationID string    `json:"organization_id"`
+	Sink           string    `json:"sink"`
+	TableName      string    `json:"table_name"`
+	LastChangedAt  time.Time `json:"last_changed_at"`
+	LastID         string    `json:"last_id"`
+	ExportedCount  int64     `json:"exported_count"`
+	UpdatedAt      time.Time `json:"updated_at"`
 }
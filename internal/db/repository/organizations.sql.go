@@ -0,0 +1,666 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: organizations.sql
+
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+)
+
+const closeOrganization = `-- name: CloseOrganization :one
+UPDATE organizations
+SET status = 'CLOSED',
+    trial_ends_at = NULL,
+    closed_at = COALESCE(closed_at, CURRENT_TIMESTAMP),
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1::uuid
+  AND purged_at IS NULL
+RETURNING id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+`
+
+func (q *Queries) CloseOrganization(ctx context.Context, id string) (Organization, error) {
+	row := q.db.QueryRow(ctx, closeOrganization, id)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const createAuditChainHead = `-- name: CreateAuditChainHead :exec
+INSERT INTO audit_chain_head (organization_id, last_sequence, last_hash)
+VALUES ($1::uuid, 0, $2)
+ON CONFLICT DO NOTHING
+`
+
+type CreateAuditChainHeadParams struct {
+	OrganizationID string `json:"organization_id"`
+	LastHash       string `json:"last_hash"`
+}
+
+func (q *Queries) CreateAuditChainHead(ctx context.Context, arg CreateAuditChainHeadParams) error {
+	_, err := q.db.Exec(ctx, createAuditChainHead, arg.OrganizationID, arg.LastHash)
+	return err
+}
+
+const createOrganization = `-- name: CreateOrganization :one
+INSERT INTO organizations (id, slug, name, status, trial_ends_at, isolation, schema_name, plan, data_region)
+VALUES ($1::uuid, $2, $3, $4, $5, $6, $7, $8, $9)
+RETURNING id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+`
+
+type CreateOrganizationParams struct {
+	ID          string         `json:"id"`
+	Slug        string         `json:"slug"`
+	Name        string         `json:"name"`
+	Status      string         `json:"status"`
+	TrialEndsAt sql.NullTime   `json:"trial_ends_at"`
+	Isolation   string         `json:"isolation"`
+	SchemaName  sql.NullString `json:"schema_name"`
+	Plan        string         `json:"plan"`
+	DataRegion  sql.NullString `json:"data_region"`
+}
+
+func (q *Queries) CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, createOrganization,
+		arg.ID,
+		arg.Slug,
+		arg.Name,
+		arg.Status,
+		arg.TrialEndsAt,
+		arg.Isolation,
+		arg.SchemaName,
+		arg.Plan,
+		arg.DataRegion,
+	)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const deleteOrganization = `-- name: DeleteOrganization :exec
+DELETE FROM organizations
+WHERE id = $1::uuid
+`
+
+func (q *Queries) DeleteOrganization(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, deleteOrganization, id)
+	return err
+}
+
+const getOrganizationByID = `-- name: GetOrganizationByID :one
+SELECT id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+FROM organizations
+WHERE id = $1::uuid
+LIMIT 1
+`
+
+func (q *Queries) GetOrganizationByID(ctx context.Context, id string) (Organization, error) {
+	row := q.db.QueryRow(ctx, getOrganizationByID, id)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganizationBySlug = `-- name: GetOrganizationBySlug :one
+SELECT id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+FROM organizations
+WHERE slug = $1
+LIMIT 1
+`
+
+func (q *Queries) GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error) {
+	row := q.db.QueryRow(ctx, getOrganizationBySlug, slug)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getOrganizationUsage = `-- name: GetOrganizationUsage :one
+SELECT
+    (SELECT COUNT(*) FROM clinics c WHERE c.organization_id = $1::uuid AND c.deleted_at IS NULL)::int AS clinics,
+    (SELECT COUNT(*) FROM dentists d WHERE d.organization_id = $1::uuid AND d.deleted_at IS NULL)::int AS dentists,
+    ((SELECT COALESCE(SUM(d.photo_size_bytes), 0) FROM dentists d WHERE d.organization_id = $1::uuid AND d.photo_key IS NOT NULL)
+        + (SELECT COALESCE(SUM(gd.size_bytes), 0) FROM generated_documents gd WHERE gd.organization_id = $1::uuid)
+        + (SELECT COALESCE(SUM(dsr.signed_size_bytes), 0) FROM document_signature_requests dsr WHERE dsr.organization_id = $1::uuid)
+        + (SELECT COALESCE(SUM(ats.size_bytes), 0) FROM attachment_scans ats WHERE ats.organization_id = $1::uuid AND ats.status = 'PENDING')
+        + (SELECT COALESCE(SUM(r.size_bytes), 0) FROM reports r WHERE r.organization_id = $1::uuid))::bigint AS storage_bytes
+`
+
+type GetOrganizationUsageRow struct {
+	Clinics      int32 `json:"clinics"`
+	Dentists     int32 `json:"dentists"`
+	StorageBytes int64 `json:"storage_bytes"`
+}
+
+func (q *Queries) GetOrganizationUsage(ctx context.Context, organizationID string) (GetOrganizationUsageRow, error) {
+	row := q.db.QueryRow(ctx, getOrganizationUsage, organizationID)
+	var i GetOrganizationUsageRow
+	err := row.Scan(&i.Clinics, &i.Dentists, &i.StorageBytes)
+	return i, err
+}
+
+const listOrganizationIDs = `-- name: ListOrganizationIDs :many
+SELECT id
+FROM organizations
+WHERE status <> 'CLOSED'
+ORDER BY id
+`
+
+func (q *Queries) ListOrganizationIDs(ctx context.Context) ([]string, error) {
+	rows, err := q.db.Query(ctx, listOrganizationIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []string{}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizations = `-- name: ListOrganizations :many
+SELECT id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+FROM organizations
+ORDER BY slug
+`
+
+func (q *Queries) ListOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := q.db.Query(ctx, listOrganizations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Organization{}
+	for rows.Next() {
+		var i Organization
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Name,
+			&i.MaxClinics,
+			&i.MaxDentists,
+			&i.MaxRequestsPerMinute,
+			&i.MaxStorageMb,
+			&i.Status,
+			&i.TrialEndsAt,
+			&i.ClosedAt,
+			&i.ExportKey,
+			&i.PurgeAfter,
+			&i.PurgedAt,
+			&i.Isolation,
+			&i.SchemaName,
+			&i.Plan,
+			&i.DataRegion,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listOrganizationsDueForPurge = `-- name: ListOrganizationsDueForPurge :many
+SELECT id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+FROM organizations
+WHERE status = 'CLOSED'
+  AND purged_at IS NULL
+  AND purge_after <= $1::timestamptz
+ORDER BY purge_after
+LIMIT $2
+`
+
+type ListOrganizationsDueForPurgeParams struct {
+	Cutoff    time.Time `json:"cutoff"`
+	PageLimit int32     `json:"page_limit"`
+}
+
+func (q *Queries) ListOrganizationsDueForPurge(ctx context.Context, arg ListOrganizationsDueForPurgeParams) ([]Organization, error) {
+	rows, err := q.db.Query(ctx, listOrganizationsDueForPurge, arg.Cutoff, arg.PageLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Organization{}
+	for rows.Next() {
+		var i Organization
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Name,
+			&i.MaxClinics,
+			&i.MaxDentists,
+			&i.MaxRequestsPerMinute,
+			&i.MaxStorageMb,
+			&i.Status,
+			&i.TrialEndsAt,
+			&i.ClosedAt,
+			&i.ExportKey,
+			&i.PurgeAfter,
+			&i.PurgedAt,
+			&i.Isolation,
+			&i.SchemaName,
+			&i.Plan,
+			&i.DataRegion,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSchemaOrganizations = `-- name: ListSchemaOrganizations :many
+SELECT id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+FROM organizations
+WHERE isolation = 'SCHEMA'
+  AND purged_at IS NULL
+ORDER BY id
+`
+
+func (q *Queries) ListSchemaOrganizations(ctx context.Context) ([]Organization, error) {
+	rows, err := q.db.Query(ctx, listSchemaOrganizations)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Organization{}
+	for rows.Next() {
+		var i Organization
+		if err := rows.Scan(
+			&i.ID,
+			&i.Slug,
+			&i.Name,
+			&i.MaxClinics,
+			&i.MaxDentists,
+			&i.MaxRequestsPerMinute,
+			&i.MaxStorageMb,
+			&i.Status,
+			&i.TrialEndsAt,
+			&i.ClosedAt,
+			&i.ExportKey,
+			&i.PurgeAfter,
+			&i.PurgedAt,
+			&i.Isolation,
+			&i.SchemaName,
+			&i.Plan,
+			&i.DataRegion,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockOrganizationForUpdate = `-- name: LockOrganizationForUpdate :one
+SELECT id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+FROM organizations
+WHERE id = $1::uuid
+FOR UPDATE
+`
+
+func (q *Queries) LockOrganizationForUpdate(ctx context.Context, organizationID string) (Organization, error) {
+	row := q.db.QueryRow(ctx, lockOrganizationForUpdate, organizationID)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const markOrganizationPurged = `-- name: MarkOrganizationPurged :exec
+UPDATE organizations
+SET purged_at = CURRENT_TIMESTAMP,
+    export_key = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1::uuid
+`
+
+func (q *Queries) MarkOrganizationPurged(ctx context.Context, id string) error {
+	_, err := q.db.Exec(ctx, markOrganizationPurged, id)
+	return err
+}
+
+const setOrganizationExport = `-- name: SetOrganizationExport :one
+UPDATE organizations
+SET export_key = $1,
+    purge_after = $2::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $3::uuid
+  AND status = 'CLOSED'
+RETURNING id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+`
+
+type SetOrganizationExportParams struct {
+	ExportKey  sql.NullString `json:"export_key"`
+	PurgeAfter time.Time      `json:"purge_after"`
+	ID         string         `json:"id"`
+}
+
+func (q *Queries) SetOrganizationExport(ctx context.Context, arg SetOrganizationExportParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, setOrganizationExport, arg.ExportKey, arg.PurgeAfter, arg.ID)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateOrganizationDataRegion = `-- name: UpdateOrganizationDataRegion :one
+UPDATE organizations
+SET data_region = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2::uuid
+  AND status <> 'CLOSED'
+RETURNING id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+`
+
+type UpdateOrganizationDataRegionParams struct {
+	DataRegion sql.NullString `json:"data_region"`
+	ID         string         `json:"id"`
+}
+
+func (q *Queries) UpdateOrganizationDataRegion(ctx context.Context, arg UpdateOrganizationDataRegionParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, updateOrganizationDataRegion, arg.DataRegion, arg.ID)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateOrganizationPlan = `-- name: UpdateOrganizationPlan :one
+UPDATE organizations
+SET plan = $1,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $2::uuid
+  AND status <> 'CLOSED'
+RETURNING id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+`
+
+type UpdateOrganizationPlanParams struct {
+	Plan string `json:"plan"`
+	ID   string `json:"id"`
+}
+
+func (q *Queries) UpdateOrganizationPlan(ctx context.Context, arg UpdateOrganizationPlanParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, updateOrganizationPlan, arg.Plan, arg.ID)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateOrganizationQuotas = `-- name: UpdateOrganizationQuotas :one
+UPDATE organizations
+SET max_clinics = $1,
+    max_dentists = $2,
+    max_requests_per_minute = $3,
+    max_storage_mb = $4,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $5::uuid
+RETURNING id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+`
+
+type UpdateOrganizationQuotasParams struct {
+	MaxClinics           sql.NullInt32 `json:"max_clinics"`
+	MaxDentists          sql.NullInt32 `json:"max_dentists"`
+	MaxRequestsPerMinute sql.NullInt32 `json:"max_requests_per_minute"`
+	MaxStorageMb         sql.NullInt32 `json:"max_storage_mb"`
+	ID                   string        `json:"id"`
+}
+
+func (q *Queries) UpdateOrganizationQuotas(ctx context.Context, arg UpdateOrganizationQuotasParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, updateOrganizationQuotas,
+		arg.MaxClinics,
+		arg.MaxDentists,
+		arg.MaxRequestsPerMinute,
+		arg.MaxStorageMb,
+		arg.ID,
+	)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const updateOrganizationStatus = `-- name: UpdateOrganizationStatus :one
+UPDATE organizations
+SET status = $1,
+    trial_ends_at = $2,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $3::uuid
+  AND status <> 'CLOSED'
+RETURNING id, slug, name, max_clinics, max_dentists, max_requests_per_minute, max_storage_mb, status, trial_ends_at, closed_at, export_key, purge_after, purged_at, isolation, schema_name, plan, data_region, created_at, updated_at
+`
+
+type UpdateOrganizationStatusParams struct {
+	Status      string       `json:"status"`
+	TrialEndsAt sql.NullTime `json:"trial_ends_at"`
+	ID          string       `json:"id"`
+}
+
+func (q *Queries) UpdateOrganizationStatus(ctx context.Context, arg UpdateOrganizationStatusParams) (Organization, error) {
+	row := q.db.QueryRow(ctx, updateOrganizationStatus, arg.Status, arg.TrialEndsAt, arg.ID)
+	var i Organization
+	err := row.Scan(
+		&i.ID,
+		&i.Slug,
+		&i.Name,
+		&i.MaxClinics,
+		&i.MaxDentists,
+		&i.MaxRequestsPerMinute,
+		&i.MaxStorageMb,
+		&i.Status,
+		&i.TrialEndsAt,
+		&i.ClosedAt,
+		&i.ExportKey,
+		&i.PurgeAfter,
+		&i.PurgedAt,
+		&i.Isolation,
+		&i.SchemaName,
+		&i.Plan,
+		&i.DataRegion,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -8,11 +8,13 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"time"
 )
 
 const createPerson = `-- name: CreatePerson :one
 INSERT INTO people (
     id,
+    organization_id,
     person_type,
     tax_id_type,
     tax_id_number,
@@ -22,31 +24,34 @@ INSERT INTO people (
     phone
 ) VALUES (
     $1::uuid,
-    $2,
+    $2::uuid,
     $3,
     $4,
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9
 )
-RETURNING id, person_type, tax_id_type, tax_id_number, legal_name, trade_name, email, phone, created_at, updated_at, deleted_at
+RETURNING id, organization_id, person_type, tax_id_type, tax_id_number, legal_name, trade_name, email, phone, created_at, updated_at, deleted_at, anonymized_at
 `
 
 type CreatePersonParams struct {
-	ID          string         `json:"id"`
-	PersonType  string         `json:"person_type"`
-	TaxIDType   string         `json:"tax_id_type"`
-	TaxIDNumber string         `json:"tax_id_number"`
-	LegalName   string         `json:"legal_name"`
-	TradeName   sql.NullString `json:"trade_name"`
-	Email       sql.NullString `json:"email"`
-	Phone       sql.NullString `json:"phone"`
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
+	PersonType     string         `json:"person_type"`
+	TaxIDType      string         `json:"tax_id_type"`
+	TaxIDNumber    string         `json:"tax_id_number"`
+	LegalName      string         `json:"legal_name"`
+	TradeName      sql.NullString `json:"trade_name"`
+	Email          sql.NullString `json:"email"`
+	Phone          sql.NullString `json:"phone"`
 }
 
 func (q *Queries) CreatePerson(ctx context.Context, arg CreatePersonParams) (Person, error) {
-	row := q.db.QueryRowContext(ctx, createPerson,
+	row := q.db.QueryRow(ctx, createPerson,
 		arg.ID,
+		arg.OrganizationID,
 		arg.PersonType,
 		arg.TaxIDType,
 		arg.TaxIDNumber,
@@ -58,6 +63,7 @@ func (q *Queries) CreatePerson(ctx context.Context, arg CreatePersonParams) (Per
 	var i Person
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.PersonType,
 		&i.TaxIDType,
 		&i.TaxIDNumber,
@@ -68,6 +74,7 @@ func (q *Queries) CreatePerson(ctx context.Context, arg CreatePersonParams) (Per
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
@@ -77,30 +84,93 @@ UPDATE people
 SET deleted_at = CURRENT_TIMESTAMP,
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $1::uuid
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
 `
 
-func (q *Queries) DeletePerson(ctx context.Context, id string) (int64, error) {
-	result, err := q.db.ExecContext(ctx, deletePerson, id)
+type DeletePersonParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) DeletePerson(ctx context.Context, arg DeletePersonParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deletePerson, arg.ID, arg.OrganizationID)
 	if err != nil {
 		return 0, err
 	}
-	return result.RowsAffected()
+	return result.RowsAffected(), nil
+}
+
+const deleteUnusedPersonAt = `-- name: DeleteUnusedPersonAt :execrows
+UPDATE people p
+SET deleted_at = $1::timestamptz,
+    updated_at = CURRENT_TIMESTAMP
+WHERE p.id = $2::uuid
+  AND p.organization_id = $3::uuid
+  AND p.deleted_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM clinics c WHERE c.person_id = p.id AND c.deleted_at IS NULL)
+  AND NOT EXISTS (SELECT 1 FROM dentists d WHERE d.person_id = p.id AND d.deleted_at IS NULL)
+  AND NOT EXISTS (SELECT 1 FROM suppliers s WHERE s.person_id = p.id AND s.deleted_at IS NULL)
+`
+
+type DeleteUnusedPersonAtParams struct {
+	DeletedAt      time.Time `json:"deleted_at"`
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+}
+
+func (q *Queries) DeleteUnusedPersonAt(ctx context.Context, arg DeleteUnusedPersonAtParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUnusedPersonAt, arg.DeletedAt, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const existsOtherActivePersonByTaxID = `-- name: ExistsOtherActivePersonByTaxID :one
+SELECT EXISTS (
+    SELECT 1
+    FROM people
+    WHERE tax_id_number = $1
+      AND organization_id = $2::uuid
+      AND id <> $3::uuid
+      AND deleted_at IS NULL
+)
+`
+
+type ExistsOtherActivePersonByTaxIDParams struct {
+	TaxIDNumber    string `json:"tax_id_number"`
+	OrganizationID string `json:"organization_id"`
+	PersonID       string `json:"person_id"`
+}
+
+func (q *Queries) ExistsOtherActivePersonByTaxID(ctx context.Context, arg ExistsOtherActivePersonByTaxIDParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsOtherActivePersonByTaxID, arg.TaxIDNumber, arg.OrganizationID, arg.PersonID)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
 }
 
 const getPersonByTaxID = `-- name: GetPersonByTaxID :one
-SELECT id, person_type, tax_id_type, tax_id_number, legal_name, trade_name, email, phone, created_at, updated_at, deleted_at
+SELECT id, organization_id, person_type, tax_id_type, tax_id_number, legal_name, trade_name, email, phone, created_at, updated_at, deleted_at, anonymized_at
 FROM people
 WHERE tax_id_number = $1
+  AND organization_id = $2::uuid
   AND deleted_at IS NULL
 LIMIT 1
 `
 
-func (q *Queries) GetPersonByTaxID(ctx context.Context, taxIDNumber string) (Person, error) {
-	row := q.db.QueryRowContext(ctx, getPersonByTaxID, taxIDNumber)
+type GetPersonByTaxIDParams struct {
+	TaxIDNumber    string `json:"tax_id_number"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) GetPersonByTaxID(ctx context.Context, arg GetPersonByTaxIDParams) (Person, error) {
+	row := q.db.QueryRow(ctx, getPersonByTaxID, arg.TaxIDNumber, arg.OrganizationID)
 	var i Person
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.PersonType,
 		&i.TaxIDType,
 		&i.TaxIDNumber,
@@ -111,10 +181,67 @@ func (q *Queries) GetPersonByTaxID(ctx context.Context, taxIDNumber string) (Per
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
 
+const getPersonIncludingDeleted = `-- name: GetPersonIncludingDeleted :one
+SELECT id, organization_id, person_type, tax_id_type, tax_id_number, legal_name, trade_name, email, phone, created_at, updated_at, deleted_at, anonymized_at
+FROM people
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+LIMIT 1
+`
+
+type GetPersonIncludingDeletedParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) GetPersonIncludingDeleted(ctx context.Context, arg GetPersonIncludingDeletedParams) (Person, error) {
+	row := q.db.QueryRow(ctx, getPersonIncludingDeleted, arg.ID, arg.OrganizationID)
+	var i Person
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.PersonType,
+		&i.TaxIDType,
+		&i.TaxIDNumber,
+		&i.LegalName,
+		&i.TradeName,
+		&i.Email,
+		&i.Phone,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+		&i.AnonymizedAt,
+	)
+	return i, err
+}
+
+const restorePerson = `-- name: RestorePerson :execrows
+UPDATE people
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NOT NULL
+`
+
+type RestorePersonParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) RestorePerson(ctx context.Context, arg RestorePersonParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restorePerson, arg.ID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const updatePerson = `-- name: UpdatePerson :one
 UPDATE people
 SET
@@ -124,29 +251,33 @@ SET
     phone = COALESCE($4, phone),
     updated_at = CURRENT_TIMESTAMP
 WHERE id = $5::uuid
+  AND organization_id = $6::uuid
   AND deleted_at IS NULL
-RETURNING id, person_type, tax_id_type, tax_id_number, legal_name, trade_name, email, phone, created_at, updated_at, deleted_at
+RETURNING id, organization_id, person_type, tax_id_type, tax_id_number, legal_name, trade_name, email, phone, created_at, updated_at, deleted_at, anonymized_at
 `
 
 type UpdatePersonParams struct {
-	LegalName sql.NullString `json:"legal_name"`
-	TradeName sql.NullString `json:"trade_name"`
-	Email     sql.NullString `json:"email"`
-	Phone     sql.NullString `json:"phone"`
-	ID        string         `json:"id"`
+	LegalName      sql.NullString `json:"legal_name"`
+	TradeName      sql.NullString `json:"trade_name"`
+	Email          sql.NullString `json:"email"`
+	Phone          sql.NullString `json:"phone"`
+	ID             string         `json:"id"`
+	OrganizationID string         `json:"organization_id"`
 }
 
 func (q *Queries) UpdatePerson(ctx context.Context, arg UpdatePersonParams) (Person, error) {
-	row := q.db.QueryRowContext(ctx, updatePerson,
+	row := q.db.QueryRow(ctx, updatePerson,
 		arg.LegalName,
 		arg.TradeName,
 		arg.Email,
 		arg.Phone,
 		arg.ID,
+		arg.OrganizationID,
 	)
 	var i Person
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.PersonType,
 		&i.TaxIDType,
 		&i.TaxIDNumber,
@@ -157,6 +288,7 @@ func (q *Queries) UpdatePerson(ctx context.Context, arg UpdatePersonParams) (Per
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
+		&i.AnonymizedAt,
 	)
 	return i, err
 }
@@ -0,0 +1,55 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: platform_operators.sql
+
+package repository
+
+import (
+	"context"
+)
+
+const createPlatformOperator = `-- name: CreatePlatformOperator :one
+INSERT INTO platform_operators (id, email, password_hash)
+VALUES ($1::uuid, $2, $3)
+RETURNING id, email, password_hash, created_at, updated_at
+`
+
+type CreatePlatformOperatorParams struct {
+	ID           string `json:"id"`
+	Email        string `json:"email"`
+	PasswordHash string `json:"password_hash"`
+}
+
+func (q *Queries) CreatePlatformOperator(ctx context.Context, arg CreatePlatformOperatorParams) (PlatformOperator, error) {
+	row := q.db.QueryRow(ctx, createPlatformOperator, arg.ID, arg.Email, arg.PasswordHash)
+	var i PlatformOperator
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getPlatformOperatorByEmail = `-- name: GetPlatformOperatorByEmail :one
+SELECT id, email, password_hash, created_at, updated_at
+FROM platform_operators
+WHERE lower(email) = lower($1)
+LIMIT 1
+`
+
+func (q *Queries) GetPlatformOperatorByEmail(ctx context.Context, email string) (PlatformOperator, error) {
+	row := q.db.QueryRow(ctx, getPlatformOperatorByEmail, email)
+	var i PlatformOperator
+	err := row.Scan(
+		&i.ID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
@@ -6,41 +6,491 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
 )
 
 type Querier interface {
-	CountActiveClinicLinksByDentist(ctx context.Context, dentistID string) (int64, error)
+	AddDentistSpecialty(ctx context.Context, arg AddDentistSpecialtyParams) error
+	AdvanceAuditExportCheckpoint(ctx context.Context, arg AdvanceAuditExportCheckpointParams) error
+	AdvanceWarehouseExportWatermark(ctx context.Context, arg AdvanceWarehouseExportWatermarkParams) error
+	AnonymizeClinicRecords(ctx context.Context, arg AnonymizeClinicRecordsParams) error
+	AnonymizeDentistRecords(ctx context.Context, arg AnonymizeDentistRecordsParams) error
+	AnonymizePerson(ctx context.Context, arg AnonymizePersonParams) (int64, error)
+	ApproveBankAccountHolder(ctx context.Context, arg ApproveBankAccountHolderParams) (int64, error)
+	AssignClinicPayablesToBatch(ctx context.Context, arg AssignClinicPayablesToBatchParams) ([]int64, error)
+	ClaimDueAttachmentScans(ctx context.Context, arg ClaimDueAttachmentScansParams) ([]AttachmentScan, error)
+	ClaimDueNotifications(ctx context.Context, arg ClaimDueNotificationsParams) ([]Notification, error)
+	ClaimDueReportDefinitions(ctx context.Context, arg ClaimDueReportDefinitionsParams) ([]ReportDefinition, error)
+	ClaimDueReports(ctx context.Context, arg ClaimDueReportsParams) ([]Report, error)
+	ClearPrimaryBankAccount(ctx context.Context, arg ClearPrimaryBankAccountParams) error
+	CloseDocumentSignatureRequest(ctx context.Context, arg CloseDocumentSignatureRequestParams) (int64, error)
+	CloseOrganization(ctx context.Context, id string) (Organization, error)
+	CloseTimeClockEntry(ctx context.Context, arg CloseTimeClockEntryParams) (TimeClockEntry, error)
+	CompleteAttachmentScan(ctx context.Context, arg CompleteAttachmentScanParams) (int64, error)
+	CompletePayoutBatch(ctx context.Context, arg CompletePayoutBatchParams) error
+	CompleteReport(ctx context.Context, arg CompleteReportParams) (int64, error)
+	CompleteReportDefinitionRun(ctx context.Context, arg CompleteReportDefinitionRunParams) error
+	CopyAddress(ctx context.Context, arg CopyAddressParams) (int64, error)
+	CopyDentistAnnouncementRecipients(ctx context.Context, arg CopyDentistAnnouncementRecipientsParams) (int64, error)
+	CopyDentistSpecialties(ctx context.Context, arg CopyDentistSpecialtiesParams) (int64, error)
+	CountActiveBranches(ctx context.Context, arg CountActiveBranchesParams) (int32, error)
+	CountActiveClinicLinksByDentist(ctx context.Context, arg CountActiveClinicLinksByDentistParams) (int64, error)
+	CountActiveSpecialtiesByIDs(ctx context.Context, arg CountActiveSpecialtiesByIDsParams) (int64, error)
+	CountClinicRoleHolders(ctx context.Context, arg CountClinicRoleHoldersParams) (CountClinicRoleHoldersRow, error)
+	CountDistinctActiveDentistsInClinicGroup(ctx context.Context, arg CountDistinctActiveDentistsInClinicGroupParams) (int32, error)
+	CountPayoutBatchItemsOnFinancialHold(ctx context.Context, arg CountPayoutBatchItemsOnFinancialHoldParams) (int32, error)
+	CountPendingBankAccountRemovals(ctx context.Context, arg CountPendingBankAccountRemovalsParams) (int32, error)
+	CountUnsealedAuditLogs(ctx context.Context, organizationID string) (int64, error)
+	CreateAttachmentScan(ctx context.Context, arg CreateAttachmentScanParams) (AttachmentScan, error)
+	CreateAuditChainHead(ctx context.Context, arg CreateAuditChainHeadParams) error
+	CreateAuditLog(ctx context.Context, arg CreateAuditLogParams) error
+	CreateAuditLogs(ctx context.Context, arg CreateAuditLogsParams) error
+	CreateAutomatedClinicTask(ctx context.Context, arg CreateAutomatedClinicTaskParams) (int64, error)
 	CreateBankAccount(ctx context.Context, arg CreateBankAccountParams) (BankAccount, error)
+	CreateBankAccountChange(ctx context.Context, arg CreateBankAccountChangeParams) (BankAccountChange, error)
+	CreateBankAccounts(ctx context.Context, arg CreateBankAccountsParams) error
 	CreateClinic(ctx context.Context, arg CreateClinicParams) (Clinic, error)
+	CreateClinicAnnouncement(ctx context.Context, arg CreateClinicAnnouncementParams) (ClinicAnnouncement, error)
+	CreateClinicAnnouncementRecipients(ctx context.Context, arg CreateClinicAnnouncementRecipientsParams) error
 	CreateClinicDentist(ctx context.Context, arg CreateClinicDentistParams) (ClinicDentist, error)
+	CreateClinicFinancialHold(ctx context.Context, arg CreateClinicFinancialHoldParams) error
+	CreateClinicHoliday(ctx context.Context, arg CreateClinicHolidayParams) (ClinicHoliday, error)
+	CreateClinicNote(ctx context.Context, arg CreateClinicNoteParams) (ClinicNote, error)
+	CreateClinicNoteMention(ctx context.Context, arg CreateClinicNoteMentionParams) error
+	CreateClinicOnboardingTransition(ctx context.Context, arg CreateClinicOnboardingTransitionParams) (ClinicOnboardingTransition, error)
+	CreateClinicOperatingHours(ctx context.Context, arg CreateClinicOperatingHoursParams) error
+	CreateClinicPayable(ctx context.Context, arg CreateClinicPayableParams) (ClinicPayable, error)
+	CreateClinicRevision(ctx context.Context, arg CreateClinicRevisionParams) error
+	CreateClinicShift(ctx context.Context, arg CreateClinicShiftParams) (ClinicShift, error)
+	CreateClinicTask(ctx context.Context, arg CreateClinicTaskParams) (ClinicTask, error)
+	CreateClinicTaskRule(ctx context.Context, arg CreateClinicTaskRuleParams) (ClinicTaskRule, error)
 	CreateDentist(ctx context.Context, arg CreateDentistParams) (Dentist, error)
+	CreateDentistDocument(ctx context.Context, arg CreateDentistDocumentParams) (DentistDocument, error)
+	CreateDocumentSignatureRequest(ctx context.Context, arg CreateDocumentSignatureRequestParams) (DocumentSignatureRequest, error)
+	CreateDocumentTemplate(ctx context.Context, arg CreateDocumentTemplateParams) (DocumentTemplate, error)
+	CreateEquipment(ctx context.Context, arg CreateEquipmentParams) (Equipment, error)
+	CreateEquipmentMaintenanceRecord(ctx context.Context, arg CreateEquipmentMaintenanceRecordParams) (EquipmentMaintenanceRecord, error)
+	CreateGeneratedDocument(ctx context.Context, arg CreateGeneratedDocumentParams) (GeneratedDocument, error)
+	CreateInventoryItem(ctx context.Context, arg CreateInventoryItemParams) (InventoryItem, error)
+	CreateInventoryMovement(ctx context.Context, arg CreateInventoryMovementParams) (InventoryMovement, error)
+	CreateLedgerEntry(ctx context.Context, arg CreateLedgerEntryParams) error
+	CreateLedgerTransaction(ctx context.Context, arg CreateLedgerTransactionParams) error
+	CreateLegalHold(ctx context.Context, arg CreateLegalHoldParams) error
+	CreateNotification(ctx context.Context, arg CreateNotificationParams) error
+	CreateNotificationSuppression(ctx context.Context, arg CreateNotificationSuppressionParams) error
+	CreateOrganization(ctx context.Context, arg CreateOrganizationParams) (Organization, error)
+	CreateOrganizationKey(ctx context.Context, arg CreateOrganizationKeyParams) error
+	CreatePayoutBatch(ctx context.Context, arg CreatePayoutBatchParams) (PayoutBatch, error)
+	CreatePayoutBatchItem(ctx context.Context, arg CreatePayoutBatchItemParams) error
+	CreatePendingDeletion(ctx context.Context, arg CreatePendingDeletionParams) error
 	CreatePerson(ctx context.Context, arg CreatePersonParams) (Person, error)
+	CreatePlatformOperator(ctx context.Context, arg CreatePlatformOperatorParams) (PlatformOperator, error)
+	CreatePurchaseOrder(ctx context.Context, arg CreatePurchaseOrderParams) (PurchaseOrder, error)
+	CreatePurchaseOrderItem(ctx context.Context, arg CreatePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	CreatePurchaseOrderReceipt(ctx context.Context, arg CreatePurchaseOrderReceiptParams) (PurchaseOrderReceipt, error)
+	CreateReport(ctx context.Context, arg CreateReportParams) (Report, error)
+	CreateReportDefinition(ctx context.Context, arg CreateReportDefinitionParams) (ReportDefinition, error)
+	CreateSpecialty(ctx context.Context, arg CreateSpecialtyParams) (Specialty, error)
+	CreateSupplier(ctx context.Context, arg CreateSupplierParams) (Supplier, error)
+	CreateTimeClockEntry(ctx context.Context, arg CreateTimeClockEntryParams) (TimeClockEntry, error)
+	CreateUsageRecord(ctx context.Context, arg CreateUsageRecordParams) error
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeactivateClinic(ctx context.Context, arg DeactivateClinicParams) (int64, error)
+	DeleteAddressByPersonID(ctx context.Context, arg DeleteAddressByPersonIDParams) error
 	DeleteBankAccountByIDAndClinicID(ctx context.Context, arg DeleteBankAccountByIDAndClinicIDParams) (int64, error)
-	DeleteBankAccountsByClinicID(ctx context.Context, clinicID string) (int64, error)
-	DeleteClinic(ctx context.Context, id string) (int64, error)
-	DeleteDentist(ctx context.Context, id string) (int64, error)
-	DeletePerson(ctx context.Context, id string) (int64, error)
+	DeleteBankAccountsByClinicID(ctx context.Context, arg DeleteBankAccountsByClinicIDParams) (int64, error)
+	DeleteBankAccountsByClinicIDAt(ctx context.Context, arg DeleteBankAccountsByClinicIDAtParams) (int64, error)
+	DeleteClinic(ctx context.Context, arg DeleteClinicParams) (int64, error)
+	DeleteClinicChildRecords(ctx context.Context, arg DeleteClinicChildRecordsParams) error
+	DeleteClinicHoliday(ctx context.Context, arg DeleteClinicHolidayParams) (int64, error)
+	DeleteClinicNote(ctx context.Context, arg DeleteClinicNoteParams) (int64, error)
+	DeleteClinicOperatingHours(ctx context.Context, arg DeleteClinicOperatingHoursParams) (int64, error)
+	DeleteClinicShift(ctx context.Context, arg DeleteClinicShiftParams) (int64, error)
+	DeleteClinicTask(ctx context.Context, arg DeleteClinicTaskParams) (int64, error)
+	DeleteClinicTaskRules(ctx context.Context, arg DeleteClinicTaskRulesParams) (int64, error)
+	DeleteDentist(ctx context.Context, arg DeleteDentistParams) (int64, error)
+	DeleteDentistAnnouncementRecipients(ctx context.Context, arg DeleteDentistAnnouncementRecipientsParams) (int64, error)
+	DeleteDentistChildRecords(ctx context.Context, arg DeleteDentistChildRecordsParams) error
+	DeleteDentistDocument(ctx context.Context, arg DeleteDentistDocumentParams) (int64, error)
+	DeleteDentistDocumentsByDentist(ctx context.Context, arg DeleteDentistDocumentsByDentistParams) (int64, error)
+	DeleteDentistDocumentsByDentistAt(ctx context.Context, arg DeleteDentistDocumentsByDentistAtParams) (int64, error)
+	DeleteDentistNoteMentions(ctx context.Context, arg DeleteDentistNoteMentionsParams) (int64, error)
+	DeleteDentistNotificationPreferences(ctx context.Context, arg DeleteDentistNotificationPreferencesParams) (int64, error)
+	DeleteDentistSpecialtiesByDentist(ctx context.Context, arg DeleteDentistSpecialtiesByDentistParams) (int64, error)
+	DeleteDentistSpecialtiesBySpecialty(ctx context.Context, arg DeleteDentistSpecialtiesBySpecialtyParams) (int64, error)
+	DeleteDocumentRetentionRule(ctx context.Context, arg DeleteDocumentRetentionRuleParams) (int64, error)
+	DeleteDocumentTemplate(ctx context.Context, arg DeleteDocumentTemplateParams) (int64, error)
+	DeleteEquipment(ctx context.Context, arg DeleteEquipmentParams) (int64, error)
+	DeleteInventoryItem(ctx context.Context, arg DeleteInventoryItemParams) (int64, error)
+	DeleteNotificationSuppression(ctx context.Context, arg DeleteNotificationSuppressionParams) (int64, error)
+	DeleteOrganization(ctx context.Context, id string) error
+	DeleteOrphanedPerson(ctx context.Context, arg DeleteOrphanedPersonParams) (int64, error)
+	DeletePendingDeletion(ctx context.Context, arg DeletePendingDeletionParams) (int64, error)
+	DeletePerson(ctx context.Context, arg DeletePersonParams) (int64, error)
+	DeleteReport(ctx context.Context, arg DeleteReportParams) error
+	DeleteReportDefinition(ctx context.Context, arg DeleteReportDefinitionParams) (int64, error)
+	DeleteSpecialty(ctx context.Context, arg DeleteSpecialtyParams) (int64, error)
+	DeleteSupplier(ctx context.Context, arg DeleteSupplierParams) (int64, error)
+	DeleteUnusedPersonAt(ctx context.Context, arg DeleteUnusedPersonAtParams) (int64, error)
+	DeleteUsersByDentistID(ctx context.Context, arg DeleteUsersByDentistIDParams) (int64, error)
 	EndClinicDentist(ctx context.Context, arg EndClinicDentistParams) (int64, error)
-	EndClinicDentistsByClinic(ctx context.Context, clinicID string) (int64, error)
-	EndClinicDentistsByDentist(ctx context.Context, dentistID string) (int64, error)
+	EndClinicDentistsByClinic(ctx context.Context, arg EndClinicDentistsByClinicParams) (int64, error)
+	EndClinicDentistsByDentist(ctx context.Context, arg EndClinicDentistsByDentistParams) (int64, error)
+	EndDueTemporaryClinicDentist(ctx context.Context, arg EndDueTemporaryClinicDentistParams) (int64, error)
+	EnsureAuditExportCheckpoint(ctx context.Context, arg EnsureAuditExportCheckpointParams) error
+	EnsureWarehouseExportWatermark(ctx context.Context, arg EnsureWarehouseExportWatermarkParams) error
+	ExistsOtherActiveDentistByCRO(ctx context.Context, arg ExistsOtherActiveDentistByCROParams) (bool, error)
+	ExistsOtherActiveDentistByPersonID(ctx context.Context, arg ExistsOtherActiveDentistByPersonIDParams) (bool, error)
+	ExistsOtherActivePersonByTaxID(ctx context.Context, arg ExistsOtherActivePersonByTaxIDParams) (bool, error)
+	ExistsPendingDocumentSignatureRequest(ctx context.Context, arg ExistsPendingDocumentSignatureRequestParams) (bool, error)
+	ExistsUserEmailConflictForDentist(ctx context.Context, arg ExistsUserEmailConflictForDentistParams) (bool, error)
+	// Password hashes stay out of the archive; everything else the organization owns goes in.
+	// jsonb_build_object takes at most 100 arguments, so later tables go in another object.
+	ExportOrganizationData(ctx context.Context, organizationID string) (json.RawMessage, error)
+	FailPendingNotificationsForRecipient(ctx context.Context, arg FailPendingNotificationsForRecipientParams) (int64, error)
+	FailReport(ctx context.Context, arg FailReportParams) (int64, error)
 	GetActiveClinicDentist(ctx context.Context, arg GetActiveClinicDentistParams) (ClinicDentist, error)
+	GetAddressByPersonID(ctx context.Context, arg GetAddressByPersonIDParams) (Address, error)
+	GetAssignedTaskForUpdate(ctx context.Context, arg GetAssignedTaskForUpdateParams) (ClinicTask, error)
+	GetAuditChainHead(ctx context.Context, organizationID string) (AuditChainHead, error)
+	GetAuditChainHeadForUpdate(ctx context.Context, organizationID string) (AuditChainHead, error)
+	GetAuditExportCheckpointForUpdate(ctx context.Context, arg GetAuditExportCheckpointForUpdateParams) (AuditExportCheckpoint, error)
 	GetBankAccountByIDAndClinicID(ctx context.Context, arg GetBankAccountByIDAndClinicIDParams) (BankAccount, error)
-	GetClinicByID(ctx context.Context, id string) (Clinic, error)
-	GetClinicDetails(ctx context.Context, id string) (GetClinicDetailsRow, error)
-	GetDentistByID(ctx context.Context, id string) (Dentist, error)
-	GetDentistByPersonID(ctx context.Context, personID string) (Dentist, error)
-	GetDentistDetailsByID(ctx context.Context, id string) (GetDentistDetailsByIDRow, error)
-	GetPersonByTaxID(ctx context.Context, taxIDNumber string) (Person, error)
+	GetBankAccountByVerificationReference(ctx context.Context, reference string) (BankAccount, error)
+	GetBankAccountChange(ctx context.Context, arg GetBankAccountChangeParams) (BankAccountChange, error)
+	GetClinicAggregate(ctx context.Context, arg GetClinicAggregateParams) (GetClinicAggregateRow, error)
+	GetClinicAnnouncement(ctx context.Context, arg GetClinicAnnouncementParams) (GetClinicAnnouncementRow, error)
+	GetClinicAnnouncementByID(ctx context.Context, arg GetClinicAnnouncementByIDParams) (ClinicAnnouncement, error)
+	GetClinicByID(ctx context.Context, arg GetClinicByIDParams) (Clinic, error)
+	GetClinicDeletePreviewCounts(ctx context.Context, arg GetClinicDeletePreviewCountsParams) (GetClinicDeletePreviewCountsRow, error)
+	GetClinicDetails(ctx context.Context, arg GetClinicDetailsParams) (GetClinicDetailsRow, error)
+	GetClinicDetailsIncludingDeleted(ctx context.Context, arg GetClinicDetailsIncludingDeletedParams) (GetClinicDetailsIncludingDeletedRow, error)
+	GetClinicDirectoryListing(ctx context.Context, arg GetClinicDirectoryListingParams) (ClinicDirectoryListing, error)
+	GetClinicFinancialHold(ctx context.Context, arg GetClinicFinancialHoldParams) (GetClinicFinancialHoldRow, error)
+	GetClinicForRevision(ctx context.Context, arg GetClinicForRevisionParams) (Clinic, error)
+	GetClinicLedgerBalance(ctx context.Context, arg GetClinicLedgerBalanceParams) (int64, error)
+	GetClinicNoteDetails(ctx context.Context, arg GetClinicNoteDetailsParams) (GetClinicNoteDetailsRow, error)
+	GetClinicNotificationDeliverability(ctx context.Context, arg GetClinicNotificationDeliverabilityParams) ([]GetClinicNotificationDeliverabilityRow, error)
+	GetClinicRegistryRecordByClinicID(ctx context.Context, arg GetClinicRegistryRecordByClinicIDParams) (ClinicRegistryRecord, error)
+	GetClinicSettings(ctx context.Context, arg GetClinicSettingsParams) (ClinicSetting, error)
+	GetClinicShift(ctx context.Context, arg GetClinicShiftParams) (ClinicShift, error)
+	GetClinicTaskForUpdate(ctx context.Context, arg GetClinicTaskForUpdateParams) (ClinicTask, error)
+	GetClinicTimeClockSettings(ctx context.Context, arg GetClinicTimeClockSettingsParams) (ClinicTimeClockSetting, error)
+	GetDentistByID(ctx context.Context, arg GetDentistByIDParams) (Dentist, error)
+	GetDentistByPersonID(ctx context.Context, arg GetDentistByPersonIDParams) (Dentist, error)
+	GetDentistDetailsByID(ctx context.Context, arg GetDentistDetailsByIDParams) (GetDentistDetailsByIDRow, error)
+	GetDentistDetailsIncludingDeleted(ctx context.Context, arg GetDentistDetailsIncludingDeletedParams) (GetDentistDetailsIncludingDeletedRow, error)
+	GetDentistDocument(ctx context.Context, arg GetDentistDocumentParams) (DentistDocument, error)
+	GetDentistNotificationPreferences(ctx context.Context, arg GetDentistNotificationPreferencesParams) (DentistNotificationPreference, error)
+	GetDentistOrganizationID(ctx context.Context, id string) (string, error)
+	GetDocumentSignatureRequest(ctx context.Context, arg GetDocumentSignatureRequestParams) (DocumentSignatureRequest, error)
+	GetDocumentSignatureRequestByReference(ctx context.Context, reference string) (DocumentSignatureRequest, error)
+	GetDocumentTemplate(ctx context.Context, arg GetDocumentTemplateParams) (DocumentTemplate, error)
+	GetEquipment(ctx context.Context, arg GetEquipmentParams) (Equipment, error)
+	GetGeneratedDocument(ctx context.Context, arg GetGeneratedDocumentParams) (GeneratedDocument, error)
+	GetInventoryItem(ctx context.Context, arg GetInventoryItemParams) (InventoryItem, error)
+	GetLatestAttachmentScan(ctx context.Context, arg GetLatestAttachmentScanParams) (AttachmentScan, error)
+	GetLegalHold(ctx context.Context, arg GetLegalHoldParams) (GetLegalHoldRow, error)
+	// Deleted clinics and dentists can be held too, since the hold exists to stop their purge.
+	GetLegalHoldResourceClinic(ctx context.Context, arg GetLegalHoldResourceClinicParams) (string, error)
+	GetMergedDentistShiftOverlap(ctx context.Context, arg GetMergedDentistShiftOverlapParams) (GetMergedDentistShiftOverlapRow, error)
+	GetNotification(ctx context.Context, arg GetNotificationParams) (Notification, error)
+	GetNotificationForDeliveryCallback(ctx context.Context, id string) (Notification, error)
+	GetOldestAdminUser(ctx context.Context, organizationID string) (User, error)
+	GetOpenTimeClockEntry(ctx context.Context, arg GetOpenTimeClockEntryParams) (TimeClockEntry, error)
+	GetOrganizationByID(ctx context.Context, id string) (Organization, error)
+	GetOrganizationBySlug(ctx context.Context, slug string) (Organization, error)
+	GetOrganizationKey(ctx context.Context, organizationID string) (OrganizationKey, error)
+	GetOrganizationUsage(ctx context.Context, organizationID string) (GetOrganizationUsageRow, error)
+	GetOverlappingDentistShift(ctx context.Context, arg GetOverlappingDentistShiftParams) (ClinicShift, error)
+	GetPayoutBatch(ctx context.Context, arg GetPayoutBatchParams) (GetPayoutBatchRow, error)
+	GetPayoutBatchFile(ctx context.Context, arg GetPayoutBatchFileParams) (GetPayoutBatchFileRow, error)
+	GetPersonByTaxID(ctx context.Context, arg GetPersonByTaxIDParams) (Person, error)
+	GetPersonIncludingDeleted(ctx context.Context, arg GetPersonIncludingDeletedParams) (Person, error)
+	GetPlatformOperatorByEmail(ctx context.Context, email string) (PlatformOperator, error)
+	GetPurchaseOrder(ctx context.Context, arg GetPurchaseOrderParams) (PurchaseOrder, error)
+	GetReport(ctx context.Context, arg GetReportParams) (Report, error)
+	GetReportDefinition(ctx context.Context, arg GetReportDefinitionParams) (ReportDefinition, error)
+	GetSpecialtyByID(ctx context.Context, arg GetSpecialtyByIDParams) (Specialty, error)
+	GetSupplier(ctx context.Context, arg GetSupplierParams) (GetSupplierRow, error)
 	GetUserByEmail(ctx context.Context, email string) (User, error)
-	ListBankAccountsByClinicID(ctx context.Context, clinicID string) ([]BankAccount, error)
+	GetUserByID(ctx context.Context, arg GetUserByIDParams) (User, error)
+	GetWarehouseExportWatermarkForUpdate(ctx context.Context, arg GetWarehouseExportWatermarkForUpdateParams) (WarehouseExportWatermark, error)
+	HasActiveClinicFinancialHold(ctx context.Context, arg HasActiveClinicFinancialHoldParams) (bool, error)
+	HasActiveLegalHold(ctx context.Context, arg HasActiveLegalHoldParams) (bool, error)
+	HasActiveOrganizationLegalHold(ctx context.Context, organizationID string) (bool, error)
+	HasOpenSupplierPurchaseOrders(ctx context.Context, arg HasOpenSupplierPurchaseOrdersParams) (bool, error)
+	IsClinicLegalRepresentativeTaxID(ctx context.Context, arg IsClinicLegalRepresentativeTaxIDParams) (bool, error)
+	IsNotificationRecipientSuppressed(ctx context.Context, arg IsNotificationRecipientSuppressedParams) (bool, error)
+	LiftClinicFinancialHold(ctx context.Context, arg LiftClinicFinancialHoldParams) (int64, error)
+	ListActiveClinicIDsByDentist(ctx context.Context, arg ListActiveClinicIDsByDentistParams) ([]string, error)
+	ListAddressesByPersonIDs(ctx context.Context, arg ListAddressesByPersonIDsParams) ([]Address, error)
+	ListAssignedTasksCursor(ctx context.Context, arg ListAssignedTasksCursorParams) ([]ClinicTask, error)
+	ListAuditLogsByEntity(ctx context.Context, arg ListAuditLogsByEntityParams) ([]AuditLog, error)
+	ListAuditLogsCreatedBetween(ctx context.Context, arg ListAuditLogsCreatedBetweenParams) ([]AuditLog, error)
+	ListAuditLogsForExport(ctx context.Context, arg ListAuditLogsForExportParams) ([]AuditLog, error)
+	ListBankAccountChanges(ctx context.Context, arg ListBankAccountChangesParams) ([]BankAccountChange, error)
+	ListBankAccountsByClinicID(ctx context.Context, arg ListBankAccountsByClinicIDParams) ([]BankAccount, error)
+	ListBankAccountsByClinicIDDeletedAt(ctx context.Context, arg ListBankAccountsByClinicIDDeletedAtParams) ([]BankAccount, error)
+	ListBankAccountsForRevision(ctx context.Context, arg ListBankAccountsForRevisionParams) ([]BankAccount, error)
+	ListBranchClinicDetails(ctx context.Context, arg ListBranchClinicDetailsParams) ([]ListBranchClinicDetailsRow, error)
+	ListClinicAnnouncementAudience(ctx context.Context, arg ListClinicAnnouncementAudienceParams) ([]ListClinicAnnouncementAudienceRow, error)
+	ListClinicAnnouncementReceipts(ctx context.Context, arg ListClinicAnnouncementReceiptsParams) ([]ListClinicAnnouncementReceiptsRow, error)
+	ListClinicAnnouncementsCursor(ctx context.Context, arg ListClinicAnnouncementsCursorParams) ([]ListClinicAnnouncementsCursorRow, error)
+	ListClinicBankAccountHistoryCursor(ctx context.Context, arg ListClinicBankAccountHistoryCursorParams) ([]ListClinicBankAccountHistoryCursorRow, error)
+	ListClinicDentistHistory(ctx context.Context, arg ListClinicDentistHistoryParams) ([]ClinicDentist, error)
+	ListClinicDentistIDsAsOf(ctx context.Context, arg ListClinicDentistIDsAsOfParams) ([]string, error)
+	ListClinicDentistRowsByDentist(ctx context.Context, arg ListClinicDentistRowsByDentistParams) ([]ClinicDentist, error)
 	ListClinicDetailsCursor(ctx context.Context, arg ListClinicDetailsCursorParams) ([]ListClinicDetailsCursorRow, error)
-	ListDentistsByClinicID(ctx context.Context, clinicID string) ([]ListDentistsByClinicIDRow, error)
+	ListClinicDuplicateCandidates(ctx context.Context, arg ListClinicDuplicateCandidatesParams) ([]ListClinicDuplicateCandidatesRow, error)
+	ListClinicEquipmentSchedule(ctx context.Context, arg ListClinicEquipmentScheduleParams) ([]Equipment, error)
+	ListClinicFinancialHolds(ctx context.Context, arg ListClinicFinancialHoldsParams) ([]ListClinicFinancialHoldsRow, error)
+	ListClinicGeneratedDocumentsCursor(ctx context.Context, arg ListClinicGeneratedDocumentsCursorParams) ([]GeneratedDocument, error)
+	ListClinicGroupReport(ctx context.Context, arg ListClinicGroupReportParams) ([]ListClinicGroupReportRow, error)
+	ListClinicHolidays(ctx context.Context, arg ListClinicHolidaysParams) ([]ClinicHoliday, error)
+	ListClinicNoteMentionsByNoteIDs(ctx context.Context, arg ListClinicNoteMentionsByNoteIDsParams) ([]ClinicNoteMention, error)
+	ListClinicNotesCursor(ctx context.Context, arg ListClinicNotesCursorParams) ([]ListClinicNotesCursorRow, error)
+	ListClinicOnboardingTransitions(ctx context.Context, arg ListClinicOnboardingTransitionsParams) ([]ClinicOnboardingTransition, error)
+	ListClinicOperatingHours(ctx context.Context, arg ListClinicOperatingHoursParams) ([]ClinicOperatingHour, error)
+	ListClinicOverdueEquipment(ctx context.Context, arg ListClinicOverdueEquipmentParams) ([]Equipment, error)
+	ListClinicPayables(ctx context.Context, arg ListClinicPayablesParams) ([]ClinicPayable, error)
+	ListClinicRegistryRecordsByClinicIDs(ctx context.Context, arg ListClinicRegistryRecordsByClinicIDsParams) ([]ClinicRegistryRecord, error)
+	ListClinicReportDefinitions(ctx context.Context, arg ListClinicReportDefinitionsParams) ([]ReportDefinition, error)
+	ListClinicRevisionsByClinicID(ctx context.Context, arg ListClinicRevisionsByClinicIDParams) ([]ClinicRevision, error)
+	ListClinicRevisionsCursor(ctx context.Context, arg ListClinicRevisionsCursorParams) ([]ListClinicRevisionsCursorRow, error)
+	// Shifts outside the dentist's link with the clinic drop off the board, so unlinking needs no cleanup here.
+	ListClinicShiftsBetween(ctx context.Context, arg ListClinicShiftsBetweenParams) ([]ListClinicShiftsBetweenRow, error)
+	ListClinicTaskRules(ctx context.Context, arg ListClinicTaskRulesParams) ([]ClinicTaskRule, error)
+	ListClinicTasksCursor(ctx context.Context, arg ListClinicTasksCursorParams) ([]ClinicTask, error)
+	ListClinicTasksDueOn(ctx context.Context, arg ListClinicTasksDueOnParams) ([]ClinicTask, error)
+	ListClinicTimeClockEntries(ctx context.Context, arg ListClinicTimeClockEntriesParams) ([]ListClinicTimeClockEntriesRow, error)
+	ListClinicsWithOpenPayables(ctx context.Context, arg ListClinicsWithOpenPayablesParams) ([]string, error)
+	ListClinicsWithoutActiveBankAccount(ctx context.Context, arg ListClinicsWithoutActiveBankAccountParams) ([]string, error)
+	ListClinicsWithoutPrimaryBankAccount(ctx context.Context, arg ListClinicsWithoutPrimaryBankAccountParams) ([]string, error)
+	ListDeletedResourcesCursor(ctx context.Context, arg ListDeletedResourcesCursorParams) ([]ListDeletedResourcesCursorRow, error)
+	ListDentistAnnouncementsCursor(ctx context.Context, arg ListDentistAnnouncementsCursorParams) ([]ListDentistAnnouncementsCursorRow, error)
+	ListDentistDocuments(ctx context.Context, arg ListDentistDocumentsParams) ([]DentistDocument, error)
+	ListDentistEmploymentHistory(ctx context.Context, arg ListDentistEmploymentHistoryParams) ([]ListDentistEmploymentHistoryRow, error)
+	ListDentistsByClinicID(ctx context.Context, arg ListDentistsByClinicIDParams) ([]ListDentistsByClinicIDRow, error)
 	ListDentistsByClinicIDCursor(ctx context.Context, arg ListDentistsByClinicIDCursorParams) ([]ListDentistsByClinicIDCursorRow, error)
-	ListDentistsByClinicIDs(ctx context.Context, clinicIds []string) ([]ListDentistsByClinicIDsRow, error)
-	LockClinicForUpdate(ctx context.Context, id string) (string, error)
+	ListDentistsByClinicIDs(ctx context.Context, arg ListDentistsByClinicIDsParams) ([]ListDentistsByClinicIDsRow, error)
+	ListDentistsNeedingPhotoThumbnail(ctx context.Context, arg ListDentistsNeedingPhotoThumbnailParams) ([]ListDentistsNeedingPhotoThumbnailRow, error)
+	ListDentistsWithoutActiveClinic(ctx context.Context, arg ListDentistsWithoutActiveClinicParams) ([]string, error)
+	ListDocumentExpiringTaskCandidates(ctx context.Context, arg ListDocumentExpiringTaskCandidatesParams) ([]ListDocumentExpiringTaskCandidatesRow, error)
+	ListDocumentRetentionRules(ctx context.Context, organizationID string) ([]DocumentRetentionRule, error)
+	ListDocumentSignatureRequests(ctx context.Context, arg ListDocumentSignatureRequestsParams) ([]DocumentSignatureRequest, error)
+	ListDocumentTemplates(ctx context.Context, arg ListDocumentTemplatesParams) ([]DocumentTemplate, error)
+	ListDocumentsDueForNotification(ctx context.Context, arg ListDocumentsDueForNotificationParams) ([]DentistDocument, error)
+	ListDuePendingDeletions(ctx context.Context, arg ListDuePendingDeletionsParams) ([]PendingDeletion, error)
+	ListDueTemporaryClinicDentists(ctx context.Context, arg ListDueTemporaryClinicDentistsParams) ([]ClinicDentist, error)
+	ListEquipmentCursor(ctx context.Context, arg ListEquipmentCursorParams) ([]Equipment, error)
+	ListEquipmentMaintenanceRecordsCursor(ctx context.Context, arg ListEquipmentMaintenanceRecordsCursorParams) ([]EquipmentMaintenanceRecord, error)
+	ListExpiredGeneratedDocuments(ctx context.Context, arg ListExpiredGeneratedDocumentsParams) ([]ListExpiredGeneratedDocumentsRow, error)
+	ListExpiredReports(ctx context.Context, arg ListExpiredReportsParams) ([]Report, error)
+	ListExpiringDocumentsByClinic(ctx context.Context, arg ListExpiringDocumentsByClinicParams) ([]ListExpiringDocumentsByClinicRow, error)
+	ListGeneratedDocumentSignedKeys(ctx context.Context, arg ListGeneratedDocumentSignedKeysParams) ([]string, error)
+	ListInventoryItemsCursor(ctx context.Context, arg ListInventoryItemsCursorParams) ([]InventoryItem, error)
+	ListInventoryMovementsCursor(ctx context.Context, arg ListInventoryMovementsCursorParams) ([]InventoryMovement, error)
+	ListLedgerEntriesByTransactionIDs(ctx context.Context, arg ListLedgerEntriesByTransactionIDsParams) ([]LedgerEntry, error)
+	ListLedgerTransactions(ctx context.Context, arg ListLedgerTransactionsParams) ([]LedgerTransaction, error)
+	ListLegalHolds(ctx context.Context, arg ListLegalHoldsParams) ([]ListLegalHoldsRow, error)
+	ListLowStockInventoryItems(ctx context.Context, arg ListLowStockInventoryItemsParams) ([]ListLowStockInventoryItemsRow, error)
+	ListMaintenanceOverdueTaskCandidates(ctx context.Context, arg ListMaintenanceOverdueTaskCandidatesParams) ([]ListMaintenanceOverdueTaskCandidatesRow, error)
+	ListNotificationSuppressionsCursor(ctx context.Context, arg ListNotificationSuppressionsCursorParams) ([]NotificationSuppression, error)
+	ListNotificationsCursor(ctx context.Context, arg ListNotificationsCursorParams) ([]Notification, error)
+	ListOpenClinicTimeClockEntries(ctx context.Context, arg ListOpenClinicTimeClockEntriesParams) ([]ListOpenClinicTimeClockEntriesRow, error)
+	ListOrganizationAttachmentKeys(ctx context.Context, organizationID string) ([]string, error)
+	ListOrganizationIDs(ctx context.Context) ([]string, error)
+	ListOrganizationQuarantinedAttachmentKeys(ctx context.Context, organizationID string) ([]string, error)
+	ListOrganizationReportKeys(ctx context.Context, organizationID string) ([]string, error)
+	ListOrganizations(ctx context.Context) ([]Organization, error)
+	ListOrganizationsDueForPurge(ctx context.Context, arg ListOrganizationsDueForPurgeParams) ([]Organization, error)
+	ListOrphanedPeople(ctx context.Context, arg ListOrphanedPeopleParams) ([]string, error)
+	ListOverdueEquipmentForNotification(ctx context.Context, arg ListOverdueEquipmentForNotificationParams) ([]ListOverdueEquipmentForNotificationRow, error)
+	ListPayoutBatchItems(ctx context.Context, arg ListPayoutBatchItemsParams) ([]PayoutBatchItem, error)
+	ListPayoutBatchesCursor(ctx context.Context, arg ListPayoutBatchesCursorParams) ([]ListPayoutBatchesCursorRow, error)
+	ListPayoutReceiptRecipients(ctx context.Context, arg ListPayoutReceiptRecipientsParams) ([]ListPayoutReceiptRecipientsRow, error)
+	ListPendingBankAccountChangeTaskCandidates(ctx context.Context, organizationID string) ([]ListPendingBankAccountChangeTaskCandidatesRow, error)
+	ListPublicClinicDirectoryCursor(ctx context.Context, arg ListPublicClinicDirectoryCursorParams) ([]ListPublicClinicDirectoryCursorRow, error)
+	ListPublicClinicSpecialties(ctx context.Context, arg ListPublicClinicSpecialtiesParams) ([]ListPublicClinicSpecialtiesRow, error)
+	ListPurchaseOrderItems(ctx context.Context, arg ListPurchaseOrderItemsParams) ([]ListPurchaseOrderItemsRow, error)
+	ListPurchaseOrdersCursor(ctx context.Context, arg ListPurchaseOrdersCursorParams) ([]PurchaseOrder, error)
+	ListReportsCursor(ctx context.Context, arg ListReportsCursorParams) ([]Report, error)
+	// Held records go last so they cannot crowd the batch.
+	ListRetentionCandidates(ctx context.Context, arg ListRetentionCandidatesParams) ([]ListRetentionCandidatesRow, error)
+	ListSchemaOrganizations(ctx context.Context) ([]Organization, error)
+	ListSealedAuditLogs(ctx context.Context, arg ListSealedAuditLogsParams) ([]AuditLog, error)
+	ListSpecialties(ctx context.Context, organizationID string) ([]Specialty, error)
+	ListSpecialtiesByDentistIDs(ctx context.Context, arg ListSpecialtiesByDentistIDsParams) ([]ListSpecialtiesByDentistIDsRow, error)
+	ListSupplierSpend(ctx context.Context, arg ListSupplierSpendParams) ([]ListSupplierSpendRow, error)
+	ListSuppliersCursor(ctx context.Context, arg ListSuppliersCursorParams) ([]ListSuppliersCursorRow, error)
+	ListTrashCursor(ctx context.Context, arg ListTrashCursorParams) ([]ListTrashCursorRow, error)
+	ListUnsealedAuditLogs(ctx context.Context, arg ListUnsealedAuditLogsParams) ([]AuditLog, error)
+	ListUsageDailyRollups(ctx context.Context, arg ListUsageDailyRollupsParams) ([]UsageDailyRollup, error)
+	ListWarehouseExportWatermarks(ctx context.Context, organizationID string) ([]WarehouseExportWatermark, error)
+	LockClinicForUpdate(ctx context.Context, arg LockClinicForUpdateParams) (string, error)
+	LockDeletedClinicForUpdate(ctx context.Context, arg LockDeletedClinicForUpdateParams) (Clinic, error)
+	LockDeletedDentistForUpdate(ctx context.Context, arg LockDeletedDentistForUpdateParams) (Dentist, error)
+	// Serializes shift writes per dentist, since an overlap can come from a shift at any clinic of the organization.
+	LockDentistForShift(ctx context.Context, arg LockDentistForShiftParams) (string, error)
+	LockEquipmentForUpdate(ctx context.Context, arg LockEquipmentForUpdateParams) (Equipment, error)
+	LockInventoryItemForUpdate(ctx context.Context, arg LockInventoryItemForUpdateParams) (InventoryItem, error)
+	LockOrganizationForUpdate(ctx context.Context, organizationID string) (Organization, error)
+	LockPayoutBatchForUpdate(ctx context.Context, arg LockPayoutBatchForUpdateParams) (string, error)
+	LockPurchaseOrderForUpdate(ctx context.Context, arg LockPurchaseOrderForUpdateParams) (PurchaseOrder, error)
+	MarkBankAccountVerificationFailed(ctx context.Context, arg MarkBankAccountVerificationFailedParams) (int64, error)
+	MarkBankAccountVerified(ctx context.Context, arg MarkBankAccountVerifiedParams) (int64, error)
+	MarkClinicAnnouncementRead(ctx context.Context, arg MarkClinicAnnouncementReadParams) (sql.NullTime, error)
+	MarkDentistDocumentNotified(ctx context.Context, arg MarkDentistDocumentNotifiedParams) error
+	MarkDocumentSignatureSigned(ctx context.Context, arg MarkDocumentSignatureSignedParams) (int64, error)
+	MarkEquipmentOverdueNotified(ctx context.Context, arg MarkEquipmentOverdueNotifiedParams) error
+	MarkNotificationAttemptFailed(ctx context.Context, arg MarkNotificationAttemptFailedParams) error
+	MarkNotificationSent(ctx context.Context, arg MarkNotificationSentParams) error
+	MarkOrganizationPurged(ctx context.Context, id string) error
+	MoveDentistClinicTasks(ctx context.Context, arg MoveDentistClinicTasksParams) (int64, error)
+	MoveDentistDocuments(ctx context.Context, arg MoveDentistDocumentsParams) (int64, error)
+	MoveDentistGeneratedDocuments(ctx context.Context, arg MoveDentistGeneratedDocumentsParams) (int64, error)
+	MoveDentistNoteMentions(ctx context.Context, arg MoveDentistNoteMentionsParams) (int64, error)
+	MoveDentistNotificationPreferences(ctx context.Context, arg MoveDentistNotificationPreferencesParams) (int64, error)
+	MoveDentistShifts(ctx context.Context, arg MoveDentistShiftsParams) (int64, error)
+	MoveDentistTimeClockEntries(ctx context.Context, arg MoveDentistTimeClockEntriesParams) (int64, error)
+	MoveDentistUser(ctx context.Context, arg MoveDentistUserParams) (int64, error)
+	PromoteOldestBankAccountToPrimary(ctx context.Context, arg PromoteOldestBankAccountToPrimaryParams) (string, error)
+	PurgeClinic(ctx context.Context, arg PurgeClinicParams) (int64, error)
+	PurgeClinicBankAccounts(ctx context.Context, arg PurgeClinicBankAccountsParams) error
+	PurgeClinicInventory(ctx context.Context, arg PurgeClinicInventoryParams) error
+	// Stock, purchasing and equipment rows reference each other, so they go in two steps after DeleteClinicChildRecords.
+	PurgeClinicOperationalRecords(ctx context.Context, arg PurgeClinicOperationalRecordsParams) error
+	PurgeClinicReportDefinitions(ctx context.Context, arg PurgeClinicReportDefinitionsParams) error
+	PurgeDentist(ctx context.Context, arg PurgeDentistParams) (int64, error)
+	PurgeDentistAttachmentScans(ctx context.Context, arg PurgeDentistAttachmentScansParams) error
+	// The hold and rule checks are repeated here so a hold placed after the listing still wins.
+	PurgeExpiredGeneratedDocument(ctx context.Context, arg PurgeExpiredGeneratedDocumentParams) (int64, error)
+	PurgeGeneratedDocumentSignatureRequests(ctx context.Context, arg PurgeGeneratedDocumentSignatureRequestsParams) error
+	PurgeOrganizationAddresses(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationAttachmentScans(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationAuditChainHead(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationAuditExportCheckpoints(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationAuditLogs(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationBankAccountChanges(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationBankAccounts(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicAnnouncementRecipients(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicAnnouncements(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicBranches(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicDentists(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicDirectoryListings(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicFinancialHolds(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicHolidays(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicNoteMentions(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicNotes(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicOnboardingTransitions(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicOperatingHours(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicPayables(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicRegistryRecords(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicRevisions(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicSettings(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicShifts(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicTaskRules(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinicTasks(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationClinics(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationDentistDocuments(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationDentistNotificationPreferences(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationDentistSpecialties(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationDentists(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationDocumentRetentionRules(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationDocumentSignatureRequests(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationDocumentTemplates(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationEquipment(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationEquipmentMaintenanceRecords(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationGeneratedDocuments(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationInventoryItems(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationInventoryMovements(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationLedgerEntries(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationLedgerTransactions(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationLegalHolds(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationNotificationSuppressions(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationNotifications(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationPayoutBatchItems(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationPayoutBatches(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationPendingDeletions(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationPeople(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationPurchaseOrderItems(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationPurchaseOrderReceipts(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationPurchaseOrders(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationReportDefinitions(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationReports(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationSpecialties(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationSuppliers(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationTimeClockEntries(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationTimeClockSettings(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationUsageDailyRollups(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationUsageRecords(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationUsers(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrganizationWarehouseExportWatermarks(ctx context.Context, organizationID string) (int64, error)
+	PurgeOrphanAddress(ctx context.Context, arg PurgeOrphanAddressParams) error
+	PurgeOrphanPerson(ctx context.Context, arg PurgeOrphanPersonParams) (int64, error)
+	ReactivateClinic(ctx context.Context, arg ReactivateClinicParams) (int64, error)
+	ReassignClinicDentistRow(ctx context.Context, arg ReassignClinicDentistRowParams) (int64, error)
+	ReassignSubstituteFor(ctx context.Context, arg ReassignSubstituteForParams) (int64, error)
+	ReceivePurchaseOrderItem(ctx context.Context, arg ReceivePurchaseOrderItemParams) (PurchaseOrderItem, error)
+	RecordNotificationDelivery(ctx context.Context, arg RecordNotificationDeliveryParams) (int64, error)
+	ReleaseLegalHold(ctx context.Context, arg ReleaseLegalHoldParams) (int64, error)
+	ReleasePayoutBatchPayables(ctx context.Context, arg ReleasePayoutBatchPayablesParams) (int64, error)
+	RescheduleAttachmentScan(ctx context.Context, arg RescheduleAttachmentScanParams) error
+	RescheduleReport(ctx context.Context, arg RescheduleReportParams) error
+	RestoreBankAccountsDeletedAt(ctx context.Context, arg RestoreBankAccountsDeletedAtParams) (int64, error)
+	RestoreClinic(ctx context.Context, arg RestoreClinicParams) (int64, error)
+	RestoreDentist(ctx context.Context, arg RestoreDentistParams) (int64, error)
+	RestoreDentistDocumentsDeletedAt(ctx context.Context, arg RestoreDentistDocumentsDeletedAtParams) (int64, error)
+	RestorePerson(ctx context.Context, arg RestorePersonParams) (int64, error)
+	RestoreUsersByDentistIDDeletedAt(ctx context.Context, arg RestoreUsersByDentistIDDeletedAtParams) (int64, error)
+	RetryFailedNotification(ctx context.Context, arg RetryFailedNotificationParams) (int64, error)
+	ReviewBankAccountChange(ctx context.Context, arg ReviewBankAccountChangeParams) (int64, error)
+	SealAuditLog(ctx context.Context, arg SealAuditLogParams) (int64, error)
+	SetEquipmentMaintenanceSchedule(ctx context.Context, arg SetEquipmentMaintenanceScheduleParams) (Equipment, error)
+	SetInventoryItemQuantity(ctx context.Context, arg SetInventoryItemQuantityParams) error
+	SetOrganizationExport(ctx context.Context, arg SetOrganizationExportParams) (Organization, error)
+	SetPrimaryBankAccount(ctx context.Context, arg SetPrimaryBankAccountParams) (int64, error)
+	SetPurchaseOrderStatus(ctx context.Context, arg SetPurchaseOrderStatusParams) (PurchaseOrder, error)
+	ShredOrganizationKey(ctx context.Context, organizationID string) error
+	StartBankAccountVerification(ctx context.Context, arg StartBankAccountVerificationParams) (int64, error)
+	UpdateAuditChainHead(ctx context.Context, arg UpdateAuditChainHeadParams) error
+	UpdateBankAccount(ctx context.Context, arg UpdateBankAccountParams) (BankAccount, error)
+	UpdateClinicDentistAssignment(ctx context.Context, arg UpdateClinicDentistAssignmentParams) (ClinicDentist, error)
 	UpdateClinicDentistRole(ctx context.Context, arg UpdateClinicDentistRoleParams) (ClinicDentist, error)
+	UpdateClinicNotePinned(ctx context.Context, arg UpdateClinicNotePinnedParams) (int64, error)
+	UpdateClinicOnboardingStatus(ctx context.Context, arg UpdateClinicOnboardingStatusParams) (int64, error)
+	UpdateClinicShift(ctx context.Context, arg UpdateClinicShiftParams) (ClinicShift, error)
+	UpdateClinicTask(ctx context.Context, arg UpdateClinicTaskParams) (ClinicTask, error)
+	UpdateClinicTimezone(ctx context.Context, arg UpdateClinicTimezoneParams) (int64, error)
+	UpdateDentistCRO(ctx context.Context, arg UpdateDentistCROParams) (Dentist, error)
+	UpdateDentistDocument(ctx context.Context, arg UpdateDentistDocumentParams) (DentistDocument, error)
+	UpdateDentistPerson(ctx context.Context, arg UpdateDentistPersonParams) (Dentist, error)
+	UpdateDentistPhoto(ctx context.Context, arg UpdateDentistPhotoParams) (Dentist, error)
+	UpdateDentistPhotoThumbnail(ctx context.Context, arg UpdateDentistPhotoThumbnailParams) (int64, error)
+	UpdateDocumentTemplate(ctx context.Context, arg UpdateDocumentTemplateParams) (DocumentTemplate, error)
+	UpdateEquipment(ctx context.Context, arg UpdateEquipmentParams) (Equipment, error)
+	UpdateInventoryItem(ctx context.Context, arg UpdateInventoryItemParams) (InventoryItem, error)
+	UpdateOrganizationDataRegion(ctx context.Context, arg UpdateOrganizationDataRegionParams) (Organization, error)
+	UpdateOrganizationPlan(ctx context.Context, arg UpdateOrganizationPlanParams) (Organization, error)
+	UpdateOrganizationQuotas(ctx context.Context, arg UpdateOrganizationQuotasParams) (Organization, error)
+	UpdateOrganizationStatus(ctx context.Context, arg UpdateOrganizationStatusParams) (Organization, error)
+	UpdatePayoutBatchStatus(ctx context.Context, arg UpdatePayoutBatchStatusParams) error
 	UpdatePerson(ctx context.Context, arg UpdatePersonParams) (Person, error)
+	UpdateReportDefinition(ctx context.Context, arg UpdateReportDefinitionParams) (ReportDefinition, error)
+	UpdateReportProgress(ctx context.Context, arg UpdateReportProgressParams) error
+	UpdateSpecialty(ctx context.Context, arg UpdateSpecialtyParams) (Specialty, error)
+	UpdateSupplierNotes(ctx context.Context, arg UpdateSupplierNotesParams) error
+	UpsertAddress(ctx context.Context, arg UpsertAddressParams) (Address, error)
+	UpsertClinicDirectoryListing(ctx context.Context, arg UpsertClinicDirectoryListingParams) (ClinicDirectoryListing, error)
+	UpsertClinicRegistryRecord(ctx context.Context, arg UpsertClinicRegistryRecordParams) (ClinicRegistryRecord, error)
+	UpsertClinicSettings(ctx context.Context, arg UpsertClinicSettingsParams) (ClinicSetting, error)
+	UpsertClinicTimeClockSettings(ctx context.Context, arg UpsertClinicTimeClockSettingsParams) (ClinicTimeClockSetting, error)
+	UpsertDentistNotificationPreferences(ctx context.Context, arg UpsertDentistNotificationPreferencesParams) (DentistNotificationPreference, error)
+	UpsertDocumentRetentionRule(ctx context.Context, arg UpsertDocumentRetentionRuleParams) (DocumentRetentionRule, error)
+	// Counters add up over the day; gauges keep the day's peak.
+	UpsertUsageDailyRollup(ctx context.Context, arg UpsertUsageDailyRollupParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -7,34 +7,133 @@ package repository
 
 import (
 	"context"
+	"time"
+
+	"github.com/google/uuid"
 )
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (
     id,
+    organization_id,
     email,
-    password_hash
+    password_hash,
+    role,
+    dentist_id
 ) VALUES (
     $1::uuid,
-    $2,
-    $3
+    $2::uuid,
+    $3,
+    $4,
+    $5,
+    $6::uuid
 )
-RETURNING id, email, password_hash, created_at, updated_at, deleted_at
+RETURNING id, organization_id, email, password_hash, role, dentist_id, can_reveal_bank_details, created_at, updated_at, deleted_at
 `
 
 type CreateUserParams struct {
-	ID           string `json:"id"`
-	Email        string `json:"email"`
-	PasswordHash string `json:"password_hash"`
+	ID             string        `json:"id"`
+	OrganizationID string        `json:"organization_id"`
+	Email          string        `json:"email"`
+	PasswordHash   string        `json:"password_hash"`
+	Role           string        `json:"role"`
+	DentistID      uuid.NullUUID `json:"dentist_id"`
 }
 
 func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, error) {
-	row := q.db.QueryRowContext(ctx, createUser, arg.ID, arg.Email, arg.PasswordHash)
+	row := q.db.QueryRow(ctx, createUser,
+		arg.ID,
+		arg.OrganizationID,
+		arg.Email,
+		arg.PasswordHash,
+		arg.Role,
+		arg.DentistID,
+	)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.DentistID,
+		&i.CanRevealBankDetails,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const deleteUsersByDentistID = `-- name: DeleteUsersByDentistID :execrows
+UPDATE users
+SET deleted_at = CURRENT_TIMESTAMP,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NULL
+`
+
+type DeleteUsersByDentistIDParams struct {
+	DentistID      string `json:"dentist_id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) DeleteUsersByDentistID(ctx context.Context, arg DeleteUsersByDentistIDParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteUsersByDentistID, arg.DentistID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const existsUserEmailConflictForDentist = `-- name: ExistsUserEmailConflictForDentist :one
+SELECT EXISTS (
+    SELECT 1
+    FROM users u
+    JOIN users other ON lower(other.email) = lower(u.email)
+        AND other.id <> u.id
+        AND other.deleted_at IS NULL
+    WHERE u.dentist_id = $1::uuid
+      AND u.organization_id = $2::uuid
+      AND u.deleted_at = $3::timestamptz
+)
+`
+
+type ExistsUserEmailConflictForDentistParams struct {
+	DentistID      string    `json:"dentist_id"`
+	OrganizationID string    `json:"organization_id"`
+	DeletedAt      time.Time `json:"deleted_at"`
+}
+
+func (q *Queries) ExistsUserEmailConflictForDentist(ctx context.Context, arg ExistsUserEmailConflictForDentistParams) (bool, error) {
+	row := q.db.QueryRow(ctx, existsUserEmailConflictForDentist, arg.DentistID, arg.OrganizationID, arg.DeletedAt)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const getOldestAdminUser = `-- name: GetOldestAdminUser :one
+SELECT id, organization_id, email, password_hash, role, dentist_id, can_reveal_bank_details, created_at, updated_at, deleted_at
+FROM users
+WHERE organization_id = $1::uuid
+  AND role = 'ADMIN'
+  AND deleted_at IS NULL
+ORDER BY created_at, id
+LIMIT 1
+`
+
+func (q *Queries) GetOldestAdminUser(ctx context.Context, organizationID string) (User, error) {
+	row := q.db.QueryRow(ctx, getOldestAdminUser, organizationID)
 	var i User
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.Email,
 		&i.PasswordHash,
+		&i.Role,
+		&i.DentistID,
+		&i.CanRevealBankDetails,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
@@ -43,7 +142,7 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT id, email, password_hash, created_at, updated_at, deleted_at
+SELECT id, organization_id, email, password_hash, role, dentist_id, can_reveal_bank_details, created_at, updated_at, deleted_at
 FROM users
 WHERE lower(email) = lower($1)
   AND deleted_at IS NULL
@@ -51,15 +150,103 @@ LIMIT 1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
-	row := q.db.QueryRowContext(ctx, getUserByEmail, email)
+	row := q.db.QueryRow(ctx, getUserByEmail, email)
 	var i User
 	err := row.Scan(
 		&i.ID,
+		&i.OrganizationID,
 		&i.Email,
 		&i.PasswordHash,
+		&i.Role,
+		&i.DentistID,
+		&i.CanRevealBankDetails,
 		&i.CreatedAt,
 		&i.UpdatedAt,
 		&i.DeletedAt,
 	)
 	return i, err
 }
+
+const getUserByID = `-- name: GetUserByID :one
+SELECT id, organization_id, email, password_hash, role, dentist_id, can_reveal_bank_details, created_at, updated_at, deleted_at
+FROM users
+WHERE id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at IS NULL
+LIMIT 1
+`
+
+type GetUserByIDParams struct {
+	ID             string `json:"id"`
+	OrganizationID string `json:"organization_id"`
+}
+
+func (q *Queries) GetUserByID(ctx context.Context, arg GetUserByIDParams) (User, error) {
+	row := q.db.QueryRow(ctx, getUserByID, arg.ID, arg.OrganizationID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.OrganizationID,
+		&i.Email,
+		&i.PasswordHash,
+		&i.Role,
+		&i.DentistID,
+		&i.CanRevealBankDetails,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.DeletedAt,
+	)
+	return i, err
+}
+
+const moveDentistUser = `-- name: MoveDentistUser :execrows
+UPDATE users
+SET dentist_id = $1::uuid,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = $2::uuid
+  AND organization_id = $3::uuid
+  AND deleted_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1
+      FROM users existing
+      WHERE existing.dentist_id = $1::uuid
+        AND existing.deleted_at IS NULL
+  )
+`
+
+type MoveDentistUserParams struct {
+	TargetDentistID string `json:"target_dentist_id"`
+	DentistID       string `json:"dentist_id"`
+	OrganizationID  string `json:"organization_id"`
+}
+
+func (q *Queries) MoveDentistUser(ctx context.Context, arg MoveDentistUserParams) (int64, error) {
+	result, err := q.db.Exec(ctx, moveDentistUser, arg.TargetDentistID, arg.DentistID, arg.OrganizationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const restoreUsersByDentistIDDeletedAt = `-- name: RestoreUsersByDentistIDDeletedAt :execrows
+UPDATE users
+SET deleted_at = NULL,
+    updated_at = CURRENT_TIMESTAMP
+WHERE dentist_id = $1::uuid
+  AND organization_id = $2::uuid
+  AND deleted_at = $3::timestamptz
+`
+
+type RestoreUsersByDentistIDDeletedAtParams struct {
+	DentistID      string    `json:"dentist_id"`
+	OrganizationID string    `json:"organization_id"`
+	DeletedAt      time.Time `json:"deleted_at"`
+}
+
+func (q *Queries) RestoreUsersByDentistIDDeletedAt(ctx context.Context, arg RestoreUsersByDentistIDDeletedAtParams) (int64, error) {
+	result, err := q.db.Exec(ctx, restoreUsersByDentistIDDeletedAt, arg.DentistID, arg.OrganizationID, arg.DeletedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -0,0 +1,352 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const tenantPoolMaxConns = 5
+
+var tenantSchemaPattern = regexp.MustCompile(`^tenant_[0-9a-f]{32}$`)
+
+// tenantTables are cloned into each tenant schema, so the generated queries resolve them through search_path unchanged.
+// organizations, organization_keys and platform_operators stay in public: they describe tenants rather than belong to one.
+// Tables added later are cloned by their own migration.
+var tenantTables = []string{
+	"people",
+	"addresses",
+	"clinics",
+	"clinic_registry_records",
+	"dentists",
+	"clinic_dentists",
+	"clinic_operating_hours",
+	"clinic_holidays",
+	"clinic_settings",
+	"clinic_directory_listings",
+	"specialties",
+	"dentist_specialties",
+	"dentist_documents",
+	"bank_accounts",
+	"users",
+	"clinic_onboarding_transitions",
+	"clinic_notes",
+	"clinic_note_mentions",
+	"bank_account_changes",
+	"clinic_financial_holds",
+	"payout_batches",
+	"payout_batch_items",
+	"clinic_payables",
+	"ledger_transactions",
+	"ledger_entries",
+	"clinic_revisions",
+	"pending_deletions",
+	"audit_logs",
+	"audit_chain_head",
+	"audit_export_checkpoints",
+}
+
+type tenantMigration struct {
+	version int
+	apply   func(ctx context.Context, tx pgx.Tx, schema string) error
+}
+
+// Append-only: a tenant schema records the last version it ran, so editing an applied step never reaches existing tenants.
+var tenantMigrations = []tenantMigration{
+	{version: 1, apply: cloneTenantTables(tenantTables)},
+	{version: 2, apply: cloneTenantTables([]string{"usage_records", "usage_daily_rollups"})},
+	{version: 3, apply: cloneTenantTables([]string{"notifications"})},
+	{version: 4, apply: addNotificationDeliveryTracking},
+	{version: 5, apply: cloneTenantTables([]string{"clinic_announcements", "clinic_announcement_recipients", "dentist_notification_preferences"})},
+	{version: 6, apply: cloneTenantTables([]string{"inventory_items", "inventory_movements"})},
+	{version: 7, apply: cloneTenantTables([]string{"suppliers", "purchase_orders", "purchase_order_items", "purchase_order_receipts"})},
+	{version: 8, apply: cloneTenantTables([]string{"equipment", "equipment_maintenance_records"})},
+	{version: 9, apply: cloneTenantTables([]string{"clinic_shifts"})},
+	{version: 10, apply: cloneTenantTables([]string{"clinic_time_clock_settings", "time_clock_entries"})},
+	{version: 11, apply: cloneTenantTables([]string{"clinic_task_rules", "clinic_tasks"})},
+	{version: 12, apply: cloneTenantTables([]string{"document_templates", "generated_documents"})},
+	{version: 13, apply: cloneTenantTables([]string{"document_signature_requests"})},
+	{version: 14, apply: cloneTenantTables([]string{"legal_holds", "document_retention_rules"})},
+	{version: 15, apply: cloneTenantTables([]string{"attachment_scans"})},
+	{version: 16, apply: addDentistPhotoThumbnails},
+	{version: 17, apply: cloneTenantTables([]string{"reports"})},
+	{version: 18, apply: cloneTenantTables([]string{"report_definitions"})},
+	{version: 19, apply: cloneTenantTables([]string{"warehouse_export_watermarks"})},
+	{version: 20, apply: addKeysetPageIndexes},
+}
+
+// TenantSchemas hands out one pool per tenant schema, each pinned to it through search_path, next to the shared pool.
+type TenantSchemas struct {
+	shared      *pgxpool.Pool
+	databaseURL string
+	poolConfig  PoolConfig
+
+	mu    sync.Mutex
+	pools map[string]*Postgres
+}
+
+// NewTenantSchemas opens tenant pools with the shared pool's settings, capped at a few connections each.
+func NewTenantSchemas(shared *pgxpool.Pool, databaseURL string, poolConfig PoolConfig) *TenantSchemas {
+	poolConfig.MaxConns = tenantPoolMaxConns
+	return &TenantSchemas{shared: shared, databaseURL: databaseURL, poolConfig: poolConfig, pools: make(map[string]*Postgres)}
+}
+
+func (t *TenantSchemas) Pool(ctx context.Context, schema string) (*pgxpool.Pool, error) {
+	if schema == "" {
+		return t.shared, nil
+	}
+	if !tenantSchemaPattern.MatchString(schema) {
+		return nil, fmt.Errorf("invalid tenant schema %q", schema)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if pool, ok := t.pools[schema]; ok {
+		return pool.Pool, nil
+	}
+	databaseURL, err := withSearchPath(t.databaseURL, schema)
+	if err != nil {
+		return nil, err
+	}
+	pool, err := OpenPostgres(ctx, databaseURL, t.poolConfig)
+	if err != nil {
+		return nil, fmt.Errorf("open tenant schema %s: %w", schema, err)
+	}
+	t.pools[schema] = pool
+	return pool.Pool, nil
+}
+
+// Migrate creates the schema when needed and runs the migrations it has not seen yet, returning the version it ends on.
+func (t *TenantSchemas) Migrate(ctx context.Context, schema string) (int, error) {
+	if !tenantSchemaPattern.MatchString(schema) {
+		return 0, fmt.Errorf("invalid tenant schema %q", schema)
+	}
+	version := 0
+	for _, migration := range tenantMigrations {
+		if err := t.migrate(ctx, schema, migration); err != nil {
+			return version, fmt.Errorf("migrate %s to version %d: %w", schema, migration.version, err)
+		}
+		version = migration.version
+	}
+	return version, nil
+}
+
+func (t *TenantSchemas) migrate(ctx context.Context, schema string, migration tenantMigration) error {
+	tx, err := t.shared.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Two instances starting together would otherwise race to create the same tables.
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, schema); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE SCHEMA IF NOT EXISTS %s`, schema)); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s.schema_migrations (
+    version INTEGER PRIMARY KEY,
+    applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`, schema)); err != nil {
+		return err
+	}
+	var exists bool
+	if err := tx.QueryRow(ctx, fmt.Sprintf(`SELECT EXISTS (SELECT 1 FROM %s.schema_migrations WHERE version = $1)`, schema), migration.version).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		if err := migration.apply(ctx, tx, schema); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`INSERT INTO %s.schema_migrations (version) VALUES ($1)`, schema), migration.version); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (t *TenantSchemas) Close() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for schema, pool := range t.pools {
+		pool.Close()
+		delete(t.pools, schema)
+	}
+}
+
+// cloneTenantTables copies the public tables with their checks and defaults, then recreates keys and indexes under their original names,
+// since the service maps unique violations by constraint name. Foreign keys to organizations keep pointing at the shared table.
+func cloneTenantTables(tables []string) func(ctx context.Context, tx pgx.Tx, schema string) error {
+	return func(ctx context.Context, tx pgx.Tx, schema string) error {
+		return cloneTables(ctx, tx, schema, tables)
+	}
+}
+
+func cloneTables(ctx context.Context, tx pgx.Tx, schema string, tables []string) error {
+	// Definitions are read with only public visible, so they come back unqualified and resolve inside the tenant schema below.
+	if _, err := tx.Exec(ctx, `SET LOCAL search_path TO public`); err != nil {
+		return err
+	}
+	constraints, err := queryDefinitions(ctx, tx, tables, `
+SELECT cl.relname, c.conname, pg_get_constraintdef(c.oid)
+FROM pg_constraint c
+JOIN pg_class cl ON cl.oid = c.conrelid
+JOIN pg_namespace n ON n.oid = cl.relnamespace
+WHERE n.nspname = 'public'
+  AND cl.relname IN (%s)
+  AND c.contype IN ('p', 'u', 'f')
+ORDER BY CASE c.contype WHEN 'p' THEN 0 WHEN 'u' THEN 1 ELSE 2 END, cl.relname, c.conname`)
+	if err != nil {
+		return fmt.Errorf("read constraints: %w", err)
+	}
+	indexes, err := queryDefinitions(ctx, tx, tables, `
+SELECT i.tablename, i.indexname, i.indexdef
+FROM pg_indexes i
+WHERE i.schemaname = 'public'
+  AND i.tablename IN (%s)
+  AND NOT EXISTS (
+      SELECT 1
+      FROM pg_constraint c
+      JOIN pg_namespace n ON n.oid = c.connamespace
+      WHERE n.nspname = 'public' AND c.conname = i.indexname
+  )
+ORDER BY i.tablename, i.indexname`)
+	if err != nil {
+		return fmt.Errorf("read indexes: %w", err)
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`SET LOCAL search_path TO %s, public`, schema)); err != nil {
+		return err
+	}
+	for _, table := range tables {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE TABLE %s.%s (LIKE public.%s INCLUDING ALL EXCLUDING INDEXES)`, schema, table, table)); err != nil {
+			return fmt.Errorf("create %s: %w", table, err)
+		}
+	}
+	for _, constraint := range constraints {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s.%s ADD CONSTRAINT %s %s`, schema, constraint.table, constraint.name, constraint.definition)); err != nil {
+			return fmt.Errorf("add constraint %s: %w", constraint.name, err)
+		}
+	}
+	for _, index := range indexes {
+		definition := strings.Replace(index.definition, " ON public.", " ON "+schema+".", 1)
+		if _, err := tx.Exec(ctx, definition); err != nil {
+			return fmt.Errorf("create index %s: %w", index.name, err)
+		}
+	}
+	return nil
+}
+
+// addNotificationDeliveryTracking adds the columns notifications gained after version 3. A schema created since then cloned them
+// already, so each column is only added when missing.
+func addNotificationDeliveryTracking(ctx context.Context, tx pgx.Tx, schema string) error {
+	columns := []string{
+		"clinic_id UUID",
+		"delivery_status TEXT CHECK (delivery_status IN ('DELIVERED', 'BOUNCED', 'FAILED'))",
+		"bounce_type TEXT CHECK (bounce_type IN ('HARD', 'SOFT'))",
+		"delivery_detail TEXT",
+		"delivery_updated_at TIMESTAMPTZ",
+	}
+	for _, column := range columns {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s.notifications ADD COLUMN IF NOT EXISTS %s`, schema, column)); err != nil {
+			return fmt.Errorf("add notifications column: %w", err)
+		}
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_notifications_clinic_created_at ON %s.notifications(organization_id, clinic_id, created_at) WHERE clinic_id IS NOT NULL`, schema)); err != nil {
+		return fmt.Errorf("create index idx_notifications_clinic_created_at: %w", err)
+	}
+	return cloneTables(ctx, tx, schema, []string{"notification_suppressions"})
+}
+
+// addDentistPhotoThumbnails adds the thumbnail columns dentists gained after version 15, when missing like in
+// addNotificationDeliveryTracking.
+func addDentistPhotoThumbnails(ctx context.Context, tx pgx.Tx, schema string) error {
+	for _, column := range []string{"photo_thumbnail_key TEXT", "photo_thumbnail_updated_at TIMESTAMPTZ"} {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`ALTER TABLE %s.dentists ADD COLUMN IF NOT EXISTS %s`, schema, column)); err != nil {
+			return fmt.Errorf("add dentists column: %w", err)
+		}
+	}
+	return nil
+}
+
+// keysetPageIndexes back the (created_at, id) page order lists moved to after version 1 cloned their tables with (clinic_id, id)
+// indexes. A schema created since then has them already.
+var keysetPageIndexes = []struct {
+	name       string
+	definition string
+	replaces   string
+}{
+	{name: "idx_clinics_organization_created_at", definition: "clinics(organization_id, created_at, id)"},
+	{name: "idx_payout_batches_organization_created_at", definition: "payout_batches(organization_id, created_at, id)"},
+	{name: "idx_clinic_notes_clinic_created_at", definition: "clinic_notes(clinic_id, created_at, id) WHERE deleted_at IS NULL", replaces: "idx_clinic_notes_clinic_id"},
+	{name: "idx_clinic_revisions_clinic_changed_at", definition: "clinic_revisions(clinic_id, changed_at, id)", replaces: "idx_clinic_revisions_clinic_id"},
+	{name: "idx_audit_logs_clinic_created_at", definition: "audit_logs(clinic_id, created_at, id)", replaces: "idx_audit_logs_clinic_id"},
+}
+
+func addKeysetPageIndexes(ctx context.Context, tx pgx.Tx, schema string) error {
+	for _, index := range keysetPageIndexes {
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`CREATE INDEX IF NOT EXISTS %s ON %s.%s`, index.name, schema, index.definition)); err != nil {
+			return fmt.Errorf("create index %s: %w", index.name, err)
+		}
+		if index.replaces == "" {
+			continue
+		}
+		if _, err := tx.Exec(ctx, fmt.Sprintf(`DROP INDEX IF EXISTS %s.%s`, schema, index.replaces)); err != nil {
+			return fmt.Errorf("drop index %s: %w", index.replaces, err)
+		}
+	}
+	return nil
+}
+
+type definition struct {
+	table      string
+	name       string
+	definition string
+}
+
+func queryDefinitions(ctx context.Context, tx pgx.Tx, tables []string, query string) ([]definition, error) {
+	quoted := make([]string, len(tables))
+	for i, table := range tables {
+		quoted[i] = "'" + table + "'"
+	}
+	rows, err := tx.Query(ctx, fmt.Sprintf(query, strings.Join(quoted, ", ")))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var definitions []definition
+	for rows.Next() {
+		var item definition
+		if err := rows.Scan(&item.table, &item.name, &item.definition); err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, item)
+	}
+	return definitions, rows.Err()
+}
+
+// withSearchPath pins every connection of a pool to the tenant schema; pgx sends unknown settings as runtime parameters.
+func withSearchPath(databaseURL string, schema string) (string, error) {
+	searchPath := schema + ",public"
+	if !strings.Contains(databaseURL, "://") {
+		return strings.TrimSpace(databaseURL) + " search_path=" + searchPath, nil
+	}
+	parsed, err := url.Parse(databaseURL)
+	if err != nil {
+		return "", fmt.Errorf("parse database url: %w", err)
+	}
+	query := parsed.Query()
+	query.Set("search_path", searchPath)
+	parsed.RawQuery = query.Encode()
+	return parsed.String(), nil
+}
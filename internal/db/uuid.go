@@ -0,0 +1,34 @@
+package db
+
+import (
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// stringUUIDCodec lets the string ids sqlc generates for uuid columns go out in the binary format, which pgx only does for
+// its own UUID types. Single parameters would fall back to text, but uuid[] elements have no such fallback.
+type stringUUIDCodec struct {
+	pgtype.UUIDCodec
+}
+
+func registerStringUUID(types *pgtype.Map) {
+	types.RegisterType(&pgtype.Type{Name: "uuid", OID: pgtype.UUIDOID, Codec: stringUUIDCodec{}})
+}
+
+func (c stringUUIDCodec) PlanEncode(m *pgtype.Map, oid uint32, format int16, value any) pgtype.EncodePlan {
+	if _, ok := value.(string); ok && format == pgtype.BinaryFormatCode {
+		return encodePlanStringUUIDBinary{}
+	}
+	return c.UUIDCodec.PlanEncode(m, oid, format, value)
+}
+
+type encodePlanStringUUIDBinary struct{}
+
+func (encodePlanStringUUIDBinary) Encode(value any, buf []byte) ([]byte, error) {
+	var id pgtype.UUID
+	if err := id.Scan(value.(string)); err != nil {
+		return nil, fmt.Errorf("encode uuid: %w", err)
+	}
+	return append(buf, id.Bytes[:]...), nil
+}
@@ -0,0 +1,33 @@
+package db
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestStringUUIDEncodesBinaryArrays(t *testing.T) {
+	types := pgtype.NewMap()
+	if _, err := types.Encode(pgtype.UUIDArrayOID, pgtype.BinaryFormatCode, []string{"019f3329-a5a8-72ec-a95b-6e554247f442"}, nil); err == nil {
+		t.Fatal("expected the default map to refuse string uuids in binary")
+	}
+
+	registerStringUUID(types)
+	encoded, err := types.Encode(pgtype.UUIDArrayOID, pgtype.BinaryFormatCode, []string{"019f3329-a5a8-72ec-a95b-6e554247f442"}, nil)
+	if err != nil {
+		t.Fatalf("encode uuid array: %v", err)
+	}
+	want := []byte{0x01, 0x9f, 0x33, 0x29, 0xa5, 0xa8, 0x72, 0xec, 0xa9, 0x5b, 0x6e, 0x55, 0x42, 0x47, 0xf4, 0x42}
+	if !bytes.HasSuffix(encoded, want) {
+		t.Fatalf("expected array to end with the uuid bytes, got %x", encoded)
+	}
+
+	var ids []string
+	if err := types.Scan(pgtype.UUIDArrayOID, pgtype.BinaryFormatCode, encoded, &ids); err != nil || len(ids) != 1 || ids[0] != "019f3329-a5a8-72ec-a95b-6e554247f442" {
+		t.Fatalf("expected the id back, got %v (%v)", ids, err)
+	}
+	if _, err := types.Encode(pgtype.UUIDOID, pgtype.BinaryFormatCode, "not-a-uuid", nil); err == nil {
+		t.Fatal("expected an invalid uuid to be refused")
+	}
+}
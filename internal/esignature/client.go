@@ -0,0 +1,123 @@
+package esignature
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const maxSignedDocumentBytes = 20 << 20
+
+type Client struct {
+	provider    string
+	endpoint    string
+	callbackURL string
+	httpClient  *http.Client
+}
+
+type signer struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type request struct {
+	ExternalID  string   `json:"external_id"`
+	Title       string   `json:"title"`
+	FileName    string   `json:"file_name"`
+	Content     string   `json:"content_base64"`
+	Signers     []signer `json:"signers"`
+	CallbackURL string   `json:"callback_url,omitempty"`
+}
+
+type response struct {
+	Reference string `json:"reference"`
+}
+
+func New(provider string, endpoint string, callbackURL string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Client{
+		provider:    strings.TrimSpace(provider),
+		endpoint:    strings.TrimRight(strings.TrimSpace(endpoint), "/"),
+		callbackURL: strings.TrimSpace(callbackURL),
+		httpClient:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) CreateEnvelope(ctx context.Context, envelope service.SignatureEnvelopeRequest) (service.SignatureEnvelope, error) {
+	signers := make([]signer, 0, len(envelope.Signers))
+	for _, s := range envelope.Signers {
+		signers = append(signers, signer{Name: s.Name, Email: s.Email})
+	}
+	body, err := json.Marshal(request{
+		ExternalID:  envelope.DocumentID,
+		Title:       envelope.Title,
+		FileName:    envelope.FileName,
+		Content:     base64.StdEncoding.EncodeToString(envelope.Content),
+		Signers:     signers,
+		CallbackURL: c.callbackURL,
+	})
+	if err != nil {
+		return service.SignatureEnvelope{}, fmt.Errorf("encode envelope request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return service.SignatureEnvelope{}, fmt.Errorf("build envelope request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return service.SignatureEnvelope{}, fmt.Errorf("call signature provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return service.SignatureEnvelope{}, fmt.Errorf("signature provider returned status %d", resp.StatusCode)
+	}
+
+	var payload response
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return service.SignatureEnvelope{}, fmt.Errorf("decode envelope response: %w", err)
+	}
+	return service.SignatureEnvelope{Provider: c.provider, Reference: payload.Reference}, nil
+}
+
+func (c *Client) DownloadSignedDocument(ctx context.Context, reference string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/"+url.PathEscape(reference)+"/signed-file", nil)
+	if err != nil {
+		return nil, fmt.Errorf("build signed file request: %w", err)
+	}
+	req.Header.Set("Accept", "application/pdf")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("call signature provider: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, fmt.Errorf("signature provider returned status %d", resp.StatusCode)
+	}
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxSignedDocumentBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("read signed file: %w", err)
+	}
+	if len(content) > maxSignedDocumentBytes {
+		return nil, fmt.Errorf("signed file exceeds %d bytes", maxSignedDocumentBytes)
+	}
+	return content, nil
+}
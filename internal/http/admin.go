@@ -0,0 +1,118 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) adminLogin(c *gin.Context) {
+	var input service.LoginInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	output, err := h.service.AdminLogin(c.Request.Context(), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// requireOperator only accepts operator tokens; tenant tokens, impersonation ones included, are refused here.
+func (h *Handler) requireOperator() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, problem := bearerToken(c)
+		if problem != "" {
+			h.writeProblem(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", problem)
+			return
+		}
+
+		operator, err := h.service.AuthenticateOperatorToken(token)
+		if err != nil {
+			h.writeProblem(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", "invalid token")
+			return
+		}
+
+		c.Request = c.Request.WithContext(service.WithOperator(c.Request.Context(), operator))
+		c.Next()
+	}
+}
+
+func (h *Handler) listTenants(c *gin.Context) {
+	tenants, err := h.service.ListTenants(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tenants)
+}
+
+func (h *Handler) getTenant(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	tenant, err := h.service.GetTenant(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tenant)
+}
+
+func (h *Handler) getTenantUsage(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	usage, err := h.service.GetTenantUsageHistory(c.Request.Context(), id, optionalQuery(c, "from"), optionalQuery(c, "to"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+func (h *Handler) impersonateTenant(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.ImpersonateTenantInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	output, err := h.service.ImpersonateTenantAdmin(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+// recordTenantTraffic runs first so it also counts requests the later middleware rejects, once they are tied to an organization.
+func (h *Handler) recordTenantTraffic() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+		h.service.RecordRequestOutcome(c.Request.Context(), c.Writer.Status())
+	}
+}
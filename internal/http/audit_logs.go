@@ -0,0 +1,45 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) exportAuditLogs(c *gin.Context) {
+	from, err := parseTimeQuery(c, "from")
+	if err == nil && from == nil {
+		err = fmt.Errorf("missing required parameter %q", "from")
+	}
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+	to, err := parseTimeQuery(c, "to")
+	if err == nil && to == nil {
+		err = fmt.Errorf("missing required parameter %q", "to")
+	}
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	stream := newNDJSONStream(c, fmt.Sprintf("attachment; filename=%q", "audit-logs-"+from.UTC().Format("20060102T150405Z")+".ndjson"))
+	err = h.service.ExportAuditLogRange(c.Request.Context(), *from, *to, func(record service.AuditLogRecord) error {
+		return stream.write(record)
+	})
+	stream.finish(h, err)
+}
+
+func (h *Handler) verifyAuditChain(c *gin.Context) {
+	result, err := h.service.VerifyAuditChain(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
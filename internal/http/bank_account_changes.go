@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) requestBankAccountChange(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.RequestBankAccountChangeInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	change, err := h.service.RequestBankAccountChange(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, change)
+}
+
+func (h *Handler) listBankAccountChanges(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var status *string
+	if rawStatus := strings.TrimSpace(c.Query("status")); rawStatus != "" {
+		status = &rawStatus
+	}
+
+	changes, err := h.service.ListBankAccountChanges(c.Request.Context(), clinicID, status)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, changes)
+}
+
+func (h *Handler) approveBankAccountChange(c *gin.Context) {
+	h.reviewBankAccountChange(c, h.service.ApproveBankAccountChange)
+}
+
+func (h *Handler) rejectBankAccountChange(c *gin.Context) {
+	h.reviewBankAccountChange(c, h.service.RejectBankAccountChange)
+}
+
+func (h *Handler) reviewBankAccountChange(c *gin.Context, review func(ctx context.Context, clinicID string, changeID string, input service.ReviewBankAccountChangeInput) (service.BankAccountChangeOutput, error)) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+	changeID, err := parseID(c, "change_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.ReviewBankAccountChangeInput
+	if c.Request.ContentLength != 0 {
+		if err := bindStrictJSON(c, &input); err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+			return
+		}
+	}
+
+	change, err := review(c.Request.Context(), clinicID, changeID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
@@ -0,0 +1,190 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) createClinicAnnouncement(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateClinicAnnouncementInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	announcement, err := h.service.CreateClinicAnnouncement(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, announcement)
+}
+
+func (h *Handler) listClinicAnnouncements(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	announcements, nextCursor, err := h.service.ListClinicAnnouncements(c.Request.Context(), clinicID, limit, cursor)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, announcements)
+}
+
+func (h *Handler) getClinicAnnouncement(c *gin.Context) {
+	clinicID, announcementID, ok := h.parseClinicAnnouncementParams(c)
+	if !ok {
+		return
+	}
+
+	announcement, err := h.service.GetClinicAnnouncement(c.Request.Context(), clinicID, announcementID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, announcement)
+}
+
+func (h *Handler) listClinicAnnouncementReceipts(c *gin.Context) {
+	clinicID, announcementID, ok := h.parseClinicAnnouncementParams(c)
+	if !ok {
+		return
+	}
+
+	receipts, err := h.service.ListClinicAnnouncementReceipts(c.Request.Context(), clinicID, announcementID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, receipts)
+}
+
+func (h *Handler) parseClinicAnnouncementParams(c *gin.Context) (string, string, bool) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	announcementID, err := parseID(c, "announcement_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	return clinicID, announcementID, true
+}
+
+func (h *Handler) listOwnAnnouncements(c *gin.Context) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var unread *bool
+	if rawUnread := strings.TrimSpace(c.Query("unread")); rawUnread != "" {
+		parsedUnread, err := strconv.ParseBool(rawUnread)
+		if err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", fmt.Sprintf("invalid parameter %q: must be a boolean", "unread"))
+			return
+		}
+		unread = &parsedUnread
+	}
+
+	announcements, nextCursor, err := h.service.ListDentistAnnouncements(c.Request.Context(), principal.DentistID, limit, cursor, unread)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, announcements)
+}
+
+func (h *Handler) markOwnAnnouncementRead(c *gin.Context) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	announcementID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	announcement, err := h.service.MarkAnnouncementRead(c.Request.Context(), principal.DentistID, announcementID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, announcement)
+}
+
+func (h *Handler) getOwnNotificationPreferences(c *gin.Context) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	preferences, err := h.service.GetNotificationPreferences(c.Request.Context(), principal.DentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+func (h *Handler) updateOwnNotificationPreferences(c *gin.Context) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var input service.NotificationPreferencesInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	preferences, err := h.service.UpdateNotificationPreferences(c.Request.Context(), principal.DentistID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
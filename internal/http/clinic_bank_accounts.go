@@ -0,0 +1,250 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+const (
+	headerCallbackSignature      = "X-Webhook-Signature"
+	maxVerificationCallbackBytes = 64 << 10
+)
+
+func (h *Handler) makePrimaryBankAccount(c *gin.Context) {
+	clinicID, accountID, ok := h.parseBankAccountParams(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.service.MakePrimaryBankAccount(c.Request.Context(), clinicID, accountID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+func (h *Handler) listClinicBankAccounts(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	accounts, err := h.service.ListClinicBankAccounts(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, accounts)
+}
+
+func (h *Handler) createClinicBankAccount(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.BankAccountInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	account, err := h.service.CreateClinicBankAccount(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, account)
+}
+
+func (h *Handler) listClinicBankAccountHistory(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.BankAccountHistoryEntryOutput, *string, error) {
+			return h.service.ListClinicBankAccountHistory(ctx, clinicID, maxCursorLimit, cursor)
+		})
+		return
+	}
+
+	entries, nextCursor, err := h.service.ListClinicBankAccountHistory(c.Request.Context(), clinicID, limit, cursor)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, entries)
+}
+
+func (h *Handler) getClinicBankAccount(c *gin.Context) {
+	clinicID, accountID, ok := h.parseBankAccountParams(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.service.GetClinicBankAccount(c.Request.Context(), clinicID, accountID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *Handler) revealClinicBankAccount(c *gin.Context) {
+	clinicID, accountID, ok := h.parseBankAccountParams(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.service.RevealClinicBankAccount(c.Request.Context(), clinicID, accountID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *Handler) approveBankAccountHolder(c *gin.Context) {
+	clinicID, accountID, ok := h.parseBankAccountParams(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.service.ApproveBankAccountHolder(c.Request.Context(), clinicID, accountID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *Handler) updateClinicBankAccount(c *gin.Context) {
+	clinicID, accountID, ok := h.parseBankAccountParams(c)
+	if !ok {
+		return
+	}
+
+	var input service.UpdateBankAccountInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	account, err := h.service.UpdateClinicBankAccount(c.Request.Context(), clinicID, accountID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
+
+func (h *Handler) deleteClinicBankAccount(c *gin.Context) {
+	clinicID, accountID, ok := h.parseBankAccountParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteClinicBankAccount(c.Request.Context(), clinicID, accountID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) parseBankAccountParams(c *gin.Context) (string, string, bool) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	accountID, err := parseID(c, "account_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	return clinicID, accountID, true
+}
+
+func (h *Handler) startBankAccountVerification(c *gin.Context) {
+	clinicID, accountID, ok := h.parseBankAccountParams(c)
+	if !ok {
+		return
+	}
+
+	account, err := h.service.StartBankAccountVerification(c.Request.Context(), clinicID, accountID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, account)
+}
+
+func (h *Handler) bankAccountVerificationCallback(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxVerificationCallbackBytes))
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", "invalid request body")
+		return
+	}
+	if err := h.service.VerifyBankAccountCallbackSignature(body, c.GetHeader(headerCallbackSignature)); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	var input service.BankAccountVerificationCallbackInput
+	if err := json.Unmarshal(body, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+	if err := h.service.CompleteBankAccountVerification(c.Request.Context(), input); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) getClinicPayoutAccount(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	account, err := h.service.GetClinicPayoutAccount(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, account)
+}
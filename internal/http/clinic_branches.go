@@ -0,0 +1,64 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) createClinicBranch(c *gin.Context) {
+	parentClinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateClinicInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	branch, err := h.service.CreateClinicBranch(c.Request.Context(), parentClinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, branch)
+}
+
+func (h *Handler) listClinicBranches(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	branches, err := h.service.ListClinicBranches(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, branches)
+}
+
+func (h *Handler) getClinicGroupReport(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	report, err := h.service.GetClinicGroupReport(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) getClinicOperatingHours(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	hours, err := h.service.GetClinicOperatingHours(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+func (h *Handler) replaceClinicOperatingHours(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.ReplaceOperatingHoursInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	hours, err := h.service.ReplaceClinicOperatingHours(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hours)
+}
+
+func (h *Handler) listClinicHolidays(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	year := time.Now().UTC().Year()
+	if rawYear := strings.TrimSpace(c.Query("year")); rawYear != "" {
+		year, err = strconv.Atoi(rawYear)
+		if err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", fmt.Sprintf("invalid parameter %q: must be an integer", "year"))
+			return
+		}
+	}
+
+	holidays, err := h.service.ListClinicHolidays(c.Request.Context(), clinicID, year)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, holidays)
+}
+
+func (h *Handler) createClinicHoliday(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateClinicHolidayInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	holiday, err := h.service.CreateClinicHoliday(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, holiday)
+}
+
+func (h *Handler) deleteClinicHoliday(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	holidayID, err := parseID(c, "holiday_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if err := h.service.DeleteClinicHoliday(c.Request.Context(), clinicID, holidayID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) getClinicAvailability(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	at, err := time.Parse(time.RFC3339, strings.TrimSpace(c.Query("at")))
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", fmt.Sprintf("invalid parameter %q: must be an RFC 3339 timestamp", "at"))
+		return
+	}
+
+	availability, err := h.service.CheckClinicAvailability(c.Request.Context(), clinicID, at)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, availability)
+}
@@ -0,0 +1,83 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+const publicDirectoryCacheControl = "public, max-age=60"
+
+func (h *Handler) getClinicDirectoryListing(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	listing, err := h.service.GetClinicDirectoryListing(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, listing)
+}
+
+func (h *Handler) updateClinicDirectoryListing(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateClinicDirectoryListingInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	listing, err := h.service.UpdateClinicDirectoryListing(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, listing)
+}
+
+func (h *Handler) listPublicClinics(c *gin.Context) {
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	filter := service.PublicClinicDirectoryFilter{
+		Organization: optionalQuery(c, "organization"),
+		City:         optionalQuery(c, "city"),
+		State:        optionalQuery(c, "state"),
+		Specialty:    optionalQuery(c, "specialty"),
+	}
+	clinics, nextCursor, err := h.service.ListPublicClinicDirectory(c.Request.Context(), limit, cursor, filter)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.Header("Cache-Control", publicDirectoryCacheControl)
+	c.JSON(http.StatusOK, clinics)
+}
+
+func optionalQuery(c *gin.Context, key string) *string {
+	value := strings.TrimSpace(c.Query(key))
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+type duplicateClinicProblem struct {
+	ProblemDetails
+	Candidates []service.ClinicDuplicateCandidate `json:"candidates"`
+}
+
+func writeDuplicateClinicProblem(c *gin.Context, err *service.DuplicateClinicError) {
+	problem := newProblemDetails(c, http.StatusConflict, problemTypeDuplicate, "Possible Duplicate", err.Error())
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(http.StatusConflict, duplicateClinicProblem{
+		ProblemDetails: problem,
+		Candidates:     err.Candidates,
+	})
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) placeClinicFinancialHold(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.PlaceFinancialHoldInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	hold, err := h.service.PlaceClinicFinancialHold(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+func (h *Handler) listClinicFinancialHolds(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var active *bool
+	if rawActive := strings.TrimSpace(c.Query("active")); rawActive != "" {
+		parsedActive, err := strconv.ParseBool(rawActive)
+		if err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", fmt.Sprintf("invalid parameter %q: must be a boolean", "active"))
+			return
+		}
+		active = &parsedActive
+	}
+
+	holds, err := h.service.ListClinicFinancialHolds(c.Request.Context(), clinicID, active)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, holds)
+}
+
+func (h *Handler) liftClinicFinancialHold(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+	holdID, err := parseID(c, "hold_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.LiftFinancialHoldInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	hold, err := h.service.LiftClinicFinancialHold(c.Request.Context(), clinicID, holdID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
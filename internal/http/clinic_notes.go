@@ -0,0 +1,122 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) createClinicNote(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateClinicNoteInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	note, err := h.service.CreateClinicNote(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, note)
+}
+
+func (h *Handler) listClinicNotes(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var pinned *bool
+	if rawPinned := strings.TrimSpace(c.Query("pinned")); rawPinned != "" {
+		parsedPinned, err := strconv.ParseBool(rawPinned)
+		if err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", fmt.Sprintf("invalid parameter %q: must be a boolean", "pinned"))
+			return
+		}
+		pinned = &parsedPinned
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.ClinicNoteOutput, *string, error) {
+			return h.service.ListClinicNotesWithCursor(ctx, clinicID, maxCursorLimit, cursor, pinned)
+		})
+		return
+	}
+
+	notes, nextCursor, err := h.service.ListClinicNotesWithCursor(c.Request.Context(), clinicID, limit, cursor, pinned)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, notes)
+}
+
+func (h *Handler) updateClinicNote(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+	noteID, err := parseID(c, "note_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateClinicNoteInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	note, err := h.service.UpdateClinicNote(c.Request.Context(), clinicID, noteID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, note)
+}
+
+func (h *Handler) deleteClinicNote(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+	noteID, err := parseID(c, "note_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if err := h.service.DeleteClinicNote(c.Request.Context(), clinicID, noteID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,39 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) listClinicRevisions(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.ClinicRevisionOutput, *string, error) {
+			return h.service.ListClinicRevisions(ctx, clinicID, maxCursorLimit, cursor, optionalQuery(c, "entity_type"))
+		})
+		return
+	}
+
+	revisions, nextCursor, err := h.service.ListClinicRevisions(c.Request.Context(), clinicID, limit, cursor, optionalQuery(c, "entity_type"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, revisions)
+}
@@ -0,0 +1,113 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) getShiftBoard(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	board, err := h.service.GetShiftBoard(c.Request.Context(), clinicID, optionalQuery(c, "week"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, board)
+}
+
+func (h *Handler) getClinicDashboardToday(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	dashboard, err := h.service.GetClinicDashboardToday(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dashboard)
+}
+
+func (h *Handler) createClinicShift(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateClinicShiftInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	shift, err := h.service.CreateClinicShift(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, shift)
+}
+
+func (h *Handler) updateClinicShift(c *gin.Context) {
+	clinicID, shiftID, ok := h.parseClinicShiftParams(c)
+	if !ok {
+		return
+	}
+
+	var input service.UpdateClinicShiftInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	shift, err := h.service.UpdateClinicShift(c.Request.Context(), clinicID, shiftID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, shift)
+}
+
+func (h *Handler) deleteClinicShift(c *gin.Context) {
+	clinicID, shiftID, ok := h.parseClinicShiftParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteClinicShift(c.Request.Context(), clinicID, shiftID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) parseClinicShiftParams(c *gin.Context) (string, string, bool) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	shiftID, err := parseID(c, "shift_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	return clinicID, shiftID, true
+}
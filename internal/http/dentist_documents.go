@@ -0,0 +1,133 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) createDentistDocument(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateDentistDocumentInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	document, err := h.service.CreateDentistDocument(c.Request.Context(), dentistID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+func (h *Handler) listDentistDocuments(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	documents, err := h.service.ListDentistDocuments(c.Request.Context(), dentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+func (h *Handler) updateDentistDocument(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	documentID, err := parseID(c, "document_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateDentistDocumentInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	document, err := h.service.UpdateDentistDocument(c.Request.Context(), dentistID, documentID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+func (h *Handler) deleteDentistDocument(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	documentID, err := parseID(c, "document_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if err := h.service.DeleteDentistDocument(c.Request.Context(), dentistID, documentID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) listClinicExpiringDocuments(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	withinDays, err := parseWithinDays(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	documents, err := h.service.ListClinicExpiringDocuments(c.Request.Context(), clinicID, withinDays)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, documents)
+}
+
+func parseWithinDays(c *gin.Context) (int, error) {
+	rawWithinDays := strings.TrimSpace(c.Query("within_days"))
+	if rawWithinDays == "" {
+		return service.DefaultExpiringWithinDays, nil
+	}
+	withinDays, err := strconv.Atoi(rawWithinDays)
+	if err != nil || withinDays < 0 || withinDays > service.MaxExpiringWithinDays {
+		return 0, fmt.Errorf("invalid parameter %q: must be an integer between 0 and %d", "within_days", service.MaxExpiringWithinDays)
+	}
+	return withinDays, nil
+}
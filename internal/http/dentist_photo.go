@@ -0,0 +1,137 @@
+package http
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+const (
+	problemTypePayloadTooLarge = "https://capim.test/problems/payload-too-large"
+	dentistPhotoFormField      = "photo"
+	multipartOverheadBytes     = 1 << 20
+)
+
+func (h *Handler) putDentistPhoto(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	h.uploadDentistPhoto(c, dentistID)
+}
+
+func (h *Handler) uploadDentistPhoto(c *gin.Context, dentistID string) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, service.DentistPhotoMaxBytes+multipartOverheadBytes)
+	fileHeader, err := c.FormFile(dentistPhotoFormField)
+	if err != nil {
+		if _, ok := errors.AsType[*http.MaxBytesError](err); ok {
+			h.writePhotoTooLarge(c)
+			return
+		}
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("multipart field %q is required", dentistPhotoFormField))
+		return
+	}
+	if fileHeader.Size > service.DentistPhotoMaxBytes {
+		h.writePhotoTooLarge(c)
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		h.writeError(c, fmt.Errorf("open uploaded photo: %w", err))
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, service.DentistPhotoMaxBytes+1))
+	if err != nil {
+		h.writeError(c, fmt.Errorf("read uploaded photo: %w", err))
+		return
+	}
+	if len(data) > service.DentistPhotoMaxBytes {
+		h.writePhotoTooLarge(c)
+		return
+	}
+
+	photo, err := h.service.SetDentistPhoto(c.Request.Context(), dentistID, data)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	if photo.Scan != nil {
+		c.JSON(http.StatusAccepted, photo)
+		return
+	}
+	c.JSON(http.StatusOK, photo)
+}
+
+func (h *Handler) getDentistPhotoScan(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	h.writeDentistPhotoScan(c, dentistID)
+}
+
+func (h *Handler) writeDentistPhotoScan(c *gin.Context, dentistID string) {
+	scan, err := h.service.GetDentistPhotoScan(c.Request.Context(), dentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, scan)
+}
+
+func (h *Handler) getDentistPhoto(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	photo, err := h.service.GetDentistPhoto(c.Request.Context(), dentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	h.serveDentistPhoto(c, photo)
+}
+
+func (h *Handler) getDentistPhotoThumbnail(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	photo, err := h.service.GetDentistPhotoThumbnail(c.Request.Context(), dentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	h.serveDentistPhoto(c, photo)
+}
+
+func (h *Handler) serveDentistPhoto(c *gin.Context, photo service.Attachment) {
+	c.Header("Content-Type", photo.ContentType)
+	c.Header("Cache-Control", "public, max-age=300")
+	http.ServeContent(c.Writer, c.Request, "photo", photo.UpdatedAt, bytes.NewReader(photo.Data))
+}
+
+func (h *Handler) writePhotoTooLarge(c *gin.Context) {
+	h.writeProblem(c, http.StatusRequestEntityTooLarge, problemTypePayloadTooLarge, "Payload Too Large", fmt.Sprintf("photo must be at most %d bytes", service.DentistPhotoMaxBytes))
+}
@@ -0,0 +1,249 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) listDocumentTemplates(c *gin.Context) {
+	templates, err := h.service.ListDocumentTemplates(c.Request.Context(), optionalQuery(c, "kind"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+func (h *Handler) createDocumentTemplate(c *gin.Context) {
+	var input service.CreateDocumentTemplateInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	template, err := h.service.CreateDocumentTemplate(c.Request.Context(), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+func (h *Handler) getDocumentTemplate(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	template, err := h.service.GetDocumentTemplate(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *Handler) updateDocumentTemplate(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateDocumentTemplateInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	template, err := h.service.UpdateDocumentTemplate(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+func (h *Handler) deleteDocumentTemplate(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if err := h.service.DeleteDocumentTemplate(c.Request.Context(), id); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) generateDocument(c *gin.Context) {
+	var input service.GenerateDocumentInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	document, err := h.service.GenerateDocument(c.Request.Context(), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, document)
+}
+
+func (h *Handler) getGeneratedDocument(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	document, err := h.service.GetGeneratedDocument(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, document)
+}
+
+func (h *Handler) downloadGeneratedDocument(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	file, err := h.service.GetGeneratedDocumentFile(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func (h *Handler) listClinicGeneratedDocuments(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	documents, nextCursor, err := h.service.ListClinicGeneratedDocuments(c.Request.Context(), clinicID, limit, cursor, optionalQuery(c, "template_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, documents)
+}
+
+func (h *Handler) requestDocumentSignature(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateDocumentSignatureRequestInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	request, err := h.service.RequestDocumentSignature(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, request)
+}
+
+func (h *Handler) listDocumentSignatureRequests(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	requests, err := h.service.ListDocumentSignatureRequests(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+func (h *Handler) downloadSignedDocument(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+	requestID, err := parseID(c, "request_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	file, err := h.service.GetSignedDocumentFile(c.Request.Context(), id, requestID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func (h *Handler) documentSignatureCallback(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxVerificationCallbackBytes))
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", "invalid request body")
+		return
+	}
+	if err := h.service.VerifySignatureCallbackSignature(body, c.GetHeader(headerCallbackSignature)); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	var input service.DocumentSignatureCallbackInput
+	if err := json.Unmarshal(body, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+	if err := h.service.CompleteDocumentSignature(c.Request.Context(), input); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
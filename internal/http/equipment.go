@@ -0,0 +1,184 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) createEquipment(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateEquipmentInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	equipment, err := h.service.CreateEquipment(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, equipment)
+}
+
+func (h *Handler) listEquipment(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	equipment, nextCursor, err := h.service.ListEquipment(c.Request.Context(), clinicID, limit, cursor, optionalQuery(c, "kind"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, equipment)
+}
+
+func (h *Handler) getEquipment(c *gin.Context) {
+	clinicID, equipmentID, ok := h.parseEquipmentParams(c)
+	if !ok {
+		return
+	}
+
+	equipment, err := h.service.GetEquipment(c.Request.Context(), clinicID, equipmentID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, equipment)
+}
+
+func (h *Handler) updateEquipment(c *gin.Context) {
+	clinicID, equipmentID, ok := h.parseEquipmentParams(c)
+	if !ok {
+		return
+	}
+
+	var input service.UpdateEquipmentInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	equipment, err := h.service.UpdateEquipment(c.Request.Context(), clinicID, equipmentID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, equipment)
+}
+
+func (h *Handler) deleteEquipment(c *gin.Context) {
+	clinicID, equipmentID, ok := h.parseEquipmentParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteEquipment(c.Request.Context(), clinicID, equipmentID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) createMaintenanceRecord(c *gin.Context) {
+	clinicID, equipmentID, ok := h.parseEquipmentParams(c)
+	if !ok {
+		return
+	}
+
+	var input service.CreateMaintenanceRecordInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	record, err := h.service.RecordMaintenance(c.Request.Context(), clinicID, equipmentID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, record)
+}
+
+func (h *Handler) listMaintenanceRecords(c *gin.Context) {
+	clinicID, equipmentID, ok := h.parseEquipmentParams(c)
+	if !ok {
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	records, nextCursor, err := h.service.ListMaintenanceRecords(c.Request.Context(), clinicID, equipmentID, limit, cursor)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, records)
+}
+
+func (h *Handler) getEquipmentComplianceReport(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	withinDays, err := parseWithinDays(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	report, err := h.service.GetEquipmentComplianceReport(c.Request.Context(), clinicID, withinDays)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) parseEquipmentParams(c *gin.Context) (string, string, bool) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	equipmentID, err := parseID(c, "equipment_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	return clinicID, equipmentID, true
+}
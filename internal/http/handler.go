@@ -1,6 +1,8 @@
 package http
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -13,6 +15,7 @@ import (
 
 	"github.com/gin-contrib/requestid"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 	"go.opentelemetry.io/otel"
@@ -21,6 +24,7 @@ import (
 	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 
+	"capim-test/internal/sanitize"
 	"capim-test/internal/service"
 )
 
@@ -38,18 +42,32 @@ type ProblemDetails struct {
 }
 
 const (
-	problemContentType      = "application/problem+json"
-	problemTypeValidation   = "https://capim.test/problems/validation-error"
-	problemTypeNotFound     = "https://capim.test/problems/not-found"
-	problemTypeConflict     = "https://capim.test/problems/conflict"
-	problemTypeUnauthorized = "https://capim.test/problems/unauthorized"
-	problemTypeInternal     = "https://capim.test/problems/internal-error"
-	problemTypeInvalidParam = "https://capim.test/problems/invalid-parameter"
+	problemContentType       = "application/problem+json"
+	problemTypeValidation    = "https://capim.test/problems/validation-error"
+	problemTypeNotFound      = "https://capim.test/problems/not-found"
+	problemTypeConflict      = "https://capim.test/problems/conflict"
+	problemTypeUnauthorized  = "https://capim.test/problems/unauthorized"
+	problemTypeForbidden     = "https://capim.test/problems/forbidden"
+	problemTypeInternal      = "https://capim.test/problems/internal-error"
+	problemTypeInvalidParam  = "https://capim.test/problems/invalid-parameter"
+	problemTypeBlockedTaxID  = "https://capim.test/problems/blocked-tax-id"
+	problemTypeRoleInvariant = "https://capim.test/problems/clinic-role-invariant"
+	problemTypeFinancialHold = "https://capim.test/problems/financial-hold"
+	problemTypeDuplicate     = "https://capim.test/problems/possible-duplicate"
+	problemTypeConfirmation  = "https://capim.test/problems/confirmation-required"
+	problemTypeRateLimited   = "https://capim.test/problems/rate-limited"
+	problemTypeQuota         = "https://capim.test/problems/quota-exceeded"
+	problemTypeFeature       = "https://capim.test/problems/feature-not-in-plan"
+	problemTypeResidency     = "https://capim.test/problems/data-residency"
+	problemTypeOverloaded    = "https://capim.test/problems/overloaded"
+	problemTypeTimeout       = "https://capim.test/problems/timeout"
 )
 
 const (
 	defaultCursorLimit = 20
 	maxCursorLimit     = 100
+	maxCursorLength    = 256
+	cursorAlphabet     = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
 )
 
 const (
@@ -58,13 +76,85 @@ const (
 	headerRequestID  = "X-Request-ID"
 )
 
-func NewRouter(service *service.Service, serviceName string) *gin.Engine {
+// statusClientClosedRequest is the nginx convention for a request whose client hung up before the response; nobody reads it.
+const statusClientClosedRequest = 499
+
+const (
+	defaultPublicRateLimit       = 60
+	defaultPublicRateLimitWindow = time.Minute
+)
+
+type routerConfig struct {
+	publicRateLimit       int
+	publicRateLimitWindow time.Duration
+	platformAPIKey        string
+	tenantBaseDomain      string
+	adminConsole          bool
+	loadShedding          LoadSheddingConfig
+	queryTimeouts         QueryTimeoutConfig
+}
+
+type RouterOption func(*routerConfig)
+
+func WithPublicRateLimit(limit int, window time.Duration) RouterOption {
+	return func(cfg *routerConfig) {
+		// A non-positive limit disables rate limiting for the public routes.
+		cfg.publicRateLimit = limit
+		if window > 0 {
+			cfg.publicRateLimitWindow = window
+		}
+	}
+}
+
+// WithPlatformAPIKey enables the organization management routes; without a key they are not registered.
+func WithPlatformAPIKey(key string) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.platformAPIKey = strings.TrimSpace(key)
+	}
+}
+
+// WithTenantBaseDomain resolves the organization from the first label of hosts under domain, as in acme.<domain>.
+func WithTenantBaseDomain(domain string) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.tenantBaseDomain = domain
+	}
+}
+
+// WithLoadShedding answers 503 to requests beyond an adaptive concurrency limit; a zero MaxConcurrency leaves it off.
+func WithLoadShedding(config LoadSheddingConfig) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.loadShedding = config
+	}
+}
+
+// WithQueryTimeouts gives each request a deadline for its queries, config.Default unless the route has its own.
+func WithQueryTimeouts(config QueryTimeoutConfig) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.queryTimeouts = config
+	}
+}
+
+// WithAdminConsole registers the /admin/v1 operator routes; the service must have an admin signing key.
+func WithAdminConsole(enabled bool) RouterOption {
+	return func(cfg *routerConfig) {
+		cfg.adminConsole = enabled
+	}
+}
+
+func NewRouter(svc *service.Service, serviceName string, options ...RouterOption) *gin.Engine {
 	if strings.TrimSpace(serviceName) == "" {
 		serviceName = "capim-test-api"
 	}
+	cfg := routerConfig{
+		publicRateLimit:       defaultPublicRateLimit,
+		publicRateLimitWindow: defaultPublicRateLimitWindow,
+	}
+	for _, option := range options {
+		option(&cfg)
+	}
 
 	router := gin.New()
-	h := &Handler{service: service}
+	h := &Handler{service: svc}
 	requestObsMiddleware := requestObservabilityMiddleware(slog.Default())
 	router.Use(
 		requestid.New(),
@@ -72,26 +162,259 @@ func NewRouter(service *service.Service, serviceName string) *gin.Engine {
 		otelgin.Middleware(serviceName),
 		requestObsMiddleware,
 	)
+	if cfg.loadShedding.MaxConcurrency > 0 && cfg.loadShedding.TargetP99 > 0 {
+		router.Use(loadSheddingMiddleware(newConcurrencyLimiter(cfg.loadShedding), slog.Default()))
+	}
+	router.Use(queryTimeoutMiddleware(cfg.queryTimeouts))
+
+	public := router.Group("/public/v1")
+	if cfg.publicRateLimit > 0 {
+		public.Use(rateLimitMiddleware(newFixedWindowLimiter(cfg.publicRateLimit, cfg.publicRateLimitWindow)))
+	}
+	public.Use(h.resolveTenant(cfg.tenantBaseDomain), h.requireOrganizationAccess())
+	public.GET("/clinics", h.listPublicClinics)
 
 	api := router.Group("/api")
 	v1 := api.Group("/v1")
 
 	v1.GET("/health", h.health)
 	v1.POST("/auth/login", h.login)
+	v1.GET("/dentists/:id/photo", h.getDentistPhoto)
+	v1.GET("/dentists/:id/photo/thumbnail", h.getDentistPhotoThumbnail)
+	v1.POST("/bank-account-verifications/callback", h.bankAccountVerificationCallback)
+	v1.POST("/notification-deliveries/callback", h.notificationDeliveryCallback)
+	v1.POST("/signature-requests/callback", h.documentSignatureCallback)
+
+	if cfg.platformAPIKey != "" {
+		platform := v1.Group("/platform")
+		platform.Use(requirePlatformKey(cfg.platformAPIKey))
+		platform.POST("/organizations", h.createOrganization)
+		platform.GET("/organizations", h.listOrganizations)
+		platform.PUT("/organizations/:id/quotas", h.updateOrganizationQuotas)
+		platform.POST("/organizations/:id/crypto-shred", h.shredOrganizationDataKey)
+		platform.PUT("/organizations/:id/status", h.updateOrganizationStatus)
+		platform.PUT("/organizations/:id/plan", h.updateOrganizationPlan)
+		platform.PUT("/organizations/:id/data-region", h.updateOrganizationDataRegion)
+		platform.POST("/organizations/:id/offboarding", h.offboardOrganization)
+		platform.GET("/organizations/:id/export", h.downloadOrganizationExport)
+	}
+
+	if cfg.adminConsole {
+		admin := router.Group("/admin/v1")
+		admin.POST("/auth/login", h.adminLogin)
+		operator := admin.Group("")
+		operator.Use(h.requireOperator())
+		operator.GET("/tenants", h.listTenants)
+		operator.GET("/tenants/:id", h.getTenant)
+		operator.GET("/tenants/:id/usage", h.getTenantUsage)
+		operator.POST("/tenants/:id/impersonate", h.impersonateTenant)
+	}
 
-	protected := v1.Group("")
-	protected.Use(h.requireAuth())
+	authenticated := v1.Group("")
+	authenticated.Use(h.recordTenantTraffic(), h.resolveTenant(cfg.tenantBaseDomain), h.requireAuth(), h.requireOrganizationAccess(), h.enforceRequestQuota())
+
+	authenticated.GET("/org/entitlements", h.getOrganizationEntitlements)
+	authenticated.GET("/me/tasks", h.listOwnTasks)
+	authenticated.PATCH("/me/tasks/:id", h.updateOwnTask)
+
+	me := authenticated.Group("/me")
+	me.Use(h.requireRole(service.UserRoleDentist))
+	me.GET("/dentist-profile", h.getOwnDentistProfile)
+	me.PATCH("/dentist-profile", h.updateOwnDentistProfile)
+	me.PUT("/dentist-profile/photo", h.putOwnDentistPhoto)
+	me.GET("/dentist-profile/photo/scan", h.getOwnDentistPhotoScan)
+	me.POST("/clinics/:id/bank-account-changes", h.requestBankAccountChange)
+	me.GET("/announcements", h.listOwnAnnouncements)
+	me.POST("/announcements/:id/read", h.markOwnAnnouncementRead)
+	me.GET("/notification-preferences", h.getOwnNotificationPreferences)
+	me.PUT("/notification-preferences", h.updateOwnNotificationPreferences)
+	me.POST("/clinics/:id/clock-in", h.clockIn)
+	me.POST("/clinics/:id/clock-out", h.clockOut)
+	me.GET("/clinics/:id/workspace-config", h.getOwnWorkspaceConfig)
+
+	protected := authenticated.Group("")
+	protected.Use(h.requireRole(service.UserRoleAdmin))
 	protected.GET("/clinics", h.listClinics)
 	protected.POST("/clinics", h.createClinic)
 	protected.GET("/clinics/:id", h.getClinic)
 	protected.PATCH("/clinics/:id", h.updateClinic)
 	protected.DELETE("/clinics/:id", h.deleteClinic)
+	protected.GET("/clinics/:id/delete-preview", h.getClinicDeletePreview)
+	protected.POST("/clinics/:id/restore", h.restoreClinic)
+	protected.POST("/clinics/:id/deactivate", h.deactivateClinic)
+	protected.POST("/clinics/:id/reactivate", h.reactivateClinic)
+	protected.GET("/clinics/:id/revisions", h.listClinicRevisions)
+	protected.GET("/clinics/:id/bank-accounts", h.listClinicBankAccounts)
+	protected.POST("/clinics/:id/bank-accounts", h.createClinicBankAccount)
+	protected.GET("/clinics/:id/bank-accounts/history", h.listClinicBankAccountHistory)
+	protected.GET("/clinics/:id/bank-accounts/:account_id", h.getClinicBankAccount)
+	protected.GET("/clinics/:id/bank-accounts/:account_id/reveal", h.revealClinicBankAccount)
+	protected.PATCH("/clinics/:id/bank-accounts/:account_id", h.updateClinicBankAccount)
+	protected.DELETE("/clinics/:id/bank-accounts/:account_id", h.deleteClinicBankAccount)
+	protected.POST("/clinics/:id/bank-accounts/:account_id/make-primary", h.makePrimaryBankAccount)
+	protected.POST("/clinics/:id/bank-accounts/:account_id/verify", h.startBankAccountVerification)
+	protected.POST("/clinics/:id/bank-accounts/:account_id/approve-holder", h.approveBankAccountHolder)
+	protected.GET("/clinics/:id/payout-account", h.getClinicPayoutAccount)
+	protected.GET("/clinics/:id/bank-account-changes", h.listBankAccountChanges)
+	protected.POST("/clinics/:id/bank-account-changes", h.requestBankAccountChange)
+	protected.POST("/clinics/:id/bank-account-changes/:change_id/approve", h.approveBankAccountChange)
+	protected.POST("/clinics/:id/bank-account-changes/:change_id/reject", h.rejectBankAccountChange)
+	protected.GET("/clinics/:id/branches", h.listClinicBranches)
+	protected.POST("/clinics/:id/branches", h.createClinicBranch)
+	protected.GET("/clinics/:id/group-report", h.getClinicGroupReport)
+	protected.GET("/clinics/:id/settings", h.getClinicSettings)
+	protected.GET("/clinics/:id/onboarding", h.getClinicOnboarding)
+	protected.GET("/clinics/:id/dashboard/today", h.getClinicDashboardToday)
+	protected.GET("/clinics/:id/notes", h.listClinicNotes)
+	protected.POST("/clinics/:id/notes", h.createClinicNote)
+	protected.PATCH("/clinics/:id/notes/:note_id", h.updateClinicNote)
+	protected.DELETE("/clinics/:id/notes/:note_id", h.deleteClinicNote)
+	protected.GET("/clinics/:id/tasks", h.listClinicTasks)
+	protected.POST("/clinics/:id/tasks", h.createClinicTask)
+	protected.PATCH("/clinics/:id/tasks/:task_id", h.updateClinicTask)
+	protected.DELETE("/clinics/:id/tasks/:task_id", h.deleteClinicTask)
+	protected.GET("/clinics/:id/task-rules", h.listClinicTaskRules)
+	protected.PUT("/clinics/:id/task-rules", h.replaceClinicTaskRules)
+	protected.GET("/clinics/:id/inventory/items", h.listInventoryItems)
+	protected.POST("/clinics/:id/inventory/items", h.createInventoryItem)
+	protected.GET("/clinics/:id/inventory/items/:item_id", h.getInventoryItem)
+	protected.PATCH("/clinics/:id/inventory/items/:item_id", h.updateInventoryItem)
+	protected.DELETE("/clinics/:id/inventory/items/:item_id", h.deleteInventoryItem)
+	protected.GET("/clinics/:id/inventory/items/:item_id/movements", h.listInventoryMovements)
+	protected.POST("/clinics/:id/inventory/items/:item_id/movements", h.createInventoryMovement)
+	protected.GET("/clinics/:id/inventory/low-stock", h.getLowStockReport)
+	protected.GET("/clinics/:id/purchase-orders", h.listPurchaseOrders)
+	protected.POST("/clinics/:id/purchase-orders", h.createPurchaseOrder)
+	protected.GET("/clinics/:id/purchase-orders/:order_id", h.getPurchaseOrder)
+	protected.POST("/clinics/:id/purchase-orders/:order_id/submit", h.submitPurchaseOrder)
+	protected.POST("/clinics/:id/purchase-orders/:order_id/cancel", h.cancelPurchaseOrder)
+	protected.POST("/clinics/:id/purchase-orders/:order_id/receipts", h.receivePurchaseOrder)
+	protected.GET("/clinics/:id/equipment", h.listEquipment)
+	protected.POST("/clinics/:id/equipment", h.createEquipment)
+	protected.GET("/clinics/:id/equipment/:equipment_id", h.getEquipment)
+	protected.PATCH("/clinics/:id/equipment/:equipment_id", h.updateEquipment)
+	protected.DELETE("/clinics/:id/equipment/:equipment_id", h.deleteEquipment)
+	protected.GET("/clinics/:id/equipment/:equipment_id/maintenance", h.listMaintenanceRecords)
+	protected.POST("/clinics/:id/equipment/:equipment_id/maintenance", h.createMaintenanceRecord)
+	protected.GET("/clinics/:id/announcements", h.listClinicAnnouncements)
+	protected.POST("/clinics/:id/announcements", h.createClinicAnnouncement)
+	protected.GET("/clinics/:id/announcements/:announcement_id", h.getClinicAnnouncement)
+	protected.GET("/clinics/:id/announcements/:announcement_id/receipts", h.listClinicAnnouncementReceipts)
+	protected.POST("/clinics/:id/onboarding/transitions", h.advanceClinicOnboarding)
+	protected.PATCH("/clinics/:id/settings", h.updateClinicSettings)
+	protected.GET("/clinics/:id/directory-listing", h.getClinicDirectoryListing)
+	protected.PUT("/clinics/:id/directory-listing", h.updateClinicDirectoryListing)
 	protected.POST("/clinics/:id/dentists", h.createDentist)
 	protected.GET("/clinics/:id/dentists", h.listClinicDentists)
+	protected.PATCH("/clinics/:id/dentists", h.bulkUpdateClinicDentistRoles)
 	protected.PATCH("/clinics/:id/dentists/:dentist_id", h.updateClinicDentistRole)
 	protected.DELETE("/clinics/:id/dentists/:dentist_id", h.unlinkDentistFromClinic)
+	protected.GET("/clinics/:id/dentists/:dentist_id/history", h.getClinicDentistHistory)
+	protected.GET("/clinics/:id/dentists/:dentist_id/tenure", h.getClinicDentistTenure)
+	protected.GET("/clinics/:id/staff/:user_id/workspace-config", h.getStaffWorkspaceConfig)
+	protected.GET("/clinics/:id/compliance/expiring-documents", h.listClinicExpiringDocuments)
+	protected.GET("/clinics/:id/compliance/equipment-maintenance", h.getEquipmentComplianceReport)
+	protected.GET("/clinics/:id/time-clock/settings", h.getTimeClockSettings)
+	protected.PUT("/clinics/:id/time-clock/settings", h.updateTimeClockSettings)
+	protected.GET("/clinics/:id/timesheets", h.getClinicTimesheet)
+	protected.GET("/clinics/:id/timesheets/export", h.exportClinicTimesheet)
+	protected.GET("/clinics/:id/documents", h.listClinicGeneratedDocuments)
+	protected.GET("/dentists/:id", h.getDentist)
 	protected.PATCH("/dentists/:id", h.updateDentist)
 	protected.DELETE("/dentists/:id", h.deleteDentist)
+	protected.POST("/dentists/:id/restore", h.restoreDentist)
+	protected.GET("/dentists/:id/employment-history", h.getDentistEmploymentHistory)
+	protected.PUT("/dentists/:id/photo", h.putDentistPhoto)
+	protected.GET("/dentists/:id/photo/scan", h.getDentistPhotoScan)
+	protected.POST("/dentists/:id/user", h.createDentistUser)
+	protected.POST("/dentists/:id/reassign-person", h.reassignDentistPerson)
+	protected.GET("/dentists/:id/documents", h.listDentistDocuments)
+	protected.POST("/dentists/:id/documents", h.createDentistDocument)
+	protected.PATCH("/dentists/:id/documents/:document_id", h.updateDentistDocument)
+	protected.DELETE("/dentists/:id/documents/:document_id", h.deleteDentistDocument)
+	protected.GET("/document-templates", h.listDocumentTemplates)
+	protected.POST("/document-templates", h.createDocumentTemplate)
+	protected.GET("/document-templates/:id", h.getDocumentTemplate)
+	protected.PATCH("/document-templates/:id", h.updateDocumentTemplate)
+	protected.DELETE("/document-templates/:id", h.deleteDocumentTemplate)
+	protected.POST("/documents/generate", h.generateDocument)
+	protected.GET("/documents/:id", h.getGeneratedDocument)
+	protected.GET("/documents/:id/file", h.downloadGeneratedDocument)
+	protected.GET("/documents/:id/signature-requests", h.listDocumentSignatureRequests)
+	protected.POST("/documents/:id/signature-requests", h.requestDocumentSignature)
+	protected.GET("/documents/:id/signature-requests/:request_id/file", h.downloadSignedDocument)
+	protected.GET("/reports", h.listReports)
+	protected.POST("/reports", h.requestReport)
+	protected.GET("/reports/:id", h.getReport)
+	protected.GET("/reports/:id/file", h.downloadReport)
+	protected.GET("/report-builder/catalog", h.getReportBuilderCatalog)
+	protected.GET("/clinics/:id/report-definitions", h.listReportDefinitions)
+	protected.POST("/clinics/:id/report-definitions", h.createReportDefinition)
+	protected.GET("/report-definitions/:id", h.getReportDefinition)
+	protected.PATCH("/report-definitions/:id", h.updateReportDefinition)
+	protected.DELETE("/report-definitions/:id", h.deleteReportDefinition)
+	protected.POST("/report-definitions/:id/run", h.runReportDefinition)
+	protected.GET("/deleted-resources", h.listDeletedResources)
+	protected.GET("/trash", h.listTrash)
+	protected.GET("/audit-logs/export", h.exportAuditLogs)
+	protected.GET("/audit-logs/verify", h.verifyAuditChain)
+	protected.GET("/warehouse-export/watermarks", h.listWarehouseExportWatermarks)
+	protected.POST("/integrity-checks", h.runIntegrityChecks)
+	protected.GET("/org/usage", h.getOrganizationUsage)
+	protected.GET("/notifications", h.listNotifications)
+	protected.GET("/notifications/:id", h.getNotification)
+	protected.POST("/notifications/:id/retry", h.retryNotification)
+	protected.GET("/notification-suppressions", h.listNotificationSuppressions)
+	protected.DELETE("/notification-suppressions/:id", h.deleteNotificationSuppression)
+	protected.GET("/clinics/:id/notifications/deliverability", h.getClinicDeliverability)
+	protected.GET("/retention/purge-report", h.getRetentionPurgeReport)
+	protected.GET("/retention/document-rules", h.listDocumentRetentionRules)
+	protected.PUT("/retention/document-rules/:kind", h.setDocumentRetentionRule)
+	protected.DELETE("/retention/document-rules/:kind", h.deleteDocumentRetentionRule)
+	protected.GET("/legal-holds", h.listLegalHolds)
+	protected.POST("/legal-holds", h.placeLegalHold)
+	protected.GET("/legal-holds/:id", h.getLegalHold)
+	protected.POST("/legal-holds/:id/release", h.releaseLegalHold)
+	protected.GET("/suppliers", h.listSuppliers)
+	protected.POST("/suppliers", h.createSupplier)
+	protected.GET("/suppliers/spend", h.getSupplierSpendReport)
+	protected.GET("/suppliers/:id", h.getSupplier)
+	protected.PATCH("/suppliers/:id", h.updateSupplier)
+	protected.DELETE("/suppliers/:id", h.deleteSupplier)
+	protected.GET("/specialties", h.listSpecialties)
+	protected.POST("/specialties", h.createSpecialty)
+	protected.GET("/specialties/:id", h.getSpecialty)
+	protected.PATCH("/specialties/:id", h.updateSpecialty)
+	protected.DELETE("/specialties/:id", h.deleteSpecialty)
+
+	scheduling := protected.Group("")
+	scheduling.Use(h.requireFeature(service.FeatureScheduling))
+	scheduling.GET("/clinics/:id/operating-hours", h.getClinicOperatingHours)
+	scheduling.PUT("/clinics/:id/operating-hours", h.replaceClinicOperatingHours)
+	scheduling.GET("/clinics/:id/holidays", h.listClinicHolidays)
+	scheduling.POST("/clinics/:id/holidays", h.createClinicHoliday)
+	scheduling.DELETE("/clinics/:id/holidays/:holiday_id", h.deleteClinicHoliday)
+	scheduling.GET("/clinics/:id/availability", h.getClinicAvailability)
+	scheduling.GET("/clinics/:id/shifts", h.getShiftBoard)
+	scheduling.POST("/clinics/:id/shifts", h.createClinicShift)
+	scheduling.PATCH("/clinics/:id/shifts/:shift_id", h.updateClinicShift)
+	scheduling.DELETE("/clinics/:id/shifts/:shift_id", h.deleteClinicShift)
+
+	billing := protected.Group("")
+	billing.Use(h.requireFeature(service.FeatureBilling))
+	billing.GET("/clinics/:id/financial-holds", h.listClinicFinancialHolds)
+	billing.POST("/clinics/:id/financial-holds", h.placeClinicFinancialHold)
+	billing.POST("/clinics/:id/financial-holds/:hold_id/lift", h.liftClinicFinancialHold)
+	billing.GET("/clinics/:id/ledger", h.getClinicLedger)
+	billing.GET("/clinics/:id/ledger/export", h.exportClinicLedger)
+	billing.POST("/clinics/:id/ledger/entries", h.createClinicLedgerEntry)
+	billing.GET("/clinics/:id/payables", h.listClinicPayables)
+	billing.POST("/clinics/:id/payables", h.createClinicPayable)
+	billing.GET("/payout-batches", h.listPayoutBatches)
+	billing.POST("/payout-batches", h.createPayoutBatch)
+	billing.GET("/payout-batches/:id", h.getPayoutBatch)
+	billing.GET("/payout-batches/:id/file", h.downloadPayoutBatchFile)
+	billing.POST("/payout-batches/:id/status", h.updatePayoutBatchStatus)
 
 	return router
 }
@@ -254,7 +577,7 @@ func (h *Handler) health(c *gin.Context) {
 
 func (h *Handler) login(c *gin.Context) {
 	var input service.LoginInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := bindJSON(c, &input); err != nil {
 		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
 		return
 	}
@@ -268,25 +591,55 @@ func (h *Handler) login(c *gin.Context) {
 	c.JSON(http.StatusOK, output)
 }
 
+// bearerToken returns the token from the Authorization header, or the problem detail to answer with.
+func bearerToken(c *gin.Context) (string, string) {
+	rawAuthorization := strings.TrimSpace(c.GetHeader("Authorization"))
+	if rawAuthorization == "" {
+		return "", "missing bearer token"
+	}
+
+	prefix := "Bearer "
+	if !strings.HasPrefix(rawAuthorization, prefix) {
+		return "", "invalid authorization header"
+	}
+	return strings.TrimSpace(strings.TrimPrefix(rawAuthorization, prefix)), ""
+}
+
 func (h *Handler) requireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		rawAuthorization := strings.TrimSpace(c.GetHeader("Authorization"))
-		if rawAuthorization == "" {
-			h.writeProblem(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", "missing bearer token")
+		token, problem := bearerToken(c)
+		if problem != "" {
+			h.writeProblem(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", problem)
 			return
 		}
 
-		prefix := "Bearer "
-		if !strings.HasPrefix(rawAuthorization, prefix) {
-			h.writeProblem(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", "invalid authorization header")
+		principal, err := h.service.AuthenticateAccessToken(token)
+		if err != nil {
+			h.writeProblem(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", "invalid token")
 			return
 		}
 
-		token := strings.TrimSpace(strings.TrimPrefix(rawAuthorization, prefix))
-		if err := h.service.ValidateAccessToken(token); err != nil {
+		if organizationID, ok := service.OrganizationFromContext(c.Request.Context()); ok && organizationID != principal.OrganizationID {
+			h.writeProblem(c, http.StatusForbidden, problemTypeForbidden, "Forbidden", "token belongs to another organization")
+			return
+		}
+
+		c.Request = c.Request.WithContext(service.WithPrincipal(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+func (h *Handler) requireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := service.PrincipalFromContext(c.Request.Context())
+		if !ok {
 			h.writeProblem(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", "invalid token")
 			return
 		}
+		if principal.Role != role {
+			h.writeProblem(c, http.StatusForbidden, problemTypeForbidden, "Forbidden", "insufficient role for this resource")
+			return
+		}
 
 		c.Next()
 	}
@@ -299,7 +652,32 @@ func (h *Handler) listClinics(c *gin.Context) {
 		return
 	}
 
-	clinics, nextCursor, err := h.service.ListClinicsWithCursor(c.Request.Context(), limit, cursor)
+	var filter service.ListClinicsFilter
+	if rawStatus := strings.TrimSpace(c.Query("onboarding_status")); rawStatus != "" {
+		filter.OnboardingStatus = &rawStatus
+	}
+	if rawStuckForDays := strings.TrimSpace(c.Query("stuck_for_days")); rawStuckForDays != "" {
+		stuckForDays, err := strconv.Atoi(rawStuckForDays)
+		if err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", fmt.Sprintf("invalid parameter %q: must be an integer", "stuck_for_days"))
+			return
+		}
+		filter.StuckForDays = &stuckForDays
+	}
+	filter.IncludeDeleted, err = parseIncludeDeleted(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.ClinicOutput, *string, error) {
+			return h.service.ListClinicsWithCursor(ctx, maxCursorLimit, cursor, filter)
+		})
+		return
+	}
+
+	clinics, nextCursor, err := h.service.ListClinicsWithCursor(c.Request.Context(), limit, cursor, filter)
 	if err != nil {
 		h.writeError(c, err)
 		return
@@ -311,7 +689,7 @@ func (h *Handler) listClinics(c *gin.Context) {
 
 func (h *Handler) createClinic(c *gin.Context) {
 	var input service.CreateClinicInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := bindJSON(c, &input); err != nil {
 		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
 		return
 	}
@@ -332,7 +710,27 @@ func (h *Handler) getClinic(c *gin.Context) {
 		return
 	}
 
-	clinic, err := h.service.GetClinic(c.Request.Context(), id)
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	asOf, err := parseAsOf(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var clinic service.ClinicDetailsOutput
+	switch {
+	case asOf != nil:
+		clinic, err = h.service.GetClinicAsOf(c.Request.Context(), id, *asOf, includeDeleted)
+	case includeDeleted:
+		clinic, err = h.service.GetClinicIncludingDeleted(c.Request.Context(), id)
+	default:
+		clinic, err = h.service.GetClinic(c.Request.Context(), id)
+	}
 	if err != nil {
 		h.writeError(c, err)
 		return
@@ -349,7 +747,7 @@ func (h *Handler) updateClinic(c *gin.Context) {
 	}
 
 	var input service.UpdateClinicInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := bindJSON(c, &input); err != nil {
 		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
 		return
 	}
@@ -370,7 +768,7 @@ func (h *Handler) deleteClinic(c *gin.Context) {
 		return
 	}
 
-	if err := h.service.DeleteClinic(c.Request.Context(), id); err != nil {
+	if err := h.service.DeleteClinic(c.Request.Context(), id, c.Query("confirmation_token")); err != nil {
 		h.writeError(c, err)
 		return
 	}
@@ -378,6 +776,22 @@ func (h *Handler) deleteClinic(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+func (h *Handler) getClinicDeletePreview(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	preview, err := h.service.GetClinicDeletePreview(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
+
 func (h *Handler) createDentist(c *gin.Context) {
 	clinicID, err := parseID(c, "id")
 	if err != nil {
@@ -386,7 +800,7 @@ func (h *Handler) createDentist(c *gin.Context) {
 	}
 
 	var input service.CreateDentistInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := bindJSON(c, &input); err != nil {
 		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
 		return
 	}
@@ -417,7 +831,24 @@ func (h *Handler) listClinicDentists(c *gin.Context) {
 		return
 	}
 
-	dentists, nextCursor, err := h.service.ListClinicDentistsWithCursor(c.Request.Context(), clinicID, limit, cursor)
+	var specialty *string
+	if rawSpecialty := strings.TrimSpace(c.Query("specialty")); rawSpecialty != "" {
+		specialty = &rawSpecialty
+	}
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.ClinicDentistOutput, *string, error) {
+			return h.service.ListClinicDentistsWithCursor(ctx, clinicID, maxCursorLimit, cursor, specialty, includeDeleted)
+		})
+		return
+	}
+
+	dentists, nextCursor, err := h.service.ListClinicDentistsWithCursor(c.Request.Context(), clinicID, limit, cursor, specialty, includeDeleted)
 	if err != nil {
 		h.writeError(c, err)
 		return
@@ -441,7 +872,7 @@ func (h *Handler) updateClinicDentistRole(c *gin.Context) {
 	}
 
 	var input service.UpdateClinicDentistRoleInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := bindJSON(c, &input); err != nil {
 		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
 		return
 	}
@@ -455,6 +886,28 @@ func (h *Handler) updateClinicDentistRole(c *gin.Context) {
 	c.JSON(http.StatusOK, dentist)
 }
 
+func (h *Handler) bulkUpdateClinicDentistRoles(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.BulkUpdateClinicDentistRolesInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	dentists, err := h.service.BulkUpdateClinicDentistRoles(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dentists)
+}
+
 func (h *Handler) unlinkDentistFromClinic(c *gin.Context) {
 	clinicID, err := parseID(c, "id")
 	if err != nil {
@@ -476,6 +929,77 @@ func (h *Handler) unlinkDentistFromClinic(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+func (h *Handler) getClinicDentistHistory(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	dentistID, err := parseID(c, "dentist_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	history, err := h.service.ListClinicDentistHistory(c.Request.Context(), clinicID, dentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+func (h *Handler) getClinicDentistTenure(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	dentistID, err := parseID(c, "dentist_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	tenure, err := h.service.ClinicDentistTenure(c.Request.Context(), clinicID, dentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, tenure)
+}
+
+func (h *Handler) getDentist(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var dentist service.DentistOutput
+	if includeDeleted {
+		dentist, err = h.service.GetDentistIncludingDeleted(c.Request.Context(), dentistID)
+	} else {
+		dentist, err = h.service.GetDentistProfile(c.Request.Context(), dentistID)
+	}
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dentist)
+}
+
 func (h *Handler) updateDentist(c *gin.Context) {
 	dentistID, err := parseID(c, "id")
 	if err != nil {
@@ -484,7 +1008,7 @@ func (h *Handler) updateDentist(c *gin.Context) {
 	}
 
 	var input service.UpdateDentistInput
-	if err := c.ShouldBindJSON(&input); err != nil {
+	if err := bindJSON(c, &input); err != nil {
 		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
 		return
 	}
@@ -513,16 +1037,79 @@ func (h *Handler) deleteDentist(c *gin.Context) {
 	c.Status(http.StatusNoContent)
 }
 
+func (h *Handler) reassignDentistPerson(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.ReassignDentistPersonInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	output, err := h.service.ReassignDentistPerson(c.Request.Context(), dentistID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, output)
+}
+
+func (h *Handler) getDentistEmploymentHistory(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	history, err := h.service.ListDentistEmploymentHistory(c.Request.Context(), dentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
 func (h *Handler) writeError(c *gin.Context, err error) {
+	var duplicate *service.DuplicateClinicError
+	var quota *service.QuotaExceededError
+	var feature *service.FeatureNotInPlanError
 	switch {
 	case errors.Is(err, service.ErrValidation):
 		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", err.Error())
 	case errors.Is(err, service.ErrNotFound):
 		h.writeProblem(c, http.StatusNotFound, problemTypeNotFound, "Not Found", err.Error())
+	case errors.As(err, &duplicate):
+		writeDuplicateClinicProblem(c, duplicate)
+	case errors.As(err, &quota):
+		writeQuotaExceededProblem(c, quota)
+	case errors.As(err, &feature):
+		writeFeatureNotInPlanProblem(c, feature)
+	case errors.Is(err, service.ErrDataResidency):
+		h.writeProblem(c, http.StatusConflict, problemTypeResidency, "Data Residency Violation", err.Error())
 	case errors.Is(err, service.ErrConflict):
 		h.writeProblem(c, http.StatusConflict, problemTypeConflict, "Conflict", err.Error())
 	case errors.Is(err, service.ErrUnauthorized):
 		h.writeProblem(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", err.Error())
+	case errors.Is(err, service.ErrForbidden):
+		h.writeProblem(c, http.StatusForbidden, problemTypeForbidden, "Forbidden", err.Error())
+	case errors.Is(err, service.ErrRoleInvariant):
+		h.writeProblem(c, http.StatusConflict, problemTypeRoleInvariant, "Clinic Role Invariant Violation", err.Error())
+	case errors.Is(err, service.ErrFinancialHold):
+		h.writeProblem(c, http.StatusConflict, problemTypeFinancialHold, "Financial Hold", err.Error())
+	case errors.Is(err, service.ErrConfirmationRequired):
+		h.writeProblem(c, http.StatusPreconditionRequired, problemTypeConfirmation, "Confirmation Required", err.Error())
+	case errors.Is(err, service.ErrBlocked):
+		h.writeProblem(c, http.StatusUnprocessableEntity, problemTypeBlockedTaxID, "Blocked Tax ID", err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		h.writeProblem(c, http.StatusGatewayTimeout, problemTypeTimeout, "Gateway Timeout", "request took longer than its query timeout")
+	case errors.Is(err, context.Canceled) && c.Request.Context().Err() != nil:
+		c.AbortWithStatus(statusClientClosedRequest)
 	default:
 		_ = c.Error(err)
 		span := trace.SpanFromContext(c.Request.Context())
@@ -559,6 +1146,12 @@ func (h *Handler) writeProblem(c *gin.Context, status int, problemType string, t
 }
 
 func writeProblemResponse(c *gin.Context, status int, problemType string, title string, detail string) {
+	problem := newProblemDetails(c, status, problemType, title, detail)
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(status, problem)
+}
+
+func newProblemDetails(c *gin.Context, status int, problemType string, title string, detail string) ProblemDetails {
 	if problemType == "" {
 		problemType = "about:blank"
 	}
@@ -571,15 +1164,14 @@ func writeProblemResponse(c *gin.Context, status int, problemType string, title
 		c.Header(headerRequestID, requestID)
 	}
 
-	c.Header("Content-Type", problemContentType)
-	c.AbortWithStatusJSON(status, ProblemDetails{
+	return ProblemDetails{
 		Type:      problemType,
 		Title:     title,
 		Status:    status,
 		Detail:    detail,
 		Instance:  c.Request.URL.Path,
 		RequestID: requestID,
-	})
+	}
 }
 
 func classifyErrorType(err error) string {
@@ -597,6 +1189,30 @@ func classifyErrorType(err error) string {
 	return fmt.Sprintf("%T", root)
 }
 
+func bindJSON(c *gin.Context, obj any) error {
+	if c.Request == nil || c.Request.Body == nil {
+		return errors.New("missing request body")
+	}
+	if err := json.NewDecoder(c.Request.Body).Decode(obj); err != nil {
+		return err
+	}
+	sanitize.Struct(obj)
+	return binding.Validator.ValidateStruct(obj)
+}
+
+func bindStrictJSON(c *gin.Context, obj any) error {
+	if c.Request == nil || c.Request.Body == nil {
+		return errors.New("missing request body")
+	}
+	decoder := json.NewDecoder(c.Request.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(obj); err != nil {
+		return err
+	}
+	sanitize.Struct(obj)
+	return binding.Validator.ValidateStruct(obj)
+}
+
 func parseID(c *gin.Context, param string) (string, error) {
 	id := strings.TrimSpace(c.Param(param))
 	if id == "" {
@@ -609,6 +1225,38 @@ func parseID(c *gin.Context, param string) (string, error) {
 	return parsed.String(), nil
 }
 
+func parseIncludeDeleted(c *gin.Context) (bool, error) {
+	return parseBoolQuery(c, "include_deleted")
+}
+
+func parseBoolQuery(c *gin.Context, name string) (bool, error) {
+	rawValue := strings.TrimSpace(c.Query(name))
+	if rawValue == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(rawValue)
+	if err != nil {
+		return false, fmt.Errorf("invalid parameter %q: must be a boolean", name)
+	}
+	return value, nil
+}
+
+func parseAsOf(c *gin.Context) (*time.Time, error) {
+	return parseTimeQuery(c, "as_of")
+}
+
+func parseTimeQuery(c *gin.Context, name string) (*time.Time, error) {
+	rawValue := strings.TrimSpace(c.Query(name))
+	if rawValue == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, rawValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameter %q: must be an RFC 3339 timestamp", name)
+	}
+	return &value, nil
+}
+
 func parseCursorPagination(c *gin.Context) (int, *string, error) {
 	limit := defaultCursorLimit
 	if rawLimit := strings.TrimSpace(c.Query("limit")); rawLimit != "" {
@@ -627,13 +1275,11 @@ func parseCursorPagination(c *gin.Context) (int, *string, error) {
 		return limit, nil, nil
 	}
 
-	parsedCursor, err := uuid.Parse(rawCursor)
-	if err != nil || parsedCursor.Version() != 7 {
-		return 0, nil, fmt.Errorf("invalid parameter %q: must be a UUIDv7", "cursor")
+	// Cursors are opaque: the service encodes and decodes them, so only their shape is checked here.
+	if len(rawCursor) > maxCursorLength || strings.TrimLeft(rawCursor, cursorAlphabet) != "" {
+		return 0, nil, fmt.Errorf("invalid parameter %q: must be a cursor returned by a previous page", "cursor")
 	}
-
-	cursor := parsedCursor.String()
-	return limit, &cursor, nil
+	return limit, &rawCursor, nil
 }
 
 func setCursorHeaders(c *gin.Context, limit int, nextCursor *string) {
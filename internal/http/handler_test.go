@@ -1,11 +1,18 @@
 package http
 
 import (
+	"context"
+	"fmt"
+	"log/slog"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+
+	"capim-test/internal/service"
 )
 
 func TestParseIDRejectsNonUUIDV7(t *testing.T) {
@@ -55,7 +62,7 @@ func TestParseCursorPaginationRejectsInvalidCursor(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request = httptest.NewRequest("GET", "/api/v1/clinics?cursor=invalid", nil)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics?cursor=not%20a%20cursor", nil)
 
 	_, _, err := parseCursorPagination(c)
 	if err == nil {
@@ -63,6 +70,54 @@ func TestParseCursorPaginationRejectsInvalidCursor(t *testing.T) {
 	}
 }
 
+func TestParseCursorPaginationPassesOpaqueCursorThrough(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics?cursor=MjAyNi0xMC0xNFQxMzo1MTo0Ny4xMjM0NTZaLDZmMWM2YzllLTJiN2QtNGMxYS05ZDNlLTVmMmE4YjdjOWQwMQ", nil)
+
+	_, cursor, err := parseCursorPagination(c)
+	if err != nil || cursor == nil || *cursor != "MjAyNi0xMC0xNFQxMzo1MTo0Ny4xMjM0NTZaLDZmMWM2YzllLTJiN2QtNGMxYS05ZDNlLTVmMmE4YjdjOWQwMQ" {
+		t.Fatalf("expected the cursor to be passed through unchanged, got %v, %v", cursor, err)
+	}
+}
+
+func TestParseIncludeDeleted(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics?include_deleted=true", nil)
+
+	includeDeleted, err := parseIncludeDeleted(c)
+	if err != nil || !includeDeleted {
+		t.Fatalf("expected include_deleted=true to parse, got %v, %v", includeDeleted, err)
+	}
+
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics?include_deleted=maybe", nil)
+	if _, err := parseIncludeDeleted(c); err == nil {
+		t.Fatalf("expected parseIncludeDeleted error")
+	}
+}
+
+func TestParseAsOf(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics/019f3329-a5a8-72ec-a95b-6e554247f442?as_of=2024-01-31T00:00:00Z", nil)
+
+	asOf, err := parseAsOf(c)
+	if err != nil || asOf == nil || !asOf.Equal(time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected as_of to parse, got %v, %v", asOf, err)
+	}
+
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics/019f3329-a5a8-72ec-a95b-6e554247f442?as_of=2024-01-31", nil)
+	if _, err := parseAsOf(c); err == nil {
+		t.Fatalf("expected parseAsOf error")
+	}
+}
+
 func TestSetCursorHeadersSetsNextHeadersWhenPresent(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
@@ -86,3 +141,395 @@ func TestSetCursorHeadersSetsNextHeadersWhenPresent(t *testing.T) {
 		t.Fatalf("expected Link header")
 	}
 }
+
+func TestBindJSONSanitizesBeforeValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"email":" admin@example.com ","password":" secret  123 "}`
+	c.Request = httptest.NewRequest("POST", "/api/v1/auth/login", strings.NewReader(body))
+
+	var input service.LoginInput
+	if err := bindJSON(c, &input); err != nil {
+		t.Fatalf("bindJSON: %v", err)
+	}
+	if input.Email != "admin@example.com" {
+		t.Fatalf("expected sanitized email, got %q", input.Email)
+	}
+	if input.Password != " secret  123 " {
+		t.Fatalf("expected password to be left untouched, got %q", input.Password)
+	}
+}
+
+func TestBindStrictJSONRejectsRestrictedProfileFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"phone":"11987654321","tax_id_number":"52998224725"}`
+	c.Request = httptest.NewRequest("PATCH", "/api/v1/me/dentist-profile", strings.NewReader(body))
+
+	var input service.UpdateDentistProfileInput
+	if err := bindStrictJSON(c, &input); err == nil {
+		t.Fatalf("expected unknown field tax_id_number to be rejected")
+	}
+}
+
+func TestRequireRoleRejectsOtherRoles(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics", nil)
+	c.Request = c.Request.WithContext(service.WithPrincipal(c.Request.Context(), service.Principal{Role: service.UserRoleDentist}))
+
+	h := &Handler{}
+	h.requireRole(service.UserRoleAdmin)(c)
+	if w.Code != 403 {
+		t.Fatalf("expected status 403, got %d", w.Code)
+	}
+	if !c.IsAborted() {
+		t.Fatalf("expected request to be aborted")
+	}
+}
+
+func TestWriteErrorIncludesDuplicateCandidates(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/clinics", nil)
+
+	h := &Handler{}
+	h.writeError(c, &service.DuplicateClinicError{Candidates: []service.ClinicDuplicateCandidate{{
+		ClinicID:   "019f3329-a5a8-72ec-a95b-6e554247f442",
+		LegalName:  "Clinica Sorriso Ltda",
+		Reason:     service.DuplicateReasonSimilarLegalName,
+		Similarity: 0.82,
+	}}})
+
+	if w.Code != 409 {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+	body := w.Body.String()
+	if !strings.Contains(body, problemTypeDuplicate) || !strings.Contains(body, `"candidates":[{"clinic_id":"019f3329-a5a8-72ec-a95b-6e554247f442"`) {
+		t.Fatalf("expected duplicate problem with candidates, got %s", body)
+	}
+}
+
+func TestWriteErrorMapsFinancialHold(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/clinics/019f3329-a5a8-72ec-a95b-6e554247f442/bank-accounts", nil)
+
+	h := &Handler{}
+	h.writeError(c, fmt.Errorf("%w: clinic has an active financial hold", service.ErrFinancialHold))
+
+	if w.Code != 409 {
+		t.Fatalf("expected 409, got %d", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), problemTypeFinancialHold) {
+		t.Fatalf("expected financial hold problem, got %s", w.Body.String())
+	}
+}
+
+func TestRateLimitMiddlewareRejectsRequestsAboveLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	now := time.Date(2026, 3, 10, 12, 0, 15, 0, time.UTC)
+	limiter := newFixedWindowLimiter(2, time.Minute)
+	limiter.now = func() time.Time { return now }
+
+	router := gin.New()
+	router.Use(rateLimitMiddleware(limiter))
+	router.GET("/public/v1/clinics", func(c *gin.Context) { c.Status(200) })
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/public/v1/clinics", nil)
+		req.RemoteAddr = remoteAddr
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := request("203.0.113.10:5000"); w.Code != 200 {
+			t.Fatalf("request %d: expected 200, got %d", i+1, w.Code)
+		}
+	}
+	limited := request("203.0.113.10:5000")
+	if limited.Code != 429 {
+		t.Fatalf("expected 429, got %d", limited.Code)
+	}
+	if got := limited.Header().Get(headerRetryAfter); got != "45" {
+		t.Fatalf("expected Retry-After 45, got %q", got)
+	}
+	if w := request("203.0.113.20:5000"); w.Code != 200 {
+		t.Fatalf("expected other clients to keep their own quota, got %d", w.Code)
+	}
+
+	now = now.Add(time.Minute)
+	if w := request("203.0.113.10:5000"); w.Code != 200 {
+		t.Fatalf("expected quota to reset in the next window, got %d", w.Code)
+	}
+}
+
+func TestLoadSheddingRejectsRequestsAboveConcurrencyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := newConcurrencyLimiter(LoadSheddingConfig{MaxConcurrency: 1, MinConcurrency: 1, TargetP99: time.Second})
+
+	router := gin.New()
+	router.Use(loadSheddingMiddleware(limiter, slog.Default()))
+	entered := make(chan struct{})
+	release := make(chan struct{})
+	router.GET("/api/v1/clinics", func(c *gin.Context) {
+		close(entered)
+		<-release
+		c.Status(200)
+	})
+	router.GET("/api/v1/health", func(c *gin.Context) { c.Status(200) })
+
+	done := make(chan int)
+	go func() {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/v1/clinics", nil))
+		done <- w.Code
+	}()
+	<-entered
+
+	shed := httptest.NewRecorder()
+	router.ServeHTTP(shed, httptest.NewRequest("GET", "/api/v1/clinics", nil))
+	if shed.Code != 503 || shed.Header().Get(headerRetryAfter) != "1" || !strings.Contains(shed.Body.String(), problemTypeOverloaded) {
+		t.Fatalf("expected 503 with Retry-After, got %d %q %s", shed.Code, shed.Header().Get(headerRetryAfter), shed.Body.String())
+	}
+	health := httptest.NewRecorder()
+	router.ServeHTTP(health, httptest.NewRequest("GET", "/api/v1/health", nil))
+	if health.Code != 200 {
+		t.Fatalf("expected the health check to bypass shedding, got %d", health.Code)
+	}
+
+	close(release)
+	if code := <-done; code != 200 {
+		t.Fatalf("expected the admitted request to finish, got %d", code)
+	}
+}
+
+func TestQueryTimeoutMiddlewareAppliesRouteDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(queryTimeoutMiddleware(QueryTimeoutConfig{
+		Default: time.Millisecond,
+		Routes:  map[string]time.Duration{"GET /api/v1/audit-logs/export": time.Hour},
+	}))
+	h := &Handler{}
+	waitForDeadline := func(c *gin.Context) {
+		deadline, ok := c.Request.Context().Deadline()
+		if !ok {
+			t.Fatalf("expected %s to carry a deadline", c.FullPath())
+		}
+		if time.Until(deadline) > time.Minute {
+			c.Status(200)
+			return
+		}
+		<-c.Request.Context().Done()
+		h.writeError(c, fmt.Errorf("list clinics: %w", c.Request.Context().Err()))
+	}
+	router.GET("/api/v1/clinics", waitForDeadline)
+	router.GET("/api/v1/audit-logs/export", waitForDeadline)
+
+	timedOut := httptest.NewRecorder()
+	router.ServeHTTP(timedOut, httptest.NewRequest("GET", "/api/v1/clinics", nil))
+	if timedOut.Code != 504 || !strings.Contains(timedOut.Body.String(), problemTypeTimeout) {
+		t.Fatalf("expected a timeout problem, got %d %s", timedOut.Code, timedOut.Body.String())
+	}
+	overridden := httptest.NewRecorder()
+	router.ServeHTTP(overridden, httptest.NewRequest("GET", "/api/v1/audit-logs/export", nil))
+	if overridden.Code != 200 {
+		t.Fatalf("expected the route override to replace the default, got %d", overridden.Code)
+	}
+}
+
+func TestConcurrencyLimiterAdaptsToP99(t *testing.T) {
+	limiter := newConcurrencyLimiter(LoadSheddingConfig{MaxConcurrency: 50, MinConcurrency: 10, TargetP99: 100 * time.Millisecond})
+	window := func(latency time.Duration) {
+		for range loadSheddingSampleWindow {
+			limiter.acquire()
+			limiter.release(latency, true)
+		}
+	}
+
+	window(time.Second)
+	if limit, _ := limiter.snapshot(); limit != 45 {
+		t.Fatalf("expected the limit to shrink on a slow window, got %d", limit)
+	}
+	for range 30 {
+		window(time.Second)
+	}
+	if limit, _ := limiter.snapshot(); limit != 10 {
+		t.Fatalf("expected the limit to stop at the floor, got %d", limit)
+	}
+
+	window(time.Millisecond)
+	if limit, _ := limiter.snapshot(); limit != 10 {
+		t.Fatalf("expected no growth while the limit is not reached, got %d", limit)
+	}
+	for range 10 {
+		limiter.acquire()
+	}
+	limiter.release(time.Millisecond, true)
+	window(time.Millisecond)
+	if limit, _ := limiter.snapshot(); limit != 11 {
+		t.Fatalf("expected the limit to grow once reached with a healthy p99, got %d", limit)
+	}
+}
+
+func TestRequirePlatformKeyRejectsWrongKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(requirePlatformKey("platform-secret"))
+	router.GET("/api/v1/platform/organizations", func(c *gin.Context) { c.Status(200) })
+
+	request := func(key string) int {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/api/v1/platform/organizations", nil)
+		if key != "" {
+			req.Header.Set(headerPlatformKey, key)
+		}
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := request(""); code != 401 {
+		t.Fatalf("expected 401 without key, got %d", code)
+	}
+	if code := request("other-secret"); code != 401 {
+		t.Fatalf("expected 401 with wrong key, got %d", code)
+	}
+	if code := request("platform-secret"); code != 200 {
+		t.Fatalf("expected 200 with platform key, got %d", code)
+	}
+}
+
+func TestTenantFromHost(t *testing.T) {
+	cases := []struct {
+		host       string
+		baseDomain string
+		want       string
+	}{
+		{host: "acme.api.capim.test", baseDomain: "api.capim.test", want: "acme"},
+		{host: "Acme.API.capim.test:8080", baseDomain: "api.capim.test", want: "acme"},
+		{host: "api.capim.test", baseDomain: "api.capim.test", want: ""},
+		{host: "a.b.api.capim.test", baseDomain: "api.capim.test", want: ""},
+		{host: "acme.other.test", baseDomain: "api.capim.test", want: ""},
+		{host: "acme.api.capim.test", baseDomain: "", want: ""},
+	}
+	for _, tc := range cases {
+		if got := tenantFromHost(tc.host, tc.baseDomain); got != tc.want {
+			t.Fatalf("tenantFromHost(%q, %q) = %q, want %q", tc.host, tc.baseDomain, got, tc.want)
+		}
+	}
+}
+
+func TestWriteErrorMapsQuotaExceeded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/v1/clinics", nil)
+	h.writeError(c, &service.QuotaExceededError{Quota: service.QuotaClinics, Limit: 10, Usage: 10})
+	if w.Code != 403 {
+		t.Fatalf("expected 403 for resource quota, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, problemTypeQuota) || !strings.Contains(body, `"quota":"clinics","limit":10,"usage":10`) {
+		t.Fatalf("expected quota problem with usage, got %s", body)
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics", nil)
+	h.writeError(c, &service.QuotaExceededError{Quota: service.QuotaRequestsPerMinute, Limit: 100, Usage: 100, RetryAfter: 1500 * time.Millisecond})
+	if w.Code != 429 {
+		t.Fatalf("expected 429 for request quota, got %d", w.Code)
+	}
+	if got := w.Header().Get(headerRetryAfter); got != "2" {
+		t.Fatalf("expected Retry-After 2, got %q", got)
+	}
+}
+
+func TestWriteErrorMapsFeatureNotInPlan(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &Handler{}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/payout-batches", nil)
+	h.writeError(c, &service.FeatureNotInPlanError{Feature: service.FeatureBilling, Plan: service.PlanBasic})
+	if w.Code != 403 {
+		t.Fatalf("expected 403, got %d", w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, problemTypeFeature) || !strings.Contains(body, `"feature":"billing","plan":"BASIC"`) {
+		t.Fatalf("expected feature-not-in-plan problem, got %s", body)
+	}
+}
+
+func TestStreamPagesWritesEveryPageAsNDJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics", nil)
+	c.Request.Header.Set("Accept", "application/json;q=0.5, application/x-ndjson")
+	if !wantsNDJSON(c) {
+		t.Fatal("expected ndjson to be accepted")
+	}
+
+	pages := map[string][]string{"": {"a", "b"}, "b": {"c"}}
+	var requested []string
+	streamPages(&Handler{}, c, nil, func(_ context.Context, cursor *string) ([]string, *string, error) {
+		key := ""
+		if cursor != nil {
+			key = *cursor
+		}
+		requested = append(requested, key)
+		if key == "" {
+			next := "b"
+			return pages[key], &next, nil
+		}
+		return pages[key], nil, nil
+	})
+
+	if got := w.Header().Get("Content-Type"); got != ndjsonContentType {
+		t.Fatalf("expected ndjson content type, got %q", got)
+	}
+	if body := w.Body.String(); body != "\"a\"\n\"b\"\n\"c\"\n" {
+		t.Fatalf("expected one line per item across pages, got %q", body)
+	}
+	if len(requested) != 2 || requested[1] != "b" {
+		t.Fatalf("expected the second page to start at the returned cursor, got %v", requested)
+	}
+}
+
+func TestStreamPagesReportsErrorBeforeFirstRow(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics", nil)
+
+	streamPages(&Handler{}, c, nil, func(_ context.Context, cursor *string) ([]string, *string, error) {
+		return nil, nil, &service.FeatureNotInPlanError{Feature: service.FeatureBilling, Plan: service.PlanBasic}
+	})
+
+	if w.Code != 403 || w.Header().Get("Content-Type") == ndjsonContentType {
+		t.Fatalf("expected a problem response, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func BenchmarkParseCursorPagination(b *testing.B) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/api/v1/clinics?limit=50&cursor=019f3329-a5a8-72ec-a95b-6e554247f442", nil)
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, _, err := parseCursorPagination(c); err != nil {
+			b.Fatalf("parse cursor pagination: %v", err)
+		}
+	}
+}
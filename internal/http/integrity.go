@@ -0,0 +1,23 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) runIntegrityChecks(c *gin.Context) {
+	repair, err := parseBoolQuery(c, "repair")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	report, err := h.service.RunIntegrityChecks(c.Request.Context(), repair)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,178 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) createInventoryItem(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateInventoryItemInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	item, err := h.service.CreateInventoryItem(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, item)
+}
+
+func (h *Handler) listInventoryItems(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	items, nextCursor, err := h.service.ListInventoryItems(c.Request.Context(), clinicID, limit, cursor)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, items)
+}
+
+func (h *Handler) getInventoryItem(c *gin.Context) {
+	clinicID, itemID, ok := h.parseInventoryItemParams(c)
+	if !ok {
+		return
+	}
+
+	item, err := h.service.GetInventoryItem(c.Request.Context(), clinicID, itemID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *Handler) updateInventoryItem(c *gin.Context) {
+	clinicID, itemID, ok := h.parseInventoryItemParams(c)
+	if !ok {
+		return
+	}
+
+	var input service.UpdateInventoryItemInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	item, err := h.service.UpdateInventoryItem(c.Request.Context(), clinicID, itemID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+func (h *Handler) deleteInventoryItem(c *gin.Context) {
+	clinicID, itemID, ok := h.parseInventoryItemParams(c)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteInventoryItem(c.Request.Context(), clinicID, itemID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) createInventoryMovement(c *gin.Context) {
+	clinicID, itemID, ok := h.parseInventoryItemParams(c)
+	if !ok {
+		return
+	}
+
+	var input service.CreateInventoryMovementInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	movement, err := h.service.RecordInventoryMovement(c.Request.Context(), clinicID, itemID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, movement)
+}
+
+func (h *Handler) listInventoryMovements(c *gin.Context) {
+	clinicID, itemID, ok := h.parseInventoryItemParams(c)
+	if !ok {
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	movements, nextCursor, err := h.service.ListInventoryMovements(c.Request.Context(), clinicID, itemID, limit, cursor, optionalQuery(c, "kind"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, movements)
+}
+
+func (h *Handler) getLowStockReport(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	report, err := h.service.GetLowStockReport(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) parseInventoryItemParams(c *gin.Context) (string, string, bool) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	itemID, err := parseID(c, "item_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	return clinicID, itemID, true
+}
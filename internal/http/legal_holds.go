@@ -0,0 +1,86 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) placeLegalHold(c *gin.Context) {
+	var input service.PlaceLegalHoldInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	hold, err := h.service.PlaceLegalHold(c.Request.Context(), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, hold)
+}
+
+func (h *Handler) listLegalHolds(c *gin.Context) {
+	var active *bool
+	if rawActive := strings.TrimSpace(c.Query("active")); rawActive != "" {
+		parsedActive, err := strconv.ParseBool(rawActive)
+		if err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", fmt.Sprintf("invalid parameter %q: must be a boolean", "active"))
+			return
+		}
+		active = &parsedActive
+	}
+
+	holds, err := h.service.ListLegalHolds(c.Request.Context(), optionalQuery(c, "resource_type"), optionalQuery(c, "resource_id"), active)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, holds)
+}
+
+func (h *Handler) getLegalHold(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	hold, err := h.service.GetLegalHold(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
+
+func (h *Handler) releaseLegalHold(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.ReleaseLegalHoldInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	hold, err := h.service.ReleaseLegalHold(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, hold)
+}
@@ -0,0 +1,169 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	loadSheddingSampleWindow = 100
+	loadSheddingRetryAfter   = time.Second
+	loadSheddingDecrease     = 0.9
+	// loadSheddingUnmatchedRoute labels requests that match no route, so raw paths never become metric attributes.
+	loadSheddingUnmatchedRoute = "unmatched"
+)
+
+type LoadSheddingConfig struct {
+	// MaxConcurrency is the most requests served at once and where the limit starts; zero turns shedding off.
+	MaxConcurrency int
+	// MinConcurrency is the floor the limit never shrinks below, however slow responses get.
+	MinConcurrency int
+	// TargetP99 is the latency above which the limit shrinks.
+	TargetP99 time.Duration
+}
+
+// concurrencyLimiter caps requests in flight with an AIMD limit: every sample window it shrinks the limit by a tenth when p99 is
+// above target and grows it by one when p99 is fine and the limit was actually reached. Slow responses are the first sign of a
+// saturated database pool, so the limit drops before requests start queueing for connections.
+type concurrencyLimiter struct {
+	mu        sync.Mutex
+	limit     float64
+	min       float64
+	max       float64
+	target    time.Duration
+	inFlight  int
+	saturated bool
+	samples   []time.Duration
+	now       func() time.Time
+}
+
+func newConcurrencyLimiter(config LoadSheddingConfig) *concurrencyLimiter {
+	minimum := max(1, min(config.MinConcurrency, config.MaxConcurrency))
+	return &concurrencyLimiter{
+		limit:   float64(config.MaxConcurrency),
+		min:     float64(minimum),
+		max:     float64(config.MaxConcurrency),
+		target:  config.TargetP99,
+		samples: make([]time.Duration, 0, loadSheddingSampleWindow),
+		now:     time.Now,
+	}
+}
+
+func (l *concurrencyLimiter) acquire() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.inFlight >= int(l.limit) {
+		l.saturated = true
+		return false
+	}
+	l.inFlight++
+	if l.inFlight >= int(l.limit) {
+		l.saturated = true
+	}
+	return true
+}
+
+func (l *concurrencyLimiter) release(latency time.Duration, sample bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	if !sample {
+		return
+	}
+	l.samples = append(l.samples, latency)
+	if len(l.samples) < loadSheddingSampleWindow {
+		return
+	}
+	slices.Sort(l.samples)
+	p99 := l.samples[int(math.Ceil(float64(len(l.samples))*0.99))-1]
+	switch {
+	case p99 > l.target:
+		l.limit = max(l.min, l.limit*loadSheddingDecrease)
+	case l.saturated:
+		l.limit = min(l.max, l.limit+1)
+	}
+	l.samples = l.samples[:0]
+	l.saturated = false
+}
+
+func (l *concurrencyLimiter) snapshot() (int, int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return int(l.limit), l.inFlight
+}
+
+func loadSheddingMiddleware(limiter *concurrencyLimiter, logger *slog.Logger) gin.HandlerFunc {
+	meter := otel.Meter("capim-test/http")
+	shedCounter, err := meter.Int64Counter(
+		"capim.http.server.shed.count",
+		metric.WithDescription("Total de requests recusadas por excesso de carga"),
+	)
+	if err != nil {
+		logger.Error("create shed request counter", "error", err)
+	}
+	limitGauge, err := meter.Int64ObservableGauge(
+		"capim.http.server.concurrency.limit",
+		metric.WithDescription("Limite atual de requests simultaneas"),
+	)
+	if err != nil {
+		logger.Error("create concurrency limit gauge", "error", err)
+	}
+	inFlightGauge, err := meter.Int64ObservableGauge(
+		"capim.http.server.concurrency.in_flight",
+		metric.WithDescription("Requests em andamento"),
+	)
+	if err != nil {
+		logger.Error("create in-flight gauge", "error", err)
+	}
+	if limitGauge != nil && inFlightGauge != nil {
+		if _, err := meter.RegisterCallback(func(ctx context.Context, observer metric.Observer) error {
+			limit, inFlight := limiter.snapshot()
+			observer.ObserveInt64(limitGauge, int64(limit))
+			observer.ObserveInt64(inFlightGauge, int64(inFlight))
+			return nil
+		}, limitGauge, inFlightGauge); err != nil {
+			logger.Error("register concurrency gauges", "error", err)
+		}
+	}
+
+	return func(c *gin.Context) {
+		// The health check keeps answering so an overloaded instance is not mistaken for a dead one.
+		if c.Request.URL.Path == "/api/v1/health" {
+			c.Next()
+			return
+		}
+		if !limiter.acquire() {
+			if shedCounter != nil {
+				route := c.FullPath()
+				if route == "" {
+					route = loadSheddingUnmatchedRoute
+				}
+				shedCounter.Add(c.Request.Context(), 1, metric.WithAttributes(
+					attribute.String("http.request.method", c.Request.Method),
+					attribute.String("http.route", route),
+				))
+			}
+			c.Header(headerRetryAfter, strconv.Itoa(int(loadSheddingRetryAfter.Seconds())))
+			writeProblemResponse(c, http.StatusServiceUnavailable, problemTypeOverloaded, "Service Unavailable", "server is overloaded; retry later")
+			return
+		}
+		start := limiter.now()
+		defer func() {
+			// A streamed export runs for as long as it has rows, which says nothing about how loaded the server is.
+			streamed := c.Writer.Header().Get("Content-Type") == ndjsonContentType
+			limiter.release(limiter.now().Sub(start), !streamed)
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,95 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) getOwnDentistProfile(c *gin.Context) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	dentist, err := h.service.GetDentistProfile(c.Request.Context(), principal.DentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dentist)
+}
+
+func (h *Handler) updateOwnDentistProfile(c *gin.Context) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	var input service.UpdateDentistProfileInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	dentist, err := h.service.UpdateOwnDentistProfile(c.Request.Context(), principal.DentistID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dentist)
+}
+
+func (h *Handler) putOwnDentistPhoto(c *gin.Context) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	h.uploadDentistPhoto(c, principal.DentistID)
+}
+
+func (h *Handler) getOwnDentistPhotoScan(c *gin.Context) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	h.writeDentistPhotoScan(c, principal.DentistID)
+}
+
+func (h *Handler) createDentistUser(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateDentistUserInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	user, err := h.service.CreateDentistUser(c.Request.Context(), dentistID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, user)
+}
+
+func (h *Handler) dentistPrincipal(c *gin.Context) (service.Principal, bool) {
+	principal, ok := service.PrincipalFromContext(c.Request.Context())
+	if !ok || principal.DentistID == "" {
+		h.writeProblem(c, http.StatusForbidden, problemTypeForbidden, "Forbidden", "token is not linked to a dentist")
+		return service.Principal{}, false
+	}
+	return principal, true
+}
@@ -0,0 +1,141 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) listNotifications(c *gin.Context) {
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.NotificationOutput, *string, error) {
+			return h.service.ListNotifications(ctx, maxCursorLimit, cursor, optionalQuery(c, "status"))
+		})
+		return
+	}
+
+	notifications, nextCursor, err := h.service.ListNotifications(c.Request.Context(), limit, cursor, optionalQuery(c, "status"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, notifications)
+}
+
+func (h *Handler) getNotification(c *gin.Context) {
+	notificationID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	notification, err := h.service.GetNotification(c.Request.Context(), notificationID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+func (h *Handler) retryNotification(c *gin.Context) {
+	notificationID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	notification, err := h.service.RetryNotification(c.Request.Context(), notificationID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, notification)
+}
+
+func (h *Handler) notificationDeliveryCallback(c *gin.Context) {
+	body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxVerificationCallbackBytes))
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", "invalid request body")
+		return
+	}
+	if err := h.service.VerifyNotificationCallbackSignature(body, c.GetHeader(headerCallbackSignature)); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	var input service.NotificationDeliveryCallbackInput
+	if err := json.Unmarshal(body, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+	if err := h.service.RecordNotificationDelivery(c.Request.Context(), input); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) listNotificationSuppressions(c *gin.Context) {
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	suppressions, nextCursor, err := h.service.ListNotificationSuppressions(c.Request.Context(), limit, cursor, optionalQuery(c, "channel"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, suppressions)
+}
+
+func (h *Handler) deleteNotificationSuppression(c *gin.Context) {
+	suppressionID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if err := h.service.DeleteNotificationSuppression(c.Request.Context(), suppressionID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) getClinicDeliverability(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	report, err := h.service.GetClinicDeliverability(c.Request.Context(), clinicID, optionalQuery(c, "from"), optionalQuery(c, "to"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,293 @@
+package http
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+const headerPlatformKey = "X-Platform-Key"
+
+func requirePlatformKey(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := strings.TrimSpace(c.GetHeader(headerPlatformKey))
+		if provided == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(key)) != 1 {
+			writeProblemResponse(c, http.StatusUnauthorized, problemTypeUnauthorized, "Unauthorized", "invalid platform key")
+			return
+		}
+		c.Next()
+	}
+}
+
+func (h *Handler) createOrganization(c *gin.Context) {
+	var input service.CreateOrganizationInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	organization, err := h.service.CreateOrganization(c.Request.Context(), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, organization)
+}
+
+func (h *Handler) listOrganizations(c *gin.Context) {
+	organizations, err := h.service.ListOrganizations(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organizations)
+}
+
+func (h *Handler) updateOrganizationQuotas(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateOrganizationQuotasInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	organization, err := h.service.UpdateOrganizationQuotas(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+func (h *Handler) shredOrganizationDataKey(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.ShredOrganizationDataKeyInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	if err := h.service.ShredOrganizationDataKey(c.Request.Context(), id, input); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) updateOrganizationStatus(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateOrganizationStatusInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	organization, err := h.service.UpdateOrganizationStatus(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+func (h *Handler) updateOrganizationPlan(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateOrganizationPlanInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	organization, err := h.service.UpdateOrganizationPlan(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+func (h *Handler) updateOrganizationDataRegion(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateOrganizationDataRegionInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	organization, err := h.service.UpdateOrganizationDataRegion(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+func (h *Handler) offboardOrganization(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.OffboardOrganizationInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	organization, err := h.service.OffboardOrganization(c.Request.Context(), id, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, organization)
+}
+
+func (h *Handler) downloadOrganizationExport(c *gin.Context) {
+	id, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	export, err := h.service.GetOrganizationExport(c.Request.Context(), id)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "organization-"+id+".zip"))
+	c.Data(http.StatusOK, export.ContentType, export.Data)
+}
+
+// requireOrganizationAccess lets reads through for suspended organizations and expired trials so customers can still look up their data.
+func (h *Handler) requireOrganizationAccess() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		write := c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead && c.Request.Method != http.MethodOptions
+		if err := h.service.CheckOrganizationAccess(c.Request.Context(), write); err != nil {
+			h.writeError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+func (h *Handler) getOrganizationUsage(c *gin.Context) {
+	usage, err := h.service.GetOrganizationUsage(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, usage)
+}
+
+func (h *Handler) enforceRequestQuota() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.ConsumeRequestQuota(c.Request.Context()); err != nil {
+			h.writeError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+type quotaExceededProblem struct {
+	ProblemDetails
+	Quota string `json:"quota"`
+	Limit int64  `json:"limit"`
+	Usage int64  `json:"usage"`
+}
+
+// The request rate answers 429 so clients back off; resource quotas answer 403 because retrying will not help until something is removed or the plan changes.
+func writeQuotaExceededProblem(c *gin.Context, err *service.QuotaExceededError) {
+	status := http.StatusForbidden
+	if err.Quota == service.QuotaRequestsPerMinute {
+		status = http.StatusTooManyRequests
+		c.Header(headerRetryAfter, strconv.Itoa(int(math.Ceil(err.RetryAfter.Seconds()))))
+	}
+	problem := newProblemDetails(c, status, problemTypeQuota, "Quota Exceeded", err.Error())
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(status, quotaExceededProblem{
+		ProblemDetails: problem,
+		Quota:          err.Quota,
+		Limit:          err.Limit,
+		Usage:          err.Usage,
+	})
+}
+
+func (h *Handler) getOrganizationEntitlements(c *gin.Context) {
+	entitlements, err := h.service.GetEntitlements(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entitlements)
+}
+
+func (h *Handler) requireFeature(feature string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := h.service.CheckFeature(c.Request.Context(), feature); err != nil {
+			h.writeError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
+
+type featureNotInPlanProblem struct {
+	ProblemDetails
+	Feature string `json:"feature"`
+	Plan    string `json:"plan"`
+}
+
+func writeFeatureNotInPlanProblem(c *gin.Context, err *service.FeatureNotInPlanError) {
+	problem := newProblemDetails(c, http.StatusForbidden, problemTypeFeature, "Feature Not In Plan", err.Error())
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(http.StatusForbidden, featureNotInPlanProblem{
+		ProblemDetails: problem,
+		Feature:        err.Feature,
+		Plan:           err.Plan,
+	})
+}
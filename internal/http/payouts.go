@@ -0,0 +1,157 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) createClinicPayable(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreateClinicPayableInput
+	if err := bindJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	payable, err := h.service.CreateClinicPayable(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, payable)
+}
+
+func (h *Handler) listClinicPayables(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var open *bool
+	if rawOpen := strings.TrimSpace(c.Query("open")); rawOpen != "" {
+		parsedOpen, err := strconv.ParseBool(rawOpen)
+		if err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", fmt.Sprintf("invalid parameter %q: must be a boolean", "open"))
+			return
+		}
+		open = &parsedOpen
+	}
+
+	payables, err := h.service.ListClinicPayables(c.Request.Context(), clinicID, open)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, payables)
+}
+
+func (h *Handler) createPayoutBatch(c *gin.Context) {
+	var input service.CreatePayoutBatchInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	batch, err := h.service.CreatePayoutBatch(c.Request.Context(), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, batch)
+}
+
+func (h *Handler) listPayoutBatches(c *gin.Context) {
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.PayoutBatchOutput, *string, error) {
+			return h.service.ListPayoutBatches(ctx, maxCursorLimit, cursor, optionalQuery(c, "status"))
+		})
+		return
+	}
+
+	batches, nextCursor, err := h.service.ListPayoutBatches(c.Request.Context(), limit, cursor, optionalQuery(c, "status"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, batches)
+}
+
+func (h *Handler) getPayoutBatch(c *gin.Context) {
+	batchID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	batch, err := h.service.GetPayoutBatch(c.Request.Context(), batchID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
+
+func (h *Handler) downloadPayoutBatchFile(c *gin.Context) {
+	batchID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	file, err := h.service.GetPayoutBatchFile(c.Request.Context(), batchID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
+
+func (h *Handler) updatePayoutBatchStatus(c *gin.Context) {
+	batchID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdatePayoutBatchStatusInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	batch, err := h.service.UpdatePayoutBatchStatus(c.Request.Context(), batchID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, batch)
+}
@@ -0,0 +1,231 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) createSupplier(c *gin.Context) {
+	var input service.CreateSupplierInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	supplier, err := h.service.CreateSupplier(c.Request.Context(), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, supplier)
+}
+
+func (h *Handler) listSuppliers(c *gin.Context) {
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	suppliers, nextCursor, err := h.service.ListSuppliers(c.Request.Context(), limit, cursor)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, suppliers)
+}
+
+func (h *Handler) getSupplier(c *gin.Context) {
+	supplierID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	supplier, err := h.service.GetSupplier(c.Request.Context(), supplierID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+func (h *Handler) updateSupplier(c *gin.Context) {
+	supplierID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateSupplierInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	supplier, err := h.service.UpdateSupplier(c.Request.Context(), supplierID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, supplier)
+}
+
+func (h *Handler) deleteSupplier(c *gin.Context) {
+	supplierID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if err := h.service.DeleteSupplier(c.Request.Context(), supplierID); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (h *Handler) getSupplierSpendReport(c *gin.Context) {
+	report, err := h.service.GetSupplierSpendReport(c.Request.Context(), optionalQuery(c, "clinic_id"), optionalQuery(c, "from"), optionalQuery(c, "to"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) createPurchaseOrder(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.CreatePurchaseOrderInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	order, err := h.service.CreatePurchaseOrder(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, order)
+}
+
+func (h *Handler) listPurchaseOrders(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	orders, nextCursor, err := h.service.ListPurchaseOrders(c.Request.Context(), clinicID, limit, cursor, optionalQuery(c, "status"), optionalQuery(c, "supplier_id"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, orders)
+}
+
+func (h *Handler) getPurchaseOrder(c *gin.Context) {
+	clinicID, orderID, ok := h.parsePurchaseOrderParams(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.GetPurchaseOrder(c.Request.Context(), clinicID, orderID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) submitPurchaseOrder(c *gin.Context) {
+	clinicID, orderID, ok := h.parsePurchaseOrderParams(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.SubmitPurchaseOrder(c.Request.Context(), clinicID, orderID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) cancelPurchaseOrder(c *gin.Context) {
+	clinicID, orderID, ok := h.parsePurchaseOrderParams(c)
+	if !ok {
+		return
+	}
+
+	order, err := h.service.CancelPurchaseOrder(c.Request.Context(), clinicID, orderID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) receivePurchaseOrder(c *gin.Context) {
+	clinicID, orderID, ok := h.parsePurchaseOrderParams(c)
+	if !ok {
+		return
+	}
+
+	var input service.ReceivePurchaseOrderInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	order, err := h.service.ReceivePurchaseOrder(c.Request.Context(), clinicID, orderID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, order)
+}
+
+func (h *Handler) parsePurchaseOrderParams(c *gin.Context) (string, string, bool) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	orderID, err := parseID(c, "order_id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return "", "", false
+	}
+	return clinicID, orderID, true
+}
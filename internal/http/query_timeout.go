@@ -0,0 +1,53 @@
+package http
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const queryTimeoutKey = "query_timeout"
+
+type QueryTimeoutConfig struct {
+	// Default bounds the queries of every request; zero leaves requests without a deadline.
+	Default time.Duration
+	// Routes overrides Default per endpoint, keyed by method and route as in "GET /api/v1/audit-logs/export".
+	Routes map[string]time.Duration
+}
+
+func (config QueryTimeoutConfig) timeout(method string, route string) time.Duration {
+	if timeout, ok := config.Routes[method+" "+route]; ok {
+		return timeout
+	}
+	return config.Default
+}
+
+// queryTimeoutMiddleware puts the endpoint's deadline on the request context, which every repository call derives from. A client
+// that disconnects cancels the same context, so its in-flight queries stop with it.
+func queryTimeoutMiddleware(config QueryTimeoutConfig) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		timeout := config.timeout(c.Request.Method, c.FullPath())
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+		c.Set(queryTimeoutKey, timeout)
+		// A streamed list takes as long as it has pages, so streamPages bounds each page instead.
+		if wantsNDJSON(c) {
+			c.Next()
+			return
+		}
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
+
+func queryContext(c *gin.Context) (context.Context, context.CancelFunc) {
+	if timeout := c.GetDuration(queryTimeoutKey); timeout > 0 {
+		return context.WithTimeout(c.Request.Context(), timeout)
+	}
+	return context.WithCancel(c.Request.Context())
+}
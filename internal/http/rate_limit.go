@@ -0,0 +1,67 @@
+package http
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	headerRateLimitLimit     = "X-RateLimit-Limit"
+	headerRateLimitRemaining = "X-RateLimit-Remaining"
+	headerRetryAfter         = "Retry-After"
+)
+
+type fixedWindowLimiter struct {
+	mu          sync.Mutex
+	limit       int
+	window      time.Duration
+	now         func() time.Time
+	windowStart time.Time
+	counts      map[string]int
+}
+
+func newFixedWindowLimiter(limit int, window time.Duration) *fixedWindowLimiter {
+	return &fixedWindowLimiter{
+		limit:  limit,
+		window: window,
+		now:    time.Now,
+		counts: make(map[string]int),
+	}
+}
+
+func (l *fixedWindowLimiter) allow(key string) (bool, int, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	// Counters are dropped when the window rolls over, so memory is bounded by the clients seen in one window.
+	if now.Sub(l.windowStart) >= l.window {
+		l.windowStart = now.Truncate(l.window)
+		l.counts = make(map[string]int)
+	}
+	resetIn := l.windowStart.Add(l.window).Sub(now)
+	if l.counts[key] >= l.limit {
+		return false, 0, resetIn
+	}
+	l.counts[key]++
+	return true, l.limit - l.counts[key], resetIn
+}
+
+func rateLimitMiddleware(limiter *fixedWindowLimiter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		allowed, remaining, resetIn := limiter.allow(c.ClientIP())
+		c.Header(headerRateLimitLimit, strconv.Itoa(limiter.limit))
+		c.Header(headerRateLimitRemaining, strconv.Itoa(remaining))
+		if !allowed {
+			c.Header(headerRetryAfter, strconv.Itoa(int(math.Ceil(resetIn.Seconds()))))
+			writeProblemResponse(c, http.StatusTooManyRequests, problemTypeRateLimited, "Too Many Requests", "rate limit exceeded; retry later")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) restoreClinic(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	clinic, err := h.service.RestoreClinic(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, clinic)
+}
+
+func (h *Handler) restoreDentist(c *gin.Context) {
+	dentistID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	dentist, err := h.service.RestoreDentist(c.Request.Context(), dentistID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dentist)
+}
+
+func (h *Handler) listDeletedResources(c *gin.Context) {
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.DeletedResourceOutput, *string, error) {
+			return h.service.ListDeletedResources(ctx, maxCursorLimit, cursor, optionalQuery(c, "type"))
+		})
+		return
+	}
+
+	resources, nextCursor, err := h.service.ListDeletedResources(c.Request.Context(), limit, cursor, optionalQuery(c, "type"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, resources)
+}
+
+func (h *Handler) listTrash(c *gin.Context) {
+	limit, cursor, err := parseCursorPagination(c)
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	if wantsNDJSON(c) {
+		streamPages(h, c, cursor, func(ctx context.Context, cursor *string) ([]service.TrashItemOutput, *string, error) {
+			return h.service.ListTrash(ctx, maxCursorLimit, cursor, optionalQuery(c, "type"))
+		})
+		return
+	}
+
+	items, nextCursor, err := h.service.ListTrash(c.Request.Context(), limit, cursor, optionalQuery(c, "type"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	setCursorHeaders(c, limit, nextCursor)
+	c.JSON(http.StatusOK, items)
+}
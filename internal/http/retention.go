@@ -0,0 +1,55 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) getRetentionPurgeReport(c *gin.Context) {
+	report, err := h.service.GetRetentionPurgeReport(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+func (h *Handler) listDocumentRetentionRules(c *gin.Context) {
+	rules, err := h.service.ListDocumentRetentionRules(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+func (h *Handler) setDocumentRetentionRule(c *gin.Context) {
+	var input service.DocumentRetentionRuleInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	rule, err := h.service.SetDocumentRetentionRule(c.Request.Context(), c.Param("kind"), input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+func (h *Handler) deleteDocumentRetentionRule(c *gin.Context) {
+	if err := h.service.DeleteDocumentRetentionRule(c.Request.Context(), c.Param("kind")); err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
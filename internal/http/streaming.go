@@ -0,0 +1,101 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+const ndjsonContentType = "application/x-ndjson"
+
+func wantsNDJSON(c *gin.Context) bool {
+	for _, accepted := range strings.Split(c.GetHeader("Accept"), ",") {
+		mediaType, _, err := mime.ParseMediaType(strings.TrimSpace(accepted))
+		if err == nil && mediaType == ndjsonContentType {
+			return true
+		}
+	}
+	return false
+}
+
+// ndjsonStream writes one JSON document per line. Headers go out with the first row, so a failure before it still gets a problem response.
+type ndjsonStream struct {
+	c           *gin.Context
+	encoder     *json.Encoder
+	disposition string
+	started     bool
+}
+
+func newNDJSONStream(c *gin.Context, disposition string) *ndjsonStream {
+	return &ndjsonStream{c: c, encoder: json.NewEncoder(c.Writer), disposition: disposition}
+}
+
+func (s *ndjsonStream) start() {
+	s.started = true
+	s.c.Header("Cache-Control", "no-store")
+	s.c.Header("Content-Type", ndjsonContentType)
+	if s.disposition != "" {
+		s.c.Header("Content-Disposition", s.disposition)
+	}
+	s.c.Status(http.StatusOK)
+}
+
+func (s *ndjsonStream) write(value any) error {
+	if !s.started {
+		s.start()
+	}
+	return s.encoder.Encode(value)
+}
+
+func (s *ndjsonStream) flush() {
+	if s.started {
+		s.c.Writer.Flush()
+	}
+}
+
+func (s *ndjsonStream) finish(h *Handler, err error) {
+	if err != nil {
+		if !s.started {
+			h.writeError(s.c, err)
+			return
+		}
+		// Headers are already on the wire; a truncated body is all that can signal the failure.
+		slog.ErrorContext(s.c.Request.Context(), "stream response", "path", s.c.FullPath(), "error", err)
+		return
+	}
+	if !s.started {
+		s.start()
+	}
+}
+
+// streamPages writes every page of a cursor-paginated list from cursor on, fetching the next page only after the previous one is
+// on the wire, so memory stays at one page however long the list is. The endpoint's query timeout applies to each page.
+func streamPages[T any](h *Handler, c *gin.Context, cursor *string, page func(ctx context.Context, cursor *string) ([]T, *string, error)) {
+	stream := newNDJSONStream(c, "")
+	for {
+		ctx, cancel := queryContext(c)
+		items, nextCursor, err := page(ctx, cursor)
+		cancel()
+		if err != nil {
+			stream.finish(h, err)
+			return
+		}
+		for _, item := range items {
+			if err := stream.write(item); err != nil {
+				stream.finish(h, err)
+				return
+			}
+		}
+		stream.flush()
+		if nextCursor == nil || *nextCursor == "" {
+			stream.finish(h, nil)
+			return
+		}
+		cursor = nextCursor
+	}
+}
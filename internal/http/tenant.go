@@ -0,0 +1,51 @@
+package http
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+const headerOrganizationID = "X-Org-ID"
+
+// resolveTenant scopes the request to the organization named by X-Org-ID or, failing that, by the subdomain under baseDomain.
+// Requests naming no organization fall back to the token's, and requireAuth rejects a token from another organization.
+func (h *Handler) resolveTenant(baseDomain string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		reference := strings.TrimSpace(c.GetHeader(headerOrganizationID))
+		if reference == "" {
+			reference = tenantFromHost(c.Request.Host, baseDomain)
+		}
+		if reference == "" {
+			c.Next()
+			return
+		}
+
+		organizationID, err := h.service.ResolveOrganization(c.Request.Context(), reference)
+		if err != nil {
+			h.writeError(c, err)
+			return
+		}
+		c.Request = c.Request.WithContext(service.WithOrganization(c.Request.Context(), organizationID))
+		c.Next()
+	}
+}
+
+func tenantFromHost(host string, baseDomain string) string {
+	baseDomain = strings.ToLower(strings.Trim(strings.TrimSpace(baseDomain), "."))
+	if baseDomain == "" {
+		return ""
+	}
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	label, ok := strings.CutSuffix(host, "."+baseDomain)
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
@@ -0,0 +1,121 @@
+package http
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"capim-test/internal/service"
+)
+
+func (h *Handler) getTimeClockSettings(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	settings, err := h.service.GetTimeClockSettings(c.Request.Context(), clinicID)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *Handler) updateTimeClockSettings(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.UpdateTimeClockSettingsInput
+	if err := bindStrictJSON(c, &input); err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+		return
+	}
+
+	settings, err := h.service.UpdateTimeClockSettings(c.Request.Context(), clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+func (h *Handler) clockIn(c *gin.Context) {
+	h.punchTimeClock(c, http.StatusCreated, h.service.ClockIn)
+}
+
+func (h *Handler) clockOut(c *gin.Context) {
+	h.punchTimeClock(c, http.StatusOK, h.service.ClockOut)
+}
+
+func (h *Handler) punchTimeClock(c *gin.Context, status int, punch func(ctx context.Context, dentistID string, clinicID string, input service.TimeClockPunchInput) (service.TimeClockEntryOutput, error)) {
+	principal, ok := h.dentistPrincipal(c)
+	if !ok {
+		return
+	}
+
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	var input service.TimeClockPunchInput
+	if c.Request.ContentLength != 0 {
+		if err := bindStrictJSON(c, &input); err != nil {
+			h.writeProblem(c, http.StatusBadRequest, problemTypeValidation, "Validation Error", fmt.Sprintf("invalid request body: %s", err.Error()))
+			return
+		}
+	}
+	input.IP = c.ClientIP()
+
+	entry, err := punch(c.Request.Context(), principal.DentistID, clinicID, input)
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(status, entry)
+}
+
+func (h *Handler) getClinicTimesheet(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	timesheet, err := h.service.GetClinicTimesheet(c.Request.Context(), clinicID, optionalQuery(c, "month"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, timesheet)
+}
+
+func (h *Handler) exportClinicTimesheet(c *gin.Context) {
+	clinicID, err := parseID(c, "id")
+	if err != nil {
+		h.writeProblem(c, http.StatusBadRequest, problemTypeInvalidParam, "Invalid Parameter", err.Error())
+		return
+	}
+
+	file, err := h.service.ExportClinicTimesheet(c.Request.Context(), clinicID, optionalQuery(c, "month"))
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+	c.Data(http.StatusOK, file.ContentType, file.Content)
+}
@@ -0,0 +1,17 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) listWarehouseExportWatermarks(c *gin.Context) {
+	watermarks, err := h.service.ListWarehouseExportWatermarks(c.Request.Context())
+	if err != nil {
+		h.writeError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, watermarks)
+}
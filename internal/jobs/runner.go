@@ -0,0 +1,42 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+func Every(ctx context.Context, name string, interval time.Duration, run func(ctx context.Context) error) {
+	if interval <= 0 {
+		slog.Warn("job disabled: non-positive interval", "job", name)
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		runOnce(ctx, name, run)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func runOnce(ctx context.Context, name string, run func(ctx context.Context) error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			slog.ErrorContext(ctx, "job panicked", "job", name, "panic", recovered)
+		}
+	}()
+
+	startedAt := time.Now()
+	if err := run(ctx); err != nil {
+		slog.ErrorContext(ctx, "job failed", "job", name, "error", err, "duration_ms", time.Since(startedAt).Milliseconds())
+		return
+	}
+	slog.DebugContext(ctx, "job finished", "job", name, "duration_ms", time.Since(startedAt).Milliseconds())
+}
@@ -0,0 +1,63 @@
+package keywrap
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const masterKeySize = 32
+
+// LocalWrapper wraps organization data keys with a master key held in the process environment.
+// A KMS-backed wrapper only needs the same two methods; the wrapped key format is opaque to the service.
+type LocalWrapper struct {
+	aead cipher.AEAD
+}
+
+func NewLocal(encodedMasterKey string) (*LocalWrapper, error) {
+	encodedMasterKey = strings.TrimSpace(encodedMasterKey)
+	if encodedMasterKey == "" {
+		return nil, errors.New("master key is required")
+	}
+	masterKey, err := base64.StdEncoding.DecodeString(encodedMasterKey)
+	if err != nil {
+		return nil, fmt.Errorf("decode master key: %w", err)
+	}
+	if len(masterKey) != masterKeySize {
+		return nil, fmt.Errorf("master key must be %d bytes, got %d", masterKeySize, len(masterKey))
+	}
+	block, err := aes.NewCipher(masterKey)
+	if err != nil {
+		return nil, fmt.Errorf("create master key cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("create master key cipher: %w", err)
+	}
+	return &LocalWrapper{aead: aead}, nil
+}
+
+func (w *LocalWrapper) WrapKey(_ context.Context, organizationID string, key []byte) ([]byte, error) {
+	nonce := make([]byte, w.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return w.aead.Seal(nonce, nonce, key, []byte(organizationID)), nil
+}
+
+func (w *LocalWrapper) UnwrapKey(_ context.Context, organizationID string, wrapped []byte) ([]byte, error) {
+	if len(wrapped) < w.aead.NonceSize() {
+		return nil, errors.New("wrapped key is truncated")
+	}
+	nonce, sealed := wrapped[:w.aead.NonceSize()], wrapped[w.aead.NonceSize():]
+	key, err := w.aead.Open(nil, nonce, sealed, []byte(organizationID))
+	if err != nil {
+		return nil, fmt.Errorf("unwrap data key: %w", err)
+	}
+	return key, nil
+}
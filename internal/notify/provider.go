@@ -0,0 +1,74 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const headerIdempotencyKey = "Idempotency-Key"
+
+// Provider posts SMS or WhatsApp messages to a messaging gateway as JSON. Gateways with their own wire format sit behind a small
+// relay, or implement service.NotificationSender directly.
+type Provider struct {
+	endpoint   string
+	token      string
+	httpClient *http.Client
+}
+
+type providerMessage struct {
+	ID      string `json:"id"`
+	Channel string `json:"channel"`
+	To      string `json:"to"`
+	Body    string `json:"body"`
+}
+
+func NewProvider(endpoint string, token string, timeout time.Duration) *Provider {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Provider{
+		endpoint:   strings.TrimSpace(endpoint),
+		token:      strings.TrimSpace(token),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *Provider) Send(ctx context.Context, message service.NotificationMessage) error {
+	body, err := json.Marshal(providerMessage{
+		ID:      message.ID,
+		Channel: strings.ToLower(message.Channel),
+		To:      message.Recipient,
+		Body:    message.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("encode %s message: %w", strings.ToLower(message.Channel), err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build %s request: %w", strings.ToLower(message.Channel), err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerIdempotencyKey, message.ID)
+	if p.token != "" {
+		req.Header.Set("Authorization", "Bearer "+p.token)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("call %s provider: %w", strings.ToLower(message.Channel), err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%s provider returned status %d", strings.ToLower(message.Channel), resp.StatusCode)
+	}
+	return nil
+}
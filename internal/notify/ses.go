@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+type SESConfig struct {
+	Region          string
+	Endpoint        string
+	From            string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Timeout         time.Duration
+}
+
+// SESSender sends email through the Amazon SES v2 API.
+type SESSender struct {
+	cfg        SESConfig
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+type sesContent struct {
+	Data    string `json:"Data"`
+	Charset string `json:"Charset"`
+}
+
+type sesTag struct {
+	Name  string `json:"Name"`
+	Value string `json:"Value"`
+}
+
+type sesEmail struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Simple struct {
+			Subject sesContent `json:"Subject"`
+			Body    struct {
+				Text sesContent `json:"Text"`
+			} `json:"Body"`
+		} `json:"Simple"`
+	} `json:"Content"`
+	// EmailTags come back on SES delivery and bounce events, which is how a relay maps them to the delivery callback.
+	EmailTags []sesTag `json:"EmailTags"`
+}
+
+func NewSES(cfg SESConfig) (*SESSender, error) {
+	cfg.Region = strings.TrimSpace(cfg.Region)
+	cfg.From = strings.TrimSpace(cfg.From)
+	if cfg.Region == "" || cfg.From == "" {
+		return nil, errors.New("ses region and sender address are required")
+	}
+	if strings.TrimSpace(cfg.AccessKeyID) == "" || strings.TrimSpace(cfg.SecretAccessKey) == "" {
+		return nil, errors.New("ses credentials are required")
+	}
+	rawEndpoint := strings.TrimRight(strings.TrimSpace(cfg.Endpoint), "/")
+	if rawEndpoint == "" {
+		rawEndpoint = "https://email." + cfg.Region + ".amazonaws.com"
+	}
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid ses endpoint %q", rawEndpoint)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &SESSender{
+		cfg:        cfg,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}, nil
+}
+
+func (s *SESSender) Send(ctx context.Context, message service.NotificationMessage) error {
+	var email sesEmail
+	email.FromEmailAddress = s.cfg.From
+	email.EmailTags = []sesTag{{Name: "notification_id", Value: message.ID}}
+	email.Destination.ToAddresses = []string{message.Recipient}
+	email.Content.Simple.Subject = sesContent{Data: message.Subject, Charset: "UTF-8"}
+	email.Content.Simple.Body.Text = sesContent{Data: message.Body, Charset: "UTF-8"}
+	body, err := json.Marshal(email)
+	if err != nil {
+		return fmt.Errorf("encode ses request: %w", err)
+	}
+
+	requestURL := *s.endpoint
+	requestURL.Path = strings.TrimRight(requestURL.Path, "/") + "/v2/email/outbound-emails"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build ses request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	s.sign(req, body)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("call ses: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("ses returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign applies AWS Signature Version 4 to a request without query parameters.
+func (s *SESSender) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if token := strings.TrimSpace(s.cfg.SessionToken); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	signedHeaders := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("X-Amz-Security-Token") != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := shortDate + "/" + s.cfg.Region + "/ses/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	signingKey := hmacSHA256([]byte("AWS4"+strings.TrimSpace(s.cfg.SecretAccessKey)), shortDate)
+	signingKey = hmacSHA256(signingKey, s.cfg.Region)
+	signingKey = hmacSHA256(signingKey, "ses")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		strings.TrimSpace(s.cfg.AccessKeyID), scope, strings.Join(signedHeaders, ";"), signature,
+	))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
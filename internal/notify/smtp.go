@@ -0,0 +1,137 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+type SMTPConfig struct {
+	// Addr is host:port of the relay; STARTTLS is used whenever the server offers it.
+	Addr     string
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+type SMTPSender struct {
+	cfg  SMTPConfig
+	host string
+	from *mail.Address
+}
+
+func NewSMTP(cfg SMTPConfig) (*SMTPSender, error) {
+	cfg.Addr = strings.TrimSpace(cfg.Addr)
+	host, _, err := net.SplitHostPort(cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid smtp address %q: %w", cfg.Addr, err)
+	}
+	from, err := mail.ParseAddress(strings.TrimSpace(cfg.From))
+	if err != nil {
+		return nil, fmt.Errorf("invalid notification sender address: %w", err)
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	return &SMTPSender{cfg: cfg, host: host, from: from}, nil
+}
+
+func (s *SMTPSender) Send(ctx context.Context, message service.NotificationMessage) error {
+	to, err := mail.ParseAddress(message.Recipient)
+	if err != nil {
+		return fmt.Errorf("invalid recipient address: %w", err)
+	}
+	body, err := encodeEmail(s.from, to, message, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.cfg.Addr)
+	if err != nil {
+		return fmt.Errorf("dial smtp: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return fmt.Errorf("set smtp deadline: %w", err)
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		return fmt.Errorf("open smtp session: %w", err)
+	}
+	defer client.Close()
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return fmt.Errorf("smtp starttls: %w", err)
+		}
+	}
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.host)); err != nil {
+			return fmt.Errorf("smtp auth: %w", err)
+		}
+	}
+	if err := client.Mail(s.from.Address); err != nil {
+		return fmt.Errorf("smtp mail from: %w", err)
+	}
+	if err := client.Rcpt(to.Address); err != nil {
+		return fmt.Errorf("smtp rcpt to: %w", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp data: %w", err)
+	}
+	if _, err := writer.Write(body); err != nil {
+		return fmt.Errorf("write smtp message: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("send smtp message: %w", err)
+	}
+	return client.Quit()
+}
+
+// encodeEmail builds a plain-text message. The Message-ID is derived from the notification id, so a resent message can be
+// recognized as a duplicate by the recipient's server.
+func encodeEmail(from *mail.Address, to *mail.Address, message service.NotificationMessage, now time.Time) ([]byte, error) {
+	if strings.ContainsAny(message.Subject, "\r\n") {
+		return nil, errors.New("email subject must be a single line")
+	}
+	domain := from.Address[strings.LastIndex(from.Address, "@")+1:]
+	var buf bytes.Buffer
+	headers := []string{
+		"From: " + from.String(),
+		"To: " + to.String(),
+		"Subject: " + mime.QEncoding.Encode("utf-8", message.Subject),
+		"Date: " + now.UTC().Format(time.RFC1123Z),
+		"Message-ID: <" + message.ID + "@" + domain + ">",
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"Content-Transfer-Encoding: quoted-printable",
+	}
+	buf.WriteString(strings.Join(headers, "\r\n"))
+	buf.WriteString("\r\n\r\n")
+	encoder := quotedprintable.NewWriter(&buf)
+	if _, err := encoder.Write([]byte(message.Body)); err != nil {
+		return nil, fmt.Errorf("encode email body: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encode email body: %w", err)
+	}
+	return buf.Bytes(), nil
+}
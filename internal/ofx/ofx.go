@@ -0,0 +1,132 @@
+package ofx
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	lineSeparator = "\r\n"
+	dateLayout    = "20060102"
+	maxMemoLength = 255
+)
+
+type Transaction struct {
+	ID          string
+	PostedOn    time.Time
+	AmountCents int64
+	Name        string
+	Memo        string
+}
+
+type Statement struct {
+	BankID              string
+	AccountID           string
+	Currency            string
+	GeneratedAt         time.Time
+	From                time.Time
+	To                  time.Time
+	ClosingBalanceCents int64
+	Transactions        []Transaction
+}
+
+// Encode writes an OFX 1.02 (SGML) bank statement, the version Brazilian banks export and accounting software imports.
+// Positive amounts are credits to the account and negative amounts debits.
+func Encode(statement Statement) ([]byte, error) {
+	if strings.TrimSpace(statement.AccountID) == "" {
+		return nil, fmt.Errorf("ofx: account id is required")
+	}
+	if statement.To.Before(statement.From) {
+		return nil, fmt.Errorf("ofx: statement ends before it starts")
+	}
+	currency := statement.Currency
+	if currency == "" {
+		currency = "BRL"
+	}
+
+	var buf bytes.Buffer
+	line := func(parts ...string) {
+		buf.WriteString(strings.Join(parts, ""))
+		buf.WriteString(lineSeparator)
+	}
+	for _, header := range []string{"OFXHEADER:100", "DATA:OFXSGML", "VERSION:102", "SECURITY:NONE", "ENCODING:UTF-8", "CHARSET:NONE", "COMPRESSION:NONE", "OLDFILEUID:NONE", "NEWFILEUID:NONE"} {
+		line(header)
+	}
+	line()
+	line("<OFX>")
+	line("<SIGNONMSGSRSV1>")
+	line("<SONRS>")
+	line("<STATUS>")
+	line("<CODE>0")
+	line("<SEVERITY>INFO")
+	line("</STATUS>")
+	line("<DTSERVER>", statement.GeneratedAt.UTC().Format("20060102150405"))
+	line("<LANGUAGE>POR")
+	line("</SONRS>")
+	line("</SIGNONMSGSRSV1>")
+	line("<BANKMSGSRSV1>")
+	line("<STMTTRNRS>")
+	line("<TRNUID>1")
+	line("<STATUS>")
+	line("<CODE>0")
+	line("<SEVERITY>INFO")
+	line("</STATUS>")
+	line("<STMTRS>")
+	line("<CURDEF>", text(currency, 3))
+	line("<BANKACCTFROM>")
+	line("<BANKID>", text(statement.BankID, 9))
+	line("<ACCTID>", text(statement.AccountID, 36))
+	line("<ACCTTYPE>CHECKING")
+	line("</BANKACCTFROM>")
+	line("<BANKTRANLIST>")
+	line("<DTSTART>", statement.From.Format(dateLayout))
+	line("<DTEND>", statement.To.Format(dateLayout))
+	for _, transaction := range statement.Transactions {
+		kind := "CREDIT"
+		if transaction.AmountCents < 0 {
+			kind = "DEBIT"
+		}
+		line("<STMTTRN>")
+		line("<TRNTYPE>", kind)
+		line("<DTPOSTED>", transaction.PostedOn.Format(dateLayout))
+		line("<TRNAMT>", amount(transaction.AmountCents))
+		line("<FITID>", text(transaction.ID, 255))
+		if transaction.Name != "" {
+			line("<NAME>", text(transaction.Name, 32))
+		}
+		if transaction.Memo != "" {
+			line("<MEMO>", text(transaction.Memo, maxMemoLength))
+		}
+		line("</STMTTRN>")
+	}
+	line("</BANKTRANLIST>")
+	line("<LEDGERBAL>")
+	line("<BALAMT>", amount(statement.ClosingBalanceCents))
+	line("<DTASOF>", statement.To.Format(dateLayout))
+	line("</LEDGERBAL>")
+	line("</STMTRS>")
+	line("</STMTTRNRS>")
+	line("</BANKMSGSRSV1>")
+	line("</OFX>")
+	return buf.Bytes(), nil
+}
+
+func amount(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// text escapes SGML markup, folds line breaks into spaces and cuts the value to the element's maximum length.
+func text(value string, width int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > width {
+		value = string(runes[:width])
+	}
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+}
@@ -0,0 +1,49 @@
+package ofx
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeWritesSignedTransactionsAndClosingBalance(t *testing.T) {
+	statement := Statement{
+		BankID:              "CAPIM",
+		AccountID:           "0b4f6a52-3a4c-4d2e-9f33-6e8f1c2a7d10",
+		GeneratedAt:         time.Date(2026, 4, 1, 9, 30, 0, 0, time.UTC),
+		From:                time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:                  time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC),
+		ClosingBalanceCents: 149825,
+		Transactions: []Transaction{
+			{ID: "tx-1", PostedOn: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC), AmountCents: 150075, Name: "PAYMENT", Memo: "Repasse <março> & ajustes"},
+			{ID: "tx-2", PostedOn: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC), AmountCents: -250, Name: "FEE", Memo: "Taxa\nmensal"},
+		},
+	}
+
+	content, err := Encode(statement)
+	if err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	document := string(content)
+	if !strings.HasPrefix(document, "OFXHEADER:100\r\nDATA:OFXSGML\r\nVERSION:102\r\n") {
+		t.Fatalf("unexpected header: %q", document[:60])
+	}
+	for _, want := range []string{
+		"<CURDEF>BRL\r\n",
+		"<ACCTID>0b4f6a52-3a4c-4d2e-9f33-6e8f1c2a7d10\r\n",
+		"<DTSTART>20260301\r\n<DTEND>20260331\r\n",
+		"<TRNTYPE>CREDIT\r\n<DTPOSTED>20260303\r\n<TRNAMT>1500.75\r\n<FITID>tx-1\r\n",
+		"<MEMO>Repasse &lt;março&gt; &amp; ajustes\r\n",
+		"<TRNTYPE>DEBIT\r\n<DTPOSTED>20260310\r\n<TRNAMT>-2.50\r\n",
+		"<MEMO>Taxa mensal\r\n",
+		"<BALAMT>1498.25\r\n<DTASOF>20260331\r\n",
+	} {
+		if !strings.Contains(document, want) {
+			t.Fatalf("expected %q in statement", want)
+		}
+	}
+
+	if _, err := Encode(Statement{From: statement.From, To: statement.To}); err == nil {
+		t.Fatal("expected an error without an account id")
+	}
+}
@@ -0,0 +1,92 @@
+package sanitize
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	tagName      = "sanitize"
+	tagSkip      = "-"
+	tagMultiline = "multiline"
+)
+
+func String(value string) string {
+	return clean(value, false)
+}
+
+func Multiline(value string) string {
+	return clean(value, true)
+}
+
+func Struct(target any) {
+	walk(reflect.ValueOf(target), "")
+}
+
+func clean(value string, keepNewlines bool) string {
+	value = norm.NFC.String(value)
+
+	var builder strings.Builder
+	builder.Grow(len(value))
+	pendingSpace := false
+	pendingNewlines := 0
+	for _, r := range value {
+		switch {
+		case keepNewlines && r == '\n':
+			pendingSpace = false
+			pendingNewlines++
+			continue
+		case unicode.IsSpace(r):
+			pendingSpace = true
+			continue
+		case unicode.IsControl(r), unicode.Is(unicode.Cf, r):
+			continue
+		}
+
+		if builder.Len() > 0 {
+			switch {
+			case pendingNewlines > 0:
+				builder.WriteString(strings.Repeat("\n", min(pendingNewlines, 2)))
+			case pendingSpace:
+				builder.WriteByte(' ')
+			}
+		}
+		pendingSpace = false
+		pendingNewlines = 0
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
+
+func walk(value reflect.Value, mode string) {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !value.IsNil() {
+			walk(value.Elem(), mode)
+		}
+	case reflect.String:
+		if value.CanSet() {
+			value.SetString(clean(value.String(), mode == tagMultiline))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			walk(value.Index(i), mode)
+		}
+	case reflect.Struct:
+		valueType := value.Type()
+		for i := 0; i < value.NumField(); i++ {
+			field := valueType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			fieldMode := field.Tag.Get(tagName)
+			if fieldMode == tagSkip {
+				continue
+			}
+			walk(value.Field(i), fieldMode)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package sanitize
+
+import "testing"
+
+func TestStringNormalizesAndStripsInvisibleCharacters(t *testing.T) {
+	tests := map[string]string{
+		"  Odonto   Prime  ":         "Odonto Prime",
+		"Cli\u200bnica\u00a0Sorriso": "Clinica Sorriso",
+		"Jose\u0301 da Silva":        "Jos\u00e9 da Silva",
+		"Line\nbreak\tand\x00null":   "Line break andnull",
+		"\ufeffBOM prefixed":         "BOM prefixed",
+	}
+
+	for input, want := range tests {
+		if got := String(input); got != want {
+			t.Fatalf("String(%q): expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestMultilineKeepsParagraphs(t *testing.T) {
+	got := Multiline("  first   line\r\n\n\n\nsecond\u200d line  ")
+	want := "first line\n\nsecond line"
+	if got != want {
+		t.Fatalf("expected %q, got %q", want, got)
+	}
+}
+
+func TestStructSanitizesNestedFieldsAndHonorsTags(t *testing.T) {
+	type item struct {
+		Name string
+	}
+	type input struct {
+		Name     string
+		Nickname *string
+		Notes    string `sanitize:"multiline"`
+		Password string `sanitize:"-"`
+		Items    []item
+		Nested   *item
+	}
+
+	nickname := " Dr.\u200b  Jane "
+	value := input{
+		Name:     "  Jane   Doe ",
+		Nickname: &nickname,
+		Notes:    "a\n\nb",
+		Password: " keep  me ",
+		Items:    []item{{Name: " one  "}},
+		Nested:   &item{Name: " two "},
+	}
+	Struct(&value)
+
+	if value.Name != "Jane Doe" || *value.Nickname != "Dr. Jane" || value.Notes != "a\n\nb" {
+		t.Fatalf("unexpected sanitized strings: %+v (nickname %q)", value, *value.Nickname)
+	}
+	if value.Password != " keep  me " {
+		t.Fatalf("expected skipped field to be untouched, got %q", value.Password)
+	}
+	if value.Items[0].Name != "one" || value.Nested.Name != "two" {
+		t.Fatalf("expected nested values to be sanitized, got %+v / %+v", value.Items, value.Nested)
+	}
+}
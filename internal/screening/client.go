@@ -0,0 +1,68 @@
+package screening
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+type Client struct {
+	endpoint   string
+	httpClient *http.Client
+}
+
+type request struct {
+	TaxIDType   string `json:"tax_id_type"`
+	TaxIDNumber string `json:"tax_id_number"`
+}
+
+type response struct {
+	Blocked bool   `json:"blocked"`
+	Reason  string `json:"reason"`
+}
+
+func New(endpoint string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+	return &Client{
+		endpoint:   strings.TrimSpace(endpoint),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) ScreenTaxID(ctx context.Context, taxIDType string, taxID string) (service.TaxIDScreeningResult, error) {
+	body, err := json.Marshal(request{TaxIDType: taxIDType, TaxIDNumber: taxID})
+	if err != nil {
+		return service.TaxIDScreeningResult{}, fmt.Errorf("encode screening request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return service.TaxIDScreeningResult{}, fmt.Errorf("build screening request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return service.TaxIDScreeningResult{}, fmt.Errorf("call screening service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return service.TaxIDScreeningResult{}, fmt.Errorf("screening service returned status %d", resp.StatusCode)
+	}
+
+	var payload response
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return service.TaxIDScreeningResult{}, fmt.Errorf("decode screening response: %w", err)
+	}
+	return service.TaxIDScreeningResult{Blocked: payload.Blocked, Reason: payload.Reason}, nil
+}
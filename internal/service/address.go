@@ -0,0 +1,179 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	maxStreetLength     = 255
+	maxNumberLength     = 20
+	maxComplementLength = 255
+	maxCityLength       = 120
+)
+
+type AddressLookupResult struct {
+	Street     string
+	Complement string
+	City       string
+	State      string
+}
+
+type AddressLookup interface {
+	LookupCEP(ctx context.Context, cep string) (AddressLookupResult, bool, error)
+}
+
+func WithAddressLookup(lookup AddressLookup) Option {
+	return func(s *Service) {
+		s.addressLookup = lookup
+	}
+}
+
+func (s *Service) resolveAddressInput(ctx context.Context, input *AddressInput, validations *validationCollector) (*repository.UpsertAddressParams, error) {
+	if input == nil {
+		return nil, nil
+	}
+
+	cep := validation.NormalizeCEP(input.CEP)
+	validCEP := validation.ValidateCEP(cep)
+	if err := validations.check(ValidationRuleAddress, validCEP, "invalid address.cep"); err != nil {
+		return nil, err
+	}
+	if cep == "" {
+		cep = strings.TrimSpace(input.CEP)
+	}
+	if cep == "" {
+		return nil, validationError("address.cep is required")
+	}
+
+	street := strings.TrimSpace(derefString(input.Street))
+	complement := strings.TrimSpace(derefString(input.Complement))
+	city := strings.TrimSpace(derefString(input.City))
+	state := validation.NormalizeState(derefString(input.State))
+
+	if s.addressLookup != nil && validCEP && (street == "" || city == "" || state == "") {
+		result, found, err := s.addressLookup.LookupCEP(ctx, cep)
+		switch {
+		case err != nil:
+			// The lookup only pre-fills missing fields; fall through to the required checks below.
+			slog.WarnContext(ctx, "cep lookup failed", "cep", cep, "error", err)
+		case !found:
+			return nil, validationError("address.cep not found")
+		default:
+			if street == "" {
+				street = strings.TrimSpace(result.Street)
+			}
+			if complement == "" {
+				complement = strings.TrimSpace(result.Complement)
+			}
+			if city == "" {
+				city = strings.TrimSpace(result.City)
+			}
+			if state == "" {
+				state = validation.NormalizeState(result.State)
+			}
+		}
+	}
+
+	if street == "" {
+		return nil, validationError("address.street is required")
+	}
+	if city == "" {
+		return nil, validationError("address.city is required")
+	}
+	if state == "" {
+		return nil, validationError("address.state is required")
+	}
+	if err := validations.check(ValidationRuleAddress, validation.ValidateState(state), "invalid address.state"); err != nil {
+		return nil, err
+	}
+	if err := validateMaxLength("address.street", street, maxStreetLength); err != nil {
+		return nil, err
+	}
+	if err := validateOptionalMaxLength("address.number", input.Number, maxNumberLength); err != nil {
+		return nil, err
+	}
+	if err := validateMaxLength("address.complement", complement, maxComplementLength); err != nil {
+		return nil, err
+	}
+	if err := validateMaxLength("address.city", city, maxCityLength); err != nil {
+		return nil, err
+	}
+
+	return &repository.UpsertAddressParams{
+		OrganizationID: organizationID(ctx),
+		Street:         street,
+		Number:         optionalString(input.Number),
+		Complement:     optionalString(&complement),
+		City:           city,
+		State:          state,
+		Cep:            cep,
+	}, nil
+}
+
+func upsertAddress(ctx context.Context, qtx repository.Querier, personID string, address *repository.UpsertAddressParams) error {
+	if address == nil {
+		return nil
+	}
+	params := *address
+	params.PersonID = personID
+	if _, err := qtx.UpsertAddress(ctx, params); err != nil {
+		return mapDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) loadAddress(ctx context.Context, personID string) (*AddressOutput, error) {
+	address, err := s.queries.GetAddressByPersonID(ctx, repository.GetAddressByPersonIDParams{
+		OrganizationID: organizationID(ctx),
+		PersonID:       personID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mapAddress(address), nil
+}
+
+func (s *Service) loadAddressesByPersonIDs(ctx context.Context, personIDs []string) (map[string]*AddressOutput, error) {
+	return loadBatch(ctx, s, "addresses", personIDs, func(ctx context.Context, personIDs []string) (map[string]*AddressOutput, error) {
+		rows, err := s.queries.ListAddressesByPersonIDs(ctx, repository.ListAddressesByPersonIDsParams{
+			OrganizationID: organizationID(ctx),
+			PersonIds:      personIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		addressesByPerson := make(map[string]*AddressOutput, len(personIDs))
+		for _, row := range rows {
+			addressesByPerson[row.PersonID] = mapAddress(row)
+		}
+		return addressesByPerson, nil
+	})
+}
+
+func mapAddress(address repository.Address) *AddressOutput {
+	return &AddressOutput{
+		Street:     address.Street,
+		Number:     nullToPointer(address.Number),
+		Complement: nullToPointer(address.Complement),
+		City:       address.City,
+		State:      address.State,
+		CEP:        validation.FormatCEP(address.Cep),
+	}
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,371 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	AttachmentScanPending  = "PENDING"
+	AttachmentScanClean    = "CLEAN"
+	AttachmentScanInfected = "INFECTED"
+	AttachmentScanRejected = "REJECTED"
+
+	AttachmentResourceDentistPhoto = "DENTIST_PHOTO"
+
+	AuditEntityAttachmentScan = "ATTACHMENT_SCAN"
+
+	attachmentScanPage          = 20
+	attachmentScanLease         = 5 * time.Minute
+	attachmentScanRetryBase     = time.Minute
+	attachmentScanMaxAttempts   = 5
+	attachmentScanPendingUnique = "idx_attachment_scans_pending_unique"
+
+	eventAttachmentInfected = "attachment.infected"
+)
+
+type AttachmentScanResult struct {
+	Infected  bool
+	Signature string
+}
+
+// Scanners get the file exactly as it was uploaded, before anything decodes it. An error leaves the file in quarantine
+// for another attempt.
+type AttachmentScanner interface {
+	ScanAttachment(ctx context.Context, data []byte) (AttachmentScanResult, error)
+}
+
+// WithAttachmentScanner quarantines uploads until scanner clears them; without it uploads are stored right away.
+func WithAttachmentScanner(scanner AttachmentScanner) Option {
+	return func(s *Service) {
+		s.attachmentScanner = scanner
+	}
+}
+
+func (s *Service) GetDentistPhotoScan(ctx context.Context, dentistID string) (AttachmentScanOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetDentistPhotoScan")
+	defer span.End()
+
+	scan, err := s.queries.GetLatestAttachmentScan(ctx, repository.GetLatestAttachmentScanParams{
+		OrganizationID: organizationID(ctx),
+		ResourceType:   AttachmentResourceDentistPhoto,
+		ResourceID:     dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return AttachmentScanOutput{}, notFoundError("dentist photo scan not found")
+		}
+		return AttachmentScanOutput{}, err
+	}
+	return mapAttachmentScan(scan), nil
+}
+
+// quarantineDentistPhoto keeps the upload aside until it is scanned; the current photo stays in place meanwhile.
+func (s *Service) quarantineDentistPhoto(ctx context.Context, dentist repository.Dentist, data []byte, contentType string) (DentistPhotoOutput, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return DentistPhotoOutput{}, unauthorizedError("missing authenticated user")
+	}
+	if err := s.ensureStorageQuota(ctx, 0, int64(len(data))); err != nil {
+		return DentistPhotoOutput{}, err
+	}
+	if err := s.checkDataResidency(ctx, DestinationAttachments); err != nil {
+		return DentistPhotoOutput{}, err
+	}
+	scanID, err := newUUIDV7()
+	if err != nil {
+		return DentistPhotoOutput{}, err
+	}
+
+	scan, err := s.queries.CreateAttachmentScan(ctx, repository.CreateAttachmentScanParams{
+		ID:               scanID,
+		OrganizationID:   organizationID(ctx),
+		ResourceType:     AttachmentResourceDentistPhoto,
+		ResourceID:       dentist.ID,
+		QuarantineKey:    fmt.Sprintf("quarantine/%s", scanID),
+		ContentType:      contentType,
+		SizeBytes:        int64(len(data)),
+		UploadedByUserID: principal.UserID,
+	})
+	if err != nil {
+		if isConstraintViolation(err, attachmentScanPendingUnique) {
+			return DentistPhotoOutput{}, conflictError("the previous photo upload is still being scanned")
+		}
+		return DentistPhotoOutput{}, mapDatabaseError(err)
+	}
+	if err := s.attachments.PutAttachment(ctx, scan.QuarantineKey, contentType, data); err != nil {
+		s.rejectAttachmentScan(ctx, scan, "quarantine storage failed")
+		return DentistPhotoOutput{}, fmt.Errorf("store quarantined photo: %w", err)
+	}
+
+	scanOutput := mapAttachmentScan(scan)
+	output := DentistPhotoOutput{Scan: &scanOutput}
+	if url := s.dentistPhotoURL(dentist.ID, dentist.PhotoUpdatedAt); url != nil {
+		output.PhotoURL = *url
+		output.ThumbnailURL = *s.dentistThumbnailURL(dentist.ID, dentist.PhotoUpdatedAt)
+		output.Width = dentistPhotoSize
+		output.Height = dentistPhotoSize
+		output.UpdatedAt = nullTimeToPointer(dentist.PhotoUpdatedAt)
+	}
+	return output, nil
+}
+
+// ScanQuarantinedAttachments scans the caller's organization's quarantined uploads that are due. Clean files are released to
+// their resource and infected ones are deleted, with the uploader notified.
+func (s *Service) ScanQuarantinedAttachments(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ScanQuarantinedAttachments")
+	defer span.End()
+
+	if s.attachmentScanner == nil || s.attachments == nil {
+		return 0, nil
+	}
+	now := s.now()
+	claimed, err := s.queries.ClaimDueAttachmentScans(ctx, repository.ClaimDueAttachmentScansParams{
+		OrganizationID: organizationID(ctx),
+		DueBefore:      now,
+		LeaseUntil:     now.Add(attachmentScanLease),
+		PageLimit:      attachmentScanPage,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	scanned := 0
+	for _, scan := range claimed {
+		if err := s.scanAttachment(ctx, scan); err != nil {
+			return scanned, fmt.Errorf("scan attachment %s: %w", scan.ID, err)
+		}
+		scanned++
+	}
+	return scanned, nil
+}
+
+func (s *Service) scanAttachment(ctx context.Context, scan repository.AttachmentScan) error {
+	attachment, found, err := s.attachments.GetAttachment(ctx, scan.QuarantineKey)
+	if err != nil {
+		return s.retryAttachmentScan(ctx, scan, fmt.Errorf("load quarantined file: %w", err))
+	}
+	if !found {
+		s.rejectAttachmentScan(ctx, scan, "quarantined file is missing")
+		return nil
+	}
+	result, err := s.attachmentScanner.ScanAttachment(ctx, attachment.Data)
+	if err != nil {
+		return s.retryAttachmentScan(ctx, scan, err)
+	}
+	if result.Infected {
+		return s.rejectInfectedAttachment(ctx, scan, result.Signature)
+	}
+
+	err = s.releaseDentistPhoto(ctx, scan, attachment.Data)
+	var quotaErr *QuotaExceededError
+	switch {
+	case err == nil:
+	case errors.Is(err, ErrValidation), errors.Is(err, ErrNotFound), errors.Is(err, ErrDataResidency), errors.As(err, &quotaErr):
+		s.rejectAttachmentScan(ctx, scan, err.Error())
+		return nil
+	default:
+		return s.retryAttachmentScan(ctx, scan, err)
+	}
+	if err := s.attachments.DeleteAttachment(ctx, scan.QuarantineKey); err != nil {
+		slog.WarnContext(ctx, "delete released quarantined file", "scan_id", scan.ID, "error", err)
+	}
+	return nil
+}
+
+func (s *Service) releaseDentistPhoto(ctx context.Context, scan repository.AttachmentScan, data []byte) error {
+	resized, err := resizeDentistPhoto(data)
+	if err != nil {
+		return err
+	}
+	current, err := s.queries.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             scan.ResourceID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("dentist not found")
+		}
+		return err
+	}
+	// The quarantined upload is still counted in the usage until this scan completes.
+	if err := s.ensureStorageQuota(ctx, current.PhotoSizeBytes+scan.SizeBytes, int64(len(resized))); err != nil {
+		return err
+	}
+	if err := s.checkDataResidency(ctx, DestinationAttachments); err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf("dentists/%s/photo.jpg", scan.ResourceID)
+	if err := s.attachments.PutAttachment(ctx, key, dentistPhotoContentType, resized); err != nil {
+		return fmt.Errorf("store dentist photo: %w", err)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.UpdateDentistPhoto(ctx, repository.UpdateDentistPhotoParams{
+		OrganizationID: organizationID(ctx),
+		ID:             scan.ResourceID,
+		PhotoKey:       sql.NullString{String: key, Valid: true},
+		PhotoSizeBytes: int64(len(resized)),
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("dentist not found")
+		}
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.CompleteAttachmentScan(ctx, repository.CompleteAttachmentScanParams{
+		OrganizationID: organizationID(ctx),
+		ID:             scan.ID,
+		Status:         AttachmentScanClean,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) rejectInfectedAttachment(ctx context.Context, scan repository.AttachmentScan, signature string) error {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	updated, err := qtx.CompleteAttachmentScan(ctx, repository.CompleteAttachmentScanParams{
+		OrganizationID: organizationID(ctx),
+		ID:             scan.ID,
+		Status:         AttachmentScanInfected,
+		Signature:      optionalString(&signature),
+		FailureReason:  sql.NullString{String: "malware detected", Valid: true},
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if updated == 0 {
+		return nil
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "attachment.infected",
+		EntityType: AuditEntityAttachmentScan,
+		EntityID:   scan.ID,
+		Metadata: map[string]any{
+			"resource_type": scan.ResourceType,
+			"resource_id":   scan.ResourceID,
+			"signature":     signature,
+		},
+	}); err != nil {
+		return err
+	}
+	uploader, err := qtx.GetUserByID(ctx, repository.GetUserByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             scan.UploadedByUserID,
+	})
+	switch {
+	case err == nil:
+		if err := s.enqueueNotification(ctx, qtx, notificationRequest{
+			Channel:   NotificationChannelEmail,
+			Template:  NotificationTemplateAttachmentInfected,
+			Recipient: uploader.Email,
+			DedupeKey: NotificationTemplateAttachmentInfected + ":" + scan.ID,
+			Data: map[string]string{
+				"UploadedAt": scan.CreatedAt.Format(documentDateLayout),
+				"Signature":  signature,
+			},
+		}); err != nil {
+			return err
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	if err := s.attachments.DeleteAttachment(ctx, scan.QuarantineKey); err != nil {
+		slog.WarnContext(ctx, "delete infected quarantined file", "scan_id", scan.ID, "error", err)
+	}
+	s.publishAttachmentInfected(ctx, scan)
+	return nil
+}
+
+// rejectAttachmentScan closes a scan whose file can never be released; failures are only logged since the worker moves on either way.
+func (s *Service) rejectAttachmentScan(ctx context.Context, scan repository.AttachmentScan, reason string) {
+	if _, err := s.queries.CompleteAttachmentScan(ctx, repository.CompleteAttachmentScanParams{
+		OrganizationID: organizationID(ctx),
+		ID:             scan.ID,
+		Status:         AttachmentScanRejected,
+		FailureReason:  sql.NullString{String: reason, Valid: true},
+	}); err != nil {
+		slog.WarnContext(ctx, "reject attachment scan", "scan_id", scan.ID, "error", err)
+		return
+	}
+	if err := s.attachments.DeleteAttachment(ctx, scan.QuarantineKey); err != nil {
+		slog.WarnContext(ctx, "delete rejected quarantined file", "scan_id", scan.ID, "error", err)
+	}
+}
+
+// retryAttachmentScan backs off like notification delivery and rejects the file once attachmentScanMaxAttempts is reached.
+func (s *Service) retryAttachmentScan(ctx context.Context, scan repository.AttachmentScan, scanErr error) error {
+	slog.WarnContext(ctx, "attachment scan failed", "scan_id", scan.ID, "attempts", scan.Attempts, "error", scanErr)
+	if int(scan.Attempts) >= attachmentScanMaxAttempts {
+		s.rejectAttachmentScan(ctx, scan, "file could not be scanned")
+		return nil
+	}
+	return s.queries.RescheduleAttachmentScan(ctx, repository.RescheduleAttachmentScanParams{
+		OrganizationID: organizationID(ctx),
+		ID:             scan.ID,
+		NextAttemptAt:  s.now().Add(attachmentScanRetryBase << max(scan.Attempts-1, 0)),
+		FailureReason:  notificationError(scanErr),
+	})
+}
+
+func (s *Service) publishAttachmentInfected(ctx context.Context, scan repository.AttachmentScan) {
+	if !s.webhooksEnabled(ctx) {
+		return
+	}
+	event, err := s.newEvent(eventAttachmentInfected, map[string]string{
+		"scan_id":       scan.ID,
+		"resource_type": scan.ResourceType,
+		"resource_id":   scan.ResourceID,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, event)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "publish attachment infected event failed", "scan_id", scan.ID, "error", err)
+	}
+}
+
+func mapAttachmentScan(scan repository.AttachmentScan) AttachmentScanOutput {
+	return AttachmentScanOutput{
+		ID:            scan.ID,
+		ResourceType:  scan.ResourceType,
+		ResourceID:    scan.ResourceID,
+		Status:        scan.Status,
+		ContentType:   scan.ContentType,
+		SizeBytes:     scan.SizeBytes,
+		Signature:     nullToPointer(scan.Signature),
+		FailureReason: nullToPointer(scan.FailureReason),
+		Attempts:      int(scan.Attempts),
+		CreatedAt:     scan.CreatedAt,
+		ScannedAt:     nullTimeToPointer(scan.ScannedAt),
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/uuid"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	AuditEntityClinic            = "CLINIC"
+	AuditEntityDentist           = "DENTIST"
+	AuditEntityBankAccount       = "BANK_ACCOUNT"
+	AuditEntityBankAccountChange = "BANK_ACCOUNT_CHANGE"
+)
+
+type auditEntry struct {
+	ClinicID   string
+	Action     string
+	EntityType string
+	EntityID   string
+	Metadata   map[string]any
+}
+
+// Audit rows are written with the caller's querier so they commit or roll back together with the change they describe.
+func recordAudit(ctx context.Context, q repository.Querier, entry auditEntry) error {
+	id, err := newUUIDV7()
+	if err != nil {
+		return err
+	}
+	encoded, err := encodeAuditMetadata(ctx, entry.Metadata)
+	if err != nil {
+		return err
+	}
+
+	params := repository.CreateAuditLogParams{
+		OrganizationID: organizationID(ctx),
+		ID:             id,
+		ClinicID:       auditClinicID(entry.ClinicID),
+		ActorUserID:    auditActorID(ctx),
+		Action:         entry.Action,
+		EntityType:     entry.EntityType,
+		EntityID:       entry.EntityID,
+		Metadata:       encoded,
+	}
+	if err := q.CreateAuditLog(ctx, params); err != nil {
+		return mapDatabaseError(err)
+	}
+	return nil
+}
+
+// recordClinicAudits writes the audit rows of one clinic in a single insert; the ClinicID of each entry is ignored.
+func recordClinicAudits(ctx context.Context, q repository.Querier, clinicID string, entries []auditEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	params := repository.CreateAuditLogsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       auditClinicID(clinicID),
+		ActorUserID:    auditActorID(ctx),
+	}
+	for _, entry := range entries {
+		id, err := newUUIDV7()
+		if err != nil {
+			return err
+		}
+		encoded, err := encodeAuditMetadata(ctx, entry.Metadata)
+		if err != nil {
+			return err
+		}
+		params.Ids = append(params.Ids, id)
+		params.Actions = append(params.Actions, entry.Action)
+		params.EntityTypes = append(params.EntityTypes, entry.EntityType)
+		params.EntityIds = append(params.EntityIds, entry.EntityID)
+		params.Metadata = append(params.Metadata, string(encoded))
+	}
+	if err := q.CreateAuditLogs(ctx, params); err != nil {
+		return mapDatabaseError(err)
+	}
+	return nil
+}
+
+func encodeAuditMetadata(ctx context.Context, metadata map[string]any) ([]byte, error) {
+	if metadata == nil {
+		metadata = map[string]any{}
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok && principal.ImpersonatedBy != "" {
+		metadata["impersonated_by"] = principal.ImpersonatedBy
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("encode audit metadata: %w", err)
+	}
+	return encoded, nil
+}
+
+func auditClinicID(clinicID string) uuid.NullUUID {
+	if parsed, err := uuid.Parse(clinicID); err == nil {
+		return uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+	return uuid.NullUUID{}
+}
+
+func auditActorID(ctx context.Context) uuid.NullUUID {
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if parsed, err := uuid.Parse(principal.UserID); err == nil {
+			return uuid.NullUUID{UUID: parsed, Valid: true}
+		}
+	}
+	return uuid.NullUUID{}
+}
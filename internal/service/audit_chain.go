@@ -0,0 +1,217 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	AuditChainSequenceGap          = "SEQUENCE_GAP"
+	AuditChainPreviousHashMismatch = "PREVIOUS_HASH_MISMATCH"
+	AuditChainHashMismatch         = "HASH_MISMATCH"
+	AuditChainHeadMismatch         = "HEAD_MISMATCH"
+
+	auditChainSealBatchSize   = 500
+	auditChainVerifyPageLimit = 1000
+	// Matches the head row seeded in schema.sql.
+	auditChainGenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+// clinic_id stays out of the hashed fields because the retention purge clears it on rows that are otherwise immutable.
+type auditChainEntry struct {
+	Sequence     int64           `json:"sequence"`
+	PreviousHash string          `json:"previous_hash"`
+	ID           string          `json:"id"`
+	ActorUserID  *string         `json:"actor_user_id"`
+	Action       string          `json:"action"`
+	EntityType   string          `json:"entity_type"`
+	EntityID     string          `json:"entity_id"`
+	Metadata     json.RawMessage `json:"metadata"`
+	CreatedAt    string          `json:"created_at"`
+}
+
+func auditEntryHash(previousHash string, sequence int64, row repository.AuditLog) (string, error) {
+	encoded, err := json.Marshal(auditChainEntry{
+		Sequence:     sequence,
+		PreviousHash: previousHash,
+		ID:           row.ID,
+		ActorUserID:  nullUUIDToPointer(row.ActorUserID),
+		Action:       row.Action,
+		EntityType:   row.EntityType,
+		EntityID:     row.EntityID,
+		Metadata:     row.Metadata,
+		CreatedAt:    row.CreatedAt.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return "", fmt.Errorf("encode audit chain entry: %w", err)
+	}
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// SealAuditLogs appends unsealed audit rows to the chain in a separate transaction, so audit writes never contend on the chain head.
+func (s *Service) SealAuditLogs(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.SealAuditLogs")
+	defer span.End()
+
+	sealed := 0
+	for {
+		count, err := s.sealAuditLogBatch(ctx)
+		sealed += count
+		if err != nil || count < auditChainSealBatchSize {
+			return sealed, err
+		}
+	}
+}
+
+func (s *Service) sealAuditLogBatch(ctx context.Context) (int, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	head, err := qtx.GetAuditChainHeadForUpdate(ctx, organizationID(ctx))
+	if err != nil {
+		return 0, err
+	}
+	rows, err := qtx.ListUnsealedAuditLogs(ctx, repository.ListUnsealedAuditLogsParams{
+		OrganizationID: organizationID(ctx),
+		PageLimit:      auditChainSealBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	sequence, previousHash := head.LastSequence, head.LastHash
+	for _, row := range rows {
+		sequence++
+		hash, err := auditEntryHash(previousHash, sequence, row)
+		if err != nil {
+			return 0, err
+		}
+		updated, err := qtx.SealAuditLog(ctx, repository.SealAuditLogParams{
+			OrganizationID: organizationID(ctx),
+			ChainSequence:  sequence,
+			PreviousHash:   previousHash,
+			EntryHash:      hash,
+			ID:             row.ID,
+		})
+		if err != nil {
+			return 0, mapDatabaseError(err)
+		}
+		if updated == 0 {
+			return 0, fmt.Errorf("audit log %s was sealed concurrently", row.ID)
+		}
+		previousHash = hash
+	}
+	if err := qtx.UpdateAuditChainHead(ctx, repository.UpdateAuditChainHeadParams{
+		OrganizationID: organizationID(ctx),
+		LastSequence:   sequence,
+		LastHash:       previousHash,
+	}); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return len(rows), nil
+}
+
+func (s *Service) VerifyAuditChain(ctx context.Context) (AuditChainVerificationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.VerifyAuditChain")
+	defer span.End()
+
+	head, err := s.queries.GetAuditChainHead(ctx, organizationID(ctx))
+	if err != nil {
+		return AuditChainVerificationOutput{}, err
+	}
+	unsealed, err := s.queries.CountUnsealedAuditLogs(ctx, organizationID(ctx))
+	if err != nil {
+		return AuditChainVerificationOutput{}, err
+	}
+	output := AuditChainVerificationOutput{
+		VerifiedAt:      s.now().UTC(),
+		HeadSequence:    head.LastSequence,
+		HeadHash:        head.LastHash,
+		UnsealedEntries: unsealed,
+	}
+
+	// Rows sealed after the head was read are left for the next verification.
+	verifier := auditChainVerifier{hash: auditChainGenesisHash}
+	for verifier.sequence < head.LastSequence {
+		rows, err := s.queries.ListSealedAuditLogs(ctx, repository.ListSealedAuditLogsParams{
+			OrganizationID: organizationID(ctx),
+			AfterSequence:  verifier.sequence,
+			PageLimit:      auditChainVerifyPageLimit,
+		})
+		if err != nil {
+			return AuditChainVerificationOutput{}, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+		chainBreak, err := verifier.verify(rows, head.LastSequence)
+		if err != nil {
+			return AuditChainVerificationOutput{}, err
+		}
+		if chainBreak != nil {
+			output.VerifiedEntries = verifier.verified
+			output.Break = chainBreak
+			return output, nil
+		}
+	}
+
+	output.VerifiedEntries = verifier.verified
+	if verifier.sequence != head.LastSequence || verifier.hash != head.LastHash {
+		output.Break = &AuditChainBreakOutput{Sequence: verifier.sequence + 1, Reason: AuditChainHeadMismatch}
+		return output, nil
+	}
+	output.Valid = true
+	return output, nil
+}
+
+type auditChainVerifier struct {
+	sequence int64
+	hash     string
+	verified int64
+}
+
+func (v *auditChainVerifier) verify(rows []repository.AuditLog, headSequence int64) (*AuditChainBreakOutput, error) {
+	for _, row := range rows {
+		id := row.ID
+		expected := v.sequence + 1
+		if row.ChainSequence.Int64 != expected {
+			return &AuditChainBreakOutput{Sequence: expected, Reason: AuditChainSequenceGap}, nil
+		}
+		if expected > headSequence {
+			return nil, nil
+		}
+		if row.PreviousHash.String != v.hash {
+			return &AuditChainBreakOutput{Sequence: expected, AuditLogID: &id, Reason: AuditChainPreviousHashMismatch}, nil
+		}
+		hash, err := auditEntryHash(v.hash, expected, row)
+		if err != nil {
+			return nil, err
+		}
+		if row.EntryHash.String != hash {
+			return &AuditChainBreakOutput{Sequence: expected, AuditLogID: &id, Reason: AuditChainHashMismatch}, nil
+		}
+		v.sequence, v.hash = expected, hash
+		v.verified++
+	}
+	return nil, nil
+}
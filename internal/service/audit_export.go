@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	auditExportBatchSize = 500
+	auditExportRangePage = 1000
+	auditExportMaxRange  = 31 * 24 * time.Hour
+	// Audit ids are minted before commit, so a slow transaction can land behind the checkpoint; only settled rows are exported.
+	auditExportSettleWindow = time.Minute
+)
+
+type AuditExportSink interface {
+	Name() string
+	ExportAuditLogs(ctx context.Context, batch AuditExportBatch) error
+}
+
+// Batches can be delivered more than once; ID is stable across retries so sinks can deduplicate.
+type AuditExportBatch struct {
+	ID             string
+	OrganizationID string
+	Records        []AuditLogRecord
+}
+
+func WithAuditExportSink(sink AuditExportSink) Option {
+	return func(s *Service) {
+		s.auditExport = sink
+	}
+}
+
+func (s *Service) ExportPendingAuditLogs(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ExportPendingAuditLogs")
+	defer span.End()
+
+	if s.auditExport == nil {
+		return 0, nil
+	}
+	if err := s.checkDataResidency(ctx, DestinationAuditExport); err != nil {
+		return 0, err
+	}
+	exported := 0
+	for {
+		count, err := s.exportAuditLogBatch(ctx)
+		exported += count
+		if err != nil || count < auditExportBatchSize {
+			return exported, err
+		}
+	}
+}
+
+func (s *Service) exportAuditLogBatch(ctx context.Context) (int, error) {
+	sink := s.auditExport.Name()
+	if err := s.queries.EnsureAuditExportCheckpoint(ctx, repository.EnsureAuditExportCheckpointParams{
+		OrganizationID: organizationID(ctx),
+		Sink:           sink,
+	}); err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	checkpoint, err := qtx.GetAuditExportCheckpointForUpdate(ctx, repository.GetAuditExportCheckpointForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		Sink:           sink,
+	})
+	if err != nil {
+		return 0, err
+	}
+	rows, err := qtx.ListAuditLogsForExport(ctx, repository.ListAuditLogsForExportParams{
+		OrganizationID: organizationID(ctx),
+		AfterID:        checkpoint.LastAuditID,
+		SettledBefore:  s.now().UTC().Add(-auditExportSettleWindow),
+		PageLimit:      auditExportBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+
+	records := mapAuditLogRecords(rows)
+	if err := s.auditExport.ExportAuditLogs(ctx, AuditExportBatch{ID: records[0].ID, OrganizationID: organizationID(ctx), Records: records}); err != nil {
+		return 0, fmt.Errorf("export audit logs to %s: %w", sink, err)
+	}
+	if err := qtx.AdvanceAuditExportCheckpoint(ctx, repository.AdvanceAuditExportCheckpointParams{
+		OrganizationID: organizationID(ctx),
+		LastAuditID:    records[len(records)-1].ID,
+		Exported:       int64(len(records)),
+		Sink:           sink,
+	}); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return len(records), nil
+}
+
+func (s *Service) ExportAuditLogRange(ctx context.Context, from time.Time, to time.Time, emit func(AuditLogRecord) error) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ExportAuditLogRange")
+	defer span.End()
+
+	if !to.After(from) {
+		return validationError("to must be after from")
+	}
+	if to.Sub(from) > auditExportMaxRange {
+		return validationError(fmt.Sprintf("export range must not exceed %d days", int(auditExportMaxRange/(24*time.Hour))))
+	}
+
+	afterID := uuid.NullUUID{}
+	for {
+		rows, err := s.queries.ListAuditLogsCreatedBetween(ctx, repository.ListAuditLogsCreatedBetweenParams{
+			OrganizationID: organizationID(ctx),
+			CreatedFrom:    from,
+			CreatedTo:      to,
+			AfterID:        afterID,
+			PageLimit:      auditExportRangePage,
+		})
+		if err != nil {
+			return err
+		}
+		for _, record := range mapAuditLogRecords(rows) {
+			if err := emit(record); err != nil {
+				return err
+			}
+		}
+		if len(rows) < auditExportRangePage {
+			return nil
+		}
+		lastID, err := uuid.Parse(rows[len(rows)-1].ID)
+		if err != nil {
+			return fmt.Errorf("parse audit log id: %w", err)
+		}
+		afterID = uuid.NullUUID{UUID: lastID, Valid: true}
+	}
+}
+
+func mapAuditLogRecords(rows []repository.AuditLog) []AuditLogRecord {
+	records := make([]AuditLogRecord, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, AuditLogRecord{
+			ID:          row.ID,
+			ClinicID:    nullUUIDToPointer(row.ClinicID),
+			ActorUserID: nullUUIDToPointer(row.ActorUserID),
+			Action:      row.Action,
+			EntityType:  row.EntityType,
+			EntityID:    row.EntityID,
+			Metadata:    row.Metadata,
+			CreatedAt:   row.CreatedAt,
+		})
+	}
+	return records
+}
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
 	"golang.org/x/crypto/bcrypt"
 
 	"capim-test/internal/db/repository"
@@ -16,13 +18,47 @@ import (
 )
 
 type accessTokenClaims struct {
-	Email string `json:"email"`
+	Email          string `json:"email"`
+	Role           string `json:"role,omitempty"`
+	DentistID      string `json:"dentist_id,omitempty"`
+	OrganizationID string `json:"org_id,omitempty"`
+	ImpersonatedBy string `json:"impersonated_by,omitempty"`
 	jwt.RegisteredClaims
 }
 
+const dentistUserUniqueConstraint = "idx_users_dentist_id_active_unique"
+
 const dummyPasswordHash = "$2a$10$N9qo8uLOickgx2ZMRZoMyeIjZAgcfl7p92ldGxad68LJZdL17lhWy"
 
+const (
+	UserRoleAdmin   = "ADMIN"
+	UserRoleDentist = "DENTIST"
+)
+
+type Principal struct {
+	UserID         string
+	Email          string
+	Role           string
+	DentistID      string
+	OrganizationID string
+	// ImpersonatedBy is the platform operator behind an impersonation token.
+	ImpersonatedBy string
+}
+
+type principalContextKey struct{}
+
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalContextKey{}, principal)
+}
+
+func PrincipalFromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalContextKey{}).(Principal)
+	return principal, ok
+}
+
 func (s *Service) EnsureUser(ctx context.Context, email string, password string) error {
+	// The bootstrap admin always lands in the default organization.
+	ctx = WithOrganization(ctx, DefaultOrganizationID)
 	normalizedEmail := strings.ToLower(strings.TrimSpace(email))
 	if !validation.ValidateEmail(normalizedEmail) {
 		return validationError("invalid email")
@@ -50,9 +86,11 @@ func (s *Service) EnsureUser(ctx context.Context, email string, password string)
 	}
 
 	_, err = s.queries.CreateUser(ctx, repository.CreateUserParams{
-		ID:           userID,
-		Email:        normalizedEmail,
-		PasswordHash: string(passwordHash),
+		OrganizationID: organizationID(ctx),
+		ID:             userID,
+		Email:          normalizedEmail,
+		PasswordHash:   string(passwordHash),
+		Role:           UserRoleAdmin,
 	})
 	if err != nil {
 		if isUniqueConstraintError(err) {
@@ -76,7 +114,9 @@ func (s *Service) Login(ctx context.Context, input LoginInput) (LoginOutput, err
 		return LoginOutput{}, fmt.Errorf("jwt signing key is not configured")
 	}
 
-	user, err := s.queries.GetUserByEmail(ctx, email)
+	user, err := findInTenantSchemas(ctx, s, func(ctx context.Context) (repository.User, error) {
+		return s.queries.GetUserByEmail(ctx, email)
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			// Keep timing close to existing-user path to reduce account enumeration via latency.
@@ -93,7 +133,9 @@ func (s *Service) Login(ctx context.Context, input LoginInput) (LoginOutput, err
 	now := s.now().UTC()
 	expiresAt := now.Add(s.jwtAccessTokenTTL)
 	claims := accessTokenClaims{
-		Email: user.Email,
+		Email:          user.Email,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
 		RegisteredClaims: jwt.RegisteredClaims{
 			Issuer:    s.jwtIssuer,
 			Subject:   user.ID,
@@ -103,6 +145,10 @@ func (s *Service) Login(ctx context.Context, input LoginInput) (LoginOutput, err
 		},
 	}
 
+	if user.DentistID.Valid {
+		claims.DentistID = user.DentistID.UUID.String()
+	}
+
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	signedToken, err := token.SignedString(s.jwtSigningKey)
 	if err != nil {
@@ -110,20 +156,27 @@ func (s *Service) Login(ctx context.Context, input LoginInput) (LoginOutput, err
 	}
 
 	return LoginOutput{
-		AccessToken: signedToken,
-		TokenType:   "Bearer",
-		ExpiresIn:   int64(time.Until(expiresAt).Seconds()),
-		UserID:      user.ID,
-		Email:       user.Email,
+		AccessToken:    signedToken,
+		TokenType:      "Bearer",
+		ExpiresIn:      int64(time.Until(expiresAt).Seconds()),
+		UserID:         user.ID,
+		Email:          user.Email,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
 	}, nil
 }
 
 func (s *Service) ValidateAccessToken(token string) error {
+	_, err := s.AuthenticateAccessToken(token)
+	return err
+}
+
+func (s *Service) AuthenticateAccessToken(token string) (Principal, error) {
 	if strings.TrimSpace(token) == "" {
-		return unauthorizedError("invalid token")
+		return Principal{}, unauthorizedError("invalid token")
 	}
 	if len(s.jwtSigningKey) == 0 {
-		return fmt.Errorf("jwt signing key is not configured")
+		return Principal{}, fmt.Errorf("jwt signing key is not configured")
 	}
 
 	claims := &accessTokenClaims{}
@@ -139,11 +192,95 @@ func (s *Service) ValidateAccessToken(token string) error {
 		jwt.WithIssuer(s.jwtIssuer),
 	)
 	if err != nil || !parsedToken.Valid {
-		return unauthorizedError("invalid token")
+		return Principal{}, unauthorizedError("invalid token")
 	}
-	if strings.TrimSpace(claims.Subject) == "" {
-		return unauthorizedError("invalid token")
+	if strings.TrimSpace(claims.Subject) == "" || isAdminToken(claims.RegisteredClaims) {
+		return Principal{}, unauthorizedError("invalid token")
 	}
 
-	return nil
+	role := claims.Role
+	if role == "" {
+		// Tokens issued before roles existed only belonged to admins.
+		role = UserRoleAdmin
+	}
+	if role == UserRoleDentist && strings.TrimSpace(claims.DentistID) == "" {
+		return Principal{}, unauthorizedError("invalid token")
+	}
+	organization := claims.OrganizationID
+	if organization == "" {
+		// Tokens issued before organizations existed only belonged to the default one.
+		organization = DefaultOrganizationID
+	}
+
+	return Principal{
+		UserID:         claims.Subject,
+		Email:          claims.Email,
+		Role:           role,
+		DentistID:      claims.DentistID,
+		OrganizationID: organization,
+		ImpersonatedBy: claims.ImpersonatedBy,
+	}, nil
+}
+
+func (s *Service) CreateDentistUser(ctx context.Context, dentistID string, input CreateDentistUserInput) (UserOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateDentistUser")
+	defer span.End()
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if !validation.ValidateEmail(email) {
+		return UserOutput{}, validationError("invalid email")
+	}
+	if len(input.Password) < 8 {
+		return UserOutput{}, validationError("password must have at least 8 characters")
+	}
+
+	dentist, err := s.queries.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return UserOutput{}, notFoundError("dentist not found")
+		}
+		return UserOutput{}, err
+	}
+	parsedDentistID, err := uuid.Parse(dentist.ID)
+	if err != nil {
+		return UserOutput{}, fmt.Errorf("parse dentist id: %w", err)
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(input.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return UserOutput{}, fmt.Errorf("hash password: %w", err)
+	}
+	userID, err := newUUIDV7()
+	if err != nil {
+		return UserOutput{}, err
+	}
+
+	user, err := s.queries.CreateUser(ctx, repository.CreateUserParams{
+		OrganizationID: organizationID(ctx),
+		ID:             userID,
+		Email:          email,
+		PasswordHash:   string(passwordHash),
+		Role:           UserRoleDentist,
+		DentistID:      uuid.NullUUID{UUID: parsedDentistID, Valid: true},
+	})
+	if err != nil {
+		switch {
+		case isConstraintViolation(err, dentistUserUniqueConstraint):
+			return UserOutput{}, conflictError("dentist already has a user")
+		case isUniqueConstraintError(err):
+			return UserOutput{}, conflictError("email already in use")
+		default:
+			return UserOutput{}, mapDatabaseError(err)
+		}
+	}
+
+	return UserOutput{
+		ID:        user.ID,
+		Email:     user.Email,
+		Role:      user.Role,
+		DentistID: &dentist.ID,
+	}, nil
 }
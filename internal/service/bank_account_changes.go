@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	BankAccountChangeAdd    = "ADD"
+	BankAccountChangeRemove = "REMOVE"
+
+	BankAccountChangePending  = "PENDING"
+	BankAccountChangeApproved = "APPROVED"
+	BankAccountChangeRejected = "REJECTED"
+
+	eventBankAccountChangeRequested = "bank_account_change.requested"
+	eventBankAccountChangeReviewed  = "bank_account_change.reviewed"
+
+	maxBankAccountChangeTextLength = 500
+)
+
+func (s *Service) RequestBankAccountChange(ctx context.Context, clinicID string, input RequestBankAccountChangeInput) (BankAccountChangeOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RequestBankAccountChange")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return BankAccountChangeOutput{}, unauthorizedError("missing authenticated user")
+	}
+	params, err := parseBankAccountChangeInput(clinicID, input)
+	if err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+	params.OrganizationID = organizationID(ctx)
+	params.RequestedByUserID = principal.UserID
+	changeID, err := newUUIDV7()
+	if err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+	params.ID = changeID
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return BankAccountChangeOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountChangeOutput{}, notFoundError("clinic not found")
+		}
+		return BankAccountChangeOutput{}, mapDatabaseError(err)
+	}
+	if err := ensureNoFinancialHold(ctx, qtx, clinicID); err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+	if principal.Role == UserRoleDentist {
+		if err := ensureDentistAdministersClinic(ctx, qtx, clinicID, principal.DentistID); err != nil {
+			return BankAccountChangeOutput{}, err
+		}
+	}
+	if params.ChangeType == BankAccountChangeRemove {
+		accountID := params.BankAccountID.UUID.String()
+		if _, err := qtx.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             accountID,
+			ClinicID:       clinicID,
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return BankAccountChangeOutput{}, notFoundError("bank account not found")
+			}
+			return BankAccountChangeOutput{}, err
+		}
+		pending, err := qtx.CountPendingBankAccountRemovals(ctx, repository.CountPendingBankAccountRemovalsParams{
+			OrganizationID: organizationID(ctx),
+			BankAccountID:  accountID,
+		})
+		if err != nil {
+			return BankAccountChangeOutput{}, err
+		}
+		if pending > 0 {
+			return BankAccountChangeOutput{}, conflictError("bank account already has a pending removal")
+		}
+	}
+
+	change, err := qtx.CreateBankAccountChange(ctx, params)
+	if err != nil {
+		return BankAccountChangeOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     eventBankAccountChangeRequested,
+		EntityType: AuditEntityBankAccountChange,
+		EntityID:   change.ID,
+		Metadata:   map[string]any{"change_type": change.ChangeType, "requested_by_role": principal.Role},
+	}); err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return BankAccountChangeOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	s.publishBankAccountChange(ctx, eventBankAccountChangeRequested, change)
+	return mapBankAccountChange(change), nil
+}
+
+func (s *Service) ListBankAccountChanges(ctx context.Context, clinicID string, status *string) ([]BankAccountChangeOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListBankAccountChanges")
+	defer span.End()
+
+	statusFilter := sql.NullString{}
+	if status != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*status))
+		switch normalized {
+		case BankAccountChangePending, BankAccountChangeApproved, BankAccountChangeRejected:
+		default:
+			return nil, validationError(fmt.Sprintf("status must be one of: %s, %s, %s", BankAccountChangePending, BankAccountChangeApproved, BankAccountChangeRejected))
+		}
+		statusFilter = sql.NullString{String: normalized, Valid: true}
+	}
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+
+	rows, err := s.queries.ListBankAccountChanges(ctx, repository.ListBankAccountChangesParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		Status:         statusFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes := make([]BankAccountChangeOutput, 0, len(rows))
+	for _, row := range rows {
+		changes = append(changes, mapBankAccountChange(row))
+	}
+	return changes, nil
+}
+
+func (s *Service) ApproveBankAccountChange(ctx context.Context, clinicID string, changeID string, input ReviewBankAccountChangeInput) (BankAccountChangeOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ApproveBankAccountChange")
+	defer span.End()
+
+	return s.reviewBankAccountChange(ctx, clinicID, changeID, BankAccountChangeApproved, input)
+}
+
+func (s *Service) RejectBankAccountChange(ctx context.Context, clinicID string, changeID string, input ReviewBankAccountChangeInput) (BankAccountChangeOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RejectBankAccountChange")
+	defer span.End()
+
+	return s.reviewBankAccountChange(ctx, clinicID, changeID, BankAccountChangeRejected, input)
+}
+
+func (s *Service) reviewBankAccountChange(ctx context.Context, clinicID string, changeID string, decision string, input ReviewBankAccountChangeInput) (BankAccountChangeOutput, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return BankAccountChangeOutput{}, unauthorizedError("missing authenticated user")
+	}
+	if err := validateOptionalMaxLength("notes", input.Notes, maxBankAccountChangeTextLength); err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return BankAccountChangeOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountChangeOutput{}, notFoundError("clinic not found")
+		}
+		return BankAccountChangeOutput{}, mapDatabaseError(err)
+	}
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+	change, err := qtx.GetBankAccountChange(ctx, repository.GetBankAccountChangeParams{
+		OrganizationID: organizationID(ctx),
+		ID:             changeID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountChangeOutput{}, notFoundError("bank account change not found")
+		}
+		return BankAccountChangeOutput{}, err
+	}
+	if err := ensureBankAccountChangeReviewable(change, principal.UserID, decision); err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+
+	appliedAccountID := uuid.NullUUID{}
+	if decision == BankAccountChangeApproved {
+		if err := ensureNoFinancialHold(ctx, qtx, clinicID); err != nil {
+			return BankAccountChangeOutput{}, err
+		}
+		appliedAccountID, err = applyBankAccountChange(ctx, qtx, change)
+		if err != nil {
+			return BankAccountChangeOutput{}, err
+		}
+	}
+
+	updated, err := qtx.ReviewBankAccountChange(ctx, repository.ReviewBankAccountChangeParams{
+		OrganizationID:   organizationID(ctx),
+		ID:               changeID,
+		ClinicID:         clinicID,
+		Status:           decision,
+		BankAccountID:    appliedAccountID,
+		ReviewedByUserID: principal.UserID,
+		ReviewNotes:      optionalString(input.Notes),
+	})
+	if err != nil {
+		return BankAccountChangeOutput{}, mapDatabaseError(err)
+	}
+	if updated == 0 {
+		return BankAccountChangeOutput{}, conflictError("bank account change was reviewed concurrently")
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     eventBankAccountChangeReviewed,
+		EntityType: AuditEntityBankAccountChange,
+		EntityID:   changeID,
+		Metadata:   map[string]any{"decision": decision, "requested_by_user_id": change.RequestedByUserID},
+	}); err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+
+	reviewed, err := qtx.GetBankAccountChange(ctx, repository.GetBankAccountChangeParams{
+		OrganizationID: organizationID(ctx),
+		ID:             changeID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return BankAccountChangeOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return BankAccountChangeOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	s.publishBankAccountChange(ctx, eventBankAccountChangeReviewed, reviewed)
+	return mapBankAccountChange(reviewed), nil
+}
+
+func applyBankAccountChange(ctx context.Context, qtx repository.Querier, change repository.BankAccountChange) (uuid.NullUUID, error) {
+	switch change.ChangeType {
+	case BankAccountChangeAdd:
+		accountID, err := newUUIDV7()
+		if err != nil {
+			return uuid.NullUUID{}, err
+		}
+		// Approved additions never take over payouts on their own; promoting one is a separate, explicit step.
+		input := BankAccountInput{
+			BankCode:      change.BankCode.String,
+			BranchNumber:  change.BranchNumber.String,
+			AccountNumber: change.AccountNumber.String,
+			AccountType:   change.AccountType.String,
+			HolderName:    nullToPointer(change.HolderName),
+			HolderTaxID:   nullToPointer(change.HolderTaxID),
+		}
+		owner, err := qtx.GetClinicDetails(ctx, repository.GetClinicDetailsParams{
+			OrganizationID: organizationID(ctx),
+			ID:             change.ClinicID,
+		})
+		if err != nil {
+			return uuid.NullUUID{}, err
+		}
+		// Legal representatives are matched as of approval, not as of the request.
+		holder, err := resolveBankAccountHolder(ctx, qtx, owner, input)
+		if err != nil {
+			return uuid.NullUUID{}, err
+		}
+		account, err := qtx.CreateBankAccount(ctx, newBankAccountParams(organizationID(ctx), accountID, change.ClinicID, input, holder))
+		if err != nil {
+			return uuid.NullUUID{}, mapDatabaseError(err)
+		}
+		if err := recordAudit(ctx, qtx, auditEntry{
+			ClinicID:   change.ClinicID,
+			Action:     "bank_account.created",
+			EntityType: AuditEntityBankAccount,
+			EntityID:   accountID,
+			Metadata:   map[string]any{"bank_account_change_id": change.ID, "holder_review_required": account.HolderReviewRequired},
+		}); err != nil {
+			return uuid.NullUUID{}, err
+		}
+		return uuid.NullUUID{UUID: uuid.MustParse(accountID), Valid: true}, nil
+	case BankAccountChangeRemove:
+		accountID := change.BankAccountID.UUID.String()
+		accounts, err := qtx.ListBankAccountsByClinicID(ctx, repository.ListBankAccountsByClinicIDParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       change.ClinicID,
+		})
+		if err != nil {
+			return uuid.NullUUID{}, err
+		}
+		if err := ensureBankAccountRemovable(accounts, accountID); err != nil {
+			return uuid.NullUUID{}, err
+		}
+		if _, err := qtx.DeleteBankAccountByIDAndClinicID(ctx, repository.DeleteBankAccountByIDAndClinicIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             accountID,
+			ClinicID:       change.ClinicID,
+		}); err != nil {
+			return uuid.NullUUID{}, mapDatabaseError(err)
+		}
+		if err := recordAudit(ctx, qtx, auditEntry{
+			ClinicID:   change.ClinicID,
+			Action:     "bank_account.deleted",
+			EntityType: AuditEntityBankAccount,
+			EntityID:   accountID,
+			Metadata:   map[string]any{"bank_account_change_id": change.ID},
+		}); err != nil {
+			return uuid.NullUUID{}, err
+		}
+		return uuid.NullUUID{}, nil
+	}
+	return uuid.NullUUID{}, fmt.Errorf("unknown bank account change type %q", change.ChangeType)
+}
+
+func ensureBankAccountChangeReviewable(change repository.BankAccountChange, reviewerUserID string, decision string) error {
+	if change.Status != BankAccountChangePending {
+		return conflictError(fmt.Sprintf("bank account change is already %s", strings.ToLower(change.Status)))
+	}
+	// Requesters may withdraw their own change, but approving it takes a second pair of eyes.
+	if decision == BankAccountChangeApproved && change.RequestedByUserID == reviewerUserID {
+		return forbiddenError("bank account changes must be approved by a different admin than the requester")
+	}
+	return nil
+}
+
+func ensureDentistAdministersClinic(ctx context.Context, qtx repository.Querier, clinicID string, dentistID string) error {
+	link, err := qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return forbiddenError("only clinic admins can request bank account changes")
+		}
+		return err
+	}
+	if !link.IsAdmin {
+		return forbiddenError("only clinic admins can request bank account changes")
+	}
+	return nil
+}
+
+func parseBankAccountChangeInput(clinicID string, input RequestBankAccountChangeInput) (repository.CreateBankAccountChangeParams, error) {
+	params := repository.CreateBankAccountChangeParams{
+		ClinicID:   clinicID,
+		ChangeType: strings.ToUpper(strings.TrimSpace(input.ChangeType)),
+	}
+	if err := validateOptionalMaxLength("reason", input.Reason, maxBankAccountChangeTextLength); err != nil {
+		return repository.CreateBankAccountChangeParams{}, err
+	}
+	params.Reason = optionalString(input.Reason)
+
+	switch params.ChangeType {
+	case BankAccountChangeAdd:
+		if input.BankAccount == nil {
+			return repository.CreateBankAccountChangeParams{}, validationError("bank_account is required for ADD changes")
+		}
+		if input.BankAccountID != nil {
+			return repository.CreateBankAccountChangeParams{}, validationError("bank_account_id is only accepted for REMOVE changes")
+		}
+		if err := validateBankAccountInput(*input.BankAccount); err != nil {
+			return repository.CreateBankAccountChangeParams{}, validationError(fmt.Sprintf("bank_account: %s", err.Error()))
+		}
+		params.BankCode = sql.NullString{String: strings.TrimSpace(input.BankAccount.BankCode), Valid: true}
+		params.BranchNumber = sql.NullString{String: strings.TrimSpace(input.BankAccount.BranchNumber), Valid: true}
+		params.AccountNumber = sql.NullString{String: strings.TrimSpace(input.BankAccount.AccountNumber), Valid: true}
+		params.AccountType = sql.NullString{String: normalizeBankAccountType(input.BankAccount.AccountType), Valid: true}
+		params.HolderName = optionalString(input.BankAccount.HolderName)
+		if input.BankAccount.HolderTaxID != nil && strings.TrimSpace(*input.BankAccount.HolderTaxID) != "" {
+			taxID, _ := normalizeHolderTaxID(*input.BankAccount.HolderTaxID)
+			params.HolderTaxID = sql.NullString{String: taxID, Valid: true}
+		}
+	case BankAccountChangeRemove:
+		if input.BankAccount != nil {
+			return repository.CreateBankAccountChangeParams{}, validationError("bank_account is only accepted for ADD changes")
+		}
+		if input.BankAccountID == nil {
+			return repository.CreateBankAccountChangeParams{}, validationError("bank_account_id is required for REMOVE changes")
+		}
+		parsed, err := uuid.Parse(strings.TrimSpace(*input.BankAccountID))
+		if err != nil || parsed.Version() != 7 {
+			return repository.CreateBankAccountChangeParams{}, validationError("bank_account_id must be a UUIDv7")
+		}
+		params.BankAccountID = uuid.NullUUID{UUID: parsed, Valid: true}
+	default:
+		return repository.CreateBankAccountChangeParams{}, validationError(fmt.Sprintf("change_type must be one of: %s, %s", BankAccountChangeAdd, BankAccountChangeRemove))
+	}
+	return params, nil
+}
+
+func (s *Service) publishBankAccountChange(ctx context.Context, eventType string, change repository.BankAccountChange) {
+	if !s.webhooksEnabled(ctx) {
+		return
+	}
+	event, err := s.newEvent(eventType, map[string]string{
+		"clinic_id":   change.ClinicID,
+		"change_id":   change.ID,
+		"change_type": change.ChangeType,
+		"status":      change.Status,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, event)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "publish bank account change event failed", "change_id", change.ID, "error", err)
+	}
+}
+
+func mapBankAccountChange(change repository.BankAccountChange) BankAccountChangeOutput {
+	return BankAccountChangeOutput{
+		ID:                change.ID,
+		ClinicID:          change.ClinicID,
+		ChangeType:        change.ChangeType,
+		Status:            change.Status,
+		BankAccountID:     nullUUIDToPointer(change.BankAccountID),
+		BankCode:          nullToPointer(change.BankCode),
+		BranchNumber:      nullToPointer(change.BranchNumber),
+		AccountNumber:     maskedAccountNumberPointer(change.AccountNumber),
+		AccountType:       nullToPointer(change.AccountType),
+		HolderName:        nullToPointer(change.HolderName),
+		HolderTaxID:       nullToPointer(change.HolderTaxID),
+		Reason:            nullToPointer(change.Reason),
+		RequestedByUserID: change.RequestedByUserID,
+		RequestedAt:       change.CreatedAt,
+		ReviewedByUserID:  nullUUIDToPointer(change.ReviewedByUserID),
+		ReviewedAt:        nullTimeToPointer(change.ReviewedAt),
+		ReviewNotes:       nullToPointer(change.ReviewNotes),
+	}
+}
+
+func maskedAccountNumberPointer(accountNumber sql.NullString) *string {
+	if !accountNumber.Valid {
+		return nil
+	}
+	masked := maskAccountNumber(accountNumber.String)
+	return &masked
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	BankAccountTypeChecking = "CHECKING"
+	BankAccountTypeSavings  = "SAVINGS"
+	BankAccountTypePayment  = "PAYMENT"
+
+	maxHolderNameLength = 255
+)
+
+type bankAccountHolder struct {
+	AccountType    string
+	Name           string
+	TaxID          string
+	ReviewRequired bool
+}
+
+func (s *Service) ApproveBankAccountHolder(ctx context.Context, clinicID string, accountID string) (BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ApproveBankAccountHolder")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return BankAccountOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	account, err := qtx.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountOutput{}, notFoundError("bank account not found")
+		}
+		return BankAccountOutput{}, err
+	}
+	if err := ensureNoFinancialHold(ctx, qtx, clinicID); err != nil {
+		return BankAccountOutput{}, err
+	}
+	updated, err := qtx.ApproveBankAccountHolder(ctx, repository.ApproveBankAccountHolderParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return BankAccountOutput{}, mapDatabaseError(err)
+	}
+	if updated == 0 {
+		return BankAccountOutput{}, conflictError("bank account holder is not pending review")
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "bank_account.holder_approved",
+		EntityType: AuditEntityBankAccount,
+		EntityID:   accountID,
+		Metadata:   map[string]any{"holder_tax_id": account.HolderTaxID.String},
+	}); err != nil {
+		return BankAccountOutput{}, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return BankAccountOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return BankAccountOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.GetClinicBankAccount(ctx, clinicID, accountID)
+}
+
+// Accounts registered without a holder are assumed to belong to the clinic itself.
+func resolveBankAccountHolder(ctx context.Context, q repository.Querier, clinic repository.GetClinicDetailsRow, input BankAccountInput) (bankAccountHolder, error) {
+	holder := bankAccountHolder{
+		AccountType: normalizeBankAccountType(input.AccountType),
+		Name:        clinic.LegalName,
+		TaxID:       clinic.TaxIDNumber,
+	}
+	if input.HolderTaxID == nil || strings.TrimSpace(*input.HolderTaxID) == "" {
+		return holder, nil
+	}
+	holder.Name = strings.TrimSpace(*input.HolderName)
+	holder.TaxID, _ = normalizeHolderTaxID(*input.HolderTaxID)
+	if holder.TaxID == clinic.TaxIDNumber {
+		return holder, nil
+	}
+
+	// Anyone other than the clinic or one of its current legal representatives is accepted, but held for review.
+	matchesRepresentative := false
+	if len(holder.TaxID) == 11 {
+		var err error
+		matchesRepresentative, err = q.IsClinicLegalRepresentativeTaxID(ctx, repository.IsClinicLegalRepresentativeTaxIDParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinic.ClinicID,
+			TaxIDNumber:    holder.TaxID,
+		})
+		if err != nil {
+			return bankAccountHolder{}, err
+		}
+	}
+	holder.ReviewRequired = !matchesRepresentative
+	return holder, nil
+}
+
+func newBankAccountParams(organizationID string, accountID string, clinicID string, input BankAccountInput, holder bankAccountHolder) repository.CreateBankAccountParams {
+	return repository.CreateBankAccountParams{
+		OrganizationID:       organizationID,
+		ID:                   accountID,
+		ClinicID:             clinicID,
+		BankCode:             strings.TrimSpace(input.BankCode),
+		BranchNumber:         strings.TrimSpace(input.BranchNumber),
+		AccountNumber:        strings.TrimSpace(input.AccountNumber),
+		AccountType:          holder.AccountType,
+		HolderName:           sql.NullString{String: holder.Name, Valid: true},
+		HolderTaxID:          sql.NullString{String: holder.TaxID, Valid: true},
+		HolderReviewRequired: holder.ReviewRequired,
+		IsPrimary:            input.IsPrimary,
+	}
+}
+
+func validateBankAccountHolderInput(input BankAccountInput) error {
+	switch normalizeBankAccountType(input.AccountType) {
+	case BankAccountTypeChecking, BankAccountTypeSavings, BankAccountTypePayment:
+	default:
+		return fmt.Errorf("account_type must be one of: %s, %s, %s", BankAccountTypeChecking, BankAccountTypeSavings, BankAccountTypePayment)
+	}
+
+	hasName := input.HolderName != nil && strings.TrimSpace(*input.HolderName) != ""
+	hasTaxID := input.HolderTaxID != nil && strings.TrimSpace(*input.HolderTaxID) != ""
+	if hasName != hasTaxID {
+		return fmt.Errorf("holder_name and holder_tax_id must be provided together")
+	}
+	if !hasTaxID {
+		return nil
+	}
+	if countTrimmedCharacters(*input.HolderName) > maxHolderNameLength {
+		return fmt.Errorf("holder_name must be at most %d characters", maxHolderNameLength)
+	}
+	if _, ok := normalizeHolderTaxID(*input.HolderTaxID); !ok {
+		return fmt.Errorf("holder_tax_id must be a valid CPF or CNPJ")
+	}
+	return nil
+}
+
+func normalizeBankAccountType(value string) string {
+	accountType := strings.ToUpper(strings.TrimSpace(value))
+	if accountType == "" {
+		return BankAccountTypeChecking
+	}
+	return accountType
+}
+
+func normalizeHolderTaxID(value string) (string, bool) {
+	cnpj := validation.NormalizeCNPJ(value)
+	if len(cnpj) == 11 {
+		cpf := validation.NormalizeCPF(cnpj)
+		return cpf, len(cpf) == 11 && validation.ValidateCPF(cpf)
+	}
+	return cnpj, validation.ValidateCNPJ(cnpj)
+}
@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	BankVerificationUnverified = "UNVERIFIED"
+	BankVerificationPending    = "PENDING"
+	BankVerificationVerified   = "VERIFIED"
+
+	BankVerificationResultVerified = "VERIFIED"
+	BankVerificationResultFailed   = "FAILED"
+
+	callbackSignaturePrefix = "sha256="
+
+	eventBankAccountVerified           = "bank_account.verified"
+	eventBankAccountVerificationFailed = "bank_account.verification_failed"
+)
+
+type BankAccountVerificationRequest struct {
+	BankAccountID string
+	ClinicID      string
+	HolderTaxID   string
+	BankCode      string
+	BranchNumber  string
+	AccountNumber string
+}
+
+type BankAccountVerificationStart struct {
+	Provider  string
+	Reference string
+}
+
+// Providers (micro-deposits, open finance consent, ...) confirm ownership asynchronously through the callback.
+type BankAccountVerifier interface {
+	StartVerification(ctx context.Context, request BankAccountVerificationRequest) (BankAccountVerificationStart, error)
+}
+
+func WithBankAccountVerifier(verifier BankAccountVerifier, callbackSecret string) Option {
+	return func(s *Service) {
+		s.bankVerifier = verifier
+		s.bankCallbackSecret = []byte(strings.TrimSpace(callbackSecret))
+	}
+}
+
+func (s *Service) StartBankAccountVerification(ctx context.Context, clinicID string, accountID string) (BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.StartBankAccountVerification")
+	defer span.End()
+
+	if s.bankVerifier == nil {
+		return BankAccountOutput{}, conflictError("bank account verification is not configured")
+	}
+	account, err := s.queries.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountOutput{}, notFoundError("bank account not found")
+		}
+		return BankAccountOutput{}, err
+	}
+	if account.VerificationStatus == BankVerificationVerified {
+		return BankAccountOutput{}, conflictError("bank account is already verified")
+	}
+	clinic, err := s.queries.GetClinicDetails(ctx, repository.GetClinicDetailsParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountOutput{}, notFoundError("clinic not found")
+		}
+		return BankAccountOutput{}, err
+	}
+
+	holderTaxID := clinic.TaxIDNumber
+	if account.HolderTaxID.Valid {
+		holderTaxID = account.HolderTaxID.String
+	}
+
+	// Restarting a pending verification replaces its reference, so late callbacks for the old attempt are ignored.
+	started, err := s.bankVerifier.StartVerification(ctx, BankAccountVerificationRequest{
+		BankAccountID: account.ID,
+		ClinicID:      clinicID,
+		HolderTaxID:   holderTaxID,
+		BankCode:      account.BankCode,
+		BranchNumber:  account.BranchNumber,
+		AccountNumber: account.AccountNumber,
+	})
+	if err != nil {
+		return BankAccountOutput{}, fmt.Errorf("start bank account verification: %w", err)
+	}
+	if strings.TrimSpace(started.Reference) == "" {
+		return BankAccountOutput{}, errors.New("start bank account verification: provider returned an empty reference")
+	}
+
+	revisions, err := loadClinicRevisionSnapshot(ctx, s.queries, clinicID)
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	updated, err := s.queries.StartBankAccountVerification(ctx, repository.StartBankAccountVerificationParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+		Provider:       started.Provider,
+		Reference:      strings.TrimSpace(started.Reference),
+	})
+	if err != nil {
+		return BankAccountOutput{}, mapDatabaseError(err)
+	}
+	if updated == 0 {
+		return BankAccountOutput{}, conflictError("bank account changed during verification; retry")
+	}
+	if err := recordAudit(ctx, s.queries, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "bank_account.verification_started",
+		EntityType: AuditEntityBankAccount,
+		EntityID:   accountID,
+		Metadata:   map[string]any{"provider": started.Provider},
+	}); err != nil {
+		return BankAccountOutput{}, err
+	}
+	if err := recordClinicRevisions(ctx, s.queries, revisions); err != nil {
+		return BankAccountOutput{}, err
+	}
+	return s.GetClinicBankAccount(ctx, clinicID, accountID)
+}
+
+func (s *Service) VerifyBankAccountCallbackSignature(body []byte, signature string) error {
+	// Without a shared secret there is no way to tell the provider from anyone else, so every callback is refused.
+	if len(s.bankCallbackSecret) == 0 {
+		return unauthorizedError("bank account verification callbacks are not configured")
+	}
+	return verifyCallbackSignature(s.bankCallbackSecret, body, signature)
+}
+
+// verifyCallbackSignature checks a "sha256=<hex>" HMAC of the raw body, as providers send it in the callback signature header.
+func verifyCallbackSignature(secret []byte, body []byte, signature string) error {
+	received, err := hex.DecodeString(strings.TrimPrefix(strings.TrimSpace(signature), callbackSignaturePrefix))
+	if err != nil || len(received) == 0 {
+		return unauthorizedError("invalid callback signature")
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	if !hmac.Equal(received, mac.Sum(nil)) {
+		return unauthorizedError("invalid callback signature")
+	}
+	return nil
+}
+
+func (s *Service) CompleteBankAccountVerification(ctx context.Context, input BankAccountVerificationCallbackInput) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CompleteBankAccountVerification")
+	defer span.End()
+
+	reference := strings.TrimSpace(input.Reference)
+	if reference == "" {
+		return validationError("reference is required")
+	}
+	result := strings.ToUpper(strings.TrimSpace(input.Status))
+	if result != BankVerificationResultVerified && result != BankVerificationResultFailed {
+		return validationError(fmt.Sprintf("status must be one of: %s, %s", BankVerificationResultVerified, BankVerificationResultFailed))
+	}
+
+	account, err := findInTenantSchemas(ctx, s, func(ctx context.Context) (repository.BankAccount, error) {
+		return s.queries.GetBankAccountByVerificationReference(ctx, reference)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("verification not found")
+		}
+		return err
+	}
+	// The callback is unauthenticated; the reference alone identifies the tenant.
+	ctx = WithOrganization(ctx, account.OrganizationID)
+	// Providers retry callbacks; once the attempt is settled, repeating it is a no-op.
+	if account.VerificationStatus != BankVerificationPending {
+		return nil
+	}
+
+	revisions, err := loadClinicRevisionSnapshot(ctx, s.queries, account.ClinicID)
+	if err != nil {
+		return err
+	}
+	var updated int64
+	eventType := eventBankAccountVerified
+	if result == BankVerificationResultVerified {
+		updated, err = s.queries.MarkBankAccountVerified(ctx, repository.MarkBankAccountVerifiedParams{
+			OrganizationID: organizationID(ctx),
+			Reference:      reference,
+		})
+	} else {
+		eventType = eventBankAccountVerificationFailed
+		updated, err = s.queries.MarkBankAccountVerificationFailed(ctx, repository.MarkBankAccountVerificationFailedParams{
+			OrganizationID: organizationID(ctx),
+			Reference:      reference,
+			Reason:         optionalString(input.Reason),
+		})
+	}
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if updated == 0 {
+		return nil
+	}
+	metadata := map[string]any{"reference": reference}
+	if reason := strings.TrimSpace(derefString(input.Reason)); result == BankVerificationResultFailed && reason != "" {
+		metadata["reason"] = reason
+	}
+	// Callbacks come from the provider, so these rows have no actor.
+	if err := recordAudit(ctx, s.queries, auditEntry{
+		ClinicID:   account.ClinicID,
+		Action:     eventType,
+		EntityType: AuditEntityBankAccount,
+		EntityID:   account.ID,
+		Metadata:   metadata,
+	}); err != nil {
+		return err
+	}
+	if err := recordClinicRevisions(ctx, s.queries, revisions); err != nil {
+		return err
+	}
+	s.publishBankAccountVerification(ctx, eventType, account)
+	return nil
+}
+
+func (s *Service) GetClinicPayoutAccount(ctx context.Context, clinicID string) (BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicPayoutAccount")
+	defer span.End()
+
+	accounts, err := s.ListClinicBankAccounts(ctx, clinicID)
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	return payoutAccount(accounts)
+}
+
+func payoutAccount(accounts []BankAccountOutput) (BankAccountOutput, error) {
+	for _, account := range accounts {
+		if !account.IsPrimary {
+			continue
+		}
+		if account.VerificationStatus != BankVerificationVerified {
+			return BankAccountOutput{}, conflictError("primary bank account must be verified before receiving payouts")
+		}
+		return account, nil
+	}
+	return BankAccountOutput{}, conflictError("clinic has no primary bank account")
+}
+
+func (s *Service) publishBankAccountVerification(ctx context.Context, eventType string, account repository.BankAccount) {
+	if !s.webhooksEnabled(ctx) {
+		return
+	}
+	event, err := s.newEvent(eventType, map[string]string{
+		"clinic_id":       account.ClinicID,
+		"bank_account_id": account.ID,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, event)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "publish bank account verification event failed", "bank_account_id", account.ID, "error", err)
+	}
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// loadBatch resolves the keys of a list page with a single fetch. Concurrent requests asking the same lookup for the same keys
+// share one query, so the maps it returns are shared too and must be treated as read-only.
+func loadBatch[V any](ctx context.Context, s *Service, lookup string, keys []string, fetch func(ctx context.Context, keys []string) (map[string]V, error)) (map[string]V, error) {
+	keys = slices.Compact(slices.Sorted(slices.Values(keys)))
+	if len(keys) == 0 {
+		return make(map[string]V), nil
+	}
+	flight := lookup + "|" + organizationID(ctx) + "|" + strings.Join(keys, ",")
+	loaded, err, _ := s.batches.Do(flight, func() (any, error) {
+		// A caller that gives up early must not fail the others waiting on the same query.
+		detached, cancel := detachContext(ctx)
+		defer cancel()
+		return fetch(detached, keys)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return loaded.(map[string]V), nil
+}
+
+// detachContext keeps ctx's values and deadline but not its cancellation, so a shared query still ends when the request that
+// started it would have timed out.
+func detachContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	detached := context.WithoutCancel(ctx)
+	if deadline, ok := ctx.Deadline(); ok {
+		return context.WithDeadline(detached, deadline)
+	}
+	return detached, func() {}
+}
+
+// loadInParallel runs the independent lookups of a list page at the same time, each on its own connection.
+func loadInParallel(ctx context.Context, loads ...func(ctx context.Context) error) error {
+	group, ctx := errgroup.WithContext(ctx)
+	for _, load := range loads {
+		group.Go(func() error {
+			return load(ctx)
+		})
+	}
+	return group.Wait()
+}
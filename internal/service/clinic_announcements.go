@@ -0,0 +1,410 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	AuditEntityClinicAnnouncement = "CLINIC_ANNOUNCEMENT"
+
+	maxAnnouncementTitleLength = 120
+	maxAnnouncementBodyLength  = 2000
+)
+
+// defaultNotificationChannels reaches dentists who never chose their channels.
+var defaultNotificationChannels = []string{NotificationChannelEmail}
+
+type clinicAnnouncementData struct {
+	ClinicName  string
+	DentistName string
+	Title       string
+	Body        string
+}
+
+// CreateClinicAnnouncement records a message for every dentist active at the clinic and queues it on each one's preferred channels.
+// The recipient rows are what read receipts are kept on, so a dentist without a usable contact still sees it in their inbox.
+func (s *Service) CreateClinicAnnouncement(ctx context.Context, clinicID string, input CreateClinicAnnouncementInput) (ClinicAnnouncementOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinicAnnouncement")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return ClinicAnnouncementOutput{}, unauthorizedError("missing authenticated user")
+	}
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return ClinicAnnouncementOutput{}, validationError("title is required")
+	}
+	if strings.ContainsAny(title, "\r\n") {
+		return ClinicAnnouncementOutput{}, validationError("title must be a single line")
+	}
+	if err := validateMaxLength("title", title, maxAnnouncementTitleLength); err != nil {
+		return ClinicAnnouncementOutput{}, err
+	}
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return ClinicAnnouncementOutput{}, validationError("body is required")
+	}
+	if err := validateMaxLength("body", body, maxAnnouncementBodyLength); err != nil {
+		return ClinicAnnouncementOutput{}, err
+	}
+	announcementID, err := newUUIDV7()
+	if err != nil {
+		return ClinicAnnouncementOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicAnnouncementOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	clinic, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicAnnouncementOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicAnnouncementOutput{}, err
+	}
+	person, err := qtx.GetPersonIncludingDeleted(ctx, repository.GetPersonIncludingDeletedParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinic.PersonID,
+	})
+	if err != nil {
+		return ClinicAnnouncementOutput{}, err
+	}
+	clinicName := person.LegalName
+	if person.TradeName.Valid && strings.TrimSpace(person.TradeName.String) != "" {
+		clinicName = person.TradeName.String
+	}
+	audience, err := qtx.ListClinicAnnouncementAudience(ctx, repository.ListClinicAnnouncementAudienceParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return ClinicAnnouncementOutput{}, err
+	}
+	if len(audience) == 0 {
+		return ClinicAnnouncementOutput{}, validationError("clinic has no active dentists to announce to")
+	}
+
+	if _, err := qtx.CreateClinicAnnouncement(ctx, repository.CreateClinicAnnouncementParams{
+		ID:             announcementID,
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		AuthorUserID:   principal.UserID,
+		Title:          title,
+		Body:           body,
+	}); err != nil {
+		return ClinicAnnouncementOutput{}, mapDatabaseError(err)
+	}
+	dentistIDs := make([]string, 0, len(audience))
+	for _, member := range audience {
+		dentistIDs = append(dentistIDs, member.DentistID)
+	}
+	if err := qtx.CreateClinicAnnouncementRecipients(ctx, repository.CreateClinicAnnouncementRecipientsParams{
+		OrganizationID: organizationID(ctx),
+		AnnouncementID: announcementID,
+		DentistIds:     dentistIDs,
+	}); err != nil {
+		return ClinicAnnouncementOutput{}, mapDatabaseError(err)
+	}
+	for _, member := range audience {
+		data := clinicAnnouncementData{ClinicName: clinicName, DentistName: member.LegalName, Title: title, Body: body}
+		if err := s.enqueueAnnouncement(ctx, qtx, announcementID, clinicID, member, data); err != nil {
+			return ClinicAnnouncementOutput{}, err
+		}
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "clinic_announcement.created",
+		EntityType: AuditEntityClinicAnnouncement,
+		EntityID:   announcementID,
+		Metadata:   map[string]any{"recipient_count": len(audience)},
+	}); err != nil {
+		return ClinicAnnouncementOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicAnnouncementOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.GetClinicAnnouncement(ctx, clinicID, announcementID)
+}
+
+func (s *Service) enqueueAnnouncement(ctx context.Context, qtx repository.Querier, announcementID string, clinicID string, member repository.ListClinicAnnouncementAudienceRow, data clinicAnnouncementData) error {
+	channels := member.PreferredChannels
+	if len(channels) == 0 {
+		channels = defaultNotificationChannels
+	}
+	for _, channel := range channels {
+		contact := ""
+		switch channel {
+		case NotificationChannelEmail:
+			contact = strings.TrimSpace(member.Email.String)
+		case NotificationChannelSMS, NotificationChannelWhatsApp:
+			contact, _ = validation.NormalizePhone(member.Phone.String)
+		}
+		if contact == "" {
+			continue
+		}
+		if err := s.enqueueNotification(ctx, qtx, notificationRequest{
+			Channel:   channel,
+			Template:  NotificationTemplateClinicAnnouncement,
+			Recipient: contact,
+			ClinicID:  clinicID,
+			DedupeKey: NotificationTemplateClinicAnnouncement + ":" + announcementID + ":" + member.DentistID + ":" + channel,
+			Data:      data,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) GetClinicAnnouncement(ctx context.Context, clinicID string, announcementID string) (ClinicAnnouncementOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicAnnouncement")
+	defer span.End()
+
+	row, err := s.queries.GetClinicAnnouncement(ctx, repository.GetClinicAnnouncementParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             announcementID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicAnnouncementOutput{}, notFoundError("announcement not found")
+		}
+		return ClinicAnnouncementOutput{}, err
+	}
+	return mapClinicAnnouncement(repository.ListClinicAnnouncementsCursorRow(row)), nil
+}
+
+func (s *Service) ListClinicAnnouncements(ctx context.Context, clinicID string, limit int, cursor *string) ([]ClinicAnnouncementOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicAnnouncements")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, notFoundError("clinic not found")
+		}
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListClinicAnnouncementsCursorParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+
+	rows, err := s.queries.ListClinicAnnouncementsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	announcements := make([]ClinicAnnouncementOutput, 0, len(rows))
+	for _, row := range rows {
+		announcements = append(announcements, mapClinicAnnouncement(row))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return announcements, nextCursor, nil
+}
+
+func (s *Service) ListClinicAnnouncementReceipts(ctx context.Context, clinicID string, announcementID string) ([]AnnouncementReceiptOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicAnnouncementReceipts")
+	defer span.End()
+
+	if _, err := s.GetClinicAnnouncement(ctx, clinicID, announcementID); err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListClinicAnnouncementReceipts(ctx, repository.ListClinicAnnouncementReceiptsParams{
+		OrganizationID: organizationID(ctx),
+		AnnouncementID: announcementID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	receipts := make([]AnnouncementReceiptOutput, 0, len(rows))
+	for _, row := range rows {
+		receipts = append(receipts, AnnouncementReceiptOutput{
+			DentistID: row.DentistID,
+			LegalName: row.LegalName,
+			ReadAt:    nullTimeToPointer(row.ReadAt),
+		})
+	}
+	return receipts, nil
+}
+
+// ListDentistAnnouncements is the dentist's inbox, newest first, optionally only what they have not read yet.
+func (s *Service) ListDentistAnnouncements(ctx context.Context, dentistID string, limit int, cursor *string, unread *bool) ([]DentistAnnouncementOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListDentistAnnouncements")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListDentistAnnouncementsCursorParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+	if unread != nil {
+		params.Unread = sql.NullBool{Bool: *unread, Valid: true}
+	}
+
+	rows, err := s.queries.ListDentistAnnouncementsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	announcements := make([]DentistAnnouncementOutput, 0, len(rows))
+	for _, row := range rows {
+		announcements = append(announcements, DentistAnnouncementOutput{
+			ID:        row.ID,
+			ClinicID:  row.ClinicID,
+			Title:     row.Title,
+			Body:      row.Body,
+			CreatedAt: row.CreatedAt,
+			ReadAt:    nullTimeToPointer(row.ReadAt),
+		})
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return announcements, nextCursor, nil
+}
+
+// MarkAnnouncementRead records the receipt; reading again keeps the first time.
+func (s *Service) MarkAnnouncementRead(ctx context.Context, dentistID string, announcementID string) (DentistAnnouncementOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.MarkAnnouncementRead")
+	defer span.End()
+
+	readAt, err := s.queries.MarkClinicAnnouncementRead(ctx, repository.MarkClinicAnnouncementReadParams{
+		OrganizationID: organizationID(ctx),
+		AnnouncementID: announcementID,
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DentistAnnouncementOutput{}, notFoundError("announcement not found")
+		}
+		return DentistAnnouncementOutput{}, err
+	}
+	announcement, err := s.queries.GetClinicAnnouncementByID(ctx, repository.GetClinicAnnouncementByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             announcementID,
+	})
+	if err != nil {
+		return DentistAnnouncementOutput{}, err
+	}
+	return DentistAnnouncementOutput{
+		ID:        announcement.ID,
+		ClinicID:  announcement.ClinicID,
+		Title:     announcement.Title,
+		Body:      announcement.Body,
+		CreatedAt: announcement.CreatedAt,
+		ReadAt:    nullTimeToPointer(readAt),
+	}, nil
+}
+
+func (s *Service) GetNotificationPreferences(ctx context.Context, dentistID string) (NotificationPreferencesOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetNotificationPreferences")
+	defer span.End()
+
+	preferences, err := s.queries.GetDentistNotificationPreferences(ctx, repository.GetDentistNotificationPreferencesParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return NotificationPreferencesOutput{Channels: defaultNotificationChannels}, nil
+		}
+		return NotificationPreferencesOutput{}, err
+	}
+	return NotificationPreferencesOutput{Channels: preferences.Channels, UpdatedAt: &preferences.UpdatedAt}, nil
+}
+
+func (s *Service) UpdateNotificationPreferences(ctx context.Context, dentistID string, input NotificationPreferencesInput) (NotificationPreferencesOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateNotificationPreferences")
+	defer span.End()
+
+	if len(input.Channels) == 0 {
+		return NotificationPreferencesOutput{}, validationError("channels must list at least one channel")
+	}
+	channels := make([]string, 0, len(input.Channels))
+	for idx, channel := range input.Channels {
+		normalized := strings.ToUpper(strings.TrimSpace(channel))
+		switch normalized {
+		case NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWhatsApp:
+		default:
+			return NotificationPreferencesOutput{}, validationError(fmt.Sprintf("channels[%d] must be one of: %s, %s, %s", idx, NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWhatsApp))
+		}
+		if !slices.Contains(channels, normalized) {
+			channels = append(channels, normalized)
+		}
+	}
+
+	preferences, err := s.queries.UpsertDentistNotificationPreferences(ctx, repository.UpsertDentistNotificationPreferencesParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+		Channels:       channels,
+	})
+	if err != nil {
+		return NotificationPreferencesOutput{}, mapDatabaseError(err)
+	}
+	return NotificationPreferencesOutput{Channels: preferences.Channels, UpdatedAt: &preferences.UpdatedAt}, nil
+}
+
+func mapClinicAnnouncement(row repository.ListClinicAnnouncementsCursorRow) ClinicAnnouncementOutput {
+	return ClinicAnnouncementOutput{
+		ID:             row.ID,
+		ClinicID:       row.ClinicID,
+		AuthorUserID:   row.AuthorUserID,
+		Title:          row.Title,
+		Body:           row.Body,
+		RecipientCount: row.RecipientCount,
+		ReadCount:      row.ReadCount,
+		CreatedAt:      row.CreatedAt,
+	}
+}
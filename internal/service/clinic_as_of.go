@@ -0,0 +1,202 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+type clinicAsOfEntity struct {
+	entityType string
+	entityID   string
+	state      json.RawMessage
+	resolved   bool
+}
+
+type bankAccountAsOf struct {
+	id    string
+	state bankAccountRevisionState
+}
+
+// Each entity takes the state written by its last revision up to asOf or, when every revision is later, the state the
+// first of them started from. Entities without revisions have not changed since they were created.
+func (s *Service) GetClinicAsOf(ctx context.Context, clinicID string, asOf time.Time, includeDeleted bool) (ClinicDetailsOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicAsOf")
+	defer span.End()
+
+	if asOf.After(s.now()) {
+		return ClinicDetailsOutput{}, validationError("as_of cannot be in the future")
+	}
+
+	current, err := loadClinicRevisionSnapshot(ctx, s.queries, clinicID)
+	if err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	if len(current.keys) == 0 {
+		return ClinicDetailsOutput{}, notFoundError("clinic not found")
+	}
+	revisions, err := s.queries.ListClinicRevisionsByClinicID(ctx, repository.ListClinicRevisionsByClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+
+	entities := make(map[string]*clinicAsOfEntity, len(current.keys))
+	order := make([]string, 0, len(current.keys))
+	track := func(entityType string, entityID string) *clinicAsOfEntity {
+		key := entityType + ":" + entityID
+		entity, ok := entities[key]
+		if !ok {
+			entity = &clinicAsOfEntity{entityType: entityType, entityID: entityID}
+			entities[key] = entity
+			order = append(order, key)
+		}
+		return entity
+	}
+	for _, key := range current.keys {
+		state := current.states[key]
+		track(state.entityType, state.entityID)
+	}
+	for _, revision := range revisions {
+		entity := track(revision.EntityType, revision.EntityID)
+		if !revision.ChangedAt.After(asOf) {
+			entity.state, entity.resolved = revision.AfterState, true
+		} else if !entity.resolved {
+			entity.state, entity.resolved = revision.BeforeState, true
+		}
+	}
+	for key, entity := range entities {
+		if entity.resolved {
+			continue
+		}
+		if state, ok := current.states[key]; ok && !state.createdAt.After(asOf) {
+			entity.state = state.state
+		}
+	}
+
+	var (
+		clinic       clinicRevisionState
+		person       personRevisionState
+		clinicFound  bool
+		personFound  bool
+		bankAccounts []bankAccountAsOf
+	)
+	for _, key := range order {
+		entity := entities[key]
+		if len(entity.state) == 0 || bytes.Equal(entity.state, []byte("null")) {
+			continue
+		}
+		switch entity.entityType {
+		case RevisionEntityClinic:
+			if err := json.Unmarshal(entity.state, &clinic); err != nil {
+				return ClinicDetailsOutput{}, fmt.Errorf("decode clinic revision: %w", err)
+			}
+			clinicFound = true
+		case RevisionEntityPerson:
+			if err := json.Unmarshal(entity.state, &person); err != nil {
+				return ClinicDetailsOutput{}, fmt.Errorf("decode person revision: %w", err)
+			}
+			personFound = true
+		case RevisionEntityBankAccount:
+			var account bankAccountRevisionState
+			if err := json.Unmarshal(entity.state, &account); err != nil {
+				return ClinicDetailsOutput{}, fmt.Errorf("decode bank account revision: %w", err)
+			}
+			bankAccounts = append(bankAccounts, bankAccountAsOf{id: entity.entityID, state: account})
+		}
+	}
+	if !clinicFound || !personFound {
+		return ClinicDetailsOutput{}, notFoundError("clinic did not exist at as_of")
+	}
+	if clinic.DeletedAt != nil && !includeDeleted {
+		return ClinicDetailsOutput{}, notFoundError("clinic was deleted at as_of")
+	}
+
+	var dentistIDs []string
+	if clinic.DeletedAt == nil {
+		dentistIDs, err = s.queries.ListClinicDentistIDsAsOf(ctx, repository.ListClinicDentistIDsAsOfParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+			AsOf:           asOf,
+		})
+		if err != nil {
+			return ClinicDetailsOutput{}, err
+		}
+	}
+
+	output := ClinicDetailsOutput{
+		ClinicOutput: mapClinicSummary(
+			clinicID,
+			clinic.PersonID,
+			person.LegalName,
+			optionalString(person.TradeName),
+			person.TaxIDNumber,
+			optionalString(person.Email),
+			optionalString(person.Phone),
+			clinic.Timezone,
+			dentistIDs,
+		),
+		BankAccounts: mapBankAccountsAsOf(bankAccounts, clinic.DeletedAt),
+		AsOf:         &asOf,
+	}
+	output.ParentClinicID = clinic.ParentClinicID
+	output.OnboardingStatus = clinic.OnboardingStatus
+	output.OnboardingStatusChangedAt = clinic.OnboardingStatusChangedAt
+	output.Status = clinicStatus(sql.NullTime{Valid: clinic.DeactivatedAt != nil})
+	output.DeactivatedAt = clinic.DeactivatedAt
+	output.DeactivationReason = clinic.DeactivationReason
+	output.DeletedAt = clinic.DeletedAt
+	if person.Address != nil {
+		output.Address = &AddressOutput{
+			Street:     person.Address.Street,
+			Number:     person.Address.Number,
+			Complement: person.Address.Complement,
+			City:       person.Address.City,
+			State:      person.Address.State,
+			CEP:        person.Address.CEP,
+		}
+	}
+	return output, nil
+}
+
+// As in the live read, a deleted clinic keeps the accounts removed together with it.
+func mapBankAccountsAsOf(accounts []bankAccountAsOf, clinicDeletedAt *time.Time) []BankAccountOutput {
+	sort.SliceStable(accounts, func(i, j int) bool {
+		if accounts[i].state.IsPrimary != accounts[j].state.IsPrimary {
+			return accounts[i].state.IsPrimary
+		}
+		return accounts[i].id > accounts[j].id
+	})
+
+	output := make([]BankAccountOutput, 0, len(accounts))
+	for _, account := range accounts {
+		if account.state.DeletedAt != nil && (clinicDeletedAt == nil || !account.state.DeletedAt.Equal(*clinicDeletedAt)) {
+			continue
+		}
+		output = append(output, BankAccountOutput{
+			ID:                        account.id,
+			BankCode:                  account.state.BankCode,
+			BranchNumber:              account.state.BranchNumber,
+			AccountNumber:             account.state.AccountNumber,
+			AccountType:               account.state.AccountType,
+			HolderName:                account.state.HolderName,
+			HolderTaxID:               account.state.HolderTaxID,
+			HolderReviewRequired:      account.state.HolderReviewRequired,
+			IsPrimary:                 account.state.IsPrimary,
+			VerificationStatus:        account.state.VerificationStatus,
+			VerifiedAt:                account.state.VerifiedAt,
+			VerificationFailureReason: account.state.VerificationFailureReason,
+		})
+	}
+	return output
+}
@@ -0,0 +1,597 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+func (s *Service) ListClinicBankAccounts(ctx context.Context, clinicID string) ([]BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicBankAccounts")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+	accounts, err := s.queries.ListBankAccountsByClinicID(ctx, repository.ListBankAccountsByClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapBankAccounts(accounts), nil
+}
+
+func (s *Service) GetClinicBankAccount(ctx context.Context, clinicID string, accountID string) (BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicBankAccount")
+	defer span.End()
+
+	account, err := s.queries.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountOutput{}, notFoundError("bank account not found")
+		}
+		return BankAccountOutput{}, err
+	}
+	return mapBankAccount(account), nil
+}
+
+// History is read from audit_logs, so it includes removed accounts and events recorded by verification callbacks.
+func (s *Service) ListClinicBankAccountHistory(ctx context.Context, clinicID string, limit int, cursor *string) ([]BankAccountHistoryEntryOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicBankAccountHistory")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, notFoundError("clinic not found")
+		}
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err := s.queries.ListClinicBankAccountHistoryCursor(ctx, repository.ListClinicBankAccountHistoryCursorParams{
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		EntityType:      AuditEntityBankAccount,
+		CursorCreatedAt: after.At,
+		CursorID:        after.ID,
+		PageLimit:       int32(pageLimit + 1),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+
+	entries := make([]BankAccountHistoryEntryOutput, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, BankAccountHistoryEntryOutput{
+			ID:            row.ID,
+			BankAccountID: row.BankAccountID,
+			BankCode:      row.BankCode,
+			BranchNumber:  row.BranchNumber,
+			AccountNumber: maskAccountNumber(row.AccountNumber),
+			Action:        row.Action,
+			ActorUserID:   nullUUIDToPointer(row.ActorUserID),
+			ActorEmail:    nullToPointer(row.ActorEmail),
+			Metadata:      row.Metadata,
+			CreatedAt:     row.CreatedAt,
+		})
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return entries, nextCursor, nil
+}
+
+func (s *Service) RevealClinicBankAccount(ctx context.Context, clinicID string, accountID string) (BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RevealClinicBankAccount")
+	defer span.End()
+
+	if err := s.ensureCanRevealBankDetails(ctx); err != nil {
+		return BankAccountOutput{}, err
+	}
+	account, err := s.queries.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountOutput{}, notFoundError("bank account not found")
+		}
+		return BankAccountOutput{}, err
+	}
+	// Without an audit row there is no reveal.
+	if err := recordAudit(ctx, s.queries, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "bank_account.revealed",
+		EntityType: AuditEntityBankAccount,
+		EntityID:   account.ID,
+	}); err != nil {
+		return BankAccountOutput{}, err
+	}
+
+	output := mapBankAccount(account)
+	output.AccountNumber = account.AccountNumber
+	return output, nil
+}
+
+func (s *Service) ensureCanRevealBankDetails(ctx context.Context) error {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return unauthorizedError("missing authenticated user")
+	}
+	// The permission is read from the database rather than the token so revoking it takes effect immediately.
+	user, err := s.queries.GetUserByID(ctx, repository.GetUserByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             principal.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return unauthorizedError("missing authenticated user")
+		}
+		return err
+	}
+	if user.Role != UserRoleAdmin || !user.CanRevealBankDetails {
+		return forbiddenError("revealing bank account numbers requires the bank details permission")
+	}
+	return nil
+}
+
+func (s *Service) CreateClinicBankAccount(ctx context.Context, clinicID string, input BankAccountInput) (BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinicBankAccount")
+	defer span.End()
+
+	if err := validateBankAccountInput(input); err != nil {
+		return BankAccountOutput{}, validationError(err.Error())
+	}
+	accountID, err := newUUIDV7()
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return BankAccountOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountOutput{}, notFoundError("clinic not found")
+		}
+		return BankAccountOutput{}, mapDatabaseError(err)
+	}
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	if err := ensureNoFinancialHold(ctx, qtx, clinicID); err != nil {
+		return BankAccountOutput{}, err
+	}
+	owner, err := qtx.GetClinicDetails(ctx, repository.GetClinicDetailsParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	holder, err := resolveBankAccountHolder(ctx, qtx, owner, input)
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	if input.IsPrimary {
+		if err := qtx.ClearPrimaryBankAccount(ctx, repository.ClearPrimaryBankAccountParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+		}); err != nil {
+			return BankAccountOutput{}, mapDatabaseError(err)
+		}
+	}
+	account, err := qtx.CreateBankAccount(ctx, newBankAccountParams(organizationID(ctx), accountID, clinicID, input, holder))
+	if err != nil {
+		return BankAccountOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "bank_account.created",
+		EntityType: AuditEntityBankAccount,
+		EntityID:   account.ID,
+		Metadata:   map[string]any{"is_primary": account.IsPrimary, "holder_review_required": account.HolderReviewRequired},
+	}); err != nil {
+		return BankAccountOutput{}, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return BankAccountOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return BankAccountOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapBankAccount(account), nil
+}
+
+func (s *Service) UpdateClinicBankAccount(ctx context.Context, clinicID string, accountID string, input UpdateBankAccountInput) (BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateClinicBankAccount")
+	defer span.End()
+
+	if input.BankCode == nil && input.BranchNumber == nil && input.AccountNumber == nil &&
+		input.AccountType == nil && input.HolderName == nil && input.HolderTaxID == nil {
+		return BankAccountOutput{}, validationError("at least one field must be provided")
+	}
+	if (input.HolderName == nil) != (input.HolderTaxID == nil) {
+		return BankAccountOutput{}, validationError("holder_name and holder_tax_id must be updated together")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return BankAccountOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	current, err := qtx.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountOutput{}, notFoundError("bank account not found")
+		}
+		return BankAccountOutput{}, err
+	}
+	if err := ensureNoFinancialHold(ctx, qtx, clinicID); err != nil {
+		return BankAccountOutput{}, err
+	}
+	merged := BankAccountInput{
+		BankCode:      current.BankCode,
+		BranchNumber:  current.BranchNumber,
+		AccountNumber: current.AccountNumber,
+		AccountType:   current.AccountType,
+		HolderName:    nullToPointer(current.HolderName),
+		HolderTaxID:   nullToPointer(current.HolderTaxID),
+	}
+	if input.BankCode != nil {
+		merged.BankCode = *input.BankCode
+	}
+	if input.BranchNumber != nil {
+		merged.BranchNumber = *input.BranchNumber
+	}
+	if input.AccountNumber != nil {
+		merged.AccountNumber = *input.AccountNumber
+	}
+	if input.AccountType != nil {
+		merged.AccountType = *input.AccountType
+	}
+	if input.HolderName != nil {
+		merged.HolderName = input.HolderName
+	}
+	if input.HolderTaxID != nil {
+		merged.HolderTaxID = input.HolderTaxID
+	}
+	if err := validateBankAccountInput(merged); err != nil {
+		return BankAccountOutput{}, validationError(err.Error())
+	}
+	owner, err := qtx.GetClinicDetails(ctx, repository.GetClinicDetailsParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	holder, err := resolveBankAccountHolder(ctx, qtx, owner, merged)
+	if err != nil {
+		return BankAccountOutput{}, err
+	}
+	if strings.TrimSpace(merged.BankCode) == current.BankCode &&
+		strings.TrimSpace(merged.BranchNumber) == current.BranchNumber &&
+		strings.TrimSpace(merged.AccountNumber) == current.AccountNumber &&
+		holder.AccountType == current.AccountType &&
+		holder.Name == current.HolderName.String &&
+		holder.TaxID == current.HolderTaxID.String {
+		return mapBankAccount(current), nil
+	}
+	// A holder already approved by a reviewer stays approved while it is unchanged.
+	if holder.TaxID == current.HolderTaxID.String && !current.HolderReviewRequired {
+		holder.ReviewRequired = false
+	}
+
+	// Changing the account details invalidates any ownership verification done for the old ones.
+	account, err := qtx.UpdateBankAccount(ctx, repository.UpdateBankAccountParams{
+		OrganizationID:       organizationID(ctx),
+		ID:                   accountID,
+		ClinicID:             clinicID,
+		BankCode:             sql.NullString{String: strings.TrimSpace(merged.BankCode), Valid: true},
+		BranchNumber:         sql.NullString{String: strings.TrimSpace(merged.BranchNumber), Valid: true},
+		AccountNumber:        sql.NullString{String: strings.TrimSpace(merged.AccountNumber), Valid: true},
+		AccountType:          sql.NullString{String: holder.AccountType, Valid: true},
+		HolderName:           sql.NullString{String: holder.Name, Valid: true},
+		HolderTaxID:          sql.NullString{String: holder.TaxID, Valid: true},
+		HolderReviewRequired: holder.ReviewRequired,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return BankAccountOutput{}, notFoundError("bank account not found")
+		}
+		return BankAccountOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "bank_account.updated",
+		EntityType: AuditEntityBankAccount,
+		EntityID:   accountID,
+	}); err != nil {
+		return BankAccountOutput{}, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return BankAccountOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return BankAccountOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapBankAccount(account), nil
+}
+
+func (s *Service) DeleteClinicBankAccount(ctx context.Context, clinicID string, accountID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteClinicBankAccount")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("clinic not found")
+		}
+		return mapDatabaseError(err)
+	}
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return err
+	}
+	if err := ensureNoFinancialHold(ctx, qtx, clinicID); err != nil {
+		return err
+	}
+	accounts, err := qtx.ListBankAccountsByClinicID(ctx, repository.ListBankAccountsByClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return err
+	}
+	if err := ensureBankAccountRemovable(accounts, accountID); err != nil {
+		return err
+	}
+	if _, err := qtx.DeleteBankAccountByIDAndClinicID(ctx, repository.DeleteBankAccountByIDAndClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "bank_account.deleted",
+		EntityType: AuditEntityBankAccount,
+		EntityID:   accountID,
+	}); err != nil {
+		return err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) MakePrimaryBankAccount(ctx context.Context, clinicID string, accountID string) ([]BankAccountOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.MakePrimaryBankAccount")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, mapDatabaseError(err)
+	}
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureNoFinancialHold(ctx, qtx, clinicID); err != nil {
+		return nil, err
+	}
+	account, err := qtx.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             accountID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("bank account not found")
+		}
+		return nil, err
+	}
+
+	if !account.IsPrimary {
+		// The previous primary is cleared first so the partial unique index never sees two primaries.
+		if err := qtx.ClearPrimaryBankAccount(ctx, repository.ClearPrimaryBankAccountParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+		}); err != nil {
+			return nil, mapDatabaseError(err)
+		}
+		if _, err := qtx.SetPrimaryBankAccount(ctx, repository.SetPrimaryBankAccountParams{
+			OrganizationID: organizationID(ctx),
+			ID:             accountID,
+			ClinicID:       clinicID,
+		}); err != nil {
+			return nil, mapDatabaseError(err)
+		}
+		if err := recordAudit(ctx, qtx, auditEntry{
+			ClinicID:   clinicID,
+			Action:     "bank_account.made_primary",
+			EntityType: AuditEntityBankAccount,
+			EntityID:   accountID,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	accounts, err := qtx.ListBankAccountsByClinicID(ctx, repository.ListBankAccountsByClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureSinglePrimaryBankAccount(accounts); err != nil {
+		return nil, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapBankAccounts(accounts), nil
+}
+
+// createBankAccounts inserts the accounts of a clinic and their audit rows with one statement each, however many accounts there are.
+func createBankAccounts(ctx context.Context, qtx repository.Querier, owner repository.GetClinicDetailsRow, accounts []BankAccountInput) error {
+	if len(accounts) == 0 {
+		return nil
+	}
+	params := repository.CreateBankAccountsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       owner.ClinicID,
+	}
+	audits := make([]auditEntry, 0, len(accounts))
+	for _, account := range accounts {
+		bankAccountID, err := newUUIDV7()
+		if err != nil {
+			return err
+		}
+		holder, err := resolveBankAccountHolder(ctx, qtx, owner, account)
+		if err != nil {
+			return err
+		}
+		row := newBankAccountParams(params.OrganizationID, bankAccountID, owner.ClinicID, account, holder)
+		params.Ids = append(params.Ids, row.ID)
+		params.BankCodes = append(params.BankCodes, row.BankCode)
+		params.BranchNumbers = append(params.BranchNumbers, row.BranchNumber)
+		params.AccountNumbers = append(params.AccountNumbers, row.AccountNumber)
+		params.AccountTypes = append(params.AccountTypes, row.AccountType)
+		params.HolderNames = append(params.HolderNames, row.HolderName.String)
+		params.HolderTaxIds = append(params.HolderTaxIds, row.HolderTaxID.String)
+		params.HolderReviewRequired = append(params.HolderReviewRequired, row.HolderReviewRequired)
+		params.IsPrimary = append(params.IsPrimary, row.IsPrimary)
+		audits = append(audits, auditEntry{
+			Action:     "bank_account.created",
+			EntityType: AuditEntityBankAccount,
+			EntityID:   bankAccountID,
+			Metadata:   map[string]any{"is_primary": account.IsPrimary, "holder_review_required": holder.ReviewRequired},
+		})
+	}
+	if err := qtx.CreateBankAccounts(ctx, params); err != nil {
+		return mapDatabaseError(err)
+	}
+	return recordClinicAudits(ctx, qtx, owner.ClinicID, audits)
+}
+
+func primaryBankAccountIndex(accounts []BankAccountInput) int {
+	return slices.IndexFunc(accounts, func(account BankAccountInput) bool { return account.IsPrimary })
+}
+
+func ensureBankAccountRemovable(accounts []repository.BankAccount, accountID string) error {
+	idx := slices.IndexFunc(accounts, func(account repository.BankAccount) bool { return account.ID == accountID })
+	if idx < 0 {
+		return notFoundError("bank account not found")
+	}
+	if len(accounts) == 1 {
+		return conflictError("clinic must have at least one active bank account")
+	}
+	if accounts[idx].IsPrimary {
+		return conflictError("cannot remove the primary bank account; make another account primary first")
+	}
+	return nil
+}
+
+func ensureSinglePrimaryBankAccount(accounts []repository.BankAccount) error {
+	primaries := 0
+	for _, account := range accounts {
+		if account.IsPrimary {
+			primaries++
+		}
+	}
+	switch {
+	case primaries == 0:
+		return validationError("clinic must have exactly one primary bank account; make another account primary before removing the current one")
+	case primaries > 1:
+		return conflictError("clinic has more than one primary bank account")
+	}
+	return nil
+}
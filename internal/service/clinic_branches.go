@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+func (s *Service) CreateClinicBranch(ctx context.Context, parentClinicID string, input CreateClinicInput) (ClinicOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinicBranch")
+	defer span.End()
+
+	return s.createClinic(ctx, input, &parentClinicID)
+}
+
+func (s *Service) ListClinicBranches(ctx context.Context, clinicID string) ([]ClinicOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicBranches")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+
+	branches, err := s.queries.ListBranchClinicDetails(ctx, repository.ListBranchClinicDetailsParams{
+		OrganizationID: organizationID(ctx),
+		ParentClinicID: clinicID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	rows := make([]repository.ListClinicDetailsCursorRow, 0, len(branches))
+	for _, branch := range branches {
+		rows = append(rows, repository.ListClinicDetailsCursorRow(branch))
+	}
+	return s.mapClinicRows(ctx, rows)
+}
+
+func (s *Service) GetClinicGroupReport(ctx context.Context, clinicID string) (ClinicGroupReportOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicGroupReport")
+	defer span.End()
+
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicGroupReportOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicGroupReportOutput{}, err
+	}
+	// Asking a branch for the report rolls up its whole clinic group.
+	groupClinicID := clinic.ID
+	if clinic.ParentClinicID.Valid {
+		groupClinicID = clinic.ParentClinicID.UUID.String()
+	}
+
+	rows, err := s.queries.ListClinicGroupReport(ctx, repository.ListClinicGroupReportParams{
+		OrganizationID: organizationID(ctx),
+		GroupClinicID:  groupClinicID,
+	})
+	if err != nil {
+		return ClinicGroupReportOutput{}, err
+	}
+	distinctDentists, err := s.queries.CountDistinctActiveDentistsInClinicGroup(ctx, repository.CountDistinctActiveDentistsInClinicGroupParams{
+		OrganizationID: organizationID(ctx),
+		GroupClinicID:  groupClinicID,
+	})
+	if err != nil {
+		return ClinicGroupReportOutput{}, err
+	}
+
+	output := ClinicGroupReportOutput{
+		OrganizationID: groupClinicID,
+		Clinics:        make([]ClinicGroupMemberReport, 0, len(rows)),
+	}
+	for _, row := range rows {
+		output.Clinics = append(output.Clinics, ClinicGroupMemberReport{
+			ClinicID:             row.ClinicID,
+			ParentClinicID:       nullUUIDToPointer(row.ParentClinicID),
+			IsHeadquarters:       !row.ParentClinicID.Valid,
+			LegalName:            row.LegalName,
+			TradeName:            nullToPointer(row.TradeName),
+			TaxIDNumber:          row.TaxIDNumber,
+			ActiveDentists:       int(row.ActiveDentists),
+			Admins:               int(row.Admins),
+			LegalRepresentatives: int(row.LegalRepresentatives),
+			TemporaryDentists:    int(row.TemporaryDentists),
+		})
+		output.Totals.Clinics++
+		if row.ParentClinicID.Valid {
+			output.Totals.Branches++
+		}
+		output.Totals.ActiveDentistLinks += int(row.ActiveDentists)
+		output.Totals.Admins += int(row.Admins)
+		output.Totals.LegalRepresentatives += int(row.LegalRepresentatives)
+		output.Totals.TemporaryDentists += int(row.TemporaryDentists)
+	}
+	output.Totals.DistinctActiveDentists = int(distinctDentists)
+	return output, nil
+}
+
+func (s *Service) resolveBranchParent(ctx context.Context, parentClinicID *string, taxID string, validations *validationCollector) (uuid.NullUUID, error) {
+	if parentClinicID == nil {
+		return uuid.NullUUID{}, nil
+	}
+	parsedParentID, err := uuid.Parse(*parentClinicID)
+	if err != nil {
+		return uuid.NullUUID{}, validationError("parent clinic id must be a UUID")
+	}
+
+	parent, err := s.queries.GetClinicDetails(ctx, repository.GetClinicDetailsParams{
+		OrganizationID: organizationID(ctx),
+		ID:             parsedParentID.String(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.NullUUID{}, notFoundError("parent clinic not found")
+		}
+		return uuid.NullUUID{}, err
+	}
+	if parent.DeactivatedAt.Valid {
+		return uuid.NullUUID{}, conflictError("parent clinic is deactivated")
+	}
+	if parent.ParentClinicID.Valid {
+		return uuid.NullUUID{}, validationError("branches cannot have branches of their own; create it under the parent organization")
+	}
+	// Related companies with a different CNPJ root are accepted, but flagged for review.
+	if validation.CNPJRoot(taxID) != validation.CNPJRoot(parent.TaxIDNumber) {
+		validations.warn("branch CNPJ root differs from the parent organization")
+	}
+	return uuid.NullUUID{UUID: parsedParentID, Valid: true}, nil
+}
@@ -0,0 +1,378 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	HolidaySourceNational = "NATIONAL"
+	HolidaySourceClinic   = "CLINIC"
+
+	minutesPerDay           = 24 * 60
+	maxHolidayNameLength    = 120
+	holidayUniqueConstraint = "idx_clinic_holidays_date_active_unique"
+)
+
+func (s *Service) GetClinicOperatingHours(ctx context.Context, clinicID string) ([]OperatingHoursOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicOperatingHours")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+
+	hours, err := s.queries.ListClinicOperatingHours(ctx, repository.ListClinicOperatingHoursParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapOperatingHours(hours), nil
+}
+
+func (s *Service) ReplaceClinicOperatingHours(ctx context.Context, clinicID string, input ReplaceOperatingHoursInput) ([]OperatingHoursOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ReplaceClinicOperatingHours")
+	defer span.End()
+
+	if input.Hours == nil {
+		return nil, validationError("hours is required")
+	}
+	intervals, err := parseOperatingHours(input.Hours)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, mapDatabaseError(err)
+	}
+	if _, err := qtx.DeleteClinicOperatingHours(ctx, repository.DeleteClinicOperatingHoursParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	}); err != nil {
+		return nil, mapDatabaseError(err)
+	}
+	for _, interval := range intervals {
+		if err := qtx.CreateClinicOperatingHours(ctx, repository.CreateClinicOperatingHoursParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+			Weekday:        interval.Weekday,
+			OpensMinute:    interval.OpensMinute,
+			ClosesMinute:   interval.ClosesMinute,
+		}); err != nil {
+			return nil, mapDatabaseError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapOperatingHours(intervals), nil
+}
+
+func (s *Service) ListClinicHolidays(ctx context.Context, clinicID string, year int) ([]ClinicHolidayOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicHolidays")
+	defer span.End()
+
+	if year < 1900 || year > 9999 {
+		return nil, validationError("year must be between 1900 and 9999")
+	}
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+
+	custom, err := s.queries.ListClinicHolidays(ctx, repository.ListClinicHolidaysParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		FromDate:       time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC),
+		ToDate:         time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	national := brazilianNationalHolidays(year)
+	output := make([]ClinicHolidayOutput, 0, len(national)+len(custom))
+	for _, holiday := range national {
+		output = append(output, ClinicHolidayOutput{
+			Date:   holiday.date.Format(documentDateLayout),
+			Name:   holiday.name,
+			Source: HolidaySourceNational,
+		})
+	}
+	for _, holiday := range custom {
+		output = append(output, mapClinicHoliday(holiday))
+	}
+	slices.SortStableFunc(output, func(a, b ClinicHolidayOutput) int {
+		return strings.Compare(a.Date, b.Date)
+	})
+	return output, nil
+}
+
+func (s *Service) CreateClinicHoliday(ctx context.Context, clinicID string, input CreateClinicHolidayInput) (ClinicHolidayOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinicHoliday")
+	defer span.End()
+
+	date, err := parseDocumentDate("date", &input.Date)
+	if err != nil {
+		return ClinicHolidayOutput{}, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return ClinicHolidayOutput{}, validationError("name is required")
+	}
+	if err := validateMaxLength("name", name, maxHolidayNameLength); err != nil {
+		return ClinicHolidayOutput{}, err
+	}
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicHolidayOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicHolidayOutput{}, err
+	}
+
+	holidayID, err := newUUIDV7()
+	if err != nil {
+		return ClinicHolidayOutput{}, err
+	}
+	holiday, err := s.queries.CreateClinicHoliday(ctx, repository.CreateClinicHolidayParams{
+		OrganizationID: organizationID(ctx),
+		ID:             holidayID,
+		ClinicID:       clinicID,
+		HolidayDate:    date.Time,
+		Name:           name,
+	})
+	if err != nil {
+		if isConstraintViolation(err, holidayUniqueConstraint) {
+			return ClinicHolidayOutput{}, conflictError("clinic already has a holiday on this date")
+		}
+		return ClinicHolidayOutput{}, mapDatabaseError(err)
+	}
+	return mapClinicHoliday(holiday), nil
+}
+
+func (s *Service) DeleteClinicHoliday(ctx context.Context, clinicID string, holidayID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteClinicHoliday")
+	defer span.End()
+
+	rows, err := s.queries.DeleteClinicHoliday(ctx, repository.DeleteClinicHolidayParams{
+		OrganizationID: organizationID(ctx),
+		ID:             holidayID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return notFoundError("holiday not found")
+	}
+	return nil
+}
+
+func (s *Service) CheckClinicAvailability(ctx context.Context, clinicID string, at time.Time) (ClinicAvailabilityOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CheckClinicAvailability")
+	defer span.End()
+
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicAvailabilityOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicAvailabilityOutput{}, err
+	}
+
+	// Holidays and operating hours are defined on the clinic's wall clock, not in UTC.
+	local := at.In(clinicLocation(ctx, clinic.Timezone))
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+	output := ClinicAvailabilityOutput{At: at.UTC(), LocalAt: local, Timezone: clinic.Timezone, Open: true}
+	if clinic.DeactivatedAt.Valid {
+		output.Open = false
+		output.Reason = availabilityReasonInactive
+		return output, nil
+	}
+
+	for _, holiday := range brazilianNationalHolidays(local.Year()) {
+		if holiday.date.Equal(day) {
+			output.Open = false
+			output.Reason = "national holiday: " + holiday.name
+			return output, nil
+		}
+	}
+	custom, err := s.queries.ListClinicHolidays(ctx, repository.ListClinicHolidaysParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		FromDate:       day,
+		ToDate:         day,
+	})
+	if err != nil {
+		return ClinicAvailabilityOutput{}, err
+	}
+	if len(custom) > 0 {
+		output.Open = false
+		output.Reason = "clinic holiday: " + custom[0].Name
+		return output, nil
+	}
+
+	hours, err := s.queries.ListClinicOperatingHours(ctx, repository.ListClinicOperatingHoursParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return ClinicAvailabilityOutput{}, err
+	}
+	// Clinics that never configured their hours keep the previous behaviour of accepting any time.
+	if len(hours) == 0 {
+		return output, nil
+	}
+	if !withinOperatingHours(hours, local) {
+		output.Open = false
+		output.Reason = "outside operating hours"
+	}
+	return output, nil
+}
+
+func (s *Service) EnsureClinicOpen(ctx context.Context, clinicID string, at time.Time, override bool) error {
+	availability, err := s.CheckClinicAvailability(ctx, clinicID, at)
+	if err != nil {
+		return err
+	}
+	// Overrides cover closing hours and holidays, never a suspended clinic.
+	if availability.Reason == availabilityReasonInactive {
+		return conflictError("clinic is deactivated; reactivate it before scheduling")
+	}
+	if !availability.Open && !override {
+		return validationError(fmt.Sprintf("clinic is closed at the requested time (%s); set override to schedule anyway", availability.Reason))
+	}
+	return nil
+}
+
+func withinOperatingHours(hours []repository.ClinicOperatingHour, at time.Time) bool {
+	weekday := int16(at.Weekday())
+	minute := int16(at.Hour()*60 + at.Minute())
+	for _, interval := range hours {
+		if interval.Weekday == weekday && minute >= interval.OpensMinute && minute < interval.ClosesMinute {
+			return true
+		}
+	}
+	return false
+}
+
+func parseOperatingHours(input []OperatingHoursInput) ([]repository.ClinicOperatingHour, error) {
+	intervals := make([]repository.ClinicOperatingHour, 0, len(input))
+	for idx, item := range input {
+		if item.Weekday == nil || *item.Weekday < 0 || *item.Weekday > 6 {
+			return nil, validationError(fmt.Sprintf("hours[%d].weekday must be between 0 (sunday) and 6 (saturday)", idx))
+		}
+		opens, err := parseClockMinute(item.OpensAt)
+		if err != nil {
+			return nil, validationError(fmt.Sprintf("hours[%d].opens_at %s", idx, err.Error()))
+		}
+		closes, err := parseClockMinute(item.ClosesAt)
+		if err != nil {
+			return nil, validationError(fmt.Sprintf("hours[%d].closes_at %s", idx, err.Error()))
+		}
+		if opens >= closes {
+			return nil, validationError(fmt.Sprintf("hours[%d].opens_at must be before closes_at", idx))
+		}
+		intervals = append(intervals, repository.ClinicOperatingHour{
+			Weekday:      int16(*item.Weekday),
+			OpensMinute:  int16(opens),
+			ClosesMinute: int16(closes),
+		})
+	}
+
+	slices.SortFunc(intervals, func(a, b repository.ClinicOperatingHour) int {
+		if a.Weekday != b.Weekday {
+			return int(a.Weekday - b.Weekday)
+		}
+		return int(a.OpensMinute - b.OpensMinute)
+	})
+	for idx := 1; idx < len(intervals); idx++ {
+		previous, current := intervals[idx-1], intervals[idx]
+		if previous.Weekday == current.Weekday && current.OpensMinute < previous.ClosesMinute {
+			return nil, validationError(fmt.Sprintf("operating hours overlap on weekday %d", current.Weekday))
+		}
+	}
+	return intervals, nil
+}
+
+func parseClockMinute(value string) (int, error) {
+	trimmed := strings.TrimSpace(value)
+	if trimmed == "24:00" {
+		return minutesPerDay, nil
+	}
+	parsed, err := time.Parse("15:04", trimmed)
+	if err != nil {
+		return 0, errors.New("must be a time in HH:MM format")
+	}
+	return parsed.Hour()*60 + parsed.Minute(), nil
+}
+
+func formatClockMinute(minute int16) string {
+	return fmt.Sprintf("%02d:%02d", minute/60, minute%60)
+}
+
+func mapOperatingHours(hours []repository.ClinicOperatingHour) []OperatingHoursOutput {
+	output := make([]OperatingHoursOutput, 0, len(hours))
+	for _, interval := range hours {
+		output = append(output, OperatingHoursOutput{
+			Weekday:     int(interval.Weekday),
+			WeekdayName: strings.ToLower(time.Weekday(interval.Weekday).String()),
+			OpensAt:     formatClockMinute(interval.OpensMinute),
+			ClosesAt:    formatClockMinute(interval.ClosesMinute),
+		})
+	}
+	return output
+}
+
+func mapClinicHoliday(holiday repository.ClinicHoliday) ClinicHolidayOutput {
+	id := holiday.ID
+	return ClinicHolidayOutput{
+		ID:     &id,
+		Date:   holiday.HolidayDate.Format(documentDateLayout),
+		Name:   holiday.Name,
+		Source: HolidaySourceClinic,
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	DashboardBlockAvailable   = "AVAILABLE"
+	DashboardBlockUnavailable = "UNAVAILABLE"
+
+	// clinicDashboardTimeout is shared by every block, so one slow query cannot hold the whole panel back.
+	clinicDashboardTimeout = 400 * time.Millisecond
+)
+
+// GetClinicDashboardToday gathers what the clinic team needs at the start of the day, with "today" taken in the clinic's
+// timezone. Each block loads on its own; one that fails or misses the deadline is marked unavailable instead of failing the
+// response. Appointments, check-ins and billing are not kept by the API, so those blocks are always unavailable.
+func (s *Service) GetClinicDashboardToday(ctx context.Context, clinicID string) (ClinicDashboardOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicDashboardToday")
+	defer span.End()
+
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicDashboardOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicDashboardOutput{}, err
+	}
+	location := clinicLocation(ctx, clinic.Timezone)
+	today := s.todayIn(location)
+
+	dashboard := ClinicDashboardOutput{
+		ClinicID:     clinicID,
+		Timezone:     clinic.Timezone,
+		Date:         today.Format(documentDateLayout),
+		Appointments: unavailableDashboardBlock[struct{}]("the API does not keep appointments"),
+		CheckInQueue: unavailableDashboardBlock[struct{}]("the API does not keep patient check-ins"),
+		Billing:      unavailableDashboardBlock[struct{}]("the API does not keep patient billing"),
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, clinicDashboardTimeout)
+	defer cancel()
+	// Blocks report their own failures, so the group never cancels the others.
+	_ = loadInParallel(ctx,
+		loadDashboardBlock(&dashboard.Shifts, "shifts", func(ctx context.Context) ([]ClinicShiftOutput, error) {
+			if err := s.CheckFeature(ctx, FeatureScheduling); err != nil {
+				return nil, err
+			}
+			rows, err := s.queries.ListClinicShiftsBetween(ctx, repository.ListClinicShiftsBetweenParams{
+				OrganizationID: organizationID(ctx),
+				ClinicID:       clinicID,
+				FromAt:         time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, location),
+				ToAt:           time.Date(today.Year(), today.Month(), today.Day()+1, 0, 0, 0, 0, location),
+			})
+			if err != nil {
+				return nil, err
+			}
+			shifts := make([]ClinicShiftOutput, 0, len(rows))
+			for _, row := range rows {
+				shifts = append(shifts, mapScheduledShift(row))
+			}
+			return shifts, nil
+		}),
+		loadDashboardBlock(&dashboard.OpenPunches, "open_punches", func(ctx context.Context) ([]OpenPunchOutput, error) {
+			rows, err := s.queries.ListOpenClinicTimeClockEntries(ctx, repository.ListOpenClinicTimeClockEntriesParams{
+				OrganizationID: organizationID(ctx),
+				ClinicID:       clinicID,
+			})
+			if err != nil {
+				return nil, err
+			}
+			punches := make([]OpenPunchOutput, 0, len(rows))
+			for _, row := range rows {
+				punches = append(punches, OpenPunchOutput{
+					TimeClockEntryOutput: mapTimeClockEntry(repository.TimeClockEntry{
+						ID:                    row.ID,
+						OrganizationID:        row.OrganizationID,
+						ClinicID:              row.ClinicID,
+						DentistID:             row.DentistID,
+						ClockInAt:             row.ClockInAt,
+						ClockInIp:             row.ClockInIp,
+						ClockInLatitude:       row.ClockInLatitude,
+						ClockInLongitude:      row.ClockInLongitude,
+						ClockInAccuracyMeters: row.ClockInAccuracyMeters,
+						ClockInDistanceMeters: row.ClockInDistanceMeters,
+						ClockInVerified:       row.ClockInVerified,
+						CreatedAt:             row.CreatedAt,
+						UpdatedAt:             row.UpdatedAt,
+					}),
+					DentistName: row.DentistName,
+				})
+			}
+			return punches, nil
+		}),
+		loadDashboardBlock(&dashboard.TasksDueToday, "tasks_due_today", func(ctx context.Context) ([]ClinicTaskOutput, error) {
+			rows, err := s.queries.ListClinicTasksDueOn(ctx, repository.ListClinicTasksDueOnParams{
+				OrganizationID: organizationID(ctx),
+				ClinicID:       clinicID,
+				Statuses:       openTaskStatuses,
+				DueDate:        today,
+			})
+			if err != nil {
+				return nil, err
+			}
+			tasks := make([]ClinicTaskOutput, 0, len(rows))
+			for _, row := range rows {
+				tasks = append(tasks, mapClinicTask(row, today))
+			}
+			return tasks, nil
+		}),
+		loadDashboardBlock(&dashboard.LowStock, "low_stock", func(ctx context.Context) ([]LowStockItemOutput, error) {
+			rows, err := s.queries.ListLowStockInventoryItems(ctx, repository.ListLowStockInventoryItemsParams{
+				OrganizationID: organizationID(ctx),
+				ClinicID:       clinicID,
+				UsageSince:     s.now().Add(-inventoryConsumptionWindowDays * 24 * time.Hour),
+			})
+			if err != nil {
+				return nil, err
+			}
+			items := make([]LowStockItemOutput, 0, len(rows))
+			for _, row := range rows {
+				items = append(items, mapLowStockItem(row))
+			}
+			return items, nil
+		}),
+		loadDashboardBlock(&dashboard.OverdueMaintenance, "overdue_maintenance", func(ctx context.Context) ([]EquipmentOutput, error) {
+			rows, err := s.queries.ListClinicOverdueEquipment(ctx, repository.ListClinicOverdueEquipmentParams{
+				OrganizationID: organizationID(ctx),
+				ClinicID:       clinicID,
+				Today:          today,
+			})
+			if err != nil {
+				return nil, err
+			}
+			equipment := make([]EquipmentOutput, 0, len(rows))
+			for _, row := range rows {
+				equipment = append(equipment, mapEquipment(row, today, 0))
+			}
+			return equipment, nil
+		}),
+	)
+	return dashboard, nil
+}
+
+// loadDashboardBlock fills block with what load returns, or marks it unavailable when load fails.
+func loadDashboardBlock[T any](block *DashboardBlockOutput[T], name string, load func(ctx context.Context) ([]T, error)) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		items, err := load(ctx)
+		if err != nil {
+			var notInPlan *FeatureNotInPlanError
+			if errors.As(err, &notInPlan) {
+				*block = unavailableDashboardBlock[T]("not included in the organization's plan")
+				return nil
+			}
+			reason := "failed to load"
+			if ctx.Err() != nil {
+				reason = "timed out"
+			}
+			slog.WarnContext(ctx, "clinic dashboard block unavailable", "block", name, "error", err)
+			*block = unavailableDashboardBlock[T](reason)
+			return nil
+		}
+		*block = DashboardBlockOutput[T]{Status: DashboardBlockAvailable, Items: items}
+		return nil
+	}
+}
+
+func unavailableDashboardBlock[T any](reason string) DashboardBlockOutput[T] {
+	return DashboardBlockOutput[T]{Status: DashboardBlockUnavailable, Reason: &reason, Items: []T{}}
+}
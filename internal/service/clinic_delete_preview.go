@@ -0,0 +1,114 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const deleteConfirmationTTL = 10 * time.Minute
+
+func WithDeleteConfirmationThreshold(threshold int) Option {
+	return func(s *Service) {
+		if threshold > 0 {
+			s.deleteConfirmation = threshold
+		}
+	}
+}
+
+func (s *Service) GetClinicDeletePreview(ctx context.Context, clinicID string) (ClinicDeletePreviewOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicDeletePreview")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicDeletePreviewOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicDeletePreviewOutput{}, err
+	}
+	counts, err := s.queries.GetClinicDeletePreviewCounts(ctx, repository.GetClinicDeletePreviewCountsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return ClinicDeletePreviewOutput{}, err
+	}
+
+	preview := ClinicDeletePreviewOutput{
+		ClinicID:       clinicID,
+		DentistLinks:   counts.DentistLinks,
+		BankAccounts:   counts.BankAccounts,
+		ActiveBranches: counts.ActiveBranches,
+		Invoices:       counts.Invoices,
+		OpenPayables:   counts.OpenPayables,
+	}
+	if counts.ActiveBranches > 0 {
+		reason := "clinic has active branches; delete them first"
+		preview.BlockedReason = &reason
+		return preview, nil
+	}
+	if s.requiresDeleteConfirmation(counts) {
+		expiresAt := s.now().UTC().Add(deleteConfirmationTTL).Truncate(time.Second)
+		token := s.deleteConfirmationToken(clinicID, counts, expiresAt)
+		preview.ConfirmationRequired = true
+		preview.ConfirmationToken = &token
+		preview.ConfirmationExpiresAt = &expiresAt
+	}
+	return preview, nil
+}
+
+func (s *Service) requiresDeleteConfirmation(counts repository.GetClinicDeletePreviewCountsRow) bool {
+	return s.deleteConfirmation > 0 && counts.DentistLinks+counts.BankAccounts > int64(s.deleteConfirmation)
+}
+
+// The token is bound to what the preview showed: new links or accounts after the preview invalidate it.
+func (s *Service) deleteConfirmationToken(clinicID string, counts repository.GetClinicDeletePreviewCountsRow, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	mac := hmac.New(sha256.New, s.jwtSigningKey)
+	fmt.Fprintf(mac, "clinic-delete|%s|%d|%d|%s", clinicID, counts.DentistLinks, counts.BankAccounts, expires)
+	return expires + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (s *Service) ensureDeleteConfirmed(ctx context.Context, q repository.Querier, clinicID string, token string) error {
+	counts, err := q.GetClinicDeletePreviewCounts(ctx, repository.GetClinicDeletePreviewCountsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return err
+	}
+	if !s.requiresDeleteConfirmation(counts) {
+		return nil
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return confirmationRequiredError(fmt.Sprintf("deleting this clinic ends %d dentist links and removes %d bank accounts; request GET /clinics/%s/delete-preview and pass its confirmation_token", counts.DentistLinks, counts.BankAccounts, clinicID))
+	}
+	rawExpires, _, ok := strings.Cut(token, ".")
+	expires, err := strconv.ParseInt(rawExpires, 10, 64)
+	if !ok || err != nil {
+		return confirmationRequiredError("invalid confirmation_token")
+	}
+	expiresAt := time.Unix(expires, 0).UTC()
+	if !hmac.Equal([]byte(token), []byte(s.deleteConfirmationToken(clinicID, counts, expiresAt))) {
+		return confirmationRequiredError("confirmation_token does not match the current delete preview; request a new one")
+	}
+	if !s.now().Before(expiresAt) {
+		return confirmationRequiredError("confirmation_token expired; request a new delete preview")
+	}
+	return nil
+}
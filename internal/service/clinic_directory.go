@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const maxDirectoryCityLength = 100
+
+func (s *Service) GetClinicDirectoryListing(ctx context.Context, clinicID string) (ClinicDirectoryListingOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicDirectoryListing")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicDirectoryListingOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicDirectoryListingOutput{}, err
+	}
+	listing, err := s.queries.GetClinicDirectoryListing(ctx, repository.GetClinicDirectoryListingParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicDirectoryListingOutput{ClinicID: clinicID}, nil
+		}
+		return ClinicDirectoryListingOutput{}, err
+	}
+	return mapClinicDirectoryListing(listing), nil
+}
+
+func (s *Service) UpdateClinicDirectoryListing(ctx context.Context, clinicID string, input UpdateClinicDirectoryListingInput) (ClinicDirectoryListingOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateClinicDirectoryListing")
+	defer span.End()
+
+	if input.Listed == nil {
+		return ClinicDirectoryListingOutput{}, validationError("listed is required")
+	}
+	publicPhone, err := normalizePublicPhone(input.PublicPhone)
+	if err != nil {
+		return ClinicDirectoryListingOutput{}, err
+	}
+
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicDirectoryListingOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicDirectoryListingOutput{}, err
+	}
+
+	listing, err := s.queries.UpsertClinicDirectoryListing(ctx, repository.UpsertClinicDirectoryListingParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		Listed:         *input.Listed,
+		PublicPhone:    publicPhone,
+	})
+	if err != nil {
+		return ClinicDirectoryListingOutput{}, mapDatabaseError(err)
+	}
+
+	output := mapClinicDirectoryListing(listing)
+	if listing.Listed {
+		hasAddress := true
+		if _, err := s.queries.GetAddressByPersonID(ctx, repository.GetAddressByPersonIDParams{
+			OrganizationID: organizationID(ctx),
+			PersonID:       clinic.PersonID,
+		}); err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return ClinicDirectoryListingOutput{}, err
+			}
+			hasAddress = false
+		}
+		output.Warnings = directoryVisibilityWarnings(clinic, hasAddress)
+	}
+	return output, nil
+}
+
+func (s *Service) ListPublicClinicDirectory(ctx context.Context, limit int, cursor *string, filter PublicClinicDirectoryFilter) ([]PublicClinicOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListPublicClinicDirectory")
+	defer span.End()
+
+	ctx, err := s.resolveOrganizationSlug(ctx, filter.Organization)
+	if err != nil {
+		return nil, nil, err
+	}
+	params, err := publicDirectoryParams(limit, cursor, filter)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.OrganizationID = organizationID(ctx)
+	pageLimit := int(params.PageLimit) - 1
+
+	rows, err := s.queries.ListPublicClinicDirectoryCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+
+	clinicIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		clinicIDs = append(clinicIDs, row.ID)
+	}
+	specialtiesByClinic, err := s.loadPublicClinicSpecialties(ctx, clinicIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	clinics := make([]PublicClinicOutput, 0, len(rows))
+	for _, row := range rows {
+		specialties := specialtiesByClinic[row.ID]
+		if specialties == nil {
+			specialties = make([]PublicSpecialtyOutput, 0)
+		}
+		clinics = append(clinics, PublicClinicOutput{
+			ID:                 row.ID,
+			Name:               row.DisplayName,
+			City:               row.City,
+			State:              row.State,
+			PublicPhone:        nullToPointer(row.PublicPhone),
+			PublicPhoneDisplay: phoneDisplay(row.PublicPhone),
+			Specialties:        specialties,
+		})
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return clinics, nextCursor, nil
+}
+
+func publicDirectoryParams(limit int, cursor *string, filter PublicClinicDirectoryFilter) (repository.ListPublicClinicDirectoryCursorParams, error) {
+	params := repository.ListPublicClinicDirectoryCursorParams{
+		PageLimit: int32(normalizeCursorLimit(limit) + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return repository.ListPublicClinicDirectoryCursorParams{}, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+	if filter.City != nil {
+		city := strings.TrimSpace(*filter.City)
+		if err := validateMaxLength("city", city, maxDirectoryCityLength); err != nil {
+			return repository.ListPublicClinicDirectoryCursorParams{}, err
+		}
+		if city != "" {
+			params.City = sql.NullString{String: city, Valid: true}
+		}
+	}
+	if filter.State != nil {
+		state := validation.NormalizeState(*filter.State)
+		if !validation.ValidateState(state) {
+			return repository.ListPublicClinicDirectoryCursorParams{}, validationError("state must be a valid Brazilian UF")
+		}
+		params.State = sql.NullString{String: state, Valid: true}
+	}
+	if filter.Specialty != nil {
+		specialty := strings.ToUpper(strings.TrimSpace(*filter.Specialty))
+		if specialty != "" {
+			params.Specialty = sql.NullString{String: specialty, Valid: true}
+		}
+	}
+	return params, nil
+}
+
+// The directory is shown to anonymous visitors, so unlike the clinic phone the validation policy cannot relax it.
+func normalizePublicPhone(value *string) (sql.NullString, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return sql.NullString{}, nil
+	}
+	normalized, ok := validation.NormalizePhone(*value)
+	if !ok {
+		return sql.NullString{}, validationError("invalid public_phone")
+	}
+	return sql.NullString{String: normalized, Valid: true}, nil
+}
+
+func directoryVisibilityWarnings(clinic repository.Clinic, hasAddress bool) []string {
+	var warnings []string
+	if !hasAddress {
+		warnings = append(warnings, "clinic has no address and will not appear in the public directory until one is registered")
+	}
+	if clinic.DeactivatedAt.Valid {
+		warnings = append(warnings, "clinic is deactivated and will not appear in the public directory until it is reactivated")
+	}
+	if clinic.OnboardingStatus != OnboardingStatusActive {
+		warnings = append(warnings, fmt.Sprintf("clinic will appear in the public directory once onboarding reaches %s", OnboardingStatusActive))
+	}
+	return warnings
+}
+
+func mapClinicDirectoryListing(listing repository.ClinicDirectoryListing) ClinicDirectoryListingOutput {
+	updatedAt := listing.UpdatedAt
+	return ClinicDirectoryListingOutput{
+		ClinicID:           listing.ClinicID,
+		Listed:             listing.Listed,
+		PublicPhone:        nullToPointer(listing.PublicPhone),
+		PublicPhoneDisplay: phoneDisplay(listing.PublicPhone),
+		UpdatedAt:          &updatedAt,
+	}
+}
+
+func (s *Service) loadPublicClinicSpecialties(ctx context.Context, clinicIDs []string) (map[string][]PublicSpecialtyOutput, error) {
+	return loadBatch(ctx, s, "public_clinic_specialties", clinicIDs, func(ctx context.Context, clinicIDs []string) (map[string][]PublicSpecialtyOutput, error) {
+		specialties, err := s.queries.ListPublicClinicSpecialties(ctx, repository.ListPublicClinicSpecialtiesParams{
+			OrganizationID: organizationID(ctx),
+			ClinicIds:      clinicIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		specialtiesByClinic := make(map[string][]PublicSpecialtyOutput, len(clinicIDs))
+		for _, specialty := range specialties {
+			specialtiesByClinic[specialty.ClinicID] = append(specialtiesByClinic[specialty.ClinicID], PublicSpecialtyOutput{
+				Code: specialty.Code,
+				Name: specialty.Name,
+			})
+		}
+		return specialtiesByClinic, nil
+	})
+}
@@ -0,0 +1,70 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	DuplicateReasonDeletedTaxID     = "SAME_TAX_ID_DELETED"
+	DuplicateReasonSimilarLegalName = "SIMILAR_LEGAL_NAME"
+
+	clinicDuplicateMinSimilarity = 0.6
+	clinicDuplicateMaxCandidates = 5
+)
+
+type DuplicateClinicError struct {
+	Candidates []ClinicDuplicateCandidate
+}
+
+func (e *DuplicateClinicError) Error() string {
+	return fmt.Sprintf("%s: found %d possible duplicate clinic(s); review the candidates or set allow_duplicate to create anyway", ErrConflict, len(e.Candidates))
+}
+
+func (e *DuplicateClinicError) Unwrap() error {
+	return ErrConflict
+}
+
+// ensureNoClinicDuplicates looks for clinics the new one may repeat. A branch usually shares its parent's legal name, so
+// parentClinicID keeps the parent and its other active branches out of the similar-name candidates.
+func (s *Service) ensureNoClinicDuplicates(ctx context.Context, taxID string, legalName string, parentClinicID uuid.NullUUID) error {
+	rows, err := s.queries.ListClinicDuplicateCandidates(ctx, repository.ListClinicDuplicateCandidatesParams{
+		OrganizationID: organizationID(ctx),
+		GroupClinicID:  parentClinicID,
+		LegalName:      strings.TrimSpace(legalName),
+		TaxIDNumber:    taxID,
+		MinSimilarity:  clinicDuplicateMinSimilarity,
+		MaxCandidates:  clinicDuplicateMaxCandidates,
+	})
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	return &DuplicateClinicError{Candidates: mapClinicDuplicateCandidates(rows, taxID)}
+}
+
+func mapClinicDuplicateCandidates(rows []repository.ListClinicDuplicateCandidatesRow, taxID string) []ClinicDuplicateCandidate {
+	candidates := make([]ClinicDuplicateCandidate, 0, len(rows))
+	for _, row := range rows {
+		reason := DuplicateReasonSimilarLegalName
+		if row.DeletedAt.Valid && row.TaxIDNumber == taxID {
+			reason = DuplicateReasonDeletedTaxID
+		}
+		candidates = append(candidates, ClinicDuplicateCandidate{
+			ClinicID:    row.ClinicID,
+			LegalName:   row.LegalName,
+			TaxIDNumber: row.TaxIDNumber,
+			Reason:      reason,
+			Similarity:  row.Similarity,
+			DeletedAt:   nullTimeToPointer(row.DeletedAt),
+		})
+	}
+	return candidates
+}
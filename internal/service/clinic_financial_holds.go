@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	FinancialHoldFraudSuspicion    = "FRAUD_SUSPICION"
+	FinancialHoldChargebackDispute = "CHARGEBACK_DISPUTE"
+	FinancialHoldComplianceReview  = "COMPLIANCE_REVIEW"
+	FinancialHoldOther             = "OTHER"
+
+	AuditEntityFinancialHold = "FINANCIAL_HOLD"
+
+	maxFinancialHoldNotesLength = 1000
+)
+
+var financialHoldReasonCodes = []string{
+	FinancialHoldFraudSuspicion,
+	FinancialHoldChargebackDispute,
+	FinancialHoldComplianceReview,
+	FinancialHoldOther,
+}
+
+func (s *Service) PlaceClinicFinancialHold(ctx context.Context, clinicID string, input PlaceFinancialHoldInput) (FinancialHoldOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.PlaceClinicFinancialHold")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return FinancialHoldOutput{}, unauthorizedError("missing authenticated user")
+	}
+	reasonCode := strings.ToUpper(strings.TrimSpace(input.ReasonCode))
+	if !slices.Contains(financialHoldReasonCodes, reasonCode) {
+		return FinancialHoldOutput{}, validationError(fmt.Sprintf("reason_code must be one of: %s", strings.Join(financialHoldReasonCodes, ", ")))
+	}
+	notes := optionalString(input.Notes)
+	if reasonCode == FinancialHoldOther && !notes.Valid {
+		return FinancialHoldOutput{}, validationError("notes are required when reason_code is OTHER")
+	}
+	if err := validateOptionalMaxLength("notes", input.Notes, maxFinancialHoldNotesLength); err != nil {
+		return FinancialHoldOutput{}, err
+	}
+	holdID, err := newUUIDV7()
+	if err != nil {
+		return FinancialHoldOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return FinancialHoldOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return FinancialHoldOutput{}, notFoundError("clinic not found")
+		}
+		return FinancialHoldOutput{}, err
+	}
+	if err := qtx.CreateClinicFinancialHold(ctx, repository.CreateClinicFinancialHoldParams{
+		OrganizationID: organizationID(ctx),
+		ID:             holdID,
+		ClinicID:       clinicID,
+		ReasonCode:     reasonCode,
+		Notes:          notes,
+		PlacedByUserID: principal.UserID,
+	}); err != nil {
+		if isUniqueConstraintError(err) {
+			return FinancialHoldOutput{}, conflictError(fmt.Sprintf("clinic already has an active %s hold", reasonCode))
+		}
+		return FinancialHoldOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "financial_hold.placed",
+		EntityType: AuditEntityFinancialHold,
+		EntityID:   holdID,
+		Metadata:   map[string]any{"reason_code": reasonCode},
+	}); err != nil {
+		return FinancialHoldOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return FinancialHoldOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.loadClinicFinancialHold(ctx, clinicID, holdID)
+}
+
+func (s *Service) ListClinicFinancialHolds(ctx context.Context, clinicID string, active *bool) ([]FinancialHoldOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicFinancialHolds")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+	return s.listClinicFinancialHolds(ctx, clinicID, active)
+}
+
+func (s *Service) LiftClinicFinancialHold(ctx context.Context, clinicID string, holdID string, input LiftFinancialHoldInput) (FinancialHoldOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.LiftClinicFinancialHold")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return FinancialHoldOutput{}, unauthorizedError("missing authenticated user")
+	}
+	notes := optionalString(input.Notes)
+	if !notes.Valid {
+		return FinancialHoldOutput{}, validationError("notes are required to lift a hold")
+	}
+	if err := validateOptionalMaxLength("notes", input.Notes, maxFinancialHoldNotesLength); err != nil {
+		return FinancialHoldOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return FinancialHoldOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	hold, err := qtx.GetClinicFinancialHold(ctx, repository.GetClinicFinancialHoldParams{
+		OrganizationID: organizationID(ctx),
+		ID:             holdID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return FinancialHoldOutput{}, notFoundError("financial hold not found")
+		}
+		return FinancialHoldOutput{}, err
+	}
+	updated, err := qtx.LiftClinicFinancialHold(ctx, repository.LiftClinicFinancialHoldParams{
+		OrganizationID: organizationID(ctx),
+		ID:             holdID,
+		ClinicID:       clinicID,
+		LiftedByUserID: principal.UserID,
+		LiftNotes:      notes,
+	})
+	if err != nil {
+		return FinancialHoldOutput{}, mapDatabaseError(err)
+	}
+	if updated == 0 {
+		return FinancialHoldOutput{}, conflictError("financial hold is already lifted")
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "financial_hold.lifted",
+		EntityType: AuditEntityFinancialHold,
+		EntityID:   holdID,
+		Metadata:   map[string]any{"reason_code": hold.ReasonCode},
+	}); err != nil {
+		return FinancialHoldOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return FinancialHoldOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.loadClinicFinancialHold(ctx, clinicID, holdID)
+}
+
+// Payouts and bank account changes call this inside their own transaction, so a hold placed concurrently wins.
+func ensureNoFinancialHold(ctx context.Context, q repository.Querier, clinicID string) error {
+	held, err := q.HasActiveClinicFinancialHold(ctx, repository.HasActiveClinicFinancialHoldParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return err
+	}
+	if held {
+		return financialHoldError("clinic has an active financial hold")
+	}
+	return nil
+}
+
+func (s *Service) listClinicFinancialHolds(ctx context.Context, clinicID string, active *bool) ([]FinancialHoldOutput, error) {
+	activeFilter := sql.NullBool{}
+	if active != nil {
+		activeFilter = sql.NullBool{Bool: *active, Valid: true}
+	}
+	rows, err := s.queries.ListClinicFinancialHolds(ctx, repository.ListClinicFinancialHoldsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		Active:         activeFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	holds := make([]FinancialHoldOutput, 0, len(rows))
+	for _, row := range rows {
+		holds = append(holds, mapFinancialHold(repository.GetClinicFinancialHoldRow(row)))
+	}
+	return holds, nil
+}
+
+func (s *Service) loadClinicFinancialHold(ctx context.Context, clinicID string, holdID string) (FinancialHoldOutput, error) {
+	hold, err := s.queries.GetClinicFinancialHold(ctx, repository.GetClinicFinancialHoldParams{
+		OrganizationID: organizationID(ctx),
+		ID:             holdID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return FinancialHoldOutput{}, notFoundError("financial hold not found")
+		}
+		return FinancialHoldOutput{}, err
+	}
+	return mapFinancialHold(hold), nil
+}
+
+func mapFinancialHold(hold repository.GetClinicFinancialHoldRow) FinancialHoldOutput {
+	return FinancialHoldOutput{
+		ID:             hold.ID,
+		ClinicID:       hold.ClinicID,
+		ReasonCode:     hold.ReasonCode,
+		Notes:          nullToPointer(hold.Notes),
+		Active:         !hold.LiftedAt.Valid,
+		PlacedByUserID: hold.PlacedByUserID,
+		PlacedByEmail:  hold.PlacedByEmail,
+		PlacedAt:       hold.PlacedAt,
+		LiftedByUserID: nullUUIDToPointer(hold.LiftedByUserID),
+		LiftedByEmail:  nullToPointer(hold.LiftedByEmail),
+		LiftedAt:       nullTimeToPointer(hold.LiftedAt),
+		LiftNotes:      nullToPointer(hold.LiftNotes),
+	}
+}
@@ -0,0 +1,309 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	NoteMentionClinic      = "CLINIC"
+	NoteMentionDentist     = "DENTIST"
+	NoteMentionBankAccount = "BANK_ACCOUNT"
+
+	maxClinicNoteBodyLength = 5000
+	maxClinicNoteMentions   = 20
+)
+
+func (s *Service) CreateClinicNote(ctx context.Context, clinicID string, input CreateClinicNoteInput) (ClinicNoteOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinicNote")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return ClinicNoteOutput{}, unauthorizedError("missing authenticated user")
+	}
+	body := strings.TrimSpace(input.Body)
+	if body == "" {
+		return ClinicNoteOutput{}, validationError("body is required")
+	}
+	if err := validateMaxLength("body", body, maxClinicNoteBodyLength); err != nil {
+		return ClinicNoteOutput{}, err
+	}
+	mentions, err := parseNoteMentions(input.Mentions)
+	if err != nil {
+		return ClinicNoteOutput{}, err
+	}
+	noteID, err := newUUIDV7()
+	if err != nil {
+		return ClinicNoteOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicNoteOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicNoteOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicNoteOutput{}, err
+	}
+	for idx, mention := range mentions {
+		if err := ensureNoteMentionExists(ctx, qtx, clinicID, mention); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ClinicNoteOutput{}, validationError(fmt.Sprintf("mentions[%d] references an unknown %s", idx, strings.ToLower(mention.EntityType)))
+			}
+			return ClinicNoteOutput{}, err
+		}
+	}
+
+	if _, err := qtx.CreateClinicNote(ctx, repository.CreateClinicNoteParams{
+		OrganizationID: organizationID(ctx),
+		ID:             noteID,
+		ClinicID:       clinicID,
+		AuthorUserID:   principal.UserID,
+		Body:           body,
+		Pinned:         input.Pinned,
+	}); err != nil {
+		return ClinicNoteOutput{}, mapDatabaseError(err)
+	}
+	for _, mention := range mentions {
+		if err := qtx.CreateClinicNoteMention(ctx, repository.CreateClinicNoteMentionParams{
+			OrganizationID: organizationID(ctx),
+			NoteID:         noteID,
+			EntityType:     mention.EntityType,
+			EntityID:       mention.EntityID,
+		}); err != nil {
+			return ClinicNoteOutput{}, mapDatabaseError(err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicNoteOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.loadClinicNote(ctx, clinicID, noteID)
+}
+
+func (s *Service) ListClinicNotesWithCursor(ctx context.Context, clinicID string, limit int, cursor *string, pinned *bool) ([]ClinicNoteOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicNotesWithCursor")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, notFoundError("clinic not found")
+		}
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	queryLimit := int32(pageLimit + 1)
+
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	pinnedFilter := sql.NullBool{}
+	if pinned != nil {
+		pinnedFilter = sql.NullBool{Bool: *pinned, Valid: true}
+	}
+
+	rows, err := s.queries.ListClinicNotesCursor(ctx, repository.ListClinicNotesCursorParams{
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		Pinned:          pinnedFilter,
+		CursorCreatedAt: after.At,
+		CursorID:        after.ID,
+		PageLimit:       queryLimit,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+
+	noteIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		noteIDs = append(noteIDs, row.ID)
+	}
+	mentionsByNote, err := s.loadNoteMentions(ctx, noteIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	notes := make([]ClinicNoteOutput, 0, len(rows))
+	for _, row := range rows {
+		notes = append(notes, mapClinicNote(repository.GetClinicNoteDetailsRow(row), mentionsByNote[row.ID]))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return notes, nextCursor, nil
+}
+
+func (s *Service) UpdateClinicNote(ctx context.Context, clinicID string, noteID string, input UpdateClinicNoteInput) (ClinicNoteOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateClinicNote")
+	defer span.End()
+
+	// Notes are an append-only timeline; only the pinned flag can change after creation.
+	if input.Pinned == nil {
+		return ClinicNoteOutput{}, validationError("pinned is required")
+	}
+	rows, err := s.queries.UpdateClinicNotePinned(ctx, repository.UpdateClinicNotePinnedParams{
+		OrganizationID: organizationID(ctx),
+		ID:             noteID,
+		ClinicID:       clinicID,
+		Pinned:         *input.Pinned,
+	})
+	if err != nil {
+		return ClinicNoteOutput{}, mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return ClinicNoteOutput{}, notFoundError("note not found")
+	}
+	return s.loadClinicNote(ctx, clinicID, noteID)
+}
+
+func (s *Service) DeleteClinicNote(ctx context.Context, clinicID string, noteID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteClinicNote")
+	defer span.End()
+
+	rows, err := s.queries.DeleteClinicNote(ctx, repository.DeleteClinicNoteParams{
+		OrganizationID: organizationID(ctx),
+		ID:             noteID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return notFoundError("note not found")
+	}
+	return nil
+}
+
+func (s *Service) loadClinicNote(ctx context.Context, clinicID string, noteID string) (ClinicNoteOutput, error) {
+	note, err := s.queries.GetClinicNoteDetails(ctx, repository.GetClinicNoteDetailsParams{
+		OrganizationID: organizationID(ctx),
+		ID:             noteID,
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicNoteOutput{}, notFoundError("note not found")
+		}
+		return ClinicNoteOutput{}, err
+	}
+	// Read directly rather than through the shared list-page lookup, since this follows the note's own writes.
+	mentionsByNote, err := s.listNoteMentions(ctx, []string{noteID})
+	if err != nil {
+		return ClinicNoteOutput{}, err
+	}
+	return mapClinicNote(note, mentionsByNote[noteID]), nil
+}
+
+func (s *Service) loadNoteMentions(ctx context.Context, noteIDs []string) (map[string][]NoteMentionOutput, error) {
+	return loadBatch(ctx, s, "clinic_note_mentions", noteIDs, s.listNoteMentions)
+}
+
+func (s *Service) listNoteMentions(ctx context.Context, noteIDs []string) (map[string][]NoteMentionOutput, error) {
+	rows, err := s.queries.ListClinicNoteMentionsByNoteIDs(ctx, repository.ListClinicNoteMentionsByNoteIDsParams{
+		OrganizationID: organizationID(ctx),
+		NoteIds:        noteIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	mentionsByNote := make(map[string][]NoteMentionOutput, len(noteIDs))
+	for _, row := range rows {
+		mentionsByNote[row.NoteID] = append(mentionsByNote[row.NoteID], NoteMentionOutput{
+			EntityType: row.EntityType,
+			EntityID:   row.EntityID,
+		})
+	}
+	return mentionsByNote, nil
+}
+
+func parseNoteMentions(input []NoteMentionInput) ([]NoteMentionOutput, error) {
+	if len(input) > maxClinicNoteMentions {
+		return nil, validationError(fmt.Sprintf("mentions accepts at most %d entries", maxClinicNoteMentions))
+	}
+	mentions := make([]NoteMentionOutput, 0, len(input))
+	for idx, item := range input {
+		entityType := strings.ToUpper(strings.TrimSpace(item.EntityType))
+		switch entityType {
+		case NoteMentionClinic, NoteMentionDentist, NoteMentionBankAccount:
+		default:
+			return nil, validationError(fmt.Sprintf("mentions[%d].entity_type must be one of: %s, %s, %s", idx, NoteMentionClinic, NoteMentionDentist, NoteMentionBankAccount))
+		}
+		parsed, err := uuid.Parse(strings.TrimSpace(item.EntityID))
+		if err != nil || parsed.Version() != 7 {
+			return nil, validationError(fmt.Sprintf("mentions[%d].entity_id must be a UUIDv7", idx))
+		}
+		mentions = append(mentions, NoteMentionOutput{EntityType: entityType, EntityID: parsed.String()})
+	}
+	return mentions, nil
+}
+
+func ensureNoteMentionExists(ctx context.Context, qtx repository.Querier, clinicID string, mention NoteMentionOutput) error {
+	var err error
+	switch mention.EntityType {
+	case NoteMentionClinic:
+		_, err = qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             mention.EntityID,
+		})
+	case NoteMentionDentist:
+		_, err = qtx.GetDentistByID(ctx, repository.GetDentistByIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             mention.EntityID,
+		})
+	case NoteMentionBankAccount:
+		// Bank accounts are only meaningful in the clinic that owns them.
+		_, err = qtx.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             mention.EntityID,
+			ClinicID:       clinicID,
+		})
+	}
+	return err
+}
+
+func mapClinicNote(note repository.GetClinicNoteDetailsRow, mentions []NoteMentionOutput) ClinicNoteOutput {
+	if mentions == nil {
+		mentions = make([]NoteMentionOutput, 0)
+	}
+	return ClinicNoteOutput{
+		ID:           note.ID,
+		ClinicID:     note.ClinicID,
+		AuthorUserID: note.AuthorUserID,
+		AuthorEmail:  note.AuthorEmail,
+		Body:         note.Body,
+		Pinned:       note.Pinned,
+		Mentions:     mentions,
+		CreatedAt:    note.CreatedAt,
+		UpdatedAt:    note.UpdatedAt,
+	}
+}
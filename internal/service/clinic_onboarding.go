@@ -0,0 +1,280 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	OnboardingStatusDraft            = "DRAFT"
+	OnboardingStatusDocumentsPending = "DOCUMENTS_PENDING"
+	OnboardingStatusBankVerified     = "BANK_VERIFIED"
+	OnboardingStatusActive           = "ACTIVE"
+
+	maxEvidenceReferenceLength = 255
+	maxOnboardingNotesLength   = 1000
+	maxStuckForDays            = 365
+
+	eventClinicOnboardingChanged = "clinic.onboarding_status_changed"
+)
+
+var onboardingStatuses = []string{
+	OnboardingStatusDraft,
+	OnboardingStatusDocumentsPending,
+	OnboardingStatusBankVerified,
+	OnboardingStatusActive,
+}
+
+func (s *Service) GetClinicOnboarding(ctx context.Context, clinicID string) (ClinicOnboardingOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicOnboarding")
+	defer span.End()
+
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicOnboardingOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicOnboardingOutput{}, err
+	}
+	transitions, err := s.queries.ListClinicOnboardingTransitions(ctx, repository.ListClinicOnboardingTransitionsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return ClinicOnboardingOutput{}, err
+	}
+	return mapClinicOnboarding(clinic, transitions), nil
+}
+
+func (s *Service) AdvanceClinicOnboarding(ctx context.Context, clinicID string, input AdvanceClinicOnboardingInput) (ClinicOnboardingOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.AdvanceClinicOnboarding")
+	defer span.End()
+
+	toStatus, err := parseOnboardingStatus("to_status", input.ToStatus)
+	if err != nil {
+		return ClinicOnboardingOutput{}, err
+	}
+	reference := strings.TrimSpace(input.EvidenceReference)
+	if reference == "" {
+		return ClinicOnboardingOutput{}, validationError("evidence_reference is required")
+	}
+	if err := validateMaxLength("evidence_reference", reference, maxEvidenceReferenceLength); err != nil {
+		return ClinicOnboardingOutput{}, err
+	}
+	if err := validateOptionalMaxLength("notes", input.Notes, maxOnboardingNotesLength); err != nil {
+		return ClinicOnboardingOutput{}, err
+	}
+	bankAccountID := uuid.NullUUID{}
+	if toStatus == OnboardingStatusBankVerified {
+		if input.BankAccountID == nil || strings.TrimSpace(*input.BankAccountID) == "" {
+			return ClinicOnboardingOutput{}, validationError("bank_account_id is required to verify the bank account")
+		}
+		parsed, err := uuid.Parse(strings.TrimSpace(*input.BankAccountID))
+		if err != nil || parsed.Version() != 7 {
+			return ClinicOnboardingOutput{}, validationError("bank_account_id must be a UUIDv7")
+		}
+		bankAccountID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+
+	transitionID, err := newUUIDV7()
+	if err != nil {
+		return ClinicOnboardingOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicOnboardingOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicOnboardingOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicOnboardingOutput{}, mapDatabaseError(err)
+	}
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicOnboardingOutput{}, err
+	}
+	clinic, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicOnboardingOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicOnboardingOutput{}, err
+	}
+
+	next, ok := nextOnboardingStatus(clinic.OnboardingStatus)
+	if !ok {
+		return ClinicOnboardingOutput{}, conflictError("clinic onboarding is already complete")
+	}
+	if toStatus != next {
+		return ClinicOnboardingOutput{}, conflictError(fmt.Sprintf("cannot move onboarding from %s to %s; the next status is %s", clinic.OnboardingStatus, toStatus, next))
+	}
+	if err := ensureOnboardingRequirements(ctx, qtx, clinic, toStatus, bankAccountID); err != nil {
+		return ClinicOnboardingOutput{}, err
+	}
+
+	updated, err := qtx.UpdateClinicOnboardingStatus(ctx, repository.UpdateClinicOnboardingStatusParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+		FromStatus:     clinic.OnboardingStatus,
+		ToStatus:       toStatus,
+	})
+	if err != nil {
+		return ClinicOnboardingOutput{}, mapDatabaseError(err)
+	}
+	if updated == 0 {
+		return ClinicOnboardingOutput{}, conflictError("clinic onboarding status changed concurrently; retry")
+	}
+
+	performedBy := uuid.NullUUID{}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if parsed, err := uuid.Parse(principal.UserID); err == nil {
+			performedBy = uuid.NullUUID{UUID: parsed, Valid: true}
+		}
+	}
+	if _, err := qtx.CreateClinicOnboardingTransition(ctx, repository.CreateClinicOnboardingTransitionParams{
+		OrganizationID:    organizationID(ctx),
+		ID:                transitionID,
+		ClinicID:          clinicID,
+		FromStatus:        clinic.OnboardingStatus,
+		ToStatus:          toStatus,
+		EvidenceReference: reference,
+		BankAccountID:     bankAccountID,
+		Notes:             optionalString(input.Notes),
+		PerformedByUserID: performedBy,
+	}); err != nil {
+		return ClinicOnboardingOutput{}, mapDatabaseError(err)
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return ClinicOnboardingOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicOnboardingOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	s.publishOnboardingChange(ctx, clinicID, clinic.OnboardingStatus, toStatus)
+	return s.GetClinicOnboarding(ctx, clinicID)
+}
+
+func ensureOnboardingRequirements(ctx context.Context, qtx repository.Querier, clinic repository.Clinic, toStatus string, bankAccountID uuid.NullUUID) error {
+	switch toStatus {
+	case OnboardingStatusDocumentsPending:
+		if _, err := qtx.GetAddressByPersonID(ctx, repository.GetAddressByPersonIDParams{
+			OrganizationID: organizationID(ctx),
+			PersonID:       clinic.PersonID,
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return validationError("clinic address is required before documents can be submitted")
+			}
+			return err
+		}
+	case OnboardingStatusBankVerified:
+		accounts, err := qtx.ListBankAccountsByClinicID(ctx, repository.ListBankAccountsByClinicIDParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinic.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if !slices.ContainsFunc(accounts, func(account repository.BankAccount) bool {
+			return account.ID == bankAccountID.UUID.String()
+		}) {
+			return validationError("bank_account_id must be an active bank account of the clinic")
+		}
+	case OnboardingStatusActive:
+		counts, err := qtx.CountClinicRoleHolders(ctx, repository.CountClinicRoleHoldersParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinic.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if counts.LegalRepresentatives == 0 {
+			return validationError("clinic needs an active legal representative before activation")
+		}
+	}
+	return nil
+}
+
+func (s *Service) publishOnboardingChange(ctx context.Context, clinicID string, fromStatus string, toStatus string) {
+	if !s.webhooksEnabled(ctx) {
+		return
+	}
+	event, err := s.newEvent(eventClinicOnboardingChanged, map[string]string{
+		"clinic_id":   clinicID,
+		"from_status": fromStatus,
+		"to_status":   toStatus,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, event)
+	}
+	// The transition is already committed; a lost notification must not fail the request.
+	if err != nil {
+		slog.WarnContext(ctx, "publish onboarding event failed", "clinic_id", clinicID, "error", err)
+	}
+}
+
+func nextOnboardingStatus(status string) (string, bool) {
+	idx := slices.Index(onboardingStatuses, status)
+	if idx < 0 || idx == len(onboardingStatuses)-1 {
+		return "", false
+	}
+	return onboardingStatuses[idx+1], true
+}
+
+func parseOnboardingStatus(field string, value string) (string, error) {
+	status := strings.ToUpper(strings.TrimSpace(value))
+	if !slices.Contains(onboardingStatuses, status) {
+		return "", validationError(fmt.Sprintf("%s must be one of: %s", field, strings.Join(onboardingStatuses, ", ")))
+	}
+	return status, nil
+}
+
+func mapClinicOnboarding(clinic repository.Clinic, transitions []repository.ClinicOnboardingTransition) ClinicOnboardingOutput {
+	output := ClinicOnboardingOutput{
+		ClinicID:        clinic.ID,
+		Status:          clinic.OnboardingStatus,
+		StatusChangedAt: clinic.OnboardingStatusChangedAt,
+		Transitions:     make([]ClinicOnboardingTransitionOutput, 0, len(transitions)),
+	}
+	if next, ok := nextOnboardingStatus(clinic.OnboardingStatus); ok {
+		output.NextStatus = &next
+	}
+	for _, transition := range transitions {
+		output.Transitions = append(output.Transitions, ClinicOnboardingTransitionOutput{
+			ID:                transition.ID,
+			FromStatus:        transition.FromStatus,
+			ToStatus:          transition.ToStatus,
+			EvidenceReference: transition.EvidenceReference,
+			BankAccountID:     nullUUIDToPointer(transition.BankAccountID),
+			Notes:             nullToPointer(transition.Notes),
+			PerformedByUserID: nullUUIDToPointer(transition.PerformedByUserID),
+			CreatedAt:         transition.CreatedAt,
+		})
+	}
+	return output
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	RevisionEntityPerson      = "PERSON"
+	RevisionEntityClinic      = "CLINIC"
+	RevisionEntityBankAccount = "BANK_ACCOUNT"
+
+	RevisionOperationCreate = "CREATE"
+	RevisionOperationUpdate = "UPDATE"
+	RevisionOperationDelete = "DELETE"
+)
+
+type personRevisionState struct {
+	PersonType  string                `json:"person_type"`
+	TaxIDType   string                `json:"tax_id_type"`
+	TaxIDNumber string                `json:"tax_id_number"`
+	LegalName   string                `json:"legal_name"`
+	TradeName   *string               `json:"trade_name"`
+	Email       *string               `json:"email"`
+	Phone       *string               `json:"phone"`
+	Address     *addressRevisionState `json:"address"`
+	DeletedAt   *time.Time            `json:"deleted_at"`
+}
+
+type addressRevisionState struct {
+	Street     string  `json:"street"`
+	Number     *string `json:"number"`
+	Complement *string `json:"complement"`
+	City       string  `json:"city"`
+	State      string  `json:"state"`
+	CEP        string  `json:"cep"`
+}
+
+type clinicRevisionState struct {
+	PersonID                  string     `json:"person_id"`
+	ParentClinicID            *string    `json:"parent_clinic_id"`
+	Timezone                  string     `json:"timezone"`
+	OnboardingStatus          string     `json:"onboarding_status"`
+	OnboardingStatusChangedAt time.Time  `json:"onboarding_status_changed_at"`
+	DeactivatedAt             *time.Time `json:"deactivated_at"`
+	DeactivationReason        *string    `json:"deactivation_reason"`
+	DeletedAt                 *time.Time `json:"deleted_at"`
+}
+
+// Account numbers are stored masked: revisions are read by support staff who do not hold the reveal permission.
+type bankAccountRevisionState struct {
+	BankCode                  string     `json:"bank_code"`
+	BranchNumber              string     `json:"branch_number"`
+	AccountNumber             string     `json:"account_number"`
+	AccountType               string     `json:"account_type"`
+	HolderName                *string    `json:"holder_name"`
+	HolderTaxID               *string    `json:"holder_tax_id"`
+	HolderReviewRequired      bool       `json:"holder_review_required"`
+	IsPrimary                 bool       `json:"is_primary"`
+	VerificationStatus        string     `json:"verification_status"`
+	VerificationFailureReason *string    `json:"verification_failure_reason"`
+	VerifiedAt                *time.Time `json:"verified_at"`
+	DeletedAt                 *time.Time `json:"deleted_at"`
+}
+
+type revisionState struct {
+	entityType string
+	entityID   string
+	state      json.RawMessage
+	deleted    bool
+	createdAt  time.Time
+}
+
+type clinicRevisionSnapshot struct {
+	clinicID string
+	keys     []string
+	states   map[string]revisionState
+}
+
+func (s *Service) ListClinicRevisions(ctx context.Context, clinicID string, limit int, cursor *string, entityType *string) ([]ClinicRevisionOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicRevisions")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	typeFilter := sql.NullString{}
+	if entityType != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*entityType))
+		switch normalized {
+		case RevisionEntityPerson, RevisionEntityClinic, RevisionEntityBankAccount:
+		default:
+			return nil, nil, validationError(fmt.Sprintf("entity_type must be one of: %s, %s, %s", RevisionEntityPerson, RevisionEntityClinic, RevisionEntityBankAccount))
+		}
+		typeFilter = sql.NullString{String: normalized, Valid: true}
+	}
+
+	// Deleted clinics keep their timeline so support can see what happened before the deletion.
+	if _, err := s.queries.GetClinicForRevision(ctx, repository.GetClinicForRevisionParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, notFoundError("clinic not found")
+		}
+		return nil, nil, err
+	}
+
+	rows, err := s.queries.ListClinicRevisionsCursor(ctx, repository.ListClinicRevisionsCursorParams{
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		EntityType:      typeFilter,
+		CursorChangedAt: after.At,
+		CursorID:        after.ID,
+		PageLimit:       int32(pageLimit + 1),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+
+	revisions := make([]ClinicRevisionOutput, 0, len(rows))
+	for _, row := range rows {
+		changes, err := diffRevisionStates(row.BeforeState, row.AfterState)
+		if err != nil {
+			return nil, nil, err
+		}
+		revisions = append(revisions, ClinicRevisionOutput{
+			ID:              row.ID,
+			EntityType:      row.EntityType,
+			EntityID:        row.EntityID,
+			Operation:       row.Operation,
+			ChangedByUserID: nullUUIDToPointer(row.ChangedByUserID),
+			ChangedByEmail:  nullToPointer(row.ChangedByEmail),
+			ChangedAt:       row.ChangedAt,
+			Changes:         changes,
+			Before:          row.BeforeState,
+			After:           row.AfterState,
+		})
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.ChangedAt, Valid: true}, last.ID)
+	}
+	return revisions, nextCursor, nil
+}
+
+func loadClinicRevisionSnapshot(ctx context.Context, q repository.Querier, clinicID string) (clinicRevisionSnapshot, error) {
+	snapshot := clinicRevisionSnapshot{clinicID: clinicID, states: map[string]revisionState{}}
+	clinic, err := q.GetClinicForRevision(ctx, repository.GetClinicForRevisionParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return snapshot, nil
+		}
+		return clinicRevisionSnapshot{}, err
+	}
+	person, err := q.GetPersonIncludingDeleted(ctx, repository.GetPersonIncludingDeletedParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinic.PersonID,
+	})
+	if err != nil {
+		return clinicRevisionSnapshot{}, err
+	}
+	var address *repository.Address
+	if row, err := q.GetAddressByPersonID(ctx, repository.GetAddressByPersonIDParams{
+		OrganizationID: organizationID(ctx),
+		PersonID:       person.ID,
+	}); err == nil {
+		address = &row
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return clinicRevisionSnapshot{}, err
+	}
+	accounts, err := q.ListBankAccountsForRevision(ctx, repository.ListBankAccountsForRevisionParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return clinicRevisionSnapshot{}, err
+	}
+
+	if err := snapshot.add(RevisionEntityPerson, person.ID, newPersonRevisionState(person, address), person.DeletedAt.Valid, person.CreatedAt); err != nil {
+		return clinicRevisionSnapshot{}, err
+	}
+	if err := snapshot.add(RevisionEntityClinic, clinic.ID, newClinicRevisionState(clinic), clinic.DeletedAt.Valid, clinic.CreatedAt); err != nil {
+		return clinicRevisionSnapshot{}, err
+	}
+	for _, account := range accounts {
+		if err := snapshot.add(RevisionEntityBankAccount, account.ID, newBankAccountRevisionState(account), account.DeletedAt.Valid, account.CreatedAt); err != nil {
+			return clinicRevisionSnapshot{}, err
+		}
+	}
+	return snapshot, nil
+}
+
+func (snapshot *clinicRevisionSnapshot) add(entityType string, entityID string, state any, deleted bool, createdAt time.Time) error {
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode %s revision: %w", strings.ToLower(entityType), err)
+	}
+	key := entityType + ":" + entityID
+	snapshot.keys = append(snapshot.keys, key)
+	snapshot.states[key] = revisionState{entityType: entityType, entityID: entityID, state: encoded, deleted: deleted, createdAt: createdAt}
+	return nil
+}
+
+// Callers take the "before" snapshot at the start of the write and pass it here right before committing, so each
+// changed entity gets one revision with its prior and new state, written in the same transaction as the change.
+func recordClinicRevisions(ctx context.Context, q repository.Querier, before clinicRevisionSnapshot) error {
+	after, err := loadClinicRevisionSnapshot(ctx, q, before.clinicID)
+	if err != nil {
+		return err
+	}
+	if len(after.keys) == 0 {
+		return nil
+	}
+
+	changedBy := uuid.NullUUID{}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if parsed, err := uuid.Parse(principal.UserID); err == nil {
+			changedBy = uuid.NullUUID{UUID: parsed, Valid: true}
+		}
+	}
+
+	for _, key := range after.keys {
+		current := after.states[key]
+		previous, existed := before.states[key]
+		operation := RevisionOperationCreate
+		beforeState := json.RawMessage("null")
+		if existed {
+			if bytes.Equal(previous.state, current.state) {
+				continue
+			}
+			operation = RevisionOperationUpdate
+			if current.deleted && !previous.deleted {
+				operation = RevisionOperationDelete
+			}
+			beforeState = previous.state
+		}
+
+		id, err := newUUIDV7()
+		if err != nil {
+			return err
+		}
+		if err := q.CreateClinicRevision(ctx, repository.CreateClinicRevisionParams{
+			OrganizationID:  organizationID(ctx),
+			ID:              id,
+			ClinicID:        before.clinicID,
+			EntityType:      current.entityType,
+			EntityID:        current.entityID,
+			Operation:       operation,
+			BeforeState:     beforeState,
+			AfterState:      current.state,
+			ChangedByUserID: changedBy,
+		}); err != nil {
+			return mapDatabaseError(err)
+		}
+	}
+	return nil
+}
+
+func newPersonRevisionState(person repository.Person, address *repository.Address) personRevisionState {
+	state := personRevisionState{
+		PersonType:  person.PersonType,
+		TaxIDType:   person.TaxIDType,
+		TaxIDNumber: person.TaxIDNumber,
+		LegalName:   person.LegalName,
+		TradeName:   nullToPointer(person.TradeName),
+		Email:       nullToPointer(person.Email),
+		Phone:       nullToPointer(person.Phone),
+		DeletedAt:   nullTimeToPointer(person.DeletedAt),
+	}
+	if address != nil {
+		state.Address = &addressRevisionState{
+			Street:     address.Street,
+			Number:     nullToPointer(address.Number),
+			Complement: nullToPointer(address.Complement),
+			City:       address.City,
+			State:      address.State,
+			CEP:        address.Cep,
+		}
+	}
+	return state
+}
+
+func newClinicRevisionState(clinic repository.Clinic) clinicRevisionState {
+	return clinicRevisionState{
+		PersonID:                  clinic.PersonID,
+		ParentClinicID:            nullUUIDToPointer(clinic.ParentClinicID),
+		Timezone:                  clinic.Timezone,
+		OnboardingStatus:          clinic.OnboardingStatus,
+		OnboardingStatusChangedAt: clinic.OnboardingStatusChangedAt,
+		DeactivatedAt:             nullTimeToPointer(clinic.DeactivatedAt),
+		DeactivationReason:        nullToPointer(clinic.DeactivationReason),
+		DeletedAt:                 nullTimeToPointer(clinic.DeletedAt),
+	}
+}
+
+func newBankAccountRevisionState(account repository.BankAccount) bankAccountRevisionState {
+	return bankAccountRevisionState{
+		BankCode:                  account.BankCode,
+		BranchNumber:              account.BranchNumber,
+		AccountNumber:             maskAccountNumber(account.AccountNumber),
+		AccountType:               account.AccountType,
+		HolderName:                nullToPointer(account.HolderName),
+		HolderTaxID:               nullToPointer(account.HolderTaxID),
+		HolderReviewRequired:      account.HolderReviewRequired,
+		IsPrimary:                 account.IsPrimary,
+		VerificationStatus:        account.VerificationStatus,
+		VerificationFailureReason: nullToPointer(account.VerificationFailureReason),
+		VerifiedAt:                nullTimeToPointer(account.VerifiedAt),
+		DeletedAt:                 nullTimeToPointer(account.DeletedAt),
+	}
+}
+
+func diffRevisionStates(before json.RawMessage, after json.RawMessage) ([]RevisionChangeOutput, error) {
+	beforeFields := map[string]any{}
+	afterFields := map[string]any{}
+	if err := flattenRevisionState(before, beforeFields); err != nil {
+		return nil, err
+	}
+	if err := flattenRevisionState(after, afterFields); err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, 0, len(afterFields))
+	for field := range afterFields {
+		fields = append(fields, field)
+	}
+	for field := range beforeFields {
+		if _, ok := afterFields[field]; !ok {
+			fields = append(fields, field)
+		}
+	}
+	sort.Strings(fields)
+
+	changes := make([]RevisionChangeOutput, 0)
+	for _, field := range fields {
+		from, to := beforeFields[field], afterFields[field]
+		if reflect.DeepEqual(from, to) {
+			continue
+		}
+		changes = append(changes, RevisionChangeOutput{Field: field, From: from, To: to})
+	}
+	return changes, nil
+}
+
+// Nested objects such as the address are flattened to dotted paths so a single changed line shows up on its own.
+func flattenRevisionState(raw json.RawMessage, fields map[string]any) error {
+	var decoded map[string]any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return fmt.Errorf("decode revision state: %w", err)
+	}
+	flattenRevisionFields("", decoded, fields)
+	return nil
+}
+
+func flattenRevisionFields(prefix string, values map[string]any, fields map[string]any) {
+	for key, value := range values {
+		path := key
+		if prefix != "" {
+			path = prefix + "." + key
+		}
+		if nested, ok := value.(map[string]any); ok {
+			flattenRevisionFields(path, nested, fields)
+			continue
+		}
+		fields[path] = value
+	}
+}
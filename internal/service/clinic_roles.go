@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+func (s *Service) BulkUpdateClinicDentistRoles(ctx context.Context, clinicID string, input BulkUpdateClinicDentistRolesInput) ([]ClinicDentistOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.BulkUpdateClinicDentistRoles")
+	defer span.End()
+
+	if len(input.Updates) == 0 {
+		return nil, validationError("updates must contain at least one item")
+	}
+	seen := make(map[string]struct{}, len(input.Updates))
+	for idx, update := range input.Updates {
+		parsedID, err := uuid.Parse(strings.TrimSpace(update.DentistID))
+		if err != nil || parsedID.Version() != 7 {
+			return nil, validationError(fmt.Sprintf("updates[%d].dentist_id must be a UUIDv7", idx))
+		}
+		if _, ok := seen[parsedID.String()]; ok {
+			return nil, validationError(fmt.Sprintf("updates[%d].dentist_id is duplicated", idx))
+		}
+		seen[parsedID.String()] = struct{}{}
+		if update.IsAdmin == nil && update.IsLegalRepresentative == nil {
+			return nil, validationError(fmt.Sprintf("updates[%d] must provide at least one role field", idx))
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	before, err := lockClinicRoleCounts(ctx, qtx, clinicID)
+	if err != nil {
+		return nil, err
+	}
+
+	relations := make([]repository.ClinicDentist, 0, len(input.Updates))
+	for _, update := range input.Updates {
+		relation, err := qtx.UpdateClinicDentistRole(ctx, repository.UpdateClinicDentistRoleParams{
+			OrganizationID:        organizationID(ctx),
+			ClinicID:              clinicID,
+			DentistID:             strings.TrimSpace(update.DentistID),
+			IsAdmin:               optionalBool(update.IsAdmin),
+			IsLegalRepresentative: optionalBool(update.IsLegalRepresentative),
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, notFoundError(fmt.Sprintf("clinic dentist active link not found for dentist %s", strings.TrimSpace(update.DentistID)))
+			}
+			return nil, mapDatabaseError(err)
+		}
+		relations = append(relations, relation)
+	}
+	if err := ensureClinicRoleInvariant(ctx, qtx, clinicID, before); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	output := make([]ClinicDentistOutput, 0, len(relations))
+	for _, relation := range relations {
+		dentist, err := s.loadClinicDentistOutput(ctx, relation)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, dentist)
+	}
+	return output, nil
+}
+
+func lockClinicRoleCounts(ctx context.Context, qtx repository.Querier, clinicID string) (repository.CountClinicRoleHoldersRow, error) {
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.CountClinicRoleHoldersRow{}, notFoundError("clinic not found")
+		}
+		return repository.CountClinicRoleHoldersRow{}, mapDatabaseError(err)
+	}
+	counts, err := qtx.CountClinicRoleHolders(ctx, repository.CountClinicRoleHoldersParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return repository.CountClinicRoleHoldersRow{}, mapDatabaseError(err)
+	}
+	return counts, nil
+}
+
+func ensureClinicRoleInvariant(ctx context.Context, qtx repository.Querier, clinicID string, before repository.CountClinicRoleHoldersRow) error {
+	after, err := qtx.CountClinicRoleHolders(ctx, repository.CountClinicRoleHoldersParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	return checkClinicRoleInvariant(before, after)
+}
+
+func checkClinicRoleInvariant(before repository.CountClinicRoleHoldersRow, after repository.CountClinicRoleHoldersRow) error {
+	// Clinics still being staffed may not have role holders yet, and a clinic left without any
+	// active dentist has nobody to hold the roles; only block changes that remove the last holder.
+	if after.ActiveDentists == 0 {
+		return nil
+	}
+	if before.Admins > 0 && after.Admins == 0 {
+		return roleInvariantError("clinic must keep at least one active admin")
+	}
+	if before.LegalRepresentatives > 0 && after.LegalRepresentatives == 0 {
+		return roleInvariantError("clinic must keep at least one active legal representative")
+	}
+	return nil
+}
+
+func (s *Service) loadClinicDentistOutput(ctx context.Context, relation repository.ClinicDentist) (ClinicDentistOutput, error) {
+	details, err := s.queries.GetDentistDetailsByID(ctx, repository.GetDentistDetailsByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             relation.DentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicDentistOutput{}, notFoundError("dentist not found")
+		}
+		return ClinicDentistOutput{}, err
+	}
+	address, err := s.loadAddress(ctx, details.PersonID)
+	if err != nil {
+		return ClinicDentistOutput{}, err
+	}
+	specialties, err := s.loadDentistSpecialties(ctx, details.DentistID)
+	if err != nil {
+		return ClinicDentistOutput{}, err
+	}
+
+	output := ClinicDentistOutput{
+		DentistOutput: DentistOutput{
+			ID:           details.DentistID,
+			PersonID:     details.PersonID,
+			LegalName:    details.LegalName,
+			TaxIDNumber:  details.TaxIDNumber,
+			Email:        nullToPointer(details.Email),
+			Phone:        nullToPointer(details.Phone),
+			PhoneDisplay: phoneDisplay(details.Phone),
+			CRONumber:    nullToPointer(details.CroNumber),
+			CROState:     nullToPointer(details.CroState),
+			PhotoURL:     s.dentistPhotoURL(details.DentistID, details.PhotoUpdatedAt),
+			ThumbnailURL: s.dentistThumbnailURL(details.DentistID, details.PhotoUpdatedAt),
+			Address:      address,
+			Specialties:  specialties,
+		},
+		IsAdmin:               relation.IsAdmin,
+		IsLegalRepresentative: relation.IsLegalRepresentative,
+		StartedAt:             relation.StartedAt,
+	}
+	applyAssignment(&output, relation.PlannedEndAt, relation.SubstituteForDentistID)
+	return output, nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	DefaultAppointmentDurationMinutes = 30
+	DefaultInvoiceNumberPrefix        = "INV"
+	DefaultClinicLocale               = "pt-BR"
+	DefaultClinicCurrency             = "BRL"
+
+	minAppointmentDurationMinutes = 5
+	maxAppointmentDurationMinutes = 480
+	appointmentDurationStep       = 5
+	maxReminderLeadTimes          = 5
+	minReminderLeadMinutes        = 5
+	maxReminderLeadMinutes        = 30 * 24 * 60
+)
+
+var defaultReminderLeadMinutes = []int{24 * 60, 2 * 60}
+
+var invoicePrefixPattern = regexp.MustCompile(`^[A-Z0-9][A-Z0-9-]{0,9}$`)
+
+var supportedClinicLocales = []string{"en-US", "es-ES", "pt-BR"}
+
+var supportedClinicCurrencies = []string{"BRL", "EUR", "USD"}
+
+func (s *Service) GetClinicSettings(ctx context.Context, clinicID string) (ClinicSettingsOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicSettings")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicSettingsOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicSettingsOutput{}, err
+	}
+	return cachedReference(ctx, s, referenceKey(ctx, referenceClinicSettings, clinicID), func(ctx context.Context) (ClinicSettingsOutput, error) {
+		return loadClinicSettings(ctx, s.queries, clinicID)
+	})
+}
+
+func (s *Service) UpdateClinicSettings(ctx context.Context, clinicID string, input UpdateClinicSettingsInput) (ClinicSettingsOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateClinicSettings")
+	defer span.End()
+
+	if input.DefaultAppointmentDurationMinutes == nil &&
+		input.ReminderLeadMinutes == nil &&
+		input.InvoiceNumberPrefix == nil &&
+		input.Locale == nil &&
+		input.Currency == nil {
+		return ClinicSettingsOutput{}, validationError("at least one field must be provided")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicSettingsOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicSettingsOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicSettingsOutput{}, mapDatabaseError(err)
+	}
+
+	current, err := loadClinicSettings(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicSettingsOutput{}, err
+	}
+	params, err := applyClinicSettingsInput(current, input)
+	if err != nil {
+		return ClinicSettingsOutput{}, err
+	}
+	params.OrganizationID = organizationID(ctx)
+
+	settings, err := qtx.UpsertClinicSettings(ctx, params)
+	if err != nil {
+		return ClinicSettingsOutput{}, mapDatabaseError(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicSettingsOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	s.invalidateReference(ctx, referenceKey(ctx, referenceClinicSettings, clinicID))
+	return mapClinicSettings(settings), nil
+}
+
+func loadClinicSettings(ctx context.Context, q repository.Querier, clinicID string) (ClinicSettingsOutput, error) {
+	settings, err := q.GetClinicSettings(ctx, repository.GetClinicSettingsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return defaultClinicSettings(clinicID), nil
+		}
+		return ClinicSettingsOutput{}, err
+	}
+	return mapClinicSettings(settings), nil
+}
+
+func defaultClinicSettings(clinicID string) ClinicSettingsOutput {
+	return ClinicSettingsOutput{
+		ClinicID:                          clinicID,
+		DefaultAppointmentDurationMinutes: DefaultAppointmentDurationMinutes,
+		ReminderLeadMinutes:               slices.Clone(defaultReminderLeadMinutes),
+		InvoiceNumberPrefix:               DefaultInvoiceNumberPrefix,
+		Locale:                            DefaultClinicLocale,
+		Currency:                          DefaultClinicCurrency,
+		IsDefault:                         true,
+	}
+}
+
+func applyClinicSettingsInput(current ClinicSettingsOutput, input UpdateClinicSettingsInput) (repository.UpsertClinicSettingsParams, error) {
+	duration := current.DefaultAppointmentDurationMinutes
+	if input.DefaultAppointmentDurationMinutes != nil {
+		duration = *input.DefaultAppointmentDurationMinutes
+		if duration < minAppointmentDurationMinutes || duration > maxAppointmentDurationMinutes || duration%appointmentDurationStep != 0 {
+			return repository.UpsertClinicSettingsParams{}, validationError(fmt.Sprintf(
+				"default_appointment_duration_minutes must be a multiple of %d between %d and %d",
+				appointmentDurationStep, minAppointmentDurationMinutes, maxAppointmentDurationMinutes,
+			))
+		}
+	}
+
+	leadTimes := current.ReminderLeadMinutes
+	if input.ReminderLeadMinutes != nil {
+		normalized, err := normalizeReminderLeadMinutes(*input.ReminderLeadMinutes)
+		if err != nil {
+			return repository.UpsertClinicSettingsParams{}, err
+		}
+		leadTimes = normalized
+	}
+
+	prefix := current.InvoiceNumberPrefix
+	if input.InvoiceNumberPrefix != nil {
+		prefix = strings.ToUpper(strings.TrimSpace(*input.InvoiceNumberPrefix))
+		if !invoicePrefixPattern.MatchString(prefix) {
+			return repository.UpsertClinicSettingsParams{}, validationError("invoice_number_prefix must have 1 to 10 letters, digits or hyphens")
+		}
+	}
+
+	locale := current.Locale
+	if input.Locale != nil {
+		locale = strings.TrimSpace(*input.Locale)
+		if !slices.Contains(supportedClinicLocales, locale) {
+			return repository.UpsertClinicSettingsParams{}, validationError(fmt.Sprintf("locale must be one of: %s", strings.Join(supportedClinicLocales, ", ")))
+		}
+	}
+
+	currency := current.Currency
+	if input.Currency != nil {
+		currency = strings.ToUpper(strings.TrimSpace(*input.Currency))
+		if !slices.Contains(supportedClinicCurrencies, currency) {
+			return repository.UpsertClinicSettingsParams{}, validationError(fmt.Sprintf("currency must be one of: %s", strings.Join(supportedClinicCurrencies, ", ")))
+		}
+	}
+
+	reminderLeadMinutes := make([]int32, 0, len(leadTimes))
+	for _, minutes := range leadTimes {
+		reminderLeadMinutes = append(reminderLeadMinutes, int32(minutes))
+	}
+	return repository.UpsertClinicSettingsParams{
+		ClinicID:                          current.ClinicID,
+		DefaultAppointmentDurationMinutes: int16(duration),
+		ReminderLeadMinutes:               reminderLeadMinutes,
+		InvoiceNumberPrefix:               prefix,
+		Locale:                            locale,
+		Currency:                          currency,
+	}, nil
+}
+
+func normalizeReminderLeadMinutes(values []int) ([]int, error) {
+	if len(values) > maxReminderLeadTimes {
+		return nil, validationError(fmt.Sprintf("reminder_lead_minutes accepts at most %d values", maxReminderLeadTimes))
+	}
+	normalized := make([]int, 0, len(values))
+	for idx, minutes := range values {
+		if minutes < minReminderLeadMinutes || minutes > maxReminderLeadMinutes {
+			return nil, validationError(fmt.Sprintf("reminder_lead_minutes[%d] must be between %d and %d", idx, minReminderLeadMinutes, maxReminderLeadMinutes))
+		}
+		if slices.Contains(normalized, minutes) {
+			return nil, validationError(fmt.Sprintf("reminder_lead_minutes[%d] is duplicated", idx))
+		}
+		normalized = append(normalized, minutes)
+	}
+	// Reminders are sent from the earliest to the latest, so keep the longest lead time first.
+	slices.SortFunc(normalized, func(a, b int) int { return b - a })
+	return normalized, nil
+}
+
+func mapClinicSettings(settings repository.ClinicSetting) ClinicSettingsOutput {
+	leadTimes := make([]int, 0, len(settings.ReminderLeadMinutes))
+	for _, minutes := range settings.ReminderLeadMinutes {
+		leadTimes = append(leadTimes, int(minutes))
+	}
+	updatedAt := settings.UpdatedAt
+	return ClinicSettingsOutput{
+		ClinicID:                          settings.ClinicID,
+		DefaultAppointmentDurationMinutes: int(settings.DefaultAppointmentDurationMinutes),
+		ReminderLeadMinutes:               leadTimes,
+		InvoiceNumberPrefix:               settings.InvoiceNumberPrefix,
+		Locale:                            settings.Locale,
+		Currency:                          settings.Currency,
+		UpdatedAt:                         &updatedAt,
+	}
+}
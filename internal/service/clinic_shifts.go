@@ -0,0 +1,452 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const maxShiftDuration = 24 * time.Hour
+
+func (s *Service) CreateClinicShift(ctx context.Context, clinicID string, input CreateClinicShiftInput) (ClinicShiftOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinicShift")
+	defer span.End()
+
+	dentistID := strings.TrimSpace(input.DentistID)
+	if _, err := uuid.Parse(dentistID); err != nil {
+		return ClinicShiftOutput{}, validationError("invalid dentist_id")
+	}
+	if err := validateShiftWindow(input.StartsAt, input.EndsAt); err != nil {
+		return ClinicShiftOutput{}, err
+	}
+	shiftID, err := newUUIDV7()
+	if err != nil {
+		return ClinicShiftOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicShiftOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if err := s.checkShiftSchedule(ctx, qtx, clinicID, dentistID, "", input.StartsAt, input.EndsAt, input.Override); err != nil {
+		return ClinicShiftOutput{}, err
+	}
+	shift, err := qtx.CreateClinicShift(ctx, repository.CreateClinicShiftParams{
+		ID:              shiftID,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		DentistID:       dentistID,
+		StartsAt:        input.StartsAt.UTC(),
+		EndsAt:          input.EndsAt.UTC(),
+		Notes:           optionalString(input.Notes),
+		CreatedByUserID: auditActorID(ctx),
+	})
+	if err != nil {
+		return ClinicShiftOutput{}, mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicShiftOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapClinicShift(shift, ""), nil
+}
+
+func (s *Service) UpdateClinicShift(ctx context.Context, clinicID string, shiftID string, input UpdateClinicShiftInput) (ClinicShiftOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateClinicShift")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicShiftOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	current, err := qtx.GetClinicShift(ctx, repository.GetClinicShiftParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             shiftID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicShiftOutput{}, notFoundError("shift not found")
+		}
+		return ClinicShiftOutput{}, err
+	}
+
+	startsAt, endsAt, notes := current.StartsAt, current.EndsAt, current.Notes
+	if input.StartsAt != nil {
+		startsAt = *input.StartsAt
+	}
+	if input.EndsAt != nil {
+		endsAt = *input.EndsAt
+	}
+	if input.Notes != nil {
+		notes = optionalString(input.Notes)
+	}
+	if err := validateShiftWindow(startsAt, endsAt); err != nil {
+		return ClinicShiftOutput{}, err
+	}
+	if input.StartsAt != nil || input.EndsAt != nil {
+		if err := s.checkShiftSchedule(ctx, qtx, clinicID, current.DentistID, current.ID, startsAt, endsAt, input.Override); err != nil {
+			return ClinicShiftOutput{}, err
+		}
+	}
+
+	shift, err := qtx.UpdateClinicShift(ctx, repository.UpdateClinicShiftParams{
+		OrganizationID: organizationID(ctx),
+		ID:             current.ID,
+		StartsAt:       startsAt.UTC(),
+		EndsAt:         endsAt.UTC(),
+		Notes:          notes,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicShiftOutput{}, notFoundError("shift not found")
+		}
+		return ClinicShiftOutput{}, mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicShiftOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapClinicShift(shift, ""), nil
+}
+
+func (s *Service) DeleteClinicShift(ctx context.Context, clinicID string, shiftID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteClinicShift")
+	defer span.End()
+
+	rows, err := s.queries.DeleteClinicShift(ctx, repository.DeleteClinicShiftParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             shiftID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return notFoundError("shift not found")
+	}
+	return nil
+}
+
+// GetShiftBoard lays out a clinic's ISO week (Monday to Sunday, on the clinic's wall clock) with each day's shifts and
+// holidays, plus how many hours every dentist is on duty that week. Without week it shows the current one.
+func (s *Service) GetShiftBoard(ctx context.Context, clinicID string, week *string) (ShiftBoardOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetShiftBoard")
+	defer span.End()
+
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ShiftBoardOutput{}, notFoundError("clinic not found")
+		}
+		return ShiftBoardOutput{}, err
+	}
+	location := clinicLocation(ctx, clinic.Timezone)
+
+	var monday time.Time
+	if week != nil {
+		monday, err = parseISOWeek(*week)
+		if err != nil {
+			return ShiftBoardOutput{}, err
+		}
+	} else {
+		today := s.todayIn(location)
+		monday = today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	}
+	sunday := monday.AddDate(0, 0, 6)
+
+	rows, err := s.queries.ListClinicShiftsBetween(ctx, repository.ListClinicShiftsBetweenParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		FromAt:         time.Date(monday.Year(), monday.Month(), monday.Day(), 0, 0, 0, 0, location),
+		ToAt:           time.Date(monday.Year(), monday.Month(), monday.Day()+7, 0, 0, 0, 0, location),
+	})
+	if err != nil {
+		return ShiftBoardOutput{}, err
+	}
+	custom, err := s.queries.ListClinicHolidays(ctx, repository.ListClinicHolidaysParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		FromDate:       monday,
+		ToDate:         sunday,
+	})
+	if err != nil {
+		return ShiftBoardOutput{}, err
+	}
+	holidays := make(map[string]string)
+	for _, year := range []int{monday.Year(), sunday.Year()} {
+		for _, holiday := range brazilianNationalHolidays(year) {
+			holidays[holiday.date.Format(documentDateLayout)] = holiday.name
+		}
+	}
+	for _, holiday := range custom {
+		holidays[holiday.HolidayDate.Format(documentDateLayout)] = holiday.Name
+	}
+
+	year, weekNumber := monday.ISOWeek()
+	board := ShiftBoardOutput{
+		ClinicID: clinicID,
+		Timezone: clinic.Timezone,
+		Week:     fmt.Sprintf("%04d-W%02d", year, weekNumber),
+		StartsOn: monday.Format(documentDateLayout),
+		EndsOn:   sunday.Format(documentDateLayout),
+		Days:     make([]ShiftBoardDayOutput, 7),
+		Dentists: []ShiftBoardDentistOutput{},
+	}
+	for idx := range board.Days {
+		day := monday.AddDate(0, 0, idx)
+		board.Days[idx] = ShiftBoardDayOutput{
+			Date:    day.Format(documentDateLayout),
+			Weekday: strings.ToLower(day.Weekday().String()),
+			Shifts:  []ClinicShiftOutput{},
+		}
+		if name, ok := holidays[board.Days[idx].Date]; ok {
+			board.Days[idx].Holiday = &name
+		}
+	}
+
+	totals := make(map[string]int)
+	for _, row := range rows {
+		local := row.StartsAt.In(location)
+		idx := int(time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC).Sub(monday).Hours() / 24)
+		if idx < 0 || idx >= len(board.Days) {
+			continue
+		}
+		output := mapScheduledShift(row)
+		board.Days[idx].Shifts = append(board.Days[idx].Shifts, output)
+
+		position, ok := totals[row.DentistID]
+		if !ok {
+			position = len(board.Dentists)
+			totals[row.DentistID] = position
+			board.Dentists = append(board.Dentists, ShiftBoardDentistOutput{DentistID: row.DentistID, DentistName: row.DentistName})
+		}
+		board.Dentists[position].ShiftCount++
+		board.Dentists[position].ScheduledMinutes += output.DurationMinutes
+	}
+	slices.SortStableFunc(board.Dentists, func(a, b ShiftBoardDentistOutput) int {
+		return strings.Compare(a.DentistName, b.DentistName)
+	})
+	return board, nil
+}
+
+// checkShiftSchedule runs the checks shared by creating and moving a shift: the clinic must be active and open for the
+// whole window (override skips the hours and holiday checks), the dentist must hold a link covering it, and the dentist
+// cannot be on another shift at the same time, at this clinic or any other.
+func (s *Service) checkShiftSchedule(ctx context.Context, qtx repository.Querier, clinicID string, dentistID string, shiftID string, startsAt time.Time, endsAt time.Time, override bool) error {
+	clinic, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("clinic not found")
+		}
+		return err
+	}
+	if clinic.DeactivatedAt.Valid {
+		return conflictError("clinic is deactivated; reactivate it before scheduling")
+	}
+
+	if _, err := qtx.LockDentistForShift(ctx, repository.LockDentistForShiftParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("dentist not found")
+		}
+		return err
+	}
+	link, err := qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return validationError("dentist is not linked to this clinic")
+		}
+		return err
+	}
+	if startsAt.Before(link.StartedAt) {
+		return validationError("shift starts before the dentist's link with the clinic")
+	}
+	if link.PlannedEndAt.Valid && endsAt.After(link.PlannedEndAt.Time) {
+		return validationError("shift ends after the dentist's temporary assignment")
+	}
+
+	if !override {
+		location := clinicLocation(ctx, clinic.Timezone)
+		localStart, localEnd := startsAt.In(location), endsAt.In(location)
+		if reason, err := s.shiftClosedReason(ctx, qtx, clinicID, localStart, localEnd); err != nil {
+			return err
+		} else if reason != "" {
+			return validationError(fmt.Sprintf("clinic is closed during the shift (%s); set override to schedule anyway", reason))
+		}
+	}
+
+	params := repository.GetOverlappingDentistShiftParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+		StartsAt:       startsAt.UTC(),
+		EndsAt:         endsAt.UTC(),
+	}
+	if shiftID != "" {
+		params.ExcludeID = uuid.NullUUID{UUID: uuid.MustParse(shiftID), Valid: true}
+	}
+	overlapping, err := qtx.GetOverlappingDentistShift(ctx, params)
+	if err == nil {
+		return conflictError(fmt.Sprintf("dentist already has a shift from %s to %s (clinic %s)", overlapping.StartsAt.UTC().Format(time.RFC3339), overlapping.EndsAt.UTC().Format(time.RFC3339), overlapping.ClinicID))
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	return nil
+}
+
+func (s *Service) shiftClosedReason(ctx context.Context, qtx repository.Querier, clinicID string, localStart time.Time, localEnd time.Time) (string, error) {
+	firstDay := time.Date(localStart.Year(), localStart.Month(), localStart.Day(), 0, 0, 0, 0, time.UTC)
+	lastDay := time.Date(localEnd.Year(), localEnd.Month(), localEnd.Day(), 0, 0, 0, 0, time.UTC)
+	for day := firstDay; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		for _, holiday := range brazilianNationalHolidays(day.Year()) {
+			if holiday.date.Equal(day) {
+				return "national holiday: " + holiday.name, nil
+			}
+		}
+	}
+	custom, err := qtx.ListClinicHolidays(ctx, repository.ListClinicHolidaysParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		FromDate:       firstDay,
+		ToDate:         lastDay,
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(custom) > 0 {
+		return "clinic holiday: " + custom[0].Name, nil
+	}
+
+	hours, err := qtx.ListClinicOperatingHours(ctx, repository.ListClinicOperatingHoursParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return "", err
+	}
+	// Clinics that never configured their hours accept any time, as in the availability check.
+	if len(hours) > 0 && !shiftWithinOperatingHours(hours, localStart, localEnd) {
+		return "outside operating hours", nil
+	}
+	return "", nil
+}
+
+// shiftWithinOperatingHours reports whether one opening interval holds the whole shift. A shift that runs through a
+// lunch break is outside the hours and needs an override or two shifts.
+func shiftWithinOperatingHours(hours []repository.ClinicOperatingHour, localStart time.Time, localEnd time.Time) bool {
+	startMinute := localStart.Hour()*60 + localStart.Minute()
+	endMinute := localEnd.Hour()*60 + localEnd.Minute()
+	sameDay := localStart.Year() == localEnd.Year() && localStart.YearDay() == localEnd.YearDay()
+	if !sameDay {
+		// Only a shift ending exactly at midnight may finish on the next calendar day.
+		if endMinute != 0 || localEnd.Sub(localStart) > 24*time.Hour || localEnd.Add(-time.Minute).YearDay() != localStart.YearDay() {
+			return false
+		}
+		endMinute = minutesPerDay
+	}
+	weekday := int16(localStart.Weekday())
+	for _, interval := range hours {
+		if interval.Weekday == weekday && startMinute >= int(interval.OpensMinute) && endMinute <= int(interval.ClosesMinute) {
+			return true
+		}
+	}
+	return false
+}
+
+func validateShiftWindow(startsAt time.Time, endsAt time.Time) error {
+	if startsAt.IsZero() || endsAt.IsZero() {
+		return validationError("starts_at and ends_at are required")
+	}
+	if !endsAt.After(startsAt) {
+		return validationError("ends_at must be after starts_at")
+	}
+	if endsAt.Sub(startsAt) > maxShiftDuration {
+		return validationError("a shift cannot be longer than 24 hours")
+	}
+	return nil
+}
+
+// parseISOWeek returns the Monday of an ISO 8601 week such as 2026-W07, as a UTC date.
+func parseISOWeek(value string) (time.Time, error) {
+	invalid := validationError("week must be an ISO week such as 2026-W07")
+	trimmed := strings.ToUpper(strings.TrimSpace(value))
+	yearPart, weekPart, found := strings.Cut(trimmed, "-W")
+	if !found || len(yearPart) != 4 || len(weekPart) != 2 {
+		return time.Time{}, invalid
+	}
+	year, err := strconv.Atoi(yearPart)
+	if err != nil || year < 1900 {
+		return time.Time{}, invalid
+	}
+	week, err := strconv.Atoi(weekPart)
+	if err != nil || week < 1 || week > 53 {
+		return time.Time{}, invalid
+	}
+	// January 4th always falls in week 1.
+	jan4 := time.Date(year, time.January, 4, 0, 0, 0, 0, time.UTC)
+	monday := jan4.AddDate(0, 0, -((int(jan4.Weekday())+6)%7)+(week-1)*7)
+	if isoYear, isoWeek := monday.ISOWeek(); isoYear != year || isoWeek != week {
+		return time.Time{}, invalid
+	}
+	return monday, nil
+}
+
+func mapScheduledShift(row repository.ListClinicShiftsBetweenRow) ClinicShiftOutput {
+	return mapClinicShift(repository.ClinicShift{
+		ID:              row.ID,
+		OrganizationID:  row.OrganizationID,
+		ClinicID:        row.ClinicID,
+		DentistID:       row.DentistID,
+		StartsAt:        row.StartsAt,
+		EndsAt:          row.EndsAt,
+		Notes:           row.Notes,
+		CreatedByUserID: row.CreatedByUserID,
+		CreatedAt:       row.CreatedAt,
+		UpdatedAt:       row.UpdatedAt,
+	}, row.DentistName)
+}
+
+func mapClinicShift(shift repository.ClinicShift, dentistName string) ClinicShiftOutput {
+	return ClinicShiftOutput{
+		ID:              shift.ID,
+		ClinicID:        shift.ClinicID,
+		DentistID:       shift.DentistID,
+		DentistName:     dentistName,
+		StartsAt:        shift.StartsAt.UTC(),
+		EndsAt:          shift.EndsAt.UTC(),
+		DurationMinutes: int(shift.EndsAt.Sub(shift.StartsAt).Minutes()),
+		Notes:           nullToPointer(shift.Notes),
+		CreatedByUserID: nullUUIDToPointer(shift.CreatedByUserID),
+		CreatedAt:       shift.CreatedAt,
+		UpdatedAt:       shift.UpdatedAt,
+	}
+}
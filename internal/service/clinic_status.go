@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	ClinicStatusActive      = "ACTIVE"
+	ClinicStatusDeactivated = "DEACTIVATED"
+
+	maxDeactivationReasonLength = 500
+	availabilityReasonInactive  = "clinic is deactivated"
+)
+
+func (s *Service) DeactivateClinic(ctx context.Context, clinicID string, input DeactivateClinicInput) (ClinicOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeactivateClinic")
+	defer span.End()
+
+	reason := input.Reason
+	if reason != nil {
+		trimmed := strings.TrimSpace(*reason)
+		if err := validateMaxLength("reason", trimmed, maxDeactivationReasonLength); err != nil {
+			return ClinicOutput{}, err
+		}
+		reason = &trimmed
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	revisions, err := lockClinicForStatusChange(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
+	rows, err := qtx.DeactivateClinic(ctx, repository.DeactivateClinicParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+		Reason:         optionalString(reason),
+	})
+	if err != nil {
+		return ClinicOutput{}, mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return ClinicOutput{}, clinicStatusConflict(ClinicStatusDeactivated)
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return ClinicOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return loadClinicSummary(ctx, s.queries, clinicID)
+}
+
+func (s *Service) ReactivateClinic(ctx context.Context, clinicID string) (ClinicOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ReactivateClinic")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	revisions, err := lockClinicForStatusChange(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
+	rows, err := qtx.ReactivateClinic(ctx, repository.ReactivateClinicParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		return ClinicOutput{}, mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return ClinicOutput{}, clinicStatusConflict(ClinicStatusActive)
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return ClinicOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return loadClinicSummary(ctx, s.queries, clinicID)
+}
+
+// lockClinicForStatusChange holds the clinic row until commit, so the revision snapshot and the status update see the same
+// clinic and concurrent status changes serialize.
+func lockClinicForStatusChange(ctx context.Context, qtx repository.Querier, clinicID string) (clinicRevisionSnapshot, error) {
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return clinicRevisionSnapshot{}, notFoundError("clinic not found")
+		}
+		return clinicRevisionSnapshot{}, mapDatabaseError(err)
+	}
+	return loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+}
+
+// The clinic is locked by then, so no update means it is already in the requested state.
+func clinicStatusConflict(status string) error {
+	return conflictError(fmt.Sprintf("clinic is already %s", strings.ToLower(status)))
+}
+
+func ensureClinicOperational(ctx context.Context, qtx repository.Querier, clinicID string) error {
+	clinic, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("clinic not found")
+		}
+		return err
+	}
+	if clinic.DeactivatedAt.Valid {
+		return conflictError("clinic is deactivated; reactivate it before starting new operations")
+	}
+	return nil
+}
+
+func clinicStatus(deactivatedAt sql.NullTime) string {
+	if deactivatedAt.Valid {
+		return ClinicStatusDeactivated
+	}
+	return ClinicStatusActive
+}
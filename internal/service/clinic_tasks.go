@@ -0,0 +1,731 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	TaskStatusOpen       = "OPEN"
+	TaskStatusInProgress = "IN_PROGRESS"
+	TaskStatusDone       = "DONE"
+	TaskStatusCanceled   = "CANCELED"
+
+	TaskSourceManual     = "MANUAL"
+	TaskSourceAutomation = "AUTOMATION"
+
+	TaskLinkClinic            = "CLINIC"
+	TaskLinkDentist           = "DENTIST"
+	TaskLinkBankAccount       = "BANK_ACCOUNT"
+	TaskLinkBankAccountChange = "BANK_ACCOUNT_CHANGE"
+	TaskLinkEquipment         = "EQUIPMENT"
+	TaskLinkPurchaseOrder     = "PURCHASE_ORDER"
+
+	TaskTriggerMaintenanceOverdue       = "EQUIPMENT_MAINTENANCE_OVERDUE"
+	TaskTriggerDocumentExpiring         = "DENTIST_DOCUMENT_EXPIRING"
+	TaskTriggerBankAccountChangePending = "BANK_ACCOUNT_CHANGE_PENDING"
+
+	maxTaskDueInDays    = 90
+	maxTaskLeadDays     = 365
+	defaultTaskLeadDays = 30
+)
+
+var openTaskStatuses = []string{TaskStatusOpen, TaskStatusInProgress}
+
+func (s *Service) CreateClinicTask(ctx context.Context, clinicID string, input CreateClinicTaskInput) (ClinicTaskOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinicTask")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return ClinicTaskOutput{}, unauthorizedError("missing authenticated user")
+	}
+	title := strings.TrimSpace(input.Title)
+	if title == "" {
+		return ClinicTaskOutput{}, validationError("title is required")
+	}
+	dueDate, err := parseDocumentDate("due_date", input.DueDate)
+	if err != nil {
+		return ClinicTaskOutput{}, err
+	}
+	taskID, err := newUUIDV7()
+	if err != nil {
+		return ClinicTaskOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicTaskOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	clinic, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicTaskOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicTaskOutput{}, err
+	}
+	params := repository.CreateClinicTaskParams{
+		ID:              taskID,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		Title:           title,
+		Description:     optionalString(input.Description),
+		DueDate:         dueDate,
+		CreatedByUserID: principal.UserID,
+	}
+	if input.AssigneeUserID != nil {
+		if params.AssigneeUserID, err = ensureTaskAssignee(ctx, qtx, clinicID, "assignee_user_id", *input.AssigneeUserID); err != nil {
+			return ClinicTaskOutput{}, err
+		}
+	}
+	if input.Link != nil {
+		link, err := parseTaskLink(*input.Link)
+		if err != nil {
+			return ClinicTaskOutput{}, err
+		}
+		if err := ensureTaskLinkExists(ctx, qtx, clinicID, link); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ClinicTaskOutput{}, validationError(fmt.Sprintf("link references an unknown %s", strings.ToLower(link.EntityType)))
+			}
+			return ClinicTaskOutput{}, err
+		}
+		params.EntityType = sql.NullString{String: link.EntityType, Valid: true}
+		params.EntityID = uuid.NullUUID{UUID: uuid.MustParse(link.EntityID), Valid: true}
+	}
+
+	task, err := qtx.CreateClinicTask(ctx, params)
+	if err != nil {
+		return ClinicTaskOutput{}, mapDatabaseError(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicTaskOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapClinicTask(task, s.todayIn(clinicLocation(ctx, clinic.Timezone))), nil
+}
+
+func (s *Service) ListClinicTasks(ctx context.Context, clinicID string, limit int, cursor *string, status *string, assigneeUserID *string) ([]ClinicTaskOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicTasks")
+	defer span.End()
+
+	today, err := s.clinicToday(ctx, clinicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListClinicTasksCursorParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		Statuses:       []string{},
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeNullableKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorDueDate = after.At
+	params.CursorID = after.ID
+	if status != nil {
+		normalized, err := normalizeTaskStatus(*status)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.Statuses = []string{normalized}
+	}
+	if assigneeUserID != nil {
+		parsed, err := uuid.Parse(strings.TrimSpace(*assigneeUserID))
+		if err != nil {
+			return nil, nil, validationError("assignee_user_id must be a UUID")
+		}
+		params.AssigneeUserID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+
+	rows, err := s.queries.ListClinicTasksCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pageClinicTasks(rows, pageLimit, func(repository.ClinicTask) time.Time { return today })
+}
+
+// ListOwnTasks is the caller's inbox across clinics, soonest due first. Without a status filter it only shows tasks
+// that still need work.
+func (s *Service) ListOwnTasks(ctx context.Context, limit int, cursor *string, status *string) ([]ClinicTaskOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListOwnTasks")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return nil, nil, unauthorizedError("missing authenticated user")
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListAssignedTasksCursorParams{
+		OrganizationID: organizationID(ctx),
+		AssigneeUserID: principal.UserID,
+		Statuses:       openTaskStatuses,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeNullableKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorDueDate = after.At
+	params.CursorID = after.ID
+	if status != nil {
+		normalized, err := normalizeTaskStatus(*status)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.Statuses = []string{normalized}
+	}
+
+	rows, err := s.queries.ListAssignedTasksCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	todayByClinic := make(map[string]time.Time)
+	for _, row := range rows {
+		if _, ok := todayByClinic[row.ClinicID]; ok {
+			continue
+		}
+		today, err := s.clinicToday(ctx, row.ClinicID)
+		if err != nil {
+			return nil, nil, err
+		}
+		todayByClinic[row.ClinicID] = today
+	}
+	return pageClinicTasks(rows, pageLimit, func(task repository.ClinicTask) time.Time { return todayByClinic[task.ClinicID] })
+}
+
+func (s *Service) UpdateClinicTask(ctx context.Context, clinicID string, taskID string, input UpdateClinicTaskInput) (ClinicTaskOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateClinicTask")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicTaskOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	task, err := qtx.GetClinicTaskForUpdate(ctx, repository.GetClinicTaskForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             taskID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicTaskOutput{}, notFoundError("task not found")
+		}
+		return ClinicTaskOutput{}, err
+	}
+	params := taskUpdateParams(task)
+	if input.Title != nil {
+		title := strings.TrimSpace(*input.Title)
+		if title == "" {
+			return ClinicTaskOutput{}, validationError("title must not be empty")
+		}
+		params.Title = title
+	}
+	if input.Description != nil {
+		params.Description = optionalString(input.Description)
+	}
+	if input.DueDate != nil {
+		params.DueDate = sql.NullTime{}
+		if strings.TrimSpace(*input.DueDate) != "" {
+			if params.DueDate, err = parseDocumentDate("due_date", input.DueDate); err != nil {
+				return ClinicTaskOutput{}, err
+			}
+		}
+	}
+	if input.AssigneeUserID != nil {
+		params.AssigneeUserID = uuid.NullUUID{}
+		if strings.TrimSpace(*input.AssigneeUserID) != "" {
+			if params.AssigneeUserID, err = ensureTaskAssignee(ctx, qtx, clinicID, "assignee_user_id", *input.AssigneeUserID); err != nil {
+				return ClinicTaskOutput{}, err
+			}
+		}
+	}
+	if input.Status != nil {
+		if err := s.applyTaskStatus(&params, task, *input.Status); err != nil {
+			return ClinicTaskOutput{}, err
+		}
+	}
+
+	return s.saveClinicTask(ctx, tx, qtx, params)
+}
+
+// UpdateOwnTask lets the assignee move their task along; everything else about it stays with the clinic's admins.
+func (s *Service) UpdateOwnTask(ctx context.Context, taskID string, input UpdateOwnTaskInput) (ClinicTaskOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateOwnTask")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return ClinicTaskOutput{}, unauthorizedError("missing authenticated user")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicTaskOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	task, err := qtx.GetAssignedTaskForUpdate(ctx, repository.GetAssignedTaskForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		AssigneeUserID: principal.UserID,
+		ID:             taskID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicTaskOutput{}, notFoundError("task not found")
+		}
+		return ClinicTaskOutput{}, err
+	}
+	params := taskUpdateParams(task)
+	if err := s.applyTaskStatus(&params, task, input.Status); err != nil {
+		return ClinicTaskOutput{}, err
+	}
+
+	return s.saveClinicTask(ctx, tx, qtx, params)
+}
+
+func (s *Service) DeleteClinicTask(ctx context.Context, clinicID string, taskID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteClinicTask")
+	defer span.End()
+
+	rows, err := s.queries.DeleteClinicTask(ctx, repository.DeleteClinicTaskParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             taskID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return notFoundError("task not found")
+	}
+	return nil
+}
+
+func (s *Service) ListClinicTaskRules(ctx context.Context, clinicID string) ([]TaskRuleOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicTaskRules")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+	rules, err := s.queries.ListClinicTaskRules(ctx, repository.ListClinicTaskRulesParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mapTaskRules(rules), nil
+}
+
+// ReplaceClinicTaskRules sets which automation triggers open tasks in the clinic, at most one rule per trigger.
+func (s *Service) ReplaceClinicTaskRules(ctx context.Context, clinicID string, input ReplaceTaskRulesInput) ([]TaskRuleOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ReplaceClinicTaskRules")
+	defer span.End()
+
+	if input.Rules == nil {
+		return nil, validationError("rules is required")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, mapDatabaseError(err)
+	}
+
+	params := make([]repository.CreateClinicTaskRuleParams, 0, len(input.Rules))
+	seen := make(map[string]bool, len(input.Rules))
+	for idx, rule := range input.Rules {
+		trigger := strings.ToUpper(strings.TrimSpace(rule.Trigger))
+		switch trigger {
+		case TaskTriggerMaintenanceOverdue, TaskTriggerDocumentExpiring, TaskTriggerBankAccountChangePending:
+		default:
+			return nil, validationError(fmt.Sprintf("rules[%d].trigger must be one of: %s, %s, %s", idx, TaskTriggerMaintenanceOverdue, TaskTriggerDocumentExpiring, TaskTriggerBankAccountChangePending))
+		}
+		if seen[trigger] {
+			return nil, validationError(fmt.Sprintf("rules[%d].trigger %s is repeated", idx, trigger))
+		}
+		seen[trigger] = true
+		if rule.DueInDays < 0 || rule.DueInDays > maxTaskDueInDays {
+			return nil, validationError(fmt.Sprintf("rules[%d].due_in_days must be between 0 and %d", idx, maxTaskDueInDays))
+		}
+		leadDays := 0
+		if trigger == TaskTriggerDocumentExpiring {
+			leadDays = defaultTaskLeadDays
+			if rule.LeadDays != 0 {
+				leadDays = rule.LeadDays
+			}
+			if leadDays < 0 || leadDays > maxTaskLeadDays {
+				return nil, validationError(fmt.Sprintf("rules[%d].lead_days must be between 0 and %d", idx, maxTaskLeadDays))
+			}
+		} else if rule.LeadDays != 0 {
+			return nil, validationError(fmt.Sprintf("rules[%d].lead_days only applies to %s", idx, TaskTriggerDocumentExpiring))
+		}
+		ruleParams := repository.CreateClinicTaskRuleParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+			TriggerType:    trigger,
+			DueInDays:      int32(rule.DueInDays),
+			LeadDays:       int32(leadDays),
+		}
+		if rule.AssigneeUserID != nil {
+			if ruleParams.AssigneeUserID, err = ensureTaskAssignee(ctx, qtx, clinicID, fmt.Sprintf("rules[%d].assignee_user_id", idx), *rule.AssigneeUserID); err != nil {
+				return nil, err
+			}
+		}
+		params = append(params, ruleParams)
+	}
+
+	if _, err := qtx.DeleteClinicTaskRules(ctx, repository.DeleteClinicTaskRulesParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	}); err != nil {
+		return nil, mapDatabaseError(err)
+	}
+	rules := make([]repository.ClinicTaskRule, 0, len(params))
+	for _, ruleParams := range params {
+		rule, err := qtx.CreateClinicTaskRule(ctx, ruleParams)
+		if err != nil {
+			return nil, mapDatabaseError(err)
+		}
+		rules = append(rules, rule)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapTaskRules(rules), nil
+}
+
+// GenerateAutomatedTasks opens one task per rule match. The automation key names the occurrence, such as a missed due
+// date, so a task closed by hand is not reopened, while the next occurrence gets a task of its own.
+func (s *Service) GenerateAutomatedTasks(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GenerateAutomatedTasks")
+	defer span.End()
+
+	today := s.today()
+	var tasks []repository.CreateAutomatedClinicTaskParams
+
+	maintenance, err := s.queries.ListMaintenanceOverdueTaskCandidates(ctx, repository.ListMaintenanceOverdueTaskCandidatesParams{
+		OrganizationID: organizationID(ctx),
+		Today:          today,
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range maintenance {
+		due := row.NextMaintenanceDue.Format(documentDateLayout)
+		tasks = append(tasks, repository.CreateAutomatedClinicTaskParams{
+			ClinicID:          row.ClinicID,
+			Title:             fmt.Sprintf("Agendar manutenção de %s (vencida em %s)", row.EquipmentName, row.NextMaintenanceDue.Format("02/01/2006")),
+			DueDate:           today.AddDate(0, 0, int(row.DueInDays)),
+			AssigneeUserID:    row.AssigneeUserID,
+			EntityType:        TaskLinkEquipment,
+			EntityID:          row.EntityID,
+			AutomationTrigger: TaskTriggerMaintenanceOverdue,
+			AutomationKey:     TaskTriggerMaintenanceOverdue + ":" + row.ClinicID + ":" + row.EntityID + ":" + due,
+		})
+	}
+
+	documents, err := s.queries.ListDocumentExpiringTaskCandidates(ctx, repository.ListDocumentExpiringTaskCandidatesParams{
+		OrganizationID: organizationID(ctx),
+		Today:          today,
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range documents {
+		expires := row.ExpiresAt.Format(documentDateLayout)
+		tasks = append(tasks, repository.CreateAutomatedClinicTaskParams{
+			ClinicID:          row.ClinicID,
+			Title:             fmt.Sprintf("Renovar documento %s do dentista (vence em %s)", row.DocumentType, row.ExpiresAt.Format("02/01/2006")),
+			DueDate:           today.AddDate(0, 0, int(row.DueInDays)),
+			AssigneeUserID:    row.AssigneeUserID,
+			EntityType:        TaskLinkDentist,
+			EntityID:          row.DentistID,
+			AutomationTrigger: TaskTriggerDocumentExpiring,
+			AutomationKey:     TaskTriggerDocumentExpiring + ":" + row.ClinicID + ":" + row.DocumentID + ":" + expires,
+		})
+	}
+
+	changes, err := s.queries.ListPendingBankAccountChangeTaskCandidates(ctx, organizationID(ctx))
+	if err != nil {
+		return 0, err
+	}
+	for _, row := range changes {
+		tasks = append(tasks, repository.CreateAutomatedClinicTaskParams{
+			ClinicID:          row.ClinicID,
+			Title:             fmt.Sprintf("Revisar pedido de alteração de conta bancária (%s)", row.ChangeType),
+			DueDate:           today.AddDate(0, 0, int(row.DueInDays)),
+			AssigneeUserID:    row.AssigneeUserID,
+			EntityType:        TaskLinkBankAccountChange,
+			EntityID:          row.EntityID,
+			AutomationTrigger: TaskTriggerBankAccountChangePending,
+			AutomationKey:     TaskTriggerBankAccountChangePending + ":" + row.ClinicID + ":" + row.EntityID,
+		})
+	}
+
+	created := 0
+	for _, task := range tasks {
+		taskID, err := newUUIDV7()
+		if err != nil {
+			return created, err
+		}
+		task.ID = taskID
+		task.OrganizationID = organizationID(ctx)
+		rows, err := s.queries.CreateAutomatedClinicTask(ctx, task)
+		if err != nil {
+			return created, mapDatabaseError(err)
+		}
+		created += int(rows)
+	}
+	return created, nil
+}
+
+func (s *Service) applyTaskStatus(params *repository.UpdateClinicTaskParams, task repository.ClinicTask, value string) error {
+	status, err := normalizeTaskStatus(value)
+	if err != nil {
+		return err
+	}
+	params.Status = status
+	switch {
+	case !taskStatusClosed(status):
+		params.CompletedAt = sql.NullTime{}
+	case status != task.Status || !task.CompletedAt.Valid:
+		params.CompletedAt = sql.NullTime{Time: s.now().UTC(), Valid: true}
+	}
+	return nil
+}
+
+func (s *Service) saveClinicTask(ctx context.Context, tx pgx.Tx, qtx repository.Querier, params repository.UpdateClinicTaskParams) (ClinicTaskOutput, error) {
+	task, err := qtx.UpdateClinicTask(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicTaskOutput{}, notFoundError("task not found")
+		}
+		return ClinicTaskOutput{}, mapDatabaseError(err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicTaskOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	today, err := s.clinicToday(ctx, task.ClinicID)
+	if err != nil {
+		return ClinicTaskOutput{}, err
+	}
+	return mapClinicTask(task, today), nil
+}
+
+func taskUpdateParams(task repository.ClinicTask) repository.UpdateClinicTaskParams {
+	return repository.UpdateClinicTaskParams{
+		OrganizationID: task.OrganizationID,
+		ID:             task.ID,
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         task.Status,
+		DueDate:        task.DueDate,
+		AssigneeUserID: task.AssigneeUserID,
+		CompletedAt:    task.CompletedAt,
+	}
+}
+
+// ensureTaskAssignee accepts any user of the organization, except that a dentist must be linked to the clinic.
+func ensureTaskAssignee(ctx context.Context, qtx repository.Querier, clinicID string, field string, value string) (uuid.NullUUID, error) {
+	parsed, err := uuid.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return uuid.NullUUID{}, validationError(fmt.Sprintf("%s must be a UUID", field))
+	}
+	user, err := qtx.GetUserByID(ctx, repository.GetUserByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             parsed.String(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.NullUUID{}, validationError(fmt.Sprintf("%s references an unknown user", field))
+		}
+		return uuid.NullUUID{}, err
+	}
+	if user.Role == UserRoleDentist && user.DentistID.Valid {
+		if _, err := qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+			DentistID:      user.DentistID.UUID.String(),
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return uuid.NullUUID{}, validationError(fmt.Sprintf("%s references a dentist who is not linked to this clinic", field))
+			}
+			return uuid.NullUUID{}, err
+		}
+	}
+	return uuid.NullUUID{UUID: parsed, Valid: true}, nil
+}
+
+func parseTaskLink(input TaskLinkInput) (TaskLinkOutput, error) {
+	entityType := strings.ToUpper(strings.TrimSpace(input.EntityType))
+	switch entityType {
+	case TaskLinkClinic, TaskLinkDentist, TaskLinkBankAccount, TaskLinkBankAccountChange, TaskLinkEquipment, TaskLinkPurchaseOrder:
+	default:
+		return TaskLinkOutput{}, validationError(fmt.Sprintf("link.entity_type must be one of: %s, %s, %s, %s, %s, %s", TaskLinkClinic, TaskLinkDentist, TaskLinkBankAccount, TaskLinkBankAccountChange, TaskLinkEquipment, TaskLinkPurchaseOrder))
+	}
+	parsed, err := uuid.Parse(strings.TrimSpace(input.EntityID))
+	if err != nil || parsed.Version() != 7 {
+		return TaskLinkOutput{}, validationError("link.entity_id must be a UUIDv7")
+	}
+	return TaskLinkOutput{EntityType: entityType, EntityID: parsed.String()}, nil
+}
+
+func ensureTaskLinkExists(ctx context.Context, qtx repository.Querier, clinicID string, link TaskLinkOutput) error {
+	var err error
+	switch link.EntityType {
+	case TaskLinkClinic:
+		_, err = qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             link.EntityID,
+		})
+	case TaskLinkDentist:
+		_, err = qtx.GetDentistByID(ctx, repository.GetDentistByIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             link.EntityID,
+		})
+	case TaskLinkBankAccount:
+		_, err = qtx.GetBankAccountByIDAndClinicID(ctx, repository.GetBankAccountByIDAndClinicIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             link.EntityID,
+			ClinicID:       clinicID,
+		})
+	case TaskLinkBankAccountChange:
+		_, err = qtx.GetBankAccountChange(ctx, repository.GetBankAccountChangeParams{
+			OrganizationID: organizationID(ctx),
+			ID:             link.EntityID,
+			ClinicID:       clinicID,
+		})
+	case TaskLinkEquipment:
+		_, err = qtx.GetEquipment(ctx, repository.GetEquipmentParams{
+			OrganizationID: organizationID(ctx),
+			ID:             link.EntityID,
+			ClinicID:       clinicID,
+		})
+	case TaskLinkPurchaseOrder:
+		_, err = qtx.GetPurchaseOrder(ctx, repository.GetPurchaseOrderParams{
+			OrganizationID: organizationID(ctx),
+			ID:             link.EntityID,
+			ClinicID:       clinicID,
+		})
+	}
+	return err
+}
+
+func normalizeTaskStatus(value string) (string, error) {
+	status := strings.ToUpper(strings.TrimSpace(value))
+	switch status {
+	case TaskStatusOpen, TaskStatusInProgress, TaskStatusDone, TaskStatusCanceled:
+		return status, nil
+	default:
+		return "", validationError(fmt.Sprintf("status must be one of: %s, %s, %s, %s", TaskStatusOpen, TaskStatusInProgress, TaskStatusDone, TaskStatusCanceled))
+	}
+}
+
+func taskStatusClosed(status string) bool {
+	return status == TaskStatusDone || status == TaskStatusCanceled
+}
+
+func pageClinicTasks(rows []repository.ClinicTask, pageLimit int, todayFor func(repository.ClinicTask) time.Time) ([]ClinicTaskOutput, *string, error) {
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	tasks := make([]ClinicTaskOutput, 0, len(rows))
+	for _, row := range rows {
+		tasks = append(tasks, mapClinicTask(row, todayFor(row)))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(last.DueDate, last.ID)
+	}
+	return tasks, nextCursor, nil
+}
+
+func mapClinicTask(task repository.ClinicTask, today time.Time) ClinicTaskOutput {
+	output := ClinicTaskOutput{
+		ID:                task.ID,
+		ClinicID:          task.ClinicID,
+		Title:             task.Title,
+		Description:       nullToPointer(task.Description),
+		Status:            task.Status,
+		AssigneeUserID:    nullUUIDToPointer(task.AssigneeUserID),
+		Source:            TaskSourceManual,
+		AutomationTrigger: nullToPointer(task.AutomationTrigger),
+		CreatedByUserID:   nullUUIDToPointer(task.CreatedByUserID),
+		CompletedAt:       nullTimeToPointer(task.CompletedAt),
+		CreatedAt:         task.CreatedAt,
+		UpdatedAt:         task.UpdatedAt,
+	}
+	if task.DueDate.Valid {
+		dueDate := task.DueDate.Time.Format(documentDateLayout)
+		output.DueDate = &dueDate
+		output.Overdue = !taskStatusClosed(task.Status) && task.DueDate.Time.Before(today)
+	}
+	if task.EntityType.Valid && task.EntityID.Valid {
+		output.Link = &TaskLinkOutput{EntityType: task.EntityType.String, EntityID: task.EntityID.UUID.String()}
+	}
+	if task.AutomationTrigger.Valid {
+		output.Source = TaskSourceAutomation
+	}
+	return output
+}
+
+func mapTaskRules(rules []repository.ClinicTaskRule) []TaskRuleOutput {
+	output := make([]TaskRuleOutput, 0, len(rules))
+	for _, rule := range rules {
+		output = append(output, TaskRuleOutput{
+			Trigger:        rule.TriggerType,
+			AssigneeUserID: nullUUIDToPointer(rule.AssigneeUserID),
+			DueInDays:      int(rule.DueInDays),
+			LeadDays:       int(rule.LeadDays),
+			UpdatedAt:      rule.UpdatedAt,
+		})
+	}
+	return output
+}
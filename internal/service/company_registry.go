@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const registrationStatusActive = "ATIVA"
+
+type CompanyRegistryResult struct {
+	LegalName          string
+	TradeName          string
+	CNAECode           string
+	CNAEDescription    string
+	RegistrationStatus string
+	Source             string
+}
+
+type CompanyRegistry interface {
+	LookupCNPJ(ctx context.Context, cnpj string) (CompanyRegistryResult, bool, error)
+}
+
+func WithCompanyRegistry(registry CompanyRegistry) Option {
+	return func(s *Service) {
+		s.companyRegistry = registry
+	}
+}
+
+func (s *Service) enrichClinic(ctx context.Context, cnpj string, legalName string, validations *validationCollector) *repository.UpsertClinicRegistryRecordParams {
+	if s.companyRegistry == nil {
+		return nil
+	}
+
+	result, found, err := s.companyRegistry.LookupCNPJ(ctx, cnpj)
+	if err != nil {
+		// Enrichment is best effort; a registry outage must not block clinic creation.
+		slog.WarnContext(ctx, "company registry lookup failed", "error", err)
+		return nil
+	}
+	if !found {
+		validations.warn("tax_id_number not found in company registry")
+		return nil
+	}
+
+	registryLegalName := strings.TrimSpace(result.LegalName)
+	if registryLegalName != "" && !validation.SameCompanyName(legalName, registryLegalName) {
+		validations.warn(fmt.Sprintf("legal_name differs from company registry (%s)", registryLegalName))
+	}
+	status := strings.ToUpper(strings.TrimSpace(result.RegistrationStatus))
+	if status != "" && status != registrationStatusActive {
+		validations.warn(fmt.Sprintf("company registry status is %s", status))
+	}
+
+	return &repository.UpsertClinicRegistryRecordParams{
+		OrganizationID:     organizationID(ctx),
+		LegalName:          registryLegalName,
+		TradeName:          optionalString(&result.TradeName),
+		CnaeCode:           optionalString(&result.CNAECode),
+		CnaeDescription:    optionalString(&result.CNAEDescription),
+		RegistrationStatus: status,
+		Source:             result.Source,
+	}
+}
+
+func upsertClinicRegistryRecord(ctx context.Context, qtx repository.Querier, clinicID string, record *repository.UpsertClinicRegistryRecordParams) error {
+	if record == nil {
+		return nil
+	}
+	params := *record
+	params.ClinicID = clinicID
+	if _, err := qtx.UpsertClinicRegistryRecord(ctx, params); err != nil {
+		return mapDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) loadClinicRegistryRecord(ctx context.Context, clinicID string) (*CompanyRegistryOutput, error) {
+	record, err := s.queries.GetClinicRegistryRecordByClinicID(ctx, repository.GetClinicRegistryRecordByClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return mapClinicRegistryRecord(record), nil
+}
+
+func (s *Service) loadClinicRegistryRecordsByClinicIDs(ctx context.Context, clinicIDs []string) (map[string]*CompanyRegistryOutput, error) {
+	return loadBatch(ctx, s, "clinic_registry_records", clinicIDs, func(ctx context.Context, clinicIDs []string) (map[string]*CompanyRegistryOutput, error) {
+		rows, err := s.queries.ListClinicRegistryRecordsByClinicIDs(ctx, repository.ListClinicRegistryRecordsByClinicIDsParams{
+			OrganizationID: organizationID(ctx),
+			ClinicIds:      clinicIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		recordsByClinic := make(map[string]*CompanyRegistryOutput, len(clinicIDs))
+		for _, row := range rows {
+			recordsByClinic[row.ClinicID] = mapClinicRegistryRecord(row)
+		}
+		return recordsByClinic, nil
+	})
+}
+
+func mapClinicRegistryRecord(record repository.ClinicRegistryRecord) *CompanyRegistryOutput {
+	return &CompanyRegistryOutput{
+		LegalName:          record.LegalName,
+		TradeName:          nullToPointer(record.TradeName),
+		CNAECode:           nullToPointer(record.CnaeCode),
+		CNAEDescription:    nullToPointer(record.CnaeDescription),
+		RegistrationStatus: record.RegistrationStatus,
+		Source:             record.Source,
+		FetchedAt:          record.FetchedAt,
+	}
+}
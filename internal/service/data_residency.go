@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	DestinationAttachments = "attachments"
+	DestinationWebhooks    = "webhooks"
+	DestinationAuditExport = "audit_export"
+	DestinationWarehouse   = "warehouse_export"
+)
+
+var dataRegionPattern = regexp.MustCompile(`^[a-z]{2}(-[a-z0-9]+)*$`)
+
+// DestinationRegions declares where each configured destination keeps the data it receives. Attachment storage also holds offboarding exports.
+type DestinationRegions struct {
+	Attachments string
+	Webhooks    string
+	AuditExport string
+	Warehouse   string
+}
+
+func WithDestinationRegions(regions DestinationRegions) Option {
+	return func(s *Service) {
+		s.destinationRegions = DestinationRegions{
+			Attachments: normalizeDataRegion(regions.Attachments),
+			Webhooks:    normalizeDataRegion(regions.Webhooks),
+			AuditExport: normalizeDataRegion(regions.AuditExport),
+			Warehouse:   normalizeDataRegion(regions.Warehouse),
+		}
+	}
+}
+
+func normalizeDataRegion(region string) string {
+	return strings.ToLower(strings.TrimSpace(region))
+}
+
+func validateDataRegion(region *string) (sql.NullString, error) {
+	if region == nil || strings.TrimSpace(*region) == "" {
+		return sql.NullString{}, nil
+	}
+	normalized := normalizeDataRegion(*region)
+	if !dataRegionPattern.MatchString(normalized) {
+		return sql.NullString{}, validationError("data_region must be a lowercase region code such as br or sa-east-1")
+	}
+	return sql.NullString{String: normalized, Valid: true}, nil
+}
+
+// destinationRegion reports the declared region of a destination and whether the destination is in use at all.
+func (s *Service) destinationRegion(destination string) (string, bool) {
+	switch destination {
+	case DestinationAttachments:
+		return s.destinationRegions.Attachments, s.attachments != nil
+	case DestinationWebhooks:
+		return s.destinationRegions.Webhooks, s.events != nil
+	case DestinationAuditExport:
+		return s.destinationRegions.AuditExport, s.auditExport != nil
+	case DestinationWarehouse:
+		return s.destinationRegions.Warehouse, s.warehouseExport != nil
+	}
+	return "", false
+}
+
+func (s *Service) checkDestinationRegion(dataRegion sql.NullString, destination string) error {
+	if !dataRegion.Valid {
+		return nil
+	}
+	region, inUse := s.destinationRegion(destination)
+	if !inUse {
+		return nil
+	}
+	if region == "" {
+		return dataResidencyError(fmt.Sprintf("%s has no declared region and organization data must stay in %s", destination, dataRegion.String))
+	}
+	if region != dataRegion.String {
+		return dataResidencyError(fmt.Sprintf("%s is in %s and organization data must stay in %s", destination, region, dataRegion.String))
+	}
+	return nil
+}
+
+// checkDestinationRegions reports every destination in use that would take the region's data elsewhere.
+func (s *Service) checkDestinationRegions(dataRegion sql.NullString) error {
+	var errs []error
+	for _, destination := range []string{DestinationAttachments, DestinationWebhooks, DestinationAuditExport, DestinationWarehouse} {
+		if err := s.checkDestinationRegion(dataRegion, destination); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// checkDataResidency refuses to send the caller's organization data to a destination outside its declared region.
+func (s *Service) checkDataResidency(ctx context.Context, destination string) error {
+	organization := organizationID(ctx)
+	if organization == "" {
+		return nil
+	}
+	row, err := s.queries.GetOrganizationByID(ctx, organization)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("organization not found")
+		}
+		return err
+	}
+	return s.checkDestinationRegion(row.DataRegion, destination)
+}
+
+// CheckDataResidency runs at startup and rejects a destination configuration that would move any organization's data out of its region.
+func (s *Service) CheckDataResidency(ctx context.Context) error {
+	organizations, err := s.queries.ListOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, organization := range organizations {
+		if organization.PurgedAt.Valid {
+			continue
+		}
+		if err := s.checkDestinationRegions(organization.DataRegion); err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", organization.Slug, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) UpdateOrganizationDataRegion(ctx context.Context, id string, input UpdateOrganizationDataRegionInput) (OrganizationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateOrganizationDataRegion")
+	defer span.End()
+
+	dataRegion, err := validateDataRegion(input.DataRegion)
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+	if err := s.checkDestinationRegions(dataRegion); err != nil {
+		return OrganizationOutput{}, err
+	}
+
+	ctx = WithOrganization(ctx, id)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return OrganizationOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	current, err := qtx.LockOrganizationForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrganizationOutput{}, notFoundError("organization not found")
+		}
+		return OrganizationOutput{}, err
+	}
+	if current.Status == OrganizationStatusClosed {
+		return OrganizationOutput{}, conflictError("organization is closed")
+	}
+	organization, err := qtx.UpdateOrganizationDataRegion(ctx, repository.UpdateOrganizationDataRegionParams{
+		DataRegion: dataRegion,
+		ID:         id,
+	})
+	if err != nil {
+		return OrganizationOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "organization.data_region_changed",
+		EntityType: AuditEntityOrganization,
+		EntityID:   id,
+		Metadata:   map[string]any{"from": nullToPointer(current.DataRegion), "to": nullToPointer(dataRegion)},
+	}); err != nil {
+		return OrganizationOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return OrganizationOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapOrganizationOutput(organization), nil
+}
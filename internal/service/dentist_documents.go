@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	DocumentTypeCRORenewal         = "CRO_RENEWAL"
+	DocumentTypeLiabilityInsurance = "LIABILITY_INSURANCE"
+	DocumentTypeVaccinationCard    = "VACCINATION_CARD"
+
+	DocumentStatusValid    = "VALID"
+	DocumentStatusExpiring = "EXPIRING"
+	DocumentStatusExpired  = "EXPIRED"
+
+	EventDocumentExpiring = "dentist_document.expiring"
+	EventDocumentExpired  = "dentist_document.expired"
+
+	DefaultExpiringWithinDays = 30
+	MaxExpiringWithinDays     = 365
+
+	documentDateLayout                = "2006-01-02"
+	maxDocumentNumberLength           = 64
+	maxDocumentNotesLength            = 500
+	documentTypeUniqueConstraint      = "idx_dentist_documents_type_active_unique"
+	defaultDocumentExpiryNoticeWindow = 30
+)
+
+var defaultDocumentNoticeDays = []int{30, 7}
+
+var knownDocumentTypes = map[string]struct{}{
+	DocumentTypeCRORenewal:         {},
+	DocumentTypeLiabilityInsurance: {},
+	DocumentTypeVaccinationCard:    {},
+}
+
+type DocumentExpiryEventData struct {
+	Document      DentistDocumentOutput `json:"document"`
+	ClinicIDs     []string              `json:"clinic_ids"`
+	ThresholdDays int                   `json:"threshold_days"`
+}
+
+func WithDocumentNoticeDays(days []int) Option {
+	return func(s *Service) {
+		s.documentNoticeDays = normalizeNoticeDays(days)
+	}
+}
+
+func (s *Service) CreateDentistDocument(ctx context.Context, dentistID string, input CreateDentistDocumentInput) (DentistDocumentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateDentistDocument")
+	defer span.End()
+
+	documentType := strings.ToUpper(strings.TrimSpace(input.DocumentType))
+	if _, ok := knownDocumentTypes[documentType]; !ok {
+		return DentistDocumentOutput{}, validationError("document_type must be CRO_RENEWAL, LIABILITY_INSURANCE or VACCINATION_CARD")
+	}
+	expiresAt, err := parseDocumentDate("expires_at", &input.ExpiresAt)
+	if err != nil {
+		return DentistDocumentOutput{}, err
+	}
+	issuedAt, err := parseDocumentDate("issued_at", input.IssuedAt)
+	if err != nil {
+		return DentistDocumentOutput{}, err
+	}
+	if issuedAt.Valid && issuedAt.Time.After(expiresAt.Time) {
+		return DentistDocumentOutput{}, validationError("issued_at must not be after expires_at")
+	}
+	if err := validateOptionalMaxLength("document_number", input.DocumentNumber, maxDocumentNumberLength); err != nil {
+		return DentistDocumentOutput{}, err
+	}
+	if err := validateOptionalMaxLength("notes", input.Notes, maxDocumentNotesLength); err != nil {
+		return DentistDocumentOutput{}, err
+	}
+
+	if _, err := s.queries.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DentistDocumentOutput{}, notFoundError("dentist not found")
+		}
+		return DentistDocumentOutput{}, err
+	}
+
+	documentID, err := newUUIDV7()
+	if err != nil {
+		return DentistDocumentOutput{}, err
+	}
+	document, err := s.queries.CreateDentistDocument(ctx, repository.CreateDentistDocumentParams{
+		OrganizationID: organizationID(ctx),
+		ID:             documentID,
+		DentistID:      dentistID,
+		DocumentType:   documentType,
+		DocumentNumber: optionalString(input.DocumentNumber),
+		IssuedAt:       issuedAt,
+		ExpiresAt:      expiresAt.Time,
+		Notes:          optionalString(input.Notes),
+	})
+	if err != nil {
+		return DentistDocumentOutput{}, mapDentistDocumentDatabaseError(err)
+	}
+	return mapDentistDocument(document, s.today()), nil
+}
+
+func (s *Service) ListDentistDocuments(ctx context.Context, dentistID string) ([]DentistDocumentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListDentistDocuments")
+	defer span.End()
+
+	if _, err := s.queries.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("dentist not found")
+		}
+		return nil, err
+	}
+
+	documents, err := s.queries.ListDentistDocuments(ctx, repository.ListDentistDocumentsParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	today := s.today()
+	output := make([]DentistDocumentOutput, 0, len(documents))
+	for _, document := range documents {
+		output = append(output, mapDentistDocument(document, today))
+	}
+	return output, nil
+}
+
+func (s *Service) UpdateDentistDocument(ctx context.Context, dentistID string, documentID string, input UpdateDentistDocumentInput) (DentistDocumentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateDentistDocument")
+	defer span.End()
+
+	if input.DocumentNumber == nil && input.IssuedAt == nil && input.ExpiresAt == nil && input.Notes == nil {
+		return DentistDocumentOutput{}, validationError("at least one field must be provided")
+	}
+	expiresAt, err := parseDocumentDate("expires_at", input.ExpiresAt)
+	if err != nil {
+		return DentistDocumentOutput{}, err
+	}
+	issuedAt, err := parseDocumentDate("issued_at", input.IssuedAt)
+	if err != nil {
+		return DentistDocumentOutput{}, err
+	}
+	if err := validateOptionalMaxLength("document_number", input.DocumentNumber, maxDocumentNumberLength); err != nil {
+		return DentistDocumentOutput{}, err
+	}
+	if err := validateOptionalMaxLength("notes", input.Notes, maxDocumentNotesLength); err != nil {
+		return DentistDocumentOutput{}, err
+	}
+
+	current, err := s.queries.GetDentistDocument(ctx, repository.GetDentistDocumentParams{
+		OrganizationID: organizationID(ctx),
+		ID:             documentID,
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DentistDocumentOutput{}, notFoundError("document not found")
+		}
+		return DentistDocumentOutput{}, err
+	}
+	effectiveIssuedAt := current.IssuedAt
+	if issuedAt.Valid {
+		effectiveIssuedAt = issuedAt
+	}
+	effectiveExpiresAt := current.ExpiresAt
+	if expiresAt.Valid {
+		effectiveExpiresAt = expiresAt.Time
+	}
+	if effectiveIssuedAt.Valid && effectiveIssuedAt.Time.After(effectiveExpiresAt) {
+		return DentistDocumentOutput{}, validationError("issued_at must not be after expires_at")
+	}
+
+	document, err := s.queries.UpdateDentistDocument(ctx, repository.UpdateDentistDocumentParams{
+		OrganizationID: organizationID(ctx),
+		ID:             documentID,
+		DentistID:      dentistID,
+		DocumentNumber: optionalString(input.DocumentNumber),
+		IssuedAt:       issuedAt,
+		ExpiresAt:      expiresAt,
+		Notes:          optionalString(input.Notes),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DentistDocumentOutput{}, notFoundError("document not found")
+		}
+		return DentistDocumentOutput{}, mapDentistDocumentDatabaseError(err)
+	}
+	return mapDentistDocument(document, s.today()), nil
+}
+
+func (s *Service) DeleteDentistDocument(ctx context.Context, dentistID string, documentID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteDentistDocument")
+	defer span.End()
+
+	rows, err := s.queries.DeleteDentistDocument(ctx, repository.DeleteDentistDocumentParams{
+		OrganizationID: organizationID(ctx),
+		ID:             documentID,
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return notFoundError("document not found")
+	}
+	return nil
+}
+
+func (s *Service) ListClinicExpiringDocuments(ctx context.Context, clinicID string, withinDays int) ([]ExpiringDocumentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicExpiringDocuments")
+	defer span.End()
+
+	if withinDays < 0 || withinDays > MaxExpiringWithinDays {
+		return nil, validationError(fmt.Sprintf("within_days must be between 0 and %d", MaxExpiringWithinDays))
+	}
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+
+	today := s.todayIn(clinicLocation(ctx, clinic.Timezone))
+	rows, err := s.queries.ListExpiringDocumentsByClinic(ctx, repository.ListExpiringDocumentsByClinicParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		Cutoff:         today.AddDate(0, 0, withinDays),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := make([]ExpiringDocumentOutput, 0, len(rows))
+	for _, row := range rows {
+		document := mapDentistDocument(repository.DentistDocument{
+			ID:             row.ID,
+			DentistID:      row.DentistID,
+			DocumentType:   row.DocumentType,
+			DocumentNumber: row.DocumentNumber,
+			IssuedAt:       row.IssuedAt,
+			ExpiresAt:      row.ExpiresAt,
+			Notes:          row.Notes,
+		}, today)
+		output = append(output, ExpiringDocumentOutput{
+			DentistDocumentOutput: document,
+			DentistLegalName:      row.DentistLegalName,
+		})
+	}
+	return output, nil
+}
+
+func (s *Service) NotifyExpiringDocuments(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.NotifyExpiringDocuments")
+	defer span.End()
+
+	if !s.webhooksEnabled(ctx) {
+		return 0, nil
+	}
+	noticeDays := s.documentNoticeDays
+	if len(noticeDays) == 0 {
+		noticeDays = normalizeNoticeDays(defaultDocumentNoticeDays)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	today := s.today()
+	documents, err := qtx.ListDocumentsDueForNotification(ctx, repository.ListDocumentsDueForNotificationParams{
+		OrganizationID: organizationID(ctx),
+		Cutoff:         today.AddDate(0, 0, noticeDays[0]),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, document := range documents {
+		output := mapDentistDocument(document, today)
+		threshold, due := documentNoticeThreshold(output.DaysUntilExpiry, noticeDays)
+		if !due {
+			continue
+		}
+		if document.LastNotifiedThresholdDays.Valid && int(document.LastNotifiedThresholdDays.Int32) <= threshold {
+			continue
+		}
+
+		clinicIDs, err := qtx.ListActiveClinicIDsByDentist(ctx, repository.ListActiveClinicIDsByDentistParams{
+			OrganizationID: organizationID(ctx),
+			DentistID:      document.DentistID,
+		})
+		if err != nil {
+			return sent, err
+		}
+		eventType := EventDocumentExpiring
+		if threshold == 0 {
+			eventType = EventDocumentExpired
+		}
+		event, err := s.newEvent(eventType, DocumentExpiryEventData{
+			Document:      output,
+			ClinicIDs:     clinicIDs,
+			ThresholdDays: threshold,
+		})
+		if err != nil {
+			return sent, err
+		}
+		if err := s.events.Publish(ctx, event); err != nil {
+			// Left unmarked so the next run retries the delivery.
+			slog.WarnContext(ctx, "publish document expiry event failed", "document_id", document.ID, "error", err)
+			continue
+		}
+		if err := qtx.MarkDentistDocumentNotified(ctx, repository.MarkDentistDocumentNotifiedParams{
+			OrganizationID: organizationID(ctx),
+			ID:             document.ID,
+			ThresholdDays:  int32(threshold),
+		}); err != nil {
+			return sent, err
+		}
+		sent++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return sent, fmt.Errorf("commit transaction: %w", err)
+	}
+	return sent, nil
+}
+
+func (s *Service) today() time.Time {
+	return s.todayIn(time.UTC)
+}
+
+func normalizeNoticeDays(days []int) []int {
+	normalized := make([]int, 0, len(days)+1)
+	for _, day := range days {
+		if day > 0 && !slices.Contains(normalized, day) {
+			normalized = append(normalized, day)
+		}
+	}
+	slices.Sort(normalized)
+	slices.Reverse(normalized)
+	return append(normalized, 0)
+}
+
+// documentNoticeThreshold picks the tightest notice window the document has entered; noticeDays is sorted
+// descending and always ends with 0 (the expiry day itself).
+func documentNoticeThreshold(daysUntilExpiry int, noticeDays []int) (int, bool) {
+	if len(noticeDays) == 0 || daysUntilExpiry > noticeDays[0] {
+		return 0, false
+	}
+	threshold := noticeDays[0]
+	for _, day := range noticeDays {
+		if daysUntilExpiry <= day {
+			threshold = day
+		}
+	}
+	return threshold, true
+}
+
+func parseDocumentDate(field string, value *string) (sql.NullTime, error) {
+	if value == nil {
+		return sql.NullTime{}, nil
+	}
+	trimmed := strings.TrimSpace(*value)
+	if trimmed == "" {
+		return sql.NullTime{}, validationError(fmt.Sprintf("%s is required", field))
+	}
+	parsed, err := time.Parse(documentDateLayout, trimmed)
+	if err != nil {
+		return sql.NullTime{}, validationError(fmt.Sprintf("%s must be a date in YYYY-MM-DD format", field))
+	}
+	return sql.NullTime{Time: parsed, Valid: true}, nil
+}
+
+func documentStatus(daysUntilExpiry int) string {
+	switch {
+	case daysUntilExpiry < 0:
+		return DocumentStatusExpired
+	case daysUntilExpiry <= defaultDocumentExpiryNoticeWindow:
+		return DocumentStatusExpiring
+	default:
+		return DocumentStatusValid
+	}
+}
+
+func mapDentistDocument(document repository.DentistDocument, today time.Time) DentistDocumentOutput {
+	expiresAt := time.Date(document.ExpiresAt.Year(), document.ExpiresAt.Month(), document.ExpiresAt.Day(), 0, 0, 0, 0, time.UTC)
+	daysUntilExpiry := int(expiresAt.Sub(today).Hours() / 24)
+
+	output := DentistDocumentOutput{
+		ID:              document.ID,
+		DentistID:       document.DentistID,
+		DocumentType:    document.DocumentType,
+		DocumentNumber:  nullToPointer(document.DocumentNumber),
+		ExpiresAt:       expiresAt.Format(documentDateLayout),
+		Notes:           nullToPointer(document.Notes),
+		DaysUntilExpiry: daysUntilExpiry,
+		Status:          documentStatus(daysUntilExpiry),
+	}
+	if document.IssuedAt.Valid {
+		issuedAt := document.IssuedAt.Time.Format(documentDateLayout)
+		output.IssuedAt = &issuedAt
+	}
+	return output
+}
+
+func mapDentistDocumentDatabaseError(err error) error {
+	if isConstraintViolation(err, documentTypeUniqueConstraint) {
+		return conflictError("dentist already has an active document of this type")
+	}
+	return mapDatabaseError(err)
+}
@@ -0,0 +1,295 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	DentistPhotoMaxBytes     = 5 << 20
+	dentistPhotoSize         = 512
+	dentistThumbnailSize     = 128
+	dentistThumbnailPage     = 50
+	dentistPhotoMaxDimension = 8000
+	dentistPhotoJPEGQuality  = 85
+	dentistPhotoContentType  = "image/jpeg"
+)
+
+var allowedDentistPhotoTypes = map[string]struct{}{
+	"image/jpeg": {},
+	"image/png":  {},
+	"image/webp": {},
+}
+
+type Attachment struct {
+	ContentType string
+	Data        []byte
+	UpdatedAt   time.Time
+}
+
+type AttachmentStore interface {
+	PutAttachment(ctx context.Context, key string, contentType string, data []byte) error
+	GetAttachment(ctx context.Context, key string) (Attachment, bool, error)
+	DeleteAttachment(ctx context.Context, key string) error
+}
+
+func WithAttachmentStore(store AttachmentStore, publicBaseURL string) Option {
+	return func(s *Service) {
+		s.attachments = store
+		s.publicBaseURL = strings.TrimRight(strings.TrimSpace(publicBaseURL), "/")
+	}
+}
+
+func (s *Service) SetDentistPhoto(ctx context.Context, dentistID string, data []byte) (DentistPhotoOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.SetDentistPhoto")
+	defer span.End()
+
+	if s.attachments == nil {
+		return DentistPhotoOutput{}, errors.New("attachment storage is not configured")
+	}
+	if len(data) == 0 {
+		return DentistPhotoOutput{}, validationError("photo is required")
+	}
+	if len(data) > DentistPhotoMaxBytes {
+		return DentistPhotoOutput{}, validationError(fmt.Sprintf("photo must be at most %d bytes", DentistPhotoMaxBytes))
+	}
+	if _, ok := allowedDentistPhotoTypes[http.DetectContentType(data)]; !ok {
+		return DentistPhotoOutput{}, validationError("photo must be a JPEG, PNG or WebP image")
+	}
+
+	current, err := s.queries.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DentistPhotoOutput{}, notFoundError("dentist not found")
+		}
+		return DentistPhotoOutput{}, err
+	}
+
+	if s.attachmentScanner != nil {
+		return s.quarantineDentistPhoto(ctx, current, data, http.DetectContentType(data))
+	}
+
+	resized, err := resizeDentistPhoto(data)
+	if err != nil {
+		return DentistPhotoOutput{}, err
+	}
+	if err := s.ensureStorageQuota(ctx, current.PhotoSizeBytes, int64(len(resized))); err != nil {
+		return DentistPhotoOutput{}, err
+	}
+
+	if err := s.checkDataResidency(ctx, DestinationAttachments); err != nil {
+		return DentistPhotoOutput{}, err
+	}
+
+	key := fmt.Sprintf("dentists/%s/photo.jpg", dentistID)
+	if err := s.attachments.PutAttachment(ctx, key, dentistPhotoContentType, resized); err != nil {
+		return DentistPhotoOutput{}, fmt.Errorf("store dentist photo: %w", err)
+	}
+
+	dentist, err := s.queries.UpdateDentistPhoto(ctx, repository.UpdateDentistPhotoParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+		PhotoKey:       sql.NullString{String: key, Valid: true},
+		PhotoSizeBytes: int64(len(resized)),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DentistPhotoOutput{}, notFoundError("dentist not found")
+		}
+		return DentistPhotoOutput{}, mapDatabaseError(err)
+	}
+
+	return DentistPhotoOutput{
+		PhotoURL:     *s.dentistPhotoURL(dentist.ID, dentist.PhotoUpdatedAt),
+		ThumbnailURL: *s.dentistThumbnailURL(dentist.ID, dentist.PhotoUpdatedAt),
+		Width:        dentistPhotoSize,
+		Height:       dentistPhotoSize,
+		UpdatedAt:    nullTimeToPointer(dentist.PhotoUpdatedAt),
+	}, nil
+}
+
+func (s *Service) GetDentistPhoto(ctx context.Context, dentistID string) (Attachment, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetDentistPhoto")
+	defer span.End()
+
+	return s.loadDentistPhoto(ctx, dentistID, false)
+}
+
+// GetDentistPhotoThumbnail serves the full photo while the thumbnail for its current version is still being generated.
+func (s *Service) GetDentistPhotoThumbnail(ctx context.Context, dentistID string) (Attachment, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetDentistPhotoThumbnail")
+	defer span.End()
+
+	return s.loadDentistPhoto(ctx, dentistID, true)
+}
+
+func (s *Service) loadDentistPhoto(ctx context.Context, dentistID string, thumbnail bool) (Attachment, error) {
+	// Photo URLs are public, so the dentist id is what places the request in its organization.
+	dentistOrganizationID, err := findInTenantSchemas(ctx, s, func(ctx context.Context) (string, error) {
+		return s.queries.GetDentistOrganizationID(ctx, dentistID)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Attachment{}, notFoundError("dentist not found")
+		}
+		return Attachment{}, err
+	}
+	ctx = WithOrganization(ctx, dentistOrganizationID)
+
+	dentist, err := s.queries.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Attachment{}, notFoundError("dentist not found")
+		}
+		return Attachment{}, err
+	}
+	if !dentist.PhotoKey.Valid || s.attachments == nil {
+		return Attachment{}, notFoundError("dentist photo not found")
+	}
+	key := dentist.PhotoKey.String
+	if thumbnail && dentist.PhotoThumbnailKey.Valid && !dentist.PhotoThumbnailUpdatedAt.Time.Before(dentist.PhotoUpdatedAt.Time) {
+		key = dentist.PhotoThumbnailKey.String
+	}
+
+	attachment, found, err := s.attachments.GetAttachment(ctx, key)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("load dentist photo: %w", err)
+	}
+	if !found {
+		return Attachment{}, notFoundError("dentist photo not found")
+	}
+	if dentist.PhotoUpdatedAt.Valid {
+		attachment.UpdatedAt = dentist.PhotoUpdatedAt.Time
+	}
+	return attachment, nil
+}
+
+func (s *Service) dentistPhotoURL(dentistID string, photoUpdatedAt sql.NullTime) *string {
+	if !photoUpdatedAt.Valid {
+		return nil
+	}
+	url := fmt.Sprintf("%s/api/v1/dentists/%s/photo", s.publicBaseURL, dentistID)
+	return &url
+}
+
+func (s *Service) dentistThumbnailURL(dentistID string, photoUpdatedAt sql.NullTime) *string {
+	if !photoUpdatedAt.Valid {
+		return nil
+	}
+	url := fmt.Sprintf("%s/api/v1/dentists/%s/photo/thumbnail", s.publicBaseURL, dentistID)
+	return &url
+}
+
+// GenerateDentistPhotoThumbnails builds the thumbnail of every photo in the caller's organization that changed since its last
+// thumbnail. A photo replaced mid-way keeps its row stale, so the next run picks up the new version.
+func (s *Service) GenerateDentistPhotoThumbnails(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GenerateDentistPhotoThumbnails")
+	defer span.End()
+
+	if s.attachments == nil {
+		return 0, nil
+	}
+	rows, err := s.queries.ListDentistsNeedingPhotoThumbnail(ctx, repository.ListDentistsNeedingPhotoThumbnailParams{
+		OrganizationID: organizationID(ctx),
+		PageLimit:      dentistThumbnailPage,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 {
+		return 0, nil
+	}
+	if err := s.checkDataResidency(ctx, DestinationAttachments); err != nil {
+		slog.WarnContext(ctx, "dentist photo thumbnails skipped", "error", err)
+		return 0, nil
+	}
+
+	generated := 0
+	for _, row := range rows {
+		photo, found, err := s.attachments.GetAttachment(ctx, row.PhotoKey)
+		if err != nil {
+			return generated, fmt.Errorf("load dentist photo %s: %w", row.ID, err)
+		}
+		if !found {
+			continue
+		}
+		thumbnail, err := resizeDentistPhotoTo(photo.Data, dentistThumbnailSize)
+		if err != nil {
+			slog.WarnContext(ctx, "dentist photo thumbnail failed", "dentist_id", row.ID, "error", err)
+			continue
+		}
+		key := fmt.Sprintf("dentists/%s/photo-thumbnail.jpg", row.ID)
+		if err := s.attachments.PutAttachment(ctx, key, dentistPhotoContentType, thumbnail); err != nil {
+			return generated, fmt.Errorf("store dentist photo thumbnail %s: %w", row.ID, err)
+		}
+		updated, err := s.queries.UpdateDentistPhotoThumbnail(ctx, repository.UpdateDentistPhotoThumbnailParams{
+			OrganizationID:    organizationID(ctx),
+			ID:                row.ID,
+			PhotoThumbnailKey: sql.NullString{String: key, Valid: true},
+			PhotoUpdatedAt:    row.PhotoUpdatedAt,
+		})
+		if err != nil {
+			return generated, mapDatabaseError(err)
+		}
+		if updated > 0 {
+			generated++
+		}
+	}
+	return generated, nil
+}
+
+func resizeDentistPhoto(data []byte) ([]byte, error) {
+	return resizeDentistPhotoTo(data, dentistPhotoSize)
+}
+
+func resizeDentistPhotoTo(data []byte, size int) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, validationError("photo could not be decoded")
+	}
+	if config.Width > dentistPhotoMaxDimension || config.Height > dentistPhotoMaxDimension {
+		return nil, validationError(fmt.Sprintf("photo dimensions must be at most %dx%d", dentistPhotoMaxDimension, dentistPhotoMaxDimension))
+	}
+
+	source, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, validationError("photo could not be decoded")
+	}
+
+	bounds := source.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	offsetX := bounds.Min.X + (bounds.Dx()-side)/2
+	offsetY := bounds.Min.Y + (bounds.Dy()-side)/2
+	crop := image.Rect(offsetX, offsetY, offsetX+side, offsetY+side)
+
+	target := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.CatmullRom.Scale(target, target.Bounds(), source, crop, draw.Src, nil)
+
+	var buffer bytes.Buffer
+	if err := jpeg.Encode(&buffer, target, &jpeg.Options{Quality: dentistPhotoJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("encode dentist photo: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
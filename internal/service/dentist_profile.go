@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+func (s *Service) GetDentistProfile(ctx context.Context, dentistID string) (DentistOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetDentistProfile")
+	defer span.End()
+
+	details, err := s.queries.GetDentistDetailsByID(ctx, repository.GetDentistDetailsByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DentistOutput{}, notFoundError("dentist not found")
+		}
+		return DentistOutput{}, err
+	}
+
+	output := mapDentistOutput(
+		repository.Dentist{ID: details.DentistID, CroNumber: details.CroNumber, CroState: details.CroState},
+		repository.Person{ID: details.PersonID, LegalName: details.LegalName, TaxIDNumber: details.TaxIDNumber, Email: details.Email, Phone: details.Phone},
+	)
+	output.PhotoURL = s.dentistPhotoURL(details.DentistID, details.PhotoUpdatedAt)
+	output.ThumbnailURL = s.dentistThumbnailURL(details.DentistID, details.PhotoUpdatedAt)
+	output.Address, err = s.loadAddress(ctx, details.PersonID)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	output.Specialties, err = s.loadDentistSpecialties(ctx, details.DentistID)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	return output, nil
+}
+
+func (s *Service) GetDentistIncludingDeleted(ctx context.Context, dentistID string) (DentistOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetDentistIncludingDeleted")
+	defer span.End()
+
+	details, err := s.queries.GetDentistDetailsIncludingDeleted(ctx, repository.GetDentistDetailsIncludingDeletedParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DentistOutput{}, notFoundError("dentist not found")
+		}
+		return DentistOutput{}, err
+	}
+	if !details.DeletedAt.Valid {
+		return s.GetDentistProfile(ctx, dentistID)
+	}
+
+	output := mapDentistOutput(
+		repository.Dentist{ID: details.DentistID, CroNumber: details.CroNumber, CroState: details.CroState},
+		repository.Person{ID: details.PersonID, LegalName: details.LegalName, TaxIDNumber: details.TaxIDNumber, Email: details.Email, Phone: details.Phone},
+	)
+	output.DeletedAt = nullTimeToPointer(details.DeletedAt)
+	output.Address, err = s.loadAddress(ctx, details.PersonID)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	output.Specialties, err = s.loadDentistSpecialties(ctx, details.DentistID)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	return output, nil
+}
+
+func (s *Service) UpdateOwnDentistProfile(ctx context.Context, dentistID string, input UpdateDentistProfileInput) (DentistOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateOwnDentistProfile")
+	defer span.End()
+
+	// Legal name, CPF and CRO stay under clinic admin control; only contact data is self-service.
+	return s.UpdateDentist(ctx, dentistID, UpdateDentistInput{
+		Email:        input.Email,
+		Phone:        input.Phone,
+		Address:      input.Address,
+		SpecialtyIDs: input.SpecialtyIDs,
+	})
+}
@@ -0,0 +1,443 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+func (s *Service) ReassignDentistPerson(ctx context.Context, dentistID string, input ReassignDentistPersonInput) (ReassignDentistPersonOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ReassignDentistPerson")
+	defer span.End()
+
+	validations := s.newValidationCollector()
+	taxID := validation.NormalizeCPF(input.TaxIDNumber)
+	if taxID == "" {
+		return ReassignDentistPersonOutput{}, validationError("invalid CPF")
+	}
+	if err := validations.check(ValidationRuleTaxID, validation.ValidateCPF(taxID), "invalid CPF"); err != nil {
+		return ReassignDentistPersonOutput{}, err
+	}
+	if err := s.screenTaxID(ctx, taxIDTypeCPF, taxID); err != nil {
+		return ReassignDentistPersonOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ReassignDentistPersonOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	output, survivorID, err := reassignDentistPerson(ctx, s.txQuerier(tx), dentistID, taxID)
+	if err != nil {
+		return ReassignDentistPersonOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ReassignDentistPersonOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	output.Dentist, err = s.GetDentistProfile(ctx, survivorID)
+	if err != nil {
+		return ReassignDentistPersonOutput{}, err
+	}
+	output.Dentist.Warnings = validations.result()
+	return output, nil
+}
+
+// reassignDentistPerson points the dentist at the person holding taxID, creating it when missing, or merges it into the
+// dentist that person already has. It returns the dentist that survives.
+func reassignDentistPerson(ctx context.Context, qtx repository.Querier, dentistID string, taxID string) (ReassignDentistPersonOutput, string, error) {
+	source, err := qtx.GetDentistDetailsByID(ctx, repository.GetDentistDetailsByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ReassignDentistPersonOutput{}, "", notFoundError("dentist not found")
+		}
+		return ReassignDentistPersonOutput{}, "", err
+	}
+	if source.TaxIDNumber == taxID {
+		return ReassignDentistPersonOutput{}, "", validationError("dentist is already linked to this tax_id_number")
+	}
+
+	output := ReassignDentistPersonOutput{PreviousPersonID: source.PersonID}
+	targetPerson, err := qtx.GetPersonByTaxID(ctx, repository.GetPersonByTaxIDParams{
+		OrganizationID: organizationID(ctx),
+		TaxIDNumber:    taxID,
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		targetPerson, err = createPersonFromDentist(ctx, qtx, source, taxID)
+		if err != nil {
+			return ReassignDentistPersonOutput{}, "", err
+		}
+	case err != nil:
+		return ReassignDentistPersonOutput{}, "", err
+	}
+	if targetPerson.PersonType != personTypeIndividual {
+		return ReassignDentistPersonOutput{}, "", conflictError("tax_id is linked to a company person")
+	}
+
+	survivorID := source.DentistID
+	targetDentist, err := qtx.GetDentistByPersonID(ctx, repository.GetDentistByPersonIDParams{
+		OrganizationID: organizationID(ctx),
+		PersonID:       targetPerson.ID,
+	})
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		if _, err := qtx.UpdateDentistPerson(ctx, repository.UpdateDentistPersonParams{
+			OrganizationID: organizationID(ctx),
+			ID:             source.DentistID,
+			PersonID:       targetPerson.ID,
+		}); err != nil {
+			return ReassignDentistPersonOutput{}, "", mapDentistDatabaseError(err)
+		}
+		if _, err := qtx.CopyAddress(ctx, repository.CopyAddressParams{
+			OrganizationID: organizationID(ctx),
+			TargetPersonID: targetPerson.ID,
+			PersonID:       source.PersonID,
+		}); err != nil {
+			return ReassignDentistPersonOutput{}, "", mapDatabaseError(err)
+		}
+	case err != nil:
+		return ReassignDentistPersonOutput{}, "", err
+	default:
+		if err := mergeDentists(ctx, qtx, source, targetDentist); err != nil {
+			return ReassignDentistPersonOutput{}, "", err
+		}
+		survivorID = targetDentist.ID
+		output.MergedFromDentistID = &source.DentistID
+	}
+	if _, err := qtx.DeletePerson(ctx, repository.DeletePersonParams{
+		OrganizationID: organizationID(ctx),
+		ID:             source.PersonID,
+	}); err != nil {
+		return ReassignDentistPersonOutput{}, "", mapDatabaseError(err)
+	}
+	return output, survivorID, nil
+}
+
+func createPersonFromDentist(ctx context.Context, qtx repository.Querier, source repository.GetDentistDetailsByIDRow, taxID string) (repository.Person, error) {
+	personID, err := newUUIDV7()
+	if err != nil {
+		return repository.Person{}, err
+	}
+	person, err := qtx.CreatePerson(ctx, repository.CreatePersonParams{
+		OrganizationID: organizationID(ctx),
+		ID:             personID,
+		PersonType:     personTypeIndividual,
+		TaxIDType:      taxIDTypeCPF,
+		TaxIDNumber:    taxID,
+		LegalName:      source.LegalName,
+		Email:          source.Email,
+		Phone:          source.Phone,
+	})
+	if err != nil {
+		return repository.Person{}, mapDatabaseError(err)
+	}
+	return person, nil
+}
+
+func mergeDentists(ctx context.Context, qtx repository.Querier, source repository.GetDentistDetailsByIDRow, target repository.Dentist) error {
+	sourceLinks, err := qtx.ListClinicDentistRowsByDentist(ctx, repository.ListClinicDentistRowsByDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      source.DentistID,
+	})
+	if err != nil {
+		return err
+	}
+	targetLinks, err := qtx.ListClinicDentistRowsByDentist(ctx, repository.ListClinicDentistRowsByDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      target.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	activeTargetLinks := make(map[string]repository.ClinicDentist, len(targetLinks))
+	clinicIDs := make([]string, 0, len(sourceLinks)+len(targetLinks))
+	for _, link := range targetLinks {
+		if !link.EndedAt.Valid {
+			activeTargetLinks[link.ClinicID] = link
+			clinicIDs = append(clinicIDs, link.ClinicID)
+		}
+	}
+	for _, link := range sourceLinks {
+		if !link.EndedAt.Valid {
+			clinicIDs = append(clinicIDs, link.ClinicID)
+		}
+	}
+	// Lock clinics in a stable order so concurrent merges cannot deadlock.
+	slices.Sort(clinicIDs)
+	clinicIDs = slices.Compact(clinicIDs)
+	roleCountsByClinic := make(map[string]repository.CountClinicRoleHoldersRow, len(clinicIDs))
+	for _, clinicID := range clinicIDs {
+		counts, err := lockClinicRoleCounts(ctx, qtx, clinicID)
+		if err != nil {
+			return err
+		}
+		roleCountsByClinic[clinicID] = counts
+	}
+
+	for _, link := range sourceLinks {
+		if targetLink, ok := activeTargetLinks[link.ClinicID]; ok && !link.EndedAt.Valid {
+			// Both dentists are active in the clinic: keep the target link with the union of roles.
+			if _, err := qtx.UpdateClinicDentistRole(ctx, repository.UpdateClinicDentistRoleParams{
+				OrganizationID:        organizationID(ctx),
+				ClinicID:              link.ClinicID,
+				DentistID:             target.ID,
+				IsAdmin:               sql.NullBool{Bool: targetLink.IsAdmin || link.IsAdmin, Valid: true},
+				IsLegalRepresentative: sql.NullBool{Bool: targetLink.IsLegalRepresentative || link.IsLegalRepresentative, Valid: true},
+			}); err != nil {
+				return mapDatabaseError(err)
+			}
+			if _, err := qtx.EndClinicDentist(ctx, repository.EndClinicDentistParams{
+				OrganizationID: organizationID(ctx),
+				ClinicID:       link.ClinicID,
+				DentistID:      source.DentistID,
+			}); err != nil {
+				return mapDatabaseError(err)
+			}
+		}
+		if _, err := qtx.ReassignClinicDentistRow(ctx, repository.ReassignClinicDentistRowParams{
+			OrganizationID:  organizationID(ctx),
+			TargetDentistID: target.ID,
+			ClinicID:        link.ClinicID,
+			DentistID:       source.DentistID,
+			StartedAt:       link.StartedAt,
+		}); err != nil {
+			return mapDatabaseError(err)
+		}
+	}
+	if _, err := qtx.ReassignSubstituteFor(ctx, repository.ReassignSubstituteForParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+
+	if _, err := qtx.CopyDentistSpecialties(ctx, repository.CopyDentistSpecialtiesParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.DeleteDentistSpecialtiesByDentist(ctx, repository.DeleteDentistSpecialtiesByDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.MoveDentistDocuments(ctx, repository.MoveDentistDocumentsParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.DeleteDentistDocumentsByDentist(ctx, repository.DeleteDentistDocumentsByDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.MoveDentistGeneratedDocuments(ctx, repository.MoveDentistGeneratedDocumentsParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.MoveDentistUser(ctx, repository.MoveDentistUserParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.DeleteUsersByDentistID(ctx, repository.DeleteUsersByDentistIDParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	// A note that already mentions the surviving dentist keeps that mention; the duplicate is dropped.
+	if _, err := qtx.MoveDentistNoteMentions(ctx, repository.MoveDentistNoteMentionsParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.DeleteDentistNoteMentions(ctx, repository.DeleteDentistNoteMentionsParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.MoveDentistClinicTasks(ctx, repository.MoveDentistClinicTasksParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.CopyDentistAnnouncementRecipients(ctx, repository.CopyDentistAnnouncementRecipientsParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.DeleteDentistAnnouncementRecipients(ctx, repository.DeleteDentistAnnouncementRecipientsParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	// The surviving dentist's own notification preferences win over the merged one's.
+	if _, err := qtx.MoveDentistNotificationPreferences(ctx, repository.MoveDentistNotificationPreferencesParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: target.ID,
+		DentistID:       source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if _, err := qtx.DeleteDentistNotificationPreferences(ctx, repository.DeleteDentistNotificationPreferencesParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+
+	if err := moveDentistShifts(ctx, qtx, source.DentistID, target.ID); err != nil {
+		return err
+	}
+	if err := moveDentistTimeClockEntries(ctx, qtx, source.DentistID, target.ID); err != nil {
+		return err
+	}
+
+	sourceDentist, err := qtx.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             source.DentistID,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := qtx.DeleteDentist(ctx, repository.DeleteDentistParams{
+		OrganizationID: organizationID(ctx),
+		ID:             source.DentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	// The CRO and photo only carry over when the surviving dentist has none, after the source row is gone
+	// so the active CRO unique index is not hit.
+	if !target.CroNumber.Valid && sourceDentist.CroNumber.Valid {
+		if _, err := qtx.UpdateDentistCRO(ctx, repository.UpdateDentistCROParams{
+			OrganizationID: organizationID(ctx),
+			ID:             target.ID,
+			CroNumber:      sourceDentist.CroNumber,
+			CroState:       sourceDentist.CroState,
+		}); err != nil {
+			return mapDentistDatabaseError(err)
+		}
+	}
+	if !target.PhotoKey.Valid && sourceDentist.PhotoKey.Valid {
+		if _, err := qtx.UpdateDentistPhoto(ctx, repository.UpdateDentistPhotoParams{
+			OrganizationID: organizationID(ctx),
+			ID:             target.ID,
+			PhotoKey:       sourceDentist.PhotoKey,
+			PhotoSizeBytes: sourceDentist.PhotoSizeBytes,
+		}); err != nil {
+			return mapDatabaseError(err)
+		}
+	}
+
+	for _, clinicID := range clinicIDs {
+		if err := ensureClinicRoleInvariant(ctx, qtx, clinicID, roleCountsByClinic[clinicID]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveDentistShifts hands the source's shifts to the target, refusing when one would overlap a shift the target already has.
+func moveDentistShifts(ctx context.Context, qtx repository.Querier, sourceID string, targetID string) error {
+	// Shift writes lock the dentist, so both are locked, in a stable order, before looking for overlaps.
+	dentistIDs := []string{sourceID, targetID}
+	slices.Sort(dentistIDs)
+	for _, dentistID := range dentistIDs {
+		if _, err := qtx.LockDentistForShift(ctx, repository.LockDentistForShiftParams{
+			OrganizationID: organizationID(ctx),
+			ID:             dentistID,
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return notFoundError("dentist not found")
+			}
+			return err
+		}
+	}
+	overlap, err := qtx.GetMergedDentistShiftOverlap(ctx, repository.GetMergedDentistShiftOverlapParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: targetID,
+		DentistID:       sourceID,
+	})
+	if err == nil {
+		return conflictError(fmt.Sprintf("dentist shift from %s to %s overlaps a shift of the surviving dentist (clinic %s)", overlap.StartsAt.UTC().Format(time.RFC3339), overlap.EndsAt.UTC().Format(time.RFC3339), overlap.ClinicID))
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	if _, err := qtx.MoveDentistShifts(ctx, repository.MoveDentistShiftsParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: targetID,
+		DentistID:       sourceID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	return nil
+}
+
+// moveDentistTimeClockEntries hands the source's punches to the target, so daily totals and timesheets keep their hours. A dentist
+// can only have one open punch, so the merge waits for one of them to clock out when both are clocked in.
+func moveDentistTimeClockEntries(ctx context.Context, qtx repository.Querier, sourceID string, targetID string) error {
+	openSource, err := qtx.GetOpenTimeClockEntry(ctx, repository.GetOpenTimeClockEntryParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      sourceID,
+	})
+	switch {
+	case err == nil:
+		openTarget, err := qtx.GetOpenTimeClockEntry(ctx, repository.GetOpenTimeClockEntryParams{
+			OrganizationID: organizationID(ctx),
+			DentistID:      targetID,
+		})
+		if err == nil {
+			return conflictError(fmt.Sprintf("both dentists are clocked in (clinics %s and %s); clock one of them out before merging", openSource.ClinicID, openTarget.ClinicID))
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+	if _, err := qtx.MoveDentistTimeClockEntries(ctx, repository.MoveDentistTimeClockEntriesParams{
+		OrganizationID:  organizationID(ctx),
+		TargetDentistID: targetID,
+		DentistID:       sourceID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	return nil
+}
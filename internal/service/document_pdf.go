@@ -0,0 +1,214 @@
+package service
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+)
+
+const (
+	pdfPageWidth    = 595.0
+	pdfPageHeight   = 842.0
+	pdfMargin       = 56.0
+	pdfTitleSize    = 14.0
+	pdfTitleLeading = 20.0
+	pdfBodySize     = 11.0
+	pdfBodyLeading  = 15.0
+)
+
+// helveticaWidths are the standard Helvetica advance widths for ASCII 32-126, in thousandths of the font size.
+var helveticaWidths = [95]int{
+	278, 278, 355, 556, 556, 889, 667, 191, 333, 333, 389, 584, 278, 333, 278, 278,
+	556, 556, 556, 556, 556, 556, 556, 556, 556, 556, 278, 278, 584, 584, 584, 556,
+	1015, 667, 667, 722, 722, 667, 611, 778, 722, 278, 500, 667, 556, 833, 722, 778,
+	667, 778, 722, 667, 611, 722, 667, 944, 667, 667, 611, 278, 278, 278, 469, 556,
+	333, 556, 556, 500, 556, 556, 278, 556, 556, 222, 222, 500, 222, 833, 556, 556,
+	556, 556, 333, 500, 278, 556, 500, 722, 500, 500, 500, 334, 260, 334, 584,
+}
+
+type pdfLine struct {
+	text []byte
+	bold bool
+}
+
+// renderDocumentPDF lays the title and body out on A4 pages with the base Helvetica fonts, so no font is embedded.
+// Text goes out in WinAnsi; characters outside it print as "?".
+func renderDocumentPDF(title string, body string, createdAt time.Time) ([]byte, error) {
+	encoder := encoding.ReplaceUnsupported(charmap.Windows1252.NewEncoder())
+	encode := func(text string) ([]byte, error) {
+		return encoder.Bytes([]byte(text))
+	}
+
+	width := pdfPageWidth - 2*pdfMargin
+	var lines []pdfLine
+	encodedTitle, err := encode(title)
+	if err != nil {
+		return nil, fmt.Errorf("encode document title: %w", err)
+	}
+	for _, line := range wrapPDFText(encodedTitle, pdfTitleSize*1.1, width) {
+		lines = append(lines, pdfLine{text: line, bold: true})
+	}
+	lines = append(lines, pdfLine{})
+	for paragraph := range strings.SplitSeq(strings.ReplaceAll(body, "\r\n", "\n"), "\n") {
+		encoded, err := encode(paragraph)
+		if err != nil {
+			return nil, fmt.Errorf("encode document body: %w", err)
+		}
+		for _, line := range wrapPDFText(encoded, pdfBodySize, width) {
+			lines = append(lines, pdfLine{text: line})
+		}
+	}
+
+	var pages [][]byte
+	var content bytes.Buffer
+	y := pdfPageHeight - pdfMargin
+	for _, line := range lines {
+		size, leading, font := pdfBodySize, pdfBodyLeading, "F1"
+		if line.bold {
+			size, leading, font = pdfTitleSize, pdfTitleLeading, "F2"
+		}
+		if y-leading < pdfMargin {
+			pages = append(pages, bytes.Clone(content.Bytes()))
+			content.Reset()
+			y = pdfPageHeight - pdfMargin
+		}
+		y -= leading
+		if len(line.text) == 0 {
+			continue
+		}
+		fmt.Fprintf(&content, "BT /%s %.0f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, pdfMargin, y, escapePDFString(line.text))
+	}
+	pages = append(pages, content.Bytes())
+
+	return writePDF(pages, escapePDFString(encodedTitle), createdAt)
+}
+
+func writePDF(pages [][]byte, title []byte, createdAt time.Time) ([]byte, error) {
+	// Objects 1-5 are fixed; each page then takes a page object and its content stream.
+	objects := [][]byte{
+		[]byte("<< /Type /Catalog /Pages 2 0 R >>"),
+		nil,
+		[]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>"),
+		[]byte("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>"),
+		fmt.Appendf(nil, "<< /Title (%s) /CreationDate (D:%s) >>", title, createdAt.UTC().Format("20060102150405Z")),
+	}
+	kids := make([]string, 0, len(pages))
+	for _, page := range pages {
+		var compressed bytes.Buffer
+		writer := zlib.NewWriter(&compressed)
+		if _, err := writer.Write(page); err != nil {
+			return nil, fmt.Errorf("compress page: %w", err)
+		}
+		if err := writer.Close(); err != nil {
+			return nil, fmt.Errorf("compress page: %w", err)
+		}
+		pageNumber := len(objects) + 1
+		kids = append(kids, fmt.Sprintf("%d 0 R", pageNumber))
+		objects = append(objects,
+			fmt.Appendf(nil, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>", pdfPageWidth, pdfPageHeight, pageNumber+1),
+			append(fmt.Appendf(nil, "<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len()), append(compressed.Bytes(), "\nendstream"...)...),
+		)
+	}
+	objects[1] = fmt.Appendf(nil, "<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(pages))
+
+	var output bytes.Buffer
+	output.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	offsets := make([]int, len(objects))
+	for i, object := range objects {
+		offsets[i] = output.Len()
+		fmt.Fprintf(&output, "%d 0 obj\n", i+1)
+		output.Write(object)
+		output.WriteString("\nendobj\n")
+	}
+	xref := output.Len()
+	fmt.Fprintf(&output, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&output, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&output, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return output.Bytes(), nil
+}
+
+// wrapPDFText breaks WinAnsi text on spaces to fit width points, splitting words that are wider than a whole line.
+// Bold text is measured with the regular widths scaled up, which is close enough for Helvetica-Bold.
+func wrapPDFText(text []byte, size float64, width float64) [][]byte {
+	if len(bytes.Trim(text, " ")) == 0 {
+		return [][]byte{nil}
+	}
+	limit := width * 1000 / size
+	var lines [][]byte
+	var line []byte
+	lineWidth := 0
+	// The text is WinAnsi rather than UTF-8, so words are split on plain spaces only.
+	for word := range bytes.SplitSeq(text, []byte{' '}) {
+		if len(word) == 0 {
+			continue
+		}
+		wordWidth := pdfTextWidth(word)
+		if len(line) > 0 && float64(lineWidth+helveticaWidths[0]+wordWidth) <= limit {
+			line = append(append(line, ' '), word...)
+			lineWidth += helveticaWidths[0] + wordWidth
+			continue
+		}
+		if len(line) > 0 {
+			lines = append(lines, line)
+			line, lineWidth = nil, 0
+		}
+		for float64(wordWidth) > limit {
+			cut, cutWidth := 0, 0
+			for cut < len(word) && float64(cutWidth+pdfCharWidth(word[cut])) <= limit {
+				cutWidth += pdfCharWidth(word[cut])
+				cut++
+			}
+			if cut == 0 {
+				cut, cutWidth = 1, pdfCharWidth(word[0])
+			}
+			lines = append(lines, word[:cut])
+			word = word[cut:]
+			wordWidth -= cutWidth
+		}
+		line, lineWidth = bytes.Clone(word), wordWidth
+	}
+	return append(lines, line)
+}
+
+func pdfTextWidth(text []byte) int {
+	total := 0
+	for _, char := range text {
+		total += pdfCharWidth(char)
+	}
+	return total
+}
+
+// Accented capitals (0xC0-0xDE) are about as wide as their base letters; other non-ASCII characters get a lowercase width.
+func pdfCharWidth(char byte) int {
+	switch {
+	case char >= 32 && char <= 126:
+		return helveticaWidths[char-32]
+	case char >= 0xC0 && char <= 0xDE:
+		return 722
+	default:
+		return 556
+	}
+}
+
+func escapePDFString(text []byte) []byte {
+	escaped := make([]byte, 0, len(text))
+	for _, char := range text {
+		switch char {
+		case '(', ')', '\\':
+			escaped = append(escaped, '\\', char)
+		default:
+			if char < 32 {
+				char = ' '
+			}
+			escaped = append(escaped, char)
+		}
+	}
+	return escaped
+}
@@ -0,0 +1,436 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	SignatureStatusPending  = "PENDING"
+	SignatureStatusSigned   = "SIGNED"
+	SignatureStatusDeclined = "DECLINED"
+	SignatureStatusExpired  = "EXPIRED"
+
+	AuditEntityGeneratedDocument = "GENERATED_DOCUMENT"
+
+	maxDocumentSigners             = 10
+	documentSignaturePendingUnique = "idx_document_signature_requests_pending_unique"
+
+	eventDocumentSigned            = "document.signed"
+	eventDocumentSignatureDeclined = "document.signature_declined"
+	eventDocumentSignatureExpired  = "document.signature_expired"
+)
+
+type SignatureSigner struct {
+	Name  string
+	Email string
+}
+
+type SignatureEnvelopeRequest struct {
+	DocumentID string
+	ClinicID   string
+	Title      string
+	FileName   string
+	Content    []byte
+	Signers    []SignatureSigner
+}
+
+type SignatureEnvelope struct {
+	Provider  string
+	Reference string
+}
+
+// Providers (Clicksign, DocuSign, ...) collect the signatures and report the outcome through the callback; the signed
+// file is fetched from them once every signer has signed.
+type SignatureProvider interface {
+	CreateEnvelope(ctx context.Context, request SignatureEnvelopeRequest) (SignatureEnvelope, error)
+	DownloadSignedDocument(ctx context.Context, reference string) ([]byte, error)
+}
+
+func WithSignatureProvider(provider SignatureProvider, callbackSecret string) Option {
+	return func(s *Service) {
+		s.signatureProvider = provider
+		s.signatureSecret = []byte(strings.TrimSpace(callbackSecret))
+	}
+}
+
+func (s *Service) RequestDocumentSignature(ctx context.Context, documentID string, input CreateDocumentSignatureRequestInput) (DocumentSignatureRequestOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RequestDocumentSignature")
+	defer span.End()
+
+	if s.signatureProvider == nil {
+		return DocumentSignatureRequestOutput{}, conflictError("electronic signature is not configured")
+	}
+	if s.attachments == nil {
+		return DocumentSignatureRequestOutput{}, errors.New("attachment storage is not configured")
+	}
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return DocumentSignatureRequestOutput{}, unauthorizedError("missing authenticated user")
+	}
+	signers, err := normalizeDocumentSigners(input.Signers)
+	if err != nil {
+		return DocumentSignatureRequestOutput{}, err
+	}
+
+	document, err := s.getGeneratedDocument(ctx, documentID)
+	if err != nil {
+		return DocumentSignatureRequestOutput{}, err
+	}
+	pending, err := s.queries.ExistsPendingDocumentSignatureRequest(ctx, repository.ExistsPendingDocumentSignatureRequestParams{
+		OrganizationID: organizationID(ctx),
+		DocumentID:     document.ID,
+	})
+	if err != nil {
+		return DocumentSignatureRequestOutput{}, err
+	}
+	if pending {
+		return DocumentSignatureRequestOutput{}, conflictError("document already has a pending signature request")
+	}
+	attachment, found, err := s.attachments.GetAttachment(ctx, document.AttachmentKey)
+	if err != nil {
+		return DocumentSignatureRequestOutput{}, fmt.Errorf("load generated document: %w", err)
+	}
+	if !found {
+		return DocumentSignatureRequestOutput{}, notFoundError("document file not found")
+	}
+
+	envelope, err := s.signatureProvider.CreateEnvelope(ctx, SignatureEnvelopeRequest{
+		DocumentID: document.ID,
+		ClinicID:   document.ClinicID,
+		Title:      document.Title,
+		FileName:   fmt.Sprintf("%s-%s.pdf", strings.ToLower(document.Kind), document.ID),
+		Content:    attachment.Data,
+		Signers:    signers,
+	})
+	if err != nil {
+		return DocumentSignatureRequestOutput{}, fmt.Errorf("create signature envelope: %w", err)
+	}
+	reference := strings.TrimSpace(envelope.Reference)
+	if reference == "" {
+		return DocumentSignatureRequestOutput{}, errors.New("create signature envelope: provider returned an empty reference")
+	}
+
+	signersJSON, err := json.Marshal(mapDocumentSigners(signers))
+	if err != nil {
+		return DocumentSignatureRequestOutput{}, fmt.Errorf("encode signers: %w", err)
+	}
+	requestID, err := newUUIDV7()
+	if err != nil {
+		return DocumentSignatureRequestOutput{}, err
+	}
+	request, err := s.queries.CreateDocumentSignatureRequest(ctx, repository.CreateDocumentSignatureRequestParams{
+		ID:              requestID,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        document.ClinicID,
+		DocumentID:      document.ID,
+		Provider:        envelope.Provider,
+		Reference:       reference,
+		Signers:         signersJSON,
+		CreatedByUserID: principal.UserID,
+	})
+	if err != nil {
+		if isConstraintViolation(err, documentSignaturePendingUnique) {
+			return DocumentSignatureRequestOutput{}, conflictError("document already has a pending signature request")
+		}
+		return DocumentSignatureRequestOutput{}, mapDatabaseError(err)
+	}
+	// Signer emails stay out of the audit trail; the request row already holds them.
+	if err := recordAudit(ctx, s.queries, auditEntry{
+		ClinicID:   document.ClinicID,
+		Action:     "document.signature_requested",
+		EntityType: AuditEntityGeneratedDocument,
+		EntityID:   document.ID,
+		Metadata: map[string]any{
+			"signature_request_id": request.ID,
+			"provider":             request.Provider,
+			"signers":              len(signers),
+		},
+	}); err != nil {
+		return DocumentSignatureRequestOutput{}, err
+	}
+	return mapDocumentSignatureRequest(request)
+}
+
+func (s *Service) ListDocumentSignatureRequests(ctx context.Context, documentID string) ([]DocumentSignatureRequestOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListDocumentSignatureRequests")
+	defer span.End()
+
+	if _, err := s.getGeneratedDocument(ctx, documentID); err != nil {
+		return nil, err
+	}
+	requests, err := s.queries.ListDocumentSignatureRequests(ctx, repository.ListDocumentSignatureRequestsParams{
+		OrganizationID: organizationID(ctx),
+		DocumentID:     documentID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	output := make([]DocumentSignatureRequestOutput, 0, len(requests))
+	for _, request := range requests {
+		mapped, err := mapDocumentSignatureRequest(request)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, mapped)
+	}
+	return output, nil
+}
+
+func (s *Service) GetSignedDocumentFile(ctx context.Context, documentID string, requestID string) (GeneratedDocumentFileOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetSignedDocumentFile")
+	defer span.End()
+
+	request, err := s.queries.GetDocumentSignatureRequest(ctx, repository.GetDocumentSignatureRequestParams{
+		OrganizationID: organizationID(ctx),
+		DocumentID:     documentID,
+		ID:             requestID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return GeneratedDocumentFileOutput{}, notFoundError("signature request not found")
+		}
+		return GeneratedDocumentFileOutput{}, err
+	}
+	if !request.SignedAttachmentKey.Valid || s.attachments == nil {
+		return GeneratedDocumentFileOutput{}, notFoundError("signed document not found")
+	}
+	attachment, found, err := s.attachments.GetAttachment(ctx, request.SignedAttachmentKey.String)
+	if err != nil {
+		return GeneratedDocumentFileOutput{}, fmt.Errorf("load signed document: %w", err)
+	}
+	if !found {
+		return GeneratedDocumentFileOutput{}, notFoundError("signed document not found")
+	}
+	return GeneratedDocumentFileOutput{
+		FileName:    fmt.Sprintf("signed-%s.pdf", request.DocumentID),
+		ContentType: documentContentType,
+		Content:     attachment.Data,
+	}, nil
+}
+
+func (s *Service) VerifySignatureCallbackSignature(body []byte, signature string) error {
+	if len(s.signatureSecret) == 0 {
+		return unauthorizedError("electronic signature callbacks are not configured")
+	}
+	return verifyCallbackSignature(s.signatureSecret, body, signature)
+}
+
+func (s *Service) CompleteDocumentSignature(ctx context.Context, input DocumentSignatureCallbackInput) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CompleteDocumentSignature")
+	defer span.End()
+
+	reference, status, err := normalizeSignatureCallback(input)
+	if err != nil {
+		return err
+	}
+
+	request, err := findInTenantSchemas(ctx, s, func(ctx context.Context) (repository.DocumentSignatureRequest, error) {
+		return s.queries.GetDocumentSignatureRequestByReference(ctx, reference)
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("signature request not found")
+		}
+		return err
+	}
+	ctx = WithOrganization(ctx, request.OrganizationID)
+	if request.Status != SignatureStatusPending {
+		return nil
+	}
+
+	var updated int64
+	metadata := map[string]any{"signature_request_id": request.ID, "reference": reference}
+	eventType := eventDocumentSigned
+	switch status {
+	case SignatureStatusSigned:
+		updated, err = s.storeSignedDocument(ctx, request)
+		if err != nil {
+			return err
+		}
+	default:
+		eventType = eventDocumentSignatureDeclined
+		if status == SignatureStatusExpired {
+			eventType = eventDocumentSignatureExpired
+		}
+		if reason := strings.TrimSpace(derefString(input.Reason)); reason != "" {
+			metadata["reason"] = reason
+		}
+		updated, err = s.queries.CloseDocumentSignatureRequest(ctx, repository.CloseDocumentSignatureRequestParams{
+			OrganizationID: organizationID(ctx),
+			ID:             request.ID,
+			Status:         status,
+			FailureReason:  optionalString(input.Reason),
+		})
+		if err != nil {
+			return mapDatabaseError(err)
+		}
+	}
+	if updated == 0 {
+		return nil
+	}
+	if err := recordAudit(ctx, s.queries, auditEntry{
+		ClinicID:   request.ClinicID,
+		Action:     eventType,
+		EntityType: AuditEntityGeneratedDocument,
+		EntityID:   request.DocumentID,
+		Metadata:   metadata,
+	}); err != nil {
+		return err
+	}
+	s.publishDocumentSignature(ctx, eventType, request)
+	return nil
+}
+
+// storeSignedDocument keeps the provider's signed copy next to the generated document. Quota and residency failures are
+// returned so the provider retries the callback once they are resolved.
+func (s *Service) storeSignedDocument(ctx context.Context, request repository.DocumentSignatureRequest) (int64, error) {
+	if s.signatureProvider == nil {
+		return 0, conflictError("electronic signature is not configured")
+	}
+	if s.attachments == nil {
+		return 0, errors.New("attachment storage is not configured")
+	}
+	content, err := s.signatureProvider.DownloadSignedDocument(ctx, request.Reference)
+	if err != nil {
+		return 0, fmt.Errorf("download signed document: %w", err)
+	}
+	if http.DetectContentType(content) != documentContentType {
+		return 0, errors.New("download signed document: provider did not return a PDF")
+	}
+	if err := s.ensureStorageQuota(ctx, 0, int64(len(content))); err != nil {
+		return 0, err
+	}
+	if err := s.checkDataResidency(ctx, DestinationAttachments); err != nil {
+		return 0, err
+	}
+
+	key := fmt.Sprintf("documents/%s/%s-signed-%s.pdf", request.ClinicID, request.DocumentID, request.ID)
+	if err := s.attachments.PutAttachment(ctx, key, documentContentType, content); err != nil {
+		return 0, fmt.Errorf("store signed document: %w", err)
+	}
+	checksum := sha256.Sum256(content)
+	updated, err := s.queries.MarkDocumentSignatureSigned(ctx, repository.MarkDocumentSignatureSignedParams{
+		OrganizationID:       organizationID(ctx),
+		ID:                   request.ID,
+		SignedAttachmentKey:  sql.NullString{String: key, Valid: true},
+		SignedSizeBytes:      int64(len(content)),
+		SignedChecksumSha256: sql.NullString{String: hex.EncodeToString(checksum[:]), Valid: true},
+	})
+	if err != nil || updated == 0 {
+		// A concurrent callback settled the request first, so this copy is not referenced anywhere.
+		if deleteErr := s.attachments.DeleteAttachment(ctx, key); deleteErr != nil {
+			slog.WarnContext(ctx, "delete unrecorded signed document", "key", key, "error", deleteErr)
+		}
+	}
+	if err != nil {
+		return 0, mapDatabaseError(err)
+	}
+	return updated, nil
+}
+
+func (s *Service) publishDocumentSignature(ctx context.Context, eventType string, request repository.DocumentSignatureRequest) {
+	if !s.webhooksEnabled(ctx) {
+		return
+	}
+	event, err := s.newEvent(eventType, map[string]string{
+		"clinic_id":            request.ClinicID,
+		"document_id":          request.DocumentID,
+		"signature_request_id": request.ID,
+	})
+	if err == nil {
+		err = s.events.Publish(ctx, event)
+	}
+	if err != nil {
+		slog.WarnContext(ctx, "publish document signature event failed", "signature_request_id", request.ID, "error", err)
+	}
+}
+
+func normalizeDocumentSigners(input []DocumentSignerInput) ([]SignatureSigner, error) {
+	if len(input) == 0 {
+		return nil, validationError("signers must have at least one entry")
+	}
+	if len(input) > maxDocumentSigners {
+		return nil, validationError(fmt.Sprintf("signers must have at most %d entries", maxDocumentSigners))
+	}
+	signers := make([]SignatureSigner, 0, len(input))
+	seen := make(map[string]struct{}, len(input))
+	for i, signer := range input {
+		name := strings.TrimSpace(signer.Name)
+		email := strings.ToLower(strings.TrimSpace(signer.Email))
+		if name == "" {
+			return nil, validationError(fmt.Sprintf("signers[%d].name is required", i))
+		}
+		if !validation.ValidateEmail(email) {
+			return nil, validationError(fmt.Sprintf("signers[%d].email is invalid", i))
+		}
+		if _, ok := seen[email]; ok {
+			return nil, validationError(fmt.Sprintf("signers[%d].email is duplicated", i))
+		}
+		seen[email] = struct{}{}
+		signers = append(signers, SignatureSigner{Name: name, Email: email})
+	}
+	return signers, nil
+}
+
+func normalizeSignatureCallback(input DocumentSignatureCallbackInput) (string, string, error) {
+	reference := strings.TrimSpace(input.Reference)
+	if reference == "" {
+		return "", "", validationError("reference is required")
+	}
+	status := strings.ToUpper(strings.TrimSpace(input.Status))
+	switch status {
+	case SignatureStatusSigned, SignatureStatusDeclined, SignatureStatusExpired:
+		return reference, status, nil
+	default:
+		return "", "", validationError(fmt.Sprintf("status must be one of: %s, %s, %s", SignatureStatusSigned, SignatureStatusDeclined, SignatureStatusExpired))
+	}
+}
+
+func mapDocumentSigners(signers []SignatureSigner) []DocumentSignerOutput {
+	output := make([]DocumentSignerOutput, 0, len(signers))
+	for _, signer := range signers {
+		output = append(output, DocumentSignerOutput{Name: signer.Name, Email: signer.Email})
+	}
+	return output
+}
+
+func mapDocumentSignatureRequest(request repository.DocumentSignatureRequest) (DocumentSignatureRequestOutput, error) {
+	var signers []DocumentSignerOutput
+	if err := json.Unmarshal(request.Signers, &signers); err != nil {
+		return DocumentSignatureRequestOutput{}, fmt.Errorf("decode signers: %w", err)
+	}
+	output := DocumentSignatureRequestOutput{
+		ID:              request.ID,
+		DocumentID:      request.DocumentID,
+		ClinicID:        request.ClinicID,
+		Provider:        request.Provider,
+		Status:          request.Status,
+		Signers:         signers,
+		FailureReason:   nullToPointer(request.FailureReason),
+		HasSignedFile:   request.SignedAttachmentKey.Valid,
+		CreatedByUserID: request.CreatedByUserID,
+		CompletedAt:     nullTimeToPointer(request.CompletedAt),
+		CreatedAt:       request.CreatedAt,
+		UpdatedAt:       request.UpdatedAt,
+	}
+	if request.SignedAttachmentKey.Valid {
+		size := request.SignedSizeBytes
+		output.SignedSizeBytes = &size
+		output.SignedChecksumSHA256 = nullToPointer(request.SignedChecksumSha256)
+	}
+	return output, nil
+}
@@ -0,0 +1,622 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	DocumentKindContract = "CONTRACT"
+	DocumentKindBudget   = "BUDGET"
+	DocumentKindOther    = "OTHER"
+
+	documentContentType               = "application/pdf"
+	documentTemplateNameUnique        = "idx_document_templates_name_active_unique"
+	maxDocumentItemQuantity           = 1000
+	documentPlaceholderGroupDentist   = "dentist."
+	documentPlaceholderGroupPatient   = "patient."
+	documentPlaceholderGroupTreatment = "treatment_plan."
+)
+
+var documentKinds = []string{DocumentKindContract, DocumentKindBudget, DocumentKindOther}
+
+// documentPlaceholders are the names a template may use as {{name}}.
+var documentPlaceholders = []string{
+	"clinic.legal_name",
+	"clinic.trade_name",
+	"clinic.tax_id_number",
+	"clinic.email",
+	"clinic.phone",
+	"clinic.address",
+	"dentist.name",
+	"dentist.tax_id_number",
+	"dentist.cro",
+	"organization.name",
+	"patient.name",
+	"patient.tax_id_number",
+	"treatment_plan.items",
+	"treatment_plan.total",
+	"document.date",
+}
+
+var documentPlaceholderPattern = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.]+)\s*\}\}`)
+
+func (s *Service) CreateDocumentTemplate(ctx context.Context, input CreateDocumentTemplateInput) (DocumentTemplateOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateDocumentTemplate")
+	defer span.End()
+
+	params := repository.CreateDocumentTemplateParams{
+		OrganizationID: organizationID(ctx),
+		Name:           strings.TrimSpace(input.Name),
+		Kind:           strings.ToUpper(strings.TrimSpace(input.Kind)),
+		Title:          strings.TrimSpace(input.Title),
+		Body:           input.Body,
+	}
+	if err := validateDocumentTemplate(params.Name, params.Kind, params.Title, params.Body); err != nil {
+		return DocumentTemplateOutput{}, err
+	}
+	templateID, err := newUUIDV7()
+	if err != nil {
+		return DocumentTemplateOutput{}, err
+	}
+	params.ID = templateID
+
+	template, err := s.queries.CreateDocumentTemplate(ctx, params)
+	if err != nil {
+		return DocumentTemplateOutput{}, mapDocumentTemplateDatabaseError(err)
+	}
+	return mapDocumentTemplate(template), nil
+}
+
+func (s *Service) ListDocumentTemplates(ctx context.Context, kind *string) ([]DocumentTemplateOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListDocumentTemplates")
+	defer span.End()
+
+	params := repository.ListDocumentTemplatesParams{OrganizationID: organizationID(ctx)}
+	if kind != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*kind))
+		if !slices.Contains(documentKinds, normalized) {
+			return nil, validationError("kind must be one of CONTRACT, BUDGET, OTHER")
+		}
+		params.Kind = sql.NullString{String: normalized, Valid: true}
+	}
+	templates, err := s.queries.ListDocumentTemplates(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]DocumentTemplateOutput, 0, len(templates))
+	for _, template := range templates {
+		output = append(output, mapDocumentTemplate(template))
+	}
+	return output, nil
+}
+
+func (s *Service) GetDocumentTemplate(ctx context.Context, templateID string) (DocumentTemplateOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetDocumentTemplate")
+	defer span.End()
+
+	template, err := s.getDocumentTemplate(ctx, templateID)
+	if err != nil {
+		return DocumentTemplateOutput{}, err
+	}
+	return mapDocumentTemplate(template), nil
+}
+
+func (s *Service) UpdateDocumentTemplate(ctx context.Context, templateID string, input UpdateDocumentTemplateInput) (DocumentTemplateOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateDocumentTemplate")
+	defer span.End()
+
+	if input.Name == nil && input.Kind == nil && input.Title == nil && input.Body == nil {
+		return DocumentTemplateOutput{}, validationError("at least one field must be provided")
+	}
+	current, err := s.getDocumentTemplate(ctx, templateID)
+	if err != nil {
+		return DocumentTemplateOutput{}, err
+	}
+	params := repository.UpdateDocumentTemplateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             templateID,
+		Name:           current.Name,
+		Kind:           current.Kind,
+		Title:          current.Title,
+		Body:           current.Body,
+	}
+	if input.Name != nil {
+		params.Name = strings.TrimSpace(*input.Name)
+	}
+	if input.Kind != nil {
+		params.Kind = strings.ToUpper(strings.TrimSpace(*input.Kind))
+	}
+	if input.Title != nil {
+		params.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Body != nil {
+		params.Body = *input.Body
+	}
+	if err := validateDocumentTemplate(params.Name, params.Kind, params.Title, params.Body); err != nil {
+		return DocumentTemplateOutput{}, err
+	}
+
+	template, err := s.queries.UpdateDocumentTemplate(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DocumentTemplateOutput{}, notFoundError("document template not found")
+		}
+		return DocumentTemplateOutput{}, mapDocumentTemplateDatabaseError(err)
+	}
+	return mapDocumentTemplate(template), nil
+}
+
+// Deleted templates stay in the database so the documents generated from them keep their reference.
+func (s *Service) DeleteDocumentTemplate(ctx context.Context, templateID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteDocumentTemplate")
+	defer span.End()
+
+	rows, err := s.queries.DeleteDocumentTemplate(ctx, repository.DeleteDocumentTemplateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             templateID,
+	})
+	if err != nil {
+		return err
+	}
+	if rows == 0 {
+		return notFoundError("document template not found")
+	}
+	return nil
+}
+
+// GenerateDocument fills a template with the clinic, the optional dentist and the patient and treatment plan data sent
+// in the request, renders it to PDF and stores the file in attachment storage next to a metadata row.
+func (s *Service) GenerateDocument(ctx context.Context, input GenerateDocumentInput) (GeneratedDocumentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GenerateDocument")
+	defer span.End()
+
+	if s.attachments == nil {
+		return GeneratedDocumentOutput{}, errors.New("attachment storage is not configured")
+	}
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return GeneratedDocumentOutput{}, unauthorizedError("missing authenticated user")
+	}
+	references := []struct {
+		field string
+		value *string
+	}{
+		{"template_id", &input.TemplateID},
+		{"clinic_id", &input.ClinicID},
+		{"dentist_id", input.DentistID},
+	}
+	for _, reference := range references {
+		if reference.value == nil {
+			continue
+		}
+		parsed, err := uuid.Parse(strings.TrimSpace(*reference.value))
+		if err != nil {
+			return GeneratedDocumentOutput{}, validationError(fmt.Sprintf("%s must be a valid UUID", reference.field))
+		}
+		*reference.value = parsed.String()
+	}
+
+	template, err := s.getDocumentTemplate(ctx, input.TemplateID)
+	if err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	used := templatePlaceholders(template.Title + "\n" + template.Body)
+	if err := checkDocumentInputCoverage(used, input); err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+
+	clinic, err := loadClinicDetails(ctx, s.queries, input.ClinicID)
+	if err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	organization, err := s.queries.GetOrganizationByID(ctx, organizationID(ctx))
+	if err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	values := map[string]string{
+		"clinic.legal_name":    clinic.LegalName,
+		"clinic.trade_name":    clinic.LegalName,
+		"clinic.tax_id_number": clinic.TaxIDNumber,
+		"clinic.address":       formatDocumentAddress(clinic.Address),
+		"organization.name":    organization.Name,
+		"document.date":        s.todayIn(clinicLocation(ctx, clinic.Timezone)).Format("02/01/2006"),
+	}
+	if clinic.TradeName != nil {
+		values["clinic.trade_name"] = *clinic.TradeName
+	}
+	if clinic.Email != nil {
+		values["clinic.email"] = *clinic.Email
+	}
+	if clinic.PhoneDisplay != nil {
+		values["clinic.phone"] = *clinic.PhoneDisplay
+	} else if clinic.Phone != nil {
+		values["clinic.phone"] = *clinic.Phone
+	}
+
+	var dentistID uuid.NullUUID
+	if input.DentistID != nil {
+		if _, err := s.queries.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       input.ClinicID,
+			DentistID:      *input.DentistID,
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return GeneratedDocumentOutput{}, validationError("dentist_id is not linked to this clinic")
+			}
+			return GeneratedDocumentOutput{}, err
+		}
+		dentist, err := s.queries.GetDentistDetailsByID(ctx, repository.GetDentistDetailsByIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             *input.DentistID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return GeneratedDocumentOutput{}, validationError("dentist_id is not linked to this clinic")
+			}
+			return GeneratedDocumentOutput{}, err
+		}
+		values["dentist.name"] = dentist.LegalName
+		values["dentist.tax_id_number"] = dentist.TaxIDNumber
+		if dentist.CroNumber.Valid && dentist.CroState.Valid {
+			values["dentist.cro"] = fmt.Sprintf("CRO-%s %s", dentist.CroState.String, dentist.CroNumber.String)
+		}
+		dentistID = uuid.NullUUID{UUID: uuid.MustParse(dentist.DentistID), Valid: true}
+	}
+	patientValues, err := documentPatientValues(input.Patient)
+	if err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	itemValues, err := documentItemValues(input.Items)
+	if err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	for key, value := range patientValues {
+		values[key] = value
+	}
+	for key, value := range itemValues {
+		values[key] = value
+	}
+
+	title := strings.TrimSpace(fillDocumentTemplate(template.Title, values))
+	if title == "" {
+		title = template.Name
+	}
+	now := s.now()
+	content, err := renderDocumentPDF(title, fillDocumentTemplate(template.Body, values), now)
+	if err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	if err := s.ensureStorageQuota(ctx, 0, int64(len(content))); err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	if err := s.checkDataResidency(ctx, DestinationAttachments); err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+
+	documentID, err := newUUIDV7()
+	if err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	key := fmt.Sprintf("documents/%s/%s.pdf", input.ClinicID, documentID)
+	if err := s.attachments.PutAttachment(ctx, key, documentContentType, content); err != nil {
+		return GeneratedDocumentOutput{}, fmt.Errorf("store generated document: %w", err)
+	}
+	checksum := sha256.Sum256(content)
+	document, err := s.queries.CreateGeneratedDocument(ctx, repository.CreateGeneratedDocumentParams{
+		ID:              documentID,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        input.ClinicID,
+		TemplateID:      template.ID,
+		DentistID:       dentistID,
+		Kind:            template.Kind,
+		Title:           title,
+		AttachmentKey:   key,
+		ContentType:     documentContentType,
+		SizeBytes:       int64(len(content)),
+		ChecksumSha256:  hex.EncodeToString(checksum[:]),
+		Placeholders:    used,
+		CreatedByUserID: principal.UserID,
+	})
+	if err != nil {
+		if deleteErr := s.attachments.DeleteAttachment(ctx, key); deleteErr != nil {
+			slog.WarnContext(ctx, "delete unrecorded generated document", "key", key, "error", deleteErr)
+		}
+		return GeneratedDocumentOutput{}, mapDatabaseError(err)
+	}
+	return mapGeneratedDocument(document), nil
+}
+
+func (s *Service) GetGeneratedDocument(ctx context.Context, documentID string) (GeneratedDocumentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetGeneratedDocument")
+	defer span.End()
+
+	document, err := s.getGeneratedDocument(ctx, documentID)
+	if err != nil {
+		return GeneratedDocumentOutput{}, err
+	}
+	return mapGeneratedDocument(document), nil
+}
+
+func (s *Service) GetGeneratedDocumentFile(ctx context.Context, documentID string) (GeneratedDocumentFileOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetGeneratedDocumentFile")
+	defer span.End()
+
+	document, err := s.getGeneratedDocument(ctx, documentID)
+	if err != nil {
+		return GeneratedDocumentFileOutput{}, err
+	}
+	if s.attachments == nil {
+		return GeneratedDocumentFileOutput{}, notFoundError("document file not found")
+	}
+	attachment, found, err := s.attachments.GetAttachment(ctx, document.AttachmentKey)
+	if err != nil {
+		return GeneratedDocumentFileOutput{}, fmt.Errorf("load generated document: %w", err)
+	}
+	if !found {
+		return GeneratedDocumentFileOutput{}, notFoundError("document file not found")
+	}
+	return GeneratedDocumentFileOutput{
+		FileName:    fmt.Sprintf("%s-%s.pdf", strings.ToLower(document.Kind), document.ID),
+		ContentType: document.ContentType,
+		Content:     attachment.Data,
+	}, nil
+}
+
+func (s *Service) ListClinicGeneratedDocuments(ctx context.Context, clinicID string, limit int, cursor *string, templateID *string) ([]GeneratedDocumentOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicGeneratedDocuments")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, notFoundError("clinic not found")
+		}
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListClinicGeneratedDocumentsCursorParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	if templateID != nil {
+		parsed, err := uuid.Parse(*templateID)
+		if err != nil {
+			return nil, nil, validationError("template_id must be a valid UUID")
+		}
+		params.TemplateID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+
+	rows, err := s.queries.ListClinicGeneratedDocumentsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	documents := make([]GeneratedDocumentOutput, 0, len(rows))
+	for _, row := range rows {
+		documents = append(documents, mapGeneratedDocument(row))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return documents, nextCursor, nil
+}
+
+func (s *Service) getDocumentTemplate(ctx context.Context, templateID string) (repository.DocumentTemplate, error) {
+	template, err := s.queries.GetDocumentTemplate(ctx, repository.GetDocumentTemplateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             templateID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.DocumentTemplate{}, notFoundError("document template not found")
+		}
+		return repository.DocumentTemplate{}, err
+	}
+	return template, nil
+}
+
+func (s *Service) getGeneratedDocument(ctx context.Context, documentID string) (repository.GeneratedDocument, error) {
+	document, err := s.queries.GetGeneratedDocument(ctx, repository.GetGeneratedDocumentParams{
+		OrganizationID: organizationID(ctx),
+		ID:             documentID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.GeneratedDocument{}, notFoundError("document not found")
+		}
+		return repository.GeneratedDocument{}, err
+	}
+	return document, nil
+}
+
+func validateDocumentTemplate(name string, kind string, title string, body string) error {
+	if name == "" {
+		return validationError("name is required")
+	}
+	if !slices.Contains(documentKinds, kind) {
+		return validationError("kind must be one of CONTRACT, BUDGET, OTHER")
+	}
+	if title == "" {
+		return validationError("title is required")
+	}
+	if strings.TrimSpace(body) == "" {
+		return validationError("body is required")
+	}
+	for _, name := range templatePlaceholders(title + "\n" + body) {
+		if !slices.Contains(documentPlaceholders, name) {
+			return validationError(fmt.Sprintf("unknown placeholder {{%s}}", name))
+		}
+	}
+	return nil
+}
+
+// templatePlaceholders returns the placeholder names a template uses, sorted and without repeats.
+func templatePlaceholders(text string) []string {
+	names := []string{}
+	for _, match := range documentPlaceholderPattern.FindAllStringSubmatch(text, -1) {
+		names = append(names, match[1])
+	}
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+func fillDocumentTemplate(text string, values map[string]string) string {
+	return documentPlaceholderPattern.ReplaceAllStringFunc(text, func(match string) string {
+		name := documentPlaceholderPattern.FindStringSubmatch(match)[1]
+		return values[name]
+	})
+}
+
+// checkDocumentInputCoverage rejects a request that leaves dentist, patient or treatment plan placeholders without data,
+// which would otherwise print as blanks in a contract.
+func checkDocumentInputCoverage(placeholders []string, input GenerateDocumentInput) error {
+	for _, name := range placeholders {
+		switch {
+		case strings.HasPrefix(name, documentPlaceholderGroupDentist) && input.DentistID == nil:
+			return validationError("dentist_id is required by this template")
+		case strings.HasPrefix(name, documentPlaceholderGroupPatient) && input.Patient == nil:
+			return validationError("patient is required by this template")
+		case strings.HasPrefix(name, documentPlaceholderGroupTreatment) && len(input.Items) == 0:
+			return validationError("items are required by this template")
+		}
+	}
+	return nil
+}
+
+func documentPatientValues(patient *DocumentPatientInput) (map[string]string, error) {
+	values := map[string]string{}
+	if patient == nil {
+		return values, nil
+	}
+	name := strings.TrimSpace(patient.Name)
+	if name == "" {
+		return nil, validationError("patient.name is required")
+	}
+	values["patient.name"] = name
+	if patient.TaxIDNumber != nil {
+		taxID := validation.NormalizeCPF(*patient.TaxIDNumber)
+		if len(taxID) != 11 || !validation.ValidateCPF(taxID) {
+			return nil, validationError("patient.tax_id_number must be a valid CPF")
+		}
+		values["patient.tax_id_number"] = fmt.Sprintf("%s.%s.%s-%s", taxID[:3], taxID[3:6], taxID[6:9], taxID[9:])
+	}
+	return values, nil
+}
+
+// documentItemValues lists each item on its own line, as in "1. Limpeza - 2 x R$ 150,00 = R$ 300,00", plus the total.
+func documentItemValues(items []DocumentItemInput) (map[string]string, error) {
+	values := map[string]string{}
+	if len(items) == 0 {
+		return values, nil
+	}
+	lines := make([]string, 0, len(items))
+	var total int64
+	for i, item := range items {
+		description := strings.TrimSpace(item.Description)
+		if description == "" {
+			return nil, validationError(fmt.Sprintf("items[%d].description is required", i))
+		}
+		quantity := 1
+		if item.Quantity != nil {
+			quantity = *item.Quantity
+		}
+		if quantity < 1 || quantity > maxDocumentItemQuantity {
+			return nil, validationError(fmt.Sprintf("items[%d].quantity must be between 1 and %d", i, maxDocumentItemQuantity))
+		}
+		if item.UnitPriceCents < 0 {
+			return nil, validationError(fmt.Sprintf("items[%d].unit_price_cents must be zero or greater", i))
+		}
+		amount := item.UnitPriceCents * int64(quantity)
+		total += amount
+		lines = append(lines, fmt.Sprintf("%d. %s - %d x %s = %s", i+1, description, quantity, formatBRL(item.UnitPriceCents), formatBRL(amount)))
+	}
+	values["treatment_plan.items"] = strings.Join(lines, "\n")
+	values["treatment_plan.total"] = formatBRL(total)
+	return values, nil
+}
+
+func formatDocumentAddress(address *AddressOutput) string {
+	if address == nil {
+		return ""
+	}
+	street := address.Street
+	if address.Number != nil {
+		street += ", " + *address.Number
+	}
+	if address.Complement != nil {
+		street += ", " + *address.Complement
+	}
+	return fmt.Sprintf("%s - %s/%s - CEP %s", street, address.City, address.State, validation.FormatCEP(address.CEP))
+}
+
+func mapDocumentTemplateDatabaseError(err error) error {
+	if isConstraintViolation(err, documentTemplateNameUnique) {
+		return conflictError("a document template with this name already exists")
+	}
+	return mapDatabaseError(err)
+}
+
+func mapDocumentTemplate(template repository.DocumentTemplate) DocumentTemplateOutput {
+	return DocumentTemplateOutput{
+		ID:           template.ID,
+		Name:         template.Name,
+		Kind:         template.Kind,
+		Title:        template.Title,
+		Body:         template.Body,
+		Placeholders: templatePlaceholders(template.Title + "\n" + template.Body),
+		CreatedAt:    template.CreatedAt,
+		UpdatedAt:    template.UpdatedAt,
+	}
+}
+
+func mapGeneratedDocument(document repository.GeneratedDocument) GeneratedDocumentOutput {
+	placeholders := document.Placeholders
+	if placeholders == nil {
+		placeholders = []string{}
+	}
+	return GeneratedDocumentOutput{
+		ID:              document.ID,
+		ClinicID:        document.ClinicID,
+		TemplateID:      document.TemplateID,
+		DentistID:       nullUUIDToPointer(document.DentistID),
+		Kind:            document.Kind,
+		Title:           document.Title,
+		ContentType:     document.ContentType,
+		SizeBytes:       document.SizeBytes,
+		ChecksumSHA256:  document.ChecksumSha256,
+		Placeholders:    placeholders,
+		CreatedByUserID: document.CreatedByUserID,
+		CreatedAt:       document.CreatedAt,
+	}
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+func (s *Service) ListClinicDentistHistory(ctx context.Context, clinicID string, dentistID string) ([]EmploymentPeriodOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicDentistHistory")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+
+	rows, err := s.queries.ListClinicDentistHistory(ctx, repository.ListClinicDentistHistoryParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, notFoundError("clinic dentist link not found")
+	}
+
+	now := time.Now().UTC()
+	output := make([]EmploymentPeriodOutput, 0, len(rows))
+	for _, row := range rows {
+		output = append(output, mapEmploymentPeriod(row.ClinicID, row.DentistID, row.IsAdmin, row.IsLegalRepresentative, row.StartedAt, row.EndedAt, now))
+	}
+	return output, nil
+}
+
+func (s *Service) ListDentistEmploymentHistory(ctx context.Context, dentistID string) ([]EmploymentPeriodOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListDentistEmploymentHistory")
+	defer span.End()
+
+	if _, err := s.queries.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("dentist not found")
+		}
+		return nil, err
+	}
+
+	rows, err := s.queries.ListDentistEmploymentHistory(ctx, repository.ListDentistEmploymentHistoryParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	output := make([]EmploymentPeriodOutput, 0, len(rows))
+	for _, row := range rows {
+		period := mapEmploymentPeriod(row.ClinicID, row.DentistID, row.IsAdmin, row.IsLegalRepresentative, row.StartedAt, row.EndedAt, now)
+		period.ClinicLegalName = &row.ClinicLegalName
+		period.ClinicTradeName = nullToPointer(row.ClinicTradeName)
+		output = append(output, period)
+	}
+	return output, nil
+}
+
+func (s *Service) ClinicDentistTenure(ctx context.Context, clinicID string, dentistID string) (ClinicDentistTenureOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ClinicDentistTenure")
+	defer span.End()
+
+	periods, err := s.ListClinicDentistHistory(ctx, clinicID, dentistID)
+	if err != nil {
+		return ClinicDentistTenureOutput{}, err
+	}
+	return summarizeTenure(clinicID, dentistID, periods, time.Now().UTC()), nil
+}
+
+func (s *Service) loadPreviousPeriods(ctx context.Context, clinicID string, dentistID string, currentStartedAt time.Time) ([]EmploymentPeriodOutput, *int, error) {
+	rows, err := s.queries.ListClinicDentistHistory(ctx, repository.ListClinicDentistHistoryParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now().UTC()
+	periods := make([]EmploymentPeriodOutput, 0, len(rows))
+	var previous []EmploymentPeriodOutput
+	for _, row := range rows {
+		period := mapEmploymentPeriod(row.ClinicID, row.DentistID, row.IsAdmin, row.IsLegalRepresentative, row.StartedAt, row.EndedAt, now)
+		periods = append(periods, period)
+		if !row.StartedAt.Equal(currentStartedAt) {
+			previous = append(previous, period)
+		}
+	}
+	if len(previous) == 0 {
+		return nil, nil, nil
+	}
+
+	totalDays := summarizeTenure(clinicID, dentistID, periods, now).TotalDays
+	return previous, &totalDays, nil
+}
+
+func summarizeTenure(clinicID string, dentistID string, periods []EmploymentPeriodOutput, now time.Time) ClinicDentistTenureOutput {
+	output := ClinicDentistTenureOutput{
+		ClinicID:  clinicID,
+		DentistID: dentistID,
+		Periods:   len(periods),
+	}
+	var total time.Duration
+	for idx := range periods {
+		period := periods[idx]
+		end := now
+		if period.EndedAt != nil {
+			end = *period.EndedAt
+		} else {
+			output.Active = true
+		}
+		total += max(end.Sub(period.StartedAt), 0)
+		if output.FirstStartedAt == nil || period.StartedAt.Before(*output.FirstStartedAt) {
+			output.FirstStartedAt = &periods[idx].StartedAt
+		}
+	}
+	output.TotalDays = int(total / (24 * time.Hour))
+	return output
+}
+
+func mapEmploymentPeriod(
+	clinicID string,
+	dentistID string,
+	isAdmin bool,
+	isLegalRepresentative bool,
+	startedAt time.Time,
+	endedAt sql.NullTime,
+	now time.Time,
+) EmploymentPeriodOutput {
+	end := now
+	var endedAtPointer *time.Time
+	if endedAt.Valid {
+		end = endedAt.Time
+		endedAtPointer = &endedAt.Time
+	}
+	duration := max(end.Sub(startedAt), 0)
+
+	return EmploymentPeriodOutput{
+		ClinicID:              clinicID,
+		DentistID:             dentistID,
+		IsAdmin:               isAdmin,
+		IsLegalRepresentative: isLegalRepresentative,
+		StartedAt:             startedAt,
+		EndedAt:               endedAtPointer,
+		Active:                !endedAt.Valid,
+		DurationDays:          int(duration / (24 * time.Hour)),
+	}
+}
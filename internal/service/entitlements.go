@@ -0,0 +1,163 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	PlanBasic      = "BASIC"
+	PlanPro        = "PRO"
+	PlanEnterprise = "ENTERPRISE"
+
+	FeatureScheduling = "scheduling"
+	FeatureBilling    = "billing"
+	FeatureWebhooks   = "webhooks"
+)
+
+// Features lists every gated module in the order the entitlements endpoint reports them.
+var Features = []string{FeatureScheduling, FeatureBilling, FeatureWebhooks}
+
+var planFeatures = map[string][]string{
+	PlanBasic:      {},
+	PlanPro:        {FeatureScheduling, FeatureBilling},
+	PlanEnterprise: {FeatureScheduling, FeatureBilling, FeatureWebhooks},
+}
+
+type FeatureNotInPlanError struct {
+	Feature string
+	Plan    string
+}
+
+func (e *FeatureNotInPlanError) Error() string {
+	return fmt.Sprintf("%s: %s is not included in the %s plan", ErrFeatureNotInPlan, e.Feature, e.Plan)
+}
+
+func (e *FeatureNotInPlanError) Unwrap() error {
+	return ErrFeatureNotInPlan
+}
+
+func planIncludes(plan string, feature string) bool {
+	return slices.Contains(planFeatures[plan], feature)
+}
+
+func validatePlan(plan string) (string, error) {
+	plan = strings.ToUpper(strings.TrimSpace(plan))
+	if _, ok := planFeatures[plan]; !ok {
+		return "", validationError(fmt.Sprintf("plan must be one of: %s, %s, %s", PlanBasic, PlanPro, PlanEnterprise))
+	}
+	return plan, nil
+}
+
+// CheckFeature refuses the caller's organization a module its plan does not include; calls without an organization are not gated.
+func (s *Service) CheckFeature(ctx context.Context, feature string) error {
+	organization := organizationID(ctx)
+	if organization == "" {
+		return nil
+	}
+	row, err := s.queries.GetOrganizationByID(ctx, organization)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("organization not found")
+		}
+		return err
+	}
+	if !planIncludes(row.Plan, feature) {
+		return &FeatureNotInPlanError{Feature: feature, Plan: row.Plan}
+	}
+	return nil
+}
+
+func (s *Service) GetEntitlements(ctx context.Context) (EntitlementsOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetEntitlements")
+	defer span.End()
+
+	row, err := s.queries.GetOrganizationByID(ctx, organizationID(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EntitlementsOutput{}, notFoundError("organization not found")
+		}
+		return EntitlementsOutput{}, err
+	}
+	output := EntitlementsOutput{Plan: row.Plan, Features: make([]FeatureEntitlementOutput, 0, len(Features))}
+	for _, feature := range Features {
+		output.Features = append(output.Features, FeatureEntitlementOutput{Feature: feature, Enabled: planIncludes(row.Plan, feature)})
+	}
+	return output, nil
+}
+
+func (s *Service) UpdateOrganizationPlan(ctx context.Context, id string, input UpdateOrganizationPlanInput) (OrganizationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateOrganizationPlan")
+	defer span.End()
+
+	plan, err := validatePlan(input.Plan)
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+
+	ctx = WithOrganization(ctx, id)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return OrganizationOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	current, err := qtx.LockOrganizationForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrganizationOutput{}, notFoundError("organization not found")
+		}
+		return OrganizationOutput{}, err
+	}
+	if current.Status == OrganizationStatusClosed {
+		return OrganizationOutput{}, conflictError("organization is closed")
+	}
+	organization, err := qtx.UpdateOrganizationPlan(ctx, repository.UpdateOrganizationPlanParams{
+		Plan: plan,
+		ID:   id,
+	})
+	if err != nil {
+		return OrganizationOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "organization.plan_changed",
+		EntityType: AuditEntityOrganization,
+		EntityID:   id,
+		Metadata:   map[string]any{"from": current.Plan, "to": plan},
+	}); err != nil {
+		return OrganizationOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return OrganizationOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapOrganizationOutput(organization), nil
+}
+
+// webhooksEnabled gates outgoing events on the plan and the data region; a failed check skips the event rather than failing the change that triggered it.
+func (s *Service) webhooksEnabled(ctx context.Context) bool {
+	if s.events == nil {
+		return false
+	}
+	if err := s.CheckFeature(ctx, FeatureWebhooks); err != nil {
+		if !errors.Is(err, ErrFeatureNotInPlan) {
+			slog.WarnContext(ctx, "check webhooks entitlement failed", "error", err)
+		}
+		return false
+	}
+	if err := s.checkDataResidency(ctx, DestinationWebhooks); err != nil {
+		slog.WarnContext(ctx, "webhook event withheld", "error", err)
+		return false
+	}
+	return true
+}
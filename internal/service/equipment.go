@@ -0,0 +1,652 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	EquipmentAutoclave   = "AUTOCLAVE"
+	EquipmentCompressor  = "COMPRESSOR"
+	EquipmentXRay        = "XRAY"
+	EquipmentDentalChair = "DENTAL_CHAIR"
+	EquipmentOther       = "OTHER"
+
+	MaintenancePreventive  = "PREVENTIVE"
+	MaintenanceCorrective  = "CORRECTIVE"
+	MaintenanceCalibration = "CALIBRATION"
+	MaintenanceInspection  = "INSPECTION"
+
+	MaintenanceStatusOverdue     = "OVERDUE"
+	MaintenanceStatusDueSoon     = "DUE_SOON"
+	MaintenanceStatusUpToDate    = "UP_TO_DATE"
+	MaintenanceStatusUnscheduled = "UNSCHEDULED"
+
+	defaultMaintenanceDueSoonDays = 30
+	maxMaintenanceIntervalDays    = 3650
+)
+
+type equipmentMaintenanceOverdueData struct {
+	ClinicName    string
+	EquipmentName string
+	DueDate       string
+	DaysOverdue   int
+}
+
+func (s *Service) CreateEquipment(ctx context.Context, clinicID string, input CreateEquipmentInput) (EquipmentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateEquipment")
+	defer span.End()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return EquipmentOutput{}, validationError("name is required")
+	}
+	kind, err := normalizeEquipmentKind(input.Kind)
+	if err != nil {
+		return EquipmentOutput{}, err
+	}
+	interval, err := validateMaintenanceInterval(input.MaintenanceIntervalDays)
+	if err != nil {
+		return EquipmentOutput{}, err
+	}
+	lastMaintainedAt, err := parseDocumentDate("last_maintained_at", input.LastMaintainedAt)
+	if err != nil {
+		return EquipmentOutput{}, err
+	}
+	nextDue, err := parseDocumentDate("next_maintenance_due", input.NextMaintenanceDue)
+	if err != nil {
+		return EquipmentOutput{}, err
+	}
+	if !nextDue.Valid && lastMaintainedAt.Valid && interval.Valid {
+		nextDue = sql.NullTime{Time: lastMaintainedAt.Time.AddDate(0, 0, int(interval.Int32)), Valid: true}
+	}
+	equipmentID, err := newUUIDV7()
+	if err != nil {
+		return EquipmentOutput{}, err
+	}
+
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EquipmentOutput{}, notFoundError("clinic not found")
+		}
+		return EquipmentOutput{}, err
+	}
+	today := s.todayIn(clinicLocation(ctx, clinic.Timezone))
+	if lastMaintainedAt.Valid && lastMaintainedAt.Time.After(today) {
+		return EquipmentOutput{}, validationError("last_maintained_at must not be in the future")
+	}
+
+	equipment, err := s.queries.CreateEquipment(ctx, repository.CreateEquipmentParams{
+		ID:                      equipmentID,
+		OrganizationID:          organizationID(ctx),
+		ClinicID:                clinicID,
+		Name:                    name,
+		Kind:                    kind,
+		Manufacturer:            optionalString(input.Manufacturer),
+		Model:                   optionalString(input.Model),
+		SerialNumber:            optionalString(input.SerialNumber),
+		MaintenanceIntervalDays: interval,
+		LastMaintainedAt:        lastMaintainedAt,
+		NextMaintenanceDue:      nextDue,
+		Notes:                   optionalString(input.Notes),
+	})
+	if err != nil {
+		return EquipmentOutput{}, mapDatabaseError(err)
+	}
+	return mapEquipment(equipment, today, defaultMaintenanceDueSoonDays), nil
+}
+
+func (s *Service) GetEquipment(ctx context.Context, clinicID string, equipmentID string) (EquipmentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetEquipment")
+	defer span.End()
+
+	today, err := s.clinicToday(ctx, clinicID)
+	if err != nil {
+		return EquipmentOutput{}, err
+	}
+	equipment, err := s.queries.GetEquipment(ctx, repository.GetEquipmentParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             equipmentID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EquipmentOutput{}, notFoundError("equipment not found")
+		}
+		return EquipmentOutput{}, err
+	}
+	return mapEquipment(equipment, today, defaultMaintenanceDueSoonDays), nil
+}
+
+func (s *Service) ListEquipment(ctx context.Context, clinicID string, limit int, cursor *string, kind *string) ([]EquipmentOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListEquipment")
+	defer span.End()
+
+	today, err := s.clinicToday(ctx, clinicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListEquipmentCursorParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+	if kind != nil {
+		normalized, err := normalizeEquipmentKind(*kind)
+		if err != nil {
+			return nil, nil, err
+		}
+		params.Kind = sql.NullString{String: normalized, Valid: true}
+	}
+
+	rows, err := s.queries.ListEquipmentCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	equipment := make([]EquipmentOutput, 0, len(rows))
+	for _, row := range rows {
+		equipment = append(equipment, mapEquipment(row, today, defaultMaintenanceDueSoonDays))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return equipment, nextCursor, nil
+}
+
+// UpdateEquipment edits the registry entry. next_maintenance_due can be moved by hand, for example after a technician
+// visit is booked; service history only changes through maintenance records.
+func (s *Service) UpdateEquipment(ctx context.Context, clinicID string, equipmentID string, input UpdateEquipmentInput) (EquipmentOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateEquipment")
+	defer span.End()
+
+	params := repository.UpdateEquipmentParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             equipmentID,
+	}
+	if input.Name != nil {
+		if params.Name = optionalString(input.Name); !params.Name.Valid {
+			return EquipmentOutput{}, validationError("name must not be empty")
+		}
+	}
+	if input.Kind != nil {
+		kind, err := normalizeEquipmentKind(*input.Kind)
+		if err != nil {
+			return EquipmentOutput{}, err
+		}
+		params.Kind = sql.NullString{String: kind, Valid: true}
+	}
+	// Empty text fields clear them.
+	for _, field := range []struct {
+		value  *string
+		target *sql.NullString
+	}{
+		{input.Manufacturer, &params.Manufacturer},
+		{input.Model, &params.Model},
+		{input.SerialNumber, &params.SerialNumber},
+		{input.Notes, &params.Notes},
+	} {
+		if field.value != nil {
+			*field.target = sql.NullString{String: strings.TrimSpace(*field.value), Valid: true}
+		}
+	}
+	if input.MaintenanceIntervalDays != nil {
+		if *input.MaintenanceIntervalDays == 0 {
+			params.ClearMaintenanceInterval = true
+		} else {
+			interval, err := validateMaintenanceInterval(input.MaintenanceIntervalDays)
+			if err != nil {
+				return EquipmentOutput{}, err
+			}
+			params.MaintenanceIntervalDays = interval
+		}
+	}
+	nextDue, err := parseDocumentDate("next_maintenance_due", input.NextMaintenanceDue)
+	if err != nil {
+		return EquipmentOutput{}, err
+	}
+	params.NextMaintenanceDue = nextDue
+
+	today, err := s.clinicToday(ctx, clinicID)
+	if err != nil {
+		return EquipmentOutput{}, err
+	}
+	equipment, err := s.queries.UpdateEquipment(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return EquipmentOutput{}, notFoundError("equipment not found")
+		}
+		return EquipmentOutput{}, mapDatabaseError(err)
+	}
+	return mapEquipment(equipment, today, defaultMaintenanceDueSoonDays), nil
+}
+
+// DeleteEquipment retires the equipment; its service history is kept.
+func (s *Service) DeleteEquipment(ctx context.Context, clinicID string, equipmentID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteEquipment")
+	defer span.End()
+
+	deleted, err := s.queries.DeleteEquipment(ctx, repository.DeleteEquipmentParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             equipmentID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return notFoundError("equipment not found")
+	}
+	return nil
+}
+
+// RecordMaintenance adds a service to the equipment's history and moves its schedule forward in the same transaction.
+func (s *Service) RecordMaintenance(ctx context.Context, clinicID string, equipmentID string, input CreateMaintenanceRecordInput) (MaintenanceRecordOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RecordMaintenance")
+	defer span.End()
+
+	kind := strings.ToUpper(strings.TrimSpace(input.Kind))
+	switch kind {
+	case MaintenancePreventive, MaintenanceCorrective, MaintenanceCalibration, MaintenanceInspection:
+	default:
+		return MaintenanceRecordOutput{}, validationError(fmt.Sprintf("kind must be one of: %s, %s, %s, %s", MaintenancePreventive, MaintenanceCorrective, MaintenanceCalibration, MaintenanceInspection))
+	}
+	performedAt, err := parseDocumentDate("performed_at", &input.PerformedAt)
+	if err != nil {
+		return MaintenanceRecordOutput{}, err
+	}
+	nextDue, err := parseDocumentDate("next_maintenance_due", input.NextMaintenanceDue)
+	if err != nil {
+		return MaintenanceRecordOutput{}, err
+	}
+	if nextDue.Valid && !nextDue.Time.After(performedAt.Time) {
+		return MaintenanceRecordOutput{}, validationError("next_maintenance_due must be after performed_at")
+	}
+	var cost sql.NullInt64
+	if input.CostCents != nil {
+		if *input.CostCents < 0 {
+			return MaintenanceRecordOutput{}, validationError("cost_cents must not be negative")
+		}
+		cost = sql.NullInt64{Int64: *input.CostCents, Valid: true}
+	}
+	today, err := s.clinicToday(ctx, clinicID)
+	if err != nil {
+		return MaintenanceRecordOutput{}, err
+	}
+	if performedAt.Time.After(today) {
+		return MaintenanceRecordOutput{}, validationError("performed_at must not be in the future")
+	}
+	recordID, err := newUUIDV7()
+	if err != nil {
+		return MaintenanceRecordOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return MaintenanceRecordOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	equipment, err := qtx.LockEquipmentForUpdate(ctx, repository.LockEquipmentForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             equipmentID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return MaintenanceRecordOutput{}, notFoundError("equipment not found")
+		}
+		return MaintenanceRecordOutput{}, err
+	}
+	lastMaintainedAt, scheduledDue := nextMaintenanceSchedule(equipment, kind, performedAt.Time, nextDue)
+	record, err := qtx.CreateEquipmentMaintenanceRecord(ctx, repository.CreateEquipmentMaintenanceRecordParams{
+		ID:                 recordID,
+		OrganizationID:     organizationID(ctx),
+		ClinicID:           clinicID,
+		EquipmentID:        equipment.ID,
+		Kind:               kind,
+		PerformedAt:        performedAt.Time,
+		PerformedBy:        optionalString(input.PerformedBy),
+		CostCents:          cost,
+		Notes:              optionalString(input.Notes),
+		NextMaintenanceDue: scheduledDue,
+		CreatedByUserID:    auditActorID(ctx),
+	})
+	if err != nil {
+		return MaintenanceRecordOutput{}, mapDatabaseError(err)
+	}
+	if _, err := qtx.SetEquipmentMaintenanceSchedule(ctx, repository.SetEquipmentMaintenanceScheduleParams{
+		OrganizationID:     organizationID(ctx),
+		ID:                 equipment.ID,
+		LastMaintainedAt:   lastMaintainedAt,
+		NextMaintenanceDue: scheduledDue,
+	}); err != nil {
+		return MaintenanceRecordOutput{}, mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return MaintenanceRecordOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapMaintenanceRecord(record), nil
+}
+
+// nextMaintenanceSchedule works out the equipment's schedule after a service. A corrective repair does not replace the
+// periodic service, and a record backdated before the last one never pulls the schedule back; an explicit due date from
+// the technician wins over the interval.
+func nextMaintenanceSchedule(equipment repository.Equipment, kind string, performedAt time.Time, explicitDue sql.NullTime) (sql.NullTime, sql.NullTime) {
+	last := equipment.LastMaintainedAt
+	due := equipment.NextMaintenanceDue
+	if kind == MaintenanceCorrective || (last.Valid && performedAt.Before(last.Time)) {
+		if explicitDue.Valid {
+			due = explicitDue
+		}
+		return last, due
+	}
+	last = sql.NullTime{Time: performedAt, Valid: true}
+	switch {
+	case explicitDue.Valid:
+		due = explicitDue
+	case equipment.MaintenanceIntervalDays.Valid:
+		due = sql.NullTime{Time: performedAt.AddDate(0, 0, int(equipment.MaintenanceIntervalDays.Int32)), Valid: true}
+	default:
+		due = sql.NullTime{}
+	}
+	return last, due
+}
+
+func (s *Service) ListMaintenanceRecords(ctx context.Context, clinicID string, equipmentID string, limit int, cursor *string) ([]MaintenanceRecordOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListMaintenanceRecords")
+	defer span.End()
+
+	if _, err := s.queries.GetEquipment(ctx, repository.GetEquipmentParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             equipmentID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, notFoundError("equipment not found")
+		}
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListEquipmentMaintenanceRecordsCursorParams{
+		OrganizationID: organizationID(ctx),
+		EquipmentID:    equipmentID,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+
+	rows, err := s.queries.ListEquipmentMaintenanceRecordsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	records := make([]MaintenanceRecordOutput, 0, len(rows))
+	for _, row := range rows {
+		records = append(records, mapMaintenanceRecord(row))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return records, nextCursor, nil
+}
+
+// GetEquipmentComplianceReport lists every active piece of equipment at the clinic by due date, counting what is overdue,
+// due within the window and without any schedule.
+func (s *Service) GetEquipmentComplianceReport(ctx context.Context, clinicID string, withinDays int) (EquipmentComplianceReportOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetEquipmentComplianceReport")
+	defer span.End()
+
+	if withinDays < 0 || withinDays > MaxExpiringWithinDays {
+		return EquipmentComplianceReportOutput{}, validationError(fmt.Sprintf("within_days must be between 0 and %d", MaxExpiringWithinDays))
+	}
+	today, err := s.clinicToday(ctx, clinicID)
+	if err != nil {
+		return EquipmentComplianceReportOutput{}, err
+	}
+	rows, err := s.queries.ListClinicEquipmentSchedule(ctx, repository.ListClinicEquipmentScheduleParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		return EquipmentComplianceReportOutput{}, err
+	}
+
+	report := EquipmentComplianceReportOutput{
+		ClinicID:   clinicID,
+		AsOf:       today.Format(documentDateLayout),
+		WithinDays: withinDays,
+		Equipment:  make([]EquipmentOutput, 0, len(rows)),
+	}
+	for _, row := range rows {
+		output := mapEquipment(row, today, withinDays)
+		switch output.MaintenanceStatus {
+		case MaintenanceStatusOverdue:
+			report.Overdue++
+		case MaintenanceStatusDueSoon:
+			report.DueSoon++
+		case MaintenanceStatusUnscheduled:
+			report.Unscheduled++
+		}
+		report.Equipment = append(report.Equipment, output)
+	}
+	return report, nil
+}
+
+// NotifyOverdueMaintenance alerts each clinic, through the notification pipeline, once for every due date its equipment
+// misses. Logging a service or moving the due date arms the alert again for the new date.
+func (s *Service) NotifyOverdueMaintenance(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.NotifyOverdueMaintenance")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	today := s.today()
+	rows, err := qtx.ListOverdueEquipmentForNotification(ctx, repository.ListOverdueEquipmentForNotificationParams{
+		OrganizationID: organizationID(ctx),
+		Today:          today,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	alerted := 0
+	for _, row := range rows {
+		if err := s.enqueueMaintenanceOverdue(ctx, qtx, row, today); err != nil {
+			return alerted, err
+		}
+		if err := qtx.MarkEquipmentOverdueNotified(ctx, repository.MarkEquipmentOverdueNotifiedParams{
+			OrganizationID: organizationID(ctx),
+			ID:             row.ID,
+			Due:            row.NextMaintenanceDue.Time,
+		}); err != nil {
+			return alerted, err
+		}
+		alerted++
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return alerted, fmt.Errorf("commit transaction: %w", err)
+	}
+	return alerted, nil
+}
+
+func (s *Service) enqueueMaintenanceOverdue(ctx context.Context, qtx repository.Querier, row repository.ListOverdueEquipmentForNotificationRow, today time.Time) error {
+	name := row.ClinicLegalName
+	if row.ClinicTradeName.Valid && strings.TrimSpace(row.ClinicTradeName.String) != "" {
+		name = row.ClinicTradeName.String
+	}
+	data := equipmentMaintenanceOverdueData{
+		ClinicName:    name,
+		EquipmentName: row.Name,
+		DueDate:       row.NextMaintenanceDue.Time.Format("02/01/2006"),
+		DaysOverdue:   int(today.Sub(row.NextMaintenanceDue.Time).Hours() / 24),
+	}
+	contacts := make(map[string]string)
+	if email := strings.TrimSpace(row.ClinicEmail.String); email != "" {
+		contacts[NotificationChannelEmail] = email
+	}
+	if phone, ok := validation.NormalizePhone(row.ClinicPhone.String); ok {
+		contacts[NotificationChannelSMS] = phone
+		contacts[NotificationChannelWhatsApp] = phone
+	}
+	for _, channel := range []string{NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWhatsApp} {
+		contact, ok := contacts[channel]
+		if !ok {
+			continue
+		}
+		if err := s.enqueueNotification(ctx, qtx, notificationRequest{
+			Channel:   channel,
+			Template:  NotificationTemplateMaintenanceOverdue,
+			Recipient: contact,
+			ClinicID:  row.ClinicID,
+			DedupeKey: NotificationTemplateMaintenanceOverdue + ":" + row.ID + ":" + row.NextMaintenanceDue.Time.Format(documentDateLayout) + ":" + channel,
+			Data:      data,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) clinicToday(ctx context.Context, clinicID string) (time.Time, error) {
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return time.Time{}, notFoundError("clinic not found")
+		}
+		return time.Time{}, err
+	}
+	return s.todayIn(clinicLocation(ctx, clinic.Timezone)), nil
+}
+
+func normalizeEquipmentKind(value string) (string, error) {
+	kind := strings.ToUpper(strings.TrimSpace(value))
+	switch kind {
+	case EquipmentAutoclave, EquipmentCompressor, EquipmentXRay, EquipmentDentalChair, EquipmentOther:
+		return kind, nil
+	default:
+		return "", validationError(fmt.Sprintf("kind must be one of: %s, %s, %s, %s, %s", EquipmentAutoclave, EquipmentCompressor, EquipmentXRay, EquipmentDentalChair, EquipmentOther))
+	}
+}
+
+func validateMaintenanceInterval(days *int) (sql.NullInt32, error) {
+	if days == nil {
+		return sql.NullInt32{}, nil
+	}
+	if *days <= 0 || *days > maxMaintenanceIntervalDays {
+		return sql.NullInt32{}, validationError(fmt.Sprintf("maintenance_interval_days must be between 1 and %d", maxMaintenanceIntervalDays))
+	}
+	return sql.NullInt32{Int32: int32(*days), Valid: true}, nil
+}
+
+func mapEquipment(equipment repository.Equipment, today time.Time, dueSoonDays int) EquipmentOutput {
+	output := EquipmentOutput{
+		ID:                equipment.ID,
+		ClinicID:          equipment.ClinicID,
+		Name:              equipment.Name,
+		Kind:              equipment.Kind,
+		Manufacturer:      nullToPointer(equipment.Manufacturer),
+		Model:             nullToPointer(equipment.Model),
+		SerialNumber:      nullToPointer(equipment.SerialNumber),
+		MaintenanceStatus: MaintenanceStatusUnscheduled,
+		Notes:             nullToPointer(equipment.Notes),
+		CreatedAt:         equipment.CreatedAt,
+		UpdatedAt:         equipment.UpdatedAt,
+	}
+	if equipment.MaintenanceIntervalDays.Valid {
+		interval := int(equipment.MaintenanceIntervalDays.Int32)
+		output.MaintenanceIntervalDays = &interval
+	}
+	if equipment.LastMaintainedAt.Valid {
+		last := equipment.LastMaintainedAt.Time.Format(documentDateLayout)
+		output.LastMaintainedAt = &last
+	}
+	if equipment.NextMaintenanceDue.Valid {
+		due := equipment.NextMaintenanceDue.Time
+		dueDate := due.Format(documentDateLayout)
+		days := int(due.Sub(today).Hours() / 24)
+		output.NextMaintenanceDue = &dueDate
+		output.DaysUntilDue = &days
+		switch {
+		case days < 0:
+			output.MaintenanceStatus = MaintenanceStatusOverdue
+		case days <= dueSoonDays:
+			output.MaintenanceStatus = MaintenanceStatusDueSoon
+		default:
+			output.MaintenanceStatus = MaintenanceStatusUpToDate
+		}
+	}
+	return output
+}
+
+func mapMaintenanceRecord(record repository.EquipmentMaintenanceRecord) MaintenanceRecordOutput {
+	output := MaintenanceRecordOutput{
+		ID:              record.ID,
+		EquipmentID:     record.EquipmentID,
+		Kind:            record.Kind,
+		PerformedAt:     record.PerformedAt.Format(documentDateLayout),
+		PerformedBy:     nullToPointer(record.PerformedBy),
+		Notes:           nullToPointer(record.Notes),
+		CreatedByUserID: nullUUIDToPointer(record.CreatedByUserID),
+		CreatedAt:       record.CreatedAt,
+	}
+	if record.CostCents.Valid {
+		cost := record.CostCents.Int64
+		output.CostCents = &cost
+	}
+	if record.NextMaintenanceDue.Valid {
+		due := record.NextMaintenanceDue.Time.Format(documentDateLayout)
+		output.NextMaintenanceDue = &due
+	}
+	return output
+}
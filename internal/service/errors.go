@@ -6,10 +6,18 @@ import (
 )
 
 var (
-	ErrNotFound     = errors.New("not found")
-	ErrValidation   = errors.New("validation error")
-	ErrConflict     = errors.New("conflict")
-	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound             = errors.New("not found")
+	ErrValidation           = errors.New("validation error")
+	ErrConflict             = errors.New("conflict")
+	ErrUnauthorized         = errors.New("unauthorized")
+	ErrForbidden            = errors.New("forbidden")
+	ErrBlocked              = errors.New("blocked")
+	ErrRoleInvariant        = errors.New("role invariant violation")
+	ErrFinancialHold        = errors.New("financial hold")
+	ErrConfirmationRequired = errors.New("confirmation required")
+	ErrQuotaExceeded        = errors.New("quota exceeded")
+	ErrFeatureNotInPlan     = errors.New("feature not in plan")
+	ErrDataResidency        = errors.New("data residency violation")
 )
 
 func notFoundError(message string) error {
@@ -27,3 +35,27 @@ func conflictError(message string) error {
 func unauthorizedError(message string) error {
 	return fmt.Errorf("%w: %s", ErrUnauthorized, message)
 }
+
+func forbiddenError(message string) error {
+	return fmt.Errorf("%w: %s", ErrForbidden, message)
+}
+
+func blockedError(message string) error {
+	return fmt.Errorf("%w: %s", ErrBlocked, message)
+}
+
+func roleInvariantError(message string) error {
+	return fmt.Errorf("%w: %s", ErrRoleInvariant, message)
+}
+
+func financialHoldError(message string) error {
+	return fmt.Errorf("%w: %s", ErrFinancialHold, message)
+}
+
+func confirmationRequiredError(message string) error {
+	return fmt.Errorf("%w: %s", ErrConfirmationRequired, message)
+}
+
+func dataResidencyError(message string) error {
+	return fmt.Errorf("%w: %s", ErrDataResidency, message)
+}
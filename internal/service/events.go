@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+type Event struct {
+	ID         string
+	Type       string
+	OccurredAt time.Time
+	Data       any
+}
+
+type EventPublisher interface {
+	Publish(ctx context.Context, event Event) error
+}
+
+func WithEventPublisher(publisher EventPublisher) Option {
+	return func(s *Service) {
+		s.events = publisher
+	}
+}
+
+func (s *Service) newEvent(eventType string, data any) (Event, error) {
+	eventID, err := newUUIDV7()
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		ID:         eventID,
+		Type:       eventType,
+		OccurredAt: s.now().UTC(),
+		Data:       data,
+	}, nil
+}
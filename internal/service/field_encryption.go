@@ -0,0 +1,432 @@
+package service
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	AuditEntityOrganization = "ORGANIZATION"
+
+	encryptedFieldPrefix = "enc:v1:"
+	dataKeySize          = 32
+	// Other instances keep serving a shredded organization's key for at most this long.
+	dataKeyCacheTTL = 5 * time.Minute
+)
+
+// DataKeyWrapper protects organization data keys with a master key, either local or held by a KMS.
+type DataKeyWrapper interface {
+	WrapKey(ctx context.Context, organizationID string, key []byte) ([]byte, error)
+	UnwrapKey(ctx context.Context, organizationID string, wrapped []byte) ([]byte, error)
+}
+
+func WithDataKeyWrapper(wrapper DataKeyWrapper) Option {
+	return func(s *Service) {
+		s.keyWrapper = wrapper
+	}
+}
+
+type cachedDataKey struct {
+	aead     cipher.AEAD
+	loadedAt time.Time
+}
+
+// dataKeyStore creates keys through the non-transactional querier, so a rolled-back write never leaves values sealed with a key that was not stored.
+type dataKeyStore struct {
+	wrapper DataKeyWrapper
+	queries repository.Querier
+	now     func() time.Time
+
+	mu    sync.Mutex
+	cache map[string]cachedDataKey
+}
+
+func newDataKeyStore(wrapper DataKeyWrapper, queries repository.Querier, now func() time.Time) *dataKeyStore {
+	return &dataKeyStore{
+		wrapper: wrapper,
+		queries: queries,
+		now:     now,
+		cache:   make(map[string]cachedDataKey),
+	}
+}
+
+func (k *dataKeyStore) key(ctx context.Context, organization string, create bool) (cipher.AEAD, error) {
+	k.mu.Lock()
+	cached, ok := k.cache[organization]
+	k.mu.Unlock()
+	if ok && k.now().Sub(cached.loadedAt) < dataKeyCacheTTL {
+		return cached.aead, nil
+	}
+
+	ctx = WithOrganization(ctx, organization)
+	row, err := k.queries.GetOrganizationKey(ctx, organization)
+	if errors.Is(err, sql.ErrNoRows) && create {
+		if err := k.createKey(ctx, organization); err != nil {
+			return nil, err
+		}
+		// Re-read so concurrent first writes all settle on the key that won the insert.
+		row, err = k.queries.GetOrganizationKey(ctx, organization)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("organization %s has encrypted values but no data key", organization)
+		}
+		return nil, fmt.Errorf("load organization data key: %w", err)
+	}
+	if row.ShreddedAt.Valid {
+		return nil, conflictError("organization data key was shredded")
+	}
+
+	key, err := k.wrapper.UnwrapKey(ctx, organization, row.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newFieldCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	k.mu.Lock()
+	k.cache[organization] = cachedDataKey{aead: aead, loadedAt: k.now()}
+	k.mu.Unlock()
+	return aead, nil
+}
+
+func (k *dataKeyStore) createKey(ctx context.Context, organization string) error {
+	key := make([]byte, dataKeySize)
+	if _, err := rand.Read(key); err != nil {
+		return fmt.Errorf("generate data key: %w", err)
+	}
+	wrapped, err := k.wrapper.WrapKey(ctx, organization, key)
+	if err != nil {
+		return err
+	}
+	return k.queries.CreateOrganizationKey(ctx, repository.CreateOrganizationKeyParams{
+		OrganizationID: organization,
+		WrappedKey:     wrapped,
+	})
+}
+
+func (k *dataKeyStore) forget(organization string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.cache, organization)
+}
+
+// seal leaves values in plaintext while no master key is configured; open reads those and legacy rows unchanged.
+func (k *dataKeyStore) seal(ctx context.Context, organization string, value string) (string, error) {
+	if k.wrapper == nil || value == "" {
+		return value, nil
+	}
+	aead, err := k.key(ctx, organization, true)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(organization))
+	return encryptedFieldPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+func (k *dataKeyStore) open(ctx context.Context, organization string, value string) (string, error) {
+	encoded, ok := strings.CutPrefix(value, encryptedFieldPrefix)
+	if !ok {
+		return value, nil
+	}
+	if k.wrapper == nil {
+		return "", errors.New("encrypted value found but no field encryption master key is configured")
+	}
+	aead, err := k.key(ctx, organization, false)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], []byte(organization))
+	if err != nil {
+		return "", fmt.Errorf("decrypt field: %w", err)
+	}
+	return string(plaintext), nil
+}
+
+func (k *dataKeyStore) sealNull(ctx context.Context, organization string, value sql.NullString) (sql.NullString, error) {
+	if !value.Valid {
+		return value, nil
+	}
+	sealed, err := k.seal(ctx, organization, value.String)
+	return sql.NullString{String: sealed, Valid: true}, err
+}
+
+func (k *dataKeyStore) openNull(ctx context.Context, organization string, value sql.NullString) (sql.NullString, error) {
+	if !value.Valid {
+		return value, nil
+	}
+	opened, err := k.open(ctx, organization, value.String)
+	return sql.NullString{String: opened, Valid: true}, err
+}
+
+func newFieldCipher(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create data key cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptingQuerier seals account numbers and payout files on their way into the database and opens them on the way out, so the rest of the service only sees plaintext.
+type encryptingQuerier struct {
+	repository.Querier
+	keys *dataKeyStore
+}
+
+func (q encryptingQuerier) openBankAccount(ctx context.Context, account repository.BankAccount, err error) (repository.BankAccount, error) {
+	if err != nil {
+		return account, err
+	}
+	account.AccountNumber, err = q.keys.open(ctx, account.OrganizationID, account.AccountNumber)
+	return account, err
+}
+
+func (q encryptingQuerier) openBankAccounts(ctx context.Context, accounts []repository.BankAccount, err error) ([]repository.BankAccount, error) {
+	if err != nil {
+		return accounts, err
+	}
+	for i := range accounts {
+		if accounts[i], err = q.openBankAccount(ctx, accounts[i], nil); err != nil {
+			return nil, err
+		}
+	}
+	return accounts, nil
+}
+
+func (q encryptingQuerier) openBankAccountChange(ctx context.Context, change repository.BankAccountChange, err error) (repository.BankAccountChange, error) {
+	if err != nil {
+		return change, err
+	}
+	change.AccountNumber, err = q.keys.openNull(ctx, change.OrganizationID, change.AccountNumber)
+	return change, err
+}
+
+func (q encryptingQuerier) CreateBankAccount(ctx context.Context, arg repository.CreateBankAccountParams) (repository.BankAccount, error) {
+	var err error
+	if arg.AccountNumber, err = q.keys.seal(ctx, arg.OrganizationID, arg.AccountNumber); err != nil {
+		return repository.BankAccount{}, err
+	}
+	account, err := q.Querier.CreateBankAccount(ctx, arg)
+	return q.openBankAccount(ctx, account, err)
+}
+
+func (q encryptingQuerier) CreateBankAccounts(ctx context.Context, arg repository.CreateBankAccountsParams) error {
+	sealed := make([]string, len(arg.AccountNumbers))
+	for i, accountNumber := range arg.AccountNumbers {
+		var err error
+		if sealed[i], err = q.keys.seal(ctx, arg.OrganizationID, accountNumber); err != nil {
+			return err
+		}
+	}
+	arg.AccountNumbers = sealed
+	return q.Querier.CreateBankAccounts(ctx, arg)
+}
+
+func (q encryptingQuerier) UpdateBankAccount(ctx context.Context, arg repository.UpdateBankAccountParams) (repository.BankAccount, error) {
+	var err error
+	if arg.AccountNumber, err = q.keys.sealNull(ctx, arg.OrganizationID, arg.AccountNumber); err != nil {
+		return repository.BankAccount{}, err
+	}
+	account, err := q.Querier.UpdateBankAccount(ctx, arg)
+	return q.openBankAccount(ctx, account, err)
+}
+
+func (q encryptingQuerier) GetBankAccountByIDAndClinicID(ctx context.Context, arg repository.GetBankAccountByIDAndClinicIDParams) (repository.BankAccount, error) {
+	account, err := q.Querier.GetBankAccountByIDAndClinicID(ctx, arg)
+	return q.openBankAccount(ctx, account, err)
+}
+
+func (q encryptingQuerier) GetBankAccountByVerificationReference(ctx context.Context, reference string) (repository.BankAccount, error) {
+	account, err := q.Querier.GetBankAccountByVerificationReference(ctx, reference)
+	return q.openBankAccount(ctx, account, err)
+}
+
+func (q encryptingQuerier) ListBankAccountsByClinicID(ctx context.Context, arg repository.ListBankAccountsByClinicIDParams) ([]repository.BankAccount, error) {
+	accounts, err := q.Querier.ListBankAccountsByClinicID(ctx, arg)
+	return q.openBankAccounts(ctx, accounts, err)
+}
+
+func (q encryptingQuerier) ListBankAccountsByClinicIDDeletedAt(ctx context.Context, arg repository.ListBankAccountsByClinicIDDeletedAtParams) ([]repository.BankAccount, error) {
+	accounts, err := q.Querier.ListBankAccountsByClinicIDDeletedAt(ctx, arg)
+	return q.openBankAccounts(ctx, accounts, err)
+}
+
+func (q encryptingQuerier) ListBankAccountsForRevision(ctx context.Context, arg repository.ListBankAccountsForRevisionParams) ([]repository.BankAccount, error) {
+	accounts, err := q.Querier.ListBankAccountsForRevision(ctx, arg)
+	return q.openBankAccounts(ctx, accounts, err)
+}
+
+// The aggregate carries bank accounts as JSON, so their account numbers are opened in place.
+func (q encryptingQuerier) GetClinicAggregate(ctx context.Context, arg repository.GetClinicAggregateParams) (repository.GetClinicAggregateRow, error) {
+	row, err := q.Querier.GetClinicAggregate(ctx, arg)
+	if err != nil {
+		return row, err
+	}
+	var accounts []map[string]json.RawMessage
+	if err := json.Unmarshal(row.BankAccounts, &accounts); err != nil {
+		return row, fmt.Errorf("decode clinic bank accounts: %w", err)
+	}
+	for _, account := range accounts {
+		var accountNumber string
+		if err := json.Unmarshal(account["account_number"], &accountNumber); err != nil {
+			return row, fmt.Errorf("decode account number: %w", err)
+		}
+		if accountNumber, err = q.keys.open(ctx, arg.OrganizationID, accountNumber); err != nil {
+			return row, err
+		}
+		if account["account_number"], err = json.Marshal(accountNumber); err != nil {
+			return row, err
+		}
+	}
+	row.BankAccounts, err = json.Marshal(accounts)
+	return row, err
+}
+
+func (q encryptingQuerier) CreateBankAccountChange(ctx context.Context, arg repository.CreateBankAccountChangeParams) (repository.BankAccountChange, error) {
+	var err error
+	if arg.AccountNumber, err = q.keys.sealNull(ctx, arg.OrganizationID, arg.AccountNumber); err != nil {
+		return repository.BankAccountChange{}, err
+	}
+	change, err := q.Querier.CreateBankAccountChange(ctx, arg)
+	return q.openBankAccountChange(ctx, change, err)
+}
+
+func (q encryptingQuerier) GetBankAccountChange(ctx context.Context, arg repository.GetBankAccountChangeParams) (repository.BankAccountChange, error) {
+	change, err := q.Querier.GetBankAccountChange(ctx, arg)
+	return q.openBankAccountChange(ctx, change, err)
+}
+
+func (q encryptingQuerier) ListBankAccountChanges(ctx context.Context, arg repository.ListBankAccountChangesParams) ([]repository.BankAccountChange, error) {
+	changes, err := q.Querier.ListBankAccountChanges(ctx, arg)
+	if err != nil {
+		return changes, err
+	}
+	for i := range changes {
+		if changes[i], err = q.openBankAccountChange(ctx, changes[i], nil); err != nil {
+			return nil, err
+		}
+	}
+	return changes, nil
+}
+
+func (q encryptingQuerier) CreatePayoutBatchItem(ctx context.Context, arg repository.CreatePayoutBatchItemParams) error {
+	var err error
+	if arg.AccountNumber, err = q.keys.seal(ctx, arg.OrganizationID, arg.AccountNumber); err != nil {
+		return err
+	}
+	return q.Querier.CreatePayoutBatchItem(ctx, arg)
+}
+
+func (q encryptingQuerier) ListPayoutBatchItems(ctx context.Context, arg repository.ListPayoutBatchItemsParams) ([]repository.PayoutBatchItem, error) {
+	items, err := q.Querier.ListPayoutBatchItems(ctx, arg)
+	if err != nil {
+		return items, err
+	}
+	for i := range items {
+		if items[i].AccountNumber, err = q.keys.open(ctx, items[i].OrganizationID, items[i].AccountNumber); err != nil {
+			return nil, err
+		}
+	}
+	return items, nil
+}
+
+// The generated payout file carries every payee's account number, so it is sealed whole like the batch items.
+func (q encryptingQuerier) CompletePayoutBatch(ctx context.Context, arg repository.CompletePayoutBatchParams) error {
+	sealed, err := q.keys.seal(ctx, arg.OrganizationID, string(arg.FileContent))
+	if err != nil {
+		return err
+	}
+	arg.FileContent = []byte(sealed)
+	return q.Querier.CompletePayoutBatch(ctx, arg)
+}
+
+func (q encryptingQuerier) GetPayoutBatchFile(ctx context.Context, arg repository.GetPayoutBatchFileParams) (repository.GetPayoutBatchFileRow, error) {
+	file, err := q.Querier.GetPayoutBatchFile(ctx, arg)
+	if err != nil {
+		return file, err
+	}
+	content, err := q.keys.open(ctx, arg.OrganizationID, string(file.FileContent))
+	file.FileContent = []byte(content)
+	return file, err
+}
+
+func (q encryptingQuerier) ListClinicBankAccountHistoryCursor(ctx context.Context, arg repository.ListClinicBankAccountHistoryCursorParams) ([]repository.ListClinicBankAccountHistoryCursorRow, error) {
+	rows, err := q.Querier.ListClinicBankAccountHistoryCursor(ctx, arg)
+	if err != nil {
+		return rows, err
+	}
+	for i := range rows {
+		if rows[i].AccountNumber, err = q.keys.open(ctx, arg.OrganizationID, rows[i].AccountNumber); err != nil {
+			return nil, err
+		}
+	}
+	return rows, nil
+}
+
+// ShredOrganizationDataKey destroys the organization's data key, leaving every value sealed with it unreadable for good.
+func (s *Service) ShredOrganizationDataKey(ctx context.Context, id string, input ShredOrganizationDataKeyInput) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ShredOrganizationDataKey")
+	defer span.End()
+
+	if s.dataKeys == nil || s.dataKeys.wrapper == nil {
+		return conflictError("field encryption is not configured")
+	}
+	ctx = WithOrganization(ctx, id)
+	organization, err := s.queries.GetOrganizationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("organization not found")
+		}
+		return err
+	}
+	if strings.TrimSpace(input.ConfirmSlug) != organization.Slug {
+		return validationError("confirm_slug must match the organization slug")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	if err := qtx.ShredOrganizationKey(ctx, organization.ID); err != nil {
+		return mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "organization.data_key_shredded",
+		EntityType: AuditEntityOrganization,
+		EntityID:   organization.ID,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	s.dataKeys.forget(organization.ID)
+	return nil
+}
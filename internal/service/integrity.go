@@ -0,0 +1,224 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	IntegrityClinicWithoutBankAccount        = "CLINIC_WITHOUT_BANK_ACCOUNT"
+	IntegrityClinicWithoutPrimaryBankAccount = "CLINIC_WITHOUT_PRIMARY_BANK_ACCOUNT"
+	IntegrityDentistWithoutActiveClinic      = "DENTIST_WITHOUT_ACTIVE_CLINIC"
+	IntegrityOrphanedPerson                  = "ORPHANED_PERSON"
+
+	AuditEntityPerson = "PERSON"
+
+	integrityFindingLimit = 500
+)
+
+type integrityCheck struct {
+	name       string
+	entityType string
+	list       func(ctx context.Context, pageLimit int32) ([]string, error)
+	// repair is only set for violations with a single unambiguous fix; it reports false when the row no longer needs one.
+	repair func(ctx context.Context, id string) (bool, error)
+}
+
+func (s *Service) integrityChecks() []integrityCheck {
+	return []integrityCheck{
+		{
+			name:       IntegrityClinicWithoutBankAccount,
+			entityType: AuditEntityClinic,
+			list: func(ctx context.Context, pageLimit int32) ([]string, error) {
+				return s.queries.ListClinicsWithoutActiveBankAccount(ctx, repository.ListClinicsWithoutActiveBankAccountParams{
+					OrganizationID: organizationID(ctx),
+					PageLimit:      pageLimit,
+				})
+			},
+		},
+		{
+			name:       IntegrityClinicWithoutPrimaryBankAccount,
+			entityType: AuditEntityClinic,
+			list: func(ctx context.Context, pageLimit int32) ([]string, error) {
+				return s.queries.ListClinicsWithoutPrimaryBankAccount(ctx, repository.ListClinicsWithoutPrimaryBankAccountParams{
+					OrganizationID: organizationID(ctx),
+					PageLimit:      pageLimit,
+				})
+			},
+			repair: s.repairPrimaryBankAccount,
+		},
+		{
+			name:       IntegrityDentistWithoutActiveClinic,
+			entityType: AuditEntityDentist,
+			list: func(ctx context.Context, pageLimit int32) ([]string, error) {
+				return s.queries.ListDentistsWithoutActiveClinic(ctx, repository.ListDentistsWithoutActiveClinicParams{
+					OrganizationID: organizationID(ctx),
+					PageLimit:      pageLimit,
+				})
+			},
+		},
+		{
+			name:       IntegrityOrphanedPerson,
+			entityType: AuditEntityPerson,
+			list: func(ctx context.Context, pageLimit int32) ([]string, error) {
+				return s.queries.ListOrphanedPeople(ctx, repository.ListOrphanedPeopleParams{
+					OrganizationID: organizationID(ctx),
+					PageLimit:      pageLimit,
+				})
+			},
+			repair: s.repairOrphanedPerson,
+		},
+	}
+}
+
+func (s *Service) RunIntegrityChecks(ctx context.Context, repair bool) (IntegrityReportOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RunIntegrityChecks")
+	defer span.End()
+
+	report := IntegrityReportOutput{
+		CheckedAt: s.now().UTC(),
+		Repair:    repair,
+		Checks:    make([]IntegrityCheckOutput, 0, 4),
+	}
+	for _, check := range s.integrityChecks() {
+		ids, err := check.list(ctx, integrityFindingLimit+1)
+		if err != nil {
+			return IntegrityReportOutput{}, fmt.Errorf("run integrity check %s: %w", check.name, err)
+		}
+		truncated := len(ids) > integrityFindingLimit
+		if truncated {
+			ids = ids[:integrityFindingLimit]
+		}
+		if ids == nil {
+			ids = []string{}
+		}
+		result := IntegrityCheckOutput{
+			Check:       check.name,
+			EntityType:  check.entityType,
+			Repairable:  check.repair != nil,
+			Count:       len(ids),
+			Truncated:   truncated,
+			EntityIDs:   ids,
+			RepairedIDs: []string{},
+		}
+		if repair && check.repair != nil {
+			for _, id := range ids {
+				repaired, err := check.repair(ctx, id)
+				if err != nil {
+					return IntegrityReportOutput{}, fmt.Errorf("repair %s %s: %w", check.name, id, err)
+				}
+				if repaired {
+					result.RepairedIDs = append(result.RepairedIDs, id)
+				}
+			}
+		}
+		report.Checks = append(report.Checks, result)
+	}
+
+	recordIntegrityMetrics(ctx, report)
+	return report, nil
+}
+
+func (s *Service) repairPrimaryBankAccount(ctx context.Context, clinicID string) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, err
+	}
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return false, err
+	}
+	accountID, err := qtx.PromoteOldestBankAccountToPrimary(ctx, repository.PromoteOldestBankAccountToPrimaryParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		return false, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "integrity.primary_bank_account_assigned",
+		EntityType: AuditEntityBankAccount,
+		EntityID:   accountID,
+	}); err != nil {
+		return false, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+func (s *Service) repairOrphanedPerson(ctx context.Context, personID string) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	rows, err := qtx.DeleteOrphanedPerson(ctx, repository.DeleteOrphanedPersonParams{
+		OrganizationID: organizationID(ctx),
+		ID:             personID,
+	})
+	if err != nil {
+		return false, mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "integrity.orphaned_person_deleted",
+		EntityType: AuditEntityPerson,
+		EntityID:   personID,
+	}); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+func recordIntegrityMetrics(ctx context.Context, report IntegrityReportOutput) {
+	gauge, err := otel.Meter(serviceTracerName).Int64Gauge(
+		"capim.integrity.violation.count",
+		metric.WithDescription("Violacoes de integridade restantes apos a ultima verificacao"),
+	)
+	if err != nil {
+		slog.WarnContext(ctx, "create integrity violation gauge", "error", err)
+		return
+	}
+	for _, check := range report.Checks {
+		gauge.Record(ctx, int64(check.Count-len(check.RepairedIDs)), metric.WithAttributes(attribute.String("check", check.Check), attribute.String("organization_id", organizationID(ctx))))
+	}
+}
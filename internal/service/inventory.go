@@ -0,0 +1,471 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	InventoryPurchase    = "PURCHASE"
+	InventoryConsumption = "CONSUMPTION"
+	InventoryAdjustment  = "ADJUSTMENT"
+
+	inventoryConsumptionWindowDays = 30
+	initialInventoryCountNote      = "initial count"
+)
+
+func (s *Service) CreateInventoryItem(ctx context.Context, clinicID string, input CreateInventoryItemInput) (InventoryItemOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateInventoryItem")
+	defer span.End()
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return InventoryItemOutput{}, validationError("name is required")
+	}
+	unit := strings.TrimSpace(input.Unit)
+	if unit == "" {
+		return InventoryItemOutput{}, validationError("unit is required")
+	}
+	if input.MinimumQuantity < 0 {
+		return InventoryItemOutput{}, validationError("minimum_quantity must not be negative")
+	}
+	if input.InitialQuantity < 0 {
+		return InventoryItemOutput{}, validationError("initial_quantity must not be negative")
+	}
+	itemID, err := newUUIDV7()
+	if err != nil {
+		return InventoryItemOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return InventoryItemOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return InventoryItemOutput{}, notFoundError("clinic not found")
+		}
+		return InventoryItemOutput{}, err
+	}
+	item, err := qtx.CreateInventoryItem(ctx, repository.CreateInventoryItemParams{
+		ID:              itemID,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		Name:            name,
+		Sku:             optionalString(input.SKU),
+		Unit:            unit,
+		MinimumQuantity: input.MinimumQuantity,
+	})
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return InventoryItemOutput{}, conflictError("an inventory item with this name already exists at the clinic")
+		}
+		return InventoryItemOutput{}, mapDatabaseError(err)
+	}
+	// Stock on hand when the item is first registered is an adjustment like any later count, so the history adds up.
+	if input.InitialQuantity > 0 {
+		note := initialInventoryCountNote
+		if _, err := applyInventoryMovement(ctx, qtx, item, InventoryAdjustment, input.InitialQuantity, sql.NullInt64{}, &note); err != nil {
+			return InventoryItemOutput{}, err
+		}
+		item.Quantity = input.InitialQuantity
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return InventoryItemOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapInventoryItem(item), nil
+}
+
+func (s *Service) GetInventoryItem(ctx context.Context, clinicID string, itemID string) (InventoryItemOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetInventoryItem")
+	defer span.End()
+
+	item, err := s.queries.GetInventoryItem(ctx, repository.GetInventoryItemParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             itemID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return InventoryItemOutput{}, notFoundError("inventory item not found")
+		}
+		return InventoryItemOutput{}, err
+	}
+	return mapInventoryItem(item), nil
+}
+
+func (s *Service) ListInventoryItems(ctx context.Context, clinicID string, limit int, cursor *string) ([]InventoryItemOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListInventoryItems")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, notFoundError("clinic not found")
+		}
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListInventoryItemsCursorParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+
+	rows, err := s.queries.ListInventoryItemsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	items := make([]InventoryItemOutput, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, mapInventoryItem(row))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return items, nextCursor, nil
+}
+
+// UpdateInventoryItem changes how an item is described; its quantity only moves through movements.
+func (s *Service) UpdateInventoryItem(ctx context.Context, clinicID string, itemID string, input UpdateInventoryItemInput) (InventoryItemOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateInventoryItem")
+	defer span.End()
+
+	params := repository.UpdateInventoryItemParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             itemID,
+	}
+	if input.Name != nil {
+		if params.Name = optionalString(input.Name); !params.Name.Valid {
+			return InventoryItemOutput{}, validationError("name must not be empty")
+		}
+	}
+	if input.Unit != nil {
+		if params.Unit = optionalString(input.Unit); !params.Unit.Valid {
+			return InventoryItemOutput{}, validationError("unit must not be empty")
+		}
+	}
+	if input.SKU != nil {
+		// An empty sku clears it.
+		params.Sku = sql.NullString{String: strings.TrimSpace(*input.SKU), Valid: true}
+	}
+	if input.MinimumQuantity != nil {
+		if *input.MinimumQuantity < 0 {
+			return InventoryItemOutput{}, validationError("minimum_quantity must not be negative")
+		}
+		params.MinimumQuantity = sql.NullInt64{Int64: *input.MinimumQuantity, Valid: true}
+	}
+
+	item, err := s.queries.UpdateInventoryItem(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return InventoryItemOutput{}, notFoundError("inventory item not found")
+		}
+		if isUniqueConstraintError(err) {
+			return InventoryItemOutput{}, conflictError("an inventory item with this name already exists at the clinic")
+		}
+		return InventoryItemOutput{}, mapDatabaseError(err)
+	}
+	return mapInventoryItem(item), nil
+}
+
+func (s *Service) DeleteInventoryItem(ctx context.Context, clinicID string, itemID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteInventoryItem")
+	defer span.End()
+
+	deleted, err := s.queries.DeleteInventoryItem(ctx, repository.DeleteInventoryItemParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             itemID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return notFoundError("inventory item not found")
+	}
+	return nil
+}
+
+// RecordInventoryMovement changes the item's stock and writes the movement in one transaction, with the item row locked so
+// concurrent consumptions cannot both spend the last unit. For an adjustment, quantity is the counted stock, not a difference.
+func (s *Service) RecordInventoryMovement(ctx context.Context, clinicID string, itemID string, input CreateInventoryMovementInput) (InventoryMovementOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RecordInventoryMovement")
+	defer span.End()
+
+	kind := strings.ToUpper(strings.TrimSpace(input.Kind))
+	switch kind {
+	case InventoryPurchase, InventoryConsumption:
+		if input.Quantity <= 0 {
+			return InventoryMovementOutput{}, validationError("quantity must be greater than zero")
+		}
+	case InventoryAdjustment:
+		if input.Quantity < 0 {
+			return InventoryMovementOutput{}, validationError("quantity must not be negative")
+		}
+		if !optionalString(input.Notes).Valid {
+			return InventoryMovementOutput{}, validationError("notes are required for an adjustment")
+		}
+	default:
+		return InventoryMovementOutput{}, validationError(fmt.Sprintf("kind must be one of: %s, %s, %s", InventoryPurchase, InventoryConsumption, InventoryAdjustment))
+	}
+	var unitCost sql.NullInt64
+	if input.UnitCostCents != nil {
+		if kind != InventoryPurchase {
+			return InventoryMovementOutput{}, validationError("unit_cost_cents is only accepted for a purchase")
+		}
+		if *input.UnitCostCents < 0 {
+			return InventoryMovementOutput{}, validationError("unit_cost_cents must not be negative")
+		}
+		unitCost = sql.NullInt64{Int64: *input.UnitCostCents, Valid: true}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return InventoryMovementOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	item, err := qtx.LockInventoryItemForUpdate(ctx, repository.LockInventoryItemForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             itemID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return InventoryMovementOutput{}, notFoundError("inventory item not found")
+		}
+		return InventoryMovementOutput{}, err
+	}
+	movement, err := applyInventoryMovement(ctx, qtx, item, kind, input.Quantity, unitCost, input.Notes)
+	if err != nil {
+		return InventoryMovementOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return InventoryMovementOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapInventoryMovement(movement), nil
+}
+
+// applyInventoryMovement expects the item to be locked, or to have been created in the same transaction.
+func applyInventoryMovement(ctx context.Context, qtx repository.Querier, item repository.InventoryItem, kind string, quantity int64, unitCost sql.NullInt64, notes *string) (repository.InventoryMovement, error) {
+	var delta int64
+	switch kind {
+	case InventoryPurchase:
+		delta = quantity
+	case InventoryConsumption:
+		delta = -quantity
+	case InventoryAdjustment:
+		delta = quantity - item.Quantity
+	}
+	after := item.Quantity + delta
+	if after < 0 {
+		return repository.InventoryMovement{}, conflictError(fmt.Sprintf("insufficient stock: %d %s available", item.Quantity, item.Unit))
+	}
+	movementID, err := newUUIDV7()
+	if err != nil {
+		return repository.InventoryMovement{}, err
+	}
+
+	if err := qtx.SetInventoryItemQuantity(ctx, repository.SetInventoryItemQuantityParams{
+		OrganizationID: organizationID(ctx),
+		ID:             item.ID,
+		Quantity:       after,
+	}); err != nil {
+		return repository.InventoryMovement{}, mapDatabaseError(err)
+	}
+	movement, err := qtx.CreateInventoryMovement(ctx, repository.CreateInventoryMovementParams{
+		ID:              movementID,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        item.ClinicID,
+		ItemID:          item.ID,
+		Kind:            kind,
+		QuantityDelta:   delta,
+		QuantityAfter:   after,
+		UnitCostCents:   unitCost,
+		Notes:           optionalString(notes),
+		CreatedByUserID: auditActorID(ctx),
+	})
+	if err != nil {
+		return repository.InventoryMovement{}, mapDatabaseError(err)
+	}
+	return movement, nil
+}
+
+func (s *Service) ListInventoryMovements(ctx context.Context, clinicID string, itemID string, limit int, cursor *string, kind *string) ([]InventoryMovementOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListInventoryMovements")
+	defer span.End()
+
+	if _, err := s.GetInventoryItem(ctx, clinicID, itemID); err != nil {
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListInventoryMovementsCursorParams{
+		OrganizationID: organizationID(ctx),
+		ItemID:         itemID,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+	if kind != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*kind))
+		switch normalized {
+		case InventoryPurchase, InventoryConsumption, InventoryAdjustment:
+		default:
+			return nil, nil, validationError(fmt.Sprintf("kind must be one of: %s, %s, %s", InventoryPurchase, InventoryConsumption, InventoryAdjustment))
+		}
+		params.Kind = sql.NullString{String: normalized, Valid: true}
+	}
+
+	rows, err := s.queries.ListInventoryMovementsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	movements := make([]InventoryMovementOutput, 0, len(rows))
+	for _, row := range rows {
+		movements = append(movements, mapInventoryMovement(row))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return movements, nextCursor, nil
+}
+
+// GetLowStockReport lists the clinic's items at or below their minimum, the furthest below first, with how long the remaining
+// stock lasts at the last 30 days' consumption.
+func (s *Service) GetLowStockReport(ctx context.Context, clinicID string) (LowStockReportOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetLowStockReport")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LowStockReportOutput{}, notFoundError("clinic not found")
+		}
+		return LowStockReportOutput{}, err
+	}
+	rows, err := s.queries.ListLowStockInventoryItems(ctx, repository.ListLowStockInventoryItemsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		UsageSince:     s.now().Add(-inventoryConsumptionWindowDays * 24 * time.Hour),
+	})
+	if err != nil {
+		return LowStockReportOutput{}, err
+	}
+
+	report := LowStockReportOutput{
+		ClinicID:              clinicID,
+		ConsumptionWindowDays: inventoryConsumptionWindowDays,
+		Items:                 make([]LowStockItemOutput, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.Items = append(report.Items, mapLowStockItem(row))
+	}
+	return report, nil
+}
+
+func mapLowStockItem(row repository.ListLowStockInventoryItemsRow) LowStockItemOutput {
+	entry := LowStockItemOutput{
+		InventoryItemOutput: mapInventoryItem(repository.InventoryItem{
+			ID:              row.ID,
+			OrganizationID:  row.OrganizationID,
+			ClinicID:        row.ClinicID,
+			Name:            row.Name,
+			Sku:             row.Sku,
+			Unit:            row.Unit,
+			Quantity:        row.Quantity,
+			MinimumQuantity: row.MinimumQuantity,
+			CreatedAt:       row.CreatedAt,
+			UpdatedAt:       row.UpdatedAt,
+			DeletedAt:       row.DeletedAt,
+		}),
+		Shortfall:         row.MinimumQuantity - row.Quantity,
+		RecentConsumption: row.RecentConsumption,
+	}
+	if row.RecentConsumption > 0 {
+		days := float64(row.Quantity) / (float64(row.RecentConsumption) / inventoryConsumptionWindowDays)
+		entry.DaysOfStock = &days
+	}
+	return entry
+}
+
+func mapInventoryItem(item repository.InventoryItem) InventoryItemOutput {
+	return InventoryItemOutput{
+		ID:              item.ID,
+		ClinicID:        item.ClinicID,
+		Name:            item.Name,
+		SKU:             nullToPointer(item.Sku),
+		Unit:            item.Unit,
+		Quantity:        item.Quantity,
+		MinimumQuantity: item.MinimumQuantity,
+		LowStock:        item.Quantity <= item.MinimumQuantity,
+		CreatedAt:       item.CreatedAt,
+		UpdatedAt:       item.UpdatedAt,
+	}
+}
+
+func mapInventoryMovement(movement repository.InventoryMovement) InventoryMovementOutput {
+	output := InventoryMovementOutput{
+		ID:              movement.ID,
+		ItemID:          movement.ItemID,
+		Kind:            movement.Kind,
+		QuantityDelta:   movement.QuantityDelta,
+		QuantityAfter:   movement.QuantityAfter,
+		Notes:           nullToPointer(movement.Notes),
+		CreatedByUserID: nullUUIDToPointer(movement.CreatedByUserID),
+		CreatedAt:       movement.CreatedAt,
+	}
+	if movement.UnitCostCents.Valid {
+		unitCost := movement.UnitCostCents.Int64
+		output.UnitCostCents = &unitCost
+	}
+	return output
+}
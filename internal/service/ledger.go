@@ -0,0 +1,419 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	LedgerKindInvoice    = "INVOICE"
+	LedgerKindPayment    = "PAYMENT"
+	LedgerKindFee        = "FEE"
+	LedgerKindPayout     = "PAYOUT"
+	LedgerKindAdjustment = "ADJUSTMENT"
+
+	LedgerAccountClinicBalance       = "CLINIC_BALANCE"
+	LedgerAccountPlatformCash        = "PLATFORM_CASH"
+	LedgerAccountPlatformRevenue     = "PLATFORM_REVENUE"
+	LedgerAccountPlatformAdjustments = "PLATFORM_ADJUSTMENTS"
+
+	LedgerDebit  = "DEBIT"
+	LedgerCredit = "CREDIT"
+
+	AuditEntityLedgerTransaction = "LEDGER_TRANSACTION"
+
+	ledgerReferencePayable     = "CLINIC_PAYABLE"
+	ledgerReferencePayoutBatch = "PAYOUT_BATCH"
+
+	maxLedgerDescriptionLength = 255
+	maxLedgerRangeDays         = 366
+	defaultLedgerRangeDays     = 30
+	// maxLedgerAmountCents caps a manual entry at R$ 1 billion, far below where negating or summing amounts overflows int64.
+	maxLedgerAmountCents = 100_000_000_000
+)
+
+// Payments and payouts are posted by the payables and payout flows; only the others are recorded by hand.
+var manualLedgerKinds = []string{LedgerKindInvoice, LedgerKindFee, LedgerKindAdjustment}
+
+type ledgerEntry struct {
+	Account     string
+	Direction   string
+	AmountCents int64
+}
+
+type ledgerTransaction struct {
+	ClinicID      string
+	Kind          string
+	Description   string
+	ReferenceType string
+	ReferenceID   string
+	OccurredOn    time.Time
+	Entries       []ledgerEntry
+}
+
+func (s *Service) RecordClinicLedgerEntry(ctx context.Context, clinicID string, input CreateLedgerEntryInput) (LedgerTransactionOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RecordClinicLedgerEntry")
+	defer span.End()
+
+	kind := strings.ToUpper(strings.TrimSpace(input.Kind))
+	if !slices.Contains(manualLedgerKinds, kind) {
+		return LedgerTransactionOutput{}, validationError(fmt.Sprintf("kind must be one of: %s", strings.Join(manualLedgerKinds, ", ")))
+	}
+	// Adjustments are signed from the clinic's point of view; invoices and fees always reduce its balance.
+	clinicEffect := input.AmountCents
+	switch {
+	case kind == LedgerKindAdjustment && input.AmountCents == 0:
+		return LedgerTransactionOutput{}, validationError("amount_cents must not be zero")
+	case kind == LedgerKindAdjustment && (input.AmountCents < -maxLedgerAmountCents || input.AmountCents > maxLedgerAmountCents):
+		return LedgerTransactionOutput{}, validationError(fmt.Sprintf("amount_cents must be between -%d and %d", maxLedgerAmountCents, maxLedgerAmountCents))
+	case kind != LedgerKindAdjustment && (input.AmountCents <= 0 || input.AmountCents > maxLedgerAmountCents):
+		return LedgerTransactionOutput{}, validationError(fmt.Sprintf("amount_cents must be greater than zero and at most %d", maxLedgerAmountCents))
+	case kind != LedgerKindAdjustment:
+		clinicEffect = -input.AmountCents
+	}
+	description := strings.TrimSpace(input.Description)
+	if description == "" {
+		return LedgerTransactionOutput{}, validationError("description is required")
+	}
+	if err := validateMaxLength("description", description, maxLedgerDescriptionLength); err != nil {
+		return LedgerTransactionOutput{}, err
+	}
+	occurredOn, err := parseDocumentDate("occurred_on", &input.OccurredOn)
+	if err != nil {
+		return LedgerTransactionOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return LedgerTransactionOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	// A deactivated clinic cannot be billed, but fees and adjustments still settle what it already owes.
+	if kind == LedgerKindInvoice {
+		if err := ensureClinicOperational(ctx, qtx, clinicID); err != nil {
+			return LedgerTransactionOutput{}, err
+		}
+	} else if _, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LedgerTransactionOutput{}, notFoundError("clinic not found")
+		}
+		return LedgerTransactionOutput{}, err
+	}
+	transactionID, err := postLedgerTransaction(ctx, qtx, ledgerTransaction{
+		ClinicID:    clinicID,
+		Kind:        kind,
+		Description: description,
+		OccurredOn:  occurredOn.Time,
+		Entries:     clinicLedgerEntries(kind, clinicEffect),
+	})
+	if err != nil {
+		return LedgerTransactionOutput{}, err
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "ledger_transaction.recorded",
+		EntityType: AuditEntityLedgerTransaction,
+		EntityID:   transactionID,
+		Metadata:   map[string]any{"kind": kind, "amount_cents": clinicEffect},
+	}); err != nil {
+		return LedgerTransactionOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return LedgerTransactionOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	ledger, err := s.loadClinicLedger(ctx, clinicID, occurredOn.Time, occurredOn.Time)
+	if err != nil {
+		return LedgerTransactionOutput{}, err
+	}
+	for _, transaction := range ledger.Transactions {
+		if transaction.ID == transactionID {
+			return transaction, nil
+		}
+	}
+	return LedgerTransactionOutput{}, notFoundError("ledger transaction not found")
+}
+
+func (s *Service) GetClinicLedger(ctx context.Context, clinicID string, from *string, to *string) (LedgerOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicLedger")
+	defer span.End()
+
+	fromDate, toDate, err := s.ledgerRange(from, to)
+	if err != nil {
+		return LedgerOutput{}, err
+	}
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LedgerOutput{}, notFoundError("clinic not found")
+		}
+		return LedgerOutput{}, err
+	}
+	return s.loadClinicLedger(ctx, clinicID, fromDate, toDate)
+}
+
+// ledgerRange resolves the statement dates, covering the last defaultLedgerRangeDays up to today when they are omitted.
+func (s *Service) ledgerRange(from *string, to *string) (time.Time, time.Time, error) {
+	toDate := s.now().UTC().Truncate(24 * time.Hour)
+	if to != nil {
+		parsed, err := parseDocumentDate("to", to)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		toDate = parsed.Time
+	}
+	fromDate := toDate.AddDate(0, 0, -defaultLedgerRangeDays)
+	if from != nil {
+		parsed, err := parseDocumentDate("from", from)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+		fromDate = parsed.Time
+	}
+	if fromDate.After(toDate) {
+		return time.Time{}, time.Time{}, validationError("from must not be after to")
+	}
+	if toDate.Sub(fromDate) > maxLedgerRangeDays*24*time.Hour {
+		return time.Time{}, time.Time{}, validationError(fmt.Sprintf("ledger range must be at most %d days", maxLedgerRangeDays))
+	}
+	return fromDate, toDate, nil
+}
+
+// The statement is read from a single snapshot and checked against the stored balance before it is returned.
+func (s *Service) loadClinicLedger(ctx context.Context, clinicID string, from time.Time, to time.Time) (LedgerOutput, error) {
+	tx, err := s.db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return LedgerOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	opening, err := qtx.GetClinicLedgerBalance(ctx, repository.GetClinicLedgerBalanceParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		BeforeDate:     from,
+	})
+	if err != nil {
+		return LedgerOutput{}, err
+	}
+	transactions, err := qtx.ListLedgerTransactions(ctx, repository.ListLedgerTransactionsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		FromDate:       from,
+		ToDate:         to,
+	})
+	if err != nil {
+		return LedgerOutput{}, err
+	}
+	transactionIDs := make([]string, 0, len(transactions))
+	for _, transaction := range transactions {
+		transactionIDs = append(transactionIDs, transaction.ID)
+	}
+	entriesByTransaction := make(map[string][]repository.LedgerEntry, len(transactions))
+	if len(transactionIDs) > 0 {
+		entries, err := qtx.ListLedgerEntriesByTransactionIDs(ctx, repository.ListLedgerEntriesByTransactionIDsParams{
+			OrganizationID: organizationID(ctx),
+			TransactionIds: transactionIDs,
+		})
+		if err != nil {
+			return LedgerOutput{}, err
+		}
+		for _, entry := range entries {
+			entriesByTransaction[entry.TransactionID] = append(entriesByTransaction[entry.TransactionID], entry)
+		}
+	}
+	closing, err := qtx.GetClinicLedgerBalance(ctx, repository.GetClinicLedgerBalanceParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		BeforeDate:     to.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return LedgerOutput{}, err
+	}
+
+	ledger, err := buildLedger(transactions, entriesByTransaction, opening)
+	if err != nil {
+		return LedgerOutput{}, err
+	}
+	if ledger.ClosingBalanceCents != closing {
+		return LedgerOutput{}, fmt.Errorf("ledger for clinic %s is inconsistent: running balance %d, stored balance %d", clinicID, ledger.ClosingBalanceCents, closing)
+	}
+	ledger.ClinicID = clinicID
+	ledger.From = from.Format(documentDateLayout)
+	ledger.To = to.Format(documentDateLayout)
+	return ledger, nil
+}
+
+func buildLedger(transactions []repository.LedgerTransaction, entriesByTransaction map[string][]repository.LedgerEntry, opening int64) (LedgerOutput, error) {
+	ledger := LedgerOutput{
+		OpeningBalanceCents: opening,
+		Transactions:        make([]LedgerTransactionOutput, 0, len(transactions)),
+	}
+	balance := opening
+	for _, transaction := range transactions {
+		rows := entriesByTransaction[transaction.ID]
+		entries := make([]ledgerEntry, 0, len(rows))
+		outputs := make([]LedgerEntryOutput, 0, len(rows))
+		for _, row := range rows {
+			entries = append(entries, ledgerEntry{Account: row.Account, Direction: row.Direction, AmountCents: row.AmountCents})
+			outputs = append(outputs, LedgerEntryOutput{Account: row.Account, Direction: row.Direction, AmountCents: row.AmountCents})
+		}
+		if err := validateLedgerEntries(entries); err != nil {
+			return LedgerOutput{}, fmt.Errorf("ledger transaction %s: %w", transaction.ID, err)
+		}
+		effect := clinicBalanceEffect(entries)
+		balance += effect
+		ledger.Transactions = append(ledger.Transactions, LedgerTransactionOutput{
+			ID:              transaction.ID,
+			Kind:            transaction.Kind,
+			Description:     transaction.Description,
+			ReferenceType:   nullToPointer(transaction.ReferenceType),
+			ReferenceID:     nullUUIDToPointer(transaction.ReferenceID),
+			OccurredOn:      transaction.OccurredOn.Format(documentDateLayout),
+			AmountCents:     effect,
+			BalanceCents:    balance,
+			Entries:         outputs,
+			CreatedByUserID: nullUUIDToPointer(transaction.CreatedByUserID),
+			CreatedAt:       transaction.CreatedAt,
+		})
+	}
+	ledger.ClosingBalanceCents = balance
+	return ledger, nil
+}
+
+// Every posting goes through here so an unbalanced transaction never reaches the database.
+func postLedgerTransaction(ctx context.Context, q repository.Querier, transaction ledgerTransaction) (string, error) {
+	if err := validateLedgerEntries(transaction.Entries); err != nil {
+		return "", fmt.Errorf("post ledger transaction: %w", err)
+	}
+	transactionID, err := newUUIDV7()
+	if err != nil {
+		return "", err
+	}
+
+	params := repository.CreateLedgerTransactionParams{
+		OrganizationID: organizationID(ctx),
+		ID:             transactionID,
+		ClinicID:       transaction.ClinicID,
+		Kind:           transaction.Kind,
+		Description:    transaction.Description,
+		OccurredOn:     transaction.OccurredOn,
+	}
+	if transaction.ReferenceID != "" {
+		parsed, err := uuid.Parse(transaction.ReferenceID)
+		if err != nil {
+			return "", fmt.Errorf("post ledger transaction: invalid reference id: %w", err)
+		}
+		params.ReferenceType = sql.NullString{String: transaction.ReferenceType, Valid: true}
+		params.ReferenceID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if parsed, err := uuid.Parse(principal.UserID); err == nil {
+			params.CreatedByUserID = uuid.NullUUID{UUID: parsed, Valid: true}
+		}
+	}
+	if err := q.CreateLedgerTransaction(ctx, params); err != nil {
+		if isUniqueConstraintError(err) {
+			return "", conflictError("ledger transaction already recorded")
+		}
+		return "", mapDatabaseError(err)
+	}
+	for idx, entry := range transaction.Entries {
+		if err := q.CreateLedgerEntry(ctx, repository.CreateLedgerEntryParams{
+			OrganizationID: organizationID(ctx),
+			TransactionID:  transactionID,
+			Line:           int16(idx + 1),
+			Account:        entry.Account,
+			Direction:      entry.Direction,
+			AmountCents:    entry.AmountCents,
+		}); err != nil {
+			return "", mapDatabaseError(err)
+		}
+	}
+	return transactionID, nil
+}
+
+func validateLedgerEntries(entries []ledgerEntry) error {
+	if len(entries) < 2 {
+		return errors.New("a ledger transaction needs at least two entries")
+	}
+	var debits, credits int64
+	touchesClinic := false
+	for _, entry := range entries {
+		if entry.AmountCents <= 0 {
+			return errors.New("ledger entries must have a positive amount")
+		}
+		switch entry.Direction {
+		case LedgerDebit:
+			debits += entry.AmountCents
+		case LedgerCredit:
+			credits += entry.AmountCents
+		default:
+			return fmt.Errorf("unknown ledger direction %q", entry.Direction)
+		}
+		touchesClinic = touchesClinic || entry.Account == LedgerAccountClinicBalance
+	}
+	if debits != credits {
+		return fmt.Errorf("debits (%d) and credits (%d) do not balance", debits, credits)
+	}
+	if !touchesClinic {
+		return errors.New("a ledger transaction must affect the clinic balance")
+	}
+	return nil
+}
+
+// A positive effect means the platform owes the clinic more; the counter account absorbs the other side.
+func clinicLedgerEntries(kind string, clinicEffect int64) []ledgerEntry {
+	counterAccount := LedgerAccountPlatformAdjustments
+	switch kind {
+	case LedgerKindPayment, LedgerKindPayout:
+		counterAccount = LedgerAccountPlatformCash
+	case LedgerKindInvoice, LedgerKindFee:
+		counterAccount = LedgerAccountPlatformRevenue
+	}
+	if clinicEffect >= 0 {
+		return []ledgerEntry{
+			{Account: counterAccount, Direction: LedgerDebit, AmountCents: clinicEffect},
+			{Account: LedgerAccountClinicBalance, Direction: LedgerCredit, AmountCents: clinicEffect},
+		}
+	}
+	return []ledgerEntry{
+		{Account: LedgerAccountClinicBalance, Direction: LedgerDebit, AmountCents: -clinicEffect},
+		{Account: counterAccount, Direction: LedgerCredit, AmountCents: -clinicEffect},
+	}
+}
+
+func clinicBalanceEffect(entries []ledgerEntry) int64 {
+	var effect int64
+	for _, entry := range entries {
+		if entry.Account != LedgerAccountClinicBalance {
+			continue
+		}
+		if entry.Direction == LedgerCredit {
+			effect += entry.AmountCents
+		} else {
+			effect -= entry.AmountCents
+		}
+	}
+	return effect
+}
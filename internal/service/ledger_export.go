@@ -0,0 +1,201 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/ofx"
+)
+
+const (
+	LedgerExportFormatCSV  = "CSV"
+	LedgerExportFormatSPED = "SPED"
+	LedgerExportFormatOFX  = "OFX"
+
+	// ledgerOFXBankID stands in for the bank code: the statement is the clinic's balance held by the platform.
+	ledgerOFXBankID = "CAPIM"
+)
+
+var (
+	ledgerExportFormats  = []string{LedgerExportFormatCSV, LedgerExportFormatSPED, LedgerExportFormatOFX}
+	ledgerJournalColumns = []string{"date", "transaction_id", "kind", "description", "reference_type", "reference_id", "account", "debit", "credit"}
+)
+
+// ExportClinicLedger writes one month of the clinic's ledger for its accountant: the double-entry journal as CSV or
+// as SPED-style I200/I250 records, or the clinic balance as an OFX statement for bank reconciliation. The month
+// defaults to the previous one, the last that is closed.
+func (s *Service) ExportClinicLedger(ctx context.Context, clinicID string, month *string, format *string) (LedgerFileOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ExportClinicLedger")
+	defer span.End()
+
+	exportFormat := LedgerExportFormatCSV
+	if format != nil {
+		exportFormat = strings.ToUpper(strings.TrimSpace(*format))
+	}
+	if !slices.Contains(ledgerExportFormats, exportFormat) {
+		return LedgerFileOutput{}, validationError("format must be one of " + strings.Join(ledgerExportFormats, ", "))
+	}
+	start := s.now().UTC()
+	start = time.Date(start.Year(), start.Month()-1, 1, 0, 0, 0, 0, time.UTC)
+	if month != nil {
+		parsed, err := time.Parse(timesheetMonthLayout, strings.TrimSpace(*month))
+		if err != nil {
+			return LedgerFileOutput{}, validationError("month must be in YYYY-MM format")
+		}
+		start = parsed
+	}
+	from := start.Format(documentDateLayout)
+	to := start.AddDate(0, 1, -1).Format(documentDateLayout)
+
+	ledger, err := s.GetClinicLedger(ctx, clinicID, &from, &to)
+	if err != nil {
+		return LedgerFileOutput{}, err
+	}
+
+	fileName := "ledger-" + clinicID + "-" + start.Format(timesheetMonthLayout)
+	switch exportFormat {
+	case LedgerExportFormatSPED:
+		return LedgerFileOutput{
+			FileName:    fileName + "-sped.txt",
+			ContentType: "text/plain; charset=utf-8",
+			Content:     ledgerSPEDRecords(ledger),
+		}, nil
+	case LedgerExportFormatOFX:
+		content, err := ledgerOFXStatement(ledger, s.now())
+		if err != nil {
+			return LedgerFileOutput{}, err
+		}
+		return LedgerFileOutput{
+			FileName:    fileName + ".ofx",
+			ContentType: "application/x-ofx",
+			Content:     content,
+		}, nil
+	}
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	records := append([][]string{ledgerJournalColumns}, ledgerJournalRecords(ledger)...)
+	if err := writer.WriteAll(records); err != nil {
+		return LedgerFileOutput{}, fmt.Errorf("write ledger journal: %w", err)
+	}
+	return LedgerFileOutput{
+		FileName:    fileName + ".csv",
+		ContentType: "text/csv; charset=utf-8",
+		Content:     buffer.Bytes(),
+	}, nil
+}
+
+// ledgerJournalRecords has one row per entry, so every transaction's debit and credit columns add up to the same value.
+func ledgerJournalRecords(ledger LedgerOutput) [][]string {
+	var records [][]string
+	for _, transaction := range ledger.Transactions {
+		for _, entry := range transaction.Entries {
+			debit, credit := "", ""
+			if entry.Direction == LedgerDebit {
+				debit = ledgerDecimal(entry.AmountCents, ".")
+			} else {
+				credit = ledgerDecimal(entry.AmountCents, ".")
+			}
+			records = append(records, []string{
+				transaction.OccurredOn,
+				transaction.ID,
+				transaction.Kind,
+				transaction.Description,
+				derefString(transaction.ReferenceType),
+				derefString(transaction.ReferenceID),
+				entry.Account,
+				debit,
+				credit,
+			})
+		}
+	}
+	return records
+}
+
+// ledgerSPEDRecords follows the ECD journal records: an I200 per transaction and an I250 per entry, pipe-delimited,
+// dates as DDMMYYYY and values with a decimal comma. Account codes are the ledger account names.
+func ledgerSPEDRecords(ledger LedgerOutput) []byte {
+	var buffer bytes.Buffer
+	for _, transaction := range ledger.Transactions {
+		occurredOn, _ := time.Parse(documentDateLayout, transaction.OccurredOn)
+		var total int64
+		for _, entry := range transaction.Entries {
+			if entry.Direction == LedgerDebit {
+				total += entry.AmountCents
+			}
+		}
+		history := transaction.Kind
+		if description := spedField(transaction.Description); description != "" {
+			history += " - " + description
+		}
+		writeSPEDRecord(&buffer, "I200", transaction.ID, occurredOn.Format("02012006"), ledgerDecimal(total, ","), "N")
+		for _, entry := range transaction.Entries {
+			direction := "C"
+			if entry.Direction == LedgerDebit {
+				direction = "D"
+			}
+			writeSPEDRecord(&buffer, "I250", entry.Account, "", ledgerDecimal(entry.AmountCents, ","), direction, derefString(transaction.ReferenceID), "", history, "")
+		}
+	}
+	return buffer.Bytes()
+}
+
+func writeSPEDRecord(buffer *bytes.Buffer, fields ...string) {
+	buffer.WriteString("|" + strings.Join(fields, "|") + "|\r\n")
+}
+
+// spedField drops the pipes and line breaks the layout reserves as separators.
+func spedField(value string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(value, "|", " ")), " ")
+}
+
+func ledgerOFXStatement(ledger LedgerOutput, generatedAt time.Time) ([]byte, error) {
+	from, err := time.Parse(documentDateLayout, ledger.From)
+	if err != nil {
+		return nil, fmt.Errorf("parse ledger start: %w", err)
+	}
+	to, err := time.Parse(documentDateLayout, ledger.To)
+	if err != nil {
+		return nil, fmt.Errorf("parse ledger end: %w", err)
+	}
+	statement := ofx.Statement{
+		BankID:              ledgerOFXBankID,
+		AccountID:           ledger.ClinicID,
+		GeneratedAt:         generatedAt,
+		From:                from,
+		To:                  to,
+		ClosingBalanceCents: ledger.ClosingBalanceCents,
+		Transactions:        make([]ofx.Transaction, 0, len(ledger.Transactions)),
+	}
+	for _, transaction := range ledger.Transactions {
+		postedOn, err := time.Parse(documentDateLayout, transaction.OccurredOn)
+		if err != nil {
+			return nil, fmt.Errorf("parse ledger transaction %s date: %w", transaction.ID, err)
+		}
+		statement.Transactions = append(statement.Transactions, ofx.Transaction{
+			ID:          transaction.ID,
+			PostedOn:    postedOn,
+			AmountCents: transaction.AmountCents,
+			Name:        transaction.Kind,
+			Memo:        transaction.Description,
+		})
+	}
+	return ofx.Encode(statement)
+}
+
+// ledgerDecimal writes cents as reais without grouping, as in 1234.56, with the given decimal separator.
+func ledgerDecimal(cents int64, separator string) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d%s%02d", sign, cents/100, separator, cents%100)
+}
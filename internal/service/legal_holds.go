@@ -0,0 +1,250 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	LegalHoldResourceClinic   = "CLINIC"
+	LegalHoldResourceDentist  = "DENTIST"
+	LegalHoldResourceDocument = "GENERATED_DOCUMENT"
+
+	AuditEntityLegalHold = "LEGAL_HOLD"
+
+	legalHoldActiveUnique = "idx_legal_holds_active_unique"
+)
+
+var legalHoldResourceTypes = []string{LegalHoldResourceClinic, LegalHoldResourceDentist, LegalHoldResourceDocument}
+
+var legalHoldResourceNotFound = map[string]string{
+	LegalHoldResourceClinic:   "clinic not found",
+	LegalHoldResourceDentist:  "dentist not found",
+	LegalHoldResourceDocument: "document not found",
+}
+
+func (s *Service) PlaceLegalHold(ctx context.Context, input PlaceLegalHoldInput) (LegalHoldOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.PlaceLegalHold")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return LegalHoldOutput{}, unauthorizedError("missing authenticated user")
+	}
+	resourceType := strings.ToUpper(strings.TrimSpace(input.ResourceType))
+	if !slices.Contains(legalHoldResourceTypes, resourceType) {
+		return LegalHoldOutput{}, validationError(fmt.Sprintf("resource_type must be one of: %s", strings.Join(legalHoldResourceTypes, ", ")))
+	}
+	resourceID, err := uuid.Parse(strings.TrimSpace(input.ResourceID))
+	if err != nil {
+		return LegalHoldOutput{}, validationError("resource_id must be a valid UUID")
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return LegalHoldOutput{}, validationError("reason is required")
+	}
+	if err := validateOptionalMaxLength("case_reference", input.CaseReference, 255); err != nil {
+		return LegalHoldOutput{}, err
+	}
+	holdID, err := newUUIDV7()
+	if err != nil {
+		return LegalHoldOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return LegalHoldOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	clinicID, err := qtx.GetLegalHoldResourceClinic(ctx, repository.GetLegalHoldResourceClinicParams{
+		OrganizationID: organizationID(ctx),
+		ResourceType:   resourceType,
+		ResourceID:     resourceID.String(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LegalHoldOutput{}, notFoundError(legalHoldResourceNotFound[resourceType])
+		}
+		return LegalHoldOutput{}, err
+	}
+	if err := qtx.CreateLegalHold(ctx, repository.CreateLegalHoldParams{
+		ID:             holdID,
+		OrganizationID: organizationID(ctx),
+		ResourceType:   resourceType,
+		ResourceID:     resourceID.String(),
+		Reason:         reason,
+		CaseReference:  optionalString(input.CaseReference),
+		PlacedByUserID: principal.UserID,
+	}); err != nil {
+		if isConstraintViolation(err, legalHoldActiveUnique) {
+			return LegalHoldOutput{}, conflictError("resource already has an active legal hold")
+		}
+		return LegalHoldOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "legal_hold.placed",
+		EntityType: AuditEntityLegalHold,
+		EntityID:   holdID,
+		Metadata:   map[string]any{"resource_type": resourceType, "resource_id": resourceID.String()},
+	}); err != nil {
+		return LegalHoldOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return LegalHoldOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.loadLegalHold(ctx, holdID)
+}
+
+func (s *Service) ListLegalHolds(ctx context.Context, resourceType *string, resourceID *string, active *bool) ([]LegalHoldOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListLegalHolds")
+	defer span.End()
+
+	params := repository.ListLegalHoldsParams{OrganizationID: organizationID(ctx)}
+	if resourceType != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*resourceType))
+		if !slices.Contains(legalHoldResourceTypes, normalized) {
+			return nil, validationError(fmt.Sprintf("resource_type must be one of: %s", strings.Join(legalHoldResourceTypes, ", ")))
+		}
+		params.ResourceType = sql.NullString{String: normalized, Valid: true}
+	}
+	if resourceID != nil {
+		parsed, err := uuid.Parse(strings.TrimSpace(*resourceID))
+		if err != nil {
+			return nil, validationError("resource_id must be a valid UUID")
+		}
+		params.ResourceID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+	if active != nil {
+		params.Active = sql.NullBool{Bool: *active, Valid: true}
+	}
+	rows, err := s.queries.ListLegalHolds(ctx, params)
+	if err != nil {
+		return nil, err
+	}
+	holds := make([]LegalHoldOutput, 0, len(rows))
+	for _, row := range rows {
+		holds = append(holds, mapLegalHold(repository.GetLegalHoldRow(row)))
+	}
+	return holds, nil
+}
+
+func (s *Service) GetLegalHold(ctx context.Context, holdID string) (LegalHoldOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetLegalHold")
+	defer span.End()
+
+	return s.loadLegalHold(ctx, holdID)
+}
+
+func (s *Service) ReleaseLegalHold(ctx context.Context, holdID string, input ReleaseLegalHoldInput) (LegalHoldOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ReleaseLegalHold")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return LegalHoldOutput{}, unauthorizedError("missing authenticated user")
+	}
+	notes := optionalString(input.Notes)
+	if !notes.Valid {
+		return LegalHoldOutput{}, validationError("notes are required to release a legal hold")
+	}
+	if err := validateOptionalMaxLength("notes", input.Notes, 1000); err != nil {
+		return LegalHoldOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return LegalHoldOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	hold, err := qtx.GetLegalHold(ctx, repository.GetLegalHoldParams{
+		OrganizationID: organizationID(ctx),
+		ID:             holdID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LegalHoldOutput{}, notFoundError("legal hold not found")
+		}
+		return LegalHoldOutput{}, err
+	}
+	updated, err := qtx.ReleaseLegalHold(ctx, repository.ReleaseLegalHoldParams{
+		OrganizationID:   organizationID(ctx),
+		ID:               holdID,
+		ReleasedByUserID: principal.UserID,
+		ReleaseNotes:     notes,
+	})
+	if err != nil {
+		return LegalHoldOutput{}, mapDatabaseError(err)
+	}
+	if updated == 0 {
+		return LegalHoldOutput{}, conflictError("legal hold is already released")
+	}
+	clinicID, err := qtx.GetLegalHoldResourceClinic(ctx, repository.GetLegalHoldResourceClinicParams{
+		OrganizationID: organizationID(ctx),
+		ResourceType:   hold.ResourceType,
+		ResourceID:     hold.ResourceID,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return LegalHoldOutput{}, err
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "legal_hold.released",
+		EntityType: AuditEntityLegalHold,
+		EntityID:   holdID,
+		Metadata:   map[string]any{"resource_type": hold.ResourceType, "resource_id": hold.ResourceID},
+	}); err != nil {
+		return LegalHoldOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return LegalHoldOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.loadLegalHold(ctx, holdID)
+}
+
+func (s *Service) loadLegalHold(ctx context.Context, holdID string) (LegalHoldOutput, error) {
+	hold, err := s.queries.GetLegalHold(ctx, repository.GetLegalHoldParams{
+		OrganizationID: organizationID(ctx),
+		ID:             holdID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LegalHoldOutput{}, notFoundError("legal hold not found")
+		}
+		return LegalHoldOutput{}, err
+	}
+	return mapLegalHold(hold), nil
+}
+
+func mapLegalHold(hold repository.GetLegalHoldRow) LegalHoldOutput {
+	return LegalHoldOutput{
+		ID:               hold.ID,
+		ResourceType:     hold.ResourceType,
+		ResourceID:       hold.ResourceID,
+		Reason:           hold.Reason,
+		CaseReference:    nullToPointer(hold.CaseReference),
+		Active:           !hold.ReleasedAt.Valid,
+		PlacedByUserID:   hold.PlacedByUserID,
+		PlacedByEmail:    hold.PlacedByEmail,
+		PlacedAt:         hold.PlacedAt,
+		ReleasedByUserID: nullUUIDToPointer(hold.ReleasedByUserID),
+		ReleasedByEmail:  nullToPointer(hold.ReleasedByEmail),
+		ReleasedAt:       nullTimeToPointer(hold.ReleasedAt),
+		ReleaseNotes:     nullToPointer(hold.ReleaseNotes),
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import "time"
+
+type nationalHoliday struct {
+	date time.Time
+	name string
+}
+
+func brazilianNationalHolidays(year int) []nationalHoliday {
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	easter := easterSunday(year)
+
+	holidays := []nationalHoliday{
+		{date: date(time.January, 1), name: "Confraternização Universal"},
+		{date: easter.AddDate(0, 0, -48), name: "Carnaval"},
+		{date: easter.AddDate(0, 0, -47), name: "Carnaval"},
+		{date: easter.AddDate(0, 0, -2), name: "Sexta-feira Santa"},
+		{date: date(time.April, 21), name: "Tiradentes"},
+		{date: date(time.May, 1), name: "Dia do Trabalho"},
+		{date: easter.AddDate(0, 0, 60), name: "Corpus Christi"},
+		{date: date(time.September, 7), name: "Independência do Brasil"},
+		{date: date(time.October, 12), name: "Nossa Senhora Aparecida"},
+		{date: date(time.November, 2), name: "Finados"},
+		{date: date(time.November, 15), name: "Proclamação da República"},
+		{date: date(time.December, 25), name: "Natal"},
+	}
+	if year >= 2024 {
+		holidays = append(holidays, nationalHoliday{date: date(time.November, 20), name: "Dia Nacional de Zumbi e da Consciência Negra"})
+	}
+	return holidays
+}
+
+func easterSunday(year int) time.Time {
+	// Anonymous Gregorian algorithm (Meeus/Jones/Butcher).
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
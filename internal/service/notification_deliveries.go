@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	NotificationDelivered = "DELIVERED"
+	NotificationBounced   = "BOUNCED"
+
+	NotificationBounceHard = "HARD"
+	NotificationBounceSoft = "SOFT"
+
+	defaultDeliverabilityRangeDays = 30
+	maxDeliverabilityRangeDays     = 366
+)
+
+// WithNotificationCallbackSecret accepts delivery callbacks signed with secret. Without one every callback is refused.
+func WithNotificationCallbackSecret(secret string) Option {
+	return func(s *Service) {
+		s.notificationSecret = []byte(strings.TrimSpace(secret))
+	}
+}
+
+func (s *Service) VerifyNotificationCallbackSignature(body []byte, signature string) error {
+	if len(s.notificationSecret) == 0 {
+		return unauthorizedError("notification delivery callbacks are not configured")
+	}
+	return verifyCallbackSignature(s.notificationSecret, body, signature)
+}
+
+// RecordNotificationDelivery stores what the provider reported for a sent message. A hard bounce also suppresses the recipient on
+// that channel, so nothing else is sent to an address that does not exist.
+func (s *Service) RecordNotificationDelivery(ctx context.Context, input NotificationDeliveryCallbackInput) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RecordNotificationDelivery")
+	defer span.End()
+
+	notificationID, err := uuid.Parse(strings.TrimSpace(input.NotificationID))
+	if err != nil {
+		return validationError("notification_id must be a valid UUID")
+	}
+	status := strings.ToUpper(strings.TrimSpace(input.Status))
+	var bounceType sql.NullString
+	switch status {
+	case NotificationDelivered, NotificationFailed:
+	case NotificationBounced:
+		// Providers that do not classify bounces report permanent ones; a soft bounce has to say so.
+		bounceType = sql.NullString{String: NotificationBounceHard, Valid: true}
+		if input.BounceType != nil {
+			bounceType.String = strings.ToUpper(strings.TrimSpace(*input.BounceType))
+		}
+		if bounceType.String != NotificationBounceHard && bounceType.String != NotificationBounceSoft {
+			return validationError(fmt.Sprintf("bounce_type must be one of: %s, %s", NotificationBounceHard, NotificationBounceSoft))
+		}
+	default:
+		return validationError(fmt.Sprintf("status must be one of: %s, %s, %s", NotificationDelivered, NotificationBounced, NotificationFailed))
+	}
+
+	notification, err := findInTenantSchemas(ctx, s, func(ctx context.Context) (repository.Notification, error) {
+		return s.queries.GetNotificationForDeliveryCallback(ctx, notificationID.String())
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("notification not found")
+		}
+		return err
+	}
+	// The callback is unauthenticated; the notification alone identifies the tenant.
+	ctx = WithOrganization(ctx, notification.OrganizationID)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	// A hard bounce or failure is final, so a late "delivered" from a provider retrying its callbacks leaves it in place.
+	updated, err := qtx.RecordNotificationDelivery(ctx, repository.RecordNotificationDeliveryParams{
+		OrganizationID: organizationID(ctx),
+		ID:             notification.ID,
+		DeliveryStatus: status,
+		BounceType:     bounceType,
+		DeliveryDetail: optionalString(input.Reason),
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if updated > 0 && status == NotificationBounced && bounceType.String == NotificationBounceHard {
+		if err := suppressNotificationRecipient(ctx, qtx, notification); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func suppressNotificationRecipient(ctx context.Context, qtx repository.Querier, notification repository.Notification) error {
+	id, err := newUUIDV7()
+	if err != nil {
+		return err
+	}
+	recipient := suppressionRecipient(notification.Channel, notification.Recipient)
+	if err := qtx.CreateNotificationSuppression(ctx, repository.CreateNotificationSuppressionParams{
+		ID:             id,
+		OrganizationID: organizationID(ctx),
+		Channel:        notification.Channel,
+		Recipient:      recipient,
+		Reason:         "hard_bounce",
+		NotificationID: uuid.NullUUID{UUID: uuid.MustParse(notification.ID), Valid: true},
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	// Messages already queued for the address would bounce the same way.
+	dropped, err := qtx.FailPendingNotificationsForRecipient(ctx, repository.FailPendingNotificationsForRecipientParams{
+		OrganizationID: organizationID(ctx),
+		Channel:        notification.Channel,
+		Recipient:      recipient,
+		LastError:      sql.NullString{String: "recipient suppressed after a hard bounce", Valid: true},
+	})
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "notification recipient suppressed",
+		"notification_id", notification.ID,
+		"channel", notification.Channel,
+		"dropped_pending", dropped,
+	)
+	return nil
+}
+
+// suppressionRecipient compares email addresses case-insensitively; phone numbers are already stored normalized.
+func suppressionRecipient(channel string, recipient string) string {
+	recipient = strings.TrimSpace(recipient)
+	if channel == NotificationChannelEmail {
+		return strings.ToLower(recipient)
+	}
+	return recipient
+}
+
+func (s *Service) ListNotificationSuppressions(ctx context.Context, limit int, cursor *string, channel *string) ([]NotificationSuppressionOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListNotificationSuppressions")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListNotificationSuppressionsCursorParams{
+		OrganizationID: organizationID(ctx),
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+	if channel != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*channel))
+		switch normalized {
+		case NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWhatsApp:
+		default:
+			return nil, nil, validationError(fmt.Sprintf("channel must be one of: %s, %s, %s", NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWhatsApp))
+		}
+		params.Channel = sql.NullString{String: normalized, Valid: true}
+	}
+
+	rows, err := s.queries.ListNotificationSuppressionsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	suppressions := make([]NotificationSuppressionOutput, 0, len(rows))
+	for _, row := range rows {
+		suppressions = append(suppressions, NotificationSuppressionOutput{
+			ID:             row.ID,
+			Channel:        row.Channel,
+			Recipient:      row.Recipient,
+			Reason:         row.Reason,
+			NotificationID: nullUUIDToPointer(row.NotificationID),
+			CreatedAt:      row.CreatedAt,
+		})
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return suppressions, nextCursor, nil
+}
+
+// DeleteNotificationSuppression lets messages reach the recipient again, once the clinic has fixed the address.
+func (s *Service) DeleteNotificationSuppression(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteNotificationSuppression")
+	defer span.End()
+
+	deleted, err := s.queries.DeleteNotificationSuppression(ctx, repository.DeleteNotificationSuppressionParams{
+		OrganizationID: organizationID(ctx),
+		ID:             id,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return notFoundError("notification suppression not found")
+	}
+	return nil
+}
+
+// GetClinicDeliverability counts the clinic's notifications per channel by outcome, for messages created between from and to inclusive.
+func (s *Service) GetClinicDeliverability(ctx context.Context, clinicID string, from *string, to *string) (ClinicDeliverabilityOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicDeliverability")
+	defer span.End()
+
+	toDate := s.now().UTC().Truncate(24 * time.Hour)
+	if to != nil {
+		parsed, err := parseDocumentDate("to", to)
+		if err != nil {
+			return ClinicDeliverabilityOutput{}, err
+		}
+		toDate = parsed.Time
+	}
+	fromDate := toDate.AddDate(0, 0, -defaultDeliverabilityRangeDays)
+	if from != nil {
+		parsed, err := parseDocumentDate("from", from)
+		if err != nil {
+			return ClinicDeliverabilityOutput{}, err
+		}
+		fromDate = parsed.Time
+	}
+	if fromDate.After(toDate) {
+		return ClinicDeliverabilityOutput{}, validationError("from must not be after to")
+	}
+	if toDate.Sub(fromDate) > maxDeliverabilityRangeDays*24*time.Hour {
+		return ClinicDeliverabilityOutput{}, validationError(fmt.Sprintf("deliverability range must be at most %d days", maxDeliverabilityRangeDays))
+	}
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicDeliverabilityOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicDeliverabilityOutput{}, err
+	}
+	rows, err := s.queries.GetClinicNotificationDeliverability(ctx, repository.GetClinicNotificationDeliverabilityParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		FromTime:       fromDate,
+		ToTime:         toDate.AddDate(0, 0, 1),
+	})
+	if err != nil {
+		return ClinicDeliverabilityOutput{}, err
+	}
+
+	output := ClinicDeliverabilityOutput{
+		ClinicID: clinicID,
+		From:     fromDate.Format(documentDateLayout),
+		To:       toDate.Format(documentDateLayout),
+		Channels: make([]ChannelDeliverability, 0, len(rows)),
+	}
+	for _, row := range rows {
+		channel := ChannelDeliverability{
+			Channel:     row.Channel,
+			Total:       row.Total,
+			Pending:     row.Pending,
+			Sent:        row.Sent,
+			Delivered:   row.Delivered,
+			HardBounced: row.HardBounced,
+			SoftBounced: row.SoftBounced,
+			Failed:      row.Failed,
+		}
+		if row.Sent > 0 {
+			channel.BounceRate = float64(row.HardBounced+row.SoftBounced) / float64(row.Sent)
+		}
+		output.Channels = append(output.Channels, channel)
+	}
+	return output, nil
+}
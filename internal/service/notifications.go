@@ -0,0 +1,428 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	NotificationChannelEmail    = "EMAIL"
+	NotificationChannelSMS      = "SMS"
+	NotificationChannelWhatsApp = "WHATSAPP"
+
+	NotificationPending = "PENDING"
+	NotificationSent    = "SENT"
+	NotificationFailed  = "FAILED"
+
+	NotificationTemplatePayoutReceipt      = "payout_receipt"
+	NotificationTemplateClinicAnnouncement = "clinic_announcement"
+	NotificationTemplateMaintenanceOverdue = "equipment_maintenance_overdue"
+	NotificationTemplateAttachmentInfected = "attachment_infected"
+	NotificationTemplateScheduledReport    = "scheduled_report"
+
+	notificationDeliveryPage   = 50
+	notificationLease          = 5 * time.Minute
+	notificationRetryBase      = time.Minute
+	notificationRetryMax       = 6 * time.Hour
+	notificationMaxAttempts    = 8
+	maxNotificationErrorLength = 500
+)
+
+// NotificationMessage is a rendered message ready for a channel adapter.
+type NotificationMessage struct {
+	ID        string
+	Channel   string
+	Recipient string
+	Subject   string
+	Body      string
+}
+
+// NotificationSender delivers messages over one channel. A message whose outcome is unknown is sent again with the same ID, so
+// adapters should hand the ID to the provider as an idempotency key where it has one.
+type NotificationSender interface {
+	Send(ctx context.Context, message NotificationMessage) error
+}
+
+// WithNotificationSender delivers the channel's notifications through sender. Features only enqueue messages for channels with a
+// sender, so a deployment picks its channels by configuring adapters.
+func WithNotificationSender(channel string, sender NotificationSender) Option {
+	return func(s *Service) {
+		if s.notificationSenders == nil {
+			s.notificationSenders = make(map[string]NotificationSender)
+		}
+		s.notificationSenders[channel] = sender
+	}
+}
+
+type notificationTemplate struct {
+	subject *template.Template
+	bodies  map[string]*template.Template
+}
+
+var notificationTemplates = map[string]notificationTemplate{
+	NotificationTemplatePayoutReceipt: {
+		subject: template.Must(template.New("subject").Parse(`Comprovante de repasse nº {{.FileSequence}}`)),
+		bodies: map[string]*template.Template{
+			NotificationChannelEmail: template.Must(template.New(NotificationChannelEmail).Parse(`Olá, {{.ClinicName}}.
+
+O repasse nº {{.FileSequence}} foi liquidado em {{.PaymentDate}}.
+
+Valor: {{.Amount}}
+Recebíveis incluídos: {{.PayableCount}}
+
+O valor foi creditado na conta bancária principal cadastrada para a clínica.
+`)),
+			NotificationChannelSMS:      template.Must(template.New(NotificationChannelSMS).Parse(`{{.ClinicName}}: repasse nº {{.FileSequence}} de {{.Amount}} liquidado em {{.PaymentDate}}.`)),
+			NotificationChannelWhatsApp: template.Must(template.New(NotificationChannelWhatsApp).Parse(`Olá, {{.ClinicName}}! O repasse nº {{.FileSequence}} de {{.Amount}} foi liquidado em {{.PaymentDate}}.`)),
+		},
+	},
+	NotificationTemplateClinicAnnouncement: {
+		subject: template.Must(template.New("subject").Parse(`{{.ClinicName}}: {{.Title}}`)),
+		bodies: map[string]*template.Template{
+			NotificationChannelEmail: template.Must(template.New(NotificationChannelEmail).Parse(`Olá, {{.DentistName}}.
+
+{{.Body}}
+
+Aviso enviado por {{.ClinicName}}. Confirme a leitura em Avisos, no app.
+`)),
+			NotificationChannelSMS: template.Must(template.New(NotificationChannelSMS).Parse(`{{.ClinicName}}: {{.Title}}. Veja o aviso completo no app.`)),
+			NotificationChannelWhatsApp: template.Must(template.New(NotificationChannelWhatsApp).Parse(`Olá, {{.DentistName}}! Aviso de {{.ClinicName}}: *{{.Title}}*
+
+{{.Body}}`)),
+		},
+	},
+	NotificationTemplateMaintenanceOverdue: {
+		subject: template.Must(template.New("subject").Parse(`Manutenção atrasada: {{.EquipmentName}}`)),
+		bodies: map[string]*template.Template{
+			NotificationChannelEmail: template.Must(template.New(NotificationChannelEmail).Parse(`Olá, {{.ClinicName}}.
+
+A manutenção de {{.EquipmentName}} venceu em {{.DueDate}} e está atrasada há {{.DaysOverdue}} dia(s).
+
+Agende o serviço e registre-o no histórico do equipamento para manter a clínica em conformidade.
+`)),
+			NotificationChannelSMS:      template.Must(template.New(NotificationChannelSMS).Parse(`{{.ClinicName}}: manutenção de {{.EquipmentName}} vencida em {{.DueDate}}.`)),
+			NotificationChannelWhatsApp: template.Must(template.New(NotificationChannelWhatsApp).Parse(`Olá, {{.ClinicName}}! A manutenção de *{{.EquipmentName}}* venceu em {{.DueDate}}. Agende o serviço e registre-o no app.`)),
+		},
+	},
+	NotificationTemplateAttachmentInfected: {
+		subject: template.Must(template.New("subject").Parse(`Arquivo recusado na verificação de vírus`)),
+		bodies: map[string]*template.Template{
+			NotificationChannelEmail: template.Must(template.New(NotificationChannelEmail).Parse(`Olá.
+
+O arquivo que você enviou em {{.UploadedAt}} foi recusado porque a verificação de vírus encontrou uma ameaça ({{.Signature}}).
+
+O arquivo foi apagado e nada foi alterado no cadastro. Verifique o seu dispositivo antes de enviar o arquivo novamente.
+`)),
+		},
+	},
+	NotificationTemplateScheduledReport: {
+		subject: template.Must(template.New("subject").Parse(`Relatório: {{.Name}}`)),
+		bodies: map[string]*template.Template{
+			NotificationChannelEmail: template.Must(template.New(NotificationChannelEmail).Parse(`Olá.
+
+Segue o resultado do relatório {{.Name}}{{if .Period}}, de {{.Period}}{{end}}.
+
+{{.Table}}
+{{if .Omitted}}
+Mais {{.Omitted}} linha(s) ficaram de fora deste e-mail. Rode o relatório pela API para ver o resultado completo.
+{{end}}
+Você recebe este e-mail porque está na lista de destinatários do relatório. Para sair, peça a um administrador da clínica.
+`)),
+		},
+	},
+}
+
+type notificationRequest struct {
+	Channel   string
+	Template  string
+	Recipient string
+	// ClinicID, when the message is about a clinic, counts it in that clinic's deliverability report.
+	ClinicID string
+	// DedupeKey identifies what the notification is about; enqueueing the same key twice keeps the first one.
+	DedupeKey string
+	Data      any
+}
+
+// enqueueNotification renders the message and writes it to the outbox, inside the caller's transaction so it only goes out if the
+// change it reports commits. Channels without a sender and recipients suppressed after a hard bounce are skipped.
+func (s *Service) enqueueNotification(ctx context.Context, q repository.Querier, request notificationRequest) error {
+	if _, ok := s.notificationSenders[request.Channel]; !ok {
+		return nil
+	}
+	suppressed, err := q.IsNotificationRecipientSuppressed(ctx, repository.IsNotificationRecipientSuppressedParams{
+		OrganizationID: organizationID(ctx),
+		Channel:        request.Channel,
+		Recipient:      suppressionRecipient(request.Channel, request.Recipient),
+	})
+	if err != nil {
+		return err
+	}
+	if suppressed {
+		return nil
+	}
+	templates, ok := notificationTemplates[request.Template]
+	if !ok {
+		return fmt.Errorf("unknown notification template %q", request.Template)
+	}
+	bodyTemplate, ok := templates.bodies[request.Channel]
+	if !ok {
+		return fmt.Errorf("notification template %q has no %s body", request.Template, request.Channel)
+	}
+	subject := ""
+	if request.Channel == NotificationChannelEmail {
+		rendered, err := renderNotificationTemplate(templates.subject, request.Data)
+		if err != nil {
+			return err
+		}
+		subject = rendered
+	}
+	body, err := renderNotificationTemplate(bodyTemplate, request.Data)
+	if err != nil {
+		return err
+	}
+	id, err := newUUIDV7()
+	if err != nil {
+		return err
+	}
+	var clinicID uuid.NullUUID
+	if parsed, err := uuid.Parse(request.ClinicID); err == nil {
+		clinicID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+	return q.CreateNotification(ctx, repository.CreateNotificationParams{
+		ID:             id,
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		Channel:        request.Channel,
+		Template:       request.Template,
+		Recipient:      request.Recipient,
+		Subject:        subject,
+		Body:           body,
+		DedupeKey:      request.DedupeKey,
+	})
+}
+
+func renderNotificationTemplate(tmpl *template.Template, data any) (string, error) {
+	var rendered strings.Builder
+	if err := tmpl.Execute(&rendered, data); err != nil {
+		return "", fmt.Errorf("render notification %s: %w", tmpl.Name(), err)
+	}
+	return rendered.String(), nil
+}
+
+// DeliverDueNotifications sends the caller's organization's pending notifications that are due. Each claimed message is leased
+// rather than locked, so a slow provider holds no transaction and a crash mid-send only delays the retry until the lease ends.
+func (s *Service) DeliverDueNotifications(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeliverDueNotifications")
+	defer span.End()
+
+	if len(s.notificationSenders) == 0 {
+		return 0, nil
+	}
+	now := s.now()
+	claimed, err := s.queries.ClaimDueNotifications(ctx, repository.ClaimDueNotificationsParams{
+		OrganizationID: organizationID(ctx),
+		DueBefore:      now,
+		LeaseUntil:     now.Add(notificationLease),
+		PageLimit:      notificationDeliveryPage,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent, smsSent := 0, 0
+	for _, notification := range claimed {
+		sendErr := errors.New("no sender configured for channel " + notification.Channel)
+		if sender, ok := s.notificationSenders[notification.Channel]; ok {
+			sendErr = sender.Send(ctx, NotificationMessage{
+				ID:        notification.ID,
+				Channel:   notification.Channel,
+				Recipient: notification.Recipient,
+				Subject:   notification.Subject,
+				Body:      notification.Body,
+			})
+		}
+		if sendErr == nil {
+			if err := s.queries.MarkNotificationSent(ctx, repository.MarkNotificationSentParams{
+				OrganizationID: organizationID(ctx),
+				ID:             notification.ID,
+			}); err != nil {
+				return sent, err
+			}
+			sent++
+			if notification.Channel == NotificationChannelSMS {
+				smsSent++
+			}
+			continue
+		}
+
+		status, nextAttempt := nextNotificationAttempt(int(notification.Attempts), s.now())
+		slog.WarnContext(ctx, "notification delivery failed",
+			"notification_id", notification.ID,
+			"channel", notification.Channel,
+			"attempts", notification.Attempts,
+			"status", status,
+			"error", sendErr,
+		)
+		if err := s.queries.MarkNotificationAttemptFailed(ctx, repository.MarkNotificationAttemptFailedParams{
+			OrganizationID: organizationID(ctx),
+			ID:             notification.ID,
+			Status:         status,
+			NextAttemptAt:  nextAttempt,
+			LastError:      notificationError(sendErr),
+		}); err != nil {
+			return sent, err
+		}
+	}
+	if smsSent > 0 {
+		if err := s.RecordSMSSent(ctx, smsSent); err != nil {
+			slog.WarnContext(ctx, "record sms usage failed", "count", smsSent, "error", err)
+		}
+	}
+	return sent, nil
+}
+
+// nextNotificationAttempt backs off exponentially from notificationRetryBase and gives up after notificationMaxAttempts.
+func nextNotificationAttempt(attempts int, now time.Time) (string, time.Time) {
+	if attempts >= notificationMaxAttempts {
+		return NotificationFailed, now
+	}
+	delay := notificationRetryBase << max(attempts-1, 0)
+	if delay > notificationRetryMax || delay <= 0 {
+		delay = notificationRetryMax
+	}
+	return NotificationPending, now.Add(delay)
+}
+
+func notificationError(err error) sql.NullString {
+	message := []rune(err.Error())
+	if len(message) > maxNotificationErrorLength {
+		message = message[:maxNotificationErrorLength]
+	}
+	return sql.NullString{String: string(message), Valid: true}
+}
+
+func (s *Service) ListNotifications(ctx context.Context, limit int, cursor *string, status *string) ([]NotificationOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListNotifications")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListNotificationsCursorParams{
+		OrganizationID: organizationID(ctx),
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+	if status != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*status))
+		switch normalized {
+		case NotificationPending, NotificationSent, NotificationFailed:
+		default:
+			return nil, nil, validationError(fmt.Sprintf("status must be one of: %s, %s, %s", NotificationPending, NotificationSent, NotificationFailed))
+		}
+		params.Status = sql.NullString{String: normalized, Valid: true}
+	}
+
+	rows, err := s.queries.ListNotificationsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	notifications := make([]NotificationOutput, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, mapNotification(row))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return notifications, nextCursor, nil
+}
+
+func (s *Service) GetNotification(ctx context.Context, id string) (NotificationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetNotification")
+	defer span.End()
+
+	row, err := s.queries.GetNotification(ctx, repository.GetNotificationParams{
+		OrganizationID: organizationID(ctx),
+		ID:             id,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return NotificationOutput{}, notFoundError("notification not found")
+		}
+		return NotificationOutput{}, err
+	}
+	return mapNotification(row), nil
+}
+
+// RetryNotification puts a notification that ran out of attempts back in the queue with a fresh set of attempts.
+func (s *Service) RetryNotification(ctx context.Context, id string) (NotificationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RetryNotification")
+	defer span.End()
+
+	requeued, err := s.queries.RetryFailedNotification(ctx, repository.RetryFailedNotificationParams{
+		OrganizationID: organizationID(ctx),
+		ID:             id,
+	})
+	if err != nil {
+		return NotificationOutput{}, err
+	}
+	notification, err := s.GetNotification(ctx, id)
+	if err != nil {
+		return NotificationOutput{}, err
+	}
+	if requeued == 0 {
+		return NotificationOutput{}, conflictError(fmt.Sprintf("only %s notifications can be retried; this one is %s", NotificationFailed, notification.Status))
+	}
+	return notification, nil
+}
+
+func mapNotification(row repository.Notification) NotificationOutput {
+	output := NotificationOutput{
+		ID:                row.ID,
+		ClinicID:          nullUUIDToPointer(row.ClinicID),
+		Channel:           row.Channel,
+		Template:          row.Template,
+		Recipient:         row.Recipient,
+		Subject:           row.Subject,
+		Body:              row.Body,
+		Status:            row.Status,
+		Attempts:          int(row.Attempts),
+		LastError:         nullToPointer(row.LastError),
+		DeliveryStatus:    nullToPointer(row.DeliveryStatus),
+		BounceType:        nullToPointer(row.BounceType),
+		DeliveryDetail:    nullToPointer(row.DeliveryDetail),
+		DeliveryUpdatedAt: nullTimeToPointer(row.DeliveryUpdatedAt),
+		CreatedAt:         row.CreatedAt,
+		UpdatedAt:         row.UpdatedAt,
+	}
+	if row.Status == NotificationPending {
+		nextAttemptAt := row.NextAttemptAt
+		output.NextAttemptAt = &nextAttemptAt
+	}
+	if row.SentAt.Valid {
+		sentAt := row.SentAt.Time
+		output.SentAt = &sentAt
+	}
+	return output
+}
@@ -0,0 +1,521 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	OrganizationStatusTrial     = "TRIAL"
+	OrganizationStatusActive    = "ACTIVE"
+	OrganizationStatusSuspended = "SUSPENDED"
+	OrganizationStatusClosed    = "CLOSED"
+
+	defaultOffboardingPurgeDelay = 30 * 24 * time.Hour
+	organizationPurgeBatchSize   = 10
+	offboardingExportContentType = "application/zip"
+)
+
+// Columns sealed by the field encryption layer, by table, that must be opened before they leave in an export.
+var exportedEncryptedColumns = []struct {
+	table  string
+	column string
+}{
+	{table: "bank_accounts", column: "account_number"},
+	{table: "bank_account_changes", column: "account_number"},
+	{table: "payout_batch_items", column: "account_number"},
+	{table: "payout_batches", column: "file_content"},
+}
+
+func WithOffboardingPurgeDelay(delay time.Duration) Option {
+	return func(s *Service) {
+		if delay > 0 {
+			s.offboardingPurgeDelay = delay
+		}
+	}
+}
+
+// CheckOrganizationAccess gates API calls on the tenant state: suspended organizations and expired trials are read-only, closed ones are locked out.
+func (s *Service) CheckOrganizationAccess(ctx context.Context, write bool) error {
+	organization := organizationID(ctx)
+	if organization == "" {
+		return nil
+	}
+	row, err := s.queries.GetOrganizationByID(ctx, organization)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("organization not found")
+		}
+		return err
+	}
+	switch {
+	case row.Status == OrganizationStatusClosed:
+		return forbiddenError("organization is closed")
+	case row.Status == OrganizationStatusSuspended && write:
+		return forbiddenError("organization is suspended; access is read-only")
+	case row.Status == OrganizationStatusTrial && write && row.TrialEndsAt.Valid && !s.now().Before(row.TrialEndsAt.Time):
+		return forbiddenError("organization trial has ended; access is read-only")
+	}
+	return nil
+}
+
+func (s *Service) UpdateOrganizationStatus(ctx context.Context, id string, input UpdateOrganizationStatusInput) (OrganizationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateOrganizationStatus")
+	defer span.End()
+
+	status := strings.ToUpper(strings.TrimSpace(input.Status))
+	trialEndsAt, err := s.validateOrganizationStatus(status, input.TrialEndsAt)
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+
+	ctx = WithOrganization(ctx, id)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return OrganizationOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	current, err := qtx.LockOrganizationForUpdate(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrganizationOutput{}, notFoundError("organization not found")
+		}
+		return OrganizationOutput{}, err
+	}
+	if current.Status == OrganizationStatusClosed {
+		return OrganizationOutput{}, conflictError("organization is closed")
+	}
+	organization, err := qtx.UpdateOrganizationStatus(ctx, repository.UpdateOrganizationStatusParams{
+		Status:      status,
+		TrialEndsAt: trialEndsAt,
+		ID:          id,
+	})
+	if err != nil {
+		return OrganizationOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "organization.status_changed",
+		EntityType: AuditEntityOrganization,
+		EntityID:   id,
+		Metadata:   map[string]any{"from": current.Status, "to": status},
+	}); err != nil {
+		return OrganizationOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return OrganizationOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapOrganizationOutput(organization), nil
+}
+
+func (s *Service) validateOrganizationStatus(status string, trialEndsAt *time.Time) (sql.NullTime, error) {
+	switch status {
+	case OrganizationStatusTrial:
+		if trialEndsAt == nil {
+			return sql.NullTime{}, validationError("trial_ends_at is required for TRIAL")
+		}
+		if !trialEndsAt.After(s.now()) {
+			return sql.NullTime{}, validationError("trial_ends_at must be in the future")
+		}
+		return sql.NullTime{Time: trialEndsAt.UTC(), Valid: true}, nil
+	case OrganizationStatusActive, OrganizationStatusSuspended:
+		if trialEndsAt != nil {
+			return sql.NullTime{}, validationError("trial_ends_at only applies to TRIAL")
+		}
+		return sql.NullTime{}, nil
+	case OrganizationStatusClosed:
+		return sql.NullTime{}, validationError("organizations are closed through offboarding")
+	default:
+		return sql.NullTime{}, validationError("status must be one of TRIAL, ACTIVE or SUSPENDED")
+	}
+}
+
+// OffboardOrganization closes the organization, archives everything it owns and schedules the purge.
+// It is closed before the export so nothing written afterwards is missing from the archive; a failed export can be retried.
+func (s *Service) OffboardOrganization(ctx context.Context, id string, input OffboardOrganizationInput) (OrganizationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.OffboardOrganization")
+	defer span.End()
+
+	if s.attachments == nil {
+		return OrganizationOutput{}, conflictError("attachment storage is required to keep the offboarding export")
+	}
+	if id == DefaultOrganizationID {
+		return OrganizationOutput{}, conflictError("the default organization cannot be offboarded")
+	}
+	ctx = WithOrganization(ctx, id)
+	organization, err := s.queries.GetOrganizationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrganizationOutput{}, notFoundError("organization not found")
+		}
+		return OrganizationOutput{}, err
+	}
+	if strings.TrimSpace(input.ConfirmSlug) != organization.Slug {
+		return OrganizationOutput{}, validationError("confirm_slug must match the organization slug")
+	}
+	if organization.PurgedAt.Valid {
+		return OrganizationOutput{}, conflictError("organization was already purged")
+	}
+	if organization.ExportKey.Valid {
+		return OrganizationOutput{}, conflictError("organization was already offboarded")
+	}
+	if err := s.checkDestinationRegion(organization.DataRegion, DestinationAttachments); err != nil {
+		return OrganizationOutput{}, err
+	}
+
+	organization, err = s.closeOrganization(ctx, organization)
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+	archive, err := s.buildOrganizationExport(ctx, organization)
+	if err != nil {
+		return OrganizationOutput{}, fmt.Errorf("build offboarding export: %w", err)
+	}
+	now := s.now().UTC()
+	key := fmt.Sprintf("offboarding/%s/%s.zip", organization.ID, now.Format("20060102T150405Z"))
+	if err := s.attachments.PutAttachment(ctx, key, offboardingExportContentType, archive); err != nil {
+		return OrganizationOutput{}, fmt.Errorf("store offboarding export: %w", err)
+	}
+	organization, err = s.queries.SetOrganizationExport(ctx, repository.SetOrganizationExportParams{
+		ExportKey:  sql.NullString{String: key, Valid: true},
+		PurgeAfter: now.Add(s.offboardingPurgeDelay),
+		ID:         organization.ID,
+	})
+	if err != nil {
+		return OrganizationOutput{}, mapDatabaseError(err)
+	}
+	return mapOrganizationOutput(organization), nil
+}
+
+func (s *Service) closeOrganization(ctx context.Context, organization repository.Organization) (repository.Organization, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repository.Organization{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	closed, err := qtx.CloseOrganization(ctx, organization.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.Organization{}, conflictError("organization was already purged")
+		}
+		return repository.Organization{}, mapDatabaseError(err)
+	}
+	if organization.Status != OrganizationStatusClosed {
+		if err := recordAudit(ctx, qtx, auditEntry{
+			Action:     "organization.closed",
+			EntityType: AuditEntityOrganization,
+			EntityID:   organization.ID,
+			Metadata:   map[string]any{"from": organization.Status},
+		}); err != nil {
+			return repository.Organization{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return repository.Organization{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return closed, nil
+}
+
+// buildOrganizationExport writes one JSON file per table plus the stored attachments into a zip archive.
+func (s *Service) buildOrganizationExport(ctx context.Context, organization repository.Organization) ([]byte, error) {
+	data, err := s.queries.ExportOrganizationData(ctx, organization.ID)
+	if err != nil {
+		return nil, err
+	}
+	var tables map[string]json.RawMessage
+	if err := json.Unmarshal(data, &tables); err != nil {
+		return nil, fmt.Errorf("decode export: %w", err)
+	}
+	for _, sealed := range exportedEncryptedColumns {
+		if tables[sealed.table], err = s.openExportedColumn(ctx, organization.ID, tables[sealed.table], sealed.column); err != nil {
+			return nil, fmt.Errorf("open %s: %w", sealed.table, err)
+		}
+	}
+	attachmentKeys, err := s.queries.ListOrganizationAttachmentKeys(ctx, organization.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	organizationJSON, err := json.MarshalIndent(mapOrganizationOutput(organization), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := writeZipEntry(archive, "organization.json", organizationJSON); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(tables))
+	for name := range tables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := writeZipEntry(archive, "data/"+name+".json", tables[name]); err != nil {
+			return nil, err
+		}
+	}
+	for _, key := range attachmentKeys {
+		attachment, found, err := s.attachments.GetAttachment(ctx, key)
+		if err != nil {
+			return nil, fmt.Errorf("load attachment %s: %w", key, err)
+		}
+		if !found {
+			slog.WarnContext(ctx, "offboarding export skipped missing attachment", "key", key)
+			continue
+		}
+		if err := writeZipEntry(archive, "attachments/"+key, attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("close export archive: %w", err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// Values sealed with a shredded key stay as ciphertext; the shred was deliberate and they cannot be recovered anyway. Bytea
+// columns arrive in the JSON as \x-prefixed hex and are written back the same way.
+func (s *Service) openExportedColumn(ctx context.Context, organization string, rows json.RawMessage, column string) (json.RawMessage, error) {
+	if s.dataKeys == nil || len(rows) == 0 {
+		return rows, nil
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(rows, &decoded); err != nil {
+		return nil, err
+	}
+	for _, row := range decoded {
+		value, ok := row[column].(string)
+		if !ok {
+			continue
+		}
+		encoded, isBytea := strings.CutPrefix(value, `\x`)
+		if isBytea {
+			raw, err := hex.DecodeString(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("decode %s: %w", column, err)
+			}
+			value = string(raw)
+		}
+		opened, err := s.dataKeys.open(ctx, organization, value)
+		if err != nil {
+			if errors.Is(err, ErrConflict) {
+				continue
+			}
+			return nil, err
+		}
+		if isBytea {
+			opened = `\x` + hex.EncodeToString([]byte(opened))
+		}
+		row[column] = opened
+	}
+	return json.Marshal(decoded)
+}
+
+func writeZipEntry(archive *zip.Writer, name string, data []byte) error {
+	entry, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("add %s to export: %w", name, err)
+	}
+	if _, err := entry.Write(data); err != nil {
+		return fmt.Errorf("write %s to export: %w", name, err)
+	}
+	return nil
+}
+
+func (s *Service) GetOrganizationExport(ctx context.Context, id string) (Attachment, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetOrganizationExport")
+	defer span.End()
+
+	organization, err := s.queries.GetOrganizationByID(WithOrganization(ctx, id), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return Attachment{}, notFoundError("organization not found")
+		}
+		return Attachment{}, err
+	}
+	if !organization.ExportKey.Valid || s.attachments == nil {
+		return Attachment{}, notFoundError("organization has no offboarding export")
+	}
+	attachment, found, err := s.attachments.GetAttachment(ctx, organization.ExportKey.String)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("load offboarding export: %w", err)
+	}
+	if !found {
+		return Attachment{}, notFoundError("organization has no offboarding export")
+	}
+	return attachment, nil
+}
+
+// PurgeDueOrganizations deletes every row of organizations whose offboarding grace period ran out; the organization row itself stays as a tombstone.
+func (s *Service) PurgeDueOrganizations(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.PurgeDueOrganizations")
+	defer span.End()
+
+	organizations, err := s.queries.ListOrganizationsDueForPurge(ctx, repository.ListOrganizationsDueForPurgeParams{
+		Cutoff:    s.now().UTC(),
+		PageLimit: organizationPurgeBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+	purged := 0
+	var errs []error
+	for _, organization := range organizations {
+		// A legal hold anywhere in the organization keeps all of its data until the hold is released.
+		held, err := s.queries.HasActiveOrganizationLegalHold(WithOrganization(ctx, organization.ID), organization.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("check legal holds of organization %s: %w", organization.ID, err))
+			continue
+		}
+		if held {
+			continue
+		}
+		if err := s.purgeOrganization(WithOrganization(ctx, organization.ID), organization); err != nil {
+			errs = append(errs, fmt.Errorf("purge organization %s: %w", organization.ID, err))
+			continue
+		}
+		purged++
+	}
+	return purged, errors.Join(errs...)
+}
+
+func (s *Service) purgeOrganization(ctx context.Context, organization repository.Organization) error {
+	attachmentKeys, err := s.queries.ListOrganizationAttachmentKeys(ctx, organization.ID)
+	if err != nil {
+		return err
+	}
+	quarantinedKeys, err := s.queries.ListOrganizationQuarantinedAttachmentKeys(ctx, organization.ID)
+	if err != nil {
+		return err
+	}
+	reportKeys, err := s.queries.ListOrganizationReportKeys(ctx, organization.ID)
+	if err != nil {
+		return err
+	}
+	attachmentKeys = append(attachmentKeys, quarantinedKeys...)
+	attachmentKeys = append(attachmentKeys, reportKeys...)
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	// Children before parents; every foreign key between tenant tables is ON DELETE RESTRICT.
+	steps := []struct {
+		table string
+		purge func(ctx context.Context, organizationID string) (int64, error)
+	}{
+		{"clinic_note_mentions", qtx.PurgeOrganizationClinicNoteMentions},
+		{"clinic_notes", qtx.PurgeOrganizationClinicNotes},
+		{"attachment_scans", qtx.PurgeOrganizationAttachmentScans},
+		{"reports", qtx.PurgeOrganizationReports},
+		{"report_definitions", qtx.PurgeOrganizationReportDefinitions},
+		{"legal_holds", qtx.PurgeOrganizationLegalHolds},
+		{"document_retention_rules", qtx.PurgeOrganizationDocumentRetentionRules},
+		{"document_signature_requests", qtx.PurgeOrganizationDocumentSignatureRequests},
+		{"generated_documents", qtx.PurgeOrganizationGeneratedDocuments},
+		{"document_templates", qtx.PurgeOrganizationDocumentTemplates},
+		{"clinic_tasks", qtx.PurgeOrganizationClinicTasks},
+		{"clinic_task_rules", qtx.PurgeOrganizationClinicTaskRules},
+		{"time_clock_entries", qtx.PurgeOrganizationTimeClockEntries},
+		{"clinic_time_clock_settings", qtx.PurgeOrganizationTimeClockSettings},
+		{"clinic_shifts", qtx.PurgeOrganizationClinicShifts},
+		{"equipment_maintenance_records", qtx.PurgeOrganizationEquipmentMaintenanceRecords},
+		{"equipment", qtx.PurgeOrganizationEquipment},
+		{"purchase_order_receipts", qtx.PurgeOrganizationPurchaseOrderReceipts},
+		{"inventory_movements", qtx.PurgeOrganizationInventoryMovements},
+		{"purchase_order_items", qtx.PurgeOrganizationPurchaseOrderItems},
+		{"purchase_orders", qtx.PurgeOrganizationPurchaseOrders},
+		{"suppliers", qtx.PurgeOrganizationSuppliers},
+		{"inventory_items", qtx.PurgeOrganizationInventoryItems},
+		{"clinic_announcement_recipients", qtx.PurgeOrganizationClinicAnnouncementRecipients},
+		{"clinic_announcements", qtx.PurgeOrganizationClinicAnnouncements},
+		{"clinic_onboarding_transitions", qtx.PurgeOrganizationClinicOnboardingTransitions},
+		{"bank_account_changes", qtx.PurgeOrganizationBankAccountChanges},
+		{"clinic_financial_holds", qtx.PurgeOrganizationClinicFinancialHolds},
+		{"ledger_entries", qtx.PurgeOrganizationLedgerEntries},
+		{"ledger_transactions", qtx.PurgeOrganizationLedgerTransactions},
+		{"clinic_payables", qtx.PurgeOrganizationClinicPayables},
+		{"payout_batch_items", qtx.PurgeOrganizationPayoutBatchItems},
+		{"payout_batches", qtx.PurgeOrganizationPayoutBatches},
+		{"clinic_revisions", qtx.PurgeOrganizationClinicRevisions},
+		{"pending_deletions", qtx.PurgeOrganizationPendingDeletions},
+		{"audit_export_checkpoints", qtx.PurgeOrganizationAuditExportCheckpoints},
+		{"warehouse_export_watermarks", qtx.PurgeOrganizationWarehouseExportWatermarks},
+		{"audit_chain_head", qtx.PurgeOrganizationAuditChainHead},
+		{"audit_logs", qtx.PurgeOrganizationAuditLogs},
+		{"usage_records", qtx.PurgeOrganizationUsageRecords},
+		{"usage_daily_rollups", qtx.PurgeOrganizationUsageDailyRollups},
+		{"notifications", qtx.PurgeOrganizationNotifications},
+		{"notification_suppressions", qtx.PurgeOrganizationNotificationSuppressions},
+		{"users", qtx.PurgeOrganizationUsers},
+		{"bank_accounts", qtx.PurgeOrganizationBankAccounts},
+		{"clinic_dentists", qtx.PurgeOrganizationClinicDentists},
+		{"dentist_notification_preferences", qtx.PurgeOrganizationDentistNotificationPreferences},
+		{"dentist_specialties", qtx.PurgeOrganizationDentistSpecialties},
+		{"dentist_documents", qtx.PurgeOrganizationDentistDocuments},
+		{"specialties", qtx.PurgeOrganizationSpecialties},
+		{"dentists", qtx.PurgeOrganizationDentists},
+		{"clinic_operating_hours", qtx.PurgeOrganizationClinicOperatingHours},
+		{"clinic_holidays", qtx.PurgeOrganizationClinicHolidays},
+		{"clinic_settings", qtx.PurgeOrganizationClinicSettings},
+		{"clinic_directory_listings", qtx.PurgeOrganizationClinicDirectoryListings},
+		{"clinic_registry_records", qtx.PurgeOrganizationClinicRegistryRecords},
+		{"clinic branches", qtx.PurgeOrganizationClinicBranches},
+		{"clinics", qtx.PurgeOrganizationClinics},
+		{"addresses", qtx.PurgeOrganizationAddresses},
+		{"people", qtx.PurgeOrganizationPeople},
+	}
+	for _, step := range steps {
+		if _, err := step.purge(ctx, organization.ID); err != nil {
+			return fmt.Errorf("purge %s: %w", step.table, mapDatabaseError(err))
+		}
+	}
+	if err := qtx.ShredOrganizationKey(ctx, organization.ID); err != nil {
+		return err
+	}
+	if err := qtx.MarkOrganizationPurged(ctx, organization.ID); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	if s.dataKeys != nil {
+		s.dataKeys.forget(organization.ID)
+	}
+	if s.attachments != nil {
+		if organization.ExportKey.Valid {
+			attachmentKeys = append(attachmentKeys, organization.ExportKey.String)
+		}
+		for _, key := range attachmentKeys {
+			if err := s.attachments.DeleteAttachment(ctx, key); err != nil {
+				slog.WarnContext(ctx, "delete purged organization attachment", "key", key, "error", err)
+			}
+		}
+	}
+	slog.InfoContext(ctx, "organization purged", "organization_id", organization.ID)
+	return nil
+}
@@ -0,0 +1,344 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"golang.org/x/crypto/bcrypt"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	// Seeded by schema.sql; owns the specialty catalog new organizations start from.
+	DefaultOrganizationID = "01a13a20-4e6a-7000-8000-000000000001"
+
+	organizationSlugUniqueConstraint = "idx_organizations_slug_unique"
+	maxOrganizationNameLength        = 255
+)
+
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+type organizationContextKey struct{}
+
+// WithOrganization scopes calls that have no signed-in principal, such as background jobs and public routes.
+func WithOrganization(ctx context.Context, organizationID string) context.Context {
+	return context.WithValue(ctx, organizationContextKey{}, organizationID)
+}
+
+// OrganizationFromContext only reports an organization set through WithOrganization, not the principal's.
+func OrganizationFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(organizationContextKey{}).(string)
+	return id, ok
+}
+
+func organizationID(ctx context.Context) string {
+	if id, ok := OrganizationFromContext(ctx); ok {
+		return id
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		return principal.OrganizationID
+	}
+	return ""
+}
+
+// ForEachOrganization runs fn once per tenant and keeps going after a failing organization so one tenant cannot stall the others' jobs.
+func (s *Service) ForEachOrganization(ctx context.Context, fn func(ctx context.Context) error) error {
+	ids, err := s.queries.ListOrganizationIDs(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, id := range ids {
+		if err := fn(WithOrganization(ctx, id)); err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (s *Service) CreateOrganization(ctx context.Context, input CreateOrganizationInput) (OrganizationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateOrganization")
+	defer span.End()
+
+	slug := strings.ToLower(strings.TrimSpace(input.Slug))
+	if !organizationSlugPattern.MatchString(slug) {
+		return OrganizationOutput{}, validationError("slug must have 1 to 63 lowercase letters, digits or hyphens and cannot start or end with a hyphen")
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return OrganizationOutput{}, validationError("name is required")
+	}
+	if len(name) > maxOrganizationNameLength {
+		return OrganizationOutput{}, validationError(fmt.Sprintf("name must have at most %d characters", maxOrganizationNameLength))
+	}
+	email := strings.ToLower(strings.TrimSpace(input.AdminEmail))
+	if !validation.ValidateEmail(email) {
+		return OrganizationOutput{}, validationError("invalid admin_email")
+	}
+	if len(input.AdminPassword) < 8 {
+		return OrganizationOutput{}, validationError("admin_password must have at least 8 characters")
+	}
+	status := OrganizationStatusActive
+	if input.TrialEndsAt != nil {
+		status = OrganizationStatusTrial
+	}
+	trialEndsAt, err := s.validateOrganizationStatus(status, input.TrialEndsAt)
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+	isolation, err := s.validateOrganizationIsolation(input.Isolation)
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+	dataRegion, err := validateDataRegion(input.DataRegion)
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+	if err := s.checkDestinationRegions(dataRegion); err != nil {
+		return OrganizationOutput{}, err
+	}
+	plan := PlanEnterprise
+	if strings.TrimSpace(input.Plan) != "" {
+		plan, err = validatePlan(input.Plan)
+		if err != nil {
+			return OrganizationOutput{}, err
+		}
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(input.AdminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return OrganizationOutput{}, fmt.Errorf("hash password: %w", err)
+	}
+	organizationRowID, err := newUUIDV7()
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+	var schemaName sql.NullString
+	if isolation == OrganizationIsolationSchema {
+		schemaName = sql.NullString{String: tenantSchemaName(organizationRowID), Valid: true}
+	}
+	adminID, err := newUUIDV7()
+	if err != nil {
+		return OrganizationOutput{}, err
+	}
+	admin := organizationAdmin{id: adminID, email: email, passwordHash: string(passwordHash)}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return OrganizationOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	organization, err := qtx.CreateOrganization(ctx, repository.CreateOrganizationParams{
+		ID:          organizationRowID,
+		Slug:        slug,
+		Name:        name,
+		Status:      status,
+		TrialEndsAt: trialEndsAt,
+		Isolation:   isolation,
+		SchemaName:  schemaName,
+		Plan:        plan,
+		DataRegion:  dataRegion,
+	})
+	if err != nil {
+		if isConstraintViolation(err, organizationSlugUniqueConstraint) {
+			return OrganizationOutput{}, conflictError("organization slug already in use")
+		}
+		return OrganizationOutput{}, mapDatabaseError(err)
+	}
+	ctx = WithOrganization(ctx, organization.ID)
+
+	var user repository.User
+	if isolation == OrganizationIsolationSchema {
+		// The tenant schema can only be provisioned once the organization row is visible, so the seed runs in a second
+		// transaction on the tenant's pool and a failure removes the half-created organization.
+		if err := tx.Commit(ctx); err != nil {
+			return OrganizationOutput{}, fmt.Errorf("commit transaction: %w", err)
+		}
+		user, err = s.seedSchemaOrganization(ctx, organization.ID, admin)
+		if err != nil {
+			if deleteErr := s.queries.DeleteOrganization(ctx, organization.ID); deleteErr != nil {
+				slog.ErrorContext(ctx, "remove organization after failed provisioning", "organization_id", organization.ID, "error", deleteErr)
+			}
+			return OrganizationOutput{}, err
+		}
+	} else {
+		user, err = s.seedOrganization(ctx, qtx, organization.ID, admin)
+		if err != nil {
+			return OrganizationOutput{}, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return OrganizationOutput{}, fmt.Errorf("commit transaction: %w", err)
+		}
+	}
+
+	output := mapOrganizationOutput(organization)
+	output.AdminUser = &UserOutput{ID: user.ID, Email: user.Email, Role: user.Role}
+	return output, nil
+}
+
+type organizationAdmin struct {
+	id           string
+	email        string
+	passwordHash string
+}
+
+func (s *Service) seedSchemaOrganization(ctx context.Context, id string, admin organizationAdmin) (repository.User, error) {
+	if err := s.ProvisionTenantSchema(ctx, id); err != nil {
+		return repository.User{}, err
+	}
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return repository.User{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	user, err := s.seedOrganization(ctx, s.txQuerier(tx), id, admin)
+	if err != nil {
+		return repository.User{}, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return repository.User{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return user, nil
+}
+
+// seedOrganization writes what every new organization starts with: its audit chain, the specialty catalog and the first admin.
+func (s *Service) seedOrganization(ctx context.Context, qtx repository.Querier, id string, admin organizationAdmin) (repository.User, error) {
+	if err := qtx.CreateAuditChainHead(ctx, repository.CreateAuditChainHeadParams{
+		OrganizationID: id,
+		LastHash:       auditChainGenesisHash,
+	}); err != nil {
+		return repository.User{}, err
+	}
+	// The default catalog lives in the shared tables, which a schema tenant's transaction does not see.
+	if err := copySpecialtyCatalog(ctx, s.queries, qtx, id); err != nil {
+		return repository.User{}, err
+	}
+	user, err := qtx.CreateUser(ctx, repository.CreateUserParams{
+		ID:             admin.id,
+		OrganizationID: id,
+		Email:          admin.email,
+		PasswordHash:   admin.passwordHash,
+		Role:           UserRoleAdmin,
+	})
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return repository.User{}, conflictError("email already in use")
+		}
+		return repository.User{}, mapDatabaseError(err)
+	}
+	return user, nil
+}
+
+func copySpecialtyCatalog(ctx context.Context, source repository.Querier, qtx repository.Querier, targetOrganizationID string) error {
+	specialties, err := source.ListSpecialties(WithOrganization(ctx, DefaultOrganizationID), DefaultOrganizationID)
+	if err != nil {
+		return err
+	}
+	for _, specialty := range specialties {
+		id, err := newUUIDV7()
+		if err != nil {
+			return err
+		}
+		if _, err := qtx.CreateSpecialty(ctx, repository.CreateSpecialtyParams{
+			ID:             id,
+			OrganizationID: targetOrganizationID,
+			Code:           specialty.Code,
+			Name:           specialty.Name,
+			Description:    specialty.Description,
+		}); err != nil {
+			return mapDatabaseError(err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) ListOrganizations(ctx context.Context) ([]OrganizationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListOrganizations")
+	defer span.End()
+
+	rows, err := s.queries.ListOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]OrganizationOutput, 0, len(rows))
+	for _, row := range rows {
+		output = append(output, mapOrganizationOutput(row))
+	}
+	return output, nil
+}
+
+// ResolveOrganization accepts either an organization id or its slug.
+func (s *Service) ResolveOrganization(ctx context.Context, reference string) (string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ResolveOrganization")
+	defer span.End()
+
+	reference = strings.ToLower(strings.TrimSpace(reference))
+	var (
+		organization repository.Organization
+		err          error
+	)
+	if _, parseErr := uuid.Parse(reference); parseErr == nil {
+		organization, err = s.queries.GetOrganizationByID(ctx, reference)
+	} else if organizationSlugPattern.MatchString(reference) {
+		organization, err = s.queries.GetOrganizationBySlug(ctx, reference)
+	} else {
+		return "", notFoundError("organization not found")
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", notFoundError("organization not found")
+		}
+		return "", err
+	}
+	return organization.ID, nil
+}
+
+// resolveOrganizationSlug scopes public, unauthenticated routes; an empty slug keeps the organization already resolved for the request, or the default one.
+func (s *Service) resolveOrganizationSlug(ctx context.Context, slug *string) (context.Context, error) {
+	if slug == nil || strings.TrimSpace(*slug) == "" {
+		if _, ok := OrganizationFromContext(ctx); ok {
+			return ctx, nil
+		}
+		return WithOrganization(ctx, DefaultOrganizationID), nil
+	}
+	organization, err := s.ResolveOrganization(ctx, *slug)
+	if err != nil {
+		return ctx, err
+	}
+	return WithOrganization(ctx, organization), nil
+}
+
+func mapOrganizationOutput(row repository.Organization) OrganizationOutput {
+	return OrganizationOutput{
+		ID:   row.ID,
+		Slug: row.Slug,
+		Name: row.Name,
+		Quotas: OrganizationQuotasOutput{
+			MaxClinics:           nullInt32ToPointer(row.MaxClinics),
+			MaxDentists:          nullInt32ToPointer(row.MaxDentists),
+			MaxRequestsPerMinute: nullInt32ToPointer(row.MaxRequestsPerMinute),
+			MaxStorageMB:         nullInt32ToPointer(row.MaxStorageMb),
+		},
+		Status:          row.Status,
+		TrialEndsAt:     nullTimeToPointer(row.TrialEndsAt),
+		ClosedAt:        nullTimeToPointer(row.ClosedAt),
+		PurgeAfter:      nullTimeToPointer(row.PurgeAfter),
+		PurgedAt:        nullTimeToPointer(row.PurgedAt),
+		ExportAvailable: row.ExportKey.Valid,
+		Isolation:       row.Isolation,
+		Plan:            row.Plan,
+		DataRegion:      nullToPointer(row.DataRegion),
+		CreatedAt:       row.CreatedAt,
+	}
+}
@@ -0,0 +1,823 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/cnab"
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	PayoutFormatCNAB240 = "CNAB240"
+	PayoutFormatPix     = "PIX"
+
+	PayoutBatchGenerated = "GENERATED"
+	PayoutBatchSubmitted = "SUBMITTED"
+	PayoutBatchSettled   = "SETTLED"
+	PayoutBatchFailed    = "FAILED"
+
+	AuditEntityPayoutBatch = "PAYOUT_BATCH"
+
+	maxPayableDescriptionLength = 255
+	maxPayablePeriodDays        = 366
+)
+
+var payoutBatchTransitions = map[string][]string{
+	PayoutBatchGenerated: {PayoutBatchSubmitted, PayoutBatchFailed},
+	PayoutBatchSubmitted: {PayoutBatchSettled, PayoutBatchFailed},
+}
+
+// Pix transfers to bank details identify the account type with the ISO 20022 codes.
+var pixAccountTypes = map[string]string{
+	BankAccountTypeChecking: "CACC",
+	BankAccountTypeSavings:  "SVGS",
+	BankAccountTypePayment:  "TRAN",
+}
+
+type pixBatchPayload struct {
+	BatchID     string       `json:"batch_id"`
+	Sequence    int64        `json:"sequence"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	PaymentDate string       `json:"payment_date"`
+	Payer       pixParty     `json:"payer"`
+	TotalCents  int64        `json:"total_cents"`
+	Payments    []pixPayment `json:"payments"`
+}
+
+type pixPayment struct {
+	Reference   string   `json:"reference"`
+	AmountCents int64    `json:"amount_cents"`
+	Payee       pixParty `json:"payee"`
+}
+
+type pixParty struct {
+	Name          string `json:"name"`
+	TaxID         string `json:"tax_id"`
+	BankCode      string `json:"bank_code"`
+	BranchNumber  string `json:"branch_number"`
+	AccountNumber string `json:"account_number"`
+	AccountType   string `json:"account_type,omitempty"`
+}
+
+func WithPayoutPayer(payer cnab.Payer) Option {
+	return func(s *Service) {
+		if strings.TrimSpace(payer.TaxID) == "" {
+			return
+		}
+		s.payoutPayer = &payer
+	}
+}
+
+func (s *Service) CreateClinicPayable(ctx context.Context, clinicID string, input CreateClinicPayableInput) (ClinicPayableOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinicPayable")
+	defer span.End()
+
+	if input.AmountCents <= 0 {
+		return ClinicPayableOutput{}, validationError("amount_cents must be greater than zero")
+	}
+	description := strings.TrimSpace(input.Description)
+	if description == "" {
+		return ClinicPayableOutput{}, validationError("description is required")
+	}
+	if err := validateMaxLength("description", description, maxPayableDescriptionLength); err != nil {
+		return ClinicPayableOutput{}, err
+	}
+	occurredOn, err := parseDocumentDate("occurred_on", &input.OccurredOn)
+	if err != nil {
+		return ClinicPayableOutput{}, err
+	}
+	payableID, err := newUUIDV7()
+	if err != nil {
+		return ClinicPayableOutput{}, err
+	}
+
+	params := repository.CreateClinicPayableParams{
+		OrganizationID:    organizationID(ctx),
+		ID:                payableID,
+		ClinicID:          clinicID,
+		AmountCents:       input.AmountCents,
+		Description:       description,
+		ExternalReference: optionalString(input.ExternalReference),
+		OccurredOn:        occurredOn.Time,
+	}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if parsed, err := uuid.Parse(principal.UserID); err == nil {
+			params.CreatedByUserID = uuid.NullUUID{UUID: parsed, Valid: true}
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicPayableOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if err := ensureClinicOperational(ctx, qtx, clinicID); err != nil {
+		return ClinicPayableOutput{}, err
+	}
+	payable, err := qtx.CreateClinicPayable(ctx, params)
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return ClinicPayableOutput{}, conflictError("external_reference already registered for this clinic")
+		}
+		return ClinicPayableOutput{}, mapDatabaseError(err)
+	}
+	if _, err := postLedgerTransaction(ctx, qtx, ledgerTransaction{
+		ClinicID:      clinicID,
+		Kind:          LedgerKindPayment,
+		Description:   description,
+		ReferenceType: ledgerReferencePayable,
+		ReferenceID:   payable.ID,
+		OccurredOn:    payable.OccurredOn,
+		Entries:       clinicLedgerEntries(LedgerKindPayment, payable.AmountCents),
+	}); err != nil {
+		return ClinicPayableOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicPayableOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapClinicPayable(payable), nil
+}
+
+func (s *Service) ListClinicPayables(ctx context.Context, clinicID string, open *bool) ([]ClinicPayableOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicPayables")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, notFoundError("clinic not found")
+		}
+		return nil, err
+	}
+	openFilter := sql.NullBool{}
+	if open != nil {
+		openFilter = sql.NullBool{Bool: *open, Valid: true}
+	}
+	rows, err := s.queries.ListClinicPayables(ctx, repository.ListClinicPayablesParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		Open:           openFilter,
+	})
+	if err != nil {
+		return nil, err
+	}
+	payables := make([]ClinicPayableOutput, 0, len(rows))
+	for _, row := range rows {
+		payables = append(payables, mapClinicPayable(row))
+	}
+	return payables, nil
+}
+
+func (s *Service) CreatePayoutBatch(ctx context.Context, input CreatePayoutBatchInput) (PayoutBatchOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreatePayoutBatch")
+	defer span.End()
+
+	params, err := s.payoutBatchParams(input)
+	if err != nil {
+		return PayoutBatchOutput{}, err
+	}
+	if s.payoutPayer == nil {
+		return PayoutBatchOutput{}, conflictError("payouts are not configured")
+	}
+	batchID, err := newUUIDV7()
+	if err != nil {
+		return PayoutBatchOutput{}, err
+	}
+	params.ID = batchID
+	params.OrganizationID = organizationID(ctx)
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if parsed, err := uuid.Parse(principal.UserID); err == nil {
+			params.CreatedByUserID = uuid.NullUUID{UUID: parsed, Valid: true}
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return PayoutBatchOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	batch, err := qtx.CreatePayoutBatch(ctx, params)
+	if err != nil {
+		return PayoutBatchOutput{}, mapDatabaseError(err)
+	}
+	clinicIDs, err := qtx.ListClinicsWithOpenPayables(ctx, repository.ListClinicsWithOpenPayablesParams{
+		OrganizationID: organizationID(ctx),
+		PeriodStart:    params.PeriodStart,
+		PeriodEnd:      params.PeriodEnd,
+	})
+	if err != nil {
+		return PayoutBatchOutput{}, err
+	}
+
+	skipped := make([]PayoutSkippedClinic, 0)
+	payments := make([]cnab.Payment, 0, len(clinicIDs))
+	var paymentItems []repository.CreatePayoutBatchItemParams
+	var total int64
+	for _, clinicID := range clinicIDs {
+		held, err := qtx.HasActiveClinicFinancialHold(ctx, repository.HasActiveClinicFinancialHoldParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+		})
+		if err != nil {
+			return PayoutBatchOutput{}, err
+		}
+		if held {
+			skipped = append(skipped, PayoutSkippedClinic{ClinicID: clinicID, Reason: "clinic has an active financial hold"})
+			continue
+		}
+		accounts, err := qtx.ListBankAccountsByClinicID(ctx, repository.ListBankAccountsByClinicIDParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+		})
+		if err != nil {
+			return PayoutBatchOutput{}, err
+		}
+		// Clinics that cannot receive yet keep their payables open for a later batch.
+		account, reason := selectPayoutAccount(accounts)
+		if reason != "" {
+			skipped = append(skipped, PayoutSkippedClinic{ClinicID: clinicID, Reason: reason})
+			continue
+		}
+		clinic, err := qtx.GetClinicDetails(ctx, repository.GetClinicDetailsParams{
+			OrganizationID: organizationID(ctx),
+			ID:             clinicID,
+		})
+		if err != nil {
+			return PayoutBatchOutput{}, err
+		}
+
+		amounts, err := qtx.AssignClinicPayablesToBatch(ctx, repository.AssignClinicPayablesToBatchParams{
+			OrganizationID: organizationID(ctx),
+			BatchID:        batchID,
+			ClinicID:       clinicID,
+			PeriodStart:    params.PeriodStart,
+			PeriodEnd:      params.PeriodEnd,
+		})
+		if err != nil {
+			return PayoutBatchOutput{}, mapDatabaseError(err)
+		}
+		var amount int64
+		for _, value := range amounts {
+			amount += value
+		}
+		if amount <= 0 {
+			continue
+		}
+
+		item := repository.CreatePayoutBatchItemParams{
+			OrganizationID: organizationID(ctx),
+			BatchID:        batchID,
+			ClinicID:       clinicID,
+			BankAccountID:  account.ID,
+			AmountCents:    amount,
+			PayableCount:   int32(len(amounts)),
+			BankCode:       account.BankCode,
+			BranchNumber:   account.BranchNumber,
+			AccountNumber:  account.AccountNumber,
+			AccountType:    account.AccountType,
+			HolderName:     clinic.LegalName,
+			HolderTaxID:    clinic.TaxIDNumber,
+		}
+		if account.HolderTaxID.Valid {
+			item.HolderName = account.HolderName.String
+			item.HolderTaxID = account.HolderTaxID.String
+		}
+		if err := qtx.CreatePayoutBatchItem(ctx, item); err != nil {
+			return PayoutBatchOutput{}, mapDatabaseError(err)
+		}
+		paymentItems = append(paymentItems, item)
+		total += amount
+	}
+	if len(paymentItems) == 0 {
+		if len(skipped) > 0 {
+			return PayoutBatchOutput{}, conflictError(fmt.Sprintf("no clinic can receive payouts for the period; %d clinic(s) were skipped", len(skipped)))
+		}
+		return PayoutBatchOutput{}, conflictError("no open payables for the period")
+	}
+	for idx, item := range paymentItems {
+		payments = append(payments, cnab.Payment{
+			Reference:   fmt.Sprintf("%06d%06d", batch.FileSequence, idx+1),
+			HolderName:  item.HolderName,
+			HolderTaxID: item.HolderTaxID,
+			Account:     cnab.Account{BankCode: item.BankCode, BranchNumber: item.BranchNumber, AccountNumber: item.AccountNumber},
+			AmountCents: item.AmountCents,
+		})
+	}
+
+	fileName, content, err := s.encodePayoutBatch(batch, paymentItems, payments, total)
+	if err != nil {
+		return PayoutBatchOutput{}, err
+	}
+	skippedJSON, err := json.Marshal(skipped)
+	if err != nil {
+		return PayoutBatchOutput{}, fmt.Errorf("encode skipped clinics: %w", err)
+	}
+	if err := qtx.CompletePayoutBatch(ctx, repository.CompletePayoutBatchParams{
+		OrganizationID: organizationID(ctx),
+		ID:             batchID,
+		TotalCents:     total,
+		ItemCount:      int32(len(paymentItems)),
+		Skipped:        skippedJSON,
+		FileName:       fileName,
+		FileContent:    content,
+	}); err != nil {
+		return PayoutBatchOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "payout_batch.generated",
+		EntityType: AuditEntityPayoutBatch,
+		EntityID:   batchID,
+		Metadata:   map[string]any{"format": batch.Format, "total_cents": total, "item_count": len(paymentItems)},
+	}); err != nil {
+		return PayoutBatchOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return PayoutBatchOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.GetPayoutBatch(ctx, batchID)
+}
+
+func (s *Service) GetPayoutBatch(ctx context.Context, batchID string) (PayoutBatchOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetPayoutBatch")
+	defer span.End()
+
+	batch, err := s.queries.GetPayoutBatch(ctx, repository.GetPayoutBatchParams{
+		OrganizationID: organizationID(ctx),
+		ID:             batchID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PayoutBatchOutput{}, notFoundError("payout batch not found")
+		}
+		return PayoutBatchOutput{}, err
+	}
+	items, err := s.queries.ListPayoutBatchItems(ctx, repository.ListPayoutBatchItemsParams{
+		OrganizationID: organizationID(ctx),
+		BatchID:        batchID,
+	})
+	if err != nil {
+		return PayoutBatchOutput{}, err
+	}
+	output := mapPayoutBatch(batch)
+	output.Items = make([]PayoutBatchItemOutput, 0, len(items))
+	for _, item := range items {
+		output.Items = append(output.Items, PayoutBatchItemOutput{
+			ClinicID:      item.ClinicID,
+			BankAccountID: item.BankAccountID,
+			AmountCents:   item.AmountCents,
+			PayableCount:  int(item.PayableCount),
+			BankCode:      item.BankCode,
+			BranchNumber:  item.BranchNumber,
+			AccountNumber: maskAccountNumber(item.AccountNumber),
+			AccountType:   item.AccountType,
+			HolderName:    item.HolderName,
+			HolderTaxID:   item.HolderTaxID,
+		})
+	}
+	return output, nil
+}
+
+func (s *Service) ListPayoutBatches(ctx context.Context, limit int, cursor *string, status *string) ([]PayoutBatchOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListPayoutBatches")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListPayoutBatchesCursorParams{
+		OrganizationID: organizationID(ctx),
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+	if status != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*status))
+		switch normalized {
+		case PayoutBatchGenerated, PayoutBatchSubmitted, PayoutBatchSettled, PayoutBatchFailed:
+		default:
+			return nil, nil, validationError(fmt.Sprintf("status must be one of: %s, %s, %s, %s", PayoutBatchGenerated, PayoutBatchSubmitted, PayoutBatchSettled, PayoutBatchFailed))
+		}
+		params.Status = sql.NullString{String: normalized, Valid: true}
+	}
+
+	rows, err := s.queries.ListPayoutBatchesCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	batches := make([]PayoutBatchOutput, 0, len(rows))
+	for _, row := range rows {
+		batches = append(batches, mapPayoutBatch(repository.GetPayoutBatchRow(row)))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return batches, nextCursor, nil
+}
+
+func (s *Service) GetPayoutBatchFile(ctx context.Context, batchID string) (PayoutBatchFileOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetPayoutBatchFile")
+	defer span.End()
+
+	// Batch files carry full account numbers, so downloading them takes the same permission as revealing one.
+	if err := s.ensureCanRevealBankDetails(ctx); err != nil {
+		return PayoutBatchFileOutput{}, err
+	}
+	file, err := s.queries.GetPayoutBatchFile(ctx, repository.GetPayoutBatchFileParams{
+		OrganizationID: organizationID(ctx),
+		ID:             batchID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PayoutBatchFileOutput{}, notFoundError("payout batch not found")
+		}
+		return PayoutBatchFileOutput{}, err
+	}
+	if err := recordAudit(ctx, s.queries, auditEntry{
+		Action:     "payout_batch.downloaded",
+		EntityType: AuditEntityPayoutBatch,
+		EntityID:   batchID,
+	}); err != nil {
+		return PayoutBatchFileOutput{}, err
+	}
+
+	contentType := "text/plain; charset=us-ascii"
+	if file.Format == PayoutFormatPix {
+		contentType = "application/json"
+	}
+	return PayoutBatchFileOutput{FileName: file.FileName, ContentType: contentType, Content: file.FileContent}, nil
+}
+
+func (s *Service) UpdatePayoutBatchStatus(ctx context.Context, batchID string, input UpdatePayoutBatchStatusInput) (PayoutBatchOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdatePayoutBatchStatus")
+	defer span.End()
+
+	target := strings.ToUpper(strings.TrimSpace(input.Status))
+	reason := optionalString(input.Reason)
+	if target == PayoutBatchFailed && !reason.Valid {
+		return PayoutBatchOutput{}, validationError("reason is required when a batch fails")
+	}
+	if target != PayoutBatchFailed && reason.Valid {
+		return PayoutBatchOutput{}, validationError("reason is only accepted when a batch fails")
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return PayoutBatchOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	current, err := qtx.LockPayoutBatchForUpdate(ctx, repository.LockPayoutBatchForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             batchID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PayoutBatchOutput{}, notFoundError("payout batch not found")
+		}
+		return PayoutBatchOutput{}, err
+	}
+	if err := ensurePayoutBatchTransition(current, target); err != nil {
+		return PayoutBatchOutput{}, err
+	}
+	// A hold placed after generation must stop the file from reaching the bank.
+	if target == PayoutBatchSubmitted {
+		held, err := qtx.CountPayoutBatchItemsOnFinancialHold(ctx, repository.CountPayoutBatchItemsOnFinancialHoldParams{
+			OrganizationID: organizationID(ctx),
+			BatchID:        batchID,
+		})
+		if err != nil {
+			return PayoutBatchOutput{}, err
+		}
+		if held > 0 {
+			return PayoutBatchOutput{}, financialHoldError(fmt.Sprintf("%d clinic(s) in this batch have an active financial hold; mark it as failed and generate a new batch", held))
+		}
+	}
+	if err := qtx.UpdatePayoutBatchStatus(ctx, repository.UpdatePayoutBatchStatusParams{
+		OrganizationID: organizationID(ctx),
+		ID:             batchID,
+		Status:         target,
+		FailureReason:  reason,
+	}); err != nil {
+		return PayoutBatchOutput{}, mapDatabaseError(err)
+	}
+	if target == PayoutBatchSettled {
+		if err := postPayoutBatchLedger(ctx, qtx, batchID); err != nil {
+			return PayoutBatchOutput{}, err
+		}
+		if err := s.enqueuePayoutReceipts(ctx, qtx, batchID); err != nil {
+			return PayoutBatchOutput{}, err
+		}
+	}
+	// A failed batch paid nobody; its payables go back to the open pool for the next one.
+	if target == PayoutBatchFailed {
+		if _, err := qtx.ReleasePayoutBatchPayables(ctx, repository.ReleasePayoutBatchPayablesParams{
+			OrganizationID: organizationID(ctx),
+			BatchID:        batchID,
+		}); err != nil {
+			return PayoutBatchOutput{}, mapDatabaseError(err)
+		}
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "payout_batch.status_changed",
+		EntityType: AuditEntityPayoutBatch,
+		EntityID:   batchID,
+		Metadata:   map[string]any{"from": current, "to": target},
+	}); err != nil {
+		return PayoutBatchOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return PayoutBatchOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.GetPayoutBatch(ctx, batchID)
+}
+
+func (s *Service) payoutBatchParams(input CreatePayoutBatchInput) (repository.CreatePayoutBatchParams, error) {
+	format := strings.ToUpper(strings.TrimSpace(input.Format))
+	if format != PayoutFormatCNAB240 && format != PayoutFormatPix {
+		return repository.CreatePayoutBatchParams{}, validationError(fmt.Sprintf("format must be one of: %s, %s", PayoutFormatCNAB240, PayoutFormatPix))
+	}
+	periodStart, err := parseDocumentDate("period_start", &input.PeriodStart)
+	if err != nil {
+		return repository.CreatePayoutBatchParams{}, err
+	}
+	periodEnd, err := parseDocumentDate("period_end", &input.PeriodEnd)
+	if err != nil {
+		return repository.CreatePayoutBatchParams{}, err
+	}
+	paymentDate, err := parseDocumentDate("payment_date", &input.PaymentDate)
+	if err != nil {
+		return repository.CreatePayoutBatchParams{}, err
+	}
+	if periodEnd.Time.Before(periodStart.Time) {
+		return repository.CreatePayoutBatchParams{}, validationError("period_end must not be before period_start")
+	}
+	if periodEnd.Time.Sub(periodStart.Time) > maxPayablePeriodDays*24*time.Hour {
+		return repository.CreatePayoutBatchParams{}, validationError(fmt.Sprintf("period must span at most %d days", maxPayablePeriodDays))
+	}
+	today := s.now().UTC().Format(documentDateLayout)
+	if paymentDate.Time.Format(documentDateLayout) < today {
+		return repository.CreatePayoutBatchParams{}, validationError("payment_date must not be in the past")
+	}
+	return repository.CreatePayoutBatchParams{
+		Format:      format,
+		PeriodStart: periodStart.Time,
+		PeriodEnd:   periodEnd.Time,
+		PaymentDate: paymentDate.Time,
+	}, nil
+}
+
+func (s *Service) encodePayoutBatch(batch repository.PayoutBatch, items []repository.CreatePayoutBatchItemParams, payments []cnab.Payment, total int64) (string, []byte, error) {
+	generatedAt := s.now().UTC()
+	if batch.Format == PayoutFormatCNAB240 {
+		content, err := cnab.Encode240(cnab.File{
+			Sequence:    batch.FileSequence,
+			GeneratedAt: generatedAt,
+			PaymentDate: batch.PaymentDate,
+			Payer:       *s.payoutPayer,
+			Payments:    payments,
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		return fmt.Sprintf("payout-%06d.rem", batch.FileSequence), content, nil
+	}
+
+	payload := pixBatchPayload{
+		BatchID:     batch.ID,
+		Sequence:    batch.FileSequence,
+		GeneratedAt: generatedAt,
+		PaymentDate: batch.PaymentDate.Format(documentDateLayout),
+		Payer: pixParty{
+			Name:          s.payoutPayer.Name,
+			TaxID:         validation.NormalizeCNPJ(s.payoutPayer.TaxID),
+			BankCode:      s.payoutPayer.Account.BankCode,
+			BranchNumber:  s.payoutPayer.Account.BranchNumber,
+			AccountNumber: s.payoutPayer.Account.AccountNumber,
+		},
+		TotalCents: total,
+		Payments:   make([]pixPayment, 0, len(payments)),
+	}
+	for idx, payment := range payments {
+		payload.Payments = append(payload.Payments, pixPayment{
+			Reference:   payment.Reference,
+			AmountCents: payment.AmountCents,
+			Payee: pixParty{
+				Name:          payment.HolderName,
+				TaxID:         payment.HolderTaxID,
+				BankCode:      payment.Account.BankCode,
+				BranchNumber:  payment.Account.BranchNumber,
+				AccountNumber: payment.Account.AccountNumber,
+				AccountType:   pixAccountTypes[items[idx].AccountType],
+			},
+		})
+	}
+	content, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		return "", nil, fmt.Errorf("encode pix batch: %w", err)
+	}
+	return fmt.Sprintf("payout-%06d.json", batch.FileSequence), content, nil
+}
+
+// Mirrors the payout-account rules, reporting why a clinic cannot be paid instead of failing the whole batch.
+// Payouts hit the ledger only once the bank confirms settlement, dated on the batch payment date.
+func postPayoutBatchLedger(ctx context.Context, q repository.Querier, batchID string) error {
+	batch, err := q.GetPayoutBatch(ctx, repository.GetPayoutBatchParams{
+		OrganizationID: organizationID(ctx),
+		ID:             batchID,
+	})
+	if err != nil {
+		return err
+	}
+	items, err := q.ListPayoutBatchItems(ctx, repository.ListPayoutBatchItemsParams{
+		OrganizationID: organizationID(ctx),
+		BatchID:        batchID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if _, err := postLedgerTransaction(ctx, q, ledgerTransaction{
+			ClinicID:      item.ClinicID,
+			Kind:          LedgerKindPayout,
+			Description:   fmt.Sprintf("Payout batch %06d", batch.FileSequence),
+			ReferenceType: ledgerReferencePayoutBatch,
+			ReferenceID:   batchID,
+			OccurredOn:    batch.PaymentDate,
+			Entries:       clinicLedgerEntries(LedgerKindPayout, -item.AmountCents),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func selectPayoutAccount(accounts []repository.BankAccount) (repository.BankAccount, string) {
+	idx := slices.IndexFunc(accounts, func(account repository.BankAccount) bool { return account.IsPrimary })
+	if idx < 0 {
+		return repository.BankAccount{}, "clinic has no primary bank account"
+	}
+	account := accounts[idx]
+	if account.VerificationStatus != BankVerificationVerified {
+		return repository.BankAccount{}, "primary bank account is not verified"
+	}
+	if account.HolderReviewRequired {
+		return repository.BankAccount{}, "primary bank account holder is pending review"
+	}
+	return account, ""
+}
+
+func ensurePayoutBatchTransition(from string, to string) error {
+	if from == to {
+		return conflictError(fmt.Sprintf("payout batch is already %s", strings.ToLower(from)))
+	}
+	if !slices.Contains(payoutBatchTransitions[from], to) {
+		return conflictError(fmt.Sprintf("payout batch cannot move from %s to %s", from, to))
+	}
+	return nil
+}
+
+func mapPayoutBatch(batch repository.GetPayoutBatchRow) PayoutBatchOutput {
+	skipped := make([]PayoutSkippedClinic, 0)
+	if len(batch.Skipped) > 0 {
+		_ = json.Unmarshal(batch.Skipped, &skipped)
+	}
+	return PayoutBatchOutput{
+		ID:              batch.ID,
+		FileSequence:    batch.FileSequence,
+		Format:          batch.Format,
+		Status:          batch.Status,
+		PeriodStart:     batch.PeriodStart.Format(documentDateLayout),
+		PeriodEnd:       batch.PeriodEnd.Format(documentDateLayout),
+		PaymentDate:     batch.PaymentDate.Format(documentDateLayout),
+		TotalCents:      batch.TotalCents,
+		ItemCount:       int(batch.ItemCount),
+		FileName:        batch.FileName,
+		FailureReason:   nullToPointer(batch.FailureReason),
+		CreatedByUserID: nullUUIDToPointer(batch.CreatedByUserID),
+		Skipped:         skipped,
+		StatusChangedAt: batch.StatusChangedAt,
+		CreatedAt:       batch.CreatedAt,
+	}
+}
+
+func mapClinicPayable(payable repository.ClinicPayable) ClinicPayableOutput {
+	return ClinicPayableOutput{
+		ID:                payable.ID,
+		ClinicID:          payable.ClinicID,
+		AmountCents:       payable.AmountCents,
+		Description:       payable.Description,
+		ExternalReference: nullToPointer(payable.ExternalReference),
+		OccurredOn:        payable.OccurredOn.Format(documentDateLayout),
+		PayoutBatchID:     nullUUIDToPointer(payable.PayoutBatchID),
+		CreatedAt:         payable.CreatedAt,
+	}
+}
+
+type payoutReceiptData struct {
+	ClinicName   string
+	FileSequence int64
+	PaymentDate  string
+	Amount       string
+	PayableCount int32
+}
+
+// enqueuePayoutReceipts tells each clinic paid by a settled batch how much it received, on every channel it has a contact for.
+func (s *Service) enqueuePayoutReceipts(ctx context.Context, qtx repository.Querier, batchID string) error {
+	if len(s.notificationSenders) == 0 {
+		return nil
+	}
+	recipients, err := qtx.ListPayoutReceiptRecipients(ctx, repository.ListPayoutReceiptRecipientsParams{
+		OrganizationID: organizationID(ctx),
+		BatchID:        batchID,
+	})
+	if err != nil {
+		return err
+	}
+	for _, recipient := range recipients {
+		name := recipient.LegalName
+		if recipient.TradeName.Valid && strings.TrimSpace(recipient.TradeName.String) != "" {
+			name = recipient.TradeName.String
+		}
+		data := payoutReceiptData{
+			ClinicName:   name,
+			FileSequence: recipient.FileSequence,
+			PaymentDate:  recipient.PaymentDate.Format("02/01/2006"),
+			Amount:       formatBRL(recipient.AmountCents),
+			PayableCount: recipient.PayableCount,
+		}
+		contacts := make(map[string]string)
+		if email := strings.TrimSpace(recipient.Email.String); email != "" {
+			contacts[NotificationChannelEmail] = email
+		}
+		if phone, ok := validation.NormalizePhone(recipient.Phone.String); ok {
+			contacts[NotificationChannelSMS] = phone
+			contacts[NotificationChannelWhatsApp] = phone
+		}
+		for _, channel := range []string{NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWhatsApp} {
+			contact, ok := contacts[channel]
+			if !ok {
+				continue
+			}
+			if err := s.enqueueNotification(ctx, qtx, notificationRequest{
+				Channel:   channel,
+				Template:  NotificationTemplatePayoutReceipt,
+				Recipient: contact,
+				ClinicID:  recipient.ClinicID,
+				DedupeKey: NotificationTemplatePayoutReceipt + ":" + batchID + ":" + recipient.ClinicID + ":" + channel,
+				Data:      data,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// formatBRL writes cents as Brazilian reais, as in R$ 1.234,56.
+func formatBRL(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	units := strconv.FormatInt(cents/100, 10)
+	var grouped strings.Builder
+	for i, digit := range units {
+		if i > 0 && (len(units)-i)%3 == 0 {
+			grouped.WriteByte('.')
+		}
+		grouped.WriteRune(digit)
+	}
+	return fmt.Sprintf("%sR$ %s,%02d", sign, grouped.String(), cents%100)
+}
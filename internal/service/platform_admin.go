@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"go.opentelemetry.io/otel"
+	"golang.org/x/crypto/bcrypt"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	// Operator tokens carry this audience and are signed with their own key, so neither realm accepts the other's tokens.
+	adminTokenAudience     = "capim-admin"
+	impersonationTokenTTL  = 15 * time.Minute
+	maxImpersonationReason = 500
+
+	AuditEntityUser = "USER"
+)
+
+type operatorTokenClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+type Operator struct {
+	ID    string
+	Email string
+}
+
+type operatorContextKey struct{}
+
+func WithOperator(ctx context.Context, operator Operator) context.Context {
+	return context.WithValue(ctx, operatorContextKey{}, operator)
+}
+
+func OperatorFromContext(ctx context.Context) (Operator, bool) {
+	operator, ok := ctx.Value(operatorContextKey{}).(Operator)
+	return operator, ok
+}
+
+func WithAdminAuthConfig(signingKey string) Option {
+	return func(s *Service) {
+		s.adminSigningKey = []byte(strings.TrimSpace(signingKey))
+	}
+}
+
+func (s *Service) EnsurePlatformOperator(ctx context.Context, email string, password string) error {
+	normalizedEmail := strings.ToLower(strings.TrimSpace(email))
+	if !validation.ValidateEmail(normalizedEmail) {
+		return validationError("invalid email")
+	}
+	if len(password) < 8 {
+		return validationError("password must have at least 8 characters")
+	}
+
+	_, err := s.queries.GetPlatformOperatorByEmail(ctx, normalizedEmail)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+	operatorID, err := newUUIDV7()
+	if err != nil {
+		return err
+	}
+	if _, err := s.queries.CreatePlatformOperator(ctx, repository.CreatePlatformOperatorParams{
+		ID:           operatorID,
+		Email:        normalizedEmail,
+		PasswordHash: string(passwordHash),
+	}); err != nil {
+		if isUniqueConstraintError(err) {
+			return nil
+		}
+		return mapDatabaseError(err)
+	}
+	return nil
+}
+
+func (s *Service) AdminLogin(ctx context.Context, input LoginInput) (AdminLoginOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.AdminLogin")
+	defer span.End()
+
+	email := strings.ToLower(strings.TrimSpace(input.Email))
+	if !validation.ValidateEmail(email) {
+		return AdminLoginOutput{}, validationError("invalid email")
+	}
+	if strings.TrimSpace(input.Password) == "" {
+		return AdminLoginOutput{}, validationError("password is required")
+	}
+	if len(s.adminSigningKey) == 0 {
+		return AdminLoginOutput{}, fmt.Errorf("admin signing key is not configured")
+	}
+
+	operator, err := s.queries.GetPlatformOperatorByEmail(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			_ = bcrypt.CompareHashAndPassword([]byte(dummyPasswordHash), []byte(input.Password))
+			return AdminLoginOutput{}, unauthorizedError("invalid credentials")
+		}
+		return AdminLoginOutput{}, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(operator.PasswordHash), []byte(input.Password)); err != nil {
+		return AdminLoginOutput{}, unauthorizedError("invalid credentials")
+	}
+
+	now := s.now().UTC()
+	expiresAt := now.Add(s.jwtAccessTokenTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, operatorTokenClaims{
+		Email: operator.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.jwtIssuer,
+			Subject:   operator.ID,
+			Audience:  jwt.ClaimStrings{adminTokenAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	signedToken, err := token.SignedString(s.adminSigningKey)
+	if err != nil {
+		return AdminLoginOutput{}, fmt.Errorf("sign admin token: %w", err)
+	}
+
+	return AdminLoginOutput{
+		AccessToken: signedToken,
+		TokenType:   "Bearer",
+		ExpiresIn:   int64(expiresAt.Sub(now).Seconds()),
+		OperatorID:  operator.ID,
+		Email:       operator.Email,
+	}, nil
+}
+
+func (s *Service) AuthenticateOperatorToken(token string) (Operator, error) {
+	if strings.TrimSpace(token) == "" || len(s.adminSigningKey) == 0 {
+		return Operator{}, unauthorizedError("invalid token")
+	}
+	claims := &operatorTokenClaims{}
+	parsedToken, err := jwt.ParseWithClaims(
+		token,
+		claims,
+		func(token *jwt.Token) (any, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, unauthorizedError("invalid token")
+			}
+			return s.adminSigningKey, nil
+		},
+		jwt.WithIssuer(s.jwtIssuer),
+		jwt.WithAudience(adminTokenAudience),
+	)
+	if err != nil || !parsedToken.Valid || strings.TrimSpace(claims.Subject) == "" {
+		return Operator{}, unauthorizedError("invalid token")
+	}
+	return Operator{ID: claims.Subject, Email: claims.Email}, nil
+}
+
+func (s *Service) ListTenants(ctx context.Context) ([]TenantOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListTenants")
+	defer span.End()
+
+	organizations, err := s.queries.ListOrganizations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	output := make([]TenantOutput, 0, len(organizations))
+	for _, organization := range organizations {
+		tenant, err := s.tenantOutput(WithOrganization(ctx, organization.ID), organization)
+		if err != nil {
+			return nil, err
+		}
+		output = append(output, tenant)
+	}
+	return output, nil
+}
+
+func (s *Service) GetTenant(ctx context.Context, id string) (TenantOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetTenant")
+	defer span.End()
+
+	ctx = WithOrganization(ctx, id)
+	organization, err := s.queries.GetOrganizationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TenantOutput{}, notFoundError("organization not found")
+		}
+		return TenantOutput{}, err
+	}
+	return s.tenantOutput(ctx, organization)
+}
+
+func (s *Service) tenantOutput(ctx context.Context, organization repository.Organization) (TenantOutput, error) {
+	usage, err := s.GetOrganizationUsage(ctx)
+	if err != nil {
+		return TenantOutput{}, err
+	}
+	output := TenantOutput{
+		OrganizationOutput: mapOrganizationOutput(organization),
+		Usage:              usage,
+	}
+	if s.traffic != nil {
+		output.Traffic = s.traffic.summary(organization.ID, s.now())
+	} else {
+		output.Traffic = TenantTrafficOutput{WindowMinutes: tenantTrafficBuckets}
+	}
+	return output, nil
+}
+
+// ImpersonateTenantAdmin issues a short-lived tenant token for one of the organization's admins on behalf of a platform operator.
+// The token names the operator, and every audit entry written with it records who was behind the session.
+func (s *Service) ImpersonateTenantAdmin(ctx context.Context, id string, input ImpersonateTenantInput) (LoginOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ImpersonateTenantAdmin")
+	defer span.End()
+
+	operator, ok := OperatorFromContext(ctx)
+	if !ok {
+		return LoginOutput{}, unauthorizedError("operator is required")
+	}
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return LoginOutput{}, validationError("reason is required")
+	}
+	if len([]rune(reason)) > maxImpersonationReason {
+		return LoginOutput{}, validationError(fmt.Sprintf("reason must have at most %d characters", maxImpersonationReason))
+	}
+	if len(s.jwtSigningKey) == 0 {
+		return LoginOutput{}, fmt.Errorf("jwt signing key is not configured")
+	}
+
+	ctx = WithOrganization(ctx, id)
+	organization, err := s.queries.GetOrganizationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LoginOutput{}, notFoundError("organization not found")
+		}
+		return LoginOutput{}, err
+	}
+	if organization.Status == OrganizationStatusClosed {
+		return LoginOutput{}, conflictError("organization is closed")
+	}
+
+	var user repository.User
+	if userID := strings.TrimSpace(input.UserID); userID != "" {
+		user, err = s.queries.GetUserByID(ctx, repository.GetUserByIDParams{OrganizationID: id, ID: userID})
+	} else {
+		user, err = s.queries.GetOldestAdminUser(ctx, id)
+	}
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return LoginOutput{}, notFoundError("tenant admin not found")
+		}
+		return LoginOutput{}, err
+	}
+	if user.Role != UserRoleAdmin {
+		return LoginOutput{}, validationError("only tenant admins can be impersonated")
+	}
+
+	if err := recordAudit(ctx, s.queries, auditEntry{
+		Action:     "user.impersonated",
+		EntityType: AuditEntityUser,
+		EntityID:   user.ID,
+		Metadata: map[string]any{
+			"operator_id":    operator.ID,
+			"operator_email": operator.Email,
+			"reason":         reason,
+		},
+	}); err != nil {
+		return LoginOutput{}, err
+	}
+
+	now := s.now().UTC()
+	expiresAt := now.Add(min(s.jwtAccessTokenTTL, impersonationTokenTTL))
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims{
+		Email:          user.Email,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
+		ImpersonatedBy: operator.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.jwtIssuer,
+			Subject:   user.ID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	signedToken, err := token.SignedString(s.jwtSigningKey)
+	if err != nil {
+		return LoginOutput{}, fmt.Errorf("sign access token: %w", err)
+	}
+
+	return LoginOutput{
+		AccessToken:    signedToken,
+		TokenType:      "Bearer",
+		ExpiresIn:      int64(expiresAt.Sub(now).Seconds()),
+		UserID:         user.ID,
+		Email:          user.Email,
+		Role:           user.Role,
+		OrganizationID: user.OrganizationID,
+	}, nil
+}
+
+func isAdminToken(claims jwt.RegisteredClaims) bool {
+	return slices.Contains(claims.Audience, adminTokenAudience)
+}
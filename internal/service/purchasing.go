@@ -0,0 +1,797 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	PurchaseOrderDraft             = "DRAFT"
+	PurchaseOrderSubmitted         = "SUBMITTED"
+	PurchaseOrderPartiallyReceived = "PARTIALLY_RECEIVED"
+	PurchaseOrderReceived          = "RECEIVED"
+	PurchaseOrderCancelled         = "CANCELLED"
+
+	defaultSupplierSpendRangeDays = 30
+	maxSupplierSpendRangeDays     = 366
+)
+
+// CreateSupplier registers a company as a supplier of the organization. A CNPJ already on file, such as one of the
+// organization's own clinics, is linked as is; its registration is not overwritten by the supplier form.
+func (s *Service) CreateSupplier(ctx context.Context, input CreateSupplierInput) (SupplierOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateSupplier")
+	defer span.End()
+
+	validations := s.newValidationCollector()
+	taxID := validation.NormalizeCNPJ(input.TaxIDNumber)
+	if taxID == "" {
+		return SupplierOutput{}, validationError("invalid CNPJ")
+	}
+	if err := validations.check(ValidationRuleTaxID, validation.ValidateCNPJ(taxID), "invalid CNPJ"); err != nil {
+		return SupplierOutput{}, err
+	}
+	if strings.TrimSpace(input.LegalName) == "" {
+		return SupplierOutput{}, validationError("legal_name is required")
+	}
+	if err := validateClinicFieldsLength(&input.TaxIDNumber, &input.LegalName, input.TradeName, input.Email, input.Phone); err != nil {
+		return SupplierOutput{}, err
+	}
+	if err := validateEmailInput(input.Email, validations); err != nil {
+		return SupplierOutput{}, err
+	}
+	phone, err := normalizePhoneInput(input.Phone, validations)
+	if err != nil {
+		return SupplierOutput{}, err
+	}
+	supplierID, err := newUUIDV7()
+	if err != nil {
+		return SupplierOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return SupplierOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	person, err := qtx.GetPersonByTaxID(ctx, repository.GetPersonByTaxIDParams{
+		OrganizationID: organizationID(ctx),
+		TaxIDNumber:    taxID,
+	})
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return SupplierOutput{}, err
+		}
+		personID, err := newUUIDV7()
+		if err != nil {
+			return SupplierOutput{}, err
+		}
+		person, err = qtx.CreatePerson(ctx, repository.CreatePersonParams{
+			OrganizationID: organizationID(ctx),
+			ID:             personID,
+			PersonType:     personTypeCompany,
+			TaxIDType:      taxIDTypeCNPJ,
+			TaxIDNumber:    taxID,
+			LegalName:      strings.TrimSpace(input.LegalName),
+			TradeName:      optionalString(input.TradeName),
+			Email:          optionalString(input.Email),
+			Phone:          optionalString(phone),
+		})
+		if err != nil {
+			return SupplierOutput{}, mapDatabaseError(err)
+		}
+	}
+	if person.PersonType != personTypeCompany {
+		return SupplierOutput{}, conflictError("tax_id is linked to an individual person")
+	}
+	if _, err := qtx.CreateSupplier(ctx, repository.CreateSupplierParams{
+		ID:             supplierID,
+		OrganizationID: organizationID(ctx),
+		PersonID:       person.ID,
+		Notes:          optionalString(input.Notes),
+	}); err != nil {
+		if isUniqueConstraintError(err) {
+			return SupplierOutput{}, conflictError("supplier already registered")
+		}
+		return SupplierOutput{}, mapDatabaseError(err)
+	}
+	supplier, err := qtx.GetSupplier(ctx, repository.GetSupplierParams{
+		OrganizationID: organizationID(ctx),
+		ID:             supplierID,
+	})
+	if err != nil {
+		return SupplierOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return SupplierOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapSupplier(repository.ListSuppliersCursorRow(supplier)), nil
+}
+
+func (s *Service) GetSupplier(ctx context.Context, supplierID string) (SupplierOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetSupplier")
+	defer span.End()
+
+	supplier, err := s.queries.GetSupplier(ctx, repository.GetSupplierParams{
+		OrganizationID: organizationID(ctx),
+		ID:             supplierID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SupplierOutput{}, notFoundError("supplier not found")
+		}
+		return SupplierOutput{}, err
+	}
+	return mapSupplier(repository.ListSuppliersCursorRow(supplier)), nil
+}
+
+func (s *Service) ListSuppliers(ctx context.Context, limit int, cursor *string) ([]SupplierOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListSuppliers")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListSuppliersCursorParams{
+		OrganizationID: organizationID(ctx),
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+
+	rows, err := s.queries.ListSuppliersCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	suppliers := make([]SupplierOutput, 0, len(rows))
+	for _, row := range rows {
+		suppliers = append(suppliers, mapSupplier(row))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return suppliers, nextCursor, nil
+}
+
+// UpdateSupplier edits the supplier's person record, which a clinic sharing the same CNPJ sees as well.
+func (s *Service) UpdateSupplier(ctx context.Context, supplierID string, input UpdateSupplierInput) (SupplierOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateSupplier")
+	defer span.End()
+
+	validations := s.newValidationCollector()
+	if input.LegalName != nil && strings.TrimSpace(*input.LegalName) == "" {
+		return SupplierOutput{}, validationError("legal_name must not be empty")
+	}
+	if err := validateClinicFieldsLength(nil, input.LegalName, input.TradeName, input.Email, input.Phone); err != nil {
+		return SupplierOutput{}, err
+	}
+	if err := validateEmailInput(input.Email, validations); err != nil {
+		return SupplierOutput{}, err
+	}
+	phone, err := normalizePhoneInput(input.Phone, validations)
+	if err != nil {
+		return SupplierOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return SupplierOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	supplier, err := qtx.GetSupplier(ctx, repository.GetSupplierParams{
+		OrganizationID: organizationID(ctx),
+		ID:             supplierID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SupplierOutput{}, notFoundError("supplier not found")
+		}
+		return SupplierOutput{}, err
+	}
+	if input.LegalName != nil || input.TradeName != nil || input.Email != nil || input.Phone != nil {
+		if _, err := qtx.UpdatePerson(ctx, repository.UpdatePersonParams{
+			OrganizationID: organizationID(ctx),
+			ID:             supplier.PersonID,
+			LegalName:      optionalString(input.LegalName),
+			TradeName:      optionalString(input.TradeName),
+			Email:          optionalString(input.Email),
+			Phone:          optionalString(phone),
+		}); err != nil {
+			return SupplierOutput{}, mapDatabaseError(err)
+		}
+	}
+	if input.Notes != nil {
+		// Empty notes clear them.
+		if err := qtx.UpdateSupplierNotes(ctx, repository.UpdateSupplierNotesParams{
+			OrganizationID: organizationID(ctx),
+			ID:             supplierID,
+			Notes:          strings.TrimSpace(*input.Notes),
+		}); err != nil {
+			return SupplierOutput{}, mapDatabaseError(err)
+		}
+	}
+	supplier, err = qtx.GetSupplier(ctx, repository.GetSupplierParams{
+		OrganizationID: organizationID(ctx),
+		ID:             supplierID,
+	})
+	if err != nil {
+		return SupplierOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return SupplierOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapSupplier(repository.ListSuppliersCursorRow(supplier)), nil
+}
+
+// DeleteSupplier keeps the supplier's past orders and their spend; it only refuses while an order is still open.
+func (s *Service) DeleteSupplier(ctx context.Context, supplierID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteSupplier")
+	defer span.End()
+
+	if _, err := s.GetSupplier(ctx, supplierID); err != nil {
+		return err
+	}
+	open, err := s.queries.HasOpenSupplierPurchaseOrders(ctx, repository.HasOpenSupplierPurchaseOrdersParams{
+		OrganizationID: organizationID(ctx),
+		SupplierID:     supplierID,
+	})
+	if err != nil {
+		return err
+	}
+	if open {
+		return conflictError("supplier has open purchase orders")
+	}
+	deleted, err := s.queries.DeleteSupplier(ctx, repository.DeleteSupplierParams{
+		OrganizationID: organizationID(ctx),
+		ID:             supplierID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return notFoundError("supplier not found")
+	}
+	return nil
+}
+
+// CreatePurchaseOrder drafts an order from a supplier for items of the clinic's inventory. Lines are fixed once created;
+// a wrong draft is cancelled and drafted again.
+func (s *Service) CreatePurchaseOrder(ctx context.Context, clinicID string, input CreatePurchaseOrderInput) (PurchaseOrderOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreatePurchaseOrder")
+	defer span.End()
+
+	if _, err := uuid.Parse(input.SupplierID); err != nil {
+		return PurchaseOrderOutput{}, validationError("invalid supplier_id")
+	}
+	seen := make(map[string]struct{}, len(input.Items))
+	for _, line := range input.Items {
+		if _, err := uuid.Parse(line.ItemID); err != nil {
+			return PurchaseOrderOutput{}, validationError("invalid item_id")
+		}
+		if _, duplicate := seen[line.ItemID]; duplicate {
+			return PurchaseOrderOutput{}, validationError(fmt.Sprintf("item %s appears more than once", line.ItemID))
+		}
+		seen[line.ItemID] = struct{}{}
+		if line.Quantity <= 0 {
+			return PurchaseOrderOutput{}, validationError("quantity must be greater than zero")
+		}
+		if line.UnitCostCents < 0 {
+			return PurchaseOrderOutput{}, validationError("unit_cost_cents must not be negative")
+		}
+	}
+	orderID, err := newUUIDV7()
+	if err != nil {
+		return PurchaseOrderOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return PurchaseOrderOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	if _, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PurchaseOrderOutput{}, notFoundError("clinic not found")
+		}
+		return PurchaseOrderOutput{}, err
+	}
+	if _, err := qtx.GetSupplier(ctx, repository.GetSupplierParams{
+		OrganizationID: organizationID(ctx),
+		ID:             input.SupplierID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PurchaseOrderOutput{}, notFoundError("supplier not found")
+		}
+		return PurchaseOrderOutput{}, err
+	}
+	order, err := qtx.CreatePurchaseOrder(ctx, repository.CreatePurchaseOrderParams{
+		ID:              orderID,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		SupplierID:      input.SupplierID,
+		Notes:           optionalString(input.Notes),
+		CreatedByUserID: auditActorID(ctx),
+	})
+	if err != nil {
+		return PurchaseOrderOutput{}, mapDatabaseError(err)
+	}
+	for _, line := range input.Items {
+		if _, err := qtx.GetInventoryItem(ctx, repository.GetInventoryItemParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+			ID:             line.ItemID,
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return PurchaseOrderOutput{}, notFoundError(fmt.Sprintf("inventory item %s not found at the clinic", line.ItemID))
+			}
+			return PurchaseOrderOutput{}, err
+		}
+		lineID, err := newUUIDV7()
+		if err != nil {
+			return PurchaseOrderOutput{}, err
+		}
+		if _, err := qtx.CreatePurchaseOrderItem(ctx, repository.CreatePurchaseOrderItemParams{
+			ID:              lineID,
+			OrganizationID:  organizationID(ctx),
+			PurchaseOrderID: order.ID,
+			ItemID:          line.ItemID,
+			QuantityOrdered: line.Quantity,
+			UnitCostCents:   line.UnitCostCents,
+		}); err != nil {
+			return PurchaseOrderOutput{}, mapDatabaseError(err)
+		}
+	}
+	items, err := qtx.ListPurchaseOrderItems(ctx, repository.ListPurchaseOrderItemsParams{
+		OrganizationID:   organizationID(ctx),
+		PurchaseOrderIds: []string{order.ID},
+	})
+	if err != nil {
+		return PurchaseOrderOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return PurchaseOrderOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapPurchaseOrder(order, items), nil
+}
+
+func (s *Service) GetPurchaseOrder(ctx context.Context, clinicID string, orderID string) (PurchaseOrderOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetPurchaseOrder")
+	defer span.End()
+
+	order, err := s.queries.GetPurchaseOrder(ctx, repository.GetPurchaseOrderParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             orderID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PurchaseOrderOutput{}, notFoundError("purchase order not found")
+		}
+		return PurchaseOrderOutput{}, err
+	}
+	items, err := s.queries.ListPurchaseOrderItems(ctx, repository.ListPurchaseOrderItemsParams{
+		OrganizationID:   organizationID(ctx),
+		PurchaseOrderIds: []string{order.ID},
+	})
+	if err != nil {
+		return PurchaseOrderOutput{}, err
+	}
+	return mapPurchaseOrder(order, items), nil
+}
+
+func (s *Service) ListPurchaseOrders(ctx context.Context, clinicID string, limit int, cursor *string, status *string, supplierID *string) ([]PurchaseOrderOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListPurchaseOrders")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil, notFoundError("clinic not found")
+		}
+		return nil, nil, err
+	}
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListPurchaseOrdersCursorParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		PageLimit:      int32(pageLimit + 1),
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+	if status != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*status))
+		switch normalized {
+		case PurchaseOrderDraft, PurchaseOrderSubmitted, PurchaseOrderPartiallyReceived, PurchaseOrderReceived, PurchaseOrderCancelled:
+		default:
+			return nil, nil, validationError(fmt.Sprintf("status must be one of: %s, %s, %s, %s, %s", PurchaseOrderDraft, PurchaseOrderSubmitted, PurchaseOrderPartiallyReceived, PurchaseOrderReceived, PurchaseOrderCancelled))
+		}
+		params.Status = sql.NullString{String: normalized, Valid: true}
+	}
+	if supplierID != nil {
+		parsedSupplierID, err := uuid.Parse(*supplierID)
+		if err != nil {
+			return nil, nil, validationError("invalid supplier_id")
+		}
+		params.SupplierID = uuid.NullUUID{UUID: parsedSupplierID, Valid: true}
+	}
+
+	rows, err := s.queries.ListPurchaseOrdersCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	orderIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		orderIDs = append(orderIDs, row.ID)
+	}
+	itemsByOrder := make(map[string][]repository.ListPurchaseOrderItemsRow, len(rows))
+	if len(orderIDs) > 0 {
+		items, err := s.queries.ListPurchaseOrderItems(ctx, repository.ListPurchaseOrderItemsParams{
+			OrganizationID:   organizationID(ctx),
+			PurchaseOrderIds: orderIDs,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, item := range items {
+			itemsByOrder[item.PurchaseOrderID] = append(itemsByOrder[item.PurchaseOrderID], item)
+		}
+	}
+	orders := make([]PurchaseOrderOutput, 0, len(rows))
+	for _, row := range rows {
+		orders = append(orders, mapPurchaseOrder(row, itemsByOrder[row.ID]))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return orders, nextCursor, nil
+}
+
+// SubmitPurchaseOrder marks a draft as sent to the supplier, after which it can be received.
+func (s *Service) SubmitPurchaseOrder(ctx context.Context, clinicID string, orderID string) (PurchaseOrderOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.SubmitPurchaseOrder")
+	defer span.End()
+
+	return s.transitionPurchaseOrder(ctx, clinicID, orderID, PurchaseOrderSubmitted, PurchaseOrderDraft)
+}
+
+// CancelPurchaseOrder closes an order that will not be (fully) delivered. Stock already received stays, as does its spend.
+func (s *Service) CancelPurchaseOrder(ctx context.Context, clinicID string, orderID string) (PurchaseOrderOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CancelPurchaseOrder")
+	defer span.End()
+
+	return s.transitionPurchaseOrder(ctx, clinicID, orderID, PurchaseOrderCancelled, PurchaseOrderDraft, PurchaseOrderSubmitted, PurchaseOrderPartiallyReceived)
+}
+
+func (s *Service) transitionPurchaseOrder(ctx context.Context, clinicID string, orderID string, to string, from ...string) (PurchaseOrderOutput, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return PurchaseOrderOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	order, err := qtx.LockPurchaseOrderForUpdate(ctx, repository.LockPurchaseOrderForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             orderID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PurchaseOrderOutput{}, notFoundError("purchase order not found")
+		}
+		return PurchaseOrderOutput{}, err
+	}
+	if !slices.Contains(from, order.Status) {
+		return PurchaseOrderOutput{}, conflictError(fmt.Sprintf("a %s purchase order cannot become %s", order.Status, to))
+	}
+	order, err = qtx.SetPurchaseOrderStatus(ctx, repository.SetPurchaseOrderStatusParams{
+		OrganizationID: organizationID(ctx),
+		ID:             order.ID,
+		Status:         to,
+	})
+	if err != nil {
+		return PurchaseOrderOutput{}, mapDatabaseError(err)
+	}
+	items, err := qtx.ListPurchaseOrderItems(ctx, repository.ListPurchaseOrderItemsParams{
+		OrganizationID:   organizationID(ctx),
+		PurchaseOrderIds: []string{order.ID},
+	})
+	if err != nil {
+		return PurchaseOrderOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return PurchaseOrderOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapPurchaseOrder(order, items), nil
+}
+
+// ReceivePurchaseOrder books a delivery against a submitted order: each line received becomes a PURCHASE movement at the
+// ordered unit cost, all in one transaction with the order row locked so two deliveries cannot overfill the same line.
+func (s *Service) ReceivePurchaseOrder(ctx context.Context, clinicID string, orderID string, input ReceivePurchaseOrderInput) (PurchaseOrderOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ReceivePurchaseOrder")
+	defer span.End()
+
+	seen := make(map[string]struct{}, len(input.Items))
+	for _, line := range input.Items {
+		if _, duplicate := seen[line.OrderItemID]; duplicate {
+			return PurchaseOrderOutput{}, validationError(fmt.Sprintf("order item %s appears more than once", line.OrderItemID))
+		}
+		seen[line.OrderItemID] = struct{}{}
+		if line.Quantity <= 0 {
+			return PurchaseOrderOutput{}, validationError("quantity must be greater than zero")
+		}
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return PurchaseOrderOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	order, err := qtx.LockPurchaseOrderForUpdate(ctx, repository.LockPurchaseOrderForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		ID:             orderID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return PurchaseOrderOutput{}, notFoundError("purchase order not found")
+		}
+		return PurchaseOrderOutput{}, err
+	}
+	if order.Status != PurchaseOrderSubmitted && order.Status != PurchaseOrderPartiallyReceived {
+		return PurchaseOrderOutput{}, conflictError(fmt.Sprintf("a %s purchase order cannot be received", order.Status))
+	}
+	items, err := qtx.ListPurchaseOrderItems(ctx, repository.ListPurchaseOrderItemsParams{
+		OrganizationID:   organizationID(ctx),
+		PurchaseOrderIds: []string{order.ID},
+	})
+	if err != nil {
+		return PurchaseOrderOutput{}, err
+	}
+	lineIndex := make(map[string]int, len(items))
+	for idx, item := range items {
+		lineIndex[item.ID] = idx
+	}
+
+	for _, line := range input.Items {
+		idx, ok := lineIndex[line.OrderItemID]
+		if !ok {
+			return PurchaseOrderOutput{}, validationError(fmt.Sprintf("order item %s is not on this purchase order", line.OrderItemID))
+		}
+		orderItem := items[idx]
+		if outstanding := orderItem.QuantityOrdered - orderItem.QuantityReceived; line.Quantity > outstanding {
+			return PurchaseOrderOutput{}, conflictError(fmt.Sprintf("order item %s has only %d %s outstanding", orderItem.ID, outstanding, orderItem.ItemUnit))
+		}
+		inventoryItem, err := qtx.LockInventoryItemForUpdate(ctx, repository.LockInventoryItemForUpdateParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+			ID:             orderItem.ItemID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return PurchaseOrderOutput{}, conflictError(fmt.Sprintf("inventory item %s was deleted", orderItem.ItemID))
+			}
+			return PurchaseOrderOutput{}, err
+		}
+		movement, err := applyInventoryMovement(ctx, qtx, inventoryItem, InventoryPurchase, line.Quantity, sql.NullInt64{Int64: orderItem.UnitCostCents, Valid: true}, input.Notes)
+		if err != nil {
+			return PurchaseOrderOutput{}, err
+		}
+		if _, err := qtx.ReceivePurchaseOrderItem(ctx, repository.ReceivePurchaseOrderItemParams{
+			OrganizationID: organizationID(ctx),
+			ID:             orderItem.ID,
+			Quantity:       line.Quantity,
+		}); err != nil {
+			return PurchaseOrderOutput{}, mapDatabaseError(err)
+		}
+		receiptID, err := newUUIDV7()
+		if err != nil {
+			return PurchaseOrderOutput{}, err
+		}
+		if _, err := qtx.CreatePurchaseOrderReceipt(ctx, repository.CreatePurchaseOrderReceiptParams{
+			ID:                  receiptID,
+			OrganizationID:      organizationID(ctx),
+			PurchaseOrderID:     order.ID,
+			PurchaseOrderItemID: orderItem.ID,
+			MovementID:          movement.ID,
+			Quantity:            line.Quantity,
+		}); err != nil {
+			return PurchaseOrderOutput{}, mapDatabaseError(err)
+		}
+		items[idx].QuantityReceived += line.Quantity
+	}
+
+	order, err = qtx.SetPurchaseOrderStatus(ctx, repository.SetPurchaseOrderStatusParams{
+		OrganizationID: organizationID(ctx),
+		ID:             order.ID,
+		Status:         purchaseOrderReceiptStatus(items),
+	})
+	if err != nil {
+		return PurchaseOrderOutput{}, mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return PurchaseOrderOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapPurchaseOrder(order, items), nil
+}
+
+func purchaseOrderReceiptStatus(items []repository.ListPurchaseOrderItemsRow) string {
+	for _, item := range items {
+		if item.QuantityReceived < item.QuantityOrdered {
+			return PurchaseOrderPartiallyReceived
+		}
+	}
+	return PurchaseOrderReceived
+}
+
+// GetSupplierSpendReport sums what was received from each supplier in the period at the ordered unit costs, for the whole
+// organization or a single clinic. Cancelled orders count for what they delivered before being cancelled.
+func (s *Service) GetSupplierSpendReport(ctx context.Context, clinicID *string, from *string, to *string) (SupplierSpendReportOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetSupplierSpendReport")
+	defer span.End()
+
+	toDate := s.now().UTC().Truncate(24 * time.Hour)
+	if to != nil {
+		parsed, err := parseDocumentDate("to", to)
+		if err != nil {
+			return SupplierSpendReportOutput{}, err
+		}
+		toDate = parsed.Time
+	}
+	fromDate := toDate.AddDate(0, 0, -defaultSupplierSpendRangeDays)
+	if from != nil {
+		parsed, err := parseDocumentDate("from", from)
+		if err != nil {
+			return SupplierSpendReportOutput{}, err
+		}
+		fromDate = parsed.Time
+	}
+	if fromDate.After(toDate) {
+		return SupplierSpendReportOutput{}, validationError("from must not be after to")
+	}
+	if toDate.Sub(fromDate) > maxSupplierSpendRangeDays*24*time.Hour {
+		return SupplierSpendReportOutput{}, validationError(fmt.Sprintf("spend range must be at most %d days", maxSupplierSpendRangeDays))
+	}
+
+	params := repository.ListSupplierSpendParams{
+		OrganizationID: organizationID(ctx),
+		ReceivedFrom:   fromDate,
+		ReceivedTo:     toDate.AddDate(0, 0, 1),
+	}
+	if clinicID != nil {
+		parsedClinicID, err := uuid.Parse(*clinicID)
+		if err != nil {
+			return SupplierSpendReportOutput{}, validationError("invalid clinic_id")
+		}
+		if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             parsedClinicID.String(),
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return SupplierSpendReportOutput{}, notFoundError("clinic not found")
+			}
+			return SupplierSpendReportOutput{}, err
+		}
+		params.ClinicID = uuid.NullUUID{UUID: parsedClinicID, Valid: true}
+	}
+	rows, err := s.queries.ListSupplierSpend(ctx, params)
+	if err != nil {
+		return SupplierSpendReportOutput{}, err
+	}
+
+	report := SupplierSpendReportOutput{
+		ClinicID:  clinicID,
+		From:      fromDate.Format(documentDateLayout),
+		To:        toDate.Format(documentDateLayout),
+		Suppliers: make([]SupplierSpendOutput, 0, len(rows)),
+	}
+	for _, row := range rows {
+		report.TotalCents += row.SpendCents
+		report.Suppliers = append(report.Suppliers, SupplierSpendOutput{
+			SupplierID:    row.SupplierID,
+			LegalName:     row.LegalName,
+			TaxIDNumber:   row.TaxIDNumber,
+			OrderCount:    row.OrderCount,
+			UnitsReceived: row.UnitsReceived,
+			SpendCents:    row.SpendCents,
+		})
+	}
+	return report, nil
+}
+
+func mapSupplier(supplier repository.ListSuppliersCursorRow) SupplierOutput {
+	return SupplierOutput{
+		ID:          supplier.ID,
+		PersonID:    supplier.PersonID,
+		TaxIDNumber: supplier.TaxIDNumber,
+		LegalName:   supplier.LegalName,
+		TradeName:   nullToPointer(supplier.TradeName),
+		Email:       nullToPointer(supplier.Email),
+		Phone:       nullToPointer(supplier.Phone),
+		Notes:       nullToPointer(supplier.Notes),
+		CreatedAt:   supplier.CreatedAt,
+		UpdatedAt:   supplier.UpdatedAt,
+	}
+}
+
+func mapPurchaseOrder(order repository.PurchaseOrder, items []repository.ListPurchaseOrderItemsRow) PurchaseOrderOutput {
+	output := PurchaseOrderOutput{
+		ID:              order.ID,
+		ClinicID:        order.ClinicID,
+		SupplierID:      order.SupplierID,
+		Status:          order.Status,
+		Notes:           nullToPointer(order.Notes),
+		Items:           make([]PurchaseOrderItemOutput, 0, len(items)),
+		CreatedByUserID: nullUUIDToPointer(order.CreatedByUserID),
+		SubmittedAt:     nullTimeToPointer(order.SubmittedAt),
+		ReceivedAt:      nullTimeToPointer(order.ReceivedAt),
+		CancelledAt:     nullTimeToPointer(order.CancelledAt),
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+	}
+	for _, item := range items {
+		total := item.QuantityOrdered * item.UnitCostCents
+		output.TotalCents += total
+		output.ReceivedCents += item.QuantityReceived * item.UnitCostCents
+		output.Items = append(output.Items, PurchaseOrderItemOutput{
+			ID:               item.ID,
+			ItemID:           item.ItemID,
+			ItemName:         item.ItemName,
+			Unit:             item.ItemUnit,
+			QuantityOrdered:  item.QuantityOrdered,
+			QuantityReceived: item.QuantityReceived,
+			UnitCostCents:    item.UnitCostCents,
+			TotalCents:       total,
+		})
+	}
+	return output
+}
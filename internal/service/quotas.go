@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	QuotaClinics           = "clinics"
+	QuotaDentists          = "dentists"
+	QuotaRequestsPerMinute = "requests_per_minute"
+	QuotaStorageBytes      = "storage_bytes"
+
+	requestQuotaWindow = time.Minute
+	bytesPerMegabyte   = 1 << 20
+)
+
+type QuotaExceededError struct {
+	Quota      string
+	Limit      int64
+	Usage      int64
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s: organization reached its %s quota of %d", ErrQuotaExceeded, e.Quota, e.Limit)
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// requestQuotaCounter is a fixed one-minute window per organization; limits are reloaded each window, so quota changes apply within a minute.
+// Like the public rate limit, it lives in memory and each API instance counts on its own.
+type requestQuotaCounter struct {
+	mu          sync.Mutex
+	windowStart time.Time
+	counts      map[string]int64
+	limits      map[string]sql.NullInt32
+}
+
+func newRequestQuotaCounter() *requestQuotaCounter {
+	return &requestQuotaCounter{
+		counts: make(map[string]int64),
+		limits: make(map[string]sql.NullInt32),
+	}
+}
+
+func (c *requestQuotaCounter) roll(now time.Time) {
+	if now.Sub(c.windowStart) >= requestQuotaWindow {
+		c.windowStart = now.Truncate(requestQuotaWindow)
+		c.counts = make(map[string]int64)
+		c.limits = make(map[string]sql.NullInt32)
+	}
+}
+
+func (c *requestQuotaCounter) limit(organizationID string, now time.Time) (sql.NullInt32, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.roll(now)
+	limit, ok := c.limits[organizationID]
+	return limit, ok
+}
+
+func (c *requestQuotaCounter) take(organizationID string, limit sql.NullInt32, now time.Time) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.roll(now)
+	c.limits[organizationID] = limit
+	if limit.Valid && c.counts[organizationID] >= int64(limit.Int32) {
+		return &QuotaExceededError{
+			Quota:      QuotaRequestsPerMinute,
+			Limit:      int64(limit.Int32),
+			Usage:      c.counts[organizationID],
+			RetryAfter: c.windowStart.Add(requestQuotaWindow).Sub(now),
+		}
+	}
+	c.counts[organizationID]++
+	return nil
+}
+
+func (c *requestQuotaCounter) used(organizationID string, now time.Time) int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.roll(now)
+	return c.counts[organizationID]
+}
+
+// ConsumeRequestQuota counts one request against the caller's organization.
+func (s *Service) ConsumeRequestQuota(ctx context.Context) error {
+	organization := organizationID(ctx)
+	if s.requestQuotas == nil || organization == "" {
+		return nil
+	}
+	now := s.now()
+	limit, ok := s.requestQuotas.limit(organization, now)
+	if !ok {
+		row, err := s.queries.GetOrganizationByID(ctx, organization)
+		if err != nil {
+			return err
+		}
+		limit = row.MaxRequestsPerMinute
+	}
+	return s.requestQuotas.take(organization, limit, now)
+}
+
+// ensureQuota runs inside the transaction that adds the row; the organization lock keeps concurrent creations from overshooting the limit.
+func ensureQuota(ctx context.Context, qtx repository.Querier, quota string) error {
+	organization, err := qtx.LockOrganizationForUpdate(ctx, organizationID(ctx))
+	if err != nil {
+		return err
+	}
+	limit := organization.MaxClinics
+	if quota == QuotaDentists {
+		limit = organization.MaxDentists
+	}
+	if !limit.Valid {
+		return nil
+	}
+	usage, err := qtx.GetOrganizationUsage(ctx, organizationID(ctx))
+	if err != nil {
+		return err
+	}
+	used := int64(usage.Clinics)
+	if quota == QuotaDentists {
+		used = int64(usage.Dentists)
+	}
+	if used >= int64(limit.Int32) {
+		return &QuotaExceededError{Quota: quota, Limit: int64(limit.Int32), Usage: used}
+	}
+	return nil
+}
+
+// ensureStorageQuota checks the attachment total the new upload would leave, net of the file it replaces.
+func (s *Service) ensureStorageQuota(ctx context.Context, replacedBytes int64, addedBytes int64) error {
+	organization, err := s.queries.GetOrganizationByID(ctx, organizationID(ctx))
+	if err != nil {
+		return err
+	}
+	if !organization.MaxStorageMb.Valid {
+		return nil
+	}
+	usage, err := s.queries.GetOrganizationUsage(ctx, organizationID(ctx))
+	if err != nil {
+		return err
+	}
+	limit := int64(organization.MaxStorageMb.Int32) * bytesPerMegabyte
+	if usage.StorageBytes-replacedBytes+addedBytes > limit {
+		return &QuotaExceededError{Quota: QuotaStorageBytes, Limit: limit, Usage: usage.StorageBytes}
+	}
+	return nil
+}
+
+func (s *Service) GetOrganizationUsage(ctx context.Context) (OrganizationUsageOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetOrganizationUsage")
+	defer span.End()
+
+	organization, err := s.queries.GetOrganizationByID(ctx, organizationID(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrganizationUsageOutput{}, notFoundError("organization not found")
+		}
+		return OrganizationUsageOutput{}, err
+	}
+	usage, err := s.queries.GetOrganizationUsage(ctx, organization.ID)
+	if err != nil {
+		return OrganizationUsageOutput{}, err
+	}
+	var requests int64
+	if s.requestQuotas != nil {
+		requests = s.requestQuotas.used(organization.ID, s.now())
+	}
+
+	output := OrganizationUsageOutput{
+		OrganizationID:    organization.ID,
+		Clinics:           QuotaUsageOutput{Used: int64(usage.Clinics), Limit: nullInt32ToInt64Pointer(organization.MaxClinics, 1)},
+		Dentists:          QuotaUsageOutput{Used: int64(usage.Dentists), Limit: nullInt32ToInt64Pointer(organization.MaxDentists, 1)},
+		RequestsPerMinute: QuotaUsageOutput{Used: requests, Limit: nullInt32ToInt64Pointer(organization.MaxRequestsPerMinute, 1)},
+		StorageBytes:      QuotaUsageOutput{Used: usage.StorageBytes, Limit: nullInt32ToInt64Pointer(organization.MaxStorageMb, bytesPerMegabyte)},
+	}
+	return output, nil
+}
+
+func (s *Service) UpdateOrganizationQuotas(ctx context.Context, id string, input UpdateOrganizationQuotasInput) (OrganizationOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateOrganizationQuotas")
+	defer span.End()
+
+	limits := []struct {
+		field string
+		value *int32
+	}{
+		{"max_clinics", input.MaxClinics},
+		{"max_dentists", input.MaxDentists},
+		{"max_requests_per_minute", input.MaxRequestsPerMinute},
+		{"max_storage_mb", input.MaxStorageMB},
+	}
+	for _, limit := range limits {
+		if limit.value != nil && *limit.value <= 0 {
+			return OrganizationOutput{}, validationError(fmt.Sprintf("%s must be positive; omit it for no limit", limit.field))
+		}
+	}
+
+	ctx = WithOrganization(ctx, id)
+	organization, err := s.queries.UpdateOrganizationQuotas(ctx, repository.UpdateOrganizationQuotasParams{
+		ID:                   id,
+		MaxClinics:           optionalInt32(input.MaxClinics),
+		MaxDentists:          optionalInt32(input.MaxDentists),
+		MaxRequestsPerMinute: optionalInt32(input.MaxRequestsPerMinute),
+		MaxStorageMb:         optionalInt32(input.MaxStorageMB),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return OrganizationOutput{}, notFoundError("organization not found")
+		}
+		return OrganizationOutput{}, mapDatabaseError(err)
+	}
+	return mapOrganizationOutput(organization), nil
+}
+
+func nullInt32ToInt64Pointer(value sql.NullInt32, scale int64) *int64 {
+	if !value.Valid {
+		return nil
+	}
+	scaled := int64(value.Int32) * scale
+	return &scaled
+}
+
+func nullInt32ToPointer(value sql.NullInt32) *int32 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Int32
+}
+
+func optionalInt32(value *int32) sql.NullInt32 {
+	if value == nil {
+		return sql.NullInt32{}
+	}
+	return sql.NullInt32{Int32: *value, Valid: true}
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"container/list"
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	referenceSpecialties    = "specialties"
+	referenceClinicSettings = "clinic_settings"
+)
+
+// CacheInvalidationBus tells the other API instances that a cached entry changed.
+type CacheInvalidationBus interface {
+	Publish(ctx context.Context, key string) error
+}
+
+type referenceEntry struct {
+	key       string
+	value     any
+	expiresAt time.Time
+}
+
+// referenceCache is an in-process LRU for reference data read on most requests and rarely written. Entries expire after the TTL
+// even when no invalidation arrives, which bounds how stale an instance can be if it misses a message.
+type referenceCache struct {
+	mu         sync.Mutex
+	size       int
+	ttl        time.Duration
+	entries    map[string]*list.Element
+	order      *list.List
+	generation uint64
+	loads      singleflight.Group
+	bus        CacheInvalidationBus
+}
+
+func newReferenceCache(size int, ttl time.Duration, bus CacheInvalidationBus) *referenceCache {
+	return &referenceCache{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+		bus:     bus,
+	}
+}
+
+// WithReferenceCache keeps up to size specialty catalogs and clinic settings in memory for ttl. A non-positive size or ttl leaves
+// the cache off; a nil bus limits invalidation to this instance.
+func WithReferenceCache(size int, ttl time.Duration, bus CacheInvalidationBus) Option {
+	return func(s *Service) {
+		if size > 0 && ttl > 0 {
+			s.referenceCache = newReferenceCache(size, ttl, bus)
+		}
+	}
+}
+
+func (c *referenceCache) get(key string, now time.Time) (any, uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, c.generation, false
+	}
+	entry := element.Value.(*referenceEntry)
+	if !now.Before(entry.expiresAt) {
+		c.order.Remove(element)
+		delete(c.entries, key)
+		return nil, c.generation, false
+	}
+	c.order.MoveToFront(element)
+	return entry.value, c.generation, true
+}
+
+// put stores a loaded value unless an invalidation arrived while it was loading, in which case the value may predate the change.
+func (c *referenceCache) put(key string, value any, generation uint64, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if generation != c.generation {
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+	}
+	c.entries[key] = c.order.PushFront(&referenceEntry{key: key, value: value, expiresAt: now.Add(c.ttl)})
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*referenceEntry).key)
+	}
+}
+
+func (c *referenceCache) invalidate(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.generation++
+	if key == "" {
+		c.entries = make(map[string]*list.Element)
+		c.order.Init()
+		return
+	}
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+}
+
+func referenceKey(ctx context.Context, kind string, parts ...string) string {
+	return strings.Join(append([]string{kind, organizationID(ctx)}, parts...), "|")
+}
+
+// cachedReference returns the cached value for key or loads it once for every caller waiting on the same key. Cached values are
+// shared between requests and must be treated as read-only.
+func cachedReference[V any](ctx context.Context, s *Service, key string, load func(ctx context.Context) (V, error)) (V, error) {
+	cache := s.referenceCache
+	if cache == nil {
+		return load(ctx)
+	}
+	cached, generation, ok := cache.get(key, s.now())
+	if ok {
+		return cached.(V), nil
+	}
+	loaded, err, _ := cache.loads.Do(key+"#"+strconv.FormatUint(generation, 10), func() (any, error) {
+		// A caller that gives up early must not fail the others waiting on the same load.
+		detached, cancel := detachContext(ctx)
+		defer cancel()
+		value, err := load(detached)
+		if err != nil {
+			return nil, err
+		}
+		cache.put(key, value, generation, s.now())
+		return value, nil
+	})
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	return loaded.(V), nil
+}
+
+// invalidateReference drops key here right away and asks the other instances to do the same. A failed publish is only logged:
+// the write already committed and the TTL still bounds how long the others serve the old value.
+func (s *Service) invalidateReference(ctx context.Context, key string) {
+	cache := s.referenceCache
+	if cache == nil {
+		return
+	}
+	cache.invalidate(key)
+	if cache.bus == nil {
+		return
+	}
+	if err := cache.bus.Publish(context.WithoutCancel(ctx), key); err != nil {
+		slog.WarnContext(ctx, "publish reference cache invalidation", "key", key, "error", err)
+	}
+}
+
+// InvalidateReferenceCache applies an invalidation received from another instance. An empty key drops every entry, which is what
+// a listener needs after reconnecting, since messages sent while it was away are lost.
+func (s *Service) InvalidateReferenceCache(key string) {
+	if s.referenceCache != nil {
+		s.referenceCache.invalidate(key)
+	}
+}
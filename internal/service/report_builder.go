@@ -0,0 +1,416 @@
+package service
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ReportPeriodLast7Days     = "LAST_7_DAYS"
+	ReportPeriodLast30Days    = "LAST_30_DAYS"
+	ReportPeriodCurrentMonth  = "CURRENT_MONTH"
+	ReportPeriodPreviousMonth = "PREVIOUS_MONTH"
+
+	reportFieldText = "text"
+	reportFieldEnum = "enum"
+	reportFieldUUID = "uuid"
+	reportFieldDate = "date"
+	reportFieldBool = "boolean"
+
+	reportOpEq  = "eq"
+	reportOpNeq = "neq"
+	reportOpIn  = "in"
+	reportOpGte = "gte"
+	reportOpLte = "lte"
+
+	reportBuilderMaxRows       = 1000
+	reportBuilderMaxGroupBy    = 3
+	reportBuilderMaxMetrics    = 5
+	reportBuilderMaxFilters    = 10
+	reportBuilderMaxListValues = 50
+)
+
+var reportPeriods = []string{ReportPeriodLast7Days, ReportPeriodLast30Days, ReportPeriodCurrentMonth, ReportPeriodPreviousMonth}
+
+var reportFieldOperators = map[string][]string{
+	reportFieldText: {reportOpEq, reportOpNeq, reportOpIn},
+	reportFieldEnum: {reportOpEq, reportOpNeq, reportOpIn},
+	reportFieldUUID: {reportOpEq, reportOpNeq, reportOpIn},
+	reportFieldDate: {reportOpEq, reportOpGte, reportOpLte},
+	reportFieldBool: {reportOpEq},
+}
+
+var reportFieldCasts = map[string]string{
+	reportFieldText: "text",
+	reportFieldEnum: "text",
+	reportFieldUUID: "uuid",
+	reportFieldDate: "date",
+	reportFieldBool: "boolean",
+}
+
+type reportBuilderFilter struct {
+	expr   string
+	kind   string
+	values []string
+}
+
+// reportBuilderEntity is everything a saved definition can reach for one table. Expressions are fixed SQL written here;
+// only values coming from a definition are bound as parameters, and {tz} stands for the clinic's time zone.
+type reportBuilderEntity struct {
+	table      string
+	alias      string
+	where      string
+	date       string
+	dimensions map[string]string
+	metrics    map[string]string
+	filters    map[string]reportBuilderFilter
+}
+
+var reportBuilderEntities = map[string]reportBuilderEntity{
+	"inventory_items": {
+		table: "inventory_items",
+		alias: "i",
+		where: "i.deleted_at IS NULL",
+		dimensions: map[string]string{
+			"unit":      "i.unit",
+			"low_stock": "i.quantity <= i.minimum_quantity",
+		},
+		metrics: map[string]string{
+			"count":     "COUNT(*)",
+			"quantity":  "SUM(i.quantity)",
+			"shortfall": "SUM(GREATEST(i.minimum_quantity - i.quantity, 0))",
+		},
+		filters: map[string]reportBuilderFilter{
+			"unit":      {expr: "i.unit", kind: reportFieldText},
+			"low_stock": {expr: "i.quantity <= i.minimum_quantity", kind: reportFieldBool},
+		},
+	},
+	"inventory_movements": {
+		table: "inventory_movements",
+		alias: "m",
+		date:  "(m.created_at AT TIME ZONE {tz})::date",
+		dimensions: map[string]string{
+			"kind":    "m.kind",
+			"item_id": "m.item_id",
+			"day":     "(m.created_at AT TIME ZONE {tz})::date",
+			"month":   "to_char(m.created_at AT TIME ZONE {tz}, 'YYYY-MM')",
+		},
+		metrics: map[string]string{
+			"count":               "COUNT(*)",
+			"quantity_in":         "SUM(GREATEST(m.quantity_delta, 0))",
+			"quantity_out":        "SUM(GREATEST(-m.quantity_delta, 0))",
+			"purchase_cost_cents": "SUM(m.unit_cost_cents * m.quantity_delta)",
+		},
+		filters: map[string]reportBuilderFilter{
+			"kind":    {expr: "m.kind", kind: reportFieldEnum, values: []string{InventoryPurchase, InventoryConsumption, InventoryAdjustment}},
+			"item_id": {expr: "m.item_id", kind: reportFieldUUID},
+			"date":    {expr: "(m.created_at AT TIME ZONE {tz})::date", kind: reportFieldDate},
+		},
+	},
+	"time_clock_entries": {
+		table: "time_clock_entries",
+		alias: "t",
+		date:  "(t.clock_in_at AT TIME ZONE {tz})::date",
+		dimensions: map[string]string{
+			"dentist_id": "t.dentist_id",
+			"day":        "(t.clock_in_at AT TIME ZONE {tz})::date",
+			"month":      "to_char(t.clock_in_at AT TIME ZONE {tz}, 'YYYY-MM')",
+		},
+		metrics: map[string]string{
+			"count":          "COUNT(*)",
+			"open_entries":   "COUNT(*) FILTER (WHERE t.clock_out_at IS NULL)",
+			"worked_minutes": "SUM(EXTRACT(EPOCH FROM t.clock_out_at - t.clock_in_at) / 60)",
+		},
+		filters: map[string]reportBuilderFilter{
+			"dentist_id": {expr: "t.dentist_id", kind: reportFieldUUID},
+			"date":       {expr: "(t.clock_in_at AT TIME ZONE {tz})::date", kind: reportFieldDate},
+		},
+	},
+	"ledger_transactions": {
+		table: "ledger_transactions",
+		alias: "l",
+		date:  "l.occurred_on",
+		dimensions: map[string]string{
+			"kind":  "l.kind",
+			"day":   "l.occurred_on",
+			"month": "to_char(l.occurred_on, 'YYYY-MM')",
+		},
+		metrics: map[string]string{
+			"count":        "COUNT(*)",
+			"amount_cents": "SUM((SELECT SUM(CASE WHEN e.direction = 'CREDIT' THEN e.amount_cents ELSE -e.amount_cents END) FROM ledger_entries e WHERE e.transaction_id = l.id AND e.account = 'CLINIC_BALANCE'))",
+		},
+		filters: map[string]reportBuilderFilter{
+			"kind": {expr: "l.kind", kind: reportFieldEnum, values: []string{LedgerKindInvoice, LedgerKindPayment, LedgerKindFee, LedgerKindPayout, LedgerKindAdjustment}},
+			"date": {expr: "l.occurred_on", kind: reportFieldDate},
+		},
+	},
+	"clinic_tasks": {
+		table: "clinic_tasks",
+		alias: "k",
+		where: "k.deleted_at IS NULL",
+		date:  "(k.created_at AT TIME ZONE {tz})::date",
+		dimensions: map[string]string{
+			"status":           "k.status",
+			"assignee_user_id": "k.assignee_user_id",
+			"due_month":        "to_char(k.due_date, 'YYYY-MM')",
+			"created_month":    "to_char(k.created_at AT TIME ZONE {tz}, 'YYYY-MM')",
+		},
+		metrics: map[string]string{
+			"count":   "COUNT(*)",
+			"done":    "COUNT(*) FILTER (WHERE k.status = 'DONE')",
+			"overdue": "COUNT(*) FILTER (WHERE k.status IN ('OPEN', 'IN_PROGRESS') AND k.due_date < (CURRENT_TIMESTAMP AT TIME ZONE {tz})::date)",
+		},
+		filters: map[string]reportBuilderFilter{
+			"status":           {expr: "k.status", kind: reportFieldEnum, values: []string{TaskStatusOpen, TaskStatusInProgress, TaskStatusDone, TaskStatusCanceled}},
+			"assignee_user_id": {expr: "k.assignee_user_id", kind: reportFieldUUID},
+			"date":             {expr: "(k.created_at AT TIME ZONE {tz})::date", kind: reportFieldDate},
+		},
+	},
+}
+
+type compiledReportQuery struct {
+	query      string
+	args       []any
+	columns    []string
+	dimensions int
+	from       *string
+	to         *string
+}
+
+func reportBuilderCatalog() ReportBuilderCatalogOutput {
+	catalog := ReportBuilderCatalogOutput{Periods: reportPeriods, Schedules: reportSchedules}
+	for _, name := range slices.Sorted(maps.Keys(reportBuilderEntities)) {
+		entity := reportBuilderEntities[name]
+		output := ReportBuilderEntityOutput{
+			Name:       name,
+			Dimensions: slices.Sorted(maps.Keys(entity.dimensions)),
+			Metrics:    slices.Sorted(maps.Keys(entity.metrics)),
+			Periods:    entity.date != "",
+		}
+		for _, field := range slices.Sorted(maps.Keys(entity.filters)) {
+			filter := entity.filters[field]
+			output.Filters = append(output.Filters, ReportBuilderFilterOutput{
+				Field:     field,
+				Type:      filter.kind,
+				Operators: reportFieldOperators[filter.kind],
+				Values:    filter.values,
+			})
+		}
+		catalog.Entities = append(catalog.Entities, output)
+	}
+	return catalog
+}
+
+func normalizeReportDefinitionSpec(spec ReportDefinitionSpec) ReportDefinitionSpec {
+	normalized := ReportDefinitionSpec{Entity: strings.ToLower(strings.TrimSpace(spec.Entity))}
+	for _, filter := range spec.Filters {
+		item := ReportFilter{
+			Field:  strings.ToLower(strings.TrimSpace(filter.Field)),
+			Op:     strings.ToLower(strings.TrimSpace(filter.Op)),
+			Value:  filter.Value,
+			Values: filter.Values,
+		}
+		normalized.Filters = append(normalized.Filters, item)
+	}
+	for _, dimension := range spec.GroupBy {
+		normalized.GroupBy = append(normalized.GroupBy, strings.ToLower(strings.TrimSpace(dimension)))
+	}
+	for _, metric := range spec.Metrics {
+		normalized.Metrics = append(normalized.Metrics, strings.ToLower(strings.TrimSpace(metric)))
+	}
+	if spec.Period != nil {
+		period := strings.ToUpper(strings.TrimSpace(*spec.Period))
+		normalized.Period = &period
+	}
+	return normalized
+}
+
+// compileReportDefinition turns a normalized definition into one aggregate query over the clinic's rows. Field names are
+// looked up in reportBuilderEntities, so nothing from the definition reaches the SQL text except through a placeholder.
+func compileReportDefinition(spec ReportDefinitionSpec, organizationID string, clinicID string, timezone string, today time.Time) (compiledReportQuery, error) {
+	entity, ok := reportBuilderEntities[spec.Entity]
+	if !ok {
+		return compiledReportQuery{}, validationError(fmt.Sprintf("definition.entity must be one of: %s", strings.Join(slices.Sorted(maps.Keys(reportBuilderEntities)), ", ")))
+	}
+	if len(spec.Metrics) == 0 || len(spec.Metrics) > reportBuilderMaxMetrics {
+		return compiledReportQuery{}, validationError(fmt.Sprintf("definition.metrics must have between 1 and %d entries", reportBuilderMaxMetrics))
+	}
+	if len(spec.GroupBy) > reportBuilderMaxGroupBy {
+		return compiledReportQuery{}, validationError(fmt.Sprintf("definition.group_by must have at most %d entries", reportBuilderMaxGroupBy))
+	}
+	if len(spec.Filters) > reportBuilderMaxFilters {
+		return compiledReportQuery{}, validationError(fmt.Sprintf("definition.filters must have at most %d entries", reportBuilderMaxFilters))
+	}
+
+	compiled := compiledReportQuery{args: []any{organizationID, clinicID}, dimensions: len(spec.GroupBy)}
+	bind := func(value any, cast string) string {
+		compiled.args = append(compiled.args, value)
+		return fmt.Sprintf("$%d::%s", len(compiled.args), cast)
+	}
+	timezoneParam := ""
+	expand := func(expr string) string {
+		if !strings.Contains(expr, "{tz}") {
+			return expr
+		}
+		if timezoneParam == "" {
+			timezoneParam = bind(timezone, "text")
+		}
+		return strings.ReplaceAll(expr, "{tz}", timezoneParam)
+	}
+
+	var columns []string
+	for i, name := range spec.GroupBy {
+		expr, ok := entity.dimensions[name]
+		if !ok {
+			return compiledReportQuery{}, validationError(fmt.Sprintf("definition.group_by[%d] must be one of: %s", i, strings.Join(slices.Sorted(maps.Keys(entity.dimensions)), ", ")))
+		}
+		if slices.Contains(compiled.columns, name) {
+			return compiledReportQuery{}, validationError(fmt.Sprintf("definition.group_by[%d] is duplicated", i))
+		}
+		compiled.columns = append(compiled.columns, name)
+		columns = append(columns, fmt.Sprintf("(%s)::text AS %s", expand(expr), name))
+	}
+	for i, name := range spec.Metrics {
+		expr, ok := entity.metrics[name]
+		if !ok {
+			return compiledReportQuery{}, validationError(fmt.Sprintf("definition.metrics[%d] must be one of: %s", i, strings.Join(slices.Sorted(maps.Keys(entity.metrics)), ", ")))
+		}
+		if slices.Contains(compiled.columns, name) {
+			return compiledReportQuery{}, validationError(fmt.Sprintf("definition.metrics[%d] is duplicated or clashes with a group_by", i))
+		}
+		compiled.columns = append(compiled.columns, name)
+		columns = append(columns, fmt.Sprintf("COALESCE(%s, 0)::bigint AS %s", expand(expr), name))
+	}
+
+	conditions := []string{
+		fmt.Sprintf("%s.organization_id = $1::uuid", entity.alias),
+		fmt.Sprintf("%s.clinic_id = $2::uuid", entity.alias),
+	}
+	if entity.where != "" {
+		conditions = append(conditions, entity.where)
+	}
+	for i, filter := range spec.Filters {
+		condition, err := compileReportFilter(entity, filter, bind, expand)
+		if err != nil {
+			return compiledReportQuery{}, validationError(fmt.Sprintf("definition.filters[%d] %s", i, err.Error()))
+		}
+		conditions = append(conditions, condition)
+	}
+	if spec.Period != nil {
+		if entity.date == "" {
+			return compiledReportQuery{}, validationError(fmt.Sprintf("definition.period is not available for %s", spec.Entity))
+		}
+		fromDate, toDate, err := reportPeriodRange(*spec.Period, today)
+		if err != nil {
+			return compiledReportQuery{}, err
+		}
+		from, to := fromDate.Format(documentDateLayout), toDate.Format(documentDateLayout)
+		compiled.from, compiled.to = &from, &to
+		dateExpr := expand(entity.date)
+		conditions = append(conditions, fmt.Sprintf("%s >= %s", dateExpr, bind(from, "date")), fmt.Sprintf("%s <= %s", dateExpr, bind(to, "date")))
+	}
+
+	var query strings.Builder
+	query.WriteString("-- name: RunReportDefinition :many\n")
+	fmt.Fprintf(&query, "SELECT %s\nFROM %s %s\nWHERE %s", strings.Join(columns, ",\n       "), entity.table, entity.alias, strings.Join(conditions, "\n  AND "))
+	if len(spec.GroupBy) > 0 {
+		positions := make([]string, len(spec.GroupBy))
+		for i := range positions {
+			positions[i] = strconv.Itoa(i + 1)
+		}
+		fmt.Fprintf(&query, "\nGROUP BY %s\nORDER BY %s", strings.Join(positions, ", "), strings.Join(positions, ", "))
+	}
+	fmt.Fprintf(&query, "\nLIMIT %d", reportBuilderMaxRows+1)
+	compiled.query = query.String()
+	return compiled, nil
+}
+
+func compileReportFilter(entity reportBuilderEntity, filter ReportFilter, bind func(any, string) string, expand func(string) string) (string, error) {
+	field, ok := entity.filters[filter.Field]
+	if !ok {
+		return "", fmt.Errorf("field must be one of: %s", strings.Join(slices.Sorted(maps.Keys(entity.filters)), ", "))
+	}
+	if !slices.Contains(reportFieldOperators[field.kind], filter.Op) {
+		return "", fmt.Errorf("op must be one of: %s", strings.Join(reportFieldOperators[field.kind], ", "))
+	}
+	expr := expand(field.expr)
+	cast := reportFieldCasts[field.kind]
+	if filter.Op == reportOpIn {
+		if filter.Value != nil || len(filter.Values) == 0 || len(filter.Values) > reportBuilderMaxListValues {
+			return "", fmt.Errorf("op in takes values with between 1 and %d entries", reportBuilderMaxListValues)
+		}
+		placeholders := make([]string, 0, len(filter.Values))
+		for _, raw := range filter.Values {
+			value, err := parseReportFilterValue(field, raw)
+			if err != nil {
+				return "", err
+			}
+			placeholders = append(placeholders, bind(value, cast))
+		}
+		return fmt.Sprintf("%s IN (%s)", expr, strings.Join(placeholders, ", ")), nil
+	}
+	if filter.Value == nil || len(filter.Values) > 0 {
+		return "", fmt.Errorf("op %s takes a single value", filter.Op)
+	}
+	value, err := parseReportFilterValue(field, *filter.Value)
+	if err != nil {
+		return "", err
+	}
+	operator := map[string]string{reportOpEq: "=", reportOpNeq: "IS DISTINCT FROM", reportOpGte: ">=", reportOpLte: "<="}[filter.Op]
+	return fmt.Sprintf("%s %s %s", expr, operator, bind(value, cast)), nil
+}
+
+func parseReportFilterValue(field reportBuilderFilter, raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+	switch field.kind {
+	case reportFieldEnum:
+		value = strings.ToUpper(value)
+		if !slices.Contains(field.values, value) {
+			return "", fmt.Errorf("value must be one of: %s", strings.Join(field.values, ", "))
+		}
+	case reportFieldUUID:
+		parsed, err := uuid.Parse(value)
+		if err != nil {
+			return "", fmt.Errorf("value must be a valid UUID")
+		}
+		value = parsed.String()
+	case reportFieldDate:
+		if _, err := time.Parse(documentDateLayout, value); err != nil {
+			return "", fmt.Errorf("value must be in YYYY-MM-DD format")
+		}
+	case reportFieldBool:
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("value must be true or false")
+		}
+		value = strconv.FormatBool(parsed)
+	default:
+		if value == "" {
+			return "", fmt.Errorf("value is required")
+		}
+	}
+	return value, nil
+}
+
+// reportPeriodRange resolves a relative period against the clinic's current day. The rolling windows stop at yesterday,
+// so a report sent in the morning covers whole days.
+func reportPeriodRange(period string, today time.Time) (time.Time, time.Time, error) {
+	firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+	switch period {
+	case ReportPeriodLast7Days:
+		return today.AddDate(0, 0, -7), today.AddDate(0, 0, -1), nil
+	case ReportPeriodLast30Days:
+		return today.AddDate(0, 0, -30), today.AddDate(0, 0, -1), nil
+	case ReportPeriodCurrentMonth:
+		return firstOfMonth, today, nil
+	case ReportPeriodPreviousMonth:
+		return firstOfMonth.AddDate(0, -1, 0), firstOfMonth.AddDate(0, 0, -1), nil
+	}
+	return time.Time{}, time.Time{}, validationError(fmt.Sprintf("definition.period must be one of: %s", strings.Join(reportPeriods, ", ")))
+}
@@ -0,0 +1,564 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	ReportScheduleDaily   = "DAILY"
+	ReportScheduleWeekly  = "WEEKLY"
+	ReportScheduleMonthly = "MONTHLY"
+
+	AuditEntityReportDefinition = "REPORT_DEFINITION"
+
+	reportDefinitionPage          = 10
+	reportDefinitionLease         = 10 * time.Minute
+	reportDefinitionMaxRecipients = 10
+	reportDefinitionSendHour      = 6
+	reportDefinitionEmailRows     = 50
+)
+
+var reportSchedules = []string{ReportScheduleDaily, ReportScheduleWeekly, ReportScheduleMonthly}
+
+func (s *Service) GetReportBuilderCatalog(ctx context.Context) ReportBuilderCatalogOutput {
+	_, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetReportBuilderCatalog")
+	defer span.End()
+
+	return reportBuilderCatalog()
+}
+
+func (s *Service) CreateReportDefinition(ctx context.Context, clinicID string, input CreateReportDefinitionInput) (ReportDefinitionOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateReportDefinition")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return ReportDefinitionOutput{}, unauthorizedError("missing authenticated user")
+	}
+	clinic, err := s.getReportDefinitionClinic(ctx, clinicID)
+	if err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return ReportDefinitionOutput{}, validationError("name is required")
+	}
+	definition, err := s.encodeReportDefinitionSpec(ctx, clinic, input.Definition)
+	if err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+	schedule, recipients, err := normalizeReportSchedule(input.Schedule, input.Recipients)
+	if err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+	definitionID, err := newUUIDV7()
+	if err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ReportDefinitionOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	created, err := qtx.CreateReportDefinition(ctx, repository.CreateReportDefinitionParams{
+		ID:              definitionID,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinic.ID,
+		Name:            name,
+		Definition:      definition,
+		Schedule:        schedule,
+		Recipients:      recipients,
+		NextRunAt:       s.nextReportDefinitionRun(ctx, clinic, schedule),
+		CreatedByUserID: principal.UserID,
+	})
+	if err != nil {
+		return ReportDefinitionOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinic.ID,
+		Action:     "report_definition.created",
+		EntityType: AuditEntityReportDefinition,
+		EntityID:   definitionID,
+		Metadata:   map[string]any{"name": name, "schedule": schedule.String},
+	}); err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ReportDefinitionOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapReportDefinition(created), nil
+}
+
+func (s *Service) ListReportDefinitions(ctx context.Context, clinicID string) ([]ReportDefinitionOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListReportDefinitions")
+	defer span.End()
+
+	clinic, err := s.getReportDefinitionClinic(ctx, clinicID)
+	if err != nil {
+		return nil, err
+	}
+	rows, err := s.queries.ListClinicReportDefinitions(ctx, repository.ListClinicReportDefinitionsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinic.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	definitions := make([]ReportDefinitionOutput, 0, len(rows))
+	for _, row := range rows {
+		definitions = append(definitions, mapReportDefinition(row))
+	}
+	return definitions, nil
+}
+
+func (s *Service) GetReportDefinition(ctx context.Context, definitionID string) (ReportDefinitionOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetReportDefinition")
+	defer span.End()
+
+	definition, err := s.getReportDefinition(ctx, definitionID)
+	if err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+	return mapReportDefinition(definition), nil
+}
+
+// UpdateReportDefinition replaces the fields present in input. An empty schedule stops scheduled delivery; changing the
+// schedule moves the next delivery to the first slot of the new one.
+func (s *Service) UpdateReportDefinition(ctx context.Context, definitionID string, input UpdateReportDefinitionInput) (ReportDefinitionOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateReportDefinition")
+	defer span.End()
+
+	current, err := s.getReportDefinition(ctx, definitionID)
+	if err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+	clinic, err := s.getReportDefinitionClinic(ctx, current.ClinicID)
+	if err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+	params := repository.UpdateReportDefinitionParams{
+		OrganizationID: organizationID(ctx),
+		ID:             current.ID,
+		Name:           current.Name,
+		Definition:     current.Definition,
+		Schedule:       current.Schedule,
+		Recipients:     current.Recipients,
+		NextRunAt:      current.NextRunAt,
+	}
+	if input.Name != nil {
+		params.Name = strings.TrimSpace(*input.Name)
+		if params.Name == "" {
+			return ReportDefinitionOutput{}, validationError("name is required")
+		}
+	}
+	if input.Definition != nil {
+		params.Definition, err = s.encodeReportDefinitionSpec(ctx, clinic, *input.Definition)
+		if err != nil {
+			return ReportDefinitionOutput{}, err
+		}
+	}
+	schedule := nullToPointer(current.Schedule)
+	if input.Schedule != nil {
+		schedule = input.Schedule
+		if strings.TrimSpace(*input.Schedule) == "" {
+			schedule = nil
+		}
+	}
+	recipients := current.Recipients
+	if input.Recipients != nil {
+		recipients = *input.Recipients
+	}
+	params.Schedule, params.Recipients, err = normalizeReportSchedule(schedule, recipients)
+	if err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+	if params.Schedule != current.Schedule {
+		params.NextRunAt = s.nextReportDefinitionRun(ctx, clinic, params.Schedule)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ReportDefinitionOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	updated, err := qtx.UpdateReportDefinition(ctx, params)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ReportDefinitionOutput{}, notFoundError("report definition not found")
+		}
+		return ReportDefinitionOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   updated.ClinicID,
+		Action:     "report_definition.updated",
+		EntityType: AuditEntityReportDefinition,
+		EntityID:   updated.ID,
+		Metadata:   map[string]any{"name": updated.Name, "schedule": updated.Schedule.String},
+	}); err != nil {
+		return ReportDefinitionOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ReportDefinitionOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapReportDefinition(updated), nil
+}
+
+func (s *Service) DeleteReportDefinition(ctx context.Context, definitionID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteReportDefinition")
+	defer span.End()
+
+	definition, err := s.getReportDefinition(ctx, definitionID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	deleted, err := qtx.DeleteReportDefinition(ctx, repository.DeleteReportDefinitionParams{
+		OrganizationID: organizationID(ctx),
+		ID:             definition.ID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if deleted == 0 {
+		return notFoundError("report definition not found")
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   definition.ClinicID,
+		Action:     "report_definition.deleted",
+		EntityType: AuditEntityReportDefinition,
+		EntityID:   definition.ID,
+		Metadata:   map[string]any{"name": definition.Name},
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) RunReportDefinition(ctx context.Context, definitionID string) (ReportDefinitionResultOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RunReportDefinition")
+	defer span.End()
+
+	definition, err := s.getReportDefinition(ctx, definitionID)
+	if err != nil {
+		return ReportDefinitionResultOutput{}, err
+	}
+	clinic, err := s.getReportDefinitionClinic(ctx, definition.ClinicID)
+	if err != nil {
+		return ReportDefinitionResultOutput{}, err
+	}
+	return s.runReportDefinition(ctx, definition, clinic)
+}
+
+// SendScheduledReports runs the caller's organization's due definitions and emails the results. Claiming a definition
+// pushes its next run out by the lease, so a run that fails halfway is retried instead of lost.
+func (s *Service) SendScheduledReports(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.SendScheduledReports")
+	defer span.End()
+
+	now := s.now()
+	claimed, err := s.queries.ClaimDueReportDefinitions(ctx, repository.ClaimDueReportDefinitionsParams{
+		OrganizationID: organizationID(ctx),
+		DueBefore:      now,
+		LeaseUntil:     now.Add(reportDefinitionLease),
+		PageLimit:      reportDefinitionPage,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, definition := range claimed {
+		if err := s.sendScheduledReport(ctx, definition); err != nil {
+			return sent, fmt.Errorf("send report definition %s: %w", definition.ID, err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *Service) sendScheduledReport(ctx context.Context, definition repository.ReportDefinition) error {
+	clinic, err := s.getReportDefinitionClinic(ctx, definition.ClinicID)
+	if err != nil {
+		return err
+	}
+	result, err := s.runReportDefinition(ctx, definition, clinic)
+	if err != nil {
+		return err
+	}
+	data := map[string]string{
+		"Name":    definition.Name,
+		"Period":  "",
+		"Table":   reportDefinitionEmailTable(result),
+		"Omitted": "",
+	}
+	if result.From != nil && result.To != nil {
+		data["Period"] = fmt.Sprintf("%s a %s", *result.From, *result.To)
+	}
+	if omitted := len(result.Rows) - reportDefinitionEmailRows; omitted > 0 {
+		data["Omitted"] = strconv.Itoa(omitted)
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	for _, recipient := range definition.Recipients {
+		if err := s.enqueueNotification(ctx, qtx, notificationRequest{
+			Channel:   NotificationChannelEmail,
+			Template:  NotificationTemplateScheduledReport,
+			Recipient: recipient,
+			ClinicID:  clinic.ID,
+			DedupeKey: fmt.Sprintf("%s:%s:%s", NotificationTemplateScheduledReport, definition.ID, s.todayIn(clinicLocation(ctx, clinic.Timezone)).Format(documentDateLayout)),
+			Data:      data,
+		}); err != nil {
+			return err
+		}
+	}
+	if err := qtx.CompleteReportDefinitionRun(ctx, repository.CompleteReportDefinitionRunParams{
+		OrganizationID: organizationID(ctx),
+		ID:             definition.ID,
+		RanAt:          s.now(),
+		NextRunAt:      s.nextReportDefinitionRun(ctx, clinic, definition.Schedule).Time,
+	}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) runReportDefinition(ctx context.Context, definition repository.ReportDefinition, clinic repository.Clinic) (ReportDefinitionResultOutput, error) {
+	var spec ReportDefinitionSpec
+	if err := json.Unmarshal(definition.Definition, &spec); err != nil {
+		return ReportDefinitionResultOutput{}, validationError("report definition is invalid")
+	}
+	compiled, err := compileReportDefinition(spec, organizationID(ctx), clinic.ID, clinic.Timezone, s.todayIn(clinicLocation(ctx, clinic.Timezone)))
+	if err != nil {
+		return ReportDefinitionResultOutput{}, err
+	}
+	rows, err := newTenantGuard(s.db).Query(ctx, compiled.query, compiled.args...)
+	if err != nil {
+		return ReportDefinitionResultOutput{}, mapDatabaseError(err)
+	}
+	defer rows.Close()
+
+	result := ReportDefinitionResultOutput{
+		DefinitionID: definition.ID,
+		Columns:      compiled.columns,
+		Rows:         [][]any{},
+		From:         compiled.from,
+		To:           compiled.to,
+		GeneratedAt:  s.now(),
+	}
+	for rows.Next() {
+		if len(result.Rows) == reportBuilderMaxRows {
+			result.Truncated = true
+			break
+		}
+		dimensions := make([]sql.NullString, compiled.dimensions)
+		metrics := make([]int64, len(compiled.columns)-compiled.dimensions)
+		targets := make([]any, 0, len(compiled.columns))
+		for i := range dimensions {
+			targets = append(targets, &dimensions[i])
+		}
+		for i := range metrics {
+			targets = append(targets, &metrics[i])
+		}
+		if err := rows.Scan(targets...); err != nil {
+			return ReportDefinitionResultOutput{}, err
+		}
+		row := make([]any, 0, len(compiled.columns))
+		for _, dimension := range dimensions {
+			row = append(row, nullToPointer(dimension))
+		}
+		for _, metric := range metrics {
+			row = append(row, metric)
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return ReportDefinitionResultOutput{}, err
+	}
+	return result, nil
+}
+
+// encodeReportDefinitionSpec compiles the definition once so an invalid one is rejected when saved, not when it is run.
+func (s *Service) encodeReportDefinitionSpec(ctx context.Context, clinic repository.Clinic, spec ReportDefinitionSpec) (json.RawMessage, error) {
+	normalized := normalizeReportDefinitionSpec(spec)
+	if _, err := compileReportDefinition(normalized, organizationID(ctx), clinic.ID, clinic.Timezone, s.todayIn(clinicLocation(ctx, clinic.Timezone))); err != nil {
+		return nil, err
+	}
+	encoded, err := json.Marshal(normalized)
+	if err != nil {
+		return nil, fmt.Errorf("encode report definition: %w", err)
+	}
+	return encoded, nil
+}
+
+func normalizeReportSchedule(schedule *string, recipients []string) (sql.NullString, []string, error) {
+	if len(recipients) > reportDefinitionMaxRecipients {
+		return sql.NullString{}, nil, validationError(fmt.Sprintf("recipients must have at most %d entries", reportDefinitionMaxRecipients))
+	}
+	normalized := make([]string, 0, len(recipients))
+	for i, recipient := range recipients {
+		email := strings.ToLower(strings.TrimSpace(recipient))
+		if !validation.ValidateEmail(email) {
+			return sql.NullString{}, nil, validationError(fmt.Sprintf("recipients[%d] is invalid", i))
+		}
+		if slices.Contains(normalized, email) {
+			return sql.NullString{}, nil, validationError(fmt.Sprintf("recipients[%d] is duplicated", i))
+		}
+		normalized = append(normalized, email)
+	}
+	if schedule == nil {
+		return sql.NullString{}, normalized, nil
+	}
+	value := strings.ToUpper(strings.TrimSpace(*schedule))
+	if !slices.Contains(reportSchedules, value) {
+		return sql.NullString{}, nil, validationError(fmt.Sprintf("schedule must be one of: %s", strings.Join(reportSchedules, ", ")))
+	}
+	if len(normalized) == 0 {
+		return sql.NullString{}, nil, validationError("recipients are required for a scheduled report")
+	}
+	return sql.NullString{String: value, Valid: true}, normalized, nil
+}
+
+func (s *Service) nextReportDefinitionRun(ctx context.Context, clinic repository.Clinic, schedule sql.NullString) sql.NullTime {
+	if !schedule.Valid {
+		return sql.NullTime{}
+	}
+	return sql.NullTime{Time: nextReportDefinitionRun(schedule.String, clinicLocation(ctx, clinic.Timezone), s.now()), Valid: true}
+}
+
+// nextReportDefinitionRun is the first delivery slot after now: reportDefinitionSendHour in the clinic's time zone, on
+// the next day, the next Monday or the first day of the next month.
+func nextReportDefinitionRun(schedule string, location *time.Location, now time.Time) time.Time {
+	local := now.In(location)
+	next := time.Date(local.Year(), local.Month(), local.Day(), reportDefinitionSendHour, 0, 0, 0, location)
+	switch schedule {
+	case ReportScheduleWeekly:
+		next = next.AddDate(0, 0, (int(time.Monday)-int(next.Weekday())+7)%7)
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 7)
+		}
+	case ReportScheduleMonthly:
+		next = time.Date(local.Year(), local.Month(), 1, reportDefinitionSendHour, 0, 0, 0, location)
+		if !next.After(now) {
+			next = next.AddDate(0, 1, 0)
+		}
+	default:
+		if !next.After(now) {
+			next = next.AddDate(0, 0, 1)
+		}
+	}
+	return next
+}
+
+func reportDefinitionEmailTable(result ReportDefinitionResultOutput) string {
+	lines := []string{strings.Join(result.Columns, " | ")}
+	for i, row := range result.Rows {
+		if i == reportDefinitionEmailRows {
+			break
+		}
+		values := make([]string, 0, len(row))
+		for _, value := range row {
+			switch typed := value.(type) {
+			case *string:
+				values = append(values, derefString(typed))
+			default:
+				values = append(values, fmt.Sprint(typed))
+			}
+		}
+		lines = append(lines, strings.Join(values, " | "))
+	}
+	if len(result.Rows) == 0 {
+		lines = append(lines, "(nenhuma linha)")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (s *Service) getReportDefinitionClinic(ctx context.Context, clinicID string) (repository.Clinic, error) {
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.Clinic{}, notFoundError("clinic not found")
+		}
+		return repository.Clinic{}, err
+	}
+	return clinic, nil
+}
+
+func (s *Service) getReportDefinition(ctx context.Context, definitionID string) (repository.ReportDefinition, error) {
+	definition, err := s.queries.GetReportDefinition(ctx, repository.GetReportDefinitionParams{
+		OrganizationID: organizationID(ctx),
+		ID:             definitionID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.ReportDefinition{}, notFoundError("report definition not found")
+		}
+		return repository.ReportDefinition{}, err
+	}
+	return definition, nil
+}
+
+func mapReportDefinition(definition repository.ReportDefinition) ReportDefinitionOutput {
+	var spec ReportDefinitionSpec
+	_ = json.Unmarshal(definition.Definition, &spec)
+	recipients := definition.Recipients
+	if recipients == nil {
+		recipients = []string{}
+	}
+	return ReportDefinitionOutput{
+		ID:              definition.ID,
+		ClinicID:        definition.ClinicID,
+		Name:            definition.Name,
+		Definition:      spec,
+		Schedule:        nullToPointer(definition.Schedule),
+		Recipients:      recipients,
+		NextRunAt:       nullTimeToPointer(definition.NextRunAt),
+		LastRunAt:       nullTimeToPointer(definition.LastRunAt),
+		CreatedByUserID: definition.CreatedByUserID,
+		CreatedAt:       definition.CreatedAt,
+		UpdatedAt:       definition.UpdatedAt,
+	}
+}
@@ -0,0 +1,552 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	ReportTypeTimesheet = "TIMESHEET"
+	ReportTypeLedger    = "LEDGER"
+	ReportTypeLowStock  = "LOW_STOCK"
+
+	ReportFormatCSV  = "CSV"
+	ReportFormatXLSX = "XLSX"
+	ReportFormatPDF  = "PDF"
+
+	ReportPending = "PENDING"
+	ReportRunning = "RUNNING"
+	ReportReady   = "READY"
+	ReportFailed  = "FAILED"
+
+	AuditEntityReport = "REPORT"
+
+	reportPage        = 5
+	reportExpiredPage = 50
+	reportLease       = 10 * time.Minute
+	reportRetryBase   = time.Minute
+	reportMaxAttempts = 3
+	reportRetention   = 7 * 24 * time.Hour
+
+	reportProgressLoaded   = 40
+	reportProgressRendered = 80
+)
+
+var (
+	reportTypes    = []string{ReportTypeTimesheet, ReportTypeLedger, ReportTypeLowStock}
+	reportFormats  = []string{ReportFormatCSV, ReportFormatXLSX, ReportFormatPDF}
+	reportStatuses = []string{ReportPending, ReportRunning, ReportReady, ReportFailed}
+)
+
+type reportTable struct {
+	Title     string
+	SheetName string
+	Columns   []string
+	Numeric   []bool
+	Rows      [][]string
+}
+
+// RequestReport queues a report for the background worker. Defaults that depend on the current date are resolved here,
+// so a retried report covers the same period as the one requested.
+func (s *Service) RequestReport(ctx context.Context, input CreateReportInput) (ReportOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RequestReport")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return ReportOutput{}, unauthorizedError("missing authenticated user")
+	}
+	reportType := strings.ToUpper(strings.TrimSpace(input.Type))
+	if !slices.Contains(reportTypes, reportType) {
+		return ReportOutput{}, validationError(fmt.Sprintf("type must be one of: %s", strings.Join(reportTypes, ", ")))
+	}
+	format := strings.ToUpper(strings.TrimSpace(input.Format))
+	if !slices.Contains(reportFormats, format) {
+		return ReportOutput{}, validationError(fmt.Sprintf("format must be one of: %s", strings.Join(reportFormats, ", ")))
+	}
+	clinicID, err := uuid.Parse(strings.TrimSpace(input.ClinicID))
+	if err != nil {
+		return ReportOutput{}, validationError("clinic_id must be a valid UUID")
+	}
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID.String(),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ReportOutput{}, notFoundError("clinic not found")
+		}
+		return ReportOutput{}, err
+	}
+	parameters, err := s.resolveReportParameters(ctx, reportType, clinic, input.Parameters)
+	if err != nil {
+		return ReportOutput{}, err
+	}
+	encoded, err := json.Marshal(parameters)
+	if err != nil {
+		return ReportOutput{}, fmt.Errorf("encode report parameters: %w", err)
+	}
+	reportID, err := newUUIDV7()
+	if err != nil {
+		return ReportOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ReportOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	report, err := qtx.CreateReport(ctx, repository.CreateReportParams{
+		ID:                reportID,
+		OrganizationID:    organizationID(ctx),
+		ClinicID:          clinic.ID,
+		ReportType:        reportType,
+		Format:            format,
+		Parameters:        encoded,
+		RequestedByUserID: principal.UserID,
+	})
+	if err != nil {
+		return ReportOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinic.ID,
+		Action:     "report.requested",
+		EntityType: AuditEntityReport,
+		EntityID:   reportID,
+		Metadata:   map[string]any{"type": reportType, "format": format},
+	}); err != nil {
+		return ReportOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ReportOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapReport(report), nil
+}
+
+func (s *Service) GetReport(ctx context.Context, reportID string) (ReportOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetReport")
+	defer span.End()
+
+	report, err := s.getReport(ctx, reportID)
+	if err != nil {
+		return ReportOutput{}, err
+	}
+	return mapReport(report), nil
+}
+
+func (s *Service) ListReports(ctx context.Context, limit int, cursor *string, clinicID *string, status *string) ([]ReportOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListReports")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	params := repository.ListReportsCursorParams{
+		OrganizationID: organizationID(ctx),
+		PageLimit:      int32(pageLimit + 1),
+	}
+	if clinicID != nil {
+		parsed, err := uuid.Parse(strings.TrimSpace(*clinicID))
+		if err != nil {
+			return nil, nil, validationError("clinic_id must be a valid UUID")
+		}
+		params.ClinicID = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+	if status != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*status))
+		if !slices.Contains(reportStatuses, normalized) {
+			return nil, nil, validationError(fmt.Sprintf("status must be one of: %s", strings.Join(reportStatuses, ", ")))
+		}
+		params.Status = sql.NullString{String: normalized, Valid: true}
+	}
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	params.CursorCreatedAt = after.At
+	params.CursorID = after.ID
+
+	rows, err := s.queries.ListReportsCursor(ctx, params)
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+	reports := make([]ReportOutput, 0, len(rows))
+	for _, row := range rows {
+		reports = append(reports, mapReport(row))
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ID)
+	}
+	return reports, nextCursor, nil
+}
+
+func (s *Service) GetReportFile(ctx context.Context, reportID string) (ReportFileOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetReportFile")
+	defer span.End()
+
+	report, err := s.getReport(ctx, reportID)
+	if err != nil {
+		return ReportFileOutput{}, err
+	}
+	if report.Status != ReportReady {
+		return ReportFileOutput{}, conflictError(fmt.Sprintf("report is %s", strings.ToLower(report.Status)))
+	}
+	if s.attachments == nil || !report.AttachmentKey.Valid {
+		return ReportFileOutput{}, notFoundError("report file not found")
+	}
+	attachment, found, err := s.attachments.GetAttachment(ctx, report.AttachmentKey.String)
+	if err != nil {
+		return ReportFileOutput{}, fmt.Errorf("load report file: %w", err)
+	}
+	if !found {
+		return ReportFileOutput{}, notFoundError("report file not found")
+	}
+	return ReportFileOutput{
+		FileName:    report.FileName.String,
+		ContentType: report.ContentType.String,
+		Content:     attachment.Data,
+	}, nil
+}
+
+// GenerateDueReports removes expired reports and then generates the caller's organization's queued ones. A report whose
+// worker died mid-run is picked up again once its lease runs out.
+func (s *Service) GenerateDueReports(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GenerateDueReports")
+	defer span.End()
+
+	if s.attachments == nil {
+		return 0, nil
+	}
+	if err := s.deleteExpiredReports(ctx); err != nil {
+		return 0, err
+	}
+	now := s.now()
+	claimed, err := s.queries.ClaimDueReports(ctx, repository.ClaimDueReportsParams{
+		OrganizationID: organizationID(ctx),
+		DueBefore:      now,
+		LeaseUntil:     now.Add(reportLease),
+		PageLimit:      reportPage,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	generated := 0
+	for _, report := range claimed {
+		if err := s.generateReport(ctx, report); err != nil {
+			return generated, fmt.Errorf("generate report %s: %w", report.ID, err)
+		}
+		generated++
+	}
+	return generated, nil
+}
+
+func (s *Service) generateReport(ctx context.Context, report repository.Report) error {
+	table, err := s.buildReportTable(ctx, report)
+	if err != nil {
+		return s.failOrRetryReport(ctx, report, err)
+	}
+	s.updateReportProgress(ctx, report, reportProgressLoaded)
+
+	content, contentType, extension, err := renderReport(report, table)
+	if err != nil {
+		return s.failOrRetryReport(ctx, report, err)
+	}
+	s.updateReportProgress(ctx, report, reportProgressRendered)
+
+	if err := s.ensureStorageQuota(ctx, 0, int64(len(content))); err != nil {
+		return s.failOrRetryReport(ctx, report, err)
+	}
+	if err := s.checkDataResidency(ctx, DestinationAttachments); err != nil {
+		return s.failOrRetryReport(ctx, report, err)
+	}
+	key := fmt.Sprintf("reports/%s.%s", report.ID, extension)
+	if err := s.attachments.PutAttachment(ctx, key, contentType, content); err != nil {
+		return s.failOrRetryReport(ctx, report, fmt.Errorf("store report file: %w", err))
+	}
+
+	updated, err := s.queries.CompleteReport(ctx, repository.CompleteReportParams{
+		OrganizationID: organizationID(ctx),
+		ID:             report.ID,
+		RowCount:       sql.NullInt32{Int32: int32(len(table.Rows)), Valid: true},
+		AttachmentKey:  sql.NullString{String: key, Valid: true},
+		FileName:       sql.NullString{String: fmt.Sprintf("%s-%s.%s", strings.ToLower(strings.ReplaceAll(report.ReportType, "_", "-")), report.ID, extension), Valid: true},
+		ContentType:    sql.NullString{String: contentType, Valid: true},
+		SizeBytes:      int64(len(content)),
+		ExpiresAt:      s.now().Add(reportRetention),
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if updated == 0 {
+		// The lease ran out and another run owns the report now.
+		if err := s.attachments.DeleteAttachment(ctx, key); err != nil {
+			slog.WarnContext(ctx, "delete superseded report file", "report_id", report.ID, "error", err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) buildReportTable(ctx context.Context, report repository.Report) (reportTable, error) {
+	var parameters ReportParameters
+	if err := json.Unmarshal(report.Parameters, &parameters); err != nil {
+		return reportTable{}, validationError("report parameters are invalid")
+	}
+	switch report.ReportType {
+	case ReportTypeTimesheet:
+		timesheet, err := s.GetClinicTimesheet(ctx, report.ClinicID, parameters.Month)
+		if err != nil {
+			return reportTable{}, err
+		}
+		return reportTable{
+			Title:     fmt.Sprintf("Timesheet %s", timesheet.Month),
+			SheetName: "Timesheet",
+			Columns:   timesheetColumns,
+			Numeric:   []bool{6: true, 7: true, 9: true, 10: true},
+			Rows:      timesheetRecords(timesheet, clinicLocation(ctx, timesheet.Timezone)),
+		}, nil
+	case ReportTypeLedger:
+		ledger, err := s.GetClinicLedger(ctx, report.ClinicID, parameters.From, parameters.To)
+		if err != nil {
+			return reportTable{}, err
+		}
+		table := reportTable{
+			Title:     fmt.Sprintf("Ledger %s to %s (opening balance %d, closing balance %d cents)", ledger.From, ledger.To, ledger.OpeningBalanceCents, ledger.ClosingBalanceCents),
+			SheetName: "Ledger",
+			Columns:   []string{"occurred_on", "transaction_id", "kind", "description", "reference_type", "reference_id", "amount_cents", "balance_cents"},
+			Numeric:   []bool{6: true, 7: true},
+		}
+		for _, transaction := range ledger.Transactions {
+			table.Rows = append(table.Rows, []string{
+				transaction.OccurredOn,
+				transaction.ID,
+				transaction.Kind,
+				transaction.Description,
+				derefString(transaction.ReferenceType),
+				derefString(transaction.ReferenceID),
+				strconv.FormatInt(transaction.AmountCents, 10),
+				strconv.FormatInt(transaction.BalanceCents, 10),
+			})
+		}
+		return table, nil
+	case ReportTypeLowStock:
+		lowStock, err := s.GetLowStockReport(ctx, report.ClinicID)
+		if err != nil {
+			return reportTable{}, err
+		}
+		table := reportTable{
+			Title:     fmt.Sprintf("Low stock (consumption over the last %d days)", lowStock.ConsumptionWindowDays),
+			SheetName: "Low stock",
+			Columns:   []string{"item_id", "name", "sku", "unit", "quantity", "minimum_quantity", "shortfall", "recent_consumption", "days_of_stock"},
+			Numeric:   []bool{4: true, 5: true, 6: true, 7: true, 8: true},
+		}
+		for _, item := range lowStock.Items {
+			daysOfStock := ""
+			if item.DaysOfStock != nil {
+				daysOfStock = strconv.FormatFloat(*item.DaysOfStock, 'f', 1, 64)
+			}
+			table.Rows = append(table.Rows, []string{
+				item.ID,
+				item.Name,
+				derefString(item.SKU),
+				item.Unit,
+				strconv.FormatInt(item.Quantity, 10),
+				strconv.FormatInt(item.MinimumQuantity, 10),
+				strconv.FormatInt(item.Shortfall, 10),
+				strconv.FormatInt(item.RecentConsumption, 10),
+				daysOfStock,
+			})
+		}
+		return table, nil
+	}
+	return reportTable{}, validationError(fmt.Sprintf("unknown report type %q", report.ReportType))
+}
+
+func (s *Service) resolveReportParameters(ctx context.Context, reportType string, clinic repository.Clinic, input ReportParameters) (ReportParameters, error) {
+	switch reportType {
+	case ReportTypeTimesheet:
+		if input.From != nil || input.To != nil {
+			return ReportParameters{}, validationError("timesheet reports take only month")
+		}
+		month := s.todayIn(clinicLocation(ctx, clinic.Timezone)).Format(timesheetMonthLayout)
+		if input.Month != nil {
+			parsed, err := time.Parse(timesheetMonthLayout, strings.TrimSpace(*input.Month))
+			if err != nil {
+				return ReportParameters{}, validationError("month must be in YYYY-MM format")
+			}
+			month = parsed.Format(timesheetMonthLayout)
+		}
+		return ReportParameters{Month: &month}, nil
+	case ReportTypeLedger:
+		if input.Month != nil {
+			return ReportParameters{}, validationError("ledger reports take only from and to")
+		}
+		fromDate, toDate, err := s.ledgerRange(input.From, input.To)
+		if err != nil {
+			return ReportParameters{}, err
+		}
+		from, to := fromDate.Format(documentDateLayout), toDate.Format(documentDateLayout)
+		return ReportParameters{From: &from, To: &to}, nil
+	default:
+		if input.Month != nil || input.From != nil || input.To != nil {
+			return ReportParameters{}, validationError("low stock reports take no parameters")
+		}
+		return ReportParameters{}, nil
+	}
+}
+
+func renderReport(report repository.Report, table reportTable) ([]byte, string, string, error) {
+	switch report.Format {
+	case ReportFormatCSV:
+		var buffer bytes.Buffer
+		if err := csv.NewWriter(&buffer).WriteAll(append([][]string{table.Columns}, table.Rows...)); err != nil {
+			return nil, "", "", fmt.Errorf("write report: %w", err)
+		}
+		return buffer.Bytes(), "text/csv; charset=utf-8", "csv", nil
+	case ReportFormatXLSX:
+		content, err := renderReportXLSX(table)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return content, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx", nil
+	case ReportFormatPDF:
+		lines := []string{strings.Join(table.Columns, " | ")}
+		for _, row := range table.Rows {
+			lines = append(lines, strings.Join(row, " | "))
+		}
+		content, err := renderDocumentPDF(table.Title, strings.Join(lines, "\n"), report.CreatedAt)
+		if err != nil {
+			return nil, "", "", err
+		}
+		return content, "application/pdf", "pdf", nil
+	}
+	return nil, "", "", validationError(fmt.Sprintf("unknown report format %q", report.Format))
+}
+
+// updateReportProgress is best effort: a missed update only makes polling clients see a stale percentage.
+func (s *Service) updateReportProgress(ctx context.Context, report repository.Report, progress int32) {
+	if err := s.queries.UpdateReportProgress(ctx, repository.UpdateReportProgressParams{
+		OrganizationID: organizationID(ctx),
+		ID:             report.ID,
+		Progress:       progress,
+	}); err != nil {
+		slog.WarnContext(ctx, "update report progress", "report_id", report.ID, "error", err)
+	}
+}
+
+// failOrRetryReport fails the report right away when generating it again cannot help, and otherwise backs off until
+// reportMaxAttempts is reached.
+func (s *Service) failOrRetryReport(ctx context.Context, report repository.Report, reportErr error) error {
+	var quotaErr *QuotaExceededError
+	reason := reportErr.Error()
+	switch {
+	case errors.Is(reportErr, ErrValidation), errors.Is(reportErr, ErrNotFound), errors.Is(reportErr, ErrDataResidency), errors.As(reportErr, &quotaErr):
+	case int(report.Attempts) >= reportMaxAttempts:
+		slog.WarnContext(ctx, "report generation failed", "report_id", report.ID, "attempts", report.Attempts, "error", reportErr)
+		reason = "report could not be generated"
+	default:
+		slog.WarnContext(ctx, "report generation failed", "report_id", report.ID, "attempts", report.Attempts, "error", reportErr)
+		return s.queries.RescheduleReport(ctx, repository.RescheduleReportParams{
+			OrganizationID: organizationID(ctx),
+			ID:             report.ID,
+			NextAttemptAt:  s.now().Add(reportRetryBase << max(report.Attempts-1, 0)),
+			FailureReason:  notificationError(reportErr),
+		})
+	}
+	_, err := s.queries.FailReport(ctx, repository.FailReportParams{
+		OrganizationID: organizationID(ctx),
+		ID:             report.ID,
+		FailureReason:  sql.NullString{String: reason, Valid: true},
+		ExpiresAt:      s.now().Add(reportRetention),
+	})
+	return err
+}
+
+func (s *Service) deleteExpiredReports(ctx context.Context) error {
+	expired, err := s.queries.ListExpiredReports(ctx, repository.ListExpiredReportsParams{
+		OrganizationID: organizationID(ctx),
+		ExpiredBefore:  s.now(),
+		PageLimit:      reportExpiredPage,
+	})
+	if err != nil {
+		return err
+	}
+	for _, report := range expired {
+		if report.AttachmentKey.Valid {
+			if err := s.attachments.DeleteAttachment(ctx, report.AttachmentKey.String); err != nil {
+				return fmt.Errorf("delete expired report %s: %w", report.ID, err)
+			}
+		}
+		if err := s.queries.DeleteReport(ctx, repository.DeleteReportParams{
+			OrganizationID: organizationID(ctx),
+			ID:             report.ID,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Service) getReport(ctx context.Context, reportID string) (repository.Report, error) {
+	report, err := s.queries.GetReport(ctx, repository.GetReportParams{
+		OrganizationID: organizationID(ctx),
+		ID:             reportID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.Report{}, notFoundError("report not found")
+		}
+		return repository.Report{}, err
+	}
+	return report, nil
+}
+
+func mapReport(report repository.Report) ReportOutput {
+	output := ReportOutput{
+		ID:                report.ID,
+		ClinicID:          report.ClinicID,
+		Type:              report.ReportType,
+		Format:            report.Format,
+		Status:            report.Status,
+		Progress:          int(report.Progress),
+		FileName:          nullToPointer(report.FileName),
+		ContentType:       nullToPointer(report.ContentType),
+		SizeBytes:         report.SizeBytes,
+		FailureReason:     nullToPointer(report.FailureReason),
+		Attempts:          int(report.Attempts),
+		RequestedByUserID: report.RequestedByUserID,
+		CreatedAt:         report.CreatedAt,
+		StartedAt:         nullTimeToPointer(report.StartedAt),
+		CompletedAt:       nullTimeToPointer(report.CompletedAt),
+		ExpiresAt:         nullTimeToPointer(report.ExpiresAt),
+	}
+	if report.RowCount.Valid {
+		rowCount := int(report.RowCount.Int32)
+		output.RowCount = &rowCount
+	}
+	_ = json.Unmarshal(report.Parameters, &output.Parameters)
+	return output
+}
@@ -0,0 +1,337 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	DeletedResourceClinic  = "CLINIC"
+	DeletedResourceDentist = "DENTIST"
+)
+
+// Only what was removed together with the clinic comes back; dentist links stay ended and have to be re-created.
+// A clinic still in the trash had nothing else removed yet, so it comes back with its bank accounts untouched.
+func (s *Service) RestoreClinic(ctx context.Context, clinicID string) (ClinicDetailsOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RestoreClinic")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicDetailsOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	clinic, err := qtx.LockDeletedClinicForUpdate(ctx, repository.LockDeletedClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, activeErr := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+				OrganizationID: organizationID(ctx),
+				ID:             clinicID,
+			}); activeErr == nil {
+				return ClinicDetailsOutput{}, conflictError("clinic is not deleted")
+			}
+			return ClinicDetailsOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicDetailsOutput{}, err
+	}
+	person, err := qtx.GetPersonIncludingDeleted(ctx, repository.GetPersonIncludingDeletedParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinic.PersonID,
+	})
+	if err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	if person.AnonymizedAt.Valid {
+		return ClinicDetailsOutput{}, conflictError("clinic was anonymized by the retention policy and cannot be restored")
+	}
+	if err := ensureTaxIDAvailableForRestore(ctx, qtx, person); err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	if clinic.ParentClinicID.Valid {
+		if _, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+			OrganizationID: organizationID(ctx),
+			ID:             clinic.ParentClinicID.UUID.String(),
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ClinicDetailsOutput{}, conflictError("parent clinic is deleted; restore it first")
+			}
+			return ClinicDetailsOutput{}, err
+		}
+	}
+
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	if err := ensureQuota(ctx, qtx, QuotaClinics); err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	if _, err := qtx.RestorePerson(ctx, repository.RestorePersonParams{
+		OrganizationID: organizationID(ctx),
+		ID:             person.ID,
+	}); err != nil {
+		return ClinicDetailsOutput{}, mapRestoreError(err)
+	}
+	if _, err := qtx.RestoreClinic(ctx, repository.RestoreClinicParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		return ClinicDetailsOutput{}, mapRestoreError(err)
+	}
+	restoredAccounts, err := qtx.RestoreBankAccountsDeletedAt(ctx, repository.RestoreBankAccountsDeletedAtParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DeletedAt:      clinic.DeletedAt.Time,
+	})
+	if err != nil {
+		return ClinicDetailsOutput{}, mapRestoreError(err)
+	}
+	fromTrash, err := qtx.DeletePendingDeletion(ctx, repository.DeletePendingDeletionParams{
+		OrganizationID: organizationID(ctx),
+		ResourceType:   DeletedResourceClinic,
+		ResourceID:     clinicID,
+	})
+	if err != nil {
+		return ClinicDetailsOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   clinicID,
+		Action:     "clinic.restored",
+		EntityType: AuditEntityClinic,
+		EntityID:   clinicID,
+		Metadata:   map[string]any{"deleted_at": clinic.DeletedAt.Time, "bank_accounts_restored": restoredAccounts, "from_trash": fromTrash > 0},
+	}); err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicDetailsOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.GetClinic(ctx, clinicID)
+}
+
+func (s *Service) RestoreDentist(ctx context.Context, dentistID string) (DentistOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.RestoreDentist")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return DentistOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	dentist, err := qtx.LockDeletedDentistForUpdate(ctx, repository.LockDeletedDentistForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			if _, activeErr := qtx.GetDentistByID(ctx, repository.GetDentistByIDParams{
+				OrganizationID: organizationID(ctx),
+				ID:             dentistID,
+			}); activeErr == nil {
+				return DentistOutput{}, conflictError("dentist is not deleted")
+			}
+			return DentistOutput{}, notFoundError("dentist not found")
+		}
+		return DentistOutput{}, err
+	}
+	person, err := qtx.GetPersonIncludingDeleted(ctx, repository.GetPersonIncludingDeletedParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentist.PersonID,
+	})
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	if person.AnonymizedAt.Valid {
+		return DentistOutput{}, conflictError("dentist was anonymized by the retention policy and cannot be restored")
+	}
+	if err := ensureTaxIDAvailableForRestore(ctx, qtx, person); err != nil {
+		return DentistOutput{}, err
+	}
+	personTaken, err := qtx.ExistsOtherActiveDentistByPersonID(ctx, repository.ExistsOtherActiveDentistByPersonIDParams{
+		OrganizationID: organizationID(ctx),
+		PersonID:       dentist.PersonID,
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	if personTaken {
+		return DentistOutput{}, conflictError("person already has another active dentist record")
+	}
+	if dentist.CroNumber.Valid {
+		croTaken, err := qtx.ExistsOtherActiveDentistByCRO(ctx, repository.ExistsOtherActiveDentistByCROParams{
+			OrganizationID: organizationID(ctx),
+			CroState:       dentist.CroState,
+			CroNumber:      dentist.CroNumber,
+			DentistID:      dentistID,
+		})
+		if err != nil {
+			return DentistOutput{}, err
+		}
+		if croTaken {
+			return DentistOutput{}, conflictError("cro_number was registered again for this cro_state after the dentist was deleted")
+		}
+	}
+	emailTaken, err := qtx.ExistsUserEmailConflictForDentist(ctx, repository.ExistsUserEmailConflictForDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+		DeletedAt:      dentist.DeletedAt.Time,
+	})
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	if emailTaken {
+		return DentistOutput{}, conflictError("the dentist's user email is now used by another account")
+	}
+
+	if err := ensureQuota(ctx, qtx, QuotaDentists); err != nil {
+		return DentistOutput{}, err
+	}
+	if _, err := qtx.RestorePerson(ctx, repository.RestorePersonParams{
+		OrganizationID: organizationID(ctx),
+		ID:             person.ID,
+	}); err != nil {
+		return DentistOutput{}, mapRestoreError(err)
+	}
+	if _, err := qtx.RestoreDentist(ctx, repository.RestoreDentistParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	}); err != nil {
+		return DentistOutput{}, mapRestoreError(err)
+	}
+	restoredDocuments, err := qtx.RestoreDentistDocumentsDeletedAt(ctx, repository.RestoreDentistDocumentsDeletedAtParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+		DeletedAt:      dentist.DeletedAt.Time,
+	})
+	if err != nil {
+		return DentistOutput{}, mapRestoreError(err)
+	}
+	restoredUsers, err := qtx.RestoreUsersByDentistIDDeletedAt(ctx, repository.RestoreUsersByDentistIDDeletedAtParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+		DeletedAt:      dentist.DeletedAt.Time,
+	})
+	if err != nil {
+		return DentistOutput{}, mapRestoreError(err)
+	}
+	fromTrash, err := qtx.DeletePendingDeletion(ctx, repository.DeletePendingDeletionParams{
+		OrganizationID: organizationID(ctx),
+		ResourceType:   DeletedResourceDentist,
+		ResourceID:     dentistID,
+	})
+	if err != nil {
+		return DentistOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		Action:     "dentist.restored",
+		EntityType: AuditEntityDentist,
+		EntityID:   dentistID,
+		Metadata: map[string]any{
+			"deleted_at":         dentist.DeletedAt.Time,
+			"documents_restored": restoredDocuments,
+			"users_restored":     restoredUsers,
+			"from_trash":         fromTrash > 0,
+		},
+	}); err != nil {
+		return DentistOutput{}, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return DentistOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return s.GetDentistProfile(ctx, dentistID)
+}
+
+func (s *Service) ListDeletedResources(ctx context.Context, limit int, cursor *string, resourceType *string) ([]DeletedResourceOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListDeletedResources")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	typeFilter := sql.NullString{}
+	if resourceType != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*resourceType))
+		if normalized != DeletedResourceClinic && normalized != DeletedResourceDentist {
+			return nil, nil, validationError(fmt.Sprintf("type must be one of: %s, %s", DeletedResourceClinic, DeletedResourceDentist))
+		}
+		typeFilter = sql.NullString{String: normalized, Valid: true}
+	}
+
+	rows, err := s.queries.ListDeletedResourcesCursor(ctx, repository.ListDeletedResourcesCursorParams{
+		OrganizationID:  organizationID(ctx),
+		ResourceType:    typeFilter,
+		CursorDeletedAt: after.At,
+		CursorID:        after.ID,
+		PageLimit:       int32(pageLimit + 1),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+
+	resources := make([]DeletedResourceOutput, 0, len(rows))
+	for _, row := range rows {
+		resources = append(resources, DeletedResourceOutput{
+			Type:        row.ResourceType,
+			ID:          row.ID,
+			LegalName:   row.LegalName,
+			TaxIDNumber: row.TaxIDNumber,
+			DeletedAt:   row.DeletedAt,
+		})
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.DeletedAt, Valid: true}, last.ID)
+	}
+	return resources, nextCursor, nil
+}
+
+func ensureTaxIDAvailableForRestore(ctx context.Context, q repository.Querier, person repository.Person) error {
+	taken, err := q.ExistsOtherActivePersonByTaxID(ctx, repository.ExistsOtherActivePersonByTaxIDParams{
+		OrganizationID: organizationID(ctx),
+		TaxIDNumber:    person.TaxIDNumber,
+		PersonID:       person.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if taken {
+		return conflictError(fmt.Sprintf("tax_id_number %s was registered again after the deletion", person.TaxIDNumber))
+	}
+	return nil
+}
+
+// The explicit checks cover the known cases; a unique violation here means something changed concurrently.
+func mapRestoreError(err error) error {
+	if isUniqueConstraintError(err) {
+		return conflictError("restoring would duplicate an active record")
+	}
+	return mapDatabaseError(err)
+}
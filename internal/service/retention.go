@@ -0,0 +1,520 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	retentionPurgeBatchSize = 100
+
+	RetentionActionDelete    = "DELETE"
+	RetentionActionAnonymize = "ANONYMIZE"
+	RetentionActionHold      = "HOLD"
+
+	maxDocumentRetentionDays = 36500
+)
+
+func WithRetentionDays(days int) Option {
+	return func(s *Service) {
+		if days > 0 {
+			s.retentionDays = days
+		}
+	}
+}
+
+func (s *Service) GetRetentionPurgeReport(ctx context.Context) (RetentionPurgeReportOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetRetentionPurgeReport")
+	defer span.End()
+
+	cutoff, err := s.retentionCutoff()
+	if err != nil {
+		return RetentionPurgeReportOutput{}, err
+	}
+	candidates, err := s.queries.ListRetentionCandidates(ctx, repository.ListRetentionCandidatesParams{
+		OrganizationID: organizationID(ctx),
+		Cutoff:         cutoff,
+		BatchSize:      retentionPurgeBatchSize,
+	})
+	if err != nil {
+		return RetentionPurgeReportOutput{}, err
+	}
+
+	items := make([]RetentionPurgeItemOutput, 0, len(candidates))
+	for _, candidate := range candidates {
+		items = append(items, mapRetentionCandidate(candidate))
+	}
+	return RetentionPurgeReportOutput{
+		RetentionDays: s.retentionDays,
+		Cutoff:        cutoff,
+		Items:         items,
+	}, nil
+}
+
+func (s *Service) PurgeExpiredDeletedRecords(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.PurgeExpiredDeletedRecords")
+	defer span.End()
+
+	cutoff, err := s.retentionCutoff()
+	if err != nil {
+		return 0, err
+	}
+	candidates, err := s.queries.ListRetentionCandidates(ctx, repository.ListRetentionCandidatesParams{
+		OrganizationID: organizationID(ctx),
+		Cutoff:         cutoff,
+		BatchSize:      retentionPurgeBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, candidate := range candidates {
+		item := mapRetentionCandidate(candidate)
+		if item.Action == RetentionActionHold {
+			continue
+		}
+		ok, err := s.purgeRetentionCandidate(ctx, item, cutoff)
+		if err != nil {
+			return purged, fmt.Errorf("purge %s %s: %w", candidate.ResourceType, candidate.ID, err)
+		}
+		if !ok {
+			continue
+		}
+		purged++
+		if s.attachments == nil {
+			continue
+		}
+		for _, key := range []string{candidate.PhotoKey, candidate.PhotoThumbnailKey} {
+			if key == "" {
+				continue
+			}
+			if err := s.attachments.DeleteAttachment(ctx, key); err != nil {
+				slog.WarnContext(ctx, "delete purged dentist photo", "dentist_id", candidate.ID, "key", key, "error", err)
+			}
+		}
+	}
+	return purged, nil
+}
+
+func (s *Service) retentionCutoff() (time.Time, error) {
+	if s.retentionDays <= 0 {
+		return time.Time{}, conflictError("retention policy is not configured")
+	}
+	return s.now().UTC().AddDate(0, 0, -s.retentionDays), nil
+}
+
+func (s *Service) purgeRetentionCandidate(ctx context.Context, item RetentionPurgeItemOutput, cutoff time.Time) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	var personID string
+	var actionErr error
+	switch item.Type {
+	case DeletedResourceClinic:
+		clinic, err := qtx.LockDeletedClinicForUpdate(ctx, repository.LockDeletedClinicForUpdateParams{
+			OrganizationID: organizationID(ctx),
+			ID:             item.ID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, nil
+			}
+			return false, err
+		}
+		if !clinic.DeletedAt.Time.Before(cutoff) {
+			return false, nil
+		}
+		if held, err := hasActiveLegalHold(ctx, qtx, LegalHoldResourceClinic, item.ID); err != nil || held {
+			return false, err
+		}
+		personID = clinic.PersonID
+		if item.Action == RetentionActionDelete {
+			actionErr = purgeClinic(ctx, qtx, item.ID, cutoff)
+		} else {
+			actionErr = qtx.AnonymizeClinicRecords(ctx, repository.AnonymizeClinicRecordsParams{
+				OrganizationID: organizationID(ctx),
+				ClinicID:       item.ID,
+			})
+		}
+	case DeletedResourceDentist:
+		dentist, err := qtx.LockDeletedDentistForUpdate(ctx, repository.LockDeletedDentistForUpdateParams{
+			OrganizationID: organizationID(ctx),
+			ID:             item.ID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, nil
+			}
+			return false, err
+		}
+		if !dentist.DeletedAt.Time.Before(cutoff) {
+			return false, nil
+		}
+		if held, err := hasActiveLegalHold(ctx, qtx, LegalHoldResourceDentist, item.ID); err != nil || held {
+			return false, err
+		}
+		personID = dentist.PersonID
+		if item.Action == RetentionActionDelete {
+			actionErr = purgeDentist(ctx, qtx, item.ID, cutoff)
+		} else {
+			actionErr = qtx.AnonymizeDentistRecords(ctx, repository.AnonymizeDentistRecordsParams{
+				OrganizationID: organizationID(ctx),
+				DentistID:      item.ID,
+			})
+		}
+	default:
+		return false, fmt.Errorf("unknown resource type %q", item.Type)
+	}
+	if actionErr != nil {
+		return false, mapDatabaseError(actionErr)
+	}
+
+	if item.Action == RetentionActionDelete {
+		if err := qtx.PurgeOrphanAddress(ctx, repository.PurgeOrphanAddressParams{
+			OrganizationID: organizationID(ctx),
+			PersonID:       personID,
+		}); err != nil {
+			return false, mapDatabaseError(err)
+		}
+		if _, err := qtx.PurgeOrphanPerson(ctx, repository.PurgeOrphanPersonParams{
+			OrganizationID: organizationID(ctx),
+			ID:             personID,
+		}); err != nil {
+			return false, mapDatabaseError(err)
+		}
+	} else {
+		anonymized, err := qtx.AnonymizePerson(ctx, repository.AnonymizePersonParams{
+			OrganizationID: organizationID(ctx),
+			ID:             personID,
+		})
+		if err != nil {
+			return false, mapDatabaseError(err)
+		}
+		if anonymized > 0 {
+			if err := qtx.DeleteAddressByPersonID(ctx, repository.DeleteAddressByPersonIDParams{
+				OrganizationID: organizationID(ctx),
+				PersonID:       personID,
+			}); err != nil {
+				return false, mapDatabaseError(err)
+			}
+		}
+	}
+
+	entry := auditEntry{
+		EntityID: item.ID,
+		Metadata: map[string]any{"deleted_at": item.DeletedAt, "retention_days": s.retentionDays},
+	}
+	if item.RetentionReason != nil {
+		entry.Metadata["retention_reason"] = *item.RetentionReason
+	}
+	switch item.Type {
+	case DeletedResourceClinic:
+		entry.EntityType = AuditEntityClinic
+		entry.Action = "clinic.purged"
+		if item.Action == RetentionActionAnonymize {
+			entry.ClinicID = item.ID
+			entry.Action = "clinic.anonymized"
+		}
+	case DeletedResourceDentist:
+		entry.EntityType = AuditEntityDentist
+		entry.Action = "dentist.purged"
+		if item.Action == RetentionActionAnonymize {
+			entry.Action = "dentist.anonymized"
+		}
+	}
+	if err := recordAudit(ctx, qtx, entry); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+func purgeClinic(ctx context.Context, qtx repository.Querier, clinicID string, cutoff time.Time) error {
+	if err := qtx.DeleteClinicChildRecords(ctx, repository.DeleteClinicChildRecordsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	}); err != nil {
+		return err
+	}
+	if err := qtx.PurgeClinicOperationalRecords(ctx, repository.PurgeClinicOperationalRecordsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	}); err != nil {
+		return err
+	}
+	if err := qtx.PurgeClinicInventory(ctx, repository.PurgeClinicInventoryParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	}); err != nil {
+		return err
+	}
+	if err := qtx.PurgeClinicBankAccounts(ctx, repository.PurgeClinicBankAccountsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	}); err != nil {
+		return err
+	}
+	if err := qtx.PurgeClinicReportDefinitions(ctx, repository.PurgeClinicReportDefinitionsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	}); err != nil {
+		return err
+	}
+	_, err := qtx.PurgeClinic(ctx, repository.PurgeClinicParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+		Cutoff:         cutoff,
+	})
+	return err
+}
+
+func purgeDentist(ctx context.Context, qtx repository.Querier, dentistID string, cutoff time.Time) error {
+	if err := qtx.PurgeDentistAttachmentScans(ctx, repository.PurgeDentistAttachmentScansParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	}); err != nil {
+		return err
+	}
+	if err := qtx.DeleteDentistChildRecords(ctx, repository.DeleteDentistChildRecordsParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	}); err != nil {
+		return err
+	}
+	_, err := qtx.PurgeDentist(ctx, repository.PurgeDentistParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+		Cutoff:         cutoff,
+	})
+	return err
+}
+
+// Financial records and anything that references a dentist's user must outlive the retention window, so those rows are anonymized instead.
+// A legal hold blocks both; the reason still shows what will happen once the hold is released.
+func mapRetentionCandidate(row repository.ListRetentionCandidatesRow) RetentionPurgeItemOutput {
+	item := RetentionPurgeItemOutput{
+		Type:        row.ResourceType,
+		ID:          row.ID,
+		LegalName:   row.LegalName,
+		DeletedAt:   row.DeletedAt,
+		Action:      RetentionActionDelete,
+		OnLegalHold: row.OnLegalHold,
+	}
+	if row.RetentionReason != "" {
+		reason := row.RetentionReason
+		item.Action = RetentionActionAnonymize
+		item.RetentionReason = &reason
+	}
+	if row.OnLegalHold {
+		item.Action = RetentionActionHold
+	}
+	return item
+}
+
+func hasActiveLegalHold(ctx context.Context, q repository.Querier, resourceType string, resourceID string) (bool, error) {
+	return q.HasActiveLegalHold(ctx, repository.HasActiveLegalHoldParams{
+		OrganizationID: organizationID(ctx),
+		ResourceType:   resourceType,
+		ResourceID:     resourceID,
+	})
+}
+
+func (s *Service) ListDocumentRetentionRules(ctx context.Context) ([]DocumentRetentionRuleOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListDocumentRetentionRules")
+	defer span.End()
+
+	rules, err := s.queries.ListDocumentRetentionRules(ctx, organizationID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	output := make([]DocumentRetentionRuleOutput, 0, len(rules))
+	for _, rule := range rules {
+		output = append(output, mapDocumentRetentionRule(rule))
+	}
+	return output, nil
+}
+
+func (s *Service) SetDocumentRetentionRule(ctx context.Context, kind string, input DocumentRetentionRuleInput) (DocumentRetentionRuleOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.SetDocumentRetentionRule")
+	defer span.End()
+
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return DocumentRetentionRuleOutput{}, unauthorizedError("missing authenticated user")
+	}
+	kind, err := normalizeDocumentKind(kind)
+	if err != nil {
+		return DocumentRetentionRuleOutput{}, err
+	}
+	if input.RetentionDays < 1 || input.RetentionDays > maxDocumentRetentionDays {
+		return DocumentRetentionRuleOutput{}, validationError(fmt.Sprintf("retention_days must be between 1 and %d", maxDocumentRetentionDays))
+	}
+
+	rule, err := s.queries.UpsertDocumentRetentionRule(ctx, repository.UpsertDocumentRetentionRuleParams{
+		OrganizationID:  organizationID(ctx),
+		DocumentKind:    kind,
+		RetentionDays:   int32(input.RetentionDays),
+		UpdatedByUserID: principal.UserID,
+	})
+	if err != nil {
+		return DocumentRetentionRuleOutput{}, mapDatabaseError(err)
+	}
+	if err := recordAudit(ctx, s.queries, auditEntry{
+		Action:     "document_retention_rule.updated",
+		EntityType: AuditEntityOrganization,
+		EntityID:   organizationID(ctx),
+		Metadata:   map[string]any{"document_kind": kind, "retention_days": input.RetentionDays},
+	}); err != nil {
+		return DocumentRetentionRuleOutput{}, err
+	}
+	return mapDocumentRetentionRule(rule), nil
+}
+
+func (s *Service) DeleteDocumentRetentionRule(ctx context.Context, kind string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteDocumentRetentionRule")
+	defer span.End()
+
+	kind, err := normalizeDocumentKind(kind)
+	if err != nil {
+		return err
+	}
+	deleted, err := s.queries.DeleteDocumentRetentionRule(ctx, repository.DeleteDocumentRetentionRuleParams{
+		OrganizationID: organizationID(ctx),
+		DocumentKind:   kind,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if deleted == 0 {
+		return notFoundError("document retention rule not found")
+	}
+	return recordAudit(ctx, s.queries, auditEntry{
+		Action:     "document_retention_rule.deleted",
+		EntityType: AuditEntityOrganization,
+		EntityID:   organizationID(ctx),
+		Metadata:   map[string]any{"document_kind": kind},
+	})
+}
+
+// PurgeExpiredGeneratedDocuments deletes generated documents older than their kind's retention rule, with their
+// signature requests and files. Documents on hold, or whose clinic or dentist is on hold, are kept, as are documents
+// still out for signature.
+func (s *Service) PurgeExpiredGeneratedDocuments(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.PurgeExpiredGeneratedDocuments")
+	defer span.End()
+
+	now := s.now().UTC()
+	documents, err := s.queries.ListExpiredGeneratedDocuments(ctx, repository.ListExpiredGeneratedDocumentsParams{
+		OrganizationID: organizationID(ctx),
+		Now:            now,
+		BatchSize:      retentionPurgeBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for _, document := range documents {
+		keys, err := s.purgeExpiredGeneratedDocument(ctx, document, now)
+		if err != nil {
+			return purged, fmt.Errorf("purge generated document %s: %w", document.ID, err)
+		}
+		if keys == nil {
+			continue
+		}
+		purged++
+		if s.attachments == nil {
+			continue
+		}
+		for _, key := range keys {
+			if err := s.attachments.DeleteAttachment(ctx, key); err != nil {
+				slog.WarnContext(ctx, "delete purged generated document", "document_id", document.ID, "key", key, "error", err)
+			}
+		}
+	}
+	return purged, nil
+}
+
+// purgeExpiredGeneratedDocument returns the attachment keys to delete, or nil when the document was kept.
+func (s *Service) purgeExpiredGeneratedDocument(ctx context.Context, document repository.ListExpiredGeneratedDocumentsRow, now time.Time) ([]string, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	signedKeys, err := qtx.ListGeneratedDocumentSignedKeys(ctx, repository.ListGeneratedDocumentSignedKeysParams{
+		OrganizationID: organizationID(ctx),
+		DocumentID:     document.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := qtx.PurgeGeneratedDocumentSignatureRequests(ctx, repository.PurgeGeneratedDocumentSignatureRequestsParams{
+		OrganizationID: organizationID(ctx),
+		DocumentID:     document.ID,
+	}); err != nil {
+		return nil, mapDatabaseError(err)
+	}
+	deleted, err := qtx.PurgeExpiredGeneratedDocument(ctx, repository.PurgeExpiredGeneratedDocumentParams{
+		OrganizationID: organizationID(ctx),
+		ID:             document.ID,
+		Now:            now,
+	})
+	if err != nil {
+		return nil, mapDatabaseError(err)
+	}
+	if deleted == 0 {
+		return nil, nil
+	}
+	if err := recordAudit(ctx, qtx, auditEntry{
+		ClinicID:   document.ClinicID,
+		Action:     "document.purged",
+		EntityType: AuditEntityGeneratedDocument,
+		EntityID:   document.ID,
+		Metadata:   map[string]any{"kind": document.Kind, "created_at": document.CreatedAt, "retention_days": document.RetentionDays},
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+	return append([]string{document.AttachmentKey}, signedKeys...), nil
+}
+
+func normalizeDocumentKind(kind string) (string, error) {
+	kind = strings.ToUpper(strings.TrimSpace(kind))
+	if !slices.Contains(documentKinds, kind) {
+		return "", validationError(fmt.Sprintf("kind must be one of: %s", strings.Join(documentKinds, ", ")))
+	}
+	return kind, nil
+}
+
+func mapDocumentRetentionRule(rule repository.DocumentRetentionRule) DocumentRetentionRuleOutput {
+	return DocumentRetentionRuleOutput{
+		DocumentKind:    rule.DocumentKind,
+		RetentionDays:   int(rule.RetentionDays),
+		UpdatedByUserID: rule.UpdatedByUserID,
+		UpdatedAt:       rule.UpdatedAt,
+	}
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"capim-test/internal/validation"
+)
+
+type TaxIDScreeningResult struct {
+	Blocked bool
+	Reason  string
+}
+
+type TaxIDScreener interface {
+	ScreenTaxID(ctx context.Context, taxIDType string, taxID string) (TaxIDScreeningResult, error)
+}
+
+func WithTaxIDBlocklist(taxIDs []string) Option {
+	return func(s *Service) {
+		if s.taxIDBlocklist == nil {
+			s.taxIDBlocklist = make(map[string]struct{}, len(taxIDs))
+		}
+		for _, taxID := range taxIDs {
+			normalized := validation.NormalizeCNPJ(taxID)
+			if normalized != "" {
+				s.taxIDBlocklist[normalized] = struct{}{}
+			}
+		}
+	}
+}
+
+func WithTaxIDScreener(screener TaxIDScreener) Option {
+	return func(s *Service) {
+		s.taxIDScreener = screener
+	}
+}
+
+func (s *Service) screenTaxID(ctx context.Context, taxIDType string, taxID string) error {
+	if _, blocked := s.taxIDBlocklist[taxID]; blocked {
+		return blockedError(fmt.Sprintf("%s is not accepted", taxIDType))
+	}
+	if s.taxIDScreener == nil {
+		return nil
+	}
+
+	result, err := s.taxIDScreener.ScreenTaxID(ctx, taxIDType, taxID)
+	if err != nil {
+		return fmt.Errorf("screen tax id: %w", err)
+	}
+	if result.Blocked {
+		message := fmt.Sprintf("%s is not accepted", taxIDType)
+		if reason := strings.TrimSpace(result.Reason); reason != "" {
+			message = fmt.Sprintf("%s: %s", message, reason)
+		}
+		return blockedError(message)
+	}
+	return nil
+}
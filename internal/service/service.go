@@ -3,16 +3,23 @@ package service
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 	"unicode/utf8"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"go.opentelemetry.io/otel"
+	"golang.org/x/sync/singleflight"
 
+	"capim-test/internal/cnab"
 	"capim-test/internal/db/repository"
 	"capim-test/internal/validation"
 )
@@ -29,33 +36,77 @@ const (
 	maxEmailLength       = 254
 	maxPhoneLength       = 20
 	maxBankFieldLength   = 20
+	maxCRONumberLength   = 20
+	croUniqueConstraint  = "idx_dentists_cro_active_unique"
+
+	accountNumberMask         = "****"
+	maskedAccountNumberSuffix = 4
 )
 
 type Service struct {
-	db                *sql.DB
-	queries           repository.Querier
-	txQuerier         func(tx *sql.Tx) repository.Querier
-	jwtSigningKey     []byte
-	jwtIssuer         string
-	jwtAccessTokenTTL time.Duration
-	now               func() time.Time
+	db                    *routedDB
+	queries               repository.Querier
+	txQuerier             func(tx pgx.Tx) repository.Querier
+	jwtSigningKey         []byte
+	jwtIssuer             string
+	jwtAccessTokenTTL     time.Duration
+	now                   func() time.Time
+	addressLookup         AddressLookup
+	validationPolicy      ValidationPolicy
+	taxIDBlocklist        map[string]struct{}
+	taxIDScreener         TaxIDScreener
+	companyRegistry       CompanyRegistry
+	attachments           AttachmentStore
+	attachmentScanner     AttachmentScanner
+	publicBaseURL         string
+	events                EventPublisher
+	documentNoticeDays    []int
+	bankVerifier          BankAccountVerifier
+	bankCallbackSecret    []byte
+	signatureProvider     SignatureProvider
+	signatureSecret       []byte
+	payoutPayer           *cnab.Payer
+	retentionDays         int
+	deleteConfirmation    int
+	deletionGracePeriod   time.Duration
+	auditExport           AuditExportSink
+	warehouseExport       WarehouseExportSink
+	requestQuotas         *requestQuotaCounter
+	keyWrapper            DataKeyWrapper
+	dataKeys              *dataKeyStore
+	offboardingPurgeDelay time.Duration
+	adminSigningKey       []byte
+	traffic               *tenantTraffic
+	destinationRegions    DestinationRegions
+	apiCalls              *apiCallMeter
+	batches               singleflight.Group
+	referenceCache        *referenceCache
+	notificationSenders   map[string]NotificationSender
+	notificationSecret    []byte
 }
 
 type Option func(*Service)
 
-func New(db *sql.DB, options ...Option) *Service {
-	baseQueries := repository.New(db)
+func New(db *pgxpool.Pool, options ...Option) *Service {
 	svc := &Service{
-		db:                db,
-		queries:           baseQueries,
-		txQuerier:         func(tx *sql.Tx) repository.Querier { return baseQueries.WithTx(tx) },
-		jwtIssuer:         "capim-test-api",
-		jwtAccessTokenTTL: 15 * time.Minute,
-		now:               time.Now,
+		db:                    newRoutedDB(db),
+		jwtIssuer:             "capim-test-api",
+		jwtAccessTokenTTL:     15 * time.Minute,
+		now:                   time.Now,
+		requestQuotas:         newRequestQuotaCounter(),
+		traffic:               newTenantTraffic(),
+		apiCalls:              newAPICallMeter(),
+		offboardingPurgeDelay: defaultOffboardingPurgeDelay,
 	}
 	for _, option := range options {
 		option(svc)
 	}
+	svc.queries = repository.New(newTenantGuard(svc.db))
+	svc.dataKeys = newDataKeyStore(svc.keyWrapper, svc.queries, svc.now)
+	svc.queries = encryptingQuerier{Querier: svc.queries, keys: svc.dataKeys}
+	svc.txQuerier = func(tx pgx.Tx) repository.Querier {
+		return encryptingQuerier{Querier: repository.New(newTenantGuard(tx)), keys: svc.dataKeys}
+	}
 	return svc
 }
 
@@ -75,10 +126,18 @@ func (s *Service) CreateClinic(ctx context.Context, input CreateClinicInput) (Cl
 	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateClinic")
 	defer span.End()
 
+	return s.createClinic(ctx, input, nil)
+}
+
+func (s *Service) createClinic(ctx context.Context, input CreateClinicInput, parentClinicID *string) (ClinicOutput, error) {
+	validations := s.newValidationCollector()
 	taxID := validation.NormalizeCNPJ(input.TaxIDNumber)
-	if !validation.ValidateCNPJ(taxID) {
+	if taxID == "" {
 		return ClinicOutput{}, validationError("invalid CNPJ")
 	}
+	if err := validations.check(ValidationRuleTaxID, validation.ValidateCNPJ(taxID), "invalid CNPJ"); err != nil {
+		return ClinicOutput{}, err
+	}
 	if strings.TrimSpace(input.LegalName) == "" {
 		return ClinicOutput{}, validationError("legal_name is required")
 	}
@@ -87,15 +146,41 @@ func (s *Service) CreateClinic(ctx context.Context, input CreateClinicInput) (Cl
 	if err := validateClinicFieldsLength(&taxIDForValidation, &legalNameForValidation, input.TradeName, input.Email, input.Phone); err != nil {
 		return ClinicOutput{}, err
 	}
-	if input.Email != nil && strings.TrimSpace(*input.Email) != "" && !validation.ValidateEmail(*input.Email) {
-		return ClinicOutput{}, validationError("invalid email")
+	if err := validateEmailInput(input.Email, validations); err != nil {
+		return ClinicOutput{}, err
+	}
+	phone, err := normalizePhoneInput(input.Phone, validations)
+	if err != nil {
+		return ClinicOutput{}, err
 	}
+	input.Phone = phone
 	if len(input.BankAccounts) == 0 {
 		return ClinicOutput{}, validationError("bank_accounts must contain at least one account")
 	}
 	if err := validateBankAccountsInput(input.BankAccounts); err != nil {
 		return ClinicOutput{}, err
 	}
+	address, err := s.resolveAddressInput(ctx, input.Address, validations)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
+	timezone, err := resolveTimezoneInput(input.Timezone)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
+	parent, err := s.resolveBranchParent(ctx, parentClinicID, taxID, validations)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
+	if !input.AllowDuplicate {
+		if err := s.ensureNoClinicDuplicates(ctx, taxID, input.LegalName, parent); err != nil {
+			return ClinicOutput{}, err
+		}
+	}
+	if err := s.screenTaxID(ctx, taxIDTypeCNPJ, taxID); err != nil {
+		return ClinicOutput{}, err
+	}
+	registryRecord := s.enrichClinic(ctx, taxID, input.LegalName, validations)
 
 	personID, err := newUUIDV7()
 	if err != nil {
@@ -106,54 +191,89 @@ func (s *Service) CreateClinic(ctx context.Context, input CreateClinicInput) (Cl
 		return ClinicOutput{}, err
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return ClinicOutput{}, fmt.Errorf("begin transaction: %w", err)
 	}
-	defer tx.Rollback()
+	defer tx.Rollback(ctx)
 
 	qtx := s.txQuerier(tx)
+	revisions := clinicRevisionSnapshot{clinicID: clinicID}
+	if parent.Valid {
+		if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+			OrganizationID: organizationID(ctx),
+			ID:             parent.UUID.String(),
+		}); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ClinicOutput{}, notFoundError("parent clinic not found")
+			}
+			return ClinicOutput{}, mapDatabaseError(err)
+		}
+	}
+	if err := ensureQuota(ctx, qtx, QuotaClinics); err != nil {
+		return ClinicOutput{}, err
+	}
 	person, err := qtx.CreatePerson(ctx, repository.CreatePersonParams{
-		ID:          personID,
-		PersonType:  personTypeCompany,
-		TaxIDType:   taxIDTypeCNPJ,
-		TaxIDNumber: taxID,
-		LegalName:   strings.TrimSpace(input.LegalName),
-		TradeName:   optionalString(input.TradeName),
-		Email:       optionalString(input.Email),
-		Phone:       optionalString(input.Phone),
+		OrganizationID: organizationID(ctx),
+		ID:             personID,
+		PersonType:     personTypeCompany,
+		TaxIDType:      taxIDTypeCNPJ,
+		TaxIDNumber:    taxID,
+		LegalName:      strings.TrimSpace(input.LegalName),
+		TradeName:      optionalString(input.TradeName),
+		Email:          optionalString(input.Email),
+		Phone:          optionalString(input.Phone),
 	})
 	if err != nil {
 		return ClinicOutput{}, mapDatabaseError(err)
 	}
+	if err := upsertAddress(ctx, qtx, person.ID, address); err != nil {
+		return ClinicOutput{}, err
+	}
 
-	clinic, err := qtx.CreateClinic(ctx, repository.CreateClinicParams{ID: clinicID, PersonID: person.ID})
+	clinic, err := qtx.CreateClinic(ctx, repository.CreateClinicParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+		PersonID:       person.ID,
+		ParentClinicID: parent,
+		Timezone:       timezone,
+	})
 	if err != nil {
 		return ClinicOutput{}, mapDatabaseError(err)
 	}
+	if err := upsertClinicRegistryRecord(ctx, qtx, clinic.ID, registryRecord); err != nil {
+		return ClinicOutput{}, err
+	}
 
-	for _, account := range input.BankAccounts {
-		bankAccountID, err := newUUIDV7()
-		if err != nil {
-			return ClinicOutput{}, err
-		}
-
-		if _, err := qtx.CreateBankAccount(ctx, repository.CreateBankAccountParams{
-			ID:            bankAccountID,
-			ClinicID:      clinic.ID,
-			BankCode:      strings.TrimSpace(account.BankCode),
-			BranchNumber:  strings.TrimSpace(account.BranchNumber),
-			AccountNumber: strings.TrimSpace(account.AccountNumber),
-		}); err != nil {
-			return ClinicOutput{}, mapDatabaseError(err)
-		}
+	owner, err := qtx.GetClinicDetails(ctx, repository.GetClinicDetailsParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinic.ID,
+	})
+	if err != nil {
+		return ClinicOutput{}, err
+	}
+	// Without an explicit choice, the first account becomes the payout default.
+	primaryIdx := max(primaryBankAccountIndex(input.BankAccounts), 0)
+	accounts := slices.Clone(input.BankAccounts)
+	for idx := range accounts {
+		accounts[idx].IsPrimary = idx == primaryIdx
+	}
+	if err := createBankAccounts(ctx, qtx, owner, accounts); err != nil {
+		return ClinicOutput{}, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return ClinicOutput{}, err
+	}
+	output, err := loadClinicSummary(ctx, qtx, clinic.ID)
+	if err != nil {
+		return ClinicOutput{}, err
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		return ClinicOutput{}, fmt.Errorf("commit transaction: %w", err)
 	}
-
-	return s.loadClinicSummary(ctx, clinic.ID)
+	output.Warnings = validations.result()
+	return output, nil
 }
 
 func (s *Service) UpdateClinic(ctx context.Context, clinicID string, input UpdateClinicInput) (ClinicOutput, error) {
@@ -164,6 +284,8 @@ func (s *Service) UpdateClinic(ctx context.Context, clinicID string, input Updat
 		input.TradeName == nil &&
 		input.Email == nil &&
 		input.Phone == nil &&
+		input.Address == nil &&
+		input.Timezone == nil &&
 		input.BankAccounts == nil &&
 		input.BankAccountIDsToRemove == nil {
 		return ClinicOutput{}, validationError("at least one field must be provided")
@@ -171,12 +293,29 @@ func (s *Service) UpdateClinic(ctx context.Context, clinicID string, input Updat
 	if input.LegalName != nil && strings.TrimSpace(*input.LegalName) == "" {
 		return ClinicOutput{}, validationError("legal_name cannot be empty")
 	}
-	if input.Email != nil && strings.TrimSpace(*input.Email) != "" && !validation.ValidateEmail(*input.Email) {
-		return ClinicOutput{}, validationError("invalid email")
+	validations := s.newValidationCollector()
+	if err := validateEmailInput(input.Email, validations); err != nil {
+		return ClinicOutput{}, err
 	}
 	if err := validateClinicFieldsLength(nil, input.LegalName, input.TradeName, input.Email, input.Phone); err != nil {
 		return ClinicOutput{}, err
 	}
+	phone, err := normalizePhoneInput(input.Phone, validations)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
+	input.Phone = phone
+	address, err := s.resolveAddressInput(ctx, input.Address, validations)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
+	if input.Timezone != nil {
+		timezone, err := resolveTimezoneInput(input.Timezone)
+		if err != nil {
+			return ClinicOutput{}, err
+		}
+		input.Timezone = &timezone
+	}
 	if input.BankAccounts != nil {
 		if len(*input.BankAccounts) == 0 {
 			return ClinicOutput{}, validationError("bank_accounts must contain at least one account when provided")
@@ -197,23 +336,33 @@ func (s *Service) UpdateClinic(ctx context.Context, clinicID string, input Updat
 		}
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return ClinicOutput{}, fmt.Errorf("begin transaction: %w", err)
 	}
-	defer tx.Rollback()
+	defer tx.Rollback(ctx)
 
 	qtx := s.txQuerier(tx)
-	clinic, err := qtx.GetClinicByID(ctx, clinicID)
+	clinic, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ClinicOutput{}, notFoundError("clinic not found")
 		}
 		return ClinicOutput{}, err
 	}
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
 
 	if input.BankAccounts != nil || input.BankAccountIDsToRemove != nil {
-		if _, err := qtx.LockClinicForUpdate(ctx, clinicID); err != nil {
+		if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+			OrganizationID: organizationID(ctx),
+			ID:             clinicID,
+		}); err != nil {
 			if errors.Is(err, sql.ErrNoRows) {
 				return ClinicOutput{}, notFoundError("clinic not found")
 			}
@@ -223,38 +372,60 @@ func (s *Service) UpdateClinic(ctx context.Context, clinicID string, input Updat
 
 	if input.LegalName != nil || input.TradeName != nil || input.Email != nil || input.Phone != nil {
 		if _, err := qtx.UpdatePerson(ctx, repository.UpdatePersonParams{
-			ID:        clinic.PersonID,
-			LegalName: optionalString(input.LegalName),
-			TradeName: optionalString(input.TradeName),
-			Email:     optionalString(input.Email),
-			Phone:     optionalString(input.Phone),
+			OrganizationID: organizationID(ctx),
+			ID:             clinic.PersonID,
+			LegalName:      optionalString(input.LegalName),
+			TradeName:      optionalString(input.TradeName),
+			Email:          optionalString(input.Email),
+			Phone:          optionalString(input.Phone),
+		}); err != nil {
+			return ClinicOutput{}, mapDatabaseError(err)
+		}
+	}
+	if err := upsertAddress(ctx, qtx, clinic.PersonID, address); err != nil {
+		return ClinicOutput{}, err
+	}
+	if input.Timezone != nil {
+		if _, err := qtx.UpdateClinicTimezone(ctx, repository.UpdateClinicTimezoneParams{
+			OrganizationID: organizationID(ctx),
+			ID:             clinicID,
+			Timezone:       *input.Timezone,
 		}); err != nil {
 			return ClinicOutput{}, mapDatabaseError(err)
 		}
 	}
 
+	if input.BankAccounts != nil || input.BankAccountIDsToRemove != nil {
+		if err := ensureNoFinancialHold(ctx, qtx, clinicID); err != nil {
+			return ClinicOutput{}, err
+		}
+	}
 	if input.BankAccounts != nil {
-		for _, account := range *input.BankAccounts {
-			bankAccountID, err := newUUIDV7()
-			if err != nil {
-				return ClinicOutput{}, err
-			}
-			if _, err := qtx.CreateBankAccount(ctx, repository.CreateBankAccountParams{
-				ID:            bankAccountID,
-				ClinicID:      clinicID,
-				BankCode:      strings.TrimSpace(account.BankCode),
-				BranchNumber:  strings.TrimSpace(account.BranchNumber),
-				AccountNumber: strings.TrimSpace(account.AccountNumber),
+		if primaryBankAccountIndex(*input.BankAccounts) >= 0 {
+			if err := qtx.ClearPrimaryBankAccount(ctx, repository.ClearPrimaryBankAccountParams{
+				OrganizationID: organizationID(ctx),
+				ClinicID:       clinicID,
 			}); err != nil {
 				return ClinicOutput{}, mapDatabaseError(err)
 			}
 		}
+		owner, err := qtx.GetClinicDetails(ctx, repository.GetClinicDetailsParams{
+			OrganizationID: organizationID(ctx),
+			ID:             clinicID,
+		})
+		if err != nil {
+			return ClinicOutput{}, err
+		}
+		if err := createBankAccounts(ctx, qtx, owner, *input.BankAccounts); err != nil {
+			return ClinicOutput{}, err
+		}
 	}
 	if input.BankAccountIDsToRemove != nil {
 		for _, bankAccountID := range *input.BankAccountIDsToRemove {
 			affected, err := qtx.DeleteBankAccountByIDAndClinicID(ctx, repository.DeleteBankAccountByIDAndClinicIDParams{
-				ID:       strings.TrimSpace(bankAccountID),
-				ClinicID: clinicID,
+				OrganizationID: organizationID(ctx),
+				ID:             strings.TrimSpace(bankAccountID),
+				ClinicID:       clinicID,
 			})
 			if err != nil {
 				return ClinicOutput{}, mapDatabaseError(err)
@@ -262,51 +433,161 @@ func (s *Service) UpdateClinic(ctx context.Context, clinicID string, input Updat
 			if affected == 0 {
 				return ClinicOutput{}, notFoundError("bank account not found")
 			}
+			if err := recordAudit(ctx, qtx, auditEntry{
+				ClinicID:   clinicID,
+				Action:     "bank_account.deleted",
+				EntityType: AuditEntityBankAccount,
+				EntityID:   strings.TrimSpace(bankAccountID),
+			}); err != nil {
+				return ClinicOutput{}, err
+			}
 		}
 	}
 
-	activeBankAccounts, err := qtx.ListBankAccountsByClinicID(ctx, clinicID)
+	activeBankAccounts, err := qtx.ListBankAccountsByClinicID(ctx, repository.ListBankAccountsByClinicIDParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
 	if err != nil {
 		return ClinicOutput{}, mapDatabaseError(err)
 	}
 	if len(activeBankAccounts) == 0 {
 		return ClinicOutput{}, validationError("clinic must have at least one active bank account")
 	}
+	if err := ensureSinglePrimaryBankAccount(activeBankAccounts); err != nil {
+		return ClinicOutput{}, err
+	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return ClinicOutput{}, err
+	}
+	output, err := loadClinicSummary(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicOutput{}, err
+	}
 
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		return ClinicOutput{}, fmt.Errorf("commit transaction: %w", err)
 	}
-
-	return s.loadClinicSummary(ctx, clinicID)
+	output.Warnings = validations.result()
+	return output, nil
 }
 
 func (s *Service) GetClinic(ctx context.Context, clinicID string) (ClinicDetailsOutput, error) {
 	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinic")
 	defer span.End()
 
-	return s.loadClinicDetails(ctx, clinicID)
+	clinic, err := loadClinicDetails(ctx, s.queries, clinicID)
+	if err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	// Holds can reveal an ongoing fraud investigation, so only admins see them.
+	if principal, ok := PrincipalFromContext(ctx); ok && principal.Role == UserRoleAdmin {
+		active := true
+		clinic.FinancialHolds, err = s.listClinicFinancialHolds(ctx, clinicID, &active)
+		if err != nil {
+			return ClinicDetailsOutput{}, err
+		}
+	}
+	return clinic, nil
+}
+
+// A deleted clinic comes back with the bank accounts removed together with it (or still waiting in the trash with it);
+// its dentist links were ended by the deletion.
+func (s *Service) GetClinicIncludingDeleted(ctx context.Context, clinicID string) (ClinicDetailsOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicIncludingDeleted")
+	defer span.End()
+
+	row, err := s.queries.GetClinicDetailsIncludingDeleted(ctx, repository.GetClinicDetailsIncludingDeletedParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ClinicDetailsOutput{}, notFoundError("clinic not found")
+		}
+		return ClinicDetailsOutput{}, err
+	}
+	if !row.DeletedAt.Valid {
+		return s.GetClinic(ctx, clinicID)
+	}
+
+	bankAccounts, err := s.queries.ListBankAccountsByClinicIDDeletedAt(ctx, repository.ListBankAccountsByClinicIDDeletedAtParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DeletedAt:      row.DeletedAt.Time,
+	})
+	if err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	address, err := s.loadAddress(ctx, row.PersonID)
+	if err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+	registry, err := s.loadClinicRegistryRecord(ctx, row.ClinicID)
+	if err != nil {
+		return ClinicDetailsOutput{}, err
+	}
+
+	clinic := mapClinicDetails(
+		row.ClinicID,
+		row.PersonID,
+		row.LegalName,
+		row.TradeName,
+		row.TaxIDNumber,
+		row.Email,
+		row.Phone,
+		row.Timezone,
+		nil,
+		bankAccounts,
+	)
+	clinic.ParentClinicID = nullUUIDToPointer(row.ParentClinicID)
+	clinic.OnboardingStatus = row.OnboardingStatus
+	clinic.OnboardingStatusChangedAt = row.OnboardingStatusChangedAt
+	clinic.Status = clinicStatus(row.DeactivatedAt)
+	clinic.DeactivatedAt = nullTimeToPointer(row.DeactivatedAt)
+	clinic.DeactivationReason = nullToPointer(row.DeactivationReason)
+	clinic.DeletedAt = nullTimeToPointer(row.DeletedAt)
+	clinic.Address = address
+	clinic.Registry = registry
+	return clinic, nil
 }
 
-func (s *Service) ListClinicsWithCursor(ctx context.Context, limit int, cursor *string) ([]ClinicOutput, *string, error) {
+func (s *Service) ListClinicsWithCursor(ctx context.Context, limit int, cursor *string, filter ListClinicsFilter) ([]ClinicOutput, *string, error) {
 	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicsWithCursor")
 	defer span.End()
 
 	pageLimit := normalizeCursorLimit(limit)
 	queryLimit := int32(pageLimit + 1)
 
-	afterID := uuid.NullUUID{}
-	if cursor != nil {
-		parsedAfterID, err := uuid.Parse(*cursor)
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	onboardingStatus := sql.NullString{}
+	if filter.OnboardingStatus != nil {
+		status, err := parseOnboardingStatus("onboarding_status", *filter.OnboardingStatus)
 		if err != nil {
-			return nil, nil, validationError("invalid cursor")
+			return nil, nil, err
 		}
-		afterID.UUID = parsedAfterID
-		afterID.Valid = true
+		onboardingStatus = sql.NullString{String: status, Valid: true}
+	}
+	statusChangedBefore := sql.NullTime{}
+	if filter.StuckForDays != nil {
+		if *filter.StuckForDays < 1 || *filter.StuckForDays > maxStuckForDays {
+			return nil, nil, validationError(fmt.Sprintf("stuck_for_days must be between 1 and %d", maxStuckForDays))
+		}
+		statusChangedBefore = sql.NullTime{Time: s.now().UTC().AddDate(0, 0, -*filter.StuckForDays), Valid: true}
 	}
 
 	rows, err := s.queries.ListClinicDetailsCursor(ctx, repository.ListClinicDetailsCursorParams{
-		AfterID:   afterID,
-		PageLimit: queryLimit,
+		OrganizationID:      organizationID(ctx),
+		CursorCreatedAt:     after.At,
+		CursorID:            after.ID,
+		OnboardingStatus:    onboardingStatus,
+		StatusChangedBefore: statusChangedBefore,
+		IncludeDeleted:      filter.IncludeDeleted,
+		PageLimit:           queryLimit,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -317,62 +598,56 @@ func (s *Service) ListClinicsWithCursor(ctx context.Context, limit int, cursor *
 		rows = rows[:pageLimit]
 	}
 
-	clinicIDs := make([]string, 0, len(rows))
-	for _, row := range rows {
-		clinicIDs = append(clinicIDs, row.ClinicID)
-	}
-
-	dentistIDsByClinic, err := s.loadClinicDentistIDsByClinicIDs(ctx, clinicIDs)
+	clinics, err := s.mapClinicRows(ctx, rows)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	clinics := make([]ClinicOutput, 0, len(rows))
-	for _, row := range rows {
-		clinics = append(clinics, mapClinicSummary(
-			row.ClinicID,
-			row.PersonID,
-			row.LegalName,
-			row.TradeName,
-			row.TaxIDNumber,
-			row.Email,
-			row.Phone,
-			dentistIDsByClinic[row.ClinicID],
-		))
-	}
-
 	var nextCursor *string
 	if hasNext && len(rows) > 0 {
-		cursorValue := rows[len(rows)-1].ClinicID
-		nextCursor = &cursorValue
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.ClinicID)
 	}
 
 	return clinics, nextCursor, nil
 }
 
-func (s *Service) DeleteClinic(ctx context.Context, clinicID string) error {
+func (s *Service) DeleteClinic(ctx context.Context, clinicID string, confirmationToken string) error {
 	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteClinic")
 	defer span.End()
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
-	defer tx.Rollback()
+	defer tx.Rollback(ctx)
 
 	qtx := s.txQuerier(tx)
+	if err := s.ensureDeleteConfirmed(ctx, qtx, clinicID, confirmationToken); err != nil {
+		return err
+	}
+	revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinicID)
+	if err != nil {
+		return err
+	}
 	if err := s.deleteClinicWithinTx(ctx, qtx, clinicID); err != nil {
 		return err
 	}
+	if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+		return err
+	}
 
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
 	return nil
 }
 
 func (s *Service) deleteClinicWithinTx(ctx context.Context, qtx repository.Querier, clinicID string) error {
-	clinic, err := qtx.GetClinicByID(ctx, clinicID)
+	clinic, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return notFoundError("clinic not found")
@@ -380,23 +655,56 @@ func (s *Service) deleteClinicWithinTx(ctx context.Context, qtx repository.Queri
 		return err
 	}
 
-	if _, err := qtx.LockClinicForUpdate(ctx, clinicID); err != nil {
+	if _, err := qtx.LockClinicForUpdate(ctx, repository.LockClinicForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return notFoundError("clinic not found")
 		}
 		return mapDatabaseError(err)
 	}
 
-	if _, err := qtx.EndClinicDentistsByClinic(ctx, clinicID); err != nil {
+	branches, err := qtx.CountActiveBranches(ctx, repository.CountActiveBranchesParams{
+		OrganizationID: organizationID(ctx),
+		ParentClinicID: clinicID,
+	})
+	if err != nil {
 		return mapDatabaseError(err)
 	}
-	if _, err := qtx.DeleteBankAccountsByClinicID(ctx, clinicID); err != nil {
+	if branches > 0 {
+		return conflictError("clinic has active branches; delete them first")
+	}
+
+	if _, err := qtx.EndClinicDentistsByClinic(ctx, repository.EndClinicDentistsByClinicParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	}); err != nil {
 		return mapDatabaseError(err)
 	}
-	if _, err := qtx.DeleteClinic(ctx, clinicID); err != nil {
+	// During the grace period the clinic sits in the trash with its bank accounts and person untouched.
+	deferred := s.deletionGracePeriod > 0
+	if !deferred {
+		if _, err := qtx.DeleteBankAccountsByClinicID(ctx, repository.DeleteBankAccountsByClinicIDParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+		}); err != nil {
+			return mapDatabaseError(err)
+		}
+	}
+	if _, err := qtx.DeleteClinic(ctx, repository.DeleteClinicParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
 		return mapDatabaseError(err)
 	}
-	if _, err := qtx.DeletePerson(ctx, clinic.PersonID); err != nil {
+	if deferred {
+		return s.scheduleDeletion(ctx, qtx, DeletedResourceClinic, clinicID)
+	}
+	if _, err := qtx.DeletePerson(ctx, repository.DeletePersonParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinic.PersonID,
+	}); err != nil {
 		return mapDatabaseError(err)
 	}
 
@@ -407,10 +715,14 @@ func (s *Service) CreateOrAttachDentist(ctx context.Context, clinicID string, in
 	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateOrAttachDentist")
 	defer span.End()
 
+	validations := s.newValidationCollector()
 	taxID := validation.NormalizeCPF(input.TaxIDNumber)
-	if !validation.ValidateCPF(taxID) {
+	if taxID == "" {
 		return ClinicDentistOutput{}, false, validationError("invalid CPF")
 	}
+	if err := validations.check(ValidationRuleTaxID, validation.ValidateCPF(taxID), "invalid CPF"); err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
 	if strings.TrimSpace(input.LegalName) == "" {
 		return ClinicDentistOutput{}, false, validationError("legal_name is required")
 	}
@@ -426,28 +738,59 @@ func (s *Service) CreateOrAttachDentist(ctx context.Context, clinicID string, in
 	if err := validateOptionalMaxLength("phone", input.Phone, maxPhoneLength); err != nil {
 		return ClinicDentistOutput{}, false, err
 	}
-	if input.Email != nil && strings.TrimSpace(*input.Email) != "" && !validation.ValidateEmail(*input.Email) {
-		return ClinicDentistOutput{}, false, validationError("invalid email")
+	if err := validateEmailInput(input.Email, validations); err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	phone, err := normalizePhoneInput(input.Phone, validations)
+	if err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	input.Phone = phone
+	croNumber, croState, err := normalizeCROInput(input.CRONumber, input.CROState, validations)
+	if err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	address, err := s.resolveAddressInput(ctx, input.Address, validations)
+	if err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	var specialtyIDs []string
+	if input.SpecialtyIDs != nil {
+		specialtyIDs, err = normalizeSpecialtyIDs(input.SpecialtyIDs)
+		if err != nil {
+			return ClinicDentistOutput{}, false, err
+		}
+	}
+	assignment, err := resolveAssignmentInput(input.PlannedEndAt, input.SubstituteForDentistID, s.now().UTC())
+	if err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	if err := s.screenTaxID(ctx, taxIDTypeCPF, taxID); err != nil {
+		return ClinicDentistOutput{}, false, err
 	}
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return ClinicDentistOutput{}, false, fmt.Errorf("begin transaction: %w", err)
 	}
-	defer tx.Rollback()
+	defer tx.Rollback(ctx)
 
 	qtx := s.txQuerier(tx)
-	if _, err := qtx.GetClinicByID(ctx, clinicID); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ClinicDentistOutput{}, false, notFoundError("clinic not found")
-		}
+	roleCounts, err := lockClinicRoleCounts(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	if err := ensureClinicOperational(ctx, qtx, clinicID); err != nil {
 		return ClinicDentistOutput{}, false, err
 	}
 
 	var person repository.Person
 	var dentist repository.Dentist
 
-	person, err = qtx.GetPersonByTaxID(ctx, taxID)
+	person, err = qtx.GetPersonByTaxID(ctx, repository.GetPersonByTaxIDParams{
+		OrganizationID: organizationID(ctx),
+		TaxIDNumber:    taxID,
+	})
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
 			return ClinicDentistOutput{}, false, err
@@ -459,18 +802,22 @@ func (s *Service) CreateOrAttachDentist(ctx context.Context, clinicID string, in
 		}
 
 		person, err = qtx.CreatePerson(ctx, repository.CreatePersonParams{
-			ID:          personID,
-			PersonType:  personTypeIndividual,
-			TaxIDType:   taxIDTypeCPF,
-			TaxIDNumber: taxID,
-			LegalName:   strings.TrimSpace(input.LegalName),
-			Email:       optionalString(input.Email),
-			Phone:       optionalString(input.Phone),
+			OrganizationID: organizationID(ctx),
+			ID:             personID,
+			PersonType:     personTypeIndividual,
+			TaxIDType:      taxIDTypeCPF,
+			TaxIDNumber:    taxID,
+			LegalName:      strings.TrimSpace(input.LegalName),
+			Email:          optionalString(input.Email),
+			Phone:          optionalString(input.Phone),
 		})
 		if err != nil {
 			if isUniqueConstraintError(err) {
 				// Another concurrent request created the person first; continue using the existing row.
-				person, err = qtx.GetPersonByTaxID(ctx, taxID)
+				person, err = qtx.GetPersonByTaxID(ctx, repository.GetPersonByTaxIDParams{
+					OrganizationID: organizationID(ctx),
+					TaxIDNumber:    taxID,
+				})
 				if err != nil {
 					return ClinicDentistOutput{}, false, mapDatabaseError(err)
 				}
@@ -484,58 +831,109 @@ func (s *Service) CreateOrAttachDentist(ctx context.Context, clinicID string, in
 	}
 
 	person, err = qtx.UpdatePerson(ctx, repository.UpdatePersonParams{
-		ID:        person.ID,
-		LegalName: optionalString(new(strings.TrimSpace(input.LegalName))),
-		Email:     optionalString(input.Email),
-		Phone:     optionalString(input.Phone),
+		OrganizationID: organizationID(ctx),
+		ID:             person.ID,
+		LegalName:      optionalString(new(strings.TrimSpace(input.LegalName))),
+		Email:          optionalString(input.Email),
+		Phone:          optionalString(input.Phone),
 	})
 	if err != nil {
 		return ClinicDentistOutput{}, false, mapDatabaseError(err)
 	}
+	if err := upsertAddress(ctx, qtx, person.ID, address); err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
 
-	dentist, err = qtx.GetDentistByPersonID(ctx, person.ID)
+	dentist, err = qtx.GetDentistByPersonID(ctx, repository.GetDentistByPersonIDParams{
+		OrganizationID: organizationID(ctx),
+		PersonID:       person.ID,
+	})
 	if err != nil {
 		if !errors.Is(err, sql.ErrNoRows) {
 			return ClinicDentistOutput{}, false, err
 		}
 
+		if err := ensureQuota(ctx, qtx, QuotaDentists); err != nil {
+			return ClinicDentistOutput{}, false, err
+		}
 		dentistID, err := newUUIDV7()
 		if err != nil {
 			return ClinicDentistOutput{}, false, err
 		}
-		dentist, err = qtx.CreateDentist(ctx, repository.CreateDentistParams{ID: dentistID, PersonID: person.ID})
+		dentist, err = qtx.CreateDentist(ctx, repository.CreateDentistParams{
+			OrganizationID: organizationID(ctx),
+			ID:             dentistID,
+			PersonID:       person.ID,
+			CroNumber:      croNumber,
+			CroState:       croState,
+		})
 		if err != nil {
-			if isUniqueConstraintError(err) {
+			if isUniqueConstraintError(err) && !isConstraintViolation(err, croUniqueConstraint) {
 				// Another concurrent request created the dentist first; continue with the existing row.
-				dentist, err = qtx.GetDentistByPersonID(ctx, person.ID)
+				dentist, err = qtx.GetDentistByPersonID(ctx, repository.GetDentistByPersonIDParams{
+					OrganizationID: organizationID(ctx),
+					PersonID:       person.ID,
+				})
 				if err != nil {
 					return ClinicDentistOutput{}, false, mapDatabaseError(err)
 				}
 			} else {
-				return ClinicDentistOutput{}, false, mapDatabaseError(err)
+				return ClinicDentistOutput{}, false, mapDentistDatabaseError(err)
 			}
 		}
 	}
+	if croNumber.Valid && (dentist.CroNumber != croNumber || dentist.CroState != croState) {
+		dentist, err = qtx.UpdateDentistCRO(ctx, repository.UpdateDentistCROParams{
+			OrganizationID: organizationID(ctx),
+			ID:             dentist.ID,
+			CroNumber:      croNumber,
+			CroState:       croState,
+		})
+		if err != nil {
+			return ClinicDentistOutput{}, false, mapDentistDatabaseError(err)
+		}
+	}
+	if input.SpecialtyIDs != nil {
+		if err := replaceDentistSpecialties(ctx, qtx, dentist.ID, specialtyIDs); err != nil {
+			return ClinicDentistOutput{}, false, err
+		}
+	}
+
+	if err := ensureSubstituteTarget(ctx, qtx, clinicID, dentist.ID, assignment); err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
 
 	created := false
-	relation, err := qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{ClinicID: clinicID, DentistID: dentist.ID})
+	relation, err := qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentist.ID,
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			relation, err = qtx.CreateClinicDentist(ctx, repository.CreateClinicDentistParams{
-				ClinicID:              clinicID,
-				DentistID:             dentist.ID,
-				IsAdmin:               input.IsAdmin,
-				IsLegalRepresentative: input.IsLegalRepresentative,
-				StartedAt:             time.Now().UTC(),
+				OrganizationID:         organizationID(ctx),
+				ClinicID:               clinicID,
+				DentistID:              dentist.ID,
+				IsAdmin:                input.IsAdmin,
+				IsLegalRepresentative:  input.IsLegalRepresentative,
+				StartedAt:              time.Now().UTC(),
+				PlannedEndAt:           assignment.plannedEndAt,
+				SubstituteForDentistID: assignment.substituteFor,
 			})
 			if err != nil {
 				if isUniqueConstraintError(err) {
 					// Another concurrent request created the active link first.
-					relation, err = qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{ClinicID: clinicID, DentistID: dentist.ID})
+					relation, err = qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+						OrganizationID: organizationID(ctx),
+						ClinicID:       clinicID,
+						DentistID:      dentist.ID,
+					})
 					if err != nil {
 						return ClinicDentistOutput{}, false, mapDatabaseError(err)
 					}
 					relation, err = qtx.UpdateClinicDentistRole(ctx, repository.UpdateClinicDentistRoleParams{
+						OrganizationID:        organizationID(ctx),
 						ClinicID:              clinicID,
 						DentistID:             dentist.ID,
 						IsAdmin:               sql.NullBool{Bool: input.IsAdmin, Valid: true},
@@ -555,6 +953,7 @@ func (s *Service) CreateOrAttachDentist(ctx context.Context, clinicID string, in
 		}
 	} else {
 		relation, err = qtx.UpdateClinicDentistRole(ctx, repository.UpdateClinicDentistRoleParams{
+			OrganizationID:        organizationID(ctx),
 			ClinicID:              clinicID,
 			DentistID:             dentist.ID,
 			IsAdmin:               sql.NullBool{Bool: input.IsAdmin, Valid: true},
@@ -564,31 +963,61 @@ func (s *Service) CreateOrAttachDentist(ctx context.Context, clinicID string, in
 			return ClinicDentistOutput{}, false, mapDatabaseError(err)
 		}
 	}
+	if !created {
+		relation, err = qtx.UpdateClinicDentistAssignment(ctx, repository.UpdateClinicDentistAssignmentParams{
+			OrganizationID:         organizationID(ctx),
+			ClinicID:               clinicID,
+			DentistID:              dentist.ID,
+			PlannedEndAt:           assignment.plannedEndAt,
+			SubstituteForDentistID: assignment.substituteFor,
+		})
+		if err != nil {
+			return ClinicDentistOutput{}, false, mapDatabaseError(err)
+		}
+	}
+	if err := ensureClinicRoleInvariant(ctx, qtx, clinicID, roleCounts); err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
 
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		return ClinicDentistOutput{}, false, fmt.Errorf("commit transaction: %w", err)
 	}
 
-	return ClinicDentistOutput{
-		DentistOutput: DentistOutput{
-			ID:          dentist.ID,
-			PersonID:    person.ID,
-			LegalName:   person.LegalName,
-			TaxIDNumber: person.TaxIDNumber,
-			Email:       nullToPointer(person.Email),
-			Phone:       nullToPointer(person.Phone),
-		},
+	dentistOutput := mapDentistOutput(dentist, person)
+	dentistOutput.PhotoURL = s.dentistPhotoURL(dentist.ID, dentist.PhotoUpdatedAt)
+	dentistOutput.ThumbnailURL = s.dentistThumbnailURL(dentist.ID, dentist.PhotoUpdatedAt)
+	dentistOutput.Address, err = s.loadAddress(ctx, person.ID)
+	if err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	dentistOutput.Specialties, err = s.loadDentistSpecialties(ctx, dentist.ID)
+	if err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	dentistOutput.Warnings = validations.result()
+
+	output := ClinicDentistOutput{
+		DentistOutput:         dentistOutput,
 		IsAdmin:               relation.IsAdmin,
 		IsLegalRepresentative: relation.IsLegalRepresentative,
 		StartedAt:             relation.StartedAt,
-	}, created, nil
-}
-
-func (s *Service) ListClinicDentistsWithCursor(ctx context.Context, clinicID string, limit int, cursor *string) ([]ClinicDentistOutput, *string, error) {
-	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicDentistsWithCursor")
+	}
+	applyAssignment(&output, relation.PlannedEndAt, relation.SubstituteForDentistID)
+	output.PreviousPeriods, output.TotalTenureDays, err = s.loadPreviousPeriods(ctx, clinicID, dentist.ID, relation.StartedAt)
+	if err != nil {
+		return ClinicDentistOutput{}, false, err
+	}
+	return output, created, nil
+}
+
+func (s *Service) ListClinicDentistsWithCursor(ctx context.Context, clinicID string, limit int, cursor *string, specialty *string, includeDeleted bool) ([]ClinicDentistOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListClinicDentistsWithCursor")
 	defer span.End()
 
-	if _, err := s.queries.GetClinicByID(ctx, clinicID); err != nil {
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil, notFoundError("clinic not found")
 		}
@@ -598,20 +1027,19 @@ func (s *Service) ListClinicDentistsWithCursor(ctx context.Context, clinicID str
 	pageLimit := normalizeCursorLimit(limit)
 	queryLimit := int32(pageLimit + 1)
 
-	afterDentistID := uuid.NullUUID{}
-	if cursor != nil {
-		parsedAfterID, err := uuid.Parse(*cursor)
-		if err != nil {
-			return nil, nil, validationError("invalid cursor")
-		}
-		afterDentistID.UUID = parsedAfterID
-		afterDentistID.Valid = true
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	rows, err := s.queries.ListDentistsByClinicIDCursor(ctx, repository.ListDentistsByClinicIDCursorParams{
-		ClinicID:       clinicID,
-		AfterDentistID: afterDentistID,
-		PageLimit:      queryLimit,
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		CursorCreatedAt: after.At,
+		CursorID:        after.ID,
+		Specialty:       optionalString(specialty),
+		IncludeDeleted:  includeDeleted,
+		PageLimit:       queryLimit,
 	})
 	if err != nil {
 		return nil, nil, err
@@ -622,15 +1050,45 @@ func (s *Service) ListClinicDentistsWithCursor(ctx context.Context, clinicID str
 		rows = rows[:pageLimit]
 	}
 
+	personIDs := make([]string, 0, len(rows))
+	dentistIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		personIDs = append(personIDs, row.PersonID)
+		dentistIDs = append(dentistIDs, row.DentistID)
+	}
+	var (
+		addressesByPerson    map[string]*AddressOutput
+		specialtiesByDentist map[string][]SpecialtyOutput
+	)
+	if err := loadInParallel(ctx,
+		func(ctx context.Context) (err error) {
+			addressesByPerson, err = s.loadAddressesByPersonIDs(ctx, personIDs)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			specialtiesByDentist, err = s.loadSpecialtiesByDentistIDs(ctx, dentistIDs)
+			return err
+		},
+	); err != nil {
+		return nil, nil, err
+	}
+
 	output := make([]ClinicDentistOutput, 0, len(rows))
 	for _, row := range rows {
-		output = append(output, mapDentistCursorRow(row))
+		dentist := mapDentistCursorRow(row)
+		dentist.Address = addressesByPerson[row.PersonID]
+		dentist.Specialties = specialtiesByDentist[row.DentistID]
+		dentist.PhotoURL = s.dentistPhotoURL(row.DentistID, row.PhotoUpdatedAt)
+		dentist.ThumbnailURL = s.dentistThumbnailURL(row.DentistID, row.PhotoUpdatedAt)
+		applyAssignment(&dentist, row.PlannedEndAt, row.SubstituteForDentistID)
+		dentist.DeletedAt = nullTimeToPointer(row.DeletedAt)
+		output = append(output, dentist)
 	}
 
 	var nextCursor *string
 	if hasNext && len(rows) > 0 {
-		cursorValue := rows[len(rows)-1].DentistID
-		nextCursor = &cursorValue
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CreatedAt, Valid: true}, last.DentistID)
 	}
 
 	return output, nextCursor, nil
@@ -644,7 +1102,20 @@ func (s *Service) UpdateClinicDentistRole(ctx context.Context, clinicID string,
 		return ClinicDentistOutput{}, validationError("at least one role field must be provided")
 	}
 
-	relation, err := s.queries.UpdateClinicDentistRole(ctx, repository.UpdateClinicDentistRoleParams{
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return ClinicDentistOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	before, err := lockClinicRoleCounts(ctx, qtx, clinicID)
+	if err != nil {
+		return ClinicDentistOutput{}, err
+	}
+
+	relation, err := qtx.UpdateClinicDentistRole(ctx, repository.UpdateClinicDentistRoleParams{
+		OrganizationID:        organizationID(ctx),
 		ClinicID:              clinicID,
 		DentistID:             dentistID,
 		IsAdmin:               optionalBool(input.IsAdmin),
@@ -656,37 +1127,37 @@ func (s *Service) UpdateClinicDentistRole(ctx context.Context, clinicID string,
 		}
 		return ClinicDentistOutput{}, mapDatabaseError(err)
 	}
-
-	details, err := s.queries.GetDentistDetailsByID(ctx, dentistID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ClinicDentistOutput{}, notFoundError("dentist not found")
-		}
+	if err := ensureClinicRoleInvariant(ctx, qtx, clinicID, before); err != nil {
 		return ClinicDentistOutput{}, err
 	}
 
-	return ClinicDentistOutput{
-		DentistOutput: DentistOutput{
-			ID:          details.DentistID,
-			PersonID:    details.PersonID,
-			LegalName:   details.LegalName,
-			TaxIDNumber: details.TaxIDNumber,
-			Email:       nullToPointer(details.Email),
-			Phone:       nullToPointer(details.Phone),
-		},
-		IsAdmin:               relation.IsAdmin,
-		IsLegalRepresentative: relation.IsLegalRepresentative,
-		StartedAt:             relation.StartedAt,
-	}, nil
+	if err := tx.Commit(ctx); err != nil {
+		return ClinicDentistOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return s.loadClinicDentistOutput(ctx, relation)
 }
 
 func (s *Service) UnlinkDentistFromClinic(ctx context.Context, clinicID string, dentistID string) error {
 	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UnlinkDentistFromClinic")
 	defer span.End()
 
-	if _, err := s.queries.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
-		ClinicID:  clinicID,
-		DentistID: dentistID,
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	before, err := lockClinicRoleCounts(ctx, qtx, clinicID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentistID,
 	}); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return notFoundError("clinic dentist active link not found")
@@ -694,7 +1165,10 @@ func (s *Service) UnlinkDentistFromClinic(ctx context.Context, clinicID string,
 		return mapDatabaseError(err)
 	}
 
-	activeLinks, err := s.queries.CountActiveClinicLinksByDentist(ctx, dentistID)
+	activeLinks, err := qtx.CountActiveClinicLinksByDentist(ctx, repository.CountActiveClinicLinksByDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	})
 	if err != nil {
 		return mapDatabaseError(err)
 	}
@@ -702,13 +1176,24 @@ func (s *Service) UnlinkDentistFromClinic(ctx context.Context, clinicID string,
 		return conflictError("cannot unlink dentist from the last active clinic")
 	}
 
-	affected, err := s.queries.EndClinicDentist(ctx, repository.EndClinicDentistParams{ClinicID: clinicID, DentistID: dentistID})
+	affected, err := qtx.EndClinicDentist(ctx, repository.EndClinicDentistParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentistID,
+	})
 	if err != nil {
 		return mapDatabaseError(err)
 	}
 	if affected == 0 {
 		return notFoundError("clinic dentist active link not found")
 	}
+	if err := ensureClinicRoleInvariant(ctx, qtx, clinicID, before); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
 	return nil
 }
 
@@ -716,14 +1201,21 @@ func (s *Service) UpdateDentist(ctx context.Context, dentistID string, input Upd
 	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateDentist")
 	defer span.End()
 
-	if input.LegalName == nil && input.Email == nil && input.Phone == nil {
+	if input.LegalName == nil &&
+		input.Email == nil &&
+		input.Phone == nil &&
+		input.CRONumber == nil &&
+		input.CROState == nil &&
+		input.Address == nil &&
+		input.SpecialtyIDs == nil {
 		return DentistOutput{}, validationError("at least one field must be provided")
 	}
 	if input.LegalName != nil && strings.TrimSpace(*input.LegalName) == "" {
 		return DentistOutput{}, validationError("legal_name cannot be empty")
 	}
-	if input.Email != nil && strings.TrimSpace(*input.Email) != "" && !validation.ValidateEmail(*input.Email) {
-		return DentistOutput{}, validationError("invalid email")
+	validations := s.newValidationCollector()
+	if err := validateEmailInput(input.Email, validations); err != nil {
+		return DentistOutput{}, err
 	}
 	if err := validateOptionalMaxLength("legal_name", input.LegalName, maxLegalNameLength); err != nil {
 		return DentistOutput{}, err
@@ -734,8 +1226,38 @@ func (s *Service) UpdateDentist(ctx context.Context, dentistID string, input Upd
 	if err := validateOptionalMaxLength("phone", input.Phone, maxPhoneLength); err != nil {
 		return DentistOutput{}, err
 	}
+	phone, err := normalizePhoneInput(input.Phone, validations)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	input.Phone = phone
+	croNumber, croState, err := normalizeCROInput(input.CRONumber, input.CROState, validations)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	address, err := s.resolveAddressInput(ctx, input.Address, validations)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	var specialtyIDs []string
+	if input.SpecialtyIDs != nil {
+		specialtyIDs, err = normalizeSpecialtyIDs(*input.SpecialtyIDs)
+		if err != nil {
+			return DentistOutput{}, err
+		}
+	}
 
-	dentist, err := s.queries.GetDentistByID(ctx, dentistID)
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return DentistOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	dentist, err := qtx.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return DentistOutput{}, notFoundError("dentist not found")
@@ -743,38 +1265,74 @@ func (s *Service) UpdateDentist(ctx context.Context, dentistID string, input Upd
 		return DentistOutput{}, err
 	}
 
-	person, err := s.queries.UpdatePerson(ctx, repository.UpdatePersonParams{
-		ID:        dentist.PersonID,
-		LegalName: optionalString(input.LegalName),
-		Email:     optionalString(input.Email),
-		Phone:     optionalString(input.Phone),
+	person, err := qtx.UpdatePerson(ctx, repository.UpdatePersonParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentist.PersonID,
+		LegalName:      optionalString(input.LegalName),
+		Email:          optionalString(input.Email),
+		Phone:          optionalString(input.Phone),
 	})
 	if err != nil {
 		return DentistOutput{}, mapDatabaseError(err)
 	}
+	if err := upsertAddress(ctx, qtx, person.ID, address); err != nil {
+		return DentistOutput{}, err
+	}
 
-	return DentistOutput{
-		ID:          dentist.ID,
-		PersonID:    person.ID,
-		LegalName:   person.LegalName,
-		TaxIDNumber: person.TaxIDNumber,
-		Email:       nullToPointer(person.Email),
-		Phone:       nullToPointer(person.Phone),
-	}, nil
+	if croNumber.Valid {
+		dentist, err = qtx.UpdateDentistCRO(ctx, repository.UpdateDentistCROParams{
+			OrganizationID: organizationID(ctx),
+			ID:             dentist.ID,
+			CroNumber:      croNumber,
+			CroState:       croState,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return DentistOutput{}, notFoundError("dentist not found")
+			}
+			return DentistOutput{}, mapDentistDatabaseError(err)
+		}
+	}
+	if input.SpecialtyIDs != nil {
+		if err := replaceDentistSpecialties(ctx, qtx, dentist.ID, specialtyIDs); err != nil {
+			return DentistOutput{}, err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return DentistOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	output := mapDentistOutput(dentist, person)
+	output.PhotoURL = s.dentistPhotoURL(dentist.ID, dentist.PhotoUpdatedAt)
+	output.ThumbnailURL = s.dentistThumbnailURL(dentist.ID, dentist.PhotoUpdatedAt)
+	output.Address, err = s.loadAddress(ctx, person.ID)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	output.Specialties, err = s.loadDentistSpecialties(ctx, dentist.ID)
+	if err != nil {
+		return DentistOutput{}, err
+	}
+	output.Warnings = validations.result()
+	return output, nil
 }
 
 func (s *Service) DeleteDentist(ctx context.Context, dentistID string) error {
 	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteDentist")
 	defer span.End()
 
-	tx, err := s.db.BeginTx(ctx, nil)
+	tx, err := s.db.Begin(ctx)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
 	}
-	defer tx.Rollback()
+	defer tx.Rollback(ctx)
 
 	qtx := s.txQuerier(tx)
-	dentist, err := qtx.GetDentistByID(ctx, dentistID)
+	dentist, err := qtx.GetDentistByID(ctx, repository.GetDentistByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	})
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return notFoundError("dentist not found")
@@ -782,67 +1340,181 @@ func (s *Service) DeleteDentist(ctx context.Context, dentistID string) error {
 		return err
 	}
 
-	if _, err := qtx.EndClinicDentistsByDentist(ctx, dentistID); err != nil {
+	clinicIDs, err := qtx.ListActiveClinicIDsByDentist(ctx, repository.ListActiveClinicIDsByDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	roleCountsByClinic := make(map[string]repository.CountClinicRoleHoldersRow, len(clinicIDs))
+	for _, clinicID := range clinicIDs {
+		counts, err := lockClinicRoleCounts(ctx, qtx, clinicID)
+		if err != nil {
+			return err
+		}
+		roleCountsByClinic[clinicID] = counts
+	}
+
+	if _, err := qtx.EndClinicDentistsByDentist(ctx, repository.EndClinicDentistsByDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	}); err != nil {
 		return mapDatabaseError(err)
 	}
-	if _, err := qtx.DeleteDentist(ctx, dentistID); err != nil {
+	if _, err := qtx.DeleteDentist(ctx, repository.DeleteDentistParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	for _, clinicID := range clinicIDs {
+		if err := ensureClinicRoleInvariant(ctx, qtx, clinicID, roleCountsByClinic[clinicID]); err != nil {
+			return err
+		}
+	}
+	// Logins go away at once; documents and the person wait in the trash until the grace period ends.
+	deferred := s.deletionGracePeriod > 0
+	if !deferred {
+		if _, err := qtx.DeleteDentistDocumentsByDentist(ctx, repository.DeleteDentistDocumentsByDentistParams{
+			OrganizationID: organizationID(ctx),
+			DentistID:      dentistID,
+		}); err != nil {
+			return mapDatabaseError(err)
+		}
+	}
+	if _, err := qtx.DeleteUsersByDentistID(ctx, repository.DeleteUsersByDentistIDParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	}); err != nil {
 		return mapDatabaseError(err)
 	}
-	if _, err := qtx.DeletePerson(ctx, dentist.PersonID); err != nil {
+	if deferred {
+		if err := s.scheduleDeletion(ctx, qtx, DeletedResourceDentist, dentistID); err != nil {
+			return err
+		}
+	} else if _, err := qtx.DeletePerson(ctx, repository.DeletePersonParams{
+		OrganizationID: organizationID(ctx),
+		ID:             dentist.PersonID,
+	}); err != nil {
 		return mapDatabaseError(err)
 	}
 
-	if err := tx.Commit(); err != nil {
+	if err := tx.Commit(ctx); err != nil {
 		return fmt.Errorf("commit transaction: %w", err)
 	}
 	return nil
 }
 
-func (s *Service) loadClinicSummary(ctx context.Context, clinicID string) (ClinicOutput, error) {
-	row, err := s.queries.GetClinicDetails(ctx, clinicID)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ClinicOutput{}, notFoundError("clinic not found")
-		}
-		return ClinicOutput{}, err
+func (s *Service) mapClinicRows(ctx context.Context, rows []repository.ListClinicDetailsCursorRow) ([]ClinicOutput, error) {
+	clinicIDs := make([]string, 0, len(rows))
+	personIDs := make([]string, 0, len(rows))
+	for _, row := range rows {
+		clinicIDs = append(clinicIDs, row.ClinicID)
+		personIDs = append(personIDs, row.PersonID)
 	}
 
-	dentists, err := s.queries.ListDentistsByClinicID(ctx, clinicID)
-	if err != nil {
-		return ClinicOutput{}, err
+	var (
+		dentistIDsByClinic map[string][]string
+		addressesByPerson  map[string]*AddressOutput
+		registryByClinic   map[string]*CompanyRegistryOutput
+	)
+	if err := loadInParallel(ctx,
+		func(ctx context.Context) (err error) {
+			dentistIDsByClinic, err = s.loadClinicDentistIDsByClinicIDs(ctx, clinicIDs)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			addressesByPerson, err = s.loadAddressesByPersonIDs(ctx, personIDs)
+			return err
+		},
+		func(ctx context.Context) (err error) {
+			registryByClinic, err = s.loadClinicRegistryRecordsByClinicIDs(ctx, clinicIDs)
+			return err
+		},
+	); err != nil {
+		return nil, err
 	}
 
-	return mapClinicSummary(
-		row.ClinicID,
-		row.PersonID,
-		row.LegalName,
-		row.TradeName,
-		row.TaxIDNumber,
-		row.Email,
-		row.Phone,
-		mapDentistIDs(dentists),
-	), nil
+	clinics := make([]ClinicOutput, 0, len(rows))
+	for _, row := range rows {
+		clinic := mapClinicSummary(
+			row.ClinicID,
+			row.PersonID,
+			row.LegalName,
+			row.TradeName,
+			row.TaxIDNumber,
+			row.Email,
+			row.Phone,
+			row.Timezone,
+			dentistIDsByClinic[row.ClinicID],
+		)
+		clinic.ParentClinicID = nullUUIDToPointer(row.ParentClinicID)
+		clinic.OnboardingStatus = row.OnboardingStatus
+		clinic.OnboardingStatusChangedAt = row.OnboardingStatusChangedAt
+		clinic.Status = clinicStatus(row.DeactivatedAt)
+		clinic.DeactivatedAt = nullTimeToPointer(row.DeactivatedAt)
+		clinic.DeactivationReason = nullToPointer(row.DeactivationReason)
+		clinic.DeletedAt = nullTimeToPointer(row.DeletedAt)
+		clinic.Address = addressesByPerson[row.PersonID]
+		clinic.Registry = registryByClinic[row.ClinicID]
+		clinics = append(clinics, clinic)
+	}
+	return clinics, nil
 }
 
-func (s *Service) loadClinicDetails(ctx context.Context, clinicID string) (ClinicDetailsOutput, error) {
-	row, err := s.queries.GetClinicDetails(ctx, clinicID)
+// loadClinicSummary and loadClinicDetails read the clinic, its address, registry record, dentists and bank accounts in a single statement.
+// Writers pass their transaction, so the response comes from the same snapshot they are about to commit.
+func loadClinicSummary(ctx context.Context, q repository.Querier, clinicID string) (ClinicOutput, error) {
+	row, err := getClinicAggregate(ctx, q, clinicID, false)
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return ClinicDetailsOutput{}, notFoundError("clinic not found")
-		}
-		return ClinicDetailsOutput{}, err
+		return ClinicOutput{}, err
 	}
+	return mapClinicAggregate(row)
+}
 
-	dentists, err := s.queries.ListDentistsByClinicID(ctx, clinicID)
+func loadClinicDetails(ctx context.Context, q repository.Querier, clinicID string) (ClinicDetailsOutput, error) {
+	row, err := getClinicAggregate(ctx, q, clinicID, true)
 	if err != nil {
 		return ClinicDetailsOutput{}, err
 	}
-	bankAccounts, err := s.queries.ListBankAccountsByClinicID(ctx, clinicID)
+	clinic, err := mapClinicAggregate(row)
 	if err != nil {
 		return ClinicDetailsOutput{}, err
 	}
+	var bankAccounts []clinicAggregateBankAccount
+	if err := json.Unmarshal(row.BankAccounts, &bankAccounts); err != nil {
+		return ClinicDetailsOutput{}, fmt.Errorf("decode clinic bank accounts: %w", err)
+	}
+	output := ClinicDetailsOutput{ClinicOutput: clinic, BankAccounts: make([]BankAccountOutput, 0, len(bankAccounts))}
+	for _, account := range bankAccounts {
+		output.BankAccounts = append(output.BankAccounts, account.output())
+	}
+	return output, nil
+}
+
+func getClinicAggregate(ctx context.Context, q repository.Querier, clinicID string, includeBankAccounts bool) (repository.GetClinicAggregateRow, error) {
+	row, err := q.GetClinicAggregate(ctx, repository.GetClinicAggregateParams{
+		OrganizationID:      organizationID(ctx),
+		ID:                  clinicID,
+		IncludeBankAccounts: includeBankAccounts,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return repository.GetClinicAggregateRow{}, notFoundError("clinic not found")
+		}
+		return repository.GetClinicAggregateRow{}, err
+	}
+	return row, nil
+}
+
+func mapClinicAggregate(row repository.GetClinicAggregateRow) (ClinicOutput, error) {
+	var dentistIDs []string
+	if err := json.Unmarshal(row.DentistIds, &dentistIDs); err != nil {
+		return ClinicOutput{}, fmt.Errorf("decode clinic dentists: %w", err)
+	}
 
-	return mapClinicDetails(
+	clinic := mapClinicSummary(
 		row.ClinicID,
 		row.PersonID,
 		row.LegalName,
@@ -850,26 +1522,91 @@ func (s *Service) loadClinicDetails(ctx context.Context, clinicID string) (Clini
 		row.TaxIDNumber,
 		row.Email,
 		row.Phone,
-		mapDentistIDs(dentists),
-		bankAccounts,
-	), nil
+		row.Timezone,
+		dentistIDs,
+	)
+	clinic.ParentClinicID = nullUUIDToPointer(row.ParentClinicID)
+	clinic.OnboardingStatus = row.OnboardingStatus
+	clinic.OnboardingStatusChangedAt = row.OnboardingStatusChangedAt
+	clinic.Status = clinicStatus(row.DeactivatedAt)
+	clinic.DeactivatedAt = nullTimeToPointer(row.DeactivatedAt)
+	clinic.DeactivationReason = nullToPointer(row.DeactivationReason)
+	if row.AddressStreet.Valid {
+		clinic.Address = mapAddress(repository.Address{
+			Street:     row.AddressStreet.String,
+			Number:     row.AddressNumber,
+			Complement: row.AddressComplement,
+			City:       row.AddressCity.String,
+			State:      row.AddressState.String,
+			Cep:        row.AddressCep.String,
+		})
+	}
+	if row.RegistryLegalName.Valid {
+		clinic.Registry = mapClinicRegistryRecord(repository.ClinicRegistryRecord{
+			LegalName:          row.RegistryLegalName.String,
+			TradeName:          row.RegistryTradeName,
+			CnaeCode:           row.RegistryCnaeCode,
+			CnaeDescription:    row.RegistryCnaeDescription,
+			RegistrationStatus: row.RegistryRegistrationStatus.String,
+			Source:             row.RegistrySource.String,
+			FetchedAt:          row.RegistryFetchedAt.Time,
+		})
+	}
+	return clinic, nil
 }
 
-func (s *Service) loadClinicDentistIDsByClinicIDs(ctx context.Context, clinicIDs []string) (map[string][]string, error) {
-	dentistIDsByClinic := make(map[string][]string, len(clinicIDs))
-	if len(clinicIDs) == 0 {
-		return dentistIDsByClinic, nil
-	}
+// clinicAggregateBankAccount is one element of the bank_accounts column, which holds the rows as to_jsonb renders them.
+type clinicAggregateBankAccount struct {
+	ID                        string     `json:"id"`
+	BankCode                  string     `json:"bank_code"`
+	BranchNumber              string     `json:"branch_number"`
+	AccountNumber             string     `json:"account_number"`
+	AccountType               string     `json:"account_type"`
+	HolderName                *string    `json:"holder_name"`
+	HolderTaxID               *string    `json:"holder_tax_id"`
+	HolderReviewRequired      bool       `json:"holder_review_required"`
+	IsPrimary                 bool       `json:"is_primary"`
+	VerificationStatus        string     `json:"verification_status"`
+	VerificationProvider      *string    `json:"verification_provider"`
+	VerificationRequestedAt   *time.Time `json:"verification_requested_at"`
+	VerifiedAt                *time.Time `json:"verified_at"`
+	VerificationFailureReason *string    `json:"verification_failure_reason"`
+}
 
-	dentistRows, err := s.queries.ListDentistsByClinicIDs(ctx, clinicIDs)
-	if err != nil {
-		return nil, err
-	}
-	for _, row := range dentistRows {
-		dentistIDsByClinic[row.ClinicID] = append(dentistIDsByClinic[row.ClinicID], row.DentistID)
+func (a clinicAggregateBankAccount) output() BankAccountOutput {
+	return BankAccountOutput{
+		ID:                        a.ID,
+		BankCode:                  a.BankCode,
+		BranchNumber:              a.BranchNumber,
+		AccountNumber:             maskAccountNumber(a.AccountNumber),
+		AccountType:               a.AccountType,
+		HolderName:                a.HolderName,
+		HolderTaxID:               a.HolderTaxID,
+		HolderReviewRequired:      a.HolderReviewRequired,
+		IsPrimary:                 a.IsPrimary,
+		VerificationStatus:        a.VerificationStatus,
+		VerificationProvider:      a.VerificationProvider,
+		VerificationRequestedAt:   a.VerificationRequestedAt,
+		VerifiedAt:                a.VerifiedAt,
+		VerificationFailureReason: a.VerificationFailureReason,
 	}
+}
 
-	return dentistIDsByClinic, nil
+func (s *Service) loadClinicDentistIDsByClinicIDs(ctx context.Context, clinicIDs []string) (map[string][]string, error) {
+	return loadBatch(ctx, s, "clinic_dentist_ids", clinicIDs, func(ctx context.Context, clinicIDs []string) (map[string][]string, error) {
+		dentistRows, err := s.queries.ListDentistsByClinicIDs(ctx, repository.ListDentistsByClinicIDsParams{
+			OrganizationID: organizationID(ctx),
+			ClinicIds:      clinicIDs,
+		})
+		if err != nil {
+			return nil, err
+		}
+		dentistIDsByClinic := make(map[string][]string, len(clinicIDs))
+		for _, row := range dentistRows {
+			dentistIDsByClinic[row.ClinicID] = append(dentistIDsByClinic[row.ClinicID], row.DentistID)
+		}
+		return dentistIDsByClinic, nil
+	})
 }
 
 func mapClinicSummary(
@@ -880,6 +1617,7 @@ func mapClinicSummary(
 	taxIDNumber string,
 	email sql.NullString,
 	phone sql.NullString,
+	timezone string,
 	dentistIDs []string,
 ) ClinicOutput {
 	if dentistIDs == nil {
@@ -887,14 +1625,16 @@ func mapClinicSummary(
 	}
 
 	return ClinicOutput{
-		ID:          clinicID,
-		PersonID:    personID,
-		LegalName:   legalName,
-		TradeName:   nullToPointer(tradeName),
-		TaxIDNumber: taxIDNumber,
-		Email:       nullToPointer(email),
-		Phone:       nullToPointer(phone),
-		DentistIDs:  dentistIDs,
+		ID:           clinicID,
+		PersonID:     personID,
+		LegalName:    legalName,
+		TradeName:    nullToPointer(tradeName),
+		TaxIDNumber:  taxIDNumber,
+		Email:        nullToPointer(email),
+		Phone:        nullToPointer(phone),
+		PhoneDisplay: phoneDisplay(phone),
+		Timezone:     timezone,
+		DentistIDs:   dentistIDs,
 	}
 }
 
@@ -906,6 +1646,7 @@ func mapClinicDetails(
 	taxIDNumber string,
 	email sql.NullString,
 	phone sql.NullString,
+	timezone string,
 	dentistIDs []string,
 	bankAccounts []repository.BankAccount,
 ) ClinicDetailsOutput {
@@ -918,6 +1659,7 @@ func mapClinicDetails(
 			taxIDNumber,
 			email,
 			phone,
+			timezone,
 			dentistIDs,
 		),
 		BankAccounts: mapBankAccounts(bankAccounts),
@@ -935,16 +1677,52 @@ func mapDentistIDs(rows []repository.ListDentistsByClinicIDRow) []string {
 func mapBankAccounts(rows []repository.BankAccount) []BankAccountOutput {
 	accounts := make([]BankAccountOutput, 0, len(rows))
 	for _, row := range rows {
-		accounts = append(accounts, BankAccountOutput{
-			ID:            row.ID,
-			BankCode:      row.BankCode,
-			BranchNumber:  row.BranchNumber,
-			AccountNumber: row.AccountNumber,
-		})
+		accounts = append(accounts, mapBankAccount(row))
 	}
 	return accounts
 }
 
+func mapBankAccount(row repository.BankAccount) BankAccountOutput {
+	return BankAccountOutput{
+		ID:                        row.ID,
+		BankCode:                  row.BankCode,
+		BranchNumber:              row.BranchNumber,
+		AccountNumber:             maskAccountNumber(row.AccountNumber),
+		AccountType:               row.AccountType,
+		HolderName:                nullToPointer(row.HolderName),
+		HolderTaxID:               nullToPointer(row.HolderTaxID),
+		HolderReviewRequired:      row.HolderReviewRequired,
+		IsPrimary:                 row.IsPrimary,
+		VerificationStatus:        row.VerificationStatus,
+		VerificationProvider:      nullToPointer(row.VerificationProvider),
+		VerificationRequestedAt:   nullTimeToPointer(row.VerificationRequestedAt),
+		VerifiedAt:                nullTimeToPointer(row.VerifiedAt),
+		VerificationFailureReason: nullToPointer(row.VerificationFailureReason),
+	}
+}
+
+func maskAccountNumber(accountNumber string) string {
+	runes := []rune(accountNumber)
+	if len(runes) <= maskedAccountNumberSuffix {
+		return accountNumberMask
+	}
+	return accountNumberMask + string(runes[len(runes)-maskedAccountNumberSuffix:])
+}
+
+func mapDentistOutput(dentist repository.Dentist, person repository.Person) DentistOutput {
+	return DentistOutput{
+		ID:           dentist.ID,
+		PersonID:     person.ID,
+		LegalName:    person.LegalName,
+		TaxIDNumber:  person.TaxIDNumber,
+		Email:        nullToPointer(person.Email),
+		Phone:        nullToPointer(person.Phone),
+		PhoneDisplay: phoneDisplay(person.Phone),
+		CRONumber:    nullToPointer(dentist.CroNumber),
+		CROState:     nullToPointer(dentist.CroState),
+	}
+}
+
 func mapDentistCursorRow(row repository.ListDentistsByClinicIDCursorRow) ClinicDentistOutput {
 	return mapClinicDentistSummary(
 		row.DentistID,
@@ -953,6 +1731,8 @@ func mapDentistCursorRow(row repository.ListDentistsByClinicIDCursorRow) ClinicD
 		row.TaxIDNumber,
 		row.Email,
 		row.Phone,
+		row.CroNumber,
+		row.CroState,
 		row.IsAdmin,
 		row.IsLegalRepresentative,
 		row.StartedAt,
@@ -966,18 +1746,23 @@ func mapClinicDentistSummary(
 	taxIDNumber string,
 	email sql.NullString,
 	phone sql.NullString,
+	croNumber sql.NullString,
+	croState sql.NullString,
 	isAdmin bool,
 	isLegalRepresentative bool,
 	startedAt time.Time,
 ) ClinicDentistOutput {
 	return ClinicDentistOutput{
 		DentistOutput: DentistOutput{
-			ID:          dentistID,
-			PersonID:    personID,
-			LegalName:   legalName,
-			TaxIDNumber: taxIDNumber,
-			Email:       nullToPointer(email),
-			Phone:       nullToPointer(phone),
+			ID:           dentistID,
+			PersonID:     personID,
+			LegalName:    legalName,
+			TaxIDNumber:  taxIDNumber,
+			Email:        nullToPointer(email),
+			Phone:        nullToPointer(phone),
+			PhoneDisplay: phoneDisplay(phone),
+			CRONumber:    nullToPointer(croNumber),
+			CROState:     nullToPointer(croState),
 		},
 		IsAdmin:               isAdmin,
 		IsLegalRepresentative: isLegalRepresentative,
@@ -995,6 +1780,9 @@ func validateBankAccountInput(input BankAccountInput) error {
 	if strings.TrimSpace(input.AccountNumber) == "" {
 		return fmt.Errorf("account_number is required")
 	}
+	if strings.Contains(input.AccountNumber, "*") {
+		return fmt.Errorf("account_number must be the full number, not the masked value")
+	}
 	if countTrimmedCharacters(input.BankCode) > maxBankFieldLength {
 		return fmt.Errorf("bank_code must be at most %d characters", maxBankFieldLength)
 	}
@@ -1004,7 +1792,7 @@ func validateBankAccountInput(input BankAccountInput) error {
 	if countTrimmedCharacters(input.AccountNumber) > maxBankFieldLength {
 		return fmt.Errorf("account_number must be at most %d characters", maxBankFieldLength)
 	}
-	return nil
+	return validateBankAccountHolderInput(input)
 }
 
 func validateClinicFieldsLength(taxID *string, legalName *string, tradeName *string, email *string, phone *string) error {
@@ -1044,11 +1832,76 @@ func countTrimmedCharacters(value string) int {
 	return utf8.RuneCountInString(strings.TrimSpace(value))
 }
 
+func validateEmailInput(email *string, validations *validationCollector) error {
+	if email == nil || strings.TrimSpace(*email) == "" {
+		return nil
+	}
+	return validations.check(ValidationRuleEmail, validation.ValidateEmail(*email), "invalid email")
+}
+
+func normalizePhoneInput(value *string, validations *validationCollector) (*string, error) {
+	if value == nil || strings.TrimSpace(*value) == "" {
+		return value, nil
+	}
+	normalized, ok := validation.NormalizePhone(*value)
+	if err := validations.check(ValidationRulePhone, ok, "invalid phone"); err != nil {
+		return nil, err
+	}
+	if !ok {
+		normalized = strings.TrimSpace(*value)
+	}
+	return &normalized, nil
+}
+
+func phoneDisplay(phone sql.NullString) *string {
+	if !phone.Valid {
+		return nil
+	}
+	display := validation.FormatPhoneDisplay(phone.String)
+	return &display
+}
+
+func normalizeCROInput(number *string, state *string, validations *validationCollector) (sql.NullString, sql.NullString, error) {
+	rawNumber := optionalString(number)
+	rawState := optionalString(state)
+	if !rawNumber.Valid && !rawState.Valid {
+		return sql.NullString{}, sql.NullString{}, nil
+	}
+	if rawNumber.Valid != rawState.Valid {
+		return sql.NullString{}, sql.NullString{}, validationError("cro_number and cro_state must be provided together")
+	}
+	if err := validateMaxLength("cro_number", rawNumber.String, maxCRONumberLength); err != nil {
+		return sql.NullString{}, sql.NullString{}, err
+	}
+
+	normalizedState := validation.NormalizeState(rawState.String)
+	if err := validations.check(ValidationRuleCRO, validation.ValidateState(normalizedState), "invalid cro_state"); err != nil {
+		return sql.NullString{}, sql.NullString{}, err
+	}
+	normalizedNumber := validation.NormalizeCRONumber(rawNumber.String)
+	validNumber := validation.ValidateCRONumber(normalizedNumber)
+	if err := validations.check(ValidationRuleCRO, validNumber, "invalid cro_number"); err != nil {
+		return sql.NullString{}, sql.NullString{}, err
+	}
+	if !validNumber && normalizedNumber == "" {
+		normalizedNumber = strings.ToUpper(rawNumber.String)
+	}
+
+	return sql.NullString{String: normalizedNumber, Valid: true}, sql.NullString{String: normalizedState, Valid: true}, nil
+}
+
 func validateBankAccountsInput(accounts []BankAccountInput) error {
+	primaries := 0
 	for idx, account := range accounts {
 		if err := validateBankAccountInput(account); err != nil {
 			return validationError(fmt.Sprintf("bank_accounts[%d]: %s", idx, err.Error()))
 		}
+		if account.IsPrimary {
+			primaries++
+		}
+	}
+	if primaries > 1 {
+		return validationError("only one bank account can be flagged as is_primary")
 	}
 	return nil
 }
@@ -1079,6 +1932,22 @@ func nullToPointer(value sql.NullString) *string {
 	return &v
 }
 
+func nullTimeToPointer(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	v := value.Time
+	return &v
+}
+
+func nullUUIDToPointer(value uuid.NullUUID) *string {
+	if !value.Valid {
+		return nil
+	}
+	v := value.UUID.String()
+	return &v
+}
+
 func newUUIDV7() (string, error) {
 	id, err := uuid.NewV7()
 	if err != nil {
@@ -1097,6 +1966,20 @@ func mapDatabaseError(err error) error {
 	return err
 }
 
+func mapDentistDatabaseError(err error) error {
+	if isConstraintViolation(err, croUniqueConstraint) {
+		return conflictError("cro_number already registered for this cro_state")
+	}
+	return mapDatabaseError(err)
+}
+
+func isConstraintViolation(err error, constraint string) bool {
+	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok {
+		return pgErr.ConstraintName == constraint
+	}
+	return strings.Contains(err.Error(), constraint)
+}
+
 func isUniqueConstraintError(err error) bool {
 	if pgErr, ok := errors.AsType[*pgconn.PgError](err); ok {
 		return pgErr.Code == "23505"
@@ -1125,3 +2008,63 @@ func normalizeCursorLimit(limit int) int {
 	}
 	return limit
 }
+
+// keysetCursor is the (sort key, id) pair a page continues after. The pair travels in the cursor itself, so the next page does
+// not depend on the row it came from still existing. A NULL key is kept for lists that sort NULLs last.
+type keysetCursor struct {
+	At sql.NullTime
+	ID uuid.NullUUID
+}
+
+// keysetCursorNullKey stands for a NULL sort key, so an empty key is never a valid cursor.
+const keysetCursorNullKey = "null"
+
+func encodeKeysetCursor(at sql.NullTime, id string) *string {
+	key := keysetCursorNullKey
+	if at.Valid {
+		key = at.Time.UTC().Format(time.RFC3339Nano)
+	}
+	cursor := base64.RawURLEncoding.EncodeToString([]byte(key + "," + id))
+	return &cursor
+}
+
+// decodeKeysetCursor reads a cursor for a list sorted by a NOT NULL column, so a NULL key is rejected like any other bad cursor.
+func decodeKeysetCursor(cursor *string) (keysetCursor, error) {
+	return parseKeysetCursor(cursor, false)
+}
+
+// decodeNullableKeysetCursor also accepts the NULL key, for lists that sort NULLs last.
+func decodeNullableKeysetCursor(cursor *string) (keysetCursor, error) {
+	return parseKeysetCursor(cursor, true)
+}
+
+func parseKeysetCursor(cursor *string, nullableKey bool) (keysetCursor, error) {
+	if cursor == nil {
+		return keysetCursor{}, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(*cursor)
+	if err != nil {
+		return keysetCursor{}, validationError("invalid cursor")
+	}
+	key, id, ok := strings.Cut(string(raw), ",")
+	if !ok || key == "" {
+		return keysetCursor{}, validationError("invalid cursor")
+	}
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return keysetCursor{}, validationError("invalid cursor")
+	}
+	decoded := keysetCursor{ID: uuid.NullUUID{UUID: parsedID, Valid: true}}
+	if key == keysetCursorNullKey {
+		if !nullableKey {
+			return keysetCursor{}, validationError("invalid cursor")
+		}
+		return decoded, nil
+	}
+	at, err := time.Parse(time.RFC3339Nano, key)
+	if err != nil {
+		return keysetCursor{}, validationError("invalid cursor")
+	}
+	decoded.At = sql.NullTime{Time: at, Valid: true}
+	return decoded, nil
+}
@@ -1,22 +1,41 @@
 package service
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"slices"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"golang.org/x/crypto/bcrypt"
 
 	"capim-test/internal/db/repository"
+	"capim-test/internal/keywrap"
 )
 
 type mockQuerier struct {
 	repository.Querier
 	getUserByEmailFn             func(ctx context.Context, email string) (repository.User, error)
+	getUserByIDFn                func(ctx context.Context, id string) (repository.User, error)
 	createUserFn                 func(ctx context.Context, arg repository.CreateUserParams) (repository.User, error)
 	getClinicByIDFn              func(ctx context.Context, id string) (repository.Clinic, error)
 	lockClinicForUpdateFn        func(ctx context.Context, id string) (string, error)
@@ -24,6 +43,272 @@ type mockQuerier struct {
 	deleteBankAccountsByClinicFn func(ctx context.Context, clinicID string) (int64, error)
 	deleteClinicFn               func(ctx context.Context, id string) (int64, error)
 	deletePersonFn               func(ctx context.Context, id string) (int64, error)
+	countActiveBranchesFn        func(ctx context.Context, parentClinicID string) (int32, error)
+	payoutFile                   *[]byte
+	listDuplicateCandidatesFn    func(ctx context.Context, arg repository.ListClinicDuplicateCandidatesParams) ([]repository.ListClinicDuplicateCandidatesRow, error)
+	isLegalRepresentativeFn      func(ctx context.Context, arg repository.IsClinicLegalRepresentativeTaxIDParams) (bool, error)
+	hasActiveFinancialHoldFn     func(ctx context.Context, clinicID string) (bool, error)
+	listBankAccountHistoryFn     func(ctx context.Context, arg repository.ListClinicBankAccountHistoryCursorParams) ([]repository.ListClinicBankAccountHistoryCursorRow, error)
+	existsOtherActivePersonFn    func(ctx context.Context, arg repository.ExistsOtherActivePersonByTaxIDParams) (bool, error)
+	deletePreviewCountsFn        func(ctx context.Context, clinicID string) (repository.GetClinicDeletePreviewCountsRow, error)
+	createPendingDeletionFn      func(ctx context.Context, arg repository.CreatePendingDeletionParams) error
+	listAuditLogsBetweenFn       func(ctx context.Context, arg repository.ListAuditLogsCreatedBetweenParams) ([]repository.AuditLog, error)
+	integrityFindingsFn          func(check string, pageLimit int32) ([]string, error)
+	auditChainHead               repository.AuditChainHead
+	organization                 repository.Organization
+	organizationUsage            repository.GetOrganizationUsageRow
+	sealedAuditLogs              []repository.AuditLog
+	organizationKey              *repository.OrganizationKey
+	platformOperator             repository.PlatformOperator
+	schemaOrganizations          []repository.Organization
+	clinicAggregate              *repository.GetClinicAggregateRow
+	bulkInserts                  *[]any
+	dueNotifications             []repository.Notification
+	notificationWrites           *[]any
+	suppressedRecipients         map[string]bool
+	inventoryWrites              *[]any
+	dueAttachmentScans           []repository.AttachmentScan
+	attachmentScanWrites         *[]any
+	photosNeedingThumbnail       []repository.ListDentistsNeedingPhotoThumbnailRow
+	thumbnailWrites              *[]any
+	dueReports                   []repository.Report
+	lowStockItems                []repository.ListLowStockInventoryItemsRow
+	reportWrites                 *[]any
+}
+
+func (m mockQuerier) ListExpiredReports(ctx context.Context, arg repository.ListExpiredReportsParams) ([]repository.Report, error) {
+	return nil, nil
+}
+
+func (m mockQuerier) ClaimDueReports(ctx context.Context, arg repository.ClaimDueReportsParams) ([]repository.Report, error) {
+	return m.dueReports, nil
+}
+
+func (m mockQuerier) ListLowStockInventoryItems(ctx context.Context, arg repository.ListLowStockInventoryItemsParams) ([]repository.ListLowStockInventoryItemsRow, error) {
+	return m.lowStockItems, nil
+}
+
+func (m mockQuerier) UpdateReportProgress(ctx context.Context, arg repository.UpdateReportProgressParams) error {
+	return nil
+}
+
+func (m mockQuerier) RescheduleReport(ctx context.Context, arg repository.RescheduleReportParams) error {
+	*m.reportWrites = append(*m.reportWrites, arg)
+	return nil
+}
+
+func (m mockQuerier) CompleteReport(ctx context.Context, arg repository.CompleteReportParams) (int64, error) {
+	*m.reportWrites = append(*m.reportWrites, arg)
+	return 1, nil
+}
+
+func (m mockQuerier) FailReport(ctx context.Context, arg repository.FailReportParams) (int64, error) {
+	*m.reportWrites = append(*m.reportWrites, arg)
+	return 1, nil
+}
+
+func (m mockQuerier) ListDentistsNeedingPhotoThumbnail(ctx context.Context, arg repository.ListDentistsNeedingPhotoThumbnailParams) ([]repository.ListDentistsNeedingPhotoThumbnailRow, error) {
+	return m.photosNeedingThumbnail, nil
+}
+
+func (m mockQuerier) UpdateDentistPhotoThumbnail(ctx context.Context, arg repository.UpdateDentistPhotoThumbnailParams) (int64, error) {
+	*m.thumbnailWrites = append(*m.thumbnailWrites, arg)
+	return 1, nil
+}
+
+func (m mockQuerier) ClaimDueAttachmentScans(ctx context.Context, arg repository.ClaimDueAttachmentScansParams) ([]repository.AttachmentScan, error) {
+	return m.dueAttachmentScans, nil
+}
+
+func (m mockQuerier) RescheduleAttachmentScan(ctx context.Context, arg repository.RescheduleAttachmentScanParams) error {
+	*m.attachmentScanWrites = append(*m.attachmentScanWrites, arg)
+	return nil
+}
+
+func (m mockQuerier) CompleteAttachmentScan(ctx context.Context, arg repository.CompleteAttachmentScanParams) (int64, error) {
+	*m.attachmentScanWrites = append(*m.attachmentScanWrites, arg)
+	return 1, nil
+}
+
+func (m mockQuerier) SetInventoryItemQuantity(ctx context.Context, arg repository.SetInventoryItemQuantityParams) error {
+	*m.inventoryWrites = append(*m.inventoryWrites, arg)
+	return nil
+}
+
+func (m mockQuerier) CreateInventoryMovement(ctx context.Context, arg repository.CreateInventoryMovementParams) (repository.InventoryMovement, error) {
+	*m.inventoryWrites = append(*m.inventoryWrites, arg)
+	return repository.InventoryMovement{ID: arg.ID, ItemID: arg.ItemID, Kind: arg.Kind, QuantityDelta: arg.QuantityDelta, QuantityAfter: arg.QuantityAfter}, nil
+}
+
+func (m mockQuerier) IsNotificationRecipientSuppressed(ctx context.Context, arg repository.IsNotificationRecipientSuppressedParams) (bool, error) {
+	return m.suppressedRecipients[arg.Channel+":"+arg.Recipient], nil
+}
+
+func (m mockQuerier) ClaimDueNotifications(ctx context.Context, arg repository.ClaimDueNotificationsParams) ([]repository.Notification, error) {
+	return m.dueNotifications, nil
+}
+
+func (m mockQuerier) MarkNotificationSent(ctx context.Context, arg repository.MarkNotificationSentParams) error {
+	*m.notificationWrites = append(*m.notificationWrites, arg)
+	return nil
+}
+
+func (m mockQuerier) MarkNotificationAttemptFailed(ctx context.Context, arg repository.MarkNotificationAttemptFailedParams) error {
+	*m.notificationWrites = append(*m.notificationWrites, arg)
+	return nil
+}
+
+func (m mockQuerier) CreateNotification(ctx context.Context, arg repository.CreateNotificationParams) error {
+	*m.notificationWrites = append(*m.notificationWrites, arg)
+	return nil
+}
+
+func (m mockQuerier) CreateBankAccounts(ctx context.Context, arg repository.CreateBankAccountsParams) error {
+	*m.bulkInserts = append(*m.bulkInserts, arg)
+	return nil
+}
+
+func (m mockQuerier) CreateAuditLogs(ctx context.Context, arg repository.CreateAuditLogsParams) error {
+	*m.bulkInserts = append(*m.bulkInserts, arg)
+	return nil
+}
+
+func (m mockQuerier) GetClinicAggregate(ctx context.Context, arg repository.GetClinicAggregateParams) (repository.GetClinicAggregateRow, error) {
+	if m.clinicAggregate == nil || m.clinicAggregate.ClinicID != arg.ID {
+		return repository.GetClinicAggregateRow{}, sql.ErrNoRows
+	}
+	row := *m.clinicAggregate
+	if !arg.IncludeBankAccounts {
+		row.BankAccounts = json.RawMessage(`[]`)
+	}
+	return row, nil
+}
+
+func (m mockQuerier) ListSchemaOrganizations(ctx context.Context) ([]repository.Organization, error) {
+	return m.schemaOrganizations, nil
+}
+
+func (m mockQuerier) GetPlatformOperatorByEmail(ctx context.Context, email string) (repository.PlatformOperator, error) {
+	if m.platformOperator.ID == "" {
+		return repository.PlatformOperator{}, sql.ErrNoRows
+	}
+	return m.platformOperator, nil
+}
+
+func (m mockQuerier) GetOrganizationKey(ctx context.Context, organizationID string) (repository.OrganizationKey, error) {
+	if m.organizationKey == nil || m.organizationKey.OrganizationID == "" {
+		return repository.OrganizationKey{}, sql.ErrNoRows
+	}
+	return *m.organizationKey, nil
+}
+
+func (m mockQuerier) CreateOrganizationKey(ctx context.Context, arg repository.CreateOrganizationKeyParams) error {
+	if m.organizationKey.OrganizationID == "" {
+		*m.organizationKey = repository.OrganizationKey{OrganizationID: arg.OrganizationID, WrappedKey: arg.WrappedKey}
+	}
+	return nil
+}
+
+func (m mockQuerier) LockOrganizationForUpdate(ctx context.Context, organizationID string) (repository.Organization, error) {
+	return m.organization, nil
+}
+
+func (m mockQuerier) GetOrganizationByID(ctx context.Context, id string) (repository.Organization, error) {
+	return m.organization, nil
+}
+
+func (m mockQuerier) GetOrganizationUsage(ctx context.Context, organizationID string) (repository.GetOrganizationUsageRow, error) {
+	return m.organizationUsage, nil
+}
+
+func (m mockQuerier) GetAuditChainHead(ctx context.Context, organizationID string) (repository.AuditChainHead, error) {
+	return m.auditChainHead, nil
+}
+
+func (m mockQuerier) CountUnsealedAuditLogs(ctx context.Context, organizationID string) (int64, error) {
+	return 0, nil
+}
+
+func (m mockQuerier) ListSealedAuditLogs(ctx context.Context, arg repository.ListSealedAuditLogsParams) ([]repository.AuditLog, error) {
+	rows := make([]repository.AuditLog, 0, len(m.sealedAuditLogs))
+	for _, row := range m.sealedAuditLogs {
+		if row.ChainSequence.Int64 > arg.AfterSequence && len(rows) < int(arg.PageLimit) {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+func (m mockQuerier) integrityFindings(check string, pageLimit int32) ([]string, error) {
+	if m.integrityFindingsFn != nil {
+		return m.integrityFindingsFn(check, pageLimit)
+	}
+	return nil, nil
+}
+
+func (m mockQuerier) ListClinicsWithoutActiveBankAccount(ctx context.Context, arg repository.ListClinicsWithoutActiveBankAccountParams) ([]string, error) {
+	return m.integrityFindings(IntegrityClinicWithoutBankAccount, arg.PageLimit)
+}
+
+func (m mockQuerier) ListClinicsWithoutPrimaryBankAccount(ctx context.Context, arg repository.ListClinicsWithoutPrimaryBankAccountParams) ([]string, error) {
+	return m.integrityFindings(IntegrityClinicWithoutPrimaryBankAccount, arg.PageLimit)
+}
+
+func (m mockQuerier) ListDentistsWithoutActiveClinic(ctx context.Context, arg repository.ListDentistsWithoutActiveClinicParams) ([]string, error) {
+	return m.integrityFindings(IntegrityDentistWithoutActiveClinic, arg.PageLimit)
+}
+
+func (m mockQuerier) ListOrphanedPeople(ctx context.Context, arg repository.ListOrphanedPeopleParams) ([]string, error) {
+	return m.integrityFindings(IntegrityOrphanedPerson, arg.PageLimit)
+}
+
+func (m mockQuerier) ListAuditLogsCreatedBetween(ctx context.Context, arg repository.ListAuditLogsCreatedBetweenParams) ([]repository.AuditLog, error) {
+	if m.listAuditLogsBetweenFn != nil {
+		return m.listAuditLogsBetweenFn(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m mockQuerier) CreatePendingDeletion(ctx context.Context, arg repository.CreatePendingDeletionParams) error {
+	if m.createPendingDeletionFn != nil {
+		return m.createPendingDeletionFn(ctx, arg)
+	}
+	return nil
+}
+
+func (m mockQuerier) GetClinicDeletePreviewCounts(ctx context.Context, arg repository.GetClinicDeletePreviewCountsParams) (repository.GetClinicDeletePreviewCountsRow, error) {
+	if m.deletePreviewCountsFn != nil {
+		return m.deletePreviewCountsFn(ctx, arg.ClinicID)
+	}
+	return repository.GetClinicDeletePreviewCountsRow{}, nil
+}
+
+func (m mockQuerier) ExistsOtherActivePersonByTaxID(ctx context.Context, arg repository.ExistsOtherActivePersonByTaxIDParams) (bool, error) {
+	if m.existsOtherActivePersonFn != nil {
+		return m.existsOtherActivePersonFn(ctx, arg)
+	}
+	return false, nil
+}
+
+func (m mockQuerier) HasActiveClinicFinancialHold(ctx context.Context, arg repository.HasActiveClinicFinancialHoldParams) (bool, error) {
+	if m.hasActiveFinancialHoldFn != nil {
+		return m.hasActiveFinancialHoldFn(ctx, arg.ClinicID)
+	}
+	return false, nil
+}
+
+func (m mockQuerier) ListClinicBankAccountHistoryCursor(ctx context.Context, arg repository.ListClinicBankAccountHistoryCursorParams) ([]repository.ListClinicBankAccountHistoryCursorRow, error) {
+	if m.listBankAccountHistoryFn != nil {
+		return m.listBankAccountHistoryFn(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m mockQuerier) IsClinicLegalRepresentativeTaxID(ctx context.Context, arg repository.IsClinicLegalRepresentativeTaxIDParams) (bool, error) {
+	if m.isLegalRepresentativeFn != nil {
+		return m.isLegalRepresentativeFn(ctx, arg)
+	}
+	return false, nil
 }
 
 func (m mockQuerier) GetUserByEmail(ctx context.Context, email string) (repository.User, error) {
@@ -33,6 +318,13 @@ func (m mockQuerier) GetUserByEmail(ctx context.Context, email string) (reposito
 	return repository.User{}, sql.ErrNoRows
 }
 
+func (m mockQuerier) GetUserByID(ctx context.Context, arg repository.GetUserByIDParams) (repository.User, error) {
+	if m.getUserByIDFn != nil {
+		return m.getUserByIDFn(ctx, arg.ID)
+	}
+	return repository.User{}, sql.ErrNoRows
+}
+
 func (m mockQuerier) CreateUser(ctx context.Context, arg repository.CreateUserParams) (repository.User, error) {
 	if m.createUserFn != nil {
 		return m.createUserFn(ctx, arg)
@@ -40,48 +332,83 @@ func (m mockQuerier) CreateUser(ctx context.Context, arg repository.CreateUserPa
 	return repository.User{ID: arg.ID, Email: arg.Email, PasswordHash: arg.PasswordHash}, nil
 }
 
-func (m mockQuerier) GetClinicByID(ctx context.Context, id string) (repository.Clinic, error) {
+func (m mockQuerier) GetClinicByID(ctx context.Context, arg repository.GetClinicByIDParams) (repository.Clinic, error) {
 	if m.getClinicByIDFn != nil {
-		return m.getClinicByIDFn(ctx, id)
+		return m.getClinicByIDFn(ctx, arg.ID)
 	}
 	return repository.Clinic{}, sql.ErrNoRows
 }
 
-func (m mockQuerier) LockClinicForUpdate(ctx context.Context, id string) (string, error) {
+func (m mockQuerier) LockClinicForUpdate(ctx context.Context, arg repository.LockClinicForUpdateParams) (string, error) {
 	if m.lockClinicForUpdateFn != nil {
-		return m.lockClinicForUpdateFn(ctx, id)
+		return m.lockClinicForUpdateFn(ctx, arg.ID)
 	}
-	return id, nil
+	return arg.ID, nil
 }
 
-func (m mockQuerier) EndClinicDentistsByClinic(ctx context.Context, clinicID string) (int64, error) {
+func (m mockQuerier) EndClinicDentistsByClinic(ctx context.Context, arg repository.EndClinicDentistsByClinicParams) (int64, error) {
 	if m.endClinicDentistsByClinicFn != nil {
-		return m.endClinicDentistsByClinicFn(ctx, clinicID)
+		return m.endClinicDentistsByClinicFn(ctx, arg.ClinicID)
 	}
 	return 1, nil
 }
 
-func (m mockQuerier) DeleteBankAccountsByClinicID(ctx context.Context, clinicID string) (int64, error) {
+func (m mockQuerier) DeleteBankAccountsByClinicID(ctx context.Context, arg repository.DeleteBankAccountsByClinicIDParams) (int64, error) {
 	if m.deleteBankAccountsByClinicFn != nil {
-		return m.deleteBankAccountsByClinicFn(ctx, clinicID)
+		return m.deleteBankAccountsByClinicFn(ctx, arg.ClinicID)
 	}
 	return 1, nil
 }
 
-func (m mockQuerier) DeleteClinic(ctx context.Context, id string) (int64, error) {
+func (m mockQuerier) DeleteClinic(ctx context.Context, arg repository.DeleteClinicParams) (int64, error) {
 	if m.deleteClinicFn != nil {
-		return m.deleteClinicFn(ctx, id)
+		return m.deleteClinicFn(ctx, arg.ID)
 	}
 	return 1, nil
 }
 
-func (m mockQuerier) DeletePerson(ctx context.Context, id string) (int64, error) {
+func (m mockQuerier) DeletePerson(ctx context.Context, arg repository.DeletePersonParams) (int64, error) {
 	if m.deletePersonFn != nil {
-		return m.deletePersonFn(ctx, id)
+		return m.deletePersonFn(ctx, arg.ID)
 	}
 	return 1, nil
 }
 
+func (m mockQuerier) CountActiveBranches(ctx context.Context, arg repository.CountActiveBranchesParams) (int32, error) {
+	if m.countActiveBranchesFn != nil {
+		return m.countActiveBranchesFn(ctx, arg.ParentClinicID)
+	}
+	return 0, nil
+}
+
+func (m mockQuerier) CompletePayoutBatch(ctx context.Context, arg repository.CompletePayoutBatchParams) error {
+	*m.payoutFile = arg.FileContent
+	return nil
+}
+
+func (m mockQuerier) GetPayoutBatchFile(ctx context.Context, arg repository.GetPayoutBatchFileParams) (repository.GetPayoutBatchFileRow, error) {
+	return repository.GetPayoutBatchFileRow{FileName: "batch.rem", FileContent: *m.payoutFile, Format: PayoutFormatCNAB240}, nil
+}
+
+func (m mockQuerier) ListClinicDuplicateCandidates(ctx context.Context, arg repository.ListClinicDuplicateCandidatesParams) ([]repository.ListClinicDuplicateCandidatesRow, error) {
+	if m.listDuplicateCandidatesFn != nil {
+		return m.listDuplicateCandidatesFn(ctx, arg)
+	}
+	return nil, nil
+}
+
+type stubAddressLookup struct {
+	result AddressLookupResult
+	found  bool
+	err    error
+	calls  int
+}
+
+func (l *stubAddressLookup) LookupCEP(ctx context.Context, cep string) (AddressLookupResult, bool, error) {
+	l.calls++
+	return l.result, l.found, l.err
+}
+
 func newAuthServiceForTest(q repository.Querier) *Service {
 	return &Service{
 		queries:           q,
@@ -121,196 +448,3685 @@ func TestUpdateClinicInvalidBankAccountIDToRemove(t *testing.T) {
 	}
 }
 
-func TestValidateMaxLengthCountsUnicodeCharacters(t *testing.T) {
-	if err := validateMaxLength("legal_name", strings.Repeat("á", 255), 255); err != nil {
-		t.Fatalf("expected multibyte input within character limit to pass, got: %v", err)
-	}
+func TestCreateClinicRejectsMultiplePrimaryBankAccounts(t *testing.T) {
+	svc := &Service{}
 
-	err := validateMaxLength("legal_name", strings.Repeat("á", 256), 255)
+	_, err := svc.CreateClinic(context.Background(), CreateClinicInput{
+		TaxIDNumber: "11222333000181",
+		LegalName:   "Clinica Sorriso Ltda",
+		BankAccounts: []BankAccountInput{
+			{BankCode: "001", BranchNumber: "1234", AccountNumber: "998877", IsPrimary: true},
+			{BankCode: "341", BranchNumber: "4321", AccountNumber: "112233", IsPrimary: true},
+		},
+	})
 	if !errors.Is(err, ErrValidation) {
-		t.Fatalf("expected ErrValidation for input over character limit, got: %v", err)
+		t.Fatalf("expected ErrValidation, got: %v", err)
 	}
 }
 
-func TestValidateBankAccountInputCountsUnicodeCharacters(t *testing.T) {
-	valid := BankAccountInput{
-		BankCode:      strings.Repeat("ç", maxBankFieldLength),
-		BranchNumber:  "1234",
-		AccountNumber: "998877",
+func TestEnsureSinglePrimaryBankAccount(t *testing.T) {
+	if err := ensureSinglePrimaryBankAccount([]repository.BankAccount{{IsPrimary: true}, {}}); err != nil {
+		t.Fatalf("expected one primary account to pass, got: %v", err)
 	}
-	if err := validateBankAccountInput(valid); err != nil {
-		t.Fatalf("expected multibyte bank code within character limit to pass, got: %v", err)
+	if err := ensureSinglePrimaryBankAccount([]repository.BankAccount{{}, {}}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation without a primary account, got: %v", err)
 	}
+}
 
-	invalid := BankAccountInput{
-		BankCode:      strings.Repeat("ç", maxBankFieldLength+1),
-		BranchNumber:  "1234",
-		AccountNumber: "998877",
+func TestEnsureBankAccountRemovable(t *testing.T) {
+	primary := repository.BankAccount{ID: "019f3329-a5a8-72ec-a95b-6e554247f442", IsPrimary: true}
+	secondary := repository.BankAccount{ID: "019f3329-a5a8-72ec-a95b-6e554247f443"}
+
+	if err := ensureBankAccountRemovable([]repository.BankAccount{primary, secondary}, secondary.ID); err != nil {
+		t.Fatalf("expected secondary account to be removable, got: %v", err)
 	}
-	if err := validateBankAccountInput(invalid); err == nil {
-		t.Fatalf("expected validation error for bank code over character limit")
+	if err := ensureBankAccountRemovable([]repository.BankAccount{primary, secondary}, primary.ID); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict when removing the primary account, got: %v", err)
+	}
+	if err := ensureBankAccountRemovable([]repository.BankAccount{secondary}, secondary.ID); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict when removing the last active account, got: %v", err)
+	}
+	if err := ensureBankAccountRemovable([]repository.BankAccount{primary}, secondary.ID); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown account, got: %v", err)
 	}
 }
 
-func TestCreateClinicRejectsOversizedUnicodeBeforeDB(t *testing.T) {
-	svc := &Service{}
+func TestVerifyBankAccountCallbackSignature(t *testing.T) {
+	body := []byte(`{"reference":"ver_123","status":"VERIFIED"}`)
+	mac := hmac.New(sha256.New, []byte("callback-secret"))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
 
-	_, err := svc.CreateClinic(context.Background(), CreateClinicInput{
-		TaxIDNumber: "43542338000150",
-		LegalName:   strings.Repeat("á", maxLegalNameLength+1),
-		BankAccounts: []BankAccountInput{{
-			BankCode:      "001",
-			BranchNumber:  "1234",
-			AccountNumber: "998877",
-		}},
-	})
-	if !errors.Is(err, ErrValidation) {
-		t.Fatalf("expected ErrValidation for oversized legal_name, got: %v", err)
+	svc := &Service{bankCallbackSecret: []byte("callback-secret")}
+	if err := svc.VerifyBankAccountCallbackSignature(body, signature); err != nil {
+		t.Fatalf("expected valid signature to pass, got: %v", err)
+	}
+	if err := svc.VerifyBankAccountCallbackSignature([]byte(`{"reference":"ver_123","status":"FAILED"}`), signature); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized for a tampered body, got: %v", err)
+	}
+	if err := (&Service{}).VerifyBankAccountCallbackSignature(body, signature); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized without a configured secret, got: %v", err)
 	}
 }
 
-func TestUpdateClinicRejectsOversizedUnicodeBeforeDB(t *testing.T) {
-	svc := &Service{}
-	tooLong := strings.Repeat("ç", maxTradeNameLength+1)
+func TestEnsureBankAccountChangeReviewable(t *testing.T) {
+	change := repository.BankAccountChange{Status: BankAccountChangePending, RequestedByUserID: "requester"}
 
-	_, err := svc.UpdateClinic(context.Background(), "019f3329-a5a8-72ec-a95b-6e554247f442", UpdateClinicInput{
-		TradeName: &tooLong,
-	})
-	if !errors.Is(err, ErrValidation) {
-		t.Fatalf("expected ErrValidation for oversized trade_name, got: %v", err)
+	if err := ensureBankAccountChangeReviewable(change, "requester", BankAccountChangeApproved); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden when the requester approves, got: %v", err)
+	}
+	if err := ensureBankAccountChangeReviewable(change, "requester", BankAccountChangeRejected); err != nil {
+		t.Fatalf("expected requester to withdraw the change, got: %v", err)
+	}
+	if err := ensureBankAccountChangeReviewable(change, "reviewer", BankAccountChangeApproved); err != nil {
+		t.Fatalf("expected another admin to approve, got: %v", err)
+	}
+
+	change.Status = BankAccountChangeApproved
+	if err := ensureBankAccountChangeReviewable(change, "reviewer", BankAccountChangeRejected); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for a reviewed change, got: %v", err)
 	}
 }
 
-func TestDeleteClinicLocksClinicBeforeDeletingBankAccounts(t *testing.T) {
-	clinicID := "019f3329-a5a8-72ec-a95b-6e554247f442"
-	personID := "019f3329-a5a8-72ec-a95b-6e554247f443"
-	calls := make([]string, 0, 6)
+func TestMapBankAccountMasksAccountNumber(t *testing.T) {
+	account := mapBankAccount(repository.BankAccount{AccountNumber: "12345678"})
+	if account.AccountNumber != "****5678" {
+		t.Fatalf("expected masked account number, got %q", account.AccountNumber)
+	}
+	if masked := maskAccountNumber("123"); masked != "****" {
+		t.Fatalf("expected short account numbers to be fully masked, got %q", masked)
+	}
+}
 
-	q := mockQuerier{
-		getClinicByIDFn: func(ctx context.Context, id string) (repository.Clinic, error) {
-			calls = append(calls, "GetClinicByID")
-			return repository.Clinic{ID: id, PersonID: personID}, nil
-		},
-		lockClinicForUpdateFn: func(ctx context.Context, id string) (string, error) {
-			calls = append(calls, "LockClinicForUpdate")
-			return id, nil
-		},
-		endClinicDentistsByClinicFn: func(ctx context.Context, id string) (int64, error) {
-			calls = append(calls, "EndClinicDentistsByClinic")
-			return 1, nil
-		},
-		deleteBankAccountsByClinicFn: func(ctx context.Context, id string) (int64, error) {
-			calls = append(calls, "DeleteBankAccountsByClinicID")
-			return 1, nil
-		},
-		deleteClinicFn: func(ctx context.Context, id string) (int64, error) {
-			calls = append(calls, "DeleteClinic")
-			return 1, nil
-		},
-		deletePersonFn: func(ctx context.Context, id string) (int64, error) {
-			calls = append(calls, "DeletePerson")
-			return 1, nil
+func TestRevealClinicBankAccountRequiresPermission(t *testing.T) {
+	svc := &Service{queries: mockQuerier{
+		getUserByIDFn: func(ctx context.Context, id string) (repository.User, error) {
+			return repository.User{ID: id, Role: UserRoleAdmin}, nil
 		},
+	}}
+	ctx := WithPrincipal(context.Background(), Principal{UserID: "admin", Role: UserRoleAdmin})
+
+	if _, err := svc.RevealClinicBankAccount(ctx, "clinic", "account"); !errors.Is(err, ErrForbidden) {
+		t.Fatalf("expected ErrForbidden without the bank details permission, got: %v", err)
 	}
+}
 
-	svc := &Service{}
-	if err := svc.deleteClinicWithinTx(context.Background(), q, clinicID); err != nil {
-		t.Fatalf("delete clinic within tx: %v", err)
+func TestValidateBankAccountHolderInput(t *testing.T) {
+	holderName := "Maria Souza"
+	validCPF := "529.982.247-25"
+	invalidCPF := "111.111.111-12"
+	base := BankAccountInput{BankCode: "001", BranchNumber: "1234", AccountNumber: "998877"}
+
+	valid := base
+	valid.AccountType = "savings"
+	valid.HolderName = &holderName
+	valid.HolderTaxID = &validCPF
+	if err := validateBankAccountInput(valid); err != nil {
+		t.Fatalf("expected CPF holder to be valid, got: %v", err)
 	}
 
-	want := []string{
-		"GetClinicByID",
-		"LockClinicForUpdate",
-		"EndClinicDentistsByClinic",
-		"DeleteBankAccountsByClinicID",
-		"DeleteClinic",
-		"DeletePerson",
+	invalidType := base
+	invalidType.AccountType = "investment"
+	if err := validateBankAccountInput(invalidType); err == nil {
+		t.Fatal("expected unknown account_type to be rejected")
 	}
 
-	if len(calls) != len(want) {
-		t.Fatalf("unexpected number of calls: got %d, want %d (%v)", len(calls), len(want), calls)
+	missingName := base
+	missingName.HolderTaxID = &validCPF
+	if err := validateBankAccountInput(missingName); err == nil {
+		t.Fatal("expected holder_tax_id without holder_name to be rejected")
 	}
-	for i := range want {
-		if calls[i] != want[i] {
-			t.Fatalf("unexpected call order at index %d: got %q, want %q (full calls: %v)", i, calls[i], want[i], calls)
-		}
+
+	invalidDocument := valid
+	invalidDocument.HolderTaxID = &invalidCPF
+	if err := validateBankAccountInput(invalidDocument); err == nil {
+		t.Fatal("expected invalid holder_tax_id to be rejected")
 	}
 }
 
-func TestEnsureUserCreatesWhenMissing(t *testing.T) {
-	created := false
-	q := mockQuerier{
-		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
-			return repository.User{}, sql.ErrNoRows
-		},
-		createUserFn: func(ctx context.Context, arg repository.CreateUserParams) (repository.User, error) {
-			created = true
-			if arg.Email != "admin@example.com" {
-				t.Fatalf("unexpected email: %s", arg.Email)
-			}
-			if err := bcrypt.CompareHashAndPassword([]byte(arg.PasswordHash), []byte("secret123")); err != nil {
-				t.Fatalf("password hash does not match input password: %v", err)
-			}
-			return repository.User{ID: arg.ID, Email: arg.Email, PasswordHash: arg.PasswordHash}, nil
-		},
-	}
-	svc := newAuthServiceForTest(q)
+func TestResolveBankAccountHolderFlagsMismatches(t *testing.T) {
+	clinic := repository.GetClinicDetailsRow{ClinicID: "clinic", LegalName: "Clinica Sorriso LTDA", TaxIDNumber: "11222333000181"}
+	holderName := "Maria Souza"
+	representativeCPF := "52998224725"
+	otherCNPJ := "11.444.777/0001-61"
+	q := mockQuerier{}
 
-	if err := svc.EnsureUser(context.Background(), "admin@example.com", "secret123"); err != nil {
-		t.Fatalf("ensure user: %v", err)
+	holder, err := resolveBankAccountHolder(context.Background(), q, clinic, BankAccountInput{})
+	if err != nil {
+		t.Fatalf("resolveBankAccountHolder: %v", err)
 	}
-	if !created {
-		t.Fatalf("expected CreateUser to be called")
+	if holder.TaxID != clinic.TaxIDNumber || holder.Name != clinic.LegalName || holder.ReviewRequired || holder.AccountType != BankAccountTypeChecking {
+		t.Fatalf("expected the clinic as default holder, got %+v", holder)
 	}
-}
+
+	holder, err = resolveBankAccountHolder(context.Background(), q, clinic, BankAccountInput{HolderName: &holderName, HolderTaxID: &otherCNPJ})
+	if err != nil {
+		t.Fatalf("resolveBankAccountHolder: %v", err)
+	}
+	if !holder.ReviewRequired {
+		t.Fatal("expected unrelated CNPJ holder to require review")
+	}
+
+	q.isLegalRepresentativeFn = func(ctx context.Context, arg repository.IsClinicLegalRepresentativeTaxIDParams) (bool, error) {
+		return arg.TaxIDNumber == representativeCPF, nil
+	}
+	holder, err = resolveBankAccountHolder(context.Background(), q, clinic, BankAccountInput{HolderName: &holderName, HolderTaxID: &representativeCPF})
+	if err != nil {
+		t.Fatalf("resolveBankAccountHolder: %v", err)
+	}
+	if holder.ReviewRequired {
+		t.Fatal("expected legal representative holder to be accepted")
+	}
+}
+
+func TestListClinicBankAccountHistoryMasksAndPaginates(t *testing.T) {
+	createdAt := time.Date(2026, 3, 2, 10, 30, 0, 123456000, time.UTC)
+	svc := &Service{queries: mockQuerier{
+		getClinicByIDFn: func(ctx context.Context, id string) (repository.Clinic, error) {
+			return repository.Clinic{ID: id}, nil
+		},
+		listBankAccountHistoryFn: func(ctx context.Context, arg repository.ListClinicBankAccountHistoryCursorParams) ([]repository.ListClinicBankAccountHistoryCursorRow, error) {
+			if arg.EntityType != AuditEntityBankAccount || arg.PageLimit != 2 {
+				t.Fatalf("unexpected history params: %+v", arg)
+			}
+			return []repository.ListClinicBankAccountHistoryCursorRow{
+				{ID: "event-1", AccountNumber: "12345678", Action: "bank_account.created", CreatedAt: createdAt},
+				{ID: "event-2", AccountNumber: "12345678", Action: "bank_account.deleted"},
+			}, nil
+		},
+	}}
+
+	entries, next, err := svc.ListClinicBankAccountHistory(context.Background(), "clinic", 1, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || entries[0].AccountNumber != "****5678" {
+		t.Fatalf("expected one masked entry, got %+v", entries)
+	}
+	if next == nil || *next != *encodeKeysetCursor(sql.NullTime{Time: createdAt, Valid: true}, "event-1") {
+		t.Fatalf("expected next cursor after event-1, got %v", next)
+	}
+}
+
+func TestKeysetCursorCarriesSortKeyAndID(t *testing.T) {
+	id := "019f3329-a5a8-72ec-a95b-6e554247f442"
+	createdAt := time.Date(2026, 3, 2, 10, 30, 0, 123456000, time.FixedZone("BRT", -3*60*60))
+
+	decoded, err := decodeKeysetCursor(encodeKeysetCursor(sql.NullTime{Time: createdAt, Valid: true}, id))
+	if err != nil {
+		t.Fatalf("decode cursor: %v", err)
+	}
+	if !decoded.At.Valid || !decoded.At.Time.Equal(createdAt) || !decoded.ID.Valid || decoded.ID.UUID.String() != id {
+		t.Fatalf("expected the created_at and id back, got %+v", decoded)
+	}
+
+	decoded, err = decodeNullableKeysetCursor(encodeKeysetCursor(sql.NullTime{}, id))
+	if err != nil || decoded.At.Valid || decoded.ID.UUID.String() != id {
+		t.Fatalf("expected a NULL sort key to survive, got %+v (%v)", decoded, err)
+	}
+
+	if decoded, err := decodeKeysetCursor(nil); err != nil || decoded.ID.Valid {
+		t.Fatalf("expected no cursor on the first page, got %+v (%v)", decoded, err)
+	}
+	for _, cursor := range []string{
+		id,
+		"bm90LWEtY3Vyc29y",
+		base64.RawURLEncoding.EncodeToString([]byte("yesterday," + id)),
+		base64.RawURLEncoding.EncodeToString([]byte("," + id)),
+		*encodeKeysetCursor(sql.NullTime{}, id),
+	} {
+		if _, err := decodeKeysetCursor(&cursor); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected cursor %q to be rejected, got %v", cursor, err)
+		}
+	}
+	emptyKey := base64.RawURLEncoding.EncodeToString([]byte("," + id))
+	if _, err := decodeNullableKeysetCursor(&emptyKey); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected an empty sort key to be rejected even where NULL keys are allowed, got %v", err)
+	}
+}
+
+func TestEnsureNoFinancialHoldBlocksHeldClinics(t *testing.T) {
+	q := mockQuerier{hasActiveFinancialHoldFn: func(ctx context.Context, clinicID string) (bool, error) {
+		return clinicID == "held", nil
+	}}
+	if err := ensureNoFinancialHold(context.Background(), q, "held"); !errors.Is(err, ErrFinancialHold) {
+		t.Fatalf("expected ErrFinancialHold, got %v", err)
+	}
+	if err := ensureNoFinancialHold(context.Background(), q, "clear"); err != nil {
+		t.Fatalf("expected clinic without hold to pass, got %v", err)
+	}
+}
+
+func TestPlaceClinicFinancialHoldValidatesReason(t *testing.T) {
+	svc := &Service{}
+	ctx := WithPrincipal(context.Background(), Principal{UserID: "admin", Role: UserRoleAdmin})
+	if _, err := svc.PlaceClinicFinancialHold(ctx, "clinic", PlaceFinancialHoldInput{ReasonCode: "SUSPICIOUS"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown reason code to be rejected, got %v", err)
+	}
+	if _, err := svc.PlaceClinicFinancialHold(ctx, "clinic", PlaceFinancialHoldInput{ReasonCode: FinancialHoldOther}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected OTHER without notes to be rejected, got %v", err)
+	}
+}
+
+func TestEnsureTaxIDAvailableForRestoreRejectsReRegisteredTaxID(t *testing.T) {
+	q := mockQuerier{existsOtherActivePersonFn: func(ctx context.Context, arg repository.ExistsOtherActivePersonByTaxIDParams) (bool, error) {
+		if arg.PersonID != "deleted-person" {
+			t.Fatalf("expected the restored person to be excluded, got %q", arg.PersonID)
+		}
+		return arg.TaxIDNumber == "11222333000181", nil
+	}}
+	err := ensureTaxIDAvailableForRestore(context.Background(), q, repository.Person{ID: "deleted-person", TaxIDNumber: "11222333000181"})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict, got %v", err)
+	}
+	if err := ensureTaxIDAvailableForRestore(context.Background(), q, repository.Person{ID: "deleted-person", TaxIDNumber: "52998224725"}); err != nil {
+		t.Fatalf("expected free tax id to pass, got %v", err)
+	}
+}
+
+func TestListDeletedResourcesValidatesType(t *testing.T) {
+	svc := &Service{}
+	resourceType := "SPECIALTY"
+	if _, _, err := svc.ListDeletedResources(context.Background(), 10, nil, &resourceType); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown resource type to be rejected, got %v", err)
+	}
+}
+
+func TestGetRetentionPurgeReportRequiresPolicy(t *testing.T) {
+	svc := &Service{now: time.Now}
+	if _, err := svc.GetRetentionPurgeReport(context.Background()); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict without retention days, got %v", err)
+	}
+}
+
+func TestMapRetentionCandidateAnonymizesRetainedRecords(t *testing.T) {
+	deleted := mapRetentionCandidate(repository.ListRetentionCandidatesRow{ResourceType: DeletedResourceClinic, ID: "clinic"})
+	if deleted.Action != RetentionActionDelete || deleted.RetentionReason != nil {
+		t.Fatalf("expected clinic without retained records to be deleted, got %+v", deleted)
+	}
+	retained := mapRetentionCandidate(repository.ListRetentionCandidatesRow{ResourceType: DeletedResourceClinic, ID: "clinic", RetentionReason: "FINANCIAL_RECORDS"})
+	if retained.Action != RetentionActionAnonymize || retained.RetentionReason == nil || *retained.RetentionReason != "FINANCIAL_RECORDS" {
+		t.Fatalf("expected clinic with financial records to be anonymized, got %+v", retained)
+	}
+}
+
+func TestValidateLedgerEntriesRequiresBalancedClinicPosting(t *testing.T) {
+	if err := validateLedgerEntries(clinicLedgerEntries(LedgerKindFee, -500)); err != nil {
+		t.Fatalf("expected generated entries to balance, got %v", err)
+	}
+	unbalanced := []ledgerEntry{
+		{Account: LedgerAccountPlatformCash, Direction: LedgerDebit, AmountCents: 1000},
+		{Account: LedgerAccountClinicBalance, Direction: LedgerCredit, AmountCents: 900},
+	}
+	if err := validateLedgerEntries(unbalanced); err == nil {
+		t.Fatal("expected unbalanced entries to be rejected")
+	}
+	platformOnly := []ledgerEntry{
+		{Account: LedgerAccountPlatformCash, Direction: LedgerDebit, AmountCents: 1000},
+		{Account: LedgerAccountPlatformRevenue, Direction: LedgerCredit, AmountCents: 1000},
+	}
+	if err := validateLedgerEntries(platformOnly); err == nil {
+		t.Fatal("expected entries that skip the clinic balance to be rejected")
+	}
+}
+
+func TestRecordClinicLedgerEntryRejectsOutOfRangeAmountsBeforeDB(t *testing.T) {
+	svc := &Service{}
+	for _, input := range []CreateLedgerEntryInput{
+		{Kind: LedgerKindInvoice, AmountCents: math.MinInt64},
+		{Kind: LedgerKindFee, AmountCents: maxLedgerAmountCents + 1},
+		{Kind: LedgerKindAdjustment, AmountCents: math.MinInt64},
+		{Kind: LedgerKindAdjustment, AmountCents: maxLedgerAmountCents + 1},
+	} {
+		input.Description = "ajuste"
+		input.OccurredOn = "2026-03-10"
+		if _, err := svc.RecordClinicLedgerEntry(context.Background(), "clinic", input); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected ErrValidation for %s %d, got %v", input.Kind, input.AmountCents, err)
+		}
+	}
+}
+
+func TestBuildLedgerRunsBalanceFromOpening(t *testing.T) {
+	day := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	transactions := []repository.LedgerTransaction{
+		{ID: "payment", Kind: LedgerKindPayment, OccurredOn: day},
+		{ID: "fee", Kind: LedgerKindFee, OccurredOn: day},
+	}
+	toRows := func(id string, entries []ledgerEntry) []repository.LedgerEntry {
+		rows := make([]repository.LedgerEntry, 0, len(entries))
+		for idx, entry := range entries {
+			rows = append(rows, repository.LedgerEntry{TransactionID: id, Line: int16(idx + 1), Account: entry.Account, Direction: entry.Direction, AmountCents: entry.AmountCents})
+		}
+		return rows
+	}
+	entries := map[string][]repository.LedgerEntry{
+		"payment": toRows("payment", clinicLedgerEntries(LedgerKindPayment, 10000)),
+		"fee":     toRows("fee", clinicLedgerEntries(LedgerKindFee, -250)),
+	}
+
+	ledger, err := buildLedger(transactions, entries, 1000)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ledger.Transactions[0].BalanceCents != 11000 || ledger.Transactions[1].AmountCents != -250 || ledger.ClosingBalanceCents != 10750 {
+		t.Fatalf("unexpected running balance: %+v", ledger)
+	}
+
+	entries["fee"] = entries["fee"][:1]
+	if _, err := buildLedger(transactions, entries, 1000); err == nil {
+		t.Fatal("expected an unbalanced stored transaction to fail the statement")
+	}
+}
+
+func TestLedgerExportWritesBalancedJournalAndSPEDRecords(t *testing.T) {
+	toOutputs := func(entries []ledgerEntry) []LedgerEntryOutput {
+		outputs := make([]LedgerEntryOutput, 0, len(entries))
+		for _, entry := range entries {
+			outputs = append(outputs, LedgerEntryOutput{Account: entry.Account, Direction: entry.Direction, AmountCents: entry.AmountCents})
+		}
+		return outputs
+	}
+	ledger := LedgerOutput{
+		ClinicID: "clinic-1",
+		From:     "2026-03-01",
+		To:       "2026-03-31",
+		Transactions: []LedgerTransactionOutput{
+			{ID: "payment", Kind: LedgerKindPayment, Description: "Consulta | março", OccurredOn: "2026-03-10", AmountCents: 150075, Entries: toOutputs(clinicLedgerEntries(LedgerKindPayment, 150075))},
+			{ID: "fee", Kind: LedgerKindFee, Description: "Taxa", OccurredOn: "2026-03-11", AmountCents: -250, Entries: toOutputs(clinicLedgerEntries(LedgerKindFee, -250))},
+		},
+	}
+
+	records := ledgerJournalRecords(ledger)
+	if len(records) != 4 {
+		t.Fatalf("expected one row per entry, got %d", len(records))
+	}
+	if records[0][6] != LedgerAccountPlatformCash || records[0][7] != "1500.75" || records[1][6] != LedgerAccountClinicBalance || records[1][8] != "1500.75" {
+		t.Fatalf("unexpected payment rows: %v", records[:2])
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(ledgerSPEDRecords(ledger)), "\r\n"), "\r\n")
+	if len(lines) != 6 {
+		t.Fatalf("expected an I200 and two I250 per transaction, got %d lines", len(lines))
+	}
+	if lines[0] != "|I200|payment|10032026|1500,75|N|" {
+		t.Fatalf("unexpected I200 record %s", lines[0])
+	}
+	if !strings.HasPrefix(lines[1], "|I250|PLATFORM_CASH||1500,75|D|") || !strings.Contains(lines[1], "|PAYMENT - Consulta março|") {
+		t.Fatalf("unexpected I250 record %s", lines[1])
+	}
+	if _, err := ledgerOFXStatement(ledger, time.Now()); err != nil {
+		t.Fatalf("ledgerOFXStatement: %v", err)
+	}
+}
+
+func TestSelectPayoutAccountSkipsUnpayableClinics(t *testing.T) {
+	verified := repository.BankAccount{ID: "primary", IsPrimary: true, VerificationStatus: BankVerificationVerified}
+	if _, reason := selectPayoutAccount([]repository.BankAccount{{ID: "secondary", VerificationStatus: BankVerificationVerified}}); reason == "" {
+		t.Fatal("expected clinic without primary account to be skipped")
+	}
+	pendingHolder := verified
+	pendingHolder.HolderReviewRequired = true
+	if _, reason := selectPayoutAccount([]repository.BankAccount{pendingHolder}); reason == "" {
+		t.Fatal("expected account with holder pending review to be skipped")
+	}
+	account, reason := selectPayoutAccount([]repository.BankAccount{verified})
+	if reason != "" || account.ID != "primary" {
+		t.Fatalf("expected verified primary account, got %q (%s)", account.ID, reason)
+	}
+}
+
+func TestEnsurePayoutBatchTransition(t *testing.T) {
+	if err := ensurePayoutBatchTransition(PayoutBatchGenerated, PayoutBatchSubmitted); err != nil {
+		t.Fatalf("expected GENERATED -> SUBMITTED to be allowed, got %v", err)
+	}
+	if err := ensurePayoutBatchTransition(PayoutBatchSettled, PayoutBatchFailed); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected settled batch to be final, got %v", err)
+	}
+}
+
+func TestPayoutAccountRequiresVerifiedPrimary(t *testing.T) {
+	accounts := []BankAccountOutput{
+		{ID: "primary", IsPrimary: true, VerificationStatus: BankVerificationPending},
+		{ID: "secondary", VerificationStatus: BankVerificationVerified},
+	}
+	if _, err := payoutAccount(accounts); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected ErrConflict for an unverified primary account, got: %v", err)
+	}
+
+	accounts[0].VerificationStatus = BankVerificationVerified
+	account, err := payoutAccount(accounts)
+	if err != nil {
+		t.Fatalf("payoutAccount: %v", err)
+	}
+	if account.ID != "primary" {
+		t.Fatalf("expected primary account, got %q", account.ID)
+	}
+}
+
+func TestValidateMaxLengthCountsUnicodeCharacters(t *testing.T) {
+	if err := validateMaxLength("legal_name", strings.Repeat("á", 255), 255); err != nil {
+		t.Fatalf("expected multibyte input within character limit to pass, got: %v", err)
+	}
+
+	err := validateMaxLength("legal_name", strings.Repeat("á", 256), 255)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for input over character limit, got: %v", err)
+	}
+}
+
+func TestValidateBankAccountInputCountsUnicodeCharacters(t *testing.T) {
+	valid := BankAccountInput{
+		BankCode:      strings.Repeat("ç", maxBankFieldLength),
+		BranchNumber:  "1234",
+		AccountNumber: "998877",
+	}
+	if err := validateBankAccountInput(valid); err != nil {
+		t.Fatalf("expected multibyte bank code within character limit to pass, got: %v", err)
+	}
+
+	invalid := BankAccountInput{
+		BankCode:      strings.Repeat("ç", maxBankFieldLength+1),
+		BranchNumber:  "1234",
+		AccountNumber: "998877",
+	}
+	if err := validateBankAccountInput(invalid); err == nil {
+		t.Fatalf("expected validation error for bank code over character limit")
+	}
+}
+
+func TestCreateClinicRejectsOversizedUnicodeBeforeDB(t *testing.T) {
+	svc := &Service{}
+
+	_, err := svc.CreateClinic(context.Background(), CreateClinicInput{
+		TaxIDNumber: "43542338000150",
+		LegalName:   strings.Repeat("á", maxLegalNameLength+1),
+		BankAccounts: []BankAccountInput{{
+			BankCode:      "001",
+			BranchNumber:  "1234",
+			AccountNumber: "998877",
+		}},
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for oversized legal_name, got: %v", err)
+	}
+}
+
+func TestUpdateClinicRejectsOversizedUnicodeBeforeDB(t *testing.T) {
+	svc := &Service{}
+	tooLong := strings.Repeat("ç", maxTradeNameLength+1)
+
+	_, err := svc.UpdateClinic(context.Background(), "019f3329-a5a8-72ec-a95b-6e554247f442", UpdateClinicInput{
+		TradeName: &tooLong,
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for oversized trade_name, got: %v", err)
+	}
+}
+
+func TestCreateOrAttachDentistRequiresCROStateWithNumber(t *testing.T) {
+	svc := &Service{}
+	croNumber := "12345"
+
+	_, _, err := svc.CreateOrAttachDentist(context.Background(), "019f3329-a5a8-72ec-a95b-6e554247f442", CreateDentistInput{
+		TaxIDNumber: "52998224725",
+		LegalName:   "Dr. Jane Doe",
+		CRONumber:   &croNumber,
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for cro_number without cro_state, got: %v", err)
+	}
+}
+
+func TestNormalizeCROInput(t *testing.T) {
+	number := "CRO 012345"
+	state := "sp"
+
+	gotNumber, gotState, err := normalizeCROInput(&number, &state, &validationCollector{})
+	if err != nil {
+		t.Fatalf("normalize cro input: %v", err)
+	}
+	if gotNumber.String != "12345" || gotState.String != "SP" {
+		t.Fatalf("unexpected normalized CRO: %q/%q", gotNumber.String, gotState.String)
+	}
+
+	invalidState := "XX"
+	if _, _, err := normalizeCROInput(&number, &invalidState, &validationCollector{}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for invalid cro_state, got: %v", err)
+	}
+}
+
+func TestResolveAddressInputPrefillsFromLookup(t *testing.T) {
+	lookup := &stubAddressLookup{
+		result: AddressLookupResult{Street: "Praça da Sé", City: "São Paulo", State: "sp"},
+		found:  true,
+	}
+	svc := &Service{addressLookup: lookup}
+	number := "100"
+
+	address, err := svc.resolveAddressInput(context.Background(), &AddressInput{CEP: "01001-000", Number: &number}, &validationCollector{})
+	if err != nil {
+		t.Fatalf("resolve address: %v", err)
+	}
+	if lookup.calls != 1 {
+		t.Fatalf("expected one lookup call, got %d", lookup.calls)
+	}
+	if address.Street != "Praça da Sé" || address.City != "São Paulo" || address.State != "SP" || address.Cep != "01001000" {
+		t.Fatalf("unexpected resolved address: %+v", address)
+	}
+}
+
+func TestResolveAddressInputRequiresCityWithoutLookup(t *testing.T) {
+	svc := &Service{}
+	street := "Rua A"
+
+	_, err := svc.resolveAddressInput(context.Background(), &AddressInput{CEP: "01001000", Street: &street}, &validationCollector{})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation without city, got: %v", err)
+	}
+}
+
+func TestResolveAddressInputRejectsUnknownCEP(t *testing.T) {
+	svc := &Service{addressLookup: &stubAddressLookup{found: false}}
+
+	_, err := svc.resolveAddressInput(context.Background(), &AddressInput{CEP: "99999999"}, &validationCollector{})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for unknown CEP, got: %v", err)
+	}
+}
+
+func TestParseValidationPolicy(t *testing.T) {
+	policy, err := ParseValidationPolicy(map[string]string{"tax_id": "warn", "Phone": "OFF"})
+	if err != nil {
+		t.Fatalf("parse validation policy: %v", err)
+	}
+	if policy.mode(ValidationRuleTaxID) != ValidationModeWarn {
+		t.Fatalf("expected tax_id in warn mode, got %q", policy.mode(ValidationRuleTaxID))
+	}
+	if policy.mode(ValidationRulePhone) != ValidationModeOff {
+		t.Fatalf("expected phone in off mode, got %q", policy.mode(ValidationRulePhone))
+	}
+	if policy.mode(ValidationRuleEmail) != ValidationModeEnforce {
+		t.Fatalf("expected unspecified rules to be enforced, got %q", policy.mode(ValidationRuleEmail))
+	}
+
+	if _, err := ParseValidationPolicy(map[string]string{"unknown": "warn"}); err == nil {
+		t.Fatalf("expected error for unknown rule")
+	}
+	if _, err := ParseValidationPolicy(map[string]string{"email": "sometimes"}); err == nil {
+		t.Fatalf("expected error for invalid mode")
+	}
+}
+
+func TestValidationCollectorWarnModeRecordsWarnings(t *testing.T) {
+	validations := &validationCollector{policy: ValidationPolicy{ValidationRulePhone: ValidationModeWarn}}
+
+	phone, err := normalizePhoneInput(new("ramal 22"), validations)
+	if err != nil {
+		t.Fatalf("expected warn mode to accept invalid phone, got: %v", err)
+	}
+	if *phone != "ramal 22" {
+		t.Fatalf("expected raw phone to be kept, got %q", *phone)
+	}
+	if warnings := validations.result(); len(warnings) != 1 || warnings[0] != "invalid phone" {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+}
+
+type stubTaxIDScreener struct {
+	result TaxIDScreeningResult
+	err    error
+	calls  int
+}
+
+func (s *stubTaxIDScreener) ScreenTaxID(context.Context, string, string) (TaxIDScreeningResult, error) {
+	s.calls++
+	return s.result, s.err
+}
+
+func TestScreenTaxIDRejectsBlocklistedDocument(t *testing.T) {
+	svc := &Service{}
+	WithTaxIDBlocklist([]string{"11.222.333/0001-81", " 529.982.247-25 "})(svc)
+
+	if err := svc.screenTaxID(context.Background(), taxIDTypeCNPJ, "11222333000181"); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected ErrBlocked for blocklisted CNPJ, got: %v", err)
+	}
+	if err := svc.screenTaxID(context.Background(), taxIDTypeCPF, "52998224725"); !errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected ErrBlocked for blocklisted CPF, got: %v", err)
+	}
+	if err := svc.screenTaxID(context.Background(), taxIDTypeCPF, "11144477735"); err != nil {
+		t.Fatalf("expected non-blocklisted CPF to pass, got: %v", err)
+	}
+}
+
+func TestScreenTaxIDUsesScreener(t *testing.T) {
+	screener := &stubTaxIDScreener{result: TaxIDScreeningResult{Blocked: true, Reason: "sanctions list"}}
+	svc := &Service{taxIDScreener: screener}
+
+	err := svc.screenTaxID(context.Background(), taxIDTypeCNPJ, "11222333000181")
+	if !errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected ErrBlocked from screener, got: %v", err)
+	}
+	if !strings.Contains(err.Error(), "sanctions list") {
+		t.Fatalf("expected screening reason in error, got: %v", err)
+	}
+
+	screener.result = TaxIDScreeningResult{}
+	screener.err = errors.New("timeout")
+	err = svc.screenTaxID(context.Background(), taxIDTypeCNPJ, "11222333000181")
+	if err == nil || errors.Is(err, ErrBlocked) {
+		t.Fatalf("expected screener failure to surface as internal error, got: %v", err)
+	}
+	if screener.calls != 2 {
+		t.Fatalf("expected two screener calls, got %d", screener.calls)
+	}
+}
+
+type stubCompanyRegistry struct {
+	result CompanyRegistryResult
+	found  bool
+	err    error
+}
+
+func (s stubCompanyRegistry) LookupCNPJ(context.Context, string) (CompanyRegistryResult, bool, error) {
+	return s.result, s.found, s.err
+}
+
+func TestEnrichClinicWarnsOnDivergentLegalName(t *testing.T) {
+	svc := &Service{companyRegistry: stubCompanyRegistry{
+		result: CompanyRegistryResult{
+			LegalName:          "CLINICA DENTE FELIZ LTDA",
+			CNAECode:           "8630-5/04",
+			RegistrationStatus: "baixada",
+			Source:             "stub",
+		},
+		found: true,
+	}}
+	validations := &validationCollector{}
+
+	record := svc.enrichClinic(context.Background(), "11222333000181", "Clínica Sorriso Ltda", validations)
+	if record == nil {
+		t.Fatalf("expected registry record to be returned")
+	}
+	if record.LegalName != "CLINICA DENTE FELIZ LTDA" || record.RegistrationStatus != "BAIXADA" || record.CnaeCode.String != "8630-5/04" {
+		t.Fatalf("unexpected registry record: %+v", record)
+	}
+	warnings := validations.result()
+	if len(warnings) != 2 || !strings.Contains(warnings[0], "legal_name differs") || !strings.Contains(warnings[1], "BAIXADA") {
+		t.Fatalf("unexpected warnings: %v", warnings)
+	}
+}
+
+func TestEnrichClinicIgnoresRegistryFailure(t *testing.T) {
+	svc := &Service{companyRegistry: stubCompanyRegistry{err: errors.New("timeout")}}
+	validations := &validationCollector{}
+
+	if record := svc.enrichClinic(context.Background(), "11222333000181", "Clinica Sorriso", validations); record != nil {
+		t.Fatalf("expected no registry record on lookup failure, got %+v", record)
+	}
+	if warnings := validations.result(); warnings != nil {
+		t.Fatalf("expected no warnings on lookup failure, got %v", warnings)
+	}
+}
+
+func TestNormalizeSpecialtyIDsDeduplicatesAndRejectsInvalid(t *testing.T) {
+	first, err := newUUIDV7()
+	if err != nil {
+		t.Fatalf("new uuidv7: %v", err)
+	}
+	second, err := newUUIDV7()
+	if err != nil {
+		t.Fatalf("new uuidv7: %v", err)
+	}
+
+	ids, err := normalizeSpecialtyIDs([]string{first, " " + second + " ", first})
+	if err != nil {
+		t.Fatalf("normalize specialty ids: %v", err)
+	}
+	if len(ids) != 2 || ids[0] != first || ids[1] != second {
+		t.Fatalf("unexpected specialty ids: %v", ids)
+	}
+
+	if _, err := normalizeSpecialtyIDs([]string{uuid.NewString()}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for non-UUIDv7 specialty id, got: %v", err)
+	}
+}
+
+func TestMapEmploymentPeriodComputesDuration(t *testing.T) {
+	startedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2025, 1, 31, 12, 0, 0, 0, time.UTC)
+
+	active := mapEmploymentPeriod("clinic", "dentist", true, false, startedAt, sql.NullTime{}, now)
+	if !active.Active || active.EndedAt != nil || active.DurationDays != 30 {
+		t.Fatalf("unexpected active period: %+v", active)
+	}
+
+	endedAt := time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)
+	ended := mapEmploymentPeriod("clinic", "dentist", false, true, startedAt, sql.NullTime{Time: endedAt, Valid: true}, now)
+	if ended.Active || ended.EndedAt == nil || !ended.EndedAt.Equal(endedAt) || ended.DurationDays != 10 {
+		t.Fatalf("unexpected ended period: %+v", ended)
+	}
+}
+
+func TestSummarizeTenureSumsAllPeriods(t *testing.T) {
+	now := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	firstStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	firstEnd := time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)
+	secondStart := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	periods := []EmploymentPeriodOutput{
+		mapEmploymentPeriod("clinic", "dentist", false, false, secondStart, sql.NullTime{}, now),
+		mapEmploymentPeriod("clinic", "dentist", false, false, firstStart, sql.NullTime{Time: firstEnd, Valid: true}, now),
+	}
+
+	tenure := summarizeTenure("clinic", "dentist", periods, now)
+	if tenure.Periods != 2 || tenure.TotalDays != 48 || !tenure.Active {
+		t.Fatalf("unexpected tenure: %+v", tenure)
+	}
+	if tenure.FirstStartedAt == nil || !tenure.FirstStartedAt.Equal(firstStart) {
+		t.Fatalf("expected first_started_at %s, got %v", firstStart, tenure.FirstStartedAt)
+	}
+}
+
+func TestResizeDentistPhotoProducesSquareJPEG(t *testing.T) {
+	source := image.NewRGBA(image.Rect(0, 0, 800, 600))
+	var encoded bytes.Buffer
+	if err := png.Encode(&encoded, source); err != nil {
+		t.Fatalf("encode source png: %v", err)
+	}
+
+	resized, err := resizeDentistPhoto(encoded.Bytes())
+	if err != nil {
+		t.Fatalf("resize photo: %v", err)
+	}
+	config, format, err := image.DecodeConfig(bytes.NewReader(resized))
+	if err != nil {
+		t.Fatalf("decode resized photo: %v", err)
+	}
+	if format != "jpeg" || config.Width != dentistPhotoSize || config.Height != dentistPhotoSize {
+		t.Fatalf("unexpected resized photo: format=%s %dx%d", format, config.Width, config.Height)
+	}
+
+	if _, err := resizeDentistPhoto([]byte("not an image")); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for undecodable photo, got: %v", err)
+	}
+}
+
+func TestCheckClinicRoleInvariant(t *testing.T) {
+	tests := []struct {
+		name    string
+		before  repository.CountClinicRoleHoldersRow
+		after   repository.CountClinicRoleHoldersRow
+		wantErr bool
+	}{
+		{name: "keeps role holders", before: repository.CountClinicRoleHoldersRow{ActiveDentists: 2, Admins: 2, LegalRepresentatives: 1}, after: repository.CountClinicRoleHoldersRow{ActiveDentists: 2, Admins: 1, LegalRepresentatives: 1}},
+		{name: "removes last admin", before: repository.CountClinicRoleHoldersRow{ActiveDentists: 1, Admins: 1, LegalRepresentatives: 1}, after: repository.CountClinicRoleHoldersRow{ActiveDentists: 1, LegalRepresentatives: 1}, wantErr: true},
+		{name: "removes last legal representative", before: repository.CountClinicRoleHoldersRow{ActiveDentists: 2, Admins: 1, LegalRepresentatives: 1}, after: repository.CountClinicRoleHoldersRow{ActiveDentists: 1, Admins: 1}, wantErr: true},
+		{name: "clinic without role holders yet", before: repository.CountClinicRoleHoldersRow{ActiveDentists: 1}, after: repository.CountClinicRoleHoldersRow{ActiveDentists: 2}},
+		{name: "last dentist leaves the clinic", before: repository.CountClinicRoleHoldersRow{ActiveDentists: 1, Admins: 1, LegalRepresentatives: 1}, after: repository.CountClinicRoleHoldersRow{}},
+	}
+
+	for _, tc := range tests {
+		err := checkClinicRoleInvariant(tc.before, tc.after)
+		if tc.wantErr && !errors.Is(err, ErrRoleInvariant) {
+			t.Fatalf("%s: expected ErrRoleInvariant, got: %v", tc.name, err)
+		}
+		if !tc.wantErr && err != nil {
+			t.Fatalf("%s: expected no error, got: %v", tc.name, err)
+		}
+	}
+}
+
+func TestDocumentNoticeThreshold(t *testing.T) {
+	noticeDays := normalizeNoticeDays([]int{7, 30, 7, -1})
+	if len(noticeDays) != 3 || noticeDays[0] != 30 || noticeDays[1] != 7 || noticeDays[2] != 0 {
+		t.Fatalf("unexpected normalized notice days: %v", noticeDays)
+	}
+
+	cases := []struct {
+		daysUntilExpiry int
+		threshold       int
+		due             bool
+	}{
+		{daysUntilExpiry: 45, due: false},
+		{daysUntilExpiry: 30, threshold: 30, due: true},
+		{daysUntilExpiry: 12, threshold: 30, due: true},
+		{daysUntilExpiry: 7, threshold: 7, due: true},
+		{daysUntilExpiry: 0, threshold: 0, due: true},
+		{daysUntilExpiry: -3, threshold: 0, due: true},
+	}
+	for _, tc := range cases {
+		threshold, due := documentNoticeThreshold(tc.daysUntilExpiry, noticeDays)
+		if due != tc.due || threshold != tc.threshold {
+			t.Fatalf("days %d: expected (%d, %v), got (%d, %v)", tc.daysUntilExpiry, tc.threshold, tc.due, threshold, due)
+		}
+	}
+}
+
+func TestMapDentistDocumentComputesStatus(t *testing.T) {
+	today := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	document := repository.DentistDocument{
+		ID:           "document",
+		DentistID:    "dentist",
+		DocumentType: DocumentTypeLiabilityInsurance,
+		ExpiresAt:    time.Date(2025, 6, 11, 0, 0, 0, 0, time.UTC),
+	}
+
+	output := mapDentistDocument(document, today)
+	if output.DaysUntilExpiry != 10 || output.Status != DocumentStatusExpiring || output.ExpiresAt != "2025-06-11" {
+		t.Fatalf("unexpected expiring document: %+v", output)
+	}
+
+	document.ExpiresAt = time.Date(2025, 5, 31, 0, 0, 0, 0, time.UTC)
+	if output := mapDentistDocument(document, today); output.Status != DocumentStatusExpired || output.DaysUntilExpiry != -1 {
+		t.Fatalf("unexpected expired document: %+v", output)
+	}
+}
+
+func TestResolveAssignmentInput(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.AddDate(0, 3, 0)
+	coveredID, err := newUUIDV7()
+	if err != nil {
+		t.Fatalf("new uuidv7: %v", err)
+	}
+
+	if _, err := resolveAssignmentInput(&past, nil, now); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected past planned_end_at to be rejected, got %v", err)
+	}
+	if _, err := resolveAssignmentInput(nil, &coveredID, now); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected substitute without planned_end_at to be rejected, got %v", err)
+	}
+
+	assignment, err := resolveAssignmentInput(&future, &coveredID, now)
+	if err != nil {
+		t.Fatalf("resolve assignment: %v", err)
+	}
+	if !assignment.plannedEndAt.Valid || !assignment.plannedEndAt.Time.Equal(future) {
+		t.Fatalf("unexpected planned end: %+v", assignment.plannedEndAt)
+	}
+	if !assignment.substituteFor.Valid || assignment.substituteFor.UUID.String() != coveredID {
+		t.Fatalf("unexpected substitute: %+v", assignment.substituteFor)
+	}
+
+	permanent, err := resolveAssignmentInput(nil, nil, now)
+	if err != nil || permanent.plannedEndAt.Valid || permanent.substituteFor.Valid {
+		t.Fatalf("expected permanent assignment, got %+v (%v)", permanent, err)
+	}
+}
+
+func TestReassignDentistPersonRejectsInvalidCPFBeforeDB(t *testing.T) {
+	svc := &Service{}
+	_, err := svc.ReassignDentistPerson(context.Background(), "dentist", ReassignDentistPersonInput{TaxIDNumber: "123.456.789-00"})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation, got %v", err)
+	}
+}
+
+// mergeQuerier keeps the clinic links of the dentists being merged in memory and records the other statements by name.
+type mergeQuerier struct {
+	repository.Querier
+	source           repository.GetDentistDetailsByIDRow
+	people           map[string]repository.Person
+	dentistsByPerson map[string]repository.Dentist
+	links            []repository.ClinicDentist
+	deleted          map[string]bool
+	shiftOverlap     *repository.GetMergedDentistShiftOverlapRow
+	openPunches      map[string]string
+	// dropRoleUpdates makes role writes lose the roles, standing in for a merge step that goes wrong.
+	dropRoleUpdates bool
+	calls           []string
+}
+
+func (q *mergeQuerier) GetDentistDetailsByID(ctx context.Context, arg repository.GetDentistDetailsByIDParams) (repository.GetDentistDetailsByIDRow, error) {
+	if arg.ID != q.source.DentistID {
+		return repository.GetDentistDetailsByIDRow{}, sql.ErrNoRows
+	}
+	return q.source, nil
+}
+
+func (q *mergeQuerier) GetPersonByTaxID(ctx context.Context, arg repository.GetPersonByTaxIDParams) (repository.Person, error) {
+	person, ok := q.people[arg.TaxIDNumber]
+	if !ok {
+		return repository.Person{}, sql.ErrNoRows
+	}
+	return person, nil
+}
+
+func (q *mergeQuerier) CreatePerson(ctx context.Context, arg repository.CreatePersonParams) (repository.Person, error) {
+	q.record("CreatePerson " + arg.TaxIDNumber)
+	return repository.Person{ID: arg.ID, PersonType: arg.PersonType, TaxIDNumber: arg.TaxIDNumber, LegalName: arg.LegalName}, nil
+}
+
+func (q *mergeQuerier) GetDentistByPersonID(ctx context.Context, arg repository.GetDentistByPersonIDParams) (repository.Dentist, error) {
+	dentist, ok := q.dentistsByPerson[arg.PersonID]
+	if !ok {
+		return repository.Dentist{}, sql.ErrNoRows
+	}
+	return dentist, nil
+}
+
+func (q *mergeQuerier) UpdateDentistPerson(ctx context.Context, arg repository.UpdateDentistPersonParams) (repository.Dentist, error) {
+	q.record("UpdateDentistPerson " + arg.ID + " " + arg.PersonID)
+	return repository.Dentist{ID: arg.ID, PersonID: arg.PersonID}, nil
+}
+
+func (q *mergeQuerier) CopyAddress(ctx context.Context, arg repository.CopyAddressParams) (int64, error) {
+	return q.record("CopyAddress " + arg.PersonID + " " + arg.TargetPersonID)
+}
+
+func (q *mergeQuerier) DeletePerson(ctx context.Context, arg repository.DeletePersonParams) (int64, error) {
+	return q.record("DeletePerson " + arg.ID)
+}
+
+func (q *mergeQuerier) record(call string) (int64, error) {
+	q.calls = append(q.calls, call)
+	return 1, nil
+}
+
+func (q *mergeQuerier) called(call string) bool {
+	return slices.Contains(q.calls, call)
+}
+
+func (q *mergeQuerier) ListClinicDentistRowsByDentist(ctx context.Context, arg repository.ListClinicDentistRowsByDentistParams) ([]repository.ClinicDentist, error) {
+	var links []repository.ClinicDentist
+	for _, link := range q.links {
+		if link.DentistID == arg.DentistID {
+			links = append(links, link)
+		}
+	}
+	return links, nil
+}
+
+func (q *mergeQuerier) LockClinicForUpdate(ctx context.Context, arg repository.LockClinicForUpdateParams) (string, error) {
+	return arg.ID, nil
+}
+
+func (q *mergeQuerier) CountClinicRoleHolders(ctx context.Context, arg repository.CountClinicRoleHoldersParams) (repository.CountClinicRoleHoldersRow, error) {
+	var counts repository.CountClinicRoleHoldersRow
+	for _, link := range q.links {
+		if link.ClinicID != arg.ClinicID || link.EndedAt.Valid || q.deleted[link.DentistID] {
+			continue
+		}
+		counts.ActiveDentists++
+		if link.IsAdmin {
+			counts.Admins++
+		}
+		if link.IsLegalRepresentative {
+			counts.LegalRepresentatives++
+		}
+	}
+	return counts, nil
+}
+
+func (q *mergeQuerier) UpdateClinicDentistRole(ctx context.Context, arg repository.UpdateClinicDentistRoleParams) (repository.ClinicDentist, error) {
+	for i, link := range q.links {
+		if link.ClinicID == arg.ClinicID && link.DentistID == arg.DentistID && !link.EndedAt.Valid {
+			q.links[i].IsAdmin = arg.IsAdmin.Bool && !q.dropRoleUpdates
+			q.links[i].IsLegalRepresentative = arg.IsLegalRepresentative.Bool && !q.dropRoleUpdates
+			return q.links[i], nil
+		}
+	}
+	return repository.ClinicDentist{}, sql.ErrNoRows
+}
+
+func (q *mergeQuerier) EndClinicDentist(ctx context.Context, arg repository.EndClinicDentistParams) (int64, error) {
+	for i, link := range q.links {
+		if link.ClinicID == arg.ClinicID && link.DentistID == arg.DentistID && !link.EndedAt.Valid {
+			q.links[i].EndedAt = sql.NullTime{Time: time.Now(), Valid: true}
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (q *mergeQuerier) ReassignClinicDentistRow(ctx context.Context, arg repository.ReassignClinicDentistRowParams) (int64, error) {
+	for i, link := range q.links {
+		if link.ClinicID == arg.ClinicID && link.DentistID == arg.DentistID && link.StartedAt.Equal(arg.StartedAt) {
+			q.links[i].DentistID = arg.TargetDentistID
+			return 1, nil
+		}
+	}
+	return 0, nil
+}
+
+func (q *mergeQuerier) ReassignSubstituteFor(ctx context.Context, arg repository.ReassignSubstituteForParams) (int64, error) {
+	return q.record("ReassignSubstituteFor")
+}
+
+func (q *mergeQuerier) CopyDentistSpecialties(ctx context.Context, arg repository.CopyDentistSpecialtiesParams) (int64, error) {
+	return q.record("CopyDentistSpecialties")
+}
+
+func (q *mergeQuerier) DeleteDentistSpecialtiesByDentist(ctx context.Context, arg repository.DeleteDentistSpecialtiesByDentistParams) (int64, error) {
+	return q.record("DeleteDentistSpecialtiesByDentist")
+}
+
+func (q *mergeQuerier) MoveDentistDocuments(ctx context.Context, arg repository.MoveDentistDocumentsParams) (int64, error) {
+	return q.record("MoveDentistDocuments")
+}
+
+func (q *mergeQuerier) DeleteDentistDocumentsByDentist(ctx context.Context, arg repository.DeleteDentistDocumentsByDentistParams) (int64, error) {
+	return q.record("DeleteDentistDocumentsByDentist")
+}
+
+func (q *mergeQuerier) MoveDentistUser(ctx context.Context, arg repository.MoveDentistUserParams) (int64, error) {
+	return q.record("MoveDentistUser")
+}
+
+func (q *mergeQuerier) DeleteUsersByDentistID(ctx context.Context, arg repository.DeleteUsersByDentistIDParams) (int64, error) {
+	return q.record("DeleteUsersByDentistID")
+}
+
+func (q *mergeQuerier) MoveDentistNoteMentions(ctx context.Context, arg repository.MoveDentistNoteMentionsParams) (int64, error) {
+	return q.record("MoveDentistNoteMentions " + arg.DentistID + " " + arg.TargetDentistID)
+}
+
+func (q *mergeQuerier) DeleteDentistNoteMentions(ctx context.Context, arg repository.DeleteDentistNoteMentionsParams) (int64, error) {
+	return q.record("DeleteDentistNoteMentions " + arg.DentistID)
+}
+
+func (q *mergeQuerier) MoveDentistClinicTasks(ctx context.Context, arg repository.MoveDentistClinicTasksParams) (int64, error) {
+	return q.record("MoveDentistClinicTasks " + arg.DentistID + " " + arg.TargetDentistID)
+}
+
+func (q *mergeQuerier) CopyDentistAnnouncementRecipients(ctx context.Context, arg repository.CopyDentistAnnouncementRecipientsParams) (int64, error) {
+	return q.record("CopyDentistAnnouncementRecipients " + arg.DentistID + " " + arg.TargetDentistID)
+}
+
+func (q *mergeQuerier) DeleteDentistAnnouncementRecipients(ctx context.Context, arg repository.DeleteDentistAnnouncementRecipientsParams) (int64, error) {
+	return q.record("DeleteDentistAnnouncementRecipients " + arg.DentistID)
+}
+
+func (q *mergeQuerier) MoveDentistGeneratedDocuments(ctx context.Context, arg repository.MoveDentistGeneratedDocumentsParams) (int64, error) {
+	return q.record("MoveDentistGeneratedDocuments " + arg.DentistID + " " + arg.TargetDentistID)
+}
+
+func (q *mergeQuerier) MoveDentistNotificationPreferences(ctx context.Context, arg repository.MoveDentistNotificationPreferencesParams) (int64, error) {
+	return q.record("MoveDentistNotificationPreferences " + arg.DentistID + " " + arg.TargetDentistID)
+}
+
+func (q *mergeQuerier) DeleteDentistNotificationPreferences(ctx context.Context, arg repository.DeleteDentistNotificationPreferencesParams) (int64, error) {
+	return q.record("DeleteDentistNotificationPreferences " + arg.DentistID)
+}
+
+func (q *mergeQuerier) LockDentistForShift(ctx context.Context, arg repository.LockDentistForShiftParams) (string, error) {
+	q.record("LockDentistForShift " + arg.ID)
+	return arg.ID, nil
+}
+
+func (q *mergeQuerier) GetMergedDentistShiftOverlap(ctx context.Context, arg repository.GetMergedDentistShiftOverlapParams) (repository.GetMergedDentistShiftOverlapRow, error) {
+	if q.shiftOverlap == nil {
+		return repository.GetMergedDentistShiftOverlapRow{}, sql.ErrNoRows
+	}
+	return *q.shiftOverlap, nil
+}
+
+func (q *mergeQuerier) MoveDentistShifts(ctx context.Context, arg repository.MoveDentistShiftsParams) (int64, error) {
+	return q.record("MoveDentistShifts " + arg.DentistID + " " + arg.TargetDentistID)
+}
+
+func (q *mergeQuerier) GetOpenTimeClockEntry(ctx context.Context, arg repository.GetOpenTimeClockEntryParams) (repository.TimeClockEntry, error) {
+	if clinicID, ok := q.openPunches[arg.DentistID]; ok {
+		return repository.TimeClockEntry{DentistID: arg.DentistID, ClinicID: clinicID}, nil
+	}
+	return repository.TimeClockEntry{}, sql.ErrNoRows
+}
+
+func (q *mergeQuerier) MoveDentistTimeClockEntries(ctx context.Context, arg repository.MoveDentistTimeClockEntriesParams) (int64, error) {
+	return q.record("MoveDentistTimeClockEntries " + arg.DentistID + " " + arg.TargetDentistID)
+}
+
+func (q *mergeQuerier) GetDentistByID(ctx context.Context, arg repository.GetDentistByIDParams) (repository.Dentist, error) {
+	return repository.Dentist{ID: arg.ID}, nil
+}
+
+func (q *mergeQuerier) DeleteDentist(ctx context.Context, arg repository.DeleteDentistParams) (int64, error) {
+	if q.deleted == nil {
+		q.deleted = map[string]bool{}
+	}
+	q.deleted[arg.ID] = true
+	return 1, nil
+}
+
+func TestReassignDentistPersonRepointsWhenThePersonHasNoDentist(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	q := &mergeQuerier{
+		source: repository.GetDentistDetailsByIDRow{DentistID: "dentist-b", PersonID: "person-b", TaxIDNumber: "11144477735"},
+		people: map[string]repository.Person{"52998224725": {ID: "person-a", PersonType: personTypeIndividual, TaxIDNumber: "52998224725"}},
+	}
+
+	output, survivorID, err := reassignDentistPerson(ctx, q, "dentist-b", "52998224725")
+	if err != nil {
+		t.Fatalf("reassignDentistPerson: %v", err)
+	}
+	if survivorID != "dentist-b" || output.MergedFromDentistID != nil || output.PreviousPersonID != "person-b" {
+		t.Fatalf("expected the dentist kept and repointed, got %s and %+v", survivorID, output)
+	}
+	for _, call := range []string{"UpdateDentistPerson dentist-b person-a", "CopyAddress person-b person-a", "DeletePerson person-b"} {
+		if !q.called(call) {
+			t.Fatalf("expected %q, got %v", call, q.calls)
+		}
+	}
+	if q.called("CreatePerson 52998224725") || len(q.deleted) != 0 {
+		t.Fatalf("expected the existing person reused and no dentist deleted, got %v", q.calls)
+	}
+
+	q = &mergeQuerier{source: q.source}
+	if _, _, err := reassignDentistPerson(ctx, q, "dentist-b", "52998224725"); err != nil {
+		t.Fatalf("reassignDentistPerson: %v", err)
+	}
+	if !q.called("CreatePerson 52998224725") {
+		t.Fatalf("expected a person created for an unknown CPF, got %v", q.calls)
+	}
+}
+
+func TestReassignDentistPersonMergesSameClinicLinksWithTheUnionOfRoles(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	startedAt := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	q := &mergeQuerier{
+		source:           repository.GetDentistDetailsByIDRow{DentistID: "dentist-b", PersonID: "person-b", TaxIDNumber: "11144477735"},
+		people:           map[string]repository.Person{"52998224725": {ID: "person-a", PersonType: personTypeIndividual}},
+		dentistsByPerson: map[string]repository.Dentist{"person-a": {ID: "dentist-a", PersonID: "person-a"}},
+		links: []repository.ClinicDentist{
+			{ClinicID: "clinic-1", DentistID: "dentist-b", StartedAt: startedAt, IsAdmin: true},
+			{ClinicID: "clinic-1", DentistID: "dentist-a", StartedAt: startedAt.AddDate(0, 1, 0), IsLegalRepresentative: true},
+		},
+	}
+
+	output, survivorID, err := reassignDentistPerson(ctx, q, "dentist-b", "52998224725")
+	if err != nil {
+		t.Fatalf("reassignDentistPerson: %v", err)
+	}
+	if survivorID != "dentist-a" || output.MergedFromDentistID == nil || *output.MergedFromDentistID != "dentist-b" {
+		t.Fatalf("expected dentist-b merged into dentist-a, got %s and %+v", survivorID, output)
+	}
+	var active []repository.ClinicDentist
+	for _, link := range q.links {
+		if link.DentistID != "dentist-a" {
+			t.Fatalf("expected every link moved to the surviving dentist, got %+v", q.links)
+		}
+		if !link.EndedAt.Valid {
+			active = append(active, link)
+		}
+	}
+	if len(active) != 1 || !active[0].IsAdmin || !active[0].IsLegalRepresentative {
+		t.Fatalf("expected one active link holding both roles, got %+v", active)
+	}
+	if !q.deleted["dentist-b"] || !q.called("DeletePerson person-b") {
+		t.Fatalf("expected the merged dentist and its person removed, got %v", q.calls)
+	}
+}
+
+func TestReassignDentistPersonKeepsTheClinicRoleInvariant(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	startedAt := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	q := &mergeQuerier{
+		source:           repository.GetDentistDetailsByIDRow{DentistID: "dentist-b", PersonID: "person-b", TaxIDNumber: "11144477735"},
+		people:           map[string]repository.Person{"52998224725": {ID: "person-a", PersonType: personTypeIndividual}},
+		dentistsByPerson: map[string]repository.Dentist{"person-a": {ID: "dentist-a", PersonID: "person-a"}},
+		links: []repository.ClinicDentist{
+			{ClinicID: "clinic-1", DentistID: "dentist-b", StartedAt: startedAt, IsAdmin: true, IsLegalRepresentative: true},
+			{ClinicID: "clinic-1", DentistID: "dentist-a", StartedAt: startedAt},
+		},
+		dropRoleUpdates: true,
+	}
+
+	_, _, err := reassignDentistPerson(ctx, q, "dentist-b", "52998224725")
+	if !errors.Is(err, ErrRoleInvariant) || !strings.Contains(err.Error(), "at least one active admin") {
+		t.Fatalf("expected the merge refused when the clinic loses its only admin, got %v", err)
+	}
+	if q.called("DeletePerson person-b") {
+		t.Fatalf("expected the person kept after the refused merge, got %v", q.calls)
+	}
+}
+
+func TestMergeDentistsMovesShiftsUnlessTheyOverlap(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	source := repository.GetDentistDetailsByIDRow{DentistID: "dentist-b", PersonID: "person-b"}
+	target := repository.Dentist{ID: "dentist-a"}
+	startedAt := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+
+	q := &mergeQuerier{links: []repository.ClinicDentist{
+		{ClinicID: "clinic-1", DentistID: "dentist-b", StartedAt: startedAt},
+		{ClinicID: "clinic-2", DentistID: "dentist-a", StartedAt: startedAt},
+	}}
+	if err := mergeDentists(ctx, q, source, target); err != nil {
+		t.Fatalf("mergeDentists: %v", err)
+	}
+	locked := slices.Index(q.calls, "LockDentistForShift dentist-a")
+	if locked < 0 || !slices.Equal(q.calls[locked:min(locked+3, len(q.calls))], []string{"LockDentistForShift dentist-a", "LockDentistForShift dentist-b", "MoveDentistShifts dentist-b dentist-a"}) {
+		t.Fatalf("expected both dentists locked before the shifts moved, got %v", q.calls)
+	}
+
+	q = &mergeQuerier{
+		links: []repository.ClinicDentist{{ClinicID: "clinic-1", DentistID: "dentist-b", StartedAt: startedAt}},
+		shiftOverlap: &repository.GetMergedDentistShiftOverlapRow{
+			StartsAt: time.Date(2026, 3, 2, 11, 0, 0, 0, time.UTC),
+			EndsAt:   time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC),
+			ClinicID: "clinic-2",
+		},
+	}
+	err := mergeDentists(ctx, q, source, target)
+	if !errors.Is(err, ErrConflict) || !strings.Contains(err.Error(), "2026-03-02T11:00:00Z") {
+		t.Fatalf("expected overlapping shifts to block the merge, got %v", err)
+	}
+	if q.called("MoveDentistShifts dentist-b dentist-a") || q.deleted["dentist-b"] {
+		t.Fatalf("expected nothing moved after the overlap, got %v", q.calls)
+	}
+}
+
+func TestMergeDentistsMovesTimeClockEntries(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	source := repository.GetDentistDetailsByIDRow{DentistID: "dentist-b", PersonID: "person-b"}
+	target := repository.Dentist{ID: "dentist-a"}
+
+	q := &mergeQuerier{openPunches: map[string]string{"dentist-b": "clinic-1"}}
+	if err := mergeDentists(ctx, q, source, target); err != nil {
+		t.Fatalf("mergeDentists: %v", err)
+	}
+	if !q.called("MoveDentistTimeClockEntries dentist-b dentist-a") {
+		t.Fatalf("expected the punches, open one included, to move to the surviving dentist, got %v", q.calls)
+	}
+
+	q = &mergeQuerier{openPunches: map[string]string{"dentist-a": "clinic-2", "dentist-b": "clinic-1"}}
+	if err := mergeDentists(ctx, q, source, target); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected two open punches to block the merge, got %v", err)
+	}
+	if q.called("MoveDentistTimeClockEntries dentist-b dentist-a") || q.deleted["dentist-b"] {
+		t.Fatalf("expected nothing moved with two open punches, got %v", q.calls)
+	}
+}
+
+func TestMergeDentistsMovesGeneratedDocuments(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	q := &mergeQuerier{}
+	if err := mergeDentists(ctx, q, repository.GetDentistDetailsByIDRow{DentistID: "dentist-b"}, repository.Dentist{ID: "dentist-a"}); err != nil {
+		t.Fatalf("mergeDentists: %v", err)
+	}
+	if !q.called("MoveDentistGeneratedDocuments dentist-b dentist-a") {
+		t.Fatalf("expected the generated documents repointed at the surviving dentist, got %v", q.calls)
+	}
+}
+
+func TestMergeDentistsRepointsNoteMentions(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	q := &mergeQuerier{}
+	if err := mergeDentists(ctx, q, repository.GetDentistDetailsByIDRow{DentistID: "dentist-b"}, repository.Dentist{ID: "dentist-a"}); err != nil {
+		t.Fatalf("mergeDentists: %v", err)
+	}
+	moved := slices.Index(q.calls, "MoveDentistNoteMentions dentist-b dentist-a")
+	if moved < 0 || moved+1 >= len(q.calls) || q.calls[moved+1] != "DeleteDentistNoteMentions dentist-b" {
+		t.Fatalf("expected the mentions repointed before the duplicates are dropped, got %v", q.calls)
+	}
+}
+
+func TestMergeDentistsRepointsClinicTasks(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	q := &mergeQuerier{}
+	if err := mergeDentists(ctx, q, repository.GetDentistDetailsByIDRow{DentistID: "dentist-b"}, repository.Dentist{ID: "dentist-a"}); err != nil {
+		t.Fatalf("mergeDentists: %v", err)
+	}
+	if !q.called("MoveDentistClinicTasks dentist-b dentist-a") {
+		t.Fatalf("expected the tasks about the merged dentist repointed at the surviving one, got %v", q.calls)
+	}
+}
+
+func TestReassignDentistPersonMovesAnnouncementsAndNotificationPreferences(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	q := &mergeQuerier{
+		source:           repository.GetDentistDetailsByIDRow{DentistID: "dentist-b", PersonID: "person-b", TaxIDNumber: "11144477735"},
+		people:           map[string]repository.Person{"52998224725": {ID: "person-a", PersonType: personTypeIndividual}},
+		dentistsByPerson: map[string]repository.Dentist{"person-a": {ID: "dentist-a", PersonID: "person-a"}},
+	}
+
+	if _, _, err := reassignDentistPerson(ctx, q, "dentist-b", "52998224725"); err != nil {
+		t.Fatalf("reassignDentistPerson: %v", err)
+	}
+	calls := []string{
+		"CopyDentistAnnouncementRecipients dentist-b dentist-a",
+		"DeleteDentistAnnouncementRecipients dentist-b",
+		"MoveDentistNotificationPreferences dentist-b dentist-a",
+		"DeleteDentistNotificationPreferences dentist-b",
+	}
+	copied := slices.Index(q.calls, calls[0])
+	if copied < 0 || !slices.Equal(q.calls[copied:min(copied+len(calls), len(q.calls))], calls) {
+		t.Fatalf("expected the announcements copied and the preferences moved before the leftovers are removed, got %v", q.calls)
+	}
+	if !q.deleted["dentist-b"] {
+		t.Fatalf("expected the merged dentist removed, got %v", q.calls)
+	}
+}
+
+func TestEnsureNoClinicDuplicatesScopesBranchesToTheirGroup(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	parentID := uuid.New()
+	var got []uuid.NullUUID
+	svc := &Service{queries: mockQuerier{
+		listDuplicateCandidatesFn: func(ctx context.Context, arg repository.ListClinicDuplicateCandidatesParams) ([]repository.ListClinicDuplicateCandidatesRow, error) {
+			got = append(got, arg.GroupClinicID)
+			if arg.GroupClinicID.Valid {
+				return nil, nil
+			}
+			return []repository.ListClinicDuplicateCandidatesRow{{ClinicID: parentID.String(), LegalName: "Clinica Sorriso LTDA", Similarity: 0.9}}, nil
+		},
+	}}
+
+	if err := svc.ensureNoClinicDuplicates(ctx, "11222333000181", "Clinica Sorriso LTDA", uuid.NullUUID{UUID: parentID, Valid: true}); err != nil {
+		t.Fatalf("expected a branch not to collide with its own group, got %v", err)
+	}
+	var duplicate *DuplicateClinicError
+	if err := svc.ensureNoClinicDuplicates(ctx, "11222333000181", "Clinica Sorriso LTDA", uuid.NullUUID{}); !errors.As(err, &duplicate) || len(duplicate.Candidates) != 1 {
+		t.Fatalf("expected a standalone clinic to report the similar name, got %v", err)
+	}
+	if len(got) != 2 || got[0].UUID != parentID || got[1].Valid {
+		t.Fatalf("expected the parent id only on the branch lookup, got %v", got)
+	}
+}
+
+// dashboardQuerier records what each dashboard block asked for; every block runs on its own goroutine and writes only its own field.
+type dashboardQuerier struct {
+	mockQuerier
+	shiftsFrom time.Time
+	tasksDueOn time.Time
+}
+
+func (q *dashboardQuerier) ListClinicShiftsBetween(ctx context.Context, arg repository.ListClinicShiftsBetweenParams) ([]repository.ListClinicShiftsBetweenRow, error) {
+	q.shiftsFrom = arg.FromAt
+	return []repository.ListClinicShiftsBetweenRow{{
+		ID:          "shift-1",
+		ClinicID:    arg.ClinicID,
+		DentistID:   "dentist-1",
+		StartsAt:    arg.FromAt.Add(8 * time.Hour),
+		EndsAt:      arg.FromAt.Add(12 * time.Hour),
+		DentistName: "Ana Souza",
+	}}, nil
+}
+
+func (q *dashboardQuerier) ListOpenClinicTimeClockEntries(ctx context.Context, arg repository.ListOpenClinicTimeClockEntriesParams) ([]repository.ListOpenClinicTimeClockEntriesRow, error) {
+	return nil, errors.New("connection reset")
+}
+
+func (q *dashboardQuerier) ListClinicTasksDueOn(ctx context.Context, arg repository.ListClinicTasksDueOnParams) ([]repository.ClinicTask, error) {
+	q.tasksDueOn = arg.DueDate
+	return []repository.ClinicTask{{ID: "task-1", ClinicID: arg.ClinicID, Title: "Conferir autoclave", Status: TaskStatusOpen, DueDate: sql.NullTime{Time: arg.DueDate, Valid: true}}}, nil
+}
+
+func (q *dashboardQuerier) ListClinicOverdueEquipment(ctx context.Context, arg repository.ListClinicOverdueEquipmentParams) ([]repository.Equipment, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestGetClinicDashboardTodayLoadsBlocksInTheClinicDay(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	clinicID := "01a13a20-4e6a-7000-8000-0000000000c1"
+	q := &dashboardQuerier{mockQuerier: mockQuerier{
+		organization: repository.Organization{ID: DefaultOrganizationID, Plan: PlanPro},
+		getClinicByIDFn: func(ctx context.Context, id string) (repository.Clinic, error) {
+			return repository.Clinic{ID: id, Timezone: "America/Manaus"}, nil
+		},
+		lowStockItems: []repository.ListLowStockInventoryItemsRow{{ID: "item-1", ClinicID: clinicID, Name: "Luvas", Quantity: 2, MinimumQuantity: 10}},
+	}}
+	// 02:30 UTC is still the evening before in Manaus.
+	svc := &Service{queries: q, now: func() time.Time { return time.Date(2026, 6, 10, 2, 30, 0, 0, time.UTC) }}
+
+	dashboard, err := svc.GetClinicDashboardToday(ctx, clinicID)
+	if err != nil {
+		t.Fatalf("GetClinicDashboardToday: %v", err)
+	}
+	if dashboard.Date != "2026-06-09" || !q.tasksDueOn.Equal(time.Date(2026, 6, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected the Manaus day, got %s and tasks due %s", dashboard.Date, q.tasksDueOn)
+	}
+	if want := time.Date(2026, 6, 9, 4, 0, 0, 0, time.UTC); !q.shiftsFrom.Equal(want) {
+		t.Fatalf("expected shifts from local midnight %s, got %s", want, q.shiftsFrom)
+	}
+	if dashboard.Shifts.Status != DashboardBlockAvailable || len(dashboard.Shifts.Items) != 1 || dashboard.Shifts.Items[0].DentistName != "Ana Souza" {
+		t.Fatalf("unexpected shifts block: %+v", dashboard.Shifts)
+	}
+	if dashboard.TasksDueToday.Status != DashboardBlockAvailable || len(dashboard.TasksDueToday.Items) != 1 || dashboard.TasksDueToday.Items[0].Overdue {
+		t.Fatalf("unexpected tasks block: %+v", dashboard.TasksDueToday)
+	}
+	if dashboard.LowStock.Status != DashboardBlockAvailable || len(dashboard.LowStock.Items) != 1 || dashboard.LowStock.Items[0].Shortfall != 8 {
+		t.Fatalf("unexpected low stock block: %+v", dashboard.LowStock)
+	}
+	if dashboard.OpenPunches.Status != DashboardBlockUnavailable || *dashboard.OpenPunches.Reason != "failed to load" {
+		t.Fatalf("expected a failed block marked unavailable, got %+v", dashboard.OpenPunches)
+	}
+	if dashboard.OverdueMaintenance.Status != DashboardBlockUnavailable || *dashboard.OverdueMaintenance.Reason != "timed out" {
+		t.Fatalf("expected a block past the deadline marked unavailable, got %+v", dashboard.OverdueMaintenance)
+	}
+	for _, block := range []DashboardBlockOutput[struct{}]{dashboard.Appointments, dashboard.CheckInQueue, dashboard.Billing} {
+		if block.Status != DashboardBlockUnavailable || block.Reason == nil || block.Items == nil {
+			t.Fatalf("expected blocks the API does not keep marked unavailable, got %+v", block)
+		}
+	}
+
+	q.organization.Plan = PlanBasic
+	if dashboard, err = svc.GetClinicDashboardToday(ctx, clinicID); err != nil {
+		t.Fatalf("GetClinicDashboardToday: %v", err)
+	}
+	if dashboard.Shifts.Status != DashboardBlockUnavailable || dashboard.TasksDueToday.Status != DashboardBlockAvailable {
+		t.Fatalf("expected only the shifts block to depend on the scheduling feature, got %+v", dashboard.Shifts)
+	}
+}
+
+func TestBrazilianNationalHolidaysIncludesMovableDates(t *testing.T) {
+	holidays := brazilianNationalHolidays(2025)
+	want := map[string]string{
+		"2025-03-04": "Carnaval",
+		"2025-04-18": "Sexta-feira Santa",
+		"2025-06-19": "Corpus Christi",
+		"2025-11-20": "Dia Nacional de Zumbi e da Consciência Negra",
+	}
+	for _, holiday := range holidays {
+		delete(want, holiday.date.Format(documentDateLayout))
+	}
+	if len(want) != 0 {
+		t.Fatalf("missing holidays: %v", want)
+	}
+}
+
+func TestParseOperatingHoursRejectsOverlap(t *testing.T) {
+	monday := 1
+	_, err := parseOperatingHours([]OperatingHoursInput{
+		{Weekday: &monday, OpensAt: "08:00", ClosesAt: "12:00"},
+		{Weekday: &monday, OpensAt: "11:30", ClosesAt: "18:00"},
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected overlap to be rejected, got %v", err)
+	}
+
+	hours, err := parseOperatingHours([]OperatingHoursInput{
+		{Weekday: &monday, OpensAt: "14:00", ClosesAt: "24:00"},
+		{Weekday: &monday, OpensAt: "08:00", ClosesAt: "12:00"},
+	})
+	if err != nil {
+		t.Fatalf("parse operating hours: %v", err)
+	}
+	if len(hours) != 2 || hours[0].OpensMinute != 480 || hours[1].ClosesMinute != 1440 {
+		t.Fatalf("unexpected intervals: %+v", hours)
+	}
+
+	inside := time.Date(2025, 6, 2, 9, 30, 0, 0, time.UTC)
+	lunch := time.Date(2025, 6, 2, 12, 30, 0, 0, time.UTC)
+	if !withinOperatingHours(hours, inside) || withinOperatingHours(hours, lunch) {
+		t.Fatalf("unexpected availability for monday intervals")
+	}
+}
+
+func TestResolveTimezoneInput(t *testing.T) {
+	timezone, err := resolveTimezoneInput(nil)
+	if err != nil || timezone != DefaultClinicTimezone {
+		t.Fatalf("expected default timezone, got %q (%v)", timezone, err)
+	}
+
+	manaus := " America/Manaus "
+	timezone, err = resolveTimezoneInput(&manaus)
+	if err != nil || timezone != "America/Manaus" {
+		t.Fatalf("expected trimmed timezone, got %q (%v)", timezone, err)
+	}
+
+	for _, invalid := range []string{"", "Local", "America/Atlantis", "GMT-3"} {
+		value := invalid
+		if _, err := resolveTimezoneInput(&value); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected %q to be rejected, got %v", invalid, err)
+		}
+	}
+}
+
+func TestTodayInUsesClinicLocalDate(t *testing.T) {
+	saoPaulo, err := time.LoadLocation(DefaultClinicTimezone)
+	if err != nil {
+		t.Fatalf("load location: %v", err)
+	}
+	// 01:30 UTC is still the previous evening in Sao Paulo.
+	svc := &Service{now: func() time.Time { return time.Date(2025, 6, 3, 1, 30, 0, 0, time.UTC) }}
+
+	if got := svc.todayIn(saoPaulo); !got.Equal(time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected local date 2025-06-02, got %s", got)
+	}
+	if got := svc.todayIn(time.UTC); !got.Equal(time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Fatalf("expected UTC date 2025-06-03, got %s", got)
+	}
+}
+
+func TestApplyClinicSettingsInput(t *testing.T) {
+	current := defaultClinicSettings("019f3329-a5a8-72ec-a95b-6e554247f442")
+	duration := 45
+	leadTimes := []int{60, 2880}
+	prefix := " cl-01 "
+
+	params, err := applyClinicSettingsInput(current, UpdateClinicSettingsInput{
+		DefaultAppointmentDurationMinutes: &duration,
+		ReminderLeadMinutes:               &leadTimes,
+		InvoiceNumberPrefix:               &prefix,
+	})
+	if err != nil {
+		t.Fatalf("apply clinic settings: %v", err)
+	}
+	if params.DefaultAppointmentDurationMinutes != 45 || params.InvoiceNumberPrefix != "CL-01" {
+		t.Fatalf("unexpected params: %+v", params)
+	}
+	if len(params.ReminderLeadMinutes) != 2 || params.ReminderLeadMinutes[0] != 2880 || params.ReminderLeadMinutes[1] != 60 {
+		t.Fatalf("expected lead times sorted descending, got %v", params.ReminderLeadMinutes)
+	}
+	if params.Locale != DefaultClinicLocale || params.Currency != DefaultClinicCurrency {
+		t.Fatalf("expected untouched fields to keep defaults, got %+v", params)
+	}
+
+	invalidDuration := 7
+	duplicated := []int{60, 60}
+	currency := "JPY"
+	for name, input := range map[string]UpdateClinicSettingsInput{
+		"duration":  {DefaultAppointmentDurationMinutes: &invalidDuration},
+		"lead time": {ReminderLeadMinutes: &duplicated},
+		"currency":  {Currency: &currency},
+	} {
+		if _, err := applyClinicSettingsInput(current, input); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected invalid %s to be rejected, got %v", name, err)
+		}
+	}
+}
+
+func TestOnboardingStatusesAdvanceOneStepAtATime(t *testing.T) {
+	status := OnboardingStatusDraft
+	visited := []string{status}
+	for {
+		next, ok := nextOnboardingStatus(status)
+		if !ok {
+			break
+		}
+		status = next
+		visited = append(visited, status)
+	}
+	if len(visited) != 4 || visited[3] != OnboardingStatusActive {
+		t.Fatalf("unexpected onboarding sequence: %v", visited)
+	}
+
+	svc := &Service{}
+	_, err := svc.AdvanceClinicOnboarding(context.Background(), "019f3329-a5a8-72ec-a95b-6e554247f442", AdvanceClinicOnboardingInput{
+		ToStatus:          "bank_verified",
+		EvidenceReference: "micro-deposit 123",
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected bank verification without bank_account_id to be rejected, got %v", err)
+	}
+}
+
+func TestParseNoteMentions(t *testing.T) {
+	dentistID := "019f3329-a5a8-72ec-a95b-6e554247f442"
+	mentions, err := parseNoteMentions([]NoteMentionInput{{EntityType: " dentist ", EntityID: dentistID}})
+	if err != nil {
+		t.Fatalf("parse mentions: %v", err)
+	}
+	if len(mentions) != 1 || mentions[0].EntityType != NoteMentionDentist || mentions[0].EntityID != dentistID {
+		t.Fatalf("unexpected mentions: %+v", mentions)
+	}
+
+	if _, err := parseNoteMentions([]NoteMentionInput{{EntityType: "PATIENT", EntityID: dentistID}}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown entity type to be rejected, got %v", err)
+	}
+
+	svc := &Service{}
+	_, err = svc.CreateClinicNote(context.Background(), dentistID, CreateClinicNoteInput{Body: "call back"})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected note without author to be rejected, got %v", err)
+	}
+}
+
+func TestDeleteClinicLocksClinicBeforeDeletingBankAccounts(t *testing.T) {
+	clinicID := "019f3329-a5a8-72ec-a95b-6e554247f442"
+	personID := "019f3329-a5a8-72ec-a95b-6e554247f443"
+	calls := make([]string, 0, 6)
+
+	q := mockQuerier{
+		getClinicByIDFn: func(ctx context.Context, id string) (repository.Clinic, error) {
+			calls = append(calls, "GetClinicByID")
+			return repository.Clinic{ID: id, PersonID: personID}, nil
+		},
+		lockClinicForUpdateFn: func(ctx context.Context, id string) (string, error) {
+			calls = append(calls, "LockClinicForUpdate")
+			return id, nil
+		},
+		endClinicDentistsByClinicFn: func(ctx context.Context, id string) (int64, error) {
+			calls = append(calls, "EndClinicDentistsByClinic")
+			return 1, nil
+		},
+		deleteBankAccountsByClinicFn: func(ctx context.Context, id string) (int64, error) {
+			calls = append(calls, "DeleteBankAccountsByClinicID")
+			return 1, nil
+		},
+		deleteClinicFn: func(ctx context.Context, id string) (int64, error) {
+			calls = append(calls, "DeleteClinic")
+			return 1, nil
+		},
+		deletePersonFn: func(ctx context.Context, id string) (int64, error) {
+			calls = append(calls, "DeletePerson")
+			return 1, nil
+		},
+	}
+
+	svc := &Service{}
+	if err := svc.deleteClinicWithinTx(context.Background(), q, clinicID); err != nil {
+		t.Fatalf("delete clinic within tx: %v", err)
+	}
+
+	want := []string{
+		"GetClinicByID",
+		"LockClinicForUpdate",
+		"EndClinicDentistsByClinic",
+		"DeleteBankAccountsByClinicID",
+		"DeleteClinic",
+		"DeletePerson",
+	}
+
+	if len(calls) != len(want) {
+		t.Fatalf("unexpected number of calls: got %d, want %d (%v)", len(calls), len(want), calls)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Fatalf("unexpected call order at index %d: got %q, want %q (full calls: %v)", i, calls[i], want[i], calls)
+		}
+	}
+}
+
+func TestDeleteClinicWithGracePeriodDefersCascade(t *testing.T) {
+	clinicID := "019f3329-a5a8-72ec-a95b-6e554247f442"
+	calls := make([]string, 0, 5)
+	var scheduled repository.CreatePendingDeletionParams
+
+	q := mockQuerier{
+		getClinicByIDFn: func(ctx context.Context, id string) (repository.Clinic, error) {
+			return repository.Clinic{ID: id, PersonID: "019f3329-a5a8-72ec-a95b-6e554247f443"}, nil
+		},
+		endClinicDentistsByClinicFn: func(ctx context.Context, id string) (int64, error) {
+			calls = append(calls, "EndClinicDentistsByClinic")
+			return 1, nil
+		},
+		deleteBankAccountsByClinicFn: func(ctx context.Context, id string) (int64, error) {
+			calls = append(calls, "DeleteBankAccountsByClinicID")
+			return 1, nil
+		},
+		deleteClinicFn: func(ctx context.Context, id string) (int64, error) {
+			calls = append(calls, "DeleteClinic")
+			return 1, nil
+		},
+		deletePersonFn: func(ctx context.Context, id string) (int64, error) {
+			calls = append(calls, "DeletePerson")
+			return 1, nil
+		},
+		createPendingDeletionFn: func(ctx context.Context, arg repository.CreatePendingDeletionParams) error {
+			calls = append(calls, "CreatePendingDeletion")
+			scheduled = arg
+			return nil
+		},
+	}
+
+	svc := &Service{deletionGracePeriod: 72 * time.Hour}
+	if err := svc.deleteClinicWithinTx(context.Background(), q, clinicID); err != nil {
+		t.Fatalf("delete clinic within tx: %v", err)
+	}
+
+	want := []string{"EndClinicDentistsByClinic", "DeleteClinic", "CreatePendingDeletion"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Fatalf("unexpected calls: got %v, want %v", calls, want)
+	}
+	if scheduled.ResourceType != DeletedResourceClinic || scheduled.ResourceID != clinicID || scheduled.GraceSeconds != (72*time.Hour).Seconds() {
+		t.Fatalf("unexpected pending deletion: %+v", scheduled)
+	}
+}
+
+func TestDeleteClinicRejectsOrganizationWithActiveBranches(t *testing.T) {
+	clinicID := "019f3329-a5a8-72ec-a95b-6e554247f442"
+	deleted := false
+
+	q := mockQuerier{
+		getClinicByIDFn: func(ctx context.Context, id string) (repository.Clinic, error) {
+			return repository.Clinic{ID: id, PersonID: "019f3329-a5a8-72ec-a95b-6e554247f443"}, nil
+		},
+		countActiveBranchesFn: func(ctx context.Context, parentClinicID string) (int32, error) {
+			return 2, nil
+		},
+		deleteClinicFn: func(ctx context.Context, id string) (int64, error) {
+			deleted = true
+			return 1, nil
+		},
+	}
+
+	svc := &Service{}
+	err := svc.deleteClinicWithinTx(context.Background(), q, clinicID)
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected conflict, got %v", err)
+	}
+	if deleted {
+		t.Fatalf("expected organization to be kept while it has branches")
+	}
+}
+
+func TestEnsureClinicOperationalRejectsDeactivatedClinic(t *testing.T) {
+	deactivatedAt := sql.NullTime{}
+	q := mockQuerier{
+		getClinicByIDFn: func(ctx context.Context, id string) (repository.Clinic, error) {
+			return repository.Clinic{ID: id, DeactivatedAt: deactivatedAt}, nil
+		},
+	}
+
+	clinicID := "019f3329-a5a8-72ec-a95b-6e554247f442"
+	if err := ensureClinicOperational(context.Background(), q, clinicID); err != nil {
+		t.Fatalf("expected active clinic to be operational, got %v", err)
+	}
+
+	deactivatedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	if err := ensureClinicOperational(context.Background(), q, clinicID); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected deactivated clinic to be rejected, got %v", err)
+	}
+}
+
+func TestEnsureUserCreatesWhenMissing(t *testing.T) {
+	created := false
+	q := mockQuerier{
+		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
+			return repository.User{}, sql.ErrNoRows
+		},
+		createUserFn: func(ctx context.Context, arg repository.CreateUserParams) (repository.User, error) {
+			created = true
+			if arg.Email != "admin@example.com" {
+				t.Fatalf("unexpected email: %s", arg.Email)
+			}
+			if err := bcrypt.CompareHashAndPassword([]byte(arg.PasswordHash), []byte("secret123")); err != nil {
+				t.Fatalf("password hash does not match input password: %v", err)
+			}
+			return repository.User{ID: arg.ID, Email: arg.Email, PasswordHash: arg.PasswordHash}, nil
+		},
+	}
+	svc := newAuthServiceForTest(q)
+
+	if err := svc.EnsureUser(context.Background(), "admin@example.com", "secret123"); err != nil {
+		t.Fatalf("ensure user: %v", err)
+	}
+	if !created {
+		t.Fatalf("expected CreateUser to be called")
+	}
+}
 
 func TestLoginAndValidateAccessToken(t *testing.T) {
 	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.DefaultCost)
 	if err != nil {
-		t.Fatalf("generate password hash: %v", err)
+		t.Fatalf("generate password hash: %v", err)
+	}
+	id, err := uuid.NewV7()
+	if err != nil {
+		t.Fatalf("new uuidv7: %v", err)
+	}
+	userID := id.String()
+
+	q := mockQuerier{
+		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
+			if email != "admin@example.com" {
+				return repository.User{}, sql.ErrNoRows
+			}
+			return repository.User{ID: userID, Email: "admin@example.com", PasswordHash: string(hash)}, nil
+		},
+	}
+	svc := newAuthServiceForTest(q)
+
+	output, err := svc.Login(context.Background(), LoginInput{Email: "admin@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if output.AccessToken == "" {
+		t.Fatalf("expected access token")
+	}
+	if output.TokenType != "Bearer" {
+		t.Fatalf("expected token type Bearer, got %q", output.TokenType)
+	}
+
+	if err := svc.ValidateAccessToken(output.AccessToken); err != nil {
+		t.Fatalf("validate access token: %v", err)
+	}
+}
+
+func TestAuthenticateAccessTokenCarriesDentistRole(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("generate password hash: %v", err)
+	}
+	userID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatalf("new uuidv7: %v", err)
+	}
+	dentistID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatalf("new uuidv7: %v", err)
+	}
+
+	q := mockQuerier{
+		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
+			return repository.User{
+				ID:           userID.String(),
+				Email:        email,
+				PasswordHash: string(hash),
+				Role:         UserRoleDentist,
+				DentistID:    uuid.NullUUID{UUID: dentistID, Valid: true},
+			}, nil
+		},
+	}
+	svc := newAuthServiceForTest(q)
+
+	output, err := svc.Login(context.Background(), LoginInput{Email: "dentist@example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if output.Role != UserRoleDentist {
+		t.Fatalf("expected role %q, got %q", UserRoleDentist, output.Role)
+	}
+
+	principal, err := svc.AuthenticateAccessToken(output.AccessToken)
+	if err != nil {
+		t.Fatalf("authenticate access token: %v", err)
+	}
+	if principal.Role != UserRoleDentist || principal.DentistID != dentistID.String() || principal.UserID != userID.String() {
+		t.Fatalf("unexpected principal: %+v", principal)
+	}
+}
+
+func TestAuthenticateAccessTokenScopesPrincipalToOrganization(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.DefaultCost)
+	if err != nil {
+		t.Fatalf("generate password hash: %v", err)
+	}
+	userID, err := uuid.NewV7()
+	if err != nil {
+		t.Fatalf("new uuidv7: %v", err)
+	}
+	organization, err := uuid.NewV7()
+	if err != nil {
+		t.Fatalf("new uuidv7: %v", err)
+	}
+
+	q := mockQuerier{
+		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
+			return repository.User{
+				ID:             userID.String(),
+				OrganizationID: organization.String(),
+				Email:          email,
+				PasswordHash:   string(hash),
+				Role:           UserRoleAdmin,
+			}, nil
+		},
+	}
+	svc := newAuthServiceForTest(q)
+
+	output, err := svc.Login(context.Background(), LoginInput{Email: "admin@tenant.example.com", Password: "secret123"})
+	if err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if output.OrganizationID != organization.String() {
+		t.Fatalf("expected organization %q, got %q", organization.String(), output.OrganizationID)
+	}
+
+	principal, err := svc.AuthenticateAccessToken(output.AccessToken)
+	if err != nil {
+		t.Fatalf("authenticate access token: %v", err)
+	}
+	ctx := WithPrincipal(context.Background(), principal)
+	if got := organizationID(ctx); got != organization.String() {
+		t.Fatalf("expected queries scoped to %q, got %q", organization.String(), got)
+	}
+	if got := organizationID(WithOrganization(ctx, DefaultOrganizationID)); got != DefaultOrganizationID {
+		t.Fatalf("expected explicit organization to win, got %q", got)
+	}
+
+	legacy, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Issuer:    svc.jwtIssuer,
+		Subject:   userID.String(),
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString(svc.jwtSigningKey)
+	if err != nil {
+		t.Fatalf("sign legacy token: %v", err)
+	}
+	principal, err = svc.AuthenticateAccessToken(legacy)
+	if err != nil {
+		t.Fatalf("authenticate legacy token: %v", err)
+	}
+	if principal.OrganizationID != DefaultOrganizationID {
+		t.Fatalf("expected legacy token in default organization, got %q", principal.OrganizationID)
+	}
+}
+
+var errUnreachableDB = errors.New("unexpected database call")
+
+type unreachableDB struct{}
+
+func (unreachableDB) Exec(context.Context, string, ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, errUnreachableDB
+}
+
+func (unreachableDB) Query(context.Context, string, ...interface{}) (pgx.Rows, error) {
+	return nil, errUnreachableDB
+}
+
+func (unreachableDB) QueryRow(context.Context, string, ...interface{}) pgx.Row {
+	return errRow{err: errUnreachableDB}
+}
+
+func TestTenantGuardRefusesQueriesOutsideOrganization(t *testing.T) {
+	const (
+		scopedQuery   = "-- name: GetClinicByID :one\nSELECT id FROM clinics WHERE id = $1 AND organization_id = $2"
+		unscopedQuery = "-- name: GetUserByEmail :one\nSELECT id FROM users WHERE email = $1"
+	)
+	tenant := "019f3329-a5a8-72ec-a95b-6e554247f442"
+	other := "019f3329-a5a8-72ec-a95b-6e554247f443"
+	ctx := WithOrganization(context.Background(), tenant)
+
+	if err := checkTenantScope(context.Background(), scopedQuery, []interface{}{"clinic-id", tenant}); !errors.Is(err, ErrMissingTenantScope) {
+		t.Fatalf("expected unscoped context to be refused, got %v", err)
+	}
+	if err := checkTenantScope(ctx, scopedQuery, []interface{}{"clinic-id", other}); !errors.Is(err, ErrMissingTenantScope) {
+		t.Fatalf("expected query for another organization to be refused, got %v", err)
+	}
+	if err := checkTenantScope(ctx, scopedQuery, []interface{}{"clinic-id", tenant}); err != nil {
+		t.Fatalf("expected scoped query to pass, got %v", err)
+	}
+	if err := checkTenantScope(context.Background(), unscopedQuery, []interface{}{"admin@example.com"}); err != nil {
+		t.Fatalf("expected login lookup to pass without organization, got %v", err)
+	}
+
+	guard := newTenantGuard(unreachableDB{})
+	var id string
+	if err := guard.QueryRow(context.Background(), scopedQuery, "clinic-id", tenant).Scan(&id); !errors.Is(err, ErrMissingTenantScope) {
+		t.Fatalf("expected refused row query to report missing scope, got %v", err)
+	}
+	if _, err := guard.Exec(ctx, scopedQuery, "clinic-id", other); !errors.Is(err, ErrMissingTenantScope) {
+		t.Fatalf("expected refused exec to report missing scope, got %v", err)
+	}
+}
+
+func TestEnsureQuotaRejectsCreationAtLimit(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	q := mockQuerier{
+		organization:      repository.Organization{ID: DefaultOrganizationID, MaxClinics: sql.NullInt32{Int32: 2, Valid: true}},
+		organizationUsage: repository.GetOrganizationUsageRow{Clinics: 2, Dentists: 40},
+	}
+
+	err := ensureQuota(ctx, q, QuotaClinics)
+	var quota *QuotaExceededError
+	if !errors.As(err, &quota) || !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("expected quota exceeded error, got %v", err)
+	}
+	if quota.Quota != QuotaClinics || quota.Limit != 2 || quota.Usage != 2 {
+		t.Fatalf("unexpected quota error: %+v", quota)
+	}
+	if err := ensureQuota(ctx, q, QuotaDentists); err != nil {
+		t.Fatalf("expected dentists without a limit to pass, got %v", err)
+	}
+}
+
+func TestRequestQuotaCounterLimitsPerOrganizationWindow(t *testing.T) {
+	counter := newRequestQuotaCounter()
+	now := time.Date(2026, 3, 10, 12, 0, 20, 0, time.UTC)
+	limit := sql.NullInt32{Int32: 2, Valid: true}
+
+	for i := 0; i < 2; i++ {
+		if err := counter.take("org-a", limit, now); err != nil {
+			t.Fatalf("request %d: unexpected error %v", i+1, err)
+		}
+	}
+	err := counter.take("org-a", limit, now)
+	var quota *QuotaExceededError
+	if !errors.As(err, &quota) || quota.Quota != QuotaRequestsPerMinute || quota.RetryAfter != 40*time.Second {
+		t.Fatalf("expected request quota error with 40s retry, got %v", err)
+	}
+	if err := counter.take("org-b", limit, now); err != nil {
+		t.Fatalf("expected other organizations to keep their own quota, got %v", err)
+	}
+	if err := counter.take("org-a", limit, now.Add(time.Minute)); err != nil {
+		t.Fatalf("expected quota to reset in the next window, got %v", err)
+	}
+	if _, cached := counter.limit("org-b", now.Add(time.Minute)); cached {
+		t.Fatalf("expected limits to be reloaded in the next window")
+	}
+}
+
+func TestCreateOrganizationRejectsInvalidSlug(t *testing.T) {
+	svc := &Service{}
+
+	for _, slug := range []string{"", "-acme", "acme-", "acme_dental", strings.Repeat("a", 64)} {
+		_, err := svc.CreateOrganization(context.Background(), CreateOrganizationInput{
+			Slug:          slug,
+			Name:          "Acme",
+			AdminEmail:    "admin@acme.example.com",
+			AdminPassword: "secret123",
+		})
+		if !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected validation error for slug %q, got %v", slug, err)
+		}
+	}
+}
+
+func TestLoginInvalidCredentials(t *testing.T) {
+	q := mockQuerier{
+		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
+			return repository.User{}, sql.ErrNoRows
+		},
+	}
+	svc := newAuthServiceForTest(q)
+
+	_, err := svc.Login(context.Background(), LoginInput{Email: "wrong@example.com", Password: "invalid-password"})
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got: %v", err)
+	}
+}
+
+func TestDiffRevisionStatesFlattensNestedFields(t *testing.T) {
+	before := []byte(`{"legal_name":"Clinica Sorriso","address":{"city":"Sao Paulo","cep":"01001000"},"deleted_at":null}`)
+	after := []byte(`{"legal_name":"Clinica Sorriso","address":{"city":"Campinas","cep":"01001000"},"deleted_at":"2026-01-02T00:00:00Z"}`)
+
+	changes, err := diffRevisionStates(before, after)
+	if err != nil {
+		t.Fatalf("diff revision states: %v", err)
+	}
+	if len(changes) != 2 {
+		t.Fatalf("expected 2 changes, got %+v", changes)
+	}
+	if changes[0].Field != "address.city" || changes[0].From != "Sao Paulo" || changes[0].To != "Campinas" {
+		t.Fatalf("unexpected address change: %+v", changes[0])
+	}
+	if changes[1].Field != "deleted_at" || changes[1].From != nil {
+		t.Fatalf("unexpected deleted_at change: %+v", changes[1])
+	}
+
+	created, err := diffRevisionStates([]byte("null"), after)
+	if err != nil {
+		t.Fatalf("diff created revision: %v", err)
+	}
+	if len(created) != 4 {
+		t.Fatalf("expected every field on a created entity, got %+v", created)
+	}
+}
+
+func TestListClinicRevisionsRejectsUnknownEntityType(t *testing.T) {
+	svc := &Service{}
+	entityType := "dentist"
+
+	_, _, err := svc.ListClinicRevisions(context.Background(), "019f3329-a5a8-72ec-a95b-6e554247f442", 10, nil, &entityType)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation, got: %v", err)
+	}
+}
+
+func TestGetClinicAsOfRejectsFutureInstant(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	svc := &Service{now: func() time.Time { return now }}
+
+	_, err := svc.GetClinicAsOf(context.Background(), "019f3329-a5a8-72ec-a95b-6e554247f442", now.Add(time.Hour), false)
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation, got: %v", err)
+	}
+}
+
+func TestMapBankAccountsAsOfKeepsAccountsDeletedWithClinic(t *testing.T) {
+	clinicDeletedAt := time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC)
+	earlier := clinicDeletedAt.Add(-24 * time.Hour)
+	accounts := []bankAccountAsOf{
+		{id: "019f3329-a5a8-72ec-a95b-6e554247f401", state: bankAccountRevisionState{BankCode: "001", DeletedAt: &clinicDeletedAt}},
+		{id: "019f3329-a5a8-72ec-a95b-6e554247f402", state: bankAccountRevisionState{BankCode: "341", DeletedAt: &earlier}},
+		{id: "019f3329-a5a8-72ec-a95b-6e554247f403", state: bankAccountRevisionState{BankCode: "237", IsPrimary: true, DeletedAt: &clinicDeletedAt}},
+	}
+
+	output := mapBankAccountsAsOf(accounts, &clinicDeletedAt)
+	if len(output) != 2 {
+		t.Fatalf("expected 2 bank accounts, got %+v", output)
+	}
+	if output[0].BankCode != "237" || !output[0].IsPrimary || output[1].BankCode != "001" {
+		t.Fatalf("unexpected bank accounts: %+v", output)
+	}
+
+	if output := mapBankAccountsAsOf(accounts, nil); len(output) != 0 {
+		t.Fatalf("expected deleted accounts to be hidden for an active clinic, got %+v", output)
+	}
+}
+
+func TestEnsureDeleteConfirmedChecksTokenAgainstCurrentCounts(t *testing.T) {
+	clinicID := "019f3329-a5a8-72ec-a95b-6e554247f442"
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	counts := repository.GetClinicDeletePreviewCountsRow{DentistLinks: 4, BankAccounts: 2}
+	q := mockQuerier{deletePreviewCountsFn: func(ctx context.Context, id string) (repository.GetClinicDeletePreviewCountsRow, error) {
+		return counts, nil
+	}}
+	svc := &Service{queries: q, now: func() time.Time { return now }, jwtSigningKey: []byte("secret"), deleteConfirmation: 5}
+
+	if err := svc.ensureDeleteConfirmed(context.Background(), q, clinicID, ""); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("expected ErrConfirmationRequired without a token, got: %v", err)
+	}
+	token := svc.deleteConfirmationToken(clinicID, counts, now.Add(deleteConfirmationTTL))
+	if err := svc.ensureDeleteConfirmed(context.Background(), q, clinicID, token); err != nil {
+		t.Fatalf("expected token to confirm the delete, got: %v", err)
+	}
+
+	counts.DentistLinks++
+	if err := svc.ensureDeleteConfirmed(context.Background(), q, clinicID, token); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("expected a stale token to be rejected, got: %v", err)
+	}
+	counts.DentistLinks--
+	now = now.Add(deleteConfirmationTTL)
+	if err := svc.ensureDeleteConfirmed(context.Background(), q, clinicID, token); !errors.Is(err, ErrConfirmationRequired) {
+		t.Fatalf("expected an expired token to be rejected, got: %v", err)
+	}
+
+	counts = repository.GetClinicDeletePreviewCountsRow{DentistLinks: 1, BankAccounts: 1}
+	if err := svc.ensureDeleteConfirmed(context.Background(), q, clinicID, ""); err != nil {
+		t.Fatalf("expected small clinics to skip confirmation, got: %v", err)
+	}
+}
+
+func TestExportAuditLogRangePagesByID(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	firstPage := make([]repository.AuditLog, auditExportRangePage)
+	for i := range firstPage {
+		firstPage[i] = repository.AuditLog{ID: uuid.NewString(), EntityID: uuid.NewString(), CreatedAt: from}
+	}
+	lastID := firstPage[len(firstPage)-1].ID
+	calls := make([]repository.ListAuditLogsCreatedBetweenParams, 0, 2)
+	q := mockQuerier{listAuditLogsBetweenFn: func(ctx context.Context, arg repository.ListAuditLogsCreatedBetweenParams) ([]repository.AuditLog, error) {
+		calls = append(calls, arg)
+		if !arg.AfterID.Valid {
+			return firstPage, nil
+		}
+		return []repository.AuditLog{{ID: uuid.NewString(), EntityID: uuid.NewString(), CreatedAt: from}}, nil
+	}}
+	svc := &Service{queries: q}
+
+	emitted := 0
+	if err := svc.ExportAuditLogRange(context.Background(), from, to, func(AuditLogRecord) error {
+		emitted++
+		return nil
+	}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if emitted != auditExportRangePage+1 {
+		t.Fatalf("expected %d records, got %d", auditExportRangePage+1, emitted)
+	}
+	if len(calls) != 2 || calls[1].AfterID.UUID.String() != lastID {
+		t.Fatalf("expected second page after %s, got calls: %+v", lastID, calls)
+	}
+
+	if err := svc.ExportAuditLogRange(context.Background(), to, from, func(AuditLogRecord) error { return nil }); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for an inverted range, got: %v", err)
+	}
+	if err := svc.ExportAuditLogRange(context.Background(), from, from.Add(auditExportMaxRange+time.Hour), func(AuditLogRecord) error { return nil }); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected ErrValidation for an oversized range, got: %v", err)
+	}
+}
+
+func TestRunIntegrityChecksReportsWithoutRepairing(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	orphans := make([]string, integrityFindingLimit+1)
+	for i := range orphans {
+		orphans[i] = uuid.NewString()
+	}
+	q := mockQuerier{integrityFindingsFn: func(check string, pageLimit int32) ([]string, error) {
+		if pageLimit != integrityFindingLimit+1 {
+			t.Fatalf("expected page limit %d, got %d", integrityFindingLimit+1, pageLimit)
+		}
+		switch check {
+		case IntegrityClinicWithoutPrimaryBankAccount:
+			return []string{"019f3329-a5a8-72ec-a95b-6e554247f442"}, nil
+		case IntegrityOrphanedPerson:
+			return orphans, nil
+		}
+		return nil, nil
+	}}
+	svc := &Service{queries: q, now: func() time.Time { return now }}
+
+	report, err := svc.RunIntegrityChecks(context.Background(), false)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(report.Checks) != 4 || !report.CheckedAt.Equal(now) || report.Repair {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+	for _, check := range report.Checks {
+		if len(check.RepairedIDs) != 0 {
+			t.Fatalf("expected no repairs without repair flag, got %+v", check)
+		}
+		switch check.Check {
+		case IntegrityClinicWithoutPrimaryBankAccount:
+			if check.Count != 1 || !check.Repairable {
+				t.Fatalf("unexpected primary account finding: %+v", check)
+			}
+		case IntegrityOrphanedPerson:
+			if check.Count != integrityFindingLimit || !check.Truncated || check.EntityType != AuditEntityPerson {
+				t.Fatalf("expected truncated orphaned person finding, got count=%d truncated=%v", check.Count, check.Truncated)
+			}
+		case IntegrityClinicWithoutBankAccount, IntegrityDentistWithoutActiveClinic:
+			if check.Count != 0 || check.Repairable || check.EntityIDs == nil {
+				t.Fatalf("unexpected finding: %+v", check)
+			}
+		}
+	}
+}
+
+func TestVerifyAuditChainDetectsTampering(t *testing.T) {
+	createdAt := time.Date(2026, 3, 10, 12, 0, 0, 123456000, time.UTC)
+	clinicID := uuid.NullUUID{UUID: uuid.MustParse("019f3329-a5a8-72ec-a95b-6e554247f442"), Valid: true}
+	rows := make([]repository.AuditLog, 0, 3)
+	previousHash := auditChainGenesisHash
+	for i := int64(1); i <= 3; i++ {
+		row := repository.AuditLog{
+			ID:         uuid.NewString(),
+			ClinicID:   clinicID,
+			Action:     "clinic.updated",
+			EntityType: AuditEntityClinic,
+			EntityID:   clinicID.UUID.String(),
+			Metadata:   json.RawMessage(`{"field": "legal_name"}`),
+			CreatedAt:  createdAt.Add(time.Duration(i) * time.Second),
+		}
+		hash, err := auditEntryHash(previousHash, i, row)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		row.ChainSequence = sql.NullInt64{Int64: i, Valid: true}
+		row.PreviousHash = sql.NullString{String: previousHash, Valid: true}
+		row.EntryHash = sql.NullString{String: hash, Valid: true}
+		rows = append(rows, row)
+		previousHash = hash
+	}
+	head := repository.AuditChainHead{LastSequence: 3, LastHash: previousHash}
+	now := func() time.Time { return createdAt }
+
+	// The retention purge clears clinic_id, which must not break the chain.
+	rows[0].ClinicID = uuid.NullUUID{}
+	svc := &Service{queries: mockQuerier{auditChainHead: head, sealedAuditLogs: rows}, now: now}
+	result, err := svc.VerifyAuditChain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Valid || result.VerifiedEntries != 3 || result.Break != nil {
+		t.Fatalf("expected a valid chain, got %+v", result)
+	}
+
+	tampered := append([]repository.AuditLog(nil), rows...)
+	tampered[1].Metadata = json.RawMessage(`{"field": "trade_name"}`)
+	svc.queries = mockQuerier{auditChainHead: head, sealedAuditLogs: tampered}
+	result, err = svc.VerifyAuditChain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.Break == nil || result.Break.Sequence != 2 || result.Break.Reason != AuditChainHashMismatch {
+		t.Fatalf("expected a hash mismatch at sequence 2, got %+v", result)
+	}
+
+	svc.queries = mockQuerier{auditChainHead: head, sealedAuditLogs: []repository.AuditLog{rows[0], rows[2]}}
+	result, err = svc.VerifyAuditChain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.Break == nil || result.Break.Reason != AuditChainSequenceGap {
+		t.Fatalf("expected a sequence gap, got %+v", result)
+	}
+
+	svc.queries = mockQuerier{auditChainHead: head, sealedAuditLogs: rows[:2]}
+	result, err = svc.VerifyAuditChain(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Valid || result.Break == nil || result.Break.Reason != AuditChainHeadMismatch {
+		t.Fatalf("expected a head mismatch when the tail is removed, got %+v", result)
+	}
+}
+
+func TestDataKeyStoreSealsPerOrganizationAndRefusesShreddedKey(t *testing.T) {
+	ctx := context.Background()
+	wrapper, err := keywrap.NewLocal("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	if err != nil {
+		t.Fatalf("create wrapper: %v", err)
+	}
+	stored := &repository.OrganizationKey{}
+	store := newDataKeyStore(wrapper, mockQuerier{organizationKey: stored}, time.Now)
+
+	sealed, err := store.seal(ctx, DefaultOrganizationID, "123456-7")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	if !strings.HasPrefix(sealed, encryptedFieldPrefix) || strings.Contains(sealed, "123456") {
+		t.Fatalf("expected an encrypted value, got %q", sealed)
+	}
+	if len(stored.WrappedKey) == 0 {
+		t.Fatal("expected the data key to be stored wrapped")
+	}
+	opened, err := store.open(ctx, DefaultOrganizationID, sealed)
+	if err != nil || opened != "123456-7" {
+		t.Fatalf("expected round trip, got %q, %v", opened, err)
+	}
+	if legacy, err := store.open(ctx, DefaultOrganizationID, "998877"); err != nil || legacy != "998877" {
+		t.Fatalf("expected plaintext values to pass through, got %q, %v", legacy, err)
+	}
+	if _, err := store.open(ctx, "01a13a20-4e6a-7000-8000-000000000002", sealed); err == nil {
+		t.Fatal("expected a value sealed for one organization not to open for another")
+	}
+
+	stored.WrappedKey = nil
+	stored.ShreddedAt = sql.NullTime{Time: time.Now(), Valid: true}
+	store.forget(DefaultOrganizationID)
+	if _, err := store.open(ctx, DefaultOrganizationID, sealed); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected shredded key conflict on read, got %v", err)
+	}
+	if _, err := store.seal(ctx, DefaultOrganizationID, "123456-7"); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected shredded key conflict on write, got %v", err)
+	}
+}
+
+func TestCheckOrganizationAccessGatesByStatus(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	cases := []struct {
+		name         string
+		organization repository.Organization
+		readAllowed  bool
+		writeAllowed bool
+	}{
+		{"active", repository.Organization{Status: OrganizationStatusActive}, true, true},
+		{"running trial", repository.Organization{Status: OrganizationStatusTrial, TrialEndsAt: sql.NullTime{Time: now.Add(time.Hour), Valid: true}}, true, true},
+		{"expired trial", repository.Organization{Status: OrganizationStatusTrial, TrialEndsAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}}, true, false},
+		{"suspended", repository.Organization{Status: OrganizationStatusSuspended}, true, false},
+		{"closed", repository.Organization{Status: OrganizationStatusClosed}, false, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			svc := &Service{queries: mockQuerier{organization: tc.organization}, now: func() time.Time { return now }}
+			if err := svc.CheckOrganizationAccess(ctx, false); (err == nil) != tc.readAllowed {
+				t.Fatalf("read: expected allowed=%v, got %v", tc.readAllowed, err)
+			}
+			err := svc.CheckOrganizationAccess(ctx, true)
+			if (err == nil) != tc.writeAllowed {
+				t.Fatalf("write: expected allowed=%v, got %v", tc.writeAllowed, err)
+			}
+			if err != nil && !errors.Is(err, ErrForbidden) {
+				t.Fatalf("expected forbidden, got %v", err)
+			}
+		})
+	}
+}
+
+func TestUpdateOrganizationStatusRejectsClosingOutsideOffboarding(t *testing.T) {
+	svc := &Service{now: time.Now}
+	past := time.Now().Add(-time.Hour)
+
+	for _, input := range []UpdateOrganizationStatusInput{
+		{Status: OrganizationStatusClosed},
+		{Status: OrganizationStatusTrial},
+		{Status: OrganizationStatusTrial, TrialEndsAt: &past},
+		{Status: OrganizationStatusActive, TrialEndsAt: &past},
+		{Status: "ARCHIVED"},
+	} {
+		if _, err := svc.UpdateOrganizationStatus(context.Background(), DefaultOrganizationID, input); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected validation error for %+v, got %v", input, err)
+		}
+	}
+}
+
+func TestOperatorAndTenantTokensStayInTheirRealm(t *testing.T) {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte("operator-pass"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	svc := newAuthServiceForTest(mockQuerier{platformOperator: repository.PlatformOperator{
+		ID:           "01a13a20-4e6a-7000-8000-0000000000aa",
+		Email:        "ops@example.com",
+		PasswordHash: string(passwordHash),
+	}})
+	// Even with the same secret in both realms, the audience keeps operator tokens out of tenant routes.
+	svc.adminSigningKey = svc.jwtSigningKey
+
+	login, err := svc.AdminLogin(context.Background(), LoginInput{Email: "ops@example.com", Password: "operator-pass"})
+	if err != nil {
+		t.Fatalf("admin login: %v", err)
+	}
+	operator, err := svc.AuthenticateOperatorToken(login.AccessToken)
+	if err != nil || operator.ID != login.OperatorID {
+		t.Fatalf("expected operator token to authenticate, got %+v, %v", operator, err)
+	}
+	if _, err := svc.AuthenticateAccessToken(login.AccessToken); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected operator token to be refused on tenant routes, got %v", err)
+	}
+
+	tenantToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessTokenClaims{
+		Email:          "admin@example.com",
+		Role:           UserRoleAdmin,
+		OrganizationID: DefaultOrganizationID,
+		ImpersonatedBy: operator.ID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    svc.jwtIssuer,
+			Subject:   "01a13a20-4e6a-7000-8000-0000000000bb",
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	signed, err := tenantToken.SignedString(svc.jwtSigningKey)
+	if err != nil {
+		t.Fatalf("sign tenant token: %v", err)
+	}
+	if _, err := svc.AuthenticateOperatorToken(signed); !errors.Is(err, ErrUnauthorized) {
+		t.Fatalf("expected tenant token to be refused on admin routes, got %v", err)
+	}
+	principal, err := svc.AuthenticateAccessToken(signed)
+	if err != nil || principal.ImpersonatedBy != operator.ID {
+		t.Fatalf("expected impersonation to reach the principal, got %+v, %v", principal, err)
+	}
+}
+
+func TestTenantTrafficSummarizesLastHourPerOrganization(t *testing.T) {
+	traffic := newTenantTraffic()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	traffic.record("org-a", 200, now.Add(-2*time.Hour))
+	traffic.record("org-a", 200, now.Add(-10*time.Minute))
+	traffic.record("org-a", 404, now.Add(-time.Minute))
+	traffic.record("org-a", 500, now)
+	traffic.record("org-a", 201, now)
+	traffic.record("org-b", 500, now)
+
+	summary := traffic.summary("org-a", now)
+	if summary.Requests != 4 || summary.ClientErrors != 1 || summary.ServerErrors != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if summary.ServerErrorRate != 0.25 {
+		t.Fatalf("expected 25%% server errors, got %v", summary.ServerErrorRate)
+	}
+	if empty := traffic.summary("org-c", now); empty.Requests != 0 || empty.WindowMinutes != tenantTrafficBuckets {
+		t.Fatalf("unexpected summary for idle organization: %+v", empty)
+	}
+}
+
+type stubTenantSchemas struct{}
+
+func (stubTenantSchemas) Pool(ctx context.Context, schema string) (*pgxpool.Pool, error) {
+	return nil, nil
+}
+
+func (stubTenantSchemas) Migrate(ctx context.Context, schema string) (int, error) {
+	return 1, nil
+}
+
+func TestSchemaIsolationRoutesLookupsToTenantSchemas(t *testing.T) {
+	svc := newAuthServiceForTest(mockQuerier{})
+	if _, err := svc.validateOrganizationIsolation(OrganizationIsolationSchema); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected schema isolation to be refused while disabled, got %v", err)
+	}
+
+	svc = newAuthServiceForTest(mockQuerier{schemaOrganizations: []repository.Organization{
+		{ID: "org-a", Isolation: OrganizationIsolationSchema},
+		{ID: "org-b", Isolation: OrganizationIsolationSchema},
+	}})
+	svc.db = newRoutedDB(nil)
+	svc.db.schemas = stubTenantSchemas{}
+	if isolation, err := svc.validateOrganizationIsolation(" schema "); err != nil || isolation != OrganizationIsolationSchema {
+		t.Fatalf("expected schema isolation, got %q, %v", isolation, err)
+	}
+
+	var searched []string
+	found, err := findInTenantSchemas(context.Background(), svc, func(ctx context.Context) (string, error) {
+		searched = append(searched, organizationID(ctx))
+		if organizationID(ctx) == "org-b" {
+			return "dentist-1", nil
+		}
+		return "", sql.ErrNoRows
+	})
+	if err != nil || found != "dentist-1" {
+		t.Fatalf("expected lookup to reach org-b, got %q, %v", found, err)
+	}
+	if strings.Join(searched, ",") != ",org-a,org-b" {
+		t.Fatalf("expected shared tables first, then each schema tenant, got %v", searched)
+	}
+
+	if name := tenantSchemaName("01A13A20-4E6A-7000-8000-000000000001"); name != "tenant_01a13a204e6a70008000000000000001" {
+		t.Fatalf("unexpected schema name %q", name)
+	}
+}
+
+func TestCheckFeatureFollowsOrganizationPlan(t *testing.T) {
+	ctx := WithOrganization(context.Background(), "org-1")
+	basic := newAuthServiceForTest(mockQuerier{organization: repository.Organization{ID: "org-1", Plan: PlanBasic}})
+	var notInPlan *FeatureNotInPlanError
+	if err := basic.CheckFeature(ctx, FeatureScheduling); !errors.As(err, &notInPlan) || notInPlan.Plan != PlanBasic {
+		t.Fatalf("expected scheduling to be refused on BASIC, got %v", err)
+	}
+
+	pro := newAuthServiceForTest(mockQuerier{organization: repository.Organization{ID: "org-1", Plan: PlanPro}})
+	if err := pro.CheckFeature(ctx, FeatureBilling); err != nil {
+		t.Fatalf("expected billing on PRO, got %v", err)
+	}
+	entitlements, err := pro.GetEntitlements(ctx)
+	if err != nil {
+		t.Fatalf("get entitlements: %v", err)
+	}
+	enabled := map[string]bool{}
+	for _, feature := range entitlements.Features {
+		enabled[feature.Feature] = feature.Enabled
+	}
+	if len(entitlements.Features) != len(Features) || !enabled[FeatureScheduling] || !enabled[FeatureBilling] || enabled[FeatureWebhooks] {
+		t.Fatalf("unexpected PRO entitlements: %+v", entitlements)
+	}
+
+	if _, err := validatePlan("platinum"); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown plan to be rejected, got %v", err)
+	}
+}
+
+type discardPublisher struct{}
+
+func (discardPublisher) Publish(ctx context.Context, event Event) error {
+	return nil
+}
+
+func TestDataResidencyRejectsDestinationsOutsideRegion(t *testing.T) {
+	region := "br"
+	svc := newAuthServiceForTest(mockQuerier{organization: repository.Organization{
+		ID:         "org-1",
+		Plan:       PlanEnterprise,
+		DataRegion: sql.NullString{String: region, Valid: true},
+	}})
+	svc.events = discardPublisher{}
+	ctx := WithOrganization(context.Background(), "org-1")
+
+	if err := svc.checkDataResidency(ctx, DestinationWebhooks); !errors.Is(err, ErrDataResidency) {
+		t.Fatalf("expected undeclared webhook region to be refused, got %v", err)
+	}
+	WithDestinationRegions(DestinationRegions{Webhooks: " US "})(svc)
+	if err := svc.checkDataResidency(ctx, DestinationWebhooks); !errors.Is(err, ErrDataResidency) || !strings.Contains(err.Error(), "webhooks is in us") {
+		t.Fatalf("expected webhook in another region to be refused, got %v", err)
+	}
+	if svc.webhooksEnabled(ctx) {
+		t.Fatal("expected webhook events to be withheld outside the region")
+	}
+	if err := svc.checkDestinationRegions(sql.NullString{String: region, Valid: true}); !errors.Is(err, ErrDataResidency) {
+		t.Fatalf("expected configuration check to reject the webhook, got %v", err)
+	}
+
+	WithDestinationRegions(DestinationRegions{Webhooks: "br"})(svc)
+	if !svc.webhooksEnabled(ctx) {
+		t.Fatal("expected webhook events inside the region")
+	}
+	if err := svc.checkDataResidency(ctx, DestinationAuditExport); err != nil {
+		t.Fatalf("expected unused audit export to be ignored, got %v", err)
+	}
+	invalid := "Brazil South"
+	if _, err := validateDataRegion(&invalid); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected invalid region code to be rejected, got %v", err)
+	}
+}
+
+func TestTenantUsageHistorySumsCountersAndPeaksGauges(t *testing.T) {
+	day := func(value string) time.Time {
+		parsed, _ := time.Parse(documentDateLayout, value)
+		return parsed
+	}
+	rows := []repository.UsageDailyRollup{
+		{Metric: MeterActiveClinics, Day: day("2026-03-01"), Quantity: 4},
+		{Metric: MeterAPICalls, Day: day("2026-03-01"), Quantity: 120},
+		{Metric: MeterActiveClinics, Day: day("2026-03-02"), Quantity: 3},
+		{Metric: MeterAPICalls, Day: day("2026-03-02"), Quantity: 80},
+		{Metric: MeterSMSSent, Day: day("2026-03-02"), Quantity: 5},
+		{Metric: MeterStorageBytes, Day: day("2026-03-02"), Quantity: 2048},
+	}
+
+	output := mapTenantUsageHistory("org-1", day("2026-03-01"), day("2026-03-31"), rows)
+	if len(output.Days) != 2 || output.Days[1].Day != "2026-03-02" || output.Days[1].SMSSent != 5 {
+		t.Fatalf("unexpected days: %+v", output.Days)
+	}
+	want := UsageTotalsOutput{APICalls: 200, SMSSent: 5, PeakActiveClinics: 4, PeakStorageBytes: 2048}
+	if output.Totals != want {
+		t.Fatalf("expected totals %+v, got %+v", want, output.Totals)
+	}
+
+	meter := newAPICallMeter()
+	meter.add("org-1", 1)
+	meter.add("org-1", 1)
+	if drained := meter.drain(); drained["org-1"] != 2 {
+		t.Fatalf("expected two calls, got %v", drained)
+	}
+	if drained := meter.drain(); len(drained) != 0 {
+		t.Fatalf("expected drain to reset the meter, got %v", drained)
+	}
+}
+
+func TestLoadClinicDetailsDecodesAggregateAndOpensAccountNumbers(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	wrapper, err := keywrap.NewLocal("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	if err != nil {
+		t.Fatalf("create wrapper: %v", err)
+	}
+	mock := mockQuerier{organizationKey: &repository.OrganizationKey{}}
+	store := newDataKeyStore(wrapper, mock, time.Now)
+	sealed, err := store.seal(ctx, DefaultOrganizationID, "123456-7")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	accounts, _ := json.Marshal([]map[string]any{{
+		"id":                  "01a13a20-4e6a-7000-8000-0000000000b1",
+		"bank_code":           "341",
+		"branch_number":       "0001",
+		"account_number":      sealed,
+		"account_type":        "CHECKING",
+		"holder_name":         nil,
+		"is_primary":          true,
+		"verification_status": "PENDING",
+		"verified_at":         "2026-03-01T10:00:00.123456+00:00",
+	}})
+	mock.clinicAggregate = &repository.GetClinicAggregateRow{
+		ClinicID:      "01a13a20-4e6a-7000-8000-0000000000c1",
+		LegalName:     "Clinica Sorriso LTDA",
+		TaxIDNumber:   "11222333000181",
+		Timezone:      "America/Sao_Paulo",
+		AddressStreet: sql.NullString{String: "Rua A", Valid: true},
+		AddressCity:   sql.NullString{String: "Recife", Valid: true},
+		AddressState:  sql.NullString{String: "PE", Valid: true},
+		AddressCep:    sql.NullString{String: "50000000", Valid: true},
+		DentistIds:    json.RawMessage(`["01a13a20-4e6a-7000-8000-0000000000d1"]`),
+		BankAccounts:  accounts,
+	}
+	q := encryptingQuerier{Querier: mock, keys: store}
+
+	clinic, err := loadClinicDetails(ctx, q, mock.clinicAggregate.ClinicID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clinic.DentistIDs) != 1 || clinic.Address == nil || clinic.Address.City != "Recife" || clinic.Registry != nil {
+		t.Fatalf("unexpected clinic: %+v", clinic.ClinicOutput)
+	}
+	if len(clinic.BankAccounts) != 1 || clinic.BankAccounts[0].AccountNumber != maskAccountNumber("123456-7") {
+		t.Fatalf("expected the opened account number masked, got %+v", clinic.BankAccounts)
+	}
+	if clinic.BankAccounts[0].HolderName != nil || clinic.BankAccounts[0].VerifiedAt == nil {
+		t.Fatalf("expected nullable columns decoded, got %+v", clinic.BankAccounts[0])
+	}
+
+	summary, err := loadClinicSummary(ctx, q, mock.clinicAggregate.ClinicID)
+	if err != nil || summary.ID != mock.clinicAggregate.ClinicID {
+		t.Fatalf("unexpected summary %+v, %v", summary, err)
+	}
+	if _, err := loadClinicSummary(ctx, q, "01a13a20-4e6a-7000-8000-0000000000c2"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
+
+func TestLoadBatchSharesConcurrentLookupsAndDeduplicatesKeys(t *testing.T) {
+	svc := &Service{}
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	release := make(chan struct{})
+	var calls atomic.Int32
+	fetch := func(ctx context.Context, keys []string) (map[string]int, error) {
+		calls.Add(1)
+		<-release
+		loaded := make(map[string]int, len(keys))
+		for i, key := range keys {
+			loaded[key] = i
+		}
+		return loaded, nil
+	}
+
+	results := make(chan map[string]int, 2)
+	for _, keys := range [][]string{{"b", "a", "b"}, {"a", "b"}} {
+		go func() {
+			loaded, err := loadBatch(ctx, svc, "test", keys, fetch)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+			results <- loaded
+		}()
+	}
+	for calls.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	// Give the second caller time to join the flight already in progress.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	for range 2 {
+		if loaded := <-results; len(loaded) != 2 || loaded["a"] != 0 || loaded["b"] != 1 {
+			t.Fatalf("unexpected batch %v", loaded)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("expected one shared fetch, got %d", calls.Load())
+	}
+
+	loaded, err := loadBatch(ctx, svc, "test", nil, func(ctx context.Context, keys []string) (map[string]int, error) {
+		t.Fatal("expected no fetch without keys")
+		return nil, nil
+	})
+	if err != nil || len(loaded) != 0 {
+		t.Fatalf("expected an empty batch, got %v, %v", loaded, err)
+	}
+}
+
+type recordingInvalidationBus struct {
+	keys []string
+}
+
+func (b *recordingInvalidationBus) Publish(ctx context.Context, key string) error {
+	b.keys = append(b.keys, key)
+	return nil
+}
+
+func TestReferenceCacheServesLoadedValuesUntilInvalidated(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	bus := &recordingInvalidationBus{}
+	svc := &Service{now: func() time.Time { return now }}
+	WithReferenceCache(1, time.Minute, bus)(svc)
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+
+	calls := 0
+	load := func(ctx context.Context) (int, error) {
+		calls++
+		return calls, nil
+	}
+	key := referenceKey(ctx, referenceSpecialties)
+	for range 2 {
+		if value, err := cachedReference(ctx, svc, key, load); err != nil || value != 1 {
+			t.Fatalf("expected the first load to be served, got %d, %v", value, err)
+		}
+	}
+
+	svc.invalidateReference(ctx, key)
+	if len(bus.keys) != 1 || bus.keys[0] != key {
+		t.Fatalf("expected the invalidation to be published, got %v", bus.keys)
+	}
+	if value, _ := cachedReference(ctx, svc, key, load); value != 2 {
+		t.Fatalf("expected a reload after invalidation, got %d", value)
+	}
+
+	now = now.Add(time.Minute)
+	if value, _ := cachedReference(ctx, svc, key, load); value != 3 {
+		t.Fatalf("expected a reload after the ttl, got %d", value)
+	}
+
+	if _, err := cachedReference(ctx, svc, referenceKey(ctx, referenceClinicSettings, "clinic"), load); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if value, _ := cachedReference(ctx, svc, key, load); value != 5 {
+		t.Fatalf("expected the least recently used entry to be evicted, got %d", value)
+	}
+
+	stale := func(ctx context.Context) (int, error) {
+		svc.InvalidateReferenceCache("")
+		return 0, nil
+	}
+	if _, err := cachedReference(ctx, svc, "racing", stale); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, ok := svc.referenceCache.get("racing", now); ok {
+		t.Fatal("expected a value loaded across an invalidation not to be cached")
+	}
+}
+
+func TestEncryptingQuerierSealsPayoutBatchFiles(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	wrapper, err := keywrap.NewLocal("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	if err != nil {
+		t.Fatalf("create wrapper: %v", err)
+	}
+	var stored []byte
+	mock := mockQuerier{organizationKey: &repository.OrganizationKey{}, payoutFile: &stored}
+	q := encryptingQuerier{Querier: mock, keys: newDataKeyStore(wrapper, mock, time.Now)}
+	content := []byte("34100000      081211222333000181 0001 123456-7 CLINICA SORRISO LTDA")
+
+	if err := q.CompletePayoutBatch(ctx, repository.CompletePayoutBatchParams{
+		ID:             "01a13a20-4e6a-7000-8000-0000000000b1",
+		OrganizationID: DefaultOrganizationID,
+		FileContent:    content,
+	}); err != nil {
+		t.Fatalf("complete batch: %v", err)
+	}
+	if !strings.HasPrefix(string(stored), encryptedFieldPrefix) || strings.Contains(string(stored), "123456-7") {
+		t.Fatalf("expected the stored file sealed, got %q", stored)
+	}
+	file, err := q.GetPayoutBatchFile(ctx, repository.GetPayoutBatchFileParams{ID: "01a13a20-4e6a-7000-8000-0000000000b1", OrganizationID: DefaultOrganizationID})
+	if err != nil {
+		t.Fatalf("get batch file: %v", err)
+	}
+	if string(file.FileContent) != string(content) {
+		t.Fatalf("expected the file opened on read, got %q", file.FileContent)
+	}
+
+	stored = content
+	if file, err = q.GetPayoutBatchFile(ctx, repository.GetPayoutBatchFileParams{ID: "01a13a20-4e6a-7000-8000-0000000000b1", OrganizationID: DefaultOrganizationID}); err != nil || string(file.FileContent) != string(content) {
+		t.Fatalf("expected a file written before encryption read as is, got %q, %v", file.FileContent, err)
+	}
+}
+
+func TestOpenExportedColumnOpensSealedPayoutFiles(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	wrapper, err := keywrap.NewLocal("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	if err != nil {
+		t.Fatalf("create wrapper: %v", err)
+	}
+	mock := mockQuerier{organizationKey: &repository.OrganizationKey{}}
+	svc := &Service{dataKeys: newDataKeyStore(wrapper, mock, time.Now)}
+	sealed, err := svc.dataKeys.seal(ctx, DefaultOrganizationID, "0001 123456-7")
+	if err != nil {
+		t.Fatalf("seal: %v", err)
+	}
+	rows, _ := json.Marshal([]map[string]any{{"file_content": `\x` + hex.EncodeToString([]byte(sealed))}})
+
+	opened, err := svc.openExportedColumn(ctx, DefaultOrganizationID, rows, "file_content")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if want := `"\\x` + hex.EncodeToString([]byte("0001 123456-7")) + `"`; !strings.Contains(string(opened), want) {
+		t.Fatalf("expected %s in the export, got %s", want, opened)
+	}
+}
+
+func TestCreateBankAccountsInsertsEveryAccountInOneStatement(t *testing.T) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	wrapper, err := keywrap.NewLocal("MDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3ODlhYmNkZWY=")
+	if err != nil {
+		t.Fatalf("create wrapper: %v", err)
+	}
+	var inserts []any
+	mock := mockQuerier{organizationKey: &repository.OrganizationKey{}, bulkInserts: &inserts}
+	store := newDataKeyStore(wrapper, mock, time.Now)
+	q := encryptingQuerier{Querier: mock, keys: store}
+	owner := repository.GetClinicDetailsRow{
+		ClinicID:    "01a13a20-4e6a-7000-8000-0000000000c1",
+		LegalName:   "Clinica Sorriso LTDA",
+		TaxIDNumber: "11222333000181",
+	}
+
+	err = createBankAccounts(ctx, q, owner, []BankAccountInput{
+		{BankCode: "341", BranchNumber: "0001", AccountNumber: " 123456-7 ", AccountType: "checking", IsPrimary: true},
+		{BankCode: "001", BranchNumber: "1234", AccountNumber: "98765-4", AccountType: "savings"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(inserts) != 2 {
+		t.Fatalf("expected one bank account insert and one audit insert, got %d", len(inserts))
+	}
+	accounts := inserts[0].(repository.CreateBankAccountsParams)
+	if len(accounts.Ids) != 2 || accounts.ClinicID != owner.ClinicID || !slices.Equal(accounts.IsPrimary, []bool{true, false}) {
+		t.Fatalf("unexpected bank account rows %+v", accounts)
+	}
+	if accounts.HolderNames[1] != owner.LegalName || accounts.HolderTaxIds[1] != owner.TaxIDNumber {
+		t.Fatalf("expected the clinic to hold accounts without an explicit holder, got %+v", accounts)
+	}
+	for i, want := range []string{"123456-7", "98765-4"} {
+		if accounts.AccountNumbers[i] == want {
+			t.Fatalf("expected account number %d to be sealed", i)
+		}
+		if opened, err := store.open(ctx, DefaultOrganizationID, accounts.AccountNumbers[i]); err != nil || opened != want {
+			t.Fatalf("expected account number %q, got %q, %v", want, opened, err)
+		}
+	}
+	audits := inserts[1].(repository.CreateAuditLogsParams)
+	if !audits.ClinicID.Valid || audits.ClinicID.UUID.String() != owner.ClinicID || !slices.Equal(audits.EntityIds, accounts.Ids) {
+		t.Fatalf("expected one audit row per account, got %+v", audits)
+	}
+}
+
+func BenchmarkAuthenticateAccessToken(b *testing.B) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("secret123"), bcrypt.MinCost)
+	if err != nil {
+		b.Fatalf("generate password hash: %v", err)
 	}
-	id, err := uuid.NewV7()
+	svc := newAuthServiceForTest(mockQuerier{
+		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
+			return repository.User{
+				ID:             "01a13a20-4e6a-7000-8000-0000000000a1",
+				OrganizationID: DefaultOrganizationID,
+				Email:          email,
+				PasswordHash:   string(hash),
+				Role:           UserRoleAdmin,
+			}, nil
+		},
+	})
+	output, err := svc.Login(context.Background(), LoginInput{Email: "admin@example.com", Password: "secret123"})
 	if err != nil {
-		t.Fatalf("new uuidv7: %v", err)
+		b.Fatalf("login: %v", err)
 	}
-	userID := id.String()
 
-	q := mockQuerier{
-		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
-			if email != "admin@example.com" {
-				return repository.User{}, sql.ErrNoRows
-			}
-			return repository.User{ID: userID, Email: "admin@example.com", PasswordHash: string(hash)}, nil
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := svc.AuthenticateAccessToken(output.AccessToken); err != nil {
+			b.Fatalf("authenticate access token: %v", err)
+		}
+	}
+}
+
+func BenchmarkLoadClinicDetails(b *testing.B) {
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	accounts := make([]map[string]any, 0, 5)
+	for i := range 5 {
+		accounts = append(accounts, map[string]any{
+			"id":                  "01a13a20-4e6a-7000-8000-0000000000b" + string(rune('0'+i)),
+			"bank_code":           "341",
+			"branch_number":       "0001",
+			"account_number":      "12345" + string(rune('0'+i)) + "-7",
+			"account_type":        "CHECKING",
+			"holder_name":         nil,
+			"is_primary":          i == 0,
+			"verification_status": "VERIFIED",
+			"verified_at":         "2026-03-01T10:00:00.123456+00:00",
+		})
+	}
+	encoded, _ := json.Marshal(accounts)
+	mock := mockQuerier{clinicAggregate: &repository.GetClinicAggregateRow{
+		ClinicID:      "01a13a20-4e6a-7000-8000-0000000000c1",
+		LegalName:     "Clinica Sorriso LTDA",
+		TaxIDNumber:   "11222333000181",
+		Timezone:      "America/Sao_Paulo",
+		AddressStreet: sql.NullString{String: "Rua A", Valid: true},
+		AddressCity:   sql.NullString{String: "Recife", Valid: true},
+		AddressState:  sql.NullString{String: "PE", Valid: true},
+		AddressCep:    sql.NullString{String: "50000000", Valid: true},
+		DentistIds:    json.RawMessage(`["01a13a20-4e6a-7000-8000-0000000000d1","01a13a20-4e6a-7000-8000-0000000000d2"]`),
+		BankAccounts:  encoded,
+	}}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := loadClinicDetails(ctx, mock, mock.clinicAggregate.ClinicID); err != nil {
+			b.Fatalf("load clinic details: %v", err)
+		}
+	}
+}
+
+type stubNotificationSender struct {
+	failFor map[string]bool
+	sent    []NotificationMessage
+}
+
+func (s *stubNotificationSender) Send(ctx context.Context, message NotificationMessage) error {
+	if s.failFor[message.ID] {
+		return errors.New("provider unavailable")
+	}
+	s.sent = append(s.sent, message)
+	return nil
+}
+
+func TestDeliverDueNotificationsBacksOffAndGivesUp(t *testing.T) {
+	now := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	var writes []any
+	svc := newAuthServiceForTest(mockQuerier{
+		notificationWrites: &writes,
+		dueNotifications: []repository.Notification{
+			{ID: "delivered", Channel: NotificationChannelEmail, Recipient: "clinica@example.com", Attempts: 1},
+			{ID: "retried", Channel: NotificationChannelEmail, Recipient: "clinica@example.com", Attempts: 3},
+			{ID: "exhausted", Channel: NotificationChannelEmail, Recipient: "clinica@example.com", Attempts: notificationMaxAttempts},
+			{ID: "unrouted", Channel: NotificationChannelWhatsApp, Recipient: "+5511987654321", Attempts: 1},
 		},
+	})
+	svc.now = func() time.Time { return now }
+	sender := &stubNotificationSender{failFor: map[string]bool{"retried": true, "exhausted": true}}
+	WithNotificationSender(NotificationChannelEmail, sender)(svc)
+
+	sent, err := svc.DeliverDueNotifications(WithOrganization(context.Background(), DefaultOrganizationID))
+	if err != nil {
+		t.Fatalf("deliver notifications: %v", err)
 	}
+	if sent != 1 || len(sender.sent) != 1 || sender.sent[0].ID != "delivered" {
+		t.Fatalf("expected only the healthy notification to go out, got %d %+v", sent, sender.sent)
+	}
+	failures := map[string]repository.MarkNotificationAttemptFailedParams{}
+	for _, write := range writes {
+		if failed, ok := write.(repository.MarkNotificationAttemptFailedParams); ok {
+			failures[failed.ID] = failed
+		}
+	}
+	if retried := failures["retried"]; retried.Status != NotificationPending || !retried.NextAttemptAt.Equal(now.Add(4*time.Minute)) {
+		t.Fatalf("expected the third failure to retry in four minutes, got %+v", retried)
+	}
+	if exhausted := failures["exhausted"]; exhausted.Status != NotificationFailed {
+		t.Fatalf("expected the last attempt to give up, got %+v", exhausted)
+	}
+	if unrouted := failures["unrouted"]; unrouted.Status != NotificationPending || !unrouted.LastError.Valid {
+		t.Fatalf("expected a channel without a sender to be retried later, got %+v", unrouted)
+	}
+}
+
+func TestEnqueueNotificationRendersTemplateForConfiguredChannels(t *testing.T) {
+	var writes []any
+	q := mockQuerier{notificationWrites: &writes}
 	svc := newAuthServiceForTest(q)
+	WithNotificationSender(NotificationChannelEmail, &stubNotificationSender{})(svc)
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	data := payoutReceiptData{ClinicName: "Sorriso", FileSequence: 7, PaymentDate: "02/03/2026", Amount: formatBRL(123456789), PayableCount: 3}
 
-	output, err := svc.Login(context.Background(), LoginInput{Email: "admin@example.com", Password: "secret123"})
+	for _, channel := range []string{NotificationChannelEmail, NotificationChannelSMS} {
+		if err := svc.enqueueNotification(ctx, q, notificationRequest{
+			Channel:   channel,
+			Template:  NotificationTemplatePayoutReceipt,
+			Recipient: "clinica@example.com",
+			DedupeKey: "receipt:" + channel,
+			Data:      data,
+		}); err != nil {
+			t.Fatalf("enqueue %s: %v", channel, err)
+		}
+	}
+
+	if len(writes) != 1 {
+		t.Fatalf("expected only the configured channel to be enqueued, got %+v", writes)
+	}
+	created := writes[0].(repository.CreateNotificationParams)
+	if created.Subject != "Comprovante de repasse nº 7" || !strings.Contains(created.Body, "Valor: R$ 1.234.567,89") {
+		t.Fatalf("unexpected rendered notification: %q %q", created.Subject, created.Body)
+	}
+}
+
+func TestEnqueueNotificationSkipsSuppressedRecipients(t *testing.T) {
+	var writes []any
+	q := mockQuerier{notificationWrites: &writes, suppressedRecipients: map[string]bool{"EMAIL:clinica@example.com": true}}
+	svc := newAuthServiceForTest(q)
+	WithNotificationSender(NotificationChannelEmail, &stubNotificationSender{})(svc)
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	data := payoutReceiptData{ClinicName: "Sorriso", FileSequence: 7, PaymentDate: "02/03/2026", Amount: formatBRL(100), PayableCount: 1}
+
+	for _, recipient := range []string{" Clinica@Example.com", "financeiro@example.com"} {
+		if err := svc.enqueueNotification(ctx, q, notificationRequest{
+			Channel:   NotificationChannelEmail,
+			Template:  NotificationTemplatePayoutReceipt,
+			Recipient: recipient,
+			ClinicID:  "0196a5b2-8f3e-7c41-9d2a-5b6c7d8e9f01",
+			DedupeKey: "receipt:" + recipient,
+			Data:      data,
+		}); err != nil {
+			t.Fatalf("enqueue %s: %v", recipient, err)
+		}
+	}
+
+	if len(writes) != 1 {
+		t.Fatalf("expected the hard-bounced address to be skipped, got %+v", writes)
+	}
+	created := writes[0].(repository.CreateNotificationParams)
+	if created.Recipient != "financeiro@example.com" || !created.ClinicID.Valid {
+		t.Fatalf("expected the clinic's other address to be enqueued for its report, got %+v", created)
+	}
+
+	bounceType := "transient"
+	err := svc.RecordNotificationDelivery(ctx, NotificationDeliveryCallbackInput{
+		NotificationID: created.ID,
+		Status:         NotificationBounced,
+		BounceType:     &bounceType,
+	})
+	if !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected an unknown bounce type to be rejected, got %v", err)
+	}
+}
+
+func TestEnqueueAnnouncementUsesPreferredChannels(t *testing.T) {
+	var writes []any
+	q := mockQuerier{notificationWrites: &writes}
+	svc := newAuthServiceForTest(q)
+	for _, channel := range []string{NotificationChannelEmail, NotificationChannelSMS, NotificationChannelWhatsApp} {
+		WithNotificationSender(channel, &stubNotificationSender{})(svc)
+	}
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	members := []repository.ListClinicAnnouncementAudienceRow{
+		{DentistID: "default", LegalName: "Ana", Email: sql.NullString{String: "ana@example.com", Valid: true}, Phone: sql.NullString{String: "(11) 98765-4321", Valid: true}},
+		{DentistID: "whatsapp", LegalName: "Bruno", Email: sql.NullString{String: "bruno@example.com", Valid: true}, Phone: sql.NullString{String: "11987654322", Valid: true}, PreferredChannels: []string{NotificationChannelWhatsApp}},
+		{DentistID: "no-phone", LegalName: "Carla", PreferredChannels: []string{NotificationChannelSMS}},
+	}
+
+	for _, member := range members {
+		data := clinicAnnouncementData{ClinicName: "Sorriso", DentistName: member.LegalName, Title: "Plantão de sábado", Body: "A clínica abre das 8h às 12h."}
+		if err := svc.enqueueAnnouncement(ctx, q, "announcement", "clinic", member, data); err != nil {
+			t.Fatalf("enqueue announcement for %s: %v", member.DentistID, err)
+		}
+	}
+
+	got := map[string]repository.CreateNotificationParams{}
+	for _, write := range writes {
+		created := write.(repository.CreateNotificationParams)
+		got[created.Channel+":"+created.Recipient] = created
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected one message per reachable preferred channel, got %+v", writes)
+	}
+	if email, ok := got["EMAIL:ana@example.com"]; !ok || email.Subject != "Sorriso: Plantão de sábado" {
+		t.Fatalf("expected dentists without preferences to get email, got %+v", got)
+	}
+	if whatsApp, ok := got["WHATSAPP:+5511987654322"]; !ok || !strings.Contains(whatsApp.Body, "A clínica abre das 8h às 12h.") {
+		t.Fatalf("expected the WhatsApp preference to replace email, got %+v", got)
+	}
+}
+
+func TestApplyInventoryMovementKeepsStockNonNegative(t *testing.T) {
+	var writes []any
+	q := mockQuerier{inventoryWrites: &writes}
+	ctx := WithOrganization(context.Background(), DefaultOrganizationID)
+	item := repository.InventoryItem{ID: "item", ClinicID: "clinic", Unit: "tubete", Quantity: 4}
+
+	if _, err := applyInventoryMovement(ctx, q, item, InventoryConsumption, 5, sql.NullInt64{}, nil); !errors.Is(err, ErrConflict) {
+		t.Fatalf("expected consuming more than the stock to conflict, got %v", err)
+	}
+	if len(writes) != 0 {
+		t.Fatalf("expected a refused movement to write nothing, got %+v", writes)
+	}
+
+	notes := "contagem de sexta"
+	movement, err := applyInventoryMovement(ctx, q, item, InventoryAdjustment, 1, sql.NullInt64{}, &notes)
 	if err != nil {
-		t.Fatalf("login: %v", err)
+		t.Fatalf("adjust stock: %v", err)
 	}
-	if output.AccessToken == "" {
-		t.Fatalf("expected access token")
+	if movement.QuantityDelta != -3 || movement.QuantityAfter != 1 {
+		t.Fatalf("expected an adjustment to record the difference to the counted stock, got %+v", movement)
 	}
-	if output.TokenType != "Bearer" {
-		t.Fatalf("expected token type Bearer, got %q", output.TokenType)
+	if set := writes[0].(repository.SetInventoryItemQuantityParams); set.Quantity != 1 {
+		t.Fatalf("expected the item quantity to follow the movement, got %+v", set)
 	}
+}
 
-	if err := svc.ValidateAccessToken(output.AccessToken); err != nil {
-		t.Fatalf("validate access token: %v", err)
+func TestPurchaseOrderReceiptStatusWaitsForEveryLine(t *testing.T) {
+	items := []repository.ListPurchaseOrderItemsRow{
+		{ID: "resin", QuantityOrdered: 10, QuantityReceived: 10},
+		{ID: "gloves", QuantityOrdered: 5, QuantityReceived: 2},
+	}
+	if status := purchaseOrderReceiptStatus(items); status != PurchaseOrderPartiallyReceived {
+		t.Fatalf("expected an order with outstanding lines to stay partially received, got %s", status)
+	}
+
+	items[1].QuantityReceived = 5
+	if status := purchaseOrderReceiptStatus(items); status != PurchaseOrderReceived {
+		t.Fatalf("expected an order with every line delivered to be received, got %s", status)
 	}
 }
 
-func TestLoginInvalidCredentials(t *testing.T) {
-	q := mockQuerier{
-		getUserByEmailFn: func(ctx context.Context, email string) (repository.User, error) {
-			return repository.User{}, sql.ErrNoRows
+func TestNextMaintenanceScheduleFollowsIntervalButNotRepairs(t *testing.T) {
+	lastService := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	equipment := repository.Equipment{
+		MaintenanceIntervalDays: sql.NullInt32{Int32: 90, Valid: true},
+		LastMaintainedAt:        sql.NullTime{Time: lastService, Valid: true},
+		NextMaintenanceDue:      sql.NullTime{Time: lastService.AddDate(0, 0, 90), Valid: true},
+	}
+
+	performedAt := time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC)
+	last, due := nextMaintenanceSchedule(equipment, MaintenancePreventive, performedAt, sql.NullTime{})
+	if !last.Time.Equal(performedAt) || !due.Time.Equal(performedAt.AddDate(0, 0, 90)) {
+		t.Fatalf("expected a preventive service to restart the interval, got last %v due %v", last.Time, due.Time)
+	}
+
+	last, due = nextMaintenanceSchedule(equipment, MaintenanceCorrective, performedAt, sql.NullTime{})
+	if !last.Time.Equal(lastService) || !due.Time.Equal(equipment.NextMaintenanceDue.Time) {
+		t.Fatalf("expected a repair to keep the periodic schedule, got last %v due %v", last.Time, due.Time)
+	}
+
+	backdated := time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC)
+	last, due = nextMaintenanceSchedule(equipment, MaintenanceCalibration, backdated, sql.NullTime{})
+	if !last.Time.Equal(lastService) || !due.Time.Equal(equipment.NextMaintenanceDue.Time) {
+		t.Fatalf("expected a backdated record not to pull the schedule back, got last %v due %v", last.Time, due.Time)
+	}
+}
+
+func TestShiftWithinOperatingHoursNeedsOneInterval(t *testing.T) {
+	hours := []repository.ClinicOperatingHour{
+		{Weekday: 1, OpensMinute: 8 * 60, ClosesMinute: 12 * 60},
+		{Weekday: 1, OpensMinute: 13 * 60, ClosesMinute: 18 * 60},
+	}
+	monday := func(hour, minute int) time.Time { return time.Date(2026, 6, 8, hour, minute, 0, 0, time.UTC) }
+
+	if !shiftWithinOperatingHours(hours, monday(8, 0), monday(12, 0)) {
+		t.Fatal("expected a morning shift to fit the morning interval")
+	}
+	if shiftWithinOperatingHours(hours, monday(8, 0), monday(18, 0)) {
+		t.Fatal("expected a shift through the lunch break to be outside operating hours")
+	}
+	if shiftWithinOperatingHours(hours, monday(8, 0).AddDate(0, 0, 1), monday(12, 0).AddDate(0, 0, 1)) {
+		t.Fatal("expected a tuesday shift to be outside monday hours")
+	}
+}
+
+func TestParseISOWeekReturnsMonday(t *testing.T) {
+	monday, err := parseISOWeek("2026-W01")
+	if err != nil {
+		t.Fatalf("parse week: %v", err)
+	}
+	if want := time.Date(2025, time.December, 29, 0, 0, 0, 0, time.UTC); !monday.Equal(want) {
+		t.Fatalf("expected 2026-W01 to start on %s, got %s", want.Format(documentDateLayout), monday.Format(documentDateLayout))
+	}
+	if _, err := parseISOWeek("2027-W53"); err == nil {
+		t.Fatal("expected 2027-W53 to be rejected, 2027 has 52 ISO weeks")
+	}
+}
+
+func TestPunchVerificationAcceptsGeofenceOrAllowedNetwork(t *testing.T) {
+	settings := repository.ClinicTimeClockSetting{
+		Latitude:        sql.NullFloat64{Float64: -23.5614, Valid: true},
+		Longitude:       sql.NullFloat64{Float64: -46.6559, Valid: true},
+		RadiusMeters:    sql.NullInt32{Int32: 150, Valid: true},
+		AllowedNetworks: []string{"200.150.10.0/24"},
+	}
+	point := func(latitude, longitude float64, ip string) TimeClockPunchInput {
+		return TimeClockPunchInput{Latitude: &latitude, Longitude: &longitude, IP: ip}
+	}
+
+	distance, verified := punchVerification(settings, point(-23.5620, -46.6559, "10.0.0.8"))
+	if !verified.Bool || !distance.Valid || distance.Float64 > 150 {
+		t.Fatalf("expected a punch about 67m away to be verified, got distance %v verified %v", distance, verified)
+	}
+	if _, verified := punchVerification(settings, point(-23.5700, -46.6559, "200.150.10.42")); !verified.Bool {
+		t.Fatal("expected a punch from an allowed network to be verified outside the geofence")
+	}
+	if _, verified := punchVerification(settings, point(-23.5700, -46.6559, "10.0.0.8")); !verified.Valid || verified.Bool {
+		t.Fatal("expected a punch outside the geofence from another network to be unverified")
+	}
+	if _, verified := punchVerification(repository.ClinicTimeClockSetting{}, point(-23.5700, -46.6559, "10.0.0.8")); verified.Valid {
+		t.Fatal("expected no verification when the clinic has no rules")
+	}
+}
+
+func TestApplyTaskStatusStampsAndClearsCompletion(t *testing.T) {
+	now := time.Date(2026, 6, 8, 15, 0, 0, 0, time.UTC)
+	svc := &Service{now: func() time.Time { return now }}
+	task := repository.ClinicTask{Status: TaskStatusOpen}
+
+	params := taskUpdateParams(task)
+	if err := svc.applyTaskStatus(&params, task, "done"); err != nil {
+		t.Fatalf("apply status: %v", err)
+	}
+	if params.Status != TaskStatusDone || !params.CompletedAt.Time.Equal(now) {
+		t.Fatalf("expected DONE completed at %s, got %s at %v", now, params.Status, params.CompletedAt)
+	}
+
+	closed := repository.ClinicTask{Status: TaskStatusDone, CompletedAt: sql.NullTime{Time: now.Add(-time.Hour), Valid: true}}
+	params = taskUpdateParams(closed)
+	if err := svc.applyTaskStatus(&params, closed, TaskStatusDone); err != nil {
+		t.Fatalf("apply status: %v", err)
+	}
+	if !params.CompletedAt.Time.Equal(closed.CompletedAt.Time) {
+		t.Fatal("expected repeating DONE to keep the original completion time")
+	}
+	if err := svc.applyTaskStatus(&params, closed, TaskStatusOpen); err != nil {
+		t.Fatalf("apply status: %v", err)
+	}
+	if params.CompletedAt.Valid {
+		t.Fatal("expected reopening to clear the completion time")
+	}
+	if err := svc.applyTaskStatus(&params, closed, "ARCHIVED"); err == nil {
+		t.Fatal("expected an unknown status to be rejected")
+	}
+}
+
+func TestMapClinicTaskFlagsOverdueOnlyWhileOpen(t *testing.T) {
+	today := time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC)
+	task := repository.ClinicTask{
+		Status:            TaskStatusInProgress,
+		DueDate:           sql.NullTime{Time: today.AddDate(0, 0, -1), Valid: true},
+		AutomationTrigger: sql.NullString{String: TaskTriggerMaintenanceOverdue, Valid: true},
+	}
+	output := mapClinicTask(task, today)
+	if !output.Overdue || output.Source != TaskSourceAutomation {
+		t.Fatalf("expected an overdue automated task, got overdue=%v source=%s", output.Overdue, output.Source)
+	}
+	task.Status = TaskStatusDone
+	if mapClinicTask(task, today).Overdue {
+		t.Fatal("expected a finished task not to be overdue")
+	}
+	task.Status = TaskStatusOpen
+	task.DueDate.Time = today
+	if mapClinicTask(task, today).Overdue {
+		t.Fatal("expected a task due today not to be overdue yet")
+	}
+}
+
+func TestBuildWorkspaceConfigFollowsRoleAndPlan(t *testing.T) {
+	modules := func(config WorkspaceConfigOutput) map[string]bool {
+		enabled := make(map[string]bool, len(config.Modules))
+		for _, module := range config.Modules {
+			enabled[module.Key] = module.Enabled
+		}
+		return enabled
+	}
+
+	admin := buildWorkspaceConfig("clinic-1", UserRoleAdmin, false, PlanBasic)
+	adminModules := modules(admin)
+	if enabled, listed := adminModules["scheduling"]; !listed || enabled {
+		t.Fatal("expected scheduling to be listed but disabled on the basic plan")
+	}
+	if _, listed := adminModules["profile"]; listed {
+		t.Fatal("expected dentist-only modules to be left out for admins")
+	}
+	for _, widget := range admin.Widgets {
+		if widget.Module == "scheduling" || widget.Module == "billing" {
+			t.Fatalf("expected no widget from a module outside the plan, got %s", widget.Key)
+		}
+	}
+	if modules(buildWorkspaceConfig("clinic-1", UserRoleAdmin, false, PlanPro))["scheduling"] != true {
+		t.Fatal("expected scheduling on the pro plan")
+	}
+
+	dentistModules := modules(buildWorkspaceConfig("clinic-1", UserRoleDentist, false, PlanEnterprise))
+	if _, listed := dentistModules["bank_account_changes"]; listed {
+		t.Fatal("expected bank account changes only for clinic admins")
+	}
+	if _, listed := dentistModules["inventory"]; listed {
+		t.Fatal("expected admin modules to be left out for dentists")
+	}
+	if !modules(buildWorkspaceConfig("clinic-1", UserRoleDentist, true, PlanBasic))["bank_account_changes"] {
+		t.Fatal("expected bank account changes for a dentist who is a clinic admin")
+	}
+}
+
+func TestDocumentTemplatesRejectUnknownPlaceholdersAndRenderPDF(t *testing.T) {
+	if err := validateDocumentTemplate("Contrato", DocumentKindContract, "Contrato", "Paciente: {{ patient.nome }}"); err == nil {
+		t.Fatal("expected an unknown placeholder to be rejected")
+	}
+
+	items, err := documentItemValues([]DocumentItemInput{
+		{Description: "Limpeza", UnitPriceCents: 15000},
+		{Description: "Clareamento", Quantity: new(2), UnitPriceCents: 60000},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	body := fillDocumentTemplate("Paciente: {{patient.name}}\n{{ treatment_plan.items }}\nTotal: {{treatment_plan.total}}", map[string]string{
+		"patient.name":         "Maria",
+		"treatment_plan.items": items["treatment_plan.items"],
+		"treatment_plan.total": items["treatment_plan.total"],
+	})
+	want := "Paciente: Maria\n1. Limpeza - 1 x R$ 150,00 = R$ 150,00\n2. Clareamento - 2 x R$ 600,00 = R$ 1.200,00\nTotal: R$ 1.350,00"
+	if body != want {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+
+	long := strings.Repeat("Cláusula com texto longo (ver anexo) que precisa quebrar em várias linhas. ", 400)
+	content, err := renderDocumentPDF("Orçamento", long, time.Date(2026, 10, 14, 12, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte("%PDF-1.4")) || !bytes.HasSuffix(content, []byte("%%EOF\n")) {
+		t.Fatal("expected a complete PDF file")
+	}
+	if !bytes.Contains(content, []byte("/Title (Or\xe7amento)")) {
+		t.Fatal("expected the title in WinAnsi encoding")
+	}
+	if bytes.Contains(content, []byte("/Count 1 ")) {
+		t.Fatal("expected a long body to span several pages")
+	}
+}
+
+func TestDocumentSignersAndCallbackAreNormalized(t *testing.T) {
+	signers, err := normalizeDocumentSigners([]DocumentSignerInput{
+		{Name: " Maria Souza ", Email: " Maria@Example.com "},
+		{Name: "Clínica", Email: "contato@clinica.com.br"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if signers[0] != (SignatureSigner{Name: "Maria Souza", Email: "maria@example.com"}) {
+		t.Fatalf("unexpected signer: %+v", signers[0])
+	}
+	if _, err := normalizeDocumentSigners([]DocumentSignerInput{
+		{Name: "Maria", Email: "maria@example.com"},
+		{Name: "Outra Maria", Email: "MARIA@example.com"},
+	}); err == nil {
+		t.Fatal("expected a duplicated signer email to be rejected")
+	}
+
+	reference, status, err := normalizeSignatureCallback(DocumentSignatureCallbackInput{Reference: " env-1 ", Status: "signed"})
+	if err != nil || reference != "env-1" || status != SignatureStatusSigned {
+		t.Fatalf("unexpected callback: %q %q %v", reference, status, err)
+	}
+	if _, _, err := normalizeSignatureCallback(DocumentSignatureCallbackInput{Reference: "env-1", Status: SignatureStatusPending}); err == nil {
+		t.Fatal("expected PENDING to be rejected as a callback status")
+	}
+}
+
+func TestLegalHoldKeepsRetentionCandidatesAndValidatesRules(t *testing.T) {
+	held := mapRetentionCandidate(repository.ListRetentionCandidatesRow{ResourceType: DeletedResourceDentist, ID: "dentist", RetentionReason: "USER_ACTIVITY", OnLegalHold: true})
+	if held.Action != RetentionActionHold || !held.OnLegalHold || held.RetentionReason == nil || *held.RetentionReason != "USER_ACTIVITY" {
+		t.Fatalf("expected held dentist to be kept with its retention reason, got %+v", held)
+	}
+
+	svc := &Service{now: time.Now}
+	ctx := WithPrincipal(context.Background(), Principal{UserID: "user"})
+	if _, err := svc.SetDocumentRetentionRule(ctx, "invoice", DocumentRetentionRuleInput{RetentionDays: 30}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown document kind to be rejected, got %v", err)
+	}
+	if _, err := svc.SetDocumentRetentionRule(ctx, "contract", DocumentRetentionRuleInput{RetentionDays: maxDocumentRetentionDays + 1}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected retention_days above the limit to be rejected, got %v", err)
+	}
+	if _, err := svc.PlaceLegalHold(ctx, PlaceLegalHoldInput{ResourceType: "PATIENT", ResourceID: "0192f0c1-0000-7000-8000-000000000001", Reason: "dispute"}); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected unknown resource type to be rejected, got %v", err)
+	}
+}
+
+type memoryAttachmentStore map[string]Attachment
+
+func (m memoryAttachmentStore) PutAttachment(ctx context.Context, key string, contentType string, data []byte) error {
+	m[key] = Attachment{ContentType: contentType, Data: data}
+	return nil
+}
+
+func (m memoryAttachmentStore) GetAttachment(ctx context.Context, key string) (Attachment, bool, error) {
+	attachment, ok := m[key]
+	return attachment, ok, nil
+}
+
+func (m memoryAttachmentStore) DeleteAttachment(ctx context.Context, key string) error {
+	delete(m, key)
+	return nil
+}
+
+type failingAttachmentScanner struct{}
+
+func (failingAttachmentScanner) ScanAttachment(ctx context.Context, data []byte) (AttachmentScanResult, error) {
+	return AttachmentScanResult{}, errors.New("clamd unavailable")
+}
+
+func TestQuarantinedAttachmentsRetryThenGetRejected(t *testing.T) {
+	store := memoryAttachmentStore{
+		"quarantine/retry":     {Data: []byte("photo")},
+		"quarantine/exhausted": {Data: []byte("photo")},
+	}
+	var writes []any
+	svc := &Service{
+		now:               time.Now,
+		attachments:       store,
+		attachmentScanner: failingAttachmentScanner{},
+		queries: mockQuerier{
+			attachmentScanWrites: &writes,
+			dueAttachmentScans: []repository.AttachmentScan{
+				{ID: "retry", QuarantineKey: "quarantine/retry", Attempts: 1},
+				{ID: "exhausted", QuarantineKey: "quarantine/exhausted", Attempts: attachmentScanMaxAttempts},
+				{ID: "missing", QuarantineKey: "quarantine/missing", Attempts: 1},
+			},
 		},
 	}
-	svc := newAuthServiceForTest(q)
 
-	_, err := svc.Login(context.Background(), LoginInput{Email: "wrong@example.com", Password: "invalid-password"})
-	if !errors.Is(err, ErrUnauthorized) {
-		t.Fatalf("expected ErrUnauthorized, got: %v", err)
+	scanned, err := svc.ScanQuarantinedAttachments(context.Background())
+	if err != nil || scanned != 3 {
+		t.Fatalf("expected 3 scans handled, got %d (%v)", scanned, err)
+	}
+	if len(writes) != 3 {
+		t.Fatalf("expected 3 scan writes, got %+v", writes)
+	}
+	if retry, ok := writes[0].(repository.RescheduleAttachmentScanParams); !ok || retry.ID != "retry" || !retry.FailureReason.Valid {
+		t.Fatalf("expected the first failure to be rescheduled, got %+v", writes[0])
+	}
+	for i, id := range []string{"exhausted", "missing"} {
+		rejected, ok := writes[i+1].(repository.CompleteAttachmentScanParams)
+		if !ok || rejected.ID != id || rejected.Status != AttachmentScanRejected {
+			t.Fatalf("expected %s to be rejected, got %+v", id, writes[i+1])
+		}
+	}
+	if _, ok := store["quarantine/retry"]; !ok {
+		t.Fatal("expected the file awaiting a retry to stay in quarantine")
+	}
+	if _, ok := store["quarantine/exhausted"]; ok {
+		t.Fatal("expected the rejected file to be deleted")
+	}
+}
+
+func TestGenerateDentistPhotoThumbnailsTracksPhotoVersion(t *testing.T) {
+	var encoded bytes.Buffer
+	if err := jpeg.Encode(&encoded, image.NewRGBA(image.Rect(0, 0, dentistPhotoSize, dentistPhotoSize)), nil); err != nil {
+		t.Fatalf("encode source jpeg: %v", err)
+	}
+	photoUpdatedAt := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	store := memoryAttachmentStore{"dentists/dentist/photo.jpg": {Data: encoded.Bytes()}}
+	var writes []any
+	svc := &Service{
+		attachments: store,
+		queries: mockQuerier{
+			thumbnailWrites: &writes,
+			photosNeedingThumbnail: []repository.ListDentistsNeedingPhotoThumbnailRow{
+				{ID: "dentist", PhotoKey: "dentists/dentist/photo.jpg", PhotoUpdatedAt: photoUpdatedAt},
+				{ID: "gone", PhotoKey: "dentists/gone/photo.jpg", PhotoUpdatedAt: photoUpdatedAt},
+			},
+		},
+	}
+
+	generated, err := svc.GenerateDentistPhotoThumbnails(context.Background())
+	if err != nil || generated != 1 {
+		t.Fatalf("expected one thumbnail, got %d (%v)", generated, err)
+	}
+	thumbnail, ok := store["dentists/dentist/photo-thumbnail.jpg"]
+	if !ok {
+		t.Fatal("expected the thumbnail to be stored")
+	}
+	config, err := jpeg.DecodeConfig(bytes.NewReader(thumbnail.Data))
+	if err != nil || config.Width != dentistThumbnailSize || config.Height != dentistThumbnailSize {
+		t.Fatalf("unexpected thumbnail: %+v (%v)", config, err)
+	}
+	if len(writes) != 1 {
+		t.Fatalf("expected one thumbnail update, got %+v", writes)
+	}
+	update := writes[0].(repository.UpdateDentistPhotoThumbnailParams)
+	if update.ID != "dentist" || !update.PhotoUpdatedAt.Equal(photoUpdatedAt) {
+		t.Fatalf("expected the thumbnail to be tied to the photo version, got %+v", update)
+	}
+}
+
+func TestGenerateDueReportsStoresWorkbookAndFailsMissingClinic(t *testing.T) {
+	store := memoryAttachmentStore{}
+	var writes []any
+	svc := &Service{
+		now:         time.Now,
+		attachments: store,
+		queries: mockQuerier{
+			reportWrites: &writes,
+			getClinicByIDFn: func(ctx context.Context, id string) (repository.Clinic, error) {
+				if id != "clinic" {
+					return repository.Clinic{}, sql.ErrNoRows
+				}
+				return repository.Clinic{ID: id}, nil
+			},
+			lowStockItems: []repository.ListLowStockInventoryItemsRow{
+				{ID: "gloves", Name: "Luvas <M>", Unit: "box", Quantity: 3, MinimumQuantity: 10, RecentConsumption: 9},
+			},
+			dueReports: []repository.Report{
+				{ID: "stock", ClinicID: "clinic", ReportType: ReportTypeLowStock, Format: ReportFormatXLSX, Parameters: []byte(`{}`), Attempts: 1},
+				{ID: "gone", ClinicID: "gone", ReportType: ReportTypeLowStock, Format: ReportFormatCSV, Parameters: []byte(`{}`), Attempts: 1},
+			},
+		},
+	}
+
+	generated, err := svc.GenerateDueReports(context.Background())
+	if err != nil || generated != 2 {
+		t.Fatalf("expected 2 reports handled, got %d (%v)", generated, err)
+	}
+	if len(writes) != 2 {
+		t.Fatalf("expected 2 report writes, got %+v", writes)
+	}
+	completed, ok := writes[0].(repository.CompleteReportParams)
+	if !ok || completed.ID != "stock" || completed.RowCount.Int32 != 1 || completed.AttachmentKey.String != "reports/stock.xlsx" {
+		t.Fatalf("expected the workbook to be completed, got %+v", writes[0])
+	}
+	if failed, ok := writes[1].(repository.FailReportParams); !ok || failed.ID != "gone" || !strings.HasSuffix(failed.FailureReason.String, "clinic not found") {
+		t.Fatalf("expected the report of a missing clinic to fail without a retry, got %+v", writes[1])
+	}
+
+	workbook, err := zip.NewReader(bytes.NewReader(store["reports/stock.xlsx"].Data), int64(len(store["reports/stock.xlsx"].Data)))
+	if err != nil {
+		t.Fatalf("open workbook: %v", err)
+	}
+	var sheet bytes.Buffer
+	for _, file := range workbook.File {
+		if file.Name != "xl/worksheets/sheet1.xml" {
+			continue
+		}
+		reader, err := file.Open()
+		if err != nil {
+			t.Fatalf("open sheet: %v", err)
+		}
+		sheet.ReadFrom(reader)
+		reader.Close()
+	}
+	for _, cell := range []string{`<c r="B2" t="inlineStr"><is><t xml:space="preserve">Luvas &lt;M&gt;</t></is></c>`, `<c r="E2"><v>3</v></c>`, `<c r="G2"><v>7</v></c>`} {
+		if !strings.Contains(sheet.String(), cell) {
+			t.Fatalf("expected sheet to contain %s, got %s", cell, sheet.String())
+		}
+	}
+}
+
+func TestCompileReportDefinitionBindsValuesAndRejectsUnknownFields(t *testing.T) {
+	today := time.Date(2026, time.March, 10, 0, 0, 0, 0, time.UTC)
+	period := "previous_month"
+	value := "consumption"
+	spec := normalizeReportDefinitionSpec(ReportDefinitionSpec{
+		Entity:  "Inventory_Movements",
+		Filters: []ReportFilter{{Field: "kind", Op: "eq", Value: &value}, {Field: "item_id", Op: "in", Values: []string{"0194c1ae-0000-7000-8000-000000000001'); DROP TABLE clinics; --"}}},
+		GroupBy: []string{"month"},
+		Metrics: []string{"quantity_out"},
+		Period:  &period,
+	})
+	if _, err := compileReportDefinition(spec, "org-1", "clinic-1", "America/Sao_Paulo", today); !errors.Is(err, ErrValidation) {
+		t.Fatalf("expected an invalid item_id to be rejected, got %v", err)
+	}
+
+	spec.Filters = spec.Filters[:1]
+	compiled, err := compileReportDefinition(spec, "org-1", "clinic-1", "America/Sao_Paulo", today)
+	if err != nil {
+		t.Fatalf("compile: %v", err)
+	}
+	expected := `-- name: RunReportDefinition :many
+SELECT (to_char(m.created_at AT TIME ZONE $3::text, 'YYYY-MM'))::text AS month,
+       COALESCE(SUM(GREATEST(-m.quantity_delta, 0)), 0)::bigint AS quantity_out
+FROM inventory_movements m
+WHERE m.organization_id = $1::uuid
+  AND m.clinic_id = $2::uuid
+  AND m.kind = $4::text
+  AND (m.created_at AT TIME ZONE $3::text)::date >= $5::date
+  AND (m.created_at AT TIME ZONE $3::text)::date <= $6::date
+GROUP BY 1
+ORDER BY 1
+LIMIT 1001`
+	if compiled.query != expected {
+		t.Fatalf("unexpected query:\n%s", compiled.query)
+	}
+	if !slices.Equal(compiled.args, []any{"org-1", "clinic-1", "America/Sao_Paulo", "CONSUMPTION", "2026-02-01", "2026-02-28"}) {
+		t.Fatalf("unexpected args %v", compiled.args)
+	}
+	if !slices.Equal(compiled.columns, []string{"month", "quantity_out"}) || compiled.dimensions != 1 {
+		t.Fatalf("unexpected columns %v", compiled.columns)
+	}
+
+	for _, invalid := range []ReportDefinitionSpec{
+		{Entity: "users", Metrics: []string{"count"}},
+		{Entity: "inventory_items", Metrics: []string{"count"}, GroupBy: []string{"name; --"}},
+		{Entity: "inventory_items", Metrics: []string{"count"}, Filters: []ReportFilter{{Field: "sku", Op: "eq", Value: &value}}},
+		{Entity: "inventory_items", Metrics: []string{"count"}, Period: &period},
+	} {
+		if _, err := compileReportDefinition(normalizeReportDefinitionSpec(invalid), "org-1", "clinic-1", "UTC", today); !errors.Is(err, ErrValidation) {
+			t.Fatalf("expected %+v to be rejected, got %v", invalid, err)
+		}
+	}
+}
+
+func TestNextReportDefinitionRunUsesClinicMorning(t *testing.T) {
+	location, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	now := time.Date(2026, time.March, 11, 12, 0, 0, 0, time.UTC) // Wednesday, 09:00 local
+	for schedule, expected := range map[string]time.Time{
+		ReportScheduleDaily:   time.Date(2026, time.March, 12, 6, 0, 0, 0, location),
+		ReportScheduleWeekly:  time.Date(2026, time.March, 16, 6, 0, 0, 0, location),
+		ReportScheduleMonthly: time.Date(2026, time.April, 1, 6, 0, 0, 0, location),
+	} {
+		if next := nextReportDefinitionRun(schedule, location, now); !next.Equal(expected) {
+			t.Fatalf("%s: expected %s, got %s", schedule, expected, next)
+		}
 	}
 }
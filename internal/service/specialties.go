@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+	"capim-test/internal/validation"
+)
+
+const (
+	maxSpecialtyNameLength        = 120
+	maxSpecialtyDescriptionLength = 500
+	specialtyCodeUniqueConstraint = "idx_specialties_code_active_unique"
+)
+
+func (s *Service) CreateSpecialty(ctx context.Context, input CreateSpecialtyInput) (SpecialtyOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CreateSpecialty")
+	defer span.End()
+
+	code := validation.NormalizeSpecialtyCode(input.Code)
+	if !validation.ValidateSpecialtyCode(code) {
+		return SpecialtyOutput{}, validationError("code must contain only lowercase letters, digits and underscores")
+	}
+	if strings.TrimSpace(input.Name) == "" {
+		return SpecialtyOutput{}, validationError("name is required")
+	}
+	if err := validateMaxLength("name", input.Name, maxSpecialtyNameLength); err != nil {
+		return SpecialtyOutput{}, err
+	}
+	if err := validateOptionalMaxLength("description", input.Description, maxSpecialtyDescriptionLength); err != nil {
+		return SpecialtyOutput{}, err
+	}
+
+	specialtyID, err := newUUIDV7()
+	if err != nil {
+		return SpecialtyOutput{}, err
+	}
+
+	specialty, err := s.queries.CreateSpecialty(ctx, repository.CreateSpecialtyParams{
+		OrganizationID: organizationID(ctx),
+		ID:             specialtyID,
+		Code:           code,
+		Name:           strings.TrimSpace(input.Name),
+		Description:    optionalString(input.Description),
+	})
+	if err != nil {
+		return SpecialtyOutput{}, mapSpecialtyDatabaseError(err)
+	}
+	s.invalidateReference(ctx, referenceKey(ctx, referenceSpecialties))
+	return mapSpecialty(specialty), nil
+}
+
+func (s *Service) ListSpecialties(ctx context.Context) ([]SpecialtyOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListSpecialties")
+	defer span.End()
+
+	return cachedReference(ctx, s, referenceKey(ctx, referenceSpecialties), func(ctx context.Context) ([]SpecialtyOutput, error) {
+		specialties, err := s.queries.ListSpecialties(ctx, organizationID(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		output := make([]SpecialtyOutput, 0, len(specialties))
+		for _, specialty := range specialties {
+			output = append(output, mapSpecialty(specialty))
+		}
+		return output, nil
+	})
+}
+
+func (s *Service) GetSpecialty(ctx context.Context, specialtyID string) (SpecialtyOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetSpecialty")
+	defer span.End()
+
+	specialty, err := s.queries.GetSpecialtyByID(ctx, repository.GetSpecialtyByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             specialtyID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SpecialtyOutput{}, notFoundError("specialty not found")
+		}
+		return SpecialtyOutput{}, err
+	}
+	return mapSpecialty(specialty), nil
+}
+
+func (s *Service) UpdateSpecialty(ctx context.Context, specialtyID string, input UpdateSpecialtyInput) (SpecialtyOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateSpecialty")
+	defer span.End()
+
+	if input.Name == nil && input.Description == nil {
+		return SpecialtyOutput{}, validationError("at least one field must be provided")
+	}
+	if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+		return SpecialtyOutput{}, validationError("name cannot be empty")
+	}
+	if err := validateOptionalMaxLength("name", input.Name, maxSpecialtyNameLength); err != nil {
+		return SpecialtyOutput{}, err
+	}
+	if err := validateOptionalMaxLength("description", input.Description, maxSpecialtyDescriptionLength); err != nil {
+		return SpecialtyOutput{}, err
+	}
+
+	specialty, err := s.queries.UpdateSpecialty(ctx, repository.UpdateSpecialtyParams{
+		OrganizationID: organizationID(ctx),
+		ID:             specialtyID,
+		Name:           optionalString(input.Name),
+		Description:    optionalString(input.Description),
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return SpecialtyOutput{}, notFoundError("specialty not found")
+		}
+		return SpecialtyOutput{}, mapSpecialtyDatabaseError(err)
+	}
+	s.invalidateReference(ctx, referenceKey(ctx, referenceSpecialties))
+	return mapSpecialty(specialty), nil
+}
+
+func (s *Service) DeleteSpecialty(ctx context.Context, specialtyID string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.DeleteSpecialty")
+	defer span.End()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	affected, err := qtx.DeleteSpecialty(ctx, repository.DeleteSpecialtyParams{
+		OrganizationID: organizationID(ctx),
+		ID:             specialtyID,
+	})
+	if err != nil {
+		return mapDatabaseError(err)
+	}
+	if affected == 0 {
+		return notFoundError("specialty not found")
+	}
+	if _, err := qtx.DeleteDentistSpecialtiesBySpecialty(ctx, repository.DeleteDentistSpecialtiesBySpecialtyParams{
+		OrganizationID: organizationID(ctx),
+		SpecialtyID:    specialtyID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	s.invalidateReference(ctx, referenceKey(ctx, referenceSpecialties))
+	return nil
+}
+
+func normalizeSpecialtyIDs(ids []string) ([]string, error) {
+	normalized := make([]string, 0, len(ids))
+	seen := make(map[string]struct{}, len(ids))
+	for idx, id := range ids {
+		parsedID, err := uuid.Parse(strings.TrimSpace(id))
+		if err != nil || parsedID.Version() != 7 {
+			return nil, validationError(fmt.Sprintf("specialty_ids[%d] must be a UUIDv7", idx))
+		}
+		value := parsedID.String()
+		if _, ok := seen[value]; ok {
+			continue
+		}
+		seen[value] = struct{}{}
+		normalized = append(normalized, value)
+	}
+	return normalized, nil
+}
+
+func replaceDentistSpecialties(ctx context.Context, qtx repository.Querier, dentistID string, specialtyIDs []string) error {
+	if len(specialtyIDs) > 0 {
+		count, err := qtx.CountActiveSpecialtiesByIDs(ctx, repository.CountActiveSpecialtiesByIDsParams{
+			OrganizationID: organizationID(ctx),
+			Ids:            specialtyIDs,
+		})
+		if err != nil {
+			return mapDatabaseError(err)
+		}
+		if count != int64(len(specialtyIDs)) {
+			return validationError("specialty_ids contains unknown specialties")
+		}
+	}
+
+	if _, err := qtx.DeleteDentistSpecialtiesByDentist(ctx, repository.DeleteDentistSpecialtiesByDentistParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	for _, specialtyID := range specialtyIDs {
+		if err := qtx.AddDentistSpecialty(ctx, repository.AddDentistSpecialtyParams{
+			OrganizationID: organizationID(ctx),
+			DentistID:      dentistID,
+			SpecialtyID:    specialtyID,
+		}); err != nil {
+			return mapDatabaseError(err)
+		}
+	}
+	return nil
+}
+
+func (s *Service) loadSpecialtiesByDentistIDs(ctx context.Context, dentistIDs []string) (map[string][]SpecialtyOutput, error) {
+	return loadBatch(ctx, s, "dentist_specialties", dentistIDs, s.listSpecialtiesByDentistIDs)
+}
+
+func (s *Service) listSpecialtiesByDentistIDs(ctx context.Context, dentistIDs []string) (map[string][]SpecialtyOutput, error) {
+	rows, err := s.queries.ListSpecialtiesByDentistIDs(ctx, repository.ListSpecialtiesByDentistIDsParams{
+		OrganizationID: organizationID(ctx),
+		DentistIds:     dentistIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	specialtiesByDentist := make(map[string][]SpecialtyOutput, len(dentistIDs))
+	for _, row := range rows {
+		specialtiesByDentist[row.DentistID] = append(specialtiesByDentist[row.DentistID], SpecialtyOutput{
+			ID:          row.ID,
+			Code:        row.Code,
+			Name:        row.Name,
+			Description: nullToPointer(row.Description),
+		})
+	}
+	return specialtiesByDentist, nil
+}
+
+func (s *Service) loadDentistSpecialties(ctx context.Context, dentistID string) ([]SpecialtyOutput, error) {
+	specialtiesByDentist, err := s.listSpecialtiesByDentistIDs(ctx, []string{dentistID})
+	if err != nil {
+		return nil, err
+	}
+	return specialtiesByDentist[dentistID], nil
+}
+
+func mapSpecialty(specialty repository.Specialty) SpecialtyOutput {
+	return SpecialtyOutput{
+		ID:          specialty.ID,
+		Code:        specialty.Code,
+		Name:        specialty.Name,
+		Description: nullToPointer(specialty.Description),
+	}
+}
+
+func mapSpecialtyDatabaseError(err error) error {
+	if isConstraintViolation(err, specialtyCodeUniqueConstraint) {
+		return conflictError("specialty code already exists")
+	}
+	return mapDatabaseError(err)
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const temporaryAssignmentBatchSize = 100
+
+type clinicDentistAssignment struct {
+	plannedEndAt  sql.NullTime
+	substituteFor uuid.NullUUID
+}
+
+func resolveAssignmentInput(plannedEndAt *time.Time, substituteForDentistID *string, now time.Time) (clinicDentistAssignment, error) {
+	assignment := clinicDentistAssignment{}
+	if plannedEndAt != nil {
+		if !plannedEndAt.After(now) {
+			return clinicDentistAssignment{}, validationError("planned_end_at must be in the future")
+		}
+		assignment.plannedEndAt = sql.NullTime{Time: plannedEndAt.UTC(), Valid: true}
+	}
+	if substituteForDentistID != nil && strings.TrimSpace(*substituteForDentistID) != "" {
+		if !assignment.plannedEndAt.Valid {
+			return clinicDentistAssignment{}, validationError("substitute_for_dentist_id requires planned_end_at")
+		}
+		parsed, err := uuid.Parse(strings.TrimSpace(*substituteForDentistID))
+		if err != nil || parsed.Version() != 7 {
+			return clinicDentistAssignment{}, validationError("substitute_for_dentist_id must be a UUIDv7")
+		}
+		assignment.substituteFor = uuid.NullUUID{UUID: parsed, Valid: true}
+	}
+	return assignment, nil
+}
+
+func ensureSubstituteTarget(ctx context.Context, qtx repository.Querier, clinicID string, dentistID string, assignment clinicDentistAssignment) error {
+	if !assignment.substituteFor.Valid {
+		return nil
+	}
+	substituteForID := assignment.substituteFor.UUID.String()
+	if substituteForID == dentistID {
+		return validationError("a dentist cannot substitute for themselves")
+	}
+	if _, err := qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      substituteForID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return validationError("substitute_for_dentist_id must be an active dentist of the clinic")
+		}
+		return err
+	}
+	return nil
+}
+
+func applyAssignment(output *ClinicDentistOutput, plannedEndAt sql.NullTime, substituteFor uuid.NullUUID) {
+	output.IsTemporary = plannedEndAt.Valid
+	if plannedEndAt.Valid {
+		endsAt := plannedEndAt.Time
+		output.PlannedEndAt = &endsAt
+	}
+	if substituteFor.Valid {
+		substituteForID := substituteFor.UUID.String()
+		output.SubstituteForDentistID = &substituteForID
+	}
+}
+
+func (s *Service) EndDueTemporaryAssignments(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.EndDueTemporaryAssignments")
+	defer span.End()
+
+	now := s.now().UTC()
+	due, err := s.queries.ListDueTemporaryClinicDentists(ctx, repository.ListDueTemporaryClinicDentistsParams{
+		OrganizationID: organizationID(ctx),
+		Now:            now,
+		BatchSize:      temporaryAssignmentBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	ended := 0
+	for _, relation := range due {
+		ok, err := s.endDueTemporaryAssignment(ctx, relation, now)
+		if err != nil {
+			if errors.Is(err, ErrRoleInvariant) {
+				// Kept active until an admin hands the role over; retried on the next run.
+				slog.WarnContext(ctx, "temporary assignment not ended", "clinic_id", relation.ClinicID, "dentist_id", relation.DentistID, "error", err)
+				continue
+			}
+			return ended, err
+		}
+		if ok {
+			ended++
+		}
+	}
+	return ended, nil
+}
+
+func (s *Service) endDueTemporaryAssignment(ctx context.Context, relation repository.ClinicDentist, now time.Time) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	roleCounts, err := lockClinicRoleCounts(ctx, qtx, relation.ClinicID)
+	if err != nil {
+		return false, err
+	}
+	rows, err := qtx.EndDueTemporaryClinicDentist(ctx, repository.EndDueTemporaryClinicDentistParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       relation.ClinicID,
+		DentistID:      relation.DentistID,
+		Now:            now,
+	})
+	if err != nil {
+		return false, mapDatabaseError(err)
+	}
+	if rows == 0 {
+		return false, nil
+	}
+	if err := ensureClinicRoleInvariant(ctx, qtx, relation.ClinicID, roleCounts); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return true, nil
+}
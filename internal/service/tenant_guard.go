@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"capim-test/internal/db/repository"
+)
+
+// ErrMissingTenantScope marks a query refused because it would run outside the caller's organization; it always points at a bug.
+var ErrMissingTenantScope = errors.New("query without tenant scope")
+
+// Lookups that run before the organization is known; everything else must carry the caller's organization as an argument.
+var unscopedQueries = map[string]struct{}{
+	"GetUserByEmail":                        {},
+	"GetBankAccountByVerificationReference": {},
+	"GetNotificationForDeliveryCallback":    {},
+	"GetDentistOrganizationID":              {},
+	"CreateOrganization":                    {},
+	"GetOrganizationByID":                   {},
+	"GetOrganizationBySlug":                 {},
+	"ListOrganizations":                     {},
+	"ListOrganizationIDs":                   {},
+	"ListOrganizationsDueForPurge":          {},
+	"ListSchemaOrganizations":               {},
+	"CreatePlatformOperator":                {},
+	"GetPlatformOperatorByEmail":            {},
+}
+
+// tenantGuard sits between the generated queries and the database, so a service method that forgets the organization fails instead of reading another tenant's rows.
+type tenantGuard struct {
+	db repository.DBTX
+}
+
+func newTenantGuard(db repository.DBTX) repository.DBTX {
+	return tenantGuard{db: db}
+}
+
+func (g tenantGuard) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	if err := checkTenantScope(ctx, query, args); err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return g.db.Exec(ctx, query, args...)
+}
+
+func (g tenantGuard) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	if err := checkTenantScope(ctx, query, args); err != nil {
+		return nil, err
+	}
+	return g.db.Query(ctx, query, args...)
+}
+
+func (g tenantGuard) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	if err := checkTenantScope(ctx, query, args); err != nil {
+		return errRow{err: err}
+	}
+	return g.db.QueryRow(ctx, query, args...)
+}
+
+func checkTenantScope(ctx context.Context, query string, args []interface{}) error {
+	name := queryName(query)
+	if _, ok := unscopedQueries[name]; ok {
+		return nil
+	}
+	organization := organizationID(ctx)
+	if organization != "" {
+		for _, arg := range args {
+			if value, ok := arg.(string); ok && value == organization {
+				return nil
+			}
+		}
+	}
+	slog.ErrorContext(ctx, "query refused without tenant scope", "query", name, "organization_id", organization)
+	return fmt.Errorf("%w: %s", ErrMissingTenantScope, name)
+}
+
+func queryName(query string) string {
+	header, _, _ := strings.Cut(query, "\n")
+	fields := strings.Fields(strings.TrimPrefix(header, "-- name:"))
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+// errRow is a row that never reached the database, so Scan reports why.
+type errRow struct {
+	err error
+}
+
+func (r errRow) Scan(...any) error {
+	return r.err
+}
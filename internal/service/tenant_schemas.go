@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	OrganizationIsolationShared = "SHARED"
+	OrganizationIsolationSchema = "SCHEMA"
+)
+
+// TenantSchemaProvider owns the Postgres schemas of tenants created with schema isolation.
+type TenantSchemaProvider interface {
+	Pool(ctx context.Context, schema string) (*pgxpool.Pool, error)
+	Migrate(ctx context.Context, schema string) (int, error)
+}
+
+func WithTenantSchemas(provider TenantSchemaProvider) Option {
+	return func(s *Service) {
+		s.db.schemas = provider
+	}
+}
+
+// routedDB sends each statement to the pool of the caller's organization: the shared pool for most tenants,
+// or the one pinned to the tenant's own schema. Isolation is fixed when an organization is created, so the lookup is cached.
+type routedDB struct {
+	shared  *pgxpool.Pool
+	schemas TenantSchemaProvider
+	lookups sync.Map
+}
+
+func newRoutedDB(shared *pgxpool.Pool) *routedDB {
+	return &routedDB{shared: shared}
+}
+
+func (r *routedDB) pool(ctx context.Context) (*pgxpool.Pool, error) {
+	if r.schemas == nil {
+		return r.shared, nil
+	}
+	organization := organizationID(ctx)
+	if organization == "" {
+		return r.shared, nil
+	}
+	schema, ok := r.lookups.Load(organization)
+	if !ok {
+		row, err := repository.New(r.shared).GetOrganizationByID(ctx, organization)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return r.shared, nil
+			}
+			return nil, fmt.Errorf("resolve tenant schema: %w", err)
+		}
+		schema = row.SchemaName.String
+		r.lookups.Store(organization, schema)
+	}
+	if schema == "" {
+		return r.shared, nil
+	}
+	return r.schemas.Pool(ctx, schema.(string))
+}
+
+func (r *routedDB) Begin(ctx context.Context) (pgx.Tx, error) {
+	return r.BeginTx(ctx, pgx.TxOptions{})
+}
+
+func (r *routedDB) BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error) {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pool.BeginTx(ctx, opts)
+}
+
+func (r *routedDB) Exec(ctx context.Context, query string, args ...interface{}) (pgconn.CommandTag, error) {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return pgconn.CommandTag{}, err
+	}
+	return pool.Exec(ctx, query, args...)
+}
+
+func (r *routedDB) Query(ctx context.Context, query string, args ...interface{}) (pgx.Rows, error) {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return pool.Query(ctx, query, args...)
+}
+
+func (r *routedDB) QueryRow(ctx context.Context, query string, args ...interface{}) pgx.Row {
+	pool, err := r.pool(ctx)
+	if err != nil {
+		return errRow{err: err}
+	}
+	return pool.QueryRow(ctx, query, args...)
+}
+
+// tenantSchemas is nil unless schema isolation is enabled.
+func (s *Service) tenantSchemas() TenantSchemaProvider {
+	if s.db == nil {
+		return nil
+	}
+	return s.db.schemas
+}
+
+func tenantSchemaName(organizationID string) string {
+	return "tenant_" + strings.ReplaceAll(strings.ToLower(organizationID), "-", "")
+}
+
+func (s *Service) validateOrganizationIsolation(isolation string) (string, error) {
+	isolation = strings.ToUpper(strings.TrimSpace(isolation))
+	switch isolation {
+	case "", OrganizationIsolationShared:
+		return OrganizationIsolationShared, nil
+	case OrganizationIsolationSchema:
+		if s.tenantSchemas() == nil {
+			return "", validationError("schema isolation is not enabled")
+		}
+		return OrganizationIsolationSchema, nil
+	default:
+		return "", validationError(fmt.Sprintf("isolation must be one of: %s, %s", OrganizationIsolationShared, OrganizationIsolationSchema))
+	}
+}
+
+// ProvisionTenantSchema creates the organization's schema, or brings it up to the latest tenant migration. It is safe to repeat.
+func (s *Service) ProvisionTenantSchema(ctx context.Context, id string) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ProvisionTenantSchema")
+	defer span.End()
+
+	if s.tenantSchemas() == nil {
+		return conflictError("schema isolation is not enabled")
+	}
+	organization, err := s.queries.GetOrganizationByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return notFoundError("organization not found")
+		}
+		return err
+	}
+	if organization.Isolation != OrganizationIsolationSchema {
+		return conflictError("organization does not use schema isolation")
+	}
+	version, err := s.tenantSchemas().Migrate(ctx, organization.SchemaName.String)
+	if err != nil {
+		return err
+	}
+	slog.InfoContext(ctx, "tenant schema provisioned", "organization_id", organization.ID, "schema", organization.SchemaName.String, "version", version)
+	return nil
+}
+
+// MigrateTenantSchemas runs at startup so every schema tenant is on the latest migration before serving traffic.
+func (s *Service) MigrateTenantSchemas(ctx context.Context) error {
+	if s.tenantSchemas() == nil {
+		return nil
+	}
+	organizations, err := s.queries.ListSchemaOrganizations(ctx)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, organization := range organizations {
+		if err := s.ProvisionTenantSchema(ctx, organization.ID); err != nil {
+			errs = append(errs, fmt.Errorf("organization %s: %w", organization.ID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// findInTenantSchemas backs the few lookups that run before the organization is known: the shared tables are searched first,
+// then each schema tenant in turn.
+func findInTenantSchemas[T any](ctx context.Context, s *Service, lookup func(ctx context.Context) (T, error)) (T, error) {
+	result, err := lookup(ctx)
+	if !errors.Is(err, sql.ErrNoRows) || s.tenantSchemas() == nil {
+		return result, err
+	}
+	organizations, listErr := s.queries.ListSchemaOrganizations(ctx)
+	if listErr != nil {
+		return result, listErr
+	}
+	for _, organization := range organizations {
+		result, err = lookup(WithOrganization(ctx, organization.ID))
+		if !errors.Is(err, sql.ErrNoRows) {
+			return result, err
+		}
+	}
+	return result, sql.ErrNoRows
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const tenantTrafficBuckets = 60
+
+type trafficBucket struct {
+	minute       int64
+	requests     int64
+	clientErrors int64
+	serverErrors int64
+}
+
+// tenantTraffic keeps per-minute request outcomes for the last hour of each organization.
+// Like the request quota counter, it lives in memory and each API instance reports its own traffic.
+type tenantTraffic struct {
+	mu      sync.Mutex
+	buckets map[string]*[tenantTrafficBuckets]trafficBucket
+}
+
+func newTenantTraffic() *tenantTraffic {
+	return &tenantTraffic{buckets: make(map[string]*[tenantTrafficBuckets]trafficBucket)}
+}
+
+func (t *tenantTraffic) record(organizationID string, status int, now time.Time) {
+	minute := now.Unix() / 60
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	ring, ok := t.buckets[organizationID]
+	if !ok {
+		ring = &[tenantTrafficBuckets]trafficBucket{}
+		t.buckets[organizationID] = ring
+	}
+	bucket := &ring[minute%tenantTrafficBuckets]
+	if bucket.minute != minute {
+		*bucket = trafficBucket{minute: minute}
+	}
+	bucket.requests++
+	switch {
+	case status >= 500:
+		bucket.serverErrors++
+	case status >= 400:
+		bucket.clientErrors++
+	}
+}
+
+func (t *tenantTraffic) summary(organizationID string, now time.Time) TenantTrafficOutput {
+	output := TenantTrafficOutput{WindowMinutes: tenantTrafficBuckets}
+	minute := now.Unix() / 60
+	t.mu.Lock()
+	ring, ok := t.buckets[organizationID]
+	if ok {
+		for _, bucket := range ring {
+			if minute-bucket.minute < tenantTrafficBuckets {
+				output.Requests += bucket.requests
+				output.ClientErrors += bucket.clientErrors
+				output.ServerErrors += bucket.serverErrors
+			}
+		}
+	}
+	t.mu.Unlock()
+	if output.Requests > 0 {
+		output.ServerErrorRate = float64(output.ServerErrors) / float64(output.Requests)
+	}
+	return output
+}
+
+// RecordRequestOutcome counts a finished request against the caller's organization for the admin console and usage metering.
+func (s *Service) RecordRequestOutcome(ctx context.Context, status int) {
+	organization := organizationID(ctx)
+	if organization == "" {
+		return
+	}
+	if s.traffic != nil {
+		s.traffic.record(organization, status, s.now())
+	}
+	if s.apiCalls != nil {
+		s.apiCalls.add(organization, 1)
+	}
+}
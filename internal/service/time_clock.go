@@ -0,0 +1,541 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"net/netip"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	minGeofenceRadiusMeters = 10
+	maxGeofenceRadiusMeters = 10000
+	earthRadiusMeters       = 6371000
+	timesheetMonthLayout    = "2006-01"
+)
+
+var timesheetColumns = []string{"date", "dentist_id", "dentist_name", "tax_id_number", "first_clock_in", "last_clock_out", "entries", "worked_minutes", "worked_hours", "open_entries", "unverified_punches"}
+
+func (s *Service) GetTimeClockSettings(ctx context.Context, clinicID string) (TimeClockSettingsOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetTimeClockSettings")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TimeClockSettingsOutput{}, notFoundError("clinic not found")
+		}
+		return TimeClockSettingsOutput{}, err
+	}
+	settings, err := s.queries.GetClinicTimeClockSettings(ctx, repository.GetClinicTimeClockSettingsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TimeClockSettingsOutput{ClinicID: clinicID, AllowedNetworks: []string{}}, nil
+		}
+		return TimeClockSettingsOutput{}, err
+	}
+	return mapTimeClockSettings(settings), nil
+}
+
+// UpdateTimeClockSettings replaces the clinic's punch rules: a geofence around the clinic, the networks that count as
+// being on site, and whether a punch that matches neither is refused or only recorded as unverified.
+func (s *Service) UpdateTimeClockSettings(ctx context.Context, clinicID string, input UpdateTimeClockSettingsInput) (TimeClockSettingsOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.UpdateTimeClockSettings")
+	defer span.End()
+
+	params := repository.UpsertClinicTimeClockSettingsParams{
+		OrganizationID:  organizationID(ctx),
+		ClinicID:        clinicID,
+		AllowedNetworks: make([]string, 0, len(input.AllowedNetworks)),
+		Enforce:         input.Enforce,
+	}
+	hasCoordinates := input.Latitude != nil || input.Longitude != nil
+	if hasCoordinates {
+		if err := validateCoordinates(input.Latitude, input.Longitude); err != nil {
+			return TimeClockSettingsOutput{}, err
+		}
+		if input.RadiusMeters == nil {
+			return TimeClockSettingsOutput{}, validationError("radius_meters is required with latitude and longitude")
+		}
+		if *input.RadiusMeters < minGeofenceRadiusMeters || *input.RadiusMeters > maxGeofenceRadiusMeters {
+			return TimeClockSettingsOutput{}, validationError(fmt.Sprintf("radius_meters must be between %d and %d", minGeofenceRadiusMeters, maxGeofenceRadiusMeters))
+		}
+		params.Latitude = sql.NullFloat64{Float64: *input.Latitude, Valid: true}
+		params.Longitude = sql.NullFloat64{Float64: *input.Longitude, Valid: true}
+		params.RadiusMeters = sql.NullInt32{Int32: int32(*input.RadiusMeters), Valid: true}
+	} else if input.RadiusMeters != nil {
+		return TimeClockSettingsOutput{}, validationError("radius_meters requires latitude and longitude")
+	}
+	for idx, network := range input.AllowedNetworks {
+		prefix, err := parseAllowedNetwork(network)
+		if err != nil {
+			return TimeClockSettingsOutput{}, validationError(fmt.Sprintf("allowed_networks[%d] must be an IP address or CIDR range", idx))
+		}
+		params.AllowedNetworks = append(params.AllowedNetworks, prefix.String())
+	}
+	if input.Enforce && !hasCoordinates && len(params.AllowedNetworks) == 0 {
+		return TimeClockSettingsOutput{}, validationError("enforce requires a geofence or allowed_networks")
+	}
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TimeClockSettingsOutput{}, notFoundError("clinic not found")
+		}
+		return TimeClockSettingsOutput{}, err
+	}
+	settings, err := s.queries.UpsertClinicTimeClockSettings(ctx, params)
+	if err != nil {
+		return TimeClockSettingsOutput{}, mapDatabaseError(err)
+	}
+	return mapTimeClockSettings(settings), nil
+}
+
+// ClockIn opens a time-clock entry for the dentist at the clinic. A dentist has at most one open entry across the
+// organization, so clocking in somewhere else first needs a clock-out.
+func (s *Service) ClockIn(ctx context.Context, dentistID string, clinicID string, input TimeClockPunchInput) (TimeClockEntryOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ClockIn")
+	defer span.End()
+
+	if err := validatePunchInput(input); err != nil {
+		return TimeClockEntryOutput{}, err
+	}
+	entryID, err := newUUIDV7()
+	if err != nil {
+		return TimeClockEntryOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return TimeClockEntryOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	clinic, err := qtx.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TimeClockEntryOutput{}, notFoundError("clinic not found")
+		}
+		return TimeClockEntryOutput{}, err
+	}
+	if clinic.DeactivatedAt.Valid {
+		return TimeClockEntryOutput{}, conflictError("clinic is deactivated")
+	}
+	if _, err := qtx.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentistID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TimeClockEntryOutput{}, forbiddenError("dentist is not linked to this clinic")
+		}
+		return TimeClockEntryOutput{}, err
+	}
+	open, err := qtx.GetOpenTimeClockEntry(ctx, repository.GetOpenTimeClockEntryParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	})
+	if err == nil {
+		return TimeClockEntryOutput{}, conflictError(fmt.Sprintf("already clocked in at clinic %s since %s", open.ClinicID, open.ClockInAt.UTC().Format(time.RFC3339)))
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return TimeClockEntryOutput{}, err
+	}
+
+	distance, verified, err := s.verifyPunch(ctx, qtx, clinicID, input)
+	if err != nil {
+		return TimeClockEntryOutput{}, err
+	}
+	entry, err := qtx.CreateTimeClockEntry(ctx, repository.CreateTimeClockEntryParams{
+		ID:             entryID,
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		DentistID:      dentistID,
+		ClockInAt:      s.now().UTC(),
+		Ip:             optionalString(&input.IP),
+		Latitude:       nullFloat(input.Latitude),
+		Longitude:      nullFloat(input.Longitude),
+		AccuracyMeters: nullFloat(input.AccuracyMeters),
+		DistanceMeters: distance,
+		Verified:       verified,
+	})
+	if err != nil {
+		if isUniqueConstraintError(err) {
+			return TimeClockEntryOutput{}, conflictError("already clocked in")
+		}
+		return TimeClockEntryOutput{}, mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return TimeClockEntryOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapTimeClockEntry(entry), nil
+}
+
+func (s *Service) ClockOut(ctx context.Context, dentistID string, clinicID string, input TimeClockPunchInput) (TimeClockEntryOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ClockOut")
+	defer span.End()
+
+	if err := validatePunchInput(input); err != nil {
+		return TimeClockEntryOutput{}, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return TimeClockEntryOutput{}, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	open, err := qtx.GetOpenTimeClockEntry(ctx, repository.GetOpenTimeClockEntryParams{
+		OrganizationID: organizationID(ctx),
+		DentistID:      dentistID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TimeClockEntryOutput{}, conflictError("not clocked in")
+		}
+		return TimeClockEntryOutput{}, err
+	}
+	if open.ClinicID != clinicID {
+		return TimeClockEntryOutput{}, conflictError(fmt.Sprintf("clocked in at clinic %s, not this one", open.ClinicID))
+	}
+
+	distance, verified, err := s.verifyPunch(ctx, qtx, clinicID, input)
+	if err != nil {
+		return TimeClockEntryOutput{}, err
+	}
+	entry, err := qtx.CloseTimeClockEntry(ctx, repository.CloseTimeClockEntryParams{
+		OrganizationID: organizationID(ctx),
+		ID:             open.ID,
+		ClockOutAt:     s.now().UTC(),
+		Ip:             optionalString(&input.IP),
+		Latitude:       nullFloat(input.Latitude),
+		Longitude:      nullFloat(input.Longitude),
+		AccuracyMeters: nullFloat(input.AccuracyMeters),
+		DistanceMeters: distance,
+		Verified:       verified,
+	})
+	if err != nil {
+		return TimeClockEntryOutput{}, mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return TimeClockEntryOutput{}, fmt.Errorf("commit transaction: %w", err)
+	}
+	return mapTimeClockEntry(entry), nil
+}
+
+// GetClinicTimesheet totals the month's entries per dentist and day. An entry counts on the clinic-local day it was
+// opened, even when it runs past midnight; entries still open add nothing until they are closed.
+func (s *Service) GetClinicTimesheet(ctx context.Context, clinicID string, month *string) (TimesheetOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetClinicTimesheet")
+	defer span.End()
+
+	clinic, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TimesheetOutput{}, notFoundError("clinic not found")
+		}
+		return TimesheetOutput{}, err
+	}
+	location := clinicLocation(ctx, clinic.Timezone)
+
+	start := s.todayIn(location)
+	start = time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if month != nil {
+		start, err = time.Parse(timesheetMonthLayout, strings.TrimSpace(*month))
+		if err != nil {
+			return TimesheetOutput{}, validationError("month must be in YYYY-MM format")
+		}
+	}
+
+	rows, err := s.queries.ListClinicTimeClockEntries(ctx, repository.ListClinicTimeClockEntriesParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+		FromAt:         time.Date(start.Year(), start.Month(), 1, 0, 0, 0, 0, location),
+		ToAt:           time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, location),
+	})
+	if err != nil {
+		return TimesheetOutput{}, err
+	}
+	return buildTimesheet(clinicID, clinic.Timezone, start.Format(timesheetMonthLayout), location, rows), nil
+}
+
+func (s *Service) ExportClinicTimesheet(ctx context.Context, clinicID string, month *string) (TimesheetFileOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ExportClinicTimesheet")
+	defer span.End()
+
+	timesheet, err := s.GetClinicTimesheet(ctx, clinicID, month)
+	if err != nil {
+		return TimesheetFileOutput{}, err
+	}
+	location := clinicLocation(ctx, timesheet.Timezone)
+
+	var buffer bytes.Buffer
+	writer := csv.NewWriter(&buffer)
+	records := append([][]string{timesheetColumns}, timesheetRecords(timesheet, location)...)
+	if err := writer.WriteAll(records); err != nil {
+		return TimesheetFileOutput{}, fmt.Errorf("write timesheet: %w", err)
+	}
+	return TimesheetFileOutput{
+		FileName:    "timesheet-" + clinicID + "-" + timesheet.Month + ".csv",
+		ContentType: "text/csv; charset=utf-8",
+		Content:     buffer.Bytes(),
+	}, nil
+}
+
+func timesheetRecords(timesheet TimesheetOutput, location *time.Location) [][]string {
+	var records [][]string
+	for _, dentist := range timesheet.Dentists {
+		for _, day := range dentist.Days {
+			lastClockOut := ""
+			if day.LastClockOut != nil {
+				lastClockOut = day.LastClockOut.In(location).Format("15:04")
+			}
+			records = append(records, []string{
+				day.Date,
+				dentist.DentistID,
+				dentist.DentistName,
+				dentist.TaxIDNumber,
+				day.FirstClockIn.In(location).Format("15:04"),
+				lastClockOut,
+				strconv.Itoa(day.Entries),
+				strconv.Itoa(day.WorkedMinutes),
+				fmt.Sprintf("%d:%02d", day.WorkedMinutes/60, day.WorkedMinutes%60),
+				strconv.Itoa(day.OpenEntries),
+				strconv.Itoa(day.UnverifiedPunches),
+			})
+		}
+	}
+	return records
+}
+
+func buildTimesheet(clinicID string, timezone string, month string, location *time.Location, rows []repository.ListClinicTimeClockEntriesRow) TimesheetOutput {
+	timesheet := TimesheetOutput{ClinicID: clinicID, Month: month, Timezone: timezone, Dentists: []TimesheetDentistOutput{}}
+	for _, row := range rows {
+		if count := len(timesheet.Dentists); count == 0 || timesheet.Dentists[count-1].DentistID != row.DentistID {
+			timesheet.Dentists = append(timesheet.Dentists, TimesheetDentistOutput{
+				DentistID:   row.DentistID,
+				DentistName: row.DentistName,
+				TaxIDNumber: row.DentistTaxIDNumber,
+				Days:        []TimesheetDayOutput{},
+			})
+		}
+		dentist := &timesheet.Dentists[len(timesheet.Dentists)-1]
+
+		date := row.ClockInAt.In(location).Format(documentDateLayout)
+		if count := len(dentist.Days); count == 0 || dentist.Days[count-1].Date != date {
+			dentist.Days = append(dentist.Days, TimesheetDayOutput{Date: date, FirstClockIn: row.ClockInAt.UTC()})
+		}
+		day := &dentist.Days[len(dentist.Days)-1]
+		day.Entries++
+		if row.ClockInVerified.Valid && !row.ClockInVerified.Bool {
+			day.UnverifiedPunches++
+		}
+		if !row.ClockOutAt.Valid {
+			day.OpenEntries++
+			continue
+		}
+		if row.ClockOutVerified.Valid && !row.ClockOutVerified.Bool {
+			day.UnverifiedPunches++
+		}
+		clockOut := row.ClockOutAt.Time.UTC()
+		if day.LastClockOut == nil || clockOut.After(*day.LastClockOut) {
+			day.LastClockOut = &clockOut
+		}
+		minutes := int(row.ClockOutAt.Time.Sub(row.ClockInAt).Minutes())
+		day.WorkedMinutes += minutes
+		dentist.WorkedMinutes += minutes
+	}
+	return timesheet
+}
+
+// verifyPunch checks the punch against the clinic's rules. It is verified when the caller's IP is on an allowed network
+// or the reported position falls inside the geofence; with no rules configured, verification is left empty.
+func (s *Service) verifyPunch(ctx context.Context, qtx repository.Querier, clinicID string, input TimeClockPunchInput) (sql.NullFloat64, sql.NullBool, error) {
+	settings, err := qtx.GetClinicTimeClockSettings(ctx, repository.GetClinicTimeClockSettingsParams{
+		OrganizationID: organizationID(ctx),
+		ClinicID:       clinicID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return sql.NullFloat64{}, sql.NullBool{}, nil
+		}
+		return sql.NullFloat64{}, sql.NullBool{}, err
+	}
+	distance, verified := punchVerification(settings, input)
+	if settings.Enforce && verified.Valid && !verified.Bool {
+		return sql.NullFloat64{}, sql.NullBool{}, forbiddenError("punch is outside the clinic's geofence and allowed networks")
+	}
+	return distance, verified, nil
+}
+
+func punchVerification(settings repository.ClinicTimeClockSetting, input TimeClockPunchInput) (sql.NullFloat64, sql.NullBool) {
+	var distance sql.NullFloat64
+	if settings.Latitude.Valid && input.Latitude != nil && input.Longitude != nil {
+		distance = sql.NullFloat64{Float64: haversineMeters(settings.Latitude.Float64, settings.Longitude.Float64, *input.Latitude, *input.Longitude), Valid: true}
+	}
+	if !settings.Latitude.Valid && len(settings.AllowedNetworks) == 0 {
+		return distance, sql.NullBool{}
+	}
+	if distance.Valid && distance.Float64 <= float64(settings.RadiusMeters.Int32) {
+		return distance, sql.NullBool{Bool: true, Valid: true}
+	}
+	if addr, err := netip.ParseAddr(strings.TrimSpace(input.IP)); err == nil {
+		for _, network := range settings.AllowedNetworks {
+			if prefix, err := netip.ParsePrefix(network); err == nil && prefix.Contains(addr.Unmap()) {
+				return distance, sql.NullBool{Bool: true, Valid: true}
+			}
+		}
+	}
+	return distance, sql.NullBool{Bool: false, Valid: true}
+}
+
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	toRadians := func(degrees float64) float64 { return degrees * math.Pi / 180 }
+	dLat := toRadians(lat2 - lat1)
+	dLon := toRadians(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRadians(lat1))*math.Cos(toRadians(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(a))
+}
+
+func parseAllowedNetwork(value string) (netip.Prefix, error) {
+	trimmed := strings.TrimSpace(value)
+	if strings.Contains(trimmed, "/") {
+		prefix, err := netip.ParsePrefix(trimmed)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(trimmed)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+func validatePunchInput(input TimeClockPunchInput) error {
+	if input.Latitude != nil || input.Longitude != nil {
+		if err := validateCoordinates(input.Latitude, input.Longitude); err != nil {
+			return err
+		}
+	}
+	if input.AccuracyMeters != nil && *input.AccuracyMeters < 0 {
+		return validationError("accuracy_meters must not be negative")
+	}
+	return nil
+}
+
+func validateCoordinates(latitude *float64, longitude *float64) error {
+	if latitude == nil || longitude == nil {
+		return validationError("latitude and longitude must be sent together")
+	}
+	if *latitude < -90 || *latitude > 90 {
+		return validationError("latitude must be between -90 and 90")
+	}
+	if *longitude < -180 || *longitude > 180 {
+		return validationError("longitude must be between -180 and 180")
+	}
+	return nil
+}
+
+func nullFloat(value *float64) sql.NullFloat64 {
+	if value == nil {
+		return sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: *value, Valid: true}
+}
+
+func nullFloatToPointer(value sql.NullFloat64) *float64 {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Float64
+}
+
+func nullBoolToPointer(value sql.NullBool) *bool {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Bool
+}
+
+func mapTimeClockSettings(settings repository.ClinicTimeClockSetting) TimeClockSettingsOutput {
+	output := TimeClockSettingsOutput{
+		ClinicID:        settings.ClinicID,
+		Latitude:        nullFloatToPointer(settings.Latitude),
+		Longitude:       nullFloatToPointer(settings.Longitude),
+		AllowedNetworks: settings.AllowedNetworks,
+		Enforce:         settings.Enforce,
+		UpdatedAt:       &settings.UpdatedAt,
+	}
+	if output.AllowedNetworks == nil {
+		output.AllowedNetworks = []string{}
+	}
+	if settings.RadiusMeters.Valid {
+		radius := int(settings.RadiusMeters.Int32)
+		output.RadiusMeters = &radius
+	}
+	return output
+}
+
+func mapTimeClockEntry(entry repository.TimeClockEntry) TimeClockEntryOutput {
+	output := TimeClockEntryOutput{
+		ID:        entry.ID,
+		ClinicID:  entry.ClinicID,
+		DentistID: entry.DentistID,
+		ClockIn: TimeClockPunchOutput{
+			At:             entry.ClockInAt.UTC(),
+			IP:             nullToPointer(entry.ClockInIp),
+			Latitude:       nullFloatToPointer(entry.ClockInLatitude),
+			Longitude:      nullFloatToPointer(entry.ClockInLongitude),
+			AccuracyMeters: nullFloatToPointer(entry.ClockInAccuracyMeters),
+			DistanceMeters: nullFloatToPointer(entry.ClockInDistanceMeters),
+			Verified:       nullBoolToPointer(entry.ClockInVerified),
+		},
+		CreatedAt: entry.CreatedAt,
+		UpdatedAt: entry.UpdatedAt,
+	}
+	if entry.ClockOutAt.Valid {
+		output.ClockOut = &TimeClockPunchOutput{
+			At:             entry.ClockOutAt.Time.UTC(),
+			IP:             nullToPointer(entry.ClockOutIp),
+			Latitude:       nullFloatToPointer(entry.ClockOutLatitude),
+			Longitude:      nullFloatToPointer(entry.ClockOutLongitude),
+			AccuracyMeters: nullFloatToPointer(entry.ClockOutAccuracyMeters),
+			DistanceMeters: nullFloatToPointer(entry.ClockOutDistanceMeters),
+			Verified:       nullBoolToPointer(entry.ClockOutVerified),
+		}
+		minutes := int(entry.ClockOutAt.Time.Sub(entry.ClockInAt).Minutes())
+		output.WorkedMinutes = &minutes
+	}
+	return output
+}
@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultClinicTimezone = "America/Sao_Paulo"
+
+	maxTimezoneLength = 64
+)
+
+func resolveTimezoneInput(value *string) (string, error) {
+	if value == nil {
+		return DefaultClinicTimezone, nil
+	}
+	timezone := strings.TrimSpace(*value)
+	if timezone == "" {
+		return "", validationError("timezone cannot be empty")
+	}
+	if err := validateMaxLength("timezone", timezone, maxTimezoneLength); err != nil {
+		return "", err
+	}
+	// time.LoadLocation also accepts "Local", which would make the clinic depend on the server configuration.
+	if timezone == "Local" {
+		return "", validationError("timezone must be a valid IANA time zone name")
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return "", validationError("timezone must be a valid IANA time zone name")
+	}
+	return timezone, nil
+}
+
+func clinicLocation(ctx context.Context, timezone string) *time.Location {
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		slog.WarnContext(ctx, "invalid clinic timezone, falling back to UTC", "timezone", timezone, "error", err)
+		return time.UTC
+	}
+	return location
+}
+
+// DATE columns are compared as UTC midnights, so the local calendar day is re-anchored in UTC.
+func (s *Service) todayIn(location *time.Location) time.Time {
+	now := s.now().In(location)
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
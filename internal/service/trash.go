@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const pendingDeletionBatchSize = 100
+
+func WithDeletionGracePeriod(period time.Duration) Option {
+	return func(s *Service) {
+		if period > 0 {
+			s.deletionGracePeriod = period
+		}
+	}
+}
+
+func (s *Service) ListTrash(ctx context.Context, limit int, cursor *string, resourceType *string) ([]TrashItemOutput, *string, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListTrash")
+	defer span.End()
+
+	pageLimit := normalizeCursorLimit(limit)
+	after, err := decodeKeysetCursor(cursor)
+	if err != nil {
+		return nil, nil, err
+	}
+	typeFilter := sql.NullString{}
+	if resourceType != nil {
+		normalized := strings.ToUpper(strings.TrimSpace(*resourceType))
+		if normalized != DeletedResourceClinic && normalized != DeletedResourceDentist {
+			return nil, nil, validationError(fmt.Sprintf("type must be one of: %s, %s", DeletedResourceClinic, DeletedResourceDentist))
+		}
+		typeFilter = sql.NullString{String: normalized, Valid: true}
+	}
+
+	rows, err := s.queries.ListTrashCursor(ctx, repository.ListTrashCursorParams{
+		OrganizationID:     organizationID(ctx),
+		ResourceType:       typeFilter,
+		CursorCascadeAfter: after.At,
+		CursorID:           after.ID,
+		PageLimit:          int32(pageLimit + 1),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	hasNext := len(rows) > pageLimit
+	if hasNext {
+		rows = rows[:pageLimit]
+	}
+
+	items := make([]TrashItemOutput, 0, len(rows))
+	for _, row := range rows {
+		items = append(items, TrashItemOutput{
+			Type:              row.ResourceType,
+			ID:                row.ID,
+			LegalName:         row.LegalName,
+			TaxIDNumber:       row.TaxIDNumber,
+			DeletedAt:         row.DeletedAt,
+			CascadeAt:         row.CascadeAfter,
+			RequestedByUserID: nullUUIDToPointer(row.RequestedByUserID),
+			RequestedByEmail:  nullToPointer(row.RequestedByEmail),
+		})
+	}
+
+	var nextCursor *string
+	if hasNext && len(rows) > 0 {
+		last := rows[len(rows)-1]
+		nextCursor = encodeKeysetCursor(sql.NullTime{Time: last.CascadeAfter, Valid: true}, last.ID)
+	}
+	return items, nextCursor, nil
+}
+
+func (s *Service) CompleteDuePendingDeletions(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.CompleteDuePendingDeletions")
+	defer span.End()
+
+	due, err := s.queries.ListDuePendingDeletions(ctx, repository.ListDuePendingDeletionsParams{
+		OrganizationID: organizationID(ctx),
+		Now:            s.now().UTC(),
+		BatchSize:      pendingDeletionBatchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	completed := 0
+	for _, pending := range due {
+		ok, err := s.completePendingDeletion(ctx, pending)
+		if err != nil {
+			return completed, fmt.Errorf("complete deletion of %s %s: %w", strings.ToLower(pending.ResourceType), pending.ResourceID, err)
+		}
+		if ok {
+			completed++
+		}
+	}
+	return completed, nil
+}
+
+func (s *Service) scheduleDeletion(ctx context.Context, q repository.Querier, resourceType string, resourceID string) error {
+	requestedBy := uuid.NullUUID{}
+	if principal, ok := PrincipalFromContext(ctx); ok {
+		if parsed, err := uuid.Parse(principal.UserID); err == nil {
+			requestedBy = uuid.NullUUID{UUID: parsed, Valid: true}
+		}
+	}
+	if err := q.CreatePendingDeletion(ctx, repository.CreatePendingDeletionParams{
+		OrganizationID:    organizationID(ctx),
+		ResourceType:      resourceType,
+		ResourceID:        resourceID,
+		RequestedByUserID: requestedBy,
+		GraceSeconds:      s.deletionGracePeriod.Seconds(),
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	return nil
+}
+
+// The rest of the cascade gets the original deleted_at, so a later restore still finds everything removed with the record.
+func (s *Service) completePendingDeletion(ctx context.Context, pending repository.PendingDeletion) (bool, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return false, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	qtx := s.txQuerier(tx)
+	var entry auditEntry
+	switch pending.ResourceType {
+	case DeletedResourceClinic:
+		clinic, err := qtx.LockDeletedClinicForUpdate(ctx, repository.LockDeletedClinicForUpdateParams{
+			OrganizationID: organizationID(ctx),
+			ID:             pending.ResourceID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, nil
+			}
+			return false, err
+		}
+		if claimed, err := claimPendingDeletion(ctx, qtx, pending); err != nil || !claimed {
+			return false, err
+		}
+		revisions, err := loadClinicRevisionSnapshot(ctx, qtx, clinic.ID)
+		if err != nil {
+			return false, err
+		}
+		bankAccounts, err := qtx.DeleteBankAccountsByClinicIDAt(ctx, repository.DeleteBankAccountsByClinicIDAtParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinic.ID,
+			DeletedAt:      clinic.DeletedAt.Time,
+		})
+		if err != nil {
+			return false, mapDatabaseError(err)
+		}
+		if _, err := qtx.DeleteUnusedPersonAt(ctx, repository.DeleteUnusedPersonAtParams{
+			OrganizationID: organizationID(ctx),
+			ID:             clinic.PersonID,
+			DeletedAt:      clinic.DeletedAt.Time,
+		}); err != nil {
+			return false, mapDatabaseError(err)
+		}
+		if err := recordClinicRevisions(ctx, qtx, revisions); err != nil {
+			return false, err
+		}
+		entry = auditEntry{
+			ClinicID:   clinic.ID,
+			Action:     "clinic.deletion_completed",
+			EntityType: AuditEntityClinic,
+			EntityID:   clinic.ID,
+			Metadata:   map[string]any{"deleted_at": clinic.DeletedAt.Time, "bank_accounts_deleted": bankAccounts},
+		}
+	case DeletedResourceDentist:
+		dentist, err := qtx.LockDeletedDentistForUpdate(ctx, repository.LockDeletedDentistForUpdateParams{
+			OrganizationID: organizationID(ctx),
+			ID:             pending.ResourceID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return false, nil
+			}
+			return false, err
+		}
+		if claimed, err := claimPendingDeletion(ctx, qtx, pending); err != nil || !claimed {
+			return false, err
+		}
+		documents, err := qtx.DeleteDentistDocumentsByDentistAt(ctx, repository.DeleteDentistDocumentsByDentistAtParams{
+			OrganizationID: organizationID(ctx),
+			DentistID:      dentist.ID,
+			DeletedAt:      dentist.DeletedAt.Time,
+		})
+		if err != nil {
+			return false, mapDatabaseError(err)
+		}
+		if _, err := qtx.DeleteUnusedPersonAt(ctx, repository.DeleteUnusedPersonAtParams{
+			OrganizationID: organizationID(ctx),
+			ID:             dentist.PersonID,
+			DeletedAt:      dentist.DeletedAt.Time,
+		}); err != nil {
+			return false, mapDatabaseError(err)
+		}
+		entry = auditEntry{
+			Action:     "dentist.deletion_completed",
+			EntityType: AuditEntityDentist,
+			EntityID:   dentist.ID,
+			Metadata:   map[string]any{"deleted_at": dentist.DeletedAt.Time, "documents_deleted": documents},
+		}
+	default:
+		return false, fmt.Errorf("unknown pending deletion type %q", pending.ResourceType)
+	}
+	if err := recordAudit(ctx, qtx, entry); err != nil {
+		return false, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return false, fmt.Errorf("commit transaction: %w", err)
+	}
+	return true, nil
+}
+
+// A restore that won the clinic or dentist lock has already removed the entry.
+func claimPendingDeletion(ctx context.Context, q repository.Querier, pending repository.PendingDeletion) (bool, error) {
+	claimed, err := q.DeletePendingDeletion(ctx, repository.DeletePendingDeletionParams{
+		OrganizationID: organizationID(ctx),
+		ResourceType:   pending.ResourceType,
+		ResourceID:     pending.ResourceID,
+	})
+	if err != nil {
+		return false, mapDatabaseError(err)
+	}
+	return claimed > 0, nil
+}
@@ -1,44 +1,95 @@
 package service
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type BankAccountInput struct {
-	BankCode      string `json:"bank_code" binding:"required,max=20"`
-	BranchNumber  string `json:"branch_number" binding:"required,max=20"`
-	AccountNumber string `json:"account_number" binding:"required,max=20"`
+	BankCode      string  `json:"bank_code" binding:"required,max=20"`
+	BranchNumber  string  `json:"branch_number" binding:"required,max=20"`
+	AccountNumber string  `json:"account_number" binding:"required,max=20"`
+	AccountType   string  `json:"account_type" binding:"omitempty,max=20"`
+	HolderName    *string `json:"holder_name" binding:"omitempty,max=255"`
+	HolderTaxID   *string `json:"holder_tax_id" binding:"omitempty,max=32"`
+	IsPrimary     bool    `json:"is_primary"`
+}
+
+type UpdateBankAccountInput struct {
+	BankCode      *string `json:"bank_code" binding:"omitempty,max=20"`
+	BranchNumber  *string `json:"branch_number" binding:"omitempty,max=20"`
+	AccountNumber *string `json:"account_number" binding:"omitempty,max=20"`
+	AccountType   *string `json:"account_type" binding:"omitempty,max=20"`
+	HolderName    *string `json:"holder_name" binding:"omitempty,max=255"`
+	HolderTaxID   *string `json:"holder_tax_id" binding:"omitempty,max=32"`
+}
+
+type AddressInput struct {
+	Street     *string `json:"street" binding:"omitempty,max=255"`
+	Number     *string `json:"number" binding:"omitempty,max=20"`
+	Complement *string `json:"complement" binding:"omitempty,max=255"`
+	City       *string `json:"city" binding:"omitempty,max=120"`
+	State      *string `json:"state" binding:"omitempty,len=2"`
+	CEP        string  `json:"cep" binding:"required,max=9"`
 }
 
 type CreateClinicInput struct {
-	TaxIDNumber  string             `json:"tax_id_number" binding:"required,max=32"`
-	LegalName    string             `json:"legal_name" binding:"required,max=255"`
-	TradeName    *string            `json:"trade_name" binding:"omitempty,max=255"`
-	Email        *string            `json:"email" binding:"omitempty,email,max=254"`
-	Phone        *string            `json:"phone" binding:"omitempty,max=20"`
-	BankAccounts []BankAccountInput `json:"bank_accounts" binding:"required,min=1,dive"`
+	TaxIDNumber    string             `json:"tax_id_number" binding:"required,max=32"`
+	LegalName      string             `json:"legal_name" binding:"required,max=255"`
+	TradeName      *string            `json:"trade_name" binding:"omitempty,max=255"`
+	Email          *string            `json:"email" binding:"omitempty,max=254"`
+	Phone          *string            `json:"phone" binding:"omitempty,max=20"`
+	Address        *AddressInput      `json:"address"`
+	Timezone       *string            `json:"timezone" binding:"omitempty,max=64"`
+	BankAccounts   []BankAccountInput `json:"bank_accounts" binding:"required,min=1,dive"`
+	AllowDuplicate bool               `json:"allow_duplicate"`
 }
 
 type UpdateClinicInput struct {
 	LegalName              *string             `json:"legal_name" binding:"omitempty,max=255"`
 	TradeName              *string             `json:"trade_name" binding:"omitempty,max=255"`
-	Email                  *string             `json:"email" binding:"omitempty,email,max=254"`
+	Email                  *string             `json:"email" binding:"omitempty,max=254"`
 	Phone                  *string             `json:"phone" binding:"omitempty,max=20"`
+	Address                *AddressInput       `json:"address"`
+	Timezone               *string             `json:"timezone" binding:"omitempty,max=64"`
 	BankAccounts           *[]BankAccountInput `json:"bank_accounts" binding:"omitempty,min=1,dive"`
 	BankAccountIDsToRemove *[]string           `json:"bank_account_ids_to_remove" binding:"omitempty,min=1,dive"`
 }
 
 type CreateDentistInput struct {
-	TaxIDNumber           string  `json:"tax_id_number" binding:"required,max=32"`
-	LegalName             string  `json:"legal_name" binding:"required,max=255"`
-	Email                 *string `json:"email" binding:"omitempty,email,max=254"`
-	Phone                 *string `json:"phone" binding:"omitempty,max=20"`
-	IsAdmin               bool    `json:"is_admin"`
-	IsLegalRepresentative bool    `json:"is_legal_representative"`
+	TaxIDNumber            string        `json:"tax_id_number" binding:"required,max=32"`
+	LegalName              string        `json:"legal_name" binding:"required,max=255"`
+	Email                  *string       `json:"email" binding:"omitempty,max=254"`
+	Phone                  *string       `json:"phone" binding:"omitempty,max=20"`
+	CRONumber              *string       `json:"cro_number" binding:"omitempty,max=20"`
+	CROState               *string       `json:"cro_state" binding:"omitempty,len=2"`
+	Address                *AddressInput `json:"address"`
+	SpecialtyIDs           []string      `json:"specialty_ids"`
+	IsAdmin                bool          `json:"is_admin"`
+	IsLegalRepresentative  bool          `json:"is_legal_representative"`
+	PlannedEndAt           *time.Time    `json:"planned_end_at"`
+	SubstituteForDentistID *string       `json:"substitute_for_dentist_id"`
 }
 
 type UpdateDentistInput struct {
-	LegalName *string `json:"legal_name" binding:"omitempty,max=255"`
-	Email     *string `json:"email" binding:"omitempty,email,max=254"`
-	Phone     *string `json:"phone" binding:"omitempty,max=20"`
+	LegalName    *string       `json:"legal_name" binding:"omitempty,max=255"`
+	Email        *string       `json:"email" binding:"omitempty,max=254"`
+	Phone        *string       `json:"phone" binding:"omitempty,max=20"`
+	CRONumber    *string       `json:"cro_number" binding:"omitempty,max=20"`
+	CROState     *string       `json:"cro_state" binding:"omitempty,len=2"`
+	Address      *AddressInput `json:"address"`
+	SpecialtyIDs *[]string     `json:"specialty_ids"`
+}
+
+type CreateSpecialtyInput struct {
+	Code        string  `json:"code" binding:"required,max=50"`
+	Name        string  `json:"name" binding:"required,max=120"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
+}
+
+type UpdateSpecialtyInput struct {
+	Name        *string `json:"name" binding:"omitempty,max=120"`
+	Description *string `json:"description" binding:"omitempty,max=500"`
 }
 
 type UpdateClinicDentistRoleInput struct {
@@ -46,54 +97,1719 @@ type UpdateClinicDentistRoleInput struct {
 	IsLegalRepresentative *bool `json:"is_legal_representative"`
 }
 
+type ClinicDentistRoleUpdateInput struct {
+	DentistID             string `json:"dentist_id" binding:"required"`
+	IsAdmin               *bool  `json:"is_admin"`
+	IsLegalRepresentative *bool  `json:"is_legal_representative"`
+}
+
+type BulkUpdateClinicDentistRolesInput struct {
+	Updates []ClinicDentistRoleUpdateInput `json:"updates" binding:"required,min=1,max=100,dive"`
+}
+
 type LoginInput struct {
 	Email    string `json:"email" binding:"required,email,max=254"`
-	Password string `json:"password" binding:"required,max=1024"`
+	Password string `json:"password" binding:"required,max=1024" sanitize:"-"`
+}
+
+type AddressOutput struct {
+	Street     string  `json:"street"`
+	Number     *string `json:"number,omitempty"`
+	Complement *string `json:"complement,omitempty"`
+	City       string  `json:"city"`
+	State      string  `json:"state"`
+	CEP        string  `json:"cep"`
 }
 
 type BankAccountOutput struct {
-	ID            string `json:"id"`
+	ID                        string     `json:"id"`
+	BankCode                  string     `json:"bank_code"`
+	BranchNumber              string     `json:"branch_number"`
+	AccountNumber             string     `json:"account_number"`
+	AccountType               string     `json:"account_type"`
+	HolderName                *string    `json:"holder_name,omitempty"`
+	HolderTaxID               *string    `json:"holder_tax_id,omitempty"`
+	HolderReviewRequired      bool       `json:"holder_review_required"`
+	IsPrimary                 bool       `json:"is_primary"`
+	VerificationStatus        string     `json:"verification_status"`
+	VerificationProvider      *string    `json:"verification_provider,omitempty"`
+	VerificationRequestedAt   *time.Time `json:"verification_requested_at,omitempty"`
+	VerifiedAt                *time.Time `json:"verified_at,omitempty"`
+	VerificationFailureReason *string    `json:"verification_failure_reason,omitempty"`
+}
+
+type BankAccountHistoryEntryOutput struct {
+	ID            string          `json:"id"`
+	BankAccountID string          `json:"bank_account_id"`
+	BankCode      string          `json:"bank_code"`
+	BranchNumber  string          `json:"branch_number"`
+	AccountNumber string          `json:"account_number"`
+	Action        string          `json:"action"`
+	ActorUserID   *string         `json:"actor_user_id,omitempty"`
+	ActorEmail    *string         `json:"actor_email,omitempty"`
+	Metadata      json.RawMessage `json:"metadata"`
+	CreatedAt     time.Time       `json:"created_at"`
+}
+
+type RequestBankAccountChangeInput struct {
+	ChangeType    string            `json:"change_type" binding:"required"`
+	BankAccount   *BankAccountInput `json:"bank_account"`
+	BankAccountID *string           `json:"bank_account_id" binding:"omitempty,max=36"`
+	Reason        *string           `json:"reason" binding:"omitempty,max=500"`
+}
+
+type ReviewBankAccountChangeInput struct {
+	Notes *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+type BankAccountChangeOutput struct {
+	ID                string     `json:"id"`
+	ClinicID          string     `json:"clinic_id"`
+	ChangeType        string     `json:"change_type"`
+	Status            string     `json:"status"`
+	BankAccountID     *string    `json:"bank_account_id,omitempty"`
+	BankCode          *string    `json:"bank_code,omitempty"`
+	BranchNumber      *string    `json:"branch_number,omitempty"`
+	AccountNumber     *string    `json:"account_number,omitempty"`
+	AccountType       *string    `json:"account_type,omitempty"`
+	HolderName        *string    `json:"holder_name,omitempty"`
+	HolderTaxID       *string    `json:"holder_tax_id,omitempty"`
+	Reason            *string    `json:"reason,omitempty"`
+	RequestedByUserID string     `json:"requested_by_user_id"`
+	RequestedAt       time.Time  `json:"requested_at"`
+	ReviewedByUserID  *string    `json:"reviewed_by_user_id,omitempty"`
+	ReviewedAt        *time.Time `json:"reviewed_at,omitempty"`
+	ReviewNotes       *string    `json:"review_notes,omitempty"`
+}
+
+type CreateClinicPayableInput struct {
+	AmountCents       int64   `json:"amount_cents" binding:"required"`
+	Description       string  `json:"description" binding:"required,max=255"`
+	ExternalReference *string `json:"external_reference" binding:"omitempty,max=100"`
+	OccurredOn        string  `json:"occurred_on" binding:"required,max=10"`
+}
+
+type ClinicPayableOutput struct {
+	ID                string    `json:"id"`
+	ClinicID          string    `json:"clinic_id"`
+	AmountCents       int64     `json:"amount_cents"`
+	Description       string    `json:"description"`
+	ExternalReference *string   `json:"external_reference,omitempty"`
+	OccurredOn        string    `json:"occurred_on"`
+	PayoutBatchID     *string   `json:"payout_batch_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type CreatePayoutBatchInput struct {
+	Format      string `json:"format" binding:"required,max=20"`
+	PeriodStart string `json:"period_start" binding:"required,max=10"`
+	PeriodEnd   string `json:"period_end" binding:"required,max=10"`
+	PaymentDate string `json:"payment_date" binding:"required,max=10"`
+}
+
+type CreateLedgerEntryInput struct {
+	Kind        string `json:"kind" binding:"required,max=20"`
+	AmountCents int64  `json:"amount_cents" binding:"required"`
+	Description string `json:"description" binding:"required,max=255"`
+	OccurredOn  string `json:"occurred_on" binding:"required,max=10"`
+}
+
+type LedgerOutput struct {
+	ClinicID            string                    `json:"clinic_id"`
+	From                string                    `json:"from"`
+	To                  string                    `json:"to"`
+	OpeningBalanceCents int64                     `json:"opening_balance_cents"`
+	ClosingBalanceCents int64                     `json:"closing_balance_cents"`
+	Transactions        []LedgerTransactionOutput `json:"transactions"`
+}
+
+type LedgerTransactionOutput struct {
+	ID              string              `json:"id"`
+	Kind            string              `json:"kind"`
+	Description     string              `json:"description"`
+	ReferenceType   *string             `json:"reference_type,omitempty"`
+	ReferenceID     *string             `json:"reference_id,omitempty"`
+	OccurredOn      string              `json:"occurred_on"`
+	AmountCents     int64               `json:"amount_cents"`
+	BalanceCents    int64               `json:"balance_cents"`
+	Entries         []LedgerEntryOutput `json:"entries"`
+	CreatedByUserID *string             `json:"created_by_user_id,omitempty"`
+	CreatedAt       time.Time           `json:"created_at"`
+}
+
+type LedgerEntryOutput struct {
+	Account     string `json:"account"`
+	Direction   string `json:"direction"`
+	AmountCents int64  `json:"amount_cents"`
+}
+
+type LedgerFileOutput struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type UpdatePayoutBatchStatusInput struct {
+	Status string  `json:"status" binding:"required,max=20"`
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type PayoutBatchOutput struct {
+	ID              string                  `json:"id"`
+	FileSequence    int64                   `json:"file_sequence"`
+	Format          string                  `json:"format"`
+	Status          string                  `json:"status"`
+	PeriodStart     string                  `json:"period_start"`
+	PeriodEnd       string                  `json:"period_end"`
+	PaymentDate     string                  `json:"payment_date"`
+	TotalCents      int64                   `json:"total_cents"`
+	ItemCount       int                     `json:"item_count"`
+	FileName        string                  `json:"file_name"`
+	FailureReason   *string                 `json:"failure_reason,omitempty"`
+	CreatedByUserID *string                 `json:"created_by_user_id,omitempty"`
+	Items           []PayoutBatchItemOutput `json:"items,omitempty"`
+	Skipped         []PayoutSkippedClinic   `json:"skipped"`
+	StatusChangedAt time.Time               `json:"status_changed_at"`
+	CreatedAt       time.Time               `json:"created_at"`
+}
+
+type PayoutBatchItemOutput struct {
+	ClinicID      string `json:"clinic_id"`
+	BankAccountID string `json:"bank_account_id"`
+	AmountCents   int64  `json:"amount_cents"`
+	PayableCount  int    `json:"payable_count"`
 	BankCode      string `json:"bank_code"`
 	BranchNumber  string `json:"branch_number"`
 	AccountNumber string `json:"account_number"`
+	AccountType   string `json:"account_type"`
+	HolderName    string `json:"holder_name"`
+	HolderTaxID   string `json:"holder_tax_id"`
+}
+
+type NotificationOutput struct {
+	ID                string     `json:"id"`
+	ClinicID          *string    `json:"clinic_id,omitempty"`
+	Channel           string     `json:"channel"`
+	Template          string     `json:"template"`
+	Recipient         string     `json:"recipient"`
+	Subject           string     `json:"subject,omitempty"`
+	Body              string     `json:"body"`
+	Status            string     `json:"status"`
+	Attempts          int        `json:"attempts"`
+	NextAttemptAt     *time.Time `json:"next_attempt_at,omitempty"`
+	LastError         *string    `json:"last_error,omitempty"`
+	SentAt            *time.Time `json:"sent_at,omitempty"`
+	DeliveryStatus    *string    `json:"delivery_status,omitempty"`
+	BounceType        *string    `json:"bounce_type,omitempty"`
+	DeliveryDetail    *string    `json:"delivery_detail,omitempty"`
+	DeliveryUpdatedAt *time.Time `json:"delivery_updated_at,omitempty"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+type NotificationDeliveryCallbackInput struct {
+	NotificationID string  `json:"notification_id"`
+	Status         string  `json:"status"`
+	BounceType     *string `json:"bounce_type"`
+	Reason         *string `json:"reason"`
+}
+
+type NotificationSuppressionOutput struct {
+	ID             string    `json:"id"`
+	Channel        string    `json:"channel"`
+	Recipient      string    `json:"recipient"`
+	Reason         string    `json:"reason"`
+	NotificationID *string   `json:"notification_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type ChannelDeliverability struct {
+	Channel     string `json:"channel"`
+	Total       int64  `json:"total"`
+	Pending     int64  `json:"pending"`
+	Sent        int64  `json:"sent"`
+	Delivered   int64  `json:"delivered"`
+	HardBounced int64  `json:"hard_bounced"`
+	SoftBounced int64  `json:"soft_bounced"`
+	Failed      int64  `json:"failed"`
+	// BounceRate is hard and soft bounces over sent messages.
+	BounceRate float64 `json:"bounce_rate"`
+}
+
+type ClinicDeliverabilityOutput struct {
+	ClinicID string                  `json:"clinic_id"`
+	From     string                  `json:"from"`
+	To       string                  `json:"to"`
+	Channels []ChannelDeliverability `json:"channels"`
+}
+
+type PayoutSkippedClinic struct {
+	ClinicID string `json:"clinic_id"`
+	Reason   string `json:"reason"`
+}
+
+type PayoutBatchFileOutput struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type BankAccountVerificationCallbackInput struct {
+	Reference string  `json:"reference" binding:"required,max=255"`
+	Status    string  `json:"status" binding:"required"`
+	Reason    *string `json:"reason" binding:"omitempty,max=500"`
 }
 
 type DentistOutput struct {
+	ID           string            `json:"id"`
+	PersonID     string            `json:"person_id"`
+	LegalName    string            `json:"legal_name"`
+	TaxIDNumber  string            `json:"tax_id_number"`
+	Email        *string           `json:"email,omitempty"`
+	Phone        *string           `json:"phone,omitempty"`
+	PhoneDisplay *string           `json:"phone_display,omitempty"`
+	CRONumber    *string           `json:"cro_number,omitempty"`
+	CROState     *string           `json:"cro_state,omitempty"`
+	PhotoURL     *string           `json:"photo_url,omitempty"`
+	ThumbnailURL *string           `json:"thumbnail_url,omitempty"`
+	Address      *AddressOutput    `json:"address,omitempty"`
+	Specialties  []SpecialtyOutput `json:"specialties,omitempty"`
+	DeletedAt    *time.Time        `json:"deleted_at,omitempty"`
+	Warnings     []string          `json:"warnings,omitempty"`
+}
+
+type DentistPhotoOutput struct {
+	PhotoURL     string                `json:"photo_url,omitempty"`
+	ThumbnailURL string                `json:"thumbnail_url,omitempty"`
+	Width        int                   `json:"width,omitempty"`
+	Height       int                   `json:"height,omitempty"`
+	UpdatedAt    *time.Time            `json:"updated_at,omitempty"`
+	Scan         *AttachmentScanOutput `json:"scan,omitempty"`
+}
+
+type AttachmentScanOutput struct {
+	ID            string     `json:"id"`
+	ResourceType  string     `json:"resource_type"`
+	ResourceID    string     `json:"resource_id"`
+	Status        string     `json:"status"`
+	ContentType   string     `json:"content_type"`
+	SizeBytes     int64      `json:"size_bytes"`
+	Signature     *string    `json:"signature,omitempty"`
+	FailureReason *string    `json:"failure_reason,omitempty"`
+	Attempts      int        `json:"attempts"`
+	CreatedAt     time.Time  `json:"created_at"`
+	ScannedAt     *time.Time `json:"scanned_at,omitempty"`
+}
+
+// ReportParameters are the filters of a report: month for TIMESHEET, from and to for LEDGER, none for LOW_STOCK.
+type ReportParameters struct {
+	Month *string `json:"month,omitempty"`
+	From  *string `json:"from,omitempty"`
+	To    *string `json:"to,omitempty"`
+}
+
+type CreateReportInput struct {
+	Type       string           `json:"type" binding:"required,max=32"`
+	Format     string           `json:"format" binding:"required,max=8"`
+	ClinicID   string           `json:"clinic_id" binding:"required,max=36"`
+	Parameters ReportParameters `json:"parameters"`
+}
+
+type ReportOutput struct {
+	ID                string           `json:"id"`
+	ClinicID          string           `json:"clinic_id"`
+	Type              string           `json:"type"`
+	Format            string           `json:"format"`
+	Parameters        ReportParameters `json:"parameters"`
+	Status            string           `json:"status"`
+	Progress          int              `json:"progress"`
+	RowCount          *int             `json:"row_count,omitempty"`
+	FileName          *string          `json:"file_name,omitempty"`
+	ContentType       *string          `json:"content_type,omitempty"`
+	SizeBytes         int64            `json:"size_bytes"`
+	FailureReason     *string          `json:"failure_reason,omitempty"`
+	Attempts          int              `json:"attempts"`
+	RequestedByUserID string           `json:"requested_by_user_id"`
+	CreatedAt         time.Time        `json:"created_at"`
+	StartedAt         *time.Time       `json:"started_at,omitempty"`
+	CompletedAt       *time.Time       `json:"completed_at,omitempty"`
+	ExpiresAt         *time.Time       `json:"expires_at,omitempty"`
+}
+
+type ReportFileOutput struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type ReportFilter struct {
+	Field  string   `json:"field" binding:"required,max=64"`
+	Op     string   `json:"op" binding:"required,max=8"`
+	Value  *string  `json:"value,omitempty" binding:"omitempty,max=255"`
+	Values []string `json:"values,omitempty" binding:"omitempty,dive,max=255"`
+}
+
+type ReportDefinitionSpec struct {
+	Entity  string         `json:"entity" binding:"required,max=64"`
+	Filters []ReportFilter `json:"filters,omitempty" binding:"omitempty,dive"`
+	GroupBy []string       `json:"group_by,omitempty"`
+	Metrics []string       `json:"metrics" binding:"required"`
+	Period  *string        `json:"period,omitempty" binding:"omitempty,max=32"`
+}
+
+type CreateReportDefinitionInput struct {
+	Name       string               `json:"name" binding:"required,max=120"`
+	Definition ReportDefinitionSpec `json:"definition" binding:"required"`
+	Schedule   *string              `json:"schedule" binding:"omitempty,max=16"`
+	Recipients []string             `json:"recipients"`
+}
+
+type UpdateReportDefinitionInput struct {
+	Name       *string               `json:"name" binding:"omitempty,max=120"`
+	Definition *ReportDefinitionSpec `json:"definition"`
+	Schedule   *string               `json:"schedule" binding:"omitempty,max=16"`
+	Recipients *[]string             `json:"recipients"`
+}
+
+type ReportDefinitionOutput struct {
+	ID              string               `json:"id"`
+	ClinicID        string               `json:"clinic_id"`
+	Name            string               `json:"name"`
+	Definition      ReportDefinitionSpec `json:"definition"`
+	Schedule        *string              `json:"schedule,omitempty"`
+	Recipients      []string             `json:"recipients"`
+	NextRunAt       *time.Time           `json:"next_run_at,omitempty"`
+	LastRunAt       *time.Time           `json:"last_run_at,omitempty"`
+	CreatedByUserID string               `json:"created_by_user_id"`
+	CreatedAt       time.Time            `json:"created_at"`
+	UpdatedAt       time.Time            `json:"updated_at"`
+}
+
+type ReportDefinitionResultOutput struct {
+	DefinitionID string    `json:"definition_id"`
+	Columns      []string  `json:"columns"`
+	Rows         [][]any   `json:"rows"`
+	Truncated    bool      `json:"truncated"`
+	From         *string   `json:"from,omitempty"`
+	To           *string   `json:"to,omitempty"`
+	GeneratedAt  time.Time `json:"generated_at"`
+}
+
+type ReportBuilderFilterOutput struct {
+	Field     string   `json:"field"`
+	Type      string   `json:"type"`
+	Operators []string `json:"operators"`
+	Values    []string `json:"values,omitempty"`
+}
+
+type ReportBuilderEntityOutput struct {
+	Name       string                      `json:"name"`
+	Dimensions []string                    `json:"dimensions"`
+	Metrics    []string                    `json:"metrics"`
+	Filters    []ReportBuilderFilterOutput `json:"filters"`
+	Periods    bool                        `json:"periods"`
+}
+
+type ReportBuilderCatalogOutput struct {
+	Entities  []ReportBuilderEntityOutput `json:"entities"`
+	Periods   []string                    `json:"periods"`
+	Schedules []string                    `json:"schedules"`
+}
+
+type SpecialtyOutput struct {
 	ID          string  `json:"id"`
-	PersonID    string  `json:"person_id"`
-	LegalName   string  `json:"legal_name"`
-	TaxIDNumber string  `json:"tax_id_number"`
-	Email       *string `json:"email,omitempty"`
-	Phone       *string `json:"phone,omitempty"`
+	Code        string  `json:"code"`
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
 }
 
 type ClinicDentistOutput struct {
 	DentistOutput
-	IsAdmin               bool      `json:"is_admin"`
-	IsLegalRepresentative bool      `json:"is_legal_representative"`
-	StartedAt             time.Time `json:"started_at"`
+	IsAdmin                bool                     `json:"is_admin"`
+	IsLegalRepresentative  bool                     `json:"is_legal_representative"`
+	StartedAt              time.Time                `json:"started_at"`
+	IsTemporary            bool                     `json:"is_temporary"`
+	PlannedEndAt           *time.Time               `json:"planned_end_at,omitempty"`
+	SubstituteForDentistID *string                  `json:"substitute_for_dentist_id,omitempty"`
+	PreviousPeriods        []EmploymentPeriodOutput `json:"previous_periods,omitempty"`
+	TotalTenureDays        *int                     `json:"total_tenure_days,omitempty"`
+}
+
+type ClinicDentistTenureOutput struct {
+	ClinicID       string     `json:"clinic_id"`
+	DentistID      string     `json:"dentist_id"`
+	Periods        int        `json:"periods"`
+	TotalDays      int        `json:"total_days"`
+	FirstStartedAt *time.Time `json:"first_started_at,omitempty"`
+	Active         bool       `json:"active"`
+}
+
+type EmploymentPeriodOutput struct {
+	ClinicID              string     `json:"clinic_id"`
+	ClinicLegalName       *string    `json:"clinic_legal_name,omitempty"`
+	ClinicTradeName       *string    `json:"clinic_trade_name,omitempty"`
+	DentistID             string     `json:"dentist_id"`
+	IsAdmin               bool       `json:"is_admin"`
+	IsLegalRepresentative bool       `json:"is_legal_representative"`
+	StartedAt             time.Time  `json:"started_at"`
+	EndedAt               *time.Time `json:"ended_at,omitempty"`
+	Active                bool       `json:"active"`
+	DurationDays          int        `json:"duration_days"`
 }
 
 type ClinicOutput struct {
-	ID          string   `json:"id"`
-	PersonID    string   `json:"person_id"`
-	LegalName   string   `json:"legal_name"`
-	TradeName   *string  `json:"trade_name,omitempty"`
-	TaxIDNumber string   `json:"tax_id_number"`
-	Email       *string  `json:"email,omitempty"`
-	Phone       *string  `json:"phone,omitempty"`
-	DentistIDs  []string `json:"dentist_ids"`
+	ID                        string                 `json:"id"`
+	PersonID                  string                 `json:"person_id"`
+	ParentClinicID            *string                `json:"parent_clinic_id,omitempty"`
+	LegalName                 string                 `json:"legal_name"`
+	TradeName                 *string                `json:"trade_name,omitempty"`
+	TaxIDNumber               string                 `json:"tax_id_number"`
+	Email                     *string                `json:"email,omitempty"`
+	Phone                     *string                `json:"phone,omitempty"`
+	PhoneDisplay              *string                `json:"phone_display,omitempty"`
+	Address                   *AddressOutput         `json:"address,omitempty"`
+	Timezone                  string                 `json:"timezone"`
+	OnboardingStatus          string                 `json:"onboarding_status"`
+	OnboardingStatusChangedAt time.Time              `json:"onboarding_status_changed_at"`
+	Status                    string                 `json:"status"`
+	DeactivatedAt             *time.Time             `json:"deactivated_at,omitempty"`
+	DeactivationReason        *string                `json:"deactivation_reason,omitempty"`
+	DeletedAt                 *time.Time             `json:"deleted_at,omitempty"`
+	Registry                  *CompanyRegistryOutput `json:"registry,omitempty"`
+	DentistIDs                []string               `json:"dentist_ids"`
+	Warnings                  []string               `json:"warnings,omitempty"`
+}
+
+type ClinicGroupReportOutput struct {
+	OrganizationID string                    `json:"organization_id"`
+	Clinics        []ClinicGroupMemberReport `json:"clinics"`
+	Totals         ClinicGroupTotals         `json:"totals"`
+}
+
+type ClinicGroupMemberReport struct {
+	ClinicID             string  `json:"clinic_id"`
+	ParentClinicID       *string `json:"parent_clinic_id,omitempty"`
+	IsHeadquarters       bool    `json:"is_headquarters"`
+	LegalName            string  `json:"legal_name"`
+	TradeName            *string `json:"trade_name,omitempty"`
+	TaxIDNumber          string  `json:"tax_id_number"`
+	ActiveDentists       int     `json:"active_dentists"`
+	Admins               int     `json:"admins"`
+	LegalRepresentatives int     `json:"legal_representatives"`
+	TemporaryDentists    int     `json:"temporary_dentists"`
+}
+
+type ClinicGroupTotals struct {
+	Clinics                int `json:"clinics"`
+	Branches               int `json:"branches"`
+	ActiveDentistLinks     int `json:"active_dentist_links"`
+	DistinctActiveDentists int `json:"distinct_active_dentists"`
+	Admins                 int `json:"admins"`
+	LegalRepresentatives   int `json:"legal_representatives"`
+	TemporaryDentists      int `json:"temporary_dentists"`
+}
+
+type CompanyRegistryOutput struct {
+	LegalName          string    `json:"legal_name"`
+	TradeName          *string   `json:"trade_name,omitempty"`
+	CNAECode           *string   `json:"cnae_code,omitempty"`
+	CNAEDescription    *string   `json:"cnae_description,omitempty"`
+	RegistrationStatus string    `json:"registration_status"`
+	Source             string    `json:"source"`
+	FetchedAt          time.Time `json:"fetched_at"`
 }
 
 type ClinicDetailsOutput struct {
 	ClinicOutput
-	BankAccounts []BankAccountOutput `json:"bank_accounts"`
+	BankAccounts   []BankAccountOutput   `json:"bank_accounts"`
+	FinancialHolds []FinancialHoldOutput `json:"financial_holds,omitempty"`
+	AsOf           *time.Time            `json:"as_of,omitempty"`
+}
+
+type PlaceFinancialHoldInput struct {
+	ReasonCode string  `json:"reason_code" binding:"required,max=32"`
+	Notes      *string `json:"notes" binding:"omitempty,max=1000"`
+}
+
+type LiftFinancialHoldInput struct {
+	Notes *string `json:"notes" binding:"omitempty,max=1000"`
+}
+
+type FinancialHoldOutput struct {
+	ID             string     `json:"id"`
+	ClinicID       string     `json:"clinic_id"`
+	ReasonCode     string     `json:"reason_code"`
+	Notes          *string    `json:"notes,omitempty"`
+	Active         bool       `json:"active"`
+	PlacedByUserID string     `json:"placed_by_user_id"`
+	PlacedByEmail  string     `json:"placed_by_email"`
+	PlacedAt       time.Time  `json:"placed_at"`
+	LiftedByUserID *string    `json:"lifted_by_user_id,omitempty"`
+	LiftedByEmail  *string    `json:"lifted_by_email,omitempty"`
+	LiftedAt       *time.Time `json:"lifted_at,omitempty"`
+	LiftNotes      *string    `json:"lift_notes,omitempty"`
+}
+
+type PlaceLegalHoldInput struct {
+	ResourceType  string  `json:"resource_type" binding:"required,max=32"`
+	ResourceID    string  `json:"resource_id" binding:"required"`
+	Reason        string  `json:"reason" binding:"required,max=1000"`
+	CaseReference *string `json:"case_reference" binding:"omitempty,max=255"`
+}
+
+type ReleaseLegalHoldInput struct {
+	Notes *string `json:"notes" binding:"omitempty,max=1000"`
+}
+
+type LegalHoldOutput struct {
+	ID               string     `json:"id"`
+	ResourceType     string     `json:"resource_type"`
+	ResourceID       string     `json:"resource_id"`
+	Reason           string     `json:"reason"`
+	CaseReference    *string    `json:"case_reference,omitempty"`
+	Active           bool       `json:"active"`
+	PlacedByUserID   string     `json:"placed_by_user_id"`
+	PlacedByEmail    string     `json:"placed_by_email"`
+	PlacedAt         time.Time  `json:"placed_at"`
+	ReleasedByUserID *string    `json:"released_by_user_id,omitempty"`
+	ReleasedByEmail  *string    `json:"released_by_email,omitempty"`
+	ReleasedAt       *time.Time `json:"released_at,omitempty"`
+	ReleaseNotes     *string    `json:"release_notes,omitempty"`
+}
+
+type DocumentRetentionRuleInput struct {
+	RetentionDays int `json:"retention_days" binding:"required"`
+}
+
+type DocumentRetentionRuleOutput struct {
+	DocumentKind    string    `json:"document_kind"`
+	RetentionDays   int       `json:"retention_days"`
+	UpdatedByUserID string    `json:"updated_by_user_id"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type DeletedResourceOutput struct {
+	Type        string    `json:"type"`
+	ID          string    `json:"id"`
+	LegalName   string    `json:"legal_name"`
+	TaxIDNumber string    `json:"tax_id_number"`
+	DeletedAt   time.Time `json:"deleted_at"`
+}
+
+type ClinicRevisionOutput struct {
+	ID              string                 `json:"id"`
+	EntityType      string                 `json:"entity_type"`
+	EntityID        string                 `json:"entity_id"`
+	Operation       string                 `json:"operation"`
+	ChangedByUserID *string                `json:"changed_by_user_id,omitempty"`
+	ChangedByEmail  *string                `json:"changed_by_email,omitempty"`
+	ChangedAt       time.Time              `json:"changed_at"`
+	Changes         []RevisionChangeOutput `json:"changes"`
+	Before          json.RawMessage        `json:"before"`
+	After           json.RawMessage        `json:"after"`
+}
+
+type RevisionChangeOutput struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+type ClinicDeletePreviewOutput struct {
+	ClinicID              string     `json:"clinic_id"`
+	DentistLinks          int64      `json:"dentist_links"`
+	BankAccounts          int64      `json:"bank_accounts"`
+	ActiveBranches        int64      `json:"active_branches"`
+	Invoices              int64      `json:"invoices"`
+	OpenPayables          int64      `json:"open_payables"`
+	BlockedReason         *string    `json:"blocked_reason,omitempty"`
+	ConfirmationRequired  bool       `json:"confirmation_required"`
+	ConfirmationToken     *string    `json:"confirmation_token,omitempty"`
+	ConfirmationExpiresAt *time.Time `json:"confirmation_expires_at,omitempty"`
+}
+
+type TrashItemOutput struct {
+	Type              string    `json:"type"`
+	ID                string    `json:"id"`
+	LegalName         string    `json:"legal_name"`
+	TaxIDNumber       string    `json:"tax_id_number"`
+	DeletedAt         time.Time `json:"deleted_at"`
+	CascadeAt         time.Time `json:"cascade_at"`
+	RequestedByUserID *string   `json:"requested_by_user_id,omitempty"`
+	RequestedByEmail  *string   `json:"requested_by_email,omitempty"`
+}
+
+type RetentionPurgeReportOutput struct {
+	RetentionDays int                        `json:"retention_days"`
+	Cutoff        time.Time                  `json:"cutoff"`
+	Items         []RetentionPurgeItemOutput `json:"items"`
+}
+
+type RetentionPurgeItemOutput struct {
+	Type            string    `json:"type"`
+	ID              string    `json:"id"`
+	LegalName       string    `json:"legal_name"`
+	DeletedAt       time.Time `json:"deleted_at"`
+	Action          string    `json:"action"`
+	RetentionReason *string   `json:"retention_reason,omitempty"`
+	OnLegalHold     bool      `json:"on_legal_hold"`
 }
 
 type LoginOutput struct {
+	AccessToken    string `json:"access_token"`
+	TokenType      string `json:"token_type"`
+	ExpiresIn      int64  `json:"expires_in"`
+	UserID         string `json:"user_id"`
+	Email          string `json:"email"`
+	Role           string `json:"role"`
+	OrganizationID string `json:"organization_id"`
+}
+
+type AdminLoginOutput struct {
 	AccessToken string `json:"access_token"`
 	TokenType   string `json:"token_type"`
 	ExpiresIn   int64  `json:"expires_in"`
-	UserID      string `json:"user_id"`
+	OperatorID  string `json:"operator_id"`
 	Email       string `json:"email"`
 }
+
+type ImpersonateTenantInput struct {
+	// Empty picks the organization's oldest admin.
+	UserID string `json:"user_id" binding:"omitempty,uuid"`
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+type TenantOutput struct {
+	OrganizationOutput
+	Usage   OrganizationUsageOutput `json:"usage"`
+	Traffic TenantTrafficOutput     `json:"traffic"`
+}
+
+type TenantTrafficOutput struct {
+	WindowMinutes   int     `json:"window_minutes"`
+	Requests        int64   `json:"requests"`
+	ClientErrors    int64   `json:"client_errors"`
+	ServerErrors    int64   `json:"server_errors"`
+	ServerErrorRate float64 `json:"server_error_rate"`
+}
+
+type CreateOrganizationInput struct {
+	Slug          string `json:"slug" binding:"required,max=63"`
+	Name          string `json:"name" binding:"required,max=255"`
+	AdminEmail    string `json:"admin_email" binding:"required,email,max=254"`
+	AdminPassword string `json:"admin_password" binding:"required,min=8,max=1024" sanitize:"-"`
+	// Set to start the organization on a trial instead of ACTIVE.
+	TrialEndsAt *time.Time `json:"trial_ends_at"`
+	// SHARED by default; SCHEMA keeps the tenant's rows in a Postgres schema of its own.
+	Isolation string `json:"isolation"`
+	// BASIC, PRO or ENTERPRISE; ENTERPRISE when omitted.
+	Plan string `json:"plan"`
+	// Region the organization's data must stay in, such as br; no restriction when omitted.
+	DataRegion *string `json:"data_region"`
+}
+
+type OrganizationOutput struct {
+	ID              string                   `json:"id"`
+	Slug            string                   `json:"slug"`
+	Name            string                   `json:"name"`
+	Quotas          OrganizationQuotasOutput `json:"quotas"`
+	Status          string                   `json:"status"`
+	TrialEndsAt     *time.Time               `json:"trial_ends_at,omitempty"`
+	ClosedAt        *time.Time               `json:"closed_at,omitempty"`
+	PurgeAfter      *time.Time               `json:"purge_after,omitempty"`
+	PurgedAt        *time.Time               `json:"purged_at,omitempty"`
+	ExportAvailable bool                     `json:"export_available"`
+	Isolation       string                   `json:"isolation"`
+	Plan            string                   `json:"plan"`
+	DataRegion      *string                  `json:"data_region"`
+	CreatedAt       time.Time                `json:"created_at"`
+	AdminUser       *UserOutput              `json:"admin_user,omitempty"`
+}
+
+// A nil quota means no limit.
+type OrganizationQuotasOutput struct {
+	MaxClinics           *int32 `json:"max_clinics"`
+	MaxDentists          *int32 `json:"max_dentists"`
+	MaxRequestsPerMinute *int32 `json:"max_requests_per_minute"`
+	MaxStorageMB         *int32 `json:"max_storage_mb"`
+}
+
+type UpdateOrganizationQuotasInput struct {
+	MaxClinics           *int32 `json:"max_clinics"`
+	MaxDentists          *int32 `json:"max_dentists"`
+	MaxRequestsPerMinute *int32 `json:"max_requests_per_minute"`
+	MaxStorageMB         *int32 `json:"max_storage_mb"`
+}
+
+type UpdateOrganizationStatusInput struct {
+	Status      string     `json:"status" binding:"required"`
+	TrialEndsAt *time.Time `json:"trial_ends_at"`
+}
+
+type OffboardOrganizationInput struct {
+	ConfirmSlug string `json:"confirm_slug" binding:"required"`
+}
+
+type ShredOrganizationDataKeyInput struct {
+	ConfirmSlug string `json:"confirm_slug" binding:"required"`
+}
+
+type QuotaUsageOutput struct {
+	Used  int64  `json:"used"`
+	Limit *int64 `json:"limit"`
+}
+
+type OrganizationUsageOutput struct {
+	OrganizationID    string           `json:"organization_id"`
+	Clinics           QuotaUsageOutput `json:"clinics"`
+	Dentists          QuotaUsageOutput `json:"dentists"`
+	RequestsPerMinute QuotaUsageOutput `json:"requests_per_minute"`
+	StorageBytes      QuotaUsageOutput `json:"storage_bytes"`
+}
+
+type CreateDentistUserInput struct {
+	Email    string `json:"email" binding:"required,email,max=254"`
+	Password string `json:"password" binding:"required,min=8,max=1024" sanitize:"-"`
+}
+
+type UserOutput struct {
+	ID        string  `json:"id"`
+	Email     string  `json:"email"`
+	Role      string  `json:"role"`
+	DentistID *string `json:"dentist_id,omitempty"`
+}
+
+type UpdateDentistProfileInput struct {
+	Email        *string       `json:"email" binding:"omitempty,max=254"`
+	Phone        *string       `json:"phone" binding:"omitempty,max=20"`
+	Address      *AddressInput `json:"address"`
+	SpecialtyIDs *[]string     `json:"specialty_ids"`
+}
+
+type CreateDentistDocumentInput struct {
+	DocumentType   string  `json:"document_type" binding:"required,max=32"`
+	DocumentNumber *string `json:"document_number" binding:"omitempty,max=64"`
+	IssuedAt       *string `json:"issued_at" binding:"omitempty,max=10"`
+	ExpiresAt      string  `json:"expires_at" binding:"required,max=10"`
+	Notes          *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+type UpdateDentistDocumentInput struct {
+	DocumentNumber *string `json:"document_number" binding:"omitempty,max=64"`
+	IssuedAt       *string `json:"issued_at" binding:"omitempty,max=10"`
+	ExpiresAt      *string `json:"expires_at" binding:"omitempty,max=10"`
+	Notes          *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+type DentistDocumentOutput struct {
+	ID              string  `json:"id"`
+	DentistID       string  `json:"dentist_id"`
+	DocumentType    string  `json:"document_type"`
+	DocumentNumber  *string `json:"document_number,omitempty"`
+	IssuedAt        *string `json:"issued_at,omitempty"`
+	ExpiresAt       string  `json:"expires_at"`
+	Notes           *string `json:"notes,omitempty"`
+	DaysUntilExpiry int     `json:"days_until_expiry"`
+	Status          string  `json:"status"`
+}
+
+type ExpiringDocumentOutput struct {
+	DentistDocumentOutput
+	DentistLegalName string `json:"dentist_legal_name"`
+}
+
+type ReassignDentistPersonInput struct {
+	TaxIDNumber string `json:"tax_id_number" binding:"required,max=32"`
+}
+
+type ReassignDentistPersonOutput struct {
+	Dentist             DentistOutput `json:"dentist"`
+	PreviousPersonID    string        `json:"previous_person_id"`
+	MergedFromDentistID *string       `json:"merged_from_dentist_id,omitempty"`
+}
+
+type OperatingHoursInput struct {
+	Weekday  *int   `json:"weekday" binding:"required"`
+	OpensAt  string `json:"opens_at" binding:"required,max=5"`
+	ClosesAt string `json:"closes_at" binding:"required,max=5"`
+}
+
+type ReplaceOperatingHoursInput struct {
+	Hours []OperatingHoursInput `json:"hours" binding:"max=50,dive"`
+}
+
+type OperatingHoursOutput struct {
+	Weekday     int    `json:"weekday"`
+	WeekdayName string `json:"weekday_name"`
+	OpensAt     string `json:"opens_at"`
+	ClosesAt    string `json:"closes_at"`
+}
+
+type CreateClinicHolidayInput struct {
+	Date string `json:"date" binding:"required,max=10"`
+	Name string `json:"name" binding:"required,max=120"`
+}
+
+type ClinicHolidayOutput struct {
+	ID     *string `json:"id,omitempty"`
+	Date   string  `json:"date"`
+	Name   string  `json:"name"`
+	Source string  `json:"source"`
+}
+
+type ClinicAvailabilityOutput struct {
+	At       time.Time `json:"at"`
+	LocalAt  time.Time `json:"local_at"`
+	Timezone string    `json:"timezone"`
+	Open     bool      `json:"open"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+type UpdateClinicSettingsInput struct {
+	DefaultAppointmentDurationMinutes *int    `json:"default_appointment_duration_minutes"`
+	ReminderLeadMinutes               *[]int  `json:"reminder_lead_minutes"`
+	InvoiceNumberPrefix               *string `json:"invoice_number_prefix" binding:"omitempty,max=10"`
+	Locale                            *string `json:"locale" binding:"omitempty,max=10"`
+	Currency                          *string `json:"currency" binding:"omitempty,max=3"`
+}
+
+type ClinicSettingsOutput struct {
+	ClinicID                          string     `json:"clinic_id"`
+	DefaultAppointmentDurationMinutes int        `json:"default_appointment_duration_minutes"`
+	ReminderLeadMinutes               []int      `json:"reminder_lead_minutes"`
+	InvoiceNumberPrefix               string     `json:"invoice_number_prefix"`
+	Locale                            string     `json:"locale"`
+	Currency                          string     `json:"currency"`
+	IsDefault                         bool       `json:"is_default"`
+	UpdatedAt                         *time.Time `json:"updated_at,omitempty"`
+}
+
+type UpdateClinicDirectoryListingInput struct {
+	Listed      *bool   `json:"listed" binding:"required"`
+	PublicPhone *string `json:"public_phone" binding:"omitempty,max=20"`
+}
+
+type ClinicDirectoryListingOutput struct {
+	ClinicID           string     `json:"clinic_id"`
+	Listed             bool       `json:"listed"`
+	PublicPhone        *string    `json:"public_phone,omitempty"`
+	PublicPhoneDisplay *string    `json:"public_phone_display,omitempty"`
+	UpdatedAt          *time.Time `json:"updated_at,omitempty"`
+	Warnings           []string   `json:"warnings,omitempty"`
+}
+
+type PublicClinicDirectoryFilter struct {
+	Organization *string
+	City         *string
+	State        *string
+	Specialty    *string
+}
+
+type PublicClinicOutput struct {
+	ID                 string                  `json:"id"`
+	Name               string                  `json:"name"`
+	City               string                  `json:"city"`
+	State              string                  `json:"state"`
+	PublicPhone        *string                 `json:"public_phone,omitempty"`
+	PublicPhoneDisplay *string                 `json:"public_phone_display,omitempty"`
+	Specialties        []PublicSpecialtyOutput `json:"specialties"`
+}
+
+type PublicSpecialtyOutput struct {
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
+
+type ListClinicsFilter struct {
+	OnboardingStatus *string
+	StuckForDays     *int
+	IncludeDeleted   bool
+}
+
+type AdvanceClinicOnboardingInput struct {
+	ToStatus          string  `json:"to_status" binding:"required,max=32"`
+	EvidenceReference string  `json:"evidence_reference" binding:"required,max=255"`
+	BankAccountID     *string `json:"bank_account_id" binding:"omitempty,max=36"`
+	Notes             *string `json:"notes" binding:"omitempty,max=1000"`
+}
+
+type ClinicOnboardingOutput struct {
+	ClinicID        string                             `json:"clinic_id"`
+	Status          string                             `json:"status"`
+	StatusChangedAt time.Time                          `json:"status_changed_at"`
+	NextStatus      *string                            `json:"next_status,omitempty"`
+	Transitions     []ClinicOnboardingTransitionOutput `json:"transitions"`
+}
+
+type ClinicOnboardingTransitionOutput struct {
+	ID                string    `json:"id"`
+	FromStatus        string    `json:"from_status"`
+	ToStatus          string    `json:"to_status"`
+	EvidenceReference string    `json:"evidence_reference"`
+	BankAccountID     *string   `json:"bank_account_id,omitempty"`
+	Notes             *string   `json:"notes,omitempty"`
+	PerformedByUserID *string   `json:"performed_by_user_id,omitempty"`
+	CreatedAt         time.Time `json:"created_at"`
+}
+
+type DeactivateClinicInput struct {
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type ClinicDuplicateCandidate struct {
+	ClinicID    string     `json:"clinic_id"`
+	LegalName   string     `json:"legal_name"`
+	TaxIDNumber string     `json:"tax_id_number"`
+	Reason      string     `json:"reason"`
+	Similarity  float64    `json:"similarity"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
+}
+
+type NoteMentionInput struct {
+	EntityType string `json:"entity_type" binding:"required,max=32"`
+	EntityID   string `json:"entity_id" binding:"required,max=36"`
+}
+
+type CreateClinicNoteInput struct {
+	Body     string             `json:"body" binding:"required,max=5000"`
+	Pinned   bool               `json:"pinned"`
+	Mentions []NoteMentionInput `json:"mentions" binding:"omitempty,max=20,dive"`
+}
+
+type UpdateClinicNoteInput struct {
+	Pinned *bool `json:"pinned"`
+}
+
+type NoteMentionOutput struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+}
+
+type ClinicNoteOutput struct {
+	ID           string              `json:"id"`
+	ClinicID     string              `json:"clinic_id"`
+	AuthorUserID string              `json:"author_user_id"`
+	AuthorEmail  string              `json:"author_email"`
+	Body         string              `json:"body"`
+	Pinned       bool                `json:"pinned"`
+	Mentions     []NoteMentionOutput `json:"mentions"`
+	CreatedAt    time.Time           `json:"created_at"`
+	UpdatedAt    time.Time           `json:"updated_at"`
+}
+
+type CreateClinicAnnouncementInput struct {
+	Title string `json:"title" binding:"required,max=120"`
+	Body  string `json:"body" binding:"required,max=2000"`
+}
+
+type ClinicAnnouncementOutput struct {
+	ID             string    `json:"id"`
+	ClinicID       string    `json:"clinic_id"`
+	AuthorUserID   string    `json:"author_user_id"`
+	Title          string    `json:"title"`
+	Body           string    `json:"body"`
+	RecipientCount int64     `json:"recipient_count"`
+	ReadCount      int64     `json:"read_count"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+type AnnouncementReceiptOutput struct {
+	DentistID string     `json:"dentist_id"`
+	LegalName string     `json:"legal_name"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+type DentistAnnouncementOutput struct {
+	ID        string     `json:"id"`
+	ClinicID  string     `json:"clinic_id"`
+	Title     string     `json:"title"`
+	Body      string     `json:"body"`
+	CreatedAt time.Time  `json:"created_at"`
+	ReadAt    *time.Time `json:"read_at"`
+}
+
+type NotificationPreferencesInput struct {
+	Channels []string `json:"channels" binding:"required,max=3"`
+}
+
+type NotificationPreferencesOutput struct {
+	Channels  []string   `json:"channels"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+type CreateInventoryItemInput struct {
+	Name            string  `json:"name" binding:"required,max=120"`
+	SKU             *string `json:"sku" binding:"omitempty,max=64"`
+	Unit            string  `json:"unit" binding:"required,max=20"`
+	MinimumQuantity int64   `json:"minimum_quantity"`
+	InitialQuantity int64   `json:"initial_quantity"`
+}
+
+type UpdateInventoryItemInput struct {
+	Name            *string `json:"name" binding:"omitempty,max=120"`
+	SKU             *string `json:"sku" binding:"omitempty,max=64"`
+	Unit            *string `json:"unit" binding:"omitempty,max=20"`
+	MinimumQuantity *int64  `json:"minimum_quantity"`
+}
+
+type InventoryItemOutput struct {
+	ID              string    `json:"id"`
+	ClinicID        string    `json:"clinic_id"`
+	Name            string    `json:"name"`
+	SKU             *string   `json:"sku,omitempty"`
+	Unit            string    `json:"unit"`
+	Quantity        int64     `json:"quantity"`
+	MinimumQuantity int64     `json:"minimum_quantity"`
+	LowStock        bool      `json:"low_stock"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type CreateInventoryMovementInput struct {
+	Kind          string  `json:"kind" binding:"required"`
+	Quantity      int64   `json:"quantity"`
+	UnitCostCents *int64  `json:"unit_cost_cents"`
+	Notes         *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+type InventoryMovementOutput struct {
+	ID              string    `json:"id"`
+	ItemID          string    `json:"item_id"`
+	Kind            string    `json:"kind"`
+	QuantityDelta   int64     `json:"quantity_delta"`
+	QuantityAfter   int64     `json:"quantity_after"`
+	UnitCostCents   *int64    `json:"unit_cost_cents,omitempty"`
+	Notes           *string   `json:"notes,omitempty"`
+	CreatedByUserID *string   `json:"created_by_user_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type LowStockItemOutput struct {
+	InventoryItemOutput
+	Shortfall         int64 `json:"shortfall"`
+	RecentConsumption int64 `json:"recent_consumption"`
+	// DaysOfStock projects the remaining quantity at the recent consumption rate; absent when nothing was consumed.
+	DaysOfStock *float64 `json:"days_of_stock,omitempty"`
+}
+
+type LowStockReportOutput struct {
+	ClinicID              string               `json:"clinic_id"`
+	ConsumptionWindowDays int                  `json:"consumption_window_days"`
+	Items                 []LowStockItemOutput `json:"items"`
+}
+
+type CreateSupplierInput struct {
+	TaxIDNumber string  `json:"tax_id_number" binding:"required,max=32"`
+	LegalName   string  `json:"legal_name" binding:"required,max=255"`
+	TradeName   *string `json:"trade_name" binding:"omitempty,max=255"`
+	Email       *string `json:"email" binding:"omitempty,max=254"`
+	Phone       *string `json:"phone" binding:"omitempty,max=20"`
+	Notes       *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+type UpdateSupplierInput struct {
+	LegalName *string `json:"legal_name" binding:"omitempty,max=255"`
+	TradeName *string `json:"trade_name" binding:"omitempty,max=255"`
+	Email     *string `json:"email" binding:"omitempty,max=254"`
+	Phone     *string `json:"phone" binding:"omitempty,max=20"`
+	Notes     *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+type SupplierOutput struct {
+	ID          string    `json:"id"`
+	PersonID    string    `json:"person_id"`
+	TaxIDNumber string    `json:"tax_id_number"`
+	LegalName   string    `json:"legal_name"`
+	TradeName   *string   `json:"trade_name,omitempty"`
+	Email       *string   `json:"email,omitempty"`
+	Phone       *string   `json:"phone,omitempty"`
+	Notes       *string   `json:"notes,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type PurchaseOrderLineInput struct {
+	ItemID        string `json:"item_id" binding:"required"`
+	Quantity      int64  `json:"quantity"`
+	UnitCostCents int64  `json:"unit_cost_cents"`
+}
+
+type CreatePurchaseOrderInput struct {
+	SupplierID string                   `json:"supplier_id" binding:"required"`
+	Notes      *string                  `json:"notes" binding:"omitempty,max=500"`
+	Items      []PurchaseOrderLineInput `json:"items" binding:"required,min=1,max=100,dive"`
+}
+
+type PurchaseOrderReceiptLineInput struct {
+	OrderItemID string `json:"order_item_id" binding:"required"`
+	Quantity    int64  `json:"quantity"`
+}
+
+type ReceivePurchaseOrderInput struct {
+	Items []PurchaseOrderReceiptLineInput `json:"items" binding:"required,min=1,max=100,dive"`
+	Notes *string                         `json:"notes" binding:"omitempty,max=500"`
+}
+
+type PurchaseOrderItemOutput struct {
+	ID               string `json:"id"`
+	ItemID           string `json:"item_id"`
+	ItemName         string `json:"item_name"`
+	Unit             string `json:"unit"`
+	QuantityOrdered  int64  `json:"quantity_ordered"`
+	QuantityReceived int64  `json:"quantity_received"`
+	UnitCostCents    int64  `json:"unit_cost_cents"`
+	TotalCents       int64  `json:"total_cents"`
+}
+
+type PurchaseOrderOutput struct {
+	ID              string                    `json:"id"`
+	ClinicID        string                    `json:"clinic_id"`
+	SupplierID      string                    `json:"supplier_id"`
+	Status          string                    `json:"status"`
+	Notes           *string                   `json:"notes,omitempty"`
+	TotalCents      int64                     `json:"total_cents"`
+	ReceivedCents   int64                     `json:"received_cents"`
+	Items           []PurchaseOrderItemOutput `json:"items"`
+	CreatedByUserID *string                   `json:"created_by_user_id,omitempty"`
+	SubmittedAt     *time.Time                `json:"submitted_at,omitempty"`
+	ReceivedAt      *time.Time                `json:"received_at,omitempty"`
+	CancelledAt     *time.Time                `json:"cancelled_at,omitempty"`
+	CreatedAt       time.Time                 `json:"created_at"`
+	UpdatedAt       time.Time                 `json:"updated_at"`
+}
+
+type SupplierSpendOutput struct {
+	SupplierID    string `json:"supplier_id"`
+	LegalName     string `json:"legal_name"`
+	TaxIDNumber   string `json:"tax_id_number"`
+	OrderCount    int64  `json:"order_count"`
+	UnitsReceived int64  `json:"units_received"`
+	SpendCents    int64  `json:"spend_cents"`
+}
+
+type SupplierSpendReportOutput struct {
+	ClinicID   *string               `json:"clinic_id,omitempty"`
+	From       string                `json:"from"`
+	To         string                `json:"to"`
+	TotalCents int64                 `json:"total_cents"`
+	Suppliers  []SupplierSpendOutput `json:"suppliers"`
+}
+
+type CreateEquipmentInput struct {
+	Name                    string  `json:"name" binding:"required,max=120"`
+	Kind                    string  `json:"kind" binding:"required"`
+	Manufacturer            *string `json:"manufacturer" binding:"omitempty,max=120"`
+	Model                   *string `json:"model" binding:"omitempty,max=120"`
+	SerialNumber            *string `json:"serial_number" binding:"omitempty,max=64"`
+	MaintenanceIntervalDays *int    `json:"maintenance_interval_days"`
+	LastMaintainedAt        *string `json:"last_maintained_at"`
+	NextMaintenanceDue      *string `json:"next_maintenance_due"`
+	Notes                   *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+type UpdateEquipmentInput struct {
+	Name         *string `json:"name" binding:"omitempty,max=120"`
+	Kind         *string `json:"kind"`
+	Manufacturer *string `json:"manufacturer" binding:"omitempty,max=120"`
+	Model        *string `json:"model" binding:"omitempty,max=120"`
+	SerialNumber *string `json:"serial_number" binding:"omitempty,max=64"`
+	// MaintenanceIntervalDays of 0 removes the recurring schedule.
+	MaintenanceIntervalDays *int    `json:"maintenance_interval_days"`
+	NextMaintenanceDue      *string `json:"next_maintenance_due"`
+	Notes                   *string `json:"notes" binding:"omitempty,max=500"`
+}
+
+type EquipmentOutput struct {
+	ID                      string    `json:"id"`
+	ClinicID                string    `json:"clinic_id"`
+	Name                    string    `json:"name"`
+	Kind                    string    `json:"kind"`
+	Manufacturer            *string   `json:"manufacturer,omitempty"`
+	Model                   *string   `json:"model,omitempty"`
+	SerialNumber            *string   `json:"serial_number,omitempty"`
+	MaintenanceIntervalDays *int      `json:"maintenance_interval_days,omitempty"`
+	LastMaintainedAt        *string   `json:"last_maintained_at,omitempty"`
+	NextMaintenanceDue      *string   `json:"next_maintenance_due,omitempty"`
+	DaysUntilDue            *int      `json:"days_until_due,omitempty"`
+	MaintenanceStatus       string    `json:"maintenance_status"`
+	Notes                   *string   `json:"notes,omitempty"`
+	CreatedAt               time.Time `json:"created_at"`
+	UpdatedAt               time.Time `json:"updated_at"`
+}
+
+type CreateMaintenanceRecordInput struct {
+	Kind               string  `json:"kind" binding:"required"`
+	PerformedAt        string  `json:"performed_at" binding:"required"`
+	PerformedBy        *string `json:"performed_by" binding:"omitempty,max=255"`
+	CostCents          *int64  `json:"cost_cents"`
+	NextMaintenanceDue *string `json:"next_maintenance_due"`
+	Notes              *string `json:"notes" binding:"omitempty,max=1000"`
+}
+
+type MaintenanceRecordOutput struct {
+	ID                 string    `json:"id"`
+	EquipmentID        string    `json:"equipment_id"`
+	Kind               string    `json:"kind"`
+	PerformedAt        string    `json:"performed_at"`
+	PerformedBy        *string   `json:"performed_by,omitempty"`
+	CostCents          *int64    `json:"cost_cents,omitempty"`
+	NextMaintenanceDue *string   `json:"next_maintenance_due,omitempty"`
+	Notes              *string   `json:"notes,omitempty"`
+	CreatedByUserID    *string   `json:"created_by_user_id,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
+}
+
+type EquipmentComplianceReportOutput struct {
+	ClinicID    string            `json:"clinic_id"`
+	AsOf        string            `json:"as_of"`
+	WithinDays  int               `json:"within_days"`
+	Overdue     int               `json:"overdue"`
+	DueSoon     int               `json:"due_soon"`
+	Unscheduled int               `json:"unscheduled"`
+	Equipment   []EquipmentOutput `json:"equipment"`
+}
+
+type CreateClinicShiftInput struct {
+	DentistID string    `json:"dentist_id" binding:"required"`
+	StartsAt  time.Time `json:"starts_at" binding:"required"`
+	EndsAt    time.Time `json:"ends_at" binding:"required"`
+	Notes     *string   `json:"notes" binding:"omitempty,max=500"`
+	Override  bool      `json:"override"`
+}
+
+type UpdateClinicShiftInput struct {
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Notes    *string    `json:"notes" binding:"omitempty,max=500"`
+	Override bool       `json:"override"`
+}
+
+type ClinicShiftOutput struct {
+	ID              string    `json:"id"`
+	ClinicID        string    `json:"clinic_id"`
+	DentistID       string    `json:"dentist_id"`
+	DentistName     string    `json:"dentist_name,omitempty"`
+	StartsAt        time.Time `json:"starts_at"`
+	EndsAt          time.Time `json:"ends_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Notes           *string   `json:"notes,omitempty"`
+	CreatedByUserID *string   `json:"created_by_user_id,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+type ShiftBoardDayOutput struct {
+	Date    string              `json:"date"`
+	Weekday string              `json:"weekday"`
+	Holiday *string             `json:"holiday,omitempty"`
+	Shifts  []ClinicShiftOutput `json:"shifts"`
+}
+
+type ShiftBoardDentistOutput struct {
+	DentistID        string `json:"dentist_id"`
+	DentistName      string `json:"dentist_name"`
+	ShiftCount       int    `json:"shift_count"`
+	ScheduledMinutes int    `json:"scheduled_minutes"`
+}
+
+type ShiftBoardOutput struct {
+	ClinicID string                    `json:"clinic_id"`
+	Timezone string                    `json:"timezone"`
+	Week     string                    `json:"week"`
+	StartsOn string                    `json:"starts_on"`
+	EndsOn   string                    `json:"ends_on"`
+	Days     []ShiftBoardDayOutput     `json:"days"`
+	Dentists []ShiftBoardDentistOutput `json:"dentists"`
+}
+
+type UpdateTimeClockSettingsInput struct {
+	Latitude        *float64 `json:"latitude"`
+	Longitude       *float64 `json:"longitude"`
+	RadiusMeters    *int     `json:"radius_meters"`
+	AllowedNetworks []string `json:"allowed_networks" binding:"max=20"`
+	Enforce         bool     `json:"enforce"`
+}
+
+type TimeClockSettingsOutput struct {
+	ClinicID        string     `json:"clinic_id"`
+	Latitude        *float64   `json:"latitude,omitempty"`
+	Longitude       *float64   `json:"longitude,omitempty"`
+	RadiusMeters    *int       `json:"radius_meters,omitempty"`
+	AllowedNetworks []string   `json:"allowed_networks"`
+	Enforce         bool       `json:"enforce"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty"`
+}
+
+type TimeClockPunchInput struct {
+	Latitude       *float64 `json:"latitude"`
+	Longitude      *float64 `json:"longitude"`
+	AccuracyMeters *float64 `json:"accuracy_meters"`
+	// IP is the caller's address, filled in by the HTTP layer.
+	IP string `json:"-"`
+}
+
+type TimeClockPunchOutput struct {
+	At             time.Time `json:"at"`
+	IP             *string   `json:"ip,omitempty"`
+	Latitude       *float64  `json:"latitude,omitempty"`
+	Longitude      *float64  `json:"longitude,omitempty"`
+	AccuracyMeters *float64  `json:"accuracy_meters,omitempty"`
+	DistanceMeters *float64  `json:"distance_meters,omitempty"`
+	Verified       *bool     `json:"verified,omitempty"`
+}
+
+type TimeClockEntryOutput struct {
+	ID            string                `json:"id"`
+	ClinicID      string                `json:"clinic_id"`
+	DentistID     string                `json:"dentist_id"`
+	ClockIn       TimeClockPunchOutput  `json:"clock_in"`
+	ClockOut      *TimeClockPunchOutput `json:"clock_out,omitempty"`
+	WorkedMinutes *int                  `json:"worked_minutes,omitempty"`
+	CreatedAt     time.Time             `json:"created_at"`
+	UpdatedAt     time.Time             `json:"updated_at"`
+}
+
+type TimesheetDayOutput struct {
+	Date              string     `json:"date"`
+	FirstClockIn      time.Time  `json:"first_clock_in"`
+	LastClockOut      *time.Time `json:"last_clock_out,omitempty"`
+	Entries           int        `json:"entries"`
+	WorkedMinutes     int        `json:"worked_minutes"`
+	OpenEntries       int        `json:"open_entries"`
+	UnverifiedPunches int        `json:"unverified_punches"`
+}
+
+type TimesheetDentistOutput struct {
+	DentistID     string               `json:"dentist_id"`
+	DentistName   string               `json:"dentist_name"`
+	TaxIDNumber   string               `json:"tax_id_number"`
+	WorkedMinutes int                  `json:"worked_minutes"`
+	Days          []TimesheetDayOutput `json:"days"`
+}
+
+type TimesheetOutput struct {
+	ClinicID string                   `json:"clinic_id"`
+	Month    string                   `json:"month"`
+	Timezone string                   `json:"timezone"`
+	Dentists []TimesheetDentistOutput `json:"dentists"`
+}
+
+type TimesheetFileOutput struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type TaskLinkInput struct {
+	EntityType string `json:"entity_type" binding:"required,max=32"`
+	EntityID   string `json:"entity_id" binding:"required,max=36"`
+}
+
+type CreateClinicTaskInput struct {
+	Title          string         `json:"title" binding:"required,max=200"`
+	Description    *string        `json:"description" binding:"omitempty,max=2000"`
+	DueDate        *string        `json:"due_date"`
+	AssigneeUserID *string        `json:"assignee_user_id" binding:"omitempty,max=36"`
+	Link           *TaskLinkInput `json:"link"`
+}
+
+// UpdateClinicTaskInput treats an empty description, due_date or assignee_user_id as clearing the field.
+type UpdateClinicTaskInput struct {
+	Title          *string `json:"title" binding:"omitempty,max=200"`
+	Description    *string `json:"description" binding:"omitempty,max=2000"`
+	Status         *string `json:"status"`
+	DueDate        *string `json:"due_date"`
+	AssigneeUserID *string `json:"assignee_user_id" binding:"omitempty,max=36"`
+}
+
+type UpdateOwnTaskInput struct {
+	Status string `json:"status" binding:"required"`
+}
+
+type TaskLinkOutput struct {
+	EntityType string `json:"entity_type"`
+	EntityID   string `json:"entity_id"`
+}
+
+type ClinicTaskOutput struct {
+	ID                string          `json:"id"`
+	ClinicID          string          `json:"clinic_id"`
+	Title             string          `json:"title"`
+	Description       *string         `json:"description,omitempty"`
+	Status            string          `json:"status"`
+	DueDate           *string         `json:"due_date,omitempty"`
+	Overdue           bool            `json:"overdue"`
+	AssigneeUserID    *string         `json:"assignee_user_id,omitempty"`
+	Link              *TaskLinkOutput `json:"link,omitempty"`
+	Source            string          `json:"source"`
+	AutomationTrigger *string         `json:"automation_trigger,omitempty"`
+	CreatedByUserID   *string         `json:"created_by_user_id,omitempty"`
+	CompletedAt       *time.Time      `json:"completed_at,omitempty"`
+	CreatedAt         time.Time       `json:"created_at"`
+	UpdatedAt         time.Time       `json:"updated_at"`
+}
+
+type TaskRuleInput struct {
+	Trigger        string  `json:"trigger" binding:"required"`
+	AssigneeUserID *string `json:"assignee_user_id" binding:"omitempty,max=36"`
+	DueInDays      int     `json:"due_in_days"`
+	LeadDays       int     `json:"lead_days"`
+}
+
+type ReplaceTaskRulesInput struct {
+	Rules []TaskRuleInput `json:"rules" binding:"max=10,dive"`
+}
+
+type TaskRuleOutput struct {
+	Trigger        string    `json:"trigger"`
+	AssigneeUserID *string   `json:"assignee_user_id,omitempty"`
+	DueInDays      int       `json:"due_in_days"`
+	LeadDays       int       `json:"lead_days"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type WorkspaceModuleOutput struct {
+	Key             string  `json:"key"`
+	Enabled         bool    `json:"enabled"`
+	RequiredFeature *string `json:"required_feature,omitempty"`
+}
+
+type WorkspaceWidgetOutput struct {
+	Key      string `json:"key"`
+	Module   string `json:"module"`
+	Endpoint string `json:"endpoint"`
+}
+
+type WorkspaceConfigOutput struct {
+	ClinicID      string                  `json:"clinic_id"`
+	UserID        string                  `json:"user_id"`
+	Role          string                  `json:"role"`
+	IsClinicAdmin bool                    `json:"is_clinic_admin"`
+	Plan          string                  `json:"plan"`
+	Modules       []WorkspaceModuleOutput `json:"modules"`
+	Widgets       []WorkspaceWidgetOutput `json:"widgets"`
+}
+
+type CreateDocumentTemplateInput struct {
+	Name  string `json:"name" binding:"required,max=120"`
+	Kind  string `json:"kind" binding:"required"`
+	Title string `json:"title" binding:"required,max=200"`
+	Body  string `json:"body" binding:"required,max=20000"`
+}
+
+type UpdateDocumentTemplateInput struct {
+	Name  *string `json:"name" binding:"omitempty,max=120"`
+	Kind  *string `json:"kind"`
+	Title *string `json:"title" binding:"omitempty,max=200"`
+	Body  *string `json:"body" binding:"omitempty,max=20000"`
+}
+
+type DocumentTemplateOutput struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	Kind         string    `json:"kind"`
+	Title        string    `json:"title"`
+	Body         string    `json:"body"`
+	Placeholders []string  `json:"placeholders"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// Patient and treatment plan data only fill the placeholders of the generated PDF; the API does not keep it anywhere else.
+type GenerateDocumentInput struct {
+	TemplateID string                `json:"template_id" binding:"required,max=36"`
+	ClinicID   string                `json:"clinic_id" binding:"required,max=36"`
+	DentistID  *string               `json:"dentist_id" binding:"omitempty,max=36"`
+	Patient    *DocumentPatientInput `json:"patient"`
+	Items      []DocumentItemInput   `json:"items" binding:"max=100,dive"`
+}
+
+type DocumentPatientInput struct {
+	Name        string  `json:"name" binding:"required,max=200"`
+	TaxIDNumber *string `json:"tax_id_number" binding:"omitempty,max=20"`
+}
+
+type DocumentItemInput struct {
+	Description    string `json:"description" binding:"required,max=200"`
+	Quantity       *int   `json:"quantity"`
+	UnitPriceCents int64  `json:"unit_price_cents"`
+}
+
+type GeneratedDocumentOutput struct {
+	ID              string    `json:"id"`
+	ClinicID        string    `json:"clinic_id"`
+	TemplateID      string    `json:"template_id"`
+	DentistID       *string   `json:"dentist_id,omitempty"`
+	Kind            string    `json:"kind"`
+	Title           string    `json:"title"`
+	ContentType     string    `json:"content_type"`
+	SizeBytes       int64     `json:"size_bytes"`
+	ChecksumSHA256  string    `json:"checksum_sha256"`
+	Placeholders    []string  `json:"placeholders"`
+	CreatedByUserID string    `json:"created_by_user_id"`
+	CreatedAt       time.Time `json:"created_at"`
+}
+
+type GeneratedDocumentFileOutput struct {
+	FileName    string
+	ContentType string
+	Content     []byte
+}
+
+type DocumentSignerInput struct {
+	Name  string `json:"name" binding:"required,max=255"`
+	Email string `json:"email" binding:"required,max=255"`
+}
+
+type CreateDocumentSignatureRequestInput struct {
+	Signers []DocumentSignerInput `json:"signers" binding:"required,dive"`
+}
+
+type DocumentSignatureCallbackInput struct {
+	Reference string  `json:"reference" binding:"required,max=255"`
+	Status    string  `json:"status" binding:"required"`
+	Reason    *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+type DocumentSignerOutput struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+type DocumentSignatureRequestOutput struct {
+	ID                   string                 `json:"id"`
+	DocumentID           string                 `json:"document_id"`
+	ClinicID             string                 `json:"clinic_id"`
+	Provider             string                 `json:"provider"`
+	Status               string                 `json:"status"`
+	Signers              []DocumentSignerOutput `json:"signers"`
+	FailureReason        *string                `json:"failure_reason,omitempty"`
+	HasSignedFile        bool                   `json:"has_signed_file"`
+	SignedSizeBytes      *int64                 `json:"signed_size_bytes,omitempty"`
+	SignedChecksumSHA256 *string                `json:"signed_checksum_sha256,omitempty"`
+	CreatedByUserID      string                 `json:"created_by_user_id"`
+	CompletedAt          *time.Time             `json:"completed_at,omitempty"`
+	CreatedAt            time.Time              `json:"created_at"`
+	UpdatedAt            time.Time              `json:"updated_at"`
+}
+
+type AuditLogRecord struct {
+	ID          string          `json:"id"`
+	ClinicID    *string         `json:"clinic_id,omitempty"`
+	ActorUserID *string         `json:"actor_user_id,omitempty"`
+	Action      string          `json:"action"`
+	EntityType  string          `json:"entity_type"`
+	EntityID    string          `json:"entity_id"`
+	Metadata    json.RawMessage `json:"metadata"`
+	CreatedAt   time.Time       `json:"created_at"`
+}
+
+type WarehouseExportWatermarkOutput struct {
+	Sink          string    `json:"sink"`
+	Table         string    `json:"table"`
+	LastChangedAt time.Time `json:"last_changed_at"`
+	LastID        string    `json:"last_id"`
+	ExportedCount int64     `json:"exported_count"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type IntegrityReportOutput struct {
+	CheckedAt time.Time              `json:"checked_at"`
+	Repair    bool                   `json:"repair"`
+	Checks    []IntegrityCheckOutput `json:"checks"`
+}
+
+type IntegrityCheckOutput struct {
+	Check       string   `json:"check"`
+	EntityType  string   `json:"entity_type"`
+	Repairable  bool     `json:"repairable"`
+	Count       int      `json:"count"`
+	Truncated   bool     `json:"truncated"`
+	EntityIDs   []string `json:"entity_ids"`
+	RepairedIDs []string `json:"repaired_ids"`
+}
+
+type AuditChainVerificationOutput struct {
+	Valid           bool                   `json:"valid"`
+	VerifiedAt      time.Time              `json:"verified_at"`
+	VerifiedEntries int64                  `json:"verified_entries"`
+	HeadSequence    int64                  `json:"head_sequence"`
+	HeadHash        string                 `json:"head_hash"`
+	UnsealedEntries int64                  `json:"unsealed_entries"`
+	Break           *AuditChainBreakOutput `json:"break,omitempty"`
+}
+
+type AuditChainBreakOutput struct {
+	Sequence   int64   `json:"sequence"`
+	AuditLogID *string `json:"audit_log_id,omitempty"`
+	Reason     string  `json:"reason"`
+}
+
+type UpdateOrganizationPlanInput struct {
+	Plan string `json:"plan" binding:"required"`
+}
+
+// A null or empty data_region lifts the restriction.
+type UpdateOrganizationDataRegionInput struct {
+	DataRegion *string `json:"data_region"`
+}
+
+type EntitlementsOutput struct {
+	Plan     string                     `json:"plan"`
+	Features []FeatureEntitlementOutput `json:"features"`
+}
+
+type FeatureEntitlementOutput struct {
+	Feature string `json:"feature"`
+	Enabled bool   `json:"enabled"`
+}
+
+// Counters (api_calls, sms_sent) are summed over the range; gauges (active_clinics, storage_bytes) report the peak.
+type TenantUsageHistoryOutput struct {
+	OrganizationID string            `json:"organization_id"`
+	From           string            `json:"from"`
+	To             string            `json:"to"`
+	Totals         UsageTotalsOutput `json:"totals"`
+	Days           []UsageDayOutput  `json:"days"`
+}
+
+type UsageTotalsOutput struct {
+	APICalls          int64 `json:"api_calls"`
+	SMSSent           int64 `json:"sms_sent"`
+	PeakActiveClinics int64 `json:"peak_active_clinics"`
+	PeakStorageBytes  int64 `json:"peak_storage_bytes"`
+}
+
+type UsageDayOutput struct {
+	Day           string `json:"day"`
+	APICalls      int64  `json:"api_calls"`
+	ActiveClinics int64  `json:"active_clinics"`
+	StorageBytes  int64  `json:"storage_bytes"`
+	SMSSent       int64  `json:"sms_sent"`
+}
+
+// Blocks the API cannot fill, or that failed or ran out of time, come back UNAVAILABLE with the reason and no items.
+type DashboardBlockOutput[T any] struct {
+	Status string  `json:"status"`
+	Reason *string `json:"reason,omitempty"`
+	Items  []T     `json:"items"`
+}
+
+type OpenPunchOutput struct {
+	TimeClockEntryOutput
+	DentistName string `json:"dentist_name"`
+}
+
+type ClinicDashboardOutput struct {
+	ClinicID           string                                   `json:"clinic_id"`
+	Timezone           string                                   `json:"timezone"`
+	Date               string                                   `json:"date"`
+	Shifts             DashboardBlockOutput[ClinicShiftOutput]  `json:"shifts"`
+	OpenPunches        DashboardBlockOutput[OpenPunchOutput]    `json:"open_punches"`
+	TasksDueToday      DashboardBlockOutput[ClinicTaskOutput]   `json:"tasks_due_today"`
+	LowStock           DashboardBlockOutput[LowStockItemOutput] `json:"low_stock"`
+	OverdueMaintenance DashboardBlockOutput[EquipmentOutput]    `json:"overdue_maintenance"`
+	Appointments       DashboardBlockOutput[struct{}]           `json:"appointments"`
+	CheckInQueue       DashboardBlockOutput[struct{}]           `json:"check_in_queue"`
+	Billing            DashboardBlockOutput[struct{}]           `json:"billing"`
+}
@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	MeterAPICalls      = "api_calls"
+	MeterActiveClinics = "active_clinics"
+	MeterStorageBytes  = "storage_bytes"
+	MeterSMSSent       = "sms_sent"
+
+	defaultUsageRangeDays = 30
+	maxUsageRangeDays     = 366
+)
+
+// apiCallMeter counts authenticated requests between flushes, so metering costs one insert per organization per run rather than one per request.
+// Counts not yet flushed are lost if the process stops.
+type apiCallMeter struct {
+	mu      sync.Mutex
+	pending map[string]int64
+}
+
+func newAPICallMeter() *apiCallMeter {
+	return &apiCallMeter{pending: make(map[string]int64)}
+}
+
+func (m *apiCallMeter) add(organizationID string, count int64) {
+	m.mu.Lock()
+	m.pending[organizationID] += count
+	m.mu.Unlock()
+}
+
+func (m *apiCallMeter) drain() map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	drained := m.pending
+	m.pending = make(map[string]int64)
+	return drained
+}
+
+// FlushUsageMetering writes the API calls counted since the last run and samples every organization's active clinics and storage.
+func (s *Service) FlushUsageMetering(ctx context.Context) error {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.FlushUsageMetering")
+	defer span.End()
+
+	var errs []error
+	if s.apiCalls != nil {
+		for organization, count := range s.apiCalls.drain() {
+			if err := s.recordUsage(WithOrganization(ctx, organization), MeterAPICalls, count); err != nil {
+				// Put back so the next run retries instead of losing the calls.
+				s.apiCalls.add(organization, count)
+				errs = append(errs, fmt.Errorf("organization %s: %w", organization, err))
+			}
+		}
+	}
+	errs = append(errs, s.ForEachOrganization(ctx, func(ctx context.Context) error {
+		usage, err := s.queries.GetOrganizationUsage(ctx, organizationID(ctx))
+		if err != nil {
+			return err
+		}
+		if err := s.recordUsage(ctx, MeterActiveClinics, int64(usage.Clinics)); err != nil {
+			return err
+		}
+		return s.recordUsage(ctx, MeterStorageBytes, usage.StorageBytes)
+	}))
+	return errors.Join(errs...)
+}
+
+// RecordSMSSent meters text messages sent on behalf of the caller's organization.
+func (s *Service) RecordSMSSent(ctx context.Context, count int) error {
+	if count <= 0 {
+		return validationError("count must be positive")
+	}
+	return s.recordUsage(ctx, MeterSMSSent, int64(count))
+}
+
+func (s *Service) recordUsage(ctx context.Context, metric string, quantity int64) error {
+	id, err := newUUIDV7()
+	if err != nil {
+		return err
+	}
+	now := s.now().UTC()
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	if err := qtx.CreateUsageRecord(ctx, repository.CreateUsageRecordParams{
+		ID:             id,
+		OrganizationID: organizationID(ctx),
+		Metric:         metric,
+		Quantity:       quantity,
+		RecordedAt:     now,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+	if err := qtx.UpsertUsageDailyRollup(ctx, repository.UpsertUsageDailyRollupParams{
+		OrganizationID: organizationID(ctx),
+		Metric:         metric,
+		Day:            now.Truncate(24 * time.Hour),
+		Quantity:       quantity,
+	}); err != nil {
+		return mapDatabaseError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
+func (s *Service) GetTenantUsageHistory(ctx context.Context, id string, from *string, to *string) (TenantUsageHistoryOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetTenantUsageHistory")
+	defer span.End()
+
+	toDate := s.now().UTC().Truncate(24 * time.Hour)
+	if to != nil {
+		parsed, err := parseDocumentDate("to", to)
+		if err != nil {
+			return TenantUsageHistoryOutput{}, err
+		}
+		toDate = parsed.Time
+	}
+	fromDate := toDate.AddDate(0, 0, -defaultUsageRangeDays)
+	if from != nil {
+		parsed, err := parseDocumentDate("from", from)
+		if err != nil {
+			return TenantUsageHistoryOutput{}, err
+		}
+		fromDate = parsed.Time
+	}
+	if fromDate.After(toDate) {
+		return TenantUsageHistoryOutput{}, validationError("from must not be after to")
+	}
+	if toDate.Sub(fromDate) > maxUsageRangeDays*24*time.Hour {
+		return TenantUsageHistoryOutput{}, validationError(fmt.Sprintf("range must be at most %d days", maxUsageRangeDays))
+	}
+
+	ctx = WithOrganization(ctx, id)
+	if _, err := s.queries.GetOrganizationByID(ctx, id); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return TenantUsageHistoryOutput{}, notFoundError("organization not found")
+		}
+		return TenantUsageHistoryOutput{}, err
+	}
+	rows, err := s.queries.ListUsageDailyRollups(ctx, repository.ListUsageDailyRollupsParams{
+		OrganizationID: id,
+		FromDay:        fromDate,
+		ToDay:          toDate,
+	})
+	if err != nil {
+		return TenantUsageHistoryOutput{}, err
+	}
+	return mapTenantUsageHistory(id, fromDate, toDate, rows), nil
+}
+
+func mapTenantUsageHistory(id string, from time.Time, to time.Time, rows []repository.UsageDailyRollup) TenantUsageHistoryOutput {
+	output := TenantUsageHistoryOutput{
+		OrganizationID: id,
+		From:           from.Format(documentDateLayout),
+		To:             to.Format(documentDateLayout),
+		Days:           []UsageDayOutput{},
+	}
+	for _, row := range rows {
+		day := row.Day.Format(documentDateLayout)
+		if len(output.Days) == 0 || output.Days[len(output.Days)-1].Day != day {
+			output.Days = append(output.Days, UsageDayOutput{Day: day})
+		}
+		current := &output.Days[len(output.Days)-1]
+		switch row.Metric {
+		case MeterAPICalls:
+			current.APICalls = row.Quantity
+			output.Totals.APICalls += row.Quantity
+		case MeterSMSSent:
+			current.SMSSent = row.Quantity
+			output.Totals.SMSSent += row.Quantity
+		case MeterActiveClinics:
+			current.ActiveClinics = row.Quantity
+			output.Totals.PeakActiveClinics = max(output.Totals.PeakActiveClinics, row.Quantity)
+		case MeterStorageBytes:
+			current.StorageBytes = row.Quantity
+			output.Totals.PeakStorageBytes = max(output.Totals.PeakStorageBytes, row.Quantity)
+		}
+	}
+	return output
+}
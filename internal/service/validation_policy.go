@@ -0,0 +1,111 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type ValidationRule string
+
+const (
+	ValidationRuleTaxID   ValidationRule = "tax_id"
+	ValidationRuleEmail   ValidationRule = "email"
+	ValidationRulePhone   ValidationRule = "phone"
+	ValidationRuleCRO     ValidationRule = "cro"
+	ValidationRuleAddress ValidationRule = "address"
+)
+
+type ValidationMode string
+
+const (
+	ValidationModeEnforce ValidationMode = "enforce"
+	ValidationModeWarn    ValidationMode = "warn"
+	ValidationModeOff     ValidationMode = "off"
+)
+
+var knownValidationRules = map[ValidationRule]struct{}{
+	ValidationRuleTaxID:   {},
+	ValidationRuleEmail:   {},
+	ValidationRulePhone:   {},
+	ValidationRuleCRO:     {},
+	ValidationRuleAddress: {},
+}
+
+type ValidationPolicy map[ValidationRule]ValidationMode
+
+func ParseValidationPolicy(rules map[string]string) (ValidationPolicy, error) {
+	policy := make(ValidationPolicy, len(rules))
+	for rawRule, rawMode := range rules {
+		rule := ValidationRule(strings.ToLower(strings.TrimSpace(rawRule)))
+		if _, ok := knownValidationRules[rule]; !ok {
+			return nil, fmt.Errorf("unknown validation rule %q (known rules: %s)", rawRule, strings.Join(validationRuleNames(), ", "))
+		}
+
+		mode := ValidationMode(strings.ToLower(strings.TrimSpace(rawMode)))
+		switch mode {
+		case ValidationModeEnforce, ValidationModeWarn, ValidationModeOff:
+			policy[rule] = mode
+		default:
+			return nil, fmt.Errorf("invalid mode %q for validation rule %q: must be enforce, warn or off", rawMode, rawRule)
+		}
+	}
+	return policy, nil
+}
+
+func WithValidationPolicy(policy ValidationPolicy) Option {
+	return func(s *Service) {
+		s.validationPolicy = policy
+	}
+}
+
+func (p ValidationPolicy) mode(rule ValidationRule) ValidationMode {
+	if mode, ok := p[rule]; ok {
+		return mode
+	}
+	return ValidationModeEnforce
+}
+
+type validationCollector struct {
+	policy   ValidationPolicy
+	warnings []string
+}
+
+func (s *Service) newValidationCollector() *validationCollector {
+	return &validationCollector{policy: s.validationPolicy}
+}
+
+func (c *validationCollector) check(rule ValidationRule, valid bool, message string) error {
+	if valid {
+		return nil
+	}
+	switch c.policy.mode(rule) {
+	case ValidationModeOff:
+		return nil
+	case ValidationModeWarn:
+		c.warnings = append(c.warnings, message)
+		return nil
+	default:
+		return validationError(message)
+	}
+}
+
+func (c *validationCollector) warn(message string) {
+	c.warnings = append(c.warnings, message)
+}
+
+func (c *validationCollector) result() []string {
+	if len(c.warnings) == 0 {
+		return nil
+	}
+	return c.warnings
+}
+
+func validationRuleNames() []string {
+	names := make([]string, 0, len(knownValidationRules))
+	for rule := range knownValidationRules {
+		names = append(names, string(rule))
+	}
+	sort.Strings(names)
+	return names
+}
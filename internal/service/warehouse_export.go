@@ -0,0 +1,343 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+const (
+	WarehouseColumnText      = "text"
+	WarehouseColumnInteger   = "integer"
+	WarehouseColumnBoolean   = "boolean"
+	WarehouseColumnDate      = "date"
+	WarehouseColumnTimestamp = "timestamp"
+
+	// WarehouseTimestampLayout is how timestamp columns reach the sink: UTC with microseconds, as Postgres stores them.
+	WarehouseTimestampLayout = "2006-01-02T15:04:05.000000Z"
+
+	warehouseExportBatchSize = 1000
+	// Like audit ids, updated_at is stamped before commit, so only rows older than this are read past the watermark.
+	warehouseExportSettleWindow = time.Minute
+)
+
+type WarehouseExportSink interface {
+	Name() string
+	ExportWarehouseBatch(ctx context.Context, batch WarehouseExportBatch) error
+}
+
+type WarehouseColumn struct {
+	Name string
+	Type string
+}
+
+// WarehouseExportBatch holds rows of one table changed after the watermark, in change order. ID is stable across retries, and a
+// retried batch may hold more rows than the first attempt, so sinks should write it under the ID and overwrite.
+type WarehouseExportBatch struct {
+	ID             string
+	OrganizationID string
+	Table          string
+	ChangedFrom    time.Time
+	Columns        []WarehouseColumn
+	Rows           [][]*string
+}
+
+func WithWarehouseExportSink(sink WarehouseExportSink) Option {
+	return func(s *Service) {
+		s.warehouseExport = sink
+	}
+}
+
+type warehouseColumn struct {
+	name string
+	kind string
+	expr string
+}
+
+type warehouseTable struct {
+	name      string
+	changedAt string
+	columns   []warehouseColumn
+}
+
+// warehouseTables lists what BI gets. Secrets, tax ids, free-text notes and location data stay out; tables whose rows change
+// without touching a timestamp can't be followed by a watermark and are left out too.
+var warehouseTables = []warehouseTable{
+	{name: "clinics", changedAt: "t.updated_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"parent_clinic_id", WarehouseColumnText, "t.parent_clinic_id"},
+		{"timezone", WarehouseColumnText, "t.timezone"},
+		{"onboarding_status", WarehouseColumnText, "t.onboarding_status"},
+		{"onboarding_status_changed_at", WarehouseColumnTimestamp, "t.onboarding_status_changed_at"},
+		{"deactivated_at", WarehouseColumnTimestamp, "t.deactivated_at"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+		{"updated_at", WarehouseColumnTimestamp, "t.updated_at"},
+		{"deleted_at", WarehouseColumnTimestamp, "t.deleted_at"},
+	}},
+	{name: "dentists", changedAt: "t.updated_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"cro_state", WarehouseColumnText, "t.cro_state"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+		{"updated_at", WarehouseColumnTimestamp, "t.updated_at"},
+		{"deleted_at", WarehouseColumnTimestamp, "t.deleted_at"},
+	}},
+	{name: "inventory_items", changedAt: "t.updated_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"clinic_id", WarehouseColumnText, "t.clinic_id"},
+		{"name", WarehouseColumnText, "t.name"},
+		{"sku", WarehouseColumnText, "t.sku"},
+		{"unit", WarehouseColumnText, "t.unit"},
+		{"quantity", WarehouseColumnInteger, "t.quantity"},
+		{"minimum_quantity", WarehouseColumnInteger, "t.minimum_quantity"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+		{"updated_at", WarehouseColumnTimestamp, "t.updated_at"},
+		{"deleted_at", WarehouseColumnTimestamp, "t.deleted_at"},
+	}},
+	{name: "inventory_movements", changedAt: "t.created_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"clinic_id", WarehouseColumnText, "t.clinic_id"},
+		{"item_id", WarehouseColumnText, "t.item_id"},
+		{"kind", WarehouseColumnText, "t.kind"},
+		{"quantity_delta", WarehouseColumnInteger, "t.quantity_delta"},
+		{"quantity_after", WarehouseColumnInteger, "t.quantity_after"},
+		{"unit_cost_cents", WarehouseColumnInteger, "t.unit_cost_cents"},
+		{"created_by_user_id", WarehouseColumnText, "t.created_by_user_id"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+	}},
+	{name: "purchase_orders", changedAt: "t.updated_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"clinic_id", WarehouseColumnText, "t.clinic_id"},
+		{"supplier_id", WarehouseColumnText, "t.supplier_id"},
+		{"status", WarehouseColumnText, "t.status"},
+		{"submitted_at", WarehouseColumnTimestamp, "t.submitted_at"},
+		{"received_at", WarehouseColumnTimestamp, "t.received_at"},
+		{"cancelled_at", WarehouseColumnTimestamp, "t.cancelled_at"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+		{"updated_at", WarehouseColumnTimestamp, "t.updated_at"},
+	}},
+	{name: "equipment", changedAt: "t.updated_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"clinic_id", WarehouseColumnText, "t.clinic_id"},
+		{"name", WarehouseColumnText, "t.name"},
+		{"kind", WarehouseColumnText, "t.kind"},
+		{"manufacturer", WarehouseColumnText, "t.manufacturer"},
+		{"model", WarehouseColumnText, "t.model"},
+		{"maintenance_interval_days", WarehouseColumnInteger, "t.maintenance_interval_days"},
+		{"last_maintained_at", WarehouseColumnDate, "t.last_maintained_at"},
+		{"next_maintenance_due", WarehouseColumnDate, "t.next_maintenance_due"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+		{"updated_at", WarehouseColumnTimestamp, "t.updated_at"},
+		{"deleted_at", WarehouseColumnTimestamp, "t.deleted_at"},
+	}},
+	{name: "clinic_shifts", changedAt: "t.updated_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"clinic_id", WarehouseColumnText, "t.clinic_id"},
+		{"dentist_id", WarehouseColumnText, "t.dentist_id"},
+		{"starts_at", WarehouseColumnTimestamp, "t.starts_at"},
+		{"ends_at", WarehouseColumnTimestamp, "t.ends_at"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+		{"updated_at", WarehouseColumnTimestamp, "t.updated_at"},
+		{"deleted_at", WarehouseColumnTimestamp, "t.deleted_at"},
+	}},
+	{name: "time_clock_entries", changedAt: "t.updated_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"clinic_id", WarehouseColumnText, "t.clinic_id"},
+		{"dentist_id", WarehouseColumnText, "t.dentist_id"},
+		{"clock_in_at", WarehouseColumnTimestamp, "t.clock_in_at"},
+		{"clock_in_verified", WarehouseColumnBoolean, "t.clock_in_verified"},
+		{"clock_out_at", WarehouseColumnTimestamp, "t.clock_out_at"},
+		{"clock_out_verified", WarehouseColumnBoolean, "t.clock_out_verified"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+		{"updated_at", WarehouseColumnTimestamp, "t.updated_at"},
+	}},
+	{name: "clinic_tasks", changedAt: "t.updated_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"clinic_id", WarehouseColumnText, "t.clinic_id"},
+		{"title", WarehouseColumnText, "t.title"},
+		{"status", WarehouseColumnText, "t.status"},
+		{"due_date", WarehouseColumnDate, "t.due_date"},
+		{"assignee_user_id", WarehouseColumnText, "t.assignee_user_id"},
+		{"entity_type", WarehouseColumnText, "t.entity_type"},
+		{"entity_id", WarehouseColumnText, "t.entity_id"},
+		{"automation_trigger", WarehouseColumnText, "t.automation_trigger"},
+		{"completed_at", WarehouseColumnTimestamp, "t.completed_at"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+		{"updated_at", WarehouseColumnTimestamp, "t.updated_at"},
+		{"deleted_at", WarehouseColumnTimestamp, "t.deleted_at"},
+	}},
+	{name: "ledger_transactions", changedAt: "t.created_at", columns: []warehouseColumn{
+		{"id", WarehouseColumnText, "t.id"},
+		{"clinic_id", WarehouseColumnText, "t.clinic_id"},
+		{"kind", WarehouseColumnText, "t.kind"},
+		{"reference_type", WarehouseColumnText, "t.reference_type"},
+		{"reference_id", WarehouseColumnText, "t.reference_id"},
+		{"occurred_on", WarehouseColumnDate, "t.occurred_on"},
+		{"clinic_amount_cents", WarehouseColumnInteger, "(SELECT SUM(CASE WHEN e.direction = 'CREDIT' THEN e.amount_cents ELSE -e.amount_cents END) FROM ledger_entries e WHERE e.transaction_id = t.id AND e.account = 'CLINIC_BALANCE')"},
+		{"created_at", WarehouseColumnTimestamp, "t.created_at"},
+	}},
+}
+
+// ExportWarehouseChanges sends every table's rows changed since its watermark to the warehouse sink, one batch at a time,
+// advancing the watermark only after the sink accepted the batch.
+func (s *Service) ExportWarehouseChanges(ctx context.Context) (int, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ExportWarehouseChanges")
+	defer span.End()
+
+	if s.warehouseExport == nil {
+		return 0, nil
+	}
+	if err := s.checkDataResidency(ctx, DestinationWarehouse); err != nil {
+		return 0, err
+	}
+	exported := 0
+	for _, table := range warehouseTables {
+		for {
+			count, err := s.exportWarehouseBatch(ctx, table)
+			exported += count
+			if err != nil {
+				return exported, fmt.Errorf("export %s: %w", table.name, err)
+			}
+			if count < warehouseExportBatchSize {
+				break
+			}
+		}
+	}
+	return exported, nil
+}
+
+func (s *Service) ListWarehouseExportWatermarks(ctx context.Context) ([]WarehouseExportWatermarkOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.ListWarehouseExportWatermarks")
+	defer span.End()
+
+	rows, err := s.queries.ListWarehouseExportWatermarks(ctx, organizationID(ctx))
+	if err != nil {
+		return nil, err
+	}
+	watermarks := make([]WarehouseExportWatermarkOutput, 0, len(rows))
+	for _, row := range rows {
+		watermarks = append(watermarks, WarehouseExportWatermarkOutput{
+			Sink:          row.Sink,
+			Table:         row.TableName,
+			LastChangedAt: row.LastChangedAt,
+			LastID:        row.LastID,
+			ExportedCount: row.ExportedCount,
+			UpdatedAt:     row.UpdatedAt,
+		})
+	}
+	return watermarks, nil
+}
+
+func (s *Service) exportWarehouseBatch(ctx context.Context, table warehouseTable) (int, error) {
+	sink := s.warehouseExport.Name()
+	if err := s.queries.EnsureWarehouseExportWatermark(ctx, repository.EnsureWarehouseExportWatermarkParams{
+		OrganizationID: organizationID(ctx),
+		Sink:           sink,
+		TableName:      table.name,
+	}); err != nil {
+		return 0, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+	qtx := s.txQuerier(tx)
+
+	watermark, err := qtx.GetWarehouseExportWatermarkForUpdate(ctx, repository.GetWarehouseExportWatermarkForUpdateParams{
+		OrganizationID: organizationID(ctx),
+		Sink:           sink,
+		TableName:      table.name,
+	})
+	if err != nil {
+		return 0, err
+	}
+	rows, err := newTenantGuard(tx).Query(ctx, warehouseExportQuery(table),
+		organizationID(ctx), watermark.LastChangedAt, watermark.LastID, s.now().UTC().Add(-warehouseExportSettleWindow), warehouseExportBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	defer rows.Close()
+
+	batch := WarehouseExportBatch{OrganizationID: organizationID(ctx), Table: table.name}
+	for _, column := range table.columns {
+		batch.Columns = append(batch.Columns, WarehouseColumn{Name: column.name, Type: column.kind})
+	}
+	var lastChangedAt time.Time
+	var lastID string
+	for rows.Next() {
+		values := make([]sql.NullString, len(table.columns))
+		targets := make([]any, 0, len(values)+2)
+		for i := range values {
+			targets = append(targets, &values[i])
+		}
+		targets = append(targets, &lastChangedAt, &lastID)
+		if err := rows.Scan(targets...); err != nil {
+			return 0, err
+		}
+		row := make([]*string, 0, len(values))
+		for _, value := range values {
+			row = append(row, nullToPointer(value))
+		}
+		if len(batch.Rows) == 0 {
+			batch.ID = fmt.Sprintf("%d-%s", lastChangedAt.UnixMicro(), lastID)
+			batch.ChangedFrom = lastChangedAt
+		}
+		batch.Rows = append(batch.Rows, row)
+	}
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(batch.Rows) == 0 {
+		return 0, nil
+	}
+
+	if err := s.warehouseExport.ExportWarehouseBatch(ctx, batch); err != nil {
+		return 0, fmt.Errorf("export to %s: %w", sink, err)
+	}
+	if err := qtx.AdvanceWarehouseExportWatermark(ctx, repository.AdvanceWarehouseExportWatermarkParams{
+		OrganizationID: organizationID(ctx),
+		Sink:           sink,
+		TableName:      table.name,
+		LastChangedAt:  lastChangedAt,
+		LastID:         lastID,
+		Exported:       int64(len(batch.Rows)),
+	}); err != nil {
+		return 0, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("commit transaction: %w", err)
+	}
+	return len(batch.Rows), nil
+}
+
+// warehouseExportQuery pages through the table in (changed at, id) order, so rows sharing a timestamp are neither skipped nor
+// sent twice. Values come back as text in the layouts the sinks parse.
+func warehouseExportQuery(table warehouseTable) string {
+	columns := make([]string, 0, len(table.columns)+2)
+	for _, column := range table.columns {
+		switch column.kind {
+		case WarehouseColumnTimestamp:
+			columns = append(columns, fmt.Sprintf(`to_char(%s AT TIME ZONE 'UTC', 'YYYY-MM-DD"T"HH24:MI:SS.US"Z"')`, column.expr))
+		case WarehouseColumnDate:
+			columns = append(columns, fmt.Sprintf("to_char(%s, 'YYYY-MM-DD')", column.expr))
+		default:
+			columns = append(columns, fmt.Sprintf("(%s)::text", column.expr))
+		}
+	}
+	columns = append(columns, table.changedAt, "t.id")
+	return fmt.Sprintf(`-- name: ListWarehouseExportRows :many
+SELECT %s
+FROM %s t
+WHERE t.organization_id = $1::uuid
+  AND (%s, t.id) > ($2::timestamptz, $3::uuid)
+  AND %s <= $4::timestamptz
+ORDER BY %s, t.id
+LIMIT $5`, strings.Join(columns, ",\n       "), table.name, table.changedAt, table.changedAt, table.changedAt)
+}
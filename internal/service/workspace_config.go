@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+
+	"capim-test/internal/db/repository"
+)
+
+type workspaceModule struct {
+	key     string
+	roles   []string
+	feature string
+	// clinicAdmin limits a DENTIST module to dentists who are admins of the clinic.
+	clinicAdmin bool
+}
+
+type workspaceWidget struct {
+	key      string
+	module   string
+	role     string
+	endpoint string
+}
+
+// workspaceModules follows the route groups: ADMIN modules map to the clinic routes, DENTIST ones to /me.
+var workspaceModules = []workspaceModule{
+	{key: "clinic", roles: []string{UserRoleAdmin}},
+	{key: "dentists", roles: []string{UserRoleAdmin}},
+	{key: "bank_accounts", roles: []string{UserRoleAdmin}},
+	{key: "notes", roles: []string{UserRoleAdmin}},
+	{key: "documents", roles: []string{UserRoleAdmin}},
+	{key: "tasks", roles: []string{UserRoleAdmin, UserRoleDentist}},
+	{key: "announcements", roles: []string{UserRoleAdmin, UserRoleDentist}},
+	{key: "inventory", roles: []string{UserRoleAdmin}},
+	{key: "purchasing", roles: []string{UserRoleAdmin}},
+	{key: "equipment", roles: []string{UserRoleAdmin}},
+	{key: "compliance", roles: []string{UserRoleAdmin}},
+	{key: "time_clock", roles: []string{UserRoleAdmin, UserRoleDentist}},
+	{key: "scheduling", roles: []string{UserRoleAdmin}, feature: FeatureScheduling},
+	{key: "billing", roles: []string{UserRoleAdmin}, feature: FeatureBilling},
+	{key: "payouts", roles: []string{UserRoleAdmin}, feature: FeatureBilling},
+	{key: "profile", roles: []string{UserRoleDentist}},
+	{key: "notification_preferences", roles: []string{UserRoleDentist}},
+	{key: "bank_account_changes", roles: []string{UserRoleDentist}, clinicAdmin: true},
+}
+
+var workspaceWidgets = []workspaceWidget{
+	{key: "open_tasks", module: "tasks", role: UserRoleAdmin, endpoint: "/api/v1/clinics/%s/tasks?status=OPEN"},
+	{key: "expiring_documents", module: "compliance", role: UserRoleAdmin, endpoint: "/api/v1/clinics/%s/compliance/expiring-documents"},
+	{key: "equipment_maintenance", module: "equipment", role: UserRoleAdmin, endpoint: "/api/v1/clinics/%s/compliance/equipment-maintenance"},
+	{key: "low_stock", module: "inventory", role: UserRoleAdmin, endpoint: "/api/v1/clinics/%s/inventory/low-stock"},
+	{key: "pending_bank_account_changes", module: "bank_accounts", role: UserRoleAdmin, endpoint: "/api/v1/clinics/%s/bank-account-changes?status=PENDING"},
+	{key: "monthly_timesheet", module: "time_clock", role: UserRoleAdmin, endpoint: "/api/v1/clinics/%s/timesheets"},
+	{key: "weekly_shift_board", module: "scheduling", role: UserRoleAdmin, endpoint: "/api/v1/clinics/%s/shifts"},
+	{key: "ledger", module: "billing", role: UserRoleAdmin, endpoint: "/api/v1/clinics/%s/ledger"},
+	{key: "my_tasks", module: "tasks", role: UserRoleDentist, endpoint: "/api/v1/me/tasks"},
+	{key: "unread_announcements", module: "announcements", role: UserRoleDentist, endpoint: "/api/v1/me/announcements?unread=true"},
+}
+
+// GetWorkspaceConfig tells the frontend which modules and dashboard widgets a user gets in the clinic. Modules outside
+// the user's role are left out; modules the plan lacks are listed as disabled with the feature that unlocks them.
+func (s *Service) GetWorkspaceConfig(ctx context.Context, clinicID string, userID string) (WorkspaceConfigOutput, error) {
+	ctx, span := otel.Tracer(serviceTracerName).Start(ctx, "Service.GetWorkspaceConfig")
+	defer span.End()
+
+	if _, err := s.queries.GetClinicByID(ctx, repository.GetClinicByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             clinicID,
+	}); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WorkspaceConfigOutput{}, notFoundError("clinic not found")
+		}
+		return WorkspaceConfigOutput{}, err
+	}
+	user, err := s.queries.GetUserByID(ctx, repository.GetUserByIDParams{
+		OrganizationID: organizationID(ctx),
+		ID:             userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WorkspaceConfigOutput{}, notFoundError("staff member not found")
+		}
+		return WorkspaceConfigOutput{}, err
+	}
+	clinicAdmin := false
+	if user.Role == UserRoleDentist {
+		link, err := s.queries.GetActiveClinicDentist(ctx, repository.GetActiveClinicDentistParams{
+			OrganizationID: organizationID(ctx),
+			ClinicID:       clinicID,
+			DentistID:      user.DentistID.UUID.String(),
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return WorkspaceConfigOutput{}, notFoundError("staff member not found in this clinic")
+			}
+			return WorkspaceConfigOutput{}, err
+		}
+		clinicAdmin = link.IsAdmin
+	}
+	organization, err := s.queries.GetOrganizationByID(ctx, organizationID(ctx))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return WorkspaceConfigOutput{}, notFoundError("organization not found")
+		}
+		return WorkspaceConfigOutput{}, err
+	}
+
+	output := buildWorkspaceConfig(clinicID, user.Role, clinicAdmin, organization.Plan)
+	output.UserID = user.ID
+	return output, nil
+}
+
+func (s *Service) GetOwnWorkspaceConfig(ctx context.Context, clinicID string) (WorkspaceConfigOutput, error) {
+	principal, ok := PrincipalFromContext(ctx)
+	if !ok || principal.UserID == "" {
+		return WorkspaceConfigOutput{}, unauthorizedError("missing authenticated user")
+	}
+	return s.GetWorkspaceConfig(ctx, clinicID, principal.UserID)
+}
+
+func buildWorkspaceConfig(clinicID string, role string, clinicAdmin bool, plan string) WorkspaceConfigOutput {
+	output := WorkspaceConfigOutput{
+		ClinicID:      clinicID,
+		Role:          role,
+		IsClinicAdmin: clinicAdmin,
+		Plan:          plan,
+		Modules:       []WorkspaceModuleOutput{},
+		Widgets:       []WorkspaceWidgetOutput{},
+	}
+	enabled := make(map[string]bool, len(workspaceModules))
+	for _, module := range workspaceModules {
+		if !slices.Contains(module.roles, role) || (module.clinicAdmin && !clinicAdmin) {
+			continue
+		}
+		item := WorkspaceModuleOutput{Key: module.key, Enabled: true}
+		if module.feature != "" {
+			feature := module.feature
+			item.RequiredFeature = &feature
+			item.Enabled = planIncludes(plan, feature)
+		}
+		enabled[module.key] = item.Enabled
+		output.Modules = append(output.Modules, item)
+	}
+	for _, widget := range workspaceWidgets {
+		if widget.role != role || !enabled[widget.module] {
+			continue
+		}
+		endpoint := widget.endpoint
+		if strings.Contains(endpoint, "%s") {
+			endpoint = fmt.Sprintf(endpoint, clinicID)
+		}
+		output.Widgets = append(output.Widgets, WorkspaceWidgetOutput{Key: widget.key, Module: widget.module, Endpoint: endpoint})
+	}
+	return output
+}
@@ -0,0 +1,26 @@
+package validation
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+func NormalizeCompanyName(raw string) string {
+	stripDiacritics := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	stripped, _, err := transform.String(stripDiacritics, raw)
+	if err != nil {
+		stripped = raw
+	}
+	fields := strings.FieldsFunc(strings.ToUpper(stripped), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+func SameCompanyName(a, b string) bool {
+	return NormalizeCompanyName(a) == NormalizeCompanyName(b)
+}
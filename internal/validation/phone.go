@@ -0,0 +1,95 @@
+package validation
+
+import "strings"
+
+const (
+	brazilCountryCode      = "55"
+	minInternationalDigits = 8
+	maxInternationalDigits = 15
+)
+
+var brazilianAreaCodes = map[string]struct{}{
+	"11": {}, "12": {}, "13": {}, "14": {}, "15": {}, "16": {}, "17": {}, "18": {}, "19": {},
+	"21": {}, "22": {}, "24": {}, "27": {}, "28": {},
+	"31": {}, "32": {}, "33": {}, "34": {}, "35": {}, "37": {}, "38": {},
+	"41": {}, "42": {}, "43": {}, "44": {}, "45": {}, "46": {}, "47": {}, "48": {}, "49": {},
+	"51": {}, "53": {}, "54": {}, "55": {},
+	"61": {}, "62": {}, "63": {}, "64": {}, "65": {}, "66": {}, "67": {}, "68": {}, "69": {},
+	"71": {}, "73": {}, "74": {}, "75": {}, "77": {}, "79": {},
+	"81": {}, "82": {}, "83": {}, "84": {}, "85": {}, "86": {}, "87": {}, "88": {}, "89": {},
+	"91": {}, "92": {}, "93": {}, "94": {}, "95": {}, "96": {}, "97": {}, "98": {}, "99": {},
+}
+
+func NormalizePhone(raw string) (string, bool) {
+	trimmed := strings.TrimSpace(raw)
+	digits := nonDigits.ReplaceAllString(trimmed, "")
+	if digits == "" {
+		return "", false
+	}
+
+	international := strings.HasPrefix(trimmed, "+")
+	if !international && strings.HasPrefix(digits, "00") {
+		international = true
+		digits = strings.TrimPrefix(digits, "00")
+	}
+
+	if international {
+		if strings.HasPrefix(digits, brazilCountryCode) {
+			return normalizeBrazilianPhone(strings.TrimPrefix(digits, brazilCountryCode))
+		}
+		if len(digits) < minInternationalDigits || len(digits) > maxInternationalDigits || digits[0] == '0' {
+			return "", false
+		}
+		return "+" + digits, true
+	}
+
+	if (len(digits) == 12 || len(digits) == 13) && strings.HasPrefix(digits, brazilCountryCode) {
+		return normalizeBrazilianPhone(strings.TrimPrefix(digits, brazilCountryCode))
+	}
+	if (len(digits) == 11 || len(digits) == 12) && digits[0] == '0' {
+		digits = digits[1:]
+	}
+	return normalizeBrazilianPhone(digits)
+}
+
+func ValidatePhone(raw string) bool {
+	_, ok := NormalizePhone(raw)
+	return ok
+}
+
+func FormatPhoneDisplay(phone string) string {
+	e164, ok := NormalizePhone(phone)
+	if !ok {
+		return phone
+	}
+	if !strings.HasPrefix(e164, "+"+brazilCountryCode) {
+		return e164
+	}
+
+	national := strings.TrimPrefix(e164, "+"+brazilCountryCode)
+	areaCode, subscriber := national[:2], national[2:]
+	split := len(subscriber) - 4
+	return "+" + brazilCountryCode + " " + areaCode + " " + subscriber[:split] + "-" + subscriber[split:]
+}
+
+func normalizeBrazilianPhone(national string) (string, bool) {
+	if len(national) != 10 && len(national) != 11 {
+		return "", false
+	}
+	if _, ok := brazilianAreaCodes[national[:2]]; !ok {
+		return "", false
+	}
+
+	subscriber := national[2:]
+	switch len(subscriber) {
+	case 9:
+		if subscriber[0] != '9' {
+			return "", false
+		}
+	case 8:
+		if subscriber[0] < '2' || subscriber[0] > '5' {
+			return "", false
+		}
+	}
+	return "+" + brazilCountryCode + national, true
+}
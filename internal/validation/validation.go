@@ -10,6 +10,17 @@ import (
 
 var nonDigits = regexp.MustCompile(`\D`)
 var nonAlphanumeric = regexp.MustCompile(`[^0-9A-Za-z]`)
+var croNumberPattern = regexp.MustCompile(`^[0-9]{1,7}$`)
+var cepPattern = regexp.MustCompile(`^[0-9]{8}$`)
+var specialtyCodePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{1,49}$`)
+var specialtyCodeSeparators = regexp.MustCompile(`[\s-]+`)
+
+var brazilianStates = map[string]struct{}{
+	"AC": {}, "AL": {}, "AP": {}, "AM": {}, "BA": {}, "CE": {}, "DF": {},
+	"ES": {}, "GO": {}, "MA": {}, "MT": {}, "MS": {}, "MG": {}, "PA": {},
+	"PB": {}, "PR": {}, "PE": {}, "PI": {}, "RJ": {}, "RN": {}, "RS": {},
+	"RO": {}, "RR": {}, "SC": {}, "SP": {}, "SE": {}, "TO": {},
+}
 
 func NormalizeCPF(raw string) string {
 	return nonDigits.ReplaceAllString(raw, "")
@@ -20,6 +31,14 @@ func NormalizeCNPJ(raw string) string {
 	return strings.ToUpper(cleaned)
 }
 
+func CNPJRoot(cnpj string) string {
+	normalized := NormalizeCNPJ(cnpj)
+	if len(normalized) != 14 {
+		return ""
+	}
+	return normalized[:8]
+}
+
 func ValidateEmail(email string) bool {
 	email = strings.TrimSpace(email)
 	if email == "" {
@@ -36,3 +55,48 @@ func ValidateCPF(cpf string) bool {
 func ValidateCNPJ(cnpj string) bool {
 	return brdoc.NewCNPJ().Validate(cnpj)
 }
+
+func NormalizeState(raw string) string {
+	return strings.ToUpper(strings.TrimSpace(raw))
+}
+
+func ValidateState(state string) bool {
+	_, ok := brazilianStates[state]
+	return ok
+}
+
+func NormalizeCRONumber(raw string) string {
+	digits := nonDigits.ReplaceAllString(raw, "")
+	trimmed := strings.TrimLeft(digits, "0")
+	if trimmed == "" && digits != "" {
+		return "0"
+	}
+	return trimmed
+}
+
+func ValidateCRONumber(number string) bool {
+	return croNumberPattern.MatchString(number) && number != "0"
+}
+
+func NormalizeCEP(raw string) string {
+	return nonDigits.ReplaceAllString(raw, "")
+}
+
+func ValidateCEP(cep string) bool {
+	return cepPattern.MatchString(cep) && cep != "00000000"
+}
+
+func FormatCEP(cep string) string {
+	if len(cep) != 8 {
+		return cep
+	}
+	return cep[:5] + "-" + cep[5:]
+}
+
+func NormalizeSpecialtyCode(raw string) string {
+	return specialtyCodeSeparators.ReplaceAllString(strings.ToLower(strings.TrimSpace(raw)), "_")
+}
+
+func ValidateSpecialtyCode(code string) bool {
+	return specialtyCodePattern.MatchString(code)
+}
@@ -27,3 +27,124 @@ func TestValidateCNPJRejectsInvalidCheckDigits(t *testing.T) {
 		t.Fatalf("expected invalid CNPJ with wrong check digits")
 	}
 }
+
+func TestValidateStateAcceptsOnlyBrazilianUFs(t *testing.T) {
+	if !ValidateState(NormalizeState(" sp ")) {
+		t.Fatalf("expected SP to be a valid state")
+	}
+	if ValidateState("XX") {
+		t.Fatalf("expected XX to be rejected")
+	}
+}
+
+func TestNormalizeAndValidateCRONumber(t *testing.T) {
+	got := NormalizeCRONumber("CRO-SP 012.345")
+	if got != "12345" {
+		t.Fatalf("expected 12345, got %q", got)
+	}
+	if !ValidateCRONumber(got) {
+		t.Fatalf("expected %q to be a valid CRO number", got)
+	}
+
+	for _, tc := range []string{"", "0", "12345678"} {
+		if ValidateCRONumber(NormalizeCRONumber(tc)) {
+			t.Fatalf("expected CRO number %q to be rejected", tc)
+		}
+	}
+}
+
+func TestNormalizePhoneToE164(t *testing.T) {
+	tests := map[string]string{
+		"+55 11 99999-9999": "+5511999999999",
+		"(21) 98888-7777":   "+5521988887777",
+		"011 3333-4444":     "+551133334444",
+		"5511999999999":     "+5511999999999",
+		"+1 415 555 2671":   "+14155552671",
+		"0044 20 7946 0958": "+442079460958",
+	}
+
+	for raw, want := range tests {
+		got, ok := NormalizePhone(raw)
+		if !ok {
+			t.Fatalf("expected %q to be a valid phone", raw)
+		}
+		if got != want {
+			t.Fatalf("expected %q for %q, got %q", want, raw, got)
+		}
+	}
+}
+
+func TestNormalizePhoneRejectsInvalidBrazilianNumbers(t *testing.T) {
+	tests := []string{
+		"",
+		"12345",
+		"(20) 99999-9999",
+		"(11) 89999-9999",
+		"(11) 6333-4444",
+	}
+
+	for _, raw := range tests {
+		if ValidatePhone(raw) {
+			t.Fatalf("expected %q to be rejected", raw)
+		}
+	}
+}
+
+func TestFormatPhoneDisplay(t *testing.T) {
+	if got := FormatPhoneDisplay("+5511999999999"); got != "+55 11 99999-9999" {
+		t.Fatalf("unexpected mobile display: %q", got)
+	}
+	if got := FormatPhoneDisplay("+551133334444"); got != "+55 11 3333-4444" {
+		t.Fatalf("unexpected landline display: %q", got)
+	}
+	if got := FormatPhoneDisplay("ramal 22"); got != "ramal 22" {
+		t.Fatalf("expected unparseable phone to be returned as is, got %q", got)
+	}
+}
+
+func TestNormalizeAndValidateCEP(t *testing.T) {
+	cep := NormalizeCEP("01001-000")
+	if !ValidateCEP(cep) {
+		t.Fatalf("expected %q to be a valid CEP", cep)
+	}
+	if got := FormatCEP(cep); got != "01001-000" {
+		t.Fatalf("expected formatted CEP 01001-000, got %q", got)
+	}
+	if ValidateCEP(NormalizeCEP("1234-567")) || ValidateCEP("00000000") {
+		t.Fatalf("expected invalid CEPs to be rejected")
+	}
+}
+
+func TestSameCompanyNameIgnoresCaseAccentsAndPunctuation(t *testing.T) {
+	if !SameCompanyName("Clínica Sorriso Ltda.", "CLINICA SORRISO LTDA") {
+		t.Fatalf("expected names differing only by case, accents and punctuation to match")
+	}
+	if SameCompanyName("Clinica Sorriso Ltda", "Clinica Dente Feliz Ltda") {
+		t.Fatalf("expected different names not to match")
+	}
+}
+
+func TestNormalizeAndValidateSpecialtyCode(t *testing.T) {
+	code := NormalizeSpecialtyCode(" Oral-Surgery ")
+	if code != "oral_surgery" {
+		t.Fatalf("expected oral_surgery, got %q", code)
+	}
+	if !ValidateSpecialtyCode(code) {
+		t.Fatalf("expected %q to be a valid specialty code", code)
+	}
+	if ValidateSpecialtyCode("1st") || ValidateSpecialtyCode("ortho!") {
+		t.Fatalf("expected invalid specialty codes to be rejected")
+	}
+}
+
+func TestCNPJRoot(t *testing.T) {
+	if got := CNPJRoot("11.222.333/0001-81"); got != "11222333" {
+		t.Fatalf("unexpected root: %q", got)
+	}
+	if got := CNPJRoot("12ABC34501DE35"); got != "12ABC345" {
+		t.Fatalf("unexpected alphanumeric root: %q", got)
+	}
+	if got := CNPJRoot("123"); got != "" {
+		t.Fatalf("expected empty root for malformed CNPJ, got %q", got)
+	}
+}
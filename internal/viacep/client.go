@@ -0,0 +1,89 @@
+package viacep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const defaultBaseURL = "https://viacep.com.br/ws"
+
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+type response struct {
+	Logradouro  string `json:"logradouro"`
+	Complemento string `json:"complemento"`
+	Localidade  string `json:"localidade"`
+	UF          string `json:"uf"`
+	Erro        any    `json:"erro"`
+}
+
+func New(baseURL string, timeout time.Duration) *Client {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = defaultBaseURL
+	}
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+	return &Client{
+		baseURL:    strings.TrimRight(strings.TrimSpace(baseURL), "/"),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) LookupCEP(ctx context.Context, cep string) (service.AddressLookupResult, bool, error) {
+	endpoint := fmt.Sprintf("%s/%s/json/", c.baseURL, url.PathEscape(cep))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return service.AddressLookupResult{}, false, fmt.Errorf("build viacep request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return service.AddressLookupResult{}, false, fmt.Errorf("call viacep: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusNotFound:
+		return service.AddressLookupResult{}, false, nil
+	case resp.StatusCode != http.StatusOK:
+		return service.AddressLookupResult{}, false, fmt.Errorf("viacep returned status %d", resp.StatusCode)
+	}
+
+	var body response
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return service.AddressLookupResult{}, false, fmt.Errorf("decode viacep response: %w", err)
+	}
+	if notFound(body.Erro) {
+		return service.AddressLookupResult{}, false, nil
+	}
+
+	return service.AddressLookupResult{
+		Street:     body.Logradouro,
+		Complement: body.Complemento,
+		City:       body.Localidade,
+		State:      body.UF,
+	}, true, nil
+}
+
+func notFound(erro any) bool {
+	switch value := erro.(type) {
+	case bool:
+		return value
+	case string:
+		return strings.EqualFold(value, "true")
+	default:
+		return false
+	}
+}
@@ -0,0 +1,90 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const (
+	FormatNDJSON  = "ndjson"
+	FormatParquet = "parquet"
+
+	ndjsonContentType  = "application/x-ndjson"
+	parquetContentType = "application/vnd.apache.parquet"
+)
+
+// value is one cell parsed from the text the service hands over; which field is set follows the column type.
+type value struct {
+	null    bool
+	text    string
+	integer int64
+	boolean bool
+	time    time.Time
+}
+
+func parseValue(column service.WarehouseColumn, raw *string) (value, error) {
+	if raw == nil {
+		return value{null: true}, nil
+	}
+	switch column.Type {
+	case service.WarehouseColumnInteger:
+		parsed, err := strconv.ParseInt(*raw, 10, 64)
+		if err != nil {
+			return value{}, fmt.Errorf("column %s: %w", column.Name, err)
+		}
+		return value{integer: parsed}, nil
+	case service.WarehouseColumnBoolean:
+		parsed, err := strconv.ParseBool(*raw)
+		if err != nil {
+			return value{}, fmt.Errorf("column %s: %w", column.Name, err)
+		}
+		return value{boolean: parsed}, nil
+	case service.WarehouseColumnDate:
+		parsed, err := time.Parse(time.DateOnly, *raw)
+		if err != nil {
+			return value{}, fmt.Errorf("column %s: %w", column.Name, err)
+		}
+		return value{time: parsed, text: *raw}, nil
+	case service.WarehouseColumnTimestamp:
+		parsed, err := time.Parse(service.WarehouseTimestampLayout, *raw)
+		if err != nil {
+			return value{}, fmt.Errorf("column %s: %w", column.Name, err)
+		}
+		return value{time: parsed, text: *raw}, nil
+	}
+	return value{text: *raw}, nil
+}
+
+// encodeNDJSON writes one object per row. Integers and booleans keep their JSON types; dates and timestamps stay strings.
+func encodeNDJSON(batch service.WarehouseExportBatch) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for _, row := range batch.Rows {
+		object := make(map[string]any, len(batch.Columns))
+		for i, column := range batch.Columns {
+			parsed, err := parseValue(column, row[i])
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case parsed.null:
+				object[column.Name] = nil
+			case column.Type == service.WarehouseColumnInteger:
+				object[column.Name] = parsed.integer
+			case column.Type == service.WarehouseColumnBoolean:
+				object[column.Name] = parsed.boolean
+			default:
+				object[column.Name] = parsed.text
+			}
+		}
+		if err := encoder.Encode(object); err != nil {
+			return nil, fmt.Errorf("encode %s row: %w", batch.Table, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
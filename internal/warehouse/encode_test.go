@@ -0,0 +1,74 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strings"
+	"testing"
+
+	"capim-test/internal/service"
+)
+
+func testBatch() service.WarehouseExportBatch {
+	text := func(v string) *string { return &v }
+	return service.WarehouseExportBatch{
+		ID:    "1773232496123456-0b4f6a52-3a4c-4d2e-9f33-6e8f1c2a7d10",
+		Table: "inventory_items",
+		Columns: []service.WarehouseColumn{
+			{Name: "id", Type: service.WarehouseColumnText},
+			{Name: "quantity", Type: service.WarehouseColumnInteger},
+			{Name: "active", Type: service.WarehouseColumnBoolean},
+			{Name: "expires_on", Type: service.WarehouseColumnDate},
+			{Name: "updated_at", Type: service.WarehouseColumnTimestamp},
+		},
+		Rows: [][]*string{
+			{text("item-1"), text("12"), text("true"), text("2026-03-11"), text("2026-03-11T12:34:56.123456Z")},
+			{text("item-2"), nil, text("false"), nil, text("2026-03-11T12:35:00.000000Z")},
+		},
+	}
+}
+
+func TestEncodeNDJSONKeepsColumnTypes(t *testing.T) {
+	content, err := encodeNDJSON(testBatch())
+	if err != nil {
+		t.Fatalf("encodeNDJSON: %v", err)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if want := `{"active":true,"expires_on":"2026-03-11","id":"item-1","quantity":12,"updated_at":"2026-03-11T12:34:56.123456Z"}`; lines[0] != want {
+		t.Fatalf("unexpected first row %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"quantity":null`) || !strings.Contains(lines[1], `"expires_on":null`) {
+		t.Fatalf("expected nulls in second row, got %s", lines[1])
+	}
+}
+
+func TestEncodeParquetWritesFooterAndPages(t *testing.T) {
+	content, err := encodeParquet(testBatch())
+	if err != nil {
+		t.Fatalf("encodeParquet: %v", err)
+	}
+	if !bytes.HasPrefix(content, []byte(parquetMagic)) || !bytes.HasSuffix(content, []byte(parquetMagic)) {
+		t.Fatal("expected PAR1 magic at both ends")
+	}
+	footerLength := int(binary.LittleEndian.Uint32(content[len(content)-8 : len(content)-4]))
+	footer := content[len(content)-8-footerLength : len(content)-8]
+	for _, column := range []string{"id", "quantity", "active", "expires_on", "updated_at"} {
+		if !bytes.Contains(footer, []byte(column)) {
+			t.Fatalf("footer is missing column %s", column)
+		}
+	}
+	// 20523 days after the epoch is 2026-03-11, written as a little-endian INT32 in the expires_on page.
+	if !bytes.Contains(content, binary.LittleEndian.AppendUint32(nil, 20523)) {
+		t.Fatal("expected the date column as days since the epoch")
+	}
+
+	if _, err := encodeParquet(service.WarehouseExportBatch{
+		Columns: []service.WarehouseColumn{{Name: "quantity", Type: service.WarehouseColumnInteger}},
+		Rows:    [][]*string{{func() *string { v := "twelve"; return &v }()}},
+	}); err == nil {
+		t.Fatal("expected an error for a non-numeric integer")
+	}
+}
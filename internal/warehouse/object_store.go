@@ -0,0 +1,207 @@
+package warehouse
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const gcsEndpoint = "https://storage.googleapis.com"
+
+type Config struct {
+	Bucket          string
+	Region          string
+	Endpoint        string
+	Prefix          string
+	Format          string
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	Timeout         time.Duration
+}
+
+// ObjectStoreSink writes each batch as one object under <prefix>/<organization>/<table>/dt=<day of the first change>/<batch id>,
+// so a warehouse can load a table's folder incrementally and a redelivered batch overwrites itself.
+type ObjectStoreSink struct {
+	name       string
+	cfg        Config
+	endpoint   *url.URL
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+func NewS3(cfg Config) (*ObjectStoreSink, error) {
+	if strings.TrimSpace(cfg.Region) == "" {
+		return nil, errors.New("warehouse export region is required")
+	}
+	if strings.TrimSpace(cfg.Endpoint) == "" {
+		cfg.Endpoint = "https://s3." + strings.TrimSpace(cfg.Region) + ".amazonaws.com"
+	}
+	return newObjectStore("s3", cfg)
+}
+
+// NewGCS talks to Cloud Storage through its XML API, which accepts S3-style signed requests made with an HMAC key.
+func NewGCS(cfg Config) (*ObjectStoreSink, error) {
+	if strings.TrimSpace(cfg.Region) == "" {
+		cfg.Region = "auto"
+	}
+	if strings.TrimSpace(cfg.Endpoint) == "" {
+		cfg.Endpoint = gcsEndpoint
+	}
+	return newObjectStore("gcs", cfg)
+}
+
+func newObjectStore(name string, cfg Config) (*ObjectStoreSink, error) {
+	cfg.Bucket = strings.TrimSpace(cfg.Bucket)
+	cfg.Region = strings.TrimSpace(cfg.Region)
+	cfg.Prefix = strings.Trim(strings.TrimSpace(cfg.Prefix), "/")
+	cfg.Format = strings.ToLower(strings.TrimSpace(cfg.Format))
+	if cfg.Format == "" {
+		cfg.Format = FormatNDJSON
+	}
+	if cfg.Format != FormatNDJSON && cfg.Format != FormatParquet {
+		return nil, fmt.Errorf("unsupported warehouse export format %q", cfg.Format)
+	}
+	if cfg.Bucket == "" {
+		return nil, errors.New("warehouse export bucket is required")
+	}
+	if strings.TrimSpace(cfg.AccessKeyID) == "" || strings.TrimSpace(cfg.SecretAccessKey) == "" {
+		return nil, errors.New("warehouse export credentials are required")
+	}
+	rawEndpoint := strings.TrimRight(strings.TrimSpace(cfg.Endpoint), "/")
+	endpoint, err := url.Parse(rawEndpoint)
+	if err != nil || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid warehouse export endpoint %q", rawEndpoint)
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &ObjectStoreSink{
+		name:       name,
+		cfg:        cfg,
+		endpoint:   endpoint,
+		httpClient: &http.Client{Timeout: timeout},
+		now:        time.Now,
+	}, nil
+}
+
+func (s *ObjectStoreSink) Name() string {
+	return s.name
+}
+
+func (s *ObjectStoreSink) ExportWarehouseBatch(ctx context.Context, batch service.WarehouseExportBatch) error {
+	if len(batch.Rows) == 0 {
+		return nil
+	}
+	var body []byte
+	var contentType string
+	var err error
+	switch s.cfg.Format {
+	case FormatParquet:
+		body, err = encodeParquet(batch)
+		contentType = parquetContentType
+	default:
+		body, err = encodeNDJSON(batch)
+		contentType = ndjsonContentType
+	}
+	if err != nil {
+		return err
+	}
+
+	key := batch.OrganizationID + "/" + batch.Table + "/dt=" + batch.ChangedFrom.UTC().Format(time.DateOnly) + "/" + batch.ID + "." + s.cfg.Format
+	if s.cfg.Prefix != "" {
+		key = s.cfg.Prefix + "/" + key
+	}
+	objectURL := *s.endpoint
+	objectURL.Path = strings.TrimRight(objectURL.Path, "/") + "/" + s.cfg.Bucket + "/" + key
+	// SigV4 signs "=" percent-encoded, so the request has to send it that way too.
+	objectURL.RawPath = strings.ReplaceAll(objectURL.Path, "=", "%3D")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build warehouse export request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	s.sign(req, body)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("put warehouse export object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("warehouse export storage returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sign applies AWS Signature Version 4 for a single-chunk upload; apart from "=", object keys only use unreserved characters.
+func (s *ObjectStoreSink) sign(req *http.Request, body []byte) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if token := strings.TrimSpace(s.cfg.SessionToken); token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	signedHeaders := []string{"content-type", "host", "x-amz-content-sha256", "x-amz-date"}
+	if req.Header.Get("X-Amz-Security-Token") != "" {
+		signedHeaders = append(signedHeaders, "x-amz-security-token")
+	}
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaders {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		"",
+		canonicalHeaders.String(),
+		strings.Join(signedHeaders, ";"),
+		payloadHash,
+	}, "\n")
+
+	scope := shortDate + "/" + s.cfg.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	signingKey := hmacSHA256([]byte("AWS4"+strings.TrimSpace(s.cfg.SecretAccessKey)), shortDate)
+	signingKey = hmacSHA256(signingKey, s.cfg.Region)
+	signingKey = hmacSHA256(signingKey, "s3")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		strings.TrimSpace(s.cfg.AccessKeyID), scope, strings.Join(signedHeaders, ";"), signature,
+	))
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,285 @@
+package warehouse
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	"capim-test/internal/service"
+)
+
+const parquetMagic = "PAR1"
+
+// Values from the Parquet format's Thrift definitions.
+const (
+	parquetBoolean   = 0
+	parquetInt32     = 1
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetConvertedUTF8            = 0
+	parquetConvertedDate            = 6
+	parquetConvertedTimestampMicros = 10
+
+	parquetOptional = 1
+
+	parquetEncodingPlain = 0
+	parquetEncodingRLE   = 3
+
+	parquetUncompressed = 0
+	parquetDataPage     = 0
+)
+
+// Thrift compact protocol field types.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// encodeParquet writes the batch as a single row group with one uncompressed, plain-encoded data page per column. Every
+// column is optional, so nulls survive and a reader needs no dictionary or codec support.
+func encodeParquet(batch service.WarehouseExportBatch) ([]byte, error) {
+	var file bytes.Buffer
+	file.WriteString(parquetMagic)
+
+	type chunk struct {
+		offset int64
+		size   int64
+		values int64
+	}
+	chunks := make([]chunk, 0, len(batch.Columns))
+	for column, definition := range batch.Columns {
+		values := make([]value, 0, len(batch.Rows))
+		for _, row := range batch.Rows {
+			parsed, err := parseValue(definition, row[column])
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, parsed)
+		}
+		page := parquetPage(definition.Type, values)
+
+		var header thriftWriter
+		header.begin()
+		header.i32(1, parquetDataPage)
+		header.i32(2, int32(len(page)))
+		header.i32(3, int32(len(page)))
+		header.beginStruct(5)
+		header.i32(1, int32(len(values)))
+		header.i32(2, parquetEncodingPlain)
+		header.i32(3, parquetEncodingRLE)
+		header.i32(4, parquetEncodingRLE)
+		header.end()
+		header.end()
+
+		offset := int64(file.Len())
+		file.Write(header.buf.Bytes())
+		file.Write(page)
+		chunks = append(chunks, chunk{offset: offset, size: int64(file.Len()) - offset, values: int64(len(values))})
+	}
+
+	var footer thriftWriter
+	footer.begin()
+	footer.i32(1, 1)
+	footer.beginList(2, thriftStruct, len(batch.Columns)+1)
+	footer.beginElement()
+	footer.binary(4, []byte("schema"))
+	footer.i32(5, int32(len(batch.Columns)))
+	footer.end()
+	for _, column := range batch.Columns {
+		physical, converted := parquetType(column.Type)
+		footer.beginElement()
+		footer.i32(1, physical)
+		footer.i32(3, parquetOptional)
+		footer.binary(4, []byte(column.Name))
+		if converted >= 0 {
+			footer.i32(6, converted)
+		}
+		footer.end()
+	}
+	footer.i64(3, int64(len(batch.Rows)))
+	footer.beginList(4, thriftStruct, 1)
+	footer.beginElement()
+	footer.beginList(1, thriftStruct, len(chunks))
+	var totalSize int64
+	for i, column := range batch.Columns {
+		physical, _ := parquetType(column.Type)
+		footer.beginElement()
+		footer.i64(2, chunks[i].offset)
+		footer.beginStruct(3)
+		footer.i32(1, physical)
+		footer.listI32(2, []int32{parquetEncodingPlain, parquetEncodingRLE})
+		footer.listBinary(3, [][]byte{[]byte(column.Name)})
+		footer.i32(4, parquetUncompressed)
+		footer.i64(5, chunks[i].values)
+		footer.i64(6, chunks[i].size)
+		footer.i64(7, chunks[i].size)
+		footer.i64(9, chunks[i].offset)
+		footer.end()
+		footer.end()
+		totalSize += chunks[i].size
+	}
+	footer.i64(2, totalSize)
+	footer.i64(3, int64(len(batch.Rows)))
+	footer.end()
+	footer.binary(6, []byte("capim-test warehouse export"))
+	footer.end()
+
+	file.Write(footer.buf.Bytes())
+	if err := binary.Write(&file, binary.LittleEndian, uint32(footer.buf.Len())); err != nil {
+		return nil, fmt.Errorf("write parquet footer length: %w", err)
+	}
+	file.WriteString(parquetMagic)
+	return file.Bytes(), nil
+}
+
+func parquetType(columnType string) (int32, int32) {
+	switch columnType {
+	case service.WarehouseColumnInteger:
+		return parquetInt64, -1
+	case service.WarehouseColumnBoolean:
+		return parquetBoolean, -1
+	case service.WarehouseColumnDate:
+		return parquetInt32, parquetConvertedDate
+	case service.WarehouseColumnTimestamp:
+		return parquetInt64, parquetConvertedTimestampMicros
+	}
+	return parquetByteArray, parquetConvertedUTF8
+}
+
+// parquetPage is the body of a v1 data page: length-prefixed definition levels, then the plain values of the non-null rows.
+func parquetPage(columnType string, values []value) []byte {
+	var levels bytes.Buffer
+	for start := 0; start < len(values); {
+		end := start
+		for end < len(values) && values[end].null == values[start].null {
+			end++
+		}
+		levels.Write(binary.AppendUvarint(nil, uint64(end-start)<<1))
+		if values[start].null {
+			levels.WriteByte(0)
+		} else {
+			levels.WriteByte(1)
+		}
+		start = end
+	}
+
+	page := binary.LittleEndian.AppendUint32(nil, uint32(levels.Len()))
+	page = append(page, levels.Bytes()...)
+	var bits, count byte
+	for _, v := range values {
+		if v.null {
+			continue
+		}
+		switch columnType {
+		case service.WarehouseColumnInteger:
+			page = binary.LittleEndian.AppendUint64(page, uint64(v.integer))
+		case service.WarehouseColumnTimestamp:
+			page = binary.LittleEndian.AppendUint64(page, uint64(v.time.UnixMicro()))
+		case service.WarehouseColumnDate:
+			page = binary.LittleEndian.AppendUint32(page, uint32(v.time.Unix()/86400))
+		case service.WarehouseColumnBoolean:
+			if v.boolean {
+				bits |= 1 << count
+			}
+			if count++; count == 8 {
+				page = append(page, bits)
+				bits, count = 0, 0
+			}
+		default:
+			page = binary.LittleEndian.AppendUint32(page, uint32(len(v.text)))
+			page = append(page, v.text...)
+		}
+	}
+	if count > 0 {
+		page = append(page, bits)
+	}
+	return page
+}
+
+// thriftWriter emits the Thrift compact protocol, which is how Parquet encodes page headers and the file footer.
+type thriftWriter struct {
+	buf    bytes.Buffer
+	fields []int16
+}
+
+func (w *thriftWriter) begin() {
+	w.fields = append(w.fields, 0)
+}
+
+func (w *thriftWriter) end() {
+	w.buf.WriteByte(0)
+	w.fields = w.fields[:len(w.fields)-1]
+}
+
+func (w *thriftWriter) field(id int16, fieldType byte) {
+	last := &w.fields[len(w.fields)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta)<<4 | fieldType)
+	} else {
+		w.buf.WriteByte(fieldType)
+		w.varint(int64(id))
+	}
+	*last = id
+}
+
+func (w *thriftWriter) varint(v int64) {
+	w.buf.Write(binary.AppendUvarint(nil, uint64((v<<1)^(v>>63))))
+}
+
+func (w *thriftWriter) i32(id int16, v int32) {
+	w.field(id, thriftI32)
+	w.varint(int64(v))
+}
+
+func (w *thriftWriter) i64(id int16, v int64) {
+	w.field(id, thriftI64)
+	w.varint(v)
+}
+
+func (w *thriftWriter) binary(id int16, v []byte) {
+	w.field(id, thriftBinary)
+	w.bytes(v)
+}
+
+func (w *thriftWriter) bytes(v []byte) {
+	w.buf.Write(binary.AppendUvarint(nil, uint64(len(v))))
+	w.buf.Write(v)
+}
+
+func (w *thriftWriter) beginStruct(id int16) {
+	w.field(id, thriftStruct)
+	w.begin()
+}
+
+func (w *thriftWriter) beginList(id int16, elementType byte, size int) {
+	w.field(id, thriftList)
+	if size < 15 {
+		w.buf.WriteByte(byte(size)<<4 | elementType)
+		return
+	}
+	w.buf.WriteByte(0xf0 | elementType)
+	w.buf.Write(binary.AppendUvarint(nil, uint64(size)))
+}
+
+// beginElement starts a struct inside a list; it has no field header of its own.
+func (w *thriftWriter) beginElement() {
+	w.begin()
+}
+
+func (w *thriftWriter) listI32(id int16, values []int32) {
+	w.beginList(id, thriftI32, len(values))
+	for _, v := range values {
+		w.varint(int64(v))
+	}
+}
+
+func (w *thriftWriter) listBinary(id int16, values [][]byte) {
+	w.beginList(id, thriftBinary, len(values))
+	for _, v := range values {
+		w.bytes(v)
+	}
+}
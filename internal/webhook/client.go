@@ -0,0 +1,86 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"capim-test/internal/service"
+)
+
+const (
+	headerEventID   = "X-Webhook-Event-ID"
+	headerEventType = "X-Webhook-Event"
+	headerSignature = "X-Webhook-Signature"
+)
+
+type Client struct {
+	endpoint   string
+	secret     []byte
+	httpClient *http.Client
+}
+
+type payload struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OccurredAt time.Time `json:"occurred_at"`
+	Data       any       `json:"data"`
+}
+
+func New(endpoint string, secret string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	return &Client{
+		endpoint:   strings.TrimSpace(endpoint),
+		secret:     []byte(strings.TrimSpace(secret)),
+		httpClient: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *Client) Publish(ctx context.Context, event service.Event) error {
+	body, err := json.Marshal(payload{
+		ID:         event.ID,
+		Type:       event.Type,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Data,
+	})
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(headerEventID, event.ID)
+	req.Header.Set(headerEventType, event.Type)
+	if len(c.secret) > 0 {
+		req.Header.Set(headerSignature, "sha256="+sign(c.secret, body))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("call webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,47 @@
+#!/usr/bin/env bash
+# Compares two `go test -bench -benchmem` outputs. Each benchmark is averaged over its runs; the check fails when the mean ns/op
+# grows beyond BENCH_TOLERANCE percent (default 20) or allocs/op grows at all. Timings depend on the machine, so the baseline
+# should come from the same hardware; allocation counts do not.
+set -euo pipefail
+
+baseline="${1:?usage: compare.sh baseline.txt current.txt}"
+current="${2:?usage: compare.sh baseline.txt current.txt}"
+tolerance="${BENCH_TOLERANCE:-20}"
+
+awk -v tolerance="$tolerance" '
+function record(kind, name, ns, allocs) {
+  sum[kind, name] += ns
+  allocSum[kind, name] += allocs
+  runs[kind, name]++
+  names[name] = 1
+}
+FNR == 1 { kind = (FILENAME == ARGV[1]) ? "base" : "head" }
+/^Benchmark/ {
+  name = $1
+  sub(/-[0-9]+$/, "", name)
+  ns = ""; allocs = 0
+  for (i = 2; i < NF; i++) {
+    if ($(i + 1) == "ns/op") ns = $i
+    if ($(i + 1) == "allocs/op") allocs = $i
+  }
+  if (ns != "") record(kind, name, ns, allocs)
+}
+END {
+  failed = 0
+  printf "%-45s %14s %14s %9s %12s\n", "benchmark", "baseline ns/op", "current ns/op", "delta", "allocs/op"
+  for (name in names) {
+    if (!runs["head", name]) { printf "%-45s missing from the current run\n", name; failed = 1; continue }
+    if (!runs["base", name]) { printf "%-45s %14s %14.1f %9s %12d (new)\n", name, "-", sum["head", name] / runs["head", name], "-", allocSum["head", name] / runs["head", name]; continue }
+    base = sum["base", name] / runs["base", name]
+    head = sum["head", name] / runs["head", name]
+    baseAllocs = allocSum["base", name] / runs["base", name]
+    headAllocs = allocSum["head", name] / runs["head", name]
+    delta = (head - base) / base * 100
+    status = ""
+    if (delta > tolerance) { status = "  SLOWER"; failed = 1 }
+    if (headAllocs > baseAllocs) { status = status "  MORE ALLOCS"; failed = 1 }
+    printf "%-45s %14.1f %14.1f %+8.1f%% %5d -> %-5d%s\n", name, base, head, delta, baseAllocs, headAllocs, status
+  }
+  exit failed
+}
+' "$baseline" "$current"
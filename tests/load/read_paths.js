@@ -0,0 +1,51 @@
+// Read-heavy load on the hot authenticated paths: k6 run -e BASE_URL=... -e AUTH_EMAIL=... -e AUTH_PASSWORD=... tests/load/read_paths.js
+import http from 'k6/http';
+import { check, fail } from 'k6';
+
+const baseURL = __ENV.BASE_URL || 'http://localhost:8080';
+
+export const options = {
+  scenarios: {
+    reads: {
+      executor: 'constant-vus',
+      vus: Number(__ENV.VUS || 20),
+      duration: __ENV.DURATION || '30s',
+    },
+  },
+  thresholds: {
+    http_req_failed: ['rate<0.01'],
+    'http_req_duration{endpoint:list_clinics}': [`p(99)<${__ENV.P99_MS || 500}`],
+    'http_req_duration{endpoint:get_clinic}': [`p(99)<${__ENV.P99_MS || 500}`],
+  },
+};
+
+export function setup() {
+  const login = http.post(`${baseURL}/api/v1/auth/login`, JSON.stringify({
+    email: __ENV.AUTH_EMAIL,
+    password: __ENV.AUTH_PASSWORD,
+  }), { headers: { 'Content-Type': 'application/json' } });
+  if (login.status !== 200) {
+    fail(`login failed with status ${login.status}`);
+  }
+  const headers = { Authorization: `Bearer ${login.json('access_token')}` };
+  const clinics = http.get(`${baseURL}/api/v1/clinics?limit=100`, { headers });
+  const ids = clinics.status === 200 ? clinics.json().map((clinic) => clinic.id) : [];
+  return { headers, ids };
+}
+
+export default function (data) {
+  const list = http.get(`${baseURL}/api/v1/clinics?limit=20`, {
+    headers: data.headers,
+    tags: { endpoint: 'list_clinics' },
+  });
+  check(list, { 'list clinics is 200': (r) => r.status === 200 });
+
+  if (data.ids.length > 0) {
+    const id = data.ids[Math.floor(Math.random() * data.ids.length)];
+    const detail = http.get(`${baseURL}/api/v1/clinics/${id}`, {
+      headers: data.headers,
+      tags: { endpoint: 'get_clinic' },
+    });
+    check(detail, { 'get clinic is 200': (r) => r.status === 200 });
+  }
+}